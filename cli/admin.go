@@ -20,6 +20,8 @@ type AdminContext struct {
 	ProjectionStore core.ProjectionStore
 	Engine          core.ProjectionEngine
 	DB              *sql.DB
+	// Projectors are the projectors registered with Engine.
+	Projectors []core.Projector
 }
 
 type AdminCmd struct {
@@ -66,12 +68,10 @@ func NewAdminCmd() *AdminCmd {
 			}
 
 			engine := core.NewProjectionEngine(eventStore, projectionStore, checkpointStore)
-			engine.Register(projectors.NewRealmListProjector())
-			engine.Register(projectors.NewRuneListProjector())
-			engine.Register(projectors.NewRuneDetailProjector())
-			engine.Register(projectors.NewDependencyGraphProjector())
-			engine.Register(projectors.NewAccountLookupProjector())
-			engine.Register(projectors.NewAccountListProjector())
+			admin.Ctx.Projectors = adminProjectors()
+			for _, p := range admin.Ctx.Projectors {
+				engine.Register(p)
+			}
 
 			admin.Ctx.EventStore = eventStore
 			admin.Ctx.ProjectionStore = projectionStore
@@ -94,11 +94,41 @@ func NewAdminCmd() *AdminCmd {
 	addAdminRealmCommands(admin)
 	addAdminAccountCommands(admin)
 	addAdminPATCommands(admin)
+	addAdminWebhookCommands(admin)
+	addAdminTokenCommands(admin)
 	addAdminRebuildCommands(admin)
+	addAdminSnapshotCommands(admin)
+	addAdminReplayCommands(admin)
 
 	return admin
 }
 
+// adminProjectors returns the read model projectors the server registers.
+// Notifications, webhooks and automation only run in the server.
+func adminProjectors() []core.Projector {
+	return []core.Projector{
+		projectors.NewRealmListProjector(),
+		projectors.NewRuneListProjector(),
+		projectors.NewRuneDetailProjector(),
+		projectors.NewDependencyGraphProjector(),
+		projectors.NewAccountLookupProjector(),
+		projectors.NewAccountListProjector(),
+		projectors.NewAdminTokensProjector(),
+		projectors.NewRuneChildCountProjector(),
+		projectors.NewRuneChildrenProjector(),
+		projectors.NewRealmSettingsProjector(),
+		projectors.NewNotificationPreferencesProjector(),
+		projectors.NewWebhookListProjector(),
+		projectors.NewAutomationRulesProjector(),
+		projectors.NewEscalationPoliciesProjector(),
+		projectors.NewSchedulesProjector(),
+		projectors.NewSavedSearchesProjector(),
+		projectors.NewDailyStatsProjector(),
+		projectors.NewRuneTransitionsProjector(),
+		projectors.NewDashboardStatsProjector(),
+	}
+}
+
 func resolveUsername(ctx context.Context, projectionStore core.ProjectionStore, username string) (string, error) {
 	var accountID string
 	err := projectionStore.Get(ctx, "_admin", "account_lookup", "username:"+username, &accountID)
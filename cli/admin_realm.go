@@ -14,6 +14,8 @@ func addAdminRealmCommands(admin *AdminCmd) {
 	admin.Command.AddCommand(newAdminCreateRealmCmd(admin))
 	admin.Command.AddCommand(newAdminListRealmsCmd(admin))
 	admin.Command.AddCommand(newAdminSuspendRealmCmd(admin))
+	admin.Command.AddCommand(newAdminSetRealmSettingCmd(admin))
+	admin.Command.AddCommand(newAdminDeleteRealmSettingCmd(admin))
 }
 
 func newAdminCreateRealmCmd(admin *AdminCmd) *cobra.Command {
@@ -133,3 +135,83 @@ func newAdminSuspendRealmCmd(admin *AdminCmd) *cobra.Command {
 		},
 	}
 }
+
+func newAdminSetRealmSettingCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-realm-setting <realm-id> <key> <value>",
+		Short: "Set a realm setting",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			ctx := cmd.Context()
+
+			err := domain.HandleSetRealmSetting(ctx, domain.SetRealmSetting{
+				RealmID: args[0],
+				Key:     args[1],
+				Value:   args[2],
+			}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+
+			events, err := admin.Ctx.EventStore.ReadStream(ctx, "_admin", "realm-"+args[0], 0)
+			if err != nil {
+				return err
+			}
+			if err := syncProjections(ctx, admin.Ctx, events); err != nil {
+				return err
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(map[string]string{
+					"realm_id": args[0],
+					"key":      args[1],
+				})
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Setting %s updated for realm %s\n", args[1], args[0])
+			return nil
+		},
+	}
+}
+
+func newAdminDeleteRealmSettingCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete-realm-setting <realm-id> <key>",
+		Short: "Delete a realm setting",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			ctx := cmd.Context()
+
+			err := domain.HandleDeleteRealmSetting(ctx, domain.DeleteRealmSetting{
+				RealmID: args[0],
+				Key:     args[1],
+			}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+
+			events, err := admin.Ctx.EventStore.ReadStream(ctx, "_admin", "realm-"+args[0], 0)
+			if err != nil {
+				return err
+			}
+			if err := syncProjections(ctx, admin.Ctx, events); err != nil {
+				return err
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(map[string]string{
+					"status": "deleted",
+				})
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Setting %s deleted from realm %s\n", args[1], args[0])
+			return nil
+		},
+	}
+}
@@ -126,6 +126,52 @@ func TestAdminSuspendRealm(t *testing.T) {
 	})
 }
 
+func TestAdminSetRealmSetting(t *testing.T) {
+	t.Run("sets setting and prints confirmation", func(t *testing.T) {
+		tc := newAdminRealmTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+
+		// When
+		tc.run_set_realm_setting("bf-1234", "github.token", "abc")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("github.token")
+	})
+
+	t.Run("returns error for non-existent realm", func(t *testing.T) {
+		tc := newAdminRealmTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.run_set_realm_setting("bf-nonexistent", "github.token", "abc")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+func TestAdminDeleteRealmSetting(t *testing.T) {
+	t.Run("returns error when setting does not exist", func(t *testing.T) {
+		tc := newAdminRealmTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+
+		// When
+		tc.run_delete_realm_setting("bf-1234", "github.token")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
 // --- Test Context ---
 
 type adminRealmTestContext struct {
@@ -220,6 +266,16 @@ func (tc *adminRealmTestContext) run_suspend_realm_json(realmID string) {
 	tc.output, tc.err = executeAdminCmd(tc.cmd, "suspend-realm", realmID, "--json")
 }
 
+func (tc *adminRealmTestContext) run_set_realm_setting(realmID, key, value string) {
+	tc.t.Helper()
+	tc.output, tc.err = executeAdminCmd(tc.cmd, "set-realm-setting", realmID, key, value)
+}
+
+func (tc *adminRealmTestContext) run_delete_realm_setting(realmID, key string) {
+	tc.t.Helper()
+	tc.output, tc.err = executeAdminCmd(tc.cmd, "delete-realm-setting", realmID, key)
+}
+
 // --- Then ---
 
 func (tc *adminRealmTestContext) command_has_no_error() {
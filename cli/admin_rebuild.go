@@ -2,8 +2,10 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/devzeebo/bifrost/core"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +17,18 @@ func addAdminRebuildCommands(admin *AdminCmd) {
 and checkpoints, then replaying all events.
 
 This is useful when projector logic has been fixed and you need to
-reconstruct the projection state from the event store.`,
+reconstruct the projection state from the event store.
+
+With --projection and --realm, only that realm's entries of the projection,
+and of the others its projector writes, are cleared and projected again.
+Other realms are left as they are.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
+			projection, _ := cmd.Flags().GetString("projection")
+			realmID, _ := cmd.Flags().GetString("realm")
+			if projection != "" || realmID != "" {
+				return rebuildRealmProjection(cmd, admin, projection, realmID)
+			}
 
 			// Clear projections
 			_, err := admin.Ctx.DB.ExecContext(ctx, `DELETE FROM projections`)
@@ -41,5 +52,23 @@ reconstruct the projection state from the event store.`,
 		},
 	}
 
+	cmd.Flags().String("projection", "", "projection or projector to rebuild, by name (requires --realm)")
+	cmd.Flags().String("realm", "", "realm to rebuild the projection in (requires --projection)")
+
 	admin.Command.AddCommand(cmd)
 }
+
+func rebuildRealmProjection(cmd *cobra.Command, admin *AdminCmd, projection, realmID string) error {
+	if projection == "" || realmID == "" {
+		return errors.New("--projection and --realm must be given together")
+	}
+	rebuilder, ok := admin.Ctx.Engine.(core.ProjectionRebuilder)
+	if !ok {
+		return errors.New("the projection engine cannot rebuild a single realm")
+	}
+	if err := rebuilder.Rebuild(cmd.Context(), projection, realmID); err != nil {
+		return fmt.Errorf("rebuild %s in %s: %w", projection, realmID, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Rebuilt %s in realm %s\n", projection, realmID)
+	return nil
+}
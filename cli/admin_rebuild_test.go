@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminRebuildProjections(t *testing.T) {
+	t.Run("rebuilds a projection in one realm", func(t *testing.T) {
+		tc := newAdminRebuildTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_rebuilding_engine()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "rebuild-projections", "--projection", "rune_list", "--realm", "bf-1234")
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Contains(t, tc.output, "Rebuilt rune_list in realm bf-1234")
+		assert.Equal(t, []string{"bf-1234/rune_list"}, tc.engine.rebuilds)
+	})
+
+	t.Run("requires a realm with a projection", func(t *testing.T) {
+		tc := newAdminRebuildTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_rebuilding_engine()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "rebuild-projections", "--projection", "rune_list")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "must be given together")
+		assert.Empty(t, tc.engine.rebuilds)
+	})
+
+	t.Run("reports a projection that cannot be rebuilt", func(t *testing.T) {
+		tc := newAdminRebuildTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_rebuilding_engine()
+		tc.engine.err = core.ErrNotRebuildable
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "rebuild-projections", "--projection", "schedules", "--realm", "bf-1234")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.ErrorIs(t, tc.err, core.ErrNotRebuildable)
+	})
+}
+
+// --- Test Context ---
+
+type adminRebuildTestContext struct {
+	t *testing.T
+
+	cmd    *cobra.Command
+	engine *rebuildingEngine
+	output string
+	err    error
+}
+
+func newAdminRebuildTestContext(t *testing.T) *adminRebuildTestContext {
+	t.Helper()
+	return &adminRebuildTestContext{t: t}
+}
+
+// --- Mock Engine ---
+
+type rebuildingEngine struct {
+	mockEngine
+	rebuilds []string
+	err      error
+}
+
+func (m *rebuildingEngine) Rebuild(_ context.Context, projectionName string, realmID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.rebuilds = append(m.rebuilds, realmID+"/"+projectionName)
+	return nil
+}
+
+// --- Given ---
+
+func (tc *adminRebuildTestContext) admin_cmd_with_rebuilding_engine() {
+	tc.t.Helper()
+	tc.engine = &rebuildingEngine{}
+	admin := &AdminCmd{
+		Command: &cobra.Command{Use: "admin"},
+		Ctx:     &AdminContext{Engine: tc.engine},
+	}
+	addAdminRebuildCommands(admin)
+	tc.cmd = admin.Command
+}
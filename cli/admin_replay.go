@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func addAdminReplayCommands(admin *AdminCmd) {
+	admin.Command.AddCommand(newAdminReplayProjectionsCmd(admin))
+}
+
+func newAdminReplayProjectionsCmd(admin *AdminCmd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay-projections",
+		Short: "Replay event history into a sandbox and compare it with the live projections",
+		Long: `Replay the event history through this build's projectors into a separate
+sandbox database, then compare the result with the live projections.
+The live projections and checkpoints are not touched.
+
+Run it with a new build against a copy or the production database to see
+what its projector changes would do before deploying them. It exits with
+an error when a projector returns errors.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			realmIDs, _ := cmd.Flags().GetStringSlice("realm")
+			names, _ := cmd.Flags().GetStringSlice("projector")
+			out, _ := cmd.Flags().GetString("out")
+			ctx := cmd.Context()
+
+			selected, err := selectProjectors(admin.Ctx.Projectors, names)
+			if err != nil {
+				return err
+			}
+
+			sandbox, cleanup, err := openSandbox(out)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			report, err := core.Replay(ctx, admin.Ctx.EventStore, sandbox, selected,
+				core.ReplayRealms(realmIDs...), core.ReplayCompareWith(admin.Ctx.ProjectionStore))
+			if err != nil {
+				return err
+			}
+
+			if jsonMode {
+				data, _ := json.Marshal(report)
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			} else {
+				printReplayReport(cmd.OutOrStdout(), report)
+				if out != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), "\nSandbox projections kept in %s\n", out)
+				}
+			}
+			if report.Failed() {
+				return fmt.Errorf("projectors returned errors during the replay")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("realm", nil, "realms to replay (default: all)")
+	cmd.Flags().StringSlice("projector", nil, "projectors to run, by name (default: all)")
+	cmd.Flags().String("out", "", "keep the sandbox in a new SQLite database at this path")
+
+	return cmd
+}
+
+func selectProjectors(all []core.Projector, names []string) ([]core.Projector, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	var selected []core.Projector
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(p core.Projector) bool { return p.Name() == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown projector %q", name)
+		}
+		selected = append(selected, all[i])
+	}
+	return selected, nil
+}
+
+// openSandbox creates an empty projection store in a new SQLite database at
+// path, or in a temporary one that cleanup removes.
+func openSandbox(path string) (core.ProjectionStore, func(), error) {
+	remove := path == ""
+	if remove {
+		f, err := os.CreateTemp("", "bifrost-sandbox-*.db")
+		if err != nil {
+			return nil, nil, err
+		}
+		f.Close()
+		path = f.Name()
+	} else if _, err := os.Stat(path); err == nil {
+		return nil, nil, fmt.Errorf("%s already exists", path)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open sandbox: %w", err)
+	}
+	cleanup := func() {
+		db.Close()
+		if remove {
+			os.Remove(path)
+		}
+	}
+	store, err := sqlite.NewProjectionStore(db)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("create sandbox projection store: %w", err)
+	}
+	return store, cleanup, nil
+}
+
+func printReplayReport(out io.Writer, report *core.ReplayReport) {
+	fmt.Fprintf(out, "Replayed %d events from %d realms\n\n", report.Events, len(report.Realms))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Projector\tHandled\tErrors")
+	fmt.Fprintln(w, "---------\t-------\t------")
+	for _, p := range report.Projectors {
+		if p.Skipped {
+			fmt.Fprintf(w, "%s\tskipped (side effects)\t\n", p.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\n", p.Name, p.Handled, p.Failed)
+	}
+	w.Flush()
+
+	for _, p := range report.Projectors {
+		for _, e := range p.Errors {
+			fmt.Fprintf(out, "  %s: %s event %d (%s): %s\n", p.Name, e.RealmID, e.GlobalPosition, e.EventType, e.Error)
+		}
+	}
+
+	var differing []core.ProjectionDiff
+	for _, d := range report.Diffs {
+		if d.Differs() {
+			differing = append(differing, d)
+		}
+	}
+	fmt.Fprintf(out, "\n%d of %d projections match the live read models\n", len(report.Diffs)-len(differing), len(report.Diffs))
+	if len(differing) == 0 {
+		return
+	}
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Realm\tProjection\tChanged\tAdded\tRemoved\tLive\tSandbox\tExamples")
+	fmt.Fprintln(w, "-----\t----------\t-------\t-----\t-------\t----\t-------\t--------")
+	for _, d := range differing {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%v\n",
+			d.RealmID, d.ProjectionName, d.Changed, d.Added, d.Removed, d.LiveEntries, d.SandboxEntries, d.Examples)
+	}
+	w.Flush()
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminReplayProjections(t *testing.T) {
+	t.Run("reports projections the live read models are missing", func(t *testing.T) {
+		tc := newAdminReplayTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "replay-projections", "--projector", "realm_list")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Replayed 1 events from 1 realms")
+		tc.output_contains("0 of 1 projections match")
+		tc.output_contains("realm_list")
+		assert.Empty(t, tc.projectionStore.data, "live projections must not be written")
+	})
+
+	t.Run("reports matching projections", func(t *testing.T) {
+		tc := newAdminReplayTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+		tc.live_realm_list_entry("bf-1234", "test-realm")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "replay-projections", "--projector", "realm_list", "--json")
+
+		// Then
+		tc.command_has_no_error()
+		var report struct {
+			Events int `json:"events"`
+			Diffs  []struct {
+				Projection string `json:"projection"`
+				Same       int    `json:"same"`
+			} `json:"diffs"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(tc.output), &report))
+		assert.Equal(t, 1, report.Events)
+		require.Len(t, report.Diffs, 1)
+		assert.Equal(t, 1, report.Diffs[0].Same)
+	})
+
+	t.Run("keeps the sandbox at the --out path", func(t *testing.T) {
+		tc := newAdminReplayTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+		out := filepath.Join(t.TempDir(), "sandbox.db")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "replay-projections", "--out", out)
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Sandbox projections kept in " + out)
+		_, err := os.Stat(out)
+		assert.NoError(t, err)
+	})
+
+	t.Run("refuses to overwrite an existing --out file", func(t *testing.T) {
+		tc := newAdminReplayTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		out := filepath.Join(t.TempDir(), "sandbox.db")
+		require.NoError(t, os.WriteFile(out, nil, 0o600))
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "replay-projections", "--out", out)
+
+		// Then
+		tc.error_occurred()
+		tc.output_contains("already exists")
+	})
+
+	t.Run("returns error for an unknown projector", func(t *testing.T) {
+		tc := newAdminReplayTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "replay-projections", "--projector", "nope")
+
+		// Then
+		tc.error_occurred()
+		tc.output_contains(`unknown projector "nope"`)
+	})
+}
+
+// --- Test Context ---
+
+type adminReplayTestContext struct {
+	*adminRealmTestContext
+}
+
+func newAdminReplayTestContext(t *testing.T) *adminReplayTestContext {
+	t.Helper()
+	return &adminReplayTestContext{adminRealmTestContext: newAdminRealmTestContext(t)}
+}
+
+// --- Given ---
+
+func (tc *adminReplayTestContext) live_realm_list_entry(realmID, name string) {
+	tc.t.Helper()
+	entry := projectors.RealmListEntry{RealmID: realmID, Name: name, Status: "active", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	data, _ := json.Marshal(entry)
+	tc.projectionStore.data["_admin|realm_list|"+realmID] = entry
+	tc.projectionStore.listData["_admin|realm_list"] = []json.RawMessage{data}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/spf13/cobra"
+)
+
+func addAdminSnapshotCommands(admin *AdminCmd) {
+	export := &cobra.Command{
+		Use:   "export-projection",
+		Short: "Export a projection's rows in a realm with its checkpoint",
+		Long: `Export a realm's rows of a projection, and of the others its projector
+writes, together with the projector's checkpoint, as newline-delimited JSON.
+
+import-projection loads the snapshot into another database holding the same
+events, such as a new replica, in place of replaying the realm's events.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projection, _ := cmd.Flags().GetString("projection")
+			realmID, _ := cmd.Flags().GetString("realm")
+			path, _ := cmd.Flags().GetString("out")
+			if projection == "" || realmID == "" {
+				return errors.New("--projection and --realm are required")
+			}
+			snapshotter, err := adminSnapshotter(admin)
+			if err != nil {
+				return err
+			}
+
+			w, report := cmd.OutOrStdout(), cmd.ErrOrStderr()
+			if path != "-" {
+				f, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w, report = f, cmd.OutOrStdout()
+			}
+			rows, err := snapshotter.ExportProjection(cmd.Context(), projection, realmID, w)
+			if err != nil {
+				return fmt.Errorf("export %s in %s: %w", projection, realmID, err)
+			}
+			fmt.Fprintf(report, "Exported %d rows of %s in realm %s\n", rows, projection, realmID)
+			return nil
+		},
+	}
+	export.Flags().String("projection", "", "projection or projector to export, by name")
+	export.Flags().String("realm", "", "realm to export the projection from")
+	export.Flags().String("out", "-", "file to write the snapshot to, or - for standard output")
+
+	load := &cobra.Command{
+		Use:   "import-projection",
+		Short: "Replace a projection's rows in a realm with an exported snapshot",
+		Long: `Replace a realm's rows of a projector's projections with a snapshot
+written by export-projection, set the projector's checkpoint to the
+snapshot's, and project the realm's events after it.
+
+The checkpoint is a position in the event store, so the snapshot must come
+from a database holding the same events at the same positions.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("in")
+			snapshotter, err := adminSnapshotter(admin)
+			if err != nil {
+				return err
+			}
+
+			var r io.Reader = cmd.InOrStdin()
+			if path != "-" {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			imported, err := snapshotter.ImportProjection(cmd.Context(), r)
+			if err != nil {
+				return fmt.Errorf("import projection: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d rows of %s in realm %s at position %d\n",
+				imported.Rows, imported.Projector, imported.RealmID, imported.Checkpoint)
+			return nil
+		},
+	}
+	load.Flags().String("in", "-", "file to read the snapshot from, or - for standard input")
+
+	admin.Command.AddCommand(export, load)
+}
+
+func adminSnapshotter(admin *AdminCmd) (core.ProjectionSnapshotter, error) {
+	snapshotter, ok := admin.Ctx.Engine.(core.ProjectionSnapshotter)
+	if !ok {
+		return nil, errors.New("the projection engine cannot snapshot projections")
+	}
+	return snapshotter, nil
+}
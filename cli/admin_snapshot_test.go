@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminExportProjection(t *testing.T) {
+	t.Run("writes the snapshot to a file", func(t *testing.T) {
+		tc := newAdminSnapshotTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_snapshotting_engine()
+		path := filepath.Join(t.TempDir(), "rune_list.ndjson")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "export-projection", "--projection", "rune_list", "--realm", "bf-1234", "--out", path)
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Contains(t, tc.output, "Exported 1 rows of rune_list in realm bf-1234")
+		tc.file_contains(path, "bf-1234/rune_list\n")
+	})
+
+	t.Run("requires a projection and a realm", func(t *testing.T) {
+		tc := newAdminSnapshotTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_snapshotting_engine()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "export-projection", "--projection", "rune_list")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "are required")
+	})
+
+	t.Run("reports a projection that cannot be snapshotted", func(t *testing.T) {
+		tc := newAdminSnapshotTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_snapshotting_engine()
+		tc.engine.err = core.ErrNotSnapshottable
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "export-projection", "--projection", "schedules", "--realm", "bf-1234", "--out", filepath.Join(t.TempDir(), "out"))
+
+		// Then
+		assert.ErrorIs(t, tc.err, core.ErrNotSnapshottable)
+	})
+}
+
+func TestAdminImportProjection(t *testing.T) {
+	t.Run("loads the snapshot from a file", func(t *testing.T) {
+		tc := newAdminSnapshotTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_snapshotting_engine()
+		path := filepath.Join(t.TempDir(), "rune_list.ndjson")
+		require.NoError(t, os.WriteFile(path, []byte("snapshot\n"), 0o600))
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "import-projection", "--in", path)
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Contains(t, tc.output, "Imported 3 rows of rune_list in realm bf-1234 at position 42")
+		assert.Equal(t, "snapshot\n", tc.engine.imported)
+	})
+}
+
+// --- Test Context ---
+
+type adminSnapshotTestContext struct {
+	t *testing.T
+
+	cmd    *cobra.Command
+	engine *snapshottingEngine
+	output string
+	err    error
+}
+
+func newAdminSnapshotTestContext(t *testing.T) *adminSnapshotTestContext {
+	t.Helper()
+	return &adminSnapshotTestContext{t: t}
+}
+
+// --- Mock Engine ---
+
+type snapshottingEngine struct {
+	mockEngine
+	imported string
+	err      error
+}
+
+func (m *snapshottingEngine) ExportProjection(_ context.Context, projectionName string, realmID string, w io.Writer) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	_, err := io.WriteString(w, realmID+"/"+projectionName+"\n")
+	return 1, err
+}
+
+func (m *snapshottingEngine) ImportProjection(_ context.Context, r io.Reader) (core.ProjectionImport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return core.ProjectionImport{}, err
+	}
+	m.imported = string(data)
+	return core.ProjectionImport{RealmID: "bf-1234", Projector: "rune_list", Checkpoint: 42, Rows: 3}, nil
+}
+
+// --- Given ---
+
+func (tc *adminSnapshotTestContext) admin_cmd_with_snapshotting_engine() {
+	tc.t.Helper()
+	tc.engine = &snapshottingEngine{}
+	admin := &AdminCmd{
+		Command: &cobra.Command{Use: "admin"},
+		Ctx:     &AdminContext{Engine: tc.engine},
+	}
+	addAdminSnapshotCommands(admin)
+	tc.cmd = admin.Command
+}
+
+// --- Then ---
+
+func (tc *adminSnapshotTestContext) file_contains(path, expected string) {
+	tc.t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, string(data))
+}
@@ -403,6 +403,7 @@ func newAdminCmdForTest(eventStore core.EventStore, projectionStore core.Project
 			EventStore:      eventStore,
 			ProjectionStore: projectionStore,
 			Engine:          &mockEngine{},
+			Projectors:      adminProjectors(),
 		},
 	}
 
@@ -420,6 +421,9 @@ func newAdminCmdForTest(eventStore core.EventStore, projectionStore core.Project
 	addAdminRealmCommands(admin)
 	addAdminAccountCommands(admin)
 	addAdminPATCommands(admin)
+	addAdminWebhookCommands(admin)
+	addAdminTokenCommands(admin)
+	addAdminReplayCommands(admin)
 
 	return cmd
 }
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/spf13/cobra"
+)
+
+func addAdminTokenCommands(admin *AdminCmd) {
+	admin.Command.AddCommand(newAdminIssueTokenCmd(admin))
+	admin.Command.AddCommand(newAdminRotateTokenCmd(admin))
+	admin.Command.AddCommand(newAdminRevokeTokenCmd(admin))
+	admin.Command.AddCommand(newAdminListTokensCmd(admin))
+}
+
+func newAdminIssueTokenCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "issue-admin-token <name>",
+		Short: "Issue an admin API token for automation",
+		Long: `Issue an admin API token for automation such as provisioning scripts.
+
+Admin tokens belong to no account and cannot log in to the UI. They act as
+an admin of the _admin realm only, so they can create realms and accounts
+but cannot touch runes. The token is only printed once.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			result, err := domain.HandleIssueAdminToken(ctx, domain.IssueAdminToken{Name: args[0]}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+			return printAdminToken(ctx, cmd, admin, result)
+		},
+	}
+}
+
+func newAdminRotateTokenCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-admin-token <token-id>",
+		Short: "Replace an admin token's secret; the old one stops working",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			result, err := domain.HandleRotateAdminToken(ctx, domain.RotateAdminToken{TokenID: args[0]}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+			return printAdminToken(ctx, cmd, admin, result)
+		},
+	}
+}
+
+func newAdminRevokeTokenCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-admin-token <token-id>",
+		Short: "Revoke an admin token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			ctx := cmd.Context()
+
+			if err := domain.HandleRevokeAdminToken(ctx, domain.RevokeAdminToken{TokenID: args[0]}, admin.Ctx.EventStore); err != nil {
+				return err
+			}
+			if err := syncAdminToken(ctx, admin, args[0]); err != nil {
+				return err
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(map[string]string{"status": "revoked"})
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Admin token %s revoked\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newAdminListTokensCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-admin-tokens",
+		Short: "List live admin tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+
+			raws, err := admin.Ctx.ProjectionStore.List(cmd.Context(), domain.AdminRealmID, "admin_tokens")
+			if err != nil {
+				return err
+			}
+			tokens := []projectors.AdminTokenEntry{}
+			for _, raw := range raws {
+				var entry projectors.AdminTokenEntry
+				if err := json.Unmarshal(raw, &entry); err != nil || entry.ID == "" {
+					continue
+				}
+				tokens = append(tokens, entry)
+			}
+			sort.Slice(tokens, func(i, j int) bool { return tokens[i].IssuedAt.Before(tokens[j].IssuedAt) })
+
+			if jsonMode {
+				out, _ := json.Marshal(tokens)
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "Token ID\tName\tIssued\tRotated")
+			fmt.Fprintln(w, "--------\t----\t------\t-------")
+			for _, t := range tokens {
+				rotated := "never"
+				if !t.RotatedAt.IsZero() {
+					rotated = t.RotatedAt.Format("2006-01-02")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.ID, t.Name, t.IssuedAt.Format("2006-01-02"), rotated)
+			}
+			w.Flush()
+			return nil
+		},
+	}
+}
+
+func printAdminToken(ctx context.Context, cmd *cobra.Command, admin *AdminCmd, result domain.AdminTokenResult) error {
+	if err := syncAdminToken(ctx, admin, result.TokenID); err != nil {
+		return err
+	}
+
+	jsonMode, _ := cmd.Flags().GetBool("json")
+	if jsonMode {
+		out, _ := json.Marshal(result)
+		fmt.Fprintln(cmd.OutOrStdout(), string(out))
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Token ID: %s\n", result.TokenID)
+	fmt.Fprintf(cmd.OutOrStdout(), "Token: %s\n", result.Token)
+	fmt.Fprintln(cmd.OutOrStdout(), "Store the token now; it cannot be shown again")
+	return nil
+}
+
+func syncAdminToken(ctx context.Context, admin *AdminCmd, tokenID string) error {
+	events, err := admin.Ctx.EventStore.ReadStream(ctx, domain.AdminRealmID, "admin-token-"+tokenID, 0)
+	if err != nil {
+		return err
+	}
+	return syncProjections(ctx, admin.Ctx, events)
+}
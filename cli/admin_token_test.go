@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminIssueAdminToken(t *testing.T) {
+	t.Run("issues token and prints ID and secret", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "issue-admin-token", "provisioner")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Token ID: adm-")
+		tc.output_contains("Token: bfa_")
+	})
+
+	t.Run("prints token as json", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "issue-admin-token", "provisioner", "--json")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_is_valid_json()
+		tc.json_output_has_key("token_id")
+		tc.json_output_has_key("token")
+	})
+
+	t.Run("returns error for blank name", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "issue-admin-token", "  ")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+func TestAdminRotateAdminToken(t *testing.T) {
+	t.Run("prints a new secret for the same token", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tokenID, token := tc.admin_token_is_issued("provisioner")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "rotate-admin-token", tokenID, "--json")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_is_valid_json()
+		tc.json_output_has_value("token_id", tokenID)
+		tc.token_changed_from(token)
+	})
+
+	t.Run("returns error for unknown token", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "rotate-admin-token", "adm-missing")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+func TestAdminRevokeAdminToken(t *testing.T) {
+	t.Run("revokes issued token", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tokenID, _ := tc.admin_token_is_issued("provisioner")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "revoke-admin-token", tokenID)
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("revoked")
+	})
+
+	t.Run("returns error for revoked token", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tokenID, _ := tc.admin_token_is_issued("provisioner")
+		tc.admin_token_is_revoked(tokenID)
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "revoke-admin-token", tokenID)
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+func TestAdminListAdminTokens(t *testing.T) {
+	t.Run("lists tokens without secrets", func(t *testing.T) {
+		tc := newAdminTokenTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.projection_store_has_admin_token(projectors.AdminTokenEntry{
+			ID: "adm-a1b2c3d4", Name: "provisioner", IssuedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		})
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "list-admin-tokens")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("adm-a1b2c3d4")
+		tc.output_contains("provisioner")
+		tc.output_contains("never")
+		assert.NotContains(t, tc.output, "bfa_")
+	})
+}
+
+// --- Test Context ---
+
+type adminTokenTestContext struct {
+	*adminRealmTestContext
+}
+
+func newAdminTokenTestContext(t *testing.T) *adminTokenTestContext {
+	t.Helper()
+	return &adminTokenTestContext{adminRealmTestContext: newAdminRealmTestContext(t)}
+}
+
+// --- Given ---
+
+func (tc *adminTokenTestContext) projection_store_has_admin_token(entry projectors.AdminTokenEntry) {
+	tc.t.Helper()
+	data, _ := json.Marshal(entry)
+	key := "_admin|admin_tokens"
+	tc.projectionStore.listData[key] = append(tc.projectionStore.listData[key], data)
+}
+
+func (tc *adminTokenTestContext) admin_token_is_issued(name string) (string, string) {
+	tc.t.Helper()
+	output, err := executeAdminCmd(tc.cmd, "issue-admin-token", name, "--json")
+	require.NoError(tc.t, err)
+	var result map[string]string
+	require.NoError(tc.t, json.Unmarshal([]byte(output), &result))
+	tc.cmd = newAdminCmdForTest(tc.eventStore, tc.projectionStore)
+	return result["token_id"], result["token"]
+}
+
+func (tc *adminTokenTestContext) admin_token_is_revoked(tokenID string) {
+	tc.t.Helper()
+	_, err := executeAdminCmd(tc.cmd, "revoke-admin-token", tokenID)
+	require.NoError(tc.t, err)
+	tc.cmd = newAdminCmdForTest(tc.eventStore, tc.projectionStore)
+}
+
+// --- Then ---
+
+func (tc *adminTokenTestContext) token_changed_from(previous string) {
+	tc.t.Helper()
+	var result map[string]string
+	require.NoError(tc.t, json.Unmarshal([]byte(tc.output), &result))
+	assert.NotEqual(tc.t, previous, result["token"])
+	assert.Contains(tc.t, result["token"], "bfa_")
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/spf13/cobra"
+)
+
+func addAdminWebhookCommands(admin *AdminCmd) {
+	admin.Command.AddCommand(newAdminAddWebhookCmd(admin))
+	admin.Command.AddCommand(newAdminListWebhooksCmd(admin))
+	admin.Command.AddCommand(newAdminRemoveWebhookCmd(admin))
+}
+
+func newAdminAddWebhookCmd(admin *AdminCmd) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-webhook <realm-id> <url>",
+		Short: "Register an outbound webhook for a realm",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			secret, _ := cmd.Flags().GetString("secret")
+			eventTypes, _ := cmd.Flags().GetStringSlice("events")
+			ctx := cmd.Context()
+
+			result, err := domain.HandleRegisterWebhook(ctx, domain.RegisterWebhook{
+				RealmID: args[0],
+				URL:     args[1],
+				Secret:  secret,
+				Events:  eventTypes,
+			}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+
+			events, err := admin.Ctx.EventStore.ReadStream(ctx, "_admin", "webhook-"+result.WebhookID, 0)
+			if err != nil {
+				return err
+			}
+			if err := syncProjections(ctx, admin.Ctx, events); err != nil {
+				return err
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(map[string]string{
+					"webhook_id": result.WebhookID,
+					"secret":     result.Secret,
+				})
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Webhook ID: %s\n", result.WebhookID)
+			fmt.Fprintf(cmd.OutOrStdout(), "Secret: %s\n", result.Secret)
+			fmt.Fprintln(cmd.OutOrStdout(), "Use this secret to verify the X-Bifrost-Signature header")
+			return nil
+		},
+	}
+
+	cmd.Flags().String("secret", "", "signing secret (generated when omitted)")
+	cmd.Flags().StringSlice("events", nil, "event types to deliver, e.g. RuneCreated,RuneFulfilled (default: all)")
+
+	return cmd
+}
+
+func newAdminListWebhooksCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-webhooks <realm-id>",
+		Short: "List outbound webhooks for a realm",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			ctx := cmd.Context()
+
+			raws, err := admin.Ctx.ProjectionStore.List(ctx, args[0], "webhook_list")
+			if err != nil {
+				return err
+			}
+
+			type webhookEntry struct {
+				WebhookID string   `json:"webhook_id"`
+				URL       string   `json:"url"`
+				Events    []string `json:"events"`
+			}
+
+			hooks := []webhookEntry{}
+			for _, raw := range raws {
+				var entry projectors.WebhookListEntry
+				if err := json.Unmarshal(raw, &entry); err != nil {
+					continue
+				}
+				hooks = append(hooks, webhookEntry{WebhookID: entry.ID, URL: entry.URL, Events: entry.Events})
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(hooks)
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "Webhook ID\tURL\tEvents")
+			fmt.Fprintln(w, "----------\t---\t------")
+			for _, h := range hooks {
+				events := "all"
+				if len(h.Events) > 0 {
+					events = strings.Join(h.Events, ",")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", h.WebhookID, h.URL, events)
+			}
+			w.Flush()
+			return nil
+		},
+	}
+}
+
+func newAdminRemoveWebhookCmd(admin *AdminCmd) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-webhook <webhook-id>",
+		Short: "Remove an outbound webhook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsonMode, _ := cmd.Flags().GetBool("json")
+			ctx := cmd.Context()
+
+			err := domain.HandleRemoveWebhook(ctx, domain.RemoveWebhook{
+				WebhookID: args[0],
+			}, admin.Ctx.EventStore)
+			if err != nil {
+				return err
+			}
+
+			events, err := admin.Ctx.EventStore.ReadStream(ctx, "_admin", "webhook-"+args[0], 0)
+			if err != nil {
+				return err
+			}
+			if err := syncProjections(ctx, admin.Ctx, events); err != nil {
+				return err
+			}
+
+			if jsonMode {
+				out, _ := json.Marshal(map[string]string{
+					"status": "removed",
+				})
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Webhook %s removed\n", args[0])
+			return nil
+		},
+	}
+}
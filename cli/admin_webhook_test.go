@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminAddWebhook(t *testing.T) {
+	t.Run("registers webhook and prints ID and secret", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "add-webhook", "bf-1234", "https://example.com/hook")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Webhook ID: wh-")
+		tc.output_contains("Secret: whsec_")
+	})
+
+	t.Run("uses provided secret and events with json output", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "add-webhook", "bf-1234", "https://example.com/hook",
+			"--secret", "my-secret", "--events", "RuneCreated,RuneFulfilled", "--json")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_is_valid_json()
+		tc.json_output_has_key("webhook_id")
+		tc.json_output_has_value("secret", "my-secret")
+	})
+
+	t.Run("returns error for non-existent realm", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "add-webhook", "bf-nonexistent", "https://example.com/hook")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+func TestAdminListWebhooks(t *testing.T) {
+	t.Run("lists webhooks in a table", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.projection_store_has_webhook("bf-1234", projectors.WebhookListEntry{
+			ID: "wh-a1b2c3d4", RealmID: "bf-1234", URL: "https://example.com/hook", Events: []string{"RuneCreated"},
+		})
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "list-webhooks", "bf-1234")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("wh-a1b2c3d4")
+		tc.output_contains("https://example.com/hook")
+		tc.output_contains("RuneCreated")
+		assert.NotContains(t, tc.output, "whsec_")
+	})
+}
+
+func TestAdminRemoveWebhook(t *testing.T) {
+	t.Run("removes registered webhook", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+		tc.realm_exists("bf-1234", "test-realm")
+		webhookID := tc.webhook_is_registered("bf-1234")
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "remove-webhook", webhookID)
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("removed")
+	})
+
+	t.Run("returns error for unknown webhook", func(t *testing.T) {
+		tc := newAdminWebhookTestContext(t)
+
+		// Given
+		tc.admin_cmd_with_mock_stores()
+
+		// When
+		tc.output, tc.err = executeAdminCmd(tc.cmd, "remove-webhook", "wh-missing")
+
+		// Then
+		tc.error_occurred()
+	})
+}
+
+// --- Test Context ---
+
+type adminWebhookTestContext struct {
+	*adminRealmTestContext
+}
+
+func newAdminWebhookTestContext(t *testing.T) *adminWebhookTestContext {
+	t.Helper()
+	return &adminWebhookTestContext{adminRealmTestContext: newAdminRealmTestContext(t)}
+}
+
+// --- Given ---
+
+func (tc *adminWebhookTestContext) projection_store_has_webhook(realmID string, entry projectors.WebhookListEntry) {
+	tc.t.Helper()
+	data, _ := json.Marshal(entry)
+	key := realmID + "|webhook_list"
+	tc.projectionStore.listData[key] = append(tc.projectionStore.listData[key], data)
+}
+
+func (tc *adminWebhookTestContext) webhook_is_registered(realmID string) string {
+	tc.t.Helper()
+	output, err := executeAdminCmd(tc.cmd, "add-webhook", realmID, "https://example.com/hook", "--json")
+	require.NoError(tc.t, err)
+	var result map[string]string
+	require.NoError(tc.t, json.Unmarshal([]byte(output), &result))
+	tc.cmd = newAdminCmdForTest(tc.eventStore, tc.projectionStore)
+	return result["webhook_id"]
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type CalendarCmd struct {
+	Command *cobra.Command
+}
+
+func NewCalendarCmd(clientFn func() *Client, out *bytes.Buffer) *CalendarCmd {
+	c := &CalendarCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "calendar",
+		Short: "Issue an iCal feed URL for runes with due dates",
+		Long: "Issues a private iCal URL listing runes with due dates across your realms.\n" +
+			"Subscribe to it from Google Calendar or Outlook. Running the command again\n" +
+			"issues a new URL and disables the previous one.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			humanMode, _ := cmd.Flags().GetBool("human")
+
+			client := clientFn()
+			resp, err := client.DoPost("/calendar-token", []byte("{}"))
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			if resp.StatusCode >= 400 {
+				var errResp map[string]string
+				if json.Unmarshal(respBody, &errResp) == nil {
+					if msg, ok := errResp["error"]; ok {
+						out.WriteString(msg)
+						return fmt.Errorf("%s", msg)
+					}
+				}
+				return fmt.Errorf("server error: %s", string(respBody))
+			}
+
+			var result map[string]string
+			if err := json.Unmarshal(respBody, &result); err != nil {
+				return err
+			}
+			result["url"] = strings.TrimSuffix(client.baseURL, "/") + result["url"]
+			data, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+
+			return PrintOutput(out, data, humanMode, func(w *bytes.Buffer, _ []byte) {
+				fmt.Fprintf(w, "Calendar URL: %s\n", result["url"])
+				fmt.Fprint(w, "Keep this URL private — anyone with it can read your due dates")
+			})
+		},
+	}
+
+	cmd.Flags().Bool("human", false, "human-readable output")
+
+	c.Command = cmd
+	return c
+}
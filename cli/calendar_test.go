@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCalendarCommand(t *testing.T) {
+	t.Run("posts to /calendar-token and prints the absolute feed URL", func(t *testing.T) {
+		tc := newCalendarTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusCreated, `{"token":"abc","url":"/calendar/abc.ics"}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_calendar()
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_was(http.MethodPost, "/api/calendar-token")
+		tc.output_json_has("url", tc.server.URL+"/calendar/abc.ics")
+		tc.output_json_has("token", "abc")
+	})
+
+	t.Run("outputs human-readable URL when --human flag is set", func(t *testing.T) {
+		tc := newCalendarTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusCreated, `{"token":"abc","url":"/calendar/abc.ics"}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_calendar("--human")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Calendar URL: " + tc.server.URL + "/calendar/abc.ics")
+	})
+
+	t.Run("returns server error message", func(t *testing.T) {
+		tc := newCalendarTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusForbidden, `{"error":"account required"}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_calendar()
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "account required")
+	})
+}
+
+// --- Test Context ---
+
+type calendarTestContext struct {
+	t *testing.T
+
+	server         *httptest.Server
+	client         *Client
+	receivedMethod string
+	receivedPath   string
+	buf            *bytes.Buffer
+	err            error
+}
+
+func newCalendarTestContext(t *testing.T) *calendarTestContext {
+	t.Helper()
+	return &calendarTestContext{
+		t:   t,
+		buf: &bytes.Buffer{},
+	}
+}
+
+// --- Given ---
+
+func (tc *calendarTestContext) server_that_captures_request_and_returns(status int, response string) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedMethod = r.Method
+		tc.receivedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+func (tc *calendarTestContext) client_configured() {
+	tc.t.Helper()
+	tc.client = NewClient(&Config{
+		URL:    tc.server.URL,
+		APIKey: "test-key",
+	})
+}
+
+// --- When ---
+
+func (tc *calendarTestContext) execute_calendar(args ...string) {
+	tc.t.Helper()
+	cmd := NewCalendarCmd(func() *Client { return tc.client }, tc.buf)
+	cmd.Command.SetArgs(args)
+	tc.err = cmd.Command.Execute()
+}
+
+// --- Then ---
+
+func (tc *calendarTestContext) command_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *calendarTestContext) request_was(method, path string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, method, tc.receivedMethod)
+	assert.Equal(tc.t, path, tc.receivedPath)
+}
+
+func (tc *calendarTestContext) output_json_has(key, expected string) {
+	tc.t.Helper()
+	var result map[string]string
+	require.NoError(tc.t, json.Unmarshal(tc.buf.Bytes(), &result))
+	assert.Equal(tc.t, expected, result[key])
+}
+
+func (tc *calendarTestContext) output_contains(substr string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.buf.String(), substr)
+}
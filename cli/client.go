@@ -14,6 +14,10 @@ type Client struct {
 	apiKey     string
 	realm      string
 	httpClient *http.Client
+	// offline queues rune commands in the sync state at syncPath when the
+	// server cannot be reached, for bf sync to push later.
+	offline  bool
+	syncPath string
 }
 
 func NewClient(cfg *Config) *Client {
@@ -21,6 +25,7 @@ func NewClient(cfg *Config) *Client {
 		baseURL: cfg.URL,
 		apiKey:  cfg.APIKey,
 		realm:   cfg.Realm,
+		offline: cfg.Offline,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -78,5 +83,10 @@ func (c *Client) DoGet(path string, params map[string]string) (*http.Response, e
 }
 
 func (c *Client) DoPost(path string, body []byte) (*http.Response, error) {
-	return c.DoRequest(http.MethodPost, path, body)
+	resp, err := c.DoRequest(http.MethodPost, path, body)
+	command := strings.TrimPrefix(path, "/")
+	if err != nil && c.offline && c.syncPath != "" && offlineCommands[command] {
+		return c.queueOffline(command, body, err)
+	}
+	return resp, err
 }
@@ -12,6 +12,7 @@ type Config struct {
 	URL      string   `mapstructure:"url"`
 	APIKey   string   `mapstructure:"api_key"`
 	Realm    string   `mapstructure:"realm"`
+	Offline  bool     `mapstructure:"offline"`
 	Warnings []string `mapstructure:"-"`
 }
 
@@ -62,6 +63,7 @@ func LoadConfig(workDir, homeDir string) (*Config, error) {
 
 	v.BindEnv("url", "BIFROST_URL")
 	v.BindEnv("api_key", "BIFROST_API_KEY")
+	v.BindEnv("offline", "BIFROST_OFFLINE")
 
 	cfg := &Config{}
 	if err := v.Unmarshal(cfg); err != nil {
@@ -7,6 +7,7 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/devzeebo/bifrost/domain/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +32,7 @@ func NewCreateCmd(clientFn func() *Client, out *bytes.Buffer) *CreateCmd {
 			noBranch, _ := cmd.Flags().GetBool("no-branch")
 			branchSet := cmd.Flags().Changed("branch")
 			noBranchSet := cmd.Flags().Changed("no-branch")
+			due, _ := cmd.Flags().GetString("due")
 
 			if branchSet && noBranchSet {
 				return fmt.Errorf("--branch and --no-branch are mutually exclusive")
@@ -43,6 +45,14 @@ func NewCreateCmd(clientFn func() *Client, out *bytes.Buffer) *CreateCmd {
 			if err != nil {
 				return fmt.Errorf("invalid priority: %s", priorityStr)
 			}
+			if err := firstError(
+				validation.Title(title),
+				validation.Description(description),
+				validation.Priority(priority),
+				validation.Branch(branch),
+			); err != nil {
+				return err
+			}
 
 			body := map[string]any{
 				"title":    title,
@@ -54,6 +64,9 @@ func NewCreateCmd(clientFn func() *Client, out *bytes.Buffer) *CreateCmd {
 			if parentID != "" {
 				body["parent_id"] = parentID
 			}
+			if due != "" {
+				body["due_date"] = due
+			}
 			if noBranch {
 				body["branch"] = ""
 			} else if branchSet {
@@ -104,6 +117,7 @@ func NewCreateCmd(clientFn func() *Client, out *bytes.Buffer) *CreateCmd {
 	cmd.Flags().Bool("human", false, "human-readable output")
 	cmd.Flags().StringP("branch", "b", "", "branch name for the rune")
 	cmd.Flags().Bool("no-branch", false, "create rune without a branch")
+	cmd.Flags().String("due", "", "due date (YYYY-MM-DD)")
 
 	c.Command = cmd
 	return c
@@ -108,6 +108,21 @@ func TestCreateCommand(t *testing.T) {
 		tc.request_body_has_field("branch", "feature-x")
 	})
 
+	t.Run("sends due_date in request body when --due flag is set", func(t *testing.T) {
+		tc := newCreateTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns_created()
+		tc.client_configured()
+
+		// When
+		tc.execute_create_with_due("My Rune", "0", "2026-03-31")
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_body_has_field("due_date", "2026-03-31")
+	})
+
 	t.Run("sends empty branch in request body when --no-branch flag is set", func(t *testing.T) {
 		tc := newCreateTestContext(t)
 
@@ -168,6 +183,22 @@ func TestCreateCommand(t *testing.T) {
 		tc.error_contains("--branch and --no-branch are mutually exclusive")
 	})
 
+	t.Run("rejects an out-of-range priority without calling the server", func(t *testing.T) {
+		tc := newCreateTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns_created()
+		tc.client_configured()
+
+		// When
+		tc.execute_create("Fix bug", "9")
+
+		// Then
+		tc.command_has_error()
+		tc.error_contains("invalid priority")
+		tc.no_request_was_sent()
+	})
+
 	t.Run("returns error when server responds with error", func(t *testing.T) {
 		tc := newCreateTestContext(t)
 
@@ -176,7 +207,7 @@ func TestCreateCommand(t *testing.T) {
 		tc.client_configured()
 
 		// When
-		tc.execute_create("", "0")
+		tc.execute_create("Fix bug", "0")
 
 		// Then
 		tc.command_has_error()
@@ -287,6 +318,13 @@ func (tc *createTestContext) execute_create_without_branch_flags(title, priority
 	tc.err = cmd.Command.Execute()
 }
 
+func (tc *createTestContext) execute_create_with_due(title, priority, due string) {
+	tc.t.Helper()
+	cmd := NewCreateCmd(func() *Client { return tc.client }, tc.buf)
+	cmd.Command.SetArgs([]string{title, "-p", priority, "--no-branch", "--due", due})
+	tc.err = cmd.Command.Execute()
+}
+
 func (tc *createTestContext) execute_create_with_branch(title, priority, branch string) {
 	tc.t.Helper()
 	cmd := NewCreateCmd(func() *Client { return tc.client }, tc.buf)
@@ -320,6 +358,11 @@ func (tc *createTestContext) command_has_error() {
 	require.Error(tc.t, tc.err)
 }
 
+func (tc *createTestContext) no_request_was_sent() {
+	tc.t.Helper()
+	assert.Empty(tc.t, tc.receivedMethod)
+}
+
 func (tc *createTestContext) request_method_was(expected string) {
 	tc.t.Helper()
 	assert.Equal(tc.t, expected, tc.receivedMethod)
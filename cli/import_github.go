@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type ImportGitHubCmd struct {
+	Command *cobra.Command
+}
+
+func NewImportGitHubCmd(clientFn func() *Client, out *bytes.Buffer) *ImportGitHubCmd {
+	c := &ImportGitHubCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "import-github <owner/repo>",
+		Short: "Import GitHub issues, milestones and comments into the realm",
+		Long: "Creates a rune for every issue in the repository. Milestones become sagas,\n" +
+			"comments become notes and cross-referenced issues are linked with relates_to.\n" +
+			"Uses --token, then $GITHUB_TOKEN, then the realm's github.token setting.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			humanMode, _ := cmd.Flags().GetBool("human")
+			state, _ := cmd.Flags().GetString("state")
+			branch, _ := cmd.Flags().GetString("branch")
+			token, _ := cmd.Flags().GetString("token")
+			if token == "" {
+				token = os.Getenv("GITHUB_TOKEN")
+			}
+
+			body := map[string]string{
+				"repository": args[0],
+				"state":      state,
+				"branch":     branch,
+				"token":      token,
+			}
+
+			jsonBody, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+
+			resp, err := clientFn().DoPost("/import-github", jsonBody)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			if resp.StatusCode >= 400 {
+				var errResp map[string]string
+				if json.Unmarshal(respBody, &errResp) == nil {
+					if msg, ok := errResp["error"]; ok {
+						out.WriteString(msg)
+						return fmt.Errorf("%s", msg)
+					}
+				}
+				return fmt.Errorf("server error: %s", string(respBody))
+			}
+
+			if humanMode {
+				var result struct {
+					Issues       map[string]string `json:"issues"`
+					Milestones   map[string]string `json:"milestones"`
+					Dependencies int               `json:"dependencies"`
+					Notes        int               `json:"notes"`
+				}
+				if err := json.Unmarshal(respBody, &result); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Imported %d issue(s) and %d milestone(s) with %d note(s) and %d link(s)\n",
+					len(result.Issues), len(result.Milestones), result.Notes, result.Dependencies)
+				return nil
+			}
+
+			_, err = out.Write(respBody)
+			return err
+		},
+	}
+
+	cmd.Flags().String("state", "all", "issues to import: open, closed or all")
+	cmd.Flags().String("branch", "", "branch for top-level runes (default: main)")
+	cmd.Flags().String("token", "", "GitHub token (default: $GITHUB_TOKEN or the realm's github.token)")
+	cmd.Flags().Bool("human", false, "human-readable output")
+
+	c.Command = cmd
+	return c
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestImportGitHubCommand(t *testing.T) {
+	t.Run("sends repository and flags to /import-github", func(t *testing.T) {
+		tc := newImportGitHubTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusOK, `{"issues":{"1":"bf-a1b2"},"milestones":{},"dependencies":0,"notes":0}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_import_github("acme/app", "--state", "open", "--token", "ghp_x")
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_path_was("/api/import-github")
+		tc.request_body_has("repository", "acme/app")
+		tc.request_body_has("state", "open")
+		tc.request_body_has("token", "ghp_x")
+	})
+
+	t.Run("uses GITHUB_TOKEN when --token is not set", func(t *testing.T) {
+		tc := newImportGitHubTestContext(t)
+
+		// Given
+		t.Setenv("GITHUB_TOKEN", "ghp_env")
+		tc.server_that_captures_request_and_returns(http.StatusOK, `{"issues":{}}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_import_github("acme/app")
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_body_has("token", "ghp_env")
+	})
+
+	t.Run("outputs human-readable summary when --human flag is set", func(t *testing.T) {
+		tc := newImportGitHubTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusOK, `{"issues":{"1":"bf-a1b2","2":"bf-c3d4"},"milestones":{"3":"bf-e5f6"},"dependencies":1,"notes":4}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_import_github("acme/app", "--human")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("Imported 2 issue(s) and 1 milestone(s) with 4 note(s) and 1 link(s)")
+	})
+
+	t.Run("returns server error message", func(t *testing.T) {
+		tc := newImportGitHubTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns(http.StatusBadGateway, `{"error":"github api returned 404: Not Found"}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_import_github("acme/missing")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "github api returned 404")
+	})
+}
+
+// --- Test Context ---
+
+type importGitHubTestContext struct {
+	t *testing.T
+
+	server       *httptest.Server
+	client       *Client
+	receivedPath string
+	receivedBody map[string]string
+	buf          *bytes.Buffer
+	err          error
+}
+
+func newImportGitHubTestContext(t *testing.T) *importGitHubTestContext {
+	t.Helper()
+	t.Setenv("GITHUB_TOKEN", "")
+	return &importGitHubTestContext{
+		t:   t,
+		buf: &bytes.Buffer{},
+	}
+}
+
+// --- Given ---
+
+func (tc *importGitHubTestContext) server_that_captures_request_and_returns(status int, response string) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &tc.receivedBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+func (tc *importGitHubTestContext) client_configured() {
+	tc.t.Helper()
+	tc.client = NewClient(&Config{
+		URL:    tc.server.URL,
+		APIKey: "test-key",
+	})
+}
+
+// --- When ---
+
+func (tc *importGitHubTestContext) execute_import_github(args ...string) {
+	tc.t.Helper()
+	cmd := NewImportGitHubCmd(func() *Client { return tc.client }, tc.buf)
+	cmd.Command.SetArgs(args)
+	tc.err = cmd.Command.Execute()
+}
+
+// --- Then ---
+
+func (tc *importGitHubTestContext) command_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *importGitHubTestContext) request_path_was(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedPath)
+}
+
+func (tc *importGitHubTestContext) request_body_has(key, expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedBody[key])
+}
+
+func (tc *importGitHubTestContext) output_contains(substr string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.buf.String(), substr)
+}
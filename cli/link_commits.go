@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gitOutput runs git with the given arguments and returns its stdout.
+// Replaced in tests.
+var gitOutput = func(args ...string) ([]byte, error) {
+	return exec.Command("git", args...).Output()
+}
+
+type LinkCommitsCmd struct {
+	Command *cobra.Command
+}
+
+func NewLinkCommitsCmd(clientFn func() *Client, out *bytes.Buffer) *LinkCommitsCmd {
+	c := &LinkCommitsCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "link-commits [revision-range]",
+		Short: "Note commits on the runes their messages reference",
+		Long: "Scans commit messages for rune IDs and notes each commit on the runes it references.\n" +
+			"Defaults to the latest commit, so it can be run from a post-commit hook:\n\n" +
+			"  echo 'bf link-commits' >> .git/hooks/post-commit",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			humanMode, _ := cmd.Flags().GetBool("human")
+			repo, _ := cmd.Flags().GetString("repo")
+			branch, _ := cmd.Flags().GetString("branch")
+
+			logArgs := []string{"log", "--format=%H%x1f%an%x1f%B%x1e"}
+			if len(args) == 1 {
+				logArgs = append(logArgs, args[0])
+			} else {
+				logArgs = append(logArgs, "-1")
+			}
+			logOut, err := gitOutput(logArgs...)
+			if err != nil {
+				return fmt.Errorf("git log: %w", err)
+			}
+
+			if branch == "" {
+				if head, err := gitOutput("rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+					branch = strings.TrimSpace(string(head))
+				}
+			}
+
+			body := map[string]any{
+				"repository": repo,
+				"branch":     branch,
+				"commits":    parseGitLog(logOut),
+			}
+
+			jsonBody, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+
+			resp, err := clientFn().DoPost("/link-commits", jsonBody)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+
+			if resp.StatusCode >= 400 {
+				var errResp map[string]string
+				if json.Unmarshal(respBody, &errResp) == nil {
+					if msg, ok := errResp["error"]; ok {
+						out.WriteString(msg)
+						return fmt.Errorf("%s", msg)
+					}
+				}
+				return fmt.Errorf("server error: %s", string(respBody))
+			}
+
+			if humanMode {
+				var result struct {
+					Linked map[string][]string `json:"linked"`
+				}
+				if err := json.Unmarshal(respBody, &result); err != nil {
+					return err
+				}
+				if len(result.Linked) == 0 {
+					fmt.Fprintf(out, "No runes referenced")
+					return nil
+				}
+				ids := make([]string, 0, len(result.Linked))
+				for id := range result.Linked {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+				for _, id := range ids {
+					fmt.Fprintf(out, "%s: %d commit(s)\n", id, len(result.Linked[id]))
+				}
+				return nil
+			}
+
+			_, err = out.Write(respBody)
+			return err
+		},
+	}
+
+	cmd.Flags().String("repo", "", "repository name to include in notes")
+	cmd.Flags().String("branch", "", "branch name to include in notes (default: current branch)")
+	cmd.Flags().Bool("human", false, "human-readable output")
+
+	c.Command = cmd
+	return c
+}
+
+// parseGitLog parses output of git log --format=%H%x1f%an%x1f%B%x1e.
+func parseGitLog(data []byte) []map[string]string {
+	commits := []map[string]string{}
+	for _, record := range strings.Split(string(data), "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, map[string]string{
+			"sha":     fields[0],
+			"author":  fields[1],
+			"message": strings.TrimSpace(fields[2]),
+		})
+	}
+	return commits
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestLinkCommitsCommand(t *testing.T) {
+	t.Run("sends latest commit to /link-commits by default", func(t *testing.T) {
+		tc := newLinkCommitsTestContext(t)
+
+		// Given
+		tc.git_log_returns("abc123\x1fodin\x1fFix bf-a1b2\n\nDetails\n\x1e")
+		tc.server_that_captures_request_and_returns(`{"linked":{"bf-a1b2":["abc123"]}}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_link_commits()
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_path_was("/api/link-commits")
+		tc.git_log_was_called_with("-1")
+		tc.request_body_has_branch("feature/login")
+		tc.request_body_has_commit(0, "abc123", "odin", "Fix bf-a1b2\n\nDetails")
+	})
+
+	t.Run("passes revision range to git log", func(t *testing.T) {
+		tc := newLinkCommitsTestContext(t)
+
+		// Given
+		tc.git_log_returns("a\x1fodin\x1ffirst\x1e\nb\x1ffrigg\x1fsecond\x1e")
+		tc.server_that_captures_request_and_returns(`{"linked":{}}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_link_commits("origin/main..HEAD")
+
+		// Then
+		tc.command_has_no_error()
+		tc.git_log_was_called_with("origin/main..HEAD")
+		tc.request_body_has_commit(1, "b", "frigg", "second")
+	})
+
+	t.Run("outputs human-readable summary when --human flag is set", func(t *testing.T) {
+		tc := newLinkCommitsTestContext(t)
+
+		// Given
+		tc.git_log_returns("abc123\x1fodin\x1fFix bf-a1b2\x1e")
+		tc.server_that_captures_request_and_returns(`{"linked":{"bf-a1b2":["abc123"]}}`)
+		tc.client_configured()
+
+		// When
+		tc.execute_link_commits("--human")
+
+		// Then
+		tc.command_has_no_error()
+		tc.output_contains("bf-a1b2: 1 commit(s)")
+	})
+}
+
+// --- Test Context ---
+
+type linkCommitsTestContext struct {
+	t *testing.T
+
+	server       *httptest.Server
+	client       *Client
+	receivedPath string
+	receivedBody struct {
+		Branch  string              `json:"branch"`
+		Commits []map[string]string `json:"commits"`
+	}
+	gitLogArgs []string
+	buf        *bytes.Buffer
+	err        error
+}
+
+func newLinkCommitsTestContext(t *testing.T) *linkCommitsTestContext {
+	t.Helper()
+	return &linkCommitsTestContext{
+		t:   t,
+		buf: &bytes.Buffer{},
+	}
+}
+
+// --- Given ---
+
+func (tc *linkCommitsTestContext) git_log_returns(output string) {
+	tc.t.Helper()
+	original := gitOutput
+	tc.t.Cleanup(func() { gitOutput = original })
+	gitOutput = func(args ...string) ([]byte, error) {
+		if args[0] == "rev-parse" {
+			return []byte("feature/login\n"), nil
+		}
+		tc.gitLogArgs = args
+		return []byte(output), nil
+	}
+}
+
+func (tc *linkCommitsTestContext) server_that_captures_request_and_returns(response string) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &tc.receivedBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+func (tc *linkCommitsTestContext) client_configured() {
+	tc.t.Helper()
+	tc.client = NewClient(&Config{
+		URL:    tc.server.URL,
+		APIKey: "test-key",
+	})
+}
+
+// --- When ---
+
+func (tc *linkCommitsTestContext) execute_link_commits(args ...string) {
+	tc.t.Helper()
+	cmd := NewLinkCommitsCmd(func() *Client { return tc.client }, tc.buf)
+	cmd.Command.SetArgs(args)
+	tc.err = cmd.Command.Execute()
+}
+
+// --- Then ---
+
+func (tc *linkCommitsTestContext) command_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *linkCommitsTestContext) request_path_was(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedPath)
+}
+
+func (tc *linkCommitsTestContext) git_log_was_called_with(lastArg string) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.gitLogArgs)
+	assert.Equal(tc.t, lastArg, tc.gitLogArgs[len(tc.gitLogArgs)-1])
+}
+
+func (tc *linkCommitsTestContext) request_body_has_branch(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedBody.Branch)
+}
+
+func (tc *linkCommitsTestContext) request_body_has_commit(index int, sha, author, message string) {
+	tc.t.Helper()
+	require.Greater(tc.t, len(tc.receivedBody.Commits), index)
+	commit := tc.receivedBody.Commits[index]
+	assert.Equal(tc.t, sha, commit["sha"])
+	assert.Equal(tc.t, author, commit["author"])
+	assert.Equal(tc.t, message, commit["message"])
+}
+
+func (tc *linkCommitsTestContext) output_contains(substr string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.buf.String(), substr)
+}
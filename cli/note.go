@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/devzeebo/bifrost/domain/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,9 @@ func NewNoteCmd(clientFn func() *Client, out *bytes.Buffer) *NoteCmd {
 			id := args[0]
 			text := args[1]
 			humanMode, _ := cmd.Flags().GetBool("human")
+			if err := validation.Note(text); err != nil {
+				return err
+			}
 
 			body := map[string]string{
 				"rune_id": id,
@@ -50,6 +50,7 @@ func NewRootCmd() *RootCmd {
 
 			root.Cfg = cfg
 			root.Client = NewClient(cfg)
+			root.Client.syncPath = syncStatePath(home, cfg.URL, cfg.Realm)
 			return nil
 		},
 	}
@@ -5,6 +5,17 @@ import (
 	"os"
 )
 
+// firstError returns the first non-nil error, so a command can run several
+// validation checks at once.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func RegisterRuneCommands(root *RootCmd, out *bytes.Buffer) {
 	clientFn := func() *Client { return root.Client }
 
@@ -19,7 +30,11 @@ func RegisterRuneCommands(root *RootCmd, out *bytes.Buffer) {
 	root.Command.AddCommand(NewForgeCmd(clientFn, out).Command)
 	root.Command.AddCommand(NewUpdateCmd(clientFn, out).Command)
 	root.Command.AddCommand(NewNoteCmd(clientFn, out).Command)
+	root.Command.AddCommand(NewLinkCommitsCmd(clientFn, out).Command)
+	root.Command.AddCommand(NewImportGitHubCmd(clientFn, out).Command)
+	root.Command.AddCommand(NewCalendarCmd(clientFn, out).Command)
 	root.Command.AddCommand(NewEventsCmd(clientFn, out).Command)
+	root.Command.AddCommand(NewSyncCmd(clientFn, out).Command)
 	root.Command.AddCommand(NewSweepCmd(clientFn, out, os.Stdin).Command)
 	root.Command.AddCommand(NewShatterCmd(clientFn, out, os.Stdin).Command)
 }
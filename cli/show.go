@@ -64,6 +64,9 @@ func NewShowCmd(clientFn func() *Client, out *bytes.Buffer) *ShowCmd {
 					if branch, ok := result["branch"].(string); ok && branch != "" {
 						fmt.Fprintf(w, "Branch:      %s\n", branch)
 					}
+					if due, ok := result["due_date"].(string); ok && due != "" {
+						fmt.Fprintf(w, "Due:         %s\n", due)
+					}
 					if desc != "" {
 						fmt.Fprintf(w, "Description: %s\n", desc)
 					}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxSyncPush matches the number of commands the server accepts per push.
+const maxSyncPush = 100
+
+// offlineCommands are the commands queued for bf sync, in offline mode,
+// when the server cannot be reached.
+var offlineCommands = map[string]bool{
+	"create-rune":       true,
+	"update-rune":       true,
+	"claim-rune":        true,
+	"unclaim-rune":      true,
+	"fulfill-rune":      true,
+	"seal-rune":         true,
+	"forge-rune":        true,
+	"add-dependency":    true,
+	"remove-dependency": true,
+	"add-note":          true,
+	"shatter-rune":      true,
+}
+
+// syncState is what bf sync keeps per server and realm: the position pulled
+// to and the commands queued while offline.
+type syncState struct {
+	Position int64         `json:"position"`
+	Commands []syncCommand `json:"commands"`
+}
+
+type syncCommand struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	Command        string          `json:"command"`
+	Payload        json.RawMessage `json:"payload"`
+	BasePosition   int64           `json:"base_position,omitempty"`
+	QueuedAt       time.Time       `json:"queued_at"`
+}
+
+type syncResult struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	Command        string          `json:"command"`
+	Status         string          `json:"status"`
+	StatusCode     int             `json:"status_code,omitempty"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	Error          string          `json:"error,omitempty"`
+}
+
+type syncPull struct {
+	Events   []json.RawMessage `json:"events"`
+	Position int64             `json:"position"`
+	More     bool              `json:"more"`
+}
+
+// syncStatePath returns the sync state file of a server and realm. Pulled
+// events are kept next to it, one JSON object per line.
+func syncStatePath(homeDir, url, realm string) string {
+	sum := sha256.Sum256([]byte(normalizeURL(url) + "\n" + realm))
+	return filepath.Join(configDir(homeDir), "sync", hex.EncodeToString(sum[:8])+".json")
+}
+
+func syncEventsPath(statePath string) string {
+	return strings.TrimSuffix(statePath, ".json") + ".events.jsonl"
+}
+
+func loadSyncState(path string) (*syncState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &syncState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sync state: %w", err)
+	}
+	var state syncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing sync state: %w", err)
+	}
+	return &state, nil
+}
+
+func (s *syncState) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating sync directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing sync state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// queueOffline adds a command that could not reach the server to the sync
+// state and answers it with 202, as the server does for queued commands.
+func (c *Client) queueOffline(command string, body []byte, cause error) (*http.Response, error) {
+	state, err := loadSyncState(c.syncPath)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	queued := syncCommand{
+		IdempotencyKey: hex.EncodeToString(key),
+		Command:        command,
+		Payload:        json.RawMessage(body),
+		BasePosition:   state.Position,
+		QueuedAt:       time.Now().UTC(),
+	}
+	state.Commands = append(state.Commands, queued)
+	if err := state.save(c.syncPath); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Offline (%v): queued %s, run bf sync to push it\n", cause, command)
+
+	respBody, _ := json.Marshal(map[string]any{
+		"queued":          true,
+		"command":         command,
+		"idempotency_key": queued.IdempotencyKey,
+		"pending":         len(state.Commands),
+	})
+	return &http.Response{
+		StatusCode: http.StatusAccepted,
+		Status:     "202 Accepted",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(respBody)),
+	}, nil
+}
+
+type SyncCmd struct {
+	Command *cobra.Command
+}
+
+func NewSyncCmd(clientFn func() *Client, out *bytes.Buffer) *SyncCmd {
+	c := &SyncCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Push commands queued offline and pull new rune events",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			humanMode, _ := cmd.Flags().GetBool("human")
+			force, _ := cmd.Flags().GetBool("force")
+
+			client := clientFn()
+			if client.syncPath == "" {
+				return fmt.Errorf("sync state is not available")
+			}
+			state, err := loadSyncState(client.syncPath)
+			if err != nil {
+				return err
+			}
+
+			results := []syncResult{}
+			for start := 0; start < len(state.Commands); start += maxSyncPush {
+				batch := state.Commands[start:min(start+maxSyncPush, len(state.Commands))]
+				pushed, err := pushSyncCommands(client, batch, force)
+				if err != nil {
+					return err
+				}
+				results = append(results, pushed...)
+			}
+
+			// Conflicts and server failures stay queued for the next sync.
+			retry := map[string]bool{}
+			for _, r := range results {
+				if r.Status == "conflict" || r.Status == "failed" {
+					retry[r.IdempotencyKey] = true
+				}
+			}
+			pending := []syncCommand{}
+			for _, queued := range state.Commands {
+				if retry[queued.IdempotencyKey] {
+					pending = append(pending, queued)
+				}
+			}
+			state.Commands = pending
+			if err := state.save(client.syncPath); err != nil {
+				return err
+			}
+
+			pulled, err := pullSyncEvents(client, state)
+			if err != nil {
+				return err
+			}
+
+			respBody, err := json.Marshal(map[string]any{
+				"results":  results,
+				"pulled":   pulled,
+				"position": state.Position,
+				"pending":  len(state.Commands),
+			})
+			if err != nil {
+				return err
+			}
+			return PrintOutput(out, respBody, humanMode, func(w *bytes.Buffer, data []byte) {
+				for _, r := range results {
+					fmt.Fprintf(w, "%-9s %s", r.Status, r.Command)
+					if r.Error != "" {
+						fmt.Fprintf(w, ": %s", r.Error)
+					}
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintf(w, "Pulled %d events, at position %d, %d commands pending", pulled, state.Position, len(state.Commands))
+			})
+		},
+	}
+
+	cmd.Flags().Bool("human", false, "human-readable output")
+	cmd.Flags().Bool("force", false, "push queued commands even if their runes changed on the server")
+
+	c.Command = cmd
+	return c
+}
+
+// pushSyncCommands sends queued commands to the server. With force, the
+// base positions are left out so the server does not check for conflicts.
+func pushSyncCommands(client *Client, batch []syncCommand, force bool) ([]syncResult, error) {
+	commands := make([]syncCommand, len(batch))
+	copy(commands, batch)
+	if force {
+		for i := range commands {
+			commands[i].BasePosition = 0
+		}
+	}
+	body, err := json.Marshal(map[string]any{"commands": commands})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.DoPost("/sync", body)
+	if err != nil {
+		return nil, err
+	}
+	var pushed struct {
+		Results []syncResult `json:"results"`
+	}
+	if err := decodeSyncResponse(resp, &pushed); err != nil {
+		return nil, err
+	}
+	return pushed.Results, nil
+}
+
+// pullSyncEvents appends the events after the state's position to the
+// events file, advancing the position as each page is written.
+func pullSyncEvents(client *Client, state *syncState) (int, error) {
+	pulled := 0
+	for {
+		resp, err := client.DoGet("/sync", map[string]string{"since": strconv.FormatInt(state.Position, 10)})
+		if err != nil {
+			return pulled, err
+		}
+		var page syncPull
+		if err := decodeSyncResponse(resp, &page); err != nil {
+			return pulled, err
+		}
+		if err := appendSyncEvents(syncEventsPath(client.syncPath), page.Events); err != nil {
+			return pulled, err
+		}
+		pulled += len(page.Events)
+		state.Position = page.Position
+		if err := state.save(client.syncPath); err != nil {
+			return pulled, err
+		}
+		if !page.More {
+			return pulled, nil
+		}
+	}
+}
+
+func appendSyncEvents(path string, events []json.RawMessage) error {
+	if len(events) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("writing sync events: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, evt := range events {
+		w.Write(evt)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("writing sync events: %w", err)
+	}
+	return f.Close()
+}
+
+func decodeSyncResponse(resp *http.Response, dst any) error {
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		var errResp map[string]string
+		if json.Unmarshal(respBody, &errResp) == nil {
+			if msg, ok := errResp["error"]; ok {
+				return fmt.Errorf("%s", msg)
+			}
+		}
+		return fmt.Errorf("server error: %s", string(respBody))
+	}
+	return json.Unmarshal(respBody, dst)
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSyncCommand(t *testing.T) {
+	t.Run("pushes queued commands and keeps the conflicting ones", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.sync_state(syncState{Position: 4, Commands: []syncCommand{
+			{IdempotencyKey: "k1", Command: "add-note", Payload: json.RawMessage(`{"rune_id":"bf-a1","text":"hi"}`), BasePosition: 4},
+			{IdempotencyKey: "k2", Command: "claim-rune", Payload: json.RawMessage(`{"id":"bf-a2","claimant":"alice"}`), BasePosition: 4},
+		}})
+		tc.server_with_push_results(`[{"idempotency_key":"k1","command":"add-note","status":"applied","status_code":204},{"idempotency_key":"k2","command":"claim-rune","status":"conflict","error":"rune bf-a2 changed at position 6, after position 4"}]`)
+		tc.server_with_pull_pages(`{"events":[],"position":4,"more":false}`)
+		tc.client_configured(false)
+
+		// When
+		tc.execute_sync()
+
+		// Then
+		tc.command_has_no_error()
+		tc.pushed_base_positions_are(4, 4)
+		tc.pending_commands_are("k2")
+		tc.output_json_has("pending", float64(1))
+	})
+
+	t.Run("pulls events page by page into the events file", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.sync_state(syncState{Position: 2})
+		tc.server_with_pull_pages(
+			`{"events":[{"global_position":3,"event_type":"RuneCreated"}],"position":5,"more":true}`,
+			`{"events":[{"global_position":7,"event_type":"RuneForged"}],"position":7,"more":false}`,
+		)
+		tc.client_configured(false)
+
+		// When
+		tc.execute_sync("--human")
+
+		// Then
+		tc.command_has_no_error()
+		assert.Equal(t, []string{"2", "5"}, tc.pulledSince)
+		assert.Empty(t, tc.pushes, "nothing was queued")
+		tc.position_is(7)
+		tc.events_file_has_lines(2)
+		assert.Contains(t, tc.buf.String(), "Pulled 2 events, at position 7, 0 commands pending")
+	})
+
+	t.Run("pushes without base positions with --force", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.sync_state(syncState{Position: 4, Commands: []syncCommand{
+			{IdempotencyKey: "k1", Command: "claim-rune", Payload: json.RawMessage(`{"id":"bf-a2","claimant":"alice"}`), BasePosition: 4},
+		}})
+		tc.server_with_push_results(`[{"idempotency_key":"k1","command":"claim-rune","status":"applied","status_code":204}]`)
+		tc.server_with_pull_pages(`{"events":[],"position":4,"more":false}`)
+		tc.client_configured(false)
+
+		// When
+		tc.execute_sync("--force")
+
+		// Then
+		tc.command_has_no_error()
+		tc.pushed_base_positions_are(0)
+		tc.pending_commands_are()
+	})
+}
+
+func TestClientOfflineQueue(t *testing.T) {
+	t.Run("queues rune commands while the server is unreachable", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.sync_state(syncState{Position: 9})
+		tc.unreachable_server()
+		tc.client_configured(true)
+
+		// When
+		resp, err := tc.client.DoPost("/add-note", []byte(`{"rune_id":"bf-a1","text":"on a plane"}`))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		state := tc.saved_state()
+		require.Len(t, state.Commands, 1)
+		assert.Equal(t, "add-note", state.Commands[0].Command)
+		assert.Equal(t, int64(9), state.Commands[0].BasePosition)
+		assert.NotEmpty(t, state.Commands[0].IdempotencyKey)
+		assert.JSONEq(t, `{"rune_id":"bf-a1","text":"on a plane"}`, string(state.Commands[0].Payload))
+	})
+
+	t.Run("returns the connection error when offline mode is off", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.unreachable_server()
+		tc.client_configured(false)
+
+		// When
+		_, err := tc.client.DoPost("/add-note", []byte(`{}`))
+
+		// Then
+		require.Error(t, err)
+		tc.pending_commands_are()
+	})
+
+	t.Run("does not queue commands outside the rune commands", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.unreachable_server()
+		tc.client_configured(true)
+
+		// When
+		_, err := tc.client.DoPost("/calendar-token", nil)
+
+		// Then
+		require.Error(t, err)
+		tc.pending_commands_are()
+	})
+}
+
+// --- Test Context ---
+
+type syncTestContext struct {
+	t *testing.T
+
+	statePath   string
+	pushResults string
+	pullPages   []string
+	pushes      []map[string]any
+	pulledSince []string
+
+	serverURL string
+	client    *Client
+	buf       *bytes.Buffer
+	err       error
+}
+
+func newSyncTestContext(t *testing.T) *syncTestContext {
+	t.Helper()
+	return &syncTestContext{
+		t:         t,
+		statePath: filepath.Join(t.TempDir(), "sync", "state.json"),
+		buf:       &bytes.Buffer{},
+	}
+}
+
+// --- Given ---
+
+func (tc *syncTestContext) sync_state(state syncState) {
+	tc.t.Helper()
+	require.NoError(tc.t, state.save(tc.statePath))
+}
+
+func (tc *syncTestContext) server_with_push_results(results string) {
+	tc.t.Helper()
+	tc.pushResults = results
+}
+
+func (tc *syncTestContext) server_with_pull_pages(pages ...string) {
+	tc.t.Helper()
+	tc.pullPages = pages
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			var push map[string]any
+			_ = json.Unmarshal(body, &push)
+			tc.pushes = append(tc.pushes, push)
+			_, _ = w.Write([]byte(`{"results":` + tc.pushResults + `}`))
+			return
+		}
+		tc.pulledSince = append(tc.pulledSince, r.URL.Query().Get("since"))
+		page := tc.pullPages[0]
+		tc.pullPages = tc.pullPages[1:]
+		_, _ = w.Write([]byte(page))
+	}))
+	tc.t.Cleanup(server.Close)
+	tc.serverURL = server.URL
+}
+
+func (tc *syncTestContext) unreachable_server() {
+	tc.t.Helper()
+	server := httptest.NewServer(http.NotFoundHandler())
+	tc.serverURL = server.URL
+	server.Close()
+}
+
+func (tc *syncTestContext) client_configured(offline bool) {
+	tc.t.Helper()
+	tc.client = NewClient(&Config{URL: tc.serverURL, APIKey: "test-key", Realm: "bf-r1", Offline: offline})
+	tc.client.syncPath = tc.statePath
+}
+
+// --- When ---
+
+func (tc *syncTestContext) execute_sync(args ...string) {
+	tc.t.Helper()
+	cmd := NewSyncCmd(func() *Client { return tc.client }, tc.buf)
+	cmd.Command.SetArgs(args)
+	tc.err = cmd.Command.Execute()
+}
+
+// --- Then ---
+
+func (tc *syncTestContext) command_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *syncTestContext) saved_state() *syncState {
+	tc.t.Helper()
+	state, err := loadSyncState(tc.statePath)
+	require.NoError(tc.t, err)
+	return state
+}
+
+func (tc *syncTestContext) pushed_base_positions_are(expected ...float64) {
+	tc.t.Helper()
+	require.Len(tc.t, tc.pushes, 1)
+	commands, _ := tc.pushes[0]["commands"].([]any)
+	var got []float64
+	for _, c := range commands {
+		cmd, _ := c.(map[string]any)
+		pos, _ := cmd["base_position"].(float64)
+		got = append(got, pos)
+	}
+	assert.Equal(tc.t, expected, got)
+}
+
+func (tc *syncTestContext) pending_commands_are(keys ...string) {
+	tc.t.Helper()
+	var got []string
+	for _, c := range tc.saved_state().Commands {
+		got = append(got, c.IdempotencyKey)
+	}
+	assert.Equal(tc.t, keys, got)
+}
+
+func (tc *syncTestContext) position_is(expected int64) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.saved_state().Position)
+}
+
+func (tc *syncTestContext) events_file_has_lines(expected int) {
+	tc.t.Helper()
+	f, err := os.Open(syncEventsPath(tc.statePath))
+	require.NoError(tc.t, err)
+	defer f.Close()
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+	}
+	assert.Equal(tc.t, expected, lines)
+}
+
+func (tc *syncTestContext) output_json_has(key string, expected any) {
+	tc.t.Helper()
+	var result map[string]any
+	require.NoError(tc.t, json.Unmarshal(tc.buf.Bytes(), &result))
+	assert.Equal(tc.t, expected, result[key])
+}
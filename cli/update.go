@@ -7,6 +7,7 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/devzeebo/bifrost/domain/validation"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +30,9 @@ func NewUpdateCmd(clientFn func() *Client, out *bytes.Buffer) *UpdateCmd {
 
 			if cmd.Flags().Changed("title") {
 				title, _ := cmd.Flags().GetString("title")
+				if err := validation.Title(title); err != nil {
+					return err
+				}
 				body["title"] = title
 			}
 			if cmd.Flags().Changed("priority") {
@@ -37,16 +41,29 @@ func NewUpdateCmd(clientFn func() *Client, out *bytes.Buffer) *UpdateCmd {
 				if err != nil {
 					return fmt.Errorf("invalid priority: %s", priorityStr)
 				}
+				if err := validation.Priority(p); err != nil {
+					return err
+				}
 				body["priority"] = p
 			}
 			if cmd.Flags().Changed("description") {
 				desc, _ := cmd.Flags().GetString("description")
+				if err := validation.Description(desc); err != nil {
+					return err
+				}
 				body["description"] = desc
 			}
 			if cmd.Flags().Changed("branch") {
 				branch, _ := cmd.Flags().GetString("branch")
+				if err := validation.Branch(branch); err != nil {
+					return err
+				}
 				body["branch"] = branch
 			}
+			if cmd.Flags().Changed("due") {
+				due, _ := cmd.Flags().GetString("due")
+				body["due_date"] = due
+			}
 
 			jsonBody, err := json.Marshal(body)
 			if err != nil {
@@ -87,6 +104,7 @@ func NewUpdateCmd(clientFn func() *Client, out *bytes.Buffer) *UpdateCmd {
 	cmd.Flags().String("priority", "", "new priority (0-4)")
 	cmd.Flags().StringP("description", "d", "", "new description")
 	cmd.Flags().String("branch", "", "branch name")
+	cmd.Flags().String("due", "", `due date (YYYY-MM-DD, "" to clear)`)
 	cmd.Flags().Bool("human", false, "human-readable output")
 
 	c.Command = cmd
@@ -93,6 +93,21 @@ func TestUpdateCommand(t *testing.T) {
 		tc.request_body_has_field("branch", "feature/my-branch")
 	})
 
+	t.Run("includes due_date when --due flag is set", func(t *testing.T) {
+		tc := newUpdateTestContext(t)
+
+		// Given
+		tc.server_that_captures_request_and_returns_no_content()
+		tc.client_configured()
+
+		// When
+		tc.execute_update("bf-abc", "--due", "2026-03-31")
+
+		// Then
+		tc.command_has_no_error()
+		tc.request_body_has_field("due_date", "2026-03-31")
+	})
+
 	t.Run("omits branch when --branch flag is not set", func(t *testing.T) {
 		tc := newUpdateTestContext(t)
 
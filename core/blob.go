@@ -0,0 +1,20 @@
+package core
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BlobStore holds attachment bytes outside the event and projection stores.
+// Downloads are served by the backend through presigned URLs, so the bytes
+// never pass through the database or the API server.
+type BlobStore interface {
+	// Put uploads size bytes from body under key, replacing any existing blob.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes the blob under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL that downloads the blob without credentials
+	// until expires has elapsed.
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+}
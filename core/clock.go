@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the current time. Handlers and stores read it rather than
+// calling time.Now so tests can pin timestamps.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock backed by time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a Clock that always reports t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+type clockKey struct{}
+
+// ContextWithClock returns a copy of ctx whose handlers read the time from
+// clock.
+func ContextWithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFromContext returns the Clock set by ContextWithClock, or SystemClock
+// if there is none.
+func ClockFromContext(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return SystemClock
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestClockFromContext(t *testing.T) {
+	t.Run("returns the clock set on the context", func(t *testing.T) {
+		tc := newClockTestContext(t)
+
+		// Given
+		tc.a_context_with_fixed_clock(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+
+		// When
+		tc.time_is_read()
+
+		// Then
+		tc.time_is(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	})
+
+	t.Run("falls back to the system clock", func(t *testing.T) {
+		tc := newClockTestContext(t)
+
+		// Given
+		before := time.Now()
+
+		// When
+		tc.time_is_read()
+
+		// Then
+		assert.False(t, tc.now.Before(before))
+		assert.WithinDuration(t, time.Now(), tc.now, time.Second)
+	})
+}
+
+// --- Test Context ---
+
+type clockTestContext struct {
+	t   *testing.T
+	ctx context.Context
+	now time.Time
+}
+
+func newClockTestContext(t *testing.T) *clockTestContext {
+	t.Helper()
+	return &clockTestContext{t: t, ctx: context.Background()}
+}
+
+// --- Given ---
+
+func (tc *clockTestContext) a_context_with_fixed_clock(t time.Time) {
+	tc.t.Helper()
+	tc.ctx = ContextWithClock(tc.ctx, FixedClock(t))
+}
+
+// --- When ---
+
+func (tc *clockTestContext) time_is_read() {
+	tc.t.Helper()
+	tc.now = ClockFromContext(tc.ctx).Now()
+}
+
+// --- Then ---
+
+func (tc *clockTestContext) time_is(expected time.Time) {
+	tc.t.Helper()
+	assert.True(tc.t, expected.Equal(tc.now), "expected %s, got %s", expected, tc.now)
+}
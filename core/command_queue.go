@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Queued command statuses.
+const (
+	CommandPending   = "pending"
+	CommandRunning   = "running"
+	CommandSucceeded = "succeeded"
+	CommandFailed    = "failed"
+)
+
+// QueuedCommand is a command accepted for asynchronous processing, along with
+// the identity it was accepted under and, once processed, its outcome.
+// Headers holds the request headers the command is replayed with, such as
+// its Idempotency-Key. Position is the global position of the last event the
+// command appended, or zero when it appended none.
+type QueuedCommand struct {
+	ID         string            `json:"id"`
+	RealmID    string            `json:"realm_id"`
+	AccountID  string            `json:"account_id,omitempty"`
+	Role       string            `json:"role,omitempty"`
+	Command    string            `json:"command"`
+	Payload    json.RawMessage   `json:"payload,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Status     string            `json:"status"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Result     json.RawMessage   `json:"result,omitempty"`
+	Position   int64             `json:"position,omitempty"`
+	EnqueuedAt time.Time         `json:"enqueued_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// CommandQueue durably holds commands until a worker processes them.
+type CommandQueue interface {
+	// Enqueue adds cmd as pending.
+	Enqueue(ctx context.Context, cmd QueuedCommand) error
+	// Claim marks the oldest pending command as running and returns it, or
+	// returns false when nothing is pending.
+	Claim(ctx context.Context) (QueuedCommand, bool, error)
+	// Complete records the outcome of a running command and the position
+	// of the last event it appended.
+	Complete(ctx context.Context, id string, status string, statusCode int, position int64, result json.RawMessage) error
+	// GetCommand returns a command by ID, or a NotFoundError.
+	GetCommand(ctx context.Context, id string) (QueuedCommand, error)
+	// Pending counts commands not yet processed, including running ones.
+	Pending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrProjectorHalted is returned for a projector stopped at an event by a
+// HaltProjector policy. Catch-up tries the event again on its next pass.
+var ErrProjectorHalted = errors.New("projector halted")
+
+// FailureAction is what catch-up does with an event a projector still
+// fails to handle once its retries are spent.
+type FailureAction string
+
+const (
+	// SkipEvent records the event as a dead letter and goes on with the
+	// next one.
+	SkipEvent FailureAction = "skip"
+	// HaltProjector records the event as a dead letter and keeps the
+	// projector's checkpoint before it, so its read model stops there
+	// until the projector is fixed.
+	HaltProjector FailureAction = "halt"
+)
+
+// ErrorPolicy is how catch-up treats a projector that returns an error or
+// panics on an event. The zero policy skips the event straight away.
+type ErrorPolicy struct {
+	Retries int           // Times the event is handed to the projector again
+	Action  FailureAction // Taken once the retries are spent; SkipEvent when empty
+}
+
+// ParseErrorPolicy parses "skip", "halt", or either after "retry:<n>:",
+// such as "retry:3:halt". "retry:<n>" alone retries, then skips.
+func ParseErrorPolicy(s string) (ErrorPolicy, error) {
+	var policy ErrorPolicy
+	rest := strings.TrimSpace(s)
+	if after, ok := strings.CutPrefix(rest, "retry:"); ok {
+		count, action, _ := strings.Cut(after, ":")
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return ErrorPolicy{}, fmt.Errorf("error policy %q must retry a positive number of times", s)
+		}
+		policy.Retries = n
+		rest = action
+	}
+	if rest == "" && policy.Retries > 0 {
+		rest = string(SkipEvent)
+	}
+	switch action := FailureAction(rest); action {
+	case SkipEvent, HaltProjector:
+		policy.Action = action
+		return policy, nil
+	default:
+		return ErrorPolicy{}, fmt.Errorf("error policy %q must be skip, halt or retry:<n>[:skip|halt]", s)
+	}
+}
+
+func (p ErrorPolicy) String() string {
+	action := p.Action
+	if action == "" {
+		action = SkipEvent
+	}
+	if p.Retries > 0 {
+		return fmt.Sprintf("retry:%d:%s", p.Retries, action)
+	}
+	return string(action)
+}
+
+// DeadLetter is an event a projector failed to handle. It names the event
+// rather than copying it, so events of encrypted realms are not stored in
+// the clear beside the event store.
+type DeadLetter struct {
+	RealmID        string    `json:"realm_id"`
+	Projector      string    `json:"projector"`
+	GlobalPosition int64     `json:"global_position"`
+	StreamID       string    `json:"stream_id"`
+	Version        int       `json:"version"`
+	EventType      string    `json:"event_type"`
+	Error          string    `json:"error"`
+	Attempts       int       `json:"attempts"`
+	Halted         bool      `json:"halted"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+// DeadLetterStore keeps the events projectors failed to handle.
+type DeadLetterStore interface {
+	// RecordDeadLetter stores a dead letter. One already recorded for the
+	// same realm, projector and event is replaced, adding up their
+	// attempts.
+	RecordDeadLetter(ctx context.Context, letter DeadLetter) error
+	// ListDeadLetters returns the realm's dead letters in event order,
+	// only those of projector unless it is empty.
+	ListDeadLetters(ctx context.Context, realmID, projector string) ([]DeadLetter, error)
+}
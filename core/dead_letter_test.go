@@ -0,0 +1,251 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestParseErrorPolicy(t *testing.T) {
+	t.Run("parses the policies", func(t *testing.T) {
+		for spec, expected := range map[string]ErrorPolicy{
+			"skip":         {Action: SkipEvent},
+			"halt":         {Action: HaltProjector},
+			"retry:3":      {Retries: 3, Action: SkipEvent},
+			"retry:2:halt": {Retries: 2, Action: HaltProjector},
+		} {
+			policy, err := ParseErrorPolicy(spec)
+			require.NoError(t, err, spec)
+			assert.Equal(t, expected, policy, spec)
+			assert.Equal(t, spec, strings.TrimSuffix(policy.String(), ":skip"), spec)
+		}
+	})
+
+	t.Run("rejects anything else", func(t *testing.T) {
+		for _, spec := range []string{"", "ignore", "retry", "retry:0", "retry:-1:halt", "retry:2:ignore"} {
+			_, err := ParseErrorPolicy(spec)
+			assert.Error(t, err, spec)
+		}
+	})
+}
+
+func TestProjectionEngine_ErrorPolicy(t *testing.T) {
+	t.Run("skips a failing event and records it as a dead letter", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 3)
+		tc.a_dead_letter_store()
+		tc.a_projector_failing_on("rune_detail", "evt-2", nil)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "rune_detail", []int64{3})
+		tc.dead_letters_are(DeadLetter{
+			RealmID: "realm-1", Projector: "rune_detail", GlobalPosition: 2, EventType: "evt-2",
+			Error: "broken on evt-2", Attempts: 1,
+		})
+	})
+
+	t.Run("retries an event before giving up on it", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 2)
+		tc.a_dead_letter_store()
+		tc.error_policy("retry:2")
+		tc.a_projector_failing_on("rune_detail", "evt-1", nil)
+		tc.projector_recovers_after(2)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "rune_detail", []int64{2})
+		tc.dead_letters_are()
+	})
+
+	t.Run("halts a projector at the event it fails on", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 3)
+		tc.a_dead_letter_store()
+		tc.error_policy("retry:1:halt")
+		tc.a_projector_failing_on("analytics", "evt-2", nil)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "analytics", []int64{1})
+		tc.dead_letters_are(DeadLetter{
+			RealmID: "realm-1", Projector: "analytics", GlobalPosition: 2, EventType: "evt-2",
+			Error: "broken on evt-2", Attempts: 4, Halted: true,
+		})
+	})
+
+	t.Run("recovers a projector that panics", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 2)
+		tc.a_dead_letter_store()
+		tc.a_projector_failing_on("rune_detail", "evt-1", func() { panic("nil map") })
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "rune_detail", []int64{2})
+		tc.dead_letters_are(DeadLetter{
+			RealmID: "realm-1", Projector: "rune_detail", GlobalPosition: 1, EventType: "evt-1",
+			Error: "panic: nil map", Attempts: 1,
+		})
+	})
+
+	t.Run("keeps none of a failed event's writes", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 3)
+		tc.a_batch_projection_store(nil)
+		tc.a_projector_failing_on("counter", "evt-2", nil)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.stored_count_is("realm-1", 2)
+	})
+}
+
+// --- Given ---
+
+func (tc *catchUpTestContext) a_dead_letter_store() {
+	tc.t.Helper()
+	tc.deadLetters = &memoryDeadLetterStore{}
+}
+
+func (tc *catchUpTestContext) error_policy(spec string) {
+	tc.t.Helper()
+	policy, err := ParseErrorPolicy(spec)
+	require.NoError(tc.t, err)
+	tc.errorPolicy = &policy
+}
+
+// a_projector_failing_on counts events like countingProjector, but fails
+// on eventType after counting it, calling fail first when it is given.
+func (tc *catchUpTestContext) a_projector_failing_on(name, eventType string, fail func()) {
+	tc.t.Helper()
+	tc.projector = &brokenProjector{name: name, eventType: eventType, fail: fail}
+}
+
+func (tc *catchUpTestContext) projector_recovers_after(failures int) {
+	tc.t.Helper()
+	tc.projector.(*brokenProjector).failures = failures
+}
+
+// --- Then ---
+
+func (tc *catchUpTestContext) dead_letters_are(expected ...DeadLetter) {
+	tc.t.Helper()
+	letters, err := tc.deadLetters.ListDeadLetters(context.Background(), "realm-1", "")
+	require.NoError(tc.t, err)
+	for i := range letters {
+		assert.False(tc.t, letters[i].FailedAt.IsZero(), "failed at")
+		letters[i].FailedAt = time.Time{}
+	}
+	if expected == nil {
+		expected = []DeadLetter{}
+	}
+	assert.Equal(tc.t, expected, letters)
+}
+
+// --- Fake Dead Letter Store ---
+
+type memoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (s *memoryDeadLetterStore) RecordDeadLetter(_ context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, l := range s.letters {
+		if l.RealmID == letter.RealmID && l.Projector == letter.Projector && l.GlobalPosition == letter.GlobalPosition {
+			letter.Attempts += l.Attempts
+			s.letters[i] = letter
+			return nil
+		}
+	}
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+func (s *memoryDeadLetterStore) ListDeadLetters(_ context.Context, realmID, projector string) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letters := []DeadLetter{}
+	for _, l := range s.letters {
+		if l.RealmID == realmID && (projector == "" || l.Projector == projector) {
+			letters = append(letters, l)
+		}
+	}
+	slices.SortFunc(letters, func(a, b DeadLetter) int { return int(a.GlobalPosition - b.GlobalPosition) })
+	return letters, nil
+}
+
+// --- Test Doubles ---
+
+// brokenProjector counts events, but fails on those of eventType, the
+// first failures times when failures is set and always otherwise.
+type brokenProjector struct {
+	name      string
+	eventType string
+	fail      func()
+	failures  int
+	failed    int
+}
+
+func (p *brokenProjector) Name() string {
+	return p.name
+}
+
+func (p *brokenProjector) Handle(ctx context.Context, event Event, store ProjectionStore) error {
+	var count int
+	_ = store.Get(ctx, event.RealmID, "counts", "events", &count)
+	if err := store.Put(ctx, event.RealmID, "counts", "events", count+1); err != nil {
+		return err
+	}
+	if event.EventType != p.eventType || (p.failures > 0 && p.failed >= p.failures) {
+		return nil
+	}
+	p.failed++
+	if p.fail != nil {
+		p.fail()
+	}
+	return errors.New("broken on " + event.EventType)
+}
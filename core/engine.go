@@ -2,7 +2,10 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 )
@@ -13,9 +16,41 @@ type projectionEngine struct {
 	projectionStore ProjectionStore
 	checkpointStore CheckpointStore
 	pollInterval    time.Duration
+	batchSize       int
+	workers         int
+
+	leaseStore  LeaseStore
+	leaseHolder string
+	leaseTTL    time.Duration
+
+	transactor Transactor
+	// critical names the projectors Execute runs in the command's
+	// transaction; all of them when nil.
+	critical map[string]bool
+	// projecting holds a lock per realm and projector, serialising
+	// catch-up and Execute so they never move the same checkpoint at once
+	// while leaving other checkpoints free to move side by side.
+	projecting   map[checkpointKey]*sync.Mutex
+	projectingMu sync.Mutex
+
+	now            func() time.Time
+	projectorStats map[string]*projectorStats
+	statsMu        sync.Mutex
+
+	errorPolicy   ErrorPolicy
+	errorPolicies map[string]ErrorPolicy
+	deadLetters   DeadLetterStore
+
+	versions ProjectorVersionStore
+
+	bus *EventBus
 
 	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	// draining is closed by Shutdown to stop catch-up after its current
+	// batch.
+	draining  chan struct{}
+	drainOnce sync.Once
+	wg        sync.WaitGroup
 }
 
 type EngineOption func(*projectionEngine)
@@ -26,13 +61,111 @@ func WithPollInterval(d time.Duration) EngineOption {
 	}
 }
 
+// WithBatchSize sets how many events catch-up reads, projects and
+// checkpoints at a time when the event store supports batched reads.
+func WithBatchSize(n int) EngineOption {
+	return func(e *projectionEngine) {
+		e.batchSize = n
+	}
+}
+
+// WithWorkers makes catch-up work on up to n realms at once. Each realm is
+// still caught up by one worker at a time, so its events are projected in
+// order; n below 2 catches realms up one after another.
+func WithWorkers(n int) EngineOption {
+	return func(e *projectionEngine) {
+		e.workers = n
+	}
+}
+
+// WithLease makes the engine run catch-up only while holder has the
+// CatchUpLease, so instances sharing stores don't project the same events
+// twice. The lease is renewed before each realm and must outlast the time
+// one realm takes to catch up.
+func WithLease(store LeaseStore, holder string, ttl time.Duration) EngineOption {
+	return func(e *projectionEngine) {
+		e.leaseStore = store
+		e.leaseHolder = holder
+		e.leaseTTL = ttl
+	}
+}
+
+// WithTransactor makes Execute project a command's events in the same
+// transaction that appends them.
+func WithTransactor(t Transactor) EngineOption {
+	return func(e *projectionEngine) {
+		e.transactor = t
+	}
+}
+
+// WithErrorPolicy sets how catch-up treats a projector that fails on an
+// event, unless WithProjectorErrorPolicy names the projector.
+func WithErrorPolicy(policy ErrorPolicy) EngineOption {
+	return func(e *projectionEngine) {
+		e.errorPolicy = policy
+	}
+}
+
+// WithProjectorErrorPolicy sets the error policy of the projector named.
+func WithProjectorErrorPolicy(name string, policy ErrorPolicy) EngineOption {
+	return func(e *projectionEngine) {
+		if e.errorPolicies == nil {
+			e.errorPolicies = make(map[string]ErrorPolicy)
+		}
+		e.errorPolicies[name] = policy
+	}
+}
+
+// WithDeadLetters records the events projectors fail to handle in store.
+func WithDeadLetters(store DeadLetterStore) EngineOption {
+	return func(e *projectionEngine) {
+		e.deadLetters = store
+	}
+}
+
+// WithProjectorVersions records the versions RebuildOutdated compares in
+// store. By default they are kept by the checkpoint store, when it can.
+func WithProjectorVersions(store ProjectorVersionStore) EngineOption {
+	return func(e *projectionEngine) {
+		e.versions = store
+	}
+}
+
+// WithEventBus wakes background catch-up for the realm of each event
+// published on bus, so appends made in this process are projected straight
+// away even when the event store cannot push events to the engine.
+func WithEventBus(bus *EventBus) EngineOption {
+	return func(e *projectionEngine) {
+		e.bus = bus
+	}
+}
+
+// WithCriticalProjectors limits the projectors Execute runs in a command's
+// transaction to those named, for the read models a command's caller reads
+// straight back. Execute then returns once the transaction commits, and the
+// other projectors follow in background catch-up.
+func WithCriticalProjectors(names ...string) EngineOption {
+	return func(e *projectionEngine) {
+		e.critical = make(map[string]bool, len(names))
+		for _, name := range names {
+			e.critical[name] = true
+		}
+	}
+}
+
 func NewProjectionEngine(eventStore EventStore, projectionStore ProjectionStore, checkpointStore CheckpointStore, opts ...EngineOption) *projectionEngine {
 	e := &projectionEngine{
 		eventStore:      eventStore,
 		projectionStore: projectionStore,
 		checkpointStore: checkpointStore,
 		pollInterval:    1 * time.Second,
+		batchSize:       500,
+		workers:         1,
+		now:             time.Now,
+		projecting:      make(map[checkpointKey]*sync.Mutex),
+		draining:        make(chan struct{}),
 	}
+	e.versions, _ = checkpointStore.(ProjectorVersionStore)
 	for _, opt := range opts {
 		opt(e)
 	}
@@ -43,6 +176,25 @@ func (e *projectionEngine) Register(projector Projector) {
 	e.projectors = append(e.projectors, projector)
 }
 
+// checkpointKey names one projector's checkpoint in a realm.
+type checkpointKey struct {
+	realmID   string
+	projector string
+}
+
+// lock returns the lock of the projector's checkpoint in the realm.
+func (e *projectionEngine) lock(realmID, projector string) *sync.Mutex {
+	e.projectingMu.Lock()
+	defer e.projectingMu.Unlock()
+	key := checkpointKey{realmID: realmID, projector: projector}
+	lock, ok := e.projecting[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		e.projecting[key] = lock
+	}
+	return lock
+}
+
 func (e *projectionEngine) RunSync(ctx context.Context, events []Event) error {
 	for _, projector := range e.projectors {
 		for _, event := range events {
@@ -58,68 +210,569 @@ func (e *projectionEngine) RunCatchUpOnce(ctx context.Context) {
 	e.runCatchUpCycle(ctx)
 }
 
+// StartCatchUp projects new events in the background. Each projector has
+// a catch-up loop of its own, so one that is slow or failing never holds
+// back the others' read models. When the event store pushes events, or
+// failing that the event bus publishes them, every loop catches up the
+// realm of each as it arrives; polling every poll interval still picks up
+// whatever neither can see, such as events appended by other processes.
 func (e *projectionEngine) StartCatchUp(ctx context.Context) error {
 	ctx, e.cancel = context.WithCancel(ctx)
+	if len(e.projectors) == 0 {
+		return nil
+	}
+
+	loops := make([]*catchUpLoop, len(e.projectors))
+	for i, projector := range e.projectors {
+		loops[i] = &catchUpLoop{projector: projector, wake: make(chan struct{}, 1)}
+	}
+	// Published events are queued from the start, so none appended while
+	// the loops first catch up is left to the next poll.
+	unlisten := e.listen(loops)
+
+	var started sync.WaitGroup
+	started.Add(len(loops))
+	for _, loop := range loops {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.runLoop(ctx, loop, started.Done)
+		}()
+	}
+
 	e.wg.Add(1)
 	go func() {
 		defer e.wg.Done()
-		ticker := time.NewTicker(e.pollInterval)
-		defer ticker.Stop()
+		defer unlisten()
+		started.Wait()
+		e.dispatch(ctx, loops, unlisten)
+	}()
+	return nil
+}
 
-		e.runCatchUpCycle(ctx)
+// catchUpLoop is the background catch-up of one projector.
+type catchUpLoop struct {
+	projector Projector
+	// projected is the highest global position the loop's first catch-up
+	// got to.
+	projected int64
+
+	mu sync.Mutex
+	// pending lists the realms with pushed events the loop has yet to
+	// catch up, and wake signals that it is not empty.
+	pending []string
+	wake    chan struct{}
+}
+
+// push queues realmIDs for the loop without waiting for it.
+func (l *catchUpLoop) push(realmIDs []string) {
+	l.mu.Lock()
+	for _, realmID := range realmIDs {
+		if !slices.Contains(l.pending, realmID) {
+			l.pending = append(l.pending, realmID)
+		}
+	}
+	l.mu.Unlock()
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *catchUpLoop) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	realmIDs := l.pending
+	l.pending = nil
+	return realmIDs
+}
+
+// runLoop catches the loop's projector up with every realm, calls started,
+// then catches it up again on each poll and each push until catch-up stops.
+func (e *projectionEngine) runLoop(ctx context.Context, loop *catchUpLoop, started func()) {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	projectors := []Projector{loop.projector}
+	loop.projected = e.catchUp(ctx, projectors, e.draining)
+	started()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.draining:
+			return
+		case <-loop.wake:
+			e.catchUpRealms(ctx, loop.take(), projectors, e.draining)
+		case <-ticker.C:
+			e.catchUp(ctx, projectors, e.draining)
+		}
+	}
+}
+
+// listen queues the realm of each event published on the bus for every
+// loop, and returns a function that stops it.
+func (e *projectionEngine) listen(loops []*catchUpLoop) (unlisten func()) {
+	if e.bus == nil {
+		return func() {}
+	}
+	return e.bus.Subscribe(func(_ context.Context, events []Event) {
+		for _, loop := range loops {
+			loop.push([]string{events[0].RealmID})
+		}
+	})
+}
 
-		for {
-			select {
-			case <-ctx.Done():
+// dispatch hands the realms of pushed events to every loop. It subscribes
+// once the loops have caught up, after the events the least advanced of
+// them has projected, and then stops listening to the bus, which would
+// only wake the loops a second time. A subscription the store closes is
+// renewed after the last event it pushed, and one that closes again before
+// pushing anything is renewed a poll interval later. When the event store
+// cannot push, or a renewal fails, the bus wakes the loops until catch-up
+// stops.
+func (e *projectionEngine) dispatch(ctx context.Context, loops []*catchUpLoop, unlisten func()) {
+	from := loops[0].projected
+	for _, loop := range loops[1:] {
+		from = min(from, loop.projected)
+	}
+	pushed := e.subscribe(ctx, from)
+	if pushed == nil {
+		e.awaitStop(ctx)
+		return
+	}
+	unlisten()
+
+	var retryAfter time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.draining:
+			return
+		case evt, ok := <-pushed:
+			if ok {
+				var realmIDs []string
+				realmIDs, from = pendingRealms(evt, pushed)
+				for _, loop := range loops {
+					loop.push(realmIDs)
+				}
+				retryAfter = 0
+				continue
+			}
+			if e.stopsWithin(ctx, retryAfter) {
+				return
+			}
+			retryAfter = e.pollInterval
+			log.Printf("catch-up: event subscription closed, resubscribing after position %d", from)
+			if pushed = e.subscribe(ctx, from); pushed == nil {
+				defer e.listen(loops)()
+				e.awaitStop(ctx)
 				return
-			case <-ticker.C:
-				e.runCatchUpCycle(ctx)
 			}
 		}
-	}()
-	return nil
+	}
+}
+
+// stopsWithin waits up to d for catch-up to stop, and reports whether it
+// did.
+func (e *projectionEngine) stopsWithin(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-e.draining:
+		return true
+	default:
+	}
+	if d == 0 {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return true
+	case <-e.draining:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// awaitStop blocks until catch-up stops.
+func (e *projectionEngine) awaitStop(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-e.draining:
+	}
+}
+
+// subscribe returns the events appended after from, or nil when the event
+// store cannot push them.
+func (e *projectionEngine) subscribe(ctx context.Context, from int64) <-chan Event {
+	subscriber, ok := e.eventStore.(EventSubscriber)
+	if !ok {
+		return nil
+	}
+	events, err := subscriber.Subscribe(ctx, from)
+	if err != nil {
+		log.Printf("catch-up: subscribing to events, polling instead: %v", err)
+		return nil
+	}
+	return events
+}
+
+// pendingRealms returns the realms of evt and of the events already waiting
+// behind it, in the order they first appear, and the global position of the
+// last of those events.
+func pendingRealms(evt Event, events <-chan Event) ([]string, int64) {
+	realmIDs := []string{evt.RealmID}
+	seen := map[string]bool{evt.RealmID: true}
+	last := evt.GlobalPosition
+	for {
+		select {
+		case next, ok := <-events:
+			if !ok {
+				return realmIDs, last
+			}
+			last = next.GlobalPosition
+			if !seen[next.RealmID] {
+				seen[next.RealmID] = true
+				realmIDs = append(realmIDs, next.RealmID)
+			}
+		default:
+			return realmIDs, last
+		}
+	}
 }
 
 func (e *projectionEngine) runCatchUpCycle(ctx context.Context) {
+	e.catchUp(ctx, e.projectors, nil)
+}
+
+// catchUp feeds each of projectors the events after its checkpoint in each
+// realm, returning the highest checkpoint it got to. Once stop is closed it
+// returns after the batch in progress; a nil stop never closes.
+func (e *projectionEngine) catchUp(ctx context.Context, projectors []Projector, stop <-chan struct{}) int64 {
 	realmIDs, err := e.eventStore.ListRealmIDs(ctx)
 	if err != nil {
 		log.Printf("catch-up: error listing realms: %v", err)
-		return
+		return 0
 	}
+	return e.catchUpRealms(ctx, realmIDs, projectors, stop)
+}
 
-	for _, realmID := range realmIDs {
-		for _, projector := range e.projectors {
-			if ctx.Err() != nil {
-				return
+// catchUpRealms catches projectors up with each of realmIDs, spreading the
+// realms over the engine's workers, and returns the highest checkpoint
+// they got to. No realm is started once one reports catch-up must stop.
+func (e *projectionEngine) catchUpRealms(ctx context.Context, realmIDs []string, projectors []Projector, stop <-chan struct{}) int64 {
+	var highest int64
+	workers := min(e.workers, len(realmIDs))
+	if workers <= 1 {
+		for _, realmID := range realmIDs {
+			checkpoint, ok := e.catchUpRealm(ctx, realmID, projectors, stop)
+			highest = max(highest, checkpoint)
+			if !ok {
+				break
 			}
+		}
+		return highest
+	}
 
-			checkpoint, err := e.checkpointStore.GetCheckpoint(ctx, realmID, projector.Name())
-			if err != nil {
-				log.Printf("catch-up: error getting checkpoint for %s/%s: %v", realmID, projector.Name(), err)
-				continue
+	var (
+		mu      sync.Mutex
+		halted  bool
+		wg      sync.WaitGroup
+		pending = make(chan string)
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for realmID := range pending {
+				checkpoint, ok := e.catchUpRealm(ctx, realmID, projectors, stop)
+				mu.Lock()
+				highest = max(highest, checkpoint)
+				halted = halted || !ok
+				mu.Unlock()
 			}
+		}()
+	}
+	for _, realmID := range realmIDs {
+		mu.Lock()
+		stopped := halted
+		mu.Unlock()
+		if stopped {
+			break
+		}
+		pending <- realmID
+	}
+	close(pending)
+	wg.Wait()
+	return highest
+}
 
-			events, err := e.eventStore.ReadAll(ctx, realmID, checkpoint)
-			if err != nil {
-				log.Printf("catch-up: error reading events for realm %s: %v", realmID, err)
-				continue
-			}
+// catchUpRealm feeds each of projectors the realm's events after its
+// checkpoint. It returns the highest checkpoint they got to and whether
+// catch-up may go on to other realms.
+func (e *projectionEngine) catchUpRealm(ctx context.Context, realmID string, projectors []Projector, stop <-chan struct{}) (int64, bool) {
+	if !e.holdLease(ctx) {
+		return 0, false
+	}
+	var highest int64
+	for _, projector := range projectors {
+		if ctx.Err() != nil || closed(stop) {
+			return highest, false
+		}
+		highest = max(highest, e.catchUpProjector(ctx, realmID, projector, stop))
+	}
+	return highest, true
+}
+
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// holdLease takes or renews the catch-up lease, reporting whether this
+// engine may project. Engines without a lease store always may.
+func (e *projectionEngine) holdLease(ctx context.Context) bool {
+	if e.leaseStore == nil {
+		return true
+	}
+	ok, err := e.leaseStore.AcquireLease(ctx, CatchUpLease, e.leaseHolder, e.leaseTTL)
+	if err != nil {
+		log.Printf("catch-up: error acquiring lease: %v", err)
+		return false
+	}
+	return ok
+}
+
+// catchUpProjector feeds a projector the realm's events after its
+// checkpoint and returns the checkpoint it got to.
+func (e *projectionEngine) catchUpProjector(ctx context.Context, realmID string, projector Projector, stop <-chan struct{}) int64 {
+	lock := e.lock(realmID, projector.Name())
+	lock.Lock()
+	defer lock.Unlock()
+	stores := UnitOfWork{EventStore: e.eventStore, ProjectionStore: e.projectionStore, CheckpointStore: e.checkpointStore}
+	checkpoint, err := e.project(ctx, stores, realmID, projector, stop)
+	if err != nil {
+		log.Printf("catch-up: %s/%s: %v", realmID, projector.Name(), err)
+		if !errors.Is(err, ErrProjectorHalted) {
+			e.stats(projector.Name()).failed(err)
+		}
+	}
+	return checkpoint
+}
+
+// project feeds a projector the realm's events after its checkpoint, one
+// batch at a time, reading and writing through stores, and returns the
+// checkpoint it got to. It stops between batches once stop is closed, and
+// with ErrProjectorHalted at an event its error policy halts on.
+func (e *projectionEngine) project(ctx context.Context, stores UnitOfWork, realmID string, projector Projector, stop <-chan struct{}) (int64, error) {
+	checkpoint, err := stores.CheckpointStore.GetCheckpoint(ctx, realmID, projector.Name())
+	if err != nil {
+		return 0, fmt.Errorf("getting checkpoint: %w", err)
+	}
 
-			var lastPos int64
-			for _, event := range events {
-				if err := projector.Handle(ctx, event, e.projectionStore); err != nil {
-					log.Printf("catch-up: projector %q error on event %d: %v", projector.Name(), event.GlobalPosition, err)
+	for ctx.Err() == nil {
+		page, err := e.readBatch(ctx, stores.EventStore, realmID, checkpoint)
+		if err != nil {
+			return checkpoint, fmt.Errorf("reading events: %w", err)
+		}
+		if len(page.Events) == 0 {
+			return checkpoint, nil
+		}
+
+		// Writes are buffered and flushed together, and the checkpoint
+		// only moves once they are stored.
+		buffer := newProjectionBuffer(stores.ProjectionStore)
+		next, processed := page.Next, len(page.Events)
+		var stopped error
+		for i, event := range page.Events {
+			if stopped = e.handle(ctx, realmID, projector, event, buffer); stopped != nil {
+				next, processed = checkpoint, i
+				if i > 0 {
+					next = page.Events[i-1].GlobalPosition
 				}
-				lastPos = event.GlobalPosition
+				break
 			}
+		}
+		if err := buffer.flush(ctx); err != nil {
+			return checkpoint, fmt.Errorf("writing projections: %w", err)
+		}
+		if next != checkpoint {
+			if err := stores.CheckpointStore.SetCheckpoint(ctx, realmID, projector.Name(), next); err != nil {
+				return checkpoint, fmt.Errorf("setting checkpoint: %w", err)
+			}
+		}
+		checkpoint = next
+		e.stats(projector.Name()).handled(e.now(), processed)
+		if stopped != nil {
+			return checkpoint, stopped
+		}
+		if !page.More || closed(stop) {
+			return checkpoint, nil
+		}
+	}
+	return checkpoint, nil
+}
 
-			if len(events) > 0 {
-				if err := e.checkpointStore.SetCheckpoint(ctx, realmID, projector.Name(), lastPos); err != nil {
-					log.Printf("catch-up: error setting checkpoint for %s/%s: %v", realmID, projector.Name(), err)
-				}
+// handle feeds an event to a projector, retrying it as the projector's
+// error policy allows. Each attempt writes to a buffer of its own, merged
+// into batch once it succeeds, so a failed attempt leaves nothing behind
+// and a panic counts as a failure. An event that still fails is recorded
+// as a dead letter and skipped, or ends in ErrProjectorHalted; one cut off
+// by ctx ends in its error.
+func (e *projectionEngine) handle(ctx context.Context, realmID string, projector Projector, event Event, batch *projectionBuffer) error {
+	policy := e.errorPolicy
+	if p, ok := e.errorPolicies[projector.Name()]; ok {
+		policy = p
+	}
+
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		staged := newProjectionBuffer(batch)
+		if err = handleRecovered(ctx, projector, event, staged); err == nil {
+			for _, w := range staged.writes {
+				batch.stage(w)
 			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			// Cut off rather than failed: leave the event to the next pass.
+			return ctx.Err()
+		}
+		if attempts > policy.Retries {
+			break
+		}
+	}
+
+	log.Printf("catch-up: projector %q error on event %d: %v", projector.Name(), event.GlobalPosition, err)
+	e.stats(projector.Name()).failed(fmt.Errorf("event %d: %w", event.GlobalPosition, err))
+	halt := policy.Action == HaltProjector
+	if e.deadLetters != nil {
+		letter := DeadLetter{
+			RealmID:        realmID,
+			Projector:      projector.Name(),
+			GlobalPosition: event.GlobalPosition,
+			StreamID:       event.StreamID,
+			Version:        event.Version,
+			EventType:      event.EventType,
+			Error:          err.Error(),
+			Attempts:       attempts,
+			Halted:         halt,
+			FailedAt:       e.now(),
+		}
+		if recordErr := e.deadLetters.RecordDeadLetter(ctx, letter); recordErr != nil {
+			log.Printf("catch-up: recording dead letter of %q for event %d: %v", projector.Name(), event.GlobalPosition, recordErr)
+		}
+	}
+	if halt {
+		return fmt.Errorf("%w at event %d: %v", ErrProjectorHalted, event.GlobalPosition, err)
+	}
+	return nil
+}
+
+// handleRecovered calls projector.Handle, turning a panic into an error.
+func handleRecovered(ctx context.Context, projector Projector, event Event, store ProjectionStore) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return projector.Handle(ctx, event, store)
+}
+
+// readBatch reads the page of up to batchSize events after from. A store
+// without batched reads would read the rest of the feed for every page, so
+// it is read as one.
+func (e *projectionEngine) readBatch(ctx context.Context, store EventStore, realmID string, from int64) (EventPage, error) {
+	limit := e.batchSize
+	if _, ok := store.(BatchEventReader); !ok {
+		limit = 0
+	}
+	return ReadPage(ctx, store, realmID, from, limit)
+}
+
+// Execute runs command and projects the events it appends. With a
+// transactor, the command and the realm's projections and checkpoints share
+// one transaction, so readers never see the events without their
+// projections; projectors with side effects follow in a catch-up after the
+// commit, or in background catch-up with WithCriticalProjectors. Without
+// one, the command runs against the engine's stores and is followed by a
+// catch-up.
+func (e *projectionEngine) Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow UnitOfWork) error) error {
+	if e.transactor == nil || !e.holdLease(ctx) {
+		stores := UnitOfWork{EventStore: e.eventStore, ProjectionStore: e.projectionStore, CheckpointStore: e.checkpointStore}
+		if err := command(ctx, stores); err != nil {
+			return err
+		}
+		e.runCatchUpCycle(ctx)
+		return nil
+	}
+
+	if err := e.executeInTransaction(ctx, realmID, command); err != nil {
+		return err
+	}
+	if e.critical == nil {
+		e.runCatchUpCycle(ctx)
+	}
+	return nil
+}
+
+func (e *projectionEngine) executeInTransaction(ctx context.Context, realmID string, command func(ctx context.Context, uow UnitOfWork) error) error {
+	var projectors []Projector
+	for _, projector := range e.projectors {
+		if hasSideEffects(projector) || (e.critical != nil && !e.critical[projector.Name()]) {
+			continue
 		}
+		projectors = append(projectors, projector)
 	}
+	// Only the realm's checkpoints of the projectors run here are locked,
+	// in the order the projectors were registered, so catch-up of the
+	// others carries on meanwhile.
+	locked := make(map[*sync.Mutex]bool, len(projectors))
+	for _, projector := range projectors {
+		if lock := e.lock(realmID, projector.Name()); !locked[lock] {
+			locked[lock] = true
+			lock.Lock()
+			defer lock.Unlock()
+		}
+	}
+
+	var written []ProjectionWrite
+	// The cache never saw the transaction's writes, so evict them whether
+	// or not it committed.
+	defer func() {
+		if invalidator, ok := e.projectionStore.(ProjectionInvalidator); ok && len(written) > 0 {
+			invalidator.InvalidateProjections(written)
+		}
+	}()
+
+	return e.transactor.Transact(ctx, func(ctx context.Context, uow UnitOfWork) error {
+		if err := command(ctx, uow); err != nil {
+			return err
+		}
+		recorder := &writeRecorder{ProjectionStore: uow.ProjectionStore}
+		defer func() { written = recorder.writes }()
+		uow.ProjectionStore = recorder
+		for _, projector := range projectors {
+			// A halted projector keeps its checkpoint and is left to
+			// catch-up rather than failing the command.
+			if _, err := e.project(ctx, uow, realmID, projector, nil); err != nil && !errors.Is(err, ErrProjectorHalted) {
+				return fmt.Errorf("projector %q: %w", projector.Name(), err)
+			}
+		}
+		return nil
+	})
 }
 
 func (e *projectionEngine) Stop() error {
@@ -127,5 +780,42 @@ func (e *projectionEngine) Stop() error {
 		e.cancel()
 	}
 	e.wg.Wait()
+	return e.releaseLease()
+}
+
+// Shutdown stops background catch-up gracefully: the batch in progress is
+// projected and its checkpoint stored, so side effects such as webhook
+// deliveries are neither cut off nor repeated after a restart. If ctx ends
+// first, catch-up is cancelled as by Stop and ctx's error is returned.
+func (e *projectionEngine) Shutdown(ctx context.Context) error {
+	e.drainOnce.Do(func() { close(e.draining) })
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if e.cancel != nil {
+			e.cancel()
+		}
+		<-done
+		err = ctx.Err()
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+	return errors.Join(err, e.releaseLease())
+}
+
+// releaseLease hands the lease over now rather than when it expires.
+func (e *projectionEngine) releaseLease() error {
+	if e.leaseStore != nil {
+		return e.leaseStore.ReleaseLease(context.Background(), CatchUpLease, e.leaseHolder)
+	}
 	return nil
 }
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateWindow is how far back ProjectorStatus.EventsPerSecond looks.
+const rateWindow = 60 * time.Second
+
+// ProjectorStatus reports how far a projector has caught up, so operators
+// can tell whether the read model it keeps is stale.
+type ProjectorStatus struct {
+	Name string `json:"name"`
+	// Position is the highest global position the projector has processed
+	// in any realm.
+	Position int64 `json:"position"`
+	// Head is the global position of the newest event in any realm.
+	Head int64 `json:"head"`
+	// Lag sums, over the realms, the global positions between the
+	// projector's checkpoint and the realm's newest event: zero once it
+	// has caught up, and the events still to project where positions count
+	// up per realm.
+	Lag int64 `json:"lag"`
+	// EventsPerSecond is the rate this process projected events at over
+	// the last minute.
+	EventsPerSecond float64 `json:"events_per_second"`
+	// Errors counts the events the projector failed to handle and the
+	// batches it failed to store since the engine was created.
+	Errors    int64  `json:"errors"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// projectorStats counts what one projector did in this process.
+type projectorStats struct {
+	mu        sync.Mutex
+	errors    int64
+	lastError string
+	// projected holds the events projected in each second of the rate
+	// window, indexed by Unix second modulo its length.
+	projected [int(rateWindow / time.Second)]struct {
+		second int64
+		events int64
+	}
+}
+
+func (s *projectorStats) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors++
+	s.lastError = err.Error()
+}
+
+func (s *projectorStats) handled(now time.Time, events int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	second := now.Unix()
+	slot := &s.projected[second%int64(len(s.projected))]
+	if slot.second != second {
+		slot.second, slot.events = second, 0
+	}
+	slot.events += int64(events)
+}
+
+func (s *projectorStats) status(name string, now time.Time) ProjectorStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events int64
+	for _, slot := range s.projected {
+		if now.Unix()-slot.second < int64(len(s.projected)) {
+			events += slot.events
+		}
+	}
+	return ProjectorStatus{
+		Name:            name,
+		EventsPerSecond: float64(events) / rateWindow.Seconds(),
+		Errors:          s.errors,
+		LastError:       s.lastError,
+	}
+}
+
+// stats returns the counters of the projector named, creating them on
+// first use.
+func (e *projectionEngine) stats(name string) *projectorStats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	if e.projectorStats == nil {
+		e.projectorStats = make(map[string]*projectorStats)
+	}
+	stats, ok := e.projectorStats[name]
+	if !ok {
+		stats = &projectorStats{}
+		e.projectorStats[name] = stats
+	}
+	return stats
+}
+
+// Status reports the progress of every registered projector, in the order
+// they were registered. Positions come from the checkpoint store, so they
+// include catch-up run by other instances; rates and errors are this
+// process's own.
+func (e *projectionEngine) Status(ctx context.Context) ([]ProjectorStatus, error) {
+	now := e.now()
+	statuses := make([]ProjectorStatus, len(e.projectors))
+	for i, projector := range e.projectors {
+		statuses[i] = e.stats(projector.Name()).status(projector.Name(), now)
+	}
+
+	realmIDs, err := e.eventStore.ListRealmIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing realms: %w", err)
+	}
+	for _, realmID := range realmIDs {
+		checkpoints, err := e.realmCheckpoints(ctx, realmID)
+		if err != nil {
+			return nil, fmt.Errorf("reading checkpoints of %s: %w", realmID, err)
+		}
+		var furthest int64
+		for _, projector := range e.projectors {
+			furthest = max(furthest, checkpoints[projector.Name()])
+		}
+		head, err := e.realmHead(ctx, realmID, furthest)
+		if err != nil {
+			return nil, fmt.Errorf("reading events of %s: %w", realmID, err)
+		}
+		for i, projector := range e.projectors {
+			checkpoint := checkpoints[projector.Name()]
+			statuses[i].Position = max(statuses[i].Position, checkpoint)
+			statuses[i].Head = max(statuses[i].Head, head)
+			statuses[i].Lag += max(0, head-checkpoint)
+		}
+	}
+	return statuses, nil
+}
+
+// realmCheckpoints returns the realm's checkpoints by projector name, in
+// one read when the checkpoint store can list them.
+func (e *projectionEngine) realmCheckpoints(ctx context.Context, realmID string) (map[string]int64, error) {
+	if lister, ok := e.checkpointStore.(CheckpointLister); ok {
+		return lister.ListCheckpoints(ctx, realmID)
+	}
+	checkpoints := make(map[string]int64, len(e.projectors))
+	for _, projector := range e.projectors {
+		checkpoint, err := e.checkpointStore.GetCheckpoint(ctx, realmID, projector.Name())
+		if err != nil {
+			return nil, err
+		}
+		checkpoints[projector.Name()] = checkpoint
+	}
+	return checkpoints, nil
+}
+
+// realmHead returns the global position of the realm's newest event,
+// reading only the events after from, the furthest any projector has got.
+func (e *projectionEngine) realmHead(ctx context.Context, realmID string, from int64) (int64, error) {
+	for {
+		page, err := e.readBatch(ctx, e.eventStore, realmID, from)
+		if err != nil {
+			return 0, err
+		}
+		from = page.Next
+		if !page.More {
+			return from, nil
+		}
+	}
+}
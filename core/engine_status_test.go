@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjectionEngine_Status(t *testing.T) {
+	t.Run("reports each projector's position, head and lag", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 5)
+		tc.checkpoint("realm-1", "rune_detail", 5)
+		tc.checkpoint("realm-1", "analytics", 2)
+		tc.catch_up_engine_is_created()
+		tc.a_catch_up_recording_projector("rune_detail")
+		tc.register_catch_up_projector()
+		tc.a_catch_up_recording_projector("analytics")
+		tc.register_catch_up_projector()
+
+		// When
+		tc.status_is_read()
+
+		// Then
+		tc.status_has_no_error()
+		tc.projector_status_is("rune_detail", 5, 5, 0)
+		tc.projector_status_is("analytics", 2, 5, 3)
+	})
+
+	t.Run("reports a projector that never ran as behind every event", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 4)
+		tc.batch_size(3)
+		tc.catch_up_engine_is_created()
+		tc.a_catch_up_recording_projector("new-projector")
+		tc.register_catch_up_projector()
+
+		// When
+		tc.status_is_read()
+
+		// Then
+		tc.status_has_no_error()
+		tc.projector_status_is("new-projector", 0, 4, 4)
+	})
+
+	t.Run("counts the events a projector failed to handle", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 2)
+		tc.a_catch_up_failing_projector("failing")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.run_catch_up_once_is_called()
+
+		// When
+		tc.status_is_read()
+
+		// Then
+		tc.status_has_no_error()
+		status := tc.projector_status("failing")
+		assert.Equal(t, int64(2), status.Errors)
+		assert.Equal(t, "event 2: projector error", status.LastError)
+	})
+
+	t.Run("reports the events projected per second over the last minute", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 6)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.engine_time_is(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+		tc.run_catch_up_once_is_called()
+
+		// When
+		tc.status_is_read()
+
+		// Then
+		assert.InDelta(t, 0.1, tc.projector_status("recorder").EventsPerSecond, 1e-9)
+
+		// When
+		tc.engine_time_is(time.Date(2026, 10, 15, 12, 1, 0, 0, time.UTC))
+		tc.status_is_read()
+
+		// Then
+		assert.Zero(t, tc.projector_status("recorder").EventsPerSecond)
+	})
+}
+
+// --- Given ---
+
+func (tc *catchUpTestContext) engine_time_is(now time.Time) {
+	tc.t.Helper()
+	tc.engine.now = func() time.Time { return now }
+}
+
+// --- When ---
+
+func (tc *catchUpTestContext) status_is_read() {
+	tc.t.Helper()
+	tc.statuses, tc.statusErr = tc.engine.Status(context.Background())
+}
+
+// --- Then ---
+
+func (tc *catchUpTestContext) status_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.statusErr)
+}
+
+func (tc *catchUpTestContext) projector_status(name string) ProjectorStatus {
+	tc.t.Helper()
+	for _, status := range tc.statuses {
+		if status.Name == name {
+			return status
+		}
+	}
+	tc.t.Fatalf("no status for projector %q", name)
+	return ProjectorStatus{}
+}
+
+func (tc *catchUpTestContext) projector_status_is(name string, position, head, lag int64) {
+	tc.t.Helper()
+	status := tc.projector_status(name)
+	assert.Equal(tc.t, position, status.Position, "position")
+	assert.Equal(tc.t, head, status.Head, "head")
+	assert.Equal(tc.t, lag, status.Lag, "lag")
+}
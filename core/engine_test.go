@@ -1,8 +1,10 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -405,6 +407,244 @@ func TestProjectionEngine_StartCatchUp(t *testing.T) {
 		tc.catch_up_projector_handled_event_count("multi", 2)
 	})
 
+	t.Run("flushes a projector's writes in one batch", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+			Event{EventType: "evt-2", GlobalPosition: 2, RealmID: "realm-1"},
+			Event{EventType: "evt-3", GlobalPosition: 3, RealmID: "realm-1"},
+		)
+		tc.a_batch_projection_store(nil)
+		tc.a_counting_projector("counter")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.batches_written(1)
+		tc.stored_count_is("realm-1", 3)
+		tc.checkpoint_was_set("realm-1", "counter", 3)
+	})
+
+	t.Run("does not advance the checkpoint when the batch fails", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+		)
+		tc.a_batch_projection_store(errors.New("disk full"))
+		tc.a_counting_projector("counter")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.checkpoint_was_not_set("realm-1", "counter")
+	})
+
+	t.Run("reads, projects and checkpoints the feed in batches", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 5)
+		tc.batch_size(2)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.catch_up_projector_handled_event_count("recorder", 5)
+		tc.checkpoints_set("realm-1", "recorder", []int64{2, 4, 5})
+		assert.Equal(t, []int{2, 2, 2}, tc.pagedEventStore.limits)
+	})
+
+	t.Run("projects pushed events without waiting for the poll", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_pushing_event_store("realm-1", 2)
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.event_is_pushed("realm-1", 3)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.catch_up_projector_handled_event_count("recorder", 3)
+		tc.checkpoints_set("realm-1", "recorder", []int64{2, 3})
+		assert.Equal(t, int64(2), tc.pushingEventStore.from, "subscribed after the events already projected")
+	})
+
+	t.Run("a slow projector does not hold back the others", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+			Event{EventType: "evt-2", GlobalPosition: 2, RealmID: "realm-1"},
+			Event{EventType: "evt-3", GlobalPosition: 3, RealmID: "realm-1"},
+		)
+		tc.a_catch_up_slow_projector("analytics", 100*time.Millisecond)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.a_catch_up_recording_projector("rune_detail")
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.wait_briefly()
+
+		// Then
+		tc.checkpoint_was_set("realm-1", "rune_detail", 3)
+		tc.checkpoint_was_not_set("realm-1", "analytics")
+
+		// When
+		tc.shutdown_is_called(time.Second)
+
+		// Then
+		tc.stop_returns_nil()
+		tc.catch_up_projector_handled_events("rune_detail", []string{"evt-1", "evt-2", "evt-3"})
+		tc.checkpoint_was_set("realm-1", "analytics", 3)
+	})
+
+	t.Run("wakes every projector for a pushed event", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_pushing_event_store("realm-1", 2)
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("first")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.a_catch_up_recording_projector("second")
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.event_is_pushed("realm-1", 3)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "first", []int64{2, 3})
+		tc.checkpoints_set("realm-1", "second", []int64{2, 3})
+	})
+
+	t.Run("wakes every projector for an event published on the bus", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.an_appending_event_store("realm-1", 2)
+		tc.an_event_bus()
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("first")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.a_catch_up_recording_projector("second")
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.wait_briefly()
+		tc.event_is_published("realm-1", 3)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.checkpoint_was_set("realm-1", "first", 3)
+		tc.checkpoint_was_set("realm-1", "second", 3)
+	})
+
+	t.Run("leaves waking to the event store once it pushes", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_pushing_event_store("realm-1", 2)
+		tc.an_event_bus()
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.engine_has_subscribed()
+		tc.event_is_published("realm-1", 3)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "recorder", []int64{2})
+	})
+
+	t.Run("resubscribes after the event store closes its subscription", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_pushing_event_store("realm-1", 2)
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.event_is_pushed("realm-1", 3)
+		tc.wait_briefly()
+		tc.subscription_is_closed()
+		tc.engine_has_resubscribed()
+		tc.event_is_pushed("realm-1", 4)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "recorder", []int64{2, 3, 4})
+		assert.Equal(t, int64(3), tc.pushingEventStore.from, "resubscribed after the last pushed event")
+	})
+
+	t.Run("listens to the bus again when resubscribing fails", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_pushing_event_store("realm-1", 2)
+		tc.pushingEventStore.refuseRenewal = true
+		tc.an_event_bus()
+		tc.poll_interval(time.Hour)
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.engine_has_subscribed()
+		tc.subscription_is_closed()
+		tc.engine_has_resubscribed()
+		tc.event_is_published("realm-1", 3)
+		tc.wait_briefly()
+		tc.stop_is_called()
+
+		// Then
+		tc.checkpoints_set("realm-1", "recorder", []int64{2, 3})
+	})
+
 	t.Run("no-op when no realms exist", func(t *testing.T) {
 		tc := newCatchUpTestContext(t)
 
@@ -501,6 +741,115 @@ func TestProjectionEngine_RunCatchUpOnce(t *testing.T) {
 	})
 }
 
+func TestProjectionEngine_Workers(t *testing.T) {
+	t.Run("catches realms up side by side, each in order", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-a", "realm-b", "realm-c")
+		for _, realmID := range []string{"realm-a", "realm-b", "realm-c"} {
+			tc.realm_events(realmID, 0,
+				Event{EventType: realmID + "-1", GlobalPosition: 1, RealmID: realmID},
+				Event{EventType: realmID + "-2", GlobalPosition: 2, RealmID: realmID},
+			)
+		}
+		tc.workers(3)
+		tc.a_concurrency_projector("parallel", 20*time.Millisecond)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.realms_projected_at_once(3)
+		for _, realmID := range []string{"realm-a", "realm-b", "realm-c"} {
+			tc.realm_handled_in_order(realmID, []string{realmID + "-1", realmID + "-2"})
+			tc.checkpoint_was_set(realmID, "parallel", 2)
+		}
+	})
+
+	t.Run("catches realms up one at a time by default", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-a", "realm-b")
+		tc.realm_events("realm-a", 0, Event{EventType: "a-1", GlobalPosition: 1, RealmID: "realm-a"})
+		tc.realm_events("realm-b", 0, Event{EventType: "b-1", GlobalPosition: 1, RealmID: "realm-b"})
+		tc.a_concurrency_projector("serial", 20*time.Millisecond)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.realms_projected_at_once(1)
+	})
+}
+
+func TestProjectionEngine_Lease(t *testing.T) {
+	t.Run("catches up while holding the lease", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+		)
+		tc.a_lease_store()
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.catch_up_projector_handled_events("recorder", []string{"evt-1"})
+		tc.lease_is_held_by("node-a")
+	})
+
+	t.Run("skips catch-up while another node holds the lease", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+		)
+		tc.a_lease_store()
+		tc.lease_held_by("node-b")
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.catch_up_projector_handled_events("recorder", []string{})
+		tc.lease_is_held_by("node-b")
+	})
+
+	t.Run("releases the lease on stop", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_lease_store()
+		tc.catch_up_engine_is_created()
+		tc.run_catch_up_once_is_called()
+
+		// When
+		tc.stop_is_called()
+
+		// Then
+		tc.stop_returns_nil()
+		tc.lease_is_held_by("")
+	})
+}
+
 func TestProjectionEngine_Stop(t *testing.T) {
 	t.Run("graceful shutdown waits for in-flight processing", func(t *testing.T) {
 		tc := newCatchUpTestContext(t)
@@ -539,6 +888,67 @@ func TestProjectionEngine_Stop(t *testing.T) {
 	})
 }
 
+func TestProjectionEngine_Shutdown(t *testing.T) {
+	t.Run("finishes the batch in progress and stores its checkpoint", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.a_paged_event_store("realm-1", 6)
+		tc.batch_size(2)
+		tc.a_catch_up_slow_projector("slow", 50*time.Millisecond)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.wait_briefly()
+		tc.shutdown_is_called(time.Second)
+
+		// Then
+		tc.stop_returns_nil()
+		tc.checkpoints_set("realm-1", "slow", []int64{2})
+	})
+
+	t.Run("cancels catch-up when the context ends first", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+		)
+		tc.a_catch_up_slow_projector("slow", 100*time.Millisecond)
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.wait_briefly()
+		tc.shutdown_is_called(10 * time.Millisecond)
+
+		// Then
+		assert.ErrorIs(t, tc.stopErr, context.DeadlineExceeded)
+	})
+
+	t.Run("releases the lease", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_lease_store()
+		tc.catch_up_engine_is_created()
+
+		// When
+		tc.start_catch_up_is_called()
+		tc.wait_briefly()
+		tc.shutdown_is_called(time.Second)
+
+		// Then
+		tc.stop_returns_nil()
+		tc.lease_is_held_by("")
+	})
+}
+
 // --- Catch-Up Test Context ---
 
 type catchUpTestContext struct {
@@ -555,6 +965,36 @@ type catchUpTestContext struct {
 
 	recorders     map[string]*recordingProjector
 	slowRecorders map[string]*slowProjector
+
+	batchStore        *batchRecordingStore
+	pagedEventStore   *pagedEventStore
+	pushingEventStore *pushingEventStore
+	appendingStore    *appendingEventStore
+	bus               *EventBus
+	batchSize         int
+	workerCount       int
+	leaseStore        *memoryLeaseStore
+	concurrency       *concurrencyProjector
+
+	statuses  []ProjectorStatus
+	statusErr error
+
+	errorPolicy *ErrorPolicy
+	deadLetters *memoryDeadLetterStore
+
+	versions        *memoryVersionStore
+	truncatingStore *truncatingProjectionStore
+	transactor      *rebuildTransactor
+	rebuilt         []string
+	rebuildErr      error
+
+	indexingStore *indexingProjectionStore
+
+	snapshotStore *snapshotProjectionStore
+	snapshot      bytes.Buffer
+	exportedRows  int
+	imported      ProjectionImport
+	snapshotErr   error
 }
 
 func newCatchUpTestContext(t *testing.T) *catchUpTestContext {
@@ -606,6 +1046,87 @@ func (tc *catchUpTestContext) a_catch_up_slow_projector(name string, delay time.
 	tc.projector = sp
 }
 
+func (tc *catchUpTestContext) a_concurrency_projector(name string, delay time.Duration) {
+	tc.t.Helper()
+	tc.concurrency = &concurrencyProjector{name: name, delay: delay, handled: make(map[string][]string)}
+	tc.projector = tc.concurrency
+}
+
+func (tc *catchUpTestContext) a_batch_projection_store(writeErr error) {
+	tc.t.Helper()
+	tc.batchStore = newBatchRecordingStore(writeErr)
+}
+
+func (tc *catchUpTestContext) a_counting_projector(name string) {
+	tc.t.Helper()
+	tc.projector = &countingProjector{name: name}
+}
+
+func (tc *catchUpTestContext) a_paged_event_store(realmID string, count int) {
+	tc.t.Helper()
+	tc.pagedEventStore = &pagedEventStore{realmID: realmID}
+	for i := 1; i <= count; i++ {
+		tc.pagedEventStore.events = append(tc.pagedEventStore.events, Event{
+			EventType:      fmt.Sprintf("evt-%d", i),
+			GlobalPosition: int64(i),
+			RealmID:        realmID,
+		})
+	}
+}
+
+func (tc *catchUpTestContext) a_pushing_event_store(realmID string, count int) {
+	tc.t.Helper()
+	tc.pushingEventStore = &pushingEventStore{subscribed: make(chan struct{}), resubscribed: make(chan struct{})}
+	tc.pushingEventStore.realmID = realmID
+	for i := 1; i <= count; i++ {
+		tc.pushingEventStore.events = append(tc.pushingEventStore.events, Event{
+			EventType:      fmt.Sprintf("evt-%d", i),
+			GlobalPosition: int64(i),
+			RealmID:        realmID,
+		})
+	}
+}
+
+func (tc *catchUpTestContext) an_appending_event_store(realmID string, count int) {
+	tc.t.Helper()
+	tc.appendingStore = &appendingEventStore{}
+	tc.appendingStore.realmID = realmID
+	for i := 1; i <= count; i++ {
+		tc.appendingStore.events = append(tc.appendingStore.events, Event{
+			EventType:      fmt.Sprintf("evt-%d", i),
+			GlobalPosition: int64(i),
+			RealmID:        realmID,
+		})
+	}
+}
+
+func (tc *catchUpTestContext) an_event_bus() {
+	tc.t.Helper()
+	tc.bus = NewEventBus()
+}
+
+func (tc *catchUpTestContext) a_lease_store() {
+	tc.t.Helper()
+	tc.leaseStore = &memoryLeaseStore{}
+}
+
+func (tc *catchUpTestContext) lease_held_by(holder string) {
+	tc.t.Helper()
+	ok, err := tc.leaseStore.AcquireLease(context.Background(), CatchUpLease, holder, time.Minute)
+	require.NoError(tc.t, err)
+	require.True(tc.t, ok)
+}
+
+func (tc *catchUpTestContext) batch_size(n int) {
+	tc.t.Helper()
+	tc.batchSize = n
+}
+
+func (tc *catchUpTestContext) workers(n int) {
+	tc.t.Helper()
+	tc.workerCount = n
+}
+
 func (tc *catchUpTestContext) poll_interval(d time.Duration) {
 	tc.t.Helper()
 	tc.pollInterval = d
@@ -613,11 +1134,59 @@ func (tc *catchUpTestContext) poll_interval(d time.Duration) {
 
 func (tc *catchUpTestContext) catch_up_engine_is_created() {
 	tc.t.Helper()
+	var projectionStore ProjectionStore = &mockProjectionStore{}
+	if tc.batchStore != nil {
+		projectionStore = tc.batchStore
+	}
+	if tc.truncatingStore != nil {
+		projectionStore = tc.truncatingStore
+	}
+	if tc.snapshotStore != nil {
+		projectionStore = tc.snapshotStore
+	}
+	if tc.indexingStore != nil {
+		projectionStore = NewCachedProjectionStore(tc.indexingStore, 10)
+	}
+	var eventStore EventStore = tc.configEventStore
+	if tc.pagedEventStore != nil {
+		eventStore = tc.pagedEventStore
+	}
+	if tc.pushingEventStore != nil {
+		eventStore = tc.pushingEventStore
+	}
+	if tc.appendingStore != nil {
+		eventStore = tc.appendingStore
+	}
+	opts := []EngineOption{WithPollInterval(tc.pollInterval)}
+	if tc.batchSize > 0 {
+		opts = append(opts, WithBatchSize(tc.batchSize))
+	}
+	if tc.errorPolicy != nil {
+		opts = append(opts, WithErrorPolicy(*tc.errorPolicy))
+	}
+	if tc.deadLetters != nil {
+		opts = append(opts, WithDeadLetters(tc.deadLetters))
+	}
+	if tc.versions != nil {
+		opts = append(opts, WithProjectorVersions(tc.versions))
+	}
+	if tc.bus != nil {
+		opts = append(opts, WithEventBus(tc.bus))
+	}
+	if tc.workerCount > 0 {
+		opts = append(opts, WithWorkers(tc.workerCount))
+	}
+	if tc.leaseStore != nil {
+		opts = append(opts, WithLease(tc.leaseStore, "node-a", time.Minute))
+	}
+	if tc.transactor != nil {
+		opts = append(opts, WithTransactor(tc.transactor))
+	}
 	tc.engine = NewProjectionEngine(
-		tc.configEventStore,
-		&mockProjectionStore{},
+		eventStore,
+		projectionStore,
 		tc.configCheckpointStore,
-		WithPollInterval(tc.pollInterval),
+		opts...,
 	)
 	require.NotNil(tc.t, tc.engine)
 }
@@ -639,11 +1208,66 @@ func (tc *catchUpTestContext) stop_is_called() {
 	tc.stopErr = tc.engine.Stop()
 }
 
+func (tc *catchUpTestContext) shutdown_is_called(timeout time.Duration) {
+	tc.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	tc.stopErr = tc.engine.Shutdown(ctx)
+}
+
 func (tc *catchUpTestContext) run_catch_up_once_is_called() {
 	tc.t.Helper()
 	tc.engine.RunCatchUpOnce(context.Background())
 }
 
+func (tc *catchUpTestContext) event_is_pushed(realmID string, pos int64) {
+	tc.t.Helper()
+	select {
+	case <-tc.pushingEventStore.subscribed:
+	case <-time.After(time.Second):
+		tc.t.Fatal("engine did not subscribe")
+	}
+	tc.pushingEventStore.push(Event{EventType: fmt.Sprintf("evt-%d", pos), GlobalPosition: pos, RealmID: realmID})
+}
+
+// event_is_published stores an event without pushing it and publishes it
+// on the bus, as an append through the bus's event store does.
+func (tc *catchUpTestContext) event_is_published(realmID string, pos int64) {
+	tc.t.Helper()
+	evt := Event{EventType: fmt.Sprintf("evt-%d", pos), GlobalPosition: pos, RealmID: realmID}
+	if tc.appendingStore != nil {
+		tc.appendingStore.add(evt)
+	} else {
+		tc.pushingEventStore.add(evt)
+	}
+	tc.bus.Publish(context.Background(), []Event{evt})
+}
+
+func (tc *catchUpTestContext) subscription_is_closed() {
+	tc.t.Helper()
+	tc.pushingEventStore.close()
+}
+
+func (tc *catchUpTestContext) engine_has_resubscribed() {
+	tc.t.Helper()
+	select {
+	case <-tc.pushingEventStore.resubscribed:
+	case <-time.After(time.Second):
+		tc.t.Fatal("engine did not resubscribe")
+	}
+	tc.wait_briefly()
+}
+
+func (tc *catchUpTestContext) engine_has_subscribed() {
+	tc.t.Helper()
+	select {
+	case <-tc.pushingEventStore.subscribed:
+	case <-time.After(time.Second):
+		tc.t.Fatal("engine did not subscribe")
+	}
+	tc.wait_briefly()
+}
+
 func (tc *catchUpTestContext) wait_for_poll_cycle() {
 	tc.t.Helper()
 	time.Sleep(tc.pollInterval * 3)
@@ -682,6 +1306,37 @@ func (tc *catchUpTestContext) checkpoint_was_set(realmID, projectorName string,
 	assert.Equal(tc.t, expectedPos, pos)
 }
 
+func (tc *catchUpTestContext) checkpoints_set(realmID, projectorName string, expected []int64) {
+	tc.t.Helper()
+	tc.configCheckpointStore.mu.Lock()
+	defer tc.configCheckpointStore.mu.Unlock()
+	var actual []int64
+	for _, c := range tc.configCheckpointStore.setCalls {
+		if c.realmID == realmID && c.projectorName == projectorName {
+			actual = append(actual, c.position)
+		}
+	}
+	assert.Equal(tc.t, expected, actual)
+}
+
+func (tc *catchUpTestContext) checkpoint_was_not_set(realmID, projectorName string) {
+	tc.t.Helper()
+	_, ok := tc.configCheckpointStore.getLastSet(realmID, projectorName)
+	assert.False(tc.t, ok, "checkpoint for %s/%s should not be set", realmID, projectorName)
+}
+
+func (tc *catchUpTestContext) batches_written(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.batchStore.batches)
+}
+
+func (tc *catchUpTestContext) stored_count_is(realmID string, expected int) {
+	tc.t.Helper()
+	var count int
+	require.NoError(tc.t, tc.batchStore.Get(context.Background(), realmID, "counts", "events", &count))
+	assert.Equal(tc.t, expected, count)
+}
+
 func (tc *catchUpTestContext) stop_returns_nil() {
 	tc.t.Helper()
 	assert.NoError(tc.t, tc.stopErr)
@@ -692,6 +1347,20 @@ func (tc *catchUpTestContext) engine_poll_interval_is(expected time.Duration) {
 	assert.Equal(tc.t, expected, tc.engine.pollInterval)
 }
 
+func (tc *catchUpTestContext) realms_projected_at_once(expected int) {
+	tc.t.Helper()
+	tc.concurrency.mu.Lock()
+	defer tc.concurrency.mu.Unlock()
+	assert.Equal(tc.t, expected, tc.concurrency.peak)
+}
+
+func (tc *catchUpTestContext) realm_handled_in_order(realmID string, expectedTypes []string) {
+	tc.t.Helper()
+	tc.concurrency.mu.Lock()
+	defer tc.concurrency.mu.Unlock()
+	assert.Equal(tc.t, expectedTypes, tc.concurrency.handled[realmID])
+}
+
 func (tc *catchUpTestContext) slow_projector_completed(name string) {
 	tc.t.Helper()
 	sp, ok := tc.slowRecorders[name]
@@ -740,6 +1409,106 @@ func (m *configurableEventStore) ListRealmIDs(_ context.Context) ([]string, erro
 	return m.realmIDs, nil
 }
 
+// pagedEventStore serves a single realm's feed through ReadAllBatch.
+type pagedEventStore struct {
+	configurableEventStore
+	realmID string
+	events  []Event
+	limits  []int
+}
+
+func (m *pagedEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return []string{m.realmID}, nil
+}
+
+func (m *pagedEventStore) ReadAllBatch(_ context.Context, _ string, fromPos int64, limit int) ([]Event, error) {
+	m.limits = append(m.limits, limit)
+	var batch []Event
+	for _, e := range m.events {
+		if e.GlobalPosition > fromPos && len(batch) < limit {
+			batch = append(batch, e)
+		}
+	}
+	return batch, nil
+}
+
+// pushingEventStore is a paged event store that pushes appended events to
+// its latest subscription.
+type pushingEventStore struct {
+	pagedEventStore
+	mu            sync.Mutex
+	from          int64
+	pushed        chan Event
+	subscriptions int
+	subscribed    chan struct{}
+	resubscribed  chan struct{}
+	// Fails every subscription after the first when set
+	refuseRenewal bool
+}
+
+func (m *pushingEventStore) ReadAllBatch(ctx context.Context, realmID string, fromPos int64, limit int) ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pagedEventStore.ReadAllBatch(ctx, realmID, fromPos, limit)
+}
+
+func (m *pushingEventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions++
+	switch m.subscriptions {
+	case 1:
+		close(m.subscribed)
+	case 2:
+		close(m.resubscribed)
+	}
+	if m.subscriptions > 1 && m.refuseRenewal {
+		return nil, errors.New("subscription refused")
+	}
+	m.from = fromPosition
+	m.pushed = make(chan Event, 1)
+	return m.pushed, nil
+}
+
+func (m *pushingEventStore) push(evt Event) {
+	m.add(evt)
+	m.mu.Lock()
+	pushed := m.pushed
+	m.mu.Unlock()
+	pushed <- evt
+}
+
+func (m *pushingEventStore) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	close(m.pushed)
+}
+
+func (m *pushingEventStore) add(evt Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, evt)
+}
+
+// appendingEventStore is a paged event store that cannot push, so events
+// added to it are only seen when catch-up reads it.
+type appendingEventStore struct {
+	pagedEventStore
+	mu sync.Mutex
+}
+
+func (m *appendingEventStore) ReadAllBatch(ctx context.Context, realmID string, fromPos int64, limit int) ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pagedEventStore.ReadAllBatch(ctx, realmID, fromPos, limit)
+}
+
+func (m *appendingEventStore) add(evt Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, evt)
+}
+
 type checkpointEntry struct {
 	realmID       string
 	projectorName string
@@ -819,3 +1588,65 @@ func (s *slowProjector) completed() bool {
 	defer s.mu.Unlock()
 	return s.done
 }
+
+// concurrencyProjector records the events of each realm and the most
+// realms it was handling at once.
+type concurrencyProjector struct {
+	name  string
+	delay time.Duration
+
+	mu      sync.Mutex
+	active  map[string]bool
+	peak    int
+	handled map[string][]string
+}
+
+func (c *concurrencyProjector) Name() string {
+	return c.name
+}
+
+func (c *concurrencyProjector) Handle(_ context.Context, event Event, _ ProjectionStore) error {
+	c.mu.Lock()
+	if c.active == nil {
+		c.active = make(map[string]bool)
+	}
+	c.active[event.RealmID] = true
+	c.peak = max(c.peak, len(c.active))
+	c.handled[event.RealmID] = append(c.handled[event.RealmID], event.EventType)
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	delete(c.active, event.RealmID)
+	c.mu.Unlock()
+	return nil
+}
+
+func (tc *catchUpTestContext) lease_is_held_by(holder string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, holder, tc.leaseStore.holder)
+}
+
+// memoryLeaseStore holds a single lease in memory.
+type memoryLeaseStore struct {
+	holder  string
+	expires time.Time
+}
+
+func (s *memoryLeaseStore) AcquireLease(_ context.Context, _ string, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if s.holder != "" && s.holder != holder && now.Before(s.expires) {
+		return false, nil
+	}
+	s.holder = holder
+	s.expires = now.Add(ttl)
+	return true, nil
+}
+
+func (s *memoryLeaseStore) ReleaseLease(_ context.Context, _ string, holder string) error {
+	if s.holder == holder {
+		s.holder = ""
+	}
+	return nil
+}
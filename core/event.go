@@ -17,4 +17,9 @@ type EventData struct {
 	EventType string `json:"event_type"`
 	Data      any    `json:"data"`
 	Metadata  any    `json:"metadata"`
+	// IdempotencyKey, set on the first event of a batch, keys the whole
+	// batch: appending another batch with the same key to the same stream
+	// appends nothing and returns the events of the first one, whatever the
+	// expected version.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+// EventHandler receives events once they have been appended. The events of
+// one call belong to a single stream of a single realm.
+type EventHandler func(ctx context.Context, events []Event)
+
+// EventBus fans appended events out to subscribers in process, so webhooks,
+// notifications and caches can react to new events without polling ReadAll.
+// Events reach subscribers only after they are durable: after Append returns
+// or, inside a unit of work, after the transaction commits. Delivery is best
+// effort; a subscriber that must not miss events should track a position and
+// read the feed instead.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[int]EventHandler
+	order    []int
+	nextID   int
+}
+
+// NewEventBus returns an EventBus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: map[int]EventHandler{}}
+}
+
+// Subscribe registers h for every event published from now on, and returns
+// a function that unregisters it.
+func (b *EventBus) Subscribe(h EventHandler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+	b.order = append(b.order, id)
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.handlers[id]; !ok {
+			return
+		}
+		delete(b.handlers, id)
+		for i, other := range b.order {
+			if other == id {
+				b.order = append(b.order[:i:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Publish passes events to each subscriber in the order they subscribed. It
+// runs on the appender's goroutine, so subscribers must hand slow work off
+// rather than do it in the handler. A panicking subscriber is logged and
+// does not stop the others.
+func (b *EventBus) Publish(ctx context.Context, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	b.mu.RLock()
+	handlers := make([]EventHandler, 0, len(b.order))
+	for _, id := range b.order {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.mu.RUnlock()
+	for _, h := range handlers {
+		deliver(ctx, h, events)
+	}
+}
+
+func deliver(ctx context.Context, h EventHandler, events []Event) {
+	defer func() {
+		if v := recover(); v != nil {
+			log.Printf("event bus: subscriber panicked: %v", v)
+		}
+	}()
+	h(ctx, events)
+}
+
+// EventStore returns store with every successful append published on the
+// bus.
+func (b *EventBus) EventStore(store EventStore) EventStore {
+	return &publishingEventStore{inner: store, publish: b.Publish}
+}
+
+// Transactor returns t with the appends of each unit of work published on
+// the bus once its transaction commits. Nothing is published for a unit of
+// work that rolls back.
+func (b *EventBus) Transactor(t Transactor) Transactor {
+	return &publishingTransactor{inner: t, bus: b}
+}
+
+// publishingEventStore hands the events appended through it to publish.
+type publishingEventStore struct {
+	inner   EventStore
+	publish func(ctx context.Context, events []Event)
+}
+
+func (s *publishingEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []EventData) ([]Event, error) {
+	appended, err := s.inner.Append(ctx, realmID, streamID, expectedVersion, events)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, appended)
+	return appended, nil
+}
+
+func (s *publishingEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]Event, error) {
+	return s.inner.ReadStream(ctx, realmID, streamID, fromVersion)
+}
+
+func (s *publishingEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]Event, error) {
+	return s.inner.ReadAll(ctx, realmID, fromGlobalPosition)
+}
+
+func (s *publishingEventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir Direction) ([]Event, error) {
+	page, err := ReadStreamPage(ctx, s.inner, realmID, streamID, from, limit, dir)
+	return page.Events, err
+}
+
+func (s *publishingEventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]Event, error) {
+	page, err := ReadPage(ctx, s.inner, realmID, fromGlobalPosition, limit)
+	return page.Events, err
+}
+
+func (s *publishingEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	return s.inner.ListRealmIDs(ctx)
+}
+
+func (s *publishingEventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan Event, error) {
+	if subscriber, ok := s.inner.(EventSubscriber); ok {
+		return subscriber.Subscribe(ctx, fromPosition)
+	}
+	return nil, errors.New("event store does not push events")
+}
+
+func (s *publishingEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (Snapshot, error) {
+	if snapshots, ok := s.inner.(SnapshotStore); ok {
+		return snapshots.GetSnapshot(ctx, realmID, streamID)
+	}
+	return Snapshot{}, &NotFoundError{Entity: "snapshot", ID: streamID}
+}
+
+func (s *publishingEventStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	if snapshots, ok := s.inner.(SnapshotStore); ok {
+		return snapshots.SaveSnapshot(ctx, snapshot)
+	}
+	return nil
+}
+
+func (s *publishingEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []Event) error {
+	if redactor, ok := s.inner.(EventRedactor); ok {
+		return redactor.RedactStream(ctx, realmID, streamID, redacted)
+	}
+	return errors.New("event store cannot redact events")
+}
+
+type publishingTransactor struct {
+	inner Transactor
+	bus   *EventBus
+}
+
+func (t *publishingTransactor) Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error {
+	var appended [][]Event
+	err := t.inner.Transact(ctx, func(ctx context.Context, uow UnitOfWork) error {
+		// A retried transaction starts over, dropping the attempt before.
+		appended = nil
+		uow.EventStore = &publishingEventStore{inner: uow.EventStore, publish: func(_ context.Context, events []Event) {
+			appended = append(appended, events)
+		}}
+		return fn(ctx, uow)
+	})
+	if err != nil {
+		return err
+	}
+	for _, events := range appended {
+		t.bus.Publish(ctx, events)
+	}
+	return nil
+}
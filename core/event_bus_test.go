@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ BatchEventReader = (*publishingEventStore)(nil)
+	_ StreamPageReader = (*publishingEventStore)(nil)
+	_ SnapshotStore    = (*publishingEventStore)(nil)
+	_ EventRedactor    = (*publishingEventStore)(nil)
+)
+
+// --- Tests ---
+
+func TestEventBus(t *testing.T) {
+	t.Run("publishes events once they are appended", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.subscriber_is_registered("first")
+
+		// When
+		tc.event_is_appended("s-1")
+
+		// Then
+		tc.no_error()
+		tc.subscriber_received("first", "s-1")
+	})
+
+	t.Run("does not publish a failed append", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.subscriber_is_registered("first")
+		tc.append_fails()
+
+		// When
+		tc.event_is_appended("s-1")
+
+		// Then
+		assert.Error(t, tc.err)
+		tc.subscriber_received("first")
+	})
+
+	t.Run("publishes a unit of work's events after it commits", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.subscriber_is_registered("first")
+
+		// When
+		tc.err = tc.transactor.Transact(tc.ctx, func(ctx context.Context, uow UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "s-1", 0, []EventData{{EventType: "Noted", Data: map[string]string{}}})
+			require.NoError(t, err)
+			_, err = uow.EventStore.Append(ctx, "realm-1", "s-2", 0, []EventData{{EventType: "Noted", Data: map[string]string{}}})
+			require.NoError(t, err)
+			tc.subscriber_received("first")
+			return nil
+		})
+
+		// Then
+		tc.no_error()
+		tc.subscriber_received("first", "s-1", "s-2")
+	})
+
+	t.Run("does not publish a unit of work that rolls back", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.subscriber_is_registered("first")
+
+		// When
+		tc.err = tc.transactor.Transact(tc.ctx, func(ctx context.Context, uow UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "s-1", 0, []EventData{{EventType: "Noted", Data: map[string]string{}}})
+			require.NoError(t, err)
+			return errors.New("projection failed")
+		})
+
+		// Then
+		assert.EqualError(t, tc.err, "projection failed")
+		tc.subscriber_received("first")
+	})
+
+	t.Run("publishes to every subscriber in the order they subscribed", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.subscriber_is_registered("first")
+		tc.subscriber_is_registered("second")
+
+		// When
+		tc.event_is_appended("s-1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{"first", "second"}, tc.calls)
+		tc.subscriber_received("second", "s-1")
+	})
+
+	t.Run("stops publishing to a subscriber that unsubscribed", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		unsubscribe := tc.subscriber_is_registered("first")
+		tc.subscriber_is_registered("second")
+		unsubscribe()
+		unsubscribe()
+
+		// When
+		tc.event_is_appended("s-1")
+
+		// Then
+		tc.no_error()
+		tc.subscriber_received("first")
+		tc.subscriber_received("second", "s-1")
+	})
+
+	t.Run("keeps publishing past a subscriber that panics", func(t *testing.T) {
+		tc := newEventBusTestContext(t)
+
+		// Given
+		tc.bus.Subscribe(func(ctx context.Context, events []Event) {
+			panic("subscriber bug")
+		})
+		tc.subscriber_is_registered("second")
+
+		// When
+		tc.event_is_appended("s-1")
+
+		// Then
+		tc.no_error()
+		tc.subscriber_received("second", "s-1")
+	})
+}
+
+// --- Test Context ---
+
+type eventBusTestContext struct {
+	t          *testing.T
+	ctx        context.Context
+	inner      *failingEventStore
+	bus        *EventBus
+	store      EventStore
+	transactor Transactor
+	received   map[string][]string
+	calls      []string
+	err        error
+}
+
+func newEventBusTestContext(t *testing.T) *eventBusTestContext {
+	t.Helper()
+	inner := &failingEventStore{keyedEventStore: &keyedEventStore{
+		keys:      make(map[string][]byte),
+		snapshots: make(map[string]Snapshot),
+	}}
+	bus := NewEventBus()
+	return &eventBusTestContext{
+		t:          t,
+		ctx:        context.Background(),
+		inner:      inner,
+		bus:        bus,
+		store:      bus.EventStore(inner),
+		transactor: bus.Transactor(&passThroughTransactor{store: inner}),
+		received:   make(map[string][]string),
+	}
+}
+
+// --- Given ---
+
+func (tc *eventBusTestContext) subscriber_is_registered(name string) (unsubscribe func()) {
+	return tc.bus.Subscribe(func(ctx context.Context, events []Event) {
+		tc.calls = append(tc.calls, name)
+		for _, evt := range events {
+			tc.received[name] = append(tc.received[name], evt.StreamID)
+		}
+	})
+}
+
+func (tc *eventBusTestContext) append_fails() {
+	tc.inner.err = errors.New("disk full")
+}
+
+// --- When ---
+
+func (tc *eventBusTestContext) event_is_appended(streamID string) {
+	_, tc.err = tc.store.Append(tc.ctx, "realm-1", streamID, 0, []EventData{
+		{EventType: "Noted", Data: map[string]string{}},
+	})
+}
+
+// --- Then ---
+
+func (tc *eventBusTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *eventBusTestContext) subscriber_received(name string, streamIDs ...string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, streamIDs, tc.received[name])
+}
+
+// --- Mock Transactor ---
+
+// passThroughTransactor runs units of work directly against its store; a
+// returned error stands in for a rollback.
+type passThroughTransactor struct {
+	store EventStore
+}
+
+func (t *passThroughTransactor) Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error {
+	return fn(ctx, UnitOfWork{EventStore: t.store})
+}
+
+// failingEventStore fails its appends once err is set.
+type failingEventStore struct {
+	*keyedEventStore
+	err error
+}
+
+func (s *failingEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []EventData) ([]Event, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.keyedEventStore.Append(ctx, realmID, streamID, expectedVersion, events)
+}
@@ -0,0 +1,447 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeyWrapper encrypts the data keys of encrypted realms with a key that is
+// never stored next to them. NewAESKeyWrapper wraps with a configured master
+// key; a key management service can be plugged in by implementing it.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// DataKeyStore is implemented by event stores that keep the wrapped data
+// key of each encrypted realm, so a key created while appending a realm's
+// first events is committed or rolled back with them.
+type DataKeyStore interface {
+	// GetDataKey returns the realm's wrapped data key, or a NotFoundError
+	// when it has none.
+	GetDataKey(ctx context.Context, realmID string) ([]byte, error)
+	// AddDataKey stores the realm's wrapped data key unless it already has
+	// one, which is kept.
+	AddDataKey(ctx context.Context, realmID string, wrapped []byte) error
+}
+
+// encryptedPayload is the JSON an encrypted event's data or snapshot's state
+// is stored as: the nonce followed by the sealed plaintext.
+type encryptedPayload struct {
+	Sealed []byte `json:"$encrypted"`
+}
+
+var encryptedPrefix = []byte(`{"$encrypted":`)
+
+// RealmEncryption encrypts the event data and snapshots of chosen realms
+// with a data key per realm, itself stored wrapped by a KeyWrapper. Reads
+// decrypt whatever was stored encrypted, so realms can be added to or
+// dropped from the list at any time; only new events follow the change.
+type RealmEncryption struct {
+	wrapper KeyWrapper
+	all     bool
+	realms  map[string]bool
+
+	mu sync.Mutex
+	// aeads caches the cipher of each wrapped data key, so the wrapper is
+	// only asked to unwrap a key once.
+	aeads map[string]cipher.AEAD
+}
+
+// NewRealmEncryption encrypts the realms with the given IDs; "*" encrypts
+// every realm.
+func NewRealmEncryption(wrapper KeyWrapper, realmIDs ...string) *RealmEncryption {
+	e := &RealmEncryption{
+		wrapper: wrapper,
+		realms:  make(map[string]bool),
+		aeads:   make(map[string]cipher.AEAD),
+	}
+	for _, id := range realmIDs {
+		if id == "*" {
+			e.all = true
+		}
+		e.realms[id] = true
+	}
+	return e
+}
+
+// Encrypts reports whether events appended to the realm are encrypted.
+func (e *RealmEncryption) Encrypts(realmID string) bool {
+	return e.all || e.realms[realmID]
+}
+
+// EventStore returns store with the data of the encrypted realms' events
+// and snapshots encrypted on the way in and decrypted on the way out. The
+// store must implement DataKeyStore.
+func (e *RealmEncryption) EventStore(store EventStore) EventStore {
+	return &encryptingEventStore{inner: store, enc: e}
+}
+
+// Transactor returns t with the event store of each unit of work encrypted
+// like EventStore does.
+func (e *RealmEncryption) Transactor(t Transactor) Transactor {
+	return &encryptingTransactor{inner: t, enc: e}
+}
+
+// aead returns the cipher of the realm's data key, creating the key when
+// the realm has none and create is set.
+func (e *RealmEncryption) aead(ctx context.Context, keys DataKeyStore, realmID string, create bool) (cipher.AEAD, error) {
+	wrapped, err := keys.GetDataKey(ctx, realmID)
+	var nf *NotFoundError
+	if errors.As(err, &nf) && create {
+		wrapped, err = e.addDataKey(ctx, keys, realmID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data key for realm %s: %w", realmID, err)
+	}
+
+	e.mu.Lock()
+	aead, ok := e.aeads[string(wrapped)]
+	e.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+	key, err := e.wrapper.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key for realm %s: %w", realmID, err)
+	}
+	if aead, err = newGCM(key); err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.aeads[string(wrapped)] = aead
+	e.mu.Unlock()
+	return aead, nil
+}
+
+func (e *RealmEncryption) addDataKey(ctx context.Context, keys DataKeyStore, realmID string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	wrapped, err := e.wrapper.WrapKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	if err := keys.AddDataKey(ctx, realmID, wrapped); err != nil {
+		return nil, err
+	}
+	// Another writer may have added a key first, in which case theirs was
+	// kept and is the one to use.
+	return keys.GetDataKey(ctx, realmID)
+}
+
+func (e *RealmEncryption) seal(aead cipher.AEAD, realmID string, plaintext []byte) (json.RawMessage, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// The realm ID is authenticated, so a payload copied into another
+	// realm fails to decrypt.
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(realmID))
+	return json.Marshal(encryptedPayload{Sealed: sealed})
+}
+
+func (e *RealmEncryption) open(aead cipher.AEAD, realmID string, data []byte) ([]byte, error) {
+	var payload encryptedPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	size := aead.NonceSize()
+	if len(payload.Sealed) < size {
+		return nil, ErrDecryptionFailed
+	}
+	plaintext, err := aead.Open(nil, payload.Sealed[:size], payload.Sealed[size:], []byte(realmID))
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedPrefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// aesKeyWrapper wraps data keys with AES-256-GCM under a master key.
+type aesKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+// NewAESKeyWrapper returns a KeyWrapper that wraps data keys with
+// AES-256-GCM under masterKey, which must be 32 bytes.
+func NewAESKeyWrapper(masterKey []byte) (KeyWrapper, error) {
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &aesKeyWrapper{aead: aead}, nil
+}
+
+func (w *aesKeyWrapper) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return w.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+func (w *aesKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	size := w.aead.NonceSize()
+	if len(wrapped) < size {
+		return nil, ErrDecryptionFailed
+	}
+	key, err := w.aead.Open(nil, wrapped[:size], wrapped[size:], nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return key, nil
+}
+
+// encryptingEventStore encrypts and decrypts the events of an inner store.
+// It forwards the optional interfaces the rest of the server looks for.
+type encryptingEventStore struct {
+	inner EventStore
+	enc   *RealmEncryption
+}
+
+func (s *encryptingEventStore) keys() (DataKeyStore, error) {
+	keys, ok := s.inner.(DataKeyStore)
+	if !ok {
+		return nil, errors.New("event store does not keep data keys")
+	}
+	return keys, nil
+}
+
+func (s *encryptingEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []EventData) ([]Event, error) {
+	if !s.enc.Encrypts(realmID) || len(events) == 0 {
+		stored, err := s.inner.Append(ctx, realmID, streamID, expectedVersion, events)
+		if err != nil {
+			return nil, err
+		}
+		return s.decrypt(ctx, stored)
+	}
+	keys, err := s.keys()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := s.enc.aead(ctx, keys, realmID, true)
+	if err != nil {
+		return nil, err
+	}
+	sealed := make([]EventData, len(events))
+	for i, ed := range events {
+		plaintext, err := json.Marshal(ed.Data)
+		if err != nil {
+			return nil, err
+		}
+		data, err := s.enc.seal(aead, realmID, plaintext)
+		if err != nil {
+			return nil, err
+		}
+		ed.Data = data
+		sealed[i] = ed
+	}
+	stored, err := s.inner.Append(ctx, realmID, streamID, expectedVersion, sealed)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, stored)
+}
+
+func (s *encryptingEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]Event, error) {
+	events, err := s.inner.ReadStream(ctx, realmID, streamID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+func (s *encryptingEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]Event, error) {
+	events, err := s.inner.ReadAll(ctx, realmID, fromGlobalPosition)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, events)
+}
+
+func (s *encryptingEventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir Direction) ([]Event, error) {
+	page, err := ReadStreamPage(ctx, s.inner, realmID, streamID, from, limit, dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, page.Events)
+}
+
+func (s *encryptingEventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]Event, error) {
+	page, err := ReadPage(ctx, s.inner, realmID, fromGlobalPosition, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, page.Events)
+}
+
+func (s *encryptingEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	return s.inner.ListRealmIDs(ctx)
+}
+
+// Subscribe decrypts the inner store's pushed events. An event that fails
+// to decrypt ends the subscription, so readers fall back to polling and
+// surface the error there.
+func (s *encryptingEventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan Event, error) {
+	subscriber, ok := s.inner.(EventSubscriber)
+	if !ok {
+		return nil, errors.New("event store does not push events")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	in, err := subscriber.Subscribe(ctx, fromPosition)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for evt := range in {
+			events, err := s.decrypt(ctx, []Event{evt})
+			if err != nil {
+				return
+			}
+			select {
+			case out <- events[0]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (s *encryptingEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (Snapshot, error) {
+	snapshots, ok := s.inner.(SnapshotStore)
+	if !ok {
+		return Snapshot{}, &NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	snapshot, err := snapshots.GetSnapshot(ctx, realmID, streamID)
+	if err != nil || !isEncrypted(snapshot.State) {
+		return snapshot, err
+	}
+	keys, err := s.keys()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	aead, err := s.enc.aead(ctx, keys, realmID, false)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if snapshot.State, err = s.enc.open(aead, realmID, snapshot.State); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (s *encryptingEventStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	snapshots, ok := s.inner.(SnapshotStore)
+	if !ok {
+		return nil
+	}
+	if s.enc.Encrypts(snapshot.RealmID) {
+		keys, err := s.keys()
+		if err != nil {
+			return err
+		}
+		aead, err := s.enc.aead(ctx, keys, snapshot.RealmID, true)
+		if err != nil {
+			return err
+		}
+		if snapshot.State, err = s.enc.seal(aead, snapshot.RealmID, snapshot.State); err != nil {
+			return err
+		}
+	}
+	return snapshots.SaveSnapshot(ctx, snapshot)
+}
+
+// RedactStream seals the redacted data of an encrypted realm before the
+// inner store rewrites the events with it.
+func (s *encryptingEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []Event) error {
+	redactor, ok := s.inner.(EventRedactor)
+	if !ok {
+		return errors.New("event store cannot redact events")
+	}
+	if !s.enc.Encrypts(realmID) || len(redacted) == 0 {
+		return redactor.RedactStream(ctx, realmID, streamID, redacted)
+	}
+	keys, err := s.keys()
+	if err != nil {
+		return err
+	}
+	aead, err := s.enc.aead(ctx, keys, realmID, true)
+	if err != nil {
+		return err
+	}
+	sealed := make([]Event, len(redacted))
+	for i, evt := range redacted {
+		if evt.Data, err = s.enc.seal(aead, realmID, evt.Data); err != nil {
+			return err
+		}
+		sealed[i] = evt
+	}
+	return redactor.RedactStream(ctx, realmID, streamID, sealed)
+}
+
+// decrypt replaces the data of encrypted events with their plaintext.
+func (s *encryptingEventStore) decrypt(ctx context.Context, events []Event) ([]Event, error) {
+	aeads := make(map[string]cipher.AEAD)
+	for i, evt := range events {
+		if !isEncrypted(evt.Data) {
+			continue
+		}
+		aead, ok := aeads[evt.RealmID]
+		if !ok {
+			keys, err := s.keys()
+			if err != nil {
+				return nil, err
+			}
+			if aead, err = s.enc.aead(ctx, keys, evt.RealmID, false); err != nil {
+				return nil, err
+			}
+			aeads[evt.RealmID] = aead
+		}
+		data, err := s.enc.open(aead, evt.RealmID, evt.Data)
+		if err != nil {
+			return nil, fmt.Errorf("event %d in realm %s: %w", evt.GlobalPosition, evt.RealmID, err)
+		}
+		events[i].Data = data
+	}
+	return events, nil
+}
+
+type encryptingTransactor struct {
+	inner Transactor
+	enc   *RealmEncryption
+}
+
+func (t *encryptingTransactor) Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error {
+	return t.inner.Transact(ctx, func(ctx context.Context, uow UnitOfWork) error {
+		uow.EventStore = t.enc.EventStore(uow.EventStore)
+		return fn(ctx, uow)
+	})
+}
@@ -0,0 +1,315 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRealmEncryption(t *testing.T) {
+	t.Run("stores the events of an encrypted realm encrypted", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1")
+
+		// When
+		tc.event_is_appended("realm-1", `{"title":"secret plans"}`)
+
+		// Then
+		tc.no_error()
+		tc.stored_data_does_not_contain("secret plans")
+		tc.realm_has_data_key("realm-1")
+		tc.read_data_is("realm-1", `{"title":"secret plans"}`)
+	})
+
+	t.Run("leaves the events of other realms as they are", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1")
+
+		// When
+		tc.event_is_appended("realm-2", `{"title":"open plans"}`)
+
+		// Then
+		tc.no_error()
+		assert.JSONEq(t, `{"title":"open plans"}`, string(tc.inner.events[0].Data))
+		tc.realm_has_no_data_key("realm-2")
+	})
+
+	t.Run("encrypts every realm with a wildcard", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("*")
+
+		// When
+		tc.event_is_appended("realm-9", `{"title":"secret plans"}`)
+
+		// Then
+		tc.no_error()
+		tc.stored_data_does_not_contain("secret plans")
+	})
+
+	t.Run("decrypts events after the realm is dropped from the list", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1")
+		tc.event_is_appended("realm-1", `{"title":"secret plans"}`)
+		tc.no_error()
+
+		// When
+		tc.encryption_for()
+
+		// Then
+		tc.read_data_is("realm-1", `{"title":"secret plans"}`)
+	})
+
+	t.Run("fails to decrypt an event copied into another realm", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1", "realm-2")
+		tc.event_is_appended("realm-1", `{"title":"secret plans"}`)
+		tc.event_is_appended("realm-2", `{"title":"other plans"}`)
+		tc.no_error()
+		tc.inner.events[1].Data = tc.inner.events[0].Data
+
+		// When
+		_, err := tc.store.ReadAll(tc.ctx, "realm-2", 0)
+
+		// Then
+		assert.ErrorIs(t, err, ErrDecryptionFailed)
+	})
+
+	t.Run("seals redacted data of an encrypted realm", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1")
+		tc.event_is_appended("realm-1", `{"title":"secret plans"}`)
+		tc.no_error()
+		redacted := Event{Version: 1, Data: []byte(`{"title":"redacted"}`)}
+
+		// When
+		tc.err = tc.store.(EventRedactor).RedactStream(tc.ctx, "realm-1", "s-1", []Event{redacted})
+
+		// Then
+		tc.no_error()
+		tc.stored_data_does_not_contain("redacted")
+		tc.read_data_is("realm-1", `{"title":"redacted"}`)
+	})
+
+	t.Run("encrypts snapshots of an encrypted realm", func(t *testing.T) {
+		tc := newEncryptionTestContext(t)
+
+		// Given
+		tc.encryption_for("realm-1")
+		snapshots := tc.store.(SnapshotStore)
+
+		// When
+		err := snapshots.SaveSnapshot(tc.ctx, Snapshot{RealmID: "realm-1", StreamID: "s-1", Version: 3, State: json.RawMessage(`{"title":"secret plans"}`)})
+
+		// Then
+		require.NoError(t, err)
+		assert.NotContains(t, string(tc.inner.snapshots["s-1"].State), "secret plans")
+		snapshot, err := snapshots.GetSnapshot(tc.ctx, "realm-1", "s-1")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title":"secret plans"}`, string(snapshot.State))
+	})
+}
+
+func TestNewAESKeyWrapper(t *testing.T) {
+	t.Run("unwraps what it wraps", func(t *testing.T) {
+		// Given
+		wrapper, err := NewAESKeyWrapper(bytes.Repeat([]byte{7}, 32))
+		require.NoError(t, err)
+
+		// When
+		wrapped, err := wrapper.WrapKey(context.Background(), []byte("data key"))
+		require.NoError(t, err)
+		key, err := wrapper.UnwrapKey(context.Background(), wrapped)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), key)
+		assert.NotContains(t, string(wrapped), "data key")
+	})
+
+	t.Run("rejects a master key that is not 32 bytes", func(t *testing.T) {
+		// When
+		_, err := NewAESKeyWrapper([]byte("short"))
+
+		// Then
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
+
+// --- Test Context ---
+
+type encryptionTestContext struct {
+	t     *testing.T
+	ctx   context.Context
+	inner *keyedEventStore
+	store EventStore
+	err   error
+}
+
+func newEncryptionTestContext(t *testing.T) *encryptionTestContext {
+	t.Helper()
+	return &encryptionTestContext{
+		t:   t,
+		ctx: context.Background(),
+		inner: &keyedEventStore{
+			keys:      make(map[string][]byte),
+			snapshots: make(map[string]Snapshot),
+		},
+	}
+}
+
+// --- Given ---
+
+func (tc *encryptionTestContext) encryption_for(realmIDs ...string) {
+	tc.t.Helper()
+	wrapper, err := NewAESKeyWrapper(bytes.Repeat([]byte{1}, 32))
+	require.NoError(tc.t, err)
+	tc.store = NewRealmEncryption(wrapper, realmIDs...).EventStore(tc.inner)
+}
+
+// --- When ---
+
+func (tc *encryptionTestContext) event_is_appended(realmID, data string) {
+	tc.t.Helper()
+	_, tc.err = tc.store.Append(tc.ctx, realmID, "s-1", 0, []EventData{
+		{EventType: "Noted", Data: json.RawMessage(data)},
+	})
+}
+
+// --- Then ---
+
+func (tc *encryptionTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *encryptionTestContext) stored_data_does_not_contain(text string) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.inner.events)
+	for _, evt := range tc.inner.events {
+		assert.NotContains(tc.t, string(evt.Data), text)
+	}
+}
+
+func (tc *encryptionTestContext) realm_has_data_key(realmID string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.inner.keys, realmID)
+}
+
+func (tc *encryptionTestContext) realm_has_no_data_key(realmID string) {
+	tc.t.Helper()
+	assert.NotContains(tc.t, tc.inner.keys, realmID)
+}
+
+func (tc *encryptionTestContext) read_data_is(realmID, expected string) {
+	tc.t.Helper()
+	events, err := tc.store.ReadStream(tc.ctx, realmID, "s-1", 0)
+	require.NoError(tc.t, err)
+	require.Len(tc.t, events, 1)
+	assert.JSONEq(tc.t, expected, string(events[0].Data))
+}
+
+// --- Mock Keyed Event Store ---
+
+// keyedEventStore is an event store with one stream per realm that keeps
+// data keys and snapshots, and can redact events.
+type keyedEventStore struct {
+	events    []Event
+	keys      map[string][]byte
+	snapshots map[string]Snapshot
+}
+
+func (s *keyedEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []EventData) ([]Event, error) {
+	var result []Event
+	for i, ed := range events {
+		data, err := json.Marshal(ed.Data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Event{
+			RealmID:        realmID,
+			StreamID:       streamID,
+			Version:        expectedVersion + i + 1,
+			GlobalPosition: int64(len(s.events) + i + 1),
+			EventType:      ed.EventType,
+			Data:           data,
+			Timestamp:      ClockFromContext(ctx).Now().UTC(),
+		})
+	}
+	s.events = append(s.events, result...)
+	return append([]Event(nil), result...), nil
+}
+
+func (s *keyedEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]Event, error) {
+	return s.ReadAll(ctx, realmID, 0)
+}
+
+func (s *keyedEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]Event, error) {
+	var events []Event
+	for _, evt := range s.events {
+		if evt.RealmID == realmID && evt.GlobalPosition > fromGlobalPosition {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (s *keyedEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (s *keyedEventStore) GetDataKey(ctx context.Context, realmID string) ([]byte, error) {
+	wrapped, ok := s.keys[realmID]
+	if !ok {
+		return nil, &NotFoundError{Entity: "data key", ID: realmID}
+	}
+	return wrapped, nil
+}
+
+func (s *keyedEventStore) AddDataKey(ctx context.Context, realmID string, wrapped []byte) error {
+	if _, ok := s.keys[realmID]; !ok {
+		s.keys[realmID] = wrapped
+	}
+	return nil
+}
+
+func (s *keyedEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []Event) error {
+	for _, evt := range redacted {
+		for i := range s.events {
+			if s.events[i].RealmID == realmID && s.events[i].Version == evt.Version {
+				s.events[i].Data = evt.Data
+			}
+		}
+	}
+	return nil
+}
+
+func (s *keyedEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (Snapshot, error) {
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return Snapshot{}, &NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	return snapshot, nil
+}
+
+func (s *keyedEventStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestExportRealm(t *testing.T) {
+	t.Run("writes one event per line with embedded data", func(t *testing.T) {
+		tc := newExportTestContext(t)
+
+		// Given
+		tc.events_in("realm-1", 2)
+		tc.events_in("realm-2", 1)
+
+		// When
+		tc.realm_is_exported("realm-1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 2, tc.count)
+		exported := tc.exported_events()
+		require.Len(t, exported, 2)
+		assert.Equal(t, "realm-1", exported[0].RealmID)
+		assert.Equal(t, int64(1), exported[0].GlobalPosition)
+		assert.JSONEq(t, `{"n":1}`, string(exported[0].Data))
+		assert.Equal(t, int64(2), exported[1].GlobalPosition)
+	})
+
+	t.Run("reads stores that read in batches one batch after another", func(t *testing.T) {
+		tc := newExportTestContext(t)
+
+		// Given
+		tc.events_in("realm-1", ExportBatchSize+5)
+		tc.store_reads_in_batches()
+
+		// When
+		tc.realm_is_exported("realm-1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, ExportBatchSize+5, tc.count)
+		assert.Equal(t, 2, tc.batching.batches)
+	})
+}
+
+// --- Test Context ---
+
+type exportTestContext struct {
+	t *testing.T
+
+	inner    *keyedEventStore
+	batching *batchingEventStore
+	store    EventStore
+	out      bytes.Buffer
+
+	count int
+	err   error
+}
+
+func newExportTestContext(t *testing.T) *exportTestContext {
+	t.Helper()
+	inner := &keyedEventStore{}
+	return &exportTestContext{t: t, inner: inner, store: inner}
+}
+
+// --- Given ---
+
+func (tc *exportTestContext) events_in(realmID string, n int) {
+	tc.t.Helper()
+	for i := range n {
+		_, err := tc.inner.Append(context.Background(), realmID, "s-1", i, []EventData{
+			{EventType: "Noted", Data: map[string]int{"n": i + 1}},
+		})
+		require.NoError(tc.t, err)
+	}
+}
+
+func (tc *exportTestContext) store_reads_in_batches() {
+	tc.t.Helper()
+	tc.batching = &batchingEventStore{keyedEventStore: tc.inner}
+	tc.store = tc.batching
+}
+
+// --- When ---
+
+func (tc *exportTestContext) realm_is_exported(realmID string) {
+	tc.t.Helper()
+	tc.count, tc.err = ExportRealm(context.Background(), tc.store, realmID, &tc.out)
+}
+
+// --- Then ---
+
+func (tc *exportTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *exportTestContext) exported_events() []ExportedEvent {
+	tc.t.Helper()
+	var events []ExportedEvent
+	scanner := bufio.NewScanner(&tc.out)
+	for scanner.Scan() {
+		var evt ExportedEvent
+		require.NoError(tc.t, json.Unmarshal(scanner.Bytes(), &evt))
+		events = append(events, evt)
+	}
+	return events
+}
+
+// --- Mock Batching Event Store ---
+
+// batchingEventStore reads a keyedEventStore's feed in batches and counts
+// them.
+type batchingEventStore struct {
+	*keyedEventStore
+	batches int
+}
+
+func (s *batchingEventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]Event, error) {
+	s.batches++
+	events, err := s.ReadAll(ctx, realmID, fromGlobalPosition)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, err
+}
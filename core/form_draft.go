@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// FormDraft is the partially filled state of a create or edit form, saved
+// per account so an unsubmitted form survives a closed tab or a crash.
+type FormDraft struct {
+	AccountID string `json:"account_id"`
+	RealmID   string `json:"realm_id"`
+	// FormKey names the form, such as "create-rune" or "update-rune:bf-a1b2".
+	FormKey   string          `json:"form"`
+	Fields    json.RawMessage `json:"fields"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// DraftStore holds form drafts outside the event store; drafts are scratch
+// state that is overwritten on every autosave and has no history worth keeping.
+type DraftStore interface {
+	// SaveDraft creates or replaces the draft for the account, realm and form.
+	SaveDraft(ctx context.Context, draft FormDraft) error
+	// GetDraft returns a single draft, or a NotFoundError.
+	GetDraft(ctx context.Context, accountID, realmID, formKey string) (FormDraft, error)
+	// ListDrafts returns the account's drafts in a realm, most recently saved first.
+	ListDrafts(ctx context.Context, accountID, realmID string) ([]FormDraft, error)
+	// DeleteDraft removes a draft. Deleting a missing draft is not an error.
+	DeleteDraft(ctx context.Context, accountID, realmID, formKey string) error
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidImport is returned by ImportRealm for a dump it cannot import.
+var ErrInvalidImport = errors.New("invalid import")
+
+// ErrRealmNotEmpty is returned by ImportRealm for a realm that already has
+// events.
+var ErrRealmNotEmpty = errors.New("realm already has events")
+
+// maxImportLine bounds the length of a line of an import.
+const maxImportLine = 16 << 20
+
+// ImportRealm appends the events of a dump written by ExportRealm to the
+// realm realmID, which must have no events yet, and returns how many it
+// appended. The realm the dump was exported from is replaced by realmID.
+// Events keep their streams, versions, timestamps and order; global
+// positions are given anew.
+//
+// The whole dump is read and checked before anything is appended: it must
+// come from a single realm, and each stream's versions must count up from
+// 1. An append that fails part way leaves the events before it in place.
+func ImportRealm(ctx context.Context, store EventStore, realmID string, r io.Reader) (int, error) {
+	events, err := readImport(r)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := ReadPage(ctx, store, realmID, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(existing.Events) > 0 {
+		return 0, fmt.Errorf("%w: %s", ErrRealmNotEmpty, realmID)
+	}
+
+	count := 0
+	for start := 0; start < len(events); {
+		// Consecutive events of a stream appended at once keep their
+		// timestamp in a single append.
+		end := start + 1
+		for end < len(events) && events[end].StreamID == events[start].StreamID && events[end].Timestamp.Equal(events[start].Timestamp) {
+			end++
+		}
+		batch := make([]EventData, 0, end-start)
+		for _, evt := range events[start:end] {
+			ed := EventData{EventType: evt.EventType, Data: evt.Data}
+			if evt.Metadata != nil {
+				ed.Metadata = evt.Metadata
+			}
+			batch = append(batch, ed)
+		}
+		first := events[start]
+		appendCtx := ContextWithClock(ctx, FixedClock(first.Timestamp))
+		if _, err := store.Append(appendCtx, realmID, first.StreamID, first.Version-1, batch); err != nil {
+			return count, fmt.Errorf("import stream %s: %w", first.StreamID, err)
+		}
+		count += end - start
+		start = end
+	}
+	return count, nil
+}
+
+// readImport decodes and checks every line of a dump.
+func readImport(r io.Reader) ([]ExportedEvent, error) {
+	var events []ExportedEvent
+	versions := make(map[string]int)
+	var sourceRealm string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxImportLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var evt ExportedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImport, line, err)
+		}
+		switch {
+		case evt.StreamID == "" || evt.EventType == "":
+			return nil, fmt.Errorf("%w: line %d: stream_id and event_type are required", ErrInvalidImport, line)
+		case sourceRealm != "" && evt.RealmID != sourceRealm:
+			return nil, fmt.Errorf("%w: line %d: realm %q follows realm %q", ErrInvalidImport, line, evt.RealmID, sourceRealm)
+		case evt.Version != versions[evt.StreamID]+1:
+			return nil, fmt.Errorf("%w: line %d: stream %s version %d follows version %d", ErrInvalidImport, line, evt.StreamID, evt.Version, versions[evt.StreamID])
+		}
+		sourceRealm = evt.RealmID
+		versions[evt.StreamID] = evt.Version
+		events = append(events, evt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidImport, line+1, err)
+	}
+	return events, nil
+}
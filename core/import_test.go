@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestImportRealm(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("imports an export into another realm", func(t *testing.T) {
+		tc := newImportTestContext(t)
+
+		// Given
+		tc.source_event("s-1", at, `{"n":1}`)
+		tc.source_event("s-2", at, `{"n":2}`)
+		tc.source_event("s-1", at.Add(time.Minute), `{"n":3}`)
+		tc.source_is_exported()
+
+		// When
+		tc.dump_is_imported_into("realm-2")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 3, tc.count)
+		events := tc.imported_events("realm-2")
+		require.Len(t, events, 3)
+		assert.Equal(t, "s-1", events[0].StreamID)
+		assert.Equal(t, "s-2", events[1].StreamID)
+		assert.Equal(t, "s-1", events[2].StreamID)
+		assert.JSONEq(t, `{"n":3}`, string(events[2].Data))
+		assert.Equal(t, at.Add(time.Minute), events[2].Timestamp)
+	})
+
+	t.Run("refuses a realm that already has events", func(t *testing.T) {
+		tc := newImportTestContext(t)
+
+		// Given
+		tc.source_event("s-1", at, `{"n":1}`)
+		tc.source_is_exported()
+
+		// When
+		tc.dump_is_imported_into("realm-1")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrRealmNotEmpty)
+	})
+
+	t.Run("rejects a stream whose versions skip one", func(t *testing.T) {
+		tc := newImportTestContext(t)
+
+		// Given
+		tc.dump_is(
+			`{"realm_id":"realm-1","stream_id":"s-1","version":1,"event_type":"Noted","data":{}}`,
+			`{"realm_id":"realm-1","stream_id":"s-1","version":3,"event_type":"Noted","data":{}}`,
+		)
+
+		// When
+		tc.dump_is_imported_into("realm-2")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidImport)
+		assert.ErrorContains(t, tc.err, "line 2")
+		assert.Empty(t, tc.imported_events("realm-2"))
+	})
+
+	t.Run("rejects a dump of several realms", func(t *testing.T) {
+		tc := newImportTestContext(t)
+
+		// Given
+		tc.dump_is(
+			`{"realm_id":"realm-1","stream_id":"s-1","version":1,"event_type":"Noted","data":{}}`,
+			`{"realm_id":"realm-3","stream_id":"s-2","version":1,"event_type":"Noted","data":{}}`,
+		)
+
+		// When
+		tc.dump_is_imported_into("realm-2")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidImport)
+	})
+
+	t.Run("rejects a line that is not an event", func(t *testing.T) {
+		tc := newImportTestContext(t)
+
+		// Given
+		tc.dump_is(`not json`)
+
+		// When
+		tc.dump_is_imported_into("realm-2")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidImport)
+	})
+}
+
+// --- Test Context ---
+
+type importTestContext struct {
+	t *testing.T
+
+	store    *keyedEventStore
+	versions map[string]int
+	dump     bytes.Buffer
+
+	count int
+	err   error
+}
+
+func newImportTestContext(t *testing.T) *importTestContext {
+	t.Helper()
+	return &importTestContext{
+		t:        t,
+		store:    &keyedEventStore{},
+		versions: make(map[string]int),
+	}
+}
+
+// --- Given ---
+
+func (tc *importTestContext) source_event(streamID string, at time.Time, data string) {
+	tc.t.Helper()
+	ctx := ContextWithClock(context.Background(), FixedClock(at))
+	version := tc.versions[streamID]
+	_, err := tc.store.Append(ctx, "realm-1", streamID, version, []EventData{
+		{EventType: "Noted", Data: json.RawMessage(data)},
+	})
+	require.NoError(tc.t, err)
+	tc.versions[streamID] = version + 1
+}
+
+func (tc *importTestContext) source_is_exported() {
+	tc.t.Helper()
+	_, err := ExportRealm(context.Background(), tc.store, "realm-1", &tc.dump)
+	require.NoError(tc.t, err)
+}
+
+func (tc *importTestContext) dump_is(lines ...string) {
+	tc.t.Helper()
+	tc.dump.WriteString(strings.Join(lines, "\n") + "\n")
+}
+
+// --- When ---
+
+func (tc *importTestContext) dump_is_imported_into(realmID string) {
+	tc.t.Helper()
+	tc.count, tc.err = ImportRealm(context.Background(), tc.store, realmID, &tc.dump)
+}
+
+// --- Then ---
+
+func (tc *importTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *importTestContext) imported_events(realmID string) []Event {
+	tc.t.Helper()
+	events, err := tc.store.ReadAll(context.Background(), realmID, 0)
+	require.NoError(tc.t, err)
+	return events
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event store operations, as StoreCall.Op reports them. Batched feed reads
+// count as OpReadAll and stream pages as OpReadStream.
+const (
+	OpAppend       = "append"
+	OpReadStream   = "read_stream"
+	OpReadAll      = "read_all"
+	OpListRealmIDs = "list_realm_ids"
+)
+
+// StoreCall describes one finished call to an instrumented event store.
+type StoreCall struct {
+	Op       string
+	RealmID  string
+	StreamID string // empty for feed reads and OpListRealmIDs
+	// Events is the number of events appended or read, and Bytes the size
+	// of their data as the store returned it.
+	Events   int
+	Bytes    int
+	Duration time.Duration
+	Err      error
+}
+
+// EventStoreHooks is told about every call to an event store wrapped by
+// InstrumentEventStore, so metrics and logging can be added once for every
+// provider. Hooks run on the caller's goroutine after the call returns and
+// must be quick.
+type EventStoreHooks interface {
+	OnAppend(ctx context.Context, call StoreCall)
+	// OnRead is told about stream, feed and realm list reads.
+	OnRead(ctx context.Context, call StoreCall)
+}
+
+// InstrumentEventStore returns store with each call reported to hooks, in
+// order. Snapshots, subscriptions and redactions pass through unreported.
+func InstrumentEventStore(store EventStore, hooks ...EventStoreHooks) EventStore {
+	return &instrumentedEventStore{inner: store, hooks: hooks}
+}
+
+// InstrumentTransactor returns t with the event store of each unit of work
+// instrumented like InstrumentEventStore's. Calls are reported as they are
+// made, so appends of a unit of work that rolls back are reported too.
+func InstrumentTransactor(t Transactor, hooks ...EventStoreHooks) Transactor {
+	return &instrumentedTransactor{inner: t, hooks: hooks}
+}
+
+type instrumentedTransactor struct {
+	inner Transactor
+	hooks []EventStoreHooks
+}
+
+func (t *instrumentedTransactor) Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error {
+	return t.inner.Transact(ctx, func(ctx context.Context, uow UnitOfWork) error {
+		uow.EventStore = InstrumentEventStore(uow.EventStore, t.hooks...)
+		return fn(ctx, uow)
+	})
+}
+
+type instrumentedEventStore struct {
+	inner EventStore
+	hooks []EventStoreHooks
+}
+
+func (s *instrumentedEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []EventData) ([]Event, error) {
+	start := time.Now()
+	appended, err := s.inner.Append(ctx, realmID, streamID, expectedVersion, events)
+	call := newStoreCall(OpAppend, realmID, streamID, appended, start, err)
+	for _, h := range s.hooks {
+		h.OnAppend(ctx, call)
+	}
+	return appended, err
+}
+
+func (s *instrumentedEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]Event, error) {
+	start := time.Now()
+	events, err := s.inner.ReadStream(ctx, realmID, streamID, fromVersion)
+	s.read(ctx, newStoreCall(OpReadStream, realmID, streamID, events, start, err))
+	return events, err
+}
+
+// ReadStreamPage reads just the page when the wrapped store can, and
+// otherwise trims a read of the stream.
+func (s *instrumentedEventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir Direction) ([]Event, error) {
+	start := time.Now()
+	page, err := ReadStreamPage(ctx, s.inner, realmID, streamID, from, limit, dir)
+	s.read(ctx, newStoreCall(OpReadStream, realmID, streamID, page.Events, start, err))
+	return page.Events, err
+}
+
+func (s *instrumentedEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]Event, error) {
+	start := time.Now()
+	events, err := s.inner.ReadAll(ctx, realmID, fromGlobalPosition)
+	s.read(ctx, newStoreCall(OpReadAll, realmID, "", events, start, err))
+	return events, err
+}
+
+// ReadAllBatch reads in batches when the wrapped store can, and otherwise
+// trims a full read to the limit.
+func (s *instrumentedEventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]Event, error) {
+	start := time.Now()
+	page, err := ReadPage(ctx, s.inner, realmID, fromGlobalPosition, limit)
+	s.read(ctx, newStoreCall(OpReadAll, realmID, "", page.Events, start, err))
+	return page.Events, err
+}
+
+func (s *instrumentedEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	realmIDs, err := s.inner.ListRealmIDs(ctx)
+	s.read(ctx, newStoreCall(OpListRealmIDs, "", "", nil, start, err))
+	return realmIDs, err
+}
+
+func (s *instrumentedEventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan Event, error) {
+	if subscriber, ok := s.inner.(EventSubscriber); ok {
+		return subscriber.Subscribe(ctx, fromPosition)
+	}
+	return nil, errors.New("event store does not push events")
+}
+
+func (s *instrumentedEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (Snapshot, error) {
+	if snapshots, ok := s.inner.(SnapshotStore); ok {
+		return snapshots.GetSnapshot(ctx, realmID, streamID)
+	}
+	return Snapshot{}, &NotFoundError{Entity: "snapshot", ID: streamID}
+}
+
+func (s *instrumentedEventStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	if snapshots, ok := s.inner.(SnapshotStore); ok {
+		return snapshots.SaveSnapshot(ctx, snapshot)
+	}
+	return nil
+}
+
+func (s *instrumentedEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []Event) error {
+	if redactor, ok := s.inner.(EventRedactor); ok {
+		return redactor.RedactStream(ctx, realmID, streamID, redacted)
+	}
+	return errors.New("event store cannot redact events")
+}
+
+func (s *instrumentedEventStore) read(ctx context.Context, call StoreCall) {
+	for _, h := range s.hooks {
+		h.OnRead(ctx, call)
+	}
+}
+
+func newStoreCall(op, realmID, streamID string, events []Event, start time.Time, err error) StoreCall {
+	call := StoreCall{
+		Op:       op,
+		RealmID:  realmID,
+		StreamID: streamID,
+		Events:   len(events),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	for _, evt := range events {
+		call.Bytes += len(evt.Data)
+	}
+	return call
+}
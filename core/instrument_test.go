@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ BatchEventReader = (*instrumentedEventStore)(nil)
+	_ StreamPageReader = (*instrumentedEventStore)(nil)
+	_ SnapshotStore    = (*instrumentedEventStore)(nil)
+	_ EventSubscriber  = (*instrumentedEventStore)(nil)
+	_ EventRedactor    = (*instrumentedEventStore)(nil)
+)
+
+// --- Tests ---
+
+func TestInstrumentEventStore(t *testing.T) {
+	t.Run("reports appends with their size", func(t *testing.T) {
+		tc := newInstrumentTestContext(t)
+
+		// When
+		tc.event_is_appended("s-1", `{"title":"hello"}`)
+
+		// Then
+		tc.no_error()
+		tc.appends_are(StoreCall{Op: OpAppend, RealmID: "realm-1", StreamID: "s-1", Events: 1, Bytes: len(`{"title":"hello"}`)})
+		tc.reads_are()
+	})
+
+	t.Run("reports failed appends with their error", func(t *testing.T) {
+		tc := newInstrumentTestContext(t)
+
+		// Given
+		tc.inner.err = errors.New("disk full")
+
+		// When
+		tc.event_is_appended("s-1", `{}`)
+
+		// Then
+		assert.EqualError(t, tc.err, "disk full")
+		tc.appends_are(StoreCall{Op: OpAppend, RealmID: "realm-1", StreamID: "s-1", Err: tc.inner.err})
+	})
+
+	t.Run("reports stream, feed and realm list reads", func(t *testing.T) {
+		tc := newInstrumentTestContext(t)
+
+		// Given
+		tc.event_is_appended("s-1", `{}`)
+		tc.event_is_appended("s-1", `{}`)
+
+		// When
+		_, err := tc.store.ReadStream(tc.ctx, "realm-1", "s-1", 0)
+		require.NoError(t, err)
+		_, err = tc.store.(BatchEventReader).ReadAllBatch(tc.ctx, "realm-1", 0, 1)
+		require.NoError(t, err)
+		_, err = tc.store.ListRealmIDs(tc.ctx)
+		require.NoError(t, err)
+
+		// Then
+		tc.reads_are(
+			StoreCall{Op: OpReadStream, RealmID: "realm-1", StreamID: "s-1", Events: 2, Bytes: 4},
+			StoreCall{Op: OpReadAll, RealmID: "realm-1", Events: 1, Bytes: 2},
+			StoreCall{Op: OpListRealmIDs},
+		)
+	})
+
+	t.Run("reports each call to every hook in order", func(t *testing.T) {
+		tc := newInstrumentTestContext(t)
+
+		// Given
+		second := &recordingHooks{}
+		tc.store = InstrumentEventStore(tc.inner, tc.hooks, second)
+
+		// When
+		tc.event_is_appended("s-1", `{}`)
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.hooks.appends, 1)
+		assert.Len(t, second.appends, 1)
+	})
+}
+
+func TestInstrumentTransactor(t *testing.T) {
+	t.Run("reports appends made in a unit of work", func(t *testing.T) {
+		tc := newInstrumentTestContext(t)
+
+		// Given
+		transactor := InstrumentTransactor(&passThroughTransactor{store: tc.inner}, tc.hooks)
+
+		// When
+		tc.err = transactor.Transact(tc.ctx, func(ctx context.Context, uow UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "s-1", 0, []EventData{
+				{EventType: "Noted", Data: json.RawMessage(`{}`)},
+			})
+			return err
+		})
+
+		// Then
+		tc.no_error()
+		tc.appends_are(StoreCall{Op: OpAppend, RealmID: "realm-1", StreamID: "s-1", Events: 1, Bytes: 2})
+	})
+}
+
+// --- Test Context ---
+
+type instrumentTestContext struct {
+	t     *testing.T
+	ctx   context.Context
+	inner *failingEventStore
+	hooks *recordingHooks
+	store EventStore
+	err   error
+}
+
+func newInstrumentTestContext(t *testing.T) *instrumentTestContext {
+	t.Helper()
+	inner := &failingEventStore{keyedEventStore: &keyedEventStore{
+		keys:      make(map[string][]byte),
+		snapshots: make(map[string]Snapshot),
+	}}
+	hooks := &recordingHooks{}
+	return &instrumentTestContext{
+		t:     t,
+		ctx:   context.Background(),
+		inner: inner,
+		hooks: hooks,
+		store: InstrumentEventStore(inner, hooks),
+	}
+}
+
+// --- When ---
+
+func (tc *instrumentTestContext) event_is_appended(streamID, data string) {
+	_, tc.err = tc.store.Append(tc.ctx, "realm-1", streamID, 0, []EventData{
+		{EventType: "Noted", Data: json.RawMessage(data)},
+	})
+}
+
+// --- Then ---
+
+func (tc *instrumentTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *instrumentTestContext) appends_are(expected ...StoreCall) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, withoutDurations(tc.hooks.appends))
+}
+
+func (tc *instrumentTestContext) reads_are(expected ...StoreCall) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, withoutDurations(tc.hooks.reads))
+}
+
+func withoutDurations(calls []StoreCall) []StoreCall {
+	var trimmed []StoreCall
+	for _, call := range calls {
+		call.Duration = 0
+		trimmed = append(trimmed, call)
+	}
+	return trimmed
+}
+
+// --- Mock Hooks ---
+
+type recordingHooks struct {
+	appends []StoreCall
+	reads   []StoreCall
+}
+
+func (h *recordingHooks) OnAppend(_ context.Context, call StoreCall) {
+	h.appends = append(h.appends, call)
+}
+
+func (h *recordingHooks) OnRead(_ context.Context, call StoreCall) {
+	h.reads = append(h.reads, call)
+}
@@ -0,0 +1,21 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// CatchUpLease is the lease a projection engine must hold to run catch-up
+// when it shares its stores with other instances.
+const CatchUpLease = "projection_catch_up"
+
+// LeaseStore grants named, time-limited leases so that only one of several
+// instances sharing a store does a piece of work at a time.
+type LeaseStore interface {
+	// AcquireLease takes the lease for holder, or renews it if holder already
+	// has it, until ttl from now. It returns false while another holder's
+	// lease is unexpired.
+	AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease gives up the lease if holder has it.
+	ReleaseLease(ctx context.Context, name string, holder string) error
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxEntry is an appended event waiting to be published outside Bifrost.
+// It refers to the event by position, so the event is read, decrypted and
+// redacted like any other when it is published.
+type OutboxEntry struct {
+	ID             int64
+	RealmID        string
+	GlobalPosition int64
+	// Attempts counts the failed attempts to publish the event, the last of
+	// which failed with LastError.
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// Outbox holds appended events until they are published. Providers that
+// keep one record an entry in the same transaction as each event, so an
+// event cannot be committed without its entry.
+type Outbox interface {
+	// Due returns up to limit entries whose next attempt is due at now,
+	// oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]OutboxEntry, error)
+	// Delivered removes a published entry.
+	Delivered(ctx context.Context, id int64) error
+	// Retry records a failed attempt and when to make the next one.
+	Retry(ctx context.Context, id int64, next time.Time, lastErr string) error
+	// Pending counts the entries not yet published.
+	Pending(ctx context.Context) (int, error)
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ProjectionWrite is a Put or Delete staged by the engine during catch-up.
+type ProjectionWrite struct {
+	RealmID        string
+	ProjectionName string
+	Key            string
+	Value          json.RawMessage
+	Delete         bool
+}
+
+// BatchProjectionStore is implemented by projection stores that can apply
+// many writes at once, for example in a single transaction.
+type BatchProjectionStore interface {
+	ProjectionStore
+	WriteBatch(ctx context.Context, writes []ProjectionWrite) error
+}
+
+// WriteProjectionBatch applies writes in order, in one call when the store
+// supports batching and one at a time otherwise.
+func WriteProjectionBatch(ctx context.Context, store ProjectionStore, writes []ProjectionWrite) error {
+	if batch, ok := store.(BatchProjectionStore); ok {
+		return batch.WriteBatch(ctx, writes)
+	}
+	for _, w := range writes {
+		var err error
+		if w.Delete {
+			err = store.Delete(ctx, w.RealmID, w.ProjectionName, w.Key)
+		} else {
+			err = store.Put(ctx, w.RealmID, w.ProjectionName, w.Key, w.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectionBuffer stages a projector's writes so the engine can flush a
+// whole batch of events at once. Reads see the staged writes; a List of a
+// projection with staged writes flushes first, since listed values carry no
+// keys to merge them by.
+type projectionBuffer struct {
+	inner  ProjectionStore
+	writes []ProjectionWrite
+	index  map[string]int
+}
+
+func newProjectionBuffer(inner ProjectionStore) *projectionBuffer {
+	return &projectionBuffer{inner: inner, index: make(map[string]int)}
+}
+
+func (b *projectionBuffer) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	if i, ok := b.index[projectionEntryKey(realmID, projectionName, key)]; ok {
+		if b.writes[i].Delete {
+			return &NotFoundError{Entity: projectionName, ID: key}
+		}
+		return json.Unmarshal(b.writes[i].Value, dest)
+	}
+	return b.inner.Get(ctx, realmID, projectionName, key, dest)
+}
+
+func (b *projectionBuffer) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	for _, w := range b.writes {
+		if w.RealmID == realmID && w.ProjectionName == projectionName {
+			if err := b.flush(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return b.inner.List(ctx, realmID, projectionName)
+}
+
+func (b *projectionBuffer) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	b.stage(ProjectionWrite{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data})
+	return nil
+}
+
+func (b *projectionBuffer) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	b.stage(ProjectionWrite{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true})
+	return nil
+}
+
+func (b *projectionBuffer) stage(w ProjectionWrite) {
+	k := projectionEntryKey(w.RealmID, w.ProjectionName, w.Key)
+	if i, ok := b.index[k]; ok {
+		b.writes[i] = w
+		return
+	}
+	b.index[k] = len(b.writes)
+	b.writes = append(b.writes, w)
+}
+
+// flush writes the staged batch through to the underlying store.
+func (b *projectionBuffer) flush(ctx context.Context) error {
+	if len(b.writes) == 0 {
+		return nil
+	}
+	if err := WriteProjectionBatch(ctx, b.inner, b.writes); err != nil {
+		return err
+	}
+	b.writes = nil
+	b.index = make(map[string]int)
+	return nil
+}
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ BatchProjectionStore = (*CachedProjectionStore)(nil)
+var _ BatchProjectionStore = (*batchRecordingStore)(nil)
+
+// --- Tests ---
+
+func TestProjectionBuffer(t *testing.T) {
+	t.Run("reads its own staged writes", func(t *testing.T) {
+		tc := newProjectionBufferTestContext(t)
+
+		// When
+		tc.put("k", "staged")
+
+		// Then
+		assert.Equal(t, "staged", tc.get("k"))
+		tc.inner_has_no("k")
+	})
+
+	t.Run("hides staged deletes", func(t *testing.T) {
+		tc := newProjectionBufferTestContext(t)
+
+		// Given
+		tc.inner_has("k", "stored")
+
+		// When
+		require.NoError(t, tc.buffer.Delete(context.Background(), "realm-1", "p", "k"))
+
+		// Then
+		var dest string
+		err := tc.buffer.Get(context.Background(), "realm-1", "p", "k", &dest)
+		var nfe *NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+
+	t.Run("flushes the last write per key in one batch", func(t *testing.T) {
+		tc := newProjectionBufferTestContext(t)
+
+		// Given
+		tc.put("a", "first")
+		tc.put("b", "only")
+		tc.put("a", "second")
+
+		// When
+		require.NoError(t, tc.buffer.flush(context.Background()))
+
+		// Then
+		assert.Equal(t, 1, tc.inner.batches)
+		assert.Equal(t, "second", tc.inner_value("a"))
+		assert.Equal(t, "only", tc.inner_value("b"))
+	})
+
+	t.Run("flushes before listing a projection with staged writes", func(t *testing.T) {
+		tc := newProjectionBufferTestContext(t)
+
+		// Given
+		tc.inner_has("a", "stored")
+		tc.put("b", "staged")
+
+		// When
+		raws, err := tc.buffer.List(context.Background(), "realm-1", "p")
+
+		// Then
+		require.NoError(t, err)
+		assert.Len(t, raws, 2)
+		assert.Equal(t, 1, tc.inner.batches)
+	})
+
+	t.Run("applies writes one at a time to stores without batching", func(t *testing.T) {
+		inner := &countingProjectionStore{data: map[string][]byte{}}
+		buffer := newProjectionBuffer(inner)
+
+		// Given
+		require.NoError(t, buffer.Put(context.Background(), "realm-1", "p", "a", "one"))
+		require.NoError(t, buffer.Delete(context.Background(), "realm-1", "p", "gone"))
+
+		// When
+		require.NoError(t, buffer.flush(context.Background()))
+
+		// Then
+		var dest string
+		require.NoError(t, inner.Get(context.Background(), "realm-1", "p", "a", &dest))
+		assert.Equal(t, "one", dest)
+	})
+}
+
+// --- Test Context ---
+
+type projectionBufferTestContext struct {
+	t *testing.T
+
+	inner  *batchRecordingStore
+	buffer *projectionBuffer
+}
+
+func newProjectionBufferTestContext(t *testing.T) *projectionBufferTestContext {
+	t.Helper()
+	inner := newBatchRecordingStore(nil)
+	return &projectionBufferTestContext{
+		t:      t,
+		inner:  inner,
+		buffer: newProjectionBuffer(inner),
+	}
+}
+
+// --- Given ---
+
+func (tc *projectionBufferTestContext) inner_has(key, value string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.inner.Put(context.Background(), "realm-1", "p", key, value))
+}
+
+// --- When ---
+
+func (tc *projectionBufferTestContext) put(key, value string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.buffer.Put(context.Background(), "realm-1", "p", key, value))
+}
+
+func (tc *projectionBufferTestContext) get(key string) string {
+	tc.t.Helper()
+	var dest string
+	require.NoError(tc.t, tc.buffer.Get(context.Background(), "realm-1", "p", key, &dest))
+	return dest
+}
+
+// --- Then ---
+
+func (tc *projectionBufferTestContext) inner_value(key string) string {
+	tc.t.Helper()
+	var dest string
+	require.NoError(tc.t, tc.inner.Get(context.Background(), "realm-1", "p", key, &dest))
+	return dest
+}
+
+func (tc *projectionBufferTestContext) inner_has_no(key string) {
+	tc.t.Helper()
+	var dest string
+	err := tc.inner.Get(context.Background(), "realm-1", "p", key, &dest)
+	var nfe *NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
+
+// --- Batch Recording Store ---
+
+type batchRecordingStore struct {
+	countingProjectionStore
+	batches  int
+	writeErr error
+}
+
+func newBatchRecordingStore(writeErr error) *batchRecordingStore {
+	return &batchRecordingStore{
+		countingProjectionStore: countingProjectionStore{data: map[string][]byte{}},
+		writeErr:                writeErr,
+	}
+}
+
+func (s *batchRecordingStore) List(_ context.Context, realmID, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var keys []string
+	for k := range s.data {
+		if len(k) > len(prefix) && k[:len(prefix)] == prefix {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	results := make([]json.RawMessage, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, s.data[k])
+	}
+	return results, nil
+}
+
+func (s *batchRecordingStore) WriteBatch(ctx context.Context, writes []ProjectionWrite) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	s.batches++
+	for _, w := range writes {
+		if w.Delete {
+			_ = s.Delete(ctx, w.RealmID, w.ProjectionName, w.Key)
+		} else {
+			_ = s.Put(ctx, w.RealmID, w.ProjectionName, w.Key, w.Value)
+		}
+	}
+	return nil
+}
+
+// countingProjector keeps a running count of the events it has seen, reading
+// the previous count back from the store each time.
+type countingProjector struct {
+	name string
+}
+
+func (p *countingProjector) Name() string {
+	return p.name
+}
+
+func (p *countingProjector) Handle(ctx context.Context, event Event, store ProjectionStore) error {
+	var count int
+	_ = store.Get(ctx, event.RealmID, "counts", "events", &count)
+	return store.Put(ctx, event.RealmID, "counts", "events", count+1)
+}
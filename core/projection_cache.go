@@ -0,0 +1,214 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// CachedProjectionStore is a ProjectionStore decorator that keeps the most
+// recently read entries in memory. Put and Delete write through to the
+// underlying store and evict the key, so the cache stays consistent as long
+// as every writer, including the projection engine, goes through it. List is
+// not cached.
+type CachedProjectionStore struct {
+	inner    ProjectionStore
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+	version uint64     // bumped on every write to discard in-flight reads
+}
+
+type cacheEntry struct {
+	key   string
+	value json.RawMessage
+}
+
+// NewCachedProjectionStore caches up to capacity entries read from inner.
+func NewCachedProjectionStore(inner ProjectionStore, capacity int) *CachedProjectionStore {
+	return &CachedProjectionStore{
+		inner:    inner,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *CachedProjectionStore) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	cacheKey := projectionEntryKey(realmID, projectionName, key)
+
+	c.mu.Lock()
+	if elem, ok := c.entries[cacheKey]; ok {
+		c.order.MoveToFront(elem)
+		value := elem.Value.(*cacheEntry).value
+		c.mu.Unlock()
+		return json.Unmarshal(value, dest)
+	}
+	version := c.version
+	c.mu.Unlock()
+
+	var value json.RawMessage
+	if err := c.inner.Get(ctx, realmID, projectionName, key, &value); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	// A write that landed while the value was being read may have made it
+	// stale, so only cache it if nothing has been written since.
+	if c.version == version {
+		c.add(cacheKey, value)
+	}
+	c.mu.Unlock()
+	return json.Unmarshal(value, dest)
+}
+
+func (c *CachedProjectionStore) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	return c.inner.List(ctx, realmID, projectionName)
+}
+
+func (c *CachedProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	defer c.invalidate(realmID, projectionName, key)
+	return c.inner.Put(ctx, realmID, projectionName, key, value)
+}
+
+func (c *CachedProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	defer c.invalidate(realmID, projectionName, key)
+	return c.inner.Delete(ctx, realmID, projectionName, key)
+}
+
+// WriteBatch applies writes through the underlying store and evicts every
+// key they touch.
+func (c *CachedProjectionStore) WriteBatch(ctx context.Context, writes []ProjectionWrite) error {
+	defer func() {
+		for _, w := range writes {
+			c.invalidate(w.RealmID, w.ProjectionName, w.Key)
+		}
+	}()
+	return WriteProjectionBatch(ctx, c.inner, writes)
+}
+
+// InvalidateProjections evicts keys that were written to the underlying
+// store without going through the cache.
+func (c *CachedProjectionStore) InvalidateProjections(writes []ProjectionWrite) {
+	for _, w := range writes {
+		c.invalidate(w.RealmID, w.ProjectionName, w.Key)
+	}
+}
+
+// InvalidateRealmProjection empties the cache, which does not know the keys
+// it held of the realm's projection.
+func (c *CachedProjectionStore) InvalidateRealmProjection(realmID string, projectionName string) {
+	c.clear()
+}
+
+// TruncateProjection truncates the projection in the underlying store and
+// empties the cache, which does not know the keys it held.
+func (c *CachedProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	truncator, err := c.truncator()
+	if err != nil {
+		return err
+	}
+	defer c.clear()
+	return truncator.TruncateProjection(ctx, projectionName)
+}
+
+// TruncateRealmProjection truncates the realm's projection in the
+// underlying store and empties the cache.
+func (c *CachedProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
+	truncator, err := c.truncator()
+	if err != nil {
+		return err
+	}
+	defer c.clear()
+	return truncator.TruncateRealmProjection(ctx, realmID, projectionName)
+}
+
+func (c *CachedProjectionStore) truncator() (ProjectionTruncator, error) {
+	truncator, ok := c.inner.(ProjectionTruncator)
+	if !ok {
+		return nil, fmt.Errorf("the underlying projection store cannot truncate projections")
+	}
+	return truncator, nil
+}
+
+func (c *CachedProjectionStore) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// CanFilter reports whether the underlying store can filter on every field of
+// filter.
+func (c *CachedProjectionStore) CanFilter(projectionName string, filter ProjectionFilter) bool {
+	filtered, ok := c.inner.(FilteredProjectionStore)
+	return ok && filtered.CanFilter(projectionName, filter)
+}
+
+// IndexFields has the underlying store index the fields, if it can.
+func (c *CachedProjectionStore) IndexFields(ctx context.Context, projectionName string, fields []string) error {
+	indexer, ok := c.inner.(ProjectionIndexer)
+	if !ok {
+		return nil
+	}
+	return indexer.IndexFields(ctx, projectionName, fields)
+}
+
+// ListWhere passes filtered lists through to the underlying store uncached.
+func (c *CachedProjectionStore) ListWhere(ctx context.Context, realmID string, projectionName string, filter ProjectionFilter) ([]json.RawMessage, error) {
+	return ListProjectionWhere(ctx, c.inner, realmID, projectionName, filter)
+}
+
+// DumpProjections dumps the realm's rows from the underlying store, if it
+// can.
+func (c *CachedProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(ProjectionRow) error) error {
+	dumper, ok := c.inner.(ProjectionDumper)
+	if !ok {
+		return fmt.Errorf("the underlying projection store cannot dump projections")
+	}
+	return dumper.DumpProjections(ctx, realmID, fn)
+}
+
+// Len returns the number of cached entries.
+func (c *CachedProjectionStore) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *CachedProjectionStore) add(cacheKey string, value json.RawMessage) {
+	if c.capacity <= 0 {
+		return
+	}
+	if elem, ok := c.entries[cacheKey]; ok {
+		elem.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[cacheKey] = c.order.PushFront(&cacheEntry{key: cacheKey, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *CachedProjectionStore) invalidate(realmID, projectionName, key string) {
+	cacheKey := projectionEntryKey(realmID, projectionName, key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	if elem, ok := c.entries[cacheKey]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, cacheKey)
+	}
+}
+
+func projectionEntryKey(realmID, projectionName, key string) string {
+	return realmID + "\x00" + projectionName + "\x00" + key
+}
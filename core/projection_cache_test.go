@@ -0,0 +1,259 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ ProjectionStore = (*CachedProjectionStore)(nil)
+var _ ProjectionInvalidator = (*CachedProjectionStore)(nil)
+var _ ProjectionTruncator = (*CachedProjectionStore)(nil)
+
+// --- Tests ---
+
+func TestCachedProjectionStore(t *testing.T) {
+	t.Run("serves repeated reads from memory", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+
+		// When
+		first := tc.get("realm-1", "rune_detail", "bf-a1")
+		second := tc.get("realm-1", "rune_detail", "bf-a1")
+
+		// Then
+		assert.Equal(t, "Bridge", first["title"])
+		assert.Equal(t, "Bridge", second["title"])
+		tc.inner_reads_were(1)
+	})
+
+	t.Run("evicts the key when it is put", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+		tc.get("realm-1", "rune_detail", "bf-a1")
+
+		// When
+		require.NoError(t, tc.cache.Put(context.Background(), "realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Tower"}))
+
+		// Then
+		assert.Equal(t, "Tower", tc.get("realm-1", "rune_detail", "bf-a1")["title"])
+		tc.inner_reads_were(2)
+	})
+
+	t.Run("evicts keys written around it", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+		tc.get("realm-1", "rune_detail", "bf-a1")
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Tower"})
+
+		// When
+		tc.cache.InvalidateProjections([]ProjectionWrite{{RealmID: "realm-1", ProjectionName: "rune_detail", Key: "bf-a1"}})
+
+		// Then
+		assert.Equal(t, "Tower", tc.get("realm-1", "rune_detail", "bf-a1")["title"])
+		tc.inner_reads_were(2)
+	})
+
+	t.Run("evicts a realm's projection truncated around it", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+		tc.get("realm-1", "rune_detail", "bf-a1")
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Tower"})
+
+		// When
+		tc.cache.InvalidateRealmProjection("realm-1", "rune_detail")
+
+		// Then
+		assert.Equal(t, "Tower", tc.get("realm-1", "rune_detail", "bf-a1")["title"])
+		tc.inner_reads_were(2)
+	})
+
+	t.Run("evicts the key when it is deleted", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+		tc.get("realm-1", "rune_detail", "bf-a1")
+
+		// When
+		require.NoError(t, tc.cache.Delete(context.Background(), "realm-1", "rune_detail", "bf-a1"))
+
+		// Then
+		var dest map[string]string
+		err := tc.cache.Get(context.Background(), "realm-1", "rune_detail", "bf-a1", &dest)
+		var nfe *NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+
+	t.Run("empties the cache when a projection is truncated", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "rune_detail", "bf-a1", map[string]string{"title": "Bridge"})
+		tc.get("realm-1", "rune_detail", "bf-a1")
+
+		// When
+		err := tc.cache.TruncateProjection(context.Background(), "rune_detail")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 0, tc.cache.Len())
+		var dest map[string]string
+		var nfe *NotFoundError
+		assert.ErrorAs(t, tc.cache.Get(context.Background(), "realm-1", "rune_detail", "bf-a1", &dest), &nfe)
+	})
+
+	t.Run("evicts the least recently used entry when full", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 2)
+
+		// Given
+		tc.inner_has("realm-1", "p", "a", map[string]string{})
+		tc.inner_has("realm-1", "p", "b", map[string]string{})
+		tc.inner_has("realm-1", "p", "c", map[string]string{})
+		tc.get("realm-1", "p", "a")
+		tc.get("realm-1", "p", "b")
+		tc.get("realm-1", "p", "a")
+
+		// When
+		tc.get("realm-1", "p", "c")
+
+		// Then
+		assert.Equal(t, 2, tc.cache.Len())
+		tc.get("realm-1", "p", "a")
+		tc.inner_reads_were(3)
+		tc.get("realm-1", "p", "b")
+		tc.inner_reads_were(4)
+	})
+
+	t.Run("keeps realms and projections apart", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 10)
+
+		// Given
+		tc.inner_has("realm-1", "p", "k", map[string]string{"v": "one"})
+		tc.inner_has("realm-2", "p", "k", map[string]string{"v": "two"})
+
+		// Then
+		assert.Equal(t, "one", tc.get("realm-1", "p", "k")["v"])
+		assert.Equal(t, "two", tc.get("realm-2", "p", "k")["v"])
+	})
+
+	t.Run("does not cache when capacity is zero", func(t *testing.T) {
+		tc := newProjectionCacheTestContext(t, 0)
+
+		// Given
+		tc.inner_has("realm-1", "p", "k", map[string]string{})
+
+		// When
+		tc.get("realm-1", "p", "k")
+		tc.get("realm-1", "p", "k")
+
+		// Then
+		tc.inner_reads_were(2)
+	})
+}
+
+// --- Test Context ---
+
+type projectionCacheTestContext struct {
+	t *testing.T
+
+	inner *countingProjectionStore
+	cache *CachedProjectionStore
+}
+
+func newProjectionCacheTestContext(t *testing.T, capacity int) *projectionCacheTestContext {
+	t.Helper()
+	inner := &countingProjectionStore{data: map[string][]byte{}}
+	return &projectionCacheTestContext{
+		t:     t,
+		inner: inner,
+		cache: NewCachedProjectionStore(inner, capacity),
+	}
+}
+
+// --- Given ---
+
+func (tc *projectionCacheTestContext) inner_has(realmID, projectionName, key string, value any) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.inner.Put(context.Background(), realmID, projectionName, key, value))
+}
+
+// --- When ---
+
+func (tc *projectionCacheTestContext) get(realmID, projectionName, key string) map[string]string {
+	tc.t.Helper()
+	var dest map[string]string
+	require.NoError(tc.t, tc.cache.Get(context.Background(), realmID, projectionName, key, &dest))
+	return dest
+}
+
+// --- Then ---
+
+func (tc *projectionCacheTestContext) inner_reads_were(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.inner.gets)
+}
+
+// --- Counting Projection Store ---
+
+type countingProjectionStore struct {
+	data map[string][]byte
+	gets int
+}
+
+func (s *countingProjectionStore) Get(_ context.Context, realmID, projectionName, key string, dest any) error {
+	s.gets++
+	data, ok := s.data[realmID+":"+projectionName+":"+key]
+	if !ok {
+		return &NotFoundError{Entity: projectionName, ID: key}
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (s *countingProjectionStore) List(_ context.Context, _, _ string) ([]json.RawMessage, error) {
+	return nil, nil
+}
+
+func (s *countingProjectionStore) Put(_ context.Context, realmID, projectionName, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.data[realmID+":"+projectionName+":"+key] = data
+	return nil
+}
+
+func (s *countingProjectionStore) Delete(_ context.Context, realmID, projectionName, key string) error {
+	delete(s.data, realmID+":"+projectionName+":"+key)
+	return nil
+}
+
+func (s *countingProjectionStore) TruncateProjection(_ context.Context, projectionName string) error {
+	for key := range s.data {
+		if strings.Split(key, ":")[1] == projectionName {
+			delete(s.data, key)
+		}
+	}
+	return nil
+}
+
+func (s *countingProjectionStore) TruncateRealmProjection(_ context.Context, realmID, projectionName string) error {
+	for key := range s.data {
+		if strings.HasPrefix(key, realmID+":"+projectionName+":") {
+			delete(s.data, key)
+		}
+	}
+	return nil
+}
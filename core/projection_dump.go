@@ -0,0 +1,28 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ProjectionRow is one stored projection value and its key.
+type ProjectionRow struct {
+	ProjectionName string
+	Key            string
+	Value          json.RawMessage
+}
+
+// ProjectionDumper is implemented by projection stores that can enumerate
+// every value they hold for a realm, for example to back them up.
+type ProjectionDumper interface {
+	// DumpProjections calls fn with each of the realm's rows, ordered by
+	// projection name and key, stopping at the first error fn returns.
+	DumpProjections(ctx context.Context, realmID string, fn func(ProjectionRow) error) error
+}
+
+// CheckpointLister is implemented by checkpoint stores that can list every
+// projector's checkpoint in a realm.
+type CheckpointLister interface {
+	// ListCheckpoints returns the realm's checkpoints by projector name.
+	ListCheckpoints(ctx context.Context, realmID string) (map[string]int64, error)
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// ProjectionFilter selects projection entries whose top-level JSON fields
+// equal the given values, compared in their "%v" form.
+type ProjectionFilter map[string]string
+
+// Matches reports whether raw satisfies every field of the filter.
+func (f ProjectionFilter) Matches(raw json.RawMessage) bool {
+	if len(f) == 0 {
+		return true
+	}
+	var item map[string]any
+	if json.Unmarshal(raw, &item) != nil {
+		return false
+	}
+	for field, want := range f {
+		if fmt.Sprintf("%v", item[field]) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// FilteredProjectionStore is implemented by projection stores that keep some
+// fields of some projections in indexed columns and can filter on them
+// without loading every entry.
+type FilteredProjectionStore interface {
+	ProjectionStore
+	// CanFilter reports whether every field of filter is indexed for the
+	// projection.
+	CanFilter(projectionName string, filter ProjectionFilter) bool
+	// ListWhere returns the projection values matching filter. It is only
+	// valid for filters accepted by CanFilter.
+	ListWhere(ctx context.Context, realmID string, projectionName string, filter ProjectionFilter) ([]json.RawMessage, error)
+}
+
+// IndexedProjector is implemented by projectors whose projections are listed
+// filtered on some of their fields, such as runes by status.
+type IndexedProjector interface {
+	Projector
+	// IndexedFields maps projections the projector writes to the top-level
+	// fields of their values that lists are filtered on. The fields should
+	// hold strings or numbers.
+	IndexedFields() map[string][]string
+}
+
+// ProjectionIndexer is implemented by projection stores that can index
+// fields of a projection's values. Once a field is indexed, the store
+// filters on it as a FilteredProjectionStore.
+type ProjectionIndexer interface {
+	IndexFields(ctx context.Context, projectionName string, fields []string) error
+}
+
+// EnsureIndexes has the projection store index the fields each registered
+// IndexedProjector declares. A store that cannot index is left alone, and
+// lists of it go on being filtered in memory.
+func (e *projectionEngine) EnsureIndexes(ctx context.Context) error {
+	indexer, ok := e.projectionStore.(ProjectionIndexer)
+	if !ok {
+		return nil
+	}
+	for _, projector := range e.projectors {
+		indexed, ok := projector.(IndexedProjector)
+		if !ok {
+			continue
+		}
+		fields := indexed.IndexedFields()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if err := indexer.IndexFields(ctx, name, fields[name]); err != nil {
+				return fmt.Errorf("indexing %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ListProjectionWhere returns the projection values matching filter, letting
+// the store do the filtering when it indexes every filtered field and
+// filtering the full list in memory otherwise.
+func ListProjectionWhere(ctx context.Context, store ProjectionStore, realmID string, projectionName string, filter ProjectionFilter) ([]json.RawMessage, error) {
+	if len(filter) > 0 {
+		if filtered, ok := store.(FilteredProjectionStore); ok && filtered.CanFilter(projectionName, filter) {
+			return filtered.ListWhere(ctx, realmID, projectionName, filter)
+		}
+	}
+	raws, err := store.List(ctx, realmID, projectionName)
+	if err != nil || len(filter) == 0 {
+		return raws, err
+	}
+	results := make([]json.RawMessage, 0, len(raws))
+	for _, raw := range raws {
+		if filter.Matches(raw) {
+			results = append(results, raw)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ FilteredProjectionStore = (*CachedProjectionStore)(nil)
+var _ FilteredProjectionStore = (*indexedProjectionStore)(nil)
+var _ ProjectionIndexer = (*CachedProjectionStore)(nil)
+
+// --- Tests ---
+
+func TestListProjectionWhere(t *testing.T) {
+	t.Run("filters in memory when the store has no index", func(t *testing.T) {
+		tc := newProjectionFilterTestContext(t)
+
+		// Given
+		tc.store_has("a", `{"status":"open","priority":1}`)
+		tc.store_has("b", `{"status":"open","priority":2}`)
+		tc.store_has("c", `{"status":"sealed","priority":1}`)
+
+		// When
+		tc.list_where(tc.store, ProjectionFilter{"status": "open", "priority": "1"})
+
+		// Then
+		tc.listed_values_are(`{"status":"open","priority":1}`)
+	})
+
+	t.Run("returns everything for an empty filter", func(t *testing.T) {
+		tc := newProjectionFilterTestContext(t)
+
+		// Given
+		tc.store_has("a", `{"status":"open"}`)
+		tc.store_has("b", `{"status":"sealed"}`)
+
+		// When
+		tc.list_where(tc.store, ProjectionFilter{})
+
+		// Then
+		assert.Len(t, tc.result, 2)
+	})
+
+	t.Run("delegates indexed filters to the store", func(t *testing.T) {
+		tc := newProjectionFilterTestContext(t)
+		indexed := &indexedProjectionStore{batchRecordingStore: tc.store, fields: map[string]bool{"status": true}}
+
+		// Given
+		tc.store_has("a", `{"status":"open"}`)
+
+		// When
+		tc.list_where(NewCachedProjectionStore(indexed, 10), ProjectionFilter{"status": "open"})
+
+		// Then
+		assert.Equal(t, 1, indexed.filtered)
+		tc.listed_values_are(`{"status":"open"}`)
+	})
+
+	t.Run("falls back when a field is not indexed", func(t *testing.T) {
+		tc := newProjectionFilterTestContext(t)
+		indexed := &indexedProjectionStore{batchRecordingStore: tc.store, fields: map[string]bool{"status": true}}
+
+		// Given
+		tc.store_has("a", `{"status":"open","title":"Bridge"}`)
+		tc.store_has("b", `{"status":"open","title":"Tower"}`)
+
+		// When
+		tc.list_where(indexed, ProjectionFilter{"status": "open", "title": "Tower"})
+
+		// Then
+		assert.Equal(t, 0, indexed.filtered)
+		tc.listed_values_are(`{"status":"open","title":"Tower"}`)
+	})
+}
+
+func TestProjectionEngine_EnsureIndexes(t *testing.T) {
+	t.Run("indexes the fields projectors declare", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.an_indexing_projection_store()
+		tc.an_indexed_projector("rune_view", map[string][]string{
+			"rune_view":          {"status", "priority"},
+			"rune_view_children": {"parent_id"},
+		})
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		err := tc.engine.EnsureIndexes(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rune_view.status", "rune_view.priority", "rune_view_children.parent_id"}, tc.indexingStore.indexed)
+	})
+
+	t.Run("leaves a store that cannot index alone", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.an_indexed_projector("rune_view", map[string][]string{"rune_view": {"status"}})
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		err := tc.engine.EnsureIndexes(context.Background())
+
+		// Then
+		require.NoError(t, err)
+	})
+}
+
+// --- Test Context ---
+
+type projectionFilterTestContext struct {
+	t *testing.T
+
+	store  *batchRecordingStore
+	result []json.RawMessage
+}
+
+func newProjectionFilterTestContext(t *testing.T) *projectionFilterTestContext {
+	t.Helper()
+	return &projectionFilterTestContext{
+		t:     t,
+		store: newBatchRecordingStore(nil),
+	}
+}
+
+// --- Given ---
+
+func (tc *projectionFilterTestContext) store_has(key, value string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.store.Put(context.Background(), "realm-1", "p", key, json.RawMessage(value)))
+}
+
+// --- When ---
+
+func (tc *projectionFilterTestContext) list_where(store ProjectionStore, filter ProjectionFilter) {
+	tc.t.Helper()
+	var err error
+	tc.result, err = ListProjectionWhere(context.Background(), store, "realm-1", "p", filter)
+	require.NoError(tc.t, err)
+}
+
+// --- Then ---
+
+func (tc *projectionFilterTestContext) listed_values_are(expected ...string) {
+	tc.t.Helper()
+	actual := make([]string, 0, len(tc.result))
+	for _, raw := range tc.result {
+		actual = append(actual, string(raw))
+	}
+	assert.Equal(tc.t, expected, actual)
+}
+
+// --- Mock Indexing Projection Store ---
+
+type indexingProjectionStore struct {
+	mockProjectionStore
+	indexed []string
+}
+
+func (s *indexingProjectionStore) IndexFields(_ context.Context, projectionName string, fields []string) error {
+	for _, field := range fields {
+		s.indexed = append(s.indexed, projectionName+"."+field)
+	}
+	return nil
+}
+
+// --- Mock Indexed Projector ---
+
+type indexedProjector struct {
+	*recordingProjector
+	fields map[string][]string
+}
+
+func (p *indexedProjector) IndexedFields() map[string][]string {
+	return p.fields
+}
+
+func (tc *catchUpTestContext) an_indexing_projection_store() {
+	tc.t.Helper()
+	tc.indexingStore = &indexingProjectionStore{}
+}
+
+func (tc *catchUpTestContext) an_indexed_projector(name string, fields map[string][]string) {
+	tc.t.Helper()
+	recorder := &recordingProjector{name: name}
+	tc.recorders[name] = recorder
+	tc.projector = &indexedProjector{recordingProjector: recorder, fields: fields}
+}
+
+// --- Indexed Projection Store ---
+
+// indexedProjectionStore claims an index on fields and counts the filtered
+// lists it serves.
+type indexedProjectionStore struct {
+	*batchRecordingStore
+	fields   map[string]bool
+	filtered int
+}
+
+func (s *indexedProjectionStore) CanFilter(_ string, filter ProjectionFilter) bool {
+	for field := range filter {
+		if !s.fields[field] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *indexedProjectionStore) ListWhere(ctx context.Context, realmID string, projectionName string, filter ProjectionFilter) ([]json.RawMessage, error) {
+	s.filtered++
+	raws, err := s.List(ctx, realmID, projectionName)
+	if err != nil {
+		return nil, err
+	}
+	var results []json.RawMessage
+	for _, raw := range raws {
+		if filter.Matches(raw) {
+			results = append(results, raw)
+		}
+	}
+	return results, nil
+}
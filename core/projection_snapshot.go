@@ -0,0 +1,203 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ProjectionRecord is a line of a projection snapshot or of a backup's
+// projections archive: a projector's checkpoint when Projector is set, and a
+// projection row otherwise.
+type ProjectionRecord struct {
+	RealmID    string `json:"realm_id"`
+	Projector  string `json:"projector,omitempty"`
+	Checkpoint int64  `json:"checkpoint,omitempty"`
+	// Version is the version of a VersionedProjector that wrote the rows.
+	Version    int             `json:"version,omitempty"`
+	Projection string          `json:"projection,omitempty"`
+	Key        string          `json:"key,omitempty"`
+	Value      json.RawMessage `json:"value,omitempty"`
+}
+
+// ProjectionSnapshotter is implemented by projection engines that can
+// export a projector's rows in a realm with its checkpoint, and import them
+// into another store in place of replaying the realm's events.
+type ProjectionSnapshotter interface {
+	ExportProjection(ctx context.Context, projectionName string, realmID string, w io.Writer) (int, error)
+	ImportProjection(ctx context.Context, r io.Reader) (ProjectionImport, error)
+}
+
+// ProjectionImport describes a snapshot ImportProjection loaded.
+type ProjectionImport struct {
+	RealmID    string `json:"realm_id"`
+	Projector  string `json:"projector"`
+	Checkpoint int64  `json:"checkpoint"`
+	Rows       int    `json:"rows"`
+}
+
+// ErrNotSnapshottable is returned by ExportProjection and ImportProjection
+// for a projection whose rows in a realm cannot be taken on their own, for
+// the reasons Rebuild refuses one.
+var ErrNotSnapshottable = errors.New("projection cannot be snapshotted")
+
+// ErrInvalidSnapshot is returned by ImportProjection for a snapshot it
+// cannot load.
+var ErrInvalidSnapshot = errors.New("invalid projection snapshot")
+
+// ExportProjection writes the realm's rows of the projections written by
+// the projector owning projectionName to w as newline-delimited JSON, and
+// returns how many it wrote. The first line is the projector's checkpoint
+// and version; one ProjectionRecord per row follows, ordered by projection
+// and key. The projector does not catch up in the realm meanwhile, so the
+// rows are exactly those of the checkpoint.
+func (e *projectionEngine) ExportProjection(ctx context.Context, projectionName string, realmID string, w io.Writer) (int, error) {
+	owner, err := e.realmOwner(projectionName, ErrNotSnapshottable)
+	if err != nil {
+		return 0, err
+	}
+	dumper, ok := e.projectionStore.(ProjectionDumper)
+	if !ok {
+		return 0, errors.New("the projection store cannot dump projections")
+	}
+
+	lock := e.lock(realmID, owner.Name())
+	lock.Lock()
+	defer lock.Unlock()
+	checkpoint, err := e.checkpointStore.GetCheckpoint(ctx, realmID, owner.Name())
+	if err != nil {
+		return 0, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	header := ProjectionRecord{RealmID: realmID, Projector: owner.Name(), Checkpoint: checkpoint, Version: versionOf(owner)}
+	if err := enc.Encode(header); err != nil {
+		return 0, err
+	}
+	projections := owner.Projections()
+	count := 0
+	err = dumper.DumpProjections(ctx, realmID, func(row ProjectionRow) error {
+		if !slices.Contains(projections, row.ProjectionName) {
+			return nil
+		}
+		count++
+		return enc.Encode(ProjectionRecord{RealmID: realmID, Projection: row.ProjectionName, Key: row.Key, Value: row.Value})
+	})
+	return count, err
+}
+
+// ImportProjection replaces the realm's rows of a projector's projections
+// with those of a snapshot written by ExportProjection, sets its checkpoint
+// to the snapshot's, and catches it up on the realm's later events. The
+// checkpoint is a global position, so the event store must hold the events
+// the snapshot was taken from at the same positions, as a replica of its
+// database does.
+//
+// The whole snapshot is read and checked first. It must come from this
+// projector at its current version, and its rows must be of the realm and
+// of the projections the projector writes.
+func (e *projectionEngine) ImportProjection(ctx context.Context, r io.Reader) (ProjectionImport, error) {
+	header, rows, err := readSnapshot(r)
+	if err != nil {
+		return ProjectionImport{}, err
+	}
+	result := ProjectionImport{RealmID: header.RealmID, Projector: header.Projector, Checkpoint: header.Checkpoint}
+	owner, err := e.realmOwner(header.Projector, ErrNotSnapshottable)
+	if err != nil {
+		return result, err
+	}
+	if owner.Name() != header.Projector {
+		return result, fmt.Errorf("%w: %q is a projection, not a projector", ErrInvalidSnapshot, header.Projector)
+	}
+	if version := versionOf(owner); header.Version != version {
+		return result, fmt.Errorf("%w: taken at version %d of %q, which is at version %d",
+			ErrInvalidSnapshot, header.Version, owner.Name(), version)
+	}
+	projections := owner.Projections()
+	for _, row := range rows {
+		if row.RealmID != header.RealmID || !slices.Contains(projections, row.ProjectionName) {
+			return result, fmt.Errorf("%w: row %s/%s/%s is not of %q in realm %s",
+				ErrInvalidSnapshot, row.RealmID, row.ProjectionName, row.Key, owner.Name(), header.RealmID)
+		}
+	}
+	truncator, err := e.truncator()
+	if err != nil {
+		return result, err
+	}
+	if !e.holdLease(ctx) {
+		return result, errors.New("catch-up runs on another instance")
+	}
+
+	lock := e.lock(header.RealmID, owner.Name())
+	lock.Lock()
+	defer lock.Unlock()
+	// As in Rebuild, an import cut short leaves the projector to replay the
+	// realm rather than trust rows half loaded.
+	if err := e.checkpointStore.SetCheckpoint(ctx, header.RealmID, owner.Name(), 0); err != nil {
+		return result, fmt.Errorf("resetting checkpoint: %w", err)
+	}
+	for _, name := range projections {
+		if err := truncator.TruncateRealmProjection(ctx, header.RealmID, name); err != nil {
+			return result, fmt.Errorf("truncating %s: %w", name, err)
+		}
+	}
+	for start := 0; start < len(rows); start += ExportBatchSize {
+		batch := rows[start:min(start+ExportBatchSize, len(rows))]
+		if err := WriteProjectionBatch(ctx, e.projectionStore, batch); err != nil {
+			return result, fmt.Errorf("writing rows: %w", err)
+		}
+		result.Rows += len(batch)
+	}
+	if err := e.checkpointStore.SetCheckpoint(ctx, header.RealmID, owner.Name(), header.Checkpoint); err != nil {
+		return result, fmt.Errorf("setting checkpoint: %w", err)
+	}
+	stores := UnitOfWork{EventStore: e.eventStore, ProjectionStore: e.projectionStore, CheckpointStore: e.checkpointStore}
+	if _, err := e.project(ctx, stores, header.RealmID, owner, nil); err != nil {
+		return result, fmt.Errorf("projecting %s: %w", header.RealmID, err)
+	}
+	return result, nil
+}
+
+// readSnapshot reads a snapshot's checkpoint record and its rows as writes.
+func readSnapshot(r io.Reader) (ProjectionRecord, []ProjectionWrite, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxImportLine)
+	var header ProjectionRecord
+	var rows []ProjectionWrite
+	for line := 1; scanner.Scan(); line++ {
+		var record ProjectionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return header, nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSnapshot, line, err)
+		}
+		switch {
+		case line == 1:
+			if record.Projector == "" || record.RealmID == "" {
+				return header, nil, fmt.Errorf("%w: line 1 is not a checkpoint", ErrInvalidSnapshot)
+			}
+			header = record
+		case record.Projector != "" || record.Projection == "":
+			return header, nil, fmt.Errorf("%w: line %d is not a row", ErrInvalidSnapshot, line)
+		default:
+			rows = append(rows, ProjectionWrite{RealmID: record.RealmID, ProjectionName: record.Projection, Key: record.Key, Value: record.Value})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if header.Projector == "" {
+		return header, nil, fmt.Errorf("%w: empty snapshot", ErrInvalidSnapshot)
+	}
+	return header, rows, nil
+}
+
+// versionOf returns the version of a VersionedProjector, and 0 for other
+// projectors.
+func versionOf(p Projector) int {
+	if versioned, ok := p.(VersionedProjector); ok {
+		return versioned.Version()
+	}
+	return 0
+}
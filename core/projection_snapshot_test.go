@@ -0,0 +1,234 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjectionEngine_ExportProjection(t *testing.T) {
+	t.Run("writes the checkpoint and then the projector's rows", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 7)
+		tc.a_snapshot_projection_store(
+			ProjectionRow{ProjectionName: "account_list", Key: "a-1", Value: json.RawMessage(`{"n":0}`)},
+			ProjectionRow{ProjectionName: "rune_view", Key: "r-1", Value: json.RawMessage(`{"n":1}`)},
+			ProjectionRow{ProjectionName: "rune_view_children", Key: "r-1", Value: json.RawMessage(`{"n":2}`)},
+		)
+		tc.a_versioned_projector("rune_view", 3, "rune_view", "rune_view_children")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.export_projection_is_called("rune_view_children", "realm-1")
+
+		// Then
+		require.NoError(t, tc.snapshotErr)
+		assert.Equal(t, 2, tc.exportedRows)
+		tc.snapshot_is(
+			`{"realm_id":"realm-1","projector":"rune_view","checkpoint":7,"version":3}`,
+			`{"realm_id":"realm-1","projection":"rune_view","key":"r-1","value":{"n":1}}`,
+			`{"realm_id":"realm-1","projection":"rune_view_children","key":"r-1","value":{"n":2}}`,
+		)
+	})
+
+	t.Run("refuses a projector with side effects", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_snapshot_projection_store()
+		tc.a_projection_owner("notifier", "notifier")
+		tc.projector_has_side_effects()
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.export_projection_is_called("notifier", "realm-1")
+
+		// Then
+		assert.ErrorIs(t, tc.snapshotErr, ErrNotSnapshottable)
+		tc.snapshot_is()
+	})
+}
+
+func TestProjectionEngine_ImportProjection(t *testing.T) {
+	t.Run("replaces the realm's rows and catches up from the checkpoint", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 2,
+			Event{EventType: "evt-3", GlobalPosition: 3, RealmID: "realm-1"},
+		)
+		tc.a_snapshot_projection_store()
+		tc.a_versioned_projector("rune_view", 3, "rune_view", "rune_view_children")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.import_projection_is_called(
+			`{"realm_id":"realm-1","projector":"rune_view","checkpoint":2,"version":3}`,
+			`{"realm_id":"realm-1","projection":"rune_view","key":"r-1","value":{"n":1}}`,
+		)
+
+		// Then
+		require.NoError(t, tc.snapshotErr)
+		assert.Equal(t, ProjectionImport{RealmID: "realm-1", Projector: "rune_view", Checkpoint: 2, Rows: 1}, tc.imported)
+		tc.truncated_projections_are("realm-1/rune_view", "realm-1/rune_view_children")
+		tc.rows_written_are(ProjectionWrite{RealmID: "realm-1", ProjectionName: "rune_view", Key: "r-1", Value: json.RawMessage(`{"n":1}`)})
+		tc.checkpoints_set("realm-1", "rune_view", []int64{0, 2, 3})
+		tc.catch_up_projector_handled_events("rune_view", []string{"evt-3"})
+	})
+
+	t.Run("loads what export wrote", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 4)
+		tc.a_snapshot_projection_store(
+			ProjectionRow{ProjectionName: "rune_view", Key: "r-1", Value: json.RawMessage(`{"n":1}`)},
+		)
+		tc.a_versioned_projector("rune_view", 1, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+		tc.export_projection_is_called("rune_view", "realm-1")
+		require.NoError(t, tc.snapshotErr)
+
+		// When
+		tc.import_projection_is_called(strings.Split(strings.TrimSpace(tc.snapshot.String()), "\n")...)
+
+		// Then
+		require.NoError(t, tc.snapshotErr)
+		tc.rows_written_are(ProjectionWrite{RealmID: "realm-1", ProjectionName: "rune_view", Key: "r-1", Value: json.RawMessage(`{"n":1}`)})
+		tc.checkpoint_is("realm-1", "rune_view", 4)
+	})
+
+	t.Run("rejects a snapshot taken at another version", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_snapshot_projection_store()
+		tc.a_versioned_projector("rune_view", 3, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.import_projection_is_called(`{"realm_id":"realm-1","projector":"rune_view","checkpoint":2,"version":2}`)
+
+		// Then
+		assert.ErrorIs(t, tc.snapshotErr, ErrInvalidSnapshot)
+		tc.truncated_projections_are()
+		tc.checkpoint_was_not_set("realm-1", "rune_view")
+	})
+
+	t.Run("rejects rows the projector does not write", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_snapshot_projection_store()
+		tc.a_versioned_projector("rune_view", 1, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.import_projection_is_called(
+			`{"realm_id":"realm-1","projector":"rune_view","checkpoint":2,"version":1}`,
+			`{"realm_id":"realm-1","projection":"account_list","key":"a-1","value":{}}`,
+		)
+
+		// Then
+		assert.ErrorIs(t, tc.snapshotErr, ErrInvalidSnapshot)
+		tc.truncated_projections_are()
+	})
+
+	t.Run("rejects a snapshot without a checkpoint", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_snapshot_projection_store()
+		tc.a_versioned_projector("rune_view", 1, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.import_projection_is_called(`{"realm_id":"realm-1","projection":"rune_view","key":"r-1","value":{}}`)
+
+		// Then
+		assert.ErrorIs(t, tc.snapshotErr, ErrInvalidSnapshot)
+		tc.truncated_projections_are()
+	})
+}
+
+// --- Mock Snapshot Projection Store ---
+
+type snapshotProjectionStore struct {
+	*truncatingProjectionStore
+	rows    []ProjectionRow
+	written []ProjectionWrite
+}
+
+func (s *snapshotProjectionStore) DumpProjections(_ context.Context, _ string, fn func(ProjectionRow) error) error {
+	for _, row := range s.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotProjectionStore) WriteBatch(_ context.Context, writes []ProjectionWrite) error {
+	s.written = append(s.written, writes...)
+	return nil
+}
+
+// --- Given ---
+
+func (tc *catchUpTestContext) a_snapshot_projection_store(rows ...ProjectionRow) {
+	tc.t.Helper()
+	tc.a_truncating_projection_store()
+	tc.snapshotStore = &snapshotProjectionStore{truncatingProjectionStore: tc.truncatingStore, rows: rows}
+}
+
+// --- When ---
+
+func (tc *catchUpTestContext) export_projection_is_called(projectionName, realmID string) {
+	tc.t.Helper()
+	tc.snapshot.Reset()
+	tc.exportedRows, tc.snapshotErr = tc.engine.ExportProjection(context.Background(), projectionName, realmID, &tc.snapshot)
+}
+
+func (tc *catchUpTestContext) import_projection_is_called(lines ...string) {
+	tc.t.Helper()
+	r := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	tc.imported, tc.snapshotErr = tc.engine.ImportProjection(context.Background(), r)
+}
+
+// --- Then ---
+
+func (tc *catchUpTestContext) snapshot_is(lines ...string) {
+	tc.t.Helper()
+	var actual []string
+	if s := strings.TrimSpace(tc.snapshot.String()); s != "" {
+		actual = strings.Split(s, "\n")
+	}
+	assert.Equal(tc.t, lines, actual)
+}
+
+func (tc *catchUpTestContext) rows_written_are(expected ...ProjectionWrite) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.snapshotStore.written)
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// VersionedProjector is implemented by projectors whose read model changes
+// shape between releases. Bumping Version makes RebuildOutdated clear the
+// projections the projector writes and project the event history into them
+// again, so a changed projector never serves rows in the old shape.
+// Projectors that are not versioned are never rebuilt this way.
+type VersionedProjector interface {
+	ProjectionOwner
+	// Version is the projector's current version, starting at 1.
+	Version() int
+}
+
+// ProjectorVersionStore keeps the version of each projector whose
+// checkpoints are stored, next to the checkpoints themselves.
+type ProjectorVersionStore interface {
+	// GetProjectorVersion returns the version recorded for the projector,
+	// or 0 if none is.
+	GetProjectorVersion(ctx context.Context, projectorName string) (int, error)
+	SetProjectorVersion(ctx context.Context, projectorName string, version int) error
+}
+
+// ProjectionTruncator is implemented by projection stores that can delete a
+// projection outright.
+type ProjectionTruncator interface {
+	// TruncateProjection deletes every entry of the projection in every
+	// realm.
+	TruncateProjection(ctx context.Context, projectionName string) error
+	// TruncateRealmProjection deletes every entry of the projection in the
+	// realm.
+	TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error
+}
+
+// RebuildOutdated compares each VersionedProjector's version with the one
+// recorded for it and, where they differ, truncates its projections and
+// resets its checkpoint in every realm, so catch-up projects the whole
+// history into them again. It returns the names of the projectors reset and
+// must run before catch-up starts. A projector with no version recorded is
+// taken to be at version 1, which is how databases that predate versioning
+// see their projectors.
+//
+// The version is recorded last, so a rebuild cut short is started over on
+// the next run.
+func (e *projectionEngine) RebuildOutdated(ctx context.Context) ([]string, error) {
+	if e.versions == nil {
+		return nil, nil
+	}
+	var rebuilt []string
+	for _, projector := range e.projectors {
+		versioned, ok := projector.(VersionedProjector)
+		if !ok {
+			continue
+		}
+		recorded, err := e.versions.GetProjectorVersion(ctx, versioned.Name())
+		if err != nil {
+			return rebuilt, fmt.Errorf("reading version of %s: %w", versioned.Name(), err)
+		}
+		recorded = max(recorded, 1)
+		if recorded != versioned.Version() {
+			log.Printf("catch-up: rebuilding %q, version %d was projected by version %d", versioned.Name(), versioned.Version(), recorded)
+			if err := e.reset(ctx, versioned); err != nil {
+				return rebuilt, fmt.Errorf("rebuilding %s: %w", versioned.Name(), err)
+			}
+			rebuilt = append(rebuilt, versioned.Name())
+		}
+		if err := e.versions.SetProjectorVersion(ctx, versioned.Name(), versioned.Version()); err != nil {
+			return rebuilt, fmt.Errorf("recording version of %s: %w", versioned.Name(), err)
+		}
+	}
+	return rebuilt, nil
+}
+
+// reset empties the projector's projections and moves its checkpoint in
+// every realm back to the start of the feed.
+func (e *projectionEngine) reset(ctx context.Context, projector VersionedProjector) error {
+	truncator, err := e.truncator()
+	if err != nil {
+		return err
+	}
+	for _, name := range projector.Projections() {
+		if err := truncator.TruncateProjection(ctx, name); err != nil {
+			return fmt.Errorf("truncating %s: %w", name, err)
+		}
+	}
+	realmIDs, err := e.eventStore.ListRealmIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("listing realms: %w", err)
+	}
+	for _, realmID := range realmIDs {
+		if err := e.checkpointStore.SetCheckpoint(ctx, realmID, projector.Name(), 0); err != nil {
+			return fmt.Errorf("resetting checkpoint of %s: %w", realmID, err)
+		}
+	}
+	return nil
+}
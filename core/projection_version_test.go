@@ -0,0 +1,242 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjectionEngine_RebuildOutdated(t *testing.T) {
+	t.Run("rebuilds a projector whose version changed", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1", "realm-2")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+			Event{EventType: "evt-2", GlobalPosition: 2, RealmID: "realm-1"},
+		)
+		tc.checkpoint("realm-1", "rune_view", 2)
+		tc.checkpoint("realm-2", "rune_view", 9)
+		tc.a_truncating_projection_store()
+		tc.projector_versions_recorded(map[string]int{"rune_view": 1})
+		tc.a_versioned_projector("rune_view", 2, "rune_view", "rune_view_children")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_outdated_is_called()
+		tc.run_catch_up_once_is_called()
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.rebuilt_projectors_are("rune_view")
+		tc.truncated_projections_are("rune_view", "rune_view_children")
+		tc.checkpoint_was_set("realm-2", "rune_view", 0)
+		tc.projector_version_is("rune_view", 2)
+		tc.catch_up_projector_handled_events("rune_view", []string{"evt-1", "evt-2"})
+	})
+
+	t.Run("leaves a projector at its recorded version alone", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 7)
+		tc.a_truncating_projection_store()
+		tc.projector_versions_recorded(map[string]int{"rune_view": 3})
+		tc.a_versioned_projector("rune_view", 3, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_outdated_is_called()
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.rebuilt_projectors_are()
+		tc.truncated_projections_are()
+		tc.checkpoint_is("realm-1", "rune_view", 7)
+	})
+
+	t.Run("takes a projector with no version recorded to be at version 1", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 7)
+		tc.a_truncating_projection_store()
+		tc.projector_versions_recorded(map[string]int{})
+		tc.a_versioned_projector("rune_view", 1, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_outdated_is_called()
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.rebuilt_projectors_are()
+		tc.checkpoint_is("realm-1", "rune_view", 7)
+		tc.projector_version_is("rune_view", 1)
+	})
+
+	t.Run("does not version projectors that declare none", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_truncating_projection_store()
+		tc.projector_versions_recorded(map[string]int{})
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_outdated_is_called()
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.rebuilt_projectors_are()
+		tc.projector_version_is("recorder", 0)
+	})
+
+	t.Run("keeps the old version when the projections cannot be truncated", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.projector_versions_recorded(map[string]int{"rune_view": 1})
+		tc.a_versioned_projector("rune_view", 2, "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_outdated_is_called()
+
+		// Then
+		tc.rebuild_has_error_containing("cannot truncate")
+		tc.projector_version_is("rune_view", 1)
+	})
+}
+
+// --- Fake Projector Version Store ---
+
+type memoryVersionStore struct {
+	mu       sync.Mutex
+	versions map[string]int
+}
+
+func (s *memoryVersionStore) GetProjectorVersion(_ context.Context, projectorName string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[projectorName], nil
+}
+
+func (s *memoryVersionStore) SetProjectorVersion(_ context.Context, projectorName string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[projectorName] = version
+	return nil
+}
+
+// --- Mock Truncating Projection Store ---
+
+type truncatingProjectionStore struct {
+	mockProjectionStore
+	truncated []string
+}
+
+func (s *truncatingProjectionStore) TruncateProjection(_ context.Context, projectionName string) error {
+	s.truncated = append(s.truncated, projectionName)
+	return nil
+}
+
+func (s *truncatingProjectionStore) TruncateRealmProjection(_ context.Context, realmID string, projectionName string) error {
+	s.truncated = append(s.truncated, realmID+"/"+projectionName)
+	return nil
+}
+
+// --- Mock Versioned Projector ---
+
+type versionedProjector struct {
+	*recordingProjector
+	version     int
+	projections []string
+}
+
+func (p *versionedProjector) Version() int {
+	return p.version
+}
+
+func (p *versionedProjector) Projections() []string {
+	return p.projections
+}
+
+// --- Given ---
+
+func (tc *catchUpTestContext) a_truncating_projection_store() {
+	tc.t.Helper()
+	tc.truncatingStore = &truncatingProjectionStore{}
+}
+
+func (tc *catchUpTestContext) projector_versions_recorded(versions map[string]int) {
+	tc.t.Helper()
+	tc.versions = &memoryVersionStore{versions: versions}
+}
+
+func (tc *catchUpTestContext) a_versioned_projector(name string, version int, projections ...string) {
+	tc.t.Helper()
+	recorder := &recordingProjector{name: name}
+	tc.recorders[name] = recorder
+	tc.projector = &versionedProjector{recordingProjector: recorder, version: version, projections: projections}
+}
+
+// --- When ---
+
+func (tc *catchUpTestContext) rebuild_outdated_is_called() {
+	tc.t.Helper()
+	tc.rebuilt, tc.rebuildErr = tc.engine.RebuildOutdated(context.Background())
+}
+
+// --- Then ---
+
+func (tc *catchUpTestContext) rebuild_has_no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.rebuildErr)
+}
+
+func (tc *catchUpTestContext) rebuild_has_error_containing(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.rebuildErr)
+	assert.Contains(tc.t, tc.rebuildErr.Error(), substr)
+}
+
+func (tc *catchUpTestContext) rebuilt_projectors_are(names ...string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, names, tc.rebuilt)
+}
+
+func (tc *catchUpTestContext) truncated_projections_are(names ...string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, names, tc.truncatingStore.truncated)
+}
+
+func (tc *catchUpTestContext) checkpoint_is(realmID, projectorName string, expected int64) {
+	tc.t.Helper()
+	pos, err := tc.configCheckpointStore.GetCheckpoint(context.Background(), realmID, projectorName)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, pos)
+}
+
+func (tc *catchUpTestContext) projector_version_is(name string, expected int) {
+	tc.t.Helper()
+	version, err := tc.versions.GetProjectorVersion(context.Background(), name)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, version)
+}
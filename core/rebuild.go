@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ProjectionOwner is implemented by projectors that name the projections
+// they write, so the engine can clear them before projecting into them
+// again.
+type ProjectionOwner interface {
+	Projector
+	// Projections names every projection the projector writes, in any
+	// realm.
+	Projections() []string
+}
+
+// RealmSpanningProjector is implemented by projectors that write entries
+// into realms other than the one an event was appended to, such as a
+// workload kept in the admin realm. Their entries in a realm cannot be
+// replayed from that realm's events alone, so Rebuild refuses them.
+type RealmSpanningProjector interface {
+	Projector
+	SpansRealms() bool
+}
+
+// ProjectionRebuilder is implemented by projection engines that can rebuild
+// a projection in one realm while the others carry on.
+type ProjectionRebuilder interface {
+	Rebuild(ctx context.Context, projectionName string, realmID string) error
+}
+
+// ErrNotRebuildable is returned by Rebuild for a projection it cannot
+// rebuild: one no registered projector owns, one written by a projector
+// with side effects, which replaying would repeat, or one written by a
+// RealmSpanningProjector.
+var ErrNotRebuildable = errors.New("projection cannot be rebuilt")
+
+// Rebuild deletes the realm's entries of the projections written by the
+// projector owning projectionName, which may also be the projector's own
+// name, and projects the realm's events into them again. Only that
+// projector's checkpoint in the realm is locked meanwhile, so other realms
+// and projectors keep catching up. A projector spanning realms has to be
+// rebuilt everywhere at once by bumping its version instead.
+//
+// With a transactor, the checkpoint, the deletes and the replay share one
+// transaction, so readers see the old entries until the new ones are
+// committed and a failed rebuild leaves them as they were. Without one, a
+// reader can meanwhile find the projections empty or partly rebuilt.
+func (e *projectionEngine) Rebuild(ctx context.Context, projectionName string, realmID string) error {
+	owner, err := e.realmOwner(projectionName, ErrNotRebuildable)
+	if err != nil {
+		return err
+	}
+	if !e.holdLease(ctx) {
+		return errors.New("catch-up runs on another instance")
+	}
+
+	lock := e.lock(realmID, owner.Name())
+	lock.Lock()
+	defer lock.Unlock()
+	if e.transactor == nil {
+		stores := UnitOfWork{EventStore: e.eventStore, ProjectionStore: e.projectionStore, CheckpointStore: e.checkpointStore}
+		return e.rebuild(ctx, stores, realmID, owner)
+	}
+
+	// The cache never saw the transaction's deletes and writes, so the
+	// projections are evicted whether or not it committed.
+	defer func() {
+		if invalidator, ok := e.projectionStore.(ProjectionInvalidator); ok {
+			for _, name := range owner.Projections() {
+				invalidator.InvalidateRealmProjection(realmID, name)
+			}
+		}
+	}()
+	return e.transactor.Transact(ctx, func(ctx context.Context, uow UnitOfWork) error {
+		return e.rebuild(ctx, uow, realmID, owner)
+	})
+}
+
+// rebuild resets owner's checkpoint in the realm, deletes the realm's
+// entries of its projections and replays the realm's events, all through
+// stores.
+func (e *projectionEngine) rebuild(ctx context.Context, stores UnitOfWork, realmID string, owner ProjectionOwner) error {
+	truncator, ok := stores.ProjectionStore.(ProjectionTruncator)
+	if !ok {
+		return errors.New("the projection store cannot truncate projections")
+	}
+	// The checkpoint goes back first, so a rebuild cut short leaves the
+	// projector to replay the realm rather than skip what was deleted.
+	if err := stores.CheckpointStore.SetCheckpoint(ctx, realmID, owner.Name(), 0); err != nil {
+		return fmt.Errorf("resetting checkpoint: %w", err)
+	}
+	for _, name := range owner.Projections() {
+		if err := truncator.TruncateRealmProjection(ctx, realmID, name); err != nil {
+			return fmt.Errorf("truncating %s: %w", name, err)
+		}
+	}
+	if _, err := e.project(ctx, stores, realmID, owner, nil); err != nil {
+		return fmt.Errorf("projecting %s: %w", realmID, err)
+	}
+	return nil
+}
+
+// realmOwner returns the projector owning projectionName if its entries in
+// one realm can be replaced on their own, and an error wrapping reason
+// otherwise.
+func (e *projectionEngine) realmOwner(projectionName string, reason error) (ProjectionOwner, error) {
+	owner := e.owner(projectionName)
+	if owner == nil {
+		return nil, fmt.Errorf("%w: no projector writes %q", reason, projectionName)
+	}
+	if hasSideEffects(owner) {
+		return nil, fmt.Errorf("%w: %q has side effects", reason, owner.Name())
+	}
+	if spansRealms(owner) {
+		return nil, fmt.Errorf("%w: %q writes into other realms", reason, owner.Name())
+	}
+	return owner, nil
+}
+
+// owner returns the registered projector named name or owning the
+// projection of that name, or nil if there is none.
+func (e *projectionEngine) owner(name string) ProjectionOwner {
+	for _, projector := range e.projectors {
+		if owner, ok := projector.(ProjectionOwner); ok && owner.Name() == name {
+			return owner
+		}
+	}
+	for _, projector := range e.projectors {
+		if owner, ok := projector.(ProjectionOwner); ok && slices.Contains(owner.Projections(), name) {
+			return owner
+		}
+	}
+	return nil
+}
+
+func spansRealms(p Projector) bool {
+	s, ok := p.(RealmSpanningProjector)
+	return ok && s.SpansRealms()
+}
+
+func (e *projectionEngine) truncator() (ProjectionTruncator, error) {
+	truncator, ok := e.projectionStore.(ProjectionTruncator)
+	if !ok {
+		return nil, errors.New("the projection store cannot truncate projections")
+	}
+	return truncator, nil
+}
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjectionEngine_Rebuild(t *testing.T) {
+	t.Run("rebuilds one realm and leaves the others alone", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1", "realm-2")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+			Event{EventType: "evt-2", GlobalPosition: 2, RealmID: "realm-1"},
+		)
+		tc.checkpoint("realm-1", "rune_view", 2)
+		tc.checkpoint("realm-2", "rune_view", 9)
+		tc.a_truncating_projection_store()
+		tc.a_projection_owner("rune_view", "rune_view", "rune_view_children")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view", "realm-1")
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.truncated_projections_are("realm-1/rune_view", "realm-1/rune_view_children")
+		tc.checkpoints_set("realm-1", "rune_view", []int64{0, 2})
+		tc.checkpoint_was_not_set("realm-2", "rune_view")
+		tc.catch_up_projector_handled_events("rune_view", []string{"evt-1", "evt-2"})
+	})
+
+	t.Run("finds the projector by a projection it writes", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_truncating_projection_store()
+		tc.a_projection_owner("rune_view", "rune_view", "rune_view_children")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view_children", "realm-1")
+
+		// Then
+		tc.rebuild_has_no_error()
+		tc.truncated_projections_are("realm-1/rune_view", "realm-1/rune_view_children")
+	})
+
+	t.Run("refuses a projection no projector owns", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_truncating_projection_store()
+		tc.a_catch_up_recording_projector("recorder")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("recorder", "realm-1")
+
+		// Then
+		tc.rebuild_is_refused()
+		tc.truncated_projections_are()
+	})
+
+	t.Run("refuses a projector with side effects", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_truncating_projection_store()
+		tc.a_projection_owner("notifier", "notifier")
+		tc.projector_has_side_effects()
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("notifier", "realm-1")
+
+		// Then
+		tc.rebuild_is_refused()
+		tc.truncated_projections_are()
+	})
+
+	t.Run("refuses a projector spanning realms", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_truncating_projection_store()
+		tc.a_projection_owner("workload", "workload")
+		tc.projector_spans_realms()
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("workload", "realm-1")
+
+		// Then
+		tc.rebuild_is_refused()
+		tc.truncated_projections_are()
+	})
+
+	t.Run("fails when the projection store cannot truncate", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 2)
+		tc.a_projection_owner("rune_view", "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view", "realm-1")
+
+		// Then
+		tc.rebuild_has_error_containing("cannot truncate")
+		tc.checkpoint_was_not_set("realm-1", "rune_view")
+	})
+
+	t.Run("fails while another node holds the lease", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.a_lease_store()
+		tc.lease_held_by("node-b")
+		tc.a_truncating_projection_store()
+		tc.a_projection_owner("rune_view", "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view", "realm-1")
+
+		// Then
+		tc.rebuild_has_error_containing("another instance")
+		tc.truncated_projections_are()
+	})
+
+	t.Run("rebuilds in one transaction with a transactor", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.realm_events("realm-1", 0,
+			Event{EventType: "evt-1", GlobalPosition: 1, RealmID: "realm-1"},
+		)
+		tc.checkpoint("realm-1", "rune_view", 1)
+		tc.a_truncating_projection_store()
+		tc.a_rebuild_transactor()
+		tc.a_projection_owner("rune_view", "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view", "realm-1")
+
+		// Then
+		tc.rebuild_has_no_error()
+		assert.Equal(t, []string{"realm-1/rune_view"}, tc.transactor.projections.truncated)
+		tc.truncated_projections_are()
+		tc.checkpoints_set("realm-1", "rune_view", []int64{0, 1})
+		tc.catch_up_projector_handled_events("rune_view", []string{"evt-1"})
+		assert.Equal(t, 1, tc.transactor.committed)
+	})
+
+	t.Run("rolls the rebuild back when replaying fails", func(t *testing.T) {
+		tc := newCatchUpTestContext(t)
+
+		// Given
+		tc.realms("realm-1")
+		tc.checkpoint("realm-1", "rune_view", 1)
+		tc.a_truncating_projection_store()
+		tc.a_rebuild_transactor()
+		tc.transaction_cannot_read_events()
+		tc.a_projection_owner("rune_view", "rune_view")
+		tc.catch_up_engine_is_created()
+		tc.register_catch_up_projector()
+
+		// When
+		tc.rebuild_is_called("rune_view", "realm-1")
+
+		// Then
+		tc.rebuild_has_error_containing("disk I/O error")
+		assert.Equal(t, 0, tc.transactor.committed)
+		assert.Equal(t, 1, tc.transactor.rolledBack)
+	})
+}
+
+// --- Mock Projectors ---
+
+type sideEffectOwner struct {
+	*versionedProjector
+}
+
+func (p *sideEffectOwner) HasSideEffects() bool {
+	return true
+}
+
+type realmSpanningOwner struct {
+	*versionedProjector
+}
+
+func (p *realmSpanningOwner) SpansRealms() bool {
+	return true
+}
+
+// --- Mock Transactor ---
+
+// rebuildTransactor hands units of work a projection store of their own, so
+// what a transaction truncated can be told from what was truncated outside
+// one, and counts the transactions committed and rolled back.
+type rebuildTransactor struct {
+	events      EventStore
+	projections *truncatingProjectionStore
+	checkpoints CheckpointStore
+	committed   int
+	rolledBack  int
+}
+
+func (t *rebuildTransactor) Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error {
+	err := fn(ctx, UnitOfWork{EventStore: t.events, ProjectionStore: t.projections, CheckpointStore: t.checkpoints})
+	if err != nil {
+		t.rolledBack++
+		return err
+	}
+	t.committed++
+	return nil
+}
+
+// unreadableEventStore fails every read of a realm's events.
+type unreadableEventStore struct {
+	*configurableEventStore
+}
+
+func (s *unreadableEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]Event, error) {
+	return nil, errors.New("disk I/O error")
+}
+
+// --- Given ---
+
+func (tc *catchUpTestContext) a_projection_owner(name string, projections ...string) {
+	tc.t.Helper()
+	tc.a_versioned_projector(name, 1, projections...)
+}
+
+func (tc *catchUpTestContext) a_rebuild_transactor() {
+	tc.t.Helper()
+	tc.transactor = &rebuildTransactor{
+		events:      tc.configEventStore,
+		projections: &truncatingProjectionStore{},
+		checkpoints: tc.configCheckpointStore,
+	}
+}
+
+func (tc *catchUpTestContext) transaction_cannot_read_events() {
+	tc.t.Helper()
+	tc.transactor.events = &unreadableEventStore{configurableEventStore: tc.configEventStore}
+}
+
+func (tc *catchUpTestContext) projector_has_side_effects() {
+	tc.t.Helper()
+	tc.projector = &sideEffectOwner{versionedProjector: tc.projector.(*versionedProjector)}
+}
+
+func (tc *catchUpTestContext) projector_spans_realms() {
+	tc.t.Helper()
+	tc.projector = &realmSpanningOwner{versionedProjector: tc.projector.(*versionedProjector)}
+}
+
+// --- When ---
+
+func (tc *catchUpTestContext) rebuild_is_called(projectionName, realmID string) {
+	tc.t.Helper()
+	tc.rebuildErr = tc.engine.Rebuild(context.Background(), projectionName, realmID)
+}
+
+// --- Then ---
+
+func (tc *catchUpTestContext) rebuild_is_refused() {
+	tc.t.Helper()
+	require.Error(tc.t, tc.rebuildErr)
+	assert.ErrorIs(tc.t, tc.rebuildErr, ErrNotRebuildable)
+}
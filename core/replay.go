@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Replay runs projectors over the event history into a sandbox projection
+// store, leaving the live read models untouched, so new projector code can
+// be tried against real history before it is deployed. Projectors with side
+// effects are skipped, and projector errors are collected rather than
+// logged.
+
+const (
+	// maxReplayErrors bounds the errors kept per projector.
+	maxReplayErrors = 20
+	// maxDiffExamples bounds the keys kept per difference.
+	maxDiffExamples = 10
+)
+
+// ReplayReport describes a replay into a sandbox.
+type ReplayReport struct {
+	Realms     []string          `json:"realms"`
+	Events     int               `json:"events"`
+	Projectors []ProjectorReport `json:"projectors"`
+	// Diffs compares the sandbox with the live store, by realm and
+	// projection. It is only filled in by ReplayCompareWith.
+	Diffs []ProjectionDiff `json:"diffs,omitempty"`
+}
+
+// Failed reports whether any projector returned an error.
+func (r *ReplayReport) Failed() bool {
+	for _, p := range r.Projectors {
+		if p.Failed > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectorReport counts the events a projector handled in a replay.
+type ProjectorReport struct {
+	Name    string `json:"name"`
+	Skipped bool   `json:"skipped,omitempty"` // it has side effects, so it was not run
+	Handled int    `json:"handled"`
+	Failed  int    `json:"failed"`
+	// Errors holds the first errors the projector returned.
+	Errors []ReplayError `json:"errors,omitempty"`
+}
+
+// ReplayError is an error a projector returned for an event.
+type ReplayError struct {
+	RealmID        string `json:"realm_id"`
+	GlobalPosition int64  `json:"global_position"`
+	EventType      string `json:"event_type"`
+	Error          string `json:"error"`
+}
+
+// ProjectionDiff compares the entries the sandbox wrote in one realm's
+// projection with the same keys in the live store.
+type ProjectionDiff struct {
+	RealmID        string `json:"realm_id"`
+	ProjectionName string `json:"projection"`
+	Same           int    `json:"same"`
+	Changed        int    `json:"changed"`
+	Added          int    `json:"added"`   // in the sandbox but not live
+	Removed        int    `json:"removed"` // deleted by the sandbox but still live
+	LiveEntries    int    `json:"live_entries"`
+	SandboxEntries int    `json:"sandbox_entries"`
+	// Examples holds the first keys that differ.
+	Examples []string `json:"examples,omitempty"`
+}
+
+// Differs reports whether the sandbox disagrees with the live store.
+func (d ProjectionDiff) Differs() bool {
+	return d.Changed > 0 || d.Added > 0 || d.Removed > 0 || d.LiveEntries != d.SandboxEntries
+}
+
+// ReplayOption configures Replay.
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	realmIDs  []string
+	live      ProjectionStore
+	batchSize int
+}
+
+// ReplayRealms limits the replay to the given realms.
+func ReplayRealms(realmIDs ...string) ReplayOption {
+	return func(c *replayConfig) {
+		c.realmIDs = append(c.realmIDs, realmIDs...)
+	}
+}
+
+// ReplayCompareWith compares every entry the sandbox wrote with the live
+// store and reports the differences.
+func ReplayCompareWith(live ProjectionStore) ReplayOption {
+	return func(c *replayConfig) {
+		c.live = live
+	}
+}
+
+// Replay feeds projectors every event of each realm, from the beginning,
+// writing to sandbox. The sandbox should start empty; events is only read.
+func Replay(ctx context.Context, events EventStore, sandbox ProjectionStore, projectors []Projector, opts ...ReplayOption) (*ReplayReport, error) {
+	cfg := &replayConfig{batchSize: 500}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	realmIDs := cfg.realmIDs
+	if len(realmIDs) == 0 {
+		var err error
+		if realmIDs, err = events.ListRealmIDs(ctx); err != nil {
+			return nil, fmt.Errorf("listing realms: %w", err)
+		}
+	}
+
+	report := &ReplayReport{Realms: realmIDs, Projectors: make([]ProjectorReport, len(projectors))}
+	for i, p := range projectors {
+		report.Projectors[i] = ProjectorReport{Name: p.Name(), Skipped: hasSideEffects(p)}
+	}
+	recorder := &keyRecorder{ProjectionStore: sandbox, keys: make(map[projectionEntry]bool)}
+	engine := &projectionEngine{batchSize: cfg.batchSize}
+
+	for _, realmID := range realmIDs {
+		var from int64
+		for {
+			page, err := engine.readBatch(ctx, events, realmID, from)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", realmID, err)
+			}
+			batch := page.Events
+			if len(batch) == 0 {
+				break
+			}
+			report.Events += len(batch)
+			buffer := newProjectionBuffer(recorder)
+			for i, p := range projectors {
+				if report.Projectors[i].Skipped {
+					continue
+				}
+				replayBatch(ctx, p, batch, buffer, &report.Projectors[i])
+			}
+			if err := buffer.flush(ctx); err != nil {
+				return nil, fmt.Errorf("writing sandbox projections: %w", err)
+			}
+			from = page.Next
+			if !page.More {
+				break
+			}
+		}
+	}
+
+	if cfg.live != nil {
+		diffs, err := diffProjections(ctx, cfg.live, sandbox, recorder.keys)
+		if err != nil {
+			return nil, err
+		}
+		report.Diffs = diffs
+	}
+	return report, nil
+}
+
+func replayBatch(ctx context.Context, p Projector, batch []Event, store ProjectionStore, report *ProjectorReport) {
+	for _, event := range batch {
+		report.Handled++
+		if err := p.Handle(ctx, event, store); err != nil {
+			report.Failed++
+			if len(report.Errors) < maxReplayErrors {
+				report.Errors = append(report.Errors, ReplayError{
+					RealmID:        event.RealmID,
+					GlobalPosition: event.GlobalPosition,
+					EventType:      event.EventType,
+					Error:          err.Error(),
+				})
+			}
+		}
+	}
+}
+
+type projectionEntry struct {
+	realmID, projectionName, key string
+}
+
+// keyRecorder notes which entries were written through it.
+type keyRecorder struct {
+	ProjectionStore
+	keys map[projectionEntry]bool
+}
+
+func (r *keyRecorder) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	r.keys[projectionEntry{realmID, projectionName, key}] = true
+	return r.ProjectionStore.Put(ctx, realmID, projectionName, key, value)
+}
+
+func (r *keyRecorder) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	r.keys[projectionEntry{realmID, projectionName, key}] = true
+	return r.ProjectionStore.Delete(ctx, realmID, projectionName, key)
+}
+
+func (r *keyRecorder) WriteBatch(ctx context.Context, writes []ProjectionWrite) error {
+	for _, w := range writes {
+		r.keys[projectionEntry{w.RealmID, w.ProjectionName, w.Key}] = true
+	}
+	return WriteProjectionBatch(ctx, r.ProjectionStore, writes)
+}
+
+// diffProjections compares the written keys between the stores, grouped by
+// realm and projection in name order.
+func diffProjections(ctx context.Context, live, sandbox ProjectionStore, keys map[projectionEntry]bool) ([]ProjectionDiff, error) {
+	entries := make([]projectionEntry, 0, len(keys))
+	for k := range keys {
+		entries = append(entries, k)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.realmID != b.realmID {
+			return a.realmID < b.realmID
+		}
+		if a.projectionName != b.projectionName {
+			return a.projectionName < b.projectionName
+		}
+		return a.key < b.key
+	})
+
+	var diffs []ProjectionDiff
+	for _, e := range entries {
+		if len(diffs) == 0 || diffs[len(diffs)-1].RealmID != e.realmID || diffs[len(diffs)-1].ProjectionName != e.projectionName {
+			d := ProjectionDiff{RealmID: e.realmID, ProjectionName: e.projectionName}
+			var err error
+			if d.LiveEntries, err = countEntries(ctx, live, e.realmID, e.projectionName); err != nil {
+				return nil, err
+			}
+			if d.SandboxEntries, err = countEntries(ctx, sandbox, e.realmID, e.projectionName); err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, d)
+		}
+		d := &diffs[len(diffs)-1]
+
+		liveValue, liveFound, err := getEntry(ctx, live, e)
+		if err != nil {
+			return nil, err
+		}
+		sandboxValue, sandboxFound, err := getEntry(ctx, sandbox, e)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case liveFound && sandboxFound && reflect.DeepEqual(liveValue, sandboxValue):
+			d.Same++
+			continue
+		case !liveFound && !sandboxFound:
+			continue
+		case liveFound && sandboxFound:
+			d.Changed++
+		case sandboxFound:
+			d.Added++
+		default:
+			d.Removed++
+		}
+		if len(d.Examples) < maxDiffExamples {
+			d.Examples = append(d.Examples, e.key)
+		}
+	}
+	return diffs, nil
+}
+
+func countEntries(ctx context.Context, store ProjectionStore, realmID, projectionName string) (int, error) {
+	values, err := store.List(ctx, realmID, projectionName)
+	if err != nil {
+		return 0, fmt.Errorf("listing %s/%s: %w", realmID, projectionName, err)
+	}
+	return len(values), nil
+}
+
+// getEntry decodes an entry generically, so entries compare equal whatever
+// order their fields were stored in.
+func getEntry(ctx context.Context, store ProjectionStore, e projectionEntry) (any, bool, error) {
+	var raw json.RawMessage
+	err := store.Get(ctx, e.realmID, e.projectionName, e.key, &raw)
+	var nfe *NotFoundError
+	if errors.As(err, &nfe) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s/%s/%s: %w", e.realmID, e.projectionName, e.key, err)
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestReplay(t *testing.T) {
+	t.Run("projects every realm's history into the sandbox", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 3)
+		tc.realm_has_events("realm-b", 2)
+		tc.projector(&countingProjector{name: "counter"})
+
+		// When
+		tc.replay()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 5, tc.report.Events)
+		assert.Equal(t, []string{"realm-a", "realm-b"}, tc.report.Realms)
+		tc.sandbox_count_is("realm-a", 3)
+		tc.sandbox_count_is("realm-b", 2)
+		assert.Empty(t, tc.live.data, "live store must not be written")
+	})
+
+	t.Run("replays only the requested realms", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 3)
+		tc.realm_has_events("realm-b", 2)
+		tc.projector(&countingProjector{name: "counter"})
+
+		// When
+		tc.replay(ReplayRealms("realm-b"))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 2, tc.report.Events)
+		tc.sandbox_count_is("realm-b", 2)
+		tc.sandbox_has_no_count("realm-a")
+	})
+
+	t.Run("collects projector errors instead of stopping", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 2)
+		tc.projector(&failingProjector{name: "broken"})
+		tc.projector(&countingProjector{name: "counter"})
+
+		// When
+		tc.replay()
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.report.Failed())
+		broken := tc.report.Projectors[0]
+		assert.Equal(t, 2, broken.Failed)
+		require.Len(t, broken.Errors, 2)
+		assert.Equal(t, "projector error", broken.Errors[0].Error)
+		assert.Equal(t, int64(1), broken.Errors[0].GlobalPosition)
+		tc.sandbox_count_is("realm-a", 2)
+	})
+
+	t.Run("skips projectors with side effects", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 1)
+		tc.projector(&sideEffectProjector{})
+
+		// When
+		tc.replay()
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.report.Projectors[0].Skipped)
+		assert.Zero(t, tc.report.Projectors[0].Handled)
+	})
+
+	t.Run("compares the sandbox with the live store", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 3)
+		tc.realm_has_events("realm-b", 2)
+		tc.live_count_is("realm-a", 3)
+		tc.live_count_is("realm-b", 7)
+		tc.projector(&countingProjector{name: "counter"})
+
+		// When
+		tc.replay(ReplayCompareWith(tc.live))
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.report.Diffs, 2)
+		same, changed := tc.report.Diffs[0], tc.report.Diffs[1]
+		assert.Equal(t, ProjectionDiff{RealmID: "realm-a", ProjectionName: "counts", Same: 1, LiveEntries: 1, SandboxEntries: 1}, same)
+		assert.False(t, same.Differs())
+		assert.Equal(t, 1, changed.Changed)
+		assert.Equal(t, []string{"events"}, changed.Examples)
+		assert.True(t, changed.Differs())
+	})
+
+	t.Run("reports entries the live store lacks as added", func(t *testing.T) {
+		tc := newReplayTestContext(t)
+
+		// Given
+		tc.realm_has_events("realm-a", 1)
+		tc.projector(&countingProjector{name: "counter"})
+
+		// When
+		tc.replay(ReplayCompareWith(tc.live))
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.report.Diffs, 1)
+		assert.Equal(t, 1, tc.report.Diffs[0].Added)
+		assert.Equal(t, 0, tc.report.Diffs[0].LiveEntries)
+	})
+}
+
+// --- Test Context ---
+
+type replayTestContext struct {
+	t *testing.T
+
+	events     *configurableEventStore
+	sandbox    *batchRecordingStore
+	live       *batchRecordingStore
+	projectors []Projector
+
+	report *ReplayReport
+	err    error
+}
+
+func newReplayTestContext(t *testing.T) *replayTestContext {
+	t.Helper()
+	return &replayTestContext{
+		t:       t,
+		events:  newConfigurableEventStore(),
+		sandbox: newBatchRecordingStore(nil),
+		live:    newBatchRecordingStore(nil),
+	}
+}
+
+// --- Given ---
+
+func (tc *replayTestContext) realm_has_events(realmID string, n int) {
+	tc.t.Helper()
+	tc.events.realmIDs = append(tc.events.realmIDs, realmID)
+	key := realmEventsKey{realmID: realmID}
+	for i := range n {
+		tc.events.events[key] = append(tc.events.events[key], Event{
+			RealmID:        realmID,
+			StreamID:       "rune-1",
+			Version:        i,
+			GlobalPosition: int64(i + 1),
+			EventType:      "RuneCreated",
+		})
+	}
+}
+
+func (tc *replayTestContext) projector(p Projector) {
+	tc.t.Helper()
+	tc.projectors = append(tc.projectors, p)
+}
+
+func (tc *replayTestContext) live_count_is(realmID string, count int) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.live.Put(context.Background(), realmID, "counts", "events", count))
+}
+
+// --- When ---
+
+func (tc *replayTestContext) replay(opts ...ReplayOption) {
+	tc.t.Helper()
+	tc.report, tc.err = Replay(context.Background(), tc.events, tc.sandbox, tc.projectors, opts...)
+}
+
+// --- Then ---
+
+func (tc *replayTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *replayTestContext) sandbox_count_is(realmID string, expected int) {
+	tc.t.Helper()
+	var count int
+	require.NoError(tc.t, tc.sandbox.Get(context.Background(), realmID, "counts", "events", &count))
+	assert.Equal(tc.t, expected, count)
+}
+
+func (tc *replayTestContext) sandbox_has_no_count(realmID string) {
+	tc.t.Helper()
+	var count int
+	var nfe *NotFoundError
+	assert.ErrorAs(tc.t, tc.sandbox.Get(context.Background(), realmID, "counts", "events", &count), &nfe)
+}
+
+// --- Test Doubles ---
+
+type sideEffectProjector struct{}
+
+func (p *sideEffectProjector) Name() string { return "notifier" }
+
+func (p *sideEffectProjector) Handle(_ context.Context, _ Event, _ ProjectionStore) error {
+	return errors.New("side effects must not run in a replay")
+}
+
+func (p *sideEffectProjector) HasSideEffects() bool { return true }
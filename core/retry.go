@@ -0,0 +1,38 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// retryBaseDelay is the longest wait before the second attempt of WithRetry.
+// Each later attempt may wait up to one more retryBaseDelay.
+var retryBaseDelay = 10 * time.Millisecond
+
+// WithRetry calls fn until it returns something other than a
+// ConcurrencyError, at most attempts times, and returns its last error.
+// Between attempts it waits a random time, so that commands which conflicted
+// with each other do not conflict again. fn must read the streams it appends
+// to and check the command against them again each time it is called.
+func WithRetry(ctx context.Context, attempts int, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		var concErr *ConcurrencyError
+		if !errors.As(err, &concErr) || attempt >= attempts {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		wait := time.Duration(rand.Int64N(int64(attempt) * int64(retryBaseDelay)))
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
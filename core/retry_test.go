@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestWithRetry(t *testing.T) {
+	t.Run("retries after a concurrency conflict", func(t *testing.T) {
+		tc := newRetryTestContext(t)
+
+		// Given
+		tc.fn_fails_with(&ConcurrencyError{StreamID: "s-1"}, &ConcurrencyError{StreamID: "s-1"})
+
+		// When
+		tc.with_retry_is_called(3)
+
+		// Then
+		assert.NoError(t, tc.err)
+		assert.Equal(t, 3, tc.calls)
+	})
+
+	t.Run("returns the conflict once the attempts run out", func(t *testing.T) {
+		tc := newRetryTestContext(t)
+
+		// Given
+		tc.fn_fails_with(&ConcurrencyError{StreamID: "s-1"}, &ConcurrencyError{StreamID: "s-1"})
+
+		// When
+		tc.with_retry_is_called(2)
+
+		// Then
+		var concErr *ConcurrencyError
+		assert.ErrorAs(t, tc.err, &concErr)
+		assert.Equal(t, 2, tc.calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		tc := newRetryTestContext(t)
+
+		// Given
+		tc.fn_fails_with(errors.New("boom"))
+
+		// When
+		tc.with_retry_is_called(3)
+
+		// Then
+		assert.EqualError(t, tc.err, "boom")
+		assert.Equal(t, 1, tc.calls)
+	})
+
+	t.Run("stops waiting when the context ends", func(t *testing.T) {
+		tc := newRetryTestContext(t)
+
+		// Given
+		tc.fn_fails_with(&ConcurrencyError{StreamID: "s-1"}, &ConcurrencyError{StreamID: "s-1"})
+		tc.context_is_cancelled()
+
+		// When
+		tc.with_retry_is_called(3)
+
+		// Then
+		assert.ErrorIs(t, tc.err, context.Canceled)
+		assert.Equal(t, 1, tc.calls)
+	})
+}
+
+// --- Test Context ---
+
+type retryTestContext struct {
+	t     *testing.T
+	ctx   context.Context
+	errs  []error
+	calls int
+	err   error
+}
+
+func newRetryTestContext(t *testing.T) *retryTestContext {
+	t.Helper()
+	return &retryTestContext{t: t, ctx: context.Background()}
+}
+
+// --- Given ---
+
+func (tc *retryTestContext) fn_fails_with(errs ...error) {
+	tc.t.Helper()
+	tc.errs = errs
+}
+
+func (tc *retryTestContext) context_is_cancelled() {
+	tc.t.Helper()
+	ctx, cancel := context.WithCancel(tc.ctx)
+	cancel()
+	tc.ctx = ctx
+}
+
+// --- When ---
+
+func (tc *retryTestContext) with_retry_is_called(attempts int) {
+	tc.t.Helper()
+	tc.err = WithRetry(tc.ctx, attempts, func() error {
+		tc.calls++
+		if tc.calls <= len(tc.errs) {
+			return tc.errs[tc.calls-1]
+		}
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package core
+
+import "context"
+
+// SearchDocument is the searchable text of a rune.
+type SearchDocument struct {
+	RealmID     string
+	ID          string
+	Title       string
+	Description string
+	Notes       []string
+	Status      string
+}
+
+// SearchQuery asks for the runes of a realm matching free text, best
+// matches first.
+type SearchQuery struct {
+	RealmID string
+	Text    string
+	// Statuses restricts hits to runes in one of these statuses (any when empty).
+	Statuses []string
+	Limit    int
+}
+
+// SearchHit is a rune matching a SearchQuery.
+type SearchHit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// SearchIndex is a full-text index of runes across realms. It is fed by a
+// projector, so it may lag the projections slightly and can be rebuilt from
+// the event history at any time.
+type SearchIndex interface {
+	// Index adds the document, replacing any earlier version of the rune.
+	Index(ctx context.Context, doc SearchDocument) error
+	// Delete removes a rune. Deleting a missing rune is not an error.
+	Delete(ctx context.Context, realmID, runeID string) error
+	// Search returns the realm's runes matching the query.
+	Search(ctx context.Context, query SearchQuery) ([]SearchHit, error)
+	// Count returns the number of indexed runes across all realms.
+	Count(ctx context.Context) (int, error)
+	Close() error
+}
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Snapshot is an aggregate's state as of a version of its stream, so the
+// aggregate can be rebuilt from the snapshot and the events after it instead
+// of from the whole stream.
+type Snapshot struct {
+	RealmID  string
+	StreamID string
+	Version  int
+	State    json.RawMessage
+}
+
+// SnapshotStore is implemented by event stores that keep snapshots next to
+// the events, so a snapshot written while handling a command is committed or
+// rolled back with the command's events.
+type SnapshotStore interface {
+	// GetSnapshot returns the latest snapshot of a stream, or a
+	// NotFoundError when there is none.
+	GetSnapshot(ctx context.Context, realmID string, streamID string) (Snapshot, error)
+	// SaveSnapshot replaces the stream's snapshot.
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 type EventStore interface {
@@ -12,6 +13,168 @@ type EventStore interface {
 	ListRealmIDs(ctx context.Context) ([]string, error)
 }
 
+// BatchEventReader is implemented by event stores that can read a realm's
+// feed in bounded chunks, letting catch-up run in constant memory.
+type BatchEventReader interface {
+	ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]Event, error)
+}
+
+// EventPage is a page of a realm's feed.
+type EventPage struct {
+	Events []Event
+	// Next is the cursor the following page is read from: the position of
+	// the page's last event, or the cursor it was read from if it is empty.
+	Next int64
+	// More reports whether the following page may hold events.
+	More bool
+}
+
+// ReadPage reads up to limit events of a realm's feed after the cursor
+// from, or the rest of the feed when limit is zero or less. Stores that
+// implement BatchEventReader read just the page; others read the rest of the
+// feed and trim it, so only the former keep memory bounded.
+func ReadPage(ctx context.Context, store EventStore, realmID string, from int64, limit int) (EventPage, error) {
+	var events []Event
+	var err error
+	more := false
+	if reader, ok := store.(BatchEventReader); ok && limit > 0 {
+		events, err = reader.ReadAllBatch(ctx, realmID, from, limit)
+		more = len(events) == limit
+	} else {
+		events, err = store.ReadAll(ctx, realmID, from)
+		if limit > 0 && len(events) > limit {
+			events, more = events[:limit], true
+		}
+	}
+	if err != nil {
+		return EventPage{}, err
+	}
+	page := EventPage{Events: events, Next: from, More: more}
+	if len(events) > 0 {
+		page.Next = events[len(events)-1].GlobalPosition
+	}
+	return page, nil
+}
+
+// Direction is the order a stream is read in.
+type Direction int
+
+const (
+	// Forward reads a stream in version order.
+	Forward Direction = iota
+	// Backward reads a stream in reverse version order, latest first.
+	Backward
+)
+
+// StreamPageReader is implemented by event stores that can read a bounded
+// window of a stream without loading the rest of it.
+type StreamPageReader interface {
+	// ReadStreamPage returns up to limit events of the stream, or all of
+	// them when limit is zero or less. Reading forward it starts at version
+	// from; reading backward it starts at version from, or at the last event
+	// when from is zero or less.
+	ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir Direction) ([]Event, error)
+}
+
+// StreamPage is a window of a stream.
+type StreamPage struct {
+	Events []Event
+	// Next is the version the following page in the same direction starts
+	// at, or the version the page was read from if it is empty.
+	Next int
+	// More reports whether the following page may hold events.
+	More bool
+}
+
+// ReadStreamPage reads up to limit events of a stream from version from,
+// forward or backward as ReadStreamPage of StreamPageReader does. Stores
+// that implement StreamPageReader read just the page; others read the
+// stream and trim it.
+func ReadStreamPage(ctx context.Context, store EventStore, realmID string, streamID string, from int, limit int, dir Direction) (StreamPage, error) {
+	var events []Event
+	var err error
+	if reader, ok := store.(StreamPageReader); ok {
+		events, err = reader.ReadStreamPage(ctx, realmID, streamID, from, limit, dir)
+	} else if dir == Forward {
+		events, err = store.ReadStream(ctx, realmID, streamID, from)
+		if limit > 0 && len(events) > limit {
+			events = events[:limit]
+		}
+	} else {
+		var stream []Event
+		stream, err = store.ReadStream(ctx, realmID, streamID, 0)
+		events = make([]Event, 0)
+		for i := len(stream) - 1; i >= 0 && (limit <= 0 || len(events) < limit); i-- {
+			if from <= 0 || stream[i].Version <= from {
+				events = append(events, stream[i])
+			}
+		}
+	}
+	if err != nil {
+		return StreamPage{}, err
+	}
+
+	page := StreamPage{Events: events, Next: from}
+	if len(events) == 0 {
+		return page, nil
+	}
+	last := events[len(events)-1].Version
+	full := limit > 0 && len(events) == limit
+	if dir == Forward {
+		page.Next, page.More = last+1, full
+	} else {
+		page.Next, page.More = last-1, full && last > 1
+	}
+	return page, nil
+}
+
+// EventSubscriber is implemented by event stores that can push events to
+// readers as they are appended, instead of readers polling for them.
+type EventSubscriber interface {
+	// Subscribe returns a channel of the events after fromPosition across
+	// all realms, in global position order, starting with those already
+	// stored. The channel is closed when ctx ends.
+	Subscribe(ctx context.Context, fromPosition int64) (<-chan Event, error)
+}
+
+// EventArchiver is implemented by event stores that can move finished
+// streams out of the event feed into cold storage. ReadAll leaves archived
+// events out, so their projections are kept as they are; reading or
+// appending to an archived stream brings its events back.
+type EventArchiver interface {
+	// ArchiveStreams archives every stream whose last event is of type
+	// lastEventType and was appended before the given time, and returns
+	// how many it archived.
+	ArchiveStreams(ctx context.Context, lastEventType string, before time.Time) (int, error)
+}
+
+// EventTruncator is implemented by event stores that can delete the
+// history of finished streams for good. A truncated stream keeps its last
+// event, so its version carries on and its ID stays taken, and a snapshot
+// taken at that event, so its state can still be rebuilt. Callers that load
+// the streams again save that snapshot first.
+type EventTruncator interface {
+	// TruncatableStreams returns the IDs of the streams TruncateStreams
+	// would truncate given the same arguments.
+	TruncatableStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) ([]string, error)
+	// TruncateStreams deletes every event but the last of each of the
+	// realm's streams whose last event is of type lastEventType and was
+	// appended before the given time, archived events included, along with
+	// their snapshots taken before the last event. It returns how many
+	// streams it truncated.
+	TruncateStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) (int, error)
+}
+
+// EventRedactor is implemented by event stores that can rewrite stored
+// events, which erasure requests need and nothing else should use.
+type EventRedactor interface {
+	// RedactStream replaces the data of the stream's stored events with
+	// that of the given events of the same version, clears their metadata,
+	// and drops the stream's snapshot. Events of other versions are left
+	// as they are.
+	RedactStream(ctx context.Context, realmID string, streamID string, redacted []Event) error
+}
+
 type ProjectionStore interface {
 	Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error
 	List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error)
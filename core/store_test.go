@@ -56,6 +56,94 @@ func TestEventStore(t *testing.T) {
 	})
 }
 
+func TestReadPage(t *testing.T) {
+	t.Run("trims a full read for stores without batched reads", func(t *testing.T) {
+		// Given
+		store := &keyedEventStore{}
+		for range 3 {
+			_, err := store.Append(context.Background(), "realm-1", "s-1", 0, []EventData{{EventType: "Noted", Data: json.RawMessage(`{}`)}})
+			assert.NoError(t, err)
+		}
+
+		// When
+		page, err := ReadPage(context.Background(), store, "realm-1", 0, 2)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Len(t, page.Events, 2)
+		assert.Equal(t, int64(2), page.Next)
+		assert.True(t, page.More)
+	})
+
+	t.Run("keeps the cursor for an empty page", func(t *testing.T) {
+		// When
+		page, err := ReadPage(context.Background(), &keyedEventStore{}, "realm-1", 7, 2)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, page.Events)
+		assert.Equal(t, int64(7), page.Next)
+		assert.False(t, page.More)
+	})
+}
+
+func TestReadStreamPage(t *testing.T) {
+	t.Run("pages backward from the last event for stores without paged reads", func(t *testing.T) {
+		// Given
+		store := &keyedEventStore{}
+		_, err := store.Append(context.Background(), "realm-1", "s-1", 0, []EventData{
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+		})
+		assert.NoError(t, err)
+
+		// When
+		first, err := ReadStreamPage(context.Background(), store, "realm-1", "s-1", 0, 2, Backward)
+		assert.NoError(t, err)
+		second, err := ReadStreamPage(context.Background(), store, "realm-1", "s-1", first.Next, 2, Backward)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 2}, streamVersions(first.Events))
+		assert.Equal(t, 1, first.Next)
+		assert.True(t, first.More)
+		assert.Equal(t, []int{1}, streamVersions(second.Events))
+		assert.False(t, second.More)
+	})
+
+	t.Run("trims a forward read for stores without paged reads", func(t *testing.T) {
+		// Given
+		store := &keyedEventStore{}
+		_, err := store.Append(context.Background(), "realm-1", "s-1", 0, []EventData{
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+			{EventType: "Noted", Data: json.RawMessage(`{}`)},
+		})
+		assert.NoError(t, err)
+
+		// When
+		page, err := ReadStreamPage(context.Background(), store, "realm-1", "s-1", 1, 2, Forward)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, streamVersions(page.Events))
+		assert.Equal(t, 3, page.Next)
+		assert.True(t, page.More)
+	})
+
+	t.Run("keeps the version for an empty page", func(t *testing.T) {
+		// When
+		page, err := ReadStreamPage(context.Background(), &keyedEventStore{}, "realm-1", "s-1", 4, 2, Forward)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, page.Events)
+		assert.Equal(t, 4, page.Next)
+		assert.False(t, page.More)
+	})
+}
+
 func TestProjectionStore(t *testing.T) {
 	t.Run("Get accepts context, realmID, projectionName, key, and dest", func(t *testing.T) {
 		tc := newStoreTestContext(t)
@@ -322,3 +410,11 @@ func (m *mockCheckpointStore) GetCheckpoint(_ context.Context, _ string, _ strin
 func (m *mockCheckpointStore) SetCheckpoint(_ context.Context, _ string, _ string, _ int64) error {
 	return nil
 }
+
+func streamVersions(events []Event) []int {
+	versions := make([]int, len(events))
+	for i, evt := range events {
+		versions[i] = evt.Version
+	}
+	return versions
+}
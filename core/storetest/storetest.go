@@ -0,0 +1,853 @@
+// Package storetest is a conformance suite for implementations of the core
+// stores. A provider runs it from its own tests, passing a function that
+// returns a fresh, empty store for each case.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// EventStore checks the behaviour every core.EventStore must have. Stores
+// that implement core.BatchEventReader, core.StreamPageReader,
+// core.EventSubscriber, core.SnapshotStore or core.DataKeyStore are checked
+// for that as well.
+func EventStore(t *testing.T, newStore func(t *testing.T) core.EventStore) {
+	t.Run("appends to a new stream at version 0", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		events, err := store.Append(context.Background(), "realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{"id": "1"}, Metadata: map[string]string{"actor": "a"}},
+			{EventType: "Updated", Data: map[string]string{"id": "1"}},
+		})
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		for i, evt := range events {
+			assert.Equal(t, "realm-1", evt.RealmID)
+			assert.Equal(t, "stream-1", evt.StreamID)
+			assert.Equal(t, i+1, evt.Version)
+			assert.Positive(t, evt.GlobalPosition)
+		}
+		assert.Less(t, events[0].GlobalPosition, events[1].GlobalPosition)
+		assert.Equal(t, "Created", events[0].EventType)
+		assert.JSONEq(t, `{"id":"1"}`, string(events[0].Data))
+		assert.JSONEq(t, `{"actor":"a"}`, string(events[0].Metadata))
+		assert.Nil(t, events[1].Metadata)
+	})
+
+	t.Run("stamps events with the context's clock", func(t *testing.T) {
+		store := newStore(t)
+		now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		ctx := core.ContextWithClock(context.Background(), core.FixedClock(now))
+
+		// When
+		_, err := store.Append(ctx, "realm-1", "stream-1", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+
+		// Then
+		require.NoError(t, err)
+		events, err := store.ReadStream(ctx, "realm-1", "stream-1", 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.True(t, now.Equal(events[0].Timestamp))
+	})
+
+	t.Run("returns ConcurrencyError for the wrong expected version", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 2)
+
+		// When
+		_, err := store.Append(context.Background(), "realm-1", "stream-1", 1, []core.EventData{{EventType: "Updated", Data: map[string]string{}}})
+
+		// Then
+		var concErr *core.ConcurrencyError
+		require.ErrorAs(t, err, &concErr)
+		assert.Equal(t, "stream-1", concErr.StreamID)
+		assert.Equal(t, 1, concErr.ExpectedVersion)
+		events, err := store.ReadStream(context.Background(), "realm-1", "stream-1", 0)
+		require.NoError(t, err)
+		assert.Len(t, events, 2, "nothing was appended")
+	})
+
+	t.Run("does not append a batch again under the same idempotency key", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+
+		// Given
+		first, err := store.Append(ctx, "realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}, IdempotencyKey: "key-1"},
+			{EventType: "Updated", Data: map[string]string{}},
+		})
+		require.NoError(t, err)
+
+		// When
+		again, err := store.Append(ctx, "realm-1", "stream-1", 2, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}, IdempotencyKey: "key-1"},
+			{EventType: "Updated", Data: map[string]string{}},
+		})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+		events, err := store.ReadStream(ctx, "realm-1", "stream-1", 0)
+		require.NoError(t, err)
+		assert.Len(t, events, 2, "nothing was appended")
+	})
+
+	t.Run("scopes idempotency keys to the stream", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+
+		// Given
+		_, err := store.Append(ctx, "realm-1", "stream-1", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}, IdempotencyKey: "key-1"}})
+		require.NoError(t, err)
+
+		// When
+		_, err = store.Append(ctx, "realm-1", "stream-2", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}, IdempotencyKey: "key-1"}})
+
+		// Then
+		require.NoError(t, err)
+		events, err := store.ReadStream(ctx, "realm-1", "stream-2", 0)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("versions streams independently", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 2)
+
+		// When
+		events, err := store.Append(context.Background(), "realm-1", "stream-2", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 1, events[0].Version)
+	})
+
+	t.Run("lets one of two concurrent appends to a stream succeed", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = store.Append(context.Background(), "realm-1", "stream-1", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+			}()
+		}
+		wg.Wait()
+
+		// Then
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			var concErr *core.ConcurrencyError
+			assert.ErrorAs(t, err, &concErr)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("reads a stream in version order from a version", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 3)
+
+		// When
+		events, err := store.ReadStream(context.Background(), "realm-1", "stream-1", 2)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, 2, events[0].Version)
+		assert.Equal(t, 3, events[1].Version)
+	})
+
+	t.Run("reads a window of a stream forward and backward", func(t *testing.T) {
+		store := newStore(t)
+		reader, ok := store.(core.StreamPageReader)
+		if !ok {
+			t.Skip("store does not read streams in pages")
+		}
+		ctx := context.Background()
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 5)
+		appendEvents(t, store, "realm-1", "stream-2", 1)
+
+		// When
+		forward, err := reader.ReadStreamPage(ctx, "realm-1", "stream-1", 2, 2, core.Forward)
+		require.NoError(t, err)
+		backward, err := reader.ReadStreamPage(ctx, "realm-1", "stream-1", 4, 3, core.Backward)
+		require.NoError(t, err)
+		latest, err := reader.ReadStreamPage(ctx, "realm-1", "stream-1", 0, 2, core.Backward)
+		require.NoError(t, err)
+		rest, err := reader.ReadStreamPage(ctx, "realm-1", "stream-1", 4, 0, core.Forward)
+		require.NoError(t, err)
+		missing, err := reader.ReadStreamPage(ctx, "realm-1", "missing", 0, 2, core.Backward)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, []int{2, 3}, versions(forward))
+		assert.Equal(t, []int{4, 3, 2}, versions(backward))
+		assert.Equal(t, []int{5, 4}, versions(latest))
+		assert.Equal(t, []int{4, 5}, versions(rest))
+		assert.NotNil(t, missing)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("reads an unknown stream as an empty slice", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		events, err := store.ReadStream(context.Background(), "realm-1", "missing", 0)
+
+		// Then
+		require.NoError(t, err)
+		assert.NotNil(t, events)
+		assert.Empty(t, events)
+	})
+
+	t.Run("reads a realm's events after a global position", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		first := appendEvents(t, store, "realm-1", "stream-1", 2)
+		appendEvents(t, store, "realm-2", "stream-1", 1)
+		appendEvents(t, store, "realm-1", "stream-2", 1)
+
+		// When
+		events, err := store.ReadAll(context.Background(), "realm-1", first[0].GlobalPosition)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "stream-1", events[0].StreamID)
+		assert.Equal(t, "stream-2", events[1].StreamID)
+		for _, evt := range events {
+			assert.Equal(t, "realm-1", evt.RealmID)
+			assert.Greater(t, evt.GlobalPosition, first[0].GlobalPosition)
+		}
+		assert.Less(t, events[0].GlobalPosition, events[1].GlobalPosition)
+	})
+
+	t.Run("reads an empty slice past the last event", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appended := appendEvents(t, store, "realm-1", "stream-1", 1)
+
+		// When
+		events, err := store.ReadAll(context.Background(), "realm-1", appended[0].GlobalPosition)
+
+		// Then
+		require.NoError(t, err)
+		assert.NotNil(t, events)
+		assert.Empty(t, events)
+	})
+
+	t.Run("lists the realms with events", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 2)
+		appendEvents(t, store, "realm-2", "stream-1", 1)
+
+		// When
+		realmIDs, err := store.ListRealmIDs(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"realm-1", "realm-2"}, realmIDs)
+	})
+
+	t.Run("reads at most limit events in a batch", func(t *testing.T) {
+		store := newStore(t)
+		batch, ok := store.(core.BatchEventReader)
+		if !ok {
+			t.Skip("store does not read in batches")
+		}
+
+		// Given
+		appended := appendEvents(t, store, "realm-1", "stream-1", 4)
+
+		// When
+		events, err := batch.ReadAllBatch(context.Background(), "realm-1", appended[0].GlobalPosition, 2)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, 2, events[0].Version)
+		assert.Equal(t, 3, events[1].Version)
+	})
+
+	t.Run("pages through a realm's feed with the returned cursor", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		appendEvents(t, store, "realm-1", "stream-1", 3)
+		appendEvents(t, store, "realm-2", "stream-1", 1)
+		appendEvents(t, store, "realm-1", "stream-2", 2)
+
+		// When
+		var pages [][]int
+		var from int64
+		for {
+			page, err := core.ReadPage(context.Background(), store, "realm-1", from, 2)
+			require.NoError(t, err)
+			var versions []int
+			for _, evt := range page.Events {
+				versions = append(versions, evt.Version)
+			}
+			pages = append(pages, versions)
+			if !page.More {
+				break
+			}
+			from = page.Next
+		}
+
+		// Then
+		assert.Equal(t, [][]int{{1, 2}, {3, 1}, {2}}, pages)
+	})
+
+	t.Run("pushes stored and newly appended events to a subscription", func(t *testing.T) {
+		store := newStore(t)
+		subscriber, ok := store.(core.EventSubscriber)
+		if !ok {
+			t.Skip("store does not push events")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Given
+		stored := appendEvents(t, store, "realm-1", "stream-1", 2)
+
+		// When
+		events, err := subscriber.Subscribe(ctx, stored[0].GlobalPosition)
+		require.NoError(t, err)
+		appendEvents(t, store, "realm-2", "stream-1", 1)
+
+		// Then
+		var got []core.Event
+		for len(got) < 2 {
+			select {
+			case evt := <-events:
+				got = append(got, evt)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("received %d of 2 events", len(got))
+			}
+		}
+		assert.Equal(t, stored[1].GlobalPosition, got[0].GlobalPosition)
+		assert.Equal(t, "realm-2", got[1].RealmID)
+		assert.Greater(t, got[1].GlobalPosition, got[0].GlobalPosition)
+
+		cancel()
+		for range events {
+		}
+	})
+
+	t.Run("keeps the latest snapshot of a stream", func(t *testing.T) {
+		store := newStore(t)
+		snapshots, ok := store.(core.SnapshotStore)
+		if !ok {
+			t.Skip("store does not keep snapshots")
+		}
+
+		// Given
+		_, err := snapshots.GetSnapshot(context.Background(), "realm-1", "stream-1")
+		var nfe *core.NotFoundError
+		require.ErrorAs(t, err, &nfe)
+		require.NoError(t, snapshots.SaveSnapshot(context.Background(), core.Snapshot{RealmID: "realm-1", StreamID: "stream-1", Version: 2, State: json.RawMessage(`{"n":2}`)}))
+		require.NoError(t, snapshots.SaveSnapshot(context.Background(), core.Snapshot{RealmID: "realm-1", StreamID: "stream-1", Version: 4, State: json.RawMessage(`{"n":4}`)}))
+		require.NoError(t, snapshots.SaveSnapshot(context.Background(), core.Snapshot{RealmID: "realm-2", StreamID: "stream-1", Version: 1, State: json.RawMessage(`{"n":1}`)}))
+
+		// When
+		snapshot, err := snapshots.GetSnapshot(context.Background(), "realm-1", "stream-1")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 4, snapshot.Version)
+		assert.JSONEq(t, `{"n":4}`, string(snapshot.State))
+	})
+
+	t.Run("keeps the first data key added for a realm", func(t *testing.T) {
+		store := newStore(t)
+		keys, ok := store.(core.DataKeyStore)
+		if !ok {
+			t.Skip("store does not keep data keys")
+		}
+
+		// Given
+		_, err := keys.GetDataKey(context.Background(), "realm-1")
+		var nfe *core.NotFoundError
+		require.ErrorAs(t, err, &nfe)
+		require.NoError(t, keys.AddDataKey(context.Background(), "realm-1", []byte("first")))
+		require.NoError(t, keys.AddDataKey(context.Background(), "realm-1", []byte("second")))
+		require.NoError(t, keys.AddDataKey(context.Background(), "realm-2", []byte("other")))
+
+		// When
+		wrapped, err := keys.GetDataKey(context.Background(), "realm-1")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []byte("first"), wrapped)
+	})
+
+	t.Run("redacts a stream's events in place", func(t *testing.T) {
+		store := newStore(t)
+		redactor, ok := store.(core.EventRedactor)
+		if !ok {
+			t.Skip("store cannot redact events")
+		}
+		ctx := context.Background()
+
+		// Given
+		_, err := store.Append(ctx, "realm-1", "s-1", 0, []core.EventData{
+			{EventType: "Noted", Data: map[string]string{"text": "secret"}, Metadata: map[string]string{"by": "jane"}},
+			{EventType: "Noted", Data: map[string]string{"text": "public"}},
+		})
+		require.NoError(t, err)
+		_, err = store.Append(ctx, "realm-1", "s-2", 0, []core.EventData{
+			{EventType: "Noted", Data: map[string]string{"text": "secret"}},
+		})
+		require.NoError(t, err)
+		stored, err := store.ReadStream(ctx, "realm-1", "s-1", 0)
+		require.NoError(t, err)
+		stored[0].Data = []byte(`{}`)
+		snapshots, keepsSnapshots := store.(core.SnapshotStore)
+		if keepsSnapshots {
+			require.NoError(t, snapshots.SaveSnapshot(ctx, core.Snapshot{RealmID: "realm-1", StreamID: "s-1", Version: 2, State: json.RawMessage(`{"text":"secret"}`)}))
+		}
+
+		// When
+		err = redactor.RedactStream(ctx, "realm-1", "s-1", stored[:1])
+
+		// Then
+		require.NoError(t, err)
+		events, err := store.ReadAll(ctx, "realm-1", 0)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.JSONEq(t, `{}`, string(events[0].Data))
+		assert.Empty(t, events[0].Metadata)
+		assert.Equal(t, stored[0].GlobalPosition, events[0].GlobalPosition)
+		assert.JSONEq(t, `{"text":"public"}`, string(events[1].Data))
+		assert.JSONEq(t, `{"text":"secret"}`, string(events[2].Data))
+		if keepsSnapshots {
+			_, err := snapshots.GetSnapshot(ctx, "realm-1", "s-1")
+			var nfe *core.NotFoundError
+			assert.ErrorAs(t, err, &nfe)
+		}
+	})
+
+	t.Run("truncates finished streams to their last event", func(t *testing.T) {
+		store := newStore(t)
+		truncator, ok := store.(core.EventTruncator)
+		if !ok {
+			t.Skip("store cannot truncate streams")
+		}
+		ctx := context.Background()
+		past := core.ContextWithClock(ctx, core.FixedClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		// Given
+		_, err := store.Append(past, "realm-1", "s-1", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}},
+			{EventType: "Closed", Data: map[string]string{}},
+		})
+		require.NoError(t, err)
+		_, err = store.Append(past, "realm-1", "s-2", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}},
+		})
+		require.NoError(t, err)
+		_, err = store.Append(past, "realm-2", "s-1", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}},
+			{EventType: "Closed", Data: map[string]string{}},
+		})
+		require.NoError(t, err)
+		appendEvents(t, store, "realm-1", "s-3", 1)
+		_, err = store.Append(ctx, "realm-1", "s-3", 1, []core.EventData{
+			{EventType: "Closed", Data: map[string]string{}},
+		})
+		require.NoError(t, err)
+
+		// When
+		streamIDs, err := truncator.TruncatableStreams(ctx, "realm-1", "Closed", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		n, err := truncator.TruncateStreams(ctx, "realm-1", "Closed", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"s-1"}, streamIDs)
+		assert.Equal(t, 1, n)
+		events, err := store.ReadStream(ctx, "realm-1", "s-1", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, versions(events))
+		for _, untouched := range []struct {
+			realmID, streamID string
+			versions          []int
+		}{
+			{"realm-1", "s-2", []int{1}},    // last event of another type
+			{"realm-1", "s-3", []int{1, 2}}, // closed too recently
+			{"realm-2", "s-1", []int{1, 2}}, // another realm
+		} {
+			events, err := store.ReadStream(ctx, untouched.realmID, untouched.streamID, 0)
+			require.NoError(t, err)
+			assert.Equal(t, untouched.versions, versions(events), untouched.realmID+"/"+untouched.streamID)
+		}
+
+		// When appending to the truncated stream
+		_, err = store.Append(ctx, "realm-1", "s-1", 2, []core.EventData{
+			{EventType: "Reopened", Data: map[string]string{}},
+		})
+
+		// Then its version carries on
+		require.NoError(t, err)
+		n, err = truncator.TruncateStreams(ctx, "realm-1", "Closed", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
+
+// ProjectionStore checks the behaviour every core.ProjectionStore must
+// have. Stores that implement core.BatchProjectionStore are checked for
+// that as well.
+func ProjectionStore(t *testing.T, newStore func(t *testing.T) core.ProjectionStore) {
+	type entry struct {
+		Name  string            `json:"name"`
+		Tags  []string          `json:"tags"`
+		Attrs map[string]string `json:"attrs"`
+	}
+
+	t.Run("returns NotFoundError for a missing key", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		var got entry
+		err := store.Get(context.Background(), "realm-1", "things", "missing", &got)
+
+		// Then
+		var nfe *core.NotFoundError
+		require.ErrorAs(t, err, &nfe)
+		assert.Equal(t, "things", nfe.Entity)
+		assert.Equal(t, "missing", nfe.ID)
+	})
+
+	t.Run("round-trips a value", func(t *testing.T) {
+		store := newStore(t)
+		want := entry{Name: "a", Tags: []string{"x", "y"}, Attrs: map[string]string{"k": "v"}}
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", want))
+
+		// When
+		var got entry
+		err := store.Get(context.Background(), "realm-1", "things", "a", &got)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("overwrites an existing value", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "old"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "new"}))
+
+		// When
+		var got entry
+		err := store.Get(context.Background(), "realm-1", "things", "a", &got)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "new", got.Name)
+	})
+
+	t.Run("keeps realms and projections apart", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "one"}))
+		require.NoError(t, store.Put(context.Background(), "realm-2", "things", "a", entry{Name: "two"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "others", "a", entry{Name: "other"}))
+
+		// When
+		list, err := store.List(context.Background(), "realm-1", "things")
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.JSONEq(t, `{"name":"one","tags":null,"attrs":null}`, string(list[0]))
+	})
+
+	t.Run("lists nothing as an empty slice", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		list, err := store.List(context.Background(), "realm-1", "things")
+
+		// Then
+		require.NoError(t, err)
+		assert.NotNil(t, list)
+		assert.Empty(t, list)
+	})
+
+	t.Run("deletes an entry", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "a"}))
+
+		// When
+		err := store.Delete(context.Background(), "realm-1", "things", "a")
+
+		// Then
+		require.NoError(t, err)
+		var got entry
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, store.Get(context.Background(), "realm-1", "things", "a", &got), &nfe)
+	})
+
+	t.Run("deletes a missing entry without error", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		err := store.Delete(context.Background(), "realm-1", "things", "missing")
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("applies a batch of writes in order", func(t *testing.T) {
+		store := newStore(t)
+		batch, ok := store.(core.BatchProjectionStore)
+		if !ok {
+			t.Skip("store does not write in batches")
+		}
+
+		// When
+		err := batch.WriteBatch(context.Background(), []core.ProjectionWrite{
+			{RealmID: "realm-1", ProjectionName: "things", Key: "a", Value: json.RawMessage(`{"name":"first"}`)},
+			{RealmID: "realm-1", ProjectionName: "things", Key: "b", Value: json.RawMessage(`{"name":"b"}`)},
+			{RealmID: "realm-1", ProjectionName: "things", Key: "a", Value: json.RawMessage(`{"name":"second"}`)},
+			{RealmID: "realm-1", ProjectionName: "things", Key: "b", Delete: true},
+		})
+
+		// Then
+		require.NoError(t, err)
+		var got entry
+		require.NoError(t, store.Get(context.Background(), "realm-1", "things", "a", &got))
+		assert.Equal(t, "second", got.Name)
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, store.Get(context.Background(), "realm-1", "things", "b", &got), &nfe)
+	})
+
+	t.Run("dumps a realm's rows in projection and key order", func(t *testing.T) {
+		store := newStore(t)
+		dumper, ok := store.(core.ProjectionDumper)
+		if !ok {
+			t.Skip("store does not dump projections")
+		}
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "b", entry{Name: "b"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "a"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "others", "z", entry{Name: "z"}))
+		require.NoError(t, store.Put(context.Background(), "realm-2", "things", "c", entry{Name: "c"}))
+
+		// When
+		var rows []string
+		err := dumper.DumpProjections(context.Background(), "realm-1", func(row core.ProjectionRow) error {
+			var got entry
+			require.NoError(t, json.Unmarshal(row.Value, &got))
+			rows = append(rows, row.ProjectionName+"/"+row.Key+"="+got.Name)
+			return nil
+		})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"others/z=z", "things/a=a", "things/b=b"}, rows)
+	})
+
+	t.Run("truncates a projection in every realm", func(t *testing.T) {
+		store := newStore(t)
+		truncator, ok := store.(core.ProjectionTruncator)
+		if !ok {
+			t.Skip("store does not truncate projections")
+		}
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "a"}))
+		require.NoError(t, store.Put(context.Background(), "realm-2", "things", "b", entry{Name: "b"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "others", "z", entry{Name: "z"}))
+
+		// When
+		err := truncator.TruncateProjection(context.Background(), "things")
+
+		// Then
+		require.NoError(t, err)
+		for _, realmID := range []string{"realm-1", "realm-2"} {
+			list, err := store.List(context.Background(), realmID, "things")
+			require.NoError(t, err)
+			assert.Empty(t, list)
+		}
+		var got entry
+		require.NoError(t, store.Get(context.Background(), "realm-1", "others", "z", &got))
+		assert.Equal(t, "z", got.Name)
+	})
+
+	t.Run("truncates a projection in one realm", func(t *testing.T) {
+		store := newStore(t)
+		truncator, ok := store.(core.ProjectionTruncator)
+		if !ok {
+			t.Skip("store does not truncate projections")
+		}
+
+		// Given
+		require.NoError(t, store.Put(context.Background(), "realm-1", "things", "a", entry{Name: "a"}))
+		require.NoError(t, store.Put(context.Background(), "realm-2", "things", "b", entry{Name: "b"}))
+		require.NoError(t, store.Put(context.Background(), "realm-1", "others", "z", entry{Name: "z"}))
+
+		// When
+		err := truncator.TruncateRealmProjection(context.Background(), "realm-1", "things")
+
+		// Then
+		require.NoError(t, err)
+		list, err := store.List(context.Background(), "realm-1", "things")
+		require.NoError(t, err)
+		assert.Empty(t, list)
+		list, err = store.List(context.Background(), "realm-2", "things")
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+		var got entry
+		require.NoError(t, store.Get(context.Background(), "realm-1", "others", "z", &got))
+		assert.Equal(t, "z", got.Name)
+	})
+}
+
+// CheckpointStore checks the behaviour every core.CheckpointStore must have.
+func CheckpointStore(t *testing.T, newStore func(t *testing.T) core.CheckpointStore) {
+	t.Run("returns 0 for a missing checkpoint", func(t *testing.T) {
+		store := newStore(t)
+
+		// When
+		pos, err := store.GetCheckpoint(context.Background(), "realm-1", "projector")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), pos)
+	})
+
+	t.Run("returns the last position set", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "projector", 5))
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "projector", 9))
+
+		// When
+		pos, err := store.GetCheckpoint(context.Background(), "realm-1", "projector")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), pos)
+	})
+
+	t.Run("keeps realms and projectors apart", func(t *testing.T) {
+		store := newStore(t)
+
+		// Given
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "projector", 5))
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-2", "projector", 7))
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "other", 3))
+
+		// When
+		pos, err := store.GetCheckpoint(context.Background(), "realm-1", "projector")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), pos)
+	})
+
+	t.Run("lists a realm's checkpoints", func(t *testing.T) {
+		store := newStore(t)
+		lister, ok := store.(core.CheckpointLister)
+		if !ok {
+			t.Skip("store does not list checkpoints")
+		}
+
+		// Given
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "projector", 5))
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-1", "other", 3))
+		require.NoError(t, store.SetCheckpoint(context.Background(), "realm-2", "projector", 7))
+
+		// When
+		checkpoints, err := lister.ListCheckpoints(context.Background(), "realm-1")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{"projector": 5, "other": 3}, checkpoints)
+	})
+
+	t.Run("records projector versions", func(t *testing.T) {
+		store := newStore(t)
+		versions, ok := store.(core.ProjectorVersionStore)
+		if !ok {
+			t.Skip("store does not record projector versions")
+		}
+
+		// Given
+		missing, err := versions.GetProjectorVersion(context.Background(), "projector")
+		require.NoError(t, err)
+		require.NoError(t, versions.SetProjectorVersion(context.Background(), "projector", 2))
+		require.NoError(t, versions.SetProjectorVersion(context.Background(), "projector", 3))
+		require.NoError(t, versions.SetProjectorVersion(context.Background(), "other", 7))
+
+		// When
+		version, err := versions.GetProjectorVersion(context.Background(), "projector")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 0, missing)
+		assert.Equal(t, 3, version)
+	})
+}
+
+func appendEvents(t *testing.T, store core.EventStore, realmID, streamID string, count int) []core.Event {
+	t.Helper()
+	events, err := store.ReadStream(context.Background(), realmID, streamID, 0)
+	require.NoError(t, err)
+	data := make([]core.EventData, count)
+	for i := range data {
+		data[i] = core.EventData{EventType: "Happened", Data: map[string]int{"n": len(events) + i}}
+	}
+	appended, err := store.Append(context.Background(), realmID, streamID, len(events), data)
+	require.NoError(t, err)
+	return appended
+}
+
+func versions(events []core.Event) []int {
+	versions := make([]int, len(events))
+	for i, evt := range events {
+		versions[i] = evt.Version
+	}
+	return versions
+}
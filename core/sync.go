@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// SyncReceipt is the recorded outcome of a command pushed by an offline
+// client, kept so that a push retried after its response was lost is
+// answered from the receipt instead of running the command again.
+type SyncReceipt struct {
+	RealmID        string          `json:"realm_id"`
+	AccountID      string          `json:"account_id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Command        string          `json:"command"`
+	StatusCode     int             `json:"status_code"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// SyncReceiptStore holds sync receipts outside the event store, keyed by
+// realm, account and idempotency key.
+type SyncReceiptStore interface {
+	// SaveReceipt records a receipt. A receipt already saved under the same
+	// key is kept.
+	SaveReceipt(ctx context.Context, receipt SyncReceipt) error
+	// GetReceipt returns a receipt, or a NotFoundError.
+	GetReceipt(ctx context.Context, realmID, accountID, idempotencyKey string) (SyncReceipt, error)
+}
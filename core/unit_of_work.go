@@ -0,0 +1,63 @@
+package core
+
+import "context"
+
+// UnitOfWork holds stores that share one transaction: everything written
+// through them is committed together or not at all.
+type UnitOfWork struct {
+	EventStore      EventStore
+	ProjectionStore ProjectionStore
+	CheckpointStore CheckpointStore
+}
+
+// Transactor is implemented by providers whose stores can join a single
+// transaction.
+type Transactor interface {
+	// Transact runs fn with stores bound to a new transaction, committing it
+	// if fn returns nil and rolling it back otherwise.
+	Transact(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error
+}
+
+// SideEffectProjector is implemented by projectors that act outside the
+// projection store, such as sending notifications or appending events. The
+// engine never runs them inside a transaction that might roll back.
+type SideEffectProjector interface {
+	Projector
+	HasSideEffects() bool
+}
+
+// ProjectionInvalidator is implemented by projection stores that cache
+// entries, so writes that bypassed them can be evicted.
+type ProjectionInvalidator interface {
+	InvalidateProjections(writes []ProjectionWrite)
+	// InvalidateRealmProjection evicts every entry of the realm's
+	// projection, for when it was truncated without going through the
+	// store.
+	InvalidateRealmProjection(realmID string, projectionName string)
+}
+
+func hasSideEffects(p Projector) bool {
+	s, ok := p.(SideEffectProjector)
+	return ok && s.HasSideEffects()
+}
+
+// writeRecorder notes the keys written through it.
+type writeRecorder struct {
+	ProjectionStore
+	writes []ProjectionWrite
+}
+
+func (r *writeRecorder) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	r.writes = append(r.writes, ProjectionWrite{RealmID: realmID, ProjectionName: projectionName, Key: key})
+	return r.ProjectionStore.Put(ctx, realmID, projectionName, key, value)
+}
+
+func (r *writeRecorder) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	r.writes = append(r.writes, ProjectionWrite{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true})
+	return r.ProjectionStore.Delete(ctx, realmID, projectionName, key)
+}
+
+func (r *writeRecorder) WriteBatch(ctx context.Context, writes []ProjectionWrite) error {
+	r.writes = append(r.writes, writes...)
+	return WriteProjectionBatch(ctx, r.ProjectionStore, writes)
+}
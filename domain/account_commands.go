@@ -2,6 +2,9 @@ package domain
 
 type CreateAccount struct {
 	Username string `json:"username"`
+	// Source names the system that manages the account, such as
+	// "directory". Empty for accounts created by hand.
+	Source string `json:"source,omitempty"`
 }
 
 type SuspendAccount struct {
@@ -40,6 +43,12 @@ type RevokePAT struct {
 	PATID     string `json:"pat_id"`
 }
 
+// IssueCalendarToken issues a new calendar feed token for an account,
+// replacing any previous one.
+type IssueCalendarToken struct {
+	AccountID string `json:"account_id"`
+}
+
 type CreateAccountResult struct {
 	AccountID string `json:"account_id"`
 	RawToken  string `json:"raw_token"`
@@ -49,3 +58,7 @@ type CreatePATResult struct {
 	PATID    string `json:"pat_id"`
 	RawToken string `json:"raw_token"`
 }
+
+type IssueCalendarTokenResult struct {
+	RawToken string `json:"raw_token"`
+}
@@ -11,11 +11,14 @@ const (
 	EventPATRevoked       = "PATRevoked"
 	EventRoleAssigned     = "RoleAssigned"
 	EventRoleRevoked      = "RoleRevoked"
+
+	EventCalendarTokenIssued = "CalendarTokenIssued"
 )
 
 type AccountCreated struct {
 	AccountID string    `json:"account_id"`
 	Username  string    `json:"username"`
+	Source    string    `json:"source,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -57,3 +60,9 @@ type RoleRevoked struct {
 	AccountID string `json:"account_id"`
 	RealmID   string `json:"realm_id"`
 }
+
+type CalendarTokenIssued struct {
+	AccountID string    `json:"account_id"`
+	KeyHash   string    `json:"key_hash"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/devzeebo/bifrost/core"
 )
@@ -25,6 +24,8 @@ type AccountState struct {
 	Exists    bool
 	Realms    map[string]string
 	PATs      map[string]PATState
+
+	CalendarKeyHash string
 }
 
 type PATState struct {
@@ -82,6 +83,10 @@ func RebuildAccountState(events []core.Event) AccountState {
 				pat.Revoked = true
 				state.PATs[data.PATID] = pat
 			}
+		case EventCalendarTokenIssued:
+			var data CalendarTokenIssued
+			_ = json.Unmarshal(evt.Data, &data)
+			state.CalendarKeyHash = data.KeyHash
 		}
 	}
 	return state
@@ -133,7 +138,7 @@ func requireActiveAccount(state AccountState, accountID string) error {
 		return &core.NotFoundError{Entity: "account", ID: accountID}
 	}
 	if state.Status == "suspended" {
-		return fmt.Errorf("account %q is suspended", accountID)
+		return Rejectf(ErrSuspended, "account %q is suspended", accountID)
 	}
 	return nil
 }
@@ -143,7 +148,7 @@ func HandleCreateAccount(ctx context.Context, cmd CreateAccount, store core.Even
 	var existingAccountID string
 	err := projectionStore.Get(ctx, AdminRealmID, "account_lookup", "username:"+cmd.Username, &existingAccountID)
 	if err == nil {
-		return CreateAccountResult{}, fmt.Errorf("username %q already exists", cmd.Username)
+		return CreateAccountResult{}, Rejectf(ErrAlreadyExists, "username %q already exists", cmd.Username)
 	}
 	var nfe *core.NotFoundError
 	if !errors.As(err, &nfe) {
@@ -168,7 +173,8 @@ func HandleCreateAccount(ctx context.Context, cmd CreateAccount, store core.Even
 	created := AccountCreated{
 		AccountID: accountID,
 		Username:  cmd.Username,
-		CreatedAt: time.Now().UTC(),
+		Source:    cmd.Source,
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
 	}
 
 	patCreated := PATCreated{
@@ -176,7 +182,7 @@ func HandleCreateAccount(ctx context.Context, cmd CreateAccount, store core.Even
 		PATID:     patID,
 		KeyHash:   keyHash,
 		Label:     "initial",
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
 	}
 
 	streamID := accountStreamID(accountID)
@@ -245,7 +251,7 @@ func HandleRevokeRealm(ctx context.Context, cmd RevokeRealm, store core.EventSto
 	}
 
 	if _, ok := state.Realms[cmd.RealmID]; !ok {
-		return fmt.Errorf("realm %q is not granted to account %q", cmd.RealmID, cmd.AccountID)
+		return Rejectf(ErrNotGranted, "realm %q is not granted to account %q", cmd.RealmID, cmd.AccountID)
 	}
 
 	revoked := RoleRevoked(cmd)
@@ -259,7 +265,7 @@ func HandleRevokeRealm(ctx context.Context, cmd RevokeRealm, store core.EventSto
 
 func HandleAssignRole(ctx context.Context, cmd AssignRole, store core.EventStore) error {
 	if !IsValidRole(cmd.Role) {
-		return fmt.Errorf("invalid role %q", cmd.Role)
+		return Rejectf(ErrInvalidCommand, "invalid role %q", cmd.Role)
 	}
 
 	state, events, err := readAndRebuildAccountState(ctx, cmd.AccountID, store)
@@ -294,7 +300,7 @@ func HandleRevokeRole(ctx context.Context, cmd RevokeRole, store core.EventStore
 	}
 
 	if _, ok := state.Realms[cmd.RealmID]; !ok {
-		return fmt.Errorf("realm %q is not granted to account %q", cmd.RealmID, cmd.AccountID)
+		return Rejectf(ErrNotGranted, "realm %q is not granted to account %q", cmd.RealmID, cmd.AccountID)
 	}
 
 	revoked := RoleRevoked(cmd)
@@ -330,7 +336,7 @@ func HandleCreatePAT(ctx context.Context, cmd CreatePAT, store core.EventStore)
 		PATID:     patID,
 		KeyHash:   keyHash,
 		Label:     cmd.Label,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
 	}
 
 	streamID := accountStreamID(cmd.AccountID)
@@ -358,10 +364,10 @@ func HandleRevokePAT(ctx context.Context, cmd RevokePAT, store core.EventStore)
 
 	pat, ok := state.PATs[cmd.PATID]
 	if !ok {
-		return fmt.Errorf("PAT %q not found on account %q", cmd.PATID, cmd.AccountID)
+		return Rejectf(ErrInvalidCommand, "PAT %q not found on account %q", cmd.PATID, cmd.AccountID)
 	}
 	if pat.Revoked {
-		return fmt.Errorf("PAT %q is already revoked", cmd.PATID)
+		return Rejectf(ErrInvalidCommand, "PAT %q is already revoked", cmd.PATID)
 	}
 
 	revoked := PATRevoked(cmd)
@@ -372,3 +378,34 @@ func HandleRevokePAT(ctx context.Context, cmd RevokePAT, store core.EventStore)
 	})
 	return err
 }
+
+func HandleIssueCalendarToken(ctx context.Context, cmd IssueCalendarToken, store core.EventStore) (IssueCalendarTokenResult, error) {
+	state, events, err := readAndRebuildAccountState(ctx, cmd.AccountID, store)
+	if err != nil {
+		return IssueCalendarTokenResult{}, err
+	}
+	if err := requireActiveAccount(state, cmd.AccountID); err != nil {
+		return IssueCalendarTokenResult{}, err
+	}
+
+	rawToken, keyHash, err := generateToken()
+	if err != nil {
+		return IssueCalendarTokenResult{}, err
+	}
+
+	issued := CalendarTokenIssued{
+		AccountID: cmd.AccountID,
+		KeyHash:   keyHash,
+		IssuedAt:  core.ClockFromContext(ctx).Now().UTC(),
+	}
+
+	streamID := accountStreamID(cmd.AccountID)
+	_, err = store.Append(ctx, AdminRealmID, streamID, len(events), []core.EventData{
+		{EventType: EventCalendarTokenIssued, Data: issued},
+	})
+	if err != nil {
+		return IssueCalendarTokenResult{}, err
+	}
+
+	return IssueCalendarTokenResult{RawToken: rawToken}, nil
+}
@@ -177,6 +177,23 @@ func TestHandleCreateAccount(t *testing.T) {
 		tc.pat_created_event_has_hashed_key()
 	})
 
+	t.Run("records the managing source on the created event", func(t *testing.T) {
+		tc := newAccountHandlerTestContext(t)
+
+		// Given
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.username_is_available("alice")
+		tc.createAccountCmd = CreateAccount{Username: "alice", Source: "directory"}
+
+		// When
+		tc.handle_create_account()
+
+		// Then
+		tc.no_account_error()
+		tc.account_created_event_has_source("directory")
+	})
+
 	t.Run("returns error when username is taken", func(t *testing.T) {
 		tc := newAccountHandlerTestContext(t)
 
@@ -588,6 +605,53 @@ func TestHandleCreatePAT(t *testing.T) {
 	})
 }
 
+func TestHandleIssueCalendarToken(t *testing.T) {
+	t.Run("issues calendar token for active account", func(t *testing.T) {
+		tc := newAccountHandlerTestContext(t)
+
+		// Given
+		tc.an_event_store()
+		tc.existing_account_in_stream("acct-a1b2", "active")
+
+		// When
+		tc.handle_issue_calendar_token("acct-a1b2")
+
+		// Then
+		tc.no_account_error()
+		tc.account_event_was_appended_to_stream("account-acct-a1b2")
+		tc.appended_account_event_has_type(EventCalendarTokenIssued)
+		tc.appended_calendar_token_event_has_hashed_key()
+	})
+
+	t.Run("returns error when account is suspended", func(t *testing.T) {
+		tc := newAccountHandlerTestContext(t)
+
+		// Given
+		tc.an_event_store()
+		tc.existing_account_in_stream("acct-a1b2", "suspended")
+
+		// When
+		tc.handle_issue_calendar_token("acct-a1b2")
+
+		// Then
+		tc.account_error_contains("suspended")
+	})
+
+	t.Run("returns error when account does not exist", func(t *testing.T) {
+		tc := newAccountHandlerTestContext(t)
+
+		// Given
+		tc.an_event_store()
+		tc.empty_account_stream("acct-missing")
+
+		// When
+		tc.handle_issue_calendar_token("acct-missing")
+
+		// Then
+		tc.account_error_is_not_found("account", "acct-missing")
+	})
+}
+
 func TestHandleRevokePAT(t *testing.T) {
 	t.Run("revokes existing PAT", func(t *testing.T) {
 		tc := newAccountHandlerTestContext(t)
@@ -687,6 +751,7 @@ type accountHandlerTestContext struct {
 
 	createAccountResult CreateAccountResult
 	createPATResult     CreatePATResult
+	calendarTokenResult IssueCalendarTokenResult
 	accountState        AccountState
 	accountEvents       []core.Event
 	err                 error
@@ -1009,6 +1074,11 @@ func (tc *accountHandlerTestContext) handle_create_pat() {
 	tc.createPATResult, tc.err = HandleCreatePAT(tc.ctx, tc.createPATCmd, tc.eventStore)
 }
 
+func (tc *accountHandlerTestContext) handle_issue_calendar_token(accountID string) {
+	tc.t.Helper()
+	tc.calendarTokenResult, tc.err = HandleIssueCalendarToken(tc.ctx, IssueCalendarToken{AccountID: accountID}, tc.eventStore)
+}
+
 func (tc *accountHandlerTestContext) handle_revoke_pat() {
 	tc.t.Helper()
 	tc.err = HandleRevokePAT(tc.ctx, tc.revokePATCmd, tc.eventStore)
@@ -1130,6 +1200,15 @@ func (tc *accountHandlerTestContext) account_created_event_was_appended_to_admin
 	assert.Equal(tc.t, AdminRealmID, lastCall.realmID)
 }
 
+func (tc *accountHandlerTestContext) account_created_event_has_source(expected string) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
+	lastCall := tc.eventStore.appendedCalls[len(tc.eventStore.appendedCalls)-1]
+	created, ok := lastCall.events[0].Data.(AccountCreated)
+	require.True(tc.t, ok, "expected AccountCreated data")
+	assert.Equal(tc.t, expected, created.Source)
+}
+
 func (tc *accountHandlerTestContext) account_created_event_stream_has_account_prefix() {
 	tc.t.Helper()
 	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
@@ -1224,6 +1303,20 @@ func (tc *accountHandlerTestContext) appended_pat_created_event_has_hashed_key()
 	assert.Equal(tc.t, expectedHashStr, patEvt.KeyHash)
 }
 
+func (tc *accountHandlerTestContext) appended_calendar_token_event_has_hashed_key() {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
+	lastCall := tc.eventStore.appendedCalls[len(tc.eventStore.appendedCalls)-1]
+	require.Len(tc.t, lastCall.events, 1)
+	issued, ok := lastCall.events[0].Data.(CalendarTokenIssued)
+	require.True(tc.t, ok)
+
+	rawTokenBytes, err := base64.RawURLEncoding.DecodeString(tc.calendarTokenResult.RawToken)
+	require.NoError(tc.t, err)
+	expectedHash := sha256.Sum256(rawTokenBytes)
+	assert.Equal(tc.t, base64.RawURLEncoding.EncodeToString(expectedHash[:]), issued.KeyHash)
+}
+
 func (tc *accountHandlerTestContext) appended_role_assigned_event_has_role(expectedRole string) {
 	tc.t.Helper()
 	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
@@ -0,0 +1,20 @@
+package domain
+
+type IssueAdminToken struct {
+	// Name says what the token is for, such as "terraform".
+	Name string `json:"name"`
+}
+
+type RotateAdminToken struct {
+	TokenID string `json:"token_id"`
+}
+
+type RevokeAdminToken struct {
+	TokenID string `json:"token_id"`
+}
+
+type AdminTokenResult struct {
+	TokenID string `json:"token_id"`
+	// Token is the secret; it is only ever returned here.
+	Token string `json:"token"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+const (
+	EventAdminTokenIssued  = "AdminTokenIssued"
+	EventAdminTokenRotated = "AdminTokenRotated"
+	EventAdminTokenRevoked = "AdminTokenRevoked"
+)
+
+type AdminTokenIssued struct {
+	TokenID  string    `json:"token_id"`
+	Name     string    `json:"name"`
+	KeyHash  string    `json:"key_hash"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// AdminTokenRotated replaces a token's secret. The previous secret stops
+// working at once.
+type AdminTokenRotated struct {
+	TokenID         string    `json:"token_id"`
+	KeyHash         string    `json:"key_hash"`
+	PreviousKeyHash string    `json:"previous_key_hash"`
+	RotatedAt       time.Time `json:"rotated_at"`
+}
+
+type AdminTokenRevoked struct {
+	TokenID string `json:"token_id"`
+	KeyHash string `json:"key_hash"`
+}
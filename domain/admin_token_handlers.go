@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const adminTokenStreamPrefix = "admin-token-"
+
+// AdminTokenPrefix starts every admin token, telling them apart from PATs.
+// Admin tokens belong to no account, cannot log in to the UI and only act
+// in the _admin realm, so automation never needs a person's credentials.
+const AdminTokenPrefix = "bfa_"
+
+type AdminTokenState struct {
+	TokenID string
+	Name    string
+	KeyHash string
+	Exists  bool
+	Revoked bool
+}
+
+func RebuildAdminTokenState(events []core.Event) AdminTokenState {
+	var state AdminTokenState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventAdminTokenIssued:
+			var data AdminTokenIssued
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.TokenID = data.TokenID
+			state.Name = data.Name
+			state.KeyHash = data.KeyHash
+		case EventAdminTokenRotated:
+			var data AdminTokenRotated
+			_ = json.Unmarshal(evt.Data, &data)
+			state.KeyHash = data.KeyHash
+		case EventAdminTokenRevoked:
+			state.Revoked = true
+		}
+	}
+	return state
+}
+
+func adminTokenStreamID(tokenID string) string {
+	return adminTokenStreamPrefix + tokenID
+}
+
+func generateAdminTokenID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate admin token ID: %w", err)
+	}
+	return "adm-" + hex.EncodeToString(b), nil
+}
+
+// generateAdminToken returns a new secret and the hash it is looked up by,
+// which is the hash of the secret without AdminTokenPrefix.
+func generateAdminToken() (raw string, hash string, err error) {
+	raw, hash, err = generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	return AdminTokenPrefix + raw, hash, nil
+}
+
+// readLiveAdminToken loads a token, reporting revoked tokens as not found.
+func readLiveAdminToken(ctx context.Context, tokenID string, store core.EventStore) (AdminTokenState, []core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, adminTokenStreamID(tokenID), 0)
+	if err != nil {
+		return AdminTokenState{}, nil, err
+	}
+	state := RebuildAdminTokenState(events)
+	if !state.Exists || state.Revoked {
+		return AdminTokenState{}, nil, &core.NotFoundError{Entity: "admin token", ID: tokenID}
+	}
+	return state, events, nil
+}
+
+func HandleIssueAdminToken(ctx context.Context, cmd IssueAdminToken, store core.EventStore) (AdminTokenResult, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return AdminTokenResult{}, Rejectf(ErrInvalidCommand, "admin token name is required")
+	}
+
+	tokenID, err := generateAdminTokenID()
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+	rawToken, keyHash, err := generateAdminToken()
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+
+	issued := AdminTokenIssued{
+		TokenID:  tokenID,
+		Name:     name,
+		KeyHash:  keyHash,
+		IssuedAt: core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, adminTokenStreamID(tokenID), 0, []core.EventData{
+		{EventType: EventAdminTokenIssued, Data: issued},
+	})
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+
+	return AdminTokenResult{TokenID: tokenID, Token: rawToken}, nil
+}
+
+// HandleRotateAdminToken gives a token a new secret, keeping its ID and
+// name. The old secret stops working as soon as the rotation is projected.
+func HandleRotateAdminToken(ctx context.Context, cmd RotateAdminToken, store core.EventStore) (AdminTokenResult, error) {
+	state, events, err := readLiveAdminToken(ctx, cmd.TokenID, store)
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+
+	rawToken, keyHash, err := generateAdminToken()
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+
+	rotated := AdminTokenRotated{
+		TokenID:         cmd.TokenID,
+		KeyHash:         keyHash,
+		PreviousKeyHash: state.KeyHash,
+		RotatedAt:       core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, adminTokenStreamID(cmd.TokenID), len(events), []core.EventData{
+		{EventType: EventAdminTokenRotated, Data: rotated},
+	})
+	if err != nil {
+		return AdminTokenResult{}, err
+	}
+
+	return AdminTokenResult{TokenID: cmd.TokenID, Token: rawToken}, nil
+}
+
+func HandleRevokeAdminToken(ctx context.Context, cmd RevokeAdminToken, store core.EventStore) error {
+	state, events, err := readLiveAdminToken(ctx, cmd.TokenID, store)
+	if err != nil {
+		return err
+	}
+
+	revoked := AdminTokenRevoked{TokenID: cmd.TokenID, KeyHash: state.KeyHash}
+	_, err = store.Append(ctx, AdminRealmID, adminTokenStreamID(cmd.TokenID), len(events), []core.EventData{
+		{EventType: EventAdminTokenRevoked, Data: revoked},
+	})
+	return err
+}
@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRebuildAdminTokenState(t *testing.T) {
+	t.Run("applies rotation and revocation", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// Given
+		tc.events = []core.Event{
+			makeEvent(EventAdminTokenIssued, AdminTokenIssued{TokenID: "adm-a1b2c3d4", Name: "ci", KeyHash: "h1"}),
+			makeEvent(EventAdminTokenRotated, AdminTokenRotated{TokenID: "adm-a1b2c3d4", KeyHash: "h2", PreviousKeyHash: "h1"}),
+			makeEvent(EventAdminTokenRevoked, AdminTokenRevoked{TokenID: "adm-a1b2c3d4", KeyHash: "h2"}),
+		}
+
+		// When
+		state := RebuildAdminTokenState(tc.events)
+
+		// Then
+		assert.True(t, state.Exists)
+		assert.True(t, state.Revoked)
+		assert.Equal(t, "ci", state.Name)
+		assert.Equal(t, "h2", state.KeyHash)
+	})
+}
+
+func TestHandleIssueAdminToken(t *testing.T) {
+	t.Run("issues prefixed token and stores only its hash", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// When
+		tc.issue_admin_token_is_handled("  provisioner  ")
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^adm-[0-9a-f]{8}$`, tc.result.TokenID)
+		assert.True(t, strings.HasPrefix(tc.result.Token, AdminTokenPrefix))
+		issued := tc.appended_event_data(EventAdminTokenIssued).(AdminTokenIssued)
+		assert.Equal(t, "provisioner", issued.Name)
+		assert.NotEmpty(t, issued.KeyHash)
+		assert.NotContains(t, tc.result.Token, issued.KeyHash)
+	})
+
+	t.Run("rejects blank name", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// When
+		tc.issue_admin_token_is_handled(" ")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "admin token name is required")
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+}
+
+func TestHandleRotateAdminToken(t *testing.T) {
+	t.Run("records new and previous hash", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// Given
+		tc.existing_admin_token("adm-a1b2c3d4", "h1")
+
+		// When
+		tc.result, tc.err = HandleRotateAdminToken(tc.ctx, RotateAdminToken{TokenID: "adm-a1b2c3d4"}, tc.eventStore)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "adm-a1b2c3d4", tc.result.TokenID)
+		rotated := tc.appended_event_data(EventAdminTokenRotated).(AdminTokenRotated)
+		assert.Equal(t, "h1", rotated.PreviousKeyHash)
+		assert.NotEqual(t, "h1", rotated.KeyHash)
+		assert.Equal(t, 1, tc.eventStore.appendedCalls[0].expectedVersion)
+	})
+
+	t.Run("returns not found for unknown token", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// When
+		tc.result, tc.err = HandleRotateAdminToken(tc.ctx, RotateAdminToken{TokenID: "adm-missing"}, tc.eventStore)
+
+		// Then
+		tc.error_is_not_found("adm-missing")
+	})
+}
+
+func TestHandleRevokeAdminToken(t *testing.T) {
+	t.Run("revokes live token", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// Given
+		tc.existing_admin_token("adm-a1b2c3d4", "h1")
+
+		// When
+		tc.err = HandleRevokeAdminToken(tc.ctx, RevokeAdminToken{TokenID: "adm-a1b2c3d4"}, tc.eventStore)
+
+		// Then
+		tc.no_error()
+		revoked := tc.appended_event_data(EventAdminTokenRevoked).(AdminTokenRevoked)
+		assert.Equal(t, "h1", revoked.KeyHash)
+	})
+
+	t.Run("returns not found for revoked token", func(t *testing.T) {
+		tc := newAdminTokenHandlerTestContext(t)
+
+		// Given
+		tc.existing_admin_token("adm-a1b2c3d4", "h1")
+		tc.eventStore.streams["admin-token-adm-a1b2c3d4"] = append(tc.eventStore.streams["admin-token-adm-a1b2c3d4"],
+			makeEvent(EventAdminTokenRevoked, AdminTokenRevoked{TokenID: "adm-a1b2c3d4", KeyHash: "h1"}))
+
+		// When
+		tc.err = HandleRevokeAdminToken(tc.ctx, RevokeAdminToken{TokenID: "adm-a1b2c3d4"}, tc.eventStore)
+
+		// Then
+		tc.error_is_not_found("adm-a1b2c3d4")
+	})
+}
+
+// --- Test Context ---
+
+type adminTokenHandlerTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	events []core.Event
+	result AdminTokenResult
+	err    error
+}
+
+func newAdminTokenHandlerTestContext(t *testing.T) *adminTokenHandlerTestContext {
+	t.Helper()
+	return &adminTokenHandlerTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *adminTokenHandlerTestContext) existing_admin_token(tokenID, keyHash string) {
+	tc.t.Helper()
+	tc.eventStore.streams["admin-token-"+tokenID] = []core.Event{
+		makeEvent(EventAdminTokenIssued, AdminTokenIssued{TokenID: tokenID, Name: "ci", KeyHash: keyHash}),
+	}
+}
+
+// --- When ---
+
+func (tc *adminTokenHandlerTestContext) issue_admin_token_is_handled(name string) {
+	tc.t.Helper()
+	tc.result, tc.err = HandleIssueAdminToken(tc.ctx, IssueAdminToken{Name: name}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *adminTokenHandlerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *adminTokenHandlerTestContext) error_is_not_found(id string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, "admin token", nfe.Entity)
+	assert.Equal(tc.t, id, nfe.ID)
+}
+
+func (tc *adminTokenHandlerTestContext) appended_event_data(eventType string) any {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, AdminRealmID, call.realmID)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, eventType, call.events[0].EventType)
+	return call.events[0].Data
+}
@@ -170,7 +170,7 @@ func HandleRevokeAgentRealm(ctx context.Context, cmd RevokeAgentRealm, store cor
 	}
 
 	if !state.Realms[cmd.RealmID] {
-		return fmt.Errorf("realm %q is not granted to agent %q", cmd.RealmID, cmd.AgentID)
+		return Rejectf(ErrNotGranted, "realm %q is not granted to agent %q", cmd.RealmID, cmd.AgentID)
 	}
 
 	revoked := AgentRealmRevoked(cmd)
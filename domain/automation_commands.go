@@ -0,0 +1,25 @@
+package domain
+
+type AddAutomationRule struct {
+	RealmID string `json:"realm_id"`
+	Name    string `json:"name"`
+	// Trigger is the rune lifecycle change the rule reacts to (see
+	// AutomationTriggers).
+	Trigger string `json:"trigger"`
+	// Match, when set, limits the rule to runes whose title contains it,
+	// ignoring case.
+	Match  string `json:"match,omitempty"`
+	Action string `json:"action"`
+	// Value is the action's argument: the priority for set_priority, or the
+	// note template for add_note.
+	Value string `json:"value"`
+}
+
+type RemoveAutomationRule struct {
+	RealmID string `json:"realm_id"`
+	RuleID  string `json:"rule_id"`
+}
+
+type AddAutomationRuleResult struct {
+	RuleID string `json:"rule_id"`
+}
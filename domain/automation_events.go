@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	EventAutomationRuleAdded   = "AutomationRuleAdded"
+	EventAutomationRuleRemoved = "AutomationRuleRemoved"
+)
+
+type AutomationRuleAdded struct {
+	RuleID    string    `json:"rule_id"`
+	RealmID   string    `json:"realm_id"`
+	Name      string    `json:"name"`
+	Trigger   string    `json:"trigger"`
+	Match     string    `json:"match,omitempty"`
+	Action    string    `json:"action"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AutomationRuleRemoved struct {
+	RuleID  string `json:"rule_id"`
+	RealmID string `json:"realm_id"`
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const automationRuleStreamPrefix = "automation-rule-"
+
+const (
+	AutomationActionSetPriority = "set_priority"
+	AutomationActionAddNote     = "add_note"
+)
+
+// AutomationTriggers maps each rule trigger to the rune event that fires it.
+// Only lifecycle events are triggers, so the updates and notes that rules
+// make can never set off another rule.
+var AutomationTriggers = map[string]string{
+	"created":   EventRuneCreated,
+	"claimed":   EventRuneClaimed,
+	"fulfilled": EventRuneFulfilled,
+	"sealed":    EventRuneSealed,
+}
+
+type AutomationRuleState struct {
+	RuleID  string
+	RealmID string
+	Exists  bool
+	Removed bool
+}
+
+func RebuildAutomationRuleState(events []core.Event) AutomationRuleState {
+	var state AutomationRuleState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventAutomationRuleAdded:
+			var data AutomationRuleAdded
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.RuleID = data.RuleID
+			state.RealmID = data.RealmID
+		case EventAutomationRuleRemoved:
+			state.Removed = true
+		}
+	}
+	return state
+}
+
+func automationRuleStreamID(ruleID string) string {
+	return automationRuleStreamPrefix + ruleID
+}
+
+func generateAutomationRuleID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate automation rule ID: %w", err)
+	}
+	return "ar-" + hex.EncodeToString(b), nil
+}
+
+func readAndRebuildAutomationRuleState(ctx context.Context, ruleID string, store core.EventStore) (AutomationRuleState, []core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, automationRuleStreamID(ruleID), 0)
+	if err != nil {
+		return AutomationRuleState{}, nil, err
+	}
+	return RebuildAutomationRuleState(events), events, nil
+}
+
+func validateAutomationRule(cmd AddAutomationRule) error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return Rejectf(ErrInvalidCommand, "cannot add automation rule: name is required")
+	}
+	if _, ok := AutomationTriggers[cmd.Trigger]; !ok {
+		triggers := make([]string, 0, len(AutomationTriggers))
+		for trigger := range AutomationTriggers {
+			triggers = append(triggers, trigger)
+		}
+		sort.Strings(triggers)
+		return Rejectf(ErrInvalidCommand, "cannot add automation rule: trigger must be one of %s", strings.Join(triggers, ", "))
+	}
+	switch cmd.Action {
+	case AutomationActionSetPriority:
+		if p, err := strconv.Atoi(cmd.Value); err != nil || p < 0 {
+			return Rejectf(ErrInvalidCommand, "cannot add automation rule: set_priority value must be a non-negative integer")
+		}
+	case AutomationActionAddNote:
+		if strings.TrimSpace(cmd.Value) == "" {
+			return Rejectf(ErrInvalidCommand, "cannot add automation rule: add_note value must be the note text")
+		}
+	default:
+		return Rejectf(ErrInvalidCommand, "cannot add automation rule: action must be %s or %s", AutomationActionSetPriority, AutomationActionAddNote)
+	}
+	return nil
+}
+
+func HandleAddAutomationRule(ctx context.Context, cmd AddAutomationRule, store core.EventStore) (AddAutomationRuleResult, error) {
+	if err := validateAutomationRule(cmd); err != nil {
+		return AddAutomationRuleResult{}, err
+	}
+
+	realm, _, err := readAndRebuildRealmState(ctx, cmd.RealmID, store)
+	if err != nil {
+		return AddAutomationRuleResult{}, err
+	}
+	if !realm.Exists {
+		return AddAutomationRuleResult{}, &core.NotFoundError{Entity: "realm", ID: cmd.RealmID}
+	}
+
+	ruleID, err := generateAutomationRuleID()
+	if err != nil {
+		return AddAutomationRuleResult{}, err
+	}
+
+	added := AutomationRuleAdded{
+		RuleID:    ruleID,
+		RealmID:   cmd.RealmID,
+		Name:      strings.TrimSpace(cmd.Name),
+		Trigger:   cmd.Trigger,
+		Match:     cmd.Match,
+		Action:    cmd.Action,
+		Value:     cmd.Value,
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, automationRuleStreamID(ruleID), 0, []core.EventData{
+		{EventType: EventAutomationRuleAdded, Data: added},
+	})
+	if err != nil {
+		return AddAutomationRuleResult{}, err
+	}
+
+	return AddAutomationRuleResult{RuleID: ruleID}, nil
+}
+
+// HandleRemoveAutomationRule removes a rule from its realm. A rule belonging
+// to another realm is reported as not found.
+func HandleRemoveAutomationRule(ctx context.Context, cmd RemoveAutomationRule, store core.EventStore) error {
+	state, events, err := readAndRebuildAutomationRuleState(ctx, cmd.RuleID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists || state.Removed || state.RealmID != cmd.RealmID {
+		return &core.NotFoundError{Entity: "automation rule", ID: cmd.RuleID}
+	}
+
+	removed := AutomationRuleRemoved{RuleID: cmd.RuleID, RealmID: cmd.RealmID}
+	_, err = store.Append(ctx, AdminRealmID, automationRuleStreamID(cmd.RuleID), len(events), []core.EventData{
+		{EventType: EventAutomationRuleRemoved, Data: removed},
+	})
+	return err
+}
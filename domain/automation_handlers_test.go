@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRebuildAutomationRuleState(t *testing.T) {
+	t.Run("rebuilds state from added and removed events", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// Given
+		tc.events = []core.Event{
+			makeEvent(EventAutomationRuleAdded, AutomationRuleAdded{RuleID: "ar-a1b2c3d4", RealmID: "bf-r1"}),
+			makeEvent(EventAutomationRuleRemoved, AutomationRuleRemoved{RuleID: "ar-a1b2c3d4", RealmID: "bf-r1"}),
+		}
+
+		// When
+		tc.state = RebuildAutomationRuleState(tc.events)
+
+		// Then
+		assert.True(t, tc.state.Exists)
+		assert.True(t, tc.state.Removed)
+		assert.Equal(t, "ar-a1b2c3d4", tc.state.RuleID)
+		assert.Equal(t, "bf-r1", tc.state.RealmID)
+	})
+}
+
+func TestHandleAddAutomationRule(t *testing.T) {
+	t.Run("adds rule to realm", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+		tc.add_rule_command(AddAutomationRule{
+			RealmID: "bf-r1", Name: " Urgent first ", Trigger: "created", Match: "[urgent]",
+			Action: AutomationActionSetPriority, Value: "0",
+		})
+
+		// When
+		tc.add_rule_is_handled()
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^ar-[0-9a-f]{8}$`, tc.result.RuleID)
+		tc.added_event_was_appended()
+		assert.Equal(t, "Urgent first", tc.added.Name)
+		assert.Equal(t, "created", tc.added.Trigger)
+		assert.Equal(t, "[urgent]", tc.added.Match)
+		assert.Equal(t, "0", tc.added.Value)
+		assert.False(t, tc.added.CreatedAt.IsZero())
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		cases := map[string]struct {
+			cmd      AddAutomationRule
+			expected string
+		}{
+			"missing name":      {AddAutomationRule{Trigger: "created", Action: AutomationActionAddNote, Value: "x"}, "name is required"},
+			"unknown trigger":   {AddAutomationRule{Name: "r", Trigger: "labeled", Action: AutomationActionAddNote, Value: "x"}, "trigger must be one of claimed, created, fulfilled, sealed"},
+			"unknown action":    {AddAutomationRule{Name: "r", Trigger: "created", Action: "delete"}, "action must be set_priority or add_note"},
+			"bad priority":      {AddAutomationRule{Name: "r", Trigger: "created", Action: AutomationActionSetPriority, Value: "high"}, "non-negative integer"},
+			"empty note":        {AddAutomationRule{Name: "r", Trigger: "fulfilled", Action: AutomationActionAddNote, Value: " "}, "note text"},
+			"negative priority": {AddAutomationRule{Name: "r", Trigger: "created", Action: AutomationActionSetPriority, Value: "-1"}, "non-negative integer"},
+		}
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				tc := newAutomationHandlerTestContext(t)
+
+				// Given
+				tc.existing_realm("bf-r1")
+				c.cmd.RealmID = "bf-r1"
+				tc.add_rule_command(c.cmd)
+
+				// When
+				tc.add_rule_is_handled()
+
+				// Then
+				tc.error_contains(c.expected)
+				assert.Empty(t, tc.eventStore.appendedCalls)
+			})
+		}
+	})
+
+	t.Run("returns not found for unknown realm", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// Given
+		tc.add_rule_command(AddAutomationRule{
+			RealmID: "bf-missing", Name: "r", Trigger: "fulfilled", Action: AutomationActionAddNote, Value: "done",
+		})
+
+		// When
+		tc.add_rule_is_handled()
+
+		// Then
+		tc.error_is_not_found("realm", "bf-missing")
+	})
+}
+
+func TestHandleRemoveAutomationRule(t *testing.T) {
+	t.Run("removes existing rule", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// Given
+		tc.existing_rule("ar-a1b2c3d4", "bf-r1")
+
+		// When
+		tc.remove_rule_is_handled("bf-r1", "ar-a1b2c3d4")
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, AdminRealmID, call.realmID)
+		assert.Equal(t, "automation-rule-ar-a1b2c3d4", call.streamID)
+		assert.Equal(t, 1, call.expectedVersion)
+		assert.Equal(t, EventAutomationRuleRemoved, call.events[0].EventType)
+		assert.Equal(t, AutomationRuleRemoved{RuleID: "ar-a1b2c3d4", RealmID: "bf-r1"}, call.events[0].Data)
+	})
+
+	t.Run("returns not found for rule in another realm", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// Given
+		tc.existing_rule("ar-a1b2c3d4", "bf-r1")
+
+		// When
+		tc.remove_rule_is_handled("bf-r2", "ar-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("automation rule", "ar-a1b2c3d4")
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("returns not found for unknown rule", func(t *testing.T) {
+		tc := newAutomationHandlerTestContext(t)
+
+		// When
+		tc.remove_rule_is_handled("bf-r1", "ar-missing")
+
+		// Then
+		tc.error_is_not_found("automation rule", "ar-missing")
+	})
+}
+
+// --- Test Context ---
+
+type automationHandlerTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	cmd    AddAutomationRule
+	events []core.Event
+	state  AutomationRuleState
+	result AddAutomationRuleResult
+	added  AutomationRuleAdded
+	err    error
+}
+
+func newAutomationHandlerTestContext(t *testing.T) *automationHandlerTestContext {
+	t.Helper()
+	return &automationHandlerTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *automationHandlerTestContext) existing_realm(realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: realmID, Name: "Realm"}),
+	}
+}
+
+func (tc *automationHandlerTestContext) existing_rule(ruleID, realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["automation-rule-"+ruleID] = []core.Event{
+		makeEvent(EventAutomationRuleAdded, AutomationRuleAdded{
+			RuleID: ruleID, RealmID: realmID, Name: "r", Trigger: "fulfilled",
+			Action: AutomationActionAddNote, Value: "done",
+		}),
+	}
+}
+
+func (tc *automationHandlerTestContext) add_rule_command(cmd AddAutomationRule) {
+	tc.t.Helper()
+	tc.cmd = cmd
+}
+
+// --- When ---
+
+func (tc *automationHandlerTestContext) add_rule_is_handled() {
+	tc.t.Helper()
+	tc.result, tc.err = HandleAddAutomationRule(tc.ctx, tc.cmd, tc.eventStore)
+}
+
+func (tc *automationHandlerTestContext) remove_rule_is_handled(realmID, ruleID string) {
+	tc.t.Helper()
+	tc.err = HandleRemoveAutomationRule(tc.ctx, RemoveAutomationRule{RealmID: realmID, RuleID: ruleID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *automationHandlerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *automationHandlerTestContext) error_contains(substring string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substring)
+}
+
+func (tc *automationHandlerTestContext) error_is_not_found(entity, id string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, entity, nfe.Entity)
+	assert.Equal(tc.t, id, nfe.ID)
+}
+
+func (tc *automationHandlerTestContext) added_event_was_appended() {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, AdminRealmID, call.realmID)
+	assert.Equal(tc.t, "automation-rule-"+tc.result.RuleID, call.streamID)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, EventAutomationRuleAdded, call.events[0].EventType)
+	added, ok := call.events[0].Data.(AutomationRuleAdded)
+	require.True(tc.t, ok)
+	tc.added = added
+}
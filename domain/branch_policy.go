@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Realm settings that restrict the branch names runes may use.
+const (
+	// BranchPatternSetting is a regular expression the whole branch name
+	// must match.
+	BranchPatternSetting = "branch.pattern"
+	// BranchPrefixesSetting is a comma-separated list of prefixes, one of
+	// which the branch name must start with.
+	BranchPrefixesSetting = "branch.prefixes"
+)
+
+// BranchPolicy is a realm's rule for branch names. The zero value allows
+// any branch.
+type BranchPolicy struct {
+	Pattern  string   `json:"pattern,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// BranchPolicyFromSettings reads the policy from a realm's settings.
+func BranchPolicyFromSettings(settings map[string]string) BranchPolicy {
+	policy := BranchPolicy{Pattern: strings.TrimSpace(settings[BranchPatternSetting])}
+	for _, prefix := range strings.Split(settings[BranchPrefixesSetting], ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			policy.Prefixes = append(policy.Prefixes, prefix)
+		}
+	}
+	return policy
+}
+
+// Check returns an ErrBranchPolicy rejection, carrying the policy as its
+// details, if branch breaks the policy. An empty branch (no branch) is
+// always allowed.
+func (p BranchPolicy) Check(branch string) error {
+	if branch == "" {
+		return nil
+	}
+	var reasons []string
+	if len(p.Prefixes) > 0 && !hasAnyPrefix(branch, p.Prefixes) {
+		reasons = append(reasons, fmt.Sprintf("start with one of %q", p.Prefixes))
+	}
+	if p.Pattern != "" {
+		re, err := compileBranchPattern(p.Pattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(branch) {
+			reasons = append(reasons, fmt.Sprintf("match %q", p.Pattern))
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &RuleError{
+		Rule:    ErrBranchPolicy,
+		Details: p,
+		msg:     fmt.Sprintf("branch %q does not follow the realm's branch policy: it must %s", branch, strings.Join(reasons, " and ")),
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileBranchPattern anchors pattern so it must match the whole name.
+func compileBranchPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, Rejectf(ErrInvalidCommand, "invalid branch pattern %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// checkBranchPolicy checks branch against the policy in the realm's settings.
+func checkBranchPolicy(ctx context.Context, realmID, branch string, store core.EventStore) error {
+	if branch == "" {
+		return nil
+	}
+	realm, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return err
+	}
+	return BranchPolicyFromSettings(realm.Settings).Check(branch)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// RequireClaimantAccountSetting, when "true", makes a realm accept only
+// claimants that are the username of an existing, active account.
+const RequireClaimantAccountSetting = "claim.require_account"
+
+// resolveClaimant returns the account ID of claimant when the realm requires
+// claimants to be accounts, and "" when it does not. A claimant may be a
+// username, looked up in the account_lookup projection, or an account ID.
+// The account itself is read from its stream so a suspension is seen at once.
+func resolveClaimant(ctx context.Context, realmID, claimant string, store core.EventStore, projStore core.ProjectionStore) (string, error) {
+	realm, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return "", err
+	}
+	if required, _ := strconv.ParseBool(realm.Settings[RequireClaimantAccountSetting]); !required {
+		return "", nil
+	}
+
+	accountID := claimant
+	err = projStore.Get(ctx, AdminRealmID, "account_lookup", "username:"+claimant, &accountID)
+	var nfe *core.NotFoundError
+	if err != nil && !errors.As(err, &nfe) {
+		return "", err
+	}
+
+	account, _, err := readAndRebuildAccountState(ctx, accountID, store)
+	if err != nil {
+		return "", err
+	}
+	if !account.Exists {
+		return "", Rejectf(ErrUnknownClaimant, "claimant %q is not an account", claimant)
+	}
+	if account.Status == "suspended" {
+		return "", Rejectf(ErrSuspended, "claimant %q is suspended", claimant)
+	}
+	return account.AccountID, nil
+}
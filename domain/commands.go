@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type CreateRune struct {
 	Title       string  `json:"title"`
 	Description string  `json:"description,omitempty"`
@@ -7,6 +9,7 @@ type CreateRune struct {
 	ParentID    string  `json:"parent_id,omitempty"`
 	Branch      *string `json:"branch,omitempty"`
 	Type        string  `json:"type,omitempty"`
+	DueDate     string  `json:"due_date,omitempty"`
 }
 
 type UpdateRune struct {
@@ -15,6 +18,7 @@ type UpdateRune struct {
 	Description *string `json:"description,omitempty"`
 	Priority    *int    `json:"priority,omitempty"`
 	Branch      *string `json:"branch,omitempty"`
+	DueDate     *string `json:"due_date,omitempty"`
 }
 
 type ClaimRune struct {
@@ -43,6 +47,26 @@ type ShatterRune struct {
 	ID string `json:"id"`
 }
 
+// RecordSLABreach is issued by the SLA monitor, not by users.
+type RecordSLABreach struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Threshold string    `json:"threshold"`
+	EnteredAt time.Time `json:"entered_at"`
+}
+
+// AgeRunePriority is issued by the scheduler, not by users.
+type AgeRunePriority struct {
+	ID string `json:"id"`
+}
+
+// AgeRunePriorityResult reports the rune's priority after aging, and whether
+// it changed.
+type AgeRunePriorityResult struct {
+	Aged     bool `json:"aged"`
+	Priority int  `json:"priority"`
+}
+
 type AddDependency struct {
 	RuneID       string `json:"rune_id"`
 	TargetID     string `json:"target_id"`
@@ -59,3 +83,16 @@ type AddNote struct {
 	RuneID string `json:"rune_id"`
 	Text   string `json:"text"`
 }
+
+type CommitRef struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+type LinkCommits struct {
+	Repository string      `json:"repository,omitempty"`
+	Branch     string      `json:"branch,omitempty"`
+	Commits    []CommitRef `json:"commits"`
+}
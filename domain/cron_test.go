@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestParseCron(t *testing.T) {
+	t.Run("finds the next weekday morning", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("0 9 * * 1-5")
+
+		// When
+		tc.next_after("2026-03-20T10:00:00Z") // a Friday
+
+		// Then
+		tc.next_is("2026-03-23T09:00:00Z")
+	})
+
+	t.Run("supports steps and lists", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("*/15 8,20 * * *")
+
+		// When
+		tc.next_after("2026-03-20T08:50:00Z")
+
+		// Then
+		tc.next_is("2026-03-20T20:00:00Z")
+	})
+
+	t.Run("expands aliases", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("@monthly")
+
+		// When
+		tc.next_after("2026-03-20T10:00:00Z")
+
+		// Then
+		tc.next_is("2026-04-01T00:00:00Z")
+	})
+
+	t.Run("treats day of week 7 as Sunday", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("30 6 * * 7")
+
+		// When
+		tc.next_after("2026-03-20T10:00:00Z")
+
+		// Then
+		tc.next_is("2026-03-22T06:30:00Z")
+	})
+
+	t.Run("matches either day field when both are restricted", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("0 0 1 * 1")
+
+		// When
+		tc.next_after("2026-03-20T10:00:00Z")
+
+		// Then
+		tc.next_is("2026-03-23T00:00:00Z")
+	})
+
+	t.Run("returns zero when the schedule never matches", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("0 0 30 2 *")
+
+		// When
+		tc.next_after("2026-03-20T10:00:00Z")
+
+		// Then
+		assert.True(t, tc.next.IsZero())
+	})
+
+	t.Run("collapses missed runs into the latest", func(t *testing.T) {
+		tc := newCronTestContext(t)
+
+		// Given
+		tc.expression_is("@hourly")
+
+		// When
+		latest := tc.schedule.Latest(tc.parse_time("2026-03-20T10:00:00Z"), tc.parse_time("2026-03-20T13:30:00Z"))
+
+		// Then
+		assert.Equal(t, tc.parse_time("2026-03-20T13:00:00Z"), latest)
+	})
+
+	t.Run("rejects malformed expressions", func(t *testing.T) {
+		for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+			_, err := ParseCron(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+}
+
+// --- Test Context ---
+
+type cronTestContext struct {
+	t *testing.T
+
+	schedule CronSchedule
+	next     time.Time
+}
+
+func newCronTestContext(t *testing.T) *cronTestContext {
+	t.Helper()
+	return &cronTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *cronTestContext) expression_is(expr string) {
+	tc.t.Helper()
+	schedule, err := ParseCron(expr)
+	require.NoError(tc.t, err)
+	tc.schedule = schedule
+}
+
+func (tc *cronTestContext) parse_time(value string) time.Time {
+	tc.t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	require.NoError(tc.t, err)
+	return at
+}
+
+// --- When ---
+
+func (tc *cronTestContext) next_after(value string) {
+	tc.t.Helper()
+	tc.next = tc.schedule.Next(tc.parse_time(value))
+}
+
+// --- Then ---
+
+func (tc *cronTestContext) next_is(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, tc.parse_time(expected), tc.next)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// wouldCreateCycle reports whether runeID blocking targetID would close a
+// cycle, that is whether targetID already blocks runeID, directly or
+// through other runes. It follows blocks edges through the rune streams
+// rather than the dependency_graph projection, which may not have caught up
+// with the commands just before this one.
+func wouldCreateCycle(ctx context.Context, realmID, runeID, targetID string, store core.EventStore) (bool, error) {
+	if runeID == targetID {
+		return true, nil
+	}
+	visited := map[string]bool{targetID: true}
+	queue := []string{targetID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		events, err := store.ReadStream(ctx, realmID, runeStreamID(id), 0)
+		if err != nil {
+			return false, err
+		}
+		for _, blocked := range blockedRunes(events) {
+			if blocked == runeID {
+				return true, nil
+			}
+			if !visited[blocked] {
+				visited[blocked] = true
+				queue = append(queue, blocked)
+			}
+		}
+	}
+	return false, nil
+}
+
+// blockedRunes returns the runes a rune's stream says it currently blocks.
+func blockedRunes(events []core.Event) []string {
+	var order []string
+	blocks := make(map[string]bool)
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventDependencyAdded:
+			var data DependencyAdded
+			_ = json.Unmarshal(evt.Data, &data)
+			if data.Relationship == RelBlocks && !data.IsInverse {
+				if _, seen := blocks[data.TargetID]; !seen {
+					order = append(order, data.TargetID)
+				}
+				blocks[data.TargetID] = true
+			}
+		case EventDependencyRemoved:
+			var data DependencyRemoved
+			_ = json.Unmarshal(evt.Data, &data)
+			if data.Relationship == RelBlocks && !data.IsInverse {
+				blocks[data.TargetID] = false
+			}
+		}
+	}
+	var blocked []string
+	for _, id := range order {
+		if blocks[id] {
+			blocked = append(blocked, id)
+		}
+	}
+	return blocked
+}
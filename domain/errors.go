@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rules a command can break. Handlers return them wrapped in a RuleError
+// carrying a message about the specific entity, so callers match them with
+// errors.Is rather than by message.
+var (
+	ErrInvalidCommand  = errors.New("invalid command")
+	ErrRuneDraft       = errors.New("rune is a draft")
+	ErrRuneSealed      = errors.New("rune is sealed")
+	ErrRuneShattered   = errors.New("rune is shattered")
+	ErrRuneFulfilled   = errors.New("rune is fulfilled")
+	ErrAlreadyClaimed  = errors.New("rune is already claimed")
+	ErrNotClaimed      = errors.New("rune is not claimed")
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	ErrBranchRequired  = errors.New("branch is required")
+	ErrBranchPolicy    = errors.New("branch breaks the realm's policy")
+	ErrUnknownClaimant = errors.New("claimant is not an account")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrSuspended       = errors.New("suspended")
+	ErrNotGranted      = errors.New("not granted")
+	ErrDeleted         = errors.New("deleted")
+	ErrBlocked         = errors.New("rune is blocked")
+)
+
+// RuleError is returned when a command is rejected by a domain rule.
+// Details, when set, describe the rule so clients can explain the rejection.
+type RuleError struct {
+	Rule    error
+	Details any
+	msg     string
+}
+
+func (e *RuleError) Error() string {
+	return e.msg
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Rule
+}
+
+// Rejectf returns a RuleError for rule with a formatted message.
+func Rejectf(rule error, format string, args ...any) error {
+	return &RuleError{Rule: rule, msg: fmt.Sprintf(format, args...)}
+}
+
+// IsRejection reports whether err is, or wraps, a RuleError.
+func IsRejection(err error) bool {
+	var ruleErr *RuleError
+	return errors.As(err, &ruleErr)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestRejectf(t *testing.T) {
+	t.Run("keeps the specific message", func(t *testing.T) {
+		// When
+		err := Rejectf(ErrRuneSealed, "cannot claim sealed rune %q", "bf-a1b2")
+
+		// Then
+		assert.EqualError(t, err, `cannot claim sealed rune "bf-a1b2"`)
+	})
+
+	t.Run("matches its rule through wrapping", func(t *testing.T) {
+		// When
+		err := fmt.Errorf("claim: %w", Rejectf(ErrRuneSealed, "cannot claim sealed rune %q", "bf-a1b2"))
+
+		// Then
+		assert.ErrorIs(t, err, ErrRuneSealed)
+		assert.NotErrorIs(t, err, ErrRuneShattered)
+		assert.True(t, IsRejection(err))
+	})
+
+	t.Run("is not reported for other errors", func(t *testing.T) {
+		assert.False(t, IsRejection(errors.New("disk full")))
+	})
+}
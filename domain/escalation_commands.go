@@ -0,0 +1,36 @@
+package domain
+
+type AddEscalationPolicy struct {
+	RealmID string `json:"realm_id"`
+	Name    string `json:"name"`
+	// Trigger is overdue (the due date has passed) or stale_claim (the rune
+	// has been claimed too long).
+	Trigger string `json:"trigger"`
+	// After is how long past the due date, or since the claim, the policy
+	// waits: whole days ("3d") or a Go duration ("36h"). It is required for
+	// stale_claim and defaults to no grace for overdue.
+	After  string `json:"after,omitempty"`
+	Action string `json:"action"`
+}
+
+type RemoveEscalationPolicy struct {
+	RealmID  string `json:"realm_id"`
+	PolicyID string `json:"policy_id"`
+}
+
+type AddEscalationPolicyResult struct {
+	PolicyID string `json:"policy_id"`
+}
+
+// EscalateRune applies a policy to a rune. It is issued by the escalation
+// scheduler, not by users.
+type EscalateRune struct {
+	RuneID   string `json:"rune_id"`
+	PolicyID string `json:"policy_id"`
+}
+
+// EscalateRuneResult reports whether the policy fired. It is false when the
+// policy had already fired for the rune's current due date or claim.
+type EscalateRuneResult struct {
+	Escalated bool `json:"escalated"`
+}
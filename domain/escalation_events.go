@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	EventEscalationPolicyAdded   = "EscalationPolicyAdded"
+	EventEscalationPolicyRemoved = "EscalationPolicyRemoved"
+)
+
+type EscalationPolicyAdded struct {
+	PolicyID  string    `json:"policy_id"`
+	RealmID   string    `json:"realm_id"`
+	Name      string    `json:"name"`
+	Trigger   string    `json:"trigger"`
+	After     string    `json:"after,omitempty"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EscalationPolicyRemoved struct {
+	PolicyID string `json:"policy_id"`
+	RealmID  string `json:"realm_id"`
+}
@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const escalationPolicyStreamPrefix = "escalation-policy-"
+
+const (
+	EscalationTriggerOverdue    = "overdue"
+	EscalationTriggerStaleClaim = "stale_claim"
+)
+
+const (
+	EscalationActionBumpPriority = "bump_priority"
+	EscalationActionNotifyAdmins = "notify_admins"
+	EscalationActionUnclaim      = "unclaim"
+)
+
+type EscalationPolicyState struct {
+	PolicyID string
+	RealmID  string
+	Name     string
+	Trigger  string
+	After    string
+	Action   string
+	Exists   bool
+	Removed  bool
+}
+
+func RebuildEscalationPolicyState(events []core.Event) EscalationPolicyState {
+	var state EscalationPolicyState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventEscalationPolicyAdded:
+			var data EscalationPolicyAdded
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.PolicyID = data.PolicyID
+			state.RealmID = data.RealmID
+			state.Name = data.Name
+			state.Trigger = data.Trigger
+			state.After = data.After
+			state.Action = data.Action
+		case EventEscalationPolicyRemoved:
+			state.Removed = true
+		}
+	}
+	return state
+}
+
+func escalationPolicyStreamID(policyID string) string {
+	return escalationPolicyStreamPrefix + policyID
+}
+
+func generateEscalationPolicyID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate escalation policy ID: %w", err)
+	}
+	return "ep-" + hex.EncodeToString(b), nil
+}
+
+func readAndRebuildEscalationPolicyState(ctx context.Context, policyID string, store core.EventStore) (EscalationPolicyState, []core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, escalationPolicyStreamID(policyID), 0)
+	if err != nil {
+		return EscalationPolicyState{}, nil, err
+	}
+	return RebuildEscalationPolicyState(events), events, nil
+}
+
+func validateEscalationPolicy(cmd AddEscalationPolicy) error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return Rejectf(ErrInvalidCommand, "cannot add escalation policy: name is required")
+	}
+	switch cmd.Trigger {
+	case EscalationTriggerOverdue:
+	case EscalationTriggerStaleClaim:
+		if cmd.After == "" {
+			return Rejectf(ErrInvalidCommand, "cannot add escalation policy: after is required for %s", EscalationTriggerStaleClaim)
+		}
+	default:
+		return Rejectf(ErrInvalidCommand, "cannot add escalation policy: trigger must be %s or %s", EscalationTriggerOverdue, EscalationTriggerStaleClaim)
+	}
+	if cmd.After != "" {
+		// Policies share the SLA threshold format.
+		if _, err := ParseSLAThreshold(cmd.After); err != nil {
+			return Rejectf(ErrInvalidCommand, "cannot add escalation policy: after must be whole days (3d) or a duration (36h)")
+		}
+	}
+	switch cmd.Action {
+	case EscalationActionBumpPriority, EscalationActionNotifyAdmins, EscalationActionUnclaim:
+	default:
+		return Rejectf(ErrInvalidCommand, "cannot add escalation policy: action must be %s, %s or %s",
+			EscalationActionBumpPriority, EscalationActionNotifyAdmins, EscalationActionUnclaim)
+	}
+	if cmd.Action == EscalationActionUnclaim && cmd.Trigger != EscalationTriggerStaleClaim {
+		return Rejectf(ErrInvalidCommand, "cannot add escalation policy: %s needs the %s trigger", EscalationActionUnclaim, EscalationTriggerStaleClaim)
+	}
+	return nil
+}
+
+func HandleAddEscalationPolicy(ctx context.Context, cmd AddEscalationPolicy, store core.EventStore) (AddEscalationPolicyResult, error) {
+	if err := validateEscalationPolicy(cmd); err != nil {
+		return AddEscalationPolicyResult{}, err
+	}
+	if err := RequireActiveRealm(ctx, cmd.RealmID, store); err != nil {
+		return AddEscalationPolicyResult{}, err
+	}
+
+	policyID, err := generateEscalationPolicyID()
+	if err != nil {
+		return AddEscalationPolicyResult{}, err
+	}
+
+	added := EscalationPolicyAdded{
+		PolicyID:  policyID,
+		RealmID:   cmd.RealmID,
+		Name:      strings.TrimSpace(cmd.Name),
+		Trigger:   cmd.Trigger,
+		After:     cmd.After,
+		Action:    cmd.Action,
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, escalationPolicyStreamID(policyID), 0, []core.EventData{
+		{EventType: EventEscalationPolicyAdded, Data: added},
+	})
+	if err != nil {
+		return AddEscalationPolicyResult{}, err
+	}
+
+	return AddEscalationPolicyResult{PolicyID: policyID}, nil
+}
+
+// HandleRemoveEscalationPolicy removes a policy from its realm. A policy
+// belonging to another realm is reported as not found.
+func HandleRemoveEscalationPolicy(ctx context.Context, cmd RemoveEscalationPolicy, store core.EventStore) error {
+	state, events, err := readAndRebuildEscalationPolicyState(ctx, cmd.PolicyID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists || state.Removed || state.RealmID != cmd.RealmID {
+		return &core.NotFoundError{Entity: "escalation policy", ID: cmd.PolicyID}
+	}
+
+	removed := EscalationPolicyRemoved{PolicyID: cmd.PolicyID, RealmID: cmd.RealmID}
+	_, err = store.Append(ctx, AdminRealmID, escalationPolicyStreamID(cmd.PolicyID), len(events), []core.EventData{
+		{EventType: EventEscalationPolicyRemoved, Data: removed},
+	})
+	return err
+}
+
+// EscalationDeadline returns when the policy fires for a rune in state, and
+// the occurrence it fires for. ok is false when the trigger does not apply,
+// such as an overdue policy for a rune without a due date.
+func EscalationDeadline(policy EscalationPolicyState, state RuneState) (deadline time.Time, occurrence string, ok bool) {
+	if state.Status != "draft" && state.Status != "open" && state.Status != "claimed" {
+		return time.Time{}, "", false
+	}
+	var after time.Duration
+	if policy.After != "" {
+		after, _ = ParseSLAThreshold(policy.After)
+	}
+	switch policy.Trigger {
+	case EscalationTriggerOverdue:
+		due, err := time.Parse(time.DateOnly, state.DueDate)
+		if err != nil {
+			return time.Time{}, "", false
+		}
+		return due.AddDate(0, 0, 1).Add(after), "due:" + state.DueDate, true
+	case EscalationTriggerStaleClaim:
+		if state.Status != "claimed" || state.ClaimedAt.IsZero() {
+			return time.Time{}, "", false
+		}
+		return state.ClaimedAt.Add(after), "claim:" + state.ClaimedAt.UTC().Format(time.RFC3339Nano), true
+	}
+	return time.Time{}, "", false
+}
+
+// HandleEscalateRune fires a policy for a rune whose deadline has passed,
+// appending RuneEscalated and the action's change together. It does nothing
+// if the policy already fired for the current occurrence, so the scheduler
+// can retry safely.
+func HandleEscalateRune(ctx context.Context, realmID string, cmd EscalateRune, store core.EventStore) (EscalateRuneResult, error) {
+	policy, _, err := readAndRebuildEscalationPolicyState(ctx, cmd.PolicyID, store)
+	if err != nil {
+		return EscalateRuneResult{}, err
+	}
+	if !policy.Exists || policy.Removed || policy.RealmID != realmID {
+		return EscalateRuneResult{}, &core.NotFoundError{Entity: "escalation policy", ID: cmd.PolicyID}
+	}
+
+	state, version, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
+	if err != nil {
+		return EscalateRuneResult{}, err
+	}
+	if !state.Exists {
+		return EscalateRuneResult{}, &core.NotFoundError{Entity: "rune", ID: cmd.RuneID}
+	}
+	deadline, occurrence, ok := EscalationDeadline(policy, state)
+	if !ok {
+		return EscalateRuneResult{}, Rejectf(ErrInvalidCommand, "escalation policy %q does not apply to rune %q", cmd.PolicyID, cmd.RuneID)
+	}
+	if core.ClockFromContext(ctx).Now().Before(deadline) {
+		return EscalateRuneResult{}, Rejectf(ErrInvalidCommand, "rune %q is not due for escalation until %s", cmd.RuneID, deadline.UTC().Format(time.RFC3339))
+	}
+	if state.Escalations[cmd.PolicyID] == occurrence {
+		return EscalateRuneResult{}, nil
+	}
+
+	escalated := RuneEscalated{
+		ID:         cmd.RuneID,
+		PolicyID:   cmd.PolicyID,
+		PolicyName: policy.Name,
+		Trigger:    policy.Trigger,
+		Action:     policy.Action,
+		Occurrence: occurrence,
+	}
+	batch := []core.EventData{{EventType: EventRuneEscalated, Data: escalated}}
+	switch policy.Action {
+	case EscalationActionBumpPriority:
+		if state.Priority > 0 {
+			priority := state.Priority - 1
+			batch = append(batch, core.EventData{EventType: EventRuneUpdated, Data: RuneUpdated{ID: cmd.RuneID, Priority: &priority}})
+		}
+	case EscalationActionUnclaim:
+		batch = append(batch, core.EventData{EventType: EventRuneUnclaimed, Data: RuneUnclaimed{ID: cmd.RuneID}})
+	}
+
+	if _, err := store.Append(ctx, realmID, runeStreamID(cmd.RuneID), version, batch); err != nil {
+		return EscalateRuneResult{}, err
+	}
+	return EscalateRuneResult{Escalated: true}, nil
+}
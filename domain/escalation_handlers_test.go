@@ -0,0 +1,328 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestHandleAddEscalationPolicy(t *testing.T) {
+	t.Run("adds policy to realm", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+
+		// When
+		tc.add_policy_is_handled(AddEscalationPolicy{
+			RealmID: "bf-r1", Name: " Stale claims ", Trigger: EscalationTriggerStaleClaim,
+			After: "3d", Action: EscalationActionUnclaim,
+		})
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^ep-[0-9a-f]{8}$`, tc.result.PolicyID)
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, AdminRealmID, call.realmID)
+		assert.Equal(t, "escalation-policy-"+tc.result.PolicyID, call.streamID)
+		added, ok := call.events[0].Data.(EscalationPolicyAdded)
+		require.True(t, ok)
+		assert.Equal(t, "Stale claims", added.Name)
+		assert.Equal(t, "3d", added.After)
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		cases := map[string]struct {
+			cmd      AddEscalationPolicy
+			expected string
+		}{
+			"missing name":        {AddEscalationPolicy{Trigger: EscalationTriggerOverdue, Action: EscalationActionNotifyAdmins}, "name is required"},
+			"unknown trigger":     {AddEscalationPolicy{Name: "p", Trigger: "blocked", Action: EscalationActionNotifyAdmins}, "trigger must be overdue or stale_claim"},
+			"stale without after": {AddEscalationPolicy{Name: "p", Trigger: EscalationTriggerStaleClaim, Action: EscalationActionUnclaim}, "after is required"},
+			"bad after":           {AddEscalationPolicy{Name: "p", Trigger: EscalationTriggerOverdue, After: "soon", Action: EscalationActionNotifyAdmins}, "after must be"},
+			"unknown action":      {AddEscalationPolicy{Name: "p", Trigger: EscalationTriggerOverdue, Action: "page"}, "action must be"},
+			"unclaim overdue":     {AddEscalationPolicy{Name: "p", Trigger: EscalationTriggerOverdue, Action: EscalationActionUnclaim}, "unclaim needs the stale_claim trigger"},
+		}
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				tc := newEscalationHandlerTestContext(t)
+
+				// Given
+				tc.existing_realm("bf-r1")
+				c.cmd.RealmID = "bf-r1"
+
+				// When
+				tc.add_policy_is_handled(c.cmd)
+
+				// Then
+				tc.error_contains(c.expected)
+				assert.Empty(t, tc.eventStore.appendedCalls)
+			})
+		}
+	})
+}
+
+func TestHandleRemoveEscalationPolicy(t *testing.T) {
+	t.Run("removes existing policy", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-a1b2c3d4", "bf-r1", EscalationTriggerOverdue, "", EscalationActionNotifyAdmins)
+
+		// When
+		tc.remove_policy_is_handled("bf-r1", "ep-a1b2c3d4")
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, 1, call.expectedVersion)
+		assert.Equal(t, EscalationPolicyRemoved{PolicyID: "ep-a1b2c3d4", RealmID: "bf-r1"}, call.events[0].Data)
+	})
+
+	t.Run("returns not found for policy in another realm", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-a1b2c3d4", "bf-r1", EscalationTriggerOverdue, "", EscalationActionNotifyAdmins)
+
+		// When
+		tc.remove_policy_is_handled("bf-r2", "ep-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("escalation policy", "ep-a1b2c3d4")
+	})
+}
+
+func TestHandleEscalateRune(t *testing.T) {
+	t.Run("bumps priority of an overdue rune", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r1", EscalationTriggerOverdue, "", EscalationActionBumpPriority)
+		tc.existing_rune("bf-a1", 2, "2026-03-01")
+		tc.now_is("2026-03-02T00:00:00Z")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.escalated.Escalated)
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, "rune-bf-a1", call.streamID)
+		require.Len(t, call.events, 2)
+		assert.Equal(t, RuneEscalated{
+			ID: "bf-a1", PolicyID: "ep-1", PolicyName: "p", Trigger: EscalationTriggerOverdue,
+			Action: EscalationActionBumpPriority, Occurrence: "due:2026-03-01",
+		}, call.events[0].Data)
+		updated, ok := call.events[1].Data.(RuneUpdated)
+		require.True(t, ok)
+		require.NotNil(t, updated.Priority)
+		assert.Equal(t, 1, *updated.Priority)
+	})
+
+	t.Run("does nothing when already escalated for the due date", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r1", EscalationTriggerOverdue, "", EscalationActionBumpPriority)
+		tc.existing_rune("bf-a1", 2, "2026-03-01")
+		tc.rune_was_escalated("bf-a1", "ep-1", "due:2026-03-01")
+		tc.now_is("2026-03-05T00:00:00Z")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.no_error()
+		assert.False(t, tc.escalated.Escalated)
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("rejects a rune that is not yet due", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r1", EscalationTriggerOverdue, "1d", EscalationActionNotifyAdmins)
+		tc.existing_rune("bf-a1", 2, "2026-03-01")
+		tc.now_is("2026-03-02T12:00:00Z")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.error_contains("not due for escalation until 2026-03-03T00:00:00Z")
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("unclaims a stale claim", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r1", EscalationTriggerStaleClaim, "3d", EscalationActionUnclaim)
+		tc.existing_rune("bf-a1", 2, "")
+		tc.rune_was_claimed("bf-a1", "2026-03-01T09:00:00Z")
+		tc.now_is("2026-03-04T09:00:00Z")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		require.Len(t, call.events, 2)
+		assert.Equal(t, "claim:2026-03-01T09:00:00Z", call.events[0].Data.(RuneEscalated).Occurrence)
+		assert.Equal(t, RuneUnclaimed{ID: "bf-a1"}, call.events[1].Data)
+	})
+
+	t.Run("rejects a stale claim policy for an unclaimed rune", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r1", EscalationTriggerStaleClaim, "3d", EscalationActionUnclaim)
+		tc.existing_rune("bf-a1", 2, "")
+		tc.now_is("2026-03-04T09:00:00Z")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.error_contains("does not apply")
+	})
+
+	t.Run("returns not found for policy in another realm", func(t *testing.T) {
+		tc := newEscalationHandlerTestContext(t)
+
+		// Given
+		tc.existing_policy("ep-1", "bf-r2", EscalationTriggerOverdue, "", EscalationActionNotifyAdmins)
+		tc.existing_rune("bf-a1", 2, "2026-03-01")
+
+		// When
+		tc.escalate_is_handled("bf-r1", "bf-a1", "ep-1")
+
+		// Then
+		tc.error_is_not_found("escalation policy", "ep-1")
+	})
+}
+
+// --- Test Context ---
+
+type escalationHandlerTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	result    AddEscalationPolicyResult
+	escalated EscalateRuneResult
+	err       error
+}
+
+func newEscalationHandlerTestContext(t *testing.T) *escalationHandlerTestContext {
+	t.Helper()
+	return &escalationHandlerTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *escalationHandlerTestContext) existing_realm(realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: realmID, Name: "Realm"}),
+	}
+}
+
+func (tc *escalationHandlerTestContext) existing_policy(policyID, realmID, trigger, after, action string) {
+	tc.t.Helper()
+	tc.eventStore.streams["escalation-policy-"+policyID] = []core.Event{
+		makeEvent(EventEscalationPolicyAdded, EscalationPolicyAdded{
+			PolicyID: policyID, RealmID: realmID, Name: "p", Trigger: trigger, After: after, Action: action,
+		}),
+	}
+}
+
+func (tc *escalationHandlerTestContext) existing_rune(runeID string, priority int, dueDate string) {
+	tc.t.Helper()
+	tc.eventStore.streams["rune-"+runeID] = []core.Event{
+		makeEvent(EventRuneCreated, RuneCreated{ID: runeID, Title: "Rune", Priority: priority, DueDate: dueDate}),
+		makeEvent(EventRuneForged, RuneForged{ID: runeID}),
+	}
+}
+
+func (tc *escalationHandlerTestContext) rune_was_claimed(runeID, at string) {
+	tc.t.Helper()
+	evt := makeEvent(EventRuneClaimed, RuneClaimed{ID: runeID, Claimant: "odin"})
+	evt.Timestamp = tc.parse_time(at)
+	tc.eventStore.streams["rune-"+runeID] = append(tc.eventStore.streams["rune-"+runeID], evt)
+}
+
+func (tc *escalationHandlerTestContext) rune_was_escalated(runeID, policyID, occurrence string) {
+	tc.t.Helper()
+	tc.eventStore.streams["rune-"+runeID] = append(tc.eventStore.streams["rune-"+runeID],
+		makeEvent(EventRuneEscalated, RuneEscalated{ID: runeID, PolicyID: policyID, Occurrence: occurrence}))
+}
+
+func (tc *escalationHandlerTestContext) now_is(at string) {
+	tc.t.Helper()
+	tc.ctx = core.ContextWithClock(tc.ctx, core.FixedClock(tc.parse_time(at)))
+}
+
+func (tc *escalationHandlerTestContext) parse_time(value string) time.Time {
+	tc.t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	require.NoError(tc.t, err)
+	return at
+}
+
+// --- When ---
+
+func (tc *escalationHandlerTestContext) add_policy_is_handled(cmd AddEscalationPolicy) {
+	tc.t.Helper()
+	tc.result, tc.err = HandleAddEscalationPolicy(tc.ctx, cmd, tc.eventStore)
+}
+
+func (tc *escalationHandlerTestContext) remove_policy_is_handled(realmID, policyID string) {
+	tc.t.Helper()
+	tc.err = HandleRemoveEscalationPolicy(tc.ctx, RemoveEscalationPolicy{RealmID: realmID, PolicyID: policyID}, tc.eventStore)
+}
+
+func (tc *escalationHandlerTestContext) escalate_is_handled(realmID, runeID, policyID string) {
+	tc.t.Helper()
+	tc.escalated, tc.err = HandleEscalateRune(tc.ctx, realmID, EscalateRune{RuneID: runeID, PolicyID: policyID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *escalationHandlerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *escalationHandlerTestContext) error_contains(substring string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substring)
+}
+
+func (tc *escalationHandlerTestContext) error_is_not_found(entity, id string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, entity, nfe.Entity)
+	assert.Equal(tc.t, id, nfe.ID)
+}
@@ -1,17 +1,23 @@
 package domain
 
+import "time"
+
 const (
-	EventRuneCreated       = "RuneCreated"
-	EventRuneUpdated       = "RuneUpdated"
-	EventRuneClaimed       = "RuneClaimed"
-	EventRuneFulfilled     = "RuneFulfilled"
-	EventRuneForged        = "RuneForged"
-	EventRuneSealed        = "RuneSealed"
-	EventDependencyAdded   = "DependencyAdded"
-	EventDependencyRemoved = "DependencyRemoved"
-	EventRuneNoted         = "RuneNoted"
-	EventRuneUnclaimed     = "RuneUnclaimed"
-	EventRuneShattered     = "RuneShattered"
+	EventRuneCreated        = "RuneCreated"
+	EventRuneUpdated        = "RuneUpdated"
+	EventRuneClaimed        = "RuneClaimed"
+	EventRuneFulfilled      = "RuneFulfilled"
+	EventRuneForged         = "RuneForged"
+	EventRuneSealed         = "RuneSealed"
+	EventDependencyAdded    = "DependencyAdded"
+	EventDependencyRemoved  = "DependencyRemoved"
+	EventRuneNoted          = "RuneNoted"
+	EventRuneUnclaimed      = "RuneUnclaimed"
+	EventRuneShattered      = "RuneShattered"
+	EventRuneChildAllocated = "RuneChildAllocated"
+	EventRuneIDAllocated    = "RuneIDAllocated"
+	EventSLABreached        = "SLABreached"
+	EventRuneEscalated      = "RuneEscalated"
 )
 
 const (
@@ -35,6 +41,22 @@ type RuneCreated struct {
 	ParentID    string `json:"parent_id,omitempty"`
 	Branch      string `json:"branch,omitempty"`
 	Type        string `json:"type,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+// RuneChildAllocated reserves a child ID on the parent's stream, so two
+// concurrent creates under one parent cannot be given the same ID.
+type RuneChildAllocated struct {
+	ID       string `json:"id"`
+	ChildID  string `json:"child_id"`
+	Sequence int    `json:"sequence"`
+}
+
+// RuneIDAllocated takes the next number of a realm's rune ID sequence, so
+// two concurrent creates cannot be given the same sequential ID.
+type RuneIDAllocated struct {
+	ID       string `json:"id"`
+	Sequence int    `json:"sequence"`
 }
 
 type RuneForged struct {
@@ -47,11 +69,18 @@ type RuneUpdated struct {
 	Description *string `json:"description,omitempty"`
 	Priority    *int    `json:"priority,omitempty"`
 	Branch      *string `json:"branch,omitempty"`
+	DueDate     *string `json:"due_date,omitempty"`
+	// Actor names the policy that made the change; it is empty for
+	// changes made by people.
+	Actor string `json:"actor,omitempty"`
 }
 
 type RuneClaimed struct {
 	ID       string `json:"id"`
 	Claimant string `json:"claimant"`
+	// AccountID is the claimant's account, recorded when the realm
+	// requires claimants to be accounts.
+	AccountID string `json:"account_id,omitempty"`
 }
 
 type RuneFulfilled struct {
@@ -63,6 +92,29 @@ type RuneSealed struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// SLABreached records that a rune stayed in Status longer than the realm's
+// Threshold allows. It is appended at most once each time the rune enters
+// a status.
+type SLABreached struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Threshold string    `json:"threshold"`
+	EnteredAt time.Time `json:"entered_at"`
+}
+
+// RuneEscalated records that an escalation policy fired for a rune. Any
+// change the action made, such as a new priority, follows it in the same
+// append. Occurrence identifies what was escalated (the due date, or the
+// claim), so each policy fires once per occurrence.
+type RuneEscalated struct {
+	ID         string `json:"id"`
+	PolicyID   string `json:"policy_id"`
+	PolicyName string `json:"policy_name"`
+	Trigger    string `json:"trigger"`
+	Action     string `json:"action"`
+	Occurrence string `json:"occurrence"`
+}
+
 type DependencyAdded struct {
 	RuneID       string `json:"rune_id"`
 	TargetID     string `json:"target_id"`
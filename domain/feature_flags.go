@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// FeatureSettingPrefix starts the realm settings that turn a feature flag on
+// or off for the realm, such as "feature.strict_blocking" = "true". Flags
+// not overridden take their default.
+const FeatureSettingPrefix = "feature."
+
+// Feature flag names.
+const (
+	// FeatureStrictBlocking rejects claims on runes whose blockers are not
+	// all fulfilled.
+	FeatureStrictBlocking = "strict_blocking"
+)
+
+// FeatureFlag defines an experimental feature a realm can turn on or off.
+type FeatureFlag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+}
+
+// FeatureFlags lists the defined flags, ordered by name.
+var FeatureFlags = []FeatureFlag{
+	{
+		Name:        FeatureStrictBlocking,
+		Description: "Reject claims on runes that are blocked by runes not yet fulfilled",
+	},
+}
+
+// FeatureState is a flag's effective value in a realm.
+type FeatureState struct {
+	FeatureFlag
+	Enabled bool `json:"enabled"`
+	// Overridden is set when the realm's settings override the default.
+	Overridden bool `json:"overridden"`
+}
+
+func lookupFeatureFlag(name string) (FeatureFlag, bool) {
+	for _, flag := range FeatureFlags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return FeatureFlag{}, false
+}
+
+// FeatureStates returns every defined flag's value in a realm with the given
+// settings. Overrides that do not parse are ignored.
+func FeatureStates(settings map[string]string) []FeatureState {
+	states := make([]FeatureState, 0, len(FeatureFlags))
+	for _, flag := range FeatureFlags {
+		state := FeatureState{FeatureFlag: flag, Enabled: flag.Default}
+		if value, ok := settings[FeatureSettingPrefix+flag.Name]; ok {
+			if enabled, err := strconv.ParseBool(value); err == nil {
+				state.Enabled, state.Overridden = enabled, true
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// FeatureEnabled reports whether the named flag is on in a realm with the
+// given settings. Unknown flags are off.
+func FeatureEnabled(settings map[string]string, name string) bool {
+	for _, state := range FeatureStates(settings) {
+		if state.Name == name {
+			return state.Enabled
+		}
+	}
+	return false
+}
+
+func validateFeatureSetting(key, value string) error {
+	name := strings.TrimPrefix(key, FeatureSettingPrefix)
+	if _, ok := lookupFeatureFlag(name); !ok {
+		names := make([]string, len(FeatureFlags))
+		for i, flag := range FeatureFlags {
+			names[i] = flag.Name
+		}
+		return Rejectf(ErrInvalidCommand, "unknown feature flag %q: must be one of %s", name, strings.Join(names, ", "))
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		return Rejectf(ErrInvalidCommand, "feature flag %q must be true or false, not %q", name, value)
+	}
+	return nil
+}
+
+// checkStrictBlocking rejects claiming a rune while any of the runes blocking
+// it is not fulfilled, when the realm has strict blocking on. Blockers are
+// read from the rune streams, so a blocker fulfilled just before is seen.
+func checkStrictBlocking(ctx context.Context, realmID, runeID string, blockedBy []string, store core.EventStore) error {
+	realm, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return err
+	}
+	if !FeatureEnabled(realm.Settings, FeatureStrictBlocking) {
+		return nil
+	}
+	var open []string
+	for _, blockerID := range blockedBy {
+		blocker, _, err := readAndRebuild(ctx, realmID, blockerID, store)
+		if err != nil {
+			return err
+		}
+		if blocker.Exists && blocker.Status != "fulfilled" && blocker.Status != "shattered" {
+			open = append(open, blockerID)
+		}
+	}
+	if len(open) > 0 {
+		return &RuleError{
+			Rule:    ErrBlocked,
+			Details: map[string]any{"blocked_by": open},
+			msg:     fmt.Sprintf("cannot claim rune %q: blocked by %s", runeID, strings.Join(open, ", ")),
+		}
+	}
+	return nil
+}
@@ -2,13 +2,15 @@ package domain
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/validation"
 )
 
 const runeStreamPrefix = "rune-"
@@ -24,11 +26,40 @@ type RuneState struct {
 	Priority    int
 	Type        string
 	Exists      bool
+	// ChildSequence is the highest child sequence number allocated on the
+	// rune's stream.
+	ChildSequence int
+	// ClaimantAccountID is the claimant's account when the claim was
+	// checked against accounts.
+	ClaimantAccountID string
+	// SLABreached is set once an SLA breach is recorded for the current
+	// status, and cleared when the status changes.
+	SLABreached bool
+	DueDate     string
+	ClaimedAt   time.Time
+	// Escalations maps each escalation policy that fired to the occurrence
+	// it fired for.
+	Escalations map[string]string
+	// PrioritySetAt is when the priority was last set other than by the
+	// priority aging policy, and AgingSteps how many steps that policy has
+	// raised it by since.
+	PrioritySetAt time.Time
+	AgingSteps    int
+	// BlockedBy lists the runes that currently block this one, in the
+	// order the dependencies were added.
+	BlockedBy []string
 }
 
 func RebuildRuneState(events []core.Event) RuneState {
 	var state RuneState
+	state.apply(events)
+	return state
+}
+
+// apply folds events from the rune's stream into the state.
+func (state *RuneState) apply(events []core.Event) {
 	for _, evt := range events {
+		status := state.Status
 		switch evt.EventType {
 		case EventRuneCreated:
 			var data RuneCreated
@@ -38,12 +69,14 @@ func RebuildRuneState(events []core.Event) RuneState {
 			state.Title = data.Title
 			state.Description = data.Description
 			state.Priority = data.Priority
+			state.PrioritySetAt = evt.Timestamp
 			state.ParentID = data.ParentID
 			state.Branch = data.Branch
 			state.Type = data.Type
 			if state.Type == "" {
 				state.Type = "rune"
 			}
+			state.DueDate = data.DueDate
 			state.Status = "draft"
 		case EventRuneUpdated:
 			var data RuneUpdated
@@ -55,19 +88,32 @@ func RebuildRuneState(events []core.Event) RuneState {
 				state.Description = *data.Description
 			}
 			if data.Priority != nil {
+				if data.Actor == PriorityAgingActor {
+					state.AgingSteps += state.Priority - *data.Priority
+				} else {
+					state.PrioritySetAt = evt.Timestamp
+					state.AgingSteps = 0
+				}
 				state.Priority = *data.Priority
 			}
 			if data.Branch != nil {
 				state.Branch = *data.Branch
 			}
+			if data.DueDate != nil {
+				state.DueDate = *data.DueDate
+			}
 		case EventRuneClaimed:
 			var data RuneClaimed
 			_ = json.Unmarshal(evt.Data, &data)
 			state.Status = "claimed"
 			state.Claimant = data.Claimant
+			state.ClaimantAccountID = data.AccountID
+			state.ClaimedAt = evt.Timestamp
 		case EventRuneUnclaimed:
 			state.Status = "open"
 			state.Claimant = ""
+			state.ClaimantAccountID = ""
+			state.ClaimedAt = time.Time{}
 		case EventRuneFulfilled:
 			state.Status = "fulfilled"
 		case EventRuneForged:
@@ -76,35 +122,56 @@ func RebuildRuneState(events []core.Event) RuneState {
 			state.Status = "sealed"
 		case EventRuneShattered:
 			state.Status = "shattered"
+		case EventRuneChildAllocated:
+			var data RuneChildAllocated
+			_ = json.Unmarshal(evt.Data, &data)
+			state.ChildSequence = max(state.ChildSequence, data.Sequence)
+		case EventSLABreached:
+			state.SLABreached = true
+		case EventRuneEscalated:
+			var data RuneEscalated
+			_ = json.Unmarshal(evt.Data, &data)
+			if state.Escalations == nil {
+				state.Escalations = make(map[string]string)
+			}
+			state.Escalations[data.PolicyID] = data.Occurrence
+		case EventDependencyAdded:
+			var data DependencyAdded
+			_ = json.Unmarshal(evt.Data, &data)
+			if data.Relationship == RelBlockedBy && !slices.Contains(state.BlockedBy, data.TargetID) {
+				state.BlockedBy = append(state.BlockedBy, data.TargetID)
+			}
+		case EventDependencyRemoved:
+			var data DependencyRemoved
+			_ = json.Unmarshal(evt.Data, &data)
+			if data.Relationship == RelBlockedBy {
+				state.BlockedBy = slices.DeleteFunc(state.BlockedBy, func(id string) bool { return id == data.TargetID })
+			}
+		}
+		if state.Status != status {
+			state.SLABreached = false
 		}
 	}
-	return state
 }
 
 func runeStreamID(runeID string) string {
 	return runeStreamPrefix + runeID
 }
 
-func generateRuneID() (string, error) {
-	b := make([]byte, 2)
-	if _, err := rand.Read(b); err != nil {
-		return "", fmt.Errorf("generate rune ID: %w", err)
-	}
-	return "bf-" + hex.EncodeToString(b), nil
+// RuneStreamID returns the ID of the stream holding a rune's events.
+func RuneStreamID(runeID string) string {
+	return runeStreamID(runeID)
 }
 
-func readAndRebuild(ctx context.Context, realmID string, runeID string, store core.EventStore) (RuneState, []core.Event, error) {
-	streamID := runeStreamID(runeID)
-	events, err := store.ReadStream(ctx, realmID, streamID, 0)
-	if err != nil {
-		return RuneState{}, nil, err
-	}
-	state := RebuildRuneState(events)
-	return state, events, nil
+// IsRuneStream reports whether streamID holds a rune's events.
+func IsRuneStream(streamID string) bool {
+	return strings.HasPrefix(streamID, runeStreamPrefix)
 }
 
 func HandleCreateRune(ctx context.Context, realmID string, cmd CreateRune, store core.EventStore, projStore core.ProjectionStore) (RuneCreated, error) {
-	var runeID string
+	if err := validateCreateRune(cmd); err != nil {
+		return RuneCreated{}, err
+	}
 
 	var branch string
 
@@ -117,10 +184,10 @@ func HandleCreateRune(ctx context.Context, realmID string, cmd CreateRune, store
 			return RuneCreated{}, &core.NotFoundError{Entity: "rune", ID: cmd.ParentID}
 		}
 		if parentState.Status == "sealed" {
-			return RuneCreated{}, fmt.Errorf("cannot create child of sealed rune %q", cmd.ParentID)
+			return RuneCreated{}, Rejectf(ErrRuneSealed, "cannot create child of sealed rune %q", cmd.ParentID)
 		}
 		if parentState.Status == "shattered" {
-			return RuneCreated{}, fmt.Errorf("cannot create child of shattered rune %q", cmd.ParentID)
+			return RuneCreated{}, Rejectf(ErrRuneShattered, "cannot create child of shattered rune %q", cmd.ParentID)
 		}
 
 		if cmd.Branch != nil {
@@ -128,29 +195,32 @@ func HandleCreateRune(ctx context.Context, realmID string, cmd CreateRune, store
 		} else {
 			branch = parentState.Branch
 		}
-
-		var childCount int
-		err = projStore.Get(ctx, realmID, "RuneChildCount", cmd.ParentID, &childCount)
-		if err != nil {
-			if !isNotFoundError(err) {
-				return RuneCreated{}, err
-			}
-			childCount = 0
-		}
-		runeID = fmt.Sprintf("%s.%d", cmd.ParentID, childCount+1)
 	} else {
 		if cmd.Branch == nil {
-			return RuneCreated{}, fmt.Errorf("branch is required for top-level runes")
+			return RuneCreated{}, Rejectf(ErrBranchRequired, "branch is required for top-level runes")
 		}
 		branch = *cmd.Branch
+	}
 
-		var err error
-		runeID, err = generateRuneID()
-		if err != nil {
+	// The policy is checked before an ID is allocated, so a rejected create
+	// appends nothing.
+	if cmd.Branch != nil {
+		if err := checkBranchPolicy(ctx, realmID, branch, store); err != nil {
 			return RuneCreated{}, err
 		}
 	}
 
+	var runeID string
+	var err error
+	if cmd.ParentID != "" {
+		runeID, err = allocateChildID(ctx, realmID, cmd.ParentID, store, projStore)
+	} else {
+		runeID, err = newRuneID(ctx, realmID, store)
+	}
+	if err != nil {
+		return RuneCreated{}, err
+	}
+
 	runeType := cmd.Type
 	if runeType == "" {
 		runeType = "rune"
@@ -163,10 +233,11 @@ func HandleCreateRune(ctx context.Context, realmID string, cmd CreateRune, store
 		ParentID:    cmd.ParentID,
 		Branch:      branch,
 		Type:        runeType,
+		DueDate:     cmd.DueDate,
 	}
 
 	streamID := runeStreamID(runeID)
-	_, err := store.Append(ctx, realmID, streamID, 0, []core.EventData{
+	_, err = store.Append(ctx, realmID, streamID, 0, []core.EventData{
 		{EventType: EventRuneCreated, Data: created},
 	})
 	if err != nil {
@@ -177,91 +248,120 @@ func HandleCreateRune(ctx context.Context, realmID string, cmd CreateRune, store
 }
 
 func HandleUpdateRune(ctx context.Context, realmID string, cmd UpdateRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
-		return err
-	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
-	}
-	if state.Status == "sealed" {
-		return fmt.Errorf("cannot update sealed rune %q", cmd.ID)
-	}
-	if state.Status == "shattered" {
-		return fmt.Errorf("cannot update shattered rune %q", cmd.ID)
-	}
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status == "sealed" {
+			return Rejectf(ErrRuneSealed, "cannot update sealed rune %q", cmd.ID)
+		}
+		if state.Status == "shattered" {
+			return Rejectf(ErrRuneShattered, "cannot update shattered rune %q", cmd.ID)
+		}
+		if err := validateUpdateRune(cmd); err != nil {
+			return err
+		}
+		if cmd.Branch != nil {
+			if err := checkBranchPolicy(ctx, realmID, *cmd.Branch, store); err != nil {
+				return err
+			}
+		}
 
-	updated := RuneUpdated(cmd)
+		updated := RuneUpdated{
+			ID:          cmd.ID,
+			Title:       cmd.Title,
+			Description: cmd.Description,
+			Priority:    cmd.Priority,
+			Branch:      cmd.Branch,
+			DueDate:     cmd.DueDate,
+		}
 
-	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneUpdated, Data: updated},
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneUpdated, Data: updated},
+		})
+		return err
 	})
-	return err
 }
 
-func HandleClaimRune(ctx context.Context, realmID string, cmd ClaimRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
-		return err
-	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
-	}
-	if state.Status == "draft" {
-		return fmt.Errorf("cannot claim draft rune %q", cmd.ID)
-	}
-	if state.Status == "sealed" {
-		return fmt.Errorf("cannot claim sealed rune %q", cmd.ID)
-	}
-	if state.Status == "shattered" {
-		return fmt.Errorf("cannot claim shattered rune %q", cmd.ID)
-	}
-	if state.Status == "claimed" {
-		return fmt.Errorf("rune %q is already claimed by %q", cmd.ID, state.Claimant)
-	}
-	if state.Status == "fulfilled" {
-		return fmt.Errorf("cannot claim fulfilled rune %q", cmd.ID)
-	}
+func HandleClaimRune(ctx context.Context, realmID string, cmd ClaimRune, store core.EventStore, projStore core.ProjectionStore) error {
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status == "draft" {
+			return Rejectf(ErrRuneDraft, "cannot claim draft rune %q", cmd.ID)
+		}
+		if state.Status == "sealed" {
+			return Rejectf(ErrRuneSealed, "cannot claim sealed rune %q", cmd.ID)
+		}
+		if state.Status == "shattered" {
+			return Rejectf(ErrRuneShattered, "cannot claim shattered rune %q", cmd.ID)
+		}
+		if state.Status == "claimed" {
+			return Rejectf(ErrAlreadyClaimed, "rune %q is already claimed by %q", cmd.ID, state.Claimant)
+		}
+		if state.Status == "fulfilled" {
+			return Rejectf(ErrRuneFulfilled, "cannot claim fulfilled rune %q", cmd.ID)
+		}
+		if err := checkStrictBlocking(ctx, realmID, cmd.ID, state.BlockedBy, store); err != nil {
+			return err
+		}
 
-	claimed := RuneClaimed(cmd)
+		accountID, err := resolveClaimant(ctx, realmID, cmd.Claimant, store, projStore)
+		if err != nil {
+			return err
+		}
 
-	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneClaimed, Data: claimed},
+		claimed := RuneClaimed{ID: cmd.ID, Claimant: cmd.Claimant, AccountID: accountID}
+
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneClaimed, Data: claimed},
+		})
+		return err
 	})
-	return err
 }
 
 func HandleUnclaimRune(ctx context.Context, realmID string, cmd UnclaimRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
-		return err
-	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
-	}
-	if state.Status == "sealed" {
-		return fmt.Errorf("cannot unclaim sealed rune %q", cmd.ID)
-	}
-	if state.Status == "fulfilled" {
-		return fmt.Errorf("cannot unclaim fulfilled rune %q", cmd.ID)
-	}
-	if state.Status != "claimed" {
-		return fmt.Errorf("cannot unclaim rune %q: not claimed", cmd.ID)
-	}
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status == "sealed" {
+			return Rejectf(ErrRuneSealed, "cannot unclaim sealed rune %q", cmd.ID)
+		}
+		if state.Status == "fulfilled" {
+			return Rejectf(ErrRuneFulfilled, "cannot unclaim fulfilled rune %q", cmd.ID)
+		}
+		if state.Status != "claimed" {
+			return Rejectf(ErrNotClaimed, "cannot unclaim rune %q: not claimed", cmd.ID)
+		}
 
-	unclaimed := RuneUnclaimed(cmd)
+		unclaimed := RuneUnclaimed(cmd)
 
-	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneUnclaimed, Data: unclaimed},
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneUnclaimed, Data: unclaimed},
+		})
+		return err
 	})
-	return err
 }
 
 func HandleForgeRune(ctx context.Context, realmID string, cmd ForgeRune, store core.EventStore, projStore core.ProjectionStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+	state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
 	if err != nil {
 		return err
 	}
@@ -277,24 +377,23 @@ func HandleForgeRune(ctx context.Context, realmID string, cmd ForgeRune, store c
 
 	forged := RuneForged(cmd)
 	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
+	_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
 		{EventType: EventRuneForged, Data: forged},
 	})
 	if err != nil {
 		return err
 	}
 
-	var childCount int
-	err = projStore.Get(ctx, realmID, "RuneChildCount", cmd.ID, &childCount)
+	childCount, err := lastChildSequence(ctx, realmID, state, projStore)
 	if err != nil {
-		if !isNotFoundError(err) {
-			return err
-		}
-		childCount = 0
+		return err
 	}
 	for i := 1; i <= childCount; i++ {
 		childID := fmt.Sprintf("%s.%d", cmd.ID, i)
-		if err := HandleForgeRune(ctx, realmID, ForgeRune{ID: childID}, store, projStore); err != nil {
+		err := HandleForgeRune(ctx, realmID, ForgeRune{ID: childID}, store, projStore)
+		// A sequence number can be allocated without its child being
+		// created, if the create failed after allocating it.
+		if err != nil && !isNotFoundError(err) {
 			return err
 		}
 	}
@@ -303,62 +402,142 @@ func HandleForgeRune(ctx context.Context, realmID string, cmd ForgeRune, store c
 }
 
 func HandleFulfillRune(ctx context.Context, realmID string, cmd FulfillRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
-		return err
-	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
-	}
-	if state.Status == "sealed" {
-		return fmt.Errorf("cannot fulfill sealed rune %q", cmd.ID)
-	}
-	if state.Status == "shattered" {
-		return fmt.Errorf("cannot fulfill shattered rune %q", cmd.ID)
-	}
-	if state.Status == "fulfilled" {
-		return fmt.Errorf("rune %q is already fulfilled", cmd.ID)
-	}
-	if state.Status != "claimed" {
-		return fmt.Errorf("cannot fulfill rune %q: not claimed", cmd.ID)
-	}
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status == "sealed" {
+			return Rejectf(ErrRuneSealed, "cannot fulfill sealed rune %q", cmd.ID)
+		}
+		if state.Status == "shattered" {
+			return Rejectf(ErrRuneShattered, "cannot fulfill shattered rune %q", cmd.ID)
+		}
+		if state.Status == "fulfilled" {
+			return Rejectf(ErrRuneFulfilled, "rune %q is already fulfilled", cmd.ID)
+		}
+		if state.Status != "claimed" {
+			return Rejectf(ErrNotClaimed, "cannot fulfill rune %q: not claimed", cmd.ID)
+		}
 
-	fulfilled := RuneFulfilled(cmd)
+		fulfilled := RuneFulfilled(cmd)
 
-	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneFulfilled, Data: fulfilled},
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneFulfilled, Data: fulfilled},
+		})
+		return err
 	})
-	return err
 }
 
 func HandleSealRune(ctx context.Context, realmID string, cmd SealRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status == "sealed" {
+			return Rejectf(ErrRuneSealed, "rune %q is already sealed", cmd.ID)
+		}
+		if state.Status == "shattered" {
+			return Rejectf(ErrRuneShattered, "cannot seal shattered rune %q", cmd.ID)
+		}
+
+		sealed := RuneSealed(cmd)
+
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneSealed, Data: sealed},
+		})
+		return err
+	})
+}
+
+// HandleRecordSLABreach records that a rune overstayed its status. It does
+// nothing if a breach is already recorded for the current status, so the
+// monitor can safely retry.
+func HandleRecordSLABreach(ctx context.Context, realmID string, cmd RecordSLABreach, store core.EventStore) error {
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status != cmd.Status {
+			return Rejectf(ErrInvalidCommand, "rune %q is %s, not %s", cmd.ID, state.Status, cmd.Status)
+		}
+		if state.SLABreached {
+			return nil
+		}
+
+		breached := SLABreached(cmd)
+
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventSLABreached, Data: breached},
+		})
 		return err
+	})
+}
+
+// HandleAgeRunePriority raises the priority of an open rune by a step for
+// each of the realm's priority_aging thresholds passed since its priority was
+// last set, less the steps already taken. Taken steps are counted from the
+// rune's own events, so the scheduler can repeat it safely.
+func HandleAgeRunePriority(ctx context.Context, realmID string, cmd AgeRunePriority, store core.EventStore) (AgeRunePriorityResult, error) {
+	var result AgeRunePriorityResult
+	err := core.WithRetry(ctx, maxCommandAttempts, func() (err error) {
+		result, err = ageRunePriority(ctx, realmID, cmd, store)
+		return err
+	})
+	return result, err
+}
+
+func ageRunePriority(ctx context.Context, realmID string, cmd AgeRunePriority, store core.EventStore) (AgeRunePriorityResult, error) {
+	state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+	if err != nil {
+		return AgeRunePriorityResult{}, err
 	}
 	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		return AgeRunePriorityResult{}, &core.NotFoundError{Entity: "rune", ID: cmd.ID}
 	}
-	if state.Status == "sealed" {
-		return fmt.Errorf("rune %q is already sealed", cmd.ID)
+	result := AgeRunePriorityResult{Priority: state.Priority}
+	if state.Status != "open" || state.Priority == 0 {
+		return result, nil
 	}
-	if state.Status == "shattered" {
-		return fmt.Errorf("cannot seal shattered rune %q", cmd.ID)
+	realm, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return AgeRunePriorityResult{}, err
+	}
+	thresholds := PriorityAgingFromSettings(realm.Settings)
+	steps := PriorityAgingSteps(thresholds, state.PrioritySetAt, core.ClockFromContext(ctx).Now()) - state.AgingSteps
+	if steps <= 0 {
+		return result, nil
 	}
 
-	sealed := RuneSealed(cmd)
+	priority := max(state.Priority-steps, 0)
+	updated := RuneUpdated{ID: cmd.ID, Priority: &priority, Actor: PriorityAgingActor}
 
 	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneSealed, Data: sealed},
-	})
-	return err
+	if _, err := store.Append(ctx, realmID, streamID, version, []core.EventData{
+		{EventType: EventRuneUpdated, Data: updated},
+	}); err != nil {
+		return AgeRunePriorityResult{}, err
+	}
+	return AgeRunePriorityResult{Aged: true, Priority: priority}, nil
 }
 
 func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency, store core.EventStore, projStore core.ProjectionStore) error {
 	if !isKnownRelationship(cmd.Relationship) {
-		return fmt.Errorf("unknown relationship type %q", cmd.Relationship)
+		return Rejectf(ErrInvalidCommand, "unknown relationship type %q", cmd.Relationship)
 	}
 
 	if IsInverseRelationship(cmd.Relationship) {
@@ -366,7 +545,7 @@ func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency,
 		cmd.Relationship = ReflectRelationship(cmd.Relationship)
 	}
 
-	sourceState, sourceEvents, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
+	sourceState, sourceVersion, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
 	if err != nil {
 		return err
 	}
@@ -374,10 +553,10 @@ func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency,
 		return &core.NotFoundError{Entity: "rune", ID: cmd.RuneID}
 	}
 	if sourceState.Status == "shattered" {
-		return fmt.Errorf("cannot add dependency: rune %q is shattered", cmd.RuneID)
+		return Rejectf(ErrRuneShattered, "cannot add dependency: rune %q is shattered", cmd.RuneID)
 	}
 
-	targetState, targetEvents, err := readAndRebuild(ctx, realmID, cmd.TargetID, store)
+	targetState, targetVersion, err := readAndRebuild(ctx, realmID, cmd.TargetID, store)
 	if err != nil {
 		return err
 	}
@@ -385,19 +564,20 @@ func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency,
 		return &core.NotFoundError{Entity: "rune", ID: cmd.TargetID}
 	}
 	if targetState.Status == "shattered" {
-		return fmt.Errorf("cannot add dependency: rune %q is shattered", cmd.TargetID)
+		return Rejectf(ErrRuneShattered, "cannot add dependency: rune %q is shattered", cmd.TargetID)
 	}
 
 	if cmd.Relationship == RelBlocks {
-		var hasCycle bool
-		cycleKey := "cycle:" + cmd.RuneID + ":" + cmd.TargetID
-		err := projStore.Get(ctx, realmID, "dependency_graph", cycleKey, &hasCycle)
-		if err == nil && hasCycle {
-			return fmt.Errorf("adding blocks dependency from %q to %q would create a cycle", cmd.RuneID, cmd.TargetID)
+		hasCycle, err := wouldCreateCycle(ctx, realmID, cmd.RuneID, cmd.TargetID, store)
+		if err != nil {
+			return err
+		}
+		if hasCycle {
+			return Rejectf(ErrDependencyCycle, "adding blocks dependency from %q to %q would create a cycle", cmd.RuneID, cmd.TargetID)
 		}
 	}
 
-	inverseExpectedVersion := len(targetEvents)
+	inverseExpectedVersion := targetVersion
 
 	if cmd.Relationship == RelSupersedes {
 		sealed := RuneSealed{
@@ -405,13 +585,13 @@ func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency,
 			Reason: fmt.Sprintf("superseded by %s", cmd.RuneID),
 		}
 		targetStreamID := runeStreamID(cmd.TargetID)
-		_, err := store.Append(ctx, realmID, targetStreamID, len(targetEvents), []core.EventData{
+		_, err := store.Append(ctx, realmID, targetStreamID, targetVersion, []core.EventData{
 			{EventType: EventRuneSealed, Data: sealed},
 		})
 		if err != nil {
 			return err
 		}
-		inverseExpectedVersion = len(targetEvents) + 1
+		inverseExpectedVersion = targetVersion + 1
 	}
 
 	depAdded := DependencyAdded{
@@ -421,7 +601,7 @@ func HandleAddDependency(ctx context.Context, realmID string, cmd AddDependency,
 	}
 
 	sourceStreamID := runeStreamID(cmd.RuneID)
-	_, err = store.Append(ctx, realmID, sourceStreamID, len(sourceEvents), []core.EventData{
+	_, err = store.Append(ctx, realmID, sourceStreamID, sourceVersion, []core.EventData{
 		{EventType: EventDependencyAdded, Data: depAdded},
 	})
 	if err != nil {
@@ -448,7 +628,7 @@ func HandleRemoveDependency(ctx context.Context, realmID string, cmd RemoveDepen
 		cmd.Relationship = ReflectRelationship(cmd.Relationship)
 	}
 
-	state, events, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
+	state, version, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
 	if err != nil {
 		return err
 	}
@@ -456,10 +636,10 @@ func HandleRemoveDependency(ctx context.Context, realmID string, cmd RemoveDepen
 		return &core.NotFoundError{Entity: "rune", ID: cmd.RuneID}
 	}
 	if state.Status == "shattered" {
-		return fmt.Errorf("cannot remove dependency: rune %q is shattered", cmd.RuneID)
+		return Rejectf(ErrRuneShattered, "cannot remove dependency: rune %q is shattered", cmd.RuneID)
 	}
 
-	_, targetEvents, err := readAndRebuild(ctx, realmID, cmd.TargetID, store)
+	_, targetVersion, err := readAndRebuild(ctx, realmID, cmd.TargetID, store)
 	if err != nil {
 		return err
 	}
@@ -484,7 +664,7 @@ func HandleRemoveDependency(ctx context.Context, realmID string, cmd RemoveDepen
 	}
 
 	streamID := runeStreamID(cmd.RuneID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
+	_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
 		{EventType: EventDependencyRemoved, Data: depRemoved},
 	})
 	if err != nil {
@@ -499,63 +679,175 @@ func HandleRemoveDependency(ctx context.Context, realmID string, cmd RemoveDepen
 	}
 
 	targetStreamID := runeStreamID(cmd.TargetID)
-	_, err = store.Append(ctx, realmID, targetStreamID, len(targetEvents), []core.EventData{
+	_, err = store.Append(ctx, realmID, targetStreamID, targetVersion, []core.EventData{
 		{EventType: EventDependencyRemoved, Data: inverseDepRemoved},
 	})
 	return err
 }
 
 func HandleAddNote(ctx context.Context, realmID string, cmd AddNote, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
-	if err != nil {
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.RuneID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.RuneID}
+		}
+		if state.Status == "shattered" {
+			return Rejectf(ErrRuneShattered, "cannot add note to shattered rune %q", cmd.RuneID)
+		}
+		if err := validation.Note(cmd.Text); err != nil {
+			return Rejectf(ErrInvalidCommand, "%s", err)
+		}
+
+		noted := RuneNoted(cmd)
+
+		streamID := runeStreamID(cmd.RuneID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneNoted, Data: noted},
+		})
 		return err
-	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.RuneID}
-	}
-	if state.Status == "shattered" {
-		return fmt.Errorf("cannot add note to shattered rune %q", cmd.RuneID)
+	})
+}
+
+type LinkCommitsResult struct {
+	// Linked maps each rune ID to the SHAs of the commits noted on it.
+	Linked map[string][]string `json:"linked"`
+}
+
+// HandleLinkCommits scans commit messages for rune IDs and notes each commit
+// on the runes it references. References to unknown or shattered runes are
+// skipped, as are commits already noted on a rune.
+func HandleLinkCommits(ctx context.Context, realmID string, cmd LinkCommits, store core.EventStore) (LinkCommitsResult, error) {
+	result := LinkCommitsResult{Linked: make(map[string][]string)}
+
+	for _, commit := range cmd.Commits {
+		if commit.SHA == "" {
+			return result, Rejectf(ErrInvalidCommand, "cannot link commit: sha is required")
+		}
+		text := commitNoteText(cmd, commit)
+		prefix := "Commit " + shortSHA(commit.SHA)
+
+		for _, runeID := range ExtractRuneIDs(commit.Message) {
+			state, version, err := readAndRebuild(ctx, realmID, runeID, store)
+			if err != nil {
+				return result, err
+			}
+			if !state.Exists || state.Status == "shattered" {
+				continue
+			}
+			// Notes are not kept in the rune's state, so the whole stream
+			// is read to look for the commit.
+			streamID := runeStreamID(runeID)
+			events, err := store.ReadStream(ctx, realmID, streamID, 0)
+			if err != nil {
+				return result, err
+			}
+			if hasNoteWithPrefix(events, prefix) {
+				continue
+			}
+
+			_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+				{EventType: EventRuneNoted, Data: RuneNoted{RuneID: runeID, Text: text}},
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Linked[runeID] = append(result.Linked[runeID], commit.SHA)
+		}
 	}
 
-	noted := RuneNoted(cmd)
+	return result, nil
+}
 
-	streamID := runeStreamID(cmd.RuneID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneNoted, Data: noted},
-	})
-	return err
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
 }
 
-func HandleShatterRune(ctx context.Context, realmID string, cmd ShatterRune, store core.EventStore) error {
-	state, events, err := readAndRebuild(ctx, realmID, cmd.ID, store)
-	if err != nil {
-		return err
+func commitNoteText(cmd LinkCommits, commit CommitRef) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commit %s", shortSHA(commit.SHA))
+	if cmd.Repository != "" {
+		fmt.Fprintf(&b, " in %s", cmd.Repository)
 	}
-	if !state.Exists {
-		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+	if cmd.Branch != "" {
+		fmt.Fprintf(&b, " (%s)", cmd.Branch)
+	}
+	if commit.Author != "" {
+		fmt.Fprintf(&b, " by %s", commit.Author)
 	}
-	if state.Status != "sealed" && state.Status != "fulfilled" {
-		return fmt.Errorf("cannot shatter rune %q: must be sealed or fulfilled", cmd.ID)
+	fmt.Fprintf(&b, ": %s", subject)
+	if commit.URL != "" {
+		fmt.Fprintf(&b, "\n%s", commit.URL)
+	}
+	return b.String()
+}
+
+func hasNoteWithPrefix(events []core.Event, prefix string) bool {
+	for _, evt := range events {
+		if evt.EventType != EventRuneNoted {
+			continue
+		}
+		var data RuneNoted
+		if err := json.Unmarshal(evt.Data, &data); err == nil && strings.HasPrefix(data.Text, prefix) {
+			return true
+		}
 	}
+	return false
+}
 
-	shattered := RuneShattered(cmd)
+func HandleShatterRune(ctx context.Context, realmID string, cmd ShatterRune, store core.EventStore) error {
+	return core.WithRetry(ctx, maxCommandAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+		if err != nil {
+			return err
+		}
+		if !state.Exists {
+			return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+		}
+		if state.Status != "sealed" && state.Status != "fulfilled" {
+			return Rejectf(ErrInvalidCommand, "cannot shatter rune %q: must be sealed or fulfilled", cmd.ID)
+		}
 
-	streamID := runeStreamID(cmd.ID)
-	_, err = store.Append(ctx, realmID, streamID, len(events), []core.EventData{
-		{EventType: EventRuneShattered, Data: shattered},
+		shattered := RuneShattered(cmd)
+
+		streamID := runeStreamID(cmd.ID)
+		_, err = store.Append(ctx, realmID, streamID, version, []core.EventData{
+			{EventType: EventRuneShattered, Data: shattered},
+		})
+		return err
 	})
-	return err
 }
 
 func HandleSweepRunes(ctx context.Context, realmID string, store core.EventStore, projStore core.ProjectionStore) ([]string, error) {
+	return sweepRunes(ctx, realmID, time.Time{}, store, projStore)
+}
+
+// HandleSweepRunesFinishedBefore shatters the sealed and fulfilled runes
+// last changed before the given time, skipping those still referenced by
+// active runes as HandleSweepRunes does.
+func HandleSweepRunesFinishedBefore(ctx context.Context, realmID string, before time.Time, store core.EventStore, projStore core.ProjectionStore) ([]string, error) {
+	return sweepRunes(ctx, realmID, before, store, projStore)
+}
+
+// sweepRunes shatters unreferenced finished runes, only those last changed
+// before the given time unless it is zero.
+func sweepRunes(ctx context.Context, realmID string, before time.Time, store core.EventStore, projStore core.ProjectionStore) ([]string, error) {
 	rawEntries, err := projStore.List(ctx, realmID, "rune_list")
 	if err != nil {
 		return nil, err
 	}
 
 	type runeEntry struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
+		ID        string    `json:"id"`
+		Status    string    `json:"status"`
+		UpdatedAt time.Time `json:"updated_at"`
 	}
 
 	var candidates []runeEntry
@@ -564,6 +856,9 @@ func HandleSweepRunes(ctx context.Context, realmID string, store core.EventStore
 		if err := json.Unmarshal(raw, &entry); err != nil {
 			return nil, err
 		}
+		if !before.IsZero() && !entry.UpdatedAt.Before(before) {
+			continue
+		}
 		if entry.Status == "sealed" || entry.Status == "fulfilled" {
 			candidates = append(candidates, entry)
 		}
@@ -572,7 +867,7 @@ func HandleSweepRunes(ctx context.Context, realmID string, store core.EventStore
 	shattered := make([]string, 0)
 
 	for _, candidate := range candidates {
-		if hasActiveReference(ctx, realmID, candidate.ID, projStore) {
+		if hasActiveReference(ctx, realmID, candidate.ID, store, projStore) {
 			continue
 		}
 
@@ -585,7 +880,7 @@ func HandleSweepRunes(ctx context.Context, realmID string, store core.EventStore
 	return shattered, nil
 }
 
-func hasActiveReference(ctx context.Context, realmID string, runeID string, projStore core.ProjectionStore) bool {
+func hasActiveReference(ctx context.Context, realmID string, runeID string, store core.EventStore, projStore core.ProjectionStore) bool {
 	type graphDependent struct {
 		SourceID string `json:"source_id"`
 	}
@@ -603,16 +898,17 @@ func hasActiveReference(ctx context.Context, realmID string, runeID string, proj
 		}
 	}
 
-	var childCount int
-	err = projStore.Get(ctx, realmID, "RuneChildCount", runeID, &childCount)
+	state, _, err := readAndRebuild(ctx, realmID, runeID, store)
 	if err != nil {
-		if isNotFoundError(err) {
-			childCount = 0
-		} else {
-			return true
-		}
+		return true
 	}
-	for i := 1; i <= childCount; i++ {
+	lastChild, err := lastChildSequence(ctx, realmID, state, projStore)
+	if err != nil {
+		return true
+	}
+	// Sequence numbers allocated without a child being created are not in
+	// the rune list, so they count as inactive.
+	for i := 1; i <= lastChild; i++ {
 		childID := fmt.Sprintf("%s.%d", runeID, i)
 		if isActiveRuneInProjection(ctx, realmID, childID, projStore) {
 			return true
@@ -643,6 +939,115 @@ func isKnownRelationship(rel string) bool {
 	return false
 }
 
+// maxCommandAttempts bounds how often a rune command is run again when
+// another command appended to the rune between its read and its append.
+// Only commands that append to a single stream are retried, so that a
+// command is never left half applied and then run again.
+const maxCommandAttempts = 3
+
+// maxChildAllocationAttempts bounds how often allocateChildID retries when
+// other commands keep changing the parent.
+const maxChildAllocationAttempts = 5
+
+// allocateChildID reserves the parent's next child ID by appending a
+// RuneChildAllocated event at the version it read. If another command
+// changed the parent first the append fails with a ConcurrencyError, and it
+// reads the parent again and retries.
+func allocateChildID(ctx context.Context, realmID, parentID string, store core.EventStore, projStore core.ProjectionStore) (string, error) {
+	var childID string
+	err := core.WithRetry(ctx, maxChildAllocationAttempts, func() error {
+		state, version, err := readAndRebuild(ctx, realmID, parentID, store)
+		if err != nil {
+			return err
+		}
+		seq, err := lastChildSequence(ctx, realmID, state, projStore)
+		if err != nil {
+			return err
+		}
+		allocated := RuneChildAllocated{ID: parentID, ChildID: fmt.Sprintf("%s.%d", parentID, seq+1), Sequence: seq + 1}
+		if _, err := store.Append(ctx, realmID, runeStreamID(parentID), version, []core.EventData{
+			{EventType: EventRuneChildAllocated, Data: allocated},
+		}); err != nil {
+			return err
+		}
+		childID = allocated.ChildID
+		return nil
+	})
+	return childID, err
+}
+
+// lastChildSequence returns the highest child sequence number used under a
+// rune. Children created before allocations were recorded on the parent's
+// stream are only counted by the RuneChildCount projection.
+func lastChildSequence(ctx context.Context, realmID string, state RuneState, projStore core.ProjectionStore) (int, error) {
+	var childCount int
+	err := projStore.Get(ctx, realmID, "RuneChildCount", state.ID, &childCount)
+	if err != nil && !isNotFoundError(err) {
+		return 0, err
+	}
+	return max(childCount, state.ChildSequence), nil
+}
+
+// validateCreateRune checks a new rune's fields against the limits in the
+// validation package.
+func validateCreateRune(cmd CreateRune) error {
+	checks := []error{
+		validation.Title(cmd.Title),
+		validation.Description(cmd.Description),
+		validation.Priority(cmd.Priority),
+	}
+	if cmd.Branch != nil {
+		checks = append(checks, validation.Branch(*cmd.Branch))
+	}
+	return firstInvalid(checks, cmd.DueDate)
+}
+
+// validateUpdateRune checks the fields an update sets.
+func validateUpdateRune(cmd UpdateRune) error {
+	var checks []error
+	if cmd.Title != nil {
+		checks = append(checks, validation.Title(*cmd.Title))
+	}
+	if cmd.Description != nil {
+		checks = append(checks, validation.Description(*cmd.Description))
+	}
+	if cmd.Priority != nil {
+		checks = append(checks, validation.Priority(*cmd.Priority))
+	}
+	if cmd.Branch != nil {
+		checks = append(checks, validation.Branch(*cmd.Branch))
+	}
+	var dueDate string
+	if cmd.DueDate != nil {
+		dueDate = *cmd.DueDate
+	}
+	return firstInvalid(checks, dueDate)
+}
+
+func firstInvalid(checks []error, dueDate string) error {
+	for _, err := range checks {
+		if err != nil {
+			return Rejectf(ErrInvalidCommand, "%s", err)
+		}
+	}
+	return validateDueDate(dueDate)
+}
+
+// DueDateLayout is the format of rune due dates.
+const DueDateLayout = "2006-01-02"
+
+// validateDueDate accepts an empty due date (none, or clearing it) or a
+// calendar date in DueDateLayout.
+func validateDueDate(dueDate string) error {
+	if dueDate == "" {
+		return nil
+	}
+	if _, err := time.Parse(DueDateLayout, dueDate); err != nil {
+		return Rejectf(ErrInvalidCommand, "cannot set due date %q: expected YYYY-MM-DD", dueDate)
+	}
+	return nil
+}
+
 func isNotFoundError(err error) bool {
 	var nfe *core.NotFoundError
 	return errors.As(err, &nfe)
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/devzeebo/bifrost/core"
 	"github.com/stretchr/testify/assert"
@@ -211,6 +215,66 @@ func TestHandleCreateRune(t *testing.T) {
 		tc.created_event_has_parent_id("bf-a1b2")
 	})
 
+	t.Run("allocates the child ID on the parent's stream", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_create_rune_command("Child task", "", 2, "bf-a1b2")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		tc.append_call_is(0, "rune-bf-a1b2", 2, EventRuneChildAllocated)
+		tc.created_event_has_id("bf-a1b2.1")
+	})
+
+	t.Run("continues from the last allocated child", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.rune_stream_has_child_allocated("bf-a1b2", 3)
+		tc.projection_returns_child_count("bf-a1b2", 2)
+		tc.a_create_rune_command("Child task", "", 2, "bf-a1b2")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		tc.created_event_has_id("bf-a1b2.4")
+	})
+
+	t.Run("retries the allocation when the parent changed concurrently", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.next_append_fails(&core.ConcurrencyError{StreamID: "rune-bf-a1b2", ExpectedVersion: 2, ActualVersion: 3})
+		tc.a_create_rune_command("Child task", "", 2, "bf-a1b2")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		tc.append_call_is(0, "rune-bf-a1b2", 2, EventRuneChildAllocated)
+		tc.append_call_is(1, "rune-bf-a1b2", 2, EventRuneChildAllocated)
+		tc.created_event_has_id("bf-a1b2.1")
+	})
+
 	t.Run("creates second child rune with sequential ID", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
@@ -278,6 +342,40 @@ func TestHandleCreateRune(t *testing.T) {
 
 		// Then
 		tc.error_contains("branch is required")
+		tc.error_is(ErrBranchRequired)
+	})
+
+	t.Run("rejects fields outside the validation limits", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			title    string
+			priority int
+			branch   string
+		}{
+			{"blank title", "  ", 1, "main"},
+			{"long title", strings.Repeat("x", 201), 1, "main"},
+			{"priority out of range", "Fix the bridge", 7, "main"},
+			{"malformed branch", "Fix the bridge", 1, "feature/../main"},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				tc := newHandlerTestContext(t)
+
+				// Given
+				tc.a_realm("realm-1")
+				tc.an_event_store()
+				tc.a_projection_store()
+				tc.a_create_rune_command(c.title, "", c.priority, "")
+				tc.with_branch_on_create_command(c.branch)
+
+				// When
+				tc.handle_create_rune()
+
+				// Then
+				tc.error_is(ErrInvalidCommand)
+				tc.no_events_were_appended()
+			})
+		}
 	})
 
 	t.Run("inherits branch from parent when branch is nil", func(t *testing.T) {
@@ -336,6 +434,44 @@ func TestHandleCreateRune(t *testing.T) {
 		tc.no_error()
 		tc.created_event_has_branch("")
 	})
+
+	t.Run("creates a rune with a due date", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+		tc.with_due_date_on_create_command("2026-03-31")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		tc.created_event_has_due_date("2026-03-31")
+	})
+
+	t.Run("rejects a malformed due date", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+		tc.with_due_date_on_create_command("31/03/2026")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.error_contains("expected YYYY-MM-DD")
+		tc.no_events_were_appended()
+	})
 }
 
 func TestHandleUpdateRune(t *testing.T) {
@@ -346,7 +482,7 @@ func TestHandleUpdateRune(t *testing.T) {
 		tc.a_realm("realm-1")
 		tc.an_event_store()
 		tc.existing_rune_in_stream("bf-a1b2", "open")
-		tc.an_update_rune_command("bf-a1b2", strPtr("New title"), nil, intPtr(5))
+		tc.an_update_rune_command("bf-a1b2", strPtr("New title"), nil, intPtr(3))
 
 		// When
 		tc.handle_update_rune()
@@ -407,6 +543,42 @@ func TestHandleUpdateRune(t *testing.T) {
 		tc.event_was_appended_to_stream("rune-bf-a1b2")
 		tc.appended_event_has_type(EventRuneUpdated)
 	})
+
+	t.Run("clears due date with an empty value", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.an_update_rune_command("bf-a1b2", nil, nil, nil)
+		tc.with_due_date_on_update_command("")
+
+		// When
+		tc.handle_update_rune()
+
+		// Then
+		tc.no_error()
+		tc.appended_event_has_type(EventRuneUpdated)
+	})
+
+	t.Run("rejects a malformed due date", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.an_update_rune_command("bf-a1b2", nil, nil, nil)
+		tc.with_due_date_on_update_command("next week")
+
+		// When
+		tc.handle_update_rune()
+
+		// Then
+		tc.error_contains("expected YYYY-MM-DD")
+		tc.no_events_were_appended()
+	})
 }
 
 func TestHandleClaimRune(t *testing.T) {
@@ -442,6 +614,7 @@ func TestHandleClaimRune(t *testing.T) {
 
 		// Then
 		tc.error_contains("claimed")
+		tc.error_is(ErrAlreadyClaimed)
 	})
 
 	t.Run("returns error when rune is sealed", func(t *testing.T) {
@@ -477,6 +650,158 @@ func TestHandleClaimRune(t *testing.T) {
 	})
 }
 
+func TestHandleClaimRune_ClaimantAccounts(t *testing.T) {
+	t.Run("accepts any claimant when the realm does not require accounts", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.no_error()
+		tc.claimed_account_id_is("")
+	})
+
+	t.Run("records the account of a claimant given by username", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(RequireClaimantAccountSetting, "true")
+		tc.an_account("acct-1", "odin", "active")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.no_error()
+		tc.claimed_account_id_is("acct-1")
+	})
+
+	t.Run("records the account of a claimant given by account ID", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(RequireClaimantAccountSetting, "true")
+		tc.an_account("acct-1", "odin", "active")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_claim_rune_command("bf-a1b2", "acct-1")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.no_error()
+		tc.claimed_account_id_is("acct-1")
+	})
+
+	t.Run("rejects a claimant that is not an account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(RequireClaimantAccountSetting, "true")
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_claim_rune_command("bf-a1b2", "loki")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.error_is(ErrUnknownClaimant)
+		tc.no_events_were_appended()
+	})
+
+	t.Run("rejects a suspended claimant", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(RequireClaimantAccountSetting, "true")
+		tc.an_account("acct-1", "odin", "suspended")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.error_is(ErrSuspended)
+		tc.no_events_were_appended()
+	})
+}
+
+func TestHandleClaimRune_StrictBlocking(t *testing.T) {
+	t.Run("claims a blocked rune when strict blocking is off", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-b", "open")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.rune_is_blocked_by("bf-a1b2", "bf-b")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.no_error()
+		tc.appended_event_has_type(EventRuneClaimed)
+	})
+
+	t.Run("rejects a rune with an unfulfilled blocker", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(FeatureSettingPrefix+FeatureStrictBlocking, "true")
+		tc.existing_rune_in_stream("bf-b", "claimed")
+		tc.existing_rune_in_stream("bf-c", "fulfilled")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.rune_is_blocked_by("bf-a1b2", "bf-b")
+		tc.rune_is_blocked_by("bf-a1b2", "bf-c")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.error_is(ErrBlocked)
+		tc.rejection_details_are(map[string]any{"blocked_by": []string{"bf-b"}})
+		tc.no_events_were_appended()
+	})
+
+	t.Run("claims a rune once its blockers are fulfilled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_realm_setting(FeatureSettingPrefix+FeatureStrictBlocking, "true")
+		tc.existing_rune_in_stream("bf-b", "fulfilled")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.rune_is_blocked_by("bf-a1b2", "bf-b")
+		tc.a_claim_rune_command("bf-a1b2", "odin")
+
+		// When
+		tc.handle_claim_rune()
+
+		// Then
+		tc.no_error()
+		tc.appended_event_has_type(EventRuneClaimed)
+	})
+}
+
 func TestHandleUnclaimRune(t *testing.T) {
 	t.Run("unclaims a claimed rune", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
@@ -697,6 +1022,91 @@ func TestHandleSealRune(t *testing.T) {
 	})
 }
 
+func TestHandleRecordSLABreach(t *testing.T) {
+	t.Run("records a breach of the rune's current status", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "claimed")
+		tc.a_record_sla_breach_command("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_record_sla_breach()
+
+		// Then
+		tc.no_error()
+		tc.event_was_appended_to_stream("rune-bf-a1b2")
+		tc.appended_event_has_type(EventSLABreached)
+	})
+
+	t.Run("does nothing when the breach is already recorded", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "claimed")
+		tc.sla_breach_recorded("bf-a1b2", "claimed")
+		tc.a_record_sla_breach_command("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_record_sla_breach()
+
+		// Then
+		tc.no_error()
+		tc.no_events_were_appended()
+	})
+
+	t.Run("records a new breach after the rune changes status", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.sla_breach_recorded("bf-a1b2", "open")
+		tc.rune_event_in_stream("bf-a1b2", EventRuneClaimed, RuneClaimed{ID: "bf-a1b2", Claimant: "someone"})
+		tc.a_record_sla_breach_command("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_record_sla_breach()
+
+		// Then
+		tc.no_error()
+		tc.appended_event_has_type(EventSLABreached)
+	})
+
+	t.Run("returns error when the rune has left the status", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "fulfilled")
+		tc.a_record_sla_breach_command("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_record_sla_breach()
+
+		// Then
+		tc.error_contains("not claimed")
+		tc.no_events_were_appended()
+	})
+
+	t.Run("returns error when rune does not exist", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.empty_stream("bf-missing")
+		tc.a_record_sla_breach_command("bf-missing", "claimed")
+
+		// When
+		tc.handle_record_sla_breach()
+
+		// Then
+		tc.error_is_not_found("rune", "bf-missing")
+	})
+}
+
 func TestHandleAddDependency(t *testing.T) {
 	t.Run("adds a relates_to dependency", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
@@ -748,7 +1158,7 @@ func TestHandleAddDependency(t *testing.T) {
 		tc.a_projection_store()
 		tc.existing_rune_in_stream("bf-a1b2", "open")
 		tc.existing_rune_in_stream("bf-c3d4", "open")
-		tc.dependency_graph_has_cycle("bf-a1b2", "bf-c3d4")
+		tc.rune_blocks("bf-c3d4", "bf-a1b2")
 		tc.an_add_dependency_command("bf-a1b2", "bf-c3d4", RelBlocks)
 
 		// When
@@ -756,6 +1166,66 @@ func TestHandleAddDependency(t *testing.T) {
 
 		// Then
 		tc.error_contains("cycle")
+		tc.error_is(ErrDependencyCycle)
+	})
+
+	t.Run("returns error for blocks dependency closing a longer cycle", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.existing_rune_in_stream("bf-c3d4", "open")
+		tc.existing_rune_in_stream("bf-e5f6", "open")
+		tc.rune_blocks("bf-c3d4", "bf-e5f6")
+		tc.rune_blocks("bf-e5f6", "bf-a1b2")
+		tc.an_add_dependency_command("bf-a1b2", "bf-c3d4", RelBlocks)
+
+		// When
+		tc.handle_add_dependency()
+
+		// Then
+		tc.error_is(ErrDependencyCycle)
+		tc.no_events_were_appended()
+	})
+
+	t.Run("returns error for a rune blocking itself", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.an_add_dependency_command("bf-a1b2", "bf-a1b2", RelBlocks)
+
+		// When
+		tc.handle_add_dependency()
+
+		// Then
+		tc.error_is(ErrDependencyCycle)
+	})
+
+	t.Run("ignores blocks dependencies that were removed", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.existing_rune_in_stream("bf-c3d4", "open")
+		tc.rune_blocks("bf-c3d4", "bf-a1b2")
+		tc.rune_no_longer_blocks("bf-c3d4", "bf-a1b2")
+		tc.an_add_dependency_command("bf-a1b2", "bf-c3d4", RelBlocks)
+
+		// When
+		tc.handle_add_dependency()
+
+		// Then
+		tc.no_error()
 	})
 
 	t.Run("supersedes auto-seals target rune", func(t *testing.T) {
@@ -988,6 +1458,117 @@ func TestHandleAddNote(t *testing.T) {
 		// Then
 		tc.error_is_not_found("rune", "bf-missing")
 	})
+
+	t.Run("retries when the rune changed concurrently", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.next_append_fails(&core.ConcurrencyError{StreamID: "rune-bf-a1b2", ExpectedVersion: 2, ActualVersion: 3})
+		tc.an_add_note_command("bf-a1b2", "This is a note")
+
+		// When
+		tc.handle_add_note()
+
+		// Then
+		tc.no_error()
+		tc.append_call_is(0, "rune-bf-a1b2", 2, EventRuneNoted)
+		tc.append_call_is(1, "rune-bf-a1b2", 2, EventRuneNoted)
+	})
+
+	t.Run("returns the conflict once the retries run out", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		for range maxCommandAttempts {
+			tc.next_append_fails(&core.ConcurrencyError{StreamID: "rune-bf-a1b2", ExpectedVersion: 2, ActualVersion: 3})
+		}
+		tc.an_add_note_command("bf-a1b2", "This is a note")
+
+		// When
+		tc.handle_add_note()
+
+		// Then
+		var concErr *core.ConcurrencyError
+		assert.ErrorAs(t, tc.err, &concErr)
+		assert.Len(t, tc.eventStore.appendedCalls, maxCommandAttempts)
+	})
+}
+
+func TestHandleLinkCommits(t *testing.T) {
+	t.Run("notes commit on each referenced rune", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.existing_rune_in_stream("bf-c3d4", "claimed")
+		tc.a_link_commits_command(CommitRef{SHA: "0123456789abcdef", Message: "Fix bf-a1b2 and bf-c3d4\n\nDetails", Author: "odin"})
+
+		// When
+		tc.handle_link_commits()
+
+		// Then
+		tc.no_error()
+		tc.event_was_appended_to_stream("rune-bf-a1b2")
+		tc.event_was_appended_to_stream("rune-bf-c3d4")
+		tc.appended_event_has_type(EventRuneNoted)
+		tc.link_result_has("bf-a1b2", "0123456789abcdef")
+		tc.link_result_has("bf-c3d4", "0123456789abcdef")
+	})
+
+	t.Run("skips unknown and shattered runes", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "shattered")
+		tc.a_link_commits_command(CommitRef{SHA: "0123456789abcdef", Message: "Touch bf-a1b2 and bf-ffff"})
+
+		// When
+		tc.handle_link_commits()
+
+		// Then
+		tc.no_error()
+		tc.no_events_were_appended()
+	})
+
+	t.Run("skips commits already noted on the rune", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.rune_has_note("bf-a1b2", "Commit 0123456 by odin: Fix bf-a1b2")
+		tc.a_link_commits_command(CommitRef{SHA: "0123456789abcdef", Message: "Fix bf-a1b2"})
+
+		// When
+		tc.handle_link_commits()
+
+		// Then
+		tc.no_error()
+		tc.no_events_were_appended()
+	})
+
+	t.Run("returns error when commit has no sha", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_link_commits_command(CommitRef{Message: "Fix bf-a1b2"})
+
+		// When
+		tc.handle_link_commits()
+
+		// Then
+		tc.error_contains("sha is required")
+	})
 }
 
 func TestHandleShatterRune(t *testing.T) {
@@ -1191,6 +1772,29 @@ func TestHandleSweepRunes(t *testing.T) {
 		tc.sweep_result_is_empty()
 	})
 
+	t.Run("skips rune whose active child sits after an allocation gap", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "sealed")
+		tc.rune_stream_has_child_allocated("bf-a1b2", 1)
+		tc.rune_stream_has_child_allocated("bf-a1b2", 2)
+		tc.existing_rune_in_stream("bf-a1b2.2", "open")
+		tc.rune_in_rune_list("bf-a1b2", "sealed")
+		tc.rune_in_rune_list("bf-a1b2.2", "open")
+		tc.rune_has_children("bf-a1b2", 1)
+
+		// When
+		tc.handle_sweep_runes()
+
+		// Then
+		tc.no_error()
+		tc.sweep_result_is_empty()
+	})
+
 	t.Run("shatters rune whose only dependents are also sealed or fulfilled", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
@@ -1278,6 +1882,208 @@ func TestHandleSweepRunes(t *testing.T) {
 	})
 }
 
+func TestHandleSweepRunesFinishedBefore(t *testing.T) {
+	cutoff := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("shatters finished runes last changed before the cutoff", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "sealed")
+		tc.existing_rune_in_stream("bf-c3d4", "fulfilled")
+		tc.rune_in_rune_list_updated("bf-a1b2", "sealed", cutoff.Add(-time.Hour))
+		tc.rune_in_rune_list_updated("bf-c3d4", "fulfilled", cutoff.Add(time.Hour))
+
+		// When
+		tc.handle_sweep_runes_finished_before(cutoff)
+
+		// Then
+		tc.no_error()
+		tc.sweep_result_has_length(1)
+		tc.sweep_result_contains("bf-a1b2")
+		tc.event_was_appended_to_stream("rune-bf-a1b2")
+	})
+}
+
+func TestHandleCreateRune_BranchPolicy(t *testing.T) {
+	t.Run("accepts a branch with an allowed prefix", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.a_realm_setting(BranchPrefixesSetting, "feature/, fix/")
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("fix/bridge")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+	})
+
+	t.Run("rejects a branch without an allowed prefix", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.a_realm_setting(BranchPrefixesSetting, "feature/, fix/")
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("bridge")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.error_is(ErrBranchPolicy)
+		tc.error_contains(`must start with one of ["feature/" "fix/"]`)
+		tc.rejection_details_are(BranchPolicy{Prefixes: []string{"feature/", "fix/"}})
+		tc.no_events_were_appended()
+	})
+
+	t.Run("rejects a child's branch before allocating its ID", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.projection_returns_child_count("bf-a1b2", 0)
+		tc.a_realm_setting(BranchPrefixesSetting, "feature/, fix/")
+		tc.a_create_rune_command("Child task", "", 2, "bf-a1b2")
+		tc.with_branch_on_create_command("bridge")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.error_is(ErrBranchPolicy)
+		tc.no_events_were_appended()
+	})
+
+	t.Run("rejects a branch that does not match the whole pattern", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.a_realm_setting(BranchPatternSetting, `bf-[a-f0-9]{4}/[a-z-]+`)
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("bf-a1b2/fix-bridge-2")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.error_is(ErrBranchPolicy)
+	})
+
+	t.Run("applies to branch changes on update", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.existing_rune_in_stream("bf-a1b2", "open")
+		tc.a_realm_setting(BranchPatternSetting, `feature/.+`)
+		tc.an_update_rune_command("bf-a1b2", nil, nil, nil)
+		tc.with_branch_on_update_command("main")
+
+		// When
+		tc.handle_update_rune()
+
+		// Then
+		tc.error_is(ErrBranchPolicy)
+	})
+}
+
+func TestHandleCreateRune_RuneIDs(t *testing.T) {
+	t.Run("numbers runes in sequence with the realm's prefix", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store_that_keeps_appends()
+		tc.a_projection_store()
+		tc.a_realm_setting(RuneIDFormatSetting, RuneIDFormatSequential)
+		tc.a_realm_setting(RuneIDPrefixSetting, "PROJ")
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+
+		// When
+		tc.handle_create_rune()
+		first := tc.createdEvent.ID
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "PROJ-1", first)
+		tc.created_event_has_id("PROJ-2")
+		tc.append_call_is(0, runeIDSequenceStreamID, 0, EventRuneIDAllocated)
+	})
+
+	t.Run("skips IDs a rune already has", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.an_event_store_that_keeps_appends()
+		tc.a_projection_store()
+		tc.existing_rune_in_stream("bf-1", "open")
+		tc.a_realm_setting(RuneIDFormatSetting, RuneIDFormatSequential)
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		tc.created_event_has_id("bf-2")
+		tc.event_was_appended_to_stream_with_prefix("rune-bf-2")
+	})
+
+	t.Run("names runes with a ULID", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.a_realm_setting(RuneIDFormatSetting, RuneIDFormatULID)
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^bf-[0-7][0-9A-HJKMNP-TV-Z]{25}$`, tc.createdEvent.ID)
+	})
+
+	t.Run("keeps hex IDs with a custom prefix", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.a_projection_store()
+		tc.a_realm_setting(RuneIDPrefixSetting, "ops")
+		tc.a_create_rune_command("Fix the bridge", "", 1, "")
+		tc.with_branch_on_create_command("main")
+
+		// When
+		tc.handle_create_rune()
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^ops-[0-9a-f]{4}$`, tc.createdEvent.ID)
+	})
+}
+
 func TestHandleCreateRune_RejectsShatteredParent(t *testing.T) {
 	t.Run("returns error when parent is shattered", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
@@ -1514,11 +2320,14 @@ type handlerTestContext struct {
 	removeDepCmd RemoveDependency
 	addNoteCmd  AddNote
 	shatterCmd  ShatterRune
+	linkCmd     LinkCommits
+	slaBreachCmd RecordSLABreach
 
 	createdEvent RuneCreated
 	state        RuneState
 	events       []core.Event
 	sweepResult  []string
+	linkResult   LinkCommitsResult
 	err          error
 }
 
@@ -1544,6 +2353,12 @@ func (tc *handlerTestContext) an_event_store() {
 	}
 }
 
+func (tc *handlerTestContext) an_event_store_that_keeps_appends() {
+	tc.t.Helper()
+	tc.an_event_store()
+	tc.eventStore.keepAppends = true
+}
+
 func (tc *handlerTestContext) a_projection_store() {
 	tc.t.Helper()
 	if tc.projectionStore == nil {
@@ -1732,6 +2547,19 @@ func (tc *handlerTestContext) existing_rune_with_branch_in_stream(runeID string,
 	tc.eventStore.streams["rune-"+runeID] = events
 }
 
+func (tc *handlerTestContext) rune_has_note(runeID, text string) {
+	tc.t.Helper()
+	streamID := "rune-" + runeID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID],
+		makeEvent(EventRuneNoted, RuneNoted{RuneID: runeID, Text: text}),
+	)
+}
+
+func (tc *handlerTestContext) a_link_commits_command(commits ...CommitRef) {
+	tc.t.Helper()
+	tc.linkCmd = LinkCommits{Commits: commits}
+}
+
 func (tc *handlerTestContext) existing_rune_in_stream(runeID string, status string) {
 	tc.t.Helper()
 	tc.an_event_store()
@@ -1777,12 +2605,55 @@ func (tc *handlerTestContext) existing_rune_in_stream(runeID string, status stri
 	tc.eventStore.streams["rune-"+runeID] = events
 }
 
+func (tc *handlerTestContext) rune_event_in_stream(runeID, eventType string, data any) {
+	tc.t.Helper()
+	streamID := "rune-" + runeID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID], makeEvent(eventType, data))
+}
+
+func (tc *handlerTestContext) sla_breach_recorded(runeID, status string) {
+	tc.t.Helper()
+	tc.rune_event_in_stream(runeID, EventSLABreached, SLABreached{ID: runeID, Status: status, Threshold: "7d"})
+}
+
+func (tc *handlerTestContext) a_record_sla_breach_command(runeID, status string) {
+	tc.t.Helper()
+	tc.slaBreachCmd = RecordSLABreach{ID: runeID, Status: status, Threshold: "7d"}
+}
+
 func (tc *handlerTestContext) empty_stream(runeID string) {
 	tc.t.Helper()
 	tc.an_event_store()
 	tc.eventStore.streams["rune-"+runeID] = []core.Event{}
 }
 
+func (tc *handlerTestContext) a_realm_setting(key, value string) {
+	tc.t.Helper()
+	tc.an_event_store()
+	streamID := "realm-" + tc.realmID
+	if _, ok := tc.eventStore.streams[streamID]; !ok {
+		tc.eventStore.streams[streamID] = []core.Event{
+			makeEvent(EventRealmCreated, RealmCreated{RealmID: tc.realmID, Name: "Realm"}),
+		}
+	}
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID],
+		makeEvent(EventRealmSettingSet, RealmSettingSet{RealmID: tc.realmID, Key: key, Value: value}))
+}
+
+func (tc *handlerTestContext) an_account(accountID, username, status string) {
+	tc.t.Helper()
+	tc.an_event_store()
+	tc.a_projection_store()
+	events := []core.Event{
+		makeEvent(EventAccountCreated, AccountCreated{AccountID: accountID, Username: username}),
+	}
+	if status == "suspended" {
+		events = append(events, makeEvent(EventAccountSuspended, AccountSuspended{AccountID: accountID}))
+	}
+	tc.eventStore.streams["account-"+accountID] = events
+	tc.projectionStore.data["account_lookup:username:"+username] = accountID
+}
+
 func (tc *handlerTestContext) with_branch_on_create_command(branch string) {
 	tc.t.Helper()
 	tc.createCmd.Branch = &branch
@@ -1793,6 +2664,29 @@ func (tc *handlerTestContext) with_branch_on_update_command(branch string) {
 	tc.updateCmd.Branch = &branch
 }
 
+func (tc *handlerTestContext) with_due_date_on_create_command(dueDate string) {
+	tc.t.Helper()
+	tc.createCmd.DueDate = dueDate
+}
+
+func (tc *handlerTestContext) with_due_date_on_update_command(dueDate string) {
+	tc.t.Helper()
+	tc.updateCmd.DueDate = &dueDate
+}
+
+func (tc *handlerTestContext) rune_stream_has_child_allocated(runeID string, sequence int) {
+	tc.t.Helper()
+	streamID := "rune-" + runeID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID], makeEvent(EventRuneChildAllocated, RuneChildAllocated{
+		ID: runeID, ChildID: fmt.Sprintf("%s.%d", runeID, sequence), Sequence: sequence,
+	}))
+}
+
+func (tc *handlerTestContext) next_append_fails(err error) {
+	tc.t.Helper()
+	tc.eventStore.failNextAppends = append(tc.eventStore.failNextAppends, err)
+}
+
 func (tc *handlerTestContext) projection_returns_child_count(parentID string, count int) {
 	tc.t.Helper()
 	tc.a_projection_store()
@@ -1805,11 +2699,27 @@ func (tc *handlerTestContext) dependency_graph_has_no_cycle(sourceID, targetID s
 	// No cycle entry means no cycle detected
 }
 
-func (tc *handlerTestContext) dependency_graph_has_cycle(sourceID, targetID string) {
+func (tc *handlerTestContext) rune_blocks(sourceID, targetID string) {
 	tc.t.Helper()
-	tc.a_projection_store()
-	key := "dependency_graph:cycle:" + sourceID + ":" + targetID
-	tc.projectionStore.data[key] = true
+	streamID := "rune-" + sourceID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID], makeEvent(EventDependencyAdded, DependencyAdded{
+		RuneID: sourceID, TargetID: targetID, Relationship: RelBlocks,
+	}))
+}
+
+func (tc *handlerTestContext) rune_is_blocked_by(runeID, blockerID string) {
+	tc.t.Helper()
+	tc.rune_event_in_stream(runeID, EventDependencyAdded, DependencyAdded{
+		RuneID: runeID, TargetID: blockerID, Relationship: RelBlockedBy, IsInverse: true,
+	})
+}
+
+func (tc *handlerTestContext) rune_no_longer_blocks(sourceID, targetID string) {
+	tc.t.Helper()
+	streamID := "rune-" + sourceID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID], makeEvent(EventDependencyRemoved, DependencyRemoved{
+		RuneID: sourceID, TargetID: targetID, Relationship: RelBlocks,
+	}))
 }
 
 func (tc *handlerTestContext) dependency_exists_in_graph(sourceID, targetID, rel string) {
@@ -1923,6 +2833,15 @@ func (tc *handlerTestContext) rune_in_rune_list(runeID, status string) {
 	tc.projectionStore.data["rune_list:"+runeID] = map[string]string{"id": runeID, "status": status}
 }
 
+func (tc *handlerTestContext) rune_in_rune_list_updated(runeID, status string, updatedAt time.Time) {
+	tc.t.Helper()
+	tc.a_projection_store()
+	entry := map[string]any{"id": runeID, "status": status, "updated_at": updatedAt}
+	raw, _ := json.Marshal(entry)
+	tc.projectionStore.listData["rune_list"] = append(tc.projectionStore.listData["rune_list"], raw)
+	tc.projectionStore.data["rune_list:"+runeID] = entry
+}
+
 func (tc *handlerTestContext) dependency_graph_has_dependents(runeID string, dependentIDs ...string) {
 	tc.t.Helper()
 	tc.a_projection_store()
@@ -1961,7 +2880,7 @@ func (tc *handlerTestContext) handle_update_rune() {
 
 func (tc *handlerTestContext) handle_claim_rune() {
 	tc.t.Helper()
-	tc.err = HandleClaimRune(tc.ctx, tc.realmID, tc.claimCmd, tc.eventStore)
+	tc.err = HandleClaimRune(tc.ctx, tc.realmID, tc.claimCmd, tc.eventStore, tc.projectionStore)
 }
 
 func (tc *handlerTestContext) handle_unclaim_rune() {
@@ -1974,6 +2893,11 @@ func (tc *handlerTestContext) handle_fulfill_rune() {
 	tc.err = HandleFulfillRune(tc.ctx, tc.realmID, tc.fulfillCmd, tc.eventStore)
 }
 
+func (tc *handlerTestContext) handle_record_sla_breach() {
+	tc.t.Helper()
+	tc.err = HandleRecordSLABreach(tc.ctx, tc.realmID, tc.slaBreachCmd, tc.eventStore)
+}
+
 func (tc *handlerTestContext) handle_seal_rune() {
 	tc.t.Helper()
 	tc.err = HandleSealRune(tc.ctx, tc.realmID, tc.sealCmd, tc.eventStore)
@@ -1989,6 +2913,11 @@ func (tc *handlerTestContext) handle_remove_dependency() {
 	tc.err = HandleRemoveDependency(tc.ctx, tc.realmID, tc.removeDepCmd, tc.eventStore, tc.projectionStore)
 }
 
+func (tc *handlerTestContext) handle_link_commits() {
+	tc.t.Helper()
+	tc.linkResult, tc.err = HandleLinkCommits(tc.ctx, tc.realmID, tc.linkCmd, tc.eventStore)
+}
+
 func (tc *handlerTestContext) handle_add_note() {
 	tc.t.Helper()
 	tc.err = HandleAddNote(tc.ctx, tc.realmID, tc.addNoteCmd, tc.eventStore)
@@ -2009,6 +2938,11 @@ func (tc *handlerTestContext) handle_sweep_runes() {
 	tc.sweepResult, tc.err = HandleSweepRunes(tc.ctx, tc.realmID, tc.eventStore, tc.projectionStore)
 }
 
+func (tc *handlerTestContext) handle_sweep_runes_finished_before(before time.Time) {
+	tc.t.Helper()
+	tc.sweepResult, tc.err = HandleSweepRunesFinishedBefore(tc.ctx, tc.realmID, before, tc.eventStore, tc.projectionStore)
+}
+
 // --- Then ---
 
 func (tc *handlerTestContext) no_error() {
@@ -2022,6 +2956,20 @@ func (tc *handlerTestContext) error_contains(substring string) {
 	assert.Contains(tc.t, tc.err.Error(), substring)
 }
 
+func (tc *handlerTestContext) rejection_details_are(expected any) {
+	tc.t.Helper()
+	var ruleErr *RuleError
+	require.ErrorAs(tc.t, tc.err, &ruleErr)
+	assert.Equal(tc.t, expected, ruleErr.Details)
+}
+
+func (tc *handlerTestContext) error_is(rule error) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.ErrorIs(tc.t, tc.err, rule)
+	assert.True(tc.t, IsRejection(tc.err))
+}
+
 func (tc *handlerTestContext) error_is_not_found(entity, id string) {
 	tc.t.Helper()
 	require.Error(tc.t, tc.err)
@@ -2112,6 +3060,11 @@ func (tc *handlerTestContext) created_event_has_parent_id(expected string) {
 	assert.Equal(tc.t, expected, tc.createdEvent.ParentID)
 }
 
+func (tc *handlerTestContext) created_event_has_due_date(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.createdEvent.DueDate)
+}
+
 func (tc *handlerTestContext) created_event_has_branch(expected string) {
 	tc.t.Helper()
 	assert.Equal(tc.t, expected, tc.createdEvent.Branch)
@@ -2136,6 +3089,16 @@ func (tc *handlerTestContext) event_was_appended_with_expected_version(version i
 	assert.Equal(tc.t, version, lastCall.expectedVersion)
 }
 
+func (tc *handlerTestContext) append_call_is(index int, streamID string, expectedVersion int, eventType string) {
+	tc.t.Helper()
+	require.Greater(tc.t, len(tc.eventStore.appendedCalls), index)
+	call := tc.eventStore.appendedCalls[index]
+	assert.Equal(tc.t, streamID, call.streamID)
+	assert.Equal(tc.t, expectedVersion, call.expectedVersion)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, eventType, call.events[0].EventType)
+}
+
 func (tc *handlerTestContext) event_was_appended_to_stream(streamID string) {
 	tc.t.Helper()
 	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
@@ -2161,6 +3124,11 @@ func (tc *handlerTestContext) no_events_were_appended() {
 	assert.Empty(tc.t, tc.eventStore.appendedCalls, "expected no Append calls")
 }
 
+func (tc *handlerTestContext) link_result_has(runeID, sha string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.linkResult.Linked[runeID], sha)
+}
+
 func (tc *handlerTestContext) sweep_result_contains(runeID string) {
 	tc.t.Helper()
 	assert.Contains(tc.t, tc.sweepResult, runeID)
@@ -2177,6 +3145,15 @@ func (tc *handlerTestContext) sweep_result_has_length(n int) {
 	assert.Len(tc.t, tc.sweepResult, n)
 }
 
+func (tc *handlerTestContext) claimed_account_id_is(expected string) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
+	lastCall := tc.eventStore.appendedCalls[len(tc.eventStore.appendedCalls)-1]
+	claimed, ok := lastCall.events[0].Data.(RuneClaimed)
+	require.True(tc.t, ok, "expected RuneClaimed, got %T", lastCall.events[0].Data)
+	assert.Equal(tc.t, expected, claimed.AccountID)
+}
+
 func (tc *handlerTestContext) appended_event_has_type(eventType string) {
 	tc.t.Helper()
 	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
@@ -2294,6 +3271,11 @@ type mockEventStore struct {
 	streams       map[string][]core.Event
 	appendedCalls []appendCall
 	appendErr     error
+	// failNextAppends are returned, in order, by the next Append calls.
+	failNextAppends []error
+	// keepAppends adds appended events to their streams, so later reads
+	// see them.
+	keepAppends bool
 }
 
 func newMockEventStore() *mockEventStore {
@@ -2312,6 +3294,11 @@ func (m *mockEventStore) Append(ctx context.Context, realmID string, streamID st
 	if m.appendErr != nil {
 		return nil, m.appendErr
 	}
+	if len(m.failNextAppends) > 0 {
+		err := m.failNextAppends[0]
+		m.failNextAppends = m.failNextAppends[1:]
+		return nil, err
+	}
 	var result []core.Event
 	for i, ed := range events {
 		dataBytes, _ := json.Marshal(ed.Data)
@@ -2323,6 +3310,9 @@ func (m *mockEventStore) Append(ctx context.Context, realmID string, streamID st
 			Data:      dataBytes,
 		})
 	}
+	if m.keepAppends {
+		m.streams[streamID] = append(m.streams[streamID], result...)
+	}
 	return result, nil
 }
 
@@ -2331,6 +3321,13 @@ func (m *mockEventStore) ReadStream(ctx context.Context, realmID string, streamI
 	if !ok {
 		return []core.Event{}, nil
 	}
+	// Events made without a version are numbered as a store would.
+	events = slices.Clone(events)
+	for i := range events {
+		if events[i].Version == 0 {
+			events[i].Version = i + 1
+		}
+	}
 	return events, nil
 }
 
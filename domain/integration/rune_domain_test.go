@@ -3,7 +3,9 @@ package integration
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/devzeebo/bifrost/core"
 	"github.com/devzeebo/bifrost/domain"
 	"github.com/devzeebo/bifrost/domain/projectors"
 	"github.com/stretchr/testify/assert"
@@ -77,14 +79,14 @@ func TestUpdateRune(t *testing.T) {
 		tc.an_existing_top_level_rune("Old title", 1)
 
 		// When
-		tc.update_rune(strPtr("New title"), nil, intPtr(5))
+		tc.update_rune(strPtr("New title"), nil, intPtr(3))
 
 		// Then
 		tc.no_error()
 		tc.stream_has_event_count(3)
 		tc.stream_has_event_type(2, domain.EventRuneUpdated)
 		tc.rebuilt_state_has_title("New title")
-		tc.rebuilt_state_has_priority(5)
+		tc.rebuilt_state_has_priority(3)
 	})
 }
 
@@ -259,8 +261,6 @@ func TestAddDependency_CycleDetection(t *testing.T) {
 		tc.two_existing_runes("Task A", "Task B")
 		tc.add_dependency(tc.runeIDs[0], tc.runeIDs[1], domain.RelBlocks)
 		tc.no_error()
-		tc.project_all_events()
-		tc.store_cycle_detection_entry(tc.runeIDs[1], tc.runeIDs[0])
 
 		// When
 		tc.add_dependency(tc.runeIDs[1], tc.runeIDs[0], domain.RelBlocks)
@@ -717,6 +717,33 @@ func TestForgeRune_SagaSkipsNonDraft(t *testing.T) {
 	})
 }
 
+func TestTruncateShatteredRunes(t *testing.T) {
+	t.Run("purges a truncated rune after its last event", func(t *testing.T) {
+		tc := newIntegrationTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1")
+		tc.the_clock_is(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		tc.an_existing_claimed_rune("Old task", 1, "alice")
+		tc.fulfill_rune()
+		tc.no_error()
+		tc.shatter_rune()
+		tc.no_error()
+		shatteredVersion := tc.rune_stream_version(tc.createdEvent.ID)
+		tc.truncate_shattered_runes(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		tc.no_error()
+		tc.rune_stream_has_event_count(tc.createdEvent.ID, 1)
+
+		// When
+		tc.purge_rune()
+
+		// Then
+		tc.no_error()
+		tc.rune_stream_has_event_type(tc.createdEvent.ID, domain.EventRunePurged)
+		assert.Equal(t, shatteredVersion+1, tc.rune_stream_version(tc.createdEvent.ID))
+	})
+}
+
 // --- Test Context ---
 
 type integrationTestContext struct {
@@ -750,6 +777,11 @@ func (tc *integrationTestContext) a_realm(realmID string) {
 	tc.realmID = realmID
 }
 
+func (tc *integrationTestContext) the_clock_is(now time.Time) {
+	tc.t.Helper()
+	tc.ctx = core.ContextWithClock(context.Background(), core.FixedClock(now))
+}
+
 func (tc *integrationTestContext) an_existing_top_level_rune(title string, priority int) {
 	tc.t.Helper()
 	branch := "test-branch"
@@ -780,7 +812,7 @@ func (tc *integrationTestContext) an_existing_claimed_rune(title string, priorit
 	tc.an_existing_top_level_rune(title, priority)
 	tc.err = domain.HandleClaimRune(tc.ctx, tc.realmID, domain.ClaimRune{
 		ID: tc.createdEvent.ID, Claimant: claimant,
-	}, tc.stack.EventStore)
+	}, tc.stack.EventStore, tc.stack.ProjectionStore)
 	require.NoError(tc.t, tc.err)
 }
 
@@ -808,13 +840,6 @@ func (tc *integrationTestContext) two_existing_runes(titleA, titleB string) {
 	require.NoError(tc.t, err)
 }
 
-func (tc *integrationTestContext) store_cycle_detection_entry(sourceID, targetID string) {
-	tc.t.Helper()
-	cycleKey := "cycle:" + sourceID + ":" + targetID
-	err := tc.stack.ProjectionStore.Put(tc.ctx, tc.realmID, "dependency_graph", cycleKey, true)
-	require.NoError(tc.t, err)
-}
-
 // seed_handler_dep_lookup seeds the dep lookup key that the DependencyGraphProjector
 // would normally create, so the handler can find it without replaying all events.
 func (tc *integrationTestContext) seed_handler_dep_lookup(sourceID, targetID, relationship string) {
@@ -869,14 +894,14 @@ func (tc *integrationTestContext) claim_rune(claimant string) {
 	tc.t.Helper()
 	tc.err = domain.HandleClaimRune(tc.ctx, tc.realmID, domain.ClaimRune{
 		ID: tc.createdEvent.ID, Claimant: claimant,
-	}, tc.stack.EventStore)
+	}, tc.stack.EventStore, tc.stack.ProjectionStore)
 }
 
 func (tc *integrationTestContext) claim_specific_rune(runeID, claimant string) {
 	tc.t.Helper()
 	tc.err = domain.HandleClaimRune(tc.ctx, tc.realmID, domain.ClaimRune{
 		ID: runeID, Claimant: claimant,
-	}, tc.stack.EventStore)
+	}, tc.stack.EventStore, tc.stack.ProjectionStore)
 }
 
 func (tc *integrationTestContext) fulfill_rune() {
@@ -900,6 +925,29 @@ func (tc *integrationTestContext) seal_rune(reason string) {
 	}, tc.stack.EventStore)
 }
 
+func (tc *integrationTestContext) shatter_rune() {
+	tc.t.Helper()
+	tc.err = domain.HandleShatterRune(tc.ctx, tc.realmID, domain.ShatterRune{
+		ID: tc.createdEvent.ID,
+	}, tc.stack.EventStore)
+}
+
+func (tc *integrationTestContext) truncate_shattered_runes(before time.Time) {
+	tc.t.Helper()
+	truncator, ok := tc.stack.EventStore.(core.EventTruncator)
+	require.True(tc.t, ok, "event store cannot truncate streams")
+	_, tc.err = domain.HandleTruncateShatteredRunes(tc.ctx, tc.realmID, domain.TruncateShatteredRunes{
+		Before: before,
+	}, tc.stack.EventStore, truncator)
+}
+
+func (tc *integrationTestContext) purge_rune() {
+	tc.t.Helper()
+	tc.err = domain.HandlePurgeRune(tc.ctx, tc.realmID, domain.PurgeRune{
+		ID: tc.createdEvent.ID,
+	}, tc.stack.EventStore)
+}
+
 func (tc *integrationTestContext) add_dependency(sourceID, targetID, relationship string) {
 	tc.t.Helper()
 	tc.err = domain.HandleAddDependency(tc.ctx, tc.realmID, domain.AddDependency{
@@ -1306,5 +1354,13 @@ func (tc *integrationTestContext) rebuild_state(runeID string) domain.RuneState
 	return domain.RebuildRuneState(events)
 }
 
+func (tc *integrationTestContext) rune_stream_version(runeID string) int {
+	tc.t.Helper()
+	events, err := tc.stack.EventStore.ReadStream(tc.ctx, tc.realmID, "rune-"+runeID, 0)
+	require.NoError(tc.t, err)
+	require.NotEmpty(tc.t, events)
+	return events[len(events)-1].Version
+}
+
 func strPtr(s string) *string { return &s }
 func intPtr(i int) *int       { return &i }
@@ -0,0 +1,10 @@
+package domain
+
+// SetNotificationPreferences replaces an account's notification preferences.
+// Muted maps a channel name to the notification kinds the account does not
+// want delivered there; kinds not listed stay on.
+type SetNotificationPreferences struct {
+	AccountID string              `json:"account_id"`
+	Muted     map[string][]string `json:"muted"`
+	Digest    string              `json:"digest"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+const (
+	EventNotificationPreferencesSet = "NotificationPreferencesSet"
+)
+
+type NotificationPreferencesSet struct {
+	AccountID string              `json:"account_id"`
+	Muted     map[string][]string `json:"muted,omitempty"`
+	Digest    string              `json:"digest"`
+}
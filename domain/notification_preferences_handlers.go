@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const notificationPreferencesStreamPrefix = "notify-prefs-"
+
+// Digest frequencies an account can choose.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// NotificationChannels lists the channels an account can mute kinds on.
+var NotificationChannels = []string{"slack", "discord"}
+
+// NotificationKinds lists the notification kinds an account can mute.
+var NotificationKinds = []string{"created", "claimed", "fulfilled", "mention", "escalated"}
+
+type NotificationPreferencesState struct {
+	AccountID string
+	Muted     map[string][]string
+	Digest    string
+	Exists    bool
+}
+
+func RebuildNotificationPreferencesState(events []core.Event) NotificationPreferencesState {
+	state := NotificationPreferencesState{
+		Muted:  make(map[string][]string),
+		Digest: DigestOff,
+	}
+
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventNotificationPreferencesSet:
+			var data NotificationPreferencesSet
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.AccountID = data.AccountID
+			state.Muted = data.Muted
+			if state.Muted == nil {
+				state.Muted = make(map[string][]string)
+			}
+			state.Digest = data.Digest
+		}
+	}
+	return state
+}
+
+func notificationPreferencesStreamID(accountID string) string {
+	return notificationPreferencesStreamPrefix + accountID
+}
+
+// HandleSetNotificationPreferences validates and records an active account's
+// notification preferences. Preferences live in their own stream so changing
+// them never conflicts with account administration.
+func HandleSetNotificationPreferences(ctx context.Context, cmd SetNotificationPreferences, store core.EventStore) error {
+	account, _, err := readAndRebuildAccountState(ctx, cmd.AccountID, store)
+	if err != nil {
+		return err
+	}
+	if err := requireActiveAccount(account, cmd.AccountID); err != nil {
+		return err
+	}
+
+	set := NotificationPreferencesSet{
+		AccountID: cmd.AccountID,
+		Muted:     make(map[string][]string),
+		Digest:    cmd.Digest,
+	}
+	if set.Digest == "" {
+		set.Digest = DigestOff
+	}
+	if !slices.Contains([]string{DigestOff, DigestDaily, DigestWeekly}, set.Digest) {
+		return Rejectf(ErrInvalidCommand, "unknown digest frequency %q", cmd.Digest)
+	}
+	for channel, kinds := range cmd.Muted {
+		if !slices.Contains(NotificationChannels, channel) {
+			return Rejectf(ErrInvalidCommand, "unknown notification channel %q", channel)
+		}
+		for _, kind := range kinds {
+			if !slices.Contains(NotificationKinds, kind) {
+				return Rejectf(ErrInvalidCommand, "unknown notification kind %q", kind)
+			}
+			if !slices.Contains(set.Muted[channel], kind) {
+				set.Muted[channel] = append(set.Muted[channel], kind)
+			}
+		}
+	}
+
+	streamID := notificationPreferencesStreamID(cmd.AccountID)
+	events, err := store.ReadStream(ctx, AdminRealmID, streamID, 0)
+	if err != nil {
+		return err
+	}
+	_, err = store.Append(ctx, AdminRealmID, streamID, len(events), []core.EventData{
+		{EventType: EventNotificationPreferencesSet, Data: set},
+	})
+	return err
+}
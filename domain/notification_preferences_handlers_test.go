@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRebuildNotificationPreferencesState(t *testing.T) {
+	t.Run("defaults to nothing muted and no digest", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// When
+		tc.state_is_rebuilt()
+
+		// Then
+		assert.False(t, tc.state.Exists)
+		assert.Empty(t, tc.state.Muted)
+		assert.Equal(t, DigestOff, tc.state.Digest)
+	})
+
+	t.Run("keeps the latest preferences", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.preferences_were_set(NotificationPreferencesSet{AccountID: "acct-1", Digest: DigestDaily})
+		tc.preferences_were_set(NotificationPreferencesSet{AccountID: "acct-1", Muted: map[string][]string{"slack": {"created"}}, Digest: DigestWeekly})
+
+		// When
+		tc.state_is_rebuilt()
+
+		// Then
+		assert.True(t, tc.state.Exists)
+		assert.Equal(t, map[string][]string{"slack": {"created"}}, tc.state.Muted)
+		assert.Equal(t, DigestWeekly, tc.state.Digest)
+	})
+}
+
+func TestHandleSetNotificationPreferences(t *testing.T) {
+	t.Run("records the preferences on the account's preference stream", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.an_account("acct-1", "active")
+		tc.preferences_were_set(NotificationPreferencesSet{AccountID: "acct-1", Digest: DigestDaily})
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{
+			AccountID: "acct-1",
+			Muted:     map[string][]string{"slack": {"created", "created", "claimed"}},
+		})
+
+		// Then
+		require.NoError(t, tc.err)
+		tc.preferences_were_appended(1, NotificationPreferencesSet{
+			AccountID: "acct-1",
+			Muted:     map[string][]string{"slack": {"created", "claimed"}},
+			Digest:    DigestOff,
+		})
+	})
+
+	t.Run("rejects an unknown channel", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.an_account("acct-1", "active")
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{AccountID: "acct-1", Muted: map[string][]string{"pager": {"created"}}})
+
+		// Then
+		tc.rejected_with(ErrInvalidCommand)
+	})
+
+	t.Run("rejects an unknown kind", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.an_account("acct-1", "active")
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{AccountID: "acct-1", Muted: map[string][]string{"slack": {"deleted"}}})
+
+		// Then
+		tc.rejected_with(ErrInvalidCommand)
+	})
+
+	t.Run("rejects an unknown digest frequency", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.an_account("acct-1", "active")
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{AccountID: "acct-1", Digest: "hourly"})
+
+		// Then
+		tc.rejected_with(ErrInvalidCommand)
+	})
+
+	t.Run("rejects a suspended account", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.an_account("acct-1", "suspended")
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{AccountID: "acct-1"})
+
+		// Then
+		tc.rejected_with(ErrSuspended)
+	})
+
+	t.Run("returns not found for a missing account", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// When
+		tc.preferences_are_set(SetNotificationPreferences{AccountID: "acct-missing"})
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, tc.err, &nfe)
+	})
+}
+
+// --- Test Context ---
+
+type notificationPreferencesTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	state      NotificationPreferencesState
+	err        error
+}
+
+func newNotificationPreferencesTestContext(t *testing.T) *notificationPreferencesTestContext {
+	t.Helper()
+	return &notificationPreferencesTestContext{t: t, eventStore: newMockEventStore()}
+}
+
+// --- Given ---
+
+func (tc *notificationPreferencesTestContext) an_account(accountID, status string) {
+	tc.t.Helper()
+	events := []core.Event{
+		makeEvent(EventAccountCreated, AccountCreated{AccountID: accountID, Username: "alice"}),
+	}
+	if status == "suspended" {
+		events = append(events, makeEvent(EventAccountSuspended, AccountSuspended{AccountID: accountID}))
+	}
+	tc.eventStore.streams["account-"+accountID] = events
+}
+
+func (tc *notificationPreferencesTestContext) preferences_were_set(data NotificationPreferencesSet) {
+	tc.t.Helper()
+	streamID := "notify-prefs-" + data.AccountID
+	tc.eventStore.streams[streamID] = append(tc.eventStore.streams[streamID], makeEvent(EventNotificationPreferencesSet, data))
+}
+
+// --- When ---
+
+func (tc *notificationPreferencesTestContext) state_is_rebuilt() {
+	tc.t.Helper()
+	tc.state = RebuildNotificationPreferencesState(tc.eventStore.streams["notify-prefs-acct-1"])
+}
+
+func (tc *notificationPreferencesTestContext) preferences_are_set(cmd SetNotificationPreferences) {
+	tc.t.Helper()
+	tc.err = HandleSetNotificationPreferences(context.Background(), cmd, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *notificationPreferencesTestContext) preferences_were_appended(expectedVersion int, expected NotificationPreferencesSet) {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, AdminRealmID, call.realmID)
+	assert.Equal(tc.t, "notify-prefs-"+expected.AccountID, call.streamID)
+	assert.Equal(tc.t, expectedVersion, call.expectedVersion)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, EventNotificationPreferencesSet, call.events[0].EventType)
+	data, err := json.Marshal(call.events[0].Data)
+	require.NoError(tc.t, err)
+	var got NotificationPreferencesSet
+	require.NoError(tc.t, json.Unmarshal(data, &got))
+	assert.Equal(tc.t, expected, got)
+}
+
+func (tc *notificationPreferencesTestContext) rejected_with(rule error) {
+	tc.t.Helper()
+	assert.ErrorIs(tc.t, tc.err, rule)
+	assert.Empty(tc.t, tc.eventStore.appendedCalls)
+}
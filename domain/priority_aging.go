@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriorityAgingSetting is a comma-separated list of ages, such as "14d,30d".
+// An open rune whose priority has not been set for longer than each of them
+// gains one step of priority, stopping at 0.
+const PriorityAgingSetting = "priority_aging"
+
+// PriorityAgingActor marks the RuneUpdated events appended by the priority
+// aging policy, so they do not restart the rune's aging.
+const PriorityAgingActor = "policy:priority_aging"
+
+// ParsePriorityAging reads the thresholds of a priority_aging setting, each
+// written as an SLA threshold is. They must be in increasing order.
+func ParsePriorityAging(value string) ([]time.Duration, error) {
+	var thresholds []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := ParseSLAThreshold(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority aging threshold %q", strings.TrimSpace(part))
+		}
+		if n := len(thresholds); n > 0 && d <= thresholds[n-1] {
+			return nil, fmt.Errorf("priority aging thresholds must increase, but %q does not", strings.TrimSpace(part))
+		}
+		thresholds = append(thresholds, d)
+	}
+	return thresholds, nil
+}
+
+// PriorityAgingFromSettings returns the realm's aging thresholds, or nil when
+// the realm has no valid priority_aging setting.
+func PriorityAgingFromSettings(settings map[string]string) []time.Duration {
+	value, ok := settings[PriorityAgingSetting]
+	if !ok {
+		return nil
+	}
+	thresholds, err := ParsePriorityAging(value)
+	if err != nil {
+		return nil
+	}
+	return thresholds
+}
+
+// PriorityAgingSteps returns how many thresholds a rune whose priority was
+// set at setAt has passed by now.
+func PriorityAgingSteps(thresholds []time.Duration, setAt, now time.Time) int {
+	steps := 0
+	for _, d := range thresholds {
+		if now.Sub(setAt) >= d {
+			steps++
+		}
+	}
+	return steps
+}
+
+func validatePriorityAgingSetting(value string) error {
+	if _, err := ParsePriorityAging(value); err != nil {
+		return Rejectf(ErrInvalidCommand, "%v", err)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 type AccountListEntry struct {
 	AccountID string            `json:"account_id"`
 	Username  string            `json:"username"`
+	Source    string            `json:"source,omitempty"`
 	Status    string            `json:"status"`
 	Realms    []string          `json:"realms"`
 	Roles     map[string]string `json:"roles"`
@@ -29,6 +30,10 @@ func (p *AccountListProjector) Name() string {
 	return "account_list"
 }
 
+func (p *AccountListProjector) Projections() []string {
+	return []string{"account_list"}
+}
+
 func (p *AccountListProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	switch event.EventType {
 	case domain.EventAccountCreated:
@@ -47,6 +52,12 @@ func (p *AccountListProjector) Handle(ctx context.Context, event core.Event, sto
 		return p.handlePATCreated(ctx, event, store)
 	case domain.EventPATRevoked:
 		return p.handlePATRevoked(ctx, event, store)
+	case domain.EventAccountPurged:
+		var data domain.AccountPurged
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, "_admin", "account_list", data.AccountID)
 	}
 	return nil
 }
@@ -67,6 +78,7 @@ func (p *AccountListProjector) handleAccountCreated(ctx context.Context, event c
 	entry := AccountListEntry{
 		AccountID: data.AccountID,
 		Username:  data.Username,
+		Source:    data.Source,
 		Status:    "active",
 		Realms:    []string{},
 		Roles:     map[string]string{},
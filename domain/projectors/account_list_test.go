@@ -66,6 +66,23 @@ func TestAccountListProjector(t *testing.T) {
 		tc.account_entry_has_username("acct-1", "alice")
 	})
 
+	t.Run("handles AccountPurged by deleting the entry", func(t *testing.T) {
+		tc := newAccountListTestContext(t)
+
+		// Given
+		tc.an_account_list_projector()
+		tc.a_projection_store()
+		tc.existing_account_entry("acct-1", "alice", "suspended")
+		tc.an_account_purged_event("acct-1")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.account_entry_does_not_exist("acct-1")
+	})
+
 	t.Run("handles RealmGranted by appending realm to list", func(t *testing.T) {
 		tc := newAccountListTestContext(t)
 
@@ -166,6 +183,22 @@ func TestAccountListProjector(t *testing.T) {
 		tc.account_entry_has_roles("acct-1", map[string]string{})
 	})
 
+	t.Run("handles AccountCreated by recording the managing source", func(t *testing.T) {
+		tc := newAccountListTestContext(t)
+
+		// Given
+		tc.an_account_list_projector()
+		tc.a_projection_store()
+		tc.event = makeEvent(domain.EventAccountCreated, domain.AccountCreated{AccountID: "acct-1", Username: "alice", Source: "directory"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.account_entry_has_source("acct-1", "directory")
+	})
+
 	t.Run("handles RoleAssigned by adding realm and setting role", func(t *testing.T) {
 		tc := newAccountListTestContext(t)
 
@@ -305,6 +338,11 @@ func (tc *accountListTestContext) an_account_suspended_event(accountID string) {
 	})
 }
 
+func (tc *accountListTestContext) an_account_purged_event(accountID string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventAccountPurged, domain.AccountPurged{AccountID: accountID})
+}
+
 func (tc *accountListTestContext) a_realm_granted_event(accountID, realmID string) {
 	tc.t.Helper()
 	tc.event = makeEvent(domain.EventRealmGranted, domain.RealmGranted{
@@ -458,6 +496,13 @@ func (tc *accountListTestContext) account_entry_exists(accountID string) {
 	require.NoError(tc.t, err, "expected account list entry for %s", accountID)
 }
 
+func (tc *accountListTestContext) account_entry_does_not_exist(accountID string) {
+	tc.t.Helper()
+	var entry AccountListEntry
+	err := tc.store.Get(tc.ctx, "_admin", "account_list", accountID, &entry)
+	assert.Error(tc.t, err, "expected no account list entry for %s", accountID)
+}
+
 func (tc *accountListTestContext) account_entry_has_username(accountID, expected string) {
 	tc.t.Helper()
 	var entry AccountListEntry
@@ -466,6 +511,14 @@ func (tc *accountListTestContext) account_entry_has_username(accountID, expected
 	assert.Equal(tc.t, expected, entry.Username)
 }
 
+func (tc *accountListTestContext) account_entry_has_source(accountID, expected string) {
+	tc.t.Helper()
+	var entry AccountListEntry
+	err := tc.store.Get(tc.ctx, "_admin", "account_list", accountID, &entry)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, entry.Source)
+}
+
 func (tc *accountListTestContext) account_entry_has_status(accountID, expected string) {
 	tc.t.Helper()
 	var entry AccountListEntry
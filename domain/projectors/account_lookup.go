@@ -33,6 +33,10 @@ func (p *AccountLookupProjector) Name() string {
 	return "account_lookup"
 }
 
+func (p *AccountLookupProjector) Projections() []string {
+	return []string{"account_lookup"}
+}
+
 func (p *AccountLookupProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	switch event.EventType {
 	case domain.EventAccountCreated:
@@ -51,6 +55,10 @@ func (p *AccountLookupProjector) Handle(ctx context.Context, event core.Event, s
 		return p.handlePATCreated(ctx, event, store)
 	case domain.EventPATRevoked:
 		return p.handlePATRevoked(ctx, event, store)
+	case domain.EventCalendarTokenIssued:
+		return p.handleCalendarTokenIssued(ctx, event, store)
+	case domain.EventAccountPurged:
+		return p.handleAccountPurged(ctx, event, store)
 	}
 	return nil
 }
@@ -396,6 +404,83 @@ func (p *AccountLookupProjector) handlePATRevoked(ctx context.Context, event cor
 	return store.Delete(ctx, "_admin", "account_lookup", "keyhash_pat:"+keyHash)
 }
 
+func (p *AccountLookupProjector) handleCalendarTokenIssued(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.CalendarTokenIssued
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+
+	// Drop the previous token so only the latest one resolves
+	var previous string
+	if err := store.Get(ctx, "_admin", "account_lookup", "calendartoken:"+data.AccountID, &previous); err == nil && previous != data.KeyHash {
+		if err := store.Delete(ctx, "_admin", "account_lookup", "calendar:"+previous); err != nil {
+			return err
+		}
+	}
+
+	// Calendar hashes are prefixed so they never authenticate as PATs
+	if err := store.Put(ctx, "_admin", "account_lookup", "calendar:"+data.KeyHash, data.AccountID); err != nil {
+		return err
+	}
+	return store.Put(ctx, "_admin", "account_lookup", "calendartoken:"+data.AccountID, data.KeyHash)
+}
+
+// handleAccountPurged drops every key kept for the account, so neither its
+// username nor its tokens resolve any more.
+func (p *AccountLookupProjector) handleAccountPurged(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.AccountPurged
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+
+	var keys []string
+	var info accountInfo
+	if err := store.Get(ctx, "_admin", "account_lookup", "accountinfo:"+data.AccountID, &info); err == nil {
+		keys = append(keys, "username:"+info.Username)
+	}
+	var hashes []string
+	_ = store.Get(ctx, "_admin", "account_lookup", "account:"+data.AccountID, &hashes)
+	for _, hash := range hashes {
+		var patID string
+		if err := store.Get(ctx, "_admin", "account_lookup", "keyhash_pat:"+hash, &patID); err == nil {
+			keys = append(keys, "pat:"+patID)
+		}
+		keys = append(keys, hash, "keyhash_pat:"+hash)
+	}
+	var calendarHash string
+	if err := store.Get(ctx, "_admin", "account_lookup", "calendartoken:"+data.AccountID, &calendarHash); err == nil {
+		keys = append(keys, "calendar:"+calendarHash)
+	}
+	keys = append(keys, "accountinfo:"+data.AccountID, "account:"+data.AccountID, "calendartoken:"+data.AccountID)
+
+	for _, key := range keys {
+		if err := store.Delete(ctx, "_admin", "account_lookup", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCalendarAccount resolves a calendar feed token hash to the account it
+// was issued to.
+func GetCalendarAccount(ctx context.Context, store core.ProjectionStore, keyHash string) (AccountLookupEntry, error) {
+	var accountID string
+	if err := store.Get(ctx, "_admin", "account_lookup", "calendar:"+keyHash, &accountID); err != nil {
+		return AccountLookupEntry{}, err
+	}
+	var info accountInfo
+	if err := store.Get(ctx, "_admin", "account_lookup", "accountinfo:"+accountID, &info); err != nil {
+		return AccountLookupEntry{}, err
+	}
+	return AccountLookupEntry{
+		AccountID: accountID,
+		Username:  info.Username,
+		Status:    info.Status,
+		Realms:    info.Realms,
+		Roles:     info.Roles,
+	}, nil
+}
+
 func removeString(slice []string, s string) []string {
 	result := make([]string, 0, len(slice))
 	for _, v := range slice {
@@ -223,6 +223,52 @@ func TestAccountLookupProjector(t *testing.T) {
 		tc.account_pat_list_contains("acct-1", "hash-def")
 	})
 
+	t.Run("handles CalendarTokenIssued by replacing the previous calendar token", func(t *testing.T) {
+		tc := newAccountLookupTestContext(t)
+
+		// Given
+		tc.an_account_lookup_projector()
+		tc.a_projection_store()
+		tc.existing_account_info("acct-1", "alice", "active", []string{"realm-1"})
+		tc.a_calendar_token_issued_event("acct-1", "cal-old")
+		tc.handle_is_called()
+		tc.a_calendar_token_issued_event("acct-1", "cal-new")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.calendar_token_resolves_to("cal-new", "acct-1", "alice", []string{"realm-1"})
+		tc.calendar_token_does_not_resolve("cal-old")
+		tc.pat_entry_does_not_exist("cal-new")
+	})
+
+	t.Run("handles AccountPurged by dropping every key kept for the account", func(t *testing.T) {
+		tc := newAccountLookupTestContext(t)
+
+		// Given
+		tc.an_account_lookup_projector()
+		tc.a_projection_store()
+		tc.an_account_created_event("acct-1", "alice")
+		tc.handle_is_called()
+		tc.a_pat_created_event("acct-1", "pat-1", "hash-abc")
+		tc.handle_is_called()
+		tc.a_calendar_token_issued_event("acct-1", "cal-1")
+		tc.handle_is_called()
+		tc.an_account_purged_event("acct-1")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.username_lookup_does_not_exist("alice")
+		tc.pat_entry_does_not_exist("hash-abc")
+		tc.calendar_token_does_not_resolve("cal-1")
+		tc.store_is_empty()
+	})
+
 	t.Run("ignores unknown event types", func(t *testing.T) {
 		tc := newAccountLookupTestContext(t)
 
@@ -533,6 +579,19 @@ func (tc *accountLookupTestContext) a_pat_revoked_event(accountID, patID, keyHas
 	tc.store.put("_admin", "account_lookup", "pat:"+patID, keyHash)
 }
 
+func (tc *accountLookupTestContext) a_calendar_token_issued_event(accountID, keyHash string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventCalendarTokenIssued, domain.CalendarTokenIssued{
+		AccountID: accountID,
+		KeyHash:   keyHash,
+	})
+}
+
+func (tc *accountLookupTestContext) an_account_purged_event(accountID string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventAccountPurged, domain.AccountPurged{AccountID: accountID})
+}
+
 func (tc *accountLookupTestContext) an_unknown_event() {
 	tc.t.Helper()
 	tc.event = core.Event{EventType: "UnknownEvent", Data: []byte(`{}`)}
@@ -648,6 +707,18 @@ func (tc *accountLookupTestContext) username_lookup_has_account_id(username, exp
 	assert.Equal(tc.t, expectedAccountID, accountID)
 }
 
+func (tc *accountLookupTestContext) username_lookup_does_not_exist(username string) {
+	tc.t.Helper()
+	var accountID string
+	err := tc.store.Get(tc.ctx, "_admin", "account_lookup", "username:"+username, &accountID)
+	assert.Error(tc.t, err, "expected no username lookup for %s", username)
+}
+
+func (tc *accountLookupTestContext) store_is_empty() {
+	tc.t.Helper()
+	assert.Empty(tc.t, tc.store.data)
+}
+
 func (tc *accountLookupTestContext) pat_entry_exists(keyHash string) {
 	tc.t.Helper()
 	var entry AccountLookupEntry
@@ -741,3 +812,19 @@ func (tc *accountLookupTestContext) pat_entry_has_roles(keyHash string, expected
 	require.NoError(tc.t, err)
 	assert.Equal(tc.t, expected, entry.Roles)
 }
+
+func (tc *accountLookupTestContext) calendar_token_resolves_to(keyHash, accountID, username string, realms []string) {
+	tc.t.Helper()
+	entry, err := GetCalendarAccount(tc.ctx, tc.store, keyHash)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, accountID, entry.AccountID)
+	assert.Equal(tc.t, username, entry.Username)
+	assert.Equal(tc.t, realms, entry.Realms)
+}
+
+func (tc *accountLookupTestContext) calendar_token_does_not_resolve(keyHash string) {
+	tc.t.Helper()
+	_, err := GetCalendarAccount(tc.ctx, tc.store, keyHash)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
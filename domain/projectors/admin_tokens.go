@@ -0,0 +1,93 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// AdminTokenEntry describes a live admin token. It is stored in the admin
+// realm's admin_tokens projection by token ID, and the token ID is stored
+// in admin_token_hashes by the hash of its secret.
+type AdminTokenEntry struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	IssuedAt  time.Time `json:"issued_at"`
+	RotatedAt time.Time `json:"rotated_at,omitzero"`
+}
+
+type AdminTokensProjector struct{}
+
+func NewAdminTokensProjector() *AdminTokensProjector {
+	return &AdminTokensProjector{}
+}
+
+func (p *AdminTokensProjector) Name() string {
+	return "admin_tokens"
+}
+
+func (p *AdminTokensProjector) Projections() []string {
+	return []string{"admin_tokens", "admin_token_hashes"}
+}
+
+func (p *AdminTokensProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventAdminTokenIssued:
+		var data domain.AdminTokenIssued
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := AdminTokenEntry{ID: data.TokenID, Name: data.Name, IssuedAt: data.IssuedAt}
+		if err := store.Put(ctx, domain.AdminRealmID, "admin_tokens", data.TokenID, entry); err != nil {
+			return err
+		}
+		return store.Put(ctx, domain.AdminRealmID, "admin_token_hashes", data.KeyHash, data.TokenID)
+	case domain.EventAdminTokenRotated:
+		var data domain.AdminTokenRotated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry, err := GetAdminToken(ctx, store, data.TokenID)
+		if err != nil {
+			return err
+		}
+		entry.RotatedAt = data.RotatedAt
+		if err := store.Put(ctx, domain.AdminRealmID, "admin_tokens", data.TokenID, entry); err != nil {
+			return err
+		}
+		if err := store.Delete(ctx, domain.AdminRealmID, "admin_token_hashes", data.PreviousKeyHash); err != nil && !isNotFoundError(err) {
+			return err
+		}
+		return store.Put(ctx, domain.AdminRealmID, "admin_token_hashes", data.KeyHash, data.TokenID)
+	case domain.EventAdminTokenRevoked:
+		var data domain.AdminTokenRevoked
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		if err := store.Delete(ctx, domain.AdminRealmID, "admin_token_hashes", data.KeyHash); err != nil && !isNotFoundError(err) {
+			return err
+		}
+		return store.Delete(ctx, domain.AdminRealmID, "admin_tokens", data.TokenID)
+	}
+	return nil
+}
+
+// GetAdminToken returns the live admin token with the given ID.
+func GetAdminToken(ctx context.Context, store core.ProjectionStore, tokenID string) (AdminTokenEntry, error) {
+	var entry AdminTokenEntry
+	err := store.Get(ctx, domain.AdminRealmID, "admin_tokens", tokenID, &entry)
+	return entry, err
+}
+
+// GetAdminTokenByKeyHash returns the live admin token whose secret hashes
+// to keyHash.
+func GetAdminTokenByKeyHash(ctx context.Context, store core.ProjectionStore, keyHash string) (AdminTokenEntry, error) {
+	var tokenID string
+	if err := store.Get(ctx, domain.AdminRealmID, "admin_token_hashes", keyHash, &tokenID); err != nil {
+		return AdminTokenEntry{}, err
+	}
+	return GetAdminToken(ctx, store, tokenID)
+}
@@ -0,0 +1,138 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAdminTokensProjector(t *testing.T) {
+	t.Run("Name returns admin_tokens", func(t *testing.T) {
+		tc := newAdminTokensTestContext(t)
+
+		// Then
+		assert.Equal(t, "admin_tokens", tc.projector.Name())
+	})
+
+	t.Run("handles AdminTokenIssued by storing entry and hash lookup", func(t *testing.T) {
+		tc := newAdminTokensTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventAdminTokenIssued, domain.AdminTokenIssued{
+			TokenID: "adm-a1b2c3d4", Name: "provisioner", KeyHash: "h1", IssuedAt: tc.now,
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.token_for_hash("h1")
+		assert.Equal(t, "adm-a1b2c3d4", entry.ID)
+		assert.Equal(t, "provisioner", entry.Name)
+		assert.True(t, entry.IssuedAt.Equal(tc.now))
+	})
+
+	t.Run("handles AdminTokenRotated by swapping the hash lookup", func(t *testing.T) {
+		tc := newAdminTokensTestContext(t)
+
+		// Given
+		tc.token_is_stored("adm-a1b2c3d4", "h1")
+		tc.event = makeEvent(domain.EventAdminTokenRotated, domain.AdminTokenRotated{
+			TokenID: "adm-a1b2c3d4", KeyHash: "h2", PreviousKeyHash: "h1", RotatedAt: tc.now,
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.hash_is_unknown("h1")
+		entry := tc.token_for_hash("h2")
+		assert.True(t, entry.RotatedAt.Equal(tc.now))
+	})
+
+	t.Run("handles AdminTokenRevoked by deleting entry and hash lookup", func(t *testing.T) {
+		tc := newAdminTokensTestContext(t)
+
+		// Given
+		tc.token_is_stored("adm-a1b2c3d4", "h1")
+		tc.event = makeEvent(domain.EventAdminTokenRevoked, domain.AdminTokenRevoked{TokenID: "adm-a1b2c3d4", KeyHash: "h1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.hash_is_unknown("h1")
+		_, err := GetAdminToken(tc.ctx, tc.store, "adm-a1b2c3d4")
+		assert.True(t, isNotFoundError(err))
+	})
+}
+
+// --- Test Context ---
+
+type adminTokensTestContext struct {
+	t *testing.T
+
+	projector *AdminTokensProjector
+	store     *mockProjectionStore
+	ctx       context.Context
+	now       time.Time
+
+	event core.Event
+	err   error
+}
+
+func newAdminTokensTestContext(t *testing.T) *adminTokensTestContext {
+	t.Helper()
+	return &adminTokensTestContext{
+		t:         t,
+		projector: NewAdminTokensProjector(),
+		store:     newMockProjectionStore(),
+		ctx:       context.Background(),
+		now:       time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// --- Given ---
+
+func (tc *adminTokensTestContext) token_is_stored(tokenID, keyHash string) {
+	tc.t.Helper()
+	tc.store.put(domain.AdminRealmID, "admin_tokens", tokenID, AdminTokenEntry{ID: tokenID, Name: "provisioner"})
+	tc.store.put(domain.AdminRealmID, "admin_token_hashes", keyHash, tokenID)
+}
+
+// --- When ---
+
+func (tc *adminTokensTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(tc.ctx, tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *adminTokensTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *adminTokensTestContext) token_for_hash(keyHash string) AdminTokenEntry {
+	tc.t.Helper()
+	entry, err := GetAdminTokenByKeyHash(tc.ctx, tc.store, keyHash)
+	require.NoError(tc.t, err)
+	return entry
+}
+
+func (tc *adminTokensTestContext) hash_is_unknown(keyHash string) {
+	tc.t.Helper()
+	_, err := GetAdminTokenByKeyHash(tc.ctx, tc.store, keyHash)
+	assert.True(tc.t, isNotFoundError(err), "expected not found, got %v", err)
+}
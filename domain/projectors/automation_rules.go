@@ -0,0 +1,70 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// AutomationRuleEntry is stored under the realm the rule applies to.
+type AutomationRuleEntry struct {
+	ID        string    `json:"id"`
+	RealmID   string    `json:"realm_id"`
+	Name      string    `json:"name"`
+	Trigger   string    `json:"trigger"`
+	Match     string    `json:"match,omitempty"`
+	Action    string    `json:"action"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AutomationRulesProjector struct{}
+
+func NewAutomationRulesProjector() *AutomationRulesProjector {
+	return &AutomationRulesProjector{}
+}
+
+func (p *AutomationRulesProjector) Name() string {
+	return "automation_rules"
+}
+
+func (p *AutomationRulesProjector) Projections() []string {
+	return []string{"automation_rules"}
+}
+
+// SpansRealms reports true: rules are appended to the admin realm but
+// listed under the realm they apply to.
+func (p *AutomationRulesProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *AutomationRulesProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventAutomationRuleAdded:
+		var data domain.AutomationRuleAdded
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := AutomationRuleEntry{
+			ID:        data.RuleID,
+			RealmID:   data.RealmID,
+			Name:      data.Name,
+			Trigger:   data.Trigger,
+			Match:     data.Match,
+			Action:    data.Action,
+			Value:     data.Value,
+			CreatedAt: data.CreatedAt,
+		}
+		return store.Put(ctx, data.RealmID, "automation_rules", data.RuleID, entry)
+	case domain.EventAutomationRuleRemoved:
+		var data domain.AutomationRuleRemoved
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, data.RealmID, "automation_rules", data.RuleID)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAutomationRulesProjector(t *testing.T) {
+	t.Run("Name returns automation_rules", func(t *testing.T) {
+		tc := newAutomationRulesTestContext(t)
+
+		// Then
+		assert.Equal(t, "automation_rules", tc.projector.Name())
+	})
+
+	t.Run("handles AutomationRuleAdded by storing entry under target realm", func(t *testing.T) {
+		tc := newAutomationRulesTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventAutomationRuleAdded, domain.AutomationRuleAdded{
+			RuleID:  "ar-a1b2c3d4",
+			RealmID: "bf-r1",
+			Name:    "Urgent first",
+			Trigger: "created",
+			Match:   "[urgent]",
+			Action:  domain.AutomationActionSetPriority,
+			Value:   "0",
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "ar-a1b2c3d4")
+		assert.Equal(t, "Urgent first", entry.Name)
+		assert.Equal(t, "created", entry.Trigger)
+		assert.Equal(t, "[urgent]", entry.Match)
+		assert.Equal(t, domain.AutomationActionSetPriority, entry.Action)
+		assert.Equal(t, "0", entry.Value)
+	})
+
+	t.Run("handles AutomationRuleRemoved by deleting entry", func(t *testing.T) {
+		tc := newAutomationRulesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "automation_rules", "ar-a1b2c3d4", AutomationRuleEntry{ID: "ar-a1b2c3d4", RealmID: "bf-r1"})
+		tc.event = makeEvent(domain.EventAutomationRuleRemoved, domain.AutomationRuleRemoved{RuleID: "ar-a1b2c3d4", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.entry_does_not_exist("bf-r1", "ar-a1b2c3d4")
+	})
+}
+
+// --- Test Context ---
+
+type automationRulesTestContext struct {
+	t *testing.T
+
+	projector *AutomationRulesProjector
+	store     *mockProjectionStore
+	event     core.Event
+	err       error
+}
+
+func newAutomationRulesTestContext(t *testing.T) *automationRulesTestContext {
+	t.Helper()
+	return &automationRulesTestContext{
+		t:         t,
+		projector: NewAutomationRulesProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- When ---
+
+func (tc *automationRulesTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *automationRulesTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *automationRulesTestContext) stored_entry(realmID, ruleID string) AutomationRuleEntry {
+	tc.t.Helper()
+	var entry AutomationRuleEntry
+	require.NoError(tc.t, tc.store.Get(context.Background(), realmID, "automation_rules", ruleID, &entry))
+	return entry
+}
+
+func (tc *automationRulesTestContext) entry_does_not_exist(realmID, ruleID string) {
+	tc.t.Helper()
+	var entry AutomationRuleEntry
+	err := tc.store.Get(context.Background(), realmID, "automation_rules", ruleID, &entry)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
@@ -0,0 +1,227 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// AccountWorkload lists the runes an account has claimed, across all
+// realms. It is stored in the admin realm's claims_by_account projection by
+// account ID.
+type AccountWorkload struct {
+	AccountID string         `json:"account_id"`
+	Runes     []WorkloadRune `json:"runes"`
+}
+
+// WorkloadRune is a rune claimed by an account.
+type WorkloadRune struct {
+	RealmID   string    `json:"realm_id"`
+	RuneID    string    `json:"rune_id"`
+	Title     string    `json:"title"`
+	Priority  int       `json:"priority"`
+	DueDate   string    `json:"due_date,omitempty"`
+	ClaimedAt time.Time `json:"claimed_at"`
+}
+
+// claimedRune is what the projector keeps per rune in the rune's own realm,
+// so that a claim can be listed with the rune's title and priority and
+// moved off the account's workload when the claim ends.
+type claimedRune struct {
+	Title     string    `json:"title"`
+	Priority  int       `json:"priority"`
+	DueDate   string    `json:"due_date,omitempty"`
+	AccountID string    `json:"account_id,omitempty"`
+	ClaimedAt time.Time `json:"claimed_at,omitzero"`
+}
+
+const claimedRunesProjection = "claims_by_account_runes"
+
+type ClaimsByAccountProjector struct{}
+
+func NewClaimsByAccountProjector() *ClaimsByAccountProjector {
+	return &ClaimsByAccountProjector{}
+}
+
+func (p *ClaimsByAccountProjector) Name() string {
+	return "claims_by_account"
+}
+
+func (p *ClaimsByAccountProjector) Projections() []string {
+	return []string{"claims_by_account", claimedRunesProjection}
+}
+
+// SpansRealms reports true, since every realm's claims add up to one
+// workload per account in the admin realm.
+func (p *ClaimsByAccountProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *ClaimsByAccountProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		var data domain.RuneCreated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Put(ctx, event.RealmID, claimedRunesProjection, data.ID, claimedRune{
+			Title: data.Title, Priority: data.Priority, DueDate: data.DueDate,
+		})
+	case domain.EventRuneUpdated:
+		return p.handleUpdated(ctx, event, store)
+	case domain.EventRuneClaimed:
+		return p.handleClaimed(ctx, event, store)
+	case domain.EventRuneUnclaimed, domain.EventRuneFulfilled, domain.EventRuneSealed, domain.EventRuneShattered:
+		var data struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		claimed, err := p.getRune(ctx, store, event.RealmID, data.ID)
+		if err != nil {
+			return err
+		}
+		if err := p.release(ctx, store, event.RealmID, data.ID, &claimed); err != nil {
+			return err
+		}
+		if event.EventType == domain.EventRuneShattered {
+			return store.Delete(ctx, event.RealmID, claimedRunesProjection, data.ID)
+		}
+		return store.Put(ctx, event.RealmID, claimedRunesProjection, data.ID, claimed)
+	}
+	return nil
+}
+
+func (p *ClaimsByAccountProjector) handleUpdated(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RuneUpdated
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	claimed, err := p.getRune(ctx, store, event.RealmID, data.ID)
+	if err != nil {
+		return err
+	}
+	if data.Title != nil {
+		claimed.Title = *data.Title
+	}
+	if data.Priority != nil {
+		claimed.Priority = *data.Priority
+	}
+	if data.DueDate != nil {
+		claimed.DueDate = *data.DueDate
+	}
+	if claimed.AccountID != "" {
+		if err := p.claim(ctx, store, event.RealmID, data.ID, claimed); err != nil {
+			return err
+		}
+	}
+	return store.Put(ctx, event.RealmID, claimedRunesProjection, data.ID, claimed)
+}
+
+func (p *ClaimsByAccountProjector) handleClaimed(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RuneClaimed
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	claimed, err := p.getRune(ctx, store, event.RealmID, data.ID)
+	if err != nil {
+		return err
+	}
+	if err := p.release(ctx, store, event.RealmID, data.ID, &claimed); err != nil {
+		return err
+	}
+
+	// Claims recorded before claimants had to be accounts name a username.
+	accountID := data.AccountID
+	if accountID == "" {
+		if err := store.Get(ctx, domain.AdminRealmID, "account_lookup", "username:"+data.Claimant, &accountID); err != nil && !isNotFoundError(err) {
+			return err
+		}
+	}
+	if accountID != "" {
+		claimed.AccountID = accountID
+		claimed.ClaimedAt = event.Timestamp
+		if err := p.claim(ctx, store, event.RealmID, data.ID, claimed); err != nil {
+			return err
+		}
+	}
+	return store.Put(ctx, event.RealmID, claimedRunesProjection, data.ID, claimed)
+}
+
+// claim adds the rune to its claimant's workload, replacing any earlier
+// entry for it.
+func (p *ClaimsByAccountProjector) claim(ctx context.Context, store core.ProjectionStore, realmID, runeID string, claimed claimedRune) error {
+	workload, err := GetAccountWorkload(ctx, store, claimed.AccountID)
+	if err != nil {
+		return err
+	}
+	workload.Runes = removeWorkloadRune(workload.Runes, realmID, runeID)
+	workload.Runes = append(workload.Runes, WorkloadRune{
+		RealmID:   realmID,
+		RuneID:    runeID,
+		Title:     claimed.Title,
+		Priority:  claimed.Priority,
+		DueDate:   claimed.DueDate,
+		ClaimedAt: claimed.ClaimedAt,
+	})
+	sort.SliceStable(workload.Runes, func(i, j int) bool {
+		return workload.Runes[i].ClaimedAt.Before(workload.Runes[j].ClaimedAt)
+	})
+	return store.Put(ctx, domain.AdminRealmID, "claims_by_account", claimed.AccountID, workload)
+}
+
+// release removes the rune from the workload of the account holding it, if
+// any, and clears the claim.
+func (p *ClaimsByAccountProjector) release(ctx context.Context, store core.ProjectionStore, realmID, runeID string, claimed *claimedRune) error {
+	if claimed.AccountID == "" {
+		return nil
+	}
+	workload, err := GetAccountWorkload(ctx, store, claimed.AccountID)
+	if err != nil {
+		return err
+	}
+	workload.Runes = removeWorkloadRune(workload.Runes, realmID, runeID)
+	if err := store.Put(ctx, domain.AdminRealmID, "claims_by_account", claimed.AccountID, workload); err != nil {
+		return err
+	}
+	claimed.AccountID = ""
+	claimed.ClaimedAt = time.Time{}
+	return nil
+}
+
+func (p *ClaimsByAccountProjector) getRune(ctx context.Context, store core.ProjectionStore, realmID, runeID string) (claimedRune, error) {
+	var claimed claimedRune
+	err := store.Get(ctx, realmID, claimedRunesProjection, runeID, &claimed)
+	if err != nil && !isNotFoundError(err) {
+		return claimedRune{}, err
+	}
+	return claimed, nil
+}
+
+func removeWorkloadRune(runes []WorkloadRune, realmID, runeID string) []WorkloadRune {
+	kept := runes[:0]
+	for _, r := range runes {
+		if r.RealmID != realmID || r.RuneID != runeID {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// GetAccountWorkload returns the runes claimed by an account, oldest claim
+// first. An account with no claims has an empty workload.
+func GetAccountWorkload(ctx context.Context, store core.ProjectionStore, accountID string) (AccountWorkload, error) {
+	workload := AccountWorkload{AccountID: accountID}
+	if err := store.Get(ctx, domain.AdminRealmID, "claims_by_account", accountID, &workload); err != nil && !isNotFoundError(err) {
+		return AccountWorkload{}, err
+	}
+	if workload.Runes == nil {
+		workload.Runes = []WorkloadRune{}
+	}
+	return workload, nil
+}
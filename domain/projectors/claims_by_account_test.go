@@ -0,0 +1,213 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestClaimsByAccountProjector(t *testing.T) {
+	t.Run("Name returns claims_by_account", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Then
+		assert.Equal(t, "claims_by_account", tc.projector.Name())
+	})
+
+	t.Run("adds a claimed rune to the claimant's workload", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+
+		// When
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", "acct-1"))
+
+		// Then
+		tc.no_error()
+		workload := tc.workload_of("acct-1")
+		require.Len(t, workload.Runes, 1)
+		assert.Equal(t, WorkloadRune{RealmID: "realm-1", RuneID: "bf-a1", Title: "Fix login", Priority: 1, ClaimedAt: tc.now}, workload.Runes[0])
+	})
+
+	t.Run("collects claims from several realms, oldest first", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+		tc.rune_created("realm-2", "bf-b1", "Write docs", 3)
+		tc.handle(tc.claimed_event("realm-2", "bf-b1", "alice", "acct-1"))
+		tc.now = tc.now.Add(time.Hour)
+
+		// When
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", "acct-1"))
+
+		// Then
+		tc.no_error()
+		tc.workload_runes_are("acct-1", "bf-b1", "bf-a1")
+	})
+
+	t.Run("resolves a username claimant through the account lookup", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+		tc.store.put(domain.AdminRealmID, "account_lookup", "username:alice", "acct-1")
+
+		// When
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", ""))
+
+		// Then
+		tc.no_error()
+		tc.workload_runes_are("acct-1", "bf-a1")
+	})
+
+	t.Run("keeps claims by unknown claimants off every workload", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+
+		// When
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "ghost", ""))
+
+		// Then
+		tc.no_error()
+		tc.workload_runes_are("ghost")
+	})
+
+	t.Run("moves a re-claimed rune to the new claimant", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", "acct-1"))
+
+		// When
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "bob", "acct-2"))
+
+		// Then
+		tc.no_error()
+		tc.workload_runes_are("acct-1")
+		tc.workload_runes_are("acct-2", "bf-a1")
+	})
+
+	t.Run("refreshes the title and priority of a claimed rune on update", func(t *testing.T) {
+		tc := newClaimsByAccountTestContext(t)
+
+		// Given
+		tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+		tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", "acct-1"))
+		title, priority := "Fix login on mobile", 0
+
+		// When
+		tc.handle(tc.event_in("realm-1", domain.EventRuneUpdated, domain.RuneUpdated{ID: "bf-a1", Title: &title, Priority: &priority}))
+
+		// Then
+		tc.no_error()
+		workload := tc.workload_of("acct-1")
+		require.Len(t, workload.Runes, 1)
+		assert.Equal(t, "Fix login on mobile", workload.Runes[0].Title)
+		assert.Equal(t, 0, workload.Runes[0].Priority)
+		assert.True(t, workload.Runes[0].ClaimedAt.Equal(tc.now), "an update does not reset the claim's age")
+	})
+
+	for _, eventType := range []string{domain.EventRuneUnclaimed, domain.EventRuneFulfilled, domain.EventRuneSealed, domain.EventRuneShattered} {
+		t.Run("removes the rune from the workload on "+eventType, func(t *testing.T) {
+			tc := newClaimsByAccountTestContext(t)
+
+			// Given
+			tc.rune_created("realm-1", "bf-a1", "Fix login", 1)
+			tc.handle(tc.claimed_event("realm-1", "bf-a1", "alice", "acct-1"))
+
+			// When
+			tc.handle(tc.event_in("realm-1", eventType, map[string]string{"id": "bf-a1"}))
+
+			// Then
+			tc.no_error()
+			tc.workload_runes_are("acct-1")
+		})
+	}
+}
+
+// --- Test Context ---
+
+type claimsByAccountTestContext struct {
+	t *testing.T
+
+	projector *ClaimsByAccountProjector
+	store     *mockProjectionStore
+	ctx       context.Context
+	now       time.Time
+
+	err error
+}
+
+func newClaimsByAccountTestContext(t *testing.T) *claimsByAccountTestContext {
+	t.Helper()
+	return &claimsByAccountTestContext{
+		t:         t,
+		projector: NewClaimsByAccountProjector(),
+		store:     newMockProjectionStore(),
+		ctx:       context.Background(),
+		now:       time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// --- Given ---
+
+func (tc *claimsByAccountTestContext) rune_created(realmID, runeID, title string, priority int) {
+	tc.t.Helper()
+	tc.handle(tc.event_in(realmID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: title, Priority: priority}))
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *claimsByAccountTestContext) claimed_event(realmID, runeID, claimant, accountID string) core.Event {
+	return tc.event_in(realmID, domain.EventRuneClaimed, domain.RuneClaimed{ID: runeID, Claimant: claimant, AccountID: accountID})
+}
+
+func (tc *claimsByAccountTestContext) event_in(realmID, eventType string, data any) core.Event {
+	evt := makeEventWithTimestamp(eventType, data, tc.now)
+	evt.RealmID = realmID
+	return evt
+}
+
+// --- When ---
+
+func (tc *claimsByAccountTestContext) handle(event core.Event) {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(tc.ctx, event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *claimsByAccountTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *claimsByAccountTestContext) workload_of(accountID string) AccountWorkload {
+	tc.t.Helper()
+	workload, err := GetAccountWorkload(tc.ctx, tc.store, accountID)
+	require.NoError(tc.t, err)
+	return workload
+}
+
+func (tc *claimsByAccountTestContext) workload_runes_are(accountID string, runeIDs ...string) {
+	tc.t.Helper()
+	got := []string{}
+	for _, r := range tc.workload_of(accountID).Runes {
+		got = append(got, r.RuneID)
+	}
+	if runeIDs == nil {
+		runeIDs = []string{}
+	}
+	assert.Equal(tc.t, runeIDs, got)
+}
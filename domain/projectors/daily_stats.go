@@ -0,0 +1,148 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// DailyStatsDateFormat is the key format of daily_stats entries.
+const DailyStatsDateFormat = "2006-01-02"
+
+// DailyStats accumulates the rune activity of one UTC day. Entries are only
+// ever added to, so a timeseries is rebuilt by summing the deltas of every
+// day up to the point of interest.
+type DailyStats struct {
+	Date             string         `json:"date"`
+	StatusDelta      map[string]int `json:"status_delta"`
+	Created          int            `json:"created"`
+	Fulfilled        int            `json:"fulfilled"`
+	CycleTimeSeconds float64        `json:"cycle_time_seconds"`
+	CycleTimeCount   int            `json:"cycle_time_count"`
+	LeadTimeSeconds  float64        `json:"lead_time_seconds"`
+	LeadTimeCount    int            `json:"lead_time_count"`
+}
+
+// dailyStatsRune tracks what the projector needs to know about a rune
+// between events, kept separately so it does not depend on rune_list.
+type dailyStatsRune struct {
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	ClaimedAt time.Time `json:"claimed_at"`
+}
+
+type DailyStatsProjector struct{}
+
+func NewDailyStatsProjector() *DailyStatsProjector {
+	return &DailyStatsProjector{}
+}
+
+func (p *DailyStatsProjector) Name() string {
+	return "daily_stats"
+}
+
+func (p *DailyStatsProjector) Projections() []string {
+	return []string{"daily_stats", "daily_stats_runes"}
+}
+
+func (p *DailyStatsProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var status string
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		status = "draft"
+	case domain.EventRuneForged, domain.EventRuneUnclaimed:
+		status = "open"
+	case domain.EventRuneClaimed:
+		status = "claimed"
+	case domain.EventRuneFulfilled:
+		status = "fulfilled"
+	case domain.EventRuneSealed:
+		status = "sealed"
+	case domain.EventRuneShattered:
+	default:
+		return nil
+	}
+
+	var data struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+
+	var tracked dailyStatsRune
+	err := store.Get(ctx, event.RealmID, "daily_stats_runes", data.ID, &tracked)
+	exists := err == nil
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+
+	ts := event.Timestamp.UTC()
+	day, err := p.day(ctx, event.RealmID, ts, store)
+	if err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		if exists {
+			return nil
+		}
+		tracked = dailyStatsRune{Status: status, CreatedAt: ts}
+		day.StatusDelta[status]++
+		day.Created++
+	case domain.EventRuneShattered:
+		if !exists {
+			return nil
+		}
+		day.StatusDelta[tracked.Status]--
+		if err := store.Put(ctx, event.RealmID, "daily_stats", day.Date, day); err != nil {
+			return err
+		}
+		return store.Delete(ctx, event.RealmID, "daily_stats_runes", data.ID)
+	default:
+		if !exists || tracked.Status == status {
+			return nil
+		}
+		day.StatusDelta[tracked.Status]--
+		day.StatusDelta[status]++
+		tracked.Status = status
+		switch event.EventType {
+		case domain.EventRuneClaimed:
+			tracked.ClaimedAt = ts
+		case domain.EventRuneUnclaimed:
+			tracked.ClaimedAt = time.Time{}
+		case domain.EventRuneFulfilled:
+			day.Fulfilled++
+			day.LeadTimeSeconds += ts.Sub(tracked.CreatedAt).Seconds()
+			day.LeadTimeCount++
+			if !tracked.ClaimedAt.IsZero() {
+				day.CycleTimeSeconds += ts.Sub(tracked.ClaimedAt).Seconds()
+				day.CycleTimeCount++
+			}
+		}
+	}
+
+	if err := store.Put(ctx, event.RealmID, "daily_stats", day.Date, day); err != nil {
+		return err
+	}
+	return store.Put(ctx, event.RealmID, "daily_stats_runes", data.ID, tracked)
+}
+
+func (p *DailyStatsProjector) day(ctx context.Context, realmID string, ts time.Time, store core.ProjectionStore) (DailyStats, error) {
+	date := ts.Format(DailyStatsDateFormat)
+	var day DailyStats
+	if err := store.Get(ctx, realmID, "daily_stats", date, &day); err != nil {
+		if !isNotFoundError(err) {
+			return DailyStats{}, err
+		}
+		day = DailyStats{Date: date}
+	}
+	if day.StatusDelta == nil {
+		day.StatusDelta = map[string]int{}
+	}
+	return day, nil
+}
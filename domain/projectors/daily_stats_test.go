@@ -0,0 +1,178 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestDailyStatsProjector(t *testing.T) {
+	t.Run("Name returns daily_stats", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Then
+		assert.Equal(t, "daily_stats", tc.projector.Name())
+	})
+
+	t.Run("counts created runes as drafts on the day they were created", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// When
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// Then
+		tc.no_error()
+		day := tc.stored_day("2026-03-01")
+		assert.Equal(t, 1, day.Created)
+		assert.Equal(t, map[string]int{"draft": 1}, day.StatusDelta)
+	})
+
+	t.Run("moves a rune between statuses on transitions", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// When
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(24*time.Hour))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(25*time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, map[string]int{"draft": 1}, tc.stored_day("2026-03-01").StatusDelta)
+		assert.Equal(t, map[string]int{"draft": -1, "open": 0, "claimed": 1}, tc.stored_day("2026-03-02").StatusDelta)
+	})
+
+	t.Run("records cycle and lead time when a rune is fulfilled", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(time.Hour))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(2*time.Hour))
+
+		// When
+		tc.handle(domain.EventRuneFulfilled, domain.RuneFulfilled{ID: "bf-a1"}, tc.at(6*time.Hour))
+
+		// Then
+		tc.no_error()
+		day := tc.stored_day("2026-03-01")
+		assert.Equal(t, 1, day.Fulfilled)
+		assert.Equal(t, 1, day.CycleTimeCount)
+		assert.Equal(t, (4 * time.Hour).Seconds(), day.CycleTimeSeconds)
+		assert.Equal(t, 1, day.LeadTimeCount)
+		assert.Equal(t, (6 * time.Hour).Seconds(), day.LeadTimeSeconds)
+		assert.Equal(t, 1, day.StatusDelta["fulfilled"])
+		assert.Equal(t, 0, day.StatusDelta["claimed"])
+	})
+
+	t.Run("measures cycle time from the latest claim", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(time.Hour))
+		tc.handle(domain.EventRuneUnclaimed, domain.RuneUnclaimed{ID: "bf-a1"}, tc.at(2*time.Hour))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "bob"}, tc.at(3*time.Hour))
+
+		// When
+		tc.handle(domain.EventRuneFulfilled, domain.RuneFulfilled{ID: "bf-a1"}, tc.at(4*time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, time.Hour.Seconds(), tc.stored_day("2026-03-01").CycleTimeSeconds)
+	})
+
+	t.Run("removes a shattered rune from its status count", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// When
+		tc.handle(domain.EventRuneShattered, domain.RuneShattered{ID: "bf-a1"}, tc.at(time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 0, tc.stored_day("2026-03-01").StatusDelta["draft"])
+		tc.rune_is_not_tracked("bf-a1")
+	})
+
+	t.Run("ignores a repeated transition", func(t *testing.T) {
+		tc := newDailyStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// Then
+		tc.no_error()
+		day := tc.stored_day("2026-03-01")
+		assert.Equal(t, 1, day.Created)
+		assert.Equal(t, 1, day.StatusDelta["draft"])
+	})
+}
+
+// --- Test Context ---
+
+type dailyStatsTestContext struct {
+	t *testing.T
+
+	projector *DailyStatsProjector
+	store     *mockProjectionStore
+	start     time.Time
+	err       error
+}
+
+func newDailyStatsTestContext(t *testing.T) *dailyStatsTestContext {
+	t.Helper()
+	return &dailyStatsTestContext{
+		t:         t,
+		projector: NewDailyStatsProjector(),
+		store:     newMockProjectionStore(),
+		start:     time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func (tc *dailyStatsTestContext) at(offset time.Duration) time.Time {
+	return tc.start.Add(offset)
+}
+
+// --- When ---
+
+func (tc *dailyStatsTestContext) handle(eventType string, data any, ts time.Time) {
+	tc.t.Helper()
+	if err := tc.projector.Handle(context.Background(), makeEventWithTimestamp(eventType, data, ts), tc.store); err != nil {
+		tc.err = err
+	}
+}
+
+// --- Then ---
+
+func (tc *dailyStatsTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *dailyStatsTestContext) stored_day(date string) DailyStats {
+	tc.t.Helper()
+	var day DailyStats
+	require.NoError(tc.t, tc.store.Get(context.Background(), "realm-1", "daily_stats", date, &day))
+	return day
+}
+
+func (tc *dailyStatsTestContext) rune_is_not_tracked(runeID string) {
+	tc.t.Helper()
+	var tracked dailyStatsRune
+	err := tc.store.Get(context.Background(), "realm-1", "daily_stats_runes", runeID, &tracked)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
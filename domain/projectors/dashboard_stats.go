@@ -0,0 +1,166 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// DashboardRecentLimit is how many recently changed runes the dashboard keeps.
+const DashboardRecentLimit = 10
+
+// DashboardStats is the single "summary" entry of a realm's dashboard_stats
+// projection.
+type DashboardStats struct {
+	Total        int             `json:"total"`
+	StatusCounts map[string]int  `json:"status_counts"`
+	Recent       []DashboardRune `json:"recent"`
+}
+
+// DashboardRune is a recently changed rune, most recent first.
+type DashboardRune struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// dashboardStatsRune tracks each rune's title and status between events, kept
+// separately so the projector does not depend on rune_list.
+type dashboardStatsRune struct {
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type DashboardStatsProjector struct{}
+
+func NewDashboardStatsProjector() *DashboardStatsProjector {
+	return &DashboardStatsProjector{}
+}
+
+func (p *DashboardStatsProjector) Name() string {
+	return "dashboard_stats"
+}
+
+func (p *DashboardStatsProjector) Projections() []string {
+	return []string{"dashboard_stats", "dashboard_stats_runes"}
+}
+
+func (p *DashboardStatsProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var status string
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		status = "draft"
+	case domain.EventRuneForged, domain.EventRuneUnclaimed:
+		status = "open"
+	case domain.EventRuneClaimed:
+		status = "claimed"
+	case domain.EventRuneFulfilled:
+		status = "fulfilled"
+	case domain.EventRuneSealed:
+		status = "sealed"
+	case domain.EventRuneUpdated, domain.EventRuneShattered:
+	default:
+		return nil
+	}
+
+	var data struct {
+		ID    string  `json:"id"`
+		Title *string `json:"title"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+
+	var tracked dashboardStatsRune
+	err := store.Get(ctx, event.RealmID, "dashboard_stats_runes", data.ID, &tracked)
+	exists := err == nil
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+
+	stats, err := p.summary(ctx, event.RealmID, store)
+	if err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		if exists {
+			return nil
+		}
+		tracked = dashboardStatsRune{Status: status}
+		if data.Title != nil {
+			tracked.Title = *data.Title
+		}
+		stats.Total++
+		stats.StatusCounts[status]++
+	case domain.EventRuneShattered:
+		if !exists {
+			return nil
+		}
+		stats.Total--
+		stats.StatusCounts[tracked.Status]--
+		stats.Recent = withoutRecent(stats.Recent, data.ID)
+		if err := store.Put(ctx, event.RealmID, "dashboard_stats", "summary", stats); err != nil {
+			return err
+		}
+		return store.Delete(ctx, event.RealmID, "dashboard_stats_runes", data.ID)
+	case domain.EventRuneUpdated:
+		if !exists {
+			return nil
+		}
+		if data.Title != nil {
+			tracked.Title = *data.Title
+		}
+	default:
+		if !exists {
+			return nil
+		}
+		if tracked.Status != status {
+			stats.StatusCounts[tracked.Status]--
+			stats.StatusCounts[status]++
+			tracked.Status = status
+		}
+	}
+
+	recent := DashboardRune{ID: data.ID, Title: tracked.Title, Status: tracked.Status, UpdatedAt: event.Timestamp}
+	stats.Recent = append([]DashboardRune{recent}, withoutRecent(stats.Recent, data.ID)...)
+	if len(stats.Recent) > DashboardRecentLimit {
+		stats.Recent = stats.Recent[:DashboardRecentLimit]
+	}
+
+	if err := store.Put(ctx, event.RealmID, "dashboard_stats", "summary", stats); err != nil {
+		return err
+	}
+	return store.Put(ctx, event.RealmID, "dashboard_stats_runes", data.ID, tracked)
+}
+
+func (p *DashboardStatsProjector) summary(ctx context.Context, realmID string, store core.ProjectionStore) (DashboardStats, error) {
+	var stats DashboardStats
+	if err := store.Get(ctx, realmID, "dashboard_stats", "summary", &stats); err != nil {
+		if !isNotFoundError(err) {
+			return DashboardStats{}, err
+		}
+	}
+	if stats.StatusCounts == nil {
+		stats.StatusCounts = map[string]int{}
+	}
+	if stats.Recent == nil {
+		stats.Recent = []DashboardRune{}
+	}
+	return stats, nil
+}
+
+func withoutRecent(recent []DashboardRune, id string) []DashboardRune {
+	kept := make([]DashboardRune, 0, len(recent))
+	for _, r := range recent {
+		if r.ID != id {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,189 @@
+package projectors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestDashboardStatsProjector(t *testing.T) {
+	t.Run("Name returns dashboard_stats", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// Then
+		assert.Equal(t, "dashboard_stats", tc.projector.Name())
+	})
+
+	t.Run("counts created runes as drafts", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// When
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1", Title: "Bridge"}, tc.at(0))
+
+		// Then
+		tc.no_error()
+		stats := tc.stored_stats()
+		assert.Equal(t, 1, stats.Total)
+		assert.Equal(t, map[string]int{"draft": 1}, stats.StatusCounts)
+		assert.Equal(t, []DashboardRune{{ID: "bf-a1", Title: "Bridge", Status: "draft", UpdatedAt: tc.at(0)}}, stats.Recent)
+	})
+
+	t.Run("moves a rune between statuses on transitions", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1", Title: "Bridge"}, tc.at(0))
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a2", Title: "Tower"}, tc.at(time.Minute))
+
+		// When
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(2*time.Minute))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(3*time.Minute))
+
+		// Then
+		tc.no_error()
+		stats := tc.stored_stats()
+		assert.Equal(t, 2, stats.Total)
+		assert.Equal(t, map[string]int{"draft": 1, "open": 0, "claimed": 1}, stats.StatusCounts)
+		tc.recent_ids_are(stats, "bf-a1", "bf-a2")
+		assert.Equal(t, "claimed", stats.Recent[0].Status)
+	})
+
+	t.Run("keeps recent titles current", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1", Title: "Bridge"}, tc.at(0))
+
+		// When
+		title := "Rainbow Bridge"
+		tc.handle(domain.EventRuneUpdated, domain.RuneUpdated{ID: "bf-a1", Title: &title}, tc.at(time.Minute))
+
+		// Then
+		tc.no_error()
+		stats := tc.stored_stats()
+		assert.Equal(t, "Rainbow Bridge", stats.Recent[0].Title)
+		assert.Equal(t, tc.at(time.Minute), stats.Recent[0].UpdatedAt)
+	})
+
+	t.Run("limits the recent list", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// When
+		for i := 0; i < DashboardRecentLimit+2; i++ {
+			tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: fmt.Sprintf("bf-%02d", i)}, tc.at(time.Duration(i)*time.Minute))
+		}
+
+		// Then
+		tc.no_error()
+		stats := tc.stored_stats()
+		assert.Equal(t, DashboardRecentLimit+2, stats.Total)
+		require.Len(t, stats.Recent, DashboardRecentLimit)
+		assert.Equal(t, fmt.Sprintf("bf-%02d", DashboardRecentLimit+1), stats.Recent[0].ID)
+	})
+
+	t.Run("removes shattered runes", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a2"}, tc.at(time.Minute))
+
+		// When
+		tc.handle(domain.EventRuneShattered, domain.RuneShattered{ID: "bf-a1"}, tc.at(2*time.Minute))
+
+		// Then
+		tc.no_error()
+		stats := tc.stored_stats()
+		assert.Equal(t, 1, stats.Total)
+		assert.Equal(t, 1, stats.StatusCounts["draft"])
+		tc.recent_ids_are(stats, "bf-a2")
+		tc.rune_is_not_tracked("bf-a1")
+	})
+
+	t.Run("ignores events for unknown runes", func(t *testing.T) {
+		tc := newDashboardStatsTestContext(t)
+
+		// When
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-zz", Claimant: "alice"}, tc.at(0))
+
+		// Then
+		tc.no_error()
+		var stats DashboardStats
+		err := tc.store.Get(context.Background(), "realm-1", "dashboard_stats", "summary", &stats)
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+}
+
+// --- Test Context ---
+
+type dashboardStatsTestContext struct {
+	t *testing.T
+
+	projector *DashboardStatsProjector
+	store     *mockProjectionStore
+	start     time.Time
+	err       error
+}
+
+func newDashboardStatsTestContext(t *testing.T) *dashboardStatsTestContext {
+	t.Helper()
+	return &dashboardStatsTestContext{
+		t:         t,
+		projector: NewDashboardStatsProjector(),
+		store:     newMockProjectionStore(),
+		start:     time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func (tc *dashboardStatsTestContext) at(offset time.Duration) time.Time {
+	return tc.start.Add(offset)
+}
+
+// --- When ---
+
+func (tc *dashboardStatsTestContext) handle(eventType string, data any, ts time.Time) {
+	tc.t.Helper()
+	if err := tc.projector.Handle(context.Background(), makeEventWithTimestamp(eventType, data, ts), tc.store); err != nil {
+		tc.err = err
+	}
+}
+
+// --- Then ---
+
+func (tc *dashboardStatsTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *dashboardStatsTestContext) stored_stats() DashboardStats {
+	tc.t.Helper()
+	var stats DashboardStats
+	require.NoError(tc.t, tc.store.Get(context.Background(), "realm-1", "dashboard_stats", "summary", &stats))
+	return stats
+}
+
+func (tc *dashboardStatsTestContext) recent_ids_are(stats DashboardStats, expected ...string) {
+	tc.t.Helper()
+	ids := make([]string, 0, len(stats.Recent))
+	for _, r := range stats.Recent {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(tc.t, expected, ids)
+}
+
+func (tc *dashboardStatsTestContext) rune_is_not_tracked(runeID string) {
+	tc.t.Helper()
+	var tracked dashboardStatsRune
+	err := tc.store.Get(context.Background(), "realm-1", "dashboard_stats_runes", runeID, &tracked)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
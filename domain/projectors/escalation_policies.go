@@ -0,0 +1,68 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// EscalationPolicyEntry is stored under the realm the policy applies to.
+type EscalationPolicyEntry struct {
+	ID        string    `json:"id"`
+	RealmID   string    `json:"realm_id"`
+	Name      string    `json:"name"`
+	Trigger   string    `json:"trigger"`
+	After     string    `json:"after,omitempty"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EscalationPoliciesProjector struct{}
+
+func NewEscalationPoliciesProjector() *EscalationPoliciesProjector {
+	return &EscalationPoliciesProjector{}
+}
+
+func (p *EscalationPoliciesProjector) Name() string {
+	return "escalation_policies"
+}
+
+func (p *EscalationPoliciesProjector) Projections() []string {
+	return []string{"escalation_policies"}
+}
+
+// SpansRealms reports true because policies live in the admin realm's
+// streams while their entries sit in the realm they escalate.
+func (p *EscalationPoliciesProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *EscalationPoliciesProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventEscalationPolicyAdded:
+		var data domain.EscalationPolicyAdded
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := EscalationPolicyEntry{
+			ID:        data.PolicyID,
+			RealmID:   data.RealmID,
+			Name:      data.Name,
+			Trigger:   data.Trigger,
+			After:     data.After,
+			Action:    data.Action,
+			CreatedAt: data.CreatedAt,
+		}
+		return store.Put(ctx, data.RealmID, "escalation_policies", data.PolicyID, entry)
+	case domain.EventEscalationPolicyRemoved:
+		var data domain.EscalationPolicyRemoved
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, data.RealmID, "escalation_policies", data.PolicyID)
+	}
+	return nil
+}
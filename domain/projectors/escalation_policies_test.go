@@ -0,0 +1,111 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestEscalationPoliciesProjector(t *testing.T) {
+	t.Run("Name returns escalation_policies", func(t *testing.T) {
+		tc := newEscalationPoliciesTestContext(t)
+
+		// Then
+		assert.Equal(t, "escalation_policies", tc.projector.Name())
+	})
+
+	t.Run("handles EscalationPolicyAdded by storing entry under target realm", func(t *testing.T) {
+		tc := newEscalationPoliciesTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventEscalationPolicyAdded, domain.EscalationPolicyAdded{
+			PolicyID: "ep-a1b2c3d4",
+			RealmID:  "bf-r1",
+			Name:     "Stale claims",
+			Trigger:  domain.EscalationTriggerStaleClaim,
+			After:    "3d",
+			Action:   domain.EscalationActionUnclaim,
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "ep-a1b2c3d4")
+		assert.Equal(t, "Stale claims", entry.Name)
+		assert.Equal(t, domain.EscalationTriggerStaleClaim, entry.Trigger)
+		assert.Equal(t, "3d", entry.After)
+		assert.Equal(t, domain.EscalationActionUnclaim, entry.Action)
+	})
+
+	t.Run("handles EscalationPolicyRemoved by deleting entry", func(t *testing.T) {
+		tc := newEscalationPoliciesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "escalation_policies", "ep-a1b2c3d4", EscalationPolicyEntry{ID: "ep-a1b2c3d4", RealmID: "bf-r1"})
+		tc.event = makeEvent(domain.EventEscalationPolicyRemoved, domain.EscalationPolicyRemoved{PolicyID: "ep-a1b2c3d4", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.entry_does_not_exist("bf-r1", "ep-a1b2c3d4")
+	})
+}
+
+// --- Test Context ---
+
+type escalationPoliciesTestContext struct {
+	t *testing.T
+
+	projector *EscalationPoliciesProjector
+	store     *mockProjectionStore
+	event     core.Event
+	err       error
+}
+
+func newEscalationPoliciesTestContext(t *testing.T) *escalationPoliciesTestContext {
+	t.Helper()
+	return &escalationPoliciesTestContext{
+		t:         t,
+		projector: NewEscalationPoliciesProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- When ---
+
+func (tc *escalationPoliciesTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *escalationPoliciesTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *escalationPoliciesTestContext) stored_entry(realmID, policyID string) EscalationPolicyEntry {
+	tc.t.Helper()
+	var entry EscalationPolicyEntry
+	require.NoError(tc.t, tc.store.Get(context.Background(), realmID, "escalation_policies", policyID, &entry))
+	return entry
+}
+
+func (tc *escalationPoliciesTestContext) entry_does_not_exist(realmID, policyID string) {
+	tc.t.Helper()
+	var entry EscalationPolicyEntry
+	err := tc.store.Get(context.Background(), realmID, "escalation_policies", policyID, &entry)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
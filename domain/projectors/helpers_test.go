@@ -3,6 +3,8 @@ package projectors
 import (
 	"context"
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/devzeebo/bifrost/core"
@@ -20,6 +22,13 @@ var _ core.Projector = (*AgentDetailProjector)(nil)
 var _ core.Projector = (*SkillListProjector)(nil)
 var _ core.Projector = (*WorkflowListProjector)(nil)
 var _ core.Projector = (*RunnerSettingsProjector)(nil)
+var _ core.Projector = (*RealmSettingsProjector)(nil)
+var _ core.Projector = (*WebhookListProjector)(nil)
+var _ core.Projector = (*AutomationRulesProjector)(nil)
+var _ core.Projector = (*DailyStatsProjector)(nil)
+var _ core.Projector = (*DashboardStatsProjector)(nil)
+var _ core.Projector = (*AdminTokensProjector)(nil)
+var _ core.Projector = (*ClaimsByAccountProjector)(nil)
 
 // --- Helpers ---
 
@@ -82,8 +91,24 @@ func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionN
 	return nil
 }
 
-func (m *mockProjectionStore) List(_ context.Context, _ string, _ string) ([]json.RawMessage, error) {
-	return nil, nil
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var keys []string
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var values []json.RawMessage
+	for _, key := range keys {
+		data, err := json.Marshal(m.data[key])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, data)
+	}
+	return values, nil
 }
 
 func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
@@ -0,0 +1,74 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+type NotificationPreferencesEntry struct {
+	AccountID string              `json:"account_id"`
+	Muted     map[string][]string `json:"muted"`
+	Digest    string              `json:"digest"`
+}
+
+// Mutes reports whether the account has turned off kind on channel.
+func (e NotificationPreferencesEntry) Mutes(channel, kind string) bool {
+	for _, k := range e.Muted[channel] {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNotificationPreferences returns an account's notification preferences,
+// or the defaults (nothing muted, no digest) when it has never set any.
+func GetNotificationPreferences(ctx context.Context, store core.ProjectionStore, accountID string) (NotificationPreferencesEntry, error) {
+	entry := NotificationPreferencesEntry{AccountID: accountID, Digest: domain.DigestOff}
+	err := store.Get(ctx, domain.AdminRealmID, "notification_preferences", accountID, &entry)
+	var nfe *core.NotFoundError
+	if err != nil && !errors.As(err, &nfe) {
+		return NotificationPreferencesEntry{}, err
+	}
+	if entry.Muted == nil {
+		entry.Muted = make(map[string][]string)
+	}
+	return entry, nil
+}
+
+type NotificationPreferencesProjector struct{}
+
+func NewNotificationPreferencesProjector() *NotificationPreferencesProjector {
+	return &NotificationPreferencesProjector{}
+}
+
+func (p *NotificationPreferencesProjector) Name() string {
+	return "notification_preferences"
+}
+
+func (p *NotificationPreferencesProjector) Projections() []string {
+	return []string{"notification_preferences"}
+}
+
+func (p *NotificationPreferencesProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	if event.EventType != domain.EventNotificationPreferencesSet {
+		return nil
+	}
+	var data domain.NotificationPreferencesSet
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	entry := NotificationPreferencesEntry{
+		AccountID: data.AccountID,
+		Muted:     data.Muted,
+		Digest:    data.Digest,
+	}
+	if entry.Muted == nil {
+		entry.Muted = make(map[string][]string)
+	}
+	return store.Put(ctx, event.RealmID, "notification_preferences", data.AccountID, entry)
+}
@@ -0,0 +1,135 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestNotificationPreferencesProjector(t *testing.T) {
+	t.Run("Name returns notification_preferences", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// When
+		tc.name_is_called()
+
+		// Then
+		assert.Equal(t, "notification_preferences", tc.nameResult)
+	})
+
+	t.Run("handles NotificationPreferencesSet by replacing the entry", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.existing_preferences(NotificationPreferencesEntry{AccountID: "acct-1", Muted: map[string][]string{"discord": {"created"}}, Digest: domain.DigestDaily})
+		tc.a_preferences_set_event(domain.NotificationPreferencesSet{AccountID: "acct-1", Muted: map[string][]string{"slack": {"claimed"}}, Digest: domain.DigestWeekly})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		require.NoError(t, tc.err)
+		tc.preferences_are("acct-1", NotificationPreferencesEntry{AccountID: "acct-1", Muted: map[string][]string{"slack": {"claimed"}}, Digest: domain.DigestWeekly})
+	})
+
+	t.Run("ignores unknown event types", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.event = core.Event{EventType: "UnknownEvent", Data: []byte(`{}`)}
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		assert.NoError(t, tc.err)
+	})
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	t.Run("returns defaults for an account without preferences", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// When / Then
+		tc.preferences_are("acct-1", NotificationPreferencesEntry{AccountID: "acct-1", Muted: map[string][]string{}, Digest: domain.DigestOff})
+	})
+
+	t.Run("reports muted kinds per channel", func(t *testing.T) {
+		tc := newNotificationPreferencesTestContext(t)
+
+		// Given
+		tc.existing_preferences(NotificationPreferencesEntry{AccountID: "acct-1", Muted: map[string][]string{"slack": {"mention"}}})
+
+		// When
+		entry, err := GetNotificationPreferences(tc.ctx, tc.store, "acct-1")
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, entry.Mutes("slack", "mention"))
+		assert.False(t, entry.Mutes("discord", "mention"))
+		assert.False(t, entry.Mutes("slack", "created"))
+	})
+}
+
+// --- Test Context ---
+
+type notificationPreferencesTestContext struct {
+	t *testing.T
+
+	projector  *NotificationPreferencesProjector
+	store      *mockProjectionStore
+	event      core.Event
+	ctx        context.Context
+	nameResult string
+	err        error
+}
+
+func newNotificationPreferencesTestContext(t *testing.T) *notificationPreferencesTestContext {
+	t.Helper()
+	return &notificationPreferencesTestContext{
+		t:         t,
+		projector: NewNotificationPreferencesProjector(),
+		store:     newMockProjectionStore(),
+		ctx:       context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *notificationPreferencesTestContext) existing_preferences(entry NotificationPreferencesEntry) {
+	tc.t.Helper()
+	tc.store.put(domain.AdminRealmID, "notification_preferences", entry.AccountID, entry)
+}
+
+func (tc *notificationPreferencesTestContext) a_preferences_set_event(data domain.NotificationPreferencesSet) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventNotificationPreferencesSet, data)
+	tc.event.RealmID = domain.AdminRealmID
+}
+
+// --- When ---
+
+func (tc *notificationPreferencesTestContext) name_is_called() {
+	tc.t.Helper()
+	tc.nameResult = tc.projector.Name()
+}
+
+func (tc *notificationPreferencesTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(tc.ctx, tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *notificationPreferencesTestContext) preferences_are(accountID string, expected NotificationPreferencesEntry) {
+	tc.t.Helper()
+	entry, err := GetNotificationPreferences(tc.ctx, tc.store, accountID)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, entry)
+}
@@ -0,0 +1,94 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+type RealmSettingsEntry struct {
+	RealmID  string            `json:"realm_id"`
+	Settings map[string]string `json:"settings"`
+}
+
+// GetRealmSettings returns the settings configured for a realm, or an empty
+// map when none have been set.
+func GetRealmSettings(ctx context.Context, store core.ProjectionStore, realmID string) (map[string]string, error) {
+	var entry RealmSettingsEntry
+	err := store.Get(ctx, domain.AdminRealmID, "realm_settings", realmID, &entry)
+	var nfe *core.NotFoundError
+	if err != nil && !errors.As(err, &nfe) {
+		return nil, err
+	}
+	if entry.Settings == nil {
+		entry.Settings = make(map[string]string)
+	}
+	return entry.Settings, nil
+}
+
+type RealmSettingsProjector struct{}
+
+func NewRealmSettingsProjector() *RealmSettingsProjector {
+	return &RealmSettingsProjector{}
+}
+
+func (p *RealmSettingsProjector) Name() string {
+	return "realm_settings"
+}
+
+func (p *RealmSettingsProjector) Projections() []string {
+	return []string{"realm_settings"}
+}
+
+func (p *RealmSettingsProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventRealmSettingSet:
+		return p.handleSettingSet(ctx, event, store)
+	case domain.EventRealmSettingDeleted:
+		return p.handleSettingDeleted(ctx, event, store)
+	}
+	return nil
+}
+
+func (p *RealmSettingsProjector) handleSettingSet(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RealmSettingSet
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	entry, err := p.load(ctx, event.RealmID, data.RealmID, store)
+	if err != nil {
+		return err
+	}
+	entry.Settings[data.Key] = data.Value
+	return store.Put(ctx, event.RealmID, "realm_settings", data.RealmID, entry)
+}
+
+func (p *RealmSettingsProjector) handleSettingDeleted(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RealmSettingDeleted
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	entry, err := p.load(ctx, event.RealmID, data.RealmID, store)
+	if err != nil {
+		return err
+	}
+	delete(entry.Settings, data.Key)
+	return store.Put(ctx, event.RealmID, "realm_settings", data.RealmID, entry)
+}
+
+func (p *RealmSettingsProjector) load(ctx context.Context, storeRealmID, realmID string, store core.ProjectionStore) (RealmSettingsEntry, error) {
+	var entry RealmSettingsEntry
+	err := store.Get(ctx, storeRealmID, "realm_settings", realmID, &entry)
+	var nfe *core.NotFoundError
+	if err != nil && !errors.As(err, &nfe) {
+		return RealmSettingsEntry{}, err
+	}
+	entry.RealmID = realmID
+	if entry.Settings == nil {
+		entry.Settings = make(map[string]string)
+	}
+	return entry, nil
+}
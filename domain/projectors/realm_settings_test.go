@@ -0,0 +1,178 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRealmSettingsProjector(t *testing.T) {
+	t.Run("Name returns realm_settings", func(t *testing.T) {
+		tc := newRealmSettingsTestContext(t)
+
+		// Given
+		tc.a_realm_settings_projector()
+
+		// When
+		tc.name_is_called()
+
+		// Then
+		tc.name_is("realm_settings")
+	})
+
+	t.Run("handles RealmSettingSet by creating entry", func(t *testing.T) {
+		tc := newRealmSettingsTestContext(t)
+
+		// Given
+		tc.a_realm_settings_projector()
+		tc.a_projection_store()
+		tc.a_setting_set_event("bf-a1b2", "github.token", "abc")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.settings_are("bf-a1b2", map[string]string{"github.token": "abc"})
+	})
+
+	t.Run("handles RealmSettingSet by overwriting existing value", func(t *testing.T) {
+		tc := newRealmSettingsTestContext(t)
+
+		// Given
+		tc.a_realm_settings_projector()
+		tc.a_projection_store()
+		tc.existing_settings("bf-a1b2", map[string]string{"github.token": "old", "other": "x"})
+		tc.a_setting_set_event("bf-a1b2", "github.token", "new")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.settings_are("bf-a1b2", map[string]string{"github.token": "new", "other": "x"})
+	})
+
+	t.Run("handles RealmSettingDeleted by removing key", func(t *testing.T) {
+		tc := newRealmSettingsTestContext(t)
+
+		// Given
+		tc.a_realm_settings_projector()
+		tc.a_projection_store()
+		tc.existing_settings("bf-a1b2", map[string]string{"github.token": "abc", "other": "x"})
+		tc.a_setting_deleted_event("bf-a1b2", "github.token")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.settings_are("bf-a1b2", map[string]string{"other": "x"})
+	})
+
+	t.Run("ignores unknown event types", func(t *testing.T) {
+		tc := newRealmSettingsTestContext(t)
+
+		// Given
+		tc.a_realm_settings_projector()
+		tc.a_projection_store()
+		tc.event = core.Event{EventType: "UnknownEvent", Data: []byte(`{}`)}
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+	})
+}
+
+// --- Test Context ---
+
+type realmSettingsTestContext struct {
+	t *testing.T
+
+	projector  *RealmSettingsProjector
+	store      *mockProjectionStore
+	event      core.Event
+	ctx        context.Context
+	nameResult string
+	err        error
+}
+
+func newRealmSettingsTestContext(t *testing.T) *realmSettingsTestContext {
+	t.Helper()
+	return &realmSettingsTestContext{
+		t:   t,
+		ctx: context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *realmSettingsTestContext) a_realm_settings_projector() {
+	tc.t.Helper()
+	tc.projector = NewRealmSettingsProjector()
+}
+
+func (tc *realmSettingsTestContext) a_projection_store() {
+	tc.t.Helper()
+	tc.store = newMockProjectionStore()
+}
+
+func (tc *realmSettingsTestContext) existing_settings(realmID string, settings map[string]string) {
+	tc.t.Helper()
+	tc.store.put("realm-1", "realm_settings", realmID, RealmSettingsEntry{RealmID: realmID, Settings: settings})
+}
+
+func (tc *realmSettingsTestContext) a_setting_set_event(realmID, key, value string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventRealmSettingSet, domain.RealmSettingSet{
+		RealmID: realmID, Key: key, Value: value,
+	})
+}
+
+func (tc *realmSettingsTestContext) a_setting_deleted_event(realmID, key string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventRealmSettingDeleted, domain.RealmSettingDeleted{
+		RealmID: realmID, Key: key,
+	})
+}
+
+// --- When ---
+
+func (tc *realmSettingsTestContext) name_is_called() {
+	tc.t.Helper()
+	tc.nameResult = tc.projector.Name()
+}
+
+func (tc *realmSettingsTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(tc.ctx, tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *realmSettingsTestContext) name_is(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.nameResult)
+}
+
+func (tc *realmSettingsTestContext) no_error() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *realmSettingsTestContext) settings_are(realmID string, expected map[string]string) {
+	tc.t.Helper()
+	var entry RealmSettingsEntry
+	err := tc.store.Get(tc.ctx, "realm-1", "realm_settings", realmID, &entry)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, realmID, entry.RealmID)
+	assert.Equal(tc.t, expected, entry.Settings)
+}
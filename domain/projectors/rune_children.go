@@ -0,0 +1,201 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// RuneChild is a direct child of a rune, as listed under its parent.
+type RuneChild struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+	Claimant string `json:"claimant,omitempty"`
+}
+
+// RuneChildrenProjector keeps each parent rune's direct children under the
+// parent's ID, and each child's parent under "parent:<child ID>" so events
+// that name only the child can find the list to update.
+type RuneChildrenProjector struct{}
+
+func NewRuneChildrenProjector() *RuneChildrenProjector {
+	return &RuneChildrenProjector{}
+}
+
+func (p *RuneChildrenProjector) Name() string {
+	return "rune_children"
+}
+
+func (p *RuneChildrenProjector) Projections() []string {
+	return []string{"rune_children"}
+}
+
+func (p *RuneChildrenProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		return p.handleCreated(ctx, event, store)
+	case domain.EventRuneUpdated:
+		var data domain.RuneUpdated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.updateChild(ctx, event.RealmID, data.ID, store, func(c *RuneChild) {
+			if data.Title != nil {
+				c.Title = *data.Title
+			}
+			if data.Priority != nil {
+				c.Priority = *data.Priority
+			}
+		})
+	case domain.EventRuneClaimed:
+		var data domain.RuneClaimed
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.updateChild(ctx, event.RealmID, data.ID, store, func(c *RuneChild) {
+			c.Status = "claimed"
+			c.Claimant = data.Claimant
+		})
+	case domain.EventRuneUnclaimed:
+		var data domain.RuneUnclaimed
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.updateChild(ctx, event.RealmID, data.ID, store, func(c *RuneChild) {
+			c.Status = "open"
+			c.Claimant = ""
+		})
+	case domain.EventRuneForged:
+		var data domain.RuneForged
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.setStatus(ctx, event.RealmID, data.ID, "open", store)
+	case domain.EventRuneFulfilled:
+		var data domain.RuneFulfilled
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.setStatus(ctx, event.RealmID, data.ID, "fulfilled", store)
+	case domain.EventRuneSealed:
+		var data domain.RuneSealed
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.setStatus(ctx, event.RealmID, data.ID, "sealed", store)
+	case domain.EventRuneShattered:
+		return p.handleShattered(ctx, event, store)
+	}
+	return nil
+}
+
+func (p *RuneChildrenProjector) handleCreated(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RuneCreated
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	if data.ParentID == "" {
+		return nil
+	}
+	children, err := p.children(ctx, event.RealmID, data.ParentID, store)
+	if err != nil {
+		return err
+	}
+	child := RuneChild{ID: data.ID, Title: data.Title, Status: "draft", Priority: data.Priority}
+	// Replaying the event replaces the child rather than listing it twice.
+	children = append(removeChild(children, data.ID), child)
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+	if err := store.Put(ctx, event.RealmID, "rune_children", data.ParentID, children); err != nil {
+		return err
+	}
+	return store.Put(ctx, event.RealmID, "rune_children", "parent:"+data.ID, data.ParentID)
+}
+
+func (p *RuneChildrenProjector) handleShattered(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RuneShattered
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	if err := store.Delete(ctx, event.RealmID, "rune_children", data.ID); err != nil && !isNotFoundError(err) {
+		return err
+	}
+	parentID, err := p.parent(ctx, event.RealmID, data.ID, store)
+	if err != nil || parentID == "" {
+		return err
+	}
+	children, err := p.children(ctx, event.RealmID, parentID, store)
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, event.RealmID, "rune_children", parentID, removeChild(children, data.ID)); err != nil {
+		return err
+	}
+	return store.Delete(ctx, event.RealmID, "rune_children", "parent:"+data.ID)
+}
+
+func (p *RuneChildrenProjector) setStatus(ctx context.Context, realmID, runeID, status string, store core.ProjectionStore) error {
+	return p.updateChild(ctx, realmID, runeID, store, func(c *RuneChild) { c.Status = status })
+}
+
+// updateChild applies change to the rune's entry under its parent, and does
+// nothing for a rune without a parent.
+func (p *RuneChildrenProjector) updateChild(ctx context.Context, realmID, runeID string, store core.ProjectionStore, change func(*RuneChild)) error {
+	parentID, err := p.parent(ctx, realmID, runeID, store)
+	if err != nil || parentID == "" {
+		return err
+	}
+	children, err := p.children(ctx, realmID, parentID, store)
+	if err != nil {
+		return err
+	}
+	for i := range children {
+		if children[i].ID == runeID {
+			change(&children[i])
+			return store.Put(ctx, realmID, "rune_children", parentID, children)
+		}
+	}
+	return nil
+}
+
+func (p *RuneChildrenProjector) parent(ctx context.Context, realmID, runeID string, store core.ProjectionStore) (string, error) {
+	var parentID string
+	err := store.Get(ctx, realmID, "rune_children", "parent:"+runeID, &parentID)
+	if isNotFoundError(err) {
+		return "", nil
+	}
+	return parentID, err
+}
+
+func (p *RuneChildrenProjector) children(ctx context.Context, realmID, parentID string, store core.ProjectionStore) ([]RuneChild, error) {
+	var children []RuneChild
+	err := store.Get(ctx, realmID, "rune_children", parentID, &children)
+	if isNotFoundError(err) {
+		return nil, nil
+	}
+	return children, err
+}
+
+func removeChild(children []RuneChild, id string) []RuneChild {
+	kept := children[:0]
+	for _, c := range children {
+		if c.ID != id {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// GetRuneChildren returns a rune's direct children ordered by ID, or an empty
+// list for a rune without children.
+func GetRuneChildren(ctx context.Context, store core.ProjectionStore, realmID, runeID string) ([]RuneChild, error) {
+	children, err := (&RuneChildrenProjector{}).children(ctx, realmID, runeID, store)
+	if children == nil && err == nil {
+		children = []RuneChild{}
+	}
+	return children, err
+}
@@ -0,0 +1,184 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction check
+var _ core.Projector = (*RuneChildrenProjector)(nil)
+
+// --- Tests ---
+
+func TestRuneChildrenProjector(t *testing.T) {
+	t.Run("Name returns rune_children", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Then
+		assert.Equal(t, "rune_children", tc.projector.Name())
+	})
+
+	t.Run("lists a created child under its parent", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Given
+		tc.child_is_created("bf-a1.2", "bf-a1")
+
+		// When
+		tc.child_is_created("bf-a1.1", "bf-a1")
+
+		// Then
+		tc.no_error()
+		children := tc.children_of("bf-a1")
+		require.Len(t, children, 2)
+		assert.Equal(t, RuneChild{ID: "bf-a1.1", Title: "Child bf-a1.1", Status: "draft", Priority: 2}, children[0])
+		assert.Equal(t, "bf-a1.2", children[1].ID)
+	})
+
+	t.Run("ignores runes without a parent", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1", Title: "Root", Priority: 1}))
+
+		// Then
+		tc.no_error()
+		tc.rune_has_no_children("bf-a1")
+	})
+
+	t.Run("does not list a replayed child twice", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Given
+		tc.child_is_created("bf-a1.1", "bf-a1")
+
+		// When
+		tc.child_is_created("bf-a1.1", "bf-a1")
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.children_of("bf-a1"), 1)
+	})
+
+	t.Run("tracks a child's status, claimant and priority", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Given
+		tc.child_is_created("bf-a1.1", "bf-a1")
+		tc.handle(makeEvent(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1.1"}))
+		tc.handle(makeEvent(domain.EventRuneUpdated, domain.RuneUpdated{ID: "bf-a1.1", Priority: intPtr(1)}))
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1.1", Claimant: "alice"}))
+
+		// Then
+		tc.no_error()
+		child := tc.children_of("bf-a1")[0]
+		assert.Equal(t, "claimed", child.Status)
+		assert.Equal(t, "alice", child.Claimant)
+		assert.Equal(t, 1, child.Priority)
+	})
+
+	t.Run("clears the claimant when a child is unclaimed", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Given
+		tc.child_is_created("bf-a1.1", "bf-a1")
+		tc.handle(makeEvent(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1.1", Claimant: "alice"}))
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneUnclaimed, domain.RuneUnclaimed{ID: "bf-a1.1"}))
+
+		// Then
+		tc.no_error()
+		child := tc.children_of("bf-a1")[0]
+		assert.Equal(t, "open", child.Status)
+		assert.Empty(t, child.Claimant)
+	})
+
+	t.Run("removes a shattered child from its parent", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// Given
+		tc.child_is_created("bf-a1.1", "bf-a1")
+		tc.child_is_created("bf-a1.2", "bf-a1")
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneShattered, domain.RuneShattered{ID: "bf-a1.1"}))
+
+		// Then
+		tc.no_error()
+		children := tc.children_of("bf-a1")
+		require.Len(t, children, 1)
+		assert.Equal(t, "bf-a1.2", children[0].ID)
+	})
+
+	t.Run("ignores status changes of runes without a parent", func(t *testing.T) {
+		tc := newRuneChildrenTestContext(t)
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneFulfilled, domain.RuneFulfilled{ID: "bf-a1"}))
+
+		// Then
+		tc.no_error()
+	})
+}
+
+// --- Test Context ---
+
+type runeChildrenTestContext struct {
+	t *testing.T
+
+	projector *RuneChildrenProjector
+	store     *mockProjectionStore
+	err       error
+}
+
+func newRuneChildrenTestContext(t *testing.T) *runeChildrenTestContext {
+	t.Helper()
+	return &runeChildrenTestContext{
+		t:         t,
+		projector: NewRuneChildrenProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- Given ---
+
+func (tc *runeChildrenTestContext) child_is_created(id, parentID string) {
+	tc.t.Helper()
+	tc.handle(makeEvent(domain.EventRuneCreated, domain.RuneCreated{
+		ID: id, Title: "Child " + id, Priority: 2, ParentID: parentID,
+	}))
+}
+
+// --- When ---
+
+func (tc *runeChildrenTestContext) handle(event core.Event) {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *runeChildrenTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *runeChildrenTestContext) children_of(parentID string) []RuneChild {
+	tc.t.Helper()
+	children, err := GetRuneChildren(context.Background(), tc.store, "realm-1", parentID)
+	require.NoError(tc.t, err)
+	return children
+}
+
+func (tc *runeChildrenTestContext) rune_has_no_children(runeID string) {
+	tc.t.Helper()
+	assert.Empty(tc.t, tc.children_of(runeID))
+}
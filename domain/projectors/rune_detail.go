@@ -26,8 +26,10 @@ type RuneDetail struct {
 	Status       string          `json:"status"`
 	Priority     int             `json:"priority"`
 	Claimant     string          `json:"claimant,omitempty"`
+	ClaimantID   string          `json:"claimant_account_id,omitempty"`
 	ParentID     string          `json:"parent_id,omitempty"`
 	Branch       string          `json:"branch,omitempty"`
+	DueDate      string          `json:"due_date,omitempty"`
 	Dependencies []DependencyRef `json:"dependencies"`
 	Notes        []NoteEntry     `json:"notes"`
 	CreatedAt    time.Time       `json:"created_at"`
@@ -44,6 +46,10 @@ func (p *RuneDetailProjector) Name() string {
 	return "rune_detail"
 }
 
+func (p *RuneDetailProjector) Projections() []string {
+	return []string{"rune_detail"}
+}
+
 func (p *RuneDetailProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	switch event.EventType {
 	case domain.EventRuneCreated:
@@ -85,6 +91,7 @@ func (p *RuneDetailProjector) handleCreated(ctx context.Context, event core.Even
 		Priority:     data.Priority,
 		ParentID:     data.ParentID,
 		Branch:       data.Branch,
+		DueDate:      data.DueDate,
 		Dependencies: []DependencyRef{},
 		Notes:        []NoteEntry{},
 		CreatedAt:    event.Timestamp,
@@ -128,6 +135,9 @@ func (p *RuneDetailProjector) handleUpdated(ctx context.Context, event core.Even
 	if data.Branch != nil {
 		detail.Branch = *data.Branch
 	}
+	if data.DueDate != nil {
+		detail.DueDate = *data.DueDate
+	}
 	detail.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_detail", data.ID, detail)
 }
@@ -143,6 +153,7 @@ func (p *RuneDetailProjector) handleClaimed(ctx context.Context, event core.Even
 	}
 	detail.Status = "claimed"
 	detail.Claimant = data.Claimant
+	detail.ClaimantID = data.AccountID
 	detail.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_detail", data.ID, detail)
 }
@@ -186,6 +197,7 @@ func (p *RuneDetailProjector) handleUnclaimed(ctx context.Context, event core.Ev
 	}
 	detail.Status = "open"
 	detail.Claimant = ""
+	detail.ClaimantID = ""
 	detail.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_detail", data.ID, detail)
 }
@@ -19,8 +19,13 @@ type RuneSummary struct {
 	ParentID  string    `json:"parent_id,omitempty"`
 	Branch    string    `json:"branch,omitempty"`
 	Type      string    `json:"type,omitempty"`
+	DueDate   string    `json:"due_date,omitempty"`
+	ClaimedAt time.Time `json:"claimed_at,omitzero"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// SLABreachedAt is when the rune breached the realm's SLA for its
+	// current status; it is cleared when the status changes.
+	SLABreachedAt time.Time `json:"sla_breached_at,omitzero"`
 }
 
 type RuneListProjector struct{}
@@ -33,6 +38,16 @@ func (p *RuneListProjector) Name() string {
 	return "rune_list"
 }
 
+func (p *RuneListProjector) Projections() []string {
+	return []string{"rune_list"}
+}
+
+// IndexedFields names the fields the rune list is filtered on by
+// GET /runes.
+func (p *RuneListProjector) IndexedFields() map[string][]string {
+	return map[string][]string{"rune_list": {"status", "priority", "claimant", "branch", "parent_id"}}
+}
+
 func (p *RuneListProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	switch event.EventType {
 	case domain.EventRuneCreated:
@@ -51,6 +66,8 @@ func (p *RuneListProjector) Handle(ctx context.Context, event core.Event, store
 		return p.handleUnclaimed(ctx, event, store)
 	case domain.EventRuneShattered:
 		return p.handleShattered(ctx, event, store)
+	case domain.EventSLABreached:
+		return p.handleSLABreached(ctx, event, store)
 	}
 	return nil
 }
@@ -68,6 +85,7 @@ func (p *RuneListProjector) handleCreated(ctx context.Context, event core.Event,
 		ParentID:  data.ParentID,
 		Branch:    data.Branch,
 		Type:      data.Type,
+		DueDate:   data.DueDate,
 		CreatedAt: event.Timestamp,
 		UpdatedAt: event.Timestamp,
 	}
@@ -84,6 +102,7 @@ func (p *RuneListProjector) handleForged(ctx context.Context, event core.Event,
 		return err
 	}
 	summary.Status = "open"
+	summary.SLABreachedAt = time.Time{}
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
@@ -106,6 +125,9 @@ func (p *RuneListProjector) handleUpdated(ctx context.Context, event core.Event,
 	if data.Branch != nil {
 		summary.Branch = *data.Branch
 	}
+	if data.DueDate != nil {
+		summary.DueDate = *data.DueDate
+	}
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
@@ -120,7 +142,9 @@ func (p *RuneListProjector) handleClaimed(ctx context.Context, event core.Event,
 		return err
 	}
 	summary.Status = "claimed"
+	summary.SLABreachedAt = time.Time{}
 	summary.Claimant = data.Claimant
+	summary.ClaimedAt = event.Timestamp
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
@@ -135,6 +159,7 @@ func (p *RuneListProjector) handleFulfilled(ctx context.Context, event core.Even
 		return err
 	}
 	summary.Status = "fulfilled"
+	summary.SLABreachedAt = time.Time{}
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
@@ -149,6 +174,7 @@ func (p *RuneListProjector) handleSealed(ctx context.Context, event core.Event,
 		return err
 	}
 	summary.Status = "sealed"
+	summary.SLABreachedAt = time.Time{}
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
@@ -163,11 +189,29 @@ func (p *RuneListProjector) handleUnclaimed(ctx context.Context, event core.Even
 		return err
 	}
 	summary.Status = "open"
+	summary.SLABreachedAt = time.Time{}
 	summary.Claimant = ""
+	summary.ClaimedAt = time.Time{}
 	summary.UpdatedAt = event.Timestamp
 	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
 }
 
+func (p *RuneListProjector) handleSLABreached(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.SLABreached
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	var summary RuneSummary
+	if err := store.Get(ctx, event.RealmID, "rune_list", data.ID, &summary); err != nil {
+		return err
+	}
+	if summary.Status != data.Status {
+		return nil
+	}
+	summary.SLABreachedAt = event.Timestamp
+	return store.Put(ctx, event.RealmID, "rune_list", data.ID, summary)
+}
+
 func (p *RuneListProjector) handleShattered(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	var data domain.RuneShattered
 	if err := json.Unmarshal(event.Data, &data); err != nil {
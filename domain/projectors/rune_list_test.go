@@ -118,6 +118,7 @@ func TestRuneListProjector(t *testing.T) {
 		tc.no_error()
 		tc.stored_summary_has_status("claimed")
 		tc.stored_summary_has_claimant("odin")
+		tc.stored_summary_has_claimed_at(true)
 	})
 
 	t.Run("handles RuneFulfilled by setting status", func(t *testing.T) {
@@ -170,6 +171,57 @@ func TestRuneListProjector(t *testing.T) {
 		tc.no_error()
 		tc.stored_summary_has_status("open")
 		tc.stored_summary_has_claimant("")
+		tc.stored_summary_has_claimed_at(false)
+	})
+
+	t.Run("handles SLABreached by flagging a rune still in the breached status", func(t *testing.T) {
+		tc := newRuneListTestContext(t)
+
+		// Given
+		tc.a_rune_list_projector()
+		tc.a_projection_store()
+		tc.existing_summary("bf-a1b2", "Fix the bridge", "claimed", 1, "odin", "")
+		tc.an_sla_breached_event("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.stored_summary_has_sla_breach(true)
+	})
+
+	t.Run("ignores SLABreached for a status the rune has left", func(t *testing.T) {
+		tc := newRuneListTestContext(t)
+
+		// Given
+		tc.a_rune_list_projector()
+		tc.a_projection_store()
+		tc.existing_summary("bf-a1b2", "Fix the bridge", "fulfilled", 1, "odin", "")
+		tc.an_sla_breached_event("bf-a1b2", "claimed")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.stored_summary_has_sla_breach(false)
+	})
+
+	t.Run("clears the SLA breach when the status changes", func(t *testing.T) {
+		tc := newRuneListTestContext(t)
+
+		// Given
+		tc.a_rune_list_projector()
+		tc.existing_breached_summary("bf-a1b2", "claimed")
+		tc.a_rune_fulfilled_event("bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.stored_summary_has_sla_breach(false)
 	})
 
 	t.Run("handles RuneCreated with branch", func(t *testing.T) {
@@ -224,6 +276,30 @@ func TestRuneListProjector(t *testing.T) {
 		tc.stored_summary_has_branch("feature/old")
 	})
 
+	t.Run("handles RuneCreated and RuneUpdated with due date", func(t *testing.T) {
+		tc := newRuneListTestContext(t)
+
+		// Given
+		tc.a_rune_list_projector()
+		tc.a_projection_store()
+		tc.event = makeEvent(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge", DueDate: "2026-03-31"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.stored_summary_has_due_date("2026-03-31")
+
+		// When
+		tc.event = makeEvent(domain.EventRuneUpdated, domain.RuneUpdated{ID: "bf-a1b2", DueDate: strPtr("")})
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.stored_summary_has_due_date("")
+	})
+
 	t.Run("handles RuneShattered by deleting rune from projection", func(t *testing.T) {
 		tc := newRuneListTestContext(t)
 
@@ -405,6 +481,13 @@ func (tc *runeListTestContext) a_rune_shattered_event(id string) {
 	})
 }
 
+func (tc *runeListTestContext) an_sla_breached_event(id, status string) {
+	tc.t.Helper()
+	tc.event = makeEvent(domain.EventSLABreached, domain.SLABreached{
+		ID: id, Status: status, Threshold: "7d",
+	})
+}
+
 func (tc *runeListTestContext) an_unknown_event() {
 	tc.t.Helper()
 	tc.event = core.Event{
@@ -427,6 +510,18 @@ func (tc *runeListTestContext) existing_summary(id, title, status string, priori
 	tc.store.put(tc.realmID, "rune_list", id, summary)
 }
 
+func (tc *runeListTestContext) existing_breached_summary(id, status string) {
+	tc.t.Helper()
+	tc.a_projection_store()
+	summary := RuneSummary{
+		ID:            id,
+		Title:         "Fix the bridge",
+		Status:        status,
+		SLABreachedAt: time.Now(),
+	}
+	tc.store.put(tc.realmID, "rune_list", id, summary)
+}
+
 func (tc *runeListTestContext) existing_summary_with_branch(id, title, status string, priority int, claimant, parentID, branch string) {
 	tc.t.Helper()
 	tc.a_projection_store()
@@ -544,6 +639,24 @@ func (tc *runeListTestContext) stored_summary_has_branch(expected string) {
 	assert.Equal(tc.t, expected, tc.storedSummary.Branch)
 }
 
+func (tc *runeListTestContext) stored_summary_has_due_date(expected string) {
+	tc.t.Helper()
+	require.NotNil(tc.t, tc.storedSummary)
+	assert.Equal(tc.t, expected, tc.storedSummary.DueDate)
+}
+
+func (tc *runeListTestContext) stored_summary_has_claimed_at(expected bool) {
+	tc.t.Helper()
+	require.NotNil(tc.t, tc.storedSummary)
+	assert.Equal(tc.t, expected, !tc.storedSummary.ClaimedAt.IsZero())
+}
+
+func (tc *runeListTestContext) stored_summary_has_sla_breach(expected bool) {
+	tc.t.Helper()
+	require.NotNil(tc.t, tc.storedSummary)
+	assert.Equal(tc.t, expected, !tc.storedSummary.SLABreachedAt.IsZero())
+}
+
 func (tc *runeListTestContext) stored_summary_updated_at_changed() {
 	tc.t.Helper()
 	require.NotNil(tc.t, tc.storedSummary)
@@ -0,0 +1,144 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// StatusTransition records when a rune entered a status.
+type StatusTransition struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// RuneTransitions is a rune's status history in the order it happened. The
+// first transition is the rune's creation as a draft, unless the realm's
+// retention policy pruned it; the latest is never pruned.
+type RuneTransitions struct {
+	RuneID      string             `json:"rune_id"`
+	Transitions []StatusTransition `json:"transitions"`
+}
+
+// CreatedAt returns when the rune was created, or zero if that was pruned.
+func (r RuneTransitions) CreatedAt() time.Time {
+	if len(r.Transitions) == 0 || r.Transitions[0].Status != "draft" {
+		return time.Time{}
+	}
+	return r.Transitions[0].At
+}
+
+// FulfilledAt returns when the rune was fulfilled, and the last time it was
+// claimed before that. Either is zero when it did not happen.
+func (r RuneTransitions) FulfilledAt() (fulfilled, claimed time.Time) {
+	for _, t := range r.Transitions {
+		switch t.Status {
+		case "claimed":
+			claimed = t.At
+		case "open":
+			claimed = time.Time{}
+		case "fulfilled":
+			return t.At, claimed
+		}
+	}
+	return time.Time{}, time.Time{}
+}
+
+type RuneTransitionsProjector struct{}
+
+func NewRuneTransitionsProjector() *RuneTransitionsProjector {
+	return &RuneTransitionsProjector{}
+}
+
+func (p *RuneTransitionsProjector) Name() string {
+	return "rune_transitions"
+}
+
+func (p *RuneTransitionsProjector) Projections() []string {
+	return []string{"rune_transitions"}
+}
+
+func (p *RuneTransitionsProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var status string
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		status = "draft"
+	case domain.EventRuneForged, domain.EventRuneUnclaimed:
+		status = "open"
+	case domain.EventRuneClaimed:
+		status = "claimed"
+	case domain.EventRuneFulfilled:
+		status = "fulfilled"
+	case domain.EventRuneSealed:
+		status = "sealed"
+	case domain.EventRuneShattered:
+	case domain.EventRuneHistoryPruned:
+		return p.handlePruned(ctx, event, store)
+	default:
+		return nil
+	}
+
+	var data struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	if event.EventType == domain.EventRuneShattered {
+		return store.Delete(ctx, event.RealmID, "rune_transitions", data.ID)
+	}
+
+	var entry RuneTransitions
+	err := store.Get(ctx, event.RealmID, "rune_transitions", data.ID, &entry)
+	exists := err == nil
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+	if exists == (event.EventType == domain.EventRuneCreated) {
+		return nil
+	}
+	if exists && entry.Transitions[len(entry.Transitions)-1].Status == status {
+		return nil
+	}
+
+	entry.RuneID = data.ID
+	entry.Transitions = append(entry.Transitions, StatusTransition{Status: status, At: event.Timestamp.UTC()})
+	return store.Put(ctx, event.RealmID, "rune_transitions", data.ID, entry)
+}
+
+// handlePruned drops every rune's transitions from before the prune time,
+// except its latest, which says when it entered its current status.
+func (p *RuneTransitionsProjector) handlePruned(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	var data domain.RuneHistoryPruned
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	raws, err := store.List(ctx, event.RealmID, "rune_transitions")
+	if err != nil {
+		return err
+	}
+	for _, raw := range raws {
+		var entry RuneTransitions
+		if err := json.Unmarshal(raw, &entry); err != nil || entry.RuneID == "" {
+			continue
+		}
+		last := len(entry.Transitions) - 1
+		kept := make([]StatusTransition, 0, len(entry.Transitions))
+		for i, t := range entry.Transitions {
+			if i == last || !t.At.Before(data.Before) {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(entry.Transitions) {
+			continue
+		}
+		entry.Transitions = kept
+		if err := store.Put(ctx, event.RealmID, "rune_transitions", entry.RuneID, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRuneTransitionsProjector(t *testing.T) {
+	t.Run("Name returns rune_transitions", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// Then
+		assert.Equal(t, "rune_transitions", tc.projector.Name())
+	})
+
+	t.Run("records each status a rune enters with its timestamp", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// When
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(time.Hour))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(2*time.Hour))
+		tc.handle(domain.EventRuneFulfilled, domain.RuneFulfilled{ID: "bf-a1"}, tc.at(5*time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []StatusTransition{
+			{Status: "draft", At: tc.at(0)},
+			{Status: "open", At: tc.at(time.Hour)},
+			{Status: "claimed", At: tc.at(2 * time.Hour)},
+			{Status: "fulfilled", At: tc.at(5 * time.Hour)},
+		}, tc.stored_transitions("bf-a1").Transitions)
+	})
+
+	t.Run("ignores events that do not change the status", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// When
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(time.Hour))
+		tc.handle(domain.EventRuneUpdated, domain.RuneUpdated{ID: "bf-a1"}, tc.at(2*time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.stored_transitions("bf-a1").Transitions, 1)
+	})
+
+	t.Run("ignores transitions of runes it never saw created", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// When
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(0))
+
+		// Then
+		tc.no_error()
+		tc.rune_is_not_tracked("bf-a1")
+	})
+
+	t.Run("forgets shattered runes", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+
+		// When
+		tc.handle(domain.EventRuneShattered, domain.RuneShattered{ID: "bf-a1"}, tc.at(time.Hour))
+
+		// Then
+		tc.no_error()
+		tc.rune_is_not_tracked("bf-a1")
+	})
+
+	t.Run("prunes transitions from before the prune time but keeps each rune's latest", func(t *testing.T) {
+		tc := newRuneTransitionsTestContext(t)
+
+		// Given
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1"}, tc.at(0))
+		tc.handle(domain.EventRuneForged, domain.RuneForged{ID: "bf-a1"}, tc.at(time.Hour))
+		tc.handle(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}, tc.at(3*time.Hour))
+		tc.handle(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-b2"}, tc.at(0))
+
+		// When
+		tc.handle(domain.EventRuneHistoryPruned, domain.RuneHistoryPruned{Before: tc.at(2 * time.Hour)}, tc.at(4*time.Hour))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []StatusTransition{
+			{Status: "claimed", At: tc.at(3 * time.Hour)},
+		}, tc.stored_transitions("bf-a1").Transitions)
+		assert.True(t, tc.stored_transitions("bf-a1").CreatedAt().IsZero())
+		assert.Equal(t, []StatusTransition{
+			{Status: "draft", At: tc.at(0)},
+		}, tc.stored_transitions("bf-b2").Transitions)
+	})
+}
+
+func TestRuneTransitionsFulfilledAt(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("returns the fulfilment and the claim before it", func(t *testing.T) {
+		// Given
+		entry := RuneTransitions{Transitions: []StatusTransition{
+			{Status: "draft", At: start},
+			{Status: "claimed", At: start.Add(time.Hour)},
+			{Status: "open", At: start.Add(2 * time.Hour)},
+			{Status: "claimed", At: start.Add(3 * time.Hour)},
+			{Status: "fulfilled", At: start.Add(4 * time.Hour)},
+		}}
+
+		// When
+		fulfilled, claimed := entry.FulfilledAt()
+
+		// Then
+		assert.Equal(t, start.Add(4*time.Hour), fulfilled)
+		assert.Equal(t, start.Add(3*time.Hour), claimed)
+		assert.Equal(t, start, entry.CreatedAt())
+	})
+
+	t.Run("returns zero claim time for runes fulfilled without a claim", func(t *testing.T) {
+		// Given
+		entry := RuneTransitions{Transitions: []StatusTransition{
+			{Status: "draft", At: start},
+			{Status: "fulfilled", At: start.Add(time.Hour)},
+		}}
+
+		// When
+		_, claimed := entry.FulfilledAt()
+
+		// Then
+		assert.True(t, claimed.IsZero())
+	})
+}
+
+// --- Test Context ---
+
+type runeTransitionsTestContext struct {
+	t *testing.T
+
+	projector *RuneTransitionsProjector
+	store     *mockProjectionStore
+	start     time.Time
+	err       error
+}
+
+func newRuneTransitionsTestContext(t *testing.T) *runeTransitionsTestContext {
+	t.Helper()
+	return &runeTransitionsTestContext{
+		t:         t,
+		projector: NewRuneTransitionsProjector(),
+		store:     newMockProjectionStore(),
+		start:     time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func (tc *runeTransitionsTestContext) at(offset time.Duration) time.Time {
+	return tc.start.Add(offset)
+}
+
+// --- When ---
+
+func (tc *runeTransitionsTestContext) handle(eventType string, data any, ts time.Time) {
+	tc.t.Helper()
+	if err := tc.projector.Handle(context.Background(), makeEventWithTimestamp(eventType, data, ts), tc.store); err != nil {
+		tc.err = err
+	}
+}
+
+// --- Then ---
+
+func (tc *runeTransitionsTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *runeTransitionsTestContext) stored_transitions(runeID string) RuneTransitions {
+	tc.t.Helper()
+	var entry RuneTransitions
+	require.NoError(tc.t, tc.store.Get(context.Background(), "realm-1", "rune_transitions", runeID, &entry))
+	return entry
+}
+
+func (tc *runeTransitionsTestContext) rune_is_not_tracked(runeID string) {
+	tc.t.Helper()
+	var entry RuneTransitions
+	err := tc.store.Get(context.Background(), "realm-1", "rune_transitions", runeID, &entry)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
@@ -0,0 +1,82 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// SavedSearchEntry is stored under the realm the search runs in. Searches are
+// listed per account by filtering on AccountID.
+type SavedSearchEntry struct {
+	ID        string    `json:"id"`
+	RealmID   string    `json:"realm_id"`
+	AccountID string    `json:"account_id"`
+	Name      string    `json:"name"`
+	Filter    string    `json:"filter"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SavedSearchesProjector struct{}
+
+func NewSavedSearchesProjector() *SavedSearchesProjector {
+	return &SavedSearchesProjector{}
+}
+
+func (p *SavedSearchesProjector) Name() string {
+	return "saved_searches"
+}
+
+func (p *SavedSearchesProjector) Projections() []string {
+	return []string{"saved_searches"}
+}
+
+// SpansRealms reports true: searches are saved through the admin realm
+// but kept with the realm they search.
+func (p *SavedSearchesProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *SavedSearchesProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventSavedSearchCreated:
+		var data domain.SavedSearchCreated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := SavedSearchEntry{
+			ID:        data.SearchID,
+			RealmID:   data.RealmID,
+			AccountID: data.AccountID,
+			Name:      data.Name,
+			Filter:    data.Filter,
+			CreatedAt: data.CreatedAt,
+		}
+		return store.Put(ctx, data.RealmID, "saved_searches", data.SearchID, entry)
+	case domain.EventSavedSearchUpdated:
+		var data domain.SavedSearchUpdated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		var entry SavedSearchEntry
+		err := store.Get(ctx, data.RealmID, "saved_searches", data.SearchID, &entry)
+		var nfe *core.NotFoundError
+		if err != nil && !errors.As(err, &nfe) {
+			return err
+		}
+		entry.ID, entry.RealmID, entry.AccountID = data.SearchID, data.RealmID, data.AccountID
+		entry.Name, entry.Filter = data.Name, data.Filter
+		return store.Put(ctx, data.RealmID, "saved_searches", data.SearchID, entry)
+	case domain.EventSavedSearchDeleted:
+		var data domain.SavedSearchDeleted
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, data.RealmID, "saved_searches", data.SearchID)
+	}
+	return nil
+}
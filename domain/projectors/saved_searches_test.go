@@ -0,0 +1,126 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSavedSearchesProjector(t *testing.T) {
+	t.Run("Name returns saved_searches", func(t *testing.T) {
+		tc := newSavedSearchesTestContext(t)
+
+		// Then
+		assert.Equal(t, "saved_searches", tc.projector.Name())
+	})
+
+	t.Run("handles SavedSearchCreated by storing entry under the search's realm", func(t *testing.T) {
+		tc := newSavedSearchesTestContext(t)
+
+		// Given
+		tc.an_event(domain.EventSavedSearchCreated, domain.SavedSearchCreated{
+			SearchID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1", Name: "Open", Filter: "status=open",
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "ss-a1b2c3d4")
+		assert.Equal(t, "acct-1", entry.AccountID)
+		assert.Equal(t, "Open", entry.Name)
+		assert.Equal(t, "status=open", entry.Filter)
+	})
+
+	t.Run("handles SavedSearchUpdated by replacing name and filter", func(t *testing.T) {
+		tc := newSavedSearchesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "saved_searches", "ss-a1b2c3d4", SavedSearchEntry{
+			ID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1", Name: "Open", Filter: "status=open",
+		})
+		tc.an_event(domain.EventSavedSearchUpdated, domain.SavedSearchUpdated{
+			SearchID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1", Name: "Blocked", Filter: "blocked=true",
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "ss-a1b2c3d4")
+		assert.Equal(t, "Blocked", entry.Name)
+		assert.Equal(t, "blocked=true", entry.Filter)
+	})
+
+	t.Run("handles SavedSearchDeleted by removing entry", func(t *testing.T) {
+		tc := newSavedSearchesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "saved_searches", "ss-a1b2c3d4", SavedSearchEntry{ID: "ss-a1b2c3d4"})
+		tc.an_event(domain.EventSavedSearchDeleted, domain.SavedSearchDeleted{SearchID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		var entry SavedSearchEntry
+		assert.Error(t, tc.store.Get(context.Background(), "bf-r1", "saved_searches", "ss-a1b2c3d4", &entry))
+	})
+}
+
+// --- Test Context ---
+
+type savedSearchesTestContext struct {
+	t *testing.T
+
+	projector *SavedSearchesProjector
+	store     *mockProjectionStore
+	event     core.Event
+	err       error
+}
+
+func newSavedSearchesTestContext(t *testing.T) *savedSearchesTestContext {
+	t.Helper()
+	return &savedSearchesTestContext{
+		t:         t,
+		projector: NewSavedSearchesProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- Given ---
+
+func (tc *savedSearchesTestContext) an_event(eventType string, data any) {
+	tc.t.Helper()
+	tc.event = makeEvent(eventType, data)
+}
+
+// --- When ---
+
+func (tc *savedSearchesTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *savedSearchesTestContext) no_error() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *savedSearchesTestContext) stored_entry(realmID, searchID string) SavedSearchEntry {
+	tc.t.Helper()
+	var entry SavedSearchEntry
+	require.NoError(tc.t, tc.store.Get(context.Background(), realmID, "saved_searches", searchID, &entry))
+	return entry
+}
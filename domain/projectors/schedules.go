@@ -0,0 +1,117 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// ScheduleEntry is stored under the realm the schedule runs in.
+type ScheduleEntry struct {
+	ID        string            `json:"id"`
+	RealmID   string            `json:"realm_id"`
+	Name      string            `json:"name"`
+	Cron      string            `json:"cron"`
+	Command   string            `json:"command"`
+	Args      map[string]string `json:"args,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	// NextRunAt is the first time after the last run (or creation) that the
+	// schedule falls due. It may be in the past while a run is pending.
+	NextRunAt  time.Time `json:"next_run_at,omitzero"`
+	LastDueAt  time.Time `json:"last_due_at,omitzero"`
+	LastRunAt  time.Time `json:"last_run_at,omitzero"`
+	LastResult string    `json:"last_result,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+type SchedulesProjector struct{}
+
+func NewSchedulesProjector() *SchedulesProjector {
+	return &SchedulesProjector{}
+}
+
+func (p *SchedulesProjector) Name() string {
+	return "schedules"
+}
+
+func (p *SchedulesProjector) Projections() []string {
+	return []string{"schedules"}
+}
+
+// SpansRealms reports true because schedules are appended to the admin
+// realm and projected into the realm they run in.
+func (p *SchedulesProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *SchedulesProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventScheduleCreated:
+		var data domain.ScheduleCreated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := ScheduleEntry{
+			ID:        data.ScheduleID,
+			RealmID:   data.RealmID,
+			Name:      data.Name,
+			Cron:      data.Cron,
+			Command:   data.Command,
+			Args:      data.Args,
+			CreatedAt: data.CreatedAt,
+			NextRunAt: nextScheduleRun(data.Cron, data.CreatedAt),
+		}
+		return store.Put(ctx, data.RealmID, "schedules", data.ScheduleID, entry)
+	case domain.EventScheduleDeleted:
+		var data domain.ScheduleDeleted
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, data.RealmID, "schedules", data.ScheduleID)
+	case domain.EventScheduleRunStarted:
+		var data domain.ScheduleRunStarted
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.update(ctx, store, data.RealmID, data.ScheduleID, func(entry *ScheduleEntry) {
+			entry.LastDueAt = data.DueAt
+			entry.NextRunAt = nextScheduleRun(entry.Cron, data.DueAt)
+		})
+	case domain.EventScheduleRunFinished:
+		var data domain.ScheduleRunFinished
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return p.update(ctx, store, data.RealmID, data.ScheduleID, func(entry *ScheduleEntry) {
+			entry.LastRunAt = data.FinishedAt
+			entry.LastResult = data.Result
+			entry.LastError = data.Error
+		})
+	}
+	return nil
+}
+
+// update applies fn to a stored schedule. Runs of a deleted schedule are
+// ignored.
+func (p *SchedulesProjector) update(ctx context.Context, store core.ProjectionStore, realmID, scheduleID string, fn func(*ScheduleEntry)) error {
+	var entry ScheduleEntry
+	if err := store.Get(ctx, realmID, "schedules", scheduleID, &entry); err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	fn(&entry)
+	return store.Put(ctx, realmID, "schedules", scheduleID, entry)
+}
+
+func nextScheduleRun(cron string, after time.Time) time.Time {
+	schedule, err := domain.ParseCron(cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return schedule.Next(after)
+}
@@ -0,0 +1,164 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSchedulesProjector(t *testing.T) {
+	t.Run("Name returns schedules", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Then
+		assert.Equal(t, "schedules", tc.projector.Name())
+	})
+
+	t.Run("handles ScheduleCreated by storing entry with its first run", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventScheduleCreated, domain.ScheduleCreated{
+			ScheduleID: "sc-a1b2c3d4",
+			RealmID:    "bf-r1",
+			Name:       "Nightly sweep",
+			Cron:       "@daily",
+			Command:    domain.ScheduleCommandSweepRunes,
+			CreatedAt:  time.Date(2026, 3, 20, 15, 0, 0, 0, time.UTC),
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "sc-a1b2c3d4")
+		assert.Equal(t, "Nightly sweep", entry.Name)
+		assert.Equal(t, domain.ScheduleCommandSweepRunes, entry.Command)
+		assert.Equal(t, time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC), entry.NextRunAt)
+	})
+
+	t.Run("handles ScheduleRunStarted by advancing the next run", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "schedules", "sc-1", ScheduleEntry{ID: "sc-1", RealmID: "bf-r1", Cron: "@daily"})
+		tc.event = makeEvent(domain.EventScheduleRunStarted, domain.ScheduleRunStarted{
+			ScheduleID: "sc-1", RealmID: "bf-r1", DueAt: time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC),
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "sc-1")
+		assert.Equal(t, time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC), entry.LastDueAt)
+		assert.Equal(t, time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC), entry.NextRunAt)
+	})
+
+	t.Run("handles ScheduleRunFinished by recording the outcome", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "schedules", "sc-1", ScheduleEntry{ID: "sc-1", RealmID: "bf-r1", Cron: "@daily"})
+		tc.event = makeEvent(domain.EventScheduleRunFinished, domain.ScheduleRunFinished{
+			ScheduleID: "sc-1", RealmID: "bf-r1", Error: "boom",
+			FinishedAt: time.Date(2026, 3, 21, 0, 0, 1, 0, time.UTC),
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "sc-1")
+		assert.Equal(t, "boom", entry.LastError)
+		assert.Equal(t, time.Date(2026, 3, 21, 0, 0, 1, 0, time.UTC), entry.LastRunAt)
+	})
+
+	t.Run("ignores runs of a deleted schedule", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventScheduleRunFinished, domain.ScheduleRunFinished{ScheduleID: "sc-1", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.entry_does_not_exist("bf-r1", "sc-1")
+	})
+
+	t.Run("handles ScheduleDeleted by deleting entry", func(t *testing.T) {
+		tc := newSchedulesTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "schedules", "sc-1", ScheduleEntry{ID: "sc-1", RealmID: "bf-r1"})
+		tc.event = makeEvent(domain.EventScheduleDeleted, domain.ScheduleDeleted{ScheduleID: "sc-1", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.entry_does_not_exist("bf-r1", "sc-1")
+	})
+}
+
+// --- Test Context ---
+
+type schedulesTestContext struct {
+	t *testing.T
+
+	projector *SchedulesProjector
+	store     *mockProjectionStore
+	event     core.Event
+	err       error
+}
+
+func newSchedulesTestContext(t *testing.T) *schedulesTestContext {
+	t.Helper()
+	return &schedulesTestContext{
+		t:         t,
+		projector: NewSchedulesProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- When ---
+
+func (tc *schedulesTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *schedulesTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *schedulesTestContext) stored_entry(realmID, scheduleID string) ScheduleEntry {
+	tc.t.Helper()
+	var entry ScheduleEntry
+	require.NoError(tc.t, tc.store.Get(context.Background(), realmID, "schedules", scheduleID, &entry))
+	return entry
+}
+
+func (tc *schedulesTestContext) entry_does_not_exist(realmID, scheduleID string) {
+	tc.t.Helper()
+	var entry ScheduleEntry
+	err := tc.store.Get(context.Background(), realmID, "schedules", scheduleID, &entry)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
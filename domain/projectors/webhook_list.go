@@ -0,0 +1,74 @@
+package projectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// WebhookListEntry is stored under the realm the webhook delivers events for,
+// so dispatchers can list a realm's webhooks without scanning the admin realm.
+type WebhookListEntry struct {
+	ID        string    `json:"id"`
+	RealmID   string    `json:"realm_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookListProjector struct{}
+
+func NewWebhookListProjector() *WebhookListProjector {
+	return &WebhookListProjector{}
+}
+
+func (p *WebhookListProjector) Name() string {
+	return "webhook_list"
+}
+
+func (p *WebhookListProjector) Projections() []string {
+	return []string{"webhook_list"}
+}
+
+// SpansRealms reports true, as webhooks registered through the admin realm
+// are listed under the realm they watch.
+func (p *WebhookListProjector) SpansRealms() bool {
+	return true
+}
+
+func (p *WebhookListProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	switch event.EventType {
+	case domain.EventWebhookRegistered:
+		var data domain.WebhookRegistered
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		entry := WebhookListEntry{
+			ID:        data.WebhookID,
+			RealmID:   data.RealmID,
+			URL:       data.URL,
+			Secret:    data.Secret,
+			Events:    data.Events,
+			CreatedAt: data.CreatedAt,
+		}
+		return store.Put(ctx, data.RealmID, "webhook_list", data.WebhookID, entry)
+	case domain.EventWebhookRemoved:
+		var data domain.WebhookRemoved
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return err
+		}
+		return store.Delete(ctx, data.RealmID, "webhook_list", data.WebhookID)
+	}
+	return nil
+}
+
+// GetWebhook returns the webhook registered for the realm with the given ID.
+func GetWebhook(ctx context.Context, store core.ProjectionStore, realmID, webhookID string) (WebhookListEntry, error) {
+	var entry WebhookListEntry
+	err := store.Get(ctx, realmID, "webhook_list", webhookID, &entry)
+	return entry, err
+}
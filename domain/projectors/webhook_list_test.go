@@ -0,0 +1,122 @@
+package projectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestWebhookListProjector(t *testing.T) {
+	t.Run("Name returns webhook_list", func(t *testing.T) {
+		tc := newWebhookListTestContext(t)
+
+		// Then
+		assert.Equal(t, "webhook_list", tc.projector.Name())
+	})
+
+	t.Run("handles WebhookRegistered by storing entry under target realm", func(t *testing.T) {
+		tc := newWebhookListTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventWebhookRegistered, domain.WebhookRegistered{
+			WebhookID: "wh-a1b2c3d4",
+			RealmID:   "bf-r1",
+			URL:       "https://example.com/hook",
+			Secret:    "s3cret",
+			Events:    []string{domain.EventRuneCreated},
+		})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		entry := tc.stored_entry("bf-r1", "wh-a1b2c3d4")
+		assert.Equal(t, "https://example.com/hook", entry.URL)
+		assert.Equal(t, "s3cret", entry.Secret)
+		assert.Equal(t, []string{domain.EventRuneCreated}, entry.Events)
+	})
+
+	t.Run("handles WebhookRemoved by deleting entry", func(t *testing.T) {
+		tc := newWebhookListTestContext(t)
+
+		// Given
+		tc.store.put("bf-r1", "webhook_list", "wh-a1b2c3d4", WebhookListEntry{ID: "wh-a1b2c3d4", RealmID: "bf-r1"})
+		tc.event = makeEvent(domain.EventWebhookRemoved, domain.WebhookRemoved{WebhookID: "wh-a1b2c3d4", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.entry_does_not_exist("bf-r1", "wh-a1b2c3d4")
+	})
+
+	t.Run("is idempotent for repeated removal", func(t *testing.T) {
+		tc := newWebhookListTestContext(t)
+
+		// Given
+		tc.event = makeEvent(domain.EventWebhookRemoved, domain.WebhookRemoved{WebhookID: "wh-a1b2c3d4", RealmID: "bf-r1"})
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+	})
+}
+
+// --- Test Context ---
+
+type webhookListTestContext struct {
+	t *testing.T
+
+	projector *WebhookListProjector
+	store     *mockProjectionStore
+	event     core.Event
+	err       error
+}
+
+func newWebhookListTestContext(t *testing.T) *webhookListTestContext {
+	t.Helper()
+	return &webhookListTestContext{
+		t:         t,
+		projector: NewWebhookListProjector(),
+		store:     newMockProjectionStore(),
+	}
+}
+
+// --- When ---
+
+func (tc *webhookListTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *webhookListTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *webhookListTestContext) stored_entry(realmID, webhookID string) WebhookListEntry {
+	tc.t.Helper()
+	var entry WebhookListEntry
+	require.NoError(tc.t, tc.store.Get(context.Background(), realmID, "webhook_list", webhookID, &entry))
+	return entry
+}
+
+func (tc *webhookListTestContext) entry_does_not_exist(realmID, webhookID string) {
+	tc.t.Helper()
+	var entry WebhookListEntry
+	err := tc.store.Get(context.Background(), realmID, "webhook_list", webhookID, &entry)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
@@ -23,6 +23,10 @@ func (p *WorkflowListProjector) Name() string {
 	return "workflow_list"
 }
 
+func (p *WorkflowListProjector) Projections() []string {
+	return []string{"workflow_list"}
+}
+
 func (p *WorkflowListProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
 	switch event.EventType {
 	case domain.EventWorkflowCreated:
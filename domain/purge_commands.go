@@ -0,0 +1,12 @@
+package domain
+
+// PurgeRune erases what a shattered rune's events say about it.
+type PurgeRune struct {
+	ID string `json:"id"`
+}
+
+// PurgeAccount erases what a suspended account's events say about its
+// holder.
+type PurgeAccount struct {
+	AccountID string `json:"account_id"`
+}
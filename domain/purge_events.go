@@ -0,0 +1,17 @@
+package domain
+
+const (
+	EventRunePurged    = "RunePurged"
+	EventAccountPurged = "AccountPurged"
+)
+
+// RunePurged tombstones a rune's stream once its events were redacted.
+type RunePurged struct {
+	ID string `json:"id"`
+}
+
+// AccountPurged tombstones an account's stream once its events were
+// redacted. Projections drop what they kept about the account.
+type AccountPurged struct {
+	AccountID string `json:"account_id"`
+}
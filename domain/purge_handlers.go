@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// HandlePurgeRune redacts the events of a shattered rune down to their IDs,
+// times and relationships, and tombstones its stream with RunePurged.
+// Purging a purged rune does nothing. The rune's state is read through its
+// snapshot, as a truncated rune's events no longer rebuild it.
+func HandlePurgeRune(ctx context.Context, realmID string, cmd PurgeRune, store core.EventStore) error {
+	state, _, err := readAndRebuild(ctx, realmID, cmd.ID, store)
+	if err != nil {
+		return err
+	}
+	streamID := runeStreamID(cmd.ID)
+	events, err := store.ReadStream(ctx, realmID, streamID, 0)
+	if err != nil {
+		return err
+	}
+	if isPurged(events, EventRunePurged) {
+		return nil
+	}
+	if !state.Exists {
+		return &core.NotFoundError{Entity: "rune", ID: cmd.ID}
+	}
+	if state.Status != "shattered" {
+		return Rejectf(ErrInvalidCommand, "cannot purge rune %q: must be shattered first", cmd.ID)
+	}
+	return purgeStream(ctx, realmID, streamID, events, store, core.EventData{
+		EventType: EventRunePurged,
+		Data:      RunePurged(cmd),
+	})
+}
+
+// HandlePurgeAccount redacts the events of a suspended account down to
+// their IDs, times and roles, and tombstones its stream with AccountPurged.
+// The account's username becomes "purged-" and its ID, so it can be taken
+// again. Purging a purged account does nothing.
+func HandlePurgeAccount(ctx context.Context, cmd PurgeAccount, store core.EventStore) error {
+	state, events, err := readAndRebuildAccountState(ctx, cmd.AccountID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return &core.NotFoundError{Entity: "account", ID: cmd.AccountID}
+	}
+	if isPurged(events, EventAccountPurged) {
+		return nil
+	}
+	if state.Status != "suspended" {
+		return Rejectf(ErrInvalidCommand, "cannot purge account %q: must be suspended first", cmd.AccountID)
+	}
+	return purgeStream(ctx, AdminRealmID, accountStreamID(cmd.AccountID), events, store, core.EventData{
+		EventType: EventAccountPurged,
+		Data:      AccountPurged(cmd),
+	})
+}
+
+func isPurged(events []core.Event, tombstone string) bool {
+	return len(events) > 0 && events[len(events)-1].EventType == tombstone
+}
+
+// purgeStream redacts the stream's events and appends the tombstone after
+// them.
+func purgeStream(ctx context.Context, realmID, streamID string, events []core.Event, store core.EventStore, tombstone core.EventData) error {
+	redactor, ok := store.(core.EventRedactor)
+	if !ok {
+		return errors.New("event store cannot redact events")
+	}
+	redacted := make([]core.Event, len(events))
+	for i, evt := range events {
+		data, err := redactEventData(evt)
+		if err != nil {
+			return err
+		}
+		evt.Data = data
+		redacted[i] = evt
+	}
+	if err := redactor.RedactStream(ctx, realmID, streamID, redacted); err != nil {
+		return err
+	}
+	version := 0
+	if len(events) > 0 {
+		version = events[len(events)-1].Version
+	}
+	_, err := store.Append(ctx, realmID, streamID, version, []core.EventData{tombstone})
+	return err
+}
+
+// redactEventData keeps the fields of an event that identify things by ID,
+// say when things happened, or relate things to each other, which is what
+// projectors need to replay the stream, and drops everything else.
+func redactEventData(evt core.Event) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(evt.Data, &fields); err != nil {
+		return nil, err
+	}
+	kept := make(map[string]json.RawMessage)
+	for name, value := range fields {
+		if keepsWhenRedacted(name) {
+			kept[name] = value
+		}
+	}
+	if evt.EventType == EventAccountCreated {
+		var data AccountCreated
+		if err := json.Unmarshal(evt.Data, &data); err != nil {
+			return nil, err
+		}
+		username, err := json.Marshal("purged-" + data.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		kept["username"] = username
+	}
+	return json.Marshal(kept)
+}
+
+func keepsWhenRedacted(field string) bool {
+	switch field {
+	case "id", "key_hash", "role", "relationship", "sequence":
+		return true
+	}
+	return strings.HasSuffix(field, "_id") || strings.HasSuffix(field, "_at")
+}
@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestHandlePurgeRune(t *testing.T) {
+	t.Run("redacts a shattered rune's events and tombstones its stream", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.rune_stream(
+			makeEvent(EventRuneCreated, RuneCreated{ID: "bf-1", Title: "Call Jane Doe", Description: "jane@example.com", ParentID: "bf-0"}),
+			makeEvent(EventRuneClaimed, RuneClaimed{ID: "bf-1", Claimant: "Jane Doe", AccountID: "acct-1"}),
+			makeEvent(EventRuneFulfilled, RuneFulfilled{ID: "bf-1"}),
+			makeEvent(EventRuneShattered, RuneShattered{ID: "bf-1"}),
+		)
+
+		// When
+		tc.rune_purge_is_handled("bf-1")
+
+		// Then
+		tc.no_error()
+		tc.redacted_data_is(0, `{"id":"bf-1","parent_id":"bf-0"}`)
+		tc.redacted_data_is(1, `{"id":"bf-1","account_id":"acct-1"}`)
+		tc.tombstone_is_appended(runeStreamID("bf-1"), 4, EventRunePurged)
+	})
+
+	t.Run("rejects a rune that is not shattered", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.rune_stream(makeEvent(EventRuneCreated, RuneCreated{ID: "bf-1", Title: "Call Jane Doe"}))
+
+		// When
+		tc.rune_purge_is_handled("bf-1")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+		tc.nothing_is_redacted()
+	})
+
+	t.Run("does nothing for a purged rune", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.rune_stream(
+			makeEvent(EventRuneCreated, RuneCreated{ID: "bf-1"}),
+			makeEvent(EventRuneShattered, RuneShattered{ID: "bf-1"}),
+			makeEvent(EventRunePurged, RunePurged{ID: "bf-1"}),
+		)
+
+		// When
+		tc.rune_purge_is_handled("bf-1")
+
+		// Then
+		tc.no_error()
+		tc.nothing_is_redacted()
+	})
+
+	t.Run("returns not found for an unknown rune", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// When
+		tc.rune_purge_is_handled("bf-404")
+
+		// Then
+		var nfErr *core.NotFoundError
+		assert.ErrorAs(t, tc.err, &nfErr)
+	})
+
+	t.Run("fails when the event store cannot redact events", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.rune_stream(
+			makeEvent(EventRuneCreated, RuneCreated{ID: "bf-1"}),
+			makeEvent(EventRuneShattered, RuneShattered{ID: "bf-1"}),
+		)
+
+		// When
+		tc.err = HandlePurgeRune(tc.ctx, "realm-1", PurgeRune{ID: "bf-1"}, tc.eventStore.mockEventStore)
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+}
+
+func TestHandlePurgeAccount(t *testing.T) {
+	t.Run("redacts a suspended account's events and frees its username", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.account_stream(
+			makeEvent(EventAccountCreated, AccountCreated{AccountID: "acct-1", Username: "jane"}),
+			makeEvent(EventPATCreated, PATCreated{AccountID: "acct-1", PATID: "pat-1", KeyHash: "hash-1", Label: "Jane's laptop"}),
+			makeEvent(EventAccountSuspended, AccountSuspended{AccountID: "acct-1", Reason: "asked to be forgotten"}),
+		)
+
+		// When
+		tc.account_purge_is_handled("acct-1")
+
+		// Then
+		tc.no_error()
+		tc.redacted_data_is(0, `{"account_id":"acct-1","username":"purged-acct-1","created_at":"0001-01-01T00:00:00Z"}`)
+		tc.redacted_data_is(1, `{"account_id":"acct-1","pat_id":"pat-1","key_hash":"hash-1","created_at":"0001-01-01T00:00:00Z"}`)
+		tc.redacted_data_is(2, `{"account_id":"acct-1"}`)
+		tc.tombstone_is_appended(accountStreamID("acct-1"), 3, EventAccountPurged)
+	})
+
+	t.Run("rejects an account that is not suspended", func(t *testing.T) {
+		tc := newPurgeTestContext(t)
+
+		// Given
+		tc.account_stream(makeEvent(EventAccountCreated, AccountCreated{AccountID: "acct-1", Username: "jane"}))
+
+		// When
+		tc.account_purge_is_handled("acct-1")
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+		tc.nothing_is_redacted()
+	})
+}
+
+// --- Test Context ---
+
+type purgeTestContext struct {
+	t *testing.T
+
+	eventStore *redactingEventStore
+	ctx        context.Context
+
+	err error
+}
+
+func newPurgeTestContext(t *testing.T) *purgeTestContext {
+	t.Helper()
+	return &purgeTestContext{
+		t:          t,
+		eventStore: &redactingEventStore{mockEventStore: newMockEventStore()},
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *purgeTestContext) rune_stream(events ...core.Event) {
+	tc.t.Helper()
+	tc.eventStore.streams[runeStreamID("bf-1")] = events
+}
+
+func (tc *purgeTestContext) account_stream(events ...core.Event) {
+	tc.t.Helper()
+	tc.eventStore.streams[accountStreamID("acct-1")] = events
+}
+
+// --- When ---
+
+func (tc *purgeTestContext) rune_purge_is_handled(runeID string) {
+	tc.t.Helper()
+	tc.err = HandlePurgeRune(tc.ctx, "realm-1", PurgeRune{ID: runeID}, tc.eventStore)
+}
+
+func (tc *purgeTestContext) account_purge_is_handled(accountID string) {
+	tc.t.Helper()
+	tc.err = HandlePurgeAccount(tc.ctx, PurgeAccount{AccountID: accountID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *purgeTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *purgeTestContext) redacted_data_is(index int, expected string) {
+	tc.t.Helper()
+	require.Greater(tc.t, len(tc.eventStore.redacted), index)
+	assert.JSONEq(tc.t, expected, string(tc.eventStore.redacted[index].Data))
+}
+
+func (tc *purgeTestContext) nothing_is_redacted() {
+	tc.t.Helper()
+	assert.Empty(tc.t, tc.eventStore.redacted)
+	assert.Empty(tc.t, tc.eventStore.appendedCalls)
+}
+
+func (tc *purgeTestContext) tombstone_is_appended(streamID string, expectedVersion int, eventType string) {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, streamID, call.streamID)
+	assert.Equal(tc.t, expectedVersion, call.expectedVersion)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, eventType, call.events[0].EventType)
+}
+
+// --- Mock Redacting Event Store ---
+
+type redactingEventStore struct {
+	*mockEventStore
+	redacted []core.Event
+}
+
+func (m *redactingEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []core.Event) error {
+	m.redacted = append(m.redacted, redacted...)
+	return nil
+}
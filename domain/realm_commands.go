@@ -8,3 +8,14 @@ type SuspendRealm struct {
 	RealmID string `json:"realm_id"`
 	Reason  string `json:"reason"`
 }
+
+type SetRealmSetting struct {
+	RealmID string `json:"realm_id"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+type DeleteRealmSetting struct {
+	RealmID string `json:"realm_id"`
+	Key     string `json:"key"`
+}
@@ -3,8 +3,10 @@ package domain
 import "time"
 
 const (
-	EventRealmCreated   = "RealmCreated"
-	EventRealmSuspended = "RealmSuspended"
+	EventRealmCreated        = "RealmCreated"
+	EventRealmSuspended      = "RealmSuspended"
+	EventRealmSettingSet     = "RealmSettingSet"
+	EventRealmSettingDeleted = "RealmSettingDeleted"
 )
 
 type RealmCreated struct {
@@ -17,3 +19,14 @@ type RealmSuspended struct {
 	RealmID string `json:"realm_id"`
 	Reason  string `json:"reason"`
 }
+
+type RealmSettingSet struct {
+	RealmID string `json:"realm_id"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+type RealmSettingDeleted struct {
+	RealmID string `json:"realm_id"`
+	Key     string `json:"key"`
+}
@@ -116,6 +116,8 @@ func (tc *realmEvtTestContext) realm_event_type_constants_are_correct() {
 	tc.t.Helper()
 	assert.Equal(tc.t, "RealmCreated", EventRealmCreated)
 	assert.Equal(tc.t, "RealmSuspended", EventRealmSuspended)
+	assert.Equal(tc.t, "RealmSettingSet", EventRealmSettingSet)
+	assert.Equal(tc.t, "RealmSettingDeleted", EventRealmSettingDeleted)
 }
 
 func (tc *realmEvtTestContext) realm_created_fields_match() {
@@ -6,7 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/devzeebo/bifrost/core"
 )
@@ -17,10 +17,11 @@ const (
 )
 
 type RealmState struct {
-	RealmID string
-	Name    string
-	Status  string
-	Exists  bool
+	RealmID  string
+	Name     string
+	Status   string
+	Settings map[string]string
+	Exists   bool
 }
 
 type CreateRealmResult struct {
@@ -40,6 +41,17 @@ func rebuildRealmState(events []core.Event) RealmState {
 			state.Status = "active"
 		case EventRealmSuspended:
 			state.Status = "suspended"
+		case EventRealmSettingSet:
+			var data RealmSettingSet
+			_ = json.Unmarshal(evt.Data, &data)
+			if state.Settings == nil {
+				state.Settings = make(map[string]string)
+			}
+			state.Settings[data.Key] = data.Value
+		case EventRealmSettingDeleted:
+			var data RealmSettingDeleted
+			_ = json.Unmarshal(evt.Data, &data)
+			delete(state.Settings, data.Key)
 		}
 	}
 	return state
@@ -67,6 +79,23 @@ func readAndRebuildRealmState(ctx context.Context, realmID string, store core.Ev
 	return state, events, nil
 }
 
+// RequireActiveRealm returns a NotFoundError if the realm does not exist and
+// an ErrSuspended rejection if it is suspended. It reads the realm's stream,
+// so a realm created or suspended just before is seen.
+func RequireActiveRealm(ctx context.Context, realmID string, store core.EventStore) error {
+	state, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return &core.NotFoundError{Entity: "realm", ID: realmID}
+	}
+	if state.Status == "suspended" {
+		return Rejectf(ErrSuspended, "realm %q is suspended", realmID)
+	}
+	return nil
+}
+
 func HandleCreateRealm(ctx context.Context, cmd CreateRealm, store core.EventStore) (CreateRealmResult, error) {
 	realmID, err := generateRealmID()
 	if err != nil {
@@ -76,7 +105,7 @@ func HandleCreateRealm(ctx context.Context, cmd CreateRealm, store core.EventSto
 	created := RealmCreated{
 		RealmID:   realmID,
 		Name:      cmd.Name,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
 	}
 
 	streamID := realmStreamID(realmID)
@@ -101,7 +130,7 @@ func HandleSuspendRealm(ctx context.Context, cmd SuspendRealm, store core.EventS
 		return &core.NotFoundError{Entity: "realm", ID: cmd.RealmID}
 	}
 	if state.Status == "suspended" {
-		return fmt.Errorf("realm %q is already suspended", cmd.RealmID)
+		return Rejectf(ErrSuspended, "realm %q is already suspended", cmd.RealmID)
 	}
 
 	suspended := RealmSuspended(cmd)
@@ -112,3 +141,74 @@ func HandleSuspendRealm(ctx context.Context, cmd SuspendRealm, store core.EventS
 	})
 	return err
 }
+
+func HandleSetRealmSetting(ctx context.Context, cmd SetRealmSetting, store core.EventStore) error {
+	if cmd.Key == "" {
+		return Rejectf(ErrInvalidCommand, "realm setting key is required")
+	}
+	if cmd.Key == BranchPatternSetting && cmd.Value != "" {
+		if _, err := compileBranchPattern(cmd.Value); err != nil {
+			return err
+		}
+	}
+	if strings.HasPrefix(cmd.Key, SLASettingPrefix) {
+		if err := validateSLASetting(cmd.Key, cmd.Value); err != nil {
+			return err
+		}
+	}
+	if strings.HasPrefix(cmd.Key, RetentionSettingPrefix) {
+		if err := validateRetentionSetting(cmd.Key, cmd.Value); err != nil {
+			return err
+		}
+	}
+	if cmd.Key == PriorityAgingSetting {
+		if err := validatePriorityAgingSetting(cmd.Value); err != nil {
+			return err
+		}
+	}
+	if strings.HasPrefix(cmd.Key, FeatureSettingPrefix) {
+		if err := validateFeatureSetting(cmd.Key, cmd.Value); err != nil {
+			return err
+		}
+	}
+	if err := validateRuneIDSetting(cmd.Key, cmd.Value); err != nil {
+		return err
+	}
+
+	state, events, err := readAndRebuildRealmState(ctx, cmd.RealmID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return &core.NotFoundError{Entity: "realm", ID: cmd.RealmID}
+	}
+
+	settingSet := RealmSettingSet(cmd)
+
+	streamID := realmStreamID(cmd.RealmID)
+	_, err = store.Append(ctx, AdminRealmID, streamID, len(events), []core.EventData{
+		{EventType: EventRealmSettingSet, Data: settingSet},
+	})
+	return err
+}
+
+func HandleDeleteRealmSetting(ctx context.Context, cmd DeleteRealmSetting, store core.EventStore) error {
+	state, events, err := readAndRebuildRealmState(ctx, cmd.RealmID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists {
+		return &core.NotFoundError{Entity: "realm", ID: cmd.RealmID}
+	}
+	if _, ok := state.Settings[cmd.Key]; !ok {
+		return &core.NotFoundError{Entity: "realm setting", ID: cmd.Key}
+	}
+
+	settingDeleted := RealmSettingDeleted(cmd)
+
+	streamID := realmStreamID(cmd.RealmID)
+	_, err = store.Append(ctx, AdminRealmID, streamID, len(events), []core.EventData{
+		{EventType: EventRealmSettingDeleted, Data: settingDeleted},
+	})
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/devzeebo/bifrost/core"
 	"github.com/stretchr/testify/assert"
@@ -54,6 +55,19 @@ func TestRebuildRealmState(t *testing.T) {
 		// Then
 		tc.realm_state_has_status("suspended")
 	})
+
+	t.Run("applies RealmSettingSet and RealmSettingDeleted", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.events_from_realm_with_settings()
+
+		// When
+		tc.realm_state_is_rebuilt()
+
+		// Then
+		tc.realm_state_has_settings(map[string]string{"github.token": "abc"})
+	})
 }
 
 func TestHandleCreateRealm(t *testing.T) {
@@ -73,6 +87,22 @@ func TestHandleCreateRealm(t *testing.T) {
 		tc.create_realm_event_was_appended_to_admin_realm()
 		tc.create_realm_event_stream_has_realm_prefix()
 	})
+
+	t.Run("stamps the realm with the context's clock", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.an_event_store()
+		tc.a_fixed_clock(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+		tc.a_create_realm_command("My Realm")
+
+		// When
+		tc.handle_create_realm()
+
+		// Then
+		tc.no_realm_error()
+		tc.realm_was_created_at(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	})
 }
 
 func TestHandleSuspendRealm(t *testing.T) {
@@ -124,6 +154,335 @@ func TestHandleSuspendRealm(t *testing.T) {
 	})
 }
 
+func TestRequireActiveRealm(t *testing.T) {
+	t.Run("accepts an active realm", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+
+		// When
+		tc.require_active_realm("bf-a1b2")
+
+		// Then
+		tc.no_realm_error()
+	})
+
+	t.Run("returns not found when realm does not exist", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.empty_realm_stream("bf-missing")
+
+		// When
+		tc.require_active_realm("bf-missing")
+
+		// Then
+		tc.realm_error_is_not_found("realm", "bf-missing")
+	})
+
+	t.Run("rejects a suspended realm", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "suspended")
+
+		// When
+		tc.require_active_realm("bf-a1b2")
+
+		// Then
+		tc.realm_error_contains("suspended")
+		assert.ErrorIs(t, tc.err, ErrSuspended)
+	})
+}
+
+func TestHandleSetRealmSetting(t *testing.T) {
+	t.Run("sets a setting on an existing realm", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "github.token", "abc")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.realm_event_was_appended_to_stream("realm-bf-a1b2")
+		tc.appended_realm_event_has_type(EventRealmSettingSet)
+	})
+
+	t.Run("returns error when key is empty", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "", "abc")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("key is required")
+	})
+
+	t.Run("rejects a branch pattern that does not compile", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", BranchPatternSetting, "feature/(")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("invalid branch pattern")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("accepts an SLA threshold in days", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "sla.claimed", "7d")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.appended_realm_event_has_type(EventRealmSettingSet)
+	})
+
+	t.Run("rejects an SLA for a final status", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "sla.fulfilled", "7d")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("must be one of")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("rejects an SLA threshold that does not parse", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "sla.open", "a week")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("invalid SLA threshold")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("rejects priority aging thresholds that do not increase", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", PriorityAgingSetting, "14d,7d")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("thresholds must increase")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("accepts a retention period in days", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "retention.finished_runes", "90d")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.appended_realm_event_has_type(EventRealmSettingSet)
+	})
+
+	t.Run("rejects an unknown retention setting", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "retention.notes", "90d")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("must be retention.finished_runes, retention.transitions or retention.shattered_runes")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("rejects a retention period that is not in days", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "retention.transitions", "36h")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("positive number of days")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("accepts a feature flag override", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "feature.strict_blocking", "true")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.appended_realm_event_has_type(EventRealmSettingSet)
+	})
+
+	t.Run("rejects an unknown feature flag", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "feature.time_travel", "true")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains(`unknown feature flag "time_travel"`)
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("rejects a feature flag value that is not a boolean", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", "feature.strict_blocking", "sometimes")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains("must be true or false")
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("accepts a sequential rune ID format", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", RuneIDFormatSetting, RuneIDFormatSequential)
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.appended_realm_event_has_type(EventRealmSettingSet)
+	})
+
+	t.Run("rejects an unknown rune ID format", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", RuneIDFormatSetting, "uuid")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains(`rune ID format "uuid" must be one of hex, sequential, ulid`)
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("rejects a rune ID prefix that is not alphanumeric", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_set_realm_setting_command("bf-a1b2", RuneIDPrefixSetting, "proj-x")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_contains(`rune ID prefix "proj-x"`)
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+
+	t.Run("returns error when realm does not exist", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.empty_realm_stream("bf-missing")
+		tc.a_set_realm_setting_command("bf-missing", "github.token", "abc")
+
+		// When
+		tc.handle_set_realm_setting()
+
+		// Then
+		tc.realm_error_is_not_found("realm", "bf-missing")
+	})
+}
+
+func TestHandleDeleteRealmSetting(t *testing.T) {
+	t.Run("deletes an existing setting", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_with_setting("bf-a1b2", "github.token", "abc")
+		tc.a_delete_realm_setting_command("bf-a1b2", "github.token")
+
+		// When
+		tc.handle_delete_realm_setting()
+
+		// Then
+		tc.no_realm_error()
+		tc.appended_realm_event_has_type(EventRealmSettingDeleted)
+	})
+
+	t.Run("returns error when setting does not exist", func(t *testing.T) {
+		tc := newRealmHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm_in_stream("bf-a1b2", "active")
+		tc.a_delete_realm_setting_command("bf-a1b2", "github.token")
+
+		// When
+		tc.handle_delete_realm_setting()
+
+		// Then
+		tc.realm_error_is_not_found("realm setting", "github.token")
+	})
+}
+
 // --- Test Context ---
 
 type realmHandlerTestContext struct {
@@ -132,8 +491,10 @@ type realmHandlerTestContext struct {
 	eventStore *mockEventStore
 	ctx        context.Context
 
-	createRealmCmd  CreateRealm
-	suspendRealmCmd SuspendRealm
+	createRealmCmd        CreateRealm
+	suspendRealmCmd       SuspendRealm
+	setRealmSettingCmd    SetRealmSetting
+	deleteRealmSettingCmd DeleteRealmSetting
 
 	createRealmResult CreateRealmResult
 	realmState        RealmState
@@ -151,6 +512,11 @@ func newRealmHandlerTestContext(t *testing.T) *realmHandlerTestContext {
 
 // --- Given ---
 
+func (tc *realmHandlerTestContext) a_fixed_clock(t time.Time) {
+	tc.t.Helper()
+	tc.ctx = core.ContextWithClock(tc.ctx, core.FixedClock(t))
+}
+
 func (tc *realmHandlerTestContext) an_event_store() {
 	tc.t.Helper()
 	if tc.eventStore == nil {
@@ -184,6 +550,16 @@ func (tc *realmHandlerTestContext) events_from_created_and_suspended_realm() {
 	}
 }
 
+func (tc *realmHandlerTestContext) events_from_realm_with_settings() {
+	tc.t.Helper()
+	tc.realmEvents = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: "bf-a1b2", Name: "Test Realm"}),
+		makeEvent(EventRealmSettingSet, RealmSettingSet{RealmID: "bf-a1b2", Key: "github.token", Value: "abc"}),
+		makeEvent(EventRealmSettingSet, RealmSettingSet{RealmID: "bf-a1b2", Key: "github.webhook_secret", Value: "s3cret"}),
+		makeEvent(EventRealmSettingDeleted, RealmSettingDeleted{RealmID: "bf-a1b2", Key: "github.webhook_secret"}),
+	}
+}
+
 func (tc *realmHandlerTestContext) existing_realm_in_stream(realmID string, status string) {
 	tc.t.Helper()
 	tc.an_event_store()
@@ -200,6 +576,14 @@ func (tc *realmHandlerTestContext) existing_realm_in_stream(realmID string, stat
 	tc.eventStore.streams["realm-"+realmID] = events
 }
 
+func (tc *realmHandlerTestContext) existing_realm_with_setting(realmID, key, value string) {
+	tc.t.Helper()
+	tc.existing_realm_in_stream(realmID, "active")
+	tc.eventStore.streams["realm-"+realmID] = append(tc.eventStore.streams["realm-"+realmID],
+		makeEvent(EventRealmSettingSet, RealmSettingSet{RealmID: realmID, Key: key, Value: value}),
+	)
+}
+
 func (tc *realmHandlerTestContext) empty_realm_stream(realmID string) {
 	tc.t.Helper()
 	tc.an_event_store()
@@ -216,6 +600,16 @@ func (tc *realmHandlerTestContext) a_suspend_realm_command(realmID, reason strin
 	tc.suspendRealmCmd = SuspendRealm{RealmID: realmID, Reason: reason}
 }
 
+func (tc *realmHandlerTestContext) a_set_realm_setting_command(realmID, key, value string) {
+	tc.t.Helper()
+	tc.setRealmSettingCmd = SetRealmSetting{RealmID: realmID, Key: key, Value: value}
+}
+
+func (tc *realmHandlerTestContext) a_delete_realm_setting_command(realmID, key string) {
+	tc.t.Helper()
+	tc.deleteRealmSettingCmd = DeleteRealmSetting{RealmID: realmID, Key: key}
+}
+
 // --- When ---
 
 func (tc *realmHandlerTestContext) realm_state_is_rebuilt() {
@@ -233,6 +627,21 @@ func (tc *realmHandlerTestContext) handle_suspend_realm() {
 	tc.err = HandleSuspendRealm(tc.ctx, tc.suspendRealmCmd, tc.eventStore)
 }
 
+func (tc *realmHandlerTestContext) handle_set_realm_setting() {
+	tc.t.Helper()
+	tc.err = HandleSetRealmSetting(tc.ctx, tc.setRealmSettingCmd, tc.eventStore)
+}
+
+func (tc *realmHandlerTestContext) require_active_realm(realmID string) {
+	tc.t.Helper()
+	tc.err = RequireActiveRealm(tc.ctx, realmID, tc.eventStore)
+}
+
+func (tc *realmHandlerTestContext) handle_delete_realm_setting() {
+	tc.t.Helper()
+	tc.err = HandleDeleteRealmSetting(tc.ctx, tc.deleteRealmSettingCmd, tc.eventStore)
+}
+
 // --- Then ---
 
 func (tc *realmHandlerTestContext) no_realm_error() {
@@ -280,6 +689,11 @@ func (tc *realmHandlerTestContext) realm_state_has_status(expected string) {
 	assert.Equal(tc.t, expected, tc.realmState.Status)
 }
 
+func (tc *realmHandlerTestContext) realm_state_has_settings(expected map[string]string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.realmState.Settings)
+}
+
 func (tc *realmHandlerTestContext) create_realm_result_has_realm_id_matching_pattern() {
 	tc.t.Helper()
 	assert.Regexp(tc.t, `^bf-[0-9a-f]{4}$`, tc.createRealmResult.RealmID)
@@ -292,6 +706,15 @@ func (tc *realmHandlerTestContext) create_realm_event_was_appended_to_admin_real
 	assert.Equal(tc.t, AdminRealmID, lastCall.realmID)
 }
 
+func (tc *realmHandlerTestContext) realm_was_created_at(expected time.Time) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
+	lastCall := tc.eventStore.appendedCalls[len(tc.eventStore.appendedCalls)-1]
+	created, ok := lastCall.events[0].Data.(RealmCreated)
+	require.True(tc.t, ok, "expected RealmCreated data, got %T", lastCall.events[0].Data)
+	assert.Equal(tc.t, expected, created.CreatedAt)
+}
+
 func (tc *realmHandlerTestContext) create_realm_event_stream_has_realm_prefix() {
 	tc.t.Helper()
 	require.NotEmpty(tc.t, tc.eventStore.appendedCalls, "expected at least one Append call")
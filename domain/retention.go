@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetentionSettingPrefix starts the realm settings that set how long a
+// realm keeps old data, in whole days.
+const RetentionSettingPrefix = "retention."
+
+const (
+	// RetentionFinishedRunes is how long a sealed or fulfilled rune is kept
+	// after its last change before it is shattered.
+	RetentionFinishedRunes = RetentionSettingPrefix + "finished_runes"
+	// RetentionTransitions is how long rune status transitions are kept.
+	RetentionTransitions = RetentionSettingPrefix + "transitions"
+	// RetentionShatteredRunes is how long the events of a shattered rune
+	// are kept before they are deleted.
+	RetentionShatteredRunes = RetentionSettingPrefix + "shattered_runes"
+)
+
+// RetentionPolicy is a realm's retention settings. A zero period keeps the
+// data forever.
+type RetentionPolicy struct {
+	FinishedRunes  time.Duration
+	Transitions    time.Duration
+	ShatteredRunes time.Duration
+}
+
+// IsZero reports whether the policy keeps everything.
+func (p RetentionPolicy) IsZero() bool {
+	return p.FinishedRunes == 0 && p.Transitions == 0 && p.ShatteredRunes == 0
+}
+
+// ParseRetentionPeriod reads a period written in whole days ("90d").
+func ParseRetentionPeriod(value string) (time.Duration, error) {
+	days, ok := strings.CutSuffix(strings.TrimSpace(value), "d")
+	n, err := strconv.Atoi(days)
+	if !ok || err != nil || n <= 0 {
+		return 0, Rejectf(ErrInvalidCommand, "retention period %q must be a positive number of days, such as 90d", value)
+	}
+	return time.Duration(n) * 24 * time.Hour, nil
+}
+
+// RetentionPolicyFromSettings returns the realm's retention policy, leaving
+// out periods that do not parse.
+func RetentionPolicyFromSettings(settings map[string]string) RetentionPolicy {
+	var policy RetentionPolicy
+	if d, err := ParseRetentionPeriod(settings[RetentionFinishedRunes]); err == nil {
+		policy.FinishedRunes = d
+	}
+	if d, err := ParseRetentionPeriod(settings[RetentionTransitions]); err == nil {
+		policy.Transitions = d
+	}
+	if d, err := ParseRetentionPeriod(settings[RetentionShatteredRunes]); err == nil {
+		policy.ShatteredRunes = d
+	}
+	return policy
+}
+
+// validateRetentionSetting rejects an unknown retention.* setting or a
+// period that does not parse.
+func validateRetentionSetting(key, value string) error {
+	switch key {
+	case RetentionFinishedRunes, RetentionTransitions, RetentionShatteredRunes:
+	default:
+		return Rejectf(ErrInvalidCommand, "retention setting %q must be %s, %s or %s", key, RetentionFinishedRunes, RetentionTransitions, RetentionShatteredRunes)
+	}
+	_, err := ParseRetentionPeriod(value)
+	return err
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type PruneRuneHistory struct {
+	Before time.Time `json:"before"`
+}
+
+type TruncateShatteredRunes struct {
+	Before time.Time `json:"before"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+const (
+	EventRuneHistoryPruned   = "RuneHistoryPruned"
+	EventRuneEventsTruncated = "RuneEventsTruncated"
+)
+
+// RuneHistoryPruned records that the realm's rune status transitions from
+// before Before were discarded under its retention policy.
+type RuneHistoryPruned struct {
+	Before time.Time `json:"before"`
+}
+
+// RuneEventsTruncated records that the events of Runes runes shattered
+// before Before were deleted under the realm's retention policy, all but
+// each rune's RuneShattered event.
+type RuneEventsTruncated struct {
+	Before time.Time `json:"before"`
+	Runes  int       `json:"runes"`
+}
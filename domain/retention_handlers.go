@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// retentionStreamID is the stream, in each realm, recording what its
+// retention policy pruned.
+const retentionStreamID = "retention"
+
+// HandlePruneRuneHistory records that rune status transitions before
+// cmd.Before are to be discarded, and reports whether it recorded anything.
+// Pruning up to a time no later than an earlier prune does nothing, so the
+// retention job can repeat it safely.
+func HandlePruneRuneHistory(ctx context.Context, realmID string, cmd PruneRuneHistory, store core.EventStore) (bool, error) {
+	if cmd.Before.IsZero() {
+		return false, Rejectf(ErrInvalidCommand, "prune time is required")
+	}
+	events, err := store.ReadStream(ctx, realmID, retentionStreamID, 0)
+	if err != nil {
+		return false, err
+	}
+	var prunedBefore time.Time
+	for _, evt := range events {
+		if evt.EventType != EventRuneHistoryPruned {
+			continue
+		}
+		var data RuneHistoryPruned
+		if err := json.Unmarshal(evt.Data, &data); err == nil && data.Before.After(prunedBefore) {
+			prunedBefore = data.Before
+		}
+	}
+	if !cmd.Before.After(prunedBefore) {
+		return false, nil
+	}
+
+	pruned := RuneHistoryPruned{Before: cmd.Before.UTC()}
+
+	_, err = store.Append(ctx, realmID, retentionStreamID, len(events), []core.EventData{
+		{EventType: EventRuneHistoryPruned, Data: pruned},
+	})
+	return err == nil, err
+}
+
+// HandleTruncateShatteredRunes deletes the events of the runes shattered
+// before cmd.Before, keeping each one's RuneShattered event so its ID is
+// never handed out again, and records how many were truncated. Each rune's
+// state is snapshotted first, so commands such as a purge still find it.
+// Shattered runes are already gone from the read models, so no projection
+// changes. Nothing is truncated when store keeps no snapshots.
+func HandleTruncateShatteredRunes(ctx context.Context, realmID string, cmd TruncateShatteredRunes, store core.EventStore, truncator core.EventTruncator) (int, error) {
+	if cmd.Before.IsZero() {
+		return 0, Rejectf(ErrInvalidCommand, "truncation time is required")
+	}
+	streamIDs, err := truncator.TruncatableStreams(ctx, realmID, EventRuneShattered, cmd.Before)
+	if err != nil || len(streamIDs) == 0 {
+		return 0, err
+	}
+	snapshots, ok := store.(core.SnapshotStore)
+	if !ok {
+		return 0, errors.New("truncating runes needs an event store that keeps snapshots")
+	}
+	for _, streamID := range streamIDs {
+		runeID, ok := strings.CutPrefix(streamID, runeStreamPrefix)
+		if !ok {
+			return 0, fmt.Errorf("stream %q ending in %s is not a rune's", streamID, EventRuneShattered)
+		}
+		if err := snapshotRune(ctx, realmID, runeID, store, snapshots); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := truncator.TruncateStreams(ctx, realmID, EventRuneShattered, cmd.Before)
+	if err != nil || n == 0 {
+		return n, err
+	}
+
+	truncated := RuneEventsTruncated{Before: cmd.Before.UTC(), Runes: n}
+
+	err = core.WithRetry(ctx, maxCommandAttempts, func() error {
+		events, err := store.ReadStream(ctx, realmID, retentionStreamID, 0)
+		if err != nil {
+			return err
+		}
+		_, err = store.Append(ctx, realmID, retentionStreamID, len(events), []core.EventData{
+			{EventType: EventRuneEventsTruncated, Data: truncated},
+		})
+		return err
+	})
+	return n, err
+}
@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestHandlePruneRuneHistory(t *testing.T) {
+	before := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("records the prune in the realm's retention stream", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// When
+		tc.prune_is_handled(before)
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.pruned)
+		pruned := tc.only_appended_event(EventRuneHistoryPruned, 0).(RuneHistoryPruned)
+		assert.Equal(t, before, pruned.Before)
+	})
+
+	t.Run("records a later prune after an earlier one", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.pruned_before(before)
+
+		// When
+		tc.prune_is_handled(before.AddDate(0, 0, 1))
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.pruned)
+		tc.only_appended_event(EventRuneHistoryPruned, 1)
+	})
+
+	t.Run("does nothing when history was already pruned that far", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.pruned_before(before)
+
+		// When
+		tc.prune_is_handled(before.AddDate(0, 0, -1))
+
+		// Then
+		tc.no_error()
+		assert.False(t, tc.pruned)
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("rejects a prune without a time", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// When
+		tc.prune_is_handled(time.Time{})
+
+		// Then
+		require.Error(t, tc.err)
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+	})
+}
+
+func TestHandleTruncateShatteredRunes(t *testing.T) {
+	before := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("truncates shattered runes and records how many", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.pruned_before(before)
+		tc.shattered_rune("bf-a1")
+		tc.shattered_rune("bf-b2")
+		tc.shattered_rune("bf-c3")
+
+		// When
+		tc.truncate_is_handled(before)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 3, tc.truncated)
+		assert.Equal(t, EventRuneShattered, tc.truncator.lastEventType)
+		assert.Equal(t, before, tc.truncator.before)
+		recorded := tc.only_appended_event(EventRuneEventsTruncated, 1).(RuneEventsTruncated)
+		assert.Equal(t, RuneEventsTruncated{Before: before, Runes: 3}, recorded)
+	})
+
+	t.Run("snapshots each rune at its last event before truncating it", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.shattered_rune("bf-a1")
+
+		// When
+		tc.truncate_is_handled(before)
+
+		// Then
+		tc.no_error()
+		snapshot := tc.snapshot_of("bf-a1")
+		assert.Equal(t, 3, snapshot.Version)
+		var saved runeSnapshot
+		require.NoError(t, json.Unmarshal(snapshot.State, &saved))
+		assert.True(t, saved.State.Exists)
+		assert.Equal(t, "shattered", saved.State.Status)
+		assert.Equal(t, "Rune bf-a1", saved.State.Title)
+	})
+
+	t.Run("truncates nothing when the event store keeps no snapshots", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.shattered_rune("bf-a1")
+		tc.event_store_keeps_no_snapshots()
+
+		// When
+		tc.truncate_is_handled(before)
+
+		// Then
+		assert.ErrorContains(t, tc.err, "snapshots")
+		assert.Empty(t, tc.truncator.lastEventType)
+	})
+
+	t.Run("records nothing when no rune was truncated", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// When
+		tc.truncate_is_handled(before)
+
+		// Then
+		tc.no_error()
+		assert.Zero(t, tc.truncated)
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("rejects a truncation without a time", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// When
+		tc.truncate_is_handled(time.Time{})
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrInvalidCommand)
+		assert.Empty(t, tc.truncator.lastEventType)
+	})
+}
+
+func TestRetentionPolicyFromSettings(t *testing.T) {
+	t.Run("reads periods in days and leaves out the rest", func(t *testing.T) {
+		// When
+		policy := RetentionPolicyFromSettings(map[string]string{
+			RetentionFinishedRunes:  "30d",
+			RetentionTransitions:    "a year",
+			RetentionShatteredRunes: "180d",
+		})
+
+		// Then
+		assert.Equal(t, RetentionPolicy{FinishedRunes: 30 * 24 * time.Hour, ShatteredRunes: 180 * 24 * time.Hour}, policy)
+		assert.False(t, policy.IsZero())
+	})
+}
+
+// --- Test Context ---
+
+type retentionTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	store      core.EventStore
+	truncator  *mockTruncator
+	ctx        context.Context
+
+	pruned    bool
+	truncated int
+	err       error
+}
+
+func newRetentionTestContext(t *testing.T) *retentionTestContext {
+	t.Helper()
+	eventStore := newMockEventStore()
+	return &retentionTestContext{
+		t:          t,
+		eventStore: eventStore,
+		store:      &snapshottingEventStore{mockEventStore: eventStore, snapshots: make(map[string]core.Snapshot)},
+		truncator:  &mockTruncator{},
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *retentionTestContext) pruned_before(before time.Time) {
+	tc.t.Helper()
+	tc.eventStore.streams["retention"] = []core.Event{
+		makeEvent(EventRuneHistoryPruned, RuneHistoryPruned{Before: before}),
+	}
+}
+
+func (tc *retentionTestContext) shattered_rune(runeID string) {
+	tc.t.Helper()
+	streamID := runeStreamID(runeID)
+	tc.eventStore.streams[streamID] = []core.Event{
+		makeEvent(EventRuneCreated, RuneCreated{ID: runeID, Title: "Rune " + runeID}),
+		makeEvent(EventRuneFulfilled, RuneFulfilled{ID: runeID}),
+		makeEvent(EventRuneShattered, RuneShattered{ID: runeID}),
+	}
+	tc.truncator.streamIDs = append(tc.truncator.streamIDs, streamID)
+	tc.truncator.truncates++
+}
+
+func (tc *retentionTestContext) event_store_keeps_no_snapshots() {
+	tc.t.Helper()
+	tc.store = tc.eventStore
+}
+
+// --- When ---
+
+func (tc *retentionTestContext) prune_is_handled(before time.Time) {
+	tc.t.Helper()
+	tc.pruned, tc.err = HandlePruneRuneHistory(tc.ctx, "realm-1", PruneRuneHistory{Before: before}, tc.eventStore)
+}
+
+func (tc *retentionTestContext) truncate_is_handled(before time.Time) {
+	tc.t.Helper()
+	tc.truncated, tc.err = HandleTruncateShatteredRunes(tc.ctx, "realm-1", TruncateShatteredRunes{Before: before}, tc.store, tc.truncator)
+}
+
+// --- Then ---
+
+func (tc *retentionTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *retentionTestContext) snapshot_of(runeID string) core.Snapshot {
+	tc.t.Helper()
+	snapshot, ok := tc.store.(*snapshottingEventStore).snapshots[runeStreamID(runeID)]
+	require.True(tc.t, ok, "no snapshot of %s", runeID)
+	return snapshot
+}
+
+func (tc *retentionTestContext) only_appended_event(eventType string, expectedVersion int) any {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, "realm-1", call.realmID)
+	assert.Equal(tc.t, "retention", call.streamID)
+	assert.Equal(tc.t, expectedVersion, call.expectedVersion)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, eventType, call.events[0].EventType)
+	return call.events[0].Data
+}
+
+// --- Mock Truncator ---
+
+type mockTruncator struct {
+	streamIDs     []string
+	truncates     int
+	lastEventType string
+	before        time.Time
+}
+
+func (m *mockTruncator) TruncatableStreams(_ context.Context, _ string, _ string, _ time.Time) ([]string, error) {
+	return m.streamIDs, nil
+}
+
+func (m *mockTruncator) TruncateStreams(_ context.Context, _ string, lastEventType string, before time.Time) (int, error) {
+	m.lastEventType, m.before = lastEventType, before
+	return m.truncates, nil
+}
+
+// --- Mock Snapshotting Event Store ---
+
+// snapshottingEventStore keeps the snapshots saved to a mock event store.
+type snapshottingEventStore struct {
+	*mockEventStore
+	snapshots map[string]core.Snapshot
+}
+
+func (s *snapshottingEventStore) GetSnapshot(_ context.Context, _ string, streamID string) (core.Snapshot, error) {
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	return snapshot, nil
+}
+
+func (s *snapshottingEventStore) SaveSnapshot(_ context.Context, snapshot core.Snapshot) error {
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Realm settings that choose how the realm's top-level runes are named.
+// Child runes are always named after their parent, as bf-a1b2.1.
+const (
+	// RuneIDFormatSetting is one of the RuneIDFormat values. Without it
+	// runes get hex IDs.
+	RuneIDFormatSetting = "rune_id.format"
+	// RuneIDPrefixSetting replaces the "bf" rune IDs start with.
+	RuneIDPrefixSetting = "rune_id.prefix"
+)
+
+// Rune ID formats.
+const (
+	// RuneIDFormatHex names runes with four random hex digits: bf-a1b2.
+	RuneIDFormatHex = "hex"
+	// RuneIDFormatSequential numbers runes in the order they are created:
+	// PROJ-1, PROJ-2.
+	RuneIDFormatSequential = "sequential"
+	// RuneIDFormatULID names runes with a ULID, which sorts by creation
+	// time: bf-01ARYZ6S41TSV4RRFFQ69G5FAV.
+	RuneIDFormatULID = "ulid"
+)
+
+// RuneIDFormats lists the formats RuneIDFormatSetting accepts.
+var RuneIDFormats = []string{RuneIDFormatHex, RuneIDFormatSequential, RuneIDFormatULID}
+
+const defaultRuneIDPrefix = "bf"
+
+var runeIDPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{0,15}$`)
+
+// runeIDSequenceStreamID holds a realm's RuneIDAllocated events. It must not
+// start with runeStreamPrefix, or it would be taken for a rune's stream.
+const runeIDSequenceStreamID = "sequence-rune-ids"
+
+// maxRuneIDAttempts bounds how many candidates HandleCreateRune tries before
+// giving up on finding an ID no rune has.
+const maxRuneIDAttempts = 10
+
+// RuneIDGenerator proposes IDs for a realm's new top-level runes.
+type RuneIDGenerator interface {
+	// NextID returns a candidate ID. It may be taken already; the caller
+	// checks and asks again.
+	NextID(ctx context.Context, realmID string, store core.EventStore) (string, error)
+}
+
+// HexRuneIDs proposes a prefix and four random hex digits.
+type HexRuneIDs struct {
+	Prefix string
+}
+
+func (g HexRuneIDs) NextID(ctx context.Context, realmID string, store core.EventStore) (string, error) {
+	b := make([]byte, 2)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate rune ID: %w", err)
+	}
+	return g.Prefix + "-" + hex.EncodeToString(b), nil
+}
+
+// SequentialRuneIDs proposes a prefix and the next number of the realm's
+// sequence. Each number is allocated by an event, so concurrent creates get
+// different numbers; a number whose create then fails is skipped.
+type SequentialRuneIDs struct {
+	Prefix string
+}
+
+func (g SequentialRuneIDs) NextID(ctx context.Context, realmID string, store core.EventStore) (string, error) {
+	var id string
+	err := core.WithRetry(ctx, maxCommandAttempts, func() error {
+		last, err := core.ReadStreamPage(ctx, store, realmID, runeIDSequenceStreamID, 0, 1, core.Backward)
+		if err != nil {
+			return err
+		}
+		version := 0
+		if len(last.Events) > 0 {
+			version = last.Events[0].Version
+		}
+		allocated := RuneIDAllocated{ID: fmt.Sprintf("%s-%d", g.Prefix, version+1), Sequence: version + 1}
+		if _, err := store.Append(ctx, realmID, runeIDSequenceStreamID, version, []core.EventData{
+			{EventType: EventRuneIDAllocated, Data: allocated},
+		}); err != nil {
+			return err
+		}
+		id = allocated.ID
+		return nil
+	})
+	return id, err
+}
+
+// ULIDRuneIDs proposes a prefix and a ULID taken from the context's clock.
+type ULIDRuneIDs struct {
+	Prefix string
+}
+
+func (g ULIDRuneIDs) NextID(ctx context.Context, realmID string, store core.EventStore) (string, error) {
+	var b [16]byte
+	ms := uint64(core.ClockFromContext(ctx).Now().UnixMilli())
+	for i := range 6 {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("generate rune ID: %w", err)
+	}
+	return g.Prefix + "-" + encodeULID(b), nil
+}
+
+// encodeULID writes the 128 bits of b as 26 Crockford base32 digits, the
+// first of which carries just three bits.
+func encodeULID(b [16]byte) string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	out := make([]byte, 26)
+	for i := range out {
+		v := 0
+		for j := range 5 {
+			bit := 5*i + j - 2
+			v <<= 1
+			if bit >= 0 && b[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = alphabet[v]
+	}
+	return string(out)
+}
+
+// RuneIDGeneratorFromSettings returns the generator a realm's settings
+// choose. Settings that do not validate fall back to the defaults.
+func RuneIDGeneratorFromSettings(settings map[string]string) RuneIDGenerator {
+	prefix := strings.TrimSpace(settings[RuneIDPrefixSetting])
+	if !runeIDPrefixPattern.MatchString(prefix) {
+		prefix = defaultRuneIDPrefix
+	}
+	switch strings.TrimSpace(settings[RuneIDFormatSetting]) {
+	case RuneIDFormatSequential:
+		return SequentialRuneIDs{Prefix: prefix}
+	case RuneIDFormatULID:
+		return ULIDRuneIDs{Prefix: prefix}
+	default:
+		return HexRuneIDs{Prefix: prefix}
+	}
+}
+
+func validateRuneIDSetting(key, value string) error {
+	switch key {
+	case RuneIDFormatSetting:
+		for _, format := range RuneIDFormats {
+			if value == format {
+				return nil
+			}
+		}
+		return Rejectf(ErrInvalidCommand, "rune ID format %q must be one of %s", value, strings.Join(RuneIDFormats, ", "))
+	case RuneIDPrefixSetting:
+		if !runeIDPrefixPattern.MatchString(value) {
+			return Rejectf(ErrInvalidCommand, "rune ID prefix %q must be a letter followed by up to 15 letters or digits", value)
+		}
+	}
+	return nil
+}
+
+// newRuneID asks the realm's generator for candidates until it proposes one
+// no rune has yet. Appending the rune at version zero still catches a rune
+// created with the same ID in the meantime.
+func newRuneID(ctx context.Context, realmID string, store core.EventStore) (string, error) {
+	realm, _, err := readAndRebuildRealmState(ctx, realmID, store)
+	if err != nil {
+		return "", err
+	}
+	generator := RuneIDGeneratorFromSettings(realm.Settings)
+	for range maxRuneIDAttempts {
+		id, err := generator.NextID(ctx, realmID, store)
+		if err != nil {
+			return "", err
+		}
+		existing, err := core.ReadStreamPage(ctx, store, realmID, runeStreamID(id), 0, 1, core.Backward)
+		if err != nil {
+			return "", err
+		}
+		if len(existing.Events) == 0 {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("generate rune ID: %d candidates in a row were taken", maxRuneIDAttempts)
+}
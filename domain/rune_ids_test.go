@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestRuneIDGeneratorFromSettings(t *testing.T) {
+	t.Run("defaults to hex IDs starting with bf", func(t *testing.T) {
+		// When
+		generator := RuneIDGeneratorFromSettings(nil)
+
+		// Then
+		assert.Equal(t, HexRuneIDs{Prefix: "bf"}, generator)
+	})
+
+	t.Run("uses the realm's format and prefix", func(t *testing.T) {
+		// When
+		generator := RuneIDGeneratorFromSettings(map[string]string{
+			RuneIDFormatSetting: RuneIDFormatSequential,
+			RuneIDPrefixSetting: "PROJ",
+		})
+
+		// Then
+		assert.Equal(t, SequentialRuneIDs{Prefix: "PROJ"}, generator)
+	})
+
+	t.Run("ignores a prefix that does not validate", func(t *testing.T) {
+		// When
+		generator := RuneIDGeneratorFromSettings(map[string]string{
+			RuneIDFormatSetting: RuneIDFormatULID,
+			RuneIDPrefixSetting: "a.b",
+		})
+
+		// Then
+		assert.Equal(t, ULIDRuneIDs{Prefix: "bf"}, generator)
+	})
+}
+
+func TestEncodeULID(t *testing.T) {
+	t.Run("encodes the timestamp in the first ten digits", func(t *testing.T) {
+		// Given
+		var b [16]byte
+		ms := uint64(1469918176385)
+		for i := range 6 {
+			b[i] = byte(ms >> (40 - 8*i))
+		}
+
+		// When
+		encoded := encodeULID(b)
+
+		// Then
+		assert.Equal(t, "01ARYZ6S410000000000000000", encoded)
+	})
+
+	t.Run("encodes the largest value", func(t *testing.T) {
+		// Given
+		var b [16]byte
+		for i := range b {
+			b[i] = 0xff
+		}
+
+		// When
+		encoded := encodeULID(b)
+
+		// Then
+		assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encoded)
+	})
+}
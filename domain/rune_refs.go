@@ -0,0 +1,21 @@
+package domain
+
+import "regexp"
+
+var runeRefPattern = regexp.MustCompile(`\bbf-[0-9a-f]{4}(?:\.[0-9]+)*\b`)
+
+// ExtractRuneIDs returns the distinct rune IDs referenced in text, in the
+// order they first appear.
+func ExtractRuneIDs(text string) []string {
+	matches := runeRefPattern.FindAllString(text, -1)
+	seen := make(map[string]bool, len(matches))
+	var ids []string
+	for _, m := range matches {
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		ids = append(ids, m)
+	}
+	return ids
+}
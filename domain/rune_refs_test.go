@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestExtractRuneIDs(t *testing.T) {
+	t.Run("returns nil when text has no references", func(t *testing.T) {
+		tc := newRuneRefsTestContext(t)
+
+		// Given
+		tc.text_is("Fix typo in README")
+
+		// When
+		tc.rune_ids_are_extracted()
+
+		// Then
+		tc.extracted_ids_are(nil)
+	})
+
+	t.Run("extracts top-level and child rune IDs", func(t *testing.T) {
+		tc := newRuneRefsTestContext(t)
+
+		// Given
+		tc.text_is("Implements bf-a1b2 and bf-c3d4.1.2")
+
+		// When
+		tc.rune_ids_are_extracted()
+
+		// Then
+		tc.extracted_ids_are([]string{"bf-a1b2", "bf-c3d4.1.2"})
+	})
+
+	t.Run("extracts IDs embedded in branch names", func(t *testing.T) {
+		tc := newRuneRefsTestContext(t)
+
+		// Given
+		tc.text_is("feature/bf-a1b2-add-login")
+
+		// When
+		tc.rune_ids_are_extracted()
+
+		// Then
+		tc.extracted_ids_are([]string{"bf-a1b2"})
+	})
+
+	t.Run("deduplicates repeated references", func(t *testing.T) {
+		tc := newRuneRefsTestContext(t)
+
+		// Given
+		tc.text_is("bf-a1b2: fix; see also bf-a1b2")
+
+		// When
+		tc.rune_ids_are_extracted()
+
+		// Then
+		tc.extracted_ids_are([]string{"bf-a1b2"})
+	})
+
+	t.Run("ignores IDs with invalid hex length", func(t *testing.T) {
+		tc := newRuneRefsTestContext(t)
+
+		// Given
+		tc.text_is("bf-a1b2c3 and bf-zz99")
+
+		// When
+		tc.rune_ids_are_extracted()
+
+		// Then
+		tc.extracted_ids_are(nil)
+	})
+}
+
+// --- Test Context ---
+
+type runeRefsTestContext struct {
+	t *testing.T
+
+	text string
+	ids  []string
+}
+
+func newRuneRefsTestContext(t *testing.T) *runeRefsTestContext {
+	t.Helper()
+	return &runeRefsTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *runeRefsTestContext) text_is(text string) {
+	tc.t.Helper()
+	tc.text = text
+}
+
+// --- When ---
+
+func (tc *runeRefsTestContext) rune_ids_are_extracted() {
+	tc.t.Helper()
+	tc.ids = ExtractRuneIDs(tc.text)
+}
+
+// --- Then ---
+
+func (tc *runeRefsTestContext) extracted_ids_are(expected []string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.ids)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// RuneSnapshotInterval is how many events past its last snapshot a rune's
+// stream has to grow before a command reading it writes a new one.
+const RuneSnapshotInterval = 50
+
+// runeSnapshotSchema changes whenever RuneState or the way events fold into
+// it changes, so snapshots written by an older build are ignored.
+const runeSnapshotSchema = 1
+
+type runeSnapshot struct {
+	Schema int       `json:"schema"`
+	State  RuneState `json:"state"`
+}
+
+// readAndRebuild returns a rune's state and its stream's version. When the
+// event store keeps snapshots, only the events after the rune's latest
+// snapshot are read.
+func readAndRebuild(ctx context.Context, realmID string, runeID string, store core.EventStore) (RuneState, int, error) {
+	streamID := runeStreamID(runeID)
+	snapshots, _ := store.(core.SnapshotStore)
+
+	var state RuneState
+	var version int
+	if snapshots != nil {
+		var err error
+		if state, version, err = loadRuneSnapshot(ctx, realmID, streamID, snapshots); err != nil {
+			return RuneState{}, 0, err
+		}
+	}
+	events, err := store.ReadStream(ctx, realmID, streamID, version+1)
+	if err != nil {
+		return RuneState{}, 0, err
+	}
+	state.apply(events)
+	// A truncated stream's versions start past 1, so they are not counted.
+	if len(events) > 0 {
+		version = events[len(events)-1].Version
+	}
+
+	if snapshots != nil && len(events) >= RuneSnapshotInterval {
+		if err := saveRuneSnapshot(ctx, realmID, streamID, version, state, snapshots); err != nil {
+			return RuneState{}, 0, err
+		}
+	}
+	return state, version, nil
+}
+
+// snapshotRune saves a snapshot of a rune's state at its stream's current
+// version, so the state survives the events before it being truncated.
+func snapshotRune(ctx context.Context, realmID, runeID string, store core.EventStore, snapshots core.SnapshotStore) error {
+	state, version, err := readAndRebuild(ctx, realmID, runeID, store)
+	if err != nil {
+		return err
+	}
+	return saveRuneSnapshot(ctx, realmID, runeStreamID(runeID), version, state, snapshots)
+}
+
+// loadRuneSnapshot returns the state and version of a rune's latest usable
+// snapshot, or an empty state at version 0 when there is none.
+func loadRuneSnapshot(ctx context.Context, realmID, streamID string, snapshots core.SnapshotStore) (RuneState, int, error) {
+	snapshot, err := snapshots.GetSnapshot(ctx, realmID, streamID)
+	if isNotFoundError(err) {
+		return RuneState{}, 0, nil
+	}
+	if err != nil {
+		return RuneState{}, 0, err
+	}
+	var saved runeSnapshot
+	if err := json.Unmarshal(snapshot.State, &saved); err != nil || saved.Schema != runeSnapshotSchema {
+		return RuneState{}, 0, nil
+	}
+	return saved.State, snapshot.Version, nil
+}
+
+func saveRuneSnapshot(ctx context.Context, realmID, streamID string, version int, state RuneState, snapshots core.SnapshotStore) error {
+	data, err := json.Marshal(runeSnapshot{Schema: runeSnapshotSchema, State: state})
+	if err != nil {
+		return err
+	}
+	return snapshots.SaveSnapshot(ctx, core.Snapshot{
+		RealmID:  realmID,
+		StreamID: streamID,
+		Version:  version,
+		State:    data,
+	})
+}
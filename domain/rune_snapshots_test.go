@@ -0,0 +1,258 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestReadAndRebuild_Snapshots(t *testing.T) {
+	t.Run("writes a snapshot once the stream grows by the interval", func(t *testing.T) {
+		tc := newSnapshotTestContext(t)
+
+		// Given
+		tc.rune_with_notes("bf-a1", RuneSnapshotInterval-1)
+
+		// When
+		tc.note_is_added("bf-a1")
+
+		// Then
+		tc.no_error()
+		tc.snapshot_is_at_version("bf-a1", RuneSnapshotInterval)
+		tc.stream_has_events("bf-a1", RuneSnapshotInterval+1)
+	})
+
+	t.Run("does not write a snapshot before the interval", func(t *testing.T) {
+		tc := newSnapshotTestContext(t)
+
+		// Given
+		tc.rune_with_notes("bf-a1", 3)
+
+		// When
+		tc.note_is_added("bf-a1")
+
+		// Then
+		tc.no_error()
+		tc.no_snapshot_exists("bf-a1")
+	})
+
+	t.Run("rebuilds from the snapshot and the events after it", func(t *testing.T) {
+		tc := newSnapshotTestContext(t)
+
+		// Given
+		tc.rune_with_notes("bf-a1", 2)
+		tc.snapshot_saved("bf-a1", 3, runeSnapshotSchema, RuneState{ID: "bf-a1", Title: "From snapshot", Status: "draft", Exists: true})
+		tc.priority_updated("bf-a1", 4)
+
+		// When
+		tc.rune_is_rebuilt("bf-a1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "From snapshot", tc.state.Title)
+		assert.Equal(t, 4, tc.state.Priority)
+		assert.Equal(t, 4, tc.version)
+		assert.Equal(t, []int{4}, tc.store.readFrom)
+	})
+
+	t.Run("ignores a snapshot written for another schema", func(t *testing.T) {
+		tc := newSnapshotTestContext(t)
+
+		// Given
+		tc.rune_with_notes("bf-a1", 2)
+		tc.snapshot_saved("bf-a1", 3, runeSnapshotSchema+1, RuneState{ID: "bf-a1", Title: "Stale"})
+
+		// When
+		tc.rune_is_rebuilt("bf-a1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "Rune bf-a1", tc.state.Title)
+		assert.Equal(t, 3, tc.version)
+	})
+
+	t.Run("takes the version of a truncated stream from its last event", func(t *testing.T) {
+		tc := newSnapshotTestContext(t)
+
+		// Given
+		tc.rune_with_notes("bf-a1", 2)
+		tc.priority_updated("bf-a1", 4)
+		tc.stream_truncated_to_last_event("bf-a1")
+
+		// When
+		tc.rune_is_rebuilt("bf-a1")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 4, tc.version)
+	})
+}
+
+// --- Test Context ---
+
+type snapshotTestContext struct {
+	t     *testing.T
+	ctx   context.Context
+	store *snapshotEventStore
+
+	state   RuneState
+	version int
+	err     error
+}
+
+func newSnapshotTestContext(t *testing.T) *snapshotTestContext {
+	t.Helper()
+	return &snapshotTestContext{
+		t:   t,
+		ctx: context.Background(),
+		store: &snapshotEventStore{
+			streams:   make(map[string][]core.Event),
+			snapshots: make(map[string]core.Snapshot),
+		},
+	}
+}
+
+// --- Given ---
+
+func (tc *snapshotTestContext) rune_with_notes(runeID string, notes int) {
+	tc.t.Helper()
+	events := []core.EventData{{EventType: EventRuneCreated, Data: RuneCreated{ID: runeID, Title: "Rune " + runeID}}}
+	for range notes {
+		events = append(events, core.EventData{EventType: EventRuneNoted, Data: RuneNoted{RuneID: runeID, Text: "note"}})
+	}
+	_, err := tc.store.Append(tc.ctx, "realm-1", runeStreamID(runeID), 0, events)
+	require.NoError(tc.t, err)
+}
+
+func (tc *snapshotTestContext) priority_updated(runeID string, priority int) {
+	tc.t.Helper()
+	streamID := runeStreamID(runeID)
+	_, err := tc.store.Append(tc.ctx, "realm-1", streamID, len(tc.store.streams[streamID]), []core.EventData{
+		{EventType: EventRuneUpdated, Data: RuneUpdated{ID: runeID, Priority: &priority}},
+	})
+	require.NoError(tc.t, err)
+}
+
+func (tc *snapshotTestContext) snapshot_saved(runeID string, version, schema int, state RuneState) {
+	tc.t.Helper()
+	data, err := json.Marshal(runeSnapshot{Schema: schema, State: state})
+	require.NoError(tc.t, err)
+	require.NoError(tc.t, tc.store.SaveSnapshot(tc.ctx, core.Snapshot{
+		RealmID: "realm-1", StreamID: runeStreamID(runeID), Version: version, State: data,
+	}))
+}
+
+func (tc *snapshotTestContext) stream_truncated_to_last_event(runeID string) {
+	tc.t.Helper()
+	streamID := runeStreamID(runeID)
+	events := tc.store.streams[streamID]
+	tc.store.streams[streamID] = events[len(events)-1:]
+}
+
+// --- When ---
+
+func (tc *snapshotTestContext) note_is_added(runeID string) {
+	tc.t.Helper()
+	tc.err = HandleAddNote(tc.ctx, "realm-1", AddNote{RuneID: runeID, Text: "another note"}, tc.store)
+}
+
+func (tc *snapshotTestContext) rune_is_rebuilt(runeID string) {
+	tc.t.Helper()
+	tc.state, tc.version, tc.err = readAndRebuild(tc.ctx, "realm-1", runeID, tc.store)
+}
+
+// --- Then ---
+
+func (tc *snapshotTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *snapshotTestContext) snapshot_is_at_version(runeID string, version int) {
+	tc.t.Helper()
+	snapshot, err := tc.store.GetSnapshot(tc.ctx, "realm-1", runeStreamID(runeID))
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, version, snapshot.Version)
+
+	var saved runeSnapshot
+	require.NoError(tc.t, json.Unmarshal(snapshot.State, &saved))
+	assert.Equal(tc.t, RebuildRuneState(tc.store.streams[runeStreamID(runeID)][:version]), saved.State)
+}
+
+func (tc *snapshotTestContext) no_snapshot_exists(runeID string) {
+	tc.t.Helper()
+	_, err := tc.store.GetSnapshot(tc.ctx, "realm-1", runeStreamID(runeID))
+	assert.True(tc.t, isNotFoundError(err))
+}
+
+func (tc *snapshotTestContext) stream_has_events(runeID string, expected int) {
+	tc.t.Helper()
+	assert.Len(tc.t, tc.store.streams[runeStreamID(runeID)], expected)
+}
+
+// --- Mock Snapshot Event Store ---
+
+// snapshotEventStore is a single-realm event store that keeps snapshots and
+// records the versions streams are read from.
+type snapshotEventStore struct {
+	streams   map[string][]core.Event
+	snapshots map[string]core.Snapshot
+	readFrom  []int
+}
+
+func (s *snapshotEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	if actual := len(s.streams[streamID]); actual != expectedVersion {
+		return nil, &core.ConcurrencyError{StreamID: streamID, ExpectedVersion: expectedVersion, ActualVersion: actual}
+	}
+	var result []core.Event
+	for i, ed := range events {
+		data, _ := json.Marshal(ed.Data)
+		result = append(result, core.Event{
+			RealmID:   realmID,
+			StreamID:  streamID,
+			Version:   expectedVersion + i + 1,
+			EventType: ed.EventType,
+			Data:      data,
+		})
+	}
+	s.streams[streamID] = append(s.streams[streamID], result...)
+	return result, nil
+}
+
+func (s *snapshotEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	s.readFrom = append(s.readFrom, fromVersion)
+	events := []core.Event{}
+	for _, evt := range s.streams[streamID] {
+		if evt.Version >= fromVersion {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (s *snapshotEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (s *snapshotEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	return []string{}, nil
+}
+
+func (s *snapshotEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	return snapshot, nil
+}
+
+func (s *snapshotEventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
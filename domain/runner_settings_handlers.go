@@ -76,7 +76,7 @@ func requireExistingRunnerSettings(state RunnerSettingsState, runnerSettingsID s
 		return &core.NotFoundError{Entity: "runner_settings", ID: runnerSettingsID}
 	}
 	if state.Deleted {
-		return fmt.Errorf("runner settings %q is deleted", runnerSettingsID)
+		return Rejectf(ErrDeleted, "runner settings %q is deleted", runnerSettingsID)
 	}
 	return nil
 }
@@ -134,7 +134,7 @@ func HandleDeleteRunnerSettingsField(ctx context.Context, cmd DeleteRunnerSettin
 	}
 
 	if _, exists := state.Fields[cmd.Key]; !exists {
-		return fmt.Errorf("field %q not found in runner settings %q", cmd.Key, cmd.RunnerSettingsID)
+		return Rejectf(ErrInvalidCommand, "field %q not found in runner settings %q", cmd.Key, cmd.RunnerSettingsID)
 	}
 
 	fieldDeleted := RunnerSettingsFieldDeleted(cmd)
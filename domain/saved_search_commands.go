@@ -0,0 +1,28 @@
+package domain
+
+type CreateSavedSearch struct {
+	RealmID   string `json:"realm_id"`
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	// Filter is a rune list query string, such as
+	// "status=open&blocked=false" (see SavedSearchFilterKeys).
+	Filter string `json:"filter"`
+}
+
+type UpdateSavedSearch struct {
+	RealmID   string `json:"realm_id"`
+	AccountID string `json:"account_id"`
+	SearchID  string `json:"search_id"`
+	Name      string `json:"name"`
+	Filter    string `json:"filter"`
+}
+
+type DeleteSavedSearch struct {
+	RealmID   string `json:"realm_id"`
+	AccountID string `json:"account_id"`
+	SearchID  string `json:"search_id"`
+}
+
+type CreateSavedSearchResult struct {
+	SearchID string `json:"search_id"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+const (
+	EventSavedSearchCreated = "SavedSearchCreated"
+	EventSavedSearchUpdated = "SavedSearchUpdated"
+	EventSavedSearchDeleted = "SavedSearchDeleted"
+)
+
+type SavedSearchCreated struct {
+	SearchID  string    `json:"search_id"`
+	RealmID   string    `json:"realm_id"`
+	AccountID string    `json:"account_id"`
+	Name      string    `json:"name"`
+	Filter    string    `json:"filter"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SavedSearchUpdated struct {
+	SearchID  string `json:"search_id"`
+	RealmID   string `json:"realm_id"`
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Filter    string `json:"filter"`
+}
+
+type SavedSearchDeleted struct {
+	SearchID  string `json:"search_id"`
+	RealmID   string `json:"realm_id"`
+	AccountID string `json:"account_id"`
+}
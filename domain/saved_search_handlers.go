@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const savedSearchStreamPrefix = "saved-search-"
+
+// SavedSearchFilterKeys lists the rune list query parameters a saved search
+// may filter on.
+var SavedSearchFilterKeys = []string{"status", "priority", "assignee", "claimant", "branch", "saga", "blocked"}
+
+type SavedSearchState struct {
+	SearchID  string
+	RealmID   string
+	AccountID string
+	Exists    bool
+	Deleted   bool
+}
+
+func RebuildSavedSearchState(events []core.Event) SavedSearchState {
+	var state SavedSearchState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventSavedSearchCreated:
+			var data SavedSearchCreated
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.SearchID = data.SearchID
+			state.RealmID = data.RealmID
+			state.AccountID = data.AccountID
+		case EventSavedSearchDeleted:
+			state.Deleted = true
+		}
+	}
+	return state
+}
+
+func savedSearchStreamID(searchID string) string {
+	return savedSearchStreamPrefix + searchID
+}
+
+func generateSavedSearchID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate saved search ID: %w", err)
+	}
+	return "ss-" + hex.EncodeToString(b), nil
+}
+
+// readOwnedSavedSearch loads a saved search, reporting it as not found unless
+// it is live and belongs to the account in the realm. Saved searches are
+// private, so other accounts' searches are indistinguishable from missing ones.
+func readOwnedSavedSearch(ctx context.Context, realmID, accountID, searchID string, store core.EventStore) ([]core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, savedSearchStreamID(searchID), 0)
+	if err != nil {
+		return nil, err
+	}
+	state := RebuildSavedSearchState(events)
+	if !state.Exists || state.Deleted || state.RealmID != realmID || state.AccountID != accountID {
+		return nil, &core.NotFoundError{Entity: "saved search", ID: searchID}
+	}
+	return events, nil
+}
+
+// normalizeSavedSearch trims the name and checks the filter parses as a query
+// string over SavedSearchFilterKeys, returning the filter with its keys
+// sorted so equal searches are stored alike.
+func normalizeSavedSearch(name, filter string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", Rejectf(ErrInvalidCommand, "saved search name is required")
+	}
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(filter), "?"))
+	if err != nil {
+		return "", "", Rejectf(ErrInvalidCommand, "invalid saved search filter: %v", err)
+	}
+	for key := range values {
+		if !slices.Contains(SavedSearchFilterKeys, key) {
+			return "", "", Rejectf(ErrInvalidCommand, "saved search filter %q must be one of %s", key, strings.Join(SavedSearchFilterKeys, ", "))
+		}
+	}
+	return name, values.Encode(), nil
+}
+
+func HandleCreateSavedSearch(ctx context.Context, cmd CreateSavedSearch, store core.EventStore) (CreateSavedSearchResult, error) {
+	name, filter, err := normalizeSavedSearch(cmd.Name, cmd.Filter)
+	if err != nil {
+		return CreateSavedSearchResult{}, err
+	}
+	if cmd.AccountID == "" {
+		return CreateSavedSearchResult{}, Rejectf(ErrInvalidCommand, "saved searches belong to an account")
+	}
+	if err := RequireActiveRealm(ctx, cmd.RealmID, store); err != nil {
+		return CreateSavedSearchResult{}, err
+	}
+
+	searchID, err := generateSavedSearchID()
+	if err != nil {
+		return CreateSavedSearchResult{}, err
+	}
+
+	created := SavedSearchCreated{
+		SearchID:  searchID,
+		RealmID:   cmd.RealmID,
+		AccountID: cmd.AccountID,
+		Name:      name,
+		Filter:    filter,
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, savedSearchStreamID(searchID), 0, []core.EventData{
+		{EventType: EventSavedSearchCreated, Data: created},
+	})
+	if err != nil {
+		return CreateSavedSearchResult{}, err
+	}
+	return CreateSavedSearchResult{SearchID: searchID}, nil
+}
+
+func HandleUpdateSavedSearch(ctx context.Context, cmd UpdateSavedSearch, store core.EventStore) error {
+	name, filter, err := normalizeSavedSearch(cmd.Name, cmd.Filter)
+	if err != nil {
+		return err
+	}
+	events, err := readOwnedSavedSearch(ctx, cmd.RealmID, cmd.AccountID, cmd.SearchID, store)
+	if err != nil {
+		return err
+	}
+
+	updated := SavedSearchUpdated{
+		SearchID:  cmd.SearchID,
+		RealmID:   cmd.RealmID,
+		AccountID: cmd.AccountID,
+		Name:      name,
+		Filter:    filter,
+	}
+	_, err = store.Append(ctx, AdminRealmID, savedSearchStreamID(cmd.SearchID), len(events), []core.EventData{
+		{EventType: EventSavedSearchUpdated, Data: updated},
+	})
+	return err
+}
+
+func HandleDeleteSavedSearch(ctx context.Context, cmd DeleteSavedSearch, store core.EventStore) error {
+	events, err := readOwnedSavedSearch(ctx, cmd.RealmID, cmd.AccountID, cmd.SearchID, store)
+	if err != nil {
+		return err
+	}
+
+	deleted := SavedSearchDeleted{SearchID: cmd.SearchID, RealmID: cmd.RealmID, AccountID: cmd.AccountID}
+	_, err = store.Append(ctx, AdminRealmID, savedSearchStreamID(cmd.SearchID), len(events), []core.EventData{
+		{EventType: EventSavedSearchDeleted, Data: deleted},
+	})
+	return err
+}
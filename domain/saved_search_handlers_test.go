@@ -0,0 +1,235 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestHandleCreateSavedSearch(t *testing.T) {
+	t.Run("creates a search owned by the account", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+
+		// When
+		tc.create_is_handled(CreateSavedSearch{
+			RealmID: "bf-r1", AccountID: "acct-1", Name: " My open work ", Filter: "?status=open&claimant=odin",
+		})
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^ss-[0-9a-f]{8}$`, tc.result.SearchID)
+		created := tc.only_appended_event(EventSavedSearchCreated, 0).(SavedSearchCreated)
+		assert.Equal(t, "acct-1", created.AccountID)
+		assert.Equal(t, "bf-r1", created.RealmID)
+		assert.Equal(t, "My open work", created.Name)
+		assert.Equal(t, "claimant=odin&status=open", created.Filter)
+	})
+
+	t.Run("rejects invalid searches", func(t *testing.T) {
+		cases := map[string]struct {
+			cmd      CreateSavedSearch
+			expected string
+		}{
+			"missing name":   {CreateSavedSearch{AccountID: "acct-1", Filter: "status=open"}, "name is required"},
+			"unknown filter": {CreateSavedSearch{AccountID: "acct-1", Name: "s", Filter: "color=red"}, `"color" must be one of`},
+			"bad filter":     {CreateSavedSearch{AccountID: "acct-1", Name: "s", Filter: "status=%zz"}, "invalid saved search filter"},
+			"no account":     {CreateSavedSearch{Name: "s"}, "belong to an account"},
+		}
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				tc := newSavedSearchTestContext(t)
+
+				// When
+				tc.create_is_handled(c.cmd)
+
+				// Then
+				tc.error_contains(c.expected)
+				assert.Empty(t, tc.eventStore.appendedCalls)
+			})
+		}
+	})
+
+	t.Run("returns not found for an unknown realm", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// When
+		tc.create_is_handled(CreateSavedSearch{RealmID: "bf-missing", AccountID: "acct-1", Name: "s"})
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.True(t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	})
+}
+
+func TestHandleUpdateSavedSearch(t *testing.T) {
+	t.Run("updates the account's search", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_search("ss-a1b2c3d4", "bf-r1", "acct-1")
+
+		// When
+		tc.err = HandleUpdateSavedSearch(tc.ctx, UpdateSavedSearch{
+			RealmID: "bf-r1", AccountID: "acct-1", SearchID: "ss-a1b2c3d4", Name: "Blocked", Filter: "blocked=true",
+		}, tc.eventStore)
+
+		// Then
+		tc.no_error()
+		updated := tc.only_appended_event(EventSavedSearchUpdated, 1).(SavedSearchUpdated)
+		assert.Equal(t, "Blocked", updated.Name)
+		assert.Equal(t, "blocked=true", updated.Filter)
+	})
+
+	t.Run("returns not found for another account's search", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_search("ss-a1b2c3d4", "bf-r1", "acct-1")
+
+		// When
+		tc.err = HandleUpdateSavedSearch(tc.ctx, UpdateSavedSearch{
+			RealmID: "bf-r1", AccountID: "acct-2", SearchID: "ss-a1b2c3d4", Name: "Mine now",
+		}, tc.eventStore)
+
+		// Then
+		tc.error_is_not_found("ss-a1b2c3d4")
+	})
+}
+
+func TestHandleDeleteSavedSearch(t *testing.T) {
+	t.Run("deletes the account's search", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_search("ss-a1b2c3d4", "bf-r1", "acct-1")
+
+		// When
+		tc.delete_is_handled("bf-r1", "acct-1", "ss-a1b2c3d4")
+
+		// Then
+		tc.no_error()
+		deleted := tc.only_appended_event(EventSavedSearchDeleted, 1).(SavedSearchDeleted)
+		assert.Equal(t, SavedSearchDeleted{SearchID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1"}, deleted)
+	})
+
+	t.Run("returns not found for a search in another realm", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_search("ss-a1b2c3d4", "bf-r1", "acct-1")
+
+		// When
+		tc.delete_is_handled("bf-r2", "acct-1", "ss-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("ss-a1b2c3d4")
+	})
+
+	t.Run("returns not found for a deleted search", func(t *testing.T) {
+		tc := newSavedSearchTestContext(t)
+
+		// Given
+		tc.existing_search("ss-a1b2c3d4", "bf-r1", "acct-1")
+		tc.eventStore.streams["saved-search-ss-a1b2c3d4"] = append(tc.eventStore.streams["saved-search-ss-a1b2c3d4"],
+			makeEvent(EventSavedSearchDeleted, SavedSearchDeleted{SearchID: "ss-a1b2c3d4", RealmID: "bf-r1", AccountID: "acct-1"}))
+
+		// When
+		tc.delete_is_handled("bf-r1", "acct-1", "ss-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("ss-a1b2c3d4")
+	})
+}
+
+// --- Test Context ---
+
+type savedSearchTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	result CreateSavedSearchResult
+	err    error
+}
+
+func newSavedSearchTestContext(t *testing.T) *savedSearchTestContext {
+	t.Helper()
+	return &savedSearchTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *savedSearchTestContext) existing_realm(realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: realmID, Name: "Realm"}),
+	}
+}
+
+func (tc *savedSearchTestContext) existing_search(searchID, realmID, accountID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["saved-search-"+searchID] = []core.Event{
+		makeEvent(EventSavedSearchCreated, SavedSearchCreated{
+			SearchID: searchID, RealmID: realmID, AccountID: accountID, Name: "Open", Filter: "status=open",
+		}),
+	}
+}
+
+// --- When ---
+
+func (tc *savedSearchTestContext) create_is_handled(cmd CreateSavedSearch) {
+	tc.t.Helper()
+	tc.result, tc.err = HandleCreateSavedSearch(tc.ctx, cmd, tc.eventStore)
+}
+
+func (tc *savedSearchTestContext) delete_is_handled(realmID, accountID, searchID string) {
+	tc.t.Helper()
+	tc.err = HandleDeleteSavedSearch(tc.ctx, DeleteSavedSearch{RealmID: realmID, AccountID: accountID, SearchID: searchID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *savedSearchTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *savedSearchTestContext) error_contains(substring string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substring)
+}
+
+func (tc *savedSearchTestContext) error_is_not_found(searchID string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, "saved search", nfe.Entity)
+	assert.Equal(tc.t, searchID, nfe.ID)
+	assert.Empty(tc.t, tc.eventStore.appendedCalls)
+}
+
+func (tc *savedSearchTestContext) only_appended_event(eventType string, expectedVersion int) any {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, AdminRealmID, call.realmID)
+	assert.Equal(tc.t, expectedVersion, call.expectedVersion)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, eventType, call.events[0].EventType)
+	return call.events[0].Data
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type CreateSchedule struct {
+	RealmID string `json:"realm_id"`
+	Name    string `json:"name"`
+	// Cron is a five-field cron expression or alias such as "@daily",
+	// evaluated in UTC.
+	Cron string `json:"cron"`
+	// Command is one of ScheduleCommands; Args are its string arguments.
+	Command string            `json:"command"`
+	Args    map[string]string `json:"args,omitempty"`
+}
+
+type DeleteSchedule struct {
+	RealmID    string `json:"realm_id"`
+	ScheduleID string `json:"schedule_id"`
+}
+
+type CreateScheduleResult struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+// StartScheduleRun claims the schedule's latest due run. It is issued by the
+// scheduler, not by users.
+type StartScheduleRun struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+// StartScheduleRunResult reports the run that was claimed. Started is false
+// when nothing was due.
+type StartScheduleRunResult struct {
+	Started bool      `json:"started"`
+	DueAt   time.Time `json:"due_at,omitzero"`
+}
+
+// FinishScheduleRun records the outcome of a run claimed by
+// StartScheduleRun.
+type FinishScheduleRun struct {
+	ScheduleID string    `json:"schedule_id"`
+	DueAt      time.Time `json:"due_at"`
+	Result     string    `json:"result,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	EventScheduleCreated     = "ScheduleCreated"
+	EventScheduleDeleted     = "ScheduleDeleted"
+	EventScheduleRunStarted  = "ScheduleRunStarted"
+	EventScheduleRunFinished = "ScheduleRunFinished"
+)
+
+type ScheduleCreated struct {
+	ScheduleID string            `json:"schedule_id"`
+	RealmID    string            `json:"realm_id"`
+	Name       string            `json:"name"`
+	Cron       string            `json:"cron"`
+	Command    string            `json:"command"`
+	Args       map[string]string `json:"args,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type ScheduleDeleted struct {
+	ScheduleID string `json:"schedule_id"`
+	RealmID    string `json:"realm_id"`
+}
+
+type ScheduleRunStarted struct {
+	ScheduleID string    `json:"schedule_id"`
+	RealmID    string    `json:"realm_id"`
+	DueAt      time.Time `json:"due_at"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+type ScheduleRunFinished struct {
+	ScheduleID string    `json:"schedule_id"`
+	RealmID    string    `json:"realm_id"`
+	DueAt      time.Time `json:"due_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Result     string    `json:"result,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const scheduleStreamPrefix = "schedule-"
+
+// Commands a schedule may run.
+const (
+	ScheduleCommandSweepRunes      = "sweep_runes"
+	ScheduleCommandSealStaleDrafts = "seal_stale_drafts"
+	ScheduleCommandCreateRune      = "create_rune"
+)
+
+// ScheduleCommands lists the commands a schedule may run and the arguments
+// each accepts.
+var ScheduleCommands = map[string][]string{
+	ScheduleCommandSweepRunes:      {},
+	ScheduleCommandSealStaleDrafts: {"days"},
+	ScheduleCommandCreateRune:      {"title", "description", "priority", "branch", "parent_id", "type"},
+}
+
+type ScheduleState struct {
+	ScheduleID string
+	RealmID    string
+	Name       string
+	Cron       string
+	Command    string
+	Args       map[string]string
+	CreatedAt  time.Time
+	LastDueAt  time.Time
+	Exists     bool
+	Deleted    bool
+}
+
+func RebuildScheduleState(events []core.Event) ScheduleState {
+	var state ScheduleState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventScheduleCreated:
+			var data ScheduleCreated
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.ScheduleID = data.ScheduleID
+			state.RealmID = data.RealmID
+			state.Name = data.Name
+			state.Cron = data.Cron
+			state.Command = data.Command
+			state.Args = data.Args
+			state.CreatedAt = data.CreatedAt
+		case EventScheduleDeleted:
+			state.Deleted = true
+		case EventScheduleRunStarted:
+			var data ScheduleRunStarted
+			_ = json.Unmarshal(evt.Data, &data)
+			state.LastDueAt = data.DueAt
+		}
+	}
+	return state
+}
+
+func scheduleStreamID(scheduleID string) string {
+	return scheduleStreamPrefix + scheduleID
+}
+
+func generateScheduleID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate schedule ID: %w", err)
+	}
+	return "sc-" + hex.EncodeToString(b), nil
+}
+
+func readAndRebuildScheduleState(ctx context.Context, scheduleID string, store core.EventStore) (ScheduleState, []core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, scheduleStreamID(scheduleID), 0)
+	if err != nil {
+		return ScheduleState{}, nil, err
+	}
+	return RebuildScheduleState(events), events, nil
+}
+
+// StaleDraftAge returns the age past which seal_stale_drafts seals a draft.
+func StaleDraftAge(args map[string]string) (time.Duration, error) {
+	days, err := strconv.Atoi(args["days"])
+	if err != nil || days < 1 {
+		return 0, Rejectf(ErrInvalidCommand, "days must be a positive integer")
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// ScheduledCreateRune builds the CreateRune command a create_rune schedule
+// issues.
+func ScheduledCreateRune(args map[string]string) (CreateRune, error) {
+	cmd := CreateRune{
+		Title:       args["title"],
+		Description: args["description"],
+		ParentID:    args["parent_id"],
+		Type:        args["type"],
+	}
+	if value, ok := args["priority"]; ok {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return CreateRune{}, Rejectf(ErrInvalidCommand, "priority must be an integer")
+		}
+		cmd.Priority = priority
+	}
+	if branch, ok := args["branch"]; ok {
+		cmd.Branch = &branch
+	}
+	if cmd.ParentID == "" && cmd.Branch == nil {
+		return CreateRune{}, Rejectf(ErrBranchRequired, "branch is required for top-level runes")
+	}
+	if err := validateCreateRune(cmd); err != nil {
+		return CreateRune{}, err
+	}
+	return cmd, nil
+}
+
+func validateSchedule(cmd CreateSchedule) error {
+	if strings.TrimSpace(cmd.Name) == "" {
+		return Rejectf(ErrInvalidCommand, "cannot create schedule: name is required")
+	}
+	if _, err := ParseCron(cmd.Cron); err != nil {
+		return Rejectf(ErrInvalidCommand, "cannot create schedule: %v", err)
+	}
+	allowed, ok := ScheduleCommands[cmd.Command]
+	if !ok {
+		commands := make([]string, 0, len(ScheduleCommands))
+		for name := range ScheduleCommands {
+			commands = append(commands, name)
+		}
+		sort.Strings(commands)
+		return Rejectf(ErrInvalidCommand, "cannot create schedule: command must be one of %s", strings.Join(commands, ", "))
+	}
+	for key := range cmd.Args {
+		if !slices.Contains(allowed, key) {
+			return Rejectf(ErrInvalidCommand, "cannot create schedule: %s does not take %q", cmd.Command, key)
+		}
+	}
+	var err error
+	switch cmd.Command {
+	case ScheduleCommandSealStaleDrafts:
+		_, err = StaleDraftAge(cmd.Args)
+	case ScheduleCommandCreateRune:
+		_, err = ScheduledCreateRune(cmd.Args)
+	}
+	if err != nil {
+		return Rejectf(ErrInvalidCommand, "cannot create schedule: %v", err)
+	}
+	return nil
+}
+
+func HandleCreateSchedule(ctx context.Context, cmd CreateSchedule, store core.EventStore) (CreateScheduleResult, error) {
+	if err := validateSchedule(cmd); err != nil {
+		return CreateScheduleResult{}, err
+	}
+	if err := RequireActiveRealm(ctx, cmd.RealmID, store); err != nil {
+		return CreateScheduleResult{}, err
+	}
+
+	scheduleID, err := generateScheduleID()
+	if err != nil {
+		return CreateScheduleResult{}, err
+	}
+
+	created := ScheduleCreated{
+		ScheduleID: scheduleID,
+		RealmID:    cmd.RealmID,
+		Name:       strings.TrimSpace(cmd.Name),
+		Cron:       strings.TrimSpace(cmd.Cron),
+		Command:    cmd.Command,
+		Args:       cmd.Args,
+		CreatedAt:  core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, scheduleStreamID(scheduleID), 0, []core.EventData{
+		{EventType: EventScheduleCreated, Data: created},
+	})
+	if err != nil {
+		return CreateScheduleResult{}, err
+	}
+
+	return CreateScheduleResult{ScheduleID: scheduleID}, nil
+}
+
+// HandleDeleteSchedule deletes a schedule from its realm. A schedule
+// belonging to another realm is reported as not found.
+func HandleDeleteSchedule(ctx context.Context, cmd DeleteSchedule, store core.EventStore) error {
+	state, events, err := readAndRebuildScheduleState(ctx, cmd.ScheduleID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists || state.Deleted || state.RealmID != cmd.RealmID {
+		return &core.NotFoundError{Entity: "schedule", ID: cmd.ScheduleID}
+	}
+
+	deleted := ScheduleDeleted{ScheduleID: cmd.ScheduleID, RealmID: cmd.RealmID}
+	_, err = store.Append(ctx, AdminRealmID, scheduleStreamID(cmd.ScheduleID), len(events), []core.EventData{
+		{EventType: EventScheduleDeleted, Data: deleted},
+	})
+	return err
+}
+
+// HandleStartScheduleRun claims the latest run that fell due since the
+// previous one. The append expects the stream's current version, so when
+// several servers race for a run only one of them starts it.
+func HandleStartScheduleRun(ctx context.Context, realmID string, cmd StartScheduleRun, store core.EventStore) (StartScheduleRunResult, error) {
+	state, events, err := readAndRebuildScheduleState(ctx, cmd.ScheduleID, store)
+	if err != nil {
+		return StartScheduleRunResult{}, err
+	}
+	if !state.Exists || state.Deleted || state.RealmID != realmID {
+		return StartScheduleRunResult{}, &core.NotFoundError{Entity: "schedule", ID: cmd.ScheduleID}
+	}
+	schedule, err := ParseCron(state.Cron)
+	if err != nil {
+		return StartScheduleRunResult{}, err
+	}
+
+	from := state.LastDueAt
+	if from.IsZero() {
+		from = state.CreatedAt
+	}
+	now := core.ClockFromContext(ctx).Now().UTC()
+	dueAt := schedule.Latest(from, now)
+	if dueAt.IsZero() {
+		return StartScheduleRunResult{}, nil
+	}
+
+	started := ScheduleRunStarted{ScheduleID: cmd.ScheduleID, RealmID: realmID, DueAt: dueAt, StartedAt: now}
+	_, err = store.Append(ctx, AdminRealmID, scheduleStreamID(cmd.ScheduleID), len(events), []core.EventData{
+		{EventType: EventScheduleRunStarted, Data: started},
+	})
+	if err != nil {
+		return StartScheduleRunResult{}, err
+	}
+	return StartScheduleRunResult{Started: true, DueAt: dueAt}, nil
+}
+
+// HandleFinishScheduleRun records how a started run went. It is accepted
+// even if the schedule was deleted while the run was in progress.
+func HandleFinishScheduleRun(ctx context.Context, realmID string, cmd FinishScheduleRun, store core.EventStore) error {
+	state, events, err := readAndRebuildScheduleState(ctx, cmd.ScheduleID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists || state.RealmID != realmID {
+		return &core.NotFoundError{Entity: "schedule", ID: cmd.ScheduleID}
+	}
+
+	finished := ScheduleRunFinished{
+		ScheduleID: cmd.ScheduleID,
+		RealmID:    realmID,
+		DueAt:      cmd.DueAt,
+		FinishedAt: core.ClockFromContext(ctx).Now().UTC(),
+		Result:     cmd.Result,
+		Error:      cmd.Error,
+	}
+	_, err = store.Append(ctx, AdminRealmID, scheduleStreamID(cmd.ScheduleID), len(events), []core.EventData{
+		{EventType: EventScheduleRunFinished, Data: finished},
+	})
+	return err
+}
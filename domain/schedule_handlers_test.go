@@ -0,0 +1,286 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestHandleCreateSchedule(t *testing.T) {
+	t.Run("creates schedule in realm", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+
+		// When
+		tc.create_schedule_is_handled(CreateSchedule{
+			RealmID: "bf-r1", Name: " Weekly review ", Cron: "0 9 * * 1",
+			Command: ScheduleCommandCreateRune, Args: map[string]string{"title": "Weekly review", "branch": "main", "priority": "2"},
+		})
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^sc-[0-9a-f]{8}$`, tc.result.ScheduleID)
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, AdminRealmID, call.realmID)
+		assert.Equal(t, "schedule-"+tc.result.ScheduleID, call.streamID)
+		created, ok := call.events[0].Data.(ScheduleCreated)
+		require.True(t, ok)
+		assert.Equal(t, "Weekly review", created.Name)
+		assert.Equal(t, "0 9 * * 1", created.Cron)
+	})
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		cases := map[string]struct {
+			cmd      CreateSchedule
+			expected string
+		}{
+			"missing name":     {CreateSchedule{Cron: "@daily", Command: ScheduleCommandSweepRunes}, "name is required"},
+			"bad cron":         {CreateSchedule{Name: "s", Cron: "every day", Command: ScheduleCommandSweepRunes}, "must have 5 fields"},
+			"unknown command":  {CreateSchedule{Name: "s", Cron: "@daily", Command: "delete_realm"}, "command must be one of create_rune, seal_stale_drafts, sweep_runes"},
+			"unknown argument": {CreateSchedule{Name: "s", Cron: "@daily", Command: ScheduleCommandSweepRunes, Args: map[string]string{"force": "true"}}, `sweep_runes does not take "force"`},
+			"bad days":         {CreateSchedule{Name: "s", Cron: "@daily", Command: ScheduleCommandSealStaleDrafts, Args: map[string]string{"days": "0"}}, "days must be a positive integer"},
+			"rune no branch":   {CreateSchedule{Name: "s", Cron: "@daily", Command: ScheduleCommandCreateRune, Args: map[string]string{"title": "t"}}, "branch is required"},
+			"rune no title":    {CreateSchedule{Name: "s", Cron: "@daily", Command: ScheduleCommandCreateRune, Args: map[string]string{"branch": "main"}}, "title"},
+		}
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				tc := newScheduleHandlerTestContext(t)
+
+				// Given
+				tc.existing_realm("bf-r1")
+				c.cmd.RealmID = "bf-r1"
+
+				// When
+				tc.create_schedule_is_handled(c.cmd)
+
+				// Then
+				tc.error_contains(c.expected)
+				assert.True(t, errors.Is(tc.err, ErrInvalidCommand))
+				assert.Empty(t, tc.eventStore.appendedCalls)
+			})
+		}
+	})
+}
+
+func TestHandleDeleteSchedule(t *testing.T) {
+	t.Run("deletes existing schedule", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-a1b2c3d4", "bf-r1", "@daily")
+
+		// When
+		tc.delete_schedule_is_handled("bf-r1", "sc-a1b2c3d4")
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, 1, call.expectedVersion)
+		assert.Equal(t, ScheduleDeleted{ScheduleID: "sc-a1b2c3d4", RealmID: "bf-r1"}, call.events[0].Data)
+	})
+
+	t.Run("returns not found for schedule in another realm", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-a1b2c3d4", "bf-r1", "@daily")
+
+		// When
+		tc.delete_schedule_is_handled("bf-r2", "sc-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("schedule", "sc-a1b2c3d4")
+	})
+}
+
+func TestHandleStartScheduleRun(t *testing.T) {
+	t.Run("starts the latest due run", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-1", "bf-r1", "@hourly")
+		tc.now_is("2026-03-01T03:20:00Z")
+
+		// When
+		tc.start_run_is_handled("bf-r1", "sc-1")
+
+		// Then
+		tc.no_error()
+		assert.True(t, tc.started.Started)
+		assert.Equal(t, tc.parse_time("2026-03-01T03:00:00Z"), tc.started.DueAt)
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, 1, call.expectedVersion)
+		assert.Equal(t, EventScheduleRunStarted, call.events[0].EventType)
+	})
+
+	t.Run("does nothing when no run fell due since the last", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-1", "bf-r1", "@hourly")
+		tc.schedule_ran("sc-1", "2026-03-01T03:00:00Z")
+		tc.now_is("2026-03-01T03:59:00Z")
+
+		// When
+		tc.start_run_is_handled("bf-r1", "sc-1")
+
+		// Then
+		tc.no_error()
+		assert.False(t, tc.started.Started)
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("returns not found for a deleted schedule", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-1", "bf-r1", "@hourly")
+		tc.schedule_was_deleted("sc-1", "bf-r1")
+		tc.now_is("2026-03-01T03:20:00Z")
+
+		// When
+		tc.start_run_is_handled("bf-r1", "sc-1")
+
+		// Then
+		tc.error_is_not_found("schedule", "sc-1")
+	})
+}
+
+func TestHandleFinishScheduleRun(t *testing.T) {
+	t.Run("records the run's outcome", func(t *testing.T) {
+		tc := newScheduleHandlerTestContext(t)
+
+		// Given
+		tc.existing_schedule("sc-1", "bf-r1", "@hourly")
+		tc.schedule_ran("sc-1", "2026-03-01T03:00:00Z")
+		tc.now_is("2026-03-01T03:00:05Z")
+
+		// When
+		tc.err = HandleFinishScheduleRun(tc.ctx, "bf-r1", FinishScheduleRun{
+			ScheduleID: "sc-1", DueAt: tc.parse_time("2026-03-01T03:00:00Z"), Result: "shattered 2 runes",
+		}, tc.eventStore)
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		finished, ok := tc.eventStore.appendedCalls[0].events[0].Data.(ScheduleRunFinished)
+		require.True(t, ok)
+		assert.Equal(t, "shattered 2 runes", finished.Result)
+		assert.Equal(t, tc.parse_time("2026-03-01T03:00:05Z"), finished.FinishedAt)
+	})
+}
+
+// --- Test Context ---
+
+type scheduleHandlerTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	result  CreateScheduleResult
+	started StartScheduleRunResult
+	err     error
+}
+
+func newScheduleHandlerTestContext(t *testing.T) *scheduleHandlerTestContext {
+	t.Helper()
+	return &scheduleHandlerTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *scheduleHandlerTestContext) existing_realm(realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: realmID, Name: "Realm"}),
+	}
+}
+
+func (tc *scheduleHandlerTestContext) existing_schedule(scheduleID, realmID, cron string) {
+	tc.t.Helper()
+	tc.eventStore.streams["schedule-"+scheduleID] = []core.Event{
+		makeEvent(EventScheduleCreated, ScheduleCreated{
+			ScheduleID: scheduleID, RealmID: realmID, Name: "s", Cron: cron,
+			Command: ScheduleCommandSweepRunes, CreatedAt: tc.parse_time("2026-03-01T00:30:00Z"),
+		}),
+	}
+}
+
+func (tc *scheduleHandlerTestContext) schedule_ran(scheduleID, dueAt string) {
+	tc.t.Helper()
+	tc.eventStore.streams["schedule-"+scheduleID] = append(tc.eventStore.streams["schedule-"+scheduleID],
+		makeEvent(EventScheduleRunStarted, ScheduleRunStarted{ScheduleID: scheduleID, DueAt: tc.parse_time(dueAt)}))
+}
+
+func (tc *scheduleHandlerTestContext) schedule_was_deleted(scheduleID, realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["schedule-"+scheduleID] = append(tc.eventStore.streams["schedule-"+scheduleID],
+		makeEvent(EventScheduleDeleted, ScheduleDeleted{ScheduleID: scheduleID, RealmID: realmID}))
+}
+
+func (tc *scheduleHandlerTestContext) now_is(at string) {
+	tc.t.Helper()
+	tc.ctx = core.ContextWithClock(tc.ctx, core.FixedClock(tc.parse_time(at)))
+}
+
+func (tc *scheduleHandlerTestContext) parse_time(value string) time.Time {
+	tc.t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	require.NoError(tc.t, err)
+	return at
+}
+
+// --- When ---
+
+func (tc *scheduleHandlerTestContext) create_schedule_is_handled(cmd CreateSchedule) {
+	tc.t.Helper()
+	tc.result, tc.err = HandleCreateSchedule(tc.ctx, cmd, tc.eventStore)
+}
+
+func (tc *scheduleHandlerTestContext) delete_schedule_is_handled(realmID, scheduleID string) {
+	tc.t.Helper()
+	tc.err = HandleDeleteSchedule(tc.ctx, DeleteSchedule{RealmID: realmID, ScheduleID: scheduleID}, tc.eventStore)
+}
+
+func (tc *scheduleHandlerTestContext) start_run_is_handled(realmID, scheduleID string) {
+	tc.t.Helper()
+	tc.started, tc.err = HandleStartScheduleRun(tc.ctx, realmID, StartScheduleRun{ScheduleID: scheduleID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *scheduleHandlerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *scheduleHandlerTestContext) error_contains(substring string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substring)
+}
+
+func (tc *scheduleHandlerTestContext) error_is_not_found(entity, id string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, entity, nfe.Entity)
+	assert.Equal(tc.t, id, nfe.ID)
+}
@@ -75,7 +75,7 @@ func requireExistingSkill(state SkillState, skillID string) error {
 		return &core.NotFoundError{Entity: "skill", ID: skillID}
 	}
 	if state.Deleted {
-		return fmt.Errorf("skill %q is deleted", skillID)
+		return Rejectf(ErrDeleted, "skill %q is deleted", skillID)
 	}
 	return nil
 }
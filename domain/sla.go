@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLASettingPrefix starts the realm settings that set how long a rune may
+// stay in a status, such as "sla.claimed" = "7d".
+const SLASettingPrefix = "sla."
+
+// SLAStatuses are the statuses an SLA can be set for; the others are final.
+var SLAStatuses = []string{"draft", "open", "claimed"}
+
+// ParseSLAThreshold reads a threshold written in whole days ("7d") or as a
+// Go duration ("36h").
+func ParseSLAThreshold(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid SLA threshold %q", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid SLA threshold %q", value)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("SLA threshold %q must be positive", value)
+	}
+	return d, nil
+}
+
+// SLAThresholdsFromSettings returns the realm's thresholds keyed by status,
+// skipping any that do not parse.
+func SLAThresholdsFromSettings(settings map[string]string) map[string]time.Duration {
+	thresholds := make(map[string]time.Duration)
+	for _, status := range SLAStatuses {
+		value, ok := settings[SLASettingPrefix+status]
+		if !ok {
+			continue
+		}
+		if d, err := ParseSLAThreshold(value); err == nil {
+			thresholds[status] = d
+		}
+	}
+	return thresholds
+}
+
+// validateSLASetting rejects an sla.* setting for an unknown status or with
+// a threshold that does not parse.
+func validateSLASetting(key, value string) error {
+	status := strings.TrimPrefix(key, SLASettingPrefix)
+	if !slices.Contains(SLAStatuses, status) {
+		return Rejectf(ErrInvalidCommand, "SLA status %q must be one of %s", status, strings.Join(SLAStatuses, ", "))
+	}
+	if _, err := ParseSLAThreshold(value); err != nil {
+		return Rejectf(ErrInvalidCommand, "%v", err)
+	}
+	return nil
+}
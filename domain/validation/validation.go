@@ -0,0 +1,95 @@
+// Package validation holds the limits on rune fields. The domain handlers
+// apply them to every command, and clients such as the CLI apply them before
+// sending one, so bad input is rejected the same way everywhere.
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 64 * 1024
+	MinPriority          = 0
+	MaxPriority          = 4
+	MaxBranchLength      = 255
+	MaxNoteLength        = 16 * 1024
+)
+
+// Error reports a field that breaks one of the limits.
+type Error struct {
+	Field  string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Title requires a non-blank title of at most MaxTitleLength characters.
+func Title(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return &Error{Field: "title", Reason: "must not be blank"}
+	}
+	if n := utf8.RuneCountInString(title); n > MaxTitleLength {
+		return &Error{Field: "title", Reason: fmt.Sprintf("%d characters exceeds the limit of %d", n, MaxTitleLength)}
+	}
+	return nil
+}
+
+// Description allows descriptions of up to MaxDescriptionLength bytes.
+func Description(description string) error {
+	if n := len(description); n > MaxDescriptionLength {
+		return &Error{Field: "description", Reason: fmt.Sprintf("%d bytes exceeds the limit of %d", n, MaxDescriptionLength)}
+	}
+	return nil
+}
+
+// Priority requires a priority from MinPriority to MaxPriority.
+func Priority(priority int) error {
+	if priority < MinPriority || priority > MaxPriority {
+		return &Error{Field: "priority", Reason: fmt.Sprintf("%d is outside %d-%d", priority, MinPriority, MaxPriority)}
+	}
+	return nil
+}
+
+// Branch accepts an empty branch (none) or a git-style branch name: letters,
+// digits, '.', '_', '-' and '/', without empty path segments, "..", or a
+// leading '-'.
+func Branch(branch string) error {
+	if branch == "" {
+		return nil
+	}
+	if len(branch) > MaxBranchLength {
+		return &Error{Field: "branch", Reason: fmt.Sprintf("%d characters exceeds the limit of %d", len(branch), MaxBranchLength)}
+	}
+	for _, c := range branch {
+		if !isBranchChar(c) {
+			return &Error{Field: "branch", Reason: fmt.Sprintf("%q contains %q", branch, c)}
+		}
+	}
+	if strings.HasPrefix(branch, "-") || strings.Contains(branch, "..") ||
+		strings.HasPrefix(branch, "/") || strings.HasSuffix(branch, "/") || strings.Contains(branch, "//") ||
+		strings.HasSuffix(branch, ".") || strings.HasSuffix(branch, ".lock") {
+		return &Error{Field: "branch", Reason: fmt.Sprintf("%q is not a valid branch name", branch)}
+	}
+	return nil
+}
+
+func isBranchChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '.' || c == '_' || c == '-' || c == '/'
+}
+
+// Note requires a non-blank note of at most MaxNoteLength bytes.
+func Note(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return &Error{Field: "note", Reason: "must not be blank"}
+	}
+	if n := len(text); n > MaxNoteLength {
+		return &Error{Field: "note", Reason: fmt.Sprintf("%d bytes exceeds the limit of %d", n, MaxNoteLength)}
+	}
+	return nil
+}
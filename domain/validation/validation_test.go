@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestTitle(t *testing.T) {
+	t.Run("accepts a title at the limit", func(t *testing.T) {
+		tc := newValidationTestContext(t)
+
+		// When
+		tc.err = Title(strings.Repeat("ü", MaxTitleLength))
+
+		// Then
+		tc.is_valid()
+	})
+
+	t.Run("rejects blank and overlong titles", func(t *testing.T) {
+		for _, title := range []string{"", " \t", strings.Repeat("x", MaxTitleLength+1)} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Title(title)
+
+			// Then
+			tc.is_invalid("title")
+		}
+	})
+}
+
+func TestDescription(t *testing.T) {
+	t.Run("rejects descriptions over the limit", func(t *testing.T) {
+		tc := newValidationTestContext(t)
+
+		// When
+		tc.err = Description(strings.Repeat("x", MaxDescriptionLength+1))
+
+		// Then
+		tc.is_invalid("description")
+	})
+}
+
+func TestPriority(t *testing.T) {
+	t.Run("accepts the ends of the range", func(t *testing.T) {
+		for _, p := range []int{MinPriority, MaxPriority} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Priority(p)
+
+			// Then
+			tc.is_valid()
+		}
+	})
+
+	t.Run("rejects priorities outside the range", func(t *testing.T) {
+		for _, p := range []int{MinPriority - 1, MaxPriority + 1} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Priority(p)
+
+			// Then
+			tc.is_invalid("priority")
+		}
+	})
+}
+
+func TestBranch(t *testing.T) {
+	t.Run("accepts git-style names and no branch", func(t *testing.T) {
+		for _, b := range []string{"", "main", "feature/bf-a1b2_fix-bridge", "release/1.2"} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Branch(b)
+
+			// Then
+			tc.is_valid()
+		}
+	})
+
+	t.Run("rejects malformed names", func(t *testing.T) {
+		for _, b := range []string{"has space", "-main", "a..b", "/main", "main/", "a//b", "main.", "main.lock", "naïve", strings.Repeat("b", MaxBranchLength+1)} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Branch(b)
+
+			// Then
+			tc.is_invalid("branch")
+		}
+	})
+}
+
+func TestNote(t *testing.T) {
+	t.Run("rejects blank and overlong notes", func(t *testing.T) {
+		for _, text := range []string{"", "  ", strings.Repeat("x", MaxNoteLength+1)} {
+			tc := newValidationTestContext(t)
+
+			// When
+			tc.err = Note(text)
+
+			// Then
+			tc.is_invalid("note")
+		}
+	})
+}
+
+// --- Test Context ---
+
+type validationTestContext struct {
+	t   *testing.T
+	err error
+}
+
+func newValidationTestContext(t *testing.T) *validationTestContext {
+	t.Helper()
+	return &validationTestContext{t: t}
+}
+
+// --- Then ---
+
+func (tc *validationTestContext) is_valid() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *validationTestContext) is_invalid(field string) {
+	tc.t.Helper()
+	var verr *Error
+	require.True(tc.t, errors.As(tc.err, &verr), "expected a validation error, got %v", tc.err)
+	assert.Equal(tc.t, field, verr.Field)
+}
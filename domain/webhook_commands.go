@@ -0,0 +1,19 @@
+package domain
+
+type RegisterWebhook struct {
+	RealmID string `json:"realm_id"`
+	URL     string `json:"url"`
+	// Secret signs deliveries. Generated when empty.
+	Secret string `json:"secret,omitempty"`
+	// Events lists the event types to deliver. Empty means all events.
+	Events []string `json:"events,omitempty"`
+}
+
+type RemoveWebhook struct {
+	WebhookID string `json:"webhook_id"`
+}
+
+type RegisterWebhookResult struct {
+	WebhookID string `json:"webhook_id"`
+	Secret    string `json:"secret"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+const (
+	EventWebhookRegistered = "WebhookRegistered"
+	EventWebhookRemoved    = "WebhookRemoved"
+)
+
+type WebhookRegistered struct {
+	WebhookID string    `json:"webhook_id"`
+	RealmID   string    `json:"realm_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookRemoved struct {
+	WebhookID string `json:"webhook_id"`
+	RealmID   string `json:"realm_id"`
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const webhookStreamPrefix = "webhook-"
+
+type WebhookState struct {
+	WebhookID string
+	RealmID   string
+	URL       string
+	Secret    string
+	Events    []string
+	Exists    bool
+	Removed   bool
+}
+
+func RebuildWebhookState(events []core.Event) WebhookState {
+	var state WebhookState
+	for _, evt := range events {
+		switch evt.EventType {
+		case EventWebhookRegistered:
+			var data WebhookRegistered
+			_ = json.Unmarshal(evt.Data, &data)
+			state.Exists = true
+			state.WebhookID = data.WebhookID
+			state.RealmID = data.RealmID
+			state.URL = data.URL
+			state.Secret = data.Secret
+			state.Events = data.Events
+		case EventWebhookRemoved:
+			state.Removed = true
+		}
+	}
+	return state
+}
+
+func webhookStreamID(webhookID string) string {
+	return webhookStreamPrefix + webhookID
+}
+
+func generateWebhookID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook ID: %w", err)
+	}
+	return "wh-" + hex.EncodeToString(b), nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func readAndRebuildWebhookState(ctx context.Context, webhookID string, store core.EventStore) (WebhookState, []core.Event, error) {
+	events, err := store.ReadStream(ctx, AdminRealmID, webhookStreamID(webhookID), 0)
+	if err != nil {
+		return WebhookState{}, nil, err
+	}
+	return RebuildWebhookState(events), events, nil
+}
+
+func HandleRegisterWebhook(ctx context.Context, cmd RegisterWebhook, store core.EventStore) (RegisterWebhookResult, error) {
+	u, err := url.Parse(cmd.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return RegisterWebhookResult{}, Rejectf(ErrInvalidCommand, "cannot register webhook: url must be an absolute http or https URL")
+	}
+
+	realm, _, err := readAndRebuildRealmState(ctx, cmd.RealmID, store)
+	if err != nil {
+		return RegisterWebhookResult{}, err
+	}
+	if !realm.Exists {
+		return RegisterWebhookResult{}, &core.NotFoundError{Entity: "realm", ID: cmd.RealmID}
+	}
+
+	secret := cmd.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return RegisterWebhookResult{}, err
+		}
+	}
+	webhookID, err := generateWebhookID()
+	if err != nil {
+		return RegisterWebhookResult{}, err
+	}
+
+	registered := WebhookRegistered{
+		WebhookID: webhookID,
+		RealmID:   cmd.RealmID,
+		URL:       cmd.URL,
+		Secret:    secret,
+		Events:    cmd.Events,
+		CreatedAt: core.ClockFromContext(ctx).Now().UTC(),
+	}
+	_, err = store.Append(ctx, AdminRealmID, webhookStreamID(webhookID), 0, []core.EventData{
+		{EventType: EventWebhookRegistered, Data: registered},
+	})
+	if err != nil {
+		return RegisterWebhookResult{}, err
+	}
+
+	return RegisterWebhookResult{WebhookID: webhookID, Secret: secret}, nil
+}
+
+func HandleRemoveWebhook(ctx context.Context, cmd RemoveWebhook, store core.EventStore) error {
+	state, events, err := readAndRebuildWebhookState(ctx, cmd.WebhookID, store)
+	if err != nil {
+		return err
+	}
+	if !state.Exists || state.Removed {
+		return &core.NotFoundError{Entity: "webhook", ID: cmd.WebhookID}
+	}
+
+	removed := WebhookRemoved{WebhookID: cmd.WebhookID, RealmID: state.RealmID}
+	_, err = store.Append(ctx, AdminRealmID, webhookStreamID(cmd.WebhookID), len(events), []core.EventData{
+		{EventType: EventWebhookRemoved, Data: removed},
+	})
+	return err
+}
@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRebuildWebhookState(t *testing.T) {
+	t.Run("rebuilds state from WebhookRegistered event", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.events_from_registered_webhook()
+
+		// When
+		tc.webhook_state_is_rebuilt()
+
+		// Then
+		assert.True(t, tc.state.Exists)
+		assert.False(t, tc.state.Removed)
+		assert.Equal(t, "wh-a1b2c3d4", tc.state.WebhookID)
+		assert.Equal(t, "bf-r1", tc.state.RealmID)
+		assert.Equal(t, "https://example.com/hook", tc.state.URL)
+		assert.Equal(t, "s3cret", tc.state.Secret)
+	})
+
+	t.Run("applies WebhookRemoved", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.events_from_registered_and_removed_webhook()
+
+		// When
+		tc.webhook_state_is_rebuilt()
+
+		// Then
+		assert.True(t, tc.state.Removed)
+	})
+}
+
+func TestHandleRegisterWebhook(t *testing.T) {
+	t.Run("registers webhook with generated secret", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+		tc.register_webhook_command("bf-r1", "https://example.com/hook", "")
+
+		// When
+		tc.register_webhook_is_handled()
+
+		// Then
+		tc.no_error()
+		assert.Regexp(t, `^wh-[0-9a-f]{8}$`, tc.result.WebhookID)
+		assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, tc.result.Secret)
+		tc.registered_event_was_appended()
+		assert.Equal(t, tc.result.Secret, tc.registered.Secret)
+	})
+
+	t.Run("keeps provided secret and event filter", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+		tc.register_webhook_command("bf-r1", "https://example.com/hook", "my-secret", EventRuneCreated)
+
+		// When
+		tc.register_webhook_is_handled()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "my-secret", tc.result.Secret)
+		tc.registered_event_was_appended()
+		assert.Equal(t, []string{EventRuneCreated}, tc.registered.Events)
+		assert.Equal(t, "bf-r1", tc.registered.RealmID)
+	})
+
+	t.Run("rejects non-http URL", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.existing_realm("bf-r1")
+		tc.register_webhook_command("bf-r1", "ftp://example.com/hook", "")
+
+		// When
+		tc.register_webhook_is_handled()
+
+		// Then
+		tc.error_contains("url must be an absolute http or https URL")
+		assert.Empty(t, tc.eventStore.appendedCalls)
+	})
+
+	t.Run("returns not found for unknown realm", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.register_webhook_command("bf-missing", "https://example.com/hook", "")
+
+		// When
+		tc.register_webhook_is_handled()
+
+		// Then
+		tc.error_is_not_found("realm", "bf-missing")
+	})
+}
+
+func TestHandleRemoveWebhook(t *testing.T) {
+	t.Run("removes existing webhook", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.existing_webhook("wh-a1b2c3d4")
+
+		// When
+		tc.remove_webhook_is_handled("wh-a1b2c3d4")
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.eventStore.appendedCalls, 1)
+		call := tc.eventStore.appendedCalls[0]
+		assert.Equal(t, AdminRealmID, call.realmID)
+		assert.Equal(t, "webhook-wh-a1b2c3d4", call.streamID)
+		assert.Equal(t, 1, call.expectedVersion)
+		assert.Equal(t, EventWebhookRemoved, call.events[0].EventType)
+	})
+
+	t.Run("returns not found for unknown webhook", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// When
+		tc.remove_webhook_is_handled("wh-missing")
+
+		// Then
+		tc.error_is_not_found("webhook", "wh-missing")
+	})
+
+	t.Run("returns not found for removed webhook", func(t *testing.T) {
+		tc := newWebhookHandlerTestContext(t)
+
+		// Given
+		tc.existing_webhook("wh-a1b2c3d4")
+		tc.events_from_registered_and_removed_webhook()
+		tc.eventStore.streams["webhook-wh-a1b2c3d4"] = tc.events
+
+		// When
+		tc.remove_webhook_is_handled("wh-a1b2c3d4")
+
+		// Then
+		tc.error_is_not_found("webhook", "wh-a1b2c3d4")
+	})
+}
+
+// --- Test Context ---
+
+type webhookHandlerTestContext struct {
+	t *testing.T
+
+	eventStore *mockEventStore
+	ctx        context.Context
+
+	cmd        RegisterWebhook
+	events     []core.Event
+	state      WebhookState
+	result     RegisterWebhookResult
+	registered WebhookRegistered
+	err        error
+}
+
+func newWebhookHandlerTestContext(t *testing.T) *webhookHandlerTestContext {
+	t.Helper()
+	return &webhookHandlerTestContext{
+		t:          t,
+		eventStore: newMockEventStore(),
+		ctx:        context.Background(),
+	}
+}
+
+// --- Given ---
+
+func (tc *webhookHandlerTestContext) events_from_registered_webhook() {
+	tc.t.Helper()
+	tc.events = []core.Event{
+		makeEvent(EventWebhookRegistered, WebhookRegistered{
+			WebhookID: "wh-a1b2c3d4", RealmID: "bf-r1", URL: "https://example.com/hook", Secret: "s3cret",
+		}),
+	}
+}
+
+func (tc *webhookHandlerTestContext) events_from_registered_and_removed_webhook() {
+	tc.t.Helper()
+	tc.events_from_registered_webhook()
+	tc.events = append(tc.events, makeEvent(EventWebhookRemoved, WebhookRemoved{
+		WebhookID: "wh-a1b2c3d4", RealmID: "bf-r1",
+	}))
+}
+
+func (tc *webhookHandlerTestContext) existing_realm(realmID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		makeEvent(EventRealmCreated, RealmCreated{RealmID: realmID, Name: "Realm"}),
+	}
+}
+
+func (tc *webhookHandlerTestContext) existing_webhook(webhookID string) {
+	tc.t.Helper()
+	tc.eventStore.streams["webhook-"+webhookID] = []core.Event{
+		makeEvent(EventWebhookRegistered, WebhookRegistered{
+			WebhookID: webhookID, RealmID: "bf-r1", URL: "https://example.com/hook", Secret: "s3cret",
+		}),
+	}
+}
+
+func (tc *webhookHandlerTestContext) register_webhook_command(realmID, url, secret string, events ...string) {
+	tc.t.Helper()
+	tc.cmd = RegisterWebhook{RealmID: realmID, URL: url, Secret: secret, Events: events}
+}
+
+// --- When ---
+
+func (tc *webhookHandlerTestContext) webhook_state_is_rebuilt() {
+	tc.t.Helper()
+	tc.state = RebuildWebhookState(tc.events)
+}
+
+func (tc *webhookHandlerTestContext) register_webhook_is_handled() {
+	tc.t.Helper()
+	tc.result, tc.err = HandleRegisterWebhook(tc.ctx, tc.cmd, tc.eventStore)
+}
+
+func (tc *webhookHandlerTestContext) remove_webhook_is_handled(webhookID string) {
+	tc.t.Helper()
+	tc.err = HandleRemoveWebhook(tc.ctx, RemoveWebhook{WebhookID: webhookID}, tc.eventStore)
+}
+
+// --- Then ---
+
+func (tc *webhookHandlerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *webhookHandlerTestContext) error_contains(substring string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substring)
+}
+
+func (tc *webhookHandlerTestContext) error_is_not_found(entity, id string) {
+	tc.t.Helper()
+	var nfe *core.NotFoundError
+	require.True(tc.t, errors.As(tc.err, &nfe), "expected NotFoundError, got %v", tc.err)
+	assert.Equal(tc.t, entity, nfe.Entity)
+	assert.Equal(tc.t, id, nfe.ID)
+}
+
+func (tc *webhookHandlerTestContext) registered_event_was_appended() {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appendedCalls, 1)
+	call := tc.eventStore.appendedCalls[0]
+	assert.Equal(tc.t, AdminRealmID, call.realmID)
+	assert.Equal(tc.t, "webhook-"+tc.result.WebhookID, call.streamID)
+	require.Len(tc.t, call.events, 1)
+	assert.Equal(tc.t, EventWebhookRegistered, call.events[0].EventType)
+	registered, ok := call.events[0].Data.(WebhookRegistered)
+	require.True(tc.t, ok)
+	tc.registered = registered
+}
@@ -75,7 +75,7 @@ func requireExistingWorkflow(state WorkflowState, workflowID string) error {
 		return &core.NotFoundError{Entity: "workflow", ID: workflowID}
 	}
 	if state.Deleted {
-		return fmt.Errorf("workflow %q is deleted", workflowID)
+		return Rejectf(ErrDeleted, "workflow %q is deleted", workflowID)
 	}
 	return nil
 }
@@ -0,0 +1,7 @@
+// Package blobstore implements core.BlobStore against S3-compatible object
+// stores and Google Cloud Storage.
+//
+// Both backends are reached through their XML APIs with HMAC credentials and
+// V4 request signing, which S3 and GCS share apart from naming, so no cloud
+// SDK is required.
+package blobstore
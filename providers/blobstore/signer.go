@@ -0,0 +1,197 @@
+package blobstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+)
+
+// signer implements V4 signing. S3 (AWS4-HMAC-SHA256) and GCS
+// (GOOG4-HMAC-SHA256) differ only in the names below.
+type signer struct {
+	keyPrefix  string // prepended to the secret when deriving the signing key
+	algorithm  string
+	terminator string
+	headerPre  string // prefix of the query parameters and headers, e.g. "X-Amz"
+	service    string
+	region     string
+	accessKey  string
+	secretKey  string
+}
+
+func awsSigner(region, accessKey, secretKey string) signer {
+	return signer{
+		keyPrefix:  "AWS4",
+		algorithm:  "AWS4-HMAC-SHA256",
+		terminator: "aws4_request",
+		headerPre:  "X-Amz",
+		service:    "s3",
+		region:     region,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+	}
+}
+
+func googSigner(accessKey, secretKey string) signer {
+	return signer{
+		keyPrefix:  "GOOG4",
+		algorithm:  "GOOG4-HMAC-SHA256",
+		terminator: "goog4_request",
+		headerPre:  "X-Goog",
+		service:    "storage",
+		region:     "auto",
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+	}
+}
+
+func (s signer) scope(t time.Time) string {
+	return strings.Join([]string{t.Format("20060102"), s.region, s.service, s.terminator}, "/")
+}
+
+// presign returns u with query-string authentication for method, valid for
+// expires from t. Only the host header is signed.
+func (s signer) presign(method string, u *url.URL, t time.Time, expires time.Duration) string {
+	t = t.UTC()
+	query := u.Query()
+	query.Set(s.headerPre+"-Algorithm", s.algorithm)
+	query.Set(s.headerPre+"-Credential", s.accessKey+"/"+s.scope(t))
+	query.Set(s.headerPre+"-Date", t.Format(amzDateFormat))
+	query.Set(s.headerPre+"-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set(s.headerPre+"-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		canonicalURI(u),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	query.Set(s.headerPre+"-Signature", s.signature(t, canonical))
+
+	signed := *u
+	signed.RawPath = canonicalURI(u)
+	signed.RawQuery = canonicalQuery(query)
+	return signed.String()
+}
+
+// sign adds header authentication to req. The payload is left unsigned so
+// bodies can be streamed.
+func (s signer) sign(req *http.Request, t time.Time) {
+	t = t.UTC()
+	dateHeader := strings.ToLower(s.headerPre) + "-date"
+	hashHeader := strings.ToLower(s.headerPre) + "-content-sha256"
+	req.Header.Set(dateHeader, t.Format(amzDateFormat))
+	req.Header.Set(hashHeader, unsignedPayload)
+
+	headers := map[string]string{
+		"host":     req.URL.Host,
+		dateHeader: t.Format(amzDateFormat),
+		hashHeader: unsignedPayload,
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.algorithm, s.accessKey, s.scope(t), signedHeaders, s.signature(t, canonical)))
+}
+
+func (s signer) signature(t time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s.algorithm,
+		t.Format(amzDateFormat),
+		s.scope(t),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte(s.keyPrefix+s.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, s.terminator)
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalURI encodes each path segment per RFC 3986, keeping slashes.
+func canonicalURI(u *url.URL) string {
+	path := u.Path
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,217 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// maxPresignExpiry is the longest lifetime V4 presigned URLs accept.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// S3Config configures an S3-compatible backend such as AWS S3, MinIO or
+// Cloudflare R2.
+type S3Config struct {
+	// Endpoint is the service URL. Defaults to https://s3.<region>.amazonaws.com.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key> instead of
+	// <bucket>.<endpoint>/<key>. Most self-hosted stores require it.
+	PathStyle bool
+}
+
+// GCSConfig configures a Google Cloud Storage backend using an HMAC key of a
+// service account.
+type GCSConfig struct {
+	Bucket   string
+	AccessID string
+	Secret   string
+	// Endpoint defaults to https://storage.googleapis.com.
+	Endpoint string
+}
+
+// Store implements core.BlobStore against an object store's XML API.
+type Store struct {
+	client    *http.Client
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	signer    signer
+	now       func() time.Time
+}
+
+var _ core.BlobStore = (*Store)(nil)
+
+// NewS3 creates a Store for an S3-compatible backend.
+func NewS3(cfg S3Config, client *http.Client) (*Store, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("s3 region is required")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	return newStore(endpoint, cfg.Bucket, cfg.PathStyle, awsSigner(cfg.Region, cfg.AccessKeyID, cfg.SecretAccessKey), client)
+}
+
+// NewGCS creates a Store for Google Cloud Storage.
+func NewGCS(cfg GCSConfig, client *http.Client) (*Store, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	return newStore(endpoint, cfg.Bucket, true, googSigner(cfg.AccessID, cfg.Secret), client)
+}
+
+func newStore(endpoint, bucket string, pathStyle bool, s signer, client *http.Client) (*Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("access key and secret are required")
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", endpoint)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Store{
+		client:    client,
+		endpoint:  u,
+		bucket:    bucket,
+		pathStyle: pathStyle,
+		signer:    s,
+		now:       time.Now,
+	}, nil
+}
+
+func (s *Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = u.Path + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	return &u
+}
+
+func (s *Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return s.do(req, key)
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, key)
+}
+
+// List returns the keys of the blobs whose keys start with prefix, in
+// lexical order. It uses the ListObjectsV2 API, which GCS's XML API also
+// implements.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u := s.objectURL("")
+		u.RawPath = canonicalURI(u)
+		u.RawQuery = canonicalQuery(query)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		s.signer.sign(req, s.now())
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("list blobs %q: %w", prefix, err)
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("list blobs %q: unexpected status %d", prefix, resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list blobs %q: %w", prefix, err)
+		}
+		for _, c := range page.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// listBucketResult is a page of a ListObjectsV2 response.
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *Store) PresignGet(_ context.Context, key string, expires time.Duration) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("blob key is required")
+	}
+	if expires < time.Second || expires > maxPresignExpiry {
+		return "", fmt.Errorf("presigned URL expiry must be between 1s and %s", maxPresignExpiry)
+	}
+	return s.signer.presign(http.MethodGet, s.objectURL(key), s.now(), expires), nil
+}
+
+func (s *Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, fmt.Errorf("blob key is required")
+	}
+	u := s.objectURL(key)
+	u.RawPath = canonicalURI(u)
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *Store) do(req *http.Request, key string) error {
+	s.signer.sign(req, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s blob %q: %w", strings.ToLower(req.Method), key, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if req.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s blob %q: unexpected status %d", strings.ToLower(req.Method), key, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// CheckpointStore is an in-memory implementation of core.CheckpointStore.
+type CheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[checkpointKey]int64
+	versions    map[string]int
+}
+
+type checkpointKey struct {
+	realmID       string
+	projectorName string
+}
+
+// NewCheckpointStore creates an empty CheckpointStore.
+func NewCheckpointStore() *CheckpointStore {
+	return &CheckpointStore{checkpoints: make(map[checkpointKey]int64), versions: make(map[string]int)}
+}
+
+// GetCheckpoint returns the last global position for the given projector.
+// Returns 0 if no checkpoint exists.
+func (s *CheckpointStore) GetCheckpoint(ctx context.Context, realmID string, projectorName string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[checkpointKey{realmID, projectorName}], nil
+}
+
+// SetCheckpoint sets the checkpoint for the given projector.
+func (s *CheckpointStore) SetCheckpoint(ctx context.Context, realmID string, projectorName string, globalPosition int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[checkpointKey{realmID, projectorName}] = globalPosition
+	return nil
+}
+
+// GetProjectorVersion returns the version recorded for the projector, or 0
+// if none is.
+func (s *CheckpointStore) GetProjectorVersion(ctx context.Context, projectorName string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[projectorName], nil
+}
+
+// SetProjectorVersion records the projector's version.
+func (s *CheckpointStore) SetProjectorVersion(ctx context.Context, projectorName string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[projectorName] = version
+	return nil
+}
+
+// ListCheckpoints returns every projector's checkpoint in the realm.
+func (s *CheckpointStore) ListCheckpoints(ctx context.Context, realmID string) (map[string]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	checkpoints := make(map[string]int64)
+	for k, pos := range s.checkpoints {
+		if k.realmID == realmID {
+			checkpoints[k.projectorName] = pos
+		}
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,4 @@
+// Package memory provides in-memory event, projection and checkpoint stores
+// for Bifrost. Nothing is written to disk and everything is lost when the
+// process exits, which suits tests, demos and embedding.
+package memory
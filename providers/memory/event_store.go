@@ -0,0 +1,320 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// EventStore is an in-memory implementation of core.EventStore.
+type EventStore struct {
+	mu        sync.RWMutex
+	events    []core.Event
+	streams   map[string][]int
+	realms    map[string][]int
+	snapshots map[string]core.Snapshot
+	dataKeys  map[string][]byte
+	// keyed holds the indexes of the events of each batch appended with an
+	// idempotency key, by stream and key.
+	keyed map[string]map[string][]int
+	// subs wakes subscriptions when events are appended.
+	subs map[chan struct{}]struct{}
+}
+
+// NewEventStore creates an empty EventStore.
+func NewEventStore() *EventStore {
+	return &EventStore{
+		streams:   make(map[string][]int),
+		realms:    make(map[string][]int),
+		snapshots: make(map[string]core.Snapshot),
+		dataKeys:  make(map[string][]byte),
+		keyed:     make(map[string]map[string][]int),
+		subs:      make(map[chan struct{}]struct{}),
+	}
+}
+
+// Append adds events to a stream with optimistic concurrency control.
+// Global positions count up from 1 across all realms. A batch keyed by an
+// idempotency key already used on the stream is not appended again; its
+// stored events are returned instead.
+func (s *EventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	now := core.ClockFromContext(ctx).Now().UTC()
+	result := make([]core.Event, len(events))
+	for i, ed := range events {
+		data, err := json.Marshal(ed.Data)
+		if err != nil {
+			return nil, err
+		}
+		var metadata []byte
+		if ed.Metadata != nil {
+			if metadata, err = json.Marshal(ed.Metadata); err != nil {
+				return nil, err
+			}
+		}
+		result[i] = core.Event{
+			RealmID:   realmID,
+			StreamID:  streamID,
+			Version:   expectedVersion + i + 1,
+			EventType: ed.EventType,
+			Data:      data,
+			Metadata:  metadata,
+			Timestamp: now,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := streamKey(realmID, streamID)
+	var idempotencyKey string
+	if len(events) > 0 {
+		idempotencyKey = events[0].IdempotencyKey
+	}
+	if indexes, ok := s.keyed[key][idempotencyKey]; ok && idempotencyKey != "" {
+		stored := make([]core.Event, len(indexes))
+		for i, idx := range indexes {
+			stored[i] = copyEvent(s.events[idx])
+		}
+		return stored, nil
+	}
+	if actual := len(s.streams[key]); actual != expectedVersion {
+		return nil, &core.ConcurrencyError{
+			StreamID:        streamID,
+			ExpectedVersion: expectedVersion,
+			ActualVersion:   actual,
+		}
+	}
+	for i := range result {
+		idx := len(s.events)
+		result[i].GlobalPosition = int64(idx + 1)
+		s.events = append(s.events, result[i])
+		s.streams[key] = append(s.streams[key], idx)
+		s.realms[realmID] = append(s.realms[realmID], idx)
+	}
+	if idempotencyKey != "" && len(result) > 0 {
+		if s.keyed[key] == nil {
+			s.keyed[key] = make(map[string][]int)
+		}
+		stream := s.streams[key]
+		s.keyed[key][idempotencyKey] = append([]int(nil), stream[len(stream)-len(result):]...)
+	}
+	for wake := range s.subs {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	return copyEvents(result), nil
+}
+
+// ReadStream returns events for a specific stream starting from the given version.
+func (s *EventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[streamKey(realmID, streamID)]
+	events := make([]core.Event, 0)
+	for v := max(fromVersion, 1); v <= len(stream); v++ {
+		events = append(events, copyEvent(s.events[stream[v-1]]))
+	}
+	return events, nil
+}
+
+// ReadStreamPage returns up to limit events of the stream from a version,
+// forward or backward.
+func (s *EventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir core.Direction) ([]core.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[streamKey(realmID, streamID)]
+	events := make([]core.Event, 0)
+	if dir == core.Forward {
+		for v := max(from, 1); v <= len(stream) && (limit <= 0 || len(events) < limit); v++ {
+			events = append(events, copyEvent(s.events[stream[v-1]]))
+		}
+		return events, nil
+	}
+	last := len(stream)
+	if from > 0 {
+		last = min(from, last)
+	}
+	for v := last; v >= 1 && (limit <= 0 || len(events) < limit); v-- {
+		events = append(events, copyEvent(s.events[stream[v-1]]))
+	}
+	return events, nil
+}
+
+// ReadAll returns events across all streams in a realm after the given global position.
+func (s *EventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]core.Event, error) {
+	return s.ReadAllBatch(ctx, realmID, fromGlobalPosition, 0)
+}
+
+// ReadAllBatch returns up to limit events in a realm after the given global
+// position. A limit of zero or less returns them all.
+func (s *EventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]core.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Positions are the indexes plus one, so the realm's indexes after the
+	// position start where the index is at least the position.
+	realm := s.realms[realmID]
+	start := sort.SearchInts(realm, int(fromGlobalPosition))
+	events := make([]core.Event, 0)
+	for _, idx := range realm[start:] {
+		if limit > 0 && len(events) == limit {
+			break
+		}
+		events = append(events, copyEvent(s.events[idx]))
+	}
+	return events, nil
+}
+
+// ListRealmIDs returns the IDs of the realms that have events.
+func (s *EventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	realmIDs := make([]string, 0, len(s.realms))
+	for id := range s.realms {
+		realmIDs = append(realmIDs, id)
+	}
+	sort.Strings(realmIDs)
+	return realmIDs, nil
+}
+
+// Subscribe returns a channel of the events after fromPosition across all
+// realms, in global position order: first those already stored, then each
+// one as it is appended. The channel is closed when ctx ends.
+func (s *EventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan core.Event, error) {
+	wake := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.subs[wake] = struct{}{}
+	s.mu.Unlock()
+	out := make(chan core.Event, subscriptionBuffer)
+
+	go func() {
+		defer close(out)
+		defer func() {
+			s.mu.Lock()
+			delete(s.subs, wake)
+			s.mu.Unlock()
+		}()
+		pos := max(fromPosition, 0)
+		for {
+			s.mu.RLock()
+			pending := copyEvents(s.events[min(pos, int64(len(s.events))):])
+			s.mu.RUnlock()
+			for _, evt := range pending {
+				select {
+				case out <- evt:
+					pos = evt.GlobalPosition
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// GetSnapshot returns the stream's latest snapshot. Returns
+// core.NotFoundError if the stream has none.
+func (s *EventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[streamKey(realmID, streamID)]
+	if !ok {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	snapshot.State = append(json.RawMessage(nil), snapshot.State...)
+	return snapshot, nil
+}
+
+// SaveSnapshot replaces the stream's snapshot.
+func (s *EventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot.State = append(json.RawMessage(nil), snapshot.State...)
+	s.snapshots[streamKey(snapshot.RealmID, snapshot.StreamID)] = snapshot
+	return nil
+}
+
+// GetDataKey returns the realm's wrapped data key. Returns
+// core.NotFoundError if the realm has none.
+func (s *EventStore) GetDataKey(ctx context.Context, realmID string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wrapped, ok := s.dataKeys[realmID]
+	if !ok {
+		return nil, &core.NotFoundError{Entity: "data key", ID: realmID}
+	}
+	return append([]byte(nil), wrapped...), nil
+}
+
+// AddDataKey stores the realm's wrapped data key unless it already has one.
+func (s *EventStore) AddDataKey(ctx context.Context, realmID string, wrapped []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.dataKeys[realmID]; !ok {
+		s.dataKeys[realmID] = append([]byte(nil), wrapped...)
+	}
+	return nil
+}
+
+// RedactStream replaces the data of the stream's events with that of the
+// redacted events of the same version, clears their metadata, and drops
+// the stream's snapshot.
+func (s *EventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []core.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := streamKey(realmID, streamID)
+	stream := s.streams[key]
+	for _, evt := range redacted {
+		if evt.Version < 1 || evt.Version > len(stream) {
+			continue
+		}
+		stored := &s.events[stream[evt.Version-1]]
+		stored.Data = append([]byte(nil), evt.Data...)
+		stored.Metadata = nil
+	}
+	delete(s.snapshots, key)
+	return nil
+}
+
+// subscriptionBuffer is the buffer of a subscription's channel.
+const subscriptionBuffer = 500
+
+func streamKey(realmID, streamID string) string {
+	return realmID + "\x00" + streamID
+}
+
+// copyEvent returns evt with its own copies of the data and metadata, so
+// callers cannot change what the store holds.
+func copyEvent(evt core.Event) core.Event {
+	evt.Data = append([]byte(nil), evt.Data...)
+	if evt.Metadata != nil {
+		evt.Metadata = append([]byte(nil), evt.Metadata...)
+	}
+	return evt
+}
+
+func copyEvents(events []core.Event) []core.Event {
+	copied := make([]core.Event, len(events))
+	for i, evt := range events {
+		copied[i] = copyEvent(evt)
+	}
+	return copied
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// ProjectionStore is an in-memory implementation of core.ProjectionStore.
+// Values are kept as JSON, so reads decode a copy as the SQLite store does.
+type ProjectionStore struct {
+	mu          sync.RWMutex
+	projections map[projectionKey]map[string]json.RawMessage
+}
+
+type projectionKey struct {
+	realmID        string
+	projectionName string
+}
+
+// NewProjectionStore creates an empty ProjectionStore.
+func NewProjectionStore() *ProjectionStore {
+	return &ProjectionStore{projections: make(map[projectionKey]map[string]json.RawMessage)}
+}
+
+// Get retrieves a projection value by realm, projection name, and key.
+// Returns core.NotFoundError if there is none.
+func (s *ProjectionStore) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	s.mu.RLock()
+	value, ok := s.projections[projectionKey{realmID, projectionName}][key]
+	s.mu.RUnlock()
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	return json.Unmarshal(value, dest)
+}
+
+// List returns all projection values for the given realm and projection
+// name, ordered by key.
+func (s *ProjectionStore) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := s.projections[projectionKey{realmID, projectionName}]
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	results := make([]json.RawMessage, 0, len(keys))
+	for _, k := range keys {
+		results = append(results, append(json.RawMessage(nil), entries[k]...))
+	}
+	return results, nil
+}
+
+// DumpProjections calls fn with each of the realm's projection rows, ordered
+// by projection name and key. fn sees a copy taken before the first call.
+func (s *ProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(core.ProjectionRow) error) error {
+	s.mu.RLock()
+	var rows []core.ProjectionRow
+	for pk, entries := range s.projections {
+		if pk.realmID != realmID {
+			continue
+		}
+		for k, v := range entries {
+			rows = append(rows, core.ProjectionRow{
+				ProjectionName: pk.projectionName,
+				Key:            k,
+				Value:          append(json.RawMessage(nil), v...),
+			})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].ProjectionName != rows[j].ProjectionName {
+			return rows[i].ProjectionName < rows[j].ProjectionName
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Put upserts a projection value for the given realm, projection name, and key.
+func (s *ProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data},
+	})
+}
+
+// Delete removes a projection entry. Deleting a non-existent key is not an error.
+func (s *ProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true},
+	})
+}
+
+// TruncateProjection deletes every entry of the projection in every realm.
+func (s *ProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for pk := range s.projections {
+		if pk.projectionName == projectionName {
+			delete(s.projections, pk)
+		}
+	}
+	return nil
+}
+
+// TruncateRealmProjection deletes every entry of the projection in the
+// realm.
+func (s *ProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.projections, projectionKey{realmID, projectionName})
+	return nil
+}
+
+// WriteBatch applies the writes in order, all at once as far as readers
+// can tell.
+func (s *ProjectionStore) WriteBatch(ctx context.Context, writes []core.ProjectionWrite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, w := range writes {
+		pk := projectionKey{w.RealmID, w.ProjectionName}
+		if w.Delete {
+			delete(s.projections[pk], w.Key)
+			continue
+		}
+		entries, ok := s.projections[pk]
+		if !ok {
+			entries = make(map[string]json.RawMessage)
+			s.projections[pk] = entries
+		}
+		entries[w.Key] = append(json.RawMessage(nil), w.Value...)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/core/storetest"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ core.EventStore            = (*EventStore)(nil)
+	_ core.BatchEventReader      = (*EventStore)(nil)
+	_ core.StreamPageReader      = (*EventStore)(nil)
+	_ core.SnapshotStore         = (*EventStore)(nil)
+	_ core.EventSubscriber       = (*EventStore)(nil)
+	_ core.BatchProjectionStore  = (*ProjectionStore)(nil)
+	_ core.ProjectionDumper      = (*ProjectionStore)(nil)
+	_ core.ProjectionTruncator   = (*ProjectionStore)(nil)
+	_ core.CheckpointStore       = (*CheckpointStore)(nil)
+	_ core.CheckpointLister      = (*CheckpointStore)(nil)
+	_ core.ProjectorVersionStore = (*CheckpointStore)(nil)
+)
+
+// --- Tests ---
+
+func TestEventStore(t *testing.T) {
+	storetest.EventStore(t, func(t *testing.T) core.EventStore {
+		return NewEventStore()
+	})
+}
+
+func TestProjectionStore(t *testing.T) {
+	storetest.ProjectionStore(t, func(t *testing.T) core.ProjectionStore {
+		return NewProjectionStore()
+	})
+}
+
+func TestCheckpointStore(t *testing.T) {
+	storetest.CheckpointStore(t, func(t *testing.T) core.CheckpointStore {
+		return NewCheckpointStore()
+	})
+}
@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// CheckpointStore is a MySQL-backed implementation of core.CheckpointStore.
+type CheckpointStore struct {
+	db *sql.DB
+}
+
+// NewCheckpointStore creates a new CheckpointStore backed by the given database.
+func NewCheckpointStore(db *sql.DB) (*CheckpointStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &CheckpointStore{db: db}, nil
+}
+
+// GetCheckpoint returns the last global position for the given projector.
+// Returns 0 if no checkpoint exists.
+func (s *CheckpointStore) GetCheckpoint(ctx context.Context, realmID string, projectorName string) (int64, error) {
+	var pos int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT last_global_position FROM checkpoints WHERE realm_id = ? AND projector_name = ?`,
+		realmID, projectorName,
+	).Scan(&pos)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return pos, nil
+}
+
+// SetCheckpoint upserts the checkpoint for the given projector.
+func (s *CheckpointStore) SetCheckpoint(ctx context.Context, realmID string, projectorName string, globalPosition int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO checkpoints (realm_id, projector_name, last_global_position) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE last_global_position = VALUES(last_global_position)`,
+		realmID, projectorName, globalPosition,
+	)
+	return err
+}
+
+// GetProjectorVersion returns the version recorded for the projector, or 0
+// if none is.
+func (s *CheckpointStore) GetProjectorVersion(ctx context.Context, projectorName string) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version FROM projector_versions WHERE projector_name = ?`,
+		projectorName,
+	).Scan(&version)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, err
+}
+
+// SetProjectorVersion upserts the version recorded for the projector.
+func (s *CheckpointStore) SetProjectorVersion(ctx context.Context, projectorName string, version int) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO projector_versions (projector_name, version) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE version = VALUES(version)`,
+		projectorName, version,
+	)
+	return err
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/core/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ core.EventStore            = (*EventStore)(nil)
+	_ core.BatchEventReader      = (*EventStore)(nil)
+	_ core.StreamPageReader      = (*EventStore)(nil)
+	_ core.SnapshotStore         = (*EventStore)(nil)
+	_ core.DataKeyStore          = (*EventStore)(nil)
+	_ core.EventRedactor         = (*EventStore)(nil)
+	_ core.BatchProjectionStore  = (*ProjectionStore)(nil)
+	_ core.ProjectionTruncator   = (*ProjectionStore)(nil)
+	_ core.CheckpointStore       = (*CheckpointStore)(nil)
+	_ core.ProjectorVersionStore = (*CheckpointStore)(nil)
+)
+
+// --- Tests ---
+
+func TestEventStore_Conformance(t *testing.T) {
+	storetest.EventStore(t, func(t *testing.T) core.EventStore {
+		store, err := NewEventStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestProjectionStore_Conformance(t *testing.T) {
+	storetest.ProjectionStore(t, func(t *testing.T) core.ProjectionStore {
+		store, err := NewProjectionStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestCheckpointStore_Conformance(t *testing.T) {
+	storetest.CheckpointStore(t, func(t *testing.T) core.CheckpointStore {
+		store, err := NewCheckpointStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestEnsureSchema(t *testing.T) {
+	t.Run("is idempotent", func(t *testing.T) {
+		db := newConformanceDB(t)
+
+		// When
+		err := EnsureSchema(db)
+
+		// Then
+		require.NoError(t, err)
+		var applied int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+		require.Equal(t, len(migrations), applied)
+	})
+}
+
+// --- Test Context ---
+
+// newConformanceDB opens the database named by BIFROST_TEST_MYSQL_DSN and
+// empties Bifrost's tables, skipping the test when no DSN is set. The
+// database is shared, so these tests must not run in parallel.
+func newConformanceDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("BIFROST_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("BIFROST_TEST_MYSQL_DSN is not set")
+	}
+	db, err := Open(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, EnsureSchema(db))
+	for _, table := range []string{"events", "idempotency_keys", "snapshots", "realm_data_keys", "projections", "checkpoints"} {
+		_, err := db.Exec(`DELETE FROM ` + table)
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(`UPDATE event_sequence SET last_position = 0 WHERE id = 1`)
+	require.NoError(t, err)
+	return db
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// GetDataKey returns the realm's wrapped data key. Returns
+// core.NotFoundError if the realm has none.
+func (s *EventStore) GetDataKey(ctx context.Context, realmID string) ([]byte, error) {
+	var wrapped []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT wrapped_key FROM realm_data_keys WHERE realm_id = ?`,
+		realmID,
+	).Scan(&wrapped)
+	if err == sql.ErrNoRows {
+		return nil, &core.NotFoundError{Entity: "data key", ID: realmID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wrapped, nil
+}
+
+// AddDataKey stores the realm's wrapped data key unless it already has one.
+func (s *EventStore) AddDataKey(ctx context.Context, realmID string, wrapped []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT IGNORE INTO realm_data_keys (realm_id, wrapped_key) VALUES (?, ?)`,
+		realmID, wrapped,
+	)
+	return err
+}
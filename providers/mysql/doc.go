@@ -0,0 +1,4 @@
+// Package mysql provides MySQL- and MariaDB-backed event, projection and
+// checkpoint stores for Bifrost. The schema is created and upgraded by
+// numbered migrations when a store is made.
+package mysql
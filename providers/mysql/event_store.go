@@ -0,0 +1,323 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	mysqllib "github.com/go-sql-driver/mysql"
+)
+
+// EventStore is a MySQL-backed implementation of core.EventStore.
+type EventStore struct {
+	db *sql.DB
+}
+
+// NewEventStore creates a new EventStore backed by the given database.
+func NewEventStore(db *sql.DB) (*EventStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &EventStore{db: db}, nil
+}
+
+// Append persists new events to a stream with optimistic concurrency control.
+// A batch keyed by an idempotency key already used on the stream is not
+// appended again; its stored events are returned instead.
+//
+// Appends take the row of event_sequence for update and number their events
+// from it, so they are serialized and global positions become visible in
+// order. Catch-up reading after a position never misses an event that
+// committed late.
+func (s *EventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	var result []core.Event
+	err := inTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		result, err = s.append(ctx, tx, realmID, streamID, expectedVersion, events)
+		return err
+	})
+	if err != nil {
+		if isMySQLConcurrencyError(err) {
+			return nil, &core.ConcurrencyError{
+				StreamID:        streamID,
+				ExpectedVersion: expectedVersion,
+				ActualVersion:   expectedVersion,
+			}
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *EventStore) append(ctx context.Context, tx *sql.Tx, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	var lastPosition int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT last_position FROM event_sequence WHERE id = 1 FOR UPDATE`,
+	).Scan(&lastPosition); err != nil {
+		return nil, err
+	}
+
+	var key string
+	if len(events) > 0 {
+		key = events[0].IdempotencyKey
+	}
+	if key != "" {
+		stored, err := keyedBatch(ctx, tx, realmID, streamID, key)
+		if err != nil || stored != nil {
+			return stored, err
+		}
+	}
+
+	var actualVersion int
+	err := tx.QueryRowContext(ctx,
+		`SELECT version FROM events WHERE realm_id = ? AND stream_id = ? ORDER BY version DESC LIMIT 1`,
+		realmID, streamID,
+	).Scan(&actualVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if actualVersion != expectedVersion {
+		return nil, &core.ConcurrencyError{
+			StreamID:        streamID,
+			ExpectedVersion: expectedVersion,
+			ActualVersion:   actualVersion,
+		}
+	}
+
+	result := make([]core.Event, len(events))
+	// DATETIME(6) keeps microseconds, so events are returned as they read back.
+	now := core.ClockFromContext(ctx).Now().UTC().Truncate(time.Microsecond)
+
+	for i, ed := range events {
+		data, err := json.Marshal(ed.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		var metadata []byte
+		var metadataVal any
+		if ed.Metadata != nil {
+			metadata, err = json.Marshal(ed.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			metadataVal = string(metadata)
+		}
+
+		version := expectedVersion + i + 1
+		globalPosition := lastPosition + int64(i) + 1
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO events (global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			globalPosition, realmID, streamID, version, ed.EventType, string(data), metadataVal, now,
+		); err != nil {
+			return nil, err
+		}
+
+		result[i] = core.Event{
+			RealmID:        realmID,
+			StreamID:       streamID,
+			Version:        version,
+			GlobalPosition: globalPosition,
+			EventType:      ed.EventType,
+			Data:           data,
+			Metadata:       metadata,
+			Timestamp:      now,
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE event_sequence SET last_position = ? WHERE id = 1`,
+		lastPosition+int64(len(events)),
+	); err != nil {
+		return nil, err
+	}
+
+	if key != "" && len(result) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO idempotency_keys (realm_id, stream_id, idempotency_key, first_version, last_version) VALUES (?, ?, ?, ?, ?)`,
+			realmID, streamID, key, expectedVersion+1, expectedVersion+len(result),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// keyedBatch returns the events of the batch appended to the stream with the
+// idempotency key, or nil if there is none.
+func keyedBatch(ctx context.Context, tx *sql.Tx, realmID, streamID, key string) ([]core.Event, error) {
+	var first, last int
+	err := tx.QueryRowContext(ctx,
+		`SELECT first_version, last_version FROM idempotency_keys WHERE realm_id = ? AND stream_id = ? AND idempotency_key = ?`,
+		realmID, streamID, key,
+	).Scan(&first, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx,
+		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND stream_id = ? AND version BETWEEN ? AND ?
+		 ORDER BY version ASC`,
+		realmID, streamID, first, last,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEvents(rows)
+}
+
+// ReadStream returns events for a specific stream starting from the given version.
+func (s *EventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND stream_id = ? AND version >= ?
+		 ORDER BY version ASC`,
+		realmID, streamID, fromVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// ReadStreamPage returns up to limit events of the stream from a version,
+// forward or backward.
+func (s *EventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir core.Direction) ([]core.Event, error) {
+	query := `SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND stream_id = ?`
+	args := []any{realmID, streamID}
+	if dir == core.Forward {
+		query += ` AND version >= ? ORDER BY version ASC`
+		args = append(args, from)
+	} else {
+		if from > 0 {
+			query += ` AND version <= ?`
+			args = append(args, from)
+		}
+		query += ` ORDER BY version DESC`
+	}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// ReadAll returns events across all streams in a realm starting from the given global position.
+func (s *EventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]core.Event, error) {
+	return s.ReadAllBatch(ctx, realmID, fromGlobalPosition, 0)
+}
+
+// ReadAllBatch returns up to limit events in a realm after the given global
+// position. A limit of zero or less returns them all.
+func (s *EventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]core.Event, error) {
+	query := `SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND global_position > ?
+		 ORDER BY global_position ASC`
+	args := []any{realmID, fromGlobalPosition}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// ListRealmIDs returns all distinct realm IDs from the events table.
+func (s *EventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT realm_id FROM events`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var realmIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		realmIDs = append(realmIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return realmIDs, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]core.Event, error) {
+	events := make([]core.Event, 0)
+	for rows.Next() {
+		var e core.Event
+		if err := rows.Scan(
+			&e.GlobalPosition,
+			&e.RealmID,
+			&e.StreamID,
+			&e.Version,
+			&e.EventType,
+			&e.Data,
+			&e.Metadata,
+			&e.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isMySQLConcurrencyError returns true if the error is a MySQL error
+// indicating a concurrency conflict: a duplicate key, a deadlock, or a lock
+// wait timeout.
+func isMySQLConcurrencyError(err error) bool {
+	var mysqlErr *mysqllib.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1062, 1205, 1213: // ER_DUP_ENTRY, ER_LOCK_WAIT_TIMEOUT, ER_LOCK_DEADLOCK
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// RedactStream replaces the data of the stream's events with that of the
+// redacted events of the same version, clears their metadata, and deletes
+// the stream's snapshot.
+func (s *EventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []core.Event) error {
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		for _, evt := range redacted {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE events SET data = ?, metadata = NULL WHERE realm_id = ? AND stream_id = ? AND version = ?`,
+				string(evt.Data), realmID, streamID, evt.Version,
+			); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM snapshots WHERE realm_id = ? AND stream_id = ?`, realmID, streamID)
+		return err
+	})
+}
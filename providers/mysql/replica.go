@@ -0,0 +1,11 @@
+package mysql
+
+import "database/sql"
+
+// NewReplicaStores returns projection and checkpoint stores reading from
+// db, a read replica of the database the other stores write to. The
+// primary's stores create the schema, so these do not; writing through them
+// fails on a read-only replica.
+func NewReplicaStores(db *sql.DB) (*ProjectionStore, *CheckpointStore) {
+	return &ProjectionStore{db: db}, &CheckpointStore{db: db}
+}
@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	mysqllib "github.com/go-sql-driver/mysql"
+)
+
+// Open opens the database at dsn, a go-sql-driver/mysql data source name
+// such as "bifrost:secret@tcp(db:3306)/bifrost". Times are read and written
+// in UTC whatever the DSN says, so event timestamps survive a round trip.
+func Open(dsn string) (*sql.DB, error) {
+	cfg, err := mysqllib.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	connector, err := mysqllib.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// tableOptions keeps IDs and keys case-sensitive, as they are in SQLite.
+const tableOptions = ` ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin`
+
+// migrations are applied in order, each once. Append new ones to the end and
+// never change one that has been released.
+var migrations = []string{
+	// 1: events and the sequence that hands out their global positions
+	`CREATE TABLE IF NOT EXISTS events (
+		global_position BIGINT NOT NULL PRIMARY KEY,
+		realm_id VARCHAR(255) NOT NULL,
+		stream_id VARCHAR(255) NOT NULL,
+		version INT NOT NULL,
+		event_type VARCHAR(255) NOT NULL,
+		data LONGTEXT,
+		metadata LONGTEXT,
+		timestamp DATETIME(6) NOT NULL,
+		UNIQUE KEY uq_events_realm_stream_version (realm_id, stream_id, version),
+		KEY idx_events_realm_global (realm_id, global_position)
+	)` + tableOptions,
+	`CREATE TABLE IF NOT EXISTS event_sequence (
+		id TINYINT NOT NULL PRIMARY KEY,
+		last_position BIGINT NOT NULL
+	)` + tableOptions,
+	`INSERT IGNORE INTO event_sequence (id, last_position) VALUES (1, 0)`,
+	`CREATE TABLE IF NOT EXISTS idempotency_keys (
+		realm_id VARCHAR(255) NOT NULL,
+		stream_id VARCHAR(255) NOT NULL,
+		idempotency_key VARCHAR(255) NOT NULL,
+		first_version INT NOT NULL,
+		last_version INT NOT NULL,
+		PRIMARY KEY (realm_id, stream_id, idempotency_key)
+	)` + tableOptions,
+	// 5: snapshots and data keys
+	`CREATE TABLE IF NOT EXISTS snapshots (
+		realm_id VARCHAR(255) NOT NULL,
+		stream_id VARCHAR(255) NOT NULL,
+		version INT NOT NULL,
+		state LONGTEXT NOT NULL,
+		PRIMARY KEY (realm_id, stream_id)
+	)` + tableOptions,
+	`CREATE TABLE IF NOT EXISTS realm_data_keys (
+		realm_id VARCHAR(255) NOT NULL PRIMARY KEY,
+		wrapped_key VARBINARY(512) NOT NULL
+	)` + tableOptions,
+	// 7: projections and checkpoints
+	"CREATE TABLE IF NOT EXISTS projections (" + `
+		realm_id VARCHAR(255) NOT NULL,
+		projection_name VARCHAR(255) NOT NULL,
+		` + "`key`" + ` VARCHAR(255) NOT NULL,
+		value LONGTEXT,
+		PRIMARY KEY (realm_id, projection_name, ` + "`key`" + `)
+	)` + tableOptions,
+	`CREATE TABLE IF NOT EXISTS checkpoints (
+		realm_id VARCHAR(255) NOT NULL,
+		projector_name VARCHAR(255) NOT NULL,
+		last_global_position BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (realm_id, projector_name)
+	)` + tableOptions,
+	// 9: projector versions
+	`CREATE TABLE IF NOT EXISTS projector_versions (
+		projector_name VARCHAR(255) NOT NULL PRIMARY KEY,
+		version INT NOT NULL
+	)` + tableOptions,
+}
+
+// EnsureSchema applies the migrations the database has not had yet and
+// records them in schema_migrations. A named lock keeps servers starting at
+// the same time from running them twice.
+func EnsureSchema(db *sql.DB) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK('bifrost_schema', 60)`).Scan(&locked); err != nil {
+		return err
+	}
+	if locked.Int64 != 1 {
+		return fmt.Errorf("timed out waiting for the schema lock")
+	}
+	defer func() { _, _ = conn.ExecContext(ctx, `SELECT RELEASE_LOCK('bifrost_schema')`) }()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT NOT NULL PRIMARY KEY,
+		applied_at DATETIME(6) NOT NULL
+	)`+tableOptions); err != nil {
+		return err
+	}
+	var applied int
+	if err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		return err
+	}
+	for i := applied; i < len(migrations); i++ {
+		// MySQL commits DDL as it runs, so each migration is recorded
+		// straight after it rather than in a transaction with it.
+		if _, err := conn.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := conn.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			i+1, time.Now().UTC(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
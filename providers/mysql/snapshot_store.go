@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// GetSnapshot returns the stream's latest snapshot. Returns
+// core.NotFoundError if the stream has none.
+func (s *EventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	snapshot := core.Snapshot{RealmID: realmID, StreamID: streamID}
+	var state []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, state FROM snapshots WHERE realm_id = ? AND stream_id = ?`,
+		realmID, streamID,
+	).Scan(&snapshot.Version, &state)
+	if err == sql.ErrNoRows {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	if err != nil {
+		return core.Snapshot{}, err
+	}
+	snapshot.State = state
+	return snapshot, nil
+}
+
+// SaveSnapshot replaces the stream's snapshot.
+func (s *EventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO snapshots (realm_id, stream_id, version, state) VALUES (?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE version = VALUES(version), state = VALUES(state)`,
+		snapshot.RealmID, snapshot.StreamID, snapshot.Version, string(snapshot.State),
+	)
+	return err
+}
@@ -0,0 +1,8 @@
+// Package postgres provides PostgreSQL-backed projection and checkpoint
+// stores for Bifrost, so read models can be scaled apart from the event
+// store that appends to them. The schema is created and upgraded by
+// numbered migrations when a store is made.
+//
+// Open returns a database/sql handle on pgx's driver, which handles TLS
+// and the server's password methods as libpq does.
+package postgres
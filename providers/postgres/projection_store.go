@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// ProjectionStore is a PostgreSQL-backed implementation of core.ProjectionStore.
+type ProjectionStore struct {
+	db *sql.DB
+}
+
+// NewProjectionStore creates a new ProjectionStore backed by the given database.
+func NewProjectionStore(db *sql.DB) (*ProjectionStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &ProjectionStore{db: db}, nil
+}
+
+// Get retrieves a projection value by realm, projection name, and key.
+// Returns core.NotFoundError if no row is found.
+func (s *ProjectionStore) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	var value []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM projections WHERE realm_id = $1 AND projection_name = $2 AND key = $3`,
+		realmID, projectionName, key,
+	).Scan(&value)
+
+	if err == sql.ErrNoRows {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value, dest)
+}
+
+// List returns all projection values for the given realm and projection name.
+func (s *ProjectionStore) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT value FROM projections WHERE realm_id = $1 AND projection_name = $2`,
+		realmID, projectionName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		results = append(results, json.RawMessage(value))
+	}
+	return results, rows.Err()
+}
+
+// DumpProjections calls fn with each of the realm's projection rows, ordered
+// by projection name and key.
+func (s *ProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(core.ProjectionRow) error) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT projection_name, key, value FROM projections WHERE realm_id = $1 ORDER BY projection_name, key`,
+		realmID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row core.ProjectionRow
+		var value []byte
+		if err := rows.Scan(&row.ProjectionName, &row.Key, &value); err != nil {
+			return err
+		}
+		row.Value = json.RawMessage(value)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Put upserts a projection value for the given realm, projection name, and key.
+func (s *ProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data},
+	})
+}
+
+// Delete removes a projection entry. Deleting a non-existent key is not an error.
+func (s *ProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true},
+	})
+}
+
+// TruncateProjection deletes every entry of the projection in every realm.
+func (s *ProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM projections WHERE projection_name = $1`, projectionName)
+	return err
+}
+
+// TruncateRealmProjection deletes every entry of the projection in the
+// realm.
+func (s *ProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM projections WHERE realm_id = $1 AND projection_name = $2`,
+		realmID, projectionName,
+	)
+	return err
+}
+
+// WriteBatch applies a batch of puts and deletes in a single transaction.
+func (s *ProjectionStore) WriteBatch(ctx context.Context, writes []core.ProjectionWrite) error {
+	return inTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		for _, w := range writes {
+			if w.Delete {
+				_, err = tx.ExecContext(ctx,
+					`DELETE FROM projections WHERE realm_id = $1 AND projection_name = $2 AND key = $3`,
+					w.RealmID, w.ProjectionName, w.Key,
+				)
+			} else {
+				_, err = tx.ExecContext(ctx,
+					`INSERT INTO projections (realm_id, projection_name, key, value) VALUES ($1, $2, $3, $4)
+					 ON CONFLICT (realm_id, projection_name, key) DO UPDATE SET value = EXCLUDED.value`,
+					w.RealmID, w.ProjectionName, w.Key, string(w.Value),
+				)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
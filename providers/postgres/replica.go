@@ -0,0 +1,11 @@
+package postgres
+
+import "database/sql"
+
+// NewReplicaStores returns projection and checkpoint stores reading from
+// db, a hot standby of the database the other stores write to. The
+// primary's stores create the schema, so these do not; writing through them
+// fails on a standby.
+func NewReplicaStores(db *sql.DB) (*ProjectionStore, *CheckpointStore) {
+	return &ProjectionStore{db: db}, &CheckpointStore{db: db}
+}
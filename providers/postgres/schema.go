@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Open opens the database at dsn through pgx. The DSN is a URL such as
+// postgres://bifrost:secret@db:5432/bifrost?sslmode=require or libpq's
+// keyword=value form, and its sslmode and other parameters mean what they
+// do to libpq.
+func Open(dsn string) (*sql.DB, error) {
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return stdlib.OpenDB(*cfg), nil
+}
+
+// schemaLockID names the advisory lock held while migrating.
+const schemaLockID = 0x62696672 // "bifr"
+
+// migrations are applied in order, each once. Append new ones to the end and
+// never change one that has been released. Text columns collate as bytes, so
+// keys sort as they do in SQLite.
+var migrations = []string{
+	// 1: projections
+	`CREATE TABLE IF NOT EXISTS projections (
+		realm_id TEXT COLLATE "C" NOT NULL,
+		projection_name TEXT COLLATE "C" NOT NULL,
+		key TEXT COLLATE "C" NOT NULL,
+		value TEXT,
+		PRIMARY KEY (realm_id, projection_name, key)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_projections_name ON projections (projection_name)`,
+	// 3: checkpoints and projector versions
+	`CREATE TABLE IF NOT EXISTS checkpoints (
+		realm_id TEXT COLLATE "C" NOT NULL,
+		projector_name TEXT COLLATE "C" NOT NULL,
+		last_global_position BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (realm_id, projector_name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS projector_versions (
+		projector_name TEXT COLLATE "C" NOT NULL PRIMARY KEY,
+		version INT NOT NULL
+	)`,
+}
+
+// EnsureSchema applies the migrations the database has not had yet and
+// records them in schema_migrations. An advisory lock keeps servers starting
+// at the same time from running them twice.
+func EnsureSchema(db *sql.DB) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, schemaLockID); err != nil {
+		return err
+	}
+	defer func() { _, _ = conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, schemaLockID) }()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT NOT NULL PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`); err != nil {
+		return err
+	}
+	var applied int
+	if err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		return err
+	}
+	for i := applied; i < len(migrations); i++ {
+		// DDL is transactional, so a migration and its record are committed
+		// together.
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`,
+			i+1, time.Now().UTC(),
+		); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds.
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
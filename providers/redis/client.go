@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Client is the go-redis client a ProjectionStore writes through. It pools
+// its connections and is safe for concurrent use.
+type Client = goredis.Client
+
+// ParseURL reads options from a URL such as redis://:secret@cache:6379/2,
+// with rediss:// for TLS. The path names the database and defaults to 0.
+// Unlike go-redis, it refuses a URL without a host rather than connecting
+// to localhost.
+func ParseURL(raw string) (*goredis.Options, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	if (u.Scheme == "redis" || u.Scheme == "rediss") && u.Hostname() == "" {
+		return nil, errors.New("redis url has no host")
+	}
+	opts, err := goredis.ParseURL(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	return opts, nil
+}
+
+// NewClient returns a client for the server, checking that it answers.
+func NewClient(ctx context.Context, opts *goredis.Options) (*Client, error) {
+	client := goredis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
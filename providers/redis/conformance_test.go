@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/core/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ core.BatchProjectionStore = (*ProjectionStore)(nil)
+	_ core.ProjectionTruncator  = (*ProjectionStore)(nil)
+	_ core.ProjectionDumper     = (*ProjectionStore)(nil)
+)
+
+// --- Tests ---
+
+func TestProjectionStore_Conformance(t *testing.T) {
+	storetest.ProjectionStore(t, func(t *testing.T) core.ProjectionStore {
+		store, err := NewProjectionStore(context.Background(), newConformanceClient(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+// --- Test Context ---
+
+// newConformanceClient connects to the server at the URL in
+// BIFROST_TEST_REDIS_URL and flushes its database, skipping the test when
+// no URL is set. Point it at a scratch database.
+func newConformanceClient(t *testing.T) *Client {
+	t.Helper()
+	raw := os.Getenv("BIFROST_TEST_REDIS_URL")
+	if raw == "" {
+		t.Skip("BIFROST_TEST_REDIS_URL is not set")
+	}
+	opts, err := ParseURL(raw)
+	require.NoError(t, err)
+	client, err := NewClient(context.Background(), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	require.NoError(t, client.FlushDB(context.Background()).Err())
+	return client
+}
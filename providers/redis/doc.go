@@ -0,0 +1,13 @@
+// Package redis provides a Redis-backed projection store for Bifrost, for
+// deployments that want reads served from memory while events and
+// checkpoints stay in SQL.
+//
+// The server is reached through go-redis. Keys are written without a TTL:
+// Redis has to keep them, with persistence (AOF or RDB snapshots) if
+// projections should survive a restart without being rebuilt.
+//
+// Writes to Redis cannot join the SQL transaction that moves a projector's
+// checkpoint. A batch is written before its checkpoint, so a crash between
+// the two replays the batch on the next catch-up, and projectors writing
+// here must give the same entries when they see an event twice.
+package redis
@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ProjectionStore is a Redis-backed implementation of core.ProjectionStore.
+// Each realm's projection is a hash from the projection's keys to their JSON
+// values. Two sets per name record which hashes exist, so a projection can
+// be truncated and a realm dumped without scanning the keyspace.
+type ProjectionStore struct {
+	client *Client
+	prefix string
+}
+
+// Option configures a ProjectionStore.
+type Option func(*ProjectionStore)
+
+// WithKeyPrefix starts every key the store writes with prefix, so several
+// installations can share a database. It defaults to "bifrost:".
+func WithKeyPrefix(prefix string) Option {
+	return func(s *ProjectionStore) {
+		s.prefix = prefix
+	}
+}
+
+// NewProjectionStore creates a ProjectionStore writing through client. It
+// fails if the server would evict the store's keys to free memory, which
+// would lose projections silently; keys without a TTL are only safe under
+// noeviction or a volatile-* policy.
+func NewProjectionStore(ctx context.Context, client *Client, opts ...Option) (*ProjectionStore, error) {
+	s := &ProjectionStore{client: client, prefix: "bifrost:"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// Managed servers may disable CONFIG, leaving the policy to the
+	// operator.
+	config, err := client.ConfigGet(ctx, "maxmemory-policy").Result()
+	if policy := config["maxmemory-policy"]; err == nil && strings.HasPrefix(policy, "allkeys-") {
+		return nil, fmt.Errorf("redis maxmemory-policy %s evicts projections, use noeviction or a volatile-* policy", policy)
+	}
+	return s, nil
+}
+
+// Get retrieves a projection value by realm, projection name, and key.
+// Returns core.NotFoundError if no entry exists.
+func (s *ProjectionStore) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	value, err := s.client.HGet(ctx, s.hashKey(realmID, projectionName), key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value, dest)
+}
+
+// List returns all projection values for the given realm and projection name.
+func (s *ProjectionStore) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	values, err := s.client.HVals(ctx, s.hashKey(realmID, projectionName)).Result()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]json.RawMessage, 0, len(values))
+	for _, value := range values {
+		results = append(results, json.RawMessage(value))
+	}
+	return results, nil
+}
+
+// Put upserts a projection value for the given realm, projection name, and key.
+func (s *ProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data},
+	})
+}
+
+// Delete removes a projection entry. Deleting a non-existent key is not an error.
+func (s *ProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true},
+	})
+}
+
+// WriteBatch applies a batch of puts and deletes in a MULTI transaction, so
+// readers see all of it or none.
+func (s *ProjectionStore) WriteBatch(ctx context.Context, writes []core.ProjectionWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+	return s.transact(ctx, func(pipe goredis.Pipeliner) {
+		for _, w := range writes {
+			hash := s.hashKey(w.RealmID, w.ProjectionName)
+			if w.Delete {
+				pipe.HDel(ctx, hash, w.Key)
+				continue
+			}
+			pipe.HSet(ctx, hash, w.Key, []byte(w.Value))
+			pipe.SAdd(ctx, s.realmsKey(w.ProjectionName), w.RealmID)
+			pipe.SAdd(ctx, s.projectionsKey(w.RealmID), w.ProjectionName)
+		}
+	})
+}
+
+// DumpProjections calls fn with each of the realm's projection rows, ordered
+// by projection name and key.
+func (s *ProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(core.ProjectionRow) error) error {
+	names, err := s.client.SMembers(ctx, s.projectionsKey(realmID)).Result()
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fields, err := s.client.HGetAll(ctx, s.hashKey(realmID, name)).Result()
+		if err != nil {
+			return err
+		}
+		rows := make([]core.ProjectionRow, 0, len(fields))
+		for key, value := range fields {
+			rows = append(rows, core.ProjectionRow{ProjectionName: name, Key: key, Value: json.RawMessage(value)})
+		}
+		slices.SortFunc(rows, func(a, b core.ProjectionRow) int { return strings.Compare(a.Key, b.Key) })
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TruncateProjection deletes every entry of the projection in every realm.
+func (s *ProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	realmIDs, err := s.client.SMembers(ctx, s.realmsKey(projectionName)).Result()
+	if err != nil {
+		return err
+	}
+	return s.transact(ctx, func(pipe goredis.Pipeliner) {
+		for _, realmID := range realmIDs {
+			pipe.Del(ctx, s.hashKey(realmID, projectionName))
+			pipe.SRem(ctx, s.projectionsKey(realmID), projectionName)
+		}
+		pipe.Del(ctx, s.realmsKey(projectionName))
+	})
+}
+
+// TruncateRealmProjection deletes every entry of the projection in the
+// realm.
+func (s *ProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
+	return s.transact(ctx, func(pipe goredis.Pipeliner) {
+		pipe.Del(ctx, s.hashKey(realmID, projectionName))
+		pipe.SRem(ctx, s.realmsKey(projectionName), realmID)
+		pipe.SRem(ctx, s.projectionsKey(realmID), projectionName)
+	})
+}
+
+// transact runs the commands queued by fn between MULTI and EXEC in one
+// round trip, returning the first command's error.
+func (s *ProjectionStore) transact(ctx context.Context, fn func(pipe goredis.Pipeliner)) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		fn(pipe)
+		return nil
+	})
+	return err
+}
+
+// hashKey names the hash of a realm's projection. Names are escaped, so the
+// separators cannot occur in them.
+func (s *ProjectionStore) hashKey(realmID, projectionName string) string {
+	return s.prefix + "projection:" + url.QueryEscape(realmID) + ":" + url.QueryEscape(projectionName)
+}
+
+// realmsKey names the set of realms holding entries of the projection.
+func (s *ProjectionStore) realmsKey(projectionName string) string {
+	return s.prefix + "realms:" + url.QueryEscape(projectionName)
+}
+
+// projectionsKey names the set of projections with entries in the realm.
+func (s *ProjectionStore) projectionsKey(realmID string) string {
+	return s.prefix + "projections:" + url.QueryEscape(realmID)
+}
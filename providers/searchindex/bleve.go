@@ -0,0 +1,121 @@
+package searchindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Bleve implements core.SearchIndex with an embedded Bleve index.
+type Bleve struct {
+	index bleve.Index
+}
+
+var _ core.SearchIndex = (*Bleve)(nil)
+
+// OpenBleve opens the Bleve index in dir, creating it if it does not exist.
+func OpenBleve(dir string) (*Bleve, error) {
+	index, err := bleve.Open(dir)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(dir, bleveMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open bleve index %s: %w", dir, err)
+	}
+	return &Bleve{index: index}, nil
+}
+
+// NewMemoryBleve creates a Bleve index held in memory, lost on Close.
+func NewMemoryBleve() (*Bleve, error) {
+	index, err := bleve.NewMemOnly(bleveMapping())
+	if err != nil {
+		return nil, fmt.Errorf("create bleve index: %w", err)
+	}
+	return &Bleve{index: index}, nil
+}
+
+// bleveMapping matches realm and status exactly and stems English text.
+func bleveMapping() mapping.IndexMapping {
+	exact := bleve.NewTextFieldMapping()
+	exact.Analyzer = keyword.Name
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = en.AnalyzerName
+	text.Store = false
+
+	doc := bleve.NewDocumentMapping()
+	doc.AddFieldMappingsAt("realm_id", exact)
+	doc.AddFieldMappingsAt("status", exact)
+	doc.AddFieldMappingsAt("title", text)
+	doc.AddFieldMappingsAt("description", text)
+	doc.AddFieldMappingsAt("notes", text)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = en.AnalyzerName
+	return m
+}
+
+func (b *Bleve) Index(_ context.Context, doc core.SearchDocument) error {
+	return b.index.Index(documentID(doc.RealmID, doc.ID), documentFields(doc))
+}
+
+func (b *Bleve) Delete(_ context.Context, realmID, runeID string) error {
+	return b.index.Delete(documentID(realmID, runeID))
+}
+
+func (b *Bleve) Search(ctx context.Context, q core.SearchQuery) ([]core.SearchHit, error) {
+	realm := bleve.NewTermQuery(q.RealmID)
+	realm.SetField("realm_id")
+	must := []query.Query{realm, textQuery(q.Text)}
+	if len(q.Statuses) > 0 {
+		statuses := make([]query.Query, 0, len(q.Statuses))
+		for _, status := range q.Statuses {
+			term := bleve.NewTermQuery(status)
+			term.SetField("status")
+			statuses = append(statuses, term)
+		}
+		must = append(must, bleve.NewDisjunctionQuery(statuses...))
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), searchLimit(q.Limit), 0, false)
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("search bleve index: %w", err)
+	}
+	hits := make([]core.SearchHit, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		hits = append(hits, core.SearchHit{ID: strings.TrimPrefix(hit.ID, q.RealmID+"/"), Score: hit.Score})
+	}
+	return hits, nil
+}
+
+// textQuery matches any of the words in title, description or notes,
+// ranking title matches highest.
+func textQuery(text string) query.Query {
+	fields := map[string]float64{"title": 3, "description": 1, "notes": 1}
+	matches := make([]query.Query, 0, len(fields))
+	for field, boost := range fields {
+		match := bleve.NewMatchQuery(text)
+		match.SetField(field)
+		match.SetBoost(boost)
+		matches = append(matches, match)
+	}
+	return bleve.NewDisjunctionQuery(matches...)
+}
+
+func (b *Bleve) Count(_ context.Context) (int, error) {
+	n, err := b.index.DocCount()
+	return int(n), err
+}
+
+func (b *Bleve) Close() error {
+	return b.index.Close()
+}
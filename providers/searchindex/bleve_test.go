@@ -0,0 +1,178 @@
+package searchindex
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestBleve(t *testing.T) {
+	t.Run("finds runes by stemmed words in title, description and notes", func(t *testing.T) {
+		tc := newBleveTestContext(t)
+
+		// Given
+		tc.an_index()
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Fix login redirect", Status: "open"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a2", Title: "Billing", Description: "Redirects after payment fail", Status: "open"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a3", Title: "Docs", Notes: []string{"needs a redirect section"}, Status: "open"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a4", Title: "Unrelated", Status: "open"})
+
+		// When
+		tc.search(core.SearchQuery{RealmID: "bf-r1", Text: "redirecting"})
+
+		// Then
+		tc.no_error()
+		tc.hit_ids_are("bf-a1", "bf-a2", "bf-a3")
+		assert.Equal(t, "bf-a1", tc.hits[0].ID, "title matches rank first")
+	})
+
+	t.Run("only returns runes of the queried realm", func(t *testing.T) {
+		tc := newBleveTestContext(t)
+
+		// Given
+		tc.an_index()
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Deploy pipeline"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r2", ID: "bf-a1", Title: "Deploy pipeline"})
+
+		// When
+		tc.search(core.SearchQuery{RealmID: "bf-r2", Text: "deploy"})
+
+		// Then
+		tc.no_error()
+		tc.hit_ids_are("bf-a1")
+		tc.count_is(2)
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		tc := newBleveTestContext(t)
+
+		// Given
+		tc.an_index()
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Cache warmup", Status: "open"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a2", Title: "Cache eviction", Status: "sealed"})
+
+		// When
+		tc.search(core.SearchQuery{RealmID: "bf-r1", Text: "cache", Statuses: []string{"sealed"}})
+
+		// Then
+		tc.no_error()
+		tc.hit_ids_are("bf-a2")
+	})
+
+	t.Run("replaces and deletes documents", func(t *testing.T) {
+		tc := newBleveTestContext(t)
+
+		// Given
+		tc.an_index()
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Old title"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "New title"})
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a2", Title: "New feature"})
+
+		// When
+		require.NoError(t, tc.index.Delete(tc.ctx, "bf-r1", "bf-a2"))
+		require.NoError(t, tc.index.Delete(tc.ctx, "bf-r1", "bf-missing"))
+		tc.search(core.SearchQuery{RealmID: "bf-r1", Text: "old new"})
+
+		// Then
+		tc.no_error()
+		tc.hit_ids_are("bf-a1")
+		tc.count_is(1)
+	})
+
+	t.Run("reopens an index from disk", func(t *testing.T) {
+		tc := newBleveTestContext(t)
+
+		// Given
+		dir := filepath.Join(t.TempDir(), "search")
+		tc.an_index_in(dir)
+		tc.rune_is_indexed(core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Persisted"})
+		require.NoError(t, tc.index.Close())
+
+		// When
+		tc.an_index_in(dir)
+		tc.search(core.SearchQuery{RealmID: "bf-r1", Text: "persisted"})
+
+		// Then
+		tc.no_error()
+		tc.hit_ids_are("bf-a1")
+	})
+}
+
+// --- Test Context ---
+
+type bleveTestContext struct {
+	t   *testing.T
+	ctx context.Context
+
+	index *Bleve
+	hits  []core.SearchHit
+	err   error
+}
+
+func newBleveTestContext(t *testing.T) *bleveTestContext {
+	t.Helper()
+	tc := &bleveTestContext{t: t, ctx: context.Background()}
+	t.Cleanup(func() {
+		if tc.index != nil {
+			_ = tc.index.Close()
+		}
+	})
+	return tc
+}
+
+// --- Given ---
+
+func (tc *bleveTestContext) an_index() {
+	tc.t.Helper()
+	index, err := NewMemoryBleve()
+	require.NoError(tc.t, err)
+	tc.index = index
+}
+
+func (tc *bleveTestContext) an_index_in(dir string) {
+	tc.t.Helper()
+	index, err := OpenBleve(dir)
+	require.NoError(tc.t, err)
+	tc.index = index
+}
+
+func (tc *bleveTestContext) rune_is_indexed(doc core.SearchDocument) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.index.Index(tc.ctx, doc))
+}
+
+// --- When ---
+
+func (tc *bleveTestContext) search(q core.SearchQuery) {
+	tc.t.Helper()
+	tc.hits, tc.err = tc.index.Search(tc.ctx, q)
+}
+
+// --- Then ---
+
+func (tc *bleveTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *bleveTestContext) hit_ids_are(ids ...string) {
+	tc.t.Helper()
+	got := make([]string, 0, len(tc.hits))
+	for _, hit := range tc.hits {
+		got = append(got, hit.ID)
+	}
+	assert.ElementsMatch(tc.t, ids, got)
+}
+
+func (tc *bleveTestContext) count_is(expected int) {
+	tc.t.Helper()
+	n, err := tc.index.Count(tc.ctx)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, n)
+}
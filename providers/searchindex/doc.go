@@ -0,0 +1,7 @@
+// Package searchindex implements core.SearchIndex with an embedded Bleve
+// index, or with an Elasticsearch or OpenSearch cluster for deployments
+// whose rune volume outgrows a single node's index.
+//
+// Elasticsearch and OpenSearch are reached through the REST API they share,
+// so no client SDK is required.
+package searchindex
@@ -0,0 +1,33 @@
+package searchindex
+
+import "github.com/devzeebo/bifrost/core"
+
+// defaultLimit caps a search that does not set SearchQuery.Limit.
+const defaultLimit = 50
+
+// documentID keys a rune by realm, since rune IDs are only unique within one.
+func documentID(realmID, runeID string) string {
+	return realmID + "/" + runeID
+}
+
+func documentFields(doc core.SearchDocument) map[string]any {
+	notes := doc.Notes
+	if notes == nil {
+		notes = []string{}
+	}
+	return map[string]any{
+		"realm_id":    doc.RealmID,
+		"rune_id":     doc.ID,
+		"title":       doc.Title,
+		"description": doc.Description,
+		"notes":       notes,
+		"status":      doc.Status,
+	}
+}
+
+func searchLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return limit
+}
@@ -0,0 +1,218 @@
+package searchindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// ElasticsearchConfig configures an Elasticsearch or OpenSearch cluster.
+type ElasticsearchConfig struct {
+	// URL is the cluster endpoint, e.g. https://search.example.com:9200.
+	URL string
+	// Index names the index runes are stored in. Defaults to bifrost-runes.
+	Index string
+	// APIKey is sent as an ApiKey authorization; Username and Password as
+	// basic auth when no key is set.
+	APIKey   string
+	Username string
+	Password string
+}
+
+// Elasticsearch implements core.SearchIndex against an Elasticsearch or
+// OpenSearch index holding the runes of every realm.
+type Elasticsearch struct {
+	client   *http.Client
+	base     string
+	index    string
+	apiKey   string
+	username string
+	password string
+}
+
+var _ core.SearchIndex = (*Elasticsearch)(nil)
+
+// indexMapping matches realm and status exactly and stems English text.
+var indexMapping = map[string]any{
+	"mappings": map[string]any{
+		"properties": map[string]any{
+			"realm_id":    map[string]any{"type": "keyword"},
+			"rune_id":     map[string]any{"type": "keyword"},
+			"status":      map[string]any{"type": "keyword"},
+			"title":       map[string]any{"type": "text", "analyzer": "english"},
+			"description": map[string]any{"type": "text", "analyzer": "english"},
+			"notes":       map[string]any{"type": "text", "analyzer": "english"},
+		},
+	},
+}
+
+// NewElasticsearch connects to the cluster and creates the index if it does
+// not exist.
+func NewElasticsearch(ctx context.Context, cfg ElasticsearchConfig, client *http.Client) (*Elasticsearch, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("elasticsearch URL must be absolute, got %q", cfg.URL)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	index := cfg.Index
+	if index == "" {
+		index = "bifrost-runes"
+	}
+	es := &Elasticsearch{
+		client:   client,
+		base:     strings.TrimSuffix(cfg.URL, "/"),
+		index:    index,
+		apiKey:   cfg.APIKey,
+		username: cfg.Username,
+		password: cfg.Password,
+	}
+
+	status, err := es.do(ctx, http.MethodHead, "/"+url.PathEscape(index), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		if _, err := es.expect(ctx, http.MethodPut, "/"+url.PathEscape(index), indexMapping, nil); err != nil {
+			return nil, fmt.Errorf("create index %s: %w", index, err)
+		}
+	}
+	return es, nil
+}
+
+func (es *Elasticsearch) Index(ctx context.Context, doc core.SearchDocument) error {
+	_, err := es.expect(ctx, http.MethodPut, es.docPath(doc.RealmID, doc.ID), documentFields(doc), nil)
+	return err
+}
+
+func (es *Elasticsearch) Delete(ctx context.Context, realmID, runeID string) error {
+	status, err := es.do(ctx, http.MethodDelete, es.docPath(realmID, runeID), nil, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNotFound && !success(status) {
+		return fmt.Errorf("elasticsearch DELETE returned %d", status)
+	}
+	return nil
+}
+
+func (es *Elasticsearch) Search(ctx context.Context, q core.SearchQuery) ([]core.SearchHit, error) {
+	filter := []any{map[string]any{"term": map[string]any{"realm_id": q.RealmID}}}
+	if len(q.Statuses) > 0 {
+		filter = append(filter, map[string]any{"terms": map[string]any{"status": q.Statuses}})
+	}
+	body := map[string]any{
+		"size":    searchLimit(q.Limit),
+		"_source": []string{"rune_id"},
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": filter,
+				"must": map[string]any{
+					"multi_match": map[string]any{
+						"query":  q.Text,
+						"fields": []string{"title^3", "description", "notes"},
+					},
+				},
+			},
+		},
+	}
+
+	var res struct {
+		Hits struct {
+			Hits []struct {
+				Score  float64 `json:"_score"`
+				Source struct {
+					RuneID string `json:"rune_id"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if _, err := es.expect(ctx, http.MethodPost, "/"+url.PathEscape(es.index)+"/_search", body, &res); err != nil {
+		return nil, err
+	}
+	hits := make([]core.SearchHit, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		hits = append(hits, core.SearchHit{ID: hit.Source.RuneID, Score: hit.Score})
+	}
+	return hits, nil
+}
+
+func (es *Elasticsearch) Count(ctx context.Context) (int, error) {
+	var res struct {
+		Count int `json:"count"`
+	}
+	if _, err := es.expect(ctx, http.MethodGet, "/"+url.PathEscape(es.index)+"/_count", nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Close does nothing; the cluster outlives the server.
+func (es *Elasticsearch) Close() error {
+	return nil
+}
+
+func (es *Elasticsearch) docPath(realmID, runeID string) string {
+	return "/" + url.PathEscape(es.index) + "/_doc/" + url.PathEscape(documentID(realmID, runeID))
+}
+
+// expect sends a request that must succeed, decoding the response into out
+// when it is not nil.
+func (es *Elasticsearch) expect(ctx context.Context, method, path string, body, out any) (int, error) {
+	status, err := es.do(ctx, method, path, body, out)
+	if err != nil {
+		return status, err
+	}
+	if !success(status) {
+		return status, fmt.Errorf("elasticsearch %s %s returned %d", method, path, status)
+	}
+	return status, nil
+}
+
+func (es *Elasticsearch) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, es.base+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case es.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+es.apiKey)
+	case es.username != "":
+		req.SetBasicAuth(es.username, es.password)
+	}
+
+	resp, err := es.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("elasticsearch %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil && success(resp.StatusCode) {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("decode elasticsearch response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func success(status int) bool {
+	return status >= 200 && status < 300
+}
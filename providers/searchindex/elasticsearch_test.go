@@ -0,0 +1,184 @@
+package searchindex
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestElasticsearch(t *testing.T) {
+	t.Run("creates a missing index with keyword realm and status fields", func(t *testing.T) {
+		tc := newElasticsearchTestContext(t)
+
+		// Given
+		tc.a_cluster(false)
+
+		// When
+		tc.connect(ElasticsearchConfig{APIKey: "k3y"})
+
+		// Then
+		tc.no_error()
+		put := tc.request(http.MethodPut, "/bifrost-runes")
+		assert.Contains(t, put.body, `"realm_id":{"type":"keyword"}`)
+		assert.Equal(t, "ApiKey k3y", put.auth)
+	})
+
+	t.Run("leaves an existing index alone", func(t *testing.T) {
+		tc := newElasticsearchTestContext(t)
+
+		// Given
+		tc.a_cluster(true)
+
+		// When
+		tc.connect(ElasticsearchConfig{Index: "runes"})
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.requests, 1)
+		assert.Equal(t, "/runes", tc.requests[0].path)
+	})
+
+	t.Run("indexes documents by realm and rune ID", func(t *testing.T) {
+		tc := newElasticsearchTestContext(t)
+
+		// Given
+		tc.a_cluster(true)
+		tc.connect(ElasticsearchConfig{Username: "bifrost", Password: "pw"})
+
+		// When
+		tc.err = tc.es.Index(context.Background(), core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1", Title: "Fix login"})
+
+		// Then
+		tc.no_error()
+		put := tc.request(http.MethodPut, "/bifrost-runes/_doc/bf-r1%2Fbf-a1")
+		assert.Contains(t, put.body, `"title":"Fix login"`)
+		assert.Contains(t, put.auth, "Basic ")
+	})
+
+	t.Run("searches within the realm and statuses", func(t *testing.T) {
+		tc := newElasticsearchTestContext(t)
+
+		// Given
+		tc.a_cluster(true)
+		tc.searchResponse = `{"hits":{"hits":[{"_score":2.5,"_source":{"rune_id":"bf-a1"}}]}}`
+		tc.connect(ElasticsearchConfig{})
+
+		// When
+		hits, err := tc.es.Search(context.Background(), core.SearchQuery{RealmID: "bf-r1", Text: "login", Statuses: []string{"open"}, Limit: 5})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []core.SearchHit{{ID: "bf-a1", Score: 2.5}}, hits)
+		search := tc.request(http.MethodPost, "/bifrost-runes/_search")
+		assert.Contains(t, search.body, `{"term":{"realm_id":"bf-r1"}}`)
+		assert.Contains(t, search.body, `{"terms":{"status":["open"]}}`)
+		assert.Contains(t, search.body, `"size":5`)
+	})
+
+	t.Run("ignores deleting a missing document", func(t *testing.T) {
+		tc := newElasticsearchTestContext(t)
+
+		// Given
+		tc.a_cluster(true)
+		tc.connect(ElasticsearchConfig{})
+
+		// When
+		tc.err = tc.es.Delete(context.Background(), "bf-r1", "bf-missing")
+
+		// Then
+		tc.no_error()
+	})
+
+	t.Run("rejects relative URLs", func(t *testing.T) {
+		// When
+		_, err := NewElasticsearch(context.Background(), ElasticsearchConfig{URL: "search:9200"}, nil)
+
+		// Then
+		assert.ErrorContains(t, err, "must be absolute")
+	})
+}
+
+// --- Test Context ---
+
+type receivedRequest struct {
+	method string
+	path   string
+	body   string
+	auth   string
+}
+
+type elasticsearchTestContext struct {
+	t *testing.T
+
+	server         *httptest.Server
+	indexExists    bool
+	searchResponse string
+	requests       []receivedRequest
+
+	es  *Elasticsearch
+	err error
+}
+
+func newElasticsearchTestContext(t *testing.T) *elasticsearchTestContext {
+	t.Helper()
+	return &elasticsearchTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *elasticsearchTestContext) a_cluster(indexExists bool) {
+	tc.t.Helper()
+	tc.indexExists = indexExists
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		tc.requests = append(tc.requests, receivedRequest{
+			method: r.Method, path: r.URL.EscapedPath(), body: string(body), auth: r.Header.Get("Authorization"),
+		})
+		switch {
+		case r.Method == http.MethodHead && !tc.indexExists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(tc.searchResponse))
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"acknowledged": true})
+		}
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+// --- When ---
+
+func (tc *elasticsearchTestContext) connect(cfg ElasticsearchConfig) {
+	tc.t.Helper()
+	cfg.URL = tc.server.URL
+	tc.es, tc.err = NewElasticsearch(context.Background(), cfg, tc.server.Client())
+}
+
+// --- Then ---
+
+func (tc *elasticsearchTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *elasticsearchTestContext) request(method, path string) receivedRequest {
+	tc.t.Helper()
+	for _, r := range tc.requests {
+		if r.method == method && r.path == path {
+			return r
+		}
+	}
+	require.Failf(tc.t, "request not sent", "%s %s", method, path)
+	return receivedRequest{}
+}
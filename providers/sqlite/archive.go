@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// eventColumns lists the events table's columns, which archived_events
+// shares so rows move between them unchanged.
+const eventColumns = `global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp`
+
+// ArchiveStreams moves the events of every stream whose last event is of
+// type lastEventType and was appended before the given time from the events
+// table to archived_events. It returns how many streams were moved.
+func (s *EventStore) ArchiveStreams(ctx context.Context, lastEventType string, before time.Time) (int, error) {
+	archived := 0
+	err := s.db.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`SELECT realm_id, stream_id FROM events e
+			 WHERE event_type = ? AND timestamp < ?
+			   AND version = (SELECT MAX(version) FROM events WHERE realm_id = e.realm_id AND stream_id = e.stream_id)`,
+			lastEventType, before.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+		var streams [][2]string
+		for rows.Next() {
+			var realmID, streamID string
+			if err := rows.Scan(&realmID, &streamID); err != nil {
+				rows.Close()
+				return err
+			}
+			streams = append(streams, [2]string{realmID, streamID})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, stream := range streams {
+			if _, err := moveStream(ctx, tx, "events", "archived_events", stream[0], stream[1]); err != nil {
+				return err
+			}
+		}
+		archived = len(streams)
+		return nil
+	})
+	return archived, err
+}
+
+// rehydrate moves an archived stream's events back into the events table,
+// keeping their versions and global positions, and reports whether there
+// were any.
+func rehydrate(ctx context.Context, tx *sql.Tx, realmID, streamID string) (bool, error) {
+	moved, err := moveStream(ctx, tx, "archived_events", "events", realmID, streamID)
+	return moved > 0, err
+}
+
+// isArchived reports whether the stream has archived events.
+func (s *EventStore) isArchived(ctx context.Context, realmID, streamID string) (bool, error) {
+	var archived bool
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM archived_events WHERE realm_id = ? AND stream_id = ?)`,
+		realmID, streamID,
+	).Scan(&archived)
+	return archived, err
+}
+
+// moveStream moves a stream's events from one of the two event tables to
+// the other and returns how many there were.
+func moveStream(ctx context.Context, tx *sql.Tx, from, to, realmID, streamID string) (int64, error) {
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO `+to+` (`+eventColumns+`)
+		 SELECT `+eventColumns+` FROM `+from+` WHERE realm_id = ? AND stream_id = ?`,
+		realmID, streamID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := res.RowsAffected()
+	if err != nil || moved == 0 {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM `+from+` WHERE realm_id = ? AND stream_id = ?`, realmID, streamID)
+	return moved, err
+}
@@ -7,7 +7,7 @@ import (
 
 // CheckpointStore is a SQLite-backed implementation of core.CheckpointStore.
 type CheckpointStore struct {
-	db *sql.DB
+	db conn
 }
 
 // NewCheckpointStore creates a new CheckpointStore backed by the given database.
@@ -15,7 +15,7 @@ func NewCheckpointStore(db *sql.DB) (*CheckpointStore, error) {
 	if err := EnsureSchema(db); err != nil {
 		return nil, err
 	}
-	return &CheckpointStore{db: db}, nil
+	return &CheckpointStore{db: conn{db: db}}, nil
 }
 
 // GetCheckpoint returns the last global position for the given projector.
@@ -44,3 +44,50 @@ func (s *CheckpointStore) SetCheckpoint(ctx context.Context, realmID string, pro
 	)
 	return err
 }
+
+// GetProjectorVersion returns the version recorded for the projector, or 0
+// if none is.
+func (s *CheckpointStore) GetProjectorVersion(ctx context.Context, projectorName string) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version FROM projector_versions WHERE projector_name = ?`,
+		projectorName,
+	).Scan(&version)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, err
+}
+
+// SetProjectorVersion upserts the version recorded for the projector.
+func (s *CheckpointStore) SetProjectorVersion(ctx context.Context, projectorName string, version int) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO projector_versions (projector_name, version) VALUES (?, ?)`,
+		projectorName, version,
+	)
+	return err
+}
+
+// ListCheckpoints returns every projector's checkpoint in the realm.
+func (s *CheckpointStore) ListCheckpoints(ctx context.Context, realmID string) (map[string]int64, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT projector_name, last_global_position FROM checkpoints WHERE realm_id = ?`,
+		realmID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var pos int64
+		if err := rows.Scan(&name, &pos); err != nil {
+			return nil, err
+		}
+		checkpoints[name] = pos
+	}
+	return checkpoints, rows.Err()
+}
@@ -13,6 +13,8 @@ import (
 
 // Compile-time interface satisfaction check
 var _ core.CheckpointStore = (*CheckpointStore)(nil)
+var _ core.CheckpointLister = (*CheckpointStore)(nil)
+var _ core.ProjectorVersionStore = (*CheckpointStore)(nil)
 
 // --- Tests ---
 
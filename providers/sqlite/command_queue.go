@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+const commandQueueColumns = `id, realm_id, account_id, role, command, payload, headers, status, status_code, result, position, enqueued_at, updated_at`
+
+// CommandQueue is a SQLite-backed implementation of core.CommandQueue.
+type CommandQueue struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewCommandQueue creates a new CommandQueue backed by the given database.
+// Commands left running by a process that stopped mid-command are returned
+// to pending, so they are processed at least once.
+func NewCommandQueue(db *sql.DB) (*CommandQueue, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`UPDATE command_queue SET status = ? WHERE status = ?`, core.CommandPending, core.CommandRunning); err != nil {
+		return nil, err
+	}
+	return &CommandQueue{db: db, now: time.Now}, nil
+}
+
+func (q *CommandQueue) Enqueue(ctx context.Context, cmd core.QueuedCommand) error {
+	var headers any
+	if len(cmd.Headers) > 0 {
+		raw, err := json.Marshal(cmd.Headers)
+		if err != nil {
+			return err
+		}
+		headers = string(raw)
+	}
+	now := q.now().UnixNano()
+	_, err := q.db.ExecContext(ctx,
+		`INSERT INTO command_queue (id, realm_id, account_id, role, command, payload, headers, status, enqueued_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		cmd.ID, cmd.RealmID, cmd.AccountID, cmd.Role, cmd.Command, nullableJSON(cmd.Payload), headers, core.CommandPending, now, now,
+	)
+	return err
+}
+
+func (q *CommandQueue) Claim(ctx context.Context) (core.QueuedCommand, bool, error) {
+	row := q.db.QueryRowContext(ctx,
+		`UPDATE command_queue SET status = ?, updated_at = ?
+		WHERE seq = (SELECT seq FROM command_queue WHERE status = ? ORDER BY seq LIMIT 1)
+		RETURNING `+commandQueueColumns,
+		core.CommandRunning, q.now().UnixNano(), core.CommandPending,
+	)
+	cmd, err := scanQueuedCommand(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.QueuedCommand{}, false, nil
+	}
+	if err != nil {
+		return core.QueuedCommand{}, false, err
+	}
+	return cmd, true, nil
+}
+
+func (q *CommandQueue) Complete(ctx context.Context, id string, status string, statusCode int, position int64, result json.RawMessage) error {
+	res, err := q.db.ExecContext(ctx,
+		`UPDATE command_queue SET status = ?, status_code = ?, result = ?, position = ?, updated_at = ? WHERE id = ?`,
+		status, statusCode, nullableJSON(result), position, q.now().UnixNano(), id,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &core.NotFoundError{Entity: "command", ID: id}
+	}
+	return nil
+}
+
+func (q *CommandQueue) GetCommand(ctx context.Context, id string) (core.QueuedCommand, error) {
+	row := q.db.QueryRowContext(ctx,
+		`SELECT `+commandQueueColumns+` FROM command_queue WHERE id = ?`,
+		id,
+	)
+	cmd, err := scanQueuedCommand(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.QueuedCommand{}, &core.NotFoundError{Entity: "command", ID: id}
+	}
+	return cmd, err
+}
+
+func (q *CommandQueue) Pending(ctx context.Context) (int, error) {
+	var n int
+	err := q.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM command_queue WHERE status IN (?, ?)`,
+		core.CommandPending, core.CommandRunning,
+	).Scan(&n)
+	return n, err
+}
+
+func scanQueuedCommand(row *sql.Row) (core.QueuedCommand, error) {
+	var (
+		cmd                   core.QueuedCommand
+		accountID, role       sql.NullString
+		payload, headers      sql.NullString
+		result                sql.NullString
+		enqueuedAt, updatedAt int64
+	)
+	err := row.Scan(&cmd.ID, &cmd.RealmID, &accountID, &role, &cmd.Command, &payload, &headers,
+		&cmd.Status, &cmd.StatusCode, &result, &cmd.Position, &enqueuedAt, &updatedAt)
+	if err != nil {
+		return core.QueuedCommand{}, err
+	}
+	cmd.AccountID = accountID.String
+	cmd.Role = role.String
+	if payload.Valid {
+		cmd.Payload = json.RawMessage(payload.String)
+	}
+	if headers.Valid {
+		if err := json.Unmarshal([]byte(headers.String), &cmd.Headers); err != nil {
+			return core.QueuedCommand{}, err
+		}
+	}
+	if result.Valid {
+		cmd.Result = json.RawMessage(result.String)
+	}
+	cmd.EnqueuedAt = time.Unix(0, enqueuedAt).UTC()
+	cmd.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return cmd, nil
+}
+
+func nullableJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
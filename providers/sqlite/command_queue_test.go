@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.CommandQueue = (*CommandQueue)(nil)
+
+// --- Tests ---
+
+func TestCommandQueue_Claim(t *testing.T) {
+	t.Run("claims pending commands oldest first", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.command_is_enqueued("cmd-1")
+		tc.command_is_enqueued("cmd-2")
+
+		// When
+		tc.claim_is_called()
+
+		// Then
+		tc.claimed_command_is("cmd-1")
+		tc.command_has_status("cmd-1", core.CommandRunning)
+		tc.command_has_status("cmd-2", core.CommandPending)
+	})
+
+	t.Run("returns false when nothing is pending", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// When
+		tc.claim_is_called()
+
+		// Then
+		tc.nothing_was_claimed()
+	})
+
+	t.Run("keeps the command payload and identity", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.command_is_enqueued("cmd-1")
+
+		// When
+		tc.claim_is_called()
+
+		// Then
+		assert.Equal(t, "realm-1", tc.claimed.RealmID)
+		assert.Equal(t, "acct-1", tc.claimed.AccountID)
+		assert.Equal(t, "member", tc.claimed.Role)
+		assert.Equal(t, "create-rune", tc.claimed.Command)
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(tc.claimed.Payload))
+		assert.Equal(t, map[string]string{"Idempotency-Key": "req-1"}, tc.claimed.Headers)
+	})
+}
+
+func TestCommandQueue_Complete(t *testing.T) {
+	t.Run("records the outcome", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.command_is_enqueued("cmd-1")
+		tc.claim_is_called()
+
+		// When
+		tc.complete_is_called("cmd-1", core.CommandSucceeded, 201, 42, `{"id":"bf-1"}`)
+
+		// Then
+		tc.command_has_status("cmd-1", core.CommandSucceeded)
+		assert.Equal(t, 201, tc.fetched.StatusCode)
+		assert.Equal(t, int64(42), tc.fetched.Position)
+		assert.JSONEq(t, `{"id":"bf-1"}`, string(tc.fetched.Result))
+		tc.pending_count_is(0)
+	})
+
+	t.Run("returns not found for an unknown command", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// When
+		err := tc.queue.Complete(context.Background(), "missing", core.CommandFailed, 500, 0, nil)
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+}
+
+func TestCommandQueue_Pending(t *testing.T) {
+	t.Run("counts pending and running commands", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.command_is_enqueued("cmd-1")
+		tc.command_is_enqueued("cmd-2")
+		tc.claim_is_called()
+
+		// Then
+		tc.pending_count_is(2)
+	})
+}
+
+func TestNewCommandQueue(t *testing.T) {
+	t.Run("returns interrupted commands to pending", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.command_is_enqueued("cmd-1")
+		tc.claim_is_called()
+
+		// When
+		tc.queue_is_reopened()
+
+		// Then
+		tc.command_has_status("cmd-1", core.CommandPending)
+	})
+
+	t.Run("adds the headers column to a queue created without it", func(t *testing.T) {
+		tc := newCommandQueueTestContext(t)
+
+		// Given
+		tc.queue_table_predates_headers()
+
+		// When
+		tc.queue_is_reopened()
+		tc.command_is_enqueued("cmd-1")
+		tc.claim_is_called()
+
+		// Then
+		assert.Equal(t, map[string]string{"Idempotency-Key": "req-1"}, tc.claimed.Headers)
+	})
+}
+
+// --- Test Context ---
+
+type commandQueueTestContext struct {
+	t     *testing.T
+	db    *sql.DB
+	queue *CommandQueue
+
+	claimed core.QueuedCommand
+	ok      bool
+	fetched core.QueuedCommand
+}
+
+func newCommandQueueTestContext(t *testing.T) *commandQueueTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	queue, err := NewCommandQueue(db)
+	require.NoError(t, err)
+	return &commandQueueTestContext{t: t, db: db, queue: queue}
+}
+
+// --- Given ---
+
+func (tc *commandQueueTestContext) command_is_enqueued(id string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.queue.Enqueue(context.Background(), core.QueuedCommand{
+		ID:        id,
+		RealmID:   "realm-1",
+		AccountID: "acct-1",
+		Role:      "member",
+		Command:   "create-rune",
+		Payload:   json.RawMessage(`{"title":"Bridge"}`),
+		Headers:   map[string]string{"Idempotency-Key": "req-1"},
+	}))
+}
+
+func (tc *commandQueueTestContext) queue_table_predates_headers() {
+	tc.t.Helper()
+	_, err := tc.db.Exec(`ALTER TABLE command_queue DROP COLUMN headers`)
+	require.NoError(tc.t, err)
+}
+
+// --- When ---
+
+func (tc *commandQueueTestContext) claim_is_called() {
+	tc.t.Helper()
+	var err error
+	tc.claimed, tc.ok, err = tc.queue.Claim(context.Background())
+	require.NoError(tc.t, err)
+}
+
+func (tc *commandQueueTestContext) complete_is_called(id, status string, statusCode int, position int64, result string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.queue.Complete(context.Background(), id, status, statusCode, position, json.RawMessage(result)))
+}
+
+func (tc *commandQueueTestContext) queue_is_reopened() {
+	tc.t.Helper()
+	var err error
+	tc.queue, err = NewCommandQueue(tc.db)
+	require.NoError(tc.t, err)
+}
+
+// --- Then ---
+
+func (tc *commandQueueTestContext) claimed_command_is(id string) {
+	tc.t.Helper()
+	require.True(tc.t, tc.ok)
+	assert.Equal(tc.t, id, tc.claimed.ID)
+	assert.Equal(tc.t, core.CommandRunning, tc.claimed.Status)
+}
+
+func (tc *commandQueueTestContext) nothing_was_claimed() {
+	tc.t.Helper()
+	assert.False(tc.t, tc.ok)
+}
+
+func (tc *commandQueueTestContext) command_has_status(id, status string) {
+	tc.t.Helper()
+	var err error
+	tc.fetched, err = tc.queue.GetCommand(context.Background(), id)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, status, tc.fetched.Status)
+}
+
+func (tc *commandQueueTestContext) pending_count_is(expected int) {
+	tc.t.Helper()
+	n, err := tc.queue.Pending(context.Background())
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, n)
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/core/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestEventStore_Conformance(t *testing.T) {
+	storetest.EventStore(t, func(t *testing.T) core.EventStore {
+		store, err := NewEventStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestProjectionStore_Conformance(t *testing.T) {
+	storetest.ProjectionStore(t, func(t *testing.T) core.ProjectionStore {
+		store, err := NewProjectionStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestCheckpointStore_Conformance(t *testing.T) {
+	storetest.CheckpointStore(t, func(t *testing.T) core.CheckpointStore {
+		store, err := NewCheckpointStore(newConformanceDB(t))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestShardedEventStore_Conformance(t *testing.T) {
+	storetest.EventStore(t, func(t *testing.T) core.EventStore {
+		return newConformanceShards(t).EventStore()
+	})
+}
+
+func TestShardedProjectionStore_Conformance(t *testing.T) {
+	storetest.ProjectionStore(t, func(t *testing.T) core.ProjectionStore {
+		return newConformanceShards(t).ProjectionStore()
+	})
+}
+
+func TestShardedCheckpointStore_Conformance(t *testing.T) {
+	storetest.CheckpointStore(t, func(t *testing.T) core.CheckpointStore {
+		return newConformanceShards(t).CheckpointStore()
+	})
+}
+
+// --- Test Context ---
+
+// newConformanceDB opens an in-memory database on a single connection, since
+// every connection to ":memory:" gets a database of its own.
+func newConformanceDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newConformanceShards keeps one database open at a time, so the cases
+// spanning realms close and reopen them.
+func newConformanceShards(t *testing.T) *Shards {
+	t.Helper()
+	shards, err := NewShards(t.TempDir(), 1, Tuning{})
+	require.NoError(t, err)
+	t.Cleanup(func() { shards.Close() })
+	return shards
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// DeadLetterStore is a SQLite-backed implementation of core.DeadLetterStore.
+type DeadLetterStore struct {
+	db *sql.DB
+}
+
+// NewDeadLetterStore creates a new DeadLetterStore backed by the given database.
+func NewDeadLetterStore(db *sql.DB) (*DeadLetterStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &DeadLetterStore{db: db}, nil
+}
+
+func (s *DeadLetterStore) RecordDeadLetter(ctx context.Context, letter core.DeadLetter) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO dead_letters (realm_id, projector, global_position, stream_id, version, event_type, error, attempts, halted, failed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(realm_id, projector, global_position) DO UPDATE SET
+			error = excluded.error,
+			attempts = dead_letters.attempts + excluded.attempts,
+			halted = excluded.halted,
+			failed_at = excluded.failed_at`,
+		letter.RealmID, letter.Projector, letter.GlobalPosition, letter.StreamID, letter.Version, letter.EventType,
+		letter.Error, letter.Attempts, letter.Halted, letter.FailedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *DeadLetterStore) ListDeadLetters(ctx context.Context, realmID, projector string) ([]core.DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT projector, global_position, stream_id, version, event_type, error, attempts, halted, failed_at
+		FROM dead_letters WHERE realm_id = ? AND (? = '' OR projector = ?)
+		ORDER BY global_position, projector`,
+		realmID, projector, projector,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	letters := []core.DeadLetter{}
+	for rows.Next() {
+		letter := core.DeadLetter{RealmID: realmID}
+		var failedAt int64
+		if err := rows.Scan(&letter.Projector, &letter.GlobalPosition, &letter.StreamID, &letter.Version, &letter.EventType,
+			&letter.Error, &letter.Attempts, &letter.Halted, &failedAt); err != nil {
+			return nil, err
+		}
+		letter.FailedAt = time.Unix(0, failedAt).UTC()
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
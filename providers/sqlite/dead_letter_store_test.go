@@ -0,0 +1,129 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.DeadLetterStore = (*DeadLetterStore)(nil)
+
+// --- Tests ---
+
+func TestDeadLetterStore(t *testing.T) {
+	t.Run("lists a realm's dead letters in event order", func(t *testing.T) {
+		tc := newDeadLetterStoreTestContext(t)
+
+		// Given
+		tc.dead_letter_is_recorded("realm-1", "rune_detail", 7, "boom", false)
+		tc.dead_letter_is_recorded("realm-1", "analytics", 3, "panic: nil map", true)
+		tc.dead_letter_is_recorded("realm-2", "rune_detail", 1, "boom", false)
+
+		// When
+		tc.dead_letters_are_listed("realm-1", "")
+
+		// Then
+		require.Len(t, tc.listed, 2)
+		assert.Equal(t, core.DeadLetter{
+			RealmID: "realm-1", Projector: "analytics", GlobalPosition: 3, StreamID: "bf-3", Version: 2,
+			EventType: "RuneCreated", Error: "panic: nil map", Attempts: 1, Halted: true,
+			FailedAt: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC),
+		}, tc.listed[0])
+		assert.Equal(t, int64(7), tc.listed[1].GlobalPosition)
+	})
+
+	t.Run("lists only the projector's dead letters when one is named", func(t *testing.T) {
+		tc := newDeadLetterStoreTestContext(t)
+
+		// Given
+		tc.dead_letter_is_recorded("realm-1", "rune_detail", 7, "boom", false)
+		tc.dead_letter_is_recorded("realm-1", "analytics", 3, "boom", false)
+
+		// When
+		tc.dead_letters_are_listed("realm-1", "rune_detail")
+
+		// Then
+		require.Len(t, tc.listed, 1)
+		assert.Equal(t, "rune_detail", tc.listed[0].Projector)
+	})
+
+	t.Run("adds up the attempts of an event that fails again", func(t *testing.T) {
+		tc := newDeadLetterStoreTestContext(t)
+
+		// Given
+		tc.dead_letter_is_recorded("realm-1", "analytics", 3, "boom", true)
+
+		// When
+		tc.dead_letter_is_recorded("realm-1", "analytics", 3, "still broken", true)
+
+		// Then
+		tc.dead_letters_are_listed("realm-1", "")
+		require.Len(t, tc.listed, 1)
+		assert.Equal(t, 2, tc.listed[0].Attempts)
+		assert.Equal(t, "still broken", tc.listed[0].Error)
+	})
+
+	t.Run("lists none for a realm without dead letters", func(t *testing.T) {
+		tc := newDeadLetterStoreTestContext(t)
+
+		// When
+		tc.dead_letters_are_listed("realm-1", "")
+
+		// Then
+		assert.Equal(t, []core.DeadLetter{}, tc.listed)
+	})
+}
+
+// --- Test Context ---
+
+type deadLetterStoreTestContext struct {
+	t     *testing.T
+	store *DeadLetterStore
+
+	listed []core.DeadLetter
+}
+
+func newDeadLetterStoreTestContext(t *testing.T) *deadLetterStoreTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewDeadLetterStore(db)
+	require.NoError(t, err)
+	return &deadLetterStoreTestContext{t: t, store: store}
+}
+
+// --- Given ---
+
+func (tc *deadLetterStoreTestContext) dead_letter_is_recorded(realmID, projector string, position int64, errText string, halted bool) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.store.RecordDeadLetter(context.Background(), core.DeadLetter{
+		RealmID:        realmID,
+		Projector:      projector,
+		GlobalPosition: position,
+		StreamID:       "bf-3",
+		Version:        2,
+		EventType:      "RuneCreated",
+		Error:          errText,
+		Attempts:       1,
+		Halted:         halted,
+		FailedAt:       time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC),
+	}))
+}
+
+// --- When ---
+
+func (tc *deadLetterStoreTestContext) dead_letters_are_listed(realmID, projector string) {
+	tc.t.Helper()
+	var err error
+	tc.listed, err = tc.store.ListDeadLetters(context.Background(), realmID, projector)
+	require.NoError(tc.t, err)
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// DraftStore is a SQLite-backed implementation of core.DraftStore.
+type DraftStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewDraftStore creates a new DraftStore backed by the given database.
+func NewDraftStore(db *sql.DB) (*DraftStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &DraftStore{db: db, now: time.Now}, nil
+}
+
+func (s *DraftStore) SaveDraft(ctx context.Context, draft core.FormDraft) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO form_drafts (account_id, realm_id, form_key, fields, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, realm_id, form_key) DO UPDATE SET fields = excluded.fields, updated_at = excluded.updated_at`,
+		draft.AccountID, draft.RealmID, draft.FormKey, string(draft.Fields), s.now().UnixNano(),
+	)
+	return err
+}
+
+func (s *DraftStore) GetDraft(ctx context.Context, accountID, realmID, formKey string) (core.FormDraft, error) {
+	var (
+		fields    string
+		updatedAt int64
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT fields, updated_at FROM form_drafts WHERE account_id = ? AND realm_id = ? AND form_key = ?`,
+		accountID, realmID, formKey,
+	).Scan(&fields, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.FormDraft{}, &core.NotFoundError{Entity: "draft", ID: formKey}
+	}
+	if err != nil {
+		return core.FormDraft{}, err
+	}
+	return core.FormDraft{
+		AccountID: accountID,
+		RealmID:   realmID,
+		FormKey:   formKey,
+		Fields:    json.RawMessage(fields),
+		UpdatedAt: time.Unix(0, updatedAt).UTC(),
+	}, nil
+}
+
+func (s *DraftStore) ListDrafts(ctx context.Context, accountID, realmID string) ([]core.FormDraft, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT form_key, fields, updated_at FROM form_drafts
+		WHERE account_id = ? AND realm_id = ? ORDER BY updated_at DESC, form_key`,
+		accountID, realmID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []core.FormDraft{}
+	for rows.Next() {
+		var (
+			draft     = core.FormDraft{AccountID: accountID, RealmID: realmID}
+			fields    string
+			updatedAt int64
+		)
+		if err := rows.Scan(&draft.FormKey, &fields, &updatedAt); err != nil {
+			return nil, err
+		}
+		draft.Fields = json.RawMessage(fields)
+		draft.UpdatedAt = time.Unix(0, updatedAt).UTC()
+		drafts = append(drafts, draft)
+	}
+	return drafts, rows.Err()
+}
+
+func (s *DraftStore) DeleteDraft(ctx context.Context, accountID, realmID, formKey string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM form_drafts WHERE account_id = ? AND realm_id = ? AND form_key = ?`,
+		accountID, realmID, formKey,
+	)
+	return err
+}
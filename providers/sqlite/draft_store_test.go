@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.DraftStore = (*DraftStore)(nil)
+
+// --- Tests ---
+
+func TestDraftStore_SaveDraft(t *testing.T) {
+	t.Run("replaces the previous draft of the same form", func(t *testing.T) {
+		tc := newDraftStoreTestContext(t)
+
+		// Given
+		tc.draft_is_saved("acct-1", "realm-1", "create-rune", `{"title":"Bri"}`)
+
+		// When
+		tc.draft_is_saved("acct-1", "realm-1", "create-rune", `{"title":"Bridge"}`)
+
+		// Then
+		tc.draft_is_fetched("acct-1", "realm-1", "create-rune")
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(tc.fetched.Fields))
+		assert.Equal(t, tc.clock, tc.fetched.UpdatedAt)
+	})
+}
+
+func TestDraftStore_GetDraft(t *testing.T) {
+	t.Run("returns not found for another account's draft", func(t *testing.T) {
+		tc := newDraftStoreTestContext(t)
+
+		// Given
+		tc.draft_is_saved("acct-1", "realm-1", "create-rune", `{"title":"Bridge"}`)
+
+		// When
+		_, err := tc.store.GetDraft(context.Background(), "acct-2", "realm-1", "create-rune")
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+}
+
+func TestDraftStore_ListDrafts(t *testing.T) {
+	t.Run("lists the account's drafts in a realm, newest first", func(t *testing.T) {
+		tc := newDraftStoreTestContext(t)
+
+		// Given
+		tc.draft_is_saved("acct-1", "realm-1", "create-rune", `{}`)
+		tc.draft_is_saved("acct-1", "realm-1", "update-rune:bf-1", `{}`)
+		tc.draft_is_saved("acct-1", "realm-2", "create-rune", `{}`)
+		tc.draft_is_saved("acct-2", "realm-1", "create-rune", `{}`)
+
+		// When
+		drafts, err := tc.store.ListDrafts(context.Background(), "acct-1", "realm-1")
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, drafts, 2)
+		assert.Equal(t, "update-rune:bf-1", drafts[0].FormKey)
+		assert.Equal(t, "create-rune", drafts[1].FormKey)
+	})
+}
+
+func TestDraftStore_DeleteDraft(t *testing.T) {
+	t.Run("removes the draft and ignores missing ones", func(t *testing.T) {
+		tc := newDraftStoreTestContext(t)
+
+		// Given
+		tc.draft_is_saved("acct-1", "realm-1", "create-rune", `{}`)
+
+		// When
+		require.NoError(t, tc.store.DeleteDraft(context.Background(), "acct-1", "realm-1", "create-rune"))
+		require.NoError(t, tc.store.DeleteDraft(context.Background(), "acct-1", "realm-1", "create-rune"))
+
+		// Then
+		drafts, err := tc.store.ListDrafts(context.Background(), "acct-1", "realm-1")
+		require.NoError(t, err)
+		assert.Empty(t, drafts)
+	})
+}
+
+// --- Test Context ---
+
+type draftStoreTestContext struct {
+	t     *testing.T
+	store *DraftStore
+	clock time.Time
+
+	fetched core.FormDraft
+}
+
+func newDraftStoreTestContext(t *testing.T) *draftStoreTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewDraftStore(db)
+	require.NoError(t, err)
+	tc := &draftStoreTestContext{t: t, store: store, clock: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	store.now = func() time.Time { return tc.clock }
+	return tc
+}
+
+// --- Given ---
+
+func (tc *draftStoreTestContext) draft_is_saved(accountID, realmID, formKey, fields string) {
+	tc.t.Helper()
+	tc.clock = tc.clock.Add(time.Second)
+	require.NoError(tc.t, tc.store.SaveDraft(context.Background(), core.FormDraft{
+		AccountID: accountID,
+		RealmID:   realmID,
+		FormKey:   formKey,
+		Fields:    json.RawMessage(fields),
+	}))
+}
+
+// --- When ---
+
+func (tc *draftStoreTestContext) draft_is_fetched(accountID, realmID, formKey string) {
+	tc.t.Helper()
+	var err error
+	tc.fetched, err = tc.store.GetDraft(context.Background(), accountID, realmID, formKey)
+	require.NoError(tc.t, err)
+}
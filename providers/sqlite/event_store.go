@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"time"
 
 	"github.com/devzeebo/bifrost/core"
 	sqlitelib "modernc.org/sqlite"
@@ -13,27 +12,92 @@ import (
 
 // EventStore is a SQLite-backed implementation of core.EventStore.
 type EventStore struct {
-	db *sql.DB
+	db                   conn
+	compressionThreshold int
+	outbox               bool
+	// appended is set when events are appended within a unit of work, so
+	// the transactor wakes subscriptions once they are committed.
+	appended bool
+}
+
+type EventStoreOption func(*EventStore)
+
+// WithCompressionThreshold gzips event data of at least n bytes before
+// storing it. Reads decompress transparently whatever the setting, so it can
+// be changed at any time; zero stores everything as plain JSON.
+func WithCompressionThreshold(n int) EventStoreOption {
+	return func(s *EventStore) {
+		s.compressionThreshold = n
+	}
+}
+
+// WithOutbox records each appended event in the outbox table, in the
+// append's transaction, for an Outbox to hand to a publisher.
+func WithOutbox() EventStoreOption {
+	return func(s *EventStore) {
+		s.outbox = true
+	}
 }
 
 // NewEventStore creates a new EventStore backed by the given database.
-func NewEventStore(db *sql.DB) (*EventStore, error) {
+func NewEventStore(db *sql.DB, opts ...EventStoreOption) (*EventStore, error) {
 	if err := EnsureSchema(db); err != nil {
 		return nil, err
 	}
-	return &EventStore{db: db}, nil
+	s := &EventStore{db: conn{db: db}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // Append persists new events to a stream with optimistic concurrency control.
+// A batch keyed by an idempotency key already used on the stream is not
+// appended again; its stored events are returned instead.
 func (s *EventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
+	var result []core.Event
+	err := s.db.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		result, err = s.append(ctx, tx, realmID, streamID, expectedVersion, events)
+		return err
+	})
 	if err != nil {
+		if isSQLiteConcurrencyError(err) {
+			return nil, &core.ConcurrencyError{
+				StreamID:        streamID,
+				ExpectedVersion: expectedVersion,
+				ActualVersion:   expectedVersion,
+			}
+		}
+		return nil, err
+	}
+	if s.db.tx != nil {
+		s.appended = true
+	} else {
+		notifierFor(s.db.db).notify()
+	}
+	return result, nil
+}
+
+func (s *EventStore) append(ctx context.Context, tx *sql.Tx, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	// An archived stream is brought back before its version is checked.
+	if _, err := rehydrate(ctx, tx, realmID, streamID); err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
+
+	var key string
+	if len(events) > 0 {
+		key = events[0].IdempotencyKey
+	}
+	if key != "" {
+		stored, err := s.keyedBatch(ctx, tx, realmID, streamID, key)
+		if err != nil || stored != nil {
+			return stored, err
+		}
+	}
 
 	var actualVersion int
-	err = tx.QueryRowContext(ctx,
+	err := tx.QueryRowContext(ctx,
 		`SELECT COALESCE(MAX(version), 0) FROM events WHERE realm_id = ? AND stream_id = ?`,
 		realmID, streamID,
 	).Scan(&actualVersion)
@@ -50,7 +114,7 @@ func (s *EventStore) Append(ctx context.Context, realmID string, streamID string
 	}
 
 	result := make([]core.Event, len(events))
-	now := time.Now().UTC()
+	now := core.ClockFromContext(ctx).Now().UTC()
 
 	for i, ed := range events {
 		data, err := json.Marshal(ed.Data)
@@ -72,18 +136,20 @@ func (s *EventStore) Append(ctx context.Context, realmID string, streamID string
 			metadataVal = string(metadata)
 		}
 
+		var dataVal any = string(data)
+		if s.compressionThreshold > 0 && len(data) >= s.compressionThreshold {
+			compressed, err := compressData(data)
+			if err != nil {
+				return nil, err
+			}
+			dataVal = compressed
+		}
+
 		res, err := tx.ExecContext(ctx,
 			`INSERT INTO events (realm_id, stream_id, version, event_type, data, metadata, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			realmID, streamID, version, ed.EventType, string(data), metadataVal, now,
+			realmID, streamID, version, ed.EventType, dataVal, metadataVal, now,
 		)
 		if err != nil {
-			if isSQLiteConcurrencyError(err) {
-				return nil, &core.ConcurrencyError{
-					StreamID:        streamID,
-					ExpectedVersion: expectedVersion,
-					ActualVersion:   expectedVersion,
-				}
-			}
 			return nil, err
 		}
 
@@ -102,24 +168,119 @@ func (s *EventStore) Append(ctx context.Context, realmID string, streamID string
 			Metadata:       metadata,
 			Timestamp:      now,
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
-		if isSQLiteConcurrencyError(err) {
-			return nil, &core.ConcurrencyError{
-				StreamID:        streamID,
-				ExpectedVersion: expectedVersion,
-				ActualVersion:   expectedVersion,
+		if s.outbox {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO outbox (realm_id, global_position, next_attempt_at, created_at) VALUES (?, ?, ?, ?)`,
+				realmID, globalPosition, now.UnixNano(), now.UnixNano(),
+			); err != nil {
+				return nil, err
 			}
 		}
-		return nil, err
+	}
+
+	if key != "" && len(result) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO idempotency_keys (realm_id, stream_id, idempotency_key, first_version, last_version) VALUES (?, ?, ?, ?, ?)`,
+			realmID, streamID, key, expectedVersion+1, expectedVersion+len(result),
+		); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
-// ReadStream returns events for a specific stream starting from the given version.
+// keyedBatch returns the events of the batch appended to the stream with the
+// idempotency key, or nil if there is none.
+func (s *EventStore) keyedBatch(ctx context.Context, tx *sql.Tx, realmID, streamID, key string) ([]core.Event, error) {
+	var first, last int
+	err := tx.QueryRowContext(ctx,
+		`SELECT first_version, last_version FROM idempotency_keys WHERE realm_id = ? AND stream_id = ? AND idempotency_key = ?`,
+		realmID, streamID, key,
+	).Scan(&first, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx,
+		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND stream_id = ? AND version BETWEEN ? AND ?
+		 ORDER BY version ASC`,
+		realmID, streamID, first, last,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEvents(rows)
+}
+
+// ReadStream returns events for a specific stream starting from the given
+// version. Reading an archived stream moves its events back out of the
+// archive first.
 func (s *EventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	return s.readUnarchived(ctx, realmID, streamID, func() ([]core.Event, error) {
+		return s.readStream(ctx, realmID, streamID, fromVersion)
+	})
+}
+
+// ReadStreamPage returns up to limit events of the stream from a version,
+// forward or backward, rehydrating an archived stream like ReadStream.
+func (s *EventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir core.Direction) ([]core.Event, error) {
+	return s.readUnarchived(ctx, realmID, streamID, func() ([]core.Event, error) {
+		query := `SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND stream_id = ?`
+		args := []any{realmID, streamID}
+		if dir == core.Forward {
+			query += ` AND version >= ? ORDER BY version ASC`
+			args = append(args, from)
+		} else {
+			if from > 0 {
+				query += ` AND version <= ?`
+				args = append(args, from)
+			}
+			query += ` ORDER BY version DESC`
+		}
+		if limit > 0 {
+			query += ` LIMIT ?`
+			args = append(args, limit)
+		}
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		return scanEvents(rows)
+	})
+}
+
+// readUnarchived runs read, and if it finds nothing because the stream is
+// archived, moves the stream back out of the archive and runs it again.
+func (s *EventStore) readUnarchived(ctx context.Context, realmID string, streamID string, read func() ([]core.Event, error)) ([]core.Event, error) {
+	events, err := read()
+	if err != nil || len(events) > 0 {
+		return events, err
+	}
+	archived, err := s.isArchived(ctx, realmID, streamID)
+	if err != nil || !archived {
+		return events, err
+	}
+	err = s.db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := rehydrate(ctx, tx, realmID, streamID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return read()
+}
+
+func (s *EventStore) readStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
 		 FROM events
@@ -152,6 +313,28 @@ func (s *EventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosi
 	return scanEvents(rows)
 }
 
+// ReadAllBatch returns up to limit events in a realm after the given global
+// position. A limit of zero or less returns them all.
+func (s *EventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]core.Event, error) {
+	if limit <= 0 {
+		return s.ReadAll(ctx, realmID, fromGlobalPosition)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE realm_id = ? AND global_position > ?
+		 ORDER BY global_position ASC
+		 LIMIT ?`,
+		realmID, fromGlobalPosition, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
 // ListRealmIDs returns all distinct realm IDs from the events table.
 func (s *EventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT realm_id FROM events`)
@@ -190,6 +373,11 @@ func scanEvents(rows *sql.Rows) ([]core.Event, error) {
 		); err != nil {
 			return nil, err
 		}
+		data, err := decompressData(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		e.Data = data
 		events = append(events, e)
 	}
 	if err := rows.Err(); err != nil {
@@ -5,17 +5,24 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/devzeebo/bifrost/core"
-	"modernc.org/sqlite"
+	_ "modernc.org/sqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 // Compile-time interface satisfaction check
 var _ core.EventStore = (*EventStore)(nil)
+var _ core.BatchEventReader = (*EventStore)(nil)
+var _ core.SnapshotStore = (*EventStore)(nil)
+var _ core.EventTruncator = (*EventStore)(nil)
+var _ core.EventSubscriber = (*EventStore)(nil)
+var _ core.StreamPageReader = (*EventStore)(nil)
 
 // --- Tests ---
 
@@ -61,6 +68,25 @@ func TestEventStore_Append(t *testing.T) {
 		tc.appended_event_has_timestamp(0)
 	})
 
+	t.Run("stamps events with the context's clock", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.a_fixed_clock(time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+
+		// When
+		tc.append_is_called("realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "UserCreated", Data: map[string]string{"name": "Alice"}},
+		})
+		tc.read_stream_is_called("realm-1", "stream-1", 0)
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_event_has_timestamp(0, time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC))
+	})
+
 	t.Run("succeeds for existing stream with correct expectedVersion", func(t *testing.T) {
 		tc := newEventStoreTestContext(t)
 
@@ -191,6 +217,199 @@ func TestEventStore_ReadStream(t *testing.T) {
 	})
 }
 
+func TestEventStore_ArchiveStreams(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("archives streams that ended with the event type before the cutoff", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.stream_has_events_at("realm-1", "old-open", cutoff.Add(-time.Hour), "Created")
+		tc.stream_has_events_at("realm-1", "new-ended", cutoff.Add(time.Hour), "Created", "Ended")
+
+		// When
+		tc.archive_streams_is_called("Ended", cutoff)
+
+		// Then
+		tc.no_error_occurred()
+		assert.Equal(t, 1, tc.archived)
+		tc.read_all_is_called("realm-1", 0)
+		tc.read_streams_are("old-open", "new-ended", "new-ended")
+	})
+
+	t.Run("brings an archived stream back when it is read", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+
+		// When
+		tc.read_stream_is_called("realm-1", "old-ended", 1)
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_events_count_is(2)
+		assert.Equal(t, int64(1), tc.readEvents[0].GlobalPosition)
+		tc.read_all_is_called("realm-1", 0)
+		tc.read_streams_are("old-ended", "old-ended")
+	})
+
+	t.Run("brings an archived stream back when a page of it is read", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+
+		// When
+		tc.readEvents, tc.err = tc.store.ReadStreamPage(context.Background(), "realm-1", "old-ended", 0, 1, core.Backward)
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_events_count_is(1)
+		assert.Equal(t, "Ended", tc.readEvents[0].EventType)
+		tc.read_all_is_called("realm-1", 0)
+		tc.read_streams_are("old-ended", "old-ended")
+	})
+
+	t.Run("appends to an archived stream after its archived events", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+
+		// When
+		tc.append_is_called("realm-1", "old-ended", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+
+		// Then
+		tc.concurrency_error_is_returned("old-ended", 0, 2)
+	})
+
+	t.Run("redacts archived events where they lie", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+
+		// When
+		tc.redact_stream_is_called("realm-1", "old-ended", core.Event{Version: 1, Data: []byte(`{"redacted":true}`)})
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_stream_is_called("realm-1", "old-ended", 1)
+		tc.no_error_occurred()
+		tc.read_events_count_is(2)
+		assert.JSONEq(t, `{"redacted":true}`, string(tc.readEvents[0].Data))
+	})
+}
+
+func TestEventStore_TruncateStreams(t *testing.T) {
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("truncates an archived stream and its snapshot", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Updated", "Ended")
+		require.NoError(t, tc.store.SaveSnapshot(context.Background(), core.Snapshot{RealmID: "realm-1", StreamID: "old-ended", Version: 2, State: []byte(`{}`)}))
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+
+		// When
+		tc.truncate_streams_is_called("realm-1", "Ended", cutoff)
+
+		// Then
+		tc.no_error_occurred()
+		assert.Equal(t, 1, tc.truncated)
+		tc.read_stream_is_called("realm-1", "old-ended", 0)
+		tc.read_events_count_is(1)
+		assert.Equal(t, 3, tc.readEvents[0].Version)
+		assert.Equal(t, int64(3), tc.readEvents[0].GlobalPosition)
+		_, err := tc.store.GetSnapshot(context.Background(), "realm-1", "old-ended")
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+
+	t.Run("keeps the snapshot taken at the kept event", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Updated", "Ended")
+		require.NoError(t, tc.store.SaveSnapshot(context.Background(), core.Snapshot{RealmID: "realm-1", StreamID: "old-ended", Version: 3, State: []byte(`{"ended":true}`)}))
+
+		// When
+		tc.truncate_streams_is_called("realm-1", "Ended", cutoff)
+
+		// Then
+		tc.no_error_occurred()
+		snapshot, err := tc.store.GetSnapshot(context.Background(), "realm-1", "old-ended")
+		require.NoError(t, err)
+		assert.Equal(t, 3, snapshot.Version)
+		assert.JSONEq(t, `{"ended":true}`, string(snapshot.State))
+	})
+
+	t.Run("lists the streams it would truncate, archived or not", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "archived-ended", cutoff.Add(-2*time.Hour), "Created", "Ended")
+		tc.archive_streams_is_called("Ended", cutoff)
+		tc.no_error_occurred()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.stream_has_events_at("realm-1", "only-ended", cutoff.Add(-time.Hour), "Ended")
+		tc.stream_has_events_at("realm-1", "new-ended", cutoff.Add(time.Hour), "Created", "Ended")
+
+		// When
+		streamIDs, err := tc.store.TruncatableStreams(context.Background(), "realm-1", "Ended", cutoff)
+
+		// Then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"archived-ended", "old-ended"}, streamIDs)
+	})
+
+	t.Run("leaves the realm's feed with the kept events only", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events_at("realm-1", "old-ended", cutoff.Add(-time.Hour), "Created", "Ended")
+		tc.stream_has_events_at("realm-1", "open", cutoff.Add(-time.Hour), "Created")
+
+		// When
+		tc.truncate_streams_is_called("realm-1", "Ended", cutoff)
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_all_is_called("realm-1", 0)
+		tc.read_streams_are("old-ended", "open")
+	})
+}
+
 func TestEventStore_ReadAll(t *testing.T) {
 	t.Run("returns events in global_position order across streams", func(t *testing.T) {
 		tc := newEventStoreTestContext(t)
@@ -262,6 +481,26 @@ func TestEventStore_ReadAll(t *testing.T) {
 	})
 }
 
+func TestEventStore_ReadAllBatch(t *testing.T) {
+	t.Run("returns at most limit events after the position", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created()
+		tc.stream_has_events("realm-1", "stream-1", 5)
+
+		// When
+		tc.read_all_batch_is_called("realm-1", 1, 2)
+
+		// Then
+		tc.no_error_occurred()
+		tc.read_events_count_is(2)
+		tc.read_events_are_in_global_position_order()
+		tc.read_event_has_global_position_greater_than(0, 1)
+	})
+}
+
 func TestEventStore_Concurrency(t *testing.T) {
 	t.Run("concurrent appends to same stream: one succeeds, one gets ConcurrencyError", func(t *testing.T) {
 		tc := newEventStoreTestContext(t)
@@ -315,25 +554,95 @@ func TestEventStore_Append_StoresDataAsText(t *testing.T) {
 	})
 }
 
+func TestEventStore_Compression(t *testing.T) {
+	t.Run("compresses data at or above the threshold and reads it back", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+		description := strings.Repeat("a long imported description ", 100)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created(WithCompressionThreshold(256))
+
+		// When
+		tc.append_is_called("realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "RuneCreated", Data: map[string]string{"description": description}},
+		})
+		tc.read_all_is_called("realm-1", 0)
+
+		// Then
+		tc.no_error_occurred()
+		tc.stored_event_data_column_has_type("blob")
+		tc.stored_event_data_is_smaller_than(len(description))
+		require.Len(t, tc.readEvents, 1)
+		assert.JSONEq(t, `{"description":"`+description+`"}`, string(tc.readEvents[0].Data))
+		assert.JSONEq(t, string(tc.appendedEvents[0].Data), string(tc.readEvents[0].Data))
+	})
+
+	t.Run("stores data below the threshold as text", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created(WithCompressionThreshold(256))
+
+		// When
+		tc.append_is_called("realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "UserCreated", Data: map[string]string{"name": "Alice"}},
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.stored_event_data_column_has_type("text")
+	})
+
+	t.Run("reads compressed data after compression is turned off", func(t *testing.T) {
+		tc := newEventStoreTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_event_store_is_created(WithCompressionThreshold(1))
+		tc.append_is_called("realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "UserCreated", Data: map[string]string{"name": "Alice"}},
+		})
+
+		// When
+		tc.new_event_store_is_created()
+		tc.read_stream_is_called("realm-1", "stream-1", 0)
+
+		// Then
+		tc.no_error_occurred()
+		require.Len(t, tc.readEvents, 1)
+		assert.JSONEq(t, `{"name":"Alice"}`, string(tc.readEvents[0].Data))
+	})
+}
+
 // --- Test Context ---
 
 type eventStoreTestContext struct {
 	t              *testing.T
+	ctx            context.Context
 	db             *sql.DB
 	store          *EventStore
 	appendedEvents []core.Event
 	readEvents     []core.Event
 	err            error
 	concurrentErrs []error
+	archived       int
+	truncated      int
 }
 
 func newEventStoreTestContext(t *testing.T) *eventStoreTestContext {
 	t.Helper()
-	return &eventStoreTestContext{t: t}
+	return &eventStoreTestContext{t: t, ctx: context.Background()}
 }
 
 // --- Given ---
 
+func (tc *eventStoreTestContext) a_fixed_clock(t time.Time) {
+	tc.t.Helper()
+	tc.ctx = core.ContextWithClock(tc.ctx, core.FixedClock(t))
+}
+
 func (tc *eventStoreTestContext) a_database_with_schema() {
 	tc.t.Helper()
 	db, err := sql.Open("sqlite", ":memory:")
@@ -350,20 +659,9 @@ func (tc *eventStoreTestContext) a_file_database_with_wal() {
 	require.NoError(tc.t, err)
 	tc.t.Cleanup(func() { os.RemoveAll(dir) })
 	dbPath := filepath.Join(dir, "test.db")
-	sqlite.RegisterConnectionHook(func(conn sqlite.ExecQuerierContext, _ string) error {
-		_, err := conn.ExecContext(context.Background(), "PRAGMA journal_mode=WAL", nil)
-		if err != nil {
-			return err
-		}
-		_, err = conn.ExecContext(context.Background(), "PRAGMA busy_timeout=5000", nil)
-		return err
-	})
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := Open(dbPath, Tuning{JournalMode: "WAL", BusyTimeout: 5 * time.Second})
 	require.NoError(tc.t, err)
-	tc.t.Cleanup(func() {
-		db.Close()
-		sqlite.RegisterConnectionHook(func(sqlite.ExecQuerierContext, string) error { return nil })
-	})
+	tc.t.Cleanup(func() { db.Close() })
 	err = EnsureSchema(db)
 	require.NoError(tc.t, err)
 	tc.db = db
@@ -383,16 +681,42 @@ func (tc *eventStoreTestContext) stream_has_events(realmID, streamID string, cou
 	require.NoError(tc.t, err)
 }
 
+func (tc *eventStoreTestContext) stream_has_events_at(realmID, streamID string, at time.Time, eventTypes ...string) {
+	tc.t.Helper()
+	events := make([]core.EventData, len(eventTypes))
+	for i, eventType := range eventTypes {
+		events[i] = core.EventData{EventType: eventType, Data: map[string]string{}}
+	}
+	ctx := core.ContextWithClock(context.Background(), core.FixedClock(at))
+	_, err := tc.store.Append(ctx, realmID, streamID, 0, events)
+	require.NoError(tc.t, err)
+}
+
 // --- When ---
 
-func (tc *eventStoreTestContext) new_event_store_is_created() {
+func (tc *eventStoreTestContext) archive_streams_is_called(lastEventType string, before time.Time) {
+	tc.t.Helper()
+	tc.archived, tc.err = tc.store.ArchiveStreams(context.Background(), lastEventType, before)
+}
+
+func (tc *eventStoreTestContext) truncate_streams_is_called(realmID, lastEventType string, before time.Time) {
 	tc.t.Helper()
-	tc.store, tc.err = NewEventStore(tc.db)
+	tc.truncated, tc.err = tc.store.TruncateStreams(context.Background(), realmID, lastEventType, before)
+}
+
+func (tc *eventStoreTestContext) redact_stream_is_called(realmID, streamID string, redacted ...core.Event) {
+	tc.t.Helper()
+	tc.err = tc.store.RedactStream(context.Background(), realmID, streamID, redacted)
+}
+
+func (tc *eventStoreTestContext) new_event_store_is_created(opts ...EventStoreOption) {
+	tc.t.Helper()
+	tc.store, tc.err = NewEventStore(tc.db, opts...)
 }
 
 func (tc *eventStoreTestContext) append_is_called(realmID, streamID string, expectedVersion int, events []core.EventData) {
 	tc.t.Helper()
-	tc.appendedEvents, tc.err = tc.store.Append(context.Background(), realmID, streamID, expectedVersion, events)
+	tc.appendedEvents, tc.err = tc.store.Append(tc.ctx, realmID, streamID, expectedVersion, events)
 }
 
 func (tc *eventStoreTestContext) read_stream_is_called(realmID, streamID string, fromVersion int) {
@@ -405,6 +729,11 @@ func (tc *eventStoreTestContext) read_all_is_called(realmID string, fromGlobalPo
 	tc.readEvents, tc.err = tc.store.ReadAll(context.Background(), realmID, fromGlobalPosition)
 }
 
+func (tc *eventStoreTestContext) read_all_batch_is_called(realmID string, fromGlobalPosition int64, limit int) {
+	tc.t.Helper()
+	tc.readEvents, tc.err = tc.store.ReadAllBatch(context.Background(), realmID, fromGlobalPosition, limit)
+}
+
 func (tc *eventStoreTestContext) two_concurrent_appends_to_same_stream(realmID, streamID string) {
 	tc.t.Helper()
 	var wg sync.WaitGroup
@@ -491,6 +820,12 @@ func (tc *eventStoreTestContext) appended_event_has_timestamp(index int) {
 	assert.False(tc.t, tc.appendedEvents[index].Timestamp.IsZero())
 }
 
+func (tc *eventStoreTestContext) read_event_has_timestamp(index int, expected time.Time) {
+	tc.t.Helper()
+	require.Greater(tc.t, len(tc.readEvents), index)
+	assert.True(tc.t, expected.Equal(tc.readEvents[index].Timestamp), "expected %s, got %s", expected, tc.readEvents[index].Timestamp)
+}
+
 func (tc *eventStoreTestContext) concurrency_error_is_returned(streamID string, expectedVersion, actualVersion int) {
 	tc.t.Helper()
 	require.Error(tc.t, tc.err)
@@ -506,6 +841,15 @@ func (tc *eventStoreTestContext) read_events_count_is(expected int) {
 	assert.Len(tc.t, tc.readEvents, expected)
 }
 
+func (tc *eventStoreTestContext) read_streams_are(streamIDs ...string) {
+	tc.t.Helper()
+	var got []string
+	for _, evt := range tc.readEvents {
+		got = append(got, evt.StreamID)
+	}
+	assert.Equal(tc.t, streamIDs, got)
+}
+
 func (tc *eventStoreTestContext) read_events_is_empty_slice() {
 	tc.t.Helper()
 	assert.NotNil(tc.t, tc.readEvents)
@@ -580,3 +924,11 @@ func (tc *eventStoreTestContext) one_append_succeeded_and_one_got_concurrency_er
 	assert.Equal(tc.t, 1, successCount, "expected exactly one successful append")
 	assert.Equal(tc.t, 1, concurrencyErrCount, "expected exactly one ConcurrencyError")
 }
+
+func (tc *eventStoreTestContext) stored_event_data_is_smaller_than(size int) {
+	tc.t.Helper()
+	var length int
+	err := tc.db.QueryRow(`SELECT length(data) FROM events LIMIT 1`).Scan(&length)
+	require.NoError(tc.t, err)
+	assert.Less(tc.t, length, size)
+}
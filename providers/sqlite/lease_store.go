@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// LeaseStore is a SQLite-backed implementation of core.LeaseStore. Leases are
+// only shared by instances that open the same database file.
+type LeaseStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewLeaseStore creates a new LeaseStore backed by the given database.
+func NewLeaseStore(db *sql.DB) (*LeaseStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &LeaseStore{db: db, now: time.Now}, nil
+}
+
+// AcquireLease takes the lease if it is free, expired or already held by
+// holder, and extends it to ttl from now.
+func (s *LeaseStore) AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	now := s.now()
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO leases (name, holder, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= ?`,
+		name, holder, now.Add(ttl).UnixNano(), now.UnixNano(),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseLease drops the lease if holder has it.
+func (s *LeaseStore) ReleaseLease(ctx context.Context, name string, holder string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM leases WHERE name = ? AND holder = ?`,
+		name, holder,
+	)
+	return err
+}
@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.LeaseStore = (*LeaseStore)(nil)
+
+// --- Tests ---
+
+func TestLeaseStore_AcquireLease(t *testing.T) {
+	t.Run("grants a free lease", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// When
+		tc.acquire_is_called("node-a")
+
+		// Then
+		tc.no_error_occurred()
+		tc.lease_was_granted(true)
+	})
+
+	t.Run("renews a lease for its holder", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// Given
+		tc.acquire_is_called("node-a")
+
+		// When
+		tc.acquire_is_called("node-a")
+
+		// Then
+		tc.lease_was_granted(true)
+	})
+
+	t.Run("refuses a lease held by another node", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// Given
+		tc.acquire_is_called("node-a")
+
+		// When
+		tc.acquire_is_called("node-b")
+
+		// Then
+		tc.no_error_occurred()
+		tc.lease_was_granted(false)
+	})
+
+	t.Run("takes over an expired lease", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// Given
+		tc.acquire_is_called("node-a")
+		tc.time_passes(2 * time.Minute)
+
+		// When
+		tc.acquire_is_called("node-b")
+
+		// Then
+		tc.lease_was_granted(true)
+	})
+}
+
+func TestLeaseStore_ReleaseLease(t *testing.T) {
+	t.Run("frees the lease for other nodes", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// Given
+		tc.acquire_is_called("node-a")
+
+		// When
+		tc.release_is_called("node-a")
+		tc.acquire_is_called("node-b")
+
+		// Then
+		tc.lease_was_granted(true)
+	})
+
+	t.Run("ignores releases by other nodes", func(t *testing.T) {
+		tc := newLeaseTestContext(t)
+
+		// Given
+		tc.acquire_is_called("node-a")
+
+		// When
+		tc.release_is_called("node-b")
+		tc.acquire_is_called("node-b")
+
+		// Then
+		tc.lease_was_granted(false)
+	})
+}
+
+// --- Test Context ---
+
+type leaseTestContext struct {
+	t     *testing.T
+	store *LeaseStore
+	now   time.Time
+
+	granted bool
+	err     error
+}
+
+func newLeaseTestContext(t *testing.T) *leaseTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewLeaseStore(db)
+	require.NoError(t, err)
+
+	tc := &leaseTestContext{t: t, store: store, now: time.Now()}
+	store.now = func() time.Time { return tc.now }
+	return tc
+}
+
+// --- Given ---
+
+func (tc *leaseTestContext) time_passes(d time.Duration) {
+	tc.t.Helper()
+	tc.now = tc.now.Add(d)
+}
+
+// --- When ---
+
+func (tc *leaseTestContext) acquire_is_called(holder string) {
+	tc.t.Helper()
+	tc.granted, tc.err = tc.store.AcquireLease(context.Background(), core.CatchUpLease, holder, time.Minute)
+}
+
+func (tc *leaseTestContext) release_is_called(holder string) {
+	tc.t.Helper()
+	tc.err = tc.store.ReleaseLease(context.Background(), core.CatchUpLease, holder)
+	require.NoError(tc.t, tc.err)
+}
+
+// --- Then ---
+
+func (tc *leaseTestContext) no_error_occurred() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *leaseTestContext) lease_was_granted(expected bool) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+	assert.Equal(tc.t, expected, tc.granted)
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JournalModes are the journal modes Tuning accepts.
+var JournalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+
+// SynchronousLevels are the synchronous levels Tuning accepts.
+var SynchronousLevels = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+
+// Tuning holds the PRAGMAs Open runs on every connection of a database. A
+// zero field keeps SQLite's default.
+type Tuning struct {
+	JournalMode string        // e.g. "WAL", so readers do not block the writer
+	Synchronous string        // e.g. "NORMAL", which is safe with WAL
+	BusyTimeout time.Duration // How long a locked database is waited for before "database is locked"
+}
+
+// Validate reports a journal mode or synchronous level SQLite does not
+// know.
+func (t Tuning) Validate() error {
+	if t.JournalMode != "" && !slices.Contains(JournalModes, strings.ToUpper(t.JournalMode)) {
+		return fmt.Errorf("journal mode must be one of %s, got %q", strings.Join(JournalModes, ", "), t.JournalMode)
+	}
+	if t.Synchronous != "" && !slices.Contains(SynchronousLevels, strings.ToUpper(t.Synchronous)) {
+		return fmt.Errorf("synchronous level must be one of %s, got %q", strings.Join(SynchronousLevels, ", "), t.Synchronous)
+	}
+	if t.BusyTimeout < 0 {
+		return fmt.Errorf("busy timeout must not be negative")
+	}
+	return nil
+}
+
+// Open opens the database at path with tuning applied. The PRAGMAs are
+// passed to the driver rather than run once, since database/sql opens
+// connections as it needs them and most PRAGMAs only affect the connection
+// they run on.
+func Open(path string, tuning Tuning) (*sql.DB, error) {
+	if err := tuning.Validate(); err != nil {
+		return nil, err
+	}
+	var pragmas []string
+	if tuning.BusyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("busy_timeout(%d)", tuning.BusyTimeout.Milliseconds()))
+	}
+	if tuning.JournalMode != "" {
+		pragmas = append(pragmas, "journal_mode("+strings.ToUpper(tuning.JournalMode)+")")
+	}
+	if tuning.Synchronous != "" {
+		pragmas = append(pragmas, "synchronous("+strings.ToUpper(tuning.Synchronous)+")")
+	}
+	if len(pragmas) == 0 {
+		return sql.Open("sqlite", path)
+	}
+	query := url.Values{"_pragma": pragmas}
+	return sql.Open("sqlite", path+"?"+query.Encode())
+}
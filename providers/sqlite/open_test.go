@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestOpen(t *testing.T) {
+	t.Run("applies the tuning to every connection", func(t *testing.T) {
+		tc := newOpenTestContext(t)
+
+		// When
+		tc.database_is_opened(Tuning{JournalMode: "wal", Synchronous: "NORMAL", BusyTimeout: 2500 * time.Millisecond})
+
+		// Then
+		tc.no_error()
+		first, second := tc.connection(), tc.connection()
+		for _, c := range []*sql.Conn{first, second} {
+			tc.pragma_is(c, "journal_mode", "wal")
+			tc.pragma_is(c, "synchronous", "1")
+			tc.pragma_is(c, "busy_timeout", "2500")
+		}
+	})
+
+	t.Run("keeps SQLite's defaults without tuning", func(t *testing.T) {
+		tc := newOpenTestContext(t)
+
+		// When
+		tc.database_is_opened(Tuning{})
+
+		// Then
+		tc.no_error()
+		c := tc.connection()
+		tc.pragma_is(c, "journal_mode", "delete")
+		tc.pragma_is(c, "busy_timeout", "0")
+	})
+
+	t.Run("rejects an unknown journal mode", func(t *testing.T) {
+		tc := newOpenTestContext(t)
+
+		// When
+		tc.database_is_opened(Tuning{JournalMode: "WAL); DROP TABLE events; --"})
+
+		// Then
+		assert.ErrorContains(t, tc.err, "journal mode must be one of")
+	})
+
+	t.Run("rejects an unknown synchronous level", func(t *testing.T) {
+		tc := newOpenTestContext(t)
+
+		// When
+		tc.database_is_opened(Tuning{Synchronous: "SOMETIMES"})
+
+		// Then
+		assert.ErrorContains(t, tc.err, "synchronous level must be one of")
+	})
+}
+
+// --- Test Context ---
+
+type openTestContext struct {
+	t *testing.T
+
+	db  *sql.DB
+	err error
+}
+
+func newOpenTestContext(t *testing.T) *openTestContext {
+	t.Helper()
+	return &openTestContext{t: t}
+}
+
+// --- When ---
+
+func (tc *openTestContext) database_is_opened(tuning Tuning) {
+	tc.t.Helper()
+	tc.db, tc.err = Open(filepath.Join(tc.t.TempDir(), "bifrost.db"), tuning)
+	if tc.db != nil {
+		tc.t.Cleanup(func() { tc.db.Close() })
+	}
+}
+
+// --- Then ---
+
+func (tc *openTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+// connection holds a connection of its own until the test ends, so each
+// call gets a different one.
+func (tc *openTestContext) connection() *sql.Conn {
+	tc.t.Helper()
+	c, err := tc.db.Conn(context.Background())
+	require.NoError(tc.t, err)
+	tc.t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func (tc *openTestContext) pragma_is(c *sql.Conn, pragma, expected string) {
+	tc.t.Helper()
+	var got string
+	require.NoError(tc.t, c.QueryRowContext(context.Background(), "PRAGMA "+pragma).Scan(&got))
+	assert.Equal(tc.t, expected, got, pragma)
+}
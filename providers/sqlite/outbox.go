@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Outbox is a SQLite-backed implementation of core.Outbox. Entries are
+// only recorded by event stores made with WithOutbox.
+type Outbox struct {
+	db *sql.DB
+}
+
+// NewOutbox creates a new Outbox backed by the given database.
+func NewOutbox(db *sql.DB) (*Outbox, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &Outbox{db: db}, nil
+}
+
+func (o *Outbox) Due(ctx context.Context, now time.Time, limit int) ([]core.OutboxEntry, error) {
+	rows, err := o.db.QueryContext(ctx,
+		`SELECT id, realm_id, global_position, attempts, last_error, created_at FROM outbox
+		WHERE next_attempt_at <= ? ORDER BY id LIMIT ?`,
+		now.UnixNano(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []core.OutboxEntry
+	for rows.Next() {
+		var (
+			entry     core.OutboxEntry
+			lastError sql.NullString
+			createdAt int64
+		)
+		if err := rows.Scan(&entry.ID, &entry.RealmID, &entry.GlobalPosition, &entry.Attempts, &lastError, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.LastError = lastError.String
+		entry.CreatedAt = time.Unix(0, createdAt).UTC()
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (o *Outbox) Delivered(ctx context.Context, id int64) error {
+	_, err := o.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = ?`, id)
+	return err
+}
+
+func (o *Outbox) Retry(ctx context.Context, id int64, next time.Time, lastErr string) error {
+	res, err := o.db.ExecContext(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		lastErr, next.UnixNano(), id,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &core.NotFoundError{Entity: "outbox entry", ID: fmt.Sprint(id)}
+	}
+	return nil
+}
+
+func (o *Outbox) Pending(ctx context.Context) (int, error) {
+	var n int
+	err := o.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&n)
+	return n, err
+}
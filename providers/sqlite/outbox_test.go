@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.Outbox = (*Outbox)(nil)
+
+// --- Tests ---
+
+func TestOutbox(t *testing.T) {
+	t.Run("records appended events in order", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// When
+		first := tc.event_is_appended("stream-1", 0)
+		second := tc.event_is_appended("stream-2", 0)
+
+		// Then
+		tc.due_positions_are(time.Now(), first.GlobalPosition, second.GlobalPosition)
+		tc.pending_is(2)
+	})
+
+	t.Run("records nothing for stores made without the outbox", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// Given
+		plain, err := NewEventStore(tc.db)
+		require.NoError(t, err)
+
+		// When
+		_, err = plain.Append(context.Background(), "realm-1", "stream-1", 0, []core.EventData{
+			{EventType: "Created", Data: map[string]string{}},
+		})
+
+		// Then
+		require.NoError(t, err)
+		tc.pending_is(0)
+	})
+
+	t.Run("records nothing for a unit of work that rolls back", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// Given
+		transactor, err := NewTransactor(tc.db, WithOutbox())
+		require.NoError(t, err)
+
+		// When
+		err = transactor.Transact(context.Background(), func(ctx context.Context, uow core.UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "stream-1", 0, []core.EventData{
+				{EventType: "Created", Data: map[string]string{}},
+			})
+			require.NoError(t, err)
+			return errors.New("projection failed")
+		})
+
+		// Then
+		assert.Error(t, err)
+		tc.pending_is(0)
+	})
+
+	t.Run("holds a retried entry back until its next attempt", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// Given
+		evt := tc.event_is_appended("stream-1", 0)
+		entry := tc.only_due_entry(time.Now())
+		next := time.Now().Add(time.Minute)
+
+		// When
+		require.NoError(t, tc.outbox.Retry(context.Background(), entry.ID, next, "connection refused"))
+
+		// Then
+		tc.due_positions_are(time.Now())
+		tc.due_positions_are(next, evt.GlobalPosition)
+		retried := tc.only_due_entry(next)
+		assert.Equal(t, 1, retried.Attempts)
+		assert.Equal(t, "connection refused", retried.LastError)
+	})
+
+	t.Run("removes delivered entries", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// Given
+		tc.event_is_appended("stream-1", 0)
+		entry := tc.only_due_entry(time.Now())
+
+		// When
+		require.NoError(t, tc.outbox.Delivered(context.Background(), entry.ID))
+
+		// Then
+		tc.pending_is(0)
+	})
+
+	t.Run("returns not found when retrying a missing entry", func(t *testing.T) {
+		tc := newOutboxTestContext(t)
+
+		// When
+		err := tc.outbox.Retry(context.Background(), 42, time.Now(), "boom")
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+}
+
+// --- Test Context ---
+
+type outboxTestContext struct {
+	t      *testing.T
+	db     *sql.DB
+	events *EventStore
+	outbox *Outbox
+}
+
+func newOutboxTestContext(t *testing.T) *outboxTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	events, err := NewEventStore(db, WithOutbox())
+	require.NoError(t, err)
+	outbox, err := NewOutbox(db)
+	require.NoError(t, err)
+	return &outboxTestContext{t: t, db: db, events: events, outbox: outbox}
+}
+
+// --- When ---
+
+func (tc *outboxTestContext) event_is_appended(streamID string, expectedVersion int) core.Event {
+	tc.t.Helper()
+	events, err := tc.events.Append(context.Background(), "realm-1", streamID, expectedVersion, []core.EventData{
+		{EventType: "Created", Data: map[string]string{}},
+	})
+	require.NoError(tc.t, err)
+	return events[0]
+}
+
+// --- Then ---
+
+func (tc *outboxTestContext) due_positions_are(now time.Time, expected ...int64) {
+	tc.t.Helper()
+	entries, err := tc.outbox.Due(context.Background(), now, 10)
+	require.NoError(tc.t, err)
+	var positions []int64
+	for _, entry := range entries {
+		assert.Equal(tc.t, "realm-1", entry.RealmID)
+		positions = append(positions, entry.GlobalPosition)
+	}
+	assert.Equal(tc.t, expected, positions)
+}
+
+func (tc *outboxTestContext) only_due_entry(now time.Time) core.OutboxEntry {
+	tc.t.Helper()
+	entries, err := tc.outbox.Due(context.Background(), now, 10)
+	require.NoError(tc.t, err)
+	require.Len(tc.t, entries, 1)
+	return entries[0]
+}
+
+func (tc *outboxTestContext) pending_is(expected int) {
+	tc.t.Helper()
+	n, err := tc.outbox.Pending(context.Background())
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, n)
+}
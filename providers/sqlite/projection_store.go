@@ -4,13 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
 
 	"github.com/devzeebo/bifrost/core"
 )
 
 // ProjectionStore is a SQLite-backed implementation of core.ProjectionStore.
 type ProjectionStore struct {
-	db *sql.DB
+	db      conn
+	indexes *projectionIndexes
 }
 
 // NewProjectionStore creates a new ProjectionStore backed by the given database.
@@ -18,7 +24,7 @@ func NewProjectionStore(db *sql.DB) (*ProjectionStore, error) {
 	if err := EnsureSchema(db); err != nil {
 		return nil, err
 	}
-	return &ProjectionStore{db: db}, nil
+	return &ProjectionStore{db: conn{db: db}, indexes: newProjectionIndexes()}, nil
 }
 
 // Get retrieves a projection value by realm, projection name, and key.
@@ -61,24 +67,241 @@ func (s *ProjectionStore) List(ctx context.Context, realmID string, projectionNa
 	return results, rows.Err()
 }
 
+// DumpProjections calls fn with each of the realm's projection rows, ordered
+// by projection name and key.
+func (s *ProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(core.ProjectionRow) error) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT projection_name, key, value FROM projections WHERE realm_id = ? ORDER BY projection_name, key`,
+		realmID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row core.ProjectionRow
+		var value []byte
+		if err := rows.Scan(&row.ProjectionName, &row.Key, &value); err != nil {
+			return err
+		}
+		row.Value = json.RawMessage(value)
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // Put upserts a projection value for the given realm, projection name, and key.
 func (s *ProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
-	_, err = s.db.ExecContext(ctx,
-		`INSERT OR REPLACE INTO projections (realm_id, projection_name, key, value) VALUES (?, ?, ?, ?)`,
-		realmID, projectionName, key, string(data),
-	)
-	return err
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data},
+	})
 }
 
 // Delete removes a projection entry. Deleting a non-existent key is not an error.
 func (s *ProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true},
+	})
+}
+
+// WriteBatch applies a batch of puts and deletes in a single transaction.
+func (s *ProjectionStore) WriteBatch(ctx context.Context, writes []core.ProjectionWrite) error {
+	return s.db.inTx(ctx, func(tx *sql.Tx) error {
+		return writeProjections(ctx, tx, writes)
+	})
+}
+
+func writeProjections(ctx context.Context, tx *sql.Tx, writes []core.ProjectionWrite) error {
+	var err error
+	for _, w := range writes {
+		if w.Delete {
+			_, err = tx.ExecContext(ctx,
+				`DELETE FROM projections WHERE realm_id = ? AND projection_name = ? AND key = ?`,
+				w.RealmID, w.ProjectionName, w.Key,
+			)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				`INSERT OR REPLACE INTO projections (realm_id, projection_name, key, value) VALUES (?, ?, ?, ?)`,
+				w.RealmID, w.ProjectionName, w.Key, string(w.Value),
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncateProjection deletes every entry of the projection in every realm.
+func (s *ProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM projections WHERE projection_name = ?`, projectionName)
+	return err
+}
+
+// TruncateRealmProjection deletes every entry of the projection in the
+// realm.
+func (s *ProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
 	_, err := s.db.ExecContext(ctx,
-		`DELETE FROM projections WHERE realm_id = ? AND projection_name = ? AND key = ?`,
-		realmID, projectionName, key,
+		`DELETE FROM projections WHERE realm_id = ? AND projection_name = ?`,
+		realmID, projectionName,
 	)
 	return err
 }
+
+// IndexFields indexes the fields of the projection's values, so lists of it
+// filtered on them are served by the database. The indexes are on the
+// fields' JSON expressions, limited to the projection's rows, and are
+// created over the rows already stored.
+func (s *ProjectionStore) IndexFields(ctx context.Context, projectionName string, fields []string) error {
+	if err := createFieldIndexes(ctx, s.db, projectionName, fields); err != nil {
+		return err
+	}
+	s.indexes.add(projectionName, fields)
+	return nil
+}
+
+// CanFilter reports whether every field of filter is indexed for the
+// projection.
+func (s *ProjectionStore) CanFilter(projectionName string, filter core.ProjectionFilter) bool {
+	return s.indexes.covers(projectionName, filter)
+}
+
+// ListWhere returns the projection values matching filter, filtering on the
+// indexed fields in the database.
+func (s *ProjectionStore) ListWhere(ctx context.Context, realmID string, projectionName string, filter core.ProjectionFilter) ([]json.RawMessage, error) {
+	if !s.CanFilter(projectionName, filter) {
+		return nil, fmt.Errorf("projection %q cannot be filtered on the given fields", projectionName)
+	}
+
+	query, args := filterQuery(realmID, projectionName, filter)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		results = append(results, json.RawMessage(value))
+	}
+	return results, rows.Err()
+}
+
+// filterQuery selects the realm's values of the projection matching filter.
+// The projection name is spelled out rather than bound, so the planner can
+// match the partial indexes.
+func filterQuery(realmID string, projectionName string, filter core.ProjectionFilter) (string, []any) {
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	query := `SELECT value FROM projections WHERE realm_id = ? AND projection_name = '` + projectionName + `'`
+	args := []any{realmID}
+	for _, field := range fields {
+		query += ` AND ` + fieldExpression(field) + ` = ?`
+		args = append(args, filter[field])
+	}
+	return query, args
+}
+
+// indexName matches the projection and field names that may be indexed, as
+// both are written into SQL.
+var indexName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// fieldExpression is the indexed form of a top-level field, as text so it
+// compares with filter values the way core.ProjectionFilter.Matches does.
+func fieldExpression(field string) string {
+	return `CAST(json_extract(value, '$.` + field + `') AS TEXT)`
+}
+
+func validateIndex(projectionName string, fields []string) error {
+	if !indexName.MatchString(projectionName) {
+		return fmt.Errorf("projection %q cannot be indexed", projectionName)
+	}
+	for _, field := range fields {
+		if !indexName.MatchString(field) {
+			return fmt.Errorf("field %q of %s cannot be indexed", field, projectionName)
+		}
+	}
+	return nil
+}
+
+func createFieldIndexes(ctx context.Context, db conn, projectionName string, fields []string) error {
+	if err := validateIndex(projectionName, fields); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		_, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS "idx_projections_`+projectionName+`_`+field+`"
+			ON projections(realm_id, projection_name, `+fieldExpression(field)+`)
+			WHERE projection_name = '`+projectionName+`'`)
+		if err != nil {
+			return fmt.Errorf("indexing %s.%s: %w", projectionName, field, err)
+		}
+	}
+	return nil
+}
+
+// projectionIndexes records the fields indexed per projection. It is shared
+// by the stores of one set of databases, and a nil one indexes nothing.
+type projectionIndexes struct {
+	mu     sync.RWMutex
+	fields map[string][]string
+}
+
+func newProjectionIndexes() *projectionIndexes {
+	return &projectionIndexes{fields: make(map[string][]string)}
+}
+
+func (x *projectionIndexes) add(projectionName string, fields []string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, field := range fields {
+		if !slices.Contains(x.fields[projectionName], field) {
+			x.fields[projectionName] = append(x.fields[projectionName], field)
+		}
+	}
+}
+
+func (x *projectionIndexes) covers(projectionName string, filter core.ProjectionFilter) bool {
+	if x == nil {
+		return false
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	indexed, ok := x.fields[projectionName]
+	if !ok {
+		return false
+	}
+	for field := range filter {
+		if !slices.Contains(indexed, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// create makes the recorded indexes in db, such as a realm database opened
+// after the fields were indexed.
+func (x *projectionIndexes) create(ctx context.Context, db conn) error {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	for projectionName, fields := range x.fields {
+		if err := createFieldIndexes(ctx, db, projectionName, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
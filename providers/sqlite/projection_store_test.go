@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/devzeebo/bifrost/core"
@@ -15,6 +16,11 @@ import (
 
 // Compile-time interface satisfaction check
 var _ core.ProjectionStore = (*ProjectionStore)(nil)
+var _ core.BatchProjectionStore = (*ProjectionStore)(nil)
+var _ core.FilteredProjectionStore = (*ProjectionStore)(nil)
+var _ core.ProjectionIndexer = (*ProjectionStore)(nil)
+var _ core.ProjectionDumper = (*ProjectionStore)(nil)
+var _ core.ProjectionTruncator = (*ProjectionStore)(nil)
 
 // --- Tests ---
 
@@ -237,6 +243,141 @@ func TestProjectionStore_Delete(t *testing.T) {
 	})
 }
 
+func TestProjectionStore_WriteBatch(t *testing.T) {
+	t.Run("applies puts and deletes together", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+		tc.a_simple_value("old")
+		tc.put_is_called("realm-1", "data", "key-1")
+
+		// When
+		tc.write_batch_is_called(
+			core.ProjectionWrite{RealmID: "realm-1", ProjectionName: "data", Key: "key-1", Delete: true},
+			core.ProjectionWrite{RealmID: "realm-1", ProjectionName: "data", Key: "key-2", Value: json.RawMessage(`"new"`)},
+		)
+
+		// Then
+		tc.no_error_occurred()
+		tc.get_is_called("realm-1", "data", "key-1")
+		tc.not_found_error_is_returned("data", "key-1")
+		tc.get_is_called("realm-1", "data", "key-2")
+		tc.retrieved_value_equals("new")
+	})
+}
+
+func TestProjectionStore_ListWhere(t *testing.T) {
+	t.Run("filters on indexed fields", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+		tc.fields_are_indexed("rune_list", "status", "priority", "claimant")
+		tc.projection_has_entries("realm-1", "rune_list", map[string]string{
+			"bf-1": `{"id":"bf-1","status":"open","priority":1}`,
+			"bf-2": `{"id":"bf-2","status":"claimed","priority":1,"claimant":"alice"}`,
+			"bf-3": `{"id":"bf-3","status":"open","priority":2}`,
+		})
+		tc.projection_has_entries("realm-2", "rune_list", map[string]string{
+			"bf-4": `{"id":"bf-4","status":"open","priority":1}`,
+		})
+
+		// When
+		tc.list_where_is_called("realm-1", "rune_list", core.ProjectionFilter{"status": "open", "priority": "1"})
+
+		// Then
+		tc.no_error_occurred()
+		tc.listed_ids_are("bf-1")
+	})
+
+	t.Run("follows updates and deletes", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+		tc.fields_are_indexed("rune_list", "status", "claimant")
+		tc.projection_has_entries("realm-1", "rune_list", map[string]string{
+			"bf-1": `{"id":"bf-1","status":"open","priority":1}`,
+			"bf-2": `{"id":"bf-2","status":"open","priority":1}`,
+		})
+		tc.projection_has_entries("realm-1", "rune_list", map[string]string{
+			"bf-1": `{"id":"bf-1","status":"claimed","priority":1,"claimant":"alice"}`,
+		})
+		tc.delete_is_called("realm-1", "rune_list", "bf-2")
+
+		// When
+		tc.list_where_is_called("realm-1", "rune_list", core.ProjectionFilter{"claimant": "alice"})
+
+		// Then
+		tc.no_error_occurred()
+		tc.listed_ids_are("bf-1")
+		tc.list_where_is_called("realm-1", "rune_list", core.ProjectionFilter{"status": "open"})
+		tc.listed_ids_are()
+	})
+
+	t.Run("covers entries written before the fields were indexed", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.a_raw_projection_row("realm-1", "rune_list", "bf-1", `{"id":"bf-1","status":"open","branch":"main"}`)
+		tc.new_projection_store_is_created()
+		tc.fields_are_indexed("rune_list", "branch")
+
+		// When
+		tc.list_where_is_called("realm-1", "rune_list", core.ProjectionFilter{"branch": "main"})
+
+		// Then
+		tc.no_error_occurred()
+		tc.listed_ids_are("bf-1")
+	})
+
+	t.Run("filters in the database through the index", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+		tc.fields_are_indexed("rune_list", "status")
+
+		// Then
+		tc.filtered_list_uses_index("rune_list", "status", "idx_projections_rune_list_status")
+	})
+
+	t.Run("only filters indexed fields", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+		tc.fields_are_indexed("rune_list", "status", "parent_id")
+
+		// Then
+		assert.True(t, tc.store.CanFilter("rune_list", core.ProjectionFilter{"status": "open", "parent_id": "bf-1"}))
+		assert.False(t, tc.store.CanFilter("rune_list", core.ProjectionFilter{"title": "Bridge"}))
+		assert.False(t, tc.store.CanFilter("rune_detail", core.ProjectionFilter{"status": "open"}))
+	})
+
+	t.Run("refuses names it cannot write into SQL", func(t *testing.T) {
+		tc := newProjectionTestContext(t)
+
+		// Given
+		tc.a_database_with_schema()
+		tc.new_projection_store_is_created()
+
+		// When
+		tc.err = tc.store.IndexFields(context.Background(), "rune_list", []string{"status') --"})
+
+		// Then
+		require.Error(t, tc.err)
+		assert.False(t, tc.store.CanFilter("rune_list", core.ProjectionFilter{"status": "open"}))
+	})
+}
+
 func TestProjectionStore_Put_StoresValueAsText(t *testing.T) {
 	t.Run("stores value as text not blob", func(t *testing.T) {
 		tc := newProjectionTestContext(t)
@@ -330,8 +471,22 @@ func (tc *projectionTestContext) projection_has_entries(realmID, projectionName
 	}
 }
 
+func (tc *projectionTestContext) a_raw_projection_row(realmID, projectionName, key, value string) {
+	tc.t.Helper()
+	_, err := tc.db.Exec(
+		`INSERT INTO projections (realm_id, projection_name, key, value) VALUES (?, ?, ?, ?)`,
+		realmID, projectionName, key, value,
+	)
+	require.NoError(tc.t, err)
+}
+
 // --- When ---
 
+func (tc *projectionTestContext) fields_are_indexed(projectionName string, fields ...string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.store.IndexFields(context.Background(), projectionName, fields))
+}
+
 func (tc *projectionTestContext) new_projection_store_is_created() {
 	tc.t.Helper()
 	tc.store, tc.err = NewProjectionStore(tc.db)
@@ -366,11 +521,21 @@ func (tc *projectionTestContext) get_complex_is_called(realmID, projectionName,
 	tc.err = tc.store.Get(context.Background(), realmID, projectionName, key, &tc.retrievedProf)
 }
 
+func (tc *projectionTestContext) write_batch_is_called(writes ...core.ProjectionWrite) {
+	tc.t.Helper()
+	tc.err = tc.store.WriteBatch(context.Background(), writes)
+}
+
 func (tc *projectionTestContext) list_is_called(realmID, projectionName string) {
 	tc.t.Helper()
 	tc.listResult, tc.err = tc.store.List(context.Background(), realmID, projectionName)
 }
 
+func (tc *projectionTestContext) list_where_is_called(realmID, projectionName string, filter core.ProjectionFilter) {
+	tc.t.Helper()
+	tc.listResult, tc.err = tc.store.ListWhere(context.Background(), realmID, projectionName, filter)
+}
+
 // --- Then ---
 
 func (tc *projectionTestContext) no_error_occurred() {
@@ -415,3 +580,32 @@ func (tc *projectionTestContext) stored_projection_value_column_has_type(expecte
 	require.NoError(tc.t, err)
 	assert.Equal(tc.t, expectedType, colType)
 }
+
+func (tc *projectionTestContext) filtered_list_uses_index(projectionName, field, index string) {
+	tc.t.Helper()
+	query, args := filterQuery("realm-1", projectionName, core.ProjectionFilter{field: "open"})
+	rows, err := tc.db.Query(`EXPLAIN QUERY PLAN `+query, args...)
+	require.NoError(tc.t, err)
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		require.NoError(tc.t, rows.Scan(&id, &parent, &unused, &detail))
+		plan = append(plan, detail)
+	}
+	assert.Contains(tc.t, strings.Join(plan, "\n"), index)
+}
+
+func (tc *projectionTestContext) listed_ids_are(expected ...string) {
+	tc.t.Helper()
+	ids := make([]string, 0, len(tc.listResult))
+	for _, raw := range tc.listResult {
+		var item struct {
+			ID string `json:"id"`
+		}
+		require.NoError(tc.t, json.Unmarshal(raw, &item))
+		ids = append(ids, item.ID)
+	}
+	assert.ElementsMatch(tc.t, expected, ids)
+}
@@ -25,12 +25,112 @@ func EnsureSchema(db *sql.DB) error {
 			value TEXT,
 			PRIMARY KEY(realm_id, projection_name, key)
 		)`,
+		// rune_list was indexed through a table of its own before projectors
+		// declared their indexed fields (see ProjectionStore.IndexFields).
+		`DROP TABLE IF EXISTS rune_list_index`,
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			realm_id TEXT NOT NULL,
+			stream_id TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			first_version INTEGER NOT NULL,
+			last_version INTEGER NOT NULL,
+			PRIMARY KEY(realm_id, stream_id, idempotency_key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS archived_events (
+			global_position INTEGER PRIMARY KEY,
+			realm_id TEXT NOT NULL,
+			stream_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			data TEXT,
+			metadata TEXT,
+			timestamp DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_archived_events_stream ON archived_events(realm_id, stream_id, version)`,
+		`CREATE TABLE IF NOT EXISTS realm_data_keys (
+			realm_id TEXT PRIMARY KEY,
+			wrapped_key BLOB NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS snapshots (
+			realm_id TEXT NOT NULL,
+			stream_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			state TEXT NOT NULL,
+			PRIMARY KEY(realm_id, stream_id)
+		)`,
 		`CREATE TABLE IF NOT EXISTS checkpoints (
 			realm_id TEXT NOT NULL,
 			projector_name TEXT NOT NULL,
 			last_global_position INTEGER NOT NULL DEFAULT 0,
 			PRIMARY KEY(realm_id, projector_name)
 		)`,
+		`CREATE TABLE IF NOT EXISTS projector_versions (
+			projector_name TEXT NOT NULL PRIMARY KEY,
+			version INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS leases (
+			name TEXT PRIMARY KEY,
+			holder TEXT NOT NULL,
+			expires_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS command_queue (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT NOT NULL UNIQUE,
+			realm_id TEXT NOT NULL,
+			account_id TEXT,
+			role TEXT,
+			command TEXT NOT NULL,
+			payload TEXT,
+			headers TEXT,
+			status TEXT NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			result TEXT,
+			position INTEGER NOT NULL DEFAULT 0,
+			enqueued_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_command_queue_status ON command_queue(status, seq)`,
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			realm_id TEXT NOT NULL,
+			global_position INTEGER NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			next_attempt_at INTEGER NOT NULL,
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(next_attempt_at, id)`,
+		`CREATE TABLE IF NOT EXISTS form_drafts (
+			account_id TEXT NOT NULL,
+			realm_id TEXT NOT NULL,
+			form_key TEXT NOT NULL,
+			fields TEXT NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY(account_id, realm_id, form_key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS sync_receipts (
+			realm_id TEXT NOT NULL,
+			account_id TEXT NOT NULL,
+			idempotency_key TEXT NOT NULL,
+			command TEXT NOT NULL,
+			status_code INTEGER NOT NULL,
+			result TEXT,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY(realm_id, account_id, idempotency_key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS dead_letters (
+			realm_id TEXT NOT NULL,
+			projector TEXT NOT NULL,
+			global_position INTEGER NOT NULL,
+			stream_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			error TEXT NOT NULL,
+			attempts INTEGER NOT NULL,
+			halted INTEGER NOT NULL,
+			failed_at INTEGER NOT NULL,
+			PRIMARY KEY(realm_id, projector, global_position)
+		)`,
 		`CREATE TABLE IF NOT EXISTS agents (
 			id TEXT PRIMARY KEY,
 			name TEXT NOT NULL,
@@ -81,5 +181,21 @@ func EnsureSchema(db *sql.DB) error {
 		}
 	}
 
-	return nil
+	// Queues created before commands kept their headers or positions lack
+	// the columns.
+	if err := addColumn(db, "command_queue", "headers", "TEXT"); err != nil {
+		return err
+	}
+	return addColumn(db, "command_queue", "position", "INTEGER NOT NULL DEFAULT 0")
+}
+
+// addColumn adds a column to an existing table unless it is already there.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	if err != nil || n > 0 {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition)
+	return err
 }
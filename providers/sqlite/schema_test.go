@@ -26,6 +26,10 @@ func TestEnsureSchema(t *testing.T) {
 		tc.events_table_exists()
 		tc.projections_table_exists()
 		tc.checkpoints_table_exists()
+		tc.snapshots_table_exists()
+		tc.idempotency_keys_table_exists()
+		tc.realm_data_keys_table_exists()
+		tc.archived_events_table_exists()
 	})
 
 	t.Run("is idempotent", func(t *testing.T) {
@@ -57,6 +61,21 @@ func TestEnsureSchema(t *testing.T) {
 		tc.index_exists("idx_events_realm_global")
 	})
 
+	t.Run("drops the rune_list index table", func(t *testing.T) {
+		tc := newSchemaTestContext(t)
+
+		// Given
+		tc.an_empty_database()
+		tc.a_legacy_rune_list_index_table()
+
+		// When
+		tc.ensure_schema_is_called()
+
+		// Then
+		tc.no_error_occurred()
+		tc.table_does_not_exist("rune_list_index")
+	})
+
 	t.Run("creates agent projection tables", func(t *testing.T) {
 		tc := newSchemaTestContext(t)
 
@@ -102,6 +121,12 @@ func (tc *schemaTestContext) an_empty_database() {
 	tc.t.Cleanup(func() { db.Close() })
 }
 
+func (tc *schemaTestContext) a_legacy_rune_list_index_table() {
+	tc.t.Helper()
+	_, err := tc.db.Exec(`CREATE TABLE rune_list_index (realm_id TEXT NOT NULL, key TEXT NOT NULL, status TEXT, PRIMARY KEY(realm_id, key))`)
+	require.NoError(tc.t, err)
+}
+
 // --- When ---
 
 func (tc *schemaTestContext) ensure_schema_is_called() {
@@ -131,6 +156,26 @@ func (tc *schemaTestContext) checkpoints_table_exists() {
 	tc.table_exists("checkpoints")
 }
 
+func (tc *schemaTestContext) snapshots_table_exists() {
+	tc.t.Helper()
+	tc.table_exists("snapshots")
+}
+
+func (tc *schemaTestContext) idempotency_keys_table_exists() {
+	tc.t.Helper()
+	tc.table_exists("idempotency_keys")
+}
+
+func (tc *schemaTestContext) archived_events_table_exists() {
+	tc.t.Helper()
+	tc.table_exists("archived_events")
+}
+
+func (tc *schemaTestContext) realm_data_keys_table_exists() {
+	tc.t.Helper()
+	tc.table_exists("realm_data_keys")
+}
+
 func (tc *schemaTestContext) agents_table_exists() {
 	tc.t.Helper()
 	tc.table_exists("agents")
@@ -181,6 +226,16 @@ func (tc *schemaTestContext) table_exists(name string) {
 	assert.Equal(tc.t, 1, count, "expected table %q to exist", name)
 }
 
+func (tc *schemaTestContext) table_does_not_exist(name string) {
+	tc.t.Helper()
+	var count int
+	err := tc.db.QueryRow(
+		"SELECT count(*) FROM sqlite_master WHERE type='table' AND name=?", name,
+	).Scan(&count)
+	require.NoError(tc.t, err)
+	assert.Zero(tc.t, count, "expected table %q not to exist", name)
+}
+
 func (tc *schemaTestContext) index_exists(name string) {
 	tc.t.Helper()
 	var count int
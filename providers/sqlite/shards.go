@@ -0,0 +1,597 @@
+package sqlite
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// shardExt is the extension of a realm's database file.
+const shardExt = ".db"
+
+// errNoShard is returned when a realm that has no database file is read.
+var errNoShard = errors.New("realm has no database")
+
+// Shards keeps one SQLite database per realm in a directory, so realms do
+// not contend for the same file. A realm's database is opened the first time
+// it is written to or read, and the least recently used ones are closed once
+// more than the maximum are open. Reading a realm that has no file yet does
+// not create one.
+type Shards struct {
+	dir       string
+	maxOpen   int
+	tuning    Tuning
+	eventOpts []EventStoreOption
+	indexes   *projectionIndexes
+
+	mu   sync.Mutex
+	open map[string]*shard
+	lru  *list.List // realm IDs, most recently used first
+}
+
+// shard is an open realm database. It is closed once it has been evicted
+// and no operation is using it.
+type shard struct {
+	db          *sql.DB
+	events      *EventStore
+	projections *ProjectionStore
+	checkpoints *CheckpointStore
+	elem        *list.Element
+	refs        int
+	evicted     bool
+}
+
+// NewShards creates the directory if needed and returns shards keeping at
+// most maxOpen databases open, each opened with tuning. The event stores of
+// the shards are configured with opts, like those made by NewEventStore.
+func NewShards(dir string, maxOpen int, tuning Tuning, opts ...EventStoreOption) (*Shards, error) {
+	if err := tuning.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if maxOpen < 1 {
+		maxOpen = 1
+	}
+	return &Shards{
+		dir:       dir,
+		maxOpen:   maxOpen,
+		tuning:    tuning,
+		eventOpts: opts,
+		indexes:   newProjectionIndexes(),
+		open:      make(map[string]*shard),
+		lru:       list.New(),
+	}, nil
+}
+
+// EventStore returns an event store routing each call to its realm's database.
+func (s *Shards) EventStore() *ShardedEventStore {
+	return &ShardedEventStore{shards: s}
+}
+
+// ProjectionStore returns a projection store routing each call to its realm's database.
+func (s *Shards) ProjectionStore() *ShardedProjectionStore {
+	return &ShardedProjectionStore{shards: s}
+}
+
+// CheckpointStore returns a checkpoint store routing each call to its realm's database.
+func (s *Shards) CheckpointStore() *ShardedCheckpointStore {
+	return &ShardedCheckpointStore{shards: s}
+}
+
+// Close closes every open database. Those still in use are closed when
+// their operations finish.
+func (s *Shards) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for realmID, sh := range s.open {
+		s.evict(realmID, sh)
+		if sh.refs == 0 {
+			errs = append(errs, sh.db.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// with runs fn with the realm's database, opening it if needed. Unless
+// create is set, a realm without a database file gets errNoShard.
+func (s *Shards) with(realmID string, create bool, fn func(sh *shard) error) error {
+	sh, err := s.acquire(realmID, create)
+	if err != nil {
+		return err
+	}
+	defer s.release(sh)
+	return fn(sh)
+}
+
+func (s *Shards) acquire(realmID string, create bool) (*shard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sh, ok := s.open[realmID]; ok {
+		sh.refs++
+		s.lru.MoveToFront(sh.elem)
+		return sh, nil
+	}
+
+	path := s.path(realmID)
+	if !create {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, errNoShard
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	db, err := Open(path, s.tuning)
+	if err != nil {
+		return nil, err
+	}
+	events, err := NewEventStore(db, s.eventOpts...)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.indexes.create(context.Background(), conn{db: db}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	sh := &shard{
+		db:          db,
+		events:      events,
+		projections: &ProjectionStore{db: conn{db: db}, indexes: s.indexes},
+		checkpoints: &CheckpointStore{db: conn{db: db}},
+		refs:        1,
+	}
+	sh.elem = s.lru.PushFront(realmID)
+	s.open[realmID] = sh
+
+	for len(s.open) > s.maxOpen {
+		oldest := s.lru.Back().Value.(string)
+		old := s.open[oldest]
+		s.evict(oldest, old)
+		if old.refs == 0 {
+			old.db.Close()
+		}
+	}
+	return sh, nil
+}
+
+// openRealmIDs returns the realms whose databases are open.
+func (s *Shards) openRealmIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	realmIDs := make([]string, 0, len(s.open))
+	for realmID := range s.open {
+		realmIDs = append(realmIDs, realmID)
+	}
+	return realmIDs
+}
+
+func (s *Shards) release(sh *shard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh.refs--
+	if sh.evicted && sh.refs == 0 {
+		sh.db.Close()
+	}
+}
+
+// evict forgets an open database, so the next operation on the realm opens
+// it again. The caller closes it once it is no longer in use.
+func (s *Shards) evict(realmID string, sh *shard) {
+	s.lru.Remove(sh.elem)
+	delete(s.open, realmID)
+	sh.evicted = true
+}
+
+// path returns the realm's database file. Realm IDs are escaped, so any ID
+// makes a single file name in the directory.
+func (s *Shards) path(realmID string) string {
+	return filepath.Join(s.dir, url.PathEscape(realmID)+shardExt)
+}
+
+// realmIDs returns the realms that have a database file.
+func (s *Shards) realmIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var realmIDs []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), shardExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		realmID, err := url.PathUnescape(name)
+		if err != nil {
+			continue
+		}
+		realmIDs = append(realmIDs, realmID)
+	}
+	return realmIDs, nil
+}
+
+// ShardedEventStore is a core.EventStore keeping each realm's events in the
+// realm's own database. Global positions are ordered within a realm only,
+// so it cannot offer subscriptions across realms.
+type ShardedEventStore struct {
+	shards *Shards
+}
+
+// Append persists new events to a stream in the realm's database, creating
+// the database if the realm has none.
+func (s *ShardedEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	var result []core.Event
+	err := s.shards.with(realmID, true, func(sh *shard) error {
+		var err error
+		result, err = sh.events.Append(ctx, realmID, streamID, expectedVersion, events)
+		return err
+	})
+	return result, err
+}
+
+// ReadStream returns events for a specific stream starting from the given version.
+func (s *ShardedEventStore) ReadStream(ctx context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	return s.read(realmID, func(sh *shard) ([]core.Event, error) {
+		return sh.events.ReadStream(ctx, realmID, streamID, fromVersion)
+	})
+}
+
+// ReadStreamPage returns up to limit events of the stream from a version,
+// forward or backward.
+func (s *ShardedEventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir core.Direction) ([]core.Event, error) {
+	return s.read(realmID, func(sh *shard) ([]core.Event, error) {
+		return sh.events.ReadStreamPage(ctx, realmID, streamID, from, limit, dir)
+	})
+}
+
+// ReadAll returns events across all streams in a realm starting from the given global position.
+func (s *ShardedEventStore) ReadAll(ctx context.Context, realmID string, fromGlobalPosition int64) ([]core.Event, error) {
+	return s.read(realmID, func(sh *shard) ([]core.Event, error) {
+		return sh.events.ReadAll(ctx, realmID, fromGlobalPosition)
+	})
+}
+
+// ReadAllBatch returns up to limit events in a realm after the given global
+// position. A limit of zero or less returns them all.
+func (s *ShardedEventStore) ReadAllBatch(ctx context.Context, realmID string, fromGlobalPosition int64, limit int) ([]core.Event, error) {
+	return s.read(realmID, func(sh *shard) ([]core.Event, error) {
+		return sh.events.ReadAllBatch(ctx, realmID, fromGlobalPosition, limit)
+	})
+}
+
+// read runs fn on the realm's database, reading a realm without one as
+// having no events.
+func (s *ShardedEventStore) read(realmID string, fn func(sh *shard) ([]core.Event, error)) ([]core.Event, error) {
+	var events []core.Event
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		events, err = fn(sh)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return []core.Event{}, nil
+	}
+	return events, err
+}
+
+// ListRealmIDs returns the realms that have a database.
+func (s *ShardedEventStore) ListRealmIDs(ctx context.Context) ([]string, error) {
+	return s.shards.realmIDs()
+}
+
+// GetSnapshot returns the stream's latest snapshot. Returns
+// core.NotFoundError if the stream has none.
+func (s *ShardedEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	var snapshot core.Snapshot
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		snapshot, err = sh.events.GetSnapshot(ctx, realmID, streamID)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	return snapshot, err
+}
+
+// SaveSnapshot replaces the stream's snapshot.
+func (s *ShardedEventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	return s.shards.with(snapshot.RealmID, true, func(sh *shard) error {
+		return sh.events.SaveSnapshot(ctx, snapshot)
+	})
+}
+
+// GetDataKey returns the realm's wrapped data key. Returns
+// core.NotFoundError if the realm has none.
+func (s *ShardedEventStore) GetDataKey(ctx context.Context, realmID string) ([]byte, error) {
+	var wrapped []byte
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		wrapped, err = sh.events.GetDataKey(ctx, realmID)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return nil, &core.NotFoundError{Entity: "data key", ID: realmID}
+	}
+	return wrapped, err
+}
+
+// AddDataKey stores the realm's wrapped data key unless it already has one.
+func (s *ShardedEventStore) AddDataKey(ctx context.Context, realmID string, wrapped []byte) error {
+	return s.shards.with(realmID, true, func(sh *shard) error {
+		return sh.events.AddDataKey(ctx, realmID, wrapped)
+	})
+}
+
+// RedactStream redacts the stream's events in the realm's database.
+func (s *ShardedEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []core.Event) error {
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		return sh.events.RedactStream(ctx, realmID, streamID, redacted)
+	})
+	if errors.Is(err, errNoShard) {
+		return nil
+	}
+	return err
+}
+
+// ArchiveStreams archives matching streams in every realm's database, one
+// realm at a time, and returns how many it archived.
+func (s *ShardedEventStore) ArchiveStreams(ctx context.Context, lastEventType string, before time.Time) (int, error) {
+	realmIDs, err := s.shards.realmIDs()
+	if err != nil {
+		return 0, err
+	}
+	archived := 0
+	for _, realmID := range realmIDs {
+		err := s.shards.with(realmID, false, func(sh *shard) error {
+			n, err := sh.events.ArchiveStreams(ctx, lastEventType, before)
+			archived += n
+			return err
+		})
+		if err != nil && !errors.Is(err, errNoShard) {
+			return archived, err
+		}
+	}
+	return archived, nil
+}
+
+// TruncatableStreams lists the matching streams in the realm's database.
+func (s *ShardedEventStore) TruncatableStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) ([]string, error) {
+	var streamIDs []string
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		streamIDs, err = sh.events.TruncatableStreams(ctx, realmID, lastEventType, before)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return nil, nil
+	}
+	return streamIDs, err
+}
+
+// TruncateStreams truncates matching streams in the realm's database.
+func (s *ShardedEventStore) TruncateStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) (int, error) {
+	truncated := 0
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		truncated, err = sh.events.TruncateStreams(ctx, realmID, lastEventType, before)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return 0, nil
+	}
+	return truncated, err
+}
+
+// ShardedProjectionStore is a core.ProjectionStore keeping each realm's
+// projections in the realm's own database.
+type ShardedProjectionStore struct {
+	shards *Shards
+}
+
+// Get retrieves a projection value by realm, projection name, and key.
+// Returns core.NotFoundError if no row is found.
+func (s *ShardedProjectionStore) Get(ctx context.Context, realmID string, projectionName string, key string, dest any) error {
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		return sh.projections.Get(ctx, realmID, projectionName, key, dest)
+	})
+	if errors.Is(err, errNoShard) {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	return err
+}
+
+// List returns all projection values for the given realm and projection name.
+func (s *ShardedProjectionStore) List(ctx context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	var results []json.RawMessage
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		results, err = sh.projections.List(ctx, realmID, projectionName)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return []json.RawMessage{}, nil
+	}
+	return results, err
+}
+
+// Put upserts a projection value for the given realm, projection name, and key.
+func (s *ShardedProjectionStore) Put(ctx context.Context, realmID string, projectionName string, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Value: data},
+	})
+}
+
+// Delete removes a projection entry. Deleting a non-existent key is not an error.
+func (s *ShardedProjectionStore) Delete(ctx context.Context, realmID string, projectionName string, key string) error {
+	return s.WriteBatch(ctx, []core.ProjectionWrite{
+		{RealmID: realmID, ProjectionName: projectionName, Key: key, Delete: true},
+	})
+}
+
+// WriteBatch applies the writes of each realm in a transaction of the
+// realm's database. A batch spanning realms is not atomic across them.
+// Deletes alone do not create a database for a realm that has none.
+func (s *ShardedProjectionStore) WriteBatch(ctx context.Context, writes []core.ProjectionWrite) error {
+	var realmIDs []string
+	byRealm := make(map[string][]core.ProjectionWrite)
+	for _, w := range writes {
+		if _, ok := byRealm[w.RealmID]; !ok {
+			realmIDs = append(realmIDs, w.RealmID)
+		}
+		byRealm[w.RealmID] = append(byRealm[w.RealmID], w)
+	}
+	for _, realmID := range realmIDs {
+		realmWrites := byRealm[realmID]
+		create := false
+		for _, w := range realmWrites {
+			create = create || !w.Delete
+		}
+		err := s.shards.with(realmID, create, func(sh *shard) error {
+			return sh.projections.WriteBatch(ctx, realmWrites)
+		})
+		if err != nil && !errors.Is(err, errNoShard) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpProjections calls fn with each of the realm's projection rows. A realm
+// without a database has none.
+func (s *ShardedProjectionStore) DumpProjections(ctx context.Context, realmID string, fn func(core.ProjectionRow) error) error {
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		return sh.projections.DumpProjections(ctx, realmID, fn)
+	})
+	if errors.Is(err, errNoShard) {
+		return nil
+	}
+	return err
+}
+
+// TruncateProjection deletes every entry of the projection in each realm's
+// database.
+func (s *ShardedProjectionStore) TruncateProjection(ctx context.Context, projectionName string) error {
+	realmIDs, err := s.shards.realmIDs()
+	if err != nil {
+		return err
+	}
+	for _, realmID := range realmIDs {
+		err := s.shards.with(realmID, false, func(sh *shard) error {
+			return sh.projections.TruncateProjection(ctx, projectionName)
+		})
+		if err != nil && !errors.Is(err, errNoShard) {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncateRealmProjection deletes every entry of the projection in the
+// realm's database. A realm without a database has none.
+func (s *ShardedProjectionStore) TruncateRealmProjection(ctx context.Context, realmID string, projectionName string) error {
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		return sh.projections.TruncateRealmProjection(ctx, realmID, projectionName)
+	})
+	if errors.Is(err, errNoShard) {
+		return nil
+	}
+	return err
+}
+
+// IndexFields indexes the fields of the projection's values in every realm's
+// database: those open now, and the others as they are opened.
+func (s *ShardedProjectionStore) IndexFields(ctx context.Context, projectionName string, fields []string) error {
+	if err := validateIndex(projectionName, fields); err != nil {
+		return err
+	}
+	s.shards.indexes.add(projectionName, fields)
+	for _, realmID := range s.shards.openRealmIDs() {
+		err := s.shards.with(realmID, false, func(sh *shard) error {
+			return createFieldIndexes(ctx, sh.projections.db, projectionName, fields)
+		})
+		if err != nil && !errors.Is(err, errNoShard) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanFilter reports whether every field of filter is indexed for the
+// projection.
+func (s *ShardedProjectionStore) CanFilter(projectionName string, filter core.ProjectionFilter) bool {
+	return s.shards.indexes.covers(projectionName, filter)
+}
+
+// ListWhere returns the projection values of the realm matching filter.
+func (s *ShardedProjectionStore) ListWhere(ctx context.Context, realmID string, projectionName string, filter core.ProjectionFilter) ([]json.RawMessage, error) {
+	var results []json.RawMessage
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		results, err = sh.projections.ListWhere(ctx, realmID, projectionName, filter)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return []json.RawMessage{}, nil
+	}
+	return results, err
+}
+
+// ShardedCheckpointStore is a core.CheckpointStore keeping each realm's
+// checkpoints in the realm's own database.
+type ShardedCheckpointStore struct {
+	shards *Shards
+}
+
+// GetCheckpoint returns the last global position for the given projector.
+// Returns 0 if no checkpoint exists.
+func (s *ShardedCheckpointStore) GetCheckpoint(ctx context.Context, realmID string, projectorName string) (int64, error) {
+	var pos int64
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		pos, err = sh.checkpoints.GetCheckpoint(ctx, realmID, projectorName)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return 0, nil
+	}
+	return pos, err
+}
+
+// SetCheckpoint upserts the checkpoint for the given projector.
+func (s *ShardedCheckpointStore) SetCheckpoint(ctx context.Context, realmID string, projectorName string, globalPosition int64) error {
+	return s.shards.with(realmID, true, func(sh *shard) error {
+		return sh.checkpoints.SetCheckpoint(ctx, realmID, projectorName, globalPosition)
+	})
+}
+
+// ListCheckpoints returns every projector's checkpoint in the realm.
+func (s *ShardedCheckpointStore) ListCheckpoints(ctx context.Context, realmID string) (map[string]int64, error) {
+	checkpoints := map[string]int64{}
+	err := s.shards.with(realmID, false, func(sh *shard) error {
+		var err error
+		checkpoints, err = sh.checkpoints.ListCheckpoints(ctx, realmID)
+		return err
+	})
+	if errors.Is(err, errNoShard) {
+		return map[string]int64{}, nil
+	}
+	return checkpoints, err
+}
@@ -0,0 +1,227 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ core.BatchEventReader        = (*ShardedEventStore)(nil)
+	_ core.StreamPageReader        = (*ShardedEventStore)(nil)
+	_ core.SnapshotStore           = (*ShardedEventStore)(nil)
+	_ core.DataKeyStore            = (*ShardedEventStore)(nil)
+	_ core.EventRedactor           = (*ShardedEventStore)(nil)
+	_ core.EventArchiver           = (*ShardedEventStore)(nil)
+	_ core.EventTruncator          = (*ShardedEventStore)(nil)
+	_ core.BatchProjectionStore    = (*ShardedProjectionStore)(nil)
+	_ core.FilteredProjectionStore = (*ShardedProjectionStore)(nil)
+	_ core.ProjectionDumper        = (*ShardedProjectionStore)(nil)
+	_ core.ProjectionTruncator     = (*ShardedProjectionStore)(nil)
+	_ core.ProjectionIndexer       = (*ShardedProjectionStore)(nil)
+	_ core.CheckpointLister        = (*ShardedCheckpointStore)(nil)
+)
+
+// --- Tests ---
+
+func TestShards(t *testing.T) {
+	t.Run("creates a realm's database on its first append", func(t *testing.T) {
+		tc := newShardsTestContext(t, 4)
+
+		// When
+		tc.event_is_appended("realm-1", "stream-1", 0)
+
+		// Then
+		tc.database_exists("realm-1")
+		tc.database_does_not_exist("realm-2")
+	})
+
+	t.Run("does not create a database to read a realm", func(t *testing.T) {
+		tc := newShardsTestContext(t, 4)
+
+		// When
+		events, err := tc.shards.EventStore().ReadAll(context.Background(), "realm-1", 0)
+		var dest map[string]any
+		getErr := tc.shards.ProjectionStore().Get(context.Background(), "realm-1", "rune_list", "bf-1", &dest)
+		pos, posErr := tc.shards.CheckpointStore().GetCheckpoint(context.Background(), "realm-1", "rune_list")
+
+		// Then
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, getErr, &nfe)
+		require.NoError(t, posErr)
+		assert.Zero(t, pos)
+		tc.database_does_not_exist("realm-1")
+	})
+
+	t.Run("keeps each realm's events in its own database", func(t *testing.T) {
+		tc := newShardsTestContext(t, 4)
+
+		// When
+		first := tc.event_is_appended("realm-1", "stream-1", 0)
+		other := tc.event_is_appended("realm-2", "stream-1", 0)
+
+		// Then
+		assert.Equal(t, int64(1), first.GlobalPosition)
+		assert.Equal(t, int64(1), other.GlobalPosition)
+		tc.realms_are_listed("realm-1", "realm-2")
+	})
+
+	t.Run("closes the least recently used database past the limit", func(t *testing.T) {
+		tc := newShardsTestContext(t, 2)
+
+		// Given
+		tc.event_is_appended("realm-1", "stream-1", 0)
+		tc.event_is_appended("realm-2", "stream-1", 0)
+		tc.event_is_appended("realm-1", "stream-1", 1)
+
+		// When
+		tc.event_is_appended("realm-3", "stream-1", 0)
+
+		// Then
+		tc.open_realms_are("realm-3", "realm-1")
+		events, err := tc.shards.EventStore().ReadStream(context.Background(), "realm-2", "stream-1", 0)
+		require.NoError(t, err)
+		assert.Len(t, events, 1, "an evicted realm is opened again")
+		tc.open_realms_are("realm-2", "realm-3")
+	})
+
+	t.Run("does not close a database while it is in use", func(t *testing.T) {
+		tc := newShardsTestContext(t, 1)
+
+		// Given
+		tc.event_is_appended("realm-1", "stream-1", 0)
+
+		// When
+		err := tc.shards.with("realm-1", false, func(sh *shard) error {
+			tc.event_is_appended("realm-2", "stream-1", 0)
+			_, err := sh.events.ReadAll(context.Background(), "realm-1", 0)
+			return err
+		})
+
+		// Then
+		require.NoError(t, err)
+		tc.open_realms_are("realm-2")
+	})
+
+	t.Run("escapes realm IDs into file names", func(t *testing.T) {
+		tc := newShardsTestContext(t, 4)
+
+		// When
+		tc.event_is_appended("team/a b", "stream-1", 0)
+
+		// Then
+		tc.realms_are_listed("team/a b")
+		_, err := os.Stat(filepath.Join(tc.dir, "team%2Fa%20b.db"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("does not create a database to delete a projection", func(t *testing.T) {
+		tc := newShardsTestContext(t, 4)
+
+		// When
+		err := tc.shards.ProjectionStore().Delete(context.Background(), "realm-1", "rune_list", "bf-1")
+
+		// Then
+		require.NoError(t, err)
+		tc.database_does_not_exist("realm-1")
+	})
+
+	t.Run("indexes databases opened after the fields were indexed", func(t *testing.T) {
+		tc := newShardsTestContext(t, 1)
+		store := tc.shards.ProjectionStore()
+
+		// Given
+		tc.event_is_appended("realm-1", "stream-1", 0)
+		require.NoError(t, store.IndexFields(context.Background(), "rune_list", []string{"status"}))
+		tc.event_is_appended("realm-2", "stream-1", 0)
+		require.NoError(t, store.Put(context.Background(), "realm-1", "rune_list", "bf-1", map[string]string{"status": "open"}))
+
+		// When
+		results, err := store.ListWhere(context.Background(), "realm-1", "rune_list", core.ProjectionFilter{"status": "open"})
+
+		// Then
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		tc.database_has_index("realm-1", "idx_projections_rune_list_status")
+		tc.database_has_index("realm-2", "idx_projections_rune_list_status")
+	})
+}
+
+// --- Test Context ---
+
+type shardsTestContext struct {
+	t      *testing.T
+	dir    string
+	shards *Shards
+}
+
+func newShardsTestContext(t *testing.T, maxOpen int) *shardsTestContext {
+	t.Helper()
+	dir := t.TempDir()
+	shards, err := NewShards(dir, maxOpen, Tuning{})
+	require.NoError(t, err)
+	t.Cleanup(func() { shards.Close() })
+	return &shardsTestContext{t: t, dir: dir, shards: shards}
+}
+
+// --- When ---
+
+func (tc *shardsTestContext) event_is_appended(realmID, streamID string, expectedVersion int) core.Event {
+	tc.t.Helper()
+	events, err := tc.shards.EventStore().Append(context.Background(), realmID, streamID, expectedVersion, []core.EventData{
+		{EventType: "Created", Data: map[string]string{}},
+	})
+	require.NoError(tc.t, err)
+	return events[0]
+}
+
+// --- Then ---
+
+func (tc *shardsTestContext) database_exists(realmID string) {
+	tc.t.Helper()
+	_, err := os.Stat(tc.shards.path(realmID))
+	assert.NoError(tc.t, err)
+}
+
+func (tc *shardsTestContext) database_does_not_exist(realmID string) {
+	tc.t.Helper()
+	_, err := os.Stat(tc.shards.path(realmID))
+	assert.ErrorIs(tc.t, err, os.ErrNotExist)
+}
+
+func (tc *shardsTestContext) realms_are_listed(expected ...string) {
+	tc.t.Helper()
+	realmIDs, err := tc.shards.EventStore().ListRealmIDs(context.Background())
+	require.NoError(tc.t, err)
+	assert.ElementsMatch(tc.t, expected, realmIDs)
+}
+
+func (tc *shardsTestContext) database_has_index(realmID, index string) {
+	tc.t.Helper()
+	err := tc.shards.with(realmID, false, func(sh *shard) error {
+		var name string
+		return sh.db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'index' AND name = ?`, index).Scan(&name)
+	})
+	assert.NoError(tc.t, err)
+}
+
+// open_realms_are checks the open databases, most recently used first.
+func (tc *shardsTestContext) open_realms_are(expected ...string) {
+	tc.t.Helper()
+	tc.shards.mu.Lock()
+	defer tc.shards.mu.Unlock()
+	var open []string
+	for e := tc.shards.lru.Front(); e != nil; e = e.Next() {
+		open = append(open, e.Value.(string))
+	}
+	assert.Equal(tc.t, expected, open)
+	assert.Len(tc.t, tc.shards.open, len(expected))
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// GetSnapshot returns the stream's latest snapshot. Returns
+// core.NotFoundError if the stream has none.
+func (s *EventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	snapshot := core.Snapshot{RealmID: realmID, StreamID: streamID}
+	var state []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, state FROM snapshots WHERE realm_id = ? AND stream_id = ?`,
+		realmID, streamID,
+	).Scan(&snapshot.Version, &state)
+	if err == sql.ErrNoRows {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	if err != nil {
+		return core.Snapshot{}, err
+	}
+	snapshot.State = state
+	return snapshot, nil
+}
+
+// SaveSnapshot replaces the stream's snapshot. It joins the transaction of
+// the unit of work the store is bound to, like Append.
+func (s *EventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO snapshots (realm_id, stream_id, version, state) VALUES (?, ?, ?, ?)`,
+		snapshot.RealmID, snapshot.StreamID, snapshot.Version, string(snapshot.State),
+	)
+	return err
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// subscriptionBatch is how many events a subscription reads at a time.
+const subscriptionBatch = 500
+
+// notifiers holds a notifier per database, so that every event store and
+// transactor appending to a database wakes the subscriptions reading it.
+var notifiers sync.Map
+
+// notifier wakes subscriptions when events are appended.
+type notifier struct {
+	mu   sync.Mutex
+	subs map[chan struct{}]struct{}
+}
+
+func notifierFor(db *sql.DB) *notifier {
+	n, _ := notifiers.LoadOrStore(db, &notifier{subs: make(map[chan struct{}]struct{})})
+	return n.(*notifier)
+}
+
+func (n *notifier) subscribe() chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	wake := make(chan struct{}, 1)
+	n.subs[wake] = struct{}{}
+	return wake
+}
+
+func (n *notifier) unsubscribe(wake chan struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.subs, wake)
+}
+
+// notify wakes every subscription without waiting for any. A subscription
+// that has not handled its last wake-up yet reads the new events with the
+// earlier ones.
+func (n *notifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for wake := range n.subs {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of the events after fromPosition across all
+// realms, in global position order: first those already stored, then each
+// one appended in this process through an event store or transactor on the
+// same database. Events appended by other processes arrive with the next
+// local append. The channel is closed when ctx ends or a read fails.
+func (s *EventStore) Subscribe(ctx context.Context, fromPosition int64) (<-chan core.Event, error) {
+	if s.db.tx != nil {
+		return nil, errors.New("cannot subscribe within a unit of work")
+	}
+	n := notifierFor(s.db.db)
+	wake := n.subscribe()
+	out := make(chan core.Event, subscriptionBatch)
+
+	go func() {
+		defer close(out)
+		defer n.unsubscribe(wake)
+		pos := fromPosition
+		for {
+			events, err := s.readAfter(ctx, pos, subscriptionBatch)
+			if err != nil {
+				return
+			}
+			for _, evt := range events {
+				select {
+				case out <- evt:
+					pos = evt.GlobalPosition
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(events) == subscriptionBatch {
+				continue
+			}
+			select {
+			case <-wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// readAfter returns up to limit events of any realm after the position.
+func (s *EventStore) readAfter(ctx context.Context, fromGlobalPosition int64, limit int) ([]core.Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT global_position, realm_id, stream_id, version, event_type, data, metadata, timestamp
+		 FROM events
+		 WHERE global_position > ?
+		 ORDER BY global_position ASC
+		 LIMIT ?`,
+		fromGlobalPosition, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// SyncReceiptStore is a SQLite-backed implementation of core.SyncReceiptStore.
+type SyncReceiptStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSyncReceiptStore creates a new SyncReceiptStore backed by the given database.
+func NewSyncReceiptStore(db *sql.DB) (*SyncReceiptStore, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &SyncReceiptStore{db: db, now: time.Now}, nil
+}
+
+func (s *SyncReceiptStore) SaveReceipt(ctx context.Context, receipt core.SyncReceipt) error {
+	var result sql.NullString
+	if len(receipt.Result) > 0 {
+		result = sql.NullString{String: string(receipt.Result), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO sync_receipts (realm_id, account_id, idempotency_key, command, status_code, result, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(realm_id, account_id, idempotency_key) DO NOTHING`,
+		receipt.RealmID, receipt.AccountID, receipt.IdempotencyKey, receipt.Command, receipt.StatusCode, result, s.now().UnixNano(),
+	)
+	return err
+}
+
+func (s *SyncReceiptStore) GetReceipt(ctx context.Context, realmID, accountID, idempotencyKey string) (core.SyncReceipt, error) {
+	receipt := core.SyncReceipt{RealmID: realmID, AccountID: accountID, IdempotencyKey: idempotencyKey}
+	var (
+		result    sql.NullString
+		createdAt int64
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT command, status_code, result, created_at FROM sync_receipts
+		WHERE realm_id = ? AND account_id = ? AND idempotency_key = ?`,
+		realmID, accountID, idempotencyKey,
+	).Scan(&receipt.Command, &receipt.StatusCode, &result, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.SyncReceipt{}, &core.NotFoundError{Entity: "sync receipt", ID: idempotencyKey}
+	}
+	if err != nil {
+		return core.SyncReceipt{}, err
+	}
+	if result.Valid {
+		receipt.Result = json.RawMessage(result.String)
+	}
+	receipt.CreatedAt = time.Unix(0, createdAt).UTC()
+	return receipt, nil
+}
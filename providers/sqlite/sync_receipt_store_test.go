@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.SyncReceiptStore = (*SyncReceiptStore)(nil)
+
+// --- Tests ---
+
+func TestSyncReceiptStore_SaveReceipt(t *testing.T) {
+	t.Run("keeps the first receipt saved under a key", func(t *testing.T) {
+		tc := newSyncReceiptStoreTestContext(t)
+
+		// Given
+		tc.receipt_is_saved("realm-1", "acct-1", "key-1", 201, `{"id":"bf-a1"}`)
+
+		// When
+		tc.receipt_is_saved("realm-1", "acct-1", "key-1", 201, `{"id":"bf-b2"}`)
+
+		// Then
+		tc.receipt_is_fetched("realm-1", "acct-1", "key-1")
+		assert.Equal(t, "create-rune", tc.fetched.Command)
+		assert.Equal(t, 201, tc.fetched.StatusCode)
+		assert.JSONEq(t, `{"id":"bf-a1"}`, string(tc.fetched.Result))
+		assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), tc.fetched.CreatedAt)
+	})
+
+	t.Run("stores receipts without a result", func(t *testing.T) {
+		tc := newSyncReceiptStoreTestContext(t)
+
+		// When
+		tc.receipt_is_saved("realm-1", "acct-1", "key-1", 204, "")
+
+		// Then
+		tc.receipt_is_fetched("realm-1", "acct-1", "key-1")
+		assert.Equal(t, 204, tc.fetched.StatusCode)
+		assert.Nil(t, tc.fetched.Result)
+	})
+}
+
+func TestSyncReceiptStore_GetReceipt(t *testing.T) {
+	t.Run("returns not found for another account's key", func(t *testing.T) {
+		tc := newSyncReceiptStoreTestContext(t)
+
+		// Given
+		tc.receipt_is_saved("realm-1", "acct-1", "key-1", 200, `{}`)
+
+		// When
+		_, err := tc.store.GetReceipt(context.Background(), "realm-1", "acct-2", "key-1")
+
+		// Then
+		var nfe *core.NotFoundError
+		assert.ErrorAs(t, err, &nfe)
+	})
+}
+
+// --- Test Context ---
+
+type syncReceiptStoreTestContext struct {
+	t     *testing.T
+	store *SyncReceiptStore
+	clock time.Time
+
+	fetched core.SyncReceipt
+}
+
+func newSyncReceiptStoreTestContext(t *testing.T) *syncReceiptStoreTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSyncReceiptStore(db)
+	require.NoError(t, err)
+	tc := &syncReceiptStoreTestContext{t: t, store: store, clock: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	store.now = func() time.Time { return tc.clock }
+	return tc
+}
+
+// --- Given ---
+
+func (tc *syncReceiptStoreTestContext) receipt_is_saved(realmID, accountID, key string, statusCode int, result string) {
+	tc.t.Helper()
+	receipt := core.SyncReceipt{
+		RealmID:        realmID,
+		AccountID:      accountID,
+		IdempotencyKey: key,
+		Command:        "create-rune",
+		StatusCode:     statusCode,
+	}
+	if result != "" {
+		receipt.Result = json.RawMessage(result)
+	}
+	require.NoError(tc.t, tc.store.SaveReceipt(context.Background(), receipt))
+	tc.clock = tc.clock.Add(time.Second)
+}
+
+// --- When ---
+
+func (tc *syncReceiptStoreTestContext) receipt_is_fetched(realmID, accountID, key string) {
+	tc.t.Helper()
+	var err error
+	tc.fetched, err = tc.store.GetReceipt(context.Background(), realmID, accountID, key)
+	require.NoError(tc.t, err)
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TruncatableStreams returns the IDs of the realm's streams that
+// TruncateStreams would truncate, whether archived or not.
+func (s *EventStore) TruncatableStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) ([]string, error) {
+	var streamIDs []string
+	err := s.db.inTx(ctx, func(tx *sql.Tx) error {
+		for _, table := range []string{"events", "archived_events"} {
+			streams, err := truncatableStreams(ctx, tx, table, realmID, lastEventType, before)
+			if err != nil {
+				return err
+			}
+			for _, st := range streams {
+				streamIDs = append(streamIDs, st.id)
+			}
+		}
+		return nil
+	})
+	return streamIDs, err
+}
+
+// TruncateStreams deletes all but the last event of each of the realm's
+// streams whose last event is of type lastEventType and was appended before
+// the given time, whether the stream is archived or not, and drops their
+// snapshots taken before that event. It returns how many streams were
+// truncated.
+func (s *EventStore) TruncateStreams(ctx context.Context, realmID string, lastEventType string, before time.Time) (int, error) {
+	truncated := 0
+	err := s.db.inTx(ctx, func(tx *sql.Tx) error {
+		for _, table := range []string{"events", "archived_events"} {
+			n, err := truncateStreams(ctx, tx, table, realmID, lastEventType, before)
+			if err != nil {
+				return err
+			}
+			truncated += n
+		}
+		return nil
+	})
+	return truncated, err
+}
+
+// truncatableStream is a stream to truncate and the version of the event
+// it keeps.
+type truncatableStream struct {
+	id      string
+	version int
+}
+
+func truncatableStreams(ctx context.Context, tx *sql.Tx, table, realmID, lastEventType string, before time.Time) ([]truncatableStream, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT stream_id, version FROM `+table+` e
+		 WHERE realm_id = ? AND event_type = ? AND timestamp < ?
+		   AND version = (SELECT MAX(version) FROM `+table+` WHERE realm_id = e.realm_id AND stream_id = e.stream_id)
+		   AND EXISTS (SELECT 1 FROM `+table+` WHERE realm_id = e.realm_id AND stream_id = e.stream_id AND version < e.version)`,
+		realmID, lastEventType, before.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var streams []truncatableStream
+	for rows.Next() {
+		var st truncatableStream
+		if err := rows.Scan(&st.id, &st.version); err != nil {
+			return nil, err
+		}
+		streams = append(streams, st)
+	}
+	return streams, rows.Err()
+}
+
+func truncateStreams(ctx context.Context, tx *sql.Tx, table, realmID, lastEventType string, before time.Time) (int, error) {
+	streams, err := truncatableStreams(ctx, tx, table, realmID, lastEventType, before)
+	if err != nil {
+		return 0, err
+	}
+	for _, st := range streams {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM `+table+` WHERE realm_id = ? AND stream_id = ? AND version < ?`,
+			realmID, st.id, st.version,
+		); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM snapshots WHERE realm_id = ? AND stream_id = ? AND version < ?`,
+			realmID, st.id, st.version,
+		); err != nil {
+			return 0, err
+		}
+	}
+	return len(streams), nil
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// conn runs a store's statements against the database, or against the
+// transaction of the unit of work the store is bound to.
+type conn struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if c.tx != nil {
+		return c.tx.ExecContext(ctx, query, args...)
+	}
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if c.tx != nil {
+		return c.tx.QueryContext(ctx, query, args...)
+	}
+	return c.db.QueryContext(ctx, query, args...)
+}
+
+func (c conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if c.tx != nil {
+		return c.tx.QueryRowContext(ctx, query, args...)
+	}
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+// inTx runs fn in a transaction of its own, or in the unit of work's
+// transaction, which is then left for the unit of work to commit.
+func (c conn) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if c.tx != nil {
+		return fn(c.tx)
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Transactor is a SQLite-backed implementation of core.Transactor.
+type Transactor struct {
+	db        *sql.DB
+	eventOpts []EventStoreOption
+}
+
+// NewTransactor creates a Transactor whose event stores are configured with
+// opts, like those made by NewEventStore.
+func NewTransactor(db *sql.DB, opts ...EventStoreOption) (*Transactor, error) {
+	if err := EnsureSchema(db); err != nil {
+		return nil, err
+	}
+	return &Transactor{db: db, eventOpts: opts}, nil
+}
+
+// Transact runs fn with event, projection and checkpoint stores sharing one
+// transaction, which is committed if fn succeeds and rolled back otherwise.
+func (t *Transactor) Transact(ctx context.Context, fn func(ctx context.Context, uow core.UnitOfWork) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	c := conn{db: t.db, tx: tx}
+	events := &EventStore{db: c}
+	for _, opt := range t.eventOpts {
+		opt(events)
+	}
+	uow := core.UnitOfWork{
+		EventStore:      events,
+		ProjectionStore: &ProjectionStore{db: c},
+		CheckpointStore: &CheckpointStore{db: c},
+	}
+	if err := fn(ctx, uow); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if events.appended {
+		notifierFor(t.db).notify()
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// Compile-time interface satisfaction check
+var _ core.Transactor = (*Transactor)(nil)
+
+// --- Tests ---
+
+func TestTransactor_Transact(t *testing.T) {
+	t.Run("commits every store together", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// When
+		tc.transact(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			require.NoError(t, uow.ProjectionStore.Put(ctx, "realm-1", "titles", "rune-1", "Bridge"))
+			return uow.CheckpointStore.SetCheckpoint(ctx, "realm-1", "titles", 1)
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.event_count_is(1)
+		tc.projection_is("titles", "rune-1", "Bridge")
+		tc.checkpoint_is("titles", 1)
+	})
+
+	t.Run("rolls every store back when fn fails", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// When
+		tc.transact(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			require.NoError(t, uow.ProjectionStore.Put(ctx, "realm-1", "titles", "rune-1", "Bridge"))
+			return errors.New("command rejected")
+		})
+
+		// Then
+		assert.EqualError(t, tc.err, "command rejected")
+		tc.event_count_is(0)
+		tc.projection_is_missing("titles", "rune-1")
+	})
+
+	t.Run("keeps concurrency errors from appends", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.transact(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return nil
+		})
+
+		// When
+		tc.transact(func(ctx context.Context, uow core.UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "rune-1", 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+			return err
+		})
+
+		// Then
+		var concErr *core.ConcurrencyError
+		assert.ErrorAs(t, tc.err, &concErr)
+		tc.event_count_is(1)
+	})
+}
+
+func TestProjectionEngine_ExecuteWithTransactor(t *testing.T) {
+	t.Run("projects the command's events before returning", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.an_engine_with_transactor()
+
+		// When
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return nil
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.projection_is("streams", "rune-1", "Created")
+		tc.checkpoint_is("streams", 1)
+	})
+
+	t.Run("leaves no events or projections when the command fails", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.an_engine_with_transactor()
+
+		// When
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return errors.New("command rejected")
+		})
+
+		// Then
+		assert.Error(t, tc.err)
+		tc.event_count_is(0)
+		tc.projection_is_missing("streams", "rune-1")
+		tc.side_effects_saw(0)
+	})
+
+	t.Run("runs side effects only after the commit", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.an_engine_with_transactor()
+
+		// When
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return nil
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.side_effects_saw(1)
+		assert.True(t, tc.sideEffects.committed, "side effect ran before the events were committed")
+	})
+
+	t.Run("evicts cached entries the transaction rewrote", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.an_engine_with_transactor()
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return nil
+		})
+		tc.projection_is("streams", "rune-1", "Created")
+
+		// When
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			_, err := uow.EventStore.Append(ctx, "realm-1", "rune-1", 1, []core.EventData{{EventType: "Updated", Data: map[string]string{}}})
+			return err
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.projection_is("streams", "rune-1", "Updated")
+	})
+
+	t.Run("leaves projectors that are not critical to catch-up", func(t *testing.T) {
+		tc := newUnitOfWorkTestContext(t)
+
+		// Given
+		tc.an_engine_with_transactor(core.WithCriticalProjectors("streams"))
+
+		// When
+		tc.execute(func(ctx context.Context, uow core.UnitOfWork) error {
+			tc.append_in(ctx, uow, "rune-1")
+			return nil
+		})
+
+		// Then
+		tc.no_error_occurred()
+		tc.projection_is("streams", "rune-1", "Created")
+		tc.projection_is_missing("stream_counts", "rune-1")
+		tc.side_effects_saw(0)
+
+		// When
+		tc.catch_up_runs()
+
+		// Then
+		tc.projection_is("stream_counts", "rune-1", "1")
+		tc.side_effects_saw(1)
+	})
+}
+
+// --- Test Context ---
+
+type unitOfWorkTestContext struct {
+	t  *testing.T
+	db *sql.DB
+
+	transactor  *Transactor
+	projections core.ProjectionStore
+	engine      interface {
+		Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow core.UnitOfWork) error) error
+		RunCatchUpOnce(ctx context.Context)
+	}
+	sideEffects *sideEffectProjector
+
+	err error
+}
+
+func newUnitOfWorkTestContext(t *testing.T) *unitOfWorkTestContext {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	transactor, err := NewTransactor(db)
+	require.NoError(t, err)
+	projections, err := NewProjectionStore(db)
+	require.NoError(t, err)
+	return &unitOfWorkTestContext{t: t, db: db, transactor: transactor, projections: projections}
+}
+
+// --- Given ---
+
+func (tc *unitOfWorkTestContext) an_engine_with_transactor(opts ...core.EngineOption) {
+	tc.t.Helper()
+	events, err := NewEventStore(tc.db)
+	require.NoError(tc.t, err)
+	checkpoints, err := NewCheckpointStore(tc.db)
+	require.NoError(tc.t, err)
+	tc.projections = core.NewCachedProjectionStore(tc.projections, 100)
+	opts = append([]core.EngineOption{core.WithTransactor(tc.transactor)}, opts...)
+	engine := core.NewProjectionEngine(events, tc.projections, checkpoints, opts...)
+	engine.Register(streamProjector{})
+	engine.Register(streamCountProjector{})
+	tc.sideEffects = &sideEffectProjector{db: tc.db}
+	engine.Register(tc.sideEffects)
+	tc.engine = engine
+}
+
+// --- When ---
+
+func (tc *unitOfWorkTestContext) transact(fn func(ctx context.Context, uow core.UnitOfWork) error) {
+	tc.t.Helper()
+	tc.err = tc.transactor.Transact(context.Background(), fn)
+}
+
+func (tc *unitOfWorkTestContext) execute(command func(ctx context.Context, uow core.UnitOfWork) error) {
+	tc.t.Helper()
+	tc.err = tc.engine.Execute(context.Background(), "realm-1", command)
+}
+
+func (tc *unitOfWorkTestContext) catch_up_runs() {
+	tc.t.Helper()
+	tc.engine.RunCatchUpOnce(context.Background())
+}
+
+func (tc *unitOfWorkTestContext) append_in(ctx context.Context, uow core.UnitOfWork, streamID string) {
+	tc.t.Helper()
+	_, err := uow.EventStore.Append(ctx, "realm-1", streamID, 0, []core.EventData{{EventType: "Created", Data: map[string]string{}}})
+	require.NoError(tc.t, err)
+}
+
+// --- Then ---
+
+func (tc *unitOfWorkTestContext) no_error_occurred() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *unitOfWorkTestContext) event_count_is(expected int) {
+	tc.t.Helper()
+	var n int
+	require.NoError(tc.t, tc.db.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n))
+	assert.Equal(tc.t, expected, n)
+}
+
+func (tc *unitOfWorkTestContext) projection_is(projectionName, key, expected string) {
+	tc.t.Helper()
+	var value string
+	require.NoError(tc.t, tc.projections.Get(context.Background(), "realm-1", projectionName, key, &value))
+	assert.Equal(tc.t, expected, value)
+}
+
+func (tc *unitOfWorkTestContext) projection_is_missing(projectionName, key string) {
+	tc.t.Helper()
+	var value string
+	err := tc.projections.Get(context.Background(), "realm-1", projectionName, key, &value)
+	var nfe *core.NotFoundError
+	assert.ErrorAs(tc.t, err, &nfe)
+}
+
+func (tc *unitOfWorkTestContext) checkpoint_is(projectorName string, expected int64) {
+	tc.t.Helper()
+	var pos int64
+	require.NoError(tc.t, tc.db.QueryRow(
+		`SELECT last_global_position FROM checkpoints WHERE realm_id = ? AND projector_name = ?`,
+		"realm-1", projectorName,
+	).Scan(&pos))
+	assert.Equal(tc.t, expected, pos)
+}
+
+func (tc *unitOfWorkTestContext) side_effects_saw(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.sideEffects.handled)
+}
+
+// --- Test Projectors ---
+
+// streamProjector stores the type of each stream's latest event.
+type streamProjector struct{}
+
+func (streamProjector) Name() string { return "streams" }
+
+func (streamProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	return store.Put(ctx, event.RealmID, "streams", event.StreamID, event.EventType)
+}
+
+// streamCountProjector stores the number of events in each stream.
+type streamCountProjector struct{}
+
+func (streamCountProjector) Name() string { return "stream_counts" }
+
+func (streamCountProjector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	return store.Put(ctx, event.RealmID, "stream_counts", event.StreamID, strconv.Itoa(event.Version))
+}
+
+// sideEffectProjector counts events and checks they were committed when it
+// saw them.
+type sideEffectProjector struct {
+	db        *sql.DB
+	handled   int
+	committed bool
+}
+
+func (p *sideEffectProjector) Name() string { return "side_effects" }
+
+func (p *sideEffectProjector) HasSideEffects() bool { return true }
+
+func (p *sideEffectProjector) Handle(ctx context.Context, event core.Event, _ core.ProjectionStore) error {
+	p.handled++
+	var n int
+	err := p.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM events WHERE global_position = ?`, event.GlobalPosition).Scan(&n)
+	p.committed = err == nil && n == 1
+	return nil
+}
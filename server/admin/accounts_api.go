@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/devzeebo/bifrost/core"
 	"github.com/devzeebo/bifrost/domain"
 	"github.com/devzeebo/bifrost/domain/projectors"
 )
@@ -50,6 +51,11 @@ type SuspendAccountRequest struct {
 	Suspend bool   `json:"suspend"`
 }
 
+// PurgeAccountRequest is the request body for POST /purge-account.
+type PurgeAccountRequest struct {
+	ID string `json:"id"`
+}
+
 // GrantRealmRequest is the request body for POST /grant-realm.
 type GrantRealmRequest struct {
 	AccountID string `json:"account_id"`
@@ -90,7 +96,6 @@ type PatEntry struct {
 	LastUsed     string `json:"last_used,omitempty"`
 }
 
-
 // RegisterAccountsAPIRoutes registers the accounts JSON API routes for the Vike/React UI.
 func RegisterAccountsAPIRoutes(mux *http.ServeMux, cfg *RouteConfig) {
 	authMiddleware := AuthMiddleware(cfg.AuthConfig, cfg.ProjectionStore)
@@ -103,6 +108,7 @@ func RegisterAccountsAPIRoutes(mux *http.ServeMux, cfg *RouteConfig) {
 	// Account management
 	mux.Handle("POST /api/create-account", authMiddleware(requireAdmin(http.HandlerFunc(handleCreateAccount(cfg)))))
 	mux.Handle("POST /api/suspend-account", authMiddleware(requireAdmin(http.HandlerFunc(handleSuspendAccount(cfg)))))
+	mux.Handle("POST /api/purge-account", authMiddleware(requireAdmin(http.HandlerFunc(handlePurgeAccount(cfg)))))
 
 	// Realm access management
 	mux.Handle("POST /api/grant-realm", authMiddleware(requireAdmin(http.HandlerFunc(handleGrantRealm(cfg)))))
@@ -176,30 +182,34 @@ func handleGetAccount(cfg *RouteConfig) http.HandlerFunc {
 			return
 		}
 
-		// Get account from projection
-		var account projectors.AccountListEntry
-		if cfg.ProjectionStore != nil {
-			err := cfg.ProjectionStore.Get(r.Context(), domain.AdminRealmID, "account_list", accountID, &account)
-			if err != nil {
-				http.Error(w, "account not found", http.StatusNotFound)
-				return
-			}
-		}
+		writeAccountDetail(w, r, cfg, accountID)
+	}
+}
 
-		detail := AccountDetail{
-			AccountID: account.AccountID,
-			Username:  account.Username,
-			Status:    account.Status,
-			Realms:    account.Realms,
-			Roles:     account.Roles,
-			PATCount:  account.PATCount,
-			CreatedAt: account.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+// writeAccountDetail responds with the account's current read model.
+func writeAccountDetail(w http.ResponseWriter, r *http.Request, cfg *RouteConfig, accountID string) {
+	var account projectors.AccountListEntry
+	if cfg.ProjectionStore != nil {
+		err := cfg.ProjectionStore.Get(r.Context(), domain.AdminRealmID, "account_list", accountID, &account)
+		if err != nil {
+			http.Error(w, "account not found", http.StatusNotFound)
+			return
 		}
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(detail); err != nil {
-			log.Printf("handleGetAccount: failed to encode response: %v", err)
-		}
+	detail := AccountDetail{
+		AccountID: account.AccountID,
+		Username:  account.Username,
+		Status:    account.Status,
+		Realms:    account.Realms,
+		Roles:     account.Roles,
+		PATCount:  account.PATCount,
+		CreatedAt: account.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(detail); err != nil {
+		log.Printf("writeAccountDetail: failed to encode response: %v", err)
 	}
 }
 
@@ -218,16 +228,15 @@ func handleCreateAccount(cfg *RouteConfig) http.HandlerFunc {
 		}
 
 		// Create account via domain command
-		result, err := domain.HandleCreateAccount(r.Context(), domain.CreateAccount{
-			Username: username,
-		}, cfg.EventStore, cfg.ProjectionStore)
+		var result domain.CreateAccountResult
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) (err error) {
+			result, err = domain.HandleCreateAccount(ctx, domain.CreateAccount{
+				Username: username,
+			}, events, projections)
+			return err
+		})
 		if err != nil {
-			if strings.Contains(err.Error(), "already exists") {
-				http.Error(w, "username already exists", http.StatusConflict)
-				return
-			}
-			log.Printf("handleCreateAccount: failed to create account: %v", err)
-			http.Error(w, "failed to create account", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleCreateAccount: failed to create account", "failed to create account")
 			return
 		}
 
@@ -236,7 +245,6 @@ func handleCreateAccount(cfg *RouteConfig) http.HandlerFunc {
 			PAT:       result.RawToken,
 		}
 
-
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -271,13 +279,41 @@ func handleSuspendAccount(cfg *RouteConfig) http.HandlerFunc {
 			reason = "unsuspended via admin UI"
 		}
 
-		err := domain.HandleSuspendAccount(r.Context(), domain.SuspendAccount{
-			AccountID: req.ID,
-			Reason:    reason,
-		}, cfg.EventStore)
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleSuspendAccount(ctx, domain.SuspendAccount{
+				AccountID: req.ID,
+				Reason:    reason,
+			}, events)
+		})
 		if err != nil {
-			log.Printf("handleSuspendAccount: failed: %v", err)
-			http.Error(w, "failed to suspend account", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleSuspendAccount: failed", "failed to suspend account")
+			return
+		}
+
+		writeAccountDetail(w, r, cfg, req.ID)
+	}
+}
+
+// handlePurgeAccount redacts a suspended account's events. The account
+// leaves the account list, so there is no detail to answer with.
+func handlePurgeAccount(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req PurgeAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if req.ID == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandlePurgeAccount(ctx, domain.PurgeAccount{AccountID: req.ID}, events)
+		})
+		if err != nil {
+			writeDomainError(w, err, "handlePurgeAccount: failed", "failed to purge account")
 			return
 		}
 
@@ -299,18 +335,19 @@ func handleGrantRealm(cfg *RouteConfig) http.HandlerFunc {
 		}
 
 		// Grant role via domain command
-		err := domain.HandleAssignRole(r.Context(), domain.AssignRole{
-			AccountID: req.AccountID,
-			RealmID:   req.RealmID,
-			Role:      req.Role,
-		}, cfg.EventStore)
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleAssignRole(ctx, domain.AssignRole{
+				AccountID: req.AccountID,
+				RealmID:   req.RealmID,
+				Role:      req.Role,
+			}, events)
+		})
 		if err != nil {
-			log.Printf("handleGrantRealm: failed: %v", err)
-			http.Error(w, "failed to grant realm access", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleGrantRealm: failed", "failed to grant realm access")
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		writeAccountDetail(w, r, cfg, req.AccountID)
 	}
 }
 
@@ -328,17 +365,18 @@ func handleRevokeRealm(cfg *RouteConfig) http.HandlerFunc {
 		}
 
 		// Revoke role via domain command
-		err := domain.HandleRevokeRole(r.Context(), domain.RevokeRole{
-			AccountID: req.AccountID,
-			RealmID:   req.RealmID,
-		}, cfg.EventStore)
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleRevokeRole(ctx, domain.RevokeRole{
+				AccountID: req.AccountID,
+				RealmID:   req.RealmID,
+			}, events)
+		})
 		if err != nil {
-			log.Printf("handleRevokeRealm: failed: %v", err)
-			http.Error(w, "failed to revoke realm access", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleRevokeRealm: failed", "failed to revoke realm access")
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		writeAccountDetail(w, r, cfg, req.AccountID)
 	}
 }
 
@@ -366,13 +404,16 @@ func handleCreatePat(cfg *RouteConfig) http.HandlerFunc {
 		}
 
 		// Create PAT via domain command
-		result, err := domain.HandleCreatePAT(r.Context(), domain.CreatePAT{
-			AccountID: req.AccountID,
-			Label:     label,
-		}, cfg.EventStore)
+		var result domain.CreatePATResult
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) (err error) {
+			result, err = domain.HandleCreatePAT(ctx, domain.CreatePAT{
+				AccountID: req.AccountID,
+				Label:     label,
+			}, events)
+			return err
+		})
 		if err != nil {
-			log.Printf("handleCreatePat: failed: %v", err)
-			http.Error(w, "failed to create PAT", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleCreatePat: failed", "failed to create PAT")
 			return
 		}
 
@@ -381,7 +422,6 @@ func handleCreatePat(cfg *RouteConfig) http.HandlerFunc {
 			PATID: result.PATID,
 		}
 
-
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -422,17 +462,17 @@ func handleRevokePat(cfg *RouteConfig) http.HandlerFunc {
 		}
 
 		// Revoke PAT via domain command
-		err := domain.HandleRevokePAT(r.Context(), domain.RevokePAT{
-			AccountID: req.AccountID,
-			PATID:     req.PatID,
-		}, cfg.EventStore)
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleRevokePAT(ctx, domain.RevokePAT{
+				AccountID: req.AccountID,
+				PATID:     req.PatID,
+			}, events)
+		})
 		if err != nil {
-			log.Printf("handleRevokePat: failed: %v", err)
-			http.Error(w, "failed to revoke PAT", http.StatusInternalServerError)
+			writeDomainError(w, err, "handleRevokePat: failed", "failed to revoke PAT")
 			return
 		}
 
-
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
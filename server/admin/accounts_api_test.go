@@ -0,0 +1,148 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountsAPI_GrantRealm tests that POST /api/grant-realm applies the
+// appended events and answers with the updated account.
+func TestAccountsAPI_GrantRealm(t *testing.T) {
+	setup := func(t *testing.T) (*http.ServeMux, *RouteConfig, *recordingEngine, string) {
+		store := newMockProjectionStoreWithAccount()
+		events := newMockEventStore()
+		_, err := events.Append(context.Background(), domain.AdminRealmID, "account-acct-2", 0, []core.EventData{
+			{EventType: domain.EventAccountCreated, Data: domain.AccountCreated{AccountID: "acct-2", Username: "bob"}},
+		})
+		require.NoError(t, err)
+		store.data[compositeKey(domain.AdminRealmID, "account_list", "acct-2")] = projectors.AccountListEntry{
+			AccountID: "acct-2",
+			Username:  "bob",
+			Status:    "active",
+		}
+
+		engine := &recordingEngine{apply: func() {
+			store.data[compositeKey(domain.AdminRealmID, "account_list", "acct-2")] = projectors.AccountListEntry{
+				AccountID: "acct-2",
+				Username:  "bob",
+				Status:    "active",
+				Realms:    []string{"realm-1"},
+				Roles:     map[string]string{"realm-1": "member"},
+			}
+		}}
+		cfg := &RouteConfig{
+			AuthConfig:      DefaultAuthConfig(),
+			ProjectionStore: store,
+			EventStore:      events,
+			Engine:          engine,
+		}
+		cfg.AuthConfig.SigningKey = make([]byte, 32)
+		_, err = rand.Read(cfg.AuthConfig.SigningKey)
+		require.NoError(t, err)
+
+		token, err := GenerateJWT(cfg.AuthConfig, "account-test-123", "pat-test-123")
+		require.NoError(t, err)
+
+		mux := http.NewServeMux()
+		RegisterAccountsAPIRoutes(mux, cfg)
+		return mux, cfg, engine, token
+	}
+
+	t.Run("returns the account after the role is applied", func(t *testing.T) {
+		mux, cfg, engine, token := setup(t)
+
+		body, err := json.Marshal(GrantRealmRequest{AccountID: "acct-2", RealmID: "realm-1", Role: "member"})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/grant-realm", bytes.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, engine.runs)
+		var detail AccountDetail
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+		assert.Equal(t, "acct-2", detail.AccountID)
+		assert.Equal(t, map[string]string{"realm-1": "member"}, detail.Roles)
+	})
+
+	t.Run("does not apply events when the command fails", func(t *testing.T) {
+		mux, cfg, engine, token := setup(t)
+
+		body, err := json.Marshal(GrantRealmRequest{AccountID: "acct-2", RealmID: "realm-1", Role: "emperor"})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/grant-realm", bytes.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid role")
+		assert.Equal(t, 0, engine.runs)
+	})
+
+	t.Run("projects the command in its transaction when the engine executes commands", func(t *testing.T) {
+		mux, cfg, engine, token := setup(t)
+		executor := &executingEngine{recordingEngine: engine, events: cfg.EventStore, projections: cfg.ProjectionStore}
+		cfg.Engine = executor
+
+		body, err := json.Marshal(GrantRealmRequest{AccountID: "acct-2", RealmID: "realm-1", Role: "member"})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/grant-realm", bytes.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{domain.AdminRealmID}, executor.executed)
+		assert.Equal(t, 0, engine.runs, "no catch-up after the command")
+		var detail AccountDetail
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+		assert.Equal(t, map[string]string{"realm-1": "member"}, detail.Roles)
+	})
+}
+
+// executingEngine runs commands against the given stores and simulates the
+// projectors as if they ran in the command's transaction.
+type executingEngine struct {
+	*recordingEngine
+	events      core.EventStore
+	projections core.ProjectionStore
+	executed    []string
+}
+
+func (e *executingEngine) Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow core.UnitOfWork) error) error {
+	e.executed = append(e.executed, realmID)
+	if err := command(ctx, core.UnitOfWork{EventStore: e.events, ProjectionStore: e.projections}); err != nil {
+		return err
+	}
+	e.apply()
+	return nil
+}
+
+// recordingEngine counts catch-up runs and simulates the projectors.
+type recordingEngine struct {
+	runs  int
+	apply func()
+}
+
+func (e *recordingEngine) RunCatchUpOnce(_ context.Context) {
+	e.runs++
+	if e.apply != nil {
+		e.apply()
+	}
+}
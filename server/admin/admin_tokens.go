@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// IsAdminToken reports whether token has the form of an admin token rather
+// than a PAT.
+func IsAdminToken(token string) bool {
+	return strings.HasPrefix(token, domain.AdminTokenPrefix)
+}
+
+// AuthenticateAdminToken returns the live admin token matching token, or
+// ErrInvalidToken.
+func AuthenticateAdminToken(ctx context.Context, projectionStore core.ProjectionStore, token string) (*projectors.AdminTokenEntry, error) {
+	rawBytes, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, domain.AdminTokenPrefix))
+	if err != nil || !IsAdminToken(token) {
+		return nil, ErrInvalidToken
+	}
+	h := sha256.Sum256(rawBytes)
+	keyHash := base64.RawURLEncoding.EncodeToString(h[:])
+
+	entry, err := projectors.GetAdminTokenByKeyHash(ctx, projectionStore, keyHash)
+	if err != nil {
+		var nfe *core.NotFoundError
+		if errors.As(err, &nfe) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("authenticate admin token: %w", err)
+	}
+	return &entry, nil
+}
+
+// adminTokenFromRequest returns the admin token in the request's bearer
+// authorization, if it carries one.
+func adminTokenFromRequest(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token, ok && IsAdminToken(token)
+}
+
+// withAdminToken puts an admin token in the request context as an account
+// with the admin role in the _admin realm only.
+func withAdminToken(ctx context.Context, entry *projectors.AdminTokenEntry) context.Context {
+	ctx = context.WithValue(ctx, accountIDKey, entry.ID)
+	ctx = context.WithValue(ctx, usernameKey, entry.Name)
+	return context.WithValue(ctx, rolesKey, map[string]string{domain.AdminRealmID: domain.RoleAdmin})
+}
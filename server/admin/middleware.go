@@ -173,10 +173,22 @@ var (
 	ErrAccountSuspended = errors.New("account is suspended")
 )
 
-// AuthMiddleware returns HTTP middleware that authenticates admin UI requests via JWT cookie.
+// AuthMiddleware returns HTTP middleware that authenticates admin UI requests via JWT cookie,
+// or API requests via an admin token.
 func AuthMiddleware(cfg *AuthConfig, projectionStore core.ProjectionStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Automation authenticates with an admin token instead of a session
+			if token, ok := adminTokenFromRequest(r); ok {
+				entry, err := AuthenticateAdminToken(r.Context(), projectionStore, token)
+				if err != nil {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(withAdminToken(r.Context(), entry)))
+				return
+			}
+
 			cookie, err := r.Cookie(cfg.CookieName)
 			if err != nil {
 				redirectToLogin(w, r, cfg)
@@ -434,6 +434,40 @@ func TestAuthMiddleware(t *testing.T) {
 		assert.Equal(t, "", adminCookie.Value)
 		assert.Equal(t, -1, adminCookie.MaxAge)
 	})
+
+	t.Run("bearer admin token authenticates without cookie", func(t *testing.T) {
+		store := newMockProjectionStore()
+		rawBytes := []byte("admin-token-bytes-32-bytes-long!")
+		h := sha256.Sum256(rawBytes)
+		keyHash := base64.RawURLEncoding.EncodeToString(h[:])
+		store.data[compositeKey("_admin", "admin_token_hashes", keyHash)] = "adm-a1b2c3d4"
+		store.data[compositeKey("_admin", "admin_tokens", "adm-a1b2c3d4")] = projectors.AdminTokenEntry{
+			ID: "adm-a1b2c3d4", Name: "provisioner",
+		}
+
+		req := httptest.NewRequest("GET", "/admin/", nil)
+		req.Header.Set("Authorization", "Bearer bfa_"+base64.RawURLEncoding.EncodeToString(rawBytes))
+		rec := httptest.NewRecorder()
+
+		middleware := AuthMiddleware(cfg, store)
+		middleware(protectedHandler).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "adm-a1b2c3d4")
+	})
+
+	t.Run("unknown bearer admin token is unauthorized", func(t *testing.T) {
+		store := newMockProjectionStore()
+
+		req := httptest.NewRequest("GET", "/admin/", nil)
+		req.Header.Set("Authorization", "Bearer bfa_"+base64.RawURLEncoding.EncodeToString([]byte("unknown")))
+		rec := httptest.NewRecorder()
+
+		middleware := AuthMiddleware(cfg, store)
+		middleware(protectedHandler).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
 }
 
 func TestContextHelpers(t *testing.T) {
@@ -682,6 +716,18 @@ func (m *mockProjectionStore) Get(ctx context.Context, realm, projection, key st
 			return fmt.Errorf("mockProjectionStore.Get: type assertion failed for key %s: expected AccountListEntry, got %T", ckey, val)
 		}
 		*d = e
+	case *projectors.AdminTokenEntry:
+		e, ok := val.(projectors.AdminTokenEntry)
+		if !ok {
+			return fmt.Errorf("mockProjectionStore.Get: type assertion failed for key %s: expected AdminTokenEntry, got %T", ckey, val)
+		}
+		*d = e
+	case *projectors.NotificationPreferencesEntry:
+		e, ok := val.(projectors.NotificationPreferencesEntry)
+		if !ok {
+			return fmt.Errorf("mockProjectionStore.Get: type assertion failed for key %s: expected NotificationPreferencesEntry, got %T", ckey, val)
+		}
+		*d = e
 	default:
 		return fmt.Errorf("mockProjectionStore.Get: unhandled dest type %T", dest)
 	}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// NotificationPreferences is the JSON body of the notification preference
+// endpoints. Muted maps a channel to the kinds the account turned off there.
+type NotificationPreferences struct {
+	AccountID string              `json:"account_id"`
+	Muted     map[string][]string `json:"muted"`
+	Digest    string              `json:"digest"`
+	Channels  []string            `json:"channels,omitempty"`
+	Kinds     []string            `json:"kinds,omitempty"`
+}
+
+// RegisterNotificationPreferencesRoutes registers the endpoints accounts use
+// to read and change their notification preferences. Admins may manage any
+// account's preferences by passing its account_id.
+func RegisterNotificationPreferencesRoutes(mux *http.ServeMux, cfg *RouteConfig) {
+	authMiddleware := AuthMiddleware(cfg.AuthConfig, cfg.ProjectionStore)
+
+	mux.Handle("GET /api/notification-preferences", authMiddleware(http.HandlerFunc(handleGetNotificationPreferences(cfg))))
+	mux.Handle("POST /api/notification-preferences", authMiddleware(http.HandlerFunc(handleSetNotificationPreferences(cfg))))
+}
+
+func handleGetNotificationPreferences(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := r.URL.Query().Get("account_id")
+		if accountID == "" {
+			accountID, _ = AccountIDFromContext(r.Context())
+		}
+		if !canManageAccount(r.Context(), accountID) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		writeNotificationPreferences(w, r, cfg, accountID)
+	}
+}
+
+func handleSetNotificationPreferences(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req NotificationPreferences
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if req.AccountID == "" {
+			req.AccountID, _ = AccountIDFromContext(r.Context())
+		}
+		if !canManageAccount(r.Context(), req.AccountID) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleSetNotificationPreferences(ctx, domain.SetNotificationPreferences{
+				AccountID: req.AccountID,
+				Muted:     req.Muted,
+				Digest:    req.Digest,
+			}, events)
+		})
+		if err != nil {
+			writeDomainError(w, err, "handleSetNotificationPreferences: failed", "failed to set notification preferences")
+			return
+		}
+
+		writeNotificationPreferences(w, r, cfg, req.AccountID)
+	}
+}
+
+// writeNotificationPreferences answers with the account's preferences along
+// with the channels and kinds it can choose from, so the settings page need
+// not hard-code them.
+func writeNotificationPreferences(w http.ResponseWriter, r *http.Request, cfg *RouteConfig, accountID string) {
+	entry, err := projectors.GetNotificationPreferences(r.Context(), cfg.ProjectionStore, accountID)
+	if err != nil {
+		log.Printf("writeNotificationPreferences: failed to load preferences: %v", err)
+		http.Error(w, "failed to load notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(NotificationPreferences{
+		AccountID: entry.AccountID,
+		Muted:     entry.Muted,
+		Digest:    entry.Digest,
+		Channels:  domain.NotificationChannels,
+		Kinds:     domain.NotificationKinds,
+	}); err != nil {
+		log.Printf("writeNotificationPreferences: failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotificationPreferencesAPI tests that accounts can read and replace
+// their own notification preferences.
+func TestNotificationPreferencesAPI(t *testing.T) {
+	setup := func(t *testing.T) (*http.ServeMux, *RouteConfig, *recordingEngine, string) {
+		store := newMockProjectionStoreWithAccount()
+		events := newMockEventStore()
+		_, err := events.Append(context.Background(), domain.AdminRealmID, "account-account-test-123", 0, []core.EventData{
+			{EventType: domain.EventAccountCreated, Data: domain.AccountCreated{AccountID: "account-test-123", Username: "testuser"}},
+		})
+		require.NoError(t, err)
+
+		engine := &recordingEngine{apply: func() {
+			store.data[compositeKey(domain.AdminRealmID, "notification_preferences", "account-test-123")] = projectors.NotificationPreferencesEntry{
+				AccountID: "account-test-123",
+				Muted:     map[string][]string{"slack": {"created"}},
+				Digest:    domain.DigestDaily,
+			}
+		}}
+		cfg := &RouteConfig{
+			AuthConfig:      DefaultAuthConfig(),
+			ProjectionStore: store,
+			EventStore:      events,
+			Engine:          engine,
+		}
+		cfg.AuthConfig.SigningKey = make([]byte, 32)
+		_, err = rand.Read(cfg.AuthConfig.SigningKey)
+		require.NoError(t, err)
+
+		token, err := GenerateJWT(cfg.AuthConfig, "account-test-123", "pat-test-123")
+		require.NoError(t, err)
+
+		mux := http.NewServeMux()
+		RegisterNotificationPreferencesRoutes(mux, cfg)
+		return mux, cfg, engine, token
+	}
+
+	t.Run("returns defaults and the available choices", func(t *testing.T) {
+		mux, cfg, _, token := setup(t)
+
+		req := httptest.NewRequest("GET", "/api/notification-preferences", nil)
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var prefs NotificationPreferences
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &prefs))
+		assert.Equal(t, "account-test-123", prefs.AccountID)
+		assert.Empty(t, prefs.Muted)
+		assert.Equal(t, domain.DigestOff, prefs.Digest)
+		assert.Equal(t, domain.NotificationChannels, prefs.Channels)
+		assert.Equal(t, domain.NotificationKinds, prefs.Kinds)
+	})
+
+	t.Run("returns the preferences after they are applied", func(t *testing.T) {
+		mux, cfg, engine, token := setup(t)
+
+		body, err := json.Marshal(NotificationPreferences{Muted: map[string][]string{"slack": {"created"}}, Digest: domain.DigestDaily})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/notification-preferences", bytes.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, engine.runs)
+		var prefs NotificationPreferences
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &prefs))
+		assert.Equal(t, map[string][]string{"slack": {"created"}}, prefs.Muted)
+		assert.Equal(t, domain.DigestDaily, prefs.Digest)
+	})
+
+	t.Run("rejects an unknown digest frequency", func(t *testing.T) {
+		mux, cfg, engine, token := setup(t)
+
+		body, err := json.Marshal(NotificationPreferences{Digest: "hourly"})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/notification-preferences", bytes.NewReader(body))
+		req.AddCookie(&http.Cookie{Name: cfg.AuthConfig.CookieName, Value: token})
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unknown digest frequency")
+		assert.Equal(t, 0, engine.runs)
+	})
+}
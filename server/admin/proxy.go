@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -51,9 +52,24 @@ func NewVikeStaticHandler(staticPath, prefix string) (http.Handler, error) {
 		fullPath := filepath.Join(absPath, relPath)
 
 		// Check if file exists
-		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		info, err := os.Stat(fullPath)
+		if os.IsNotExist(err) {
 			// File not found - serve index.html for SPA routing
 			fullPath = filepath.Join(absPath, "index.html")
+			relPath = "index.html"
+			info, err = os.Stat(fullPath)
+		}
+
+		// Built assets have content-hashed names and never change; pages
+		// are revalidated so a deploy shows up immediately. ServeFile
+		// answers If-None-Match against the ETag.
+		if strings.HasPrefix(relPath, "assets/") {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		if err == nil {
+			w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
 		}
 
 		// Serve the file directly
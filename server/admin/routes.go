@@ -1,21 +1,34 @@
 package admin
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
 )
 
+// ProjectionEngine is the subset of the projection engine used to apply a
+// command's events before its handler reads them back.
+type ProjectionEngine interface {
+	RunCatchUpOnce(ctx context.Context)
+}
+
 // RouteConfig holds the configuration for registering admin routes.
 type RouteConfig struct {
 	AuthConfig      *AuthConfig
 	ProjectionStore core.ProjectionStore
 	EventStore      core.EventStore
+	Engine          ProjectionEngine
 	// Vike UI configuration (production only)
 	StaticPath string // Path to built Vike assets (production mode)
 	// Vike UI configuration (development only)
 	ViteDevServerURL string // URL of Vite dev server (development mode)
+	// HostRealm reports the realm the request's host is scoped to, if any
+	HostRealm func(ctx context.Context) (string, bool)
 }
 
 // RegisterRoutesResult contains the result of registering admin routes.
@@ -32,6 +45,9 @@ func RegisterRoutes(mux *http.ServeMux, cfg *RouteConfig) (*RegisterRoutesResult
 	// Register accounts JSON API routes for Vike/React UI
 	RegisterAccountsAPIRoutes(mux, cfg)
 
+	// Register notification preference routes for the account settings page
+	RegisterNotificationPreferencesRoutes(mux, cfg)
+
 	// Register new /ui/ routes (development or production)
 	if err := registerUIRoutes(mux, cfg); err != nil {
 		return nil, err
@@ -71,3 +87,47 @@ func registerUIRoutes(mux *http.ServeMux, cfg *RouteConfig) error {
 
 	return nil
 }
+
+// commandExecutor is implemented by engines that project a command's events
+// in the same transaction that appends them.
+type commandExecutor interface {
+	Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow core.UnitOfWork) error) error
+}
+
+// runCommand runs an admin command, whose events all belong to the admin
+// realm, and brings the projections up to date with them so the handler can
+// answer from the read model. When the engine is a commandExecutor the
+// events are projected in the transaction that appends them; otherwise a
+// catch-up follows the command.
+func runCommand(r *http.Request, cfg *RouteConfig, command func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error) error {
+	if executor, ok := cfg.Engine.(commandExecutor); ok {
+		return executor.Execute(r.Context(), domain.AdminRealmID, func(ctx context.Context, uow core.UnitOfWork) error {
+			return command(ctx, uow.EventStore, uow.ProjectionStore)
+		})
+	}
+	if err := command(r.Context(), cfg.EventStore, cfg.ProjectionStore); err != nil {
+		return err
+	}
+	if cfg.Engine != nil {
+		cfg.Engine.RunCatchUpOnce(r.Context())
+	}
+	return nil
+}
+
+// writeDomainError reports a failed domain command. Broken rules and missing
+// entities are explained to the user; anything else is logged by op and
+// answered with failure.
+func writeDomainError(w http.ResponseWriter, err error, op string, failure string) {
+	var nfErr *core.NotFoundError
+	switch {
+	case errors.Is(err, domain.ErrAlreadyExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &nfErr):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.IsRejection(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", op, err)
+		http.Error(w, failure, http.StatusInternalServerError)
+	}
+}
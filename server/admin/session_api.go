@@ -42,6 +42,7 @@ type LoginResponse struct {
 	Roles      map[string]string `json:"roles"`
 	IsSysAdmin bool              `json:"is_sysadmin"`
 	RealmNames map[string]string `json:"realm_names"` // realm_id -> name
+	HostRealm  string            `json:"host_realm,omitempty"` // realm the host is scoped to
 }
 
 // SessionInfo is the response for GET /ui/session.
@@ -52,6 +53,7 @@ type SessionInfo struct {
 	Roles      map[string]string `json:"roles"`
 	IsSysAdmin bool              `json:"is_sysadmin"`
 	RealmNames map[string]string `json:"realm_names"` // realm_id -> name
+	HostRealm  string            `json:"host_realm,omitempty"` // realm the host is scoped to
 }
 
 // OnboardingCheckResponse is the response for GET /ui/check-onboarding.
@@ -149,6 +151,7 @@ func handleUILogin(cfg *RouteConfig) http.HandlerFunc {
 			Roles:      entry.Roles,
 			IsSysAdmin: isSysAdmin,
 			RealmNames: realmNames,
+			HostRealm:  hostRealm(r.Context(), cfg),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -185,6 +188,15 @@ func getRealmNames(ctx context.Context, projectionStore core.ProjectionStore, re
 	return names
 }
 
+// hostRealm returns the realm the request's host is scoped to, or "".
+func hostRealm(ctx context.Context, cfg *RouteConfig) string {
+	if cfg.HostRealm == nil {
+		return ""
+	}
+	realmID, _ := cfg.HostRealm(ctx)
+	return realmID
+}
+
 func handleUISession(cfg *RouteConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get session cookie
@@ -225,6 +237,7 @@ func handleUISession(cfg *RouteConfig) http.HandlerFunc {
 			Roles:      entry.Roles,
 			IsSysAdmin: isSysAdmin,
 			RealmNames: realmNames,
+			HostRealm:  hostRealm(r.Context(), cfg),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -359,56 +372,66 @@ func handleCreateAdmin(cfg *RouteConfig) http.HandlerFunc {
 			return
 		}
 
+		// The realm, the account and its roles are created together, so a
+		// failed step leaves nothing half set up. op and failure describe the
+		// step that failed.
 		var resp CreateAdminResponse
-
-		// Conditionally create realm
-		if req.CreateRealm {
-			realmResult, err := domain.HandleCreateRealm(r.Context(), domain.CreateRealm{
-				Name: strings.TrimSpace(req.RealmName),
-			}, cfg.EventStore)
-			if err != nil {
-				http.Error(w, "failed to create realm", http.StatusInternalServerError)
-				return
-			}
-			resp.RealmID = realmResult.RealmID
-		}
-
-		// Conditionally create sysadmin
-		if req.CreateSysAdmin {
-			result, err := domain.HandleCreateAccount(r.Context(), domain.CreateAccount{
-				Username: strings.TrimSpace(req.Username),
-			}, cfg.EventStore, cfg.ProjectionStore)
-			if err != nil {
-				http.Error(w, "failed to create account", http.StatusInternalServerError)
-				return
+		var op, failure string
+		err := runCommand(r, cfg, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			// Conditionally create realm
+			if req.CreateRealm {
+				op, failure = "handleCreateAdmin: failed to create realm", "failed to create realm"
+				realmResult, err := domain.HandleCreateRealm(ctx, domain.CreateRealm{
+					Name: strings.TrimSpace(req.RealmName),
+				}, events)
+				if err != nil {
+					return err
+				}
+				resp.RealmID = realmResult.RealmID
 			}
 
-			// Grant admin role in _admin realm
-			err = domain.HandleAssignRole(r.Context(), domain.AssignRole{
-				AccountID: result.AccountID,
-				RealmID:   "_admin",
-				Role:      "admin",
-			}, cfg.EventStore)
-			if err != nil {
-				http.Error(w, "failed to assign admin role", http.StatusInternalServerError)
-				return
-			}
+			// Conditionally create sysadmin
+			if req.CreateSysAdmin {
+				op, failure = "handleCreateAdmin: failed to create account", "failed to create account"
+				result, err := domain.HandleCreateAccount(ctx, domain.CreateAccount{
+					Username: strings.TrimSpace(req.Username),
+				}, events, projections)
+				if err != nil {
+					return err
+				}
 
-			// Grant owner role in the realm if we created one
-			if resp.RealmID != "" {
-				err = domain.HandleAssignRole(r.Context(), domain.AssignRole{
+				// Grant admin role in _admin realm
+				op, failure = "handleCreateAdmin: failed to assign admin role", "failed to assign admin role"
+				err = domain.HandleAssignRole(ctx, domain.AssignRole{
 					AccountID: result.AccountID,
-					RealmID:   resp.RealmID,
-					Role:      "owner",
-				}, cfg.EventStore)
+					RealmID:   "_admin",
+					Role:      "admin",
+				}, events)
 				if err != nil {
-					http.Error(w, "failed to assign realm role", http.StatusInternalServerError)
-					return
+					return err
 				}
-			}
 
-			resp.AccountID = result.AccountID
-			resp.PAT = result.RawToken
+				// Grant owner role in the realm if we created one
+				if resp.RealmID != "" {
+					op, failure = "handleCreateAdmin: failed to assign realm role", "failed to assign realm role"
+					err = domain.HandleAssignRole(ctx, domain.AssignRole{
+						AccountID: result.AccountID,
+						RealmID:   resp.RealmID,
+						Role:      "owner",
+					}, events)
+					if err != nil {
+						return err
+					}
+				}
+
+				resp.AccountID = result.AccountID
+				resp.PAT = result.RawToken
+			}
+			return nil
+		})
+		if err != nil {
+			writeDomainError(w, err, op, failure)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -239,6 +239,39 @@ func TestUISessionAPI_GetSession(t *testing.T) {
 	})
 }
 
+func TestUISessionAPI_HostRealm(t *testing.T) {
+	store := newMockProjectionStoreWithAccount()
+	cfg := &RouteConfig{
+		AuthConfig:      DefaultAuthConfig(),
+		ProjectionStore: store,
+		HostRealm: func(ctx context.Context) (string, bool) {
+			return "realm-a", true
+		},
+	}
+	cfg.AuthConfig.SigningKey = make([]byte, 32)
+	_, err := rand.Read(cfg.AuthConfig.SigningKey)
+	require.NoError(t, err, "failed to generate signing key")
+
+	mux := http.NewServeMux()
+	_, err = RegisterRoutes(mux, cfg)
+	require.NoError(t, err)
+
+	t.Run("login reports the realm the host is scoped to", func(t *testing.T) {
+		body, err := json.Marshal(LoginRequest{PAT: store.validToken})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/ui/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp LoginResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "realm-a", resp.HostRealm)
+	})
+}
+
 // TestUISessionAPI_CheckOnboarding tests the GET /ui/check-onboarding endpoint.
 func TestUISessionAPI_CheckOnboarding(t *testing.T) {
 	t.Run("returns needs_onboarding=true when no accounts exist", func(t *testing.T) {
@@ -141,6 +141,49 @@ func TestNewVikeStaticHandler(t *testing.T) {
 	}
 }
 
+func TestNewVikeStaticHandler_Caching(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, writeFile(tmpDir, "index.html", []byte("<html>UI App</html>")))
+	require.NoError(t, writeFile(tmpDir, "assets/index-3f2a.js", []byte("console.log('ui')")))
+
+	handler, err := NewVikeStaticHandler(tmpDir, UIPrefix)
+	require.NoError(t, err)
+
+	serve := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("caches hashed assets for good", func(t *testing.T) {
+		rec := serve("/ui/assets/index-3f2a.js", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+
+	t.Run("revalidates pages", func(t *testing.T) {
+		rec := serve("/ui/runes/123", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("answers 304 for a matching ETag", func(t *testing.T) {
+		etag := serve("/ui/", "").Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		rec := serve("/ui/runes/123", etag)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+}
+
 // Helper function to write files with directory creation
 func writeFile(dir, path string, content []byte) error {
 	fullPath := filepath.Join(dir, path)
@@ -0,0 +1,47 @@
+package automation
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// Archiver moves the streams of runes shattered longer ago than its age
+// into the event store's cold storage. Their projections stay as they are,
+// and a stream comes back the next time it is read.
+type Archiver struct {
+	store core.EventArchiver
+	age   time.Duration
+	now   func() time.Time
+}
+
+func NewArchiver(store core.EventArchiver, age time.Duration) *Archiver {
+	return &Archiver{store: store, age: age, now: time.Now}
+}
+
+// Run archives immediately and then every interval until ctx is done.
+func (a *Archiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := a.Archive(ctx); err != nil {
+			log.Printf("archive: %v", err)
+		} else if n > 0 {
+			log.Printf("archive: archived %d shattered runes", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive archives the runes shattered before the age and returns how many
+// it archived.
+func (a *Archiver) Archive(ctx context.Context) (int, error) {
+	return a.store.ArchiveStreams(ctx, domain.EventRuneShattered, a.now().UTC().Add(-a.age))
+}
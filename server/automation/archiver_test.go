@@ -0,0 +1,79 @@
+package automation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestArchiver(t *testing.T) {
+	t.Run("archives runes shattered longer ago than the age", func(t *testing.T) {
+		tc := newArchiverTestContext(t)
+
+		// Given
+		tc.store_archives(2)
+
+		// When
+		tc.archive_is_called()
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, 2, tc.archived)
+		assert.Equal(t, domain.EventRuneShattered, tc.store.lastEventType)
+		assert.Equal(t, tc.now.Add(-90*24*time.Hour), tc.store.before)
+	})
+}
+
+// --- Test Context ---
+
+type archiverTestContext struct {
+	t        *testing.T
+	archiver *Archiver
+	store    *mockArchiver
+	now      time.Time
+
+	archived int
+	err      error
+}
+
+func newArchiverTestContext(t *testing.T) *archiverTestContext {
+	t.Helper()
+	store := &mockArchiver{}
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	archiver := NewArchiver(store, 90*24*time.Hour)
+	archiver.now = func() time.Time { return now }
+	return &archiverTestContext{t: t, archiver: archiver, store: store, now: now}
+}
+
+// --- Given ---
+
+func (tc *archiverTestContext) store_archives(n int) {
+	tc.t.Helper()
+	tc.store.result = n
+}
+
+// --- When ---
+
+func (tc *archiverTestContext) archive_is_called() {
+	tc.t.Helper()
+	tc.archived, tc.err = tc.archiver.Archive(context.Background())
+}
+
+// --- Mock Archiver ---
+
+type mockArchiver struct {
+	result        int
+	lastEventType string
+	before        time.Time
+}
+
+func (m *mockArchiver) ArchiveStreams(ctx context.Context, lastEventType string, before time.Time) (int, error) {
+	m.lastEventType, m.before = lastEventType, before
+	return m.result, nil
+}
@@ -0,0 +1,126 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Escalator applies each realm's escalation policies to runes whose due date
+// has passed or whose claim has gone stale. Like the SLAMonitor it polls,
+// since the deadlines pass without any event to react to.
+type Escalator struct {
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	now             func() time.Time
+}
+
+func NewEscalator(eventStore core.EventStore, projectionStore core.ProjectionStore) *Escalator {
+	return &Escalator{
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+		now:             time.Now,
+	}
+}
+
+// Run checks immediately and then every interval until ctx is done.
+func (e *Escalator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Check(ctx); err != nil {
+			log.Printf("escalation: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fires every policy that is due in every active realm and returns
+// how many escalations it recorded. A rune whose command fails is logged
+// and skipped.
+func (e *Escalator) Check(ctx context.Context) (int, error) {
+	raws, err := e.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return 0, err
+	}
+	escalated := 0
+	for _, raw := range raws {
+		var realm projectors.RealmListEntry
+		if json.Unmarshal(raw, &realm) != nil || realm.RealmID == "" || realm.RealmID == domain.AdminRealmID || realm.Status != "active" {
+			continue
+		}
+		n, err := e.checkRealm(ctx, realm.RealmID)
+		escalated += n
+		if err != nil {
+			return escalated, err
+		}
+	}
+	return escalated, nil
+}
+
+func (e *Escalator) checkRealm(ctx context.Context, realmID string) (int, error) {
+	raws, err := e.projectionStore.List(ctx, realmID, "escalation_policies")
+	if err != nil {
+		return 0, err
+	}
+	var policies []domain.EscalationPolicyState
+	for _, raw := range raws {
+		var entry projectors.EscalationPolicyEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.ID == "" {
+			continue
+		}
+		policies = append(policies, domain.EscalationPolicyState{
+			PolicyID: entry.ID,
+			RealmID:  entry.RealmID,
+			Name:     entry.Name,
+			Trigger:  entry.Trigger,
+			After:    entry.After,
+			Action:   entry.Action,
+			Exists:   true,
+		})
+	}
+	if len(policies) == 0 {
+		return 0, nil
+	}
+
+	raws, err = e.projectionStore.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return 0, err
+	}
+	now := e.now()
+	ctx = core.ContextWithClock(ctx, core.FixedClock(now))
+	escalated := 0
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.ID == "" {
+			continue
+		}
+		// rune_list may lag the stream; the command re-checks the deadline
+		// against the rune's events before anything is appended.
+		state := domain.RuneState{Status: summary.Status, DueDate: summary.DueDate, ClaimedAt: summary.ClaimedAt}
+		for _, policy := range policies {
+			deadline, _, ok := domain.EscalationDeadline(policy, state)
+			if !ok || now.Before(deadline) {
+				continue
+			}
+			result, err := domain.HandleEscalateRune(ctx, realmID, domain.EscalateRune{RuneID: summary.ID, PolicyID: policy.PolicyID}, e.eventStore)
+			if err != nil {
+				log.Printf("escalation: rune %s in realm %s: %v", summary.ID, realmID, err)
+				continue
+			}
+			if result.Escalated {
+				escalated++
+			}
+		}
+	}
+	return escalated, nil
+}
@@ -0,0 +1,224 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestEscalator(t *testing.T) {
+	t.Run("bumps the priority of an overdue rune", func(t *testing.T) {
+		tc := newEscalatorTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_policy("realm-1", "ep-1", domain.EscalationTriggerOverdue, "", domain.EscalationActionBumpPriority)
+		tc.a_rune_due("realm-1", "bf-a1b2", "2026-03-18")
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.escalated_count_is(1)
+		tc.appended_types_are(domain.EventRuneEscalated, domain.EventRuneUpdated)
+	})
+
+	t.Run("waits out the policy's grace period", func(t *testing.T) {
+		tc := newEscalatorTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_policy("realm-1", "ep-1", domain.EscalationTriggerOverdue, "3d", domain.EscalationActionNotifyAdmins)
+		tc.a_rune_due("realm-1", "bf-a1b2", "2026-03-18")
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.escalated_count_is(0)
+	})
+
+	t.Run("unclaims a stale claim", func(t *testing.T) {
+		tc := newEscalatorTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_policy("realm-1", "ep-1", domain.EscalationTriggerStaleClaim, "2d", domain.EscalationActionUnclaim)
+		tc.a_rune_claimed("realm-1", "bf-a1b2", 3*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.escalated_count_is(1)
+		tc.appended_types_are(domain.EventRuneEscalated, domain.EventRuneUnclaimed)
+	})
+
+	t.Run("does not count runes already escalated for the due date", func(t *testing.T) {
+		tc := newEscalatorTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_policy("realm-1", "ep-1", domain.EscalationTriggerOverdue, "", domain.EscalationActionNotifyAdmins)
+		tc.a_rune_due("realm-1", "bf-a1b2", "2026-03-18")
+		tc.rune_was_escalated("bf-a1b2", "ep-1", "due:2026-03-18")
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.escalated_count_is(0)
+	})
+
+	t.Run("skips suspended realms", func(t *testing.T) {
+		tc := newEscalatorTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "suspended")
+		tc.a_policy("realm-1", "ep-1", domain.EscalationTriggerOverdue, "", domain.EscalationActionNotifyAdmins)
+		tc.a_rune_due("realm-1", "bf-a1b2", "2026-03-18")
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.escalated_count_is(0)
+	})
+}
+
+// --- Test Context ---
+
+type escalatorTestContext struct {
+	t *testing.T
+
+	escalator  *Escalator
+	eventStore *mockEventStore
+	store      *mockProjectionStore
+	now        time.Time
+	escalated  int
+	err        error
+}
+
+func newEscalatorTestContext(t *testing.T) *escalatorTestContext {
+	t.Helper()
+	eventStore := &mockEventStore{streams: make(map[string][]core.Event)}
+	store := &mockProjectionStore{lists: make(map[string][]json.RawMessage), entries: make(map[string]any)}
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	escalator := NewEscalator(eventStore, store)
+	escalator.now = func() time.Time { return now }
+	return &escalatorTestContext{
+		t:          t,
+		escalator:  escalator,
+		eventStore: eventStore,
+		store:      store,
+		now:        now,
+	}
+}
+
+// --- Given ---
+
+func (tc *escalatorTestContext) a_realm(realmID, status string) {
+	tc.t.Helper()
+	tc.list_has("_admin:realm_list", projectors.RealmListEntry{RealmID: realmID, Status: status})
+}
+
+// a_policy puts the policy in both the escalation_policies projection and
+// its event stream, which the command re-reads.
+func (tc *escalatorTestContext) a_policy(realmID, policyID, trigger, after, action string) {
+	tc.t.Helper()
+	tc.list_has(realmID+":escalation_policies", projectors.EscalationPolicyEntry{
+		ID: policyID, RealmID: realmID, Name: "p", Trigger: trigger, After: after, Action: action,
+	})
+	tc.eventStore.streams["escalation-policy-"+policyID] = []core.Event{
+		tc.event(domain.EventEscalationPolicyAdded, domain.EscalationPolicyAdded{
+			PolicyID: policyID, RealmID: realmID, Name: "p", Trigger: trigger, After: after, Action: action,
+		}),
+	}
+}
+
+func (tc *escalatorTestContext) a_rune_due(realmID, runeID, dueDate string) {
+	tc.t.Helper()
+	tc.list_has(realmID+":rune_list", projectors.RuneSummary{ID: runeID, Status: "open", Priority: 2, DueDate: dueDate})
+	tc.eventStore.streams["rune-"+runeID] = []core.Event{
+		tc.event(domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune", Priority: 2, DueDate: dueDate}),
+		tc.event(domain.EventRuneForged, domain.RuneForged{ID: runeID}),
+	}
+}
+
+func (tc *escalatorTestContext) a_rune_claimed(realmID, runeID string, age time.Duration) {
+	tc.t.Helper()
+	claimedAt := tc.now.Add(-age)
+	tc.list_has(realmID+":rune_list", projectors.RuneSummary{ID: runeID, Status: "claimed", Claimant: "odin", ClaimedAt: claimedAt})
+	claimed := tc.event(domain.EventRuneClaimed, domain.RuneClaimed{ID: runeID, Claimant: "odin"})
+	claimed.Timestamp = claimedAt
+	tc.eventStore.streams["rune-"+runeID] = []core.Event{
+		tc.event(domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune"}),
+		tc.event(domain.EventRuneForged, domain.RuneForged{ID: runeID}),
+		claimed,
+	}
+}
+
+func (tc *escalatorTestContext) rune_was_escalated(runeID, policyID, occurrence string) {
+	tc.t.Helper()
+	tc.eventStore.streams["rune-"+runeID] = append(tc.eventStore.streams["rune-"+runeID],
+		tc.event(domain.EventRuneEscalated, domain.RuneEscalated{ID: runeID, PolicyID: policyID, Occurrence: occurrence}))
+}
+
+func (tc *escalatorTestContext) list_has(key string, value any) {
+	tc.t.Helper()
+	raw, err := json.Marshal(value)
+	require.NoError(tc.t, err)
+	tc.store.lists[key] = append(tc.store.lists[key], raw)
+}
+
+func (tc *escalatorTestContext) event(eventType string, data any) core.Event {
+	tc.t.Helper()
+	raw, err := json.Marshal(data)
+	require.NoError(tc.t, err)
+	return core.Event{RealmID: "realm-1", EventType: eventType, Data: raw}
+}
+
+// --- When ---
+
+func (tc *escalatorTestContext) check_is_called() {
+	tc.t.Helper()
+	tc.escalated, tc.err = tc.escalator.Check(context.Background())
+}
+
+// --- Then ---
+
+func (tc *escalatorTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *escalatorTestContext) escalated_count_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.escalated)
+	if expected == 0 {
+		assert.Empty(tc.t, tc.eventStore.appended)
+	}
+}
+
+func (tc *escalatorTestContext) appended_types_are(expected ...string) {
+	tc.t.Helper()
+	var types []string
+	for _, appended := range tc.eventStore.appended {
+		types = append(types, appended.EventType)
+	}
+	assert.Equal(tc.t, expected, types)
+}
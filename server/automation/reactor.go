@@ -0,0 +1,173 @@
+// Package automation runs per-realm automation rules.
+//
+// A rule pairs a trigger (a rune lifecycle change such as "fulfilled") with an
+// action (set_priority or add_note) and an optional case-insensitive title
+// match. Rules are added through the API and projected into the realm's
+// automation_rules projection; the Reactor reads them as events arrive and
+// issues the matching commands.
+//
+// Like notify.Router, the Reactor is registered with the projection engine
+// and drops events that are too old or already handled in this process.
+//
+// The SLAMonitor enforces the realm's sla.<status> settings, recording an
+// SLABreached event for runes left in a status for too long.
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+type Reactor struct {
+	eventStore core.EventStore
+	maxAge     time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	handled map[string]int64
+}
+
+type ReactorOption func(*Reactor)
+
+// WithMaxEventAge sets how old an event may be and still trigger rules.
+func WithMaxEventAge(age time.Duration) ReactorOption {
+	return func(r *Reactor) {
+		r.maxAge = age
+	}
+}
+
+func NewReactor(eventStore core.EventStore, opts ...ReactorOption) *Reactor {
+	r := &Reactor{
+		eventStore: eventStore,
+		maxAge:     10 * time.Minute,
+		now:        time.Now,
+		handled:    make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Reactor) Name() string {
+	return "automation"
+}
+
+// HasSideEffects reports true because rule actions append events of their
+// own, which must only follow committed ones.
+func (r *Reactor) HasSideEffects() bool {
+	return true
+}
+
+// Handle applies the realm's rules for the event's trigger, in name order.
+// A failing action is logged rather than returned so it never holds back
+// the checkpoint.
+func (r *Reactor) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	trigger := triggerFor(event.EventType)
+	if trigger == "" || event.RealmID == domain.AdminRealmID || !r.claim(event) {
+		return nil
+	}
+	if !event.Timestamp.IsZero() && r.now().Sub(event.Timestamp) > r.maxAge {
+		return nil
+	}
+
+	var ref struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(event.Data, &ref); err != nil || ref.ID == "" {
+		return nil
+	}
+
+	raws, err := store.List(ctx, event.RealmID, "automation_rules")
+	if err != nil {
+		return err
+	}
+	var rules []projectors.AutomationRuleEntry
+	for _, raw := range raws {
+		var rule projectors.AutomationRuleEntry
+		if json.Unmarshal(raw, &rule) != nil || rule.Trigger != trigger {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	var detail projectors.RuneDetail
+	if err := store.Get(ctx, event.RealmID, "rune_detail", ref.ID, &detail); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if rule.Match != "" && !strings.Contains(strings.ToLower(detail.Title), strings.ToLower(rule.Match)) {
+			continue
+		}
+		if err := r.apply(ctx, event.RealmID, rule, detail); err != nil {
+			log.Printf("automation: rule %s on %s: %v", rule.ID, detail.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r *Reactor) apply(ctx context.Context, realmID string, rule projectors.AutomationRuleEntry, detail projectors.RuneDetail) error {
+	switch rule.Action {
+	case domain.AutomationActionSetPriority:
+		priority, err := strconv.Atoi(rule.Value)
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", rule.Value)
+		}
+		if priority == detail.Priority {
+			return nil
+		}
+		return domain.HandleUpdateRune(ctx, realmID, domain.UpdateRune{ID: detail.ID, Priority: &priority}, r.eventStore)
+	case domain.AutomationActionAddNote:
+		return domain.HandleAddNote(ctx, realmID, domain.AddNote{RuneID: detail.ID, Text: RenderNote(rule.Value, detail)}, r.eventStore)
+	}
+	return fmt.Errorf("unknown action %q", rule.Action)
+}
+
+// RenderNote expands the {id}, {title} and {claimant} placeholders in an
+// add_note template.
+func RenderNote(template string, detail projectors.RuneDetail) string {
+	return strings.NewReplacer(
+		"{id}", detail.ID,
+		"{title}", detail.Title,
+		"{claimant}", detail.Claimant,
+	).Replace(template)
+}
+
+func triggerFor(eventType string) string {
+	for trigger, t := range domain.AutomationTriggers {
+		if t == eventType {
+			return trigger
+		}
+	}
+	return ""
+}
+
+// claim records the event as handled, returning false when it already was.
+// Catch-up cycles can overlap, so the same event may arrive twice.
+func (r *Reactor) claim(event core.Event) bool {
+	if event.GlobalPosition == 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.GlobalPosition <= r.handled[event.RealmID] {
+		return false
+	}
+	r.handled[event.RealmID] = event.GlobalPosition
+	return true
+}
@@ -0,0 +1,321 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestReactor(t *testing.T) {
+	t.Run("Name returns automation", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Then
+		assert.Equal(t, "automation", tc.reactor.Name())
+	})
+
+	t.Run("sets priority when a matching rune is created", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "[URGENT] Login broken", 2)
+		tc.a_rule("ar-1", "Urgent first", "created", "[urgent]", domain.AutomationActionSetPriority, "0")
+		tc.an_event(1, domain.EventRuneCreated, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		update := tc.only_appended(domain.EventRuneUpdated)
+		var data domain.RuneUpdated
+		require.NoError(t, json.Unmarshal(update, &data))
+		require.NotNil(t, data.Priority)
+		assert.Equal(t, 0, *data.Priority)
+	})
+
+	t.Run("skips rules whose match is not in the title", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.a_rule("ar-1", "Urgent first", "created", "[urgent]", domain.AutomationActionSetPriority, "0")
+		tc.an_event(1, domain.EventRuneCreated, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("adds rendered note when a rune is fulfilled", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.detail.Claimant = "alice"
+		tc.a_rule("ar-1", "Release note", "fulfilled", "", domain.AutomationActionAddNote, "{claimant} finished {id}: {title}")
+		tc.an_event(1, domain.EventRuneFulfilled, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		note := tc.only_appended(domain.EventRuneNoted)
+		var data domain.RuneNoted
+		require.NoError(t, json.Unmarshal(note, &data))
+		assert.Equal(t, "alice finished bf-a1b2: Login broken", data.Text)
+	})
+
+	t.Run("ignores rules for other triggers", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.a_rule("ar-1", "Release note", "fulfilled", "", domain.AutomationActionAddNote, "done")
+		tc.an_event(1, domain.EventRuneClaimed, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("does not set an unchanged priority", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 0)
+		tc.a_rule("ar-1", "Top priority", "created", "", domain.AutomationActionSetPriority, "0")
+		tc.an_event(1, domain.EventRuneCreated, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("does not react to the same event twice", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.a_rule("ar-1", "Release note", "fulfilled", "", domain.AutomationActionAddNote, "done")
+		tc.an_event(1, domain.EventRuneFulfilled, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+		tc.handle_is_called()
+
+		// Then
+		assert.Len(t, tc.eventStore.appended, 1)
+	})
+
+	t.Run("skips events older than max age", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.a_rule("ar-1", "Release note", "fulfilled", "", domain.AutomationActionAddNote, "done")
+		tc.an_event(1, domain.EventRuneFulfilled, "bf-a1b2")
+		tc.event.Timestamp = time.Now().Add(-time.Hour)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("does not fail the checkpoint when an action fails", func(t *testing.T) {
+		tc := newReactorTestContext(t)
+
+		// Given
+		tc.a_rune("bf-a1b2", "Login broken", 2)
+		tc.eventStore.streams = map[string][]core.Event{}
+		tc.a_rule("ar-1", "Release note", "fulfilled", "", domain.AutomationActionAddNote, "done")
+		tc.an_event(1, domain.EventRuneFulfilled, "bf-a1b2")
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+}
+
+// --- Test Context ---
+
+type reactorTestContext struct {
+	t *testing.T
+
+	reactor    *Reactor
+	eventStore *mockEventStore
+	store      *mockProjectionStore
+	detail     projectors.RuneDetail
+	event      core.Event
+	err        error
+}
+
+func newReactorTestContext(t *testing.T) *reactorTestContext {
+	t.Helper()
+	eventStore := &mockEventStore{streams: make(map[string][]core.Event)}
+	return &reactorTestContext{
+		t:          t,
+		reactor:    NewReactor(eventStore),
+		eventStore: eventStore,
+		store:      &mockProjectionStore{lists: make(map[string][]json.RawMessage)},
+	}
+}
+
+// --- Given ---
+
+func (tc *reactorTestContext) a_rune(id, title string, priority int) {
+	tc.t.Helper()
+	data, err := json.Marshal(domain.RuneCreated{ID: id, Title: title, Priority: priority, Branch: "main"})
+	require.NoError(tc.t, err)
+	tc.eventStore.streams["rune-"+id] = []core.Event{
+		{RealmID: "realm-1", StreamID: "rune-" + id, Version: 0, EventType: domain.EventRuneCreated, Data: data},
+	}
+	tc.detail = projectors.RuneDetail{ID: id, Title: title, Status: "open", Priority: priority}
+	tc.store.detail = &tc.detail
+}
+
+func (tc *reactorTestContext) a_rule(id, name, trigger, match, action, value string) {
+	tc.t.Helper()
+	raw, err := json.Marshal(projectors.AutomationRuleEntry{
+		ID: id, RealmID: "realm-1", Name: name, Trigger: trigger, Match: match, Action: action, Value: value,
+	})
+	require.NoError(tc.t, err)
+	tc.store.lists["realm-1:automation_rules"] = append(tc.store.lists["realm-1:automation_rules"], raw)
+}
+
+func (tc *reactorTestContext) an_event(position int64, eventType, runeID string) {
+	tc.t.Helper()
+	tc.event = core.Event{
+		RealmID:        "realm-1",
+		StreamID:       "rune-" + runeID,
+		Version:        1,
+		GlobalPosition: position,
+		EventType:      eventType,
+		Data:           []byte(`{"id":"` + runeID + `"}`),
+		Timestamp:      time.Now(),
+	}
+}
+
+// --- When ---
+
+func (tc *reactorTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.reactor.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *reactorTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *reactorTestContext) only_appended(eventType string) []byte {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appended, 1)
+	appended := tc.eventStore.appended[0]
+	assert.Equal(tc.t, eventType, appended.EventType)
+	data, err := json.Marshal(appended.Data)
+	require.NoError(tc.t, err)
+	return data
+}
+
+// --- Mock Event Store ---
+
+type mockEventStore struct {
+	streams   map[string][]core.Event
+	appended  []core.EventData
+	snapshots map[string]core.Snapshot
+}
+
+func (m *mockEventStore) Append(_ context.Context, _ string, _ string, _ int, events []core.EventData) ([]core.Event, error) {
+	m.appended = append(m.appended, events...)
+	return nil, nil
+}
+
+func (m *mockEventStore) ReadStream(_ context.Context, _ string, streamID string, _ int) ([]core.Event, error) {
+	return m.streams[streamID], nil
+}
+
+func (m *mockEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (m *mockEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockEventStore) GetSnapshot(_ context.Context, _ string, streamID string) (core.Snapshot, error) {
+	snapshot, ok := m.snapshots[streamID]
+	if !ok {
+		return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+	}
+	return snapshot, nil
+}
+
+func (m *mockEventStore) SaveSnapshot(_ context.Context, snapshot core.Snapshot) error {
+	if m.snapshots == nil {
+		m.snapshots = make(map[string]core.Snapshot)
+	}
+	m.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	lists   map[string][]json.RawMessage
+	entries map[string]any
+	detail  *projectors.RuneDetail
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	value, ok := m.entries[realmID+":"+projectionName+":"+key]
+	if projectionName == "rune_detail" && m.detail != nil && m.detail.ID == key {
+		value, ok = m.detail, true
+	}
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, _ string, _ string, _ string, _ any) error {
+	return nil
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	return m.lists[realmID+":"+projectionName], nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, _ string, _ string, _ string) error {
+	return nil
+}
@@ -0,0 +1,128 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// RetentionEnforcer applies each realm's retention.* settings: it shatters
+// finished runes left untouched for longer than retention.finished_runes,
+// and records a RuneHistoryPruned event that has rune_transitions drop the
+// transitions older than retention.transitions. The projectors prune their
+// read models in response to those events, so a rebuild ends up in the same
+// state. Given a truncator, it also deletes the events of runes shattered
+// longer ago than retention.shattered_runes.
+type RetentionEnforcer struct {
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	truncator       core.EventTruncator
+	now             func() time.Time
+}
+
+type RetentionOption func(*RetentionEnforcer)
+
+// WithTruncator lets the enforcer delete shattered runes' events through
+// truncator. Without one, retention.shattered_runes is ignored.
+func WithTruncator(truncator core.EventTruncator) RetentionOption {
+	return func(e *RetentionEnforcer) {
+		e.truncator = truncator
+	}
+}
+
+func NewRetentionEnforcer(eventStore core.EventStore, projectionStore core.ProjectionStore, opts ...RetentionOption) *RetentionEnforcer {
+	e := &RetentionEnforcer{
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// RetentionResult counts what one pass did.
+type RetentionResult struct {
+	Shattered    int // Finished runes shattered
+	PrunedRealms int // Realms whose transition history was pruned
+	Truncated    int // Shattered runes whose events were deleted
+}
+
+// Run enforces immediately and then every interval until ctx is done.
+func (e *RetentionEnforcer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Enforce(ctx); err != nil {
+			log.Printf("retention: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Enforce applies the retention policy of every active realm.
+func (e *RetentionEnforcer) Enforce(ctx context.Context) (RetentionResult, error) {
+	var result RetentionResult
+	raws, err := e.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return result, err
+	}
+	for _, raw := range raws {
+		var realm projectors.RealmListEntry
+		if json.Unmarshal(raw, &realm) != nil || realm.RealmID == "" || realm.RealmID == domain.AdminRealmID || realm.Status != "active" {
+			continue
+		}
+		if err := e.enforceRealm(ctx, realm.RealmID, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (e *RetentionEnforcer) enforceRealm(ctx context.Context, realmID string, result *RetentionResult) error {
+	settings, err := projectors.GetRealmSettings(ctx, e.projectionStore, realmID)
+	if err != nil {
+		return err
+	}
+	policy := domain.RetentionPolicyFromSettings(settings)
+	now := e.now().UTC()
+
+	if policy.FinishedRunes > 0 {
+		shattered, err := domain.HandleSweepRunesFinishedBefore(ctx, realmID, now.Add(-policy.FinishedRunes), e.eventStore, e.projectionStore)
+		if err != nil {
+			return err
+		}
+		result.Shattered += len(shattered)
+	}
+
+	if policy.Transitions > 0 {
+		// Pruning to the start of a day records at most one event a day.
+		before := now.Add(-policy.Transitions).Truncate(24 * time.Hour)
+		pruned, err := domain.HandlePruneRuneHistory(ctx, realmID, domain.PruneRuneHistory{Before: before}, e.eventStore)
+		if err != nil {
+			return err
+		}
+		if pruned {
+			result.PrunedRealms++
+		}
+	}
+
+	if policy.ShatteredRunes > 0 && e.truncator != nil {
+		truncated, err := domain.HandleTruncateShatteredRunes(ctx, realmID, domain.TruncateShatteredRunes{Before: now.Add(-policy.ShatteredRunes)}, e.eventStore, e.truncator)
+		if err != nil {
+			return err
+		}
+		result.Truncated += truncated
+	}
+	return nil
+}
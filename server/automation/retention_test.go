@@ -0,0 +1,267 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRetentionEnforcer(t *testing.T) {
+	t.Run("shatters finished runes untouched for longer than the retention period", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionFinishedRunes: "30d"})
+		tc.a_rune_last_changed("realm-1", "bf-old", "fulfilled", 31*24*time.Hour)
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 1, tc.result.Shattered)
+		var data domain.RuneShattered
+		require.NoError(t, json.Unmarshal(tc.only_appended(domain.EventRuneShattered), &data))
+		assert.Equal(t, "bf-old", data.ID)
+	})
+
+	t.Run("keeps recently finished and unfinished runes", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionFinishedRunes: "30d"})
+		tc.a_rune_last_changed("realm-1", "bf-recent", "sealed", 29*24*time.Hour)
+		tc.a_rune_last_changed("realm-1", "bf-open", "open", 90*24*time.Hour)
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 0, tc.result.Shattered)
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("prunes rune history up to the start of the day the retention period ago", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionTransitions: "90d"})
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 1, tc.result.PrunedRealms)
+		var data domain.RuneHistoryPruned
+		require.NoError(t, json.Unmarshal(tc.only_appended(domain.EventRuneHistoryPruned), &data))
+		assert.Equal(t, time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), data.Before)
+	})
+
+	t.Run("does not prune the same day twice", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionTransitions: "90d"})
+		tc.history_pruned_before(time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC))
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 0, tc.result.PrunedRealms)
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("truncates runes shattered longer ago than the retention period", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionShatteredRunes: "180d"})
+		tc.a_truncator_truncating(2)
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 2, tc.result.Truncated)
+		assert.Equal(t, []string{"realm-1"}, tc.truncator.realms)
+		assert.Equal(t, tc.now.Add(-180*24*time.Hour), tc.truncator.before)
+		assert.Len(t, tc.eventStore.snapshots, 2)
+		var data domain.RuneEventsTruncated
+		require.NoError(t, json.Unmarshal(tc.only_appended(domain.EventRuneEventsTruncated), &data))
+		assert.Equal(t, 2, data.Runes)
+	})
+
+	t.Run("ignores the shattered rune period without a truncator", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{domain.RetentionShatteredRunes: "180d"})
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, RetentionResult{}, tc.result)
+	})
+
+	t.Run("skips realms without a retention policy and suspended realms", func(t *testing.T) {
+		tc := newRetentionTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{})
+		tc.a_realm("realm-2", "suspended", map[string]string{domain.RetentionFinishedRunes: "30d"})
+		tc.a_rune_last_changed("realm-1", "bf-old", "fulfilled", 365*24*time.Hour)
+		tc.a_rune_last_changed("realm-2", "bf-other", "fulfilled", 365*24*time.Hour)
+
+		// When
+		tc.enforce_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, RetentionResult{}, tc.result)
+		assert.Empty(t, tc.eventStore.appended)
+	})
+}
+
+// --- Test Context ---
+
+type retentionTestContext struct {
+	t *testing.T
+
+	enforcer   *RetentionEnforcer
+	eventStore *mockEventStore
+	store      *mockProjectionStore
+	truncator  *mockTruncator
+	now        time.Time
+	result     RetentionResult
+	err        error
+}
+
+func newRetentionTestContext(t *testing.T) *retentionTestContext {
+	t.Helper()
+	eventStore := &mockEventStore{streams: make(map[string][]core.Event)}
+	store := &mockProjectionStore{lists: make(map[string][]json.RawMessage), entries: make(map[string]any)}
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	enforcer := NewRetentionEnforcer(eventStore, store)
+	enforcer.now = func() time.Time { return now }
+	return &retentionTestContext{
+		t:          t,
+		enforcer:   enforcer,
+		eventStore: eventStore,
+		store:      store,
+		now:        now,
+	}
+}
+
+// --- Given ---
+
+func (tc *retentionTestContext) a_realm(realmID, status string, settings map[string]string) {
+	tc.t.Helper()
+	raw, err := json.Marshal(projectors.RealmListEntry{RealmID: realmID, Status: status})
+	require.NoError(tc.t, err)
+	tc.store.lists["_admin:realm_list"] = append(tc.store.lists["_admin:realm_list"], raw)
+	tc.store.entries["_admin:realm_settings:"+realmID] = projectors.RealmSettingsEntry{RealmID: realmID, Settings: settings}
+}
+
+// a_rune_last_changed puts a rune in status, last changed age ago, in
+// rune_list and in its event stream.
+func (tc *retentionTestContext) a_rune_last_changed(realmID, runeID, status string, age time.Duration) {
+	tc.t.Helper()
+	raw, err := json.Marshal(projectors.RuneSummary{ID: runeID, Status: status, UpdatedAt: tc.now.Add(-age)})
+	require.NoError(tc.t, err)
+	tc.store.lists[realmID+":rune_list"] = append(tc.store.lists[realmID+":rune_list"], raw)
+
+	events := []core.Event{
+		tc.event("rune-"+runeID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune"}),
+		tc.event("rune-"+runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID}),
+	}
+	switch status {
+	case "fulfilled":
+		events = append(events, tc.event("rune-"+runeID, domain.EventRuneFulfilled, domain.RuneFulfilled{ID: runeID}))
+	case "sealed":
+		events = append(events, tc.event("rune-"+runeID, domain.EventRuneSealed, domain.RuneSealed{ID: runeID}))
+	}
+	tc.eventStore.streams["rune-"+runeID] = events
+}
+
+func (tc *retentionTestContext) a_truncator_truncating(n int) {
+	tc.t.Helper()
+	tc.truncator = &mockTruncator{truncates: n}
+	WithTruncator(tc.truncator)(tc.enforcer)
+}
+
+func (tc *retentionTestContext) history_pruned_before(before time.Time) {
+	tc.t.Helper()
+	tc.eventStore.streams["retention"] = []core.Event{
+		tc.event("retention", domain.EventRuneHistoryPruned, domain.RuneHistoryPruned{Before: before}),
+	}
+}
+
+func (tc *retentionTestContext) event(streamID, eventType string, data any) core.Event {
+	tc.t.Helper()
+	raw, err := json.Marshal(data)
+	require.NoError(tc.t, err)
+	return core.Event{RealmID: "realm-1", StreamID: streamID, EventType: eventType, Data: raw}
+}
+
+// --- When ---
+
+func (tc *retentionTestContext) enforce_is_called() {
+	tc.t.Helper()
+	tc.result, tc.err = tc.enforcer.Enforce(context.Background())
+}
+
+// --- Then ---
+
+func (tc *retentionTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *retentionTestContext) only_appended(eventType string) []byte {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appended, 1)
+	appended := tc.eventStore.appended[0]
+	assert.Equal(tc.t, eventType, appended.EventType)
+	data, err := json.Marshal(appended.Data)
+	require.NoError(tc.t, err)
+	return data
+}
+
+// --- Mock Truncator ---
+
+type mockTruncator struct {
+	truncates int
+	realms    []string
+	before    time.Time
+}
+
+func (m *mockTruncator) TruncatableStreams(_ context.Context, _ string, _ string, _ time.Time) ([]string, error) {
+	streamIDs := make([]string, m.truncates)
+	for i := range streamIDs {
+		streamIDs[i] = fmt.Sprintf("rune-bf-%d", i+1)
+	}
+	return streamIDs, nil
+}
+
+func (m *mockTruncator) TruncateStreams(_ context.Context, realmID string, _ string, before time.Time) (int, error) {
+	m.realms = append(m.realms, realmID)
+	m.before = before
+	return m.truncates, nil
+}
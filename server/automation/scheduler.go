@@ -0,0 +1,189 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Scheduler runs each realm's schedules when their cron expressions fall
+// due. Every run is claimed with a ScheduleRunStarted event before the
+// command runs and closed with ScheduleRunFinished, so a run happens at
+// most once even with several servers polling. It also applies the realm's
+// priority aging policy to its open runes.
+type Scheduler struct {
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	now             func() time.Time
+}
+
+func NewScheduler(eventStore core.EventStore, projectionStore core.ProjectionStore) *Scheduler {
+	return &Scheduler{
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+		now:             time.Now,
+	}
+}
+
+// Run checks immediately and then every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Check(ctx); err != nil {
+			log.Printf("scheduler: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs every due schedule in every active realm and returns how many
+// it ran. A failing command is recorded on its run rather than returned.
+func (s *Scheduler) Check(ctx context.Context) (int, error) {
+	raws, err := s.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return 0, err
+	}
+	ran := 0
+	for _, raw := range raws {
+		var realm projectors.RealmListEntry
+		if json.Unmarshal(raw, &realm) != nil || realm.RealmID == "" || realm.RealmID == domain.AdminRealmID || realm.Status != "active" {
+			continue
+		}
+		n, err := s.checkRealm(ctx, realm.RealmID)
+		ran += n
+		if err != nil {
+			return ran, err
+		}
+		if err := s.agePriorities(ctx, realm.RealmID); err != nil {
+			return ran, err
+		}
+	}
+	return ran, nil
+}
+
+// agePriorities raises the priority of the realm's open runes that have
+// waited past its priority_aging thresholds. Runes created more recently
+// than the first threshold cannot be due and are not read.
+func (s *Scheduler) agePriorities(ctx context.Context, realmID string) error {
+	settings, err := projectors.GetRealmSettings(ctx, s.projectionStore, realmID)
+	if err != nil {
+		return err
+	}
+	thresholds := domain.PriorityAgingFromSettings(settings)
+	if len(thresholds) == 0 {
+		return nil
+	}
+	raws, err := s.projectionStore.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	ctx = core.ContextWithClock(ctx, core.FixedClock(now))
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.Status != "open" || summary.Priority == 0 || now.Sub(summary.CreatedAt) < thresholds[0] {
+			continue
+		}
+		if _, err := domain.HandleAgeRunePriority(ctx, realmID, domain.AgeRunePriority{ID: summary.ID}, s.eventStore); err != nil {
+			log.Printf("scheduler: aging rune %s in realm %s: %v", summary.ID, realmID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) checkRealm(ctx context.Context, realmID string) (int, error) {
+	raws, err := s.projectionStore.List(ctx, realmID, "schedules")
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	ctx = core.ContextWithClock(ctx, core.FixedClock(now))
+	ran := 0
+	for _, raw := range raws {
+		var entry projectors.ScheduleEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.ID == "" || entry.NextRunAt.IsZero() || now.Before(entry.NextRunAt) {
+			continue
+		}
+		started, err := domain.HandleStartScheduleRun(ctx, realmID, domain.StartScheduleRun{ScheduleID: entry.ID}, s.eventStore)
+		if err != nil {
+			log.Printf("scheduler: schedule %s in realm %s: %v", entry.ID, realmID, err)
+			continue
+		}
+		if !started.Started {
+			continue
+		}
+
+		result, runErr := s.execute(ctx, realmID, entry)
+		finish := domain.FinishScheduleRun{ScheduleID: entry.ID, DueAt: started.DueAt, Result: result}
+		if runErr != nil {
+			finish.Error = runErr.Error()
+			log.Printf("scheduler: schedule %s in realm %s: %s: %v", entry.ID, realmID, entry.Command, runErr)
+		}
+		if err := domain.HandleFinishScheduleRun(ctx, realmID, finish, s.eventStore); err != nil {
+			log.Printf("scheduler: schedule %s in realm %s: %v", entry.ID, realmID, err)
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// execute runs the schedule's command and describes what it did.
+func (s *Scheduler) execute(ctx context.Context, realmID string, entry projectors.ScheduleEntry) (string, error) {
+	switch entry.Command {
+	case domain.ScheduleCommandSweepRunes:
+		shattered, err := domain.HandleSweepRunes(ctx, realmID, s.eventStore, s.projectionStore)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("shattered %d runes", len(shattered)), nil
+	case domain.ScheduleCommandSealStaleDrafts:
+		return s.sealStaleDrafts(ctx, realmID, entry.Args)
+	case domain.ScheduleCommandCreateRune:
+		cmd, err := domain.ScheduledCreateRune(entry.Args)
+		if err != nil {
+			return "", err
+		}
+		created, err := domain.HandleCreateRune(ctx, realmID, cmd, s.eventStore, s.projectionStore)
+		if err != nil {
+			return "", err
+		}
+		return "created " + created.ID, nil
+	}
+	return "", fmt.Errorf("unknown schedule command %q", entry.Command)
+}
+
+func (s *Scheduler) sealStaleDrafts(ctx context.Context, realmID string, args map[string]string) (string, error) {
+	age, err := domain.StaleDraftAge(args)
+	if err != nil {
+		return "", err
+	}
+	raws, err := s.projectionStore.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return "", err
+	}
+	cutoff := s.now().Add(-age)
+	sealed := 0
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.Status != "draft" || summary.CreatedAt.IsZero() || !summary.CreatedAt.Before(cutoff) {
+			continue
+		}
+		cmd := domain.SealRune{ID: summary.ID, Reason: fmt.Sprintf("Draft older than %s days", args["days"])}
+		if err := domain.HandleSealRune(ctx, realmID, cmd, s.eventStore); err != nil {
+			return fmt.Sprintf("sealed %d runes", sealed), err
+		}
+		sealed++
+	}
+	return fmt.Sprintf("sealed %d runes", sealed), nil
+}
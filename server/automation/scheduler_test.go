@@ -0,0 +1,356 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestScheduler(t *testing.T) {
+	t.Run("runs a due sweep and records its outcome", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandSweepRunes, nil)
+		tc.a_rune("realm-1", "bf-a1b2", "sealed", 0)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.ran_count_is(1)
+		tc.appended_types_are(domain.EventScheduleRunStarted, domain.EventRuneShattered, domain.EventScheduleRunFinished)
+		assert.Equal(t, "shattered 1 runes", tc.finished().Result)
+	})
+
+	t.Run("leaves schedules that are not yet due", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandSweepRunes, nil)
+		tc.schedule_next_run_is("realm-1", "sc-1", tc.now.Add(time.Hour))
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.ran_count_is(0)
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("seals drafts older than the configured days", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandSealStaleDrafts, map[string]string{"days": "30"})
+		tc.a_rune("realm-1", "bf-old1", "draft", 31*24*time.Hour)
+		tc.a_rune("realm-1", "bf-new1", "draft", 2*24*time.Hour)
+		tc.a_rune("realm-1", "bf-open", "open", 90*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.appended_types_are(domain.EventScheduleRunStarted, domain.EventRuneSealed, domain.EventScheduleRunFinished)
+		assert.Equal(t, domain.RuneSealed{ID: "bf-old1", Reason: "Draft older than 30 days"}, tc.eventStore.appended[1].Data)
+		assert.Equal(t, "sealed 1 runes", tc.finished().Result)
+	})
+
+	t.Run("creates a recurring rune", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandCreateRune, map[string]string{"title": "Weekly review", "branch": "main"})
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.appended_types_are(domain.EventScheduleRunStarted, domain.EventRuneCreated, domain.EventScheduleRunFinished)
+		created, ok := tc.eventStore.appended[1].Data.(domain.RuneCreated)
+		require.True(t, ok)
+		assert.Equal(t, "Weekly review", created.Title)
+		assert.Equal(t, "created "+created.ID, tc.finished().Result)
+	})
+
+	t.Run("records a failing command on the run", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandCreateRune, map[string]string{"title": "Child", "parent_id": "bf-gone"})
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.ran_count_is(1)
+		tc.appended_types_are(domain.EventScheduleRunStarted, domain.EventScheduleRunFinished)
+		assert.Contains(t, tc.finished().Error, "bf-gone")
+	})
+
+	t.Run("raises the priority of open runes past the aging thresholds", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.realm_ages_priorities("realm-1", "7d,14d")
+		tc.an_open_rune("realm-1", "bf-old1", 3, 15*24*time.Hour)
+		tc.an_open_rune("realm-1", "bf-mid1", 3, 8*24*time.Hour)
+		tc.an_open_rune("realm-1", "bf-new1", 3, 2*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.ran_count_is(0)
+		tc.priority_changes_are(map[string]int{"bf-old1": 1, "bf-mid1": 2})
+	})
+
+	t.Run("does not age a rune again for steps already taken", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.realm_ages_priorities("realm-1", "7d,14d")
+		tc.an_open_rune("realm-1", "bf-old1", 3, 8*24*time.Hour)
+		tc.rune_was_aged("bf-old1", 2)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("restarts aging when someone sets the priority", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active")
+		tc.realm_ages_priorities("realm-1", "7d")
+		tc.an_open_rune("realm-1", "bf-old1", 3, 30*24*time.Hour)
+		tc.rune_priority_set("bf-old1", 4, 24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.eventStore.appended)
+	})
+
+	t.Run("skips suspended realms", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "suspended")
+		tc.a_schedule("realm-1", "sc-1", domain.ScheduleCommandSweepRunes, nil)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.ran_count_is(0)
+	})
+}
+
+// --- Test Context ---
+
+type schedulerTestContext struct {
+	t *testing.T
+
+	scheduler  *Scheduler
+	eventStore *mockEventStore
+	store      *mockProjectionStore
+	now        time.Time
+	ran        int
+	err        error
+}
+
+func newSchedulerTestContext(t *testing.T) *schedulerTestContext {
+	t.Helper()
+	eventStore := &mockEventStore{streams: make(map[string][]core.Event)}
+	store := &mockProjectionStore{lists: make(map[string][]json.RawMessage), entries: make(map[string]any)}
+	now := time.Date(2026, 3, 20, 12, 0, 30, 0, time.UTC)
+	scheduler := NewScheduler(eventStore, store)
+	scheduler.now = func() time.Time { return now }
+	return &schedulerTestContext{
+		t:          t,
+		scheduler:  scheduler,
+		eventStore: eventStore,
+		store:      store,
+		now:        now,
+	}
+}
+
+// --- Given ---
+
+func (tc *schedulerTestContext) a_realm(realmID, status string) {
+	tc.t.Helper()
+	tc.list_has("_admin:realm_list", projectors.RealmListEntry{RealmID: realmID, Status: status})
+}
+
+// a_schedule adds an hourly schedule, created yesterday, that is due now.
+func (tc *schedulerTestContext) a_schedule(realmID, scheduleID, command string, args map[string]string) {
+	tc.t.Helper()
+	createdAt := tc.now.Add(-24 * time.Hour)
+	tc.list_has(realmID+":schedules", projectors.ScheduleEntry{
+		ID: scheduleID, RealmID: realmID, Name: "s", Cron: "@hourly", Command: command, Args: args,
+		CreatedAt: createdAt, NextRunAt: createdAt.Truncate(time.Hour).Add(time.Hour),
+	})
+	tc.eventStore.streams["schedule-"+scheduleID] = []core.Event{
+		tc.event(domain.EventScheduleCreated, domain.ScheduleCreated{
+			ScheduleID: scheduleID, RealmID: realmID, Name: "s", Cron: "@hourly", Command: command, Args: args, CreatedAt: createdAt,
+		}),
+	}
+}
+
+func (tc *schedulerTestContext) schedule_next_run_is(realmID, scheduleID string, next time.Time) {
+	tc.t.Helper()
+	key := realmID + ":schedules"
+	for i, raw := range tc.store.lists[key] {
+		var entry projectors.ScheduleEntry
+		require.NoError(tc.t, json.Unmarshal(raw, &entry))
+		if entry.ID == scheduleID {
+			entry.NextRunAt = next
+			updated, err := json.Marshal(entry)
+			require.NoError(tc.t, err)
+			tc.store.lists[key][i] = updated
+		}
+	}
+}
+
+func (tc *schedulerTestContext) a_rune(realmID, runeID, status string, age time.Duration) {
+	tc.t.Helper()
+	tc.list_has(realmID+":rune_list", projectors.RuneSummary{ID: runeID, Status: status, CreatedAt: tc.now.Add(-age)})
+	events := []core.Event{tc.event(domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune"})}
+	switch status {
+	case "open":
+		events = append(events, tc.event(domain.EventRuneForged, domain.RuneForged{ID: runeID}))
+	case "sealed":
+		events = append(events, tc.event(domain.EventRuneSealed, domain.RuneSealed{ID: runeID}))
+	}
+	tc.eventStore.streams["rune-"+runeID] = events
+}
+
+// realm_ages_priorities sets the realm's priority_aging setting both in
+// realm_settings and in the realm's stream, where the aging command reads it.
+func (tc *schedulerTestContext) realm_ages_priorities(realmID, thresholds string) {
+	tc.t.Helper()
+	settings := map[string]string{domain.PriorityAgingSetting: thresholds}
+	tc.store.entries["_admin:realm_settings:"+realmID] = projectors.RealmSettingsEntry{RealmID: realmID, Settings: settings}
+	tc.eventStore.streams["realm-"+realmID] = []core.Event{
+		tc.event(domain.EventRealmCreated, domain.RealmCreated{RealmID: realmID, Name: "r"}),
+		tc.event(domain.EventRealmSettingSet, domain.RealmSettingSet{RealmID: realmID, Key: domain.PriorityAgingSetting, Value: thresholds}),
+	}
+}
+
+// an_open_rune adds an open rune created, with its priority, age ago.
+func (tc *schedulerTestContext) an_open_rune(realmID, runeID string, priority int, age time.Duration) {
+	tc.t.Helper()
+	createdAt := tc.now.Add(-age)
+	tc.list_has(realmID+":rune_list", projectors.RuneSummary{ID: runeID, Status: "open", Priority: priority, CreatedAt: createdAt})
+	created := tc.event(domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune", Priority: priority})
+	created.Timestamp = createdAt
+	forged := tc.event(domain.EventRuneForged, domain.RuneForged{ID: runeID})
+	forged.Timestamp = createdAt
+	tc.eventStore.streams["rune-"+runeID] = []core.Event{created, forged}
+}
+
+func (tc *schedulerTestContext) rune_was_aged(runeID string, priority int) {
+	tc.t.Helper()
+	aged := tc.event(domain.EventRuneUpdated, domain.RuneUpdated{ID: runeID, Priority: &priority, Actor: domain.PriorityAgingActor})
+	aged.Timestamp = tc.now.Add(-time.Hour)
+	tc.eventStore.streams["rune-"+runeID] = append(tc.eventStore.streams["rune-"+runeID], aged)
+}
+
+func (tc *schedulerTestContext) rune_priority_set(runeID string, priority int, ago time.Duration) {
+	tc.t.Helper()
+	updated := tc.event(domain.EventRuneUpdated, domain.RuneUpdated{ID: runeID, Priority: &priority})
+	updated.Timestamp = tc.now.Add(-ago)
+	tc.eventStore.streams["rune-"+runeID] = append(tc.eventStore.streams["rune-"+runeID], updated)
+}
+
+func (tc *schedulerTestContext) list_has(key string, value any) {
+	tc.t.Helper()
+	raw, err := json.Marshal(value)
+	require.NoError(tc.t, err)
+	tc.store.lists[key] = append(tc.store.lists[key], raw)
+}
+
+func (tc *schedulerTestContext) event(eventType string, data any) core.Event {
+	tc.t.Helper()
+	raw, err := json.Marshal(data)
+	require.NoError(tc.t, err)
+	return core.Event{RealmID: "realm-1", EventType: eventType, Data: raw}
+}
+
+// --- When ---
+
+func (tc *schedulerTestContext) check_is_called() {
+	tc.t.Helper()
+	tc.ran, tc.err = tc.scheduler.Check(context.Background())
+}
+
+// --- Then ---
+
+func (tc *schedulerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *schedulerTestContext) ran_count_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.ran)
+}
+
+func (tc *schedulerTestContext) appended_types_are(expected ...string) {
+	tc.t.Helper()
+	var types []string
+	for _, appended := range tc.eventStore.appended {
+		types = append(types, appended.EventType)
+	}
+	assert.Equal(tc.t, expected, types)
+}
+
+func (tc *schedulerTestContext) priority_changes_are(expected map[string]int) {
+	tc.t.Helper()
+	got := map[string]int{}
+	for _, appended := range tc.eventStore.appended {
+		updated, ok := appended.Data.(domain.RuneUpdated)
+		require.True(tc.t, ok, "expected RuneUpdated, got %s", appended.EventType)
+		assert.Equal(tc.t, domain.PriorityAgingActor, updated.Actor)
+		got[updated.ID] = *updated.Priority
+	}
+	assert.Equal(tc.t, expected, got)
+}
+
+func (tc *schedulerTestContext) finished() domain.ScheduleRunFinished {
+	tc.t.Helper()
+	last := tc.eventStore.appended[len(tc.eventStore.appended)-1]
+	finished, ok := last.Data.(domain.ScheduleRunFinished)
+	require.True(tc.t, ok, "expected ScheduleRunFinished, got %s", last.EventType)
+	return finished
+}
@@ -0,0 +1,138 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// SLAMonitor records an SLABreached event for each rune that has stayed in
+// a status longer than its realm's sla.<status> setting allows. Breaches
+// come from time passing rather than from an event, so unlike the Reactor
+// it polls the projections instead of being registered with the engine.
+type SLAMonitor struct {
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	now             func() time.Time
+}
+
+func NewSLAMonitor(eventStore core.EventStore, projectionStore core.ProjectionStore) *SLAMonitor {
+	return &SLAMonitor{
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+		now:             time.Now,
+	}
+}
+
+// Run checks immediately and then every interval until ctx is done.
+func (m *SLAMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			log.Printf("sla: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check records the breaches found in every active realm and returns how
+// many it recorded. A rune whose command fails is logged and skipped.
+func (m *SLAMonitor) Check(ctx context.Context) (int, error) {
+	raws, err := m.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return 0, err
+	}
+	recorded := 0
+	for _, raw := range raws {
+		var realm projectors.RealmListEntry
+		if json.Unmarshal(raw, &realm) != nil || realm.RealmID == "" || realm.RealmID == domain.AdminRealmID || realm.Status != "active" {
+			continue
+		}
+		n, err := m.checkRealm(ctx, realm.RealmID)
+		recorded += n
+		if err != nil {
+			return recorded, err
+		}
+	}
+	return recorded, nil
+}
+
+func (m *SLAMonitor) checkRealm(ctx context.Context, realmID string) (int, error) {
+	settings, err := projectors.GetRealmSettings(ctx, m.projectionStore, realmID)
+	if err != nil {
+		return 0, err
+	}
+	thresholds := domain.SLAThresholdsFromSettings(settings)
+	if len(thresholds) == 0 {
+		return 0, nil
+	}
+
+	raws, err := m.projectionStore.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return 0, err
+	}
+	recorded := 0
+	now := m.now()
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.ID == "" || !summary.SLABreachedAt.IsZero() {
+			continue
+		}
+		threshold, ok := thresholds[summary.Status]
+		if !ok {
+			continue
+		}
+		enteredAt, err := m.enteredAt(ctx, realmID, summary)
+		if err != nil {
+			return recorded, err
+		}
+		if enteredAt.IsZero() || now.Sub(enteredAt) < threshold {
+			continue
+		}
+		cmd := domain.RecordSLABreach{
+			ID:        summary.ID,
+			Status:    summary.Status,
+			Threshold: settings[domain.SLASettingPrefix+summary.Status],
+			EnteredAt: enteredAt,
+		}
+		if err := domain.HandleRecordSLABreach(ctx, realmID, cmd, m.eventStore); err != nil {
+			log.Printf("sla: rune %s in realm %s: %v", summary.ID, realmID, err)
+			continue
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// enteredAt returns when the rune entered its current status, or zero if
+// rune_transitions has not caught up with it yet.
+func (m *SLAMonitor) enteredAt(ctx context.Context, realmID string, summary projectors.RuneSummary) (time.Time, error) {
+	var entry projectors.RuneTransitions
+	err := m.projectionStore.Get(ctx, realmID, "rune_transitions", summary.ID, &entry)
+	var nfe *core.NotFoundError
+	if errors.As(err, &nfe) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(entry.Transitions) == 0 {
+		return time.Time{}, nil
+	}
+	last := entry.Transitions[len(entry.Transitions)-1]
+	if last.Status != summary.Status {
+		return time.Time{}, nil
+	}
+	return last.At, nil
+}
@@ -0,0 +1,224 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSLAMonitor(t *testing.T) {
+	t.Run("records a breach for a rune past its status threshold", func(t *testing.T) {
+		tc := newSLATestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{"sla.claimed": "7d"})
+		tc.a_rune_entered("realm-1", "bf-a1b2", "claimed", 8*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.recorded_count_is(1)
+		var data domain.SLABreached
+		require.NoError(t, json.Unmarshal(tc.only_appended(domain.EventSLABreached), &data))
+		assert.Equal(t, "bf-a1b2", data.ID)
+		assert.Equal(t, "claimed", data.Status)
+		assert.Equal(t, "7d", data.Threshold)
+	})
+
+	t.Run("leaves runes within the threshold alone", func(t *testing.T) {
+		tc := newSLATestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{"sla.claimed": "7d"})
+		tc.a_rune_entered("realm-1", "bf-a1b2", "claimed", 6*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.recorded_count_is(0)
+	})
+
+	t.Run("only checks statuses that have a threshold", func(t *testing.T) {
+		tc := newSLATestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{"sla.claimed": "7d"})
+		tc.a_rune_entered("realm-1", "bf-a1b2", "open", 30*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.recorded_count_is(0)
+	})
+
+	t.Run("skips runes already flagged as breached", func(t *testing.T) {
+		tc := newSLATestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "active", map[string]string{"sla.open": "36h"})
+		tc.a_rune_entered("realm-1", "bf-a1b2", "open", 48*time.Hour)
+		tc.rune_is_flagged("realm-1", "bf-a1b2")
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.recorded_count_is(0)
+	})
+
+	t.Run("skips suspended realms", func(t *testing.T) {
+		tc := newSLATestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "suspended", map[string]string{"sla.claimed": "7d"})
+		tc.a_rune_entered("realm-1", "bf-a1b2", "claimed", 8*24*time.Hour)
+
+		// When
+		tc.check_is_called()
+
+		// Then
+		tc.no_error()
+		tc.recorded_count_is(0)
+	})
+}
+
+// --- Test Context ---
+
+type slaTestContext struct {
+	t *testing.T
+
+	monitor    *SLAMonitor
+	eventStore *mockEventStore
+	store      *mockProjectionStore
+	now        time.Time
+	recorded   int
+	err        error
+}
+
+func newSLATestContext(t *testing.T) *slaTestContext {
+	t.Helper()
+	eventStore := &mockEventStore{streams: make(map[string][]core.Event)}
+	store := &mockProjectionStore{lists: make(map[string][]json.RawMessage), entries: make(map[string]any)}
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	monitor := NewSLAMonitor(eventStore, store)
+	monitor.now = func() time.Time { return now }
+	return &slaTestContext{
+		t:          t,
+		monitor:    monitor,
+		eventStore: eventStore,
+		store:      store,
+		now:        now,
+	}
+}
+
+// --- Given ---
+
+func (tc *slaTestContext) a_realm(realmID, status string, settings map[string]string) {
+	tc.t.Helper()
+	raw, err := json.Marshal(projectors.RealmListEntry{RealmID: realmID, Status: status})
+	require.NoError(tc.t, err)
+	tc.store.lists["_admin:realm_list"] = append(tc.store.lists["_admin:realm_list"], raw)
+	tc.store.entries["_admin:realm_settings:"+realmID] = projectors.RealmSettingsEntry{RealmID: realmID, Settings: settings}
+}
+
+// a_rune_entered puts a rune in status since age ago in every projection
+// and event stream the monitor reads.
+func (tc *slaTestContext) a_rune_entered(realmID, runeID, status string, age time.Duration) {
+	tc.t.Helper()
+	enteredAt := tc.now.Add(-age)
+	tc.put_summary(realmID, projectors.RuneSummary{ID: runeID, Status: status})
+	tc.store.entries[realmID+":rune_transitions:"+runeID] = projectors.RuneTransitions{
+		RuneID: runeID,
+		Transitions: []projectors.StatusTransition{
+			{Status: "draft", At: enteredAt.Add(-time.Hour)},
+			{Status: status, At: enteredAt},
+		},
+	}
+
+	events := []core.Event{tc.rune_event(runeID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Title: "Rune"})}
+	switch status {
+	case "open":
+		events = append(events, tc.rune_event(runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID}))
+	case "claimed":
+		events = append(events,
+			tc.rune_event(runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID}),
+			tc.rune_event(runeID, domain.EventRuneClaimed, domain.RuneClaimed{ID: runeID, Claimant: "odin"}),
+		)
+	}
+	tc.eventStore.streams["rune-"+runeID] = events
+}
+
+func (tc *slaTestContext) rune_is_flagged(realmID, runeID string) {
+	tc.t.Helper()
+	key := realmID + ":rune_list"
+	for i, raw := range tc.store.lists[key] {
+		var summary projectors.RuneSummary
+		require.NoError(tc.t, json.Unmarshal(raw, &summary))
+		if summary.ID == runeID {
+			summary.SLABreachedAt = tc.now
+			tc.store.lists[key] = append(tc.store.lists[key][:i], tc.store.lists[key][i+1:]...)
+			tc.put_summary(realmID, summary)
+			return
+		}
+	}
+}
+
+func (tc *slaTestContext) put_summary(realmID string, summary projectors.RuneSummary) {
+	tc.t.Helper()
+	raw, err := json.Marshal(summary)
+	require.NoError(tc.t, err)
+	tc.store.lists[realmID+":rune_list"] = append(tc.store.lists[realmID+":rune_list"], raw)
+}
+
+func (tc *slaTestContext) rune_event(runeID, eventType string, data any) core.Event {
+	tc.t.Helper()
+	raw, err := json.Marshal(data)
+	require.NoError(tc.t, err)
+	return core.Event{RealmID: "realm-1", StreamID: "rune-" + runeID, EventType: eventType, Data: raw}
+}
+
+// --- When ---
+
+func (tc *slaTestContext) check_is_called() {
+	tc.t.Helper()
+	tc.recorded, tc.err = tc.monitor.Check(context.Background())
+}
+
+// --- Then ---
+
+func (tc *slaTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *slaTestContext) recorded_count_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorded)
+	assert.Len(tc.t, tc.eventStore.appended, expected)
+}
+
+func (tc *slaTestContext) only_appended(eventType string) []byte {
+	tc.t.Helper()
+	require.Len(tc.t, tc.eventStore.appended, 1)
+	appended := tc.eventStore.appended[0]
+	assert.Equal(tc.t, eventType, appended.EventType)
+	data, err := json.Marshal(appended.Data)
+	require.NoError(tc.t, err)
+	return data
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+func (h *Handlers) AddAutomationRule(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.AddAutomationRule
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	result, err := domain.HandleAddAutomationRule(r.Context(), cmd, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (h *Handlers) RemoveAutomationRule(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.RemoveAutomationRule
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	if err := domain.HandleRemoveAutomationRule(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAutomationRules returns the realm's rules ordered by name, the order
+// in which they run.
+func (h *Handlers) ListAutomationRules(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "automation_rules")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list automation rules")
+		return
+	}
+	rules := []projectors.AutomationRuleEntry{}
+	for _, raw := range raws {
+		var rule projectors.AutomationRuleEntry
+		if json.Unmarshal(raw, &rule) == nil && rule.ID != "" {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	writeJSON(w, http.StatusOK, rules)
+}
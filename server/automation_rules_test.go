@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAddAutomationRuleHandler(t *testing.T) {
+	t.Run("adds rule to the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/add-automation-rule", domain.AddAutomationRule{
+			RealmID: "realm-other", Name: "Release note", Trigger: "fulfilled",
+			Action: domain.AutomationActionAddNote, Value: "Shipped {id}",
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.response_body_has_field("rule_id")
+		added := tc.only_added_automation_rule()
+		assert.Equal(t, "realm-1", added.RealmID)
+		assert.Equal(t, "Shipped {id}", added.Value)
+	})
+
+	t.Run("returns 400 for an invalid rule", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/add-automation-rule", domain.AddAutomationRule{
+			Name: "Labeled", Trigger: "labeled", Action: domain.AutomationActionAddNote, Value: "x",
+		})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("trigger must be one of")
+	})
+
+	t.Run("returns 403 without realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/add-automation-rule", domain.AddAutomationRule{})
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+	})
+}
+
+func TestRemoveAutomationRuleHandler(t *testing.T) {
+	t.Run("removes rule from the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.automation_rule_exists_in_event_store("ar-1", "realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-automation-rule", map[string]string{"rule_id": "ar-1"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("returns 404 for a rule in another realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.automation_rule_exists_in_event_store("ar-1", "realm-2")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-automation-rule", map[string]string{"rule_id": "ar-1"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestListAutomationRulesHandler(t *testing.T) {
+	t.Run("lists the realm's rules by name", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.projection_has_automation_rule("realm-1", "ar-2", "Zeta")
+		tc.projection_has_automation_rule("realm-1", "ar-1", "Alpha")
+		tc.projection_has_automation_rule("realm-2", "ar-3", "Other realm")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/automation-rules")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var rules []projectors.AutomationRuleEntry
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &rules))
+		require.Len(t, rules, 2)
+		assert.Equal(t, "Alpha", rules[0].Name)
+		assert.Equal(t, "Zeta", rules[1].Name)
+	})
+
+	t.Run("returns empty array when realm has no rules", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/automation-rules")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_is_empty_json_array()
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) automation_rule_exists_in_event_store(ruleID, realmID string) {
+	tc.t.Helper()
+	added := domain.AutomationRuleAdded{
+		RuleID: ruleID, RealmID: realmID, Name: "Release note", Trigger: "fulfilled",
+		Action: domain.AutomationActionAddNote, Value: "done",
+	}
+	tc.eventStore.appendToStream(domain.AdminRealmID, "automation-rule-"+ruleID, domain.EventAutomationRuleAdded, added)
+}
+
+func (tc *handlerTestContext) projection_has_automation_rule(realmID, ruleID, name string) {
+	tc.t.Helper()
+	entry := projectors.AutomationRuleEntry{ID: ruleID, RealmID: realmID, Name: name, Trigger: "fulfilled"}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "automation_rules", ruleID, entry)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) only_added_automation_rule() domain.AutomationRuleAdded {
+	tc.t.Helper()
+	var found []domain.AutomationRuleAdded
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType != domain.EventAutomationRuleAdded {
+				continue
+			}
+			var added domain.AutomationRuleAdded
+			require.NoError(tc.t, json.Unmarshal(evt.Data, &added))
+			found = append(found, added)
+		}
+	}
+	require.Len(tc.t, found, 1)
+	return found[0]
+}
@@ -0,0 +1,414 @@
+// Package backup writes scheduled, encrypted archives of the event store to
+// a local directory or an object store, keeping the most recent few.
+//
+// An archive holds every realm's events as gzipped newline-delimited JSON,
+// one event per line in realm and then global position order, encrypted
+// with AES-256-GCM (see NewEncryptWriter). Open reads one back.
+//
+// A scheduler made WithProjections also writes a projections archive
+// beside each events archive (see ProjectionsArchiveName), holding every
+// realm's projector checkpoints and projection rows as ProjectionRecords in
+// the same format. It is written first, so its checkpoints never pass the
+// events in the events archive and replaying from them brings the rows up
+// to date.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/server/metrics"
+)
+
+const (
+	namePrefix        = "bifrost-"
+	nameSuffix        = ".bak"
+	projectionsSuffix = ".projections" + nameSuffix
+	timeLayout        = "20060102T150405Z"
+)
+
+// ArchiveName returns the name of an archive taken at t. Names sort in the
+// order the archives were taken.
+func ArchiveName(t time.Time) string {
+	return namePrefix + t.UTC().Format(timeLayout) + nameSuffix
+}
+
+// ProjectionsArchiveName returns the name of the projections archive taken
+// with the events archive name.
+func ProjectionsArchiveName(name string) string {
+	return strings.TrimSuffix(name, nameSuffix) + projectionsSuffix
+}
+
+// archiveTime returns when the archive name was taken.
+func archiveTime(name string) (time.Time, bool) {
+	if !isArchiveName(name) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeLayout, strings.TrimSuffix(strings.TrimPrefix(name, namePrefix), nameSuffix))
+	return t, err == nil
+}
+
+// isArchiveName reports whether name is an events archive. Projections
+// archives are listed and deleted with theirs.
+func isArchiveName(name string) bool {
+	return strings.HasPrefix(name, namePrefix) && strings.HasSuffix(name, nameSuffix) &&
+		!strings.HasSuffix(name, projectionsSuffix)
+}
+
+// Event is an event as stored in an archive, in the format of a realm
+// export.
+type Event = core.ExportedEvent
+
+// WriteArchive writes every realm's events to w, gzipped, and returns how
+// many it wrote. Each realm is read up to the events it holds when its turn
+// comes, so events appended during the backup may or may not be included.
+func WriteArchive(ctx context.Context, events core.EventStore, w io.Writer) (int, error) {
+	realmIDs, err := events.ListRealmIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list realms: %w", err)
+	}
+	zw := gzip.NewWriter(w)
+	count := 0
+	for _, realmID := range realmIDs {
+		n, err := core.ExportRealm(ctx, events, realmID, zw)
+		count += n
+		if err != nil {
+			return count, fmt.Errorf("back up realm %s: %w", realmID, err)
+		}
+	}
+	return count, zw.Close()
+}
+
+// ProjectionRecord is a line of a projections archive, in the format of a
+// projection snapshot.
+type ProjectionRecord = core.ProjectionRecord
+
+// WriteProjections writes the checkpoints and then the projection rows of
+// each realm to w, gzipped, and returns how many rows it wrote. Reading the
+// checkpoints first means rows may be newer than them, never older.
+func WriteProjections(ctx context.Context, realmIDs []string, projections core.ProjectionDumper, checkpoints core.CheckpointLister, w io.Writer) (int, error) {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	count := 0
+	for _, realmID := range realmIDs {
+		positions, err := checkpoints.ListCheckpoints(ctx, realmID)
+		if err != nil {
+			return count, fmt.Errorf("list checkpoints of realm %s: %w", realmID, err)
+		}
+		names := make([]string, 0, len(positions))
+		for name := range positions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := enc.Encode(ProjectionRecord{RealmID: realmID, Projector: name, Checkpoint: positions[name]}); err != nil {
+				return count, err
+			}
+		}
+		err = projections.DumpProjections(ctx, realmID, func(row core.ProjectionRow) error {
+			count++
+			return enc.Encode(ProjectionRecord{RealmID: realmID, Projection: row.ProjectionName, Key: row.Key, Value: row.Value})
+		})
+		if err != nil {
+			return count, fmt.Errorf("back up projections of realm %s: %w", realmID, err)
+		}
+	}
+	return count, zw.Close()
+}
+
+// Open returns a reader of the events in an archive encrypted with key, one
+// JSON-encoded Event per line, or of the ProjectionRecords in a projections
+// archive.
+func Open(r io.Reader, key []byte) (io.Reader, error) {
+	plain, err := NewDecryptReader(r, key)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bufio.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("read backup archive: %w", err)
+	}
+	return zr, nil
+}
+
+// Status describes the scheduler's most recent backups.
+type Status struct {
+	Destination    string    `json:"destination"`
+	Interval       string    `json:"interval"`
+	Keep           int       `json:"keep"`
+	LastAttemptAt  time.Time `json:"last_attempt_at,omitzero"`
+	LastSuccessAt  time.Time `json:"last_success_at,omitzero"`
+	LastArchive    string    `json:"last_archive,omitempty"`
+	LastSizeBytes  int64     `json:"last_size_bytes,omitempty"`
+	LastEventCount int       `json:"last_event_count,omitempty"`
+	LastRowCount   int       `json:"last_projection_row_count,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	Archives       []string  `json:"archives"`
+}
+
+// Scheduler takes a backup every interval and deletes all but the most
+// recent archives.
+type Scheduler struct {
+	events core.EventStore
+	dest   Destination
+	key    []byte
+	keep   int
+	now    func() time.Time
+
+	projections core.ProjectionDumper
+	checkpoints core.CheckpointLister
+
+	mu       sync.Mutex
+	status   Status
+	outcomes *metrics.CounterVec
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithProjections also backs up the projection rows and checkpoints of
+// every realm, into a projections archive beside each events archive.
+func WithProjections(projections core.ProjectionDumper, checkpoints core.CheckpointLister) SchedulerOption {
+	return func(s *Scheduler) {
+		s.projections = projections
+		s.checkpoints = checkpoints
+	}
+}
+
+// NewScheduler returns a scheduler backing up events to dest, encrypted
+// with key and keeping the keep most recent archives.
+func NewScheduler(events core.EventStore, dest Destination, key []byte, keep int, opts ...SchedulerOption) (*Scheduler, error) {
+	if _, err := newGCM(key); err != nil {
+		return nil, err
+	}
+	if keep < 1 {
+		return nil, fmt.Errorf("backups kept must be at least 1")
+	}
+	s := &Scheduler{
+		events: events,
+		dest:   dest,
+		key:    key,
+		keep:   keep,
+		now:    time.Now,
+		status: Status{Destination: dest.String(), Keep: keep, Archives: []string{}},
+		outcomes: metrics.NewCounterVec("bifrost_backups_total",
+			"Backups attempted since the process started, by outcome (success or error).", "outcome"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Run takes a backup whenever the most recent archive is interval old,
+// until ctx is cancelled. Restarting the server does not take an extra
+// backup.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	s.mu.Lock()
+	s.status.Interval = interval.String()
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(min(interval, time.Minute))
+	defer ticker.Stop()
+	for {
+		if due, err := s.due(ctx, interval); err != nil {
+			log.Printf("backup: %v", err)
+		} else if due {
+			if err := s.Backup(ctx); err != nil {
+				log.Printf("backup: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// due reports whether the newest archive in the destination is at least
+// interval old.
+func (s *Scheduler) due(ctx context.Context, interval time.Duration) (bool, error) {
+	s.mu.Lock()
+	last := s.status.LastAttemptAt
+	s.mu.Unlock()
+	if !last.IsZero() {
+		return s.now().Sub(last) >= interval, nil
+	}
+	names, err := sortedArchives(ctx, s.dest)
+	if err != nil {
+		return false, fmt.Errorf("list archives: %w", err)
+	}
+	s.mu.Lock()
+	s.status.Archives = names
+	s.mu.Unlock()
+	if len(names) == 0 {
+		return true, nil
+	}
+	taken, ok := archiveTime(names[len(names)-1])
+	return !ok || s.now().Sub(taken) >= interval, nil
+}
+
+// Backup takes a backup now and deletes the archives beyond the most
+// recent ones kept.
+func (s *Scheduler) Backup(ctx context.Context) error {
+	started := s.now()
+	s.mu.Lock()
+	s.status.LastAttemptAt = started
+	s.mu.Unlock()
+
+	name := ArchiveName(started)
+	rows, err := s.writeProjections(ctx, name)
+	if err != nil {
+		s.fail(err)
+		return err
+	}
+	size, count, err := s.write(ctx, name, func(w io.Writer) (int, error) {
+		return WriteArchive(ctx, s.events, w)
+	})
+	if err != nil {
+		if s.projections != nil {
+			_ = s.dest.Delete(ctx, ProjectionsArchiveName(name))
+		}
+		s.fail(err)
+		return err
+	}
+	names, err := s.rotate(ctx)
+	if err != nil {
+		s.fail(err)
+		return err
+	}
+
+	s.outcomes.Inc("success")
+	s.mu.Lock()
+	s.status.LastSuccessAt = started
+	s.status.LastArchive = name
+	s.status.LastSizeBytes = size
+	s.status.LastEventCount = count
+	s.status.LastRowCount = rows
+	s.status.LastError = ""
+	s.status.Archives = names
+	s.mu.Unlock()
+	return nil
+}
+
+// writeProjections stores the projections archive taken with the events
+// archive name, when projections are backed up, and returns how many rows
+// it holds.
+func (s *Scheduler) writeProjections(ctx context.Context, name string) (int, error) {
+	if s.projections == nil {
+		return 0, nil
+	}
+	realmIDs, err := s.events.ListRealmIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list realms: %w", err)
+	}
+	_, rows, err := s.write(ctx, ProjectionsArchiveName(name), func(w io.Writer) (int, error) {
+		return WriteProjections(ctx, realmIDs, s.projections, s.checkpoints, w)
+	})
+	return rows, err
+}
+
+// write encrypts an archive written by fill into a temporary file first,
+// since object stores need its size before the upload starts.
+func (s *Scheduler) write(ctx context.Context, name string, fill func(io.Writer) (int, error)) (int64, int, error) {
+	tmp, err := os.CreateTemp("", "bifrost-backup-*")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	enc, err := NewEncryptWriter(tmp, s.key)
+	if err != nil {
+		return 0, 0, err
+	}
+	count, err := fill(enc)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := enc.Close(); err != nil {
+		return 0, 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	if err := s.dest.Put(ctx, name, tmp, size); err != nil {
+		return 0, 0, fmt.Errorf("store %s: %w", name, err)
+	}
+	return size, count, nil
+}
+
+// rotate deletes all but the most recent archives, with their projections
+// archives, and returns those left.
+func (s *Scheduler) rotate(ctx context.Context) ([]string, error) {
+	names, err := sortedArchives(ctx, s.dest)
+	if err != nil {
+		return nil, fmt.Errorf("list archives: %w", err)
+	}
+	for len(names) > s.keep {
+		for _, name := range []string{ProjectionsArchiveName(names[0]), names[0]} {
+			if err := s.dest.Delete(ctx, name); err != nil {
+				return nil, fmt.Errorf("delete %s: %w", name, err)
+			}
+		}
+		names = names[1:]
+	}
+	return names, nil
+}
+
+func (s *Scheduler) fail(err error) {
+	s.outcomes.Inc("error")
+	s.mu.Lock()
+	s.status.LastError = err.Error()
+	s.mu.Unlock()
+}
+
+// Status returns the outcome of the most recent backups.
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Archives = append([]string(nil), s.status.Archives...)
+	return status
+}
+
+// Collect reports backup outcomes and the time and size of the last
+// successful backup.
+func (s *Scheduler) Collect(ctx context.Context) ([]metrics.Family, error) {
+	families, err := s.outcomes.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := s.Status()
+	if status.LastSuccessAt.IsZero() {
+		return families, nil
+	}
+	return append(families,
+		metrics.Family{
+			Name: "bifrost_backup_last_success_timestamp_seconds", Type: metrics.TypeGauge,
+			Help:    "Unix time the last successful backup was started.",
+			Samples: []metrics.Sample{{Value: float64(status.LastSuccessAt.Unix())}},
+		},
+		metrics.Family{
+			Name: "bifrost_backup_last_size_bytes", Type: metrics.TypeGauge,
+			Help:    "Size of the last successful backup archive.",
+			Samples: []metrics.Sample{{Value: float64(status.LastSizeBytes)}},
+		},
+	), nil
+}
@@ -0,0 +1,346 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestScheduler(t *testing.T) {
+	t.Run("writes an encrypted archive of every realm's events", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.event_in("realm-a", "rune-1", `{"title":"Bridge"}`)
+		tc.event_in("realm-b", "rune-2", `{"title":"Gate"}`)
+
+		// When
+		tc.backup_at(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+
+		// Then
+		tc.no_error()
+		tc.archives_are("bifrost-20261015T120000Z.bak")
+		events := tc.archived_events("bifrost-20261015T120000Z.bak")
+		require.Len(t, events, 2)
+		assert.Equal(t, "realm-a", events[0].RealmID)
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(events[0].Data))
+		assert.Equal(t, "realm-b", events[1].RealmID)
+		assert.Equal(t, 2, tc.scheduler.Status().LastEventCount)
+	})
+
+	t.Run("reads stores that read in batches one batch after another", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.store_reads_in_batches()
+		for range core.ExportBatchSize + 5 {
+			tc.event_in("realm-a", "rune-1", `{}`)
+		}
+
+		// When
+		tc.backup_at(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+
+		// Then
+		tc.no_error()
+		events := tc.archived_events("bifrost-20261015T120000Z.bak")
+		require.Len(t, events, core.ExportBatchSize+5)
+		for i, evt := range events {
+			assert.Equal(t, int64(i+1), evt.GlobalPosition)
+		}
+	})
+
+	t.Run("keeps only the most recent archives", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.event_in("realm-a", "rune-1", `{}`)
+		start := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+		// When
+		for i := range 4 {
+			tc.backup_at(start.Add(time.Duration(i) * time.Hour))
+		}
+
+		// Then
+		tc.no_error()
+		tc.archives_are("bifrost-20261015T020000Z.bak", "bifrost-20261015T030000Z.bak")
+		assert.Equal(t, []string{"bifrost-20261015T020000Z.bak", "bifrost-20261015T030000Z.bak"}, tc.scheduler.Status().Archives)
+	})
+
+	t.Run("writes a projections archive beside the events archive", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.projections_are_backed_up()
+		tc.event_in("realm-a", "rune-1", `{}`)
+		tc.projection_row("realm-a", "rune_list", "bf-1", `{"title":"Bridge"}`)
+		tc.checkpoint("realm-a", "rune_list", 1)
+
+		// When
+		tc.backup_at(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+
+		// Then
+		tc.no_error()
+		tc.archives_are("bifrost-20261015T120000Z.bak", "bifrost-20261015T120000Z.projections.bak")
+		records := tc.archived_projections("bifrost-20261015T120000Z.projections.bak")
+		require.Len(t, records, 2)
+		assert.Equal(t, ProjectionRecord{RealmID: "realm-a", Projector: "rune_list", Checkpoint: 1}, records[0])
+		assert.Equal(t, "rune_list", records[1].Projection)
+		assert.Equal(t, "bf-1", records[1].Key)
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(records[1].Value))
+		status := tc.scheduler.Status()
+		assert.Equal(t, 1, status.LastRowCount)
+		assert.Equal(t, []string{"bifrost-20261015T120000Z.bak"}, status.Archives)
+	})
+
+	t.Run("deletes projections archives with their events archives", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.projections_are_backed_up()
+		tc.event_in("realm-a", "rune-1", `{}`)
+		start := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+		// When
+		for i := range 3 {
+			tc.backup_at(start.Add(time.Duration(i) * time.Hour))
+		}
+
+		// Then
+		tc.no_error()
+		tc.archives_are(
+			"bifrost-20261015T010000Z.bak", "bifrost-20261015T010000Z.projections.bak",
+			"bifrost-20261015T020000Z.bak", "bifrost-20261015T020000Z.projections.bak",
+		)
+	})
+
+	t.Run("is due when the newest archive is an interval old", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.archive_exists("bifrost-20261015T000000Z.bak")
+		tc.now_is(time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC))
+
+		// Then
+		tc.is_due(24*time.Hour, false)
+		tc.now_is(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+		tc.is_due(24*time.Hour, true)
+	})
+
+	t.Run("records failures in the status", func(t *testing.T) {
+		tc := newSchedulerTestContext(t)
+
+		// Given
+		tc.events.listErr = assert.AnError
+
+		// When
+		tc.backup_at(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+
+		// Then
+		assert.ErrorIs(t, tc.err, assert.AnError)
+		status := tc.scheduler.Status()
+		assert.Contains(t, status.LastError, "list realms")
+		assert.True(t, status.LastSuccessAt.IsZero())
+		tc.archives_are()
+	})
+}
+
+// --- Test Context ---
+
+type schedulerTestContext struct {
+	t *testing.T
+
+	dir         string
+	key         []byte
+	events      *fakeEventStore
+	projections *memory.ProjectionStore
+	checkpoints *memory.CheckpointStore
+	scheduler   *Scheduler
+	err         error
+}
+
+func newSchedulerTestContext(t *testing.T) *schedulerTestContext {
+	t.Helper()
+	dir := t.TempDir()
+	dest, err := NewDirDestination(dir)
+	require.NoError(t, err)
+	key := make([]byte, KeySize)
+	events := &fakeEventStore{}
+	scheduler, err := NewScheduler(events, dest, key, 2)
+	require.NoError(t, err)
+	return &schedulerTestContext{t: t, dir: dir, key: key, events: events, scheduler: scheduler}
+}
+
+// --- Given ---
+
+func (tc *schedulerTestContext) event_in(realmID, streamID, data string) {
+	tc.t.Helper()
+	tc.events.events = append(tc.events.events, core.Event{
+		RealmID:        realmID,
+		StreamID:       streamID,
+		Version:        0,
+		GlobalPosition: int64(len(tc.events.events) + 1),
+		EventType:      "RuneCreated",
+		Data:           []byte(data),
+	})
+}
+
+func (tc *schedulerTestContext) store_reads_in_batches() {
+	tc.t.Helper()
+	tc.scheduler.events = &batchingEventStore{tc.events}
+}
+
+func (tc *schedulerTestContext) projections_are_backed_up() {
+	tc.t.Helper()
+	tc.projections = memory.NewProjectionStore()
+	tc.checkpoints = memory.NewCheckpointStore()
+	WithProjections(tc.projections, tc.checkpoints)(tc.scheduler)
+}
+
+func (tc *schedulerTestContext) projection_row(realmID, projection, key, value string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.projections.Put(context.Background(), realmID, projection, key, json.RawMessage(value)))
+}
+
+func (tc *schedulerTestContext) checkpoint(realmID, projector string, pos int64) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.checkpoints.SetCheckpoint(context.Background(), realmID, projector, pos))
+}
+
+func (tc *schedulerTestContext) archive_exists(name string) {
+	tc.t.Helper()
+	require.NoError(tc.t, os.WriteFile(filepath.Join(tc.dir, name), nil, 0o600))
+}
+
+func (tc *schedulerTestContext) now_is(t time.Time) {
+	tc.scheduler.now = func() time.Time { return t }
+}
+
+// --- When ---
+
+func (tc *schedulerTestContext) backup_at(t time.Time) {
+	tc.t.Helper()
+	tc.now_is(t)
+	tc.err = tc.scheduler.Backup(context.Background())
+}
+
+// --- Then ---
+
+func (tc *schedulerTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *schedulerTestContext) archives_are(expected ...string) {
+	tc.t.Helper()
+	entries, err := os.ReadDir(tc.dir)
+	require.NoError(tc.t, err)
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if expected == nil {
+		expected = []string{}
+	}
+	assert.Equal(tc.t, expected, names)
+}
+
+func (tc *schedulerTestContext) archived_events(name string) []Event {
+	tc.t.Helper()
+	f, err := os.Open(filepath.Join(tc.dir, name))
+	require.NoError(tc.t, err)
+	defer f.Close()
+	r, err := Open(f, tc.key)
+	require.NoError(tc.t, err)
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var evt Event
+		require.NoError(tc.t, json.Unmarshal(scanner.Bytes(), &evt))
+		events = append(events, evt)
+	}
+	require.NoError(tc.t, scanner.Err())
+	return events
+}
+
+func (tc *schedulerTestContext) archived_projections(name string) []ProjectionRecord {
+	tc.t.Helper()
+	f, err := os.Open(filepath.Join(tc.dir, name))
+	require.NoError(tc.t, err)
+	defer f.Close()
+	r, err := Open(f, tc.key)
+	require.NoError(tc.t, err)
+	var records []ProjectionRecord
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var record ProjectionRecord
+		require.NoError(tc.t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(tc.t, scanner.Err())
+	return records
+}
+
+func (tc *schedulerTestContext) is_due(interval time.Duration, expected bool) {
+	tc.t.Helper()
+	due, err := tc.scheduler.due(context.Background(), interval)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, due)
+}
+
+// --- Fake Event Store ---
+
+type fakeEventStore struct {
+	core.EventStore
+	events  []core.Event
+	listErr error
+}
+
+func (s *fakeEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	var ids []string
+	seen := map[string]bool{}
+	for _, evt := range s.events {
+		if !seen[evt.RealmID] {
+			seen[evt.RealmID] = true
+			ids = append(ids, evt.RealmID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *fakeEventStore) ReadAll(_ context.Context, realmID string, from int64) ([]core.Event, error) {
+	var events []core.Event
+	for _, evt := range s.events {
+		if evt.RealmID == realmID && evt.GlobalPosition > from {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+// batchingEventStore reads in batches, as the SQLite store does.
+type batchingEventStore struct {
+	*fakeEventStore
+}
+
+func (s *batchingEventStore) ReadAllBatch(ctx context.Context, realmID string, from int64, limit int) ([]core.Event, error) {
+	events, err := s.ReadAll(ctx, realmID, from)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, err
+}
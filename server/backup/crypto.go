@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Archives are encrypted with AES-256-GCM in chunks, so they can be written
+// and read as streams. An archive starts with a header of the magic bytes
+// and a random nonce prefix. Each chunk follows as a big-endian uint32, whose
+// top bit marks the final chunk and whose other bits are the sealed length,
+// and then the sealed chunk. A chunk's nonce is the prefix, the chunk's
+// index and its final flag, so chunks cannot be reordered, dropped or
+// appended without failing to open.
+const (
+	magic       = "BFBK\x01"
+	prefixSize  = 7
+	chunkSize   = 64 << 10
+	finalFlag   = uint32(1) << 31
+	maxSealSize = chunkSize + 16
+)
+
+// KeySize is the length of a backup encryption key.
+const KeySize = 32
+
+// ErrTruncated is returned when an archive ends before its final chunk.
+var ErrTruncated = errors.New("backup archive is truncated")
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("backup key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, index uint32, final bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], index)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	gcm    cipher.AEAD
+	prefix []byte
+	index  uint32
+	buf    []byte
+	err    error
+}
+
+// NewEncryptWriter returns a writer that encrypts what is written to it
+// with key and writes it to w. Close writes the final chunk; it does not
+// close w.
+func NewEncryptWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, magic); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(prefix); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, gcm: gcm, prefix: prefix, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n := len(p)
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data arrives, so the last
+		// chunk is always sealed by Close with the final flag
+		if len(e.buf) == chunkSize {
+			if e.err = e.seal(false); e.err != nil {
+				return 0, e.err
+			}
+		}
+		take := min(chunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = e.seal(true)
+	if e.err == nil {
+		e.err = errors.New("backup archive is closed")
+		return nil
+	}
+	return e.err
+}
+
+func (e *encryptWriter) seal(final bool) error {
+	sealed := e.gcm.Seal(nil, chunkNonce(e.prefix, e.index, final), e.buf, nil)
+	header := uint32(len(sealed))
+	if final {
+		header |= finalFlag
+	}
+	if err := binary.Write(e.w, binary.BigEndian, header); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.index++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r      io.Reader
+	gcm    cipher.AEAD
+	prefix []byte
+	index  uint32
+	plain  []byte
+	done   bool
+}
+
+// NewDecryptReader returns a reader of the plaintext of the archive read
+// from r, encrypted with key. Reads fail if the archive was modified or
+// truncated.
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(magic)+prefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("read backup header: %w", err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("not a bifrost backup archive")
+	}
+	return &decryptReader{r: r, gcm: gcm, prefix: header[len(magic):]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	var header uint32
+	if err := binary.Read(d.r, binary.BigEndian, &header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+	final := header&finalFlag != 0
+	size := header &^ finalFlag
+	if size > maxSealSize {
+		return errors.New("backup archive chunk is too large")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+	plain, err := d.gcm.Open(nil, chunkNonce(d.prefix, d.index, final), sealed, nil)
+	if err != nil {
+		return errors.New("backup archive is corrupt or the key is wrong")
+	}
+	d.index++
+	d.plain = plain
+	d.done = final
+	return nil
+}
@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestEncryption(t *testing.T) {
+	t.Run("round-trips data spanning several chunks", func(t *testing.T) {
+		tc := newCryptoTestContext(t)
+
+		// Given
+		tc.plaintext_of_size(3*chunkSize + 17)
+
+		// When
+		tc.encrypt()
+		tc.decrypt(tc.key)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, tc.plaintext, tc.decrypted)
+	})
+
+	t.Run("round-trips empty data", func(t *testing.T) {
+		tc := newCryptoTestContext(t)
+
+		// Given
+		tc.plaintext_of_size(0)
+
+		// When
+		tc.encrypt()
+		tc.decrypt(tc.key)
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.decrypted)
+	})
+
+	t.Run("fails on an archive cut at a chunk boundary", func(t *testing.T) {
+		tc := newCryptoTestContext(t)
+
+		// Given
+		tc.plaintext_of_size(2 * chunkSize)
+		tc.encrypt()
+
+		// When
+		tc.archive_is_cut_after_first_chunk()
+		tc.decrypt(tc.key)
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrTruncated)
+	})
+
+	t.Run("fails with the wrong key", func(t *testing.T) {
+		tc := newCryptoTestContext(t)
+
+		// Given
+		tc.plaintext_of_size(100)
+		tc.encrypt()
+
+		// When
+		tc.decrypt(bytes.Repeat([]byte{7}, KeySize))
+
+		// Then
+		assert.ErrorContains(t, tc.err, "corrupt or the key is wrong")
+	})
+
+	t.Run("rejects a key of the wrong size", func(t *testing.T) {
+		// When
+		_, err := NewEncryptWriter(io.Discard, []byte("short"))
+
+		// Then
+		assert.ErrorContains(t, err, "backup key must be 32 bytes")
+	})
+}
+
+// --- Test Context ---
+
+type cryptoTestContext struct {
+	t *testing.T
+
+	key       []byte
+	plaintext []byte
+	archive   []byte
+	decrypted []byte
+	err       error
+}
+
+func newCryptoTestContext(t *testing.T) *cryptoTestContext {
+	t.Helper()
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return &cryptoTestContext{t: t, key: key}
+}
+
+// --- Given ---
+
+func (tc *cryptoTestContext) plaintext_of_size(n int) {
+	tc.t.Helper()
+	tc.plaintext = make([]byte, n)
+	_, err := rand.Read(tc.plaintext)
+	require.NoError(tc.t, err)
+}
+
+func (tc *cryptoTestContext) archive_is_cut_after_first_chunk() {
+	tc.t.Helper()
+	tc.archive = tc.archive[:len(magic)+prefixSize+4+maxSealSize]
+}
+
+// --- When ---
+
+func (tc *cryptoTestContext) encrypt() {
+	tc.t.Helper()
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, tc.key)
+	require.NoError(tc.t, err)
+	_, err = w.Write(tc.plaintext)
+	require.NoError(tc.t, err)
+	require.NoError(tc.t, w.Close())
+	tc.archive = buf.Bytes()
+}
+
+func (tc *cryptoTestContext) decrypt(key []byte) {
+	tc.t.Helper()
+	r, err := NewDecryptReader(bytes.NewReader(tc.archive), key)
+	require.NoError(tc.t, err)
+	tc.decrypted, tc.err = io.ReadAll(r)
+}
+
+// --- Then ---
+
+func (tc *cryptoTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
@@ -0,0 +1,139 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Destination stores backup archives by name.
+type Destination interface {
+	// Put stores size bytes from body as the archive name.
+	Put(ctx context.Context, name string, body io.Reader, size int64) error
+	// List returns the names of the stored archives.
+	List(ctx context.Context) ([]string, error)
+	// Delete removes the archive name.
+	Delete(ctx context.Context, name string) error
+	// String describes the destination for status reports.
+	String() string
+}
+
+// DirDestination keeps archives in a local directory.
+type DirDestination struct {
+	dir string
+}
+
+// NewDirDestination creates dir if needed and returns a destination in it.
+func NewDirDestination(dir string) (*DirDestination, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create backup directory: %w", err)
+	}
+	return &DirDestination{dir: dir}, nil
+}
+
+// Put writes the archive to a temporary file and renames it into place, so
+// a partly written archive is never listed.
+func (d *DirDestination) Put(_ context.Context, name string, body io.Reader, _ int64) error {
+	tmp, err := os.CreateTemp(d.dir, ".tmp-"+name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.dir, name))
+}
+
+func (d *DirDestination) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && isArchiveName(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d *DirDestination) Delete(_ context.Context, name string) error {
+	if err := os.Remove(filepath.Join(d.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *DirDestination) String() string {
+	return d.dir
+}
+
+// ListingBlobStore is a blob store that can also list its keys, such as
+// blobstore.Store.
+type ListingBlobStore interface {
+	core.BlobStore
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// BlobDestination keeps archives in an object store under a key prefix.
+type BlobDestination struct {
+	store  ListingBlobStore
+	prefix string
+	name   string
+}
+
+// NewBlobDestination returns a destination storing archives in store with
+// keys starting with prefix. name describes the store in status reports,
+// e.g. "s3://bucket/backups/".
+func NewBlobDestination(store ListingBlobStore, prefix, name string) *BlobDestination {
+	return &BlobDestination{store: store, prefix: prefix, name: name}
+}
+
+func (b *BlobDestination) Put(ctx context.Context, name string, body io.Reader, size int64) error {
+	return b.store.Put(ctx, b.prefix+name, body, size, "application/octet-stream")
+}
+
+func (b *BlobDestination) List(ctx context.Context) ([]string, error) {
+	keys, err := b.store.List(ctx, b.prefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, b.prefix)
+		if isArchiveName(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (b *BlobDestination) Delete(ctx context.Context, name string) error {
+	return b.store.Delete(ctx, b.prefix+name)
+}
+
+func (b *BlobDestination) String() string {
+	return b.name
+}
+
+// sortedArchives returns the archive names in dest, oldest first.
+func sortedArchives(ctx context.Context, dest Destination) ([]string, error) {
+	names, err := dest.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,108 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// maxArchiveLine bounds the length of an event in an archive, as imports
+// bound theirs.
+const maxArchiveLine = 16 << 20
+
+// ErrStoreNotEmpty is returned by Restore for an event store that already
+// has events.
+var ErrStoreNotEmpty = errors.New("event store already has events")
+
+// Target is the point in history an archive is restored to. A zero field
+// does not limit the restore; with both set, an event must be within both.
+type Target struct {
+	Position int64     // Last global position restored
+	Time     time.Time // Events stored after it are left out
+}
+
+func (t Target) includes(evt Event) bool {
+	if t.Position > 0 && evt.GlobalPosition > t.Position {
+		return false
+	}
+	return t.Time.IsZero() || !evt.Timestamp.After(t.Time)
+}
+
+// RestoreResult counts what Restore appended.
+type RestoreResult struct {
+	Realms  []string `json:"realms"`
+	Events  int      `json:"events"`
+	Skipped int      `json:"skipped"` // events past the target
+}
+
+// Restore appends the events of an archive encrypted with key, up to
+// target, to events, which must be empty. Each realm is imported in turn
+// with core.ImportRealm, so events keep their streams, versions and
+// timestamps and are given new global positions.
+//
+// Global positions only grow within a stream, so the events of a stream up
+// to target always start at its first version and import cleanly. Realms
+// are appended as the archive is read, so one found truncated or modified
+// part way leaves the realms before the damage restored.
+func Restore(ctx context.Context, events core.EventStore, r io.Reader, key []byte, target Target) (RestoreResult, error) {
+	result := RestoreResult{Realms: []string{}}
+	existing, err := events.ListRealmIDs(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list realms: %w", err)
+	}
+	if len(existing) > 0 {
+		return result, ErrStoreNotEmpty
+	}
+	plain, err := Open(r, key)
+	if err != nil {
+		return result, err
+	}
+
+	var realmID string
+	var realm bytes.Buffer
+	flush := func() error {
+		if realm.Len() == 0 {
+			return nil
+		}
+		n, err := core.ImportRealm(ctx, events, realmID, &realm)
+		result.Events += n
+		if err != nil {
+			return fmt.Errorf("restore realm %s: %w", realmID, err)
+		}
+		result.Realms = append(result.Realms, realmID)
+		realm.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(plain)
+	scanner.Buffer(nil, maxArchiveLine)
+	for scanner.Scan() {
+		var evt Event
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			return result, fmt.Errorf("read backup archive: %w", err)
+		}
+		if !target.includes(evt) {
+			result.Skipped++
+			continue
+		}
+		if evt.RealmID != realmID {
+			if err := flush(); err != nil {
+				return result, err
+			}
+			realmID = evt.RealmID
+		}
+		realm.Write(scanner.Bytes())
+		realm.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("read backup archive: %w", err)
+	}
+	return result, flush()
+}
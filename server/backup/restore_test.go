@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRestore(t *testing.T) {
+	t.Run("appends every realm's events to an empty store", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.appended_at("realm-b", "rune-2", tc.start)
+		tc.appended_at("realm-a", "rune-1", tc.start.Add(time.Hour))
+		tc.archive_is_taken()
+
+		// When
+		tc.restore(Target{})
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, RestoreResult{Realms: []string{"realm-a", "realm-b"}, Events: 3}, tc.result)
+		tc.stream_has_versions("realm-a", "rune-1", 1, 2)
+		tc.stream_has_versions("realm-b", "rune-2", 1)
+	})
+
+	t.Run("leaves out events after the target position", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.appended_at("realm-b", "rune-2", tc.start)
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.archive_is_taken()
+
+		// When
+		tc.restore(Target{Position: 2})
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 2, tc.result.Events)
+		assert.Equal(t, 1, tc.result.Skipped)
+		tc.stream_has_versions("realm-a", "rune-1", 1)
+	})
+
+	t.Run("leaves out events stored after the target time", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.appended_at("realm-a", "rune-1", tc.start.Add(time.Hour))
+		tc.appended_at("realm-b", "rune-2", tc.start.Add(2*time.Hour))
+		tc.archive_is_taken()
+
+		// When
+		tc.restore(Target{Time: tc.start.Add(time.Hour)})
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{"realm-a"}, tc.result.Realms)
+		tc.stream_has_versions("realm-a", "rune-1", 1, 2)
+		tc.stream_has_versions("realm-b", "rune-2")
+	})
+
+	t.Run("keeps the stored timestamps", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.archive_is_taken()
+
+		// When
+		tc.restore(Target{})
+
+		// Then
+		tc.no_error()
+		events, err := tc.target.ReadStream(context.Background(), "realm-a", "rune-1", 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.True(t, tc.start.Equal(events[0].Timestamp))
+	})
+
+	t.Run("refuses a store that already has events", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.archive_is_taken()
+		tc.target = tc.source
+
+		// When
+		tc.restore(Target{})
+
+		// Then
+		assert.ErrorIs(t, tc.err, ErrStoreNotEmpty)
+	})
+
+	t.Run("returns error for the wrong key", func(t *testing.T) {
+		tc := newRestoreTestContext(t)
+
+		// Given
+		tc.appended_at("realm-a", "rune-1", tc.start)
+		tc.archive_is_taken()
+		tc.key = bytes.Repeat([]byte{1}, KeySize)
+
+		// When
+		tc.restore(Target{})
+
+		// Then
+		assert.Error(t, tc.err)
+		tc.stream_has_versions("realm-a", "rune-1")
+	})
+}
+
+// --- Test Context ---
+
+type restoreTestContext struct {
+	t *testing.T
+
+	start    time.Time
+	key      []byte
+	source   *memory.EventStore
+	target   *memory.EventStore
+	versions map[string]int
+	archive  []byte
+
+	result RestoreResult
+	err    error
+}
+
+func newRestoreTestContext(t *testing.T) *restoreTestContext {
+	t.Helper()
+	return &restoreTestContext{
+		t:        t,
+		start:    time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC),
+		key:      make([]byte, KeySize),
+		source:   memory.NewEventStore(),
+		target:   memory.NewEventStore(),
+		versions: make(map[string]int),
+	}
+}
+
+// --- Given ---
+
+func (tc *restoreTestContext) appended_at(realmID, streamID string, at time.Time) {
+	tc.t.Helper()
+	ctx := core.ContextWithClock(context.Background(), core.FixedClock(at))
+	version := tc.versions[realmID+"/"+streamID]
+	_, err := tc.source.Append(ctx, realmID, streamID, version, []core.EventData{
+		{EventType: "RuneUpdated", Data: map[string]int{"n": version}},
+	})
+	require.NoError(tc.t, err)
+	tc.versions[realmID+"/"+streamID]++
+}
+
+func (tc *restoreTestContext) archive_is_taken() {
+	tc.t.Helper()
+	var buf bytes.Buffer
+	enc, err := NewEncryptWriter(&buf, tc.key)
+	require.NoError(tc.t, err)
+	_, err = WriteArchive(context.Background(), tc.source, enc)
+	require.NoError(tc.t, err)
+	require.NoError(tc.t, enc.Close())
+	tc.archive = buf.Bytes()
+}
+
+// --- When ---
+
+func (tc *restoreTestContext) restore(target Target) {
+	tc.result, tc.err = Restore(context.Background(), tc.target, bytes.NewReader(tc.archive), tc.key, target)
+}
+
+// --- Then ---
+
+func (tc *restoreTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *restoreTestContext) stream_has_versions(realmID, streamID string, expected ...int) {
+	tc.t.Helper()
+	events, err := tc.target.ReadStream(context.Background(), realmID, streamID, 0)
+	require.NoError(tc.t, err)
+	got := []int{}
+	for _, evt := range events {
+		got = append(got, evt.Version)
+	}
+	if expected == nil {
+		expected = []int{}
+	}
+	assert.Equal(tc.t, expected, got)
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/devzeebo/bifrost/server/backup"
+)
+
+// BackupStatus reports the outcome of the scheduled event store backups.
+type BackupStatus interface {
+	Status() backup.Status
+}
+
+// WithBackupStatus reports the backups taken by b on the backup-status
+// endpoint.
+func WithBackupStatus(b BackupStatus) HandlersOption {
+	return func(h *Handlers) {
+		h.backups = b
+	}
+}
+
+type backupStatusResponse struct {
+	Enabled bool `json:"enabled"`
+	*backup.Status
+}
+
+// GetBackupStatus returns the most recent backups, or only enabled=false
+// when backups are not scheduled.
+func (h *Handlers) GetBackupStatus(w http.ResponseWriter, r *http.Request) {
+	if h.backups == nil {
+		writeJSON(w, http.StatusOK, backupStatusResponse{})
+		return
+	}
+	status := h.backups.Status()
+	writeJSON(w, http.StatusOK, backupStatusResponse{Enabled: true, Status: &status})
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/server/backup"
+)
+
+// --- Tests ---
+
+func TestGetBackupStatusHandler(t *testing.T) {
+	t.Run("reports backups as disabled when none are scheduled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.get("/backup-status")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"enabled":false}`)
+	})
+
+	t.Run("returns the status of the scheduled backups", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.backups_report(backup.Status{
+			Destination:   "/var/backups/bifrost",
+			Interval:      "24h0m0s",
+			Keep:          7,
+			LastSuccessAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC),
+			LastArchive:   "bifrost-20261015T120000Z.bak",
+			Archives:      []string{"bifrost-20261015T120000Z.bak"},
+		})
+		tc.handlers_configured()
+
+		// When
+		tc.get("/backup-status")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"enabled":true`)
+		tc.response_body_contains(`"destination":"/var/backups/bifrost"`)
+		tc.response_body_contains(`"last_success_at":"2026-10-15T12:00:00Z"`)
+		tc.response_body_contains(`"last_archive":"bifrost-20261015T120000Z.bak"`)
+	})
+}
+
+// --- Test Context ---
+
+type staticBackupStatus backup.Status
+
+func (s staticBackupStatus) Status() backup.Status {
+	return backup.Status(s)
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) backups_report(status backup.Status) {
+	tc.t.Helper()
+	tc.backups = staticBackupStatus(status)
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// The calendar feed publishes runes with due dates as all-day iCalendar
+// events. Calendar apps cannot send auth headers, so the feed is served at a
+// public URL carrying a per-account token that grants read access to nothing
+// else. Sagas are the closest thing Bifrost has to sprints, so a saga's due
+// date is published as the end of that sprint.
+
+// IssueCalendarToken issues a calendar feed token for the authenticated
+// account, replacing any previous one.
+func (h *Handlers) IssueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := AccountIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "account ID required")
+		return
+	}
+	result, err := domain.HandleIssueCalendarToken(r.Context(), domain.IssueCalendarToken{AccountID: accountID}, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"token": result.RawToken,
+		"url":   "/calendar/" + result.RawToken + ".ics",
+	})
+}
+
+// Calendar serves the iCalendar feed for the account that owns the token in
+// the URL, covering every realm the account can view.
+func (h *Handlers) Calendar(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(r.PathValue("token"), ".ics")
+	rawBytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || token == "" {
+		http.NotFound(w, r)
+		return
+	}
+	sum := sha256.Sum256(rawBytes)
+	account, err := projectors.GetCalendarAccount(r.Context(), h.projectionStore, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if err != nil || account.Status != "active" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var events []calendarEvent
+	for _, realmID := range account.Realms {
+		realmEvents, err := h.calendarEvents(r, realmID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to list runes")
+			return
+		}
+		events = append(events, realmEvents...)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].date != events[j].date {
+			return events[i].date < events[j].date
+		}
+		return events[i].uid < events[j].uid
+	})
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(renderCalendar("Bifrost ("+account.Username+")", events)))
+}
+
+type calendarEvent struct {
+	uid         string
+	date        string
+	summary     string
+	description string
+	status      string
+	updatedAt   time.Time
+}
+
+func (h *Handlers) calendarEvents(r *http.Request, realmID string) ([]calendarEvent, error) {
+	realmName := realmID
+	var realm projectors.RealmListEntry
+	if err := h.projectionStore.Get(r.Context(), domain.AdminRealmID, "realm_list", realmID, &realm); err == nil && realm.Name != "" {
+		realmName = realm.Name
+	}
+
+	raws, err := h.projectionStore.List(r.Context(), realmID, "rune_list")
+	if err != nil {
+		return nil, err
+	}
+	var events []calendarEvent
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.DueDate == "" {
+			continue
+		}
+		title := fmt.Sprintf("%s: %s", summary.ID, summary.Title)
+		if summary.Type == "saga" {
+			title = "Sprint ends: " + summary.Title
+		}
+		events = append(events, calendarEvent{
+			uid:         fmt.Sprintf("%s-%s@bifrost", realmID, summary.ID),
+			date:        summary.DueDate,
+			summary:     title,
+			description: fmt.Sprintf("Realm: %s\nRune: %s\nStatus: %s\nPriority: %d", realmName, summary.ID, summary.Status, summary.Priority),
+			status:      summary.Status,
+			updatedAt:   summary.UpdatedAt,
+		})
+	}
+	return events, nil
+}
+
+// renderCalendar formats events as an RFC 5545 calendar of all-day events.
+func renderCalendar(name string, events []calendarEvent) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICalLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Bifrost//Runes//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICalText(name))
+	for _, e := range events {
+		start, err := time.Parse(domain.DueDateLayout, e.date)
+		if err != nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + e.uid)
+		line("DTSTAMP:" + e.updatedAt.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + start.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICalText(e.summary))
+		line("DESCRIPTION:" + escapeICalText(e.description))
+		if e.status == "fulfilled" || e.status == "sealed" {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+// foldICalLine splits lines longer than 75 octets, continuing them on lines
+// that start with a space, without breaking UTF-8 sequences.
+func foldICalLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestIssueCalendarTokenHandler(t *testing.T) {
+	t.Run("issues a token and feed URL for the account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.account_exists_in_event_store("acct-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/calendar-token", nil)
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &body))
+		assert.NotEmpty(t, body["token"])
+		assert.Equal(t, "/calendar/"+body["token"]+".ics", body["url"])
+	})
+
+	t.Run("returns 403 without an account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/calendar-token", nil)
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+	})
+}
+
+func TestCalendarHandler(t *testing.T) {
+	t.Run("serves runes with due dates from the account's realms", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		token := tc.calendar_token_for("acct-1", "active", "realm-1")
+		tc.projection_has_realm_list()
+		tc.projection_has_due_rune("realm-1", projectors.RuneSummary{ID: "bf-0001", Title: "Fix, the bridge", Status: "open", DueDate: "2026-03-31"})
+		tc.projection_has_due_rune("realm-1", projectors.RuneSummary{ID: "bf-0002", Title: "Sprint 4", Status: "open", Type: "saga", DueDate: "2026-04-10"})
+		tc.projection_has_due_rune("realm-1", projectors.RuneSummary{ID: "bf-0003", Title: "No deadline", Status: "open"})
+
+		// When
+		tc.get("/calendar/" + token + ".ics")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, "text/calendar; charset=utf-8", tc.recorder.Header().Get("Content-Type"))
+		body := tc.recorder.Body.String()
+		assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+		assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+		assert.Contains(t, body, "UID:realm-1-bf-0001@bifrost\r\n")
+		assert.Contains(t, body, "DTSTART;VALUE=DATE:20260331\r\n")
+		assert.Contains(t, body, "DTEND;VALUE=DATE:20260401\r\n")
+		assert.Contains(t, body, `SUMMARY:bf-0001: Fix\, the bridge`)
+		assert.Contains(t, body, "SUMMARY:Sprint ends: Sprint 4\r\n")
+		assert.Contains(t, body, `Realm: Test Realm\nRune: bf-0001`)
+		assert.NotContains(t, body, "No deadline")
+	})
+
+	t.Run("returns 404 for unknown token", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.get("/calendar/AAAA.ics")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 404 for suspended account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		token := tc.calendar_token_for("acct-1", "suspended", "realm-1")
+
+		// When
+		tc.get("/calendar/" + token + ".ics")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestFoldICalLine(t *testing.T) {
+	t.Run("folds long lines at 75 octets", func(t *testing.T) {
+		folded := foldICalLine("SUMMARY:" + strings.Repeat("é", 60))
+
+		for _, part := range strings.Split(folded, "\r\n") {
+			assert.LessOrEqual(t, len(part), 75)
+		}
+		assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 60), strings.ReplaceAll(folded, "\r\n ", ""))
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) calendar_token_for(accountID, status string, realms ...string) string {
+	tc.t.Helper()
+	raw := []byte("calendar-token-" + accountID)
+	sum := sha256.Sum256(raw)
+	keyHash := base64.RawURLEncoding.EncodeToString(sum[:])
+	ctx := context.Background()
+	_ = tc.projectionStore.Put(ctx, domain.AdminRealmID, "account_lookup", "calendar:"+keyHash, accountID)
+	_ = tc.projectionStore.Put(ctx, domain.AdminRealmID, "account_lookup", "accountinfo:"+accountID, map[string]any{
+		"username": "alice", "status": status, "realms": realms,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (tc *handlerTestContext) projection_has_due_rune(realmID string, summary projectors.RuneSummary) {
+	tc.t.Helper()
+	summary.UpdatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", summary.ID, summary)
+}
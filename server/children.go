@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// RuneChildren serves GET /rune/{id}/children: the rune's direct children
+// with their status, claimant and priority, ordered by ID.
+func (h *Handlers) RuneChildren(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	if h.notModified(w, r,
+		projectionSource{realmID, "rune_list"},
+		projectionSource{realmID, "rune_children"},
+	) {
+		return
+	}
+
+	runeID := r.PathValue("id")
+	var summary projectors.RuneSummary
+	if err := h.projectionStore.Get(r.Context(), realmID, "rune_list", runeID, &summary); err != nil {
+		if isNotFound(err) {
+			writeError(w, http.StatusNotFound, "rune not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to list rune children")
+		return
+	}
+	children, err := projectors.GetRuneChildren(r.Context(), h.projectionStore, realmID, runeID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list rune children")
+		return
+	}
+	writeJSON(w, http.StatusOK, children)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRuneChildrenHandler(t *testing.T) {
+	t.Run("lists the rune's direct children", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.impact_rune("realm-1", "bf-a1", "open")
+		tc.rune_has_children("realm-1", "bf-a1",
+			projectors.RuneChild{ID: "bf-a1.1", Title: "First", Status: "claimed", Priority: 1, Claimant: "alice"},
+			projectors.RuneChild{ID: "bf-a1.2", Title: "Second", Status: "open", Priority: 3},
+		)
+
+		// When
+		tc.get("/rune/bf-a1/children")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		children := tc.rune_children()
+		require.Len(t, children, 2)
+		assert.Equal(t, "alice", children[0].Claimant)
+		assert.Equal(t, "open", children[1].Status)
+	})
+
+	t.Run("returns an empty list for a rune without children", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.impact_rune("realm-1", "bf-a1", "open")
+
+		// When
+		tc.get("/rune/bf-a1/children")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.JSONEq(t, `[]`, tc.recorder.Body.String())
+	})
+
+	t.Run("returns 404 for an unknown rune", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/rune/bf-missing/children")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) rune_has_children(realmID, runeID string, children ...projectors.RuneChild) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_children", runeID, children)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) rune_children() []projectors.RuneChild {
+	tc.t.Helper()
+	var children []projectors.RuneChild
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &children))
+	return children
+}
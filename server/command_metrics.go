@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/server/metrics"
+)
+
+// Command outcomes, from the status the command's handler answered with.
+const (
+	outcomeSuccess  = "success"
+	outcomeRejected = "rejected"
+	outcomeError    = "error"
+)
+
+// commandMetrics records how long each command takes and how it ends, by
+// command and realm.
+type commandMetrics struct {
+	durations *metrics.HistogramVec
+	outcomes  *metrics.CounterVec
+}
+
+func newCommandMetrics() *commandMetrics {
+	return &commandMetrics{
+		durations: metrics.NewHistogramVec("bifrost_command_duration_seconds",
+			"Time taken to handle commands, by command and realm.", metrics.DefaultBuckets, "command", "realm"),
+		outcomes: metrics.NewCounterVec("bifrost_commands_total",
+			"Commands handled, by command, realm and outcome (success, rejected or error).", "command", "realm", "outcome"),
+	}
+}
+
+// Collect reports the command histograms and counters since the process
+// started.
+func (h *Handlers) Collect(ctx context.Context) ([]metrics.Family, error) {
+	durations, err := h.commandMetrics.durations.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, err := h.commandMetrics.outcomes.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projections, err := h.collectProjectionStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(durations, outcomes, projections), nil
+}
+
+// measured times next and counts its outcome under the command named by the
+// last segment of the request path. Rune commands are measured inside
+// queueable, so a queued command is measured when a worker runs it rather
+// than when it is accepted.
+func (h *Handlers) measured(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		command := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		realmID, _ := RealmIDFromContext(r.Context())
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		started := time.Now()
+		next(rec, r)
+		h.commandMetrics.durations.Observe(time.Since(started).Seconds(), command, realmID)
+		h.commandMetrics.outcomes.Inc(command, realmID, commandOutcome(rec.status))
+	}
+}
+
+// commandOutcome classifies a command's response status. Client errors are
+// rejections: invalid commands, broken rules, missing runes and conflicts.
+func commandOutcome(status int) string {
+	switch {
+	case status >= 500:
+		return outcomeError
+	case status >= 400:
+		return outcomeRejected
+	default:
+		return outcomeSuccess
+	}
+}
+
+// statusRecorder remembers the status written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/server/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCommandMetrics(t *testing.T) {
+	t.Run("times a successful command by command and realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		assert.Equal(t, uint64(1), tc.handlers.commandMetrics.durations.Count("create-rune", "realm-1"))
+		tc.command_outcome_count_is("create-rune", "realm-1", outcomeSuccess, 1)
+	})
+
+	t.Run("counts a broken rule as a rejection", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.realm_exists("realm-1", "suspended")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.command_outcome_count_is("create-rune", "realm-1", outcomeRejected, 1)
+		tc.command_outcome_count_is("create-rune", "realm-1", outcomeSuccess, 0)
+	})
+
+	t.Run("measures realm administration commands", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/set-realm-setting", map[string]string{"key": "feature.unknown", "value": "true"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.command_outcome_count_is("set-realm-setting", "realm-1", outcomeRejected, 1)
+	})
+
+	t.Run("does not measure queries", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/runes")
+
+		// Then
+		tc.collected_command_metrics_do_not_contain(`command="runes"`)
+	})
+
+	t.Run("exports histograms and counters in the Prometheus format", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// When
+		out := tc.collected_command_metrics()
+
+		// Then
+		assert.Contains(t, out, `bifrost_command_duration_seconds_bucket{command="create-rune",realm="realm-1",le="+Inf"} 1`)
+		assert.Contains(t, out, `bifrost_command_duration_seconds_count{command="create-rune",realm="realm-1"} 1`)
+		assert.Contains(t, out, `bifrost_commands_total{command="create-rune",realm="realm-1",outcome="success"} 1`)
+	})
+}
+
+func TestCommandOutcome(t *testing.T) {
+	t.Run("classifies response statuses", func(t *testing.T) {
+		assert.Equal(t, outcomeSuccess, commandOutcome(http.StatusNoContent))
+		assert.Equal(t, outcomeRejected, commandOutcome(http.StatusConflict))
+		assert.Equal(t, outcomeRejected, commandOutcome(http.StatusNotFound))
+		assert.Equal(t, outcomeError, commandOutcome(http.StatusInternalServerError))
+	})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) command_outcome_count_is(command, realmID, outcome string, expected float64) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.handlers.commandMetrics.outcomes.Value(command, realmID, outcome))
+}
+
+func (tc *handlerTestContext) collected_command_metrics() string {
+	tc.t.Helper()
+	families, err := tc.handlers.Collect(context.Background())
+	require.NoError(tc.t, err)
+	var buf bytes.Buffer
+	require.NoError(tc.t, metrics.Write(&buf, families))
+	return buf.String()
+}
+
+func (tc *handlerTestContext) collected_command_metrics_do_not_contain(text string) {
+	tc.t.Helper()
+	assert.NotContains(tc.t, tc.collected_command_metrics(), text)
+}
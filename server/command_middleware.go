@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Command is a rune command on its way through the command middleware.
+type Command struct {
+	// Name is the domain command's type name, e.g. "CreateRune".
+	Name    string
+	RealmID string
+	// Actor is the account ID of the caller, empty when there is none.
+	Actor string
+	// Payload points at the decoded domain command, e.g. *domain.CreateRune.
+	// Middleware may change it before calling next. It is nil for commands
+	// without arguments, such as SweepRunes.
+	Payload any
+	// Events holds the events the command appended, once next has returned
+	// without an error.
+	Events []core.Event
+}
+
+// Position returns the highest global position among the events the command
+// appended, or zero when it appended none. Projections that have caught up
+// to it reflect the command.
+func (c *Command) Position() int64 {
+	var position int64
+	for _, event := range c.Events {
+		position = max(position, event.GlobalPosition)
+	}
+	return position
+}
+
+// CommandFunc runs a command.
+type CommandFunc func(ctx context.Context, cmd *Command) error
+
+// CommandMiddleware wraps the execution of rune commands. Code before next
+// runs before the command and can reject it by returning an error; a
+// *domain.RuleError is answered with 400, like the domain's own rules. Code
+// after next sees the command's outcome and events, after they have been
+// committed.
+type CommandMiddleware func(next CommandFunc) CommandFunc
+
+// WithCommandMiddleware adds middleware around every rune command, from the
+// HTTP API and MCP tools alike. The first middleware given is the outermost.
+func WithCommandMiddleware(mw ...CommandMiddleware) HandlersOption {
+	return func(h *Handlers) {
+		h.commandMiddleware = append(h.commandMiddleware, mw...)
+	}
+}
+
+// chainCommand wraps run in the configured middleware.
+func (h *Handlers) chainCommand(run CommandFunc) CommandFunc {
+	for i := len(h.commandMiddleware) - 1; i >= 0; i-- {
+		run = h.commandMiddleware[i](run)
+	}
+	return run
+}
+
+// recordingEventStore remembers the events appended through it. With an
+// idempotency key, each append is keyed by it, so that a retried request
+// appends nothing its first attempt appended. A command's later appends to
+// the same stream get the key with their count appended, as "<key>/1".
+type recordingEventStore struct {
+	core.EventStore
+	appended       []core.Event
+	idempotencyKey string
+	streamAppends  map[string]int
+}
+
+func (s *recordingEventStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	if s.idempotencyKey != "" && len(events) > 0 {
+		if s.streamAppends == nil {
+			s.streamAppends = make(map[string]int)
+		}
+		key := s.idempotencyKey
+		if n := s.streamAppends[streamID]; n > 0 {
+			key = fmt.Sprintf("%s/%d", key, n)
+		}
+		events = slices.Clone(events)
+		events[0].IdempotencyKey = key
+	}
+	appended, err := s.EventStore.Append(ctx, realmID, streamID, expectedVersion, events)
+	if err == nil && s.idempotencyKey != "" {
+		s.streamAppends[streamID]++
+	}
+	if err == nil {
+		s.appended = append(s.appended, appended...)
+	}
+	return appended, err
+}
+
+// ReadStreamPage lets commands read a window of a stream through the recorder.
+func (s *recordingEventStore) ReadStreamPage(ctx context.Context, realmID string, streamID string, from int, limit int, dir core.Direction) ([]core.Event, error) {
+	page, err := core.ReadStreamPage(ctx, s.EventStore, realmID, streamID, from, limit, dir)
+	return page.Events, err
+}
+
+// GetSnapshot lets commands load rune snapshots through the recorder, so a
+// rune truncated down to its last event still has its state.
+func (s *recordingEventStore) GetSnapshot(ctx context.Context, realmID string, streamID string) (core.Snapshot, error) {
+	if snapshots, ok := s.EventStore.(core.SnapshotStore); ok {
+		return snapshots.GetSnapshot(ctx, realmID, streamID)
+	}
+	return core.Snapshot{}, &core.NotFoundError{Entity: "snapshot", ID: streamID}
+}
+
+// SaveSnapshot lets commands snapshot long rune streams through the recorder.
+func (s *recordingEventStore) SaveSnapshot(ctx context.Context, snapshot core.Snapshot) error {
+	if snapshots, ok := s.EventStore.(core.SnapshotStore); ok {
+		return snapshots.SaveSnapshot(ctx, snapshot)
+	}
+	return nil
+}
+
+// RedactStream lets purge commands rewrite events through the recorder.
+func (s *recordingEventStore) RedactStream(ctx context.Context, realmID string, streamID string, redacted []core.Event) error {
+	redactor, ok := s.EventStore.(core.EventRedactor)
+	if !ok {
+		return errors.New("event store cannot redact events")
+	}
+	return redactor.RedactStream(ctx, realmID, streamID, redacted)
+}
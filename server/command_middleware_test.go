@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCommandMiddleware(t *testing.T) {
+	t.Run("sees the command before it runs and its events after", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		log := tc.a_command_log()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		require.Len(t, log.before, 1)
+		assert.Equal(t, "CreateRune", log.before[0].Name)
+		assert.Equal(t, "realm-1", log.before[0].RealmID)
+		assert.Equal(t, "acct-1", log.before[0].Actor)
+		assert.Equal(t, "Fix bug", log.before[0].Payload.(*domain.CreateRune).Title)
+		require.Len(t, log.after, 1)
+		require.Len(t, log.after[0].Events, 1)
+		assert.Equal(t, domain.EventRuneCreated, log.after[0].Events[0].EventType)
+		assert.Equal(t, log.after[0].Events[0].GlobalPosition, log.after[0].Position())
+	})
+
+	t.Run("rejects the command with 400 when middleware returns a rule error", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.command_middleware(func(next CommandFunc) CommandFunc {
+			return func(ctx context.Context, cmd *Command) error {
+				return domain.Rejectf(domain.ErrInvalidCommand, "runes are frozen")
+			}
+		})
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("runes are frozen")
+		tc.no_rune_events_appended("realm-1")
+	})
+
+	t.Run("runs the command as changed by middleware", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.command_middleware(func(next CommandFunc) CommandFunc {
+			return func(ctx context.Context, cmd *Command) error {
+				if create, ok := cmd.Payload.(*domain.CreateRune); ok {
+					create.Title = "[triage] " + create.Title
+				}
+				return next(ctx, cmd)
+			}
+		})
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.response_body_contains(`"title":"[triage] Fix bug"`)
+	})
+
+	t.Run("runs middleware in the order given", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		var order []string
+		for _, name := range []string{"outer", "inner"} {
+			tc.command_middleware(func(next CommandFunc) CommandFunc {
+				return func(ctx context.Context, cmd *Command) error {
+					order = append(order, name+":before")
+					err := next(ctx, cmd)
+					order = append(order, name+":after")
+					return err
+				}
+			})
+		}
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		assert.Equal(t, []string{"outer:before", "inner:before", "inner:after", "outer:after"}, order)
+	})
+
+	t.Run("reports the error and no events when the command fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		log := tc.a_command_log()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/fulfill-rune", domain.FulfillRune{ID: "bf-missing"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+		require.Len(t, log.after, 1)
+		assert.Error(t, log.errs[0])
+		assert.Empty(t, log.after[0].Events)
+		assert.Zero(t, log.after[0].Position())
+	})
+
+	t.Run("records events appended in the command's transaction", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		log := tc.a_command_log()
+		tc.a_command_executor()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		require.Len(t, log.after, 1)
+		assert.Len(t, log.after[0].Events, 1)
+	})
+}
+
+func TestRecordingEventStore(t *testing.T) {
+	t.Run("keys each append with the idempotency key", func(t *testing.T) {
+		// Given
+		inner := &keyCapturingStore{}
+		store := &recordingEventStore{EventStore: inner, idempotencyKey: "req-1"}
+		ctx := context.Background()
+
+		// When
+		for _, streamID := range []string{"rune-a", "rune-b", "rune-a"} {
+			_, err := store.Append(ctx, "realm-1", streamID, 0, []core.EventData{{EventType: "Noted"}, {EventType: "Noted"}})
+			require.NoError(t, err)
+		}
+
+		// Then
+		assert.Equal(t, []string{"rune-a:req-1", "rune-b:req-1", "rune-a:req-1/1"}, inner.keys)
+	})
+}
+
+// --- Given ---
+
+// commandLog records the commands a middleware saw before and after they
+// ran.
+type commandLog struct {
+	before []Command
+	after  []Command
+	errs   []error
+}
+
+func (tc *handlerTestContext) a_command_log() *commandLog {
+	tc.t.Helper()
+	log := &commandLog{}
+	tc.command_middleware(func(next CommandFunc) CommandFunc {
+		return func(ctx context.Context, cmd *Command) error {
+			log.before = append(log.before, *cmd)
+			err := next(ctx, cmd)
+			log.after = append(log.after, *cmd)
+			log.errs = append(log.errs, err)
+			return err
+		}
+	})
+	return log
+}
+
+func (tc *handlerTestContext) command_middleware(mw CommandMiddleware) {
+	tc.t.Helper()
+	tc.middleware = append(tc.middleware, mw)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) no_rune_events_appended(realmID string) {
+	tc.t.Helper()
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			assert.NotEqual(tc.t, realmID, evt.RealmID, "unexpected %s event", evt.EventType)
+		}
+	}
+}
+
+// keyCapturingStore records the stream and idempotency key of each append.
+type keyCapturingStore struct {
+	core.EventStore
+	keys []string
+}
+
+func (s *keyCapturingStore) Append(ctx context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	s.keys = append(s.keys, streamID+":"+events[0].IdempotencyKey)
+	return nil, nil
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// respondAsync is the Prefer token (RFC 7240) asking for a command to be
+// queued instead of run during the request.
+const respondAsync = "respond-async"
+
+// queuedHeaders are the request headers kept with a queued command, so it
+// runs the same way when a worker replays it.
+var queuedHeaders = []string{"Content-Type", "Idempotency-Key"}
+
+// WithCommandQueue lets clients queue rune commands by sending
+// "Prefer: respond-async". Once maxPending commands are waiting, further
+// queued requests are refused with 503 until workers catch up.
+func WithCommandQueue(q core.CommandQueue, maxPending int) HandlersOption {
+	return func(h *Handlers) {
+		h.commandQueue = q
+		h.maxQueuedCommands = maxPending
+	}
+}
+
+// queueable accepts the command into the queue and answers 202 when the
+// client prefers an asynchronous response, and runs it directly otherwise.
+func (h *Handlers) queueable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.commandQueue == nil || !prefersAsync(r) {
+			next(w, r)
+			return
+		}
+		realmID, ok := RealmIDFromContext(r.Context())
+		if !ok {
+			writeError(w, http.StatusForbidden, "realm ID required")
+			return
+		}
+		payload, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(payload) {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		pending, err := h.commandQueue.Pending(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if pending >= h.maxQueuedCommands {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusServiceUnavailable, "command queue is full")
+			return
+		}
+
+		id, err := generateCommandID()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		accountID, _ := AccountIDFromContext(r.Context())
+		role, _ := RoleFromContext(r.Context())
+		cmd := core.QueuedCommand{
+			ID:        id,
+			RealmID:   realmID,
+			AccountID: accountID,
+			Role:      role,
+			Command:   r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:],
+			Payload:   payload,
+			Headers:   keptHeaders(r.Header),
+		}
+		if err := h.commandQueue.Enqueue(r.Context(), cmd); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Location", "/api/command?id="+id)
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id, "status": core.CommandPending})
+	}
+}
+
+// GetCommand reports the status of a queued command, and its response once
+// it has run.
+func (h *Handlers) GetCommand(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "id query parameter is required")
+		return
+	}
+	if h.commandQueue == nil {
+		writeError(w, http.StatusNotFound, "command queue is not enabled")
+		return
+	}
+	cmd, err := h.commandQueue.GetCommand(r.Context(), id)
+	if err == nil && cmd.RealmID != realmID {
+		err = &core.NotFoundError{Entity: "command", ID: id}
+	}
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	resp := map[string]any{
+		"id":          cmd.ID,
+		"command":     cmd.Command,
+		"status":      cmd.Status,
+		"status_code": cmd.StatusCode,
+		"result":      cmd.Result,
+		"enqueued_at": cmd.EnqueuedAt,
+		"updated_at":  cmd.UpdatedAt,
+	}
+	if cmd.Position > 0 {
+		resp["position"] = cmd.Position
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// RunCommandWorker processes queued commands until ctx is cancelled, checking
+// for new ones every pollInterval once the queue is empty. A command already
+// taken from the queue runs to completion after ctx is cancelled, so it is
+// not left claimed and run again on restart.
+func (h *Handlers) RunCommandWorker(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			processed, err := h.ProcessNextCommand(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Printf("command queue: %v", err)
+			}
+			if !processed || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNextCommand runs the oldest pending command as the account that
+// queued it and records the response. It returns false when nothing was
+// pending.
+func (h *Handlers) ProcessNextCommand(ctx context.Context) (bool, error) {
+	cmd, ok, err := h.commandQueue.Claim(ctx)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	ctx = context.WithValue(ctx, realmIDKey, cmd.RealmID)
+	ctx = context.WithValue(ctx, accountIDKey, cmd.AccountID)
+	ctx = context.WithValue(ctx, roleKey, cmd.Role)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+cmd.Command, bytes.NewReader(cmd.Payload))
+	if err != nil {
+		return true, err
+	}
+	for name, value := range cmd.Headers {
+		req.Header.Set(name, value)
+	}
+	rec := &commandRecorder{header: http.Header{}, status: http.StatusOK}
+	h.mux.ServeHTTP(rec, req)
+
+	status := core.CommandSucceeded
+	if rec.status >= 400 {
+		status = core.CommandFailed
+	}
+	var result json.RawMessage
+	if body := bytes.TrimSpace(rec.body.Bytes()); json.Valid(body) {
+		result = body
+	}
+	position, _ := strconv.ParseInt(rec.header.Get("X-Bifrost-Position"), 10, 64)
+	return true, h.commandQueue.Complete(ctx, cmd.ID, status, rec.status, position, result)
+}
+
+func prefersAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), respondAsync) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func keptHeaders(header http.Header) map[string]string {
+	var kept map[string]string
+	for _, name := range queuedHeaders {
+		if value := header.Get(name); value != "" {
+			if kept == nil {
+				kept = map[string]string{}
+			}
+			kept[name] = value
+		}
+	}
+	return kept
+}
+
+func generateCommandID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "cmd-" + hex.EncodeToString(b), nil
+}
+
+// commandRecorder captures the response of a command run by a worker.
+type commandRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *commandRecorder) Header() http.Header { return c.header }
+
+func (c *commandRecorder) Write(b []byte) (int, error) { return c.body.Write(b) }
+
+func (c *commandRecorder) WriteHeader(status int) { c.status = status }
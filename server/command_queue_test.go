@@ -0,0 +1,383 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestQueuedCommands(t *testing.T) {
+	t.Run("queues a command when the client prefers an async response", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post_async("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusAccepted)
+		tc.response_body_has_field("id")
+		assert.Contains(t, tc.recorder.Header().Get("Location"), "/api/command?id=cmd-")
+		tc.queued_command_is(core.CommandPending, "create-rune")
+		tc.no_rune_was_written("realm-1")
+	})
+
+	t.Run("runs the command directly without the preference", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		assert.Empty(t, tc.commandQueue.commands)
+	})
+
+	t.Run("runs the command directly when no queue is configured", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post_async("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+	})
+
+	t.Run("refuses commands once the queue is full", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(1)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.post_async("/create-rune", domain.CreateRune{Title: "First", Priority: 1, Branch: strPtr("main")})
+		tc.recorder = httptest.NewRecorder()
+
+		// When
+		tc.post_async("/create-rune", domain.CreateRune{Title: "Second", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusServiceUnavailable)
+		assert.Equal(t, "1", tc.recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("returns 400 for an invalid body", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post_raw_async("/create-rune", []byte(`{invalid`))
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		assert.Empty(t, tc.commandQueue.commands)
+	})
+}
+
+func TestProcessNextCommand(t *testing.T) {
+	t.Run("runs a queued command and records its response", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.post_async("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// When
+		tc.next_command_is_processed()
+
+		// Then
+		cmd := tc.queued_command_is(core.CommandSucceeded, "create-rune")
+		assert.Equal(t, http.StatusCreated, cmd.StatusCode)
+		assert.Contains(t, string(cmd.Result), `"id"`)
+		assert.NotEmpty(t, tc.eventStore.streams)
+		assert.Equal(t, tc.eventStore.position, cmd.Position)
+	})
+
+	t.Run("records failed commands", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.post_async("/claim-rune", domain.ClaimRune{ID: "bf-missing", Claimant: "alice"})
+
+		// When
+		tc.next_command_is_processed()
+
+		// Then
+		cmd := tc.queued_command_is(core.CommandFailed, "claim-rune")
+		assert.Equal(t, http.StatusNotFound, cmd.StatusCode)
+		assert.Contains(t, string(cmd.Result), "error")
+	})
+
+	t.Run("replays the command with the headers it was queued with", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.post_async_with_key("/create-rune", "req-1", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// When
+		tc.next_command_is_processed()
+
+		// Then
+		cmd := tc.queued_command_is(core.CommandSucceeded, "create-rune")
+		assert.Equal(t, map[string]string{"Content-Type": "application/json", "Idempotency-Key": "req-1"}, cmd.Headers)
+		tc.appends_were_keyed("req-1")
+	})
+
+	t.Run("returns false when nothing is queued", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+
+		// When
+		processed, err := tc.handlers.ProcessNextCommand(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.False(t, processed)
+	})
+}
+
+func TestGetCommandHandler(t *testing.T) {
+	t.Run("returns the command status", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		id := tc.command_is_queued(domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+		tc.next_command_is_processed()
+
+		// When
+		tc.get("/command?id=" + id)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"status":"succeeded"`)
+		tc.response_body_contains(`"status_code":201`)
+		tc.response_body_contains(fmt.Sprintf(`"position":%d`, tc.eventStore.position))
+	})
+
+	t.Run("hides commands queued in another realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		id := tc.command_is_queued(domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+		tc.request_has_realm_id("realm-2")
+
+		// When
+		tc.get("/command?id=" + id)
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 404 when queueing is disabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/command?id=cmd-1")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 400 without an id", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_queue(10)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/command")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_command_queue(limit int) {
+	tc.t.Helper()
+	tc.commandQueue = &mockCommandQueue{limit: limit}
+}
+
+func (tc *handlerTestContext) command_is_queued(body any) string {
+	tc.t.Helper()
+	tc.post_async("/create-rune", body)
+	require.Equal(tc.t, http.StatusAccepted, tc.recorder.Code)
+	var accepted map[string]string
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &accepted))
+	tc.recorder = httptest.NewRecorder()
+	return accepted["id"]
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) post_async(path string, body any) {
+	tc.t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(tc.t, err)
+	tc.post_raw_async(path, data)
+}
+
+func (tc *handlerTestContext) post_raw_async(path string, body []byte) {
+	tc.t.Helper()
+	tc.post_raw_async_with_key(path, "", body)
+}
+
+func (tc *handlerTestContext) post_async_with_key(path, idempotencyKey string, body any) {
+	tc.t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(tc.t, err)
+	tc.post_raw_async_with_key(path, idempotencyKey, data)
+}
+
+func (tc *handlerTestContext) post_raw_async_with_key(path, idempotencyKey string, body []byte) {
+	tc.t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "respond-async")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	req = req.WithContext(tc.build_context(req.Context()))
+	tc.handlers.ServeHTTP(tc.recorder, req)
+}
+
+func (tc *handlerTestContext) next_command_is_processed() {
+	tc.t.Helper()
+	processed, err := tc.handlers.ProcessNextCommand(context.Background())
+	require.NoError(tc.t, err)
+	require.True(tc.t, processed)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) queued_command_is(status, command string) core.QueuedCommand {
+	tc.t.Helper()
+	require.Len(tc.t, tc.commandQueue.commands, 1)
+	cmd := tc.commandQueue.commands[0]
+	assert.Equal(tc.t, status, cmd.Status)
+	assert.Equal(tc.t, command, cmd.Command)
+	return cmd
+}
+
+func (tc *handlerTestContext) appends_were_keyed(idempotencyKey string) {
+	tc.t.Helper()
+	require.NotEmpty(tc.t, tc.eventStore.idempotencyKeys)
+	for _, key := range tc.eventStore.idempotencyKeys {
+		assert.True(tc.t, strings.HasPrefix(key, idempotencyKey), "append keyed %q", key)
+	}
+}
+
+// --- Mock Command Queue ---
+
+type mockCommandQueue struct {
+	limit    int
+	commands []core.QueuedCommand
+}
+
+func (m *mockCommandQueue) Enqueue(_ context.Context, cmd core.QueuedCommand) error {
+	cmd.Status = core.CommandPending
+	m.commands = append(m.commands, cmd)
+	return nil
+}
+
+func (m *mockCommandQueue) Claim(_ context.Context) (core.QueuedCommand, bool, error) {
+	for i := range m.commands {
+		if m.commands[i].Status == core.CommandPending {
+			m.commands[i].Status = core.CommandRunning
+			return m.commands[i], true, nil
+		}
+	}
+	return core.QueuedCommand{}, false, nil
+}
+
+func (m *mockCommandQueue) Complete(_ context.Context, id string, status string, statusCode int, position int64, result json.RawMessage) error {
+	for i := range m.commands {
+		if m.commands[i].ID == id {
+			m.commands[i].Status = status
+			m.commands[i].StatusCode = statusCode
+			m.commands[i].Position = position
+			m.commands[i].Result = result
+			return nil
+		}
+	}
+	return &core.NotFoundError{Entity: "command", ID: id}
+}
+
+func (m *mockCommandQueue) GetCommand(_ context.Context, id string) (core.QueuedCommand, error) {
+	for _, cmd := range m.commands {
+		if cmd.ID == id {
+			return cmd, nil
+		}
+	}
+	return core.QueuedCommand{}, &core.NotFoundError{Entity: "command", ID: id}
+}
+
+func (m *mockCommandQueue) Pending(_ context.Context) (int, error) {
+	n := 0
+	for _, cmd := range m.commands {
+		if cmd.Status == core.CommandPending || cmd.Status == core.CommandRunning {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (tc *handlerTestContext) no_rune_was_written(realmID string) {
+	tc.t.Helper()
+	for key := range tc.eventStore.streams {
+		assert.NotContains(tc.t, key, realmID+":rune-")
+	}
+}
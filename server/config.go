@@ -1,19 +1,170 @@
 package server
 
 import (
+	"database/sql"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/sqlite"
 )
 
 type Config struct {
-	DBDriver          string
-	DBPath            string
-	Port              int
-	CatchUpInterval   time.Duration
-	AdminUIStaticPath string // Path to built Vike assets (production mode)
-	ViteDevServerURL  string // URL of Vite dev server (development mode, e.g., "http://localhost:3000")
+	DBDriver                  string
+	DBPath                    string
+	DBDSN                     string        // MySQL data source name (mysql driver only)
+	DBReadDSN                 string        // MySQL read replica serving queries' projection reads (mysql driver only)
+	RedisURL                  string        // Redis server holding the projections (sqlite driver only; SQLite holds them when empty)
+	ProjectionDriver          string        // Store holding the projections, sqlite or postgres (the event driver's when empty)
+	CheckpointDriver          string        // Store holding the checkpoints, sqlite or postgres (the event driver's when empty)
+	PostgresDSN               string        // PostgreSQL DSN of the projection or checkpoint store (postgres drivers only)
+	PostgresReadDSN           string        // PostgreSQL standby serving queries (postgres drivers only)
+	DBMaxOpenShards           int           // Realm databases kept open by the sqlite-sharded driver
+	DBMaxOpenConns            int           // Pool size limit (driver default when zero)
+	DBMaxIdleConns            int           // Idle connections kept open (driver default when zero)
+	DBConnMaxLifetime         time.Duration // Connections are recycled after this long (never when zero)
+	DBJournalMode             string        // SQLite journal mode, e.g. WAL (SQLite's default when empty)
+	DBSynchronous             string        // SQLite synchronous level, e.g. NORMAL (SQLite's default when empty)
+	DBBusyTimeout             time.Duration // How long SQLite waits on a locked database (not at all when zero)
+	Port                      int
+	CatchUpInterval           time.Duration
+	CatchUpBatchSize          int           // Events projected per catch-up batch
+	CatchUpWorkers            int           // Realms caught up at once
+	CriticalProjectors        []string      // Projectors run in a command's transaction (every read model when empty)
+	EventEncryptionKey        []byte        // Master key wrapping the data keys of encrypted realms
+	EncryptedRealms           []string      // Realms whose events are stored encrypted ("*" for all)
+	AdminUIStaticPath         string        // Path to built Vike assets (production mode)
+	ViteDevServerURL          string        // URL of Vite dev server (development mode, e.g., "http://localhost:3000")
+	MetricsToken              string        // Bearer token required by /metrics (open when empty)
+	StaleClaimAge             time.Duration // How long a claim is held before it counts as stale
+	SLACheckInterval          time.Duration // How often runes are checked against realm SLA thresholds (disabled when zero)
+	EscalationCheckInterval   time.Duration // How often escalation policies are applied (disabled when zero)
+	SchedulerInterval         time.Duration // How often schedules are checked for due runs (disabled when zero)
+	RetentionInterval         time.Duration // How often realm retention policies are enforced (disabled when zero)
+	ArchiveAfter              time.Duration // How long after shattering a rune's events are archived (never when zero)
+	ArchiveInterval           time.Duration // How often shattered runes are checked for archiving
+	ProvisionFile             string        // YAML spec reconciled at startup (disabled when empty)
+	DirectoryFile             string        // YAML directory sync config (disabled when empty)
+	ProjectionCacheSize       int           // Projection entries cached in memory (disabled when zero)
+	NodeID                    string        // Identifies this instance when holding leases
+	CatchUpLeaseTTL           time.Duration // Catch-up runs only while holding a lease this long (single-node when zero)
+	DebugAddr                 string        // Loopback address serving pprof and expvar (disabled when empty)
+	EventCompressionThreshold int           // Event data this large is stored gzipped (disabled when zero)
+	CommandQueueSize          int           // Queued commands accepted before rejecting with 503 (queueing disabled when zero)
+	CommandWorkers            int           // Goroutines processing queued commands
+	ShutdownTimeout           time.Duration // How long shutdown waits for requests, commands and catch-up to finish
+	HTTPReadTimeout           time.Duration // Longest time to read a request, body included
+	HTTPWriteTimeout          time.Duration // Longest time from the end of the request headers to the end of the response
+	HTTPIdleTimeout           time.Duration // How long an idle keep-alive connection stays open
+	MaxBodyBytes              int64         // Largest request body accepted unless a route limit says otherwise
+	SentryDSN                 string        // Sentry-compatible project that receives panic reports (disabled when empty)
+	PanicReporter             PanicReporter // Receives panics recovered from handlers; overrides SentryDSN when set
+	RealmDomain               string        // Subdomains of this domain name realms, e.g. team-a.<domain> (disabled when empty)
+
+	// RealmHosts scopes requests for each host to a realm, by ID.
+	RealmHosts map[string]string
+
+	// ProjectorErrorPolicy is how catch-up treats an event a projector
+	// fails on, unless ProjectorErrorPolicies names the projector.
+	ProjectorErrorPolicy   core.ErrorPolicy
+	ProjectorErrorPolicies map[string]core.ErrorPolicy
+
+	// RouteLimits overrides MaxBodyBytes and the HTTP timeouts by path
+	// prefix.
+	RouteLimits map[string]RouteLimit
+
+	// Backup schedules encrypted event store backups.
+	Backup BackupConfig
+
+	// Search selects the full-text index behind the search endpoint.
+	Search SearchConfig
+
+	// Outbox publishes appended events to a webhook and/or NATS.
+	Outbox OutboxConfig
+
+	// Restore rebuilds the database from a backup archive instead of
+	// serving.
+	Restore RestoreConfig
+}
+
+// RestoreConfig makes Run restore an empty database from a backup archive,
+// up to a global position or time, and exit instead of serving.
+type RestoreConfig struct {
+	From       string    // Path of the events archive (serving normally when empty)
+	Key        []byte    // AES-256 key the archive is encrypted with
+	ToPosition int64     // Last global position restored (no limit when zero)
+	ToTime     time.Time // Events stored after it are not restored (no limit when zero)
+}
+
+// Enabled reports whether Run restores rather than serves.
+func (c RestoreConfig) Enabled() bool {
+	return c.From != ""
+}
+
+// OutboxConfig publishes every appended event, through the SQLite event
+// store's outbox, to a webhook endpoint, a NATS server, or both.
+type OutboxConfig struct {
+	Interval      time.Duration // How often the outbox is drained
+	WebhookURL    string        // Receives each event as a signed POST
+	WebhookSecret string        // Signs webhook deliveries
+	NATSURL       string        // nats:// or tls://[user:pass@]host:port, always over TLS
+	NATSSubject   string        // Subject prefix; events go to <prefix>.<realm>.<type>
+	NATSCAFile    string        // PEM CA certificates trusted for NATS instead of the system roots
+}
+
+// Enabled reports whether events are published externally.
+func (c OutboxConfig) Enabled() bool {
+	return c.WebhookURL != "" || c.NATSURL != ""
+}
+
+// SearchConfig selects the index that answers rune searches: an embedded
+// Bleve index in Dir, or an Elasticsearch or OpenSearch cluster at URL.
+type SearchConfig struct {
+	Provider string // "bleve" or "elasticsearch" (search disabled when empty)
+	Dir      string // Directory of the Bleve index
+	URL      string // Elasticsearch or OpenSearch endpoint
+	Index    string // Elasticsearch index name (bifrost-runes when empty)
+	APIKey   string
+	Username string
+	Password string
+}
+
+// Enabled reports whether rune search is available.
+func (c SearchConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// BackupConfig schedules encrypted backups of the event store to a local
+// directory, or to an S3-compatible bucket when S3Bucket is set.
+type BackupConfig struct {
+	Interval          time.Duration // How often a backup is taken (disabled when zero)
+	Dir               string        // Directory archives are written to
+	Key               []byte        // AES-256 key archives are encrypted with
+	Keep              int           // Most recent archives kept; older ones are deleted
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string // Defaults to AWS for the region
+	S3Prefix          string // Prepended to archive names, e.g. "backups/"
+	S3PathStyle       bool
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	Projections       bool // Also back up projection rows and checkpoints
+}
+
+// Enabled reports whether backups are scheduled.
+func (c BackupConfig) Enabled() bool {
+	return c.Interval > 0
+}
+
+// sqliteTuning returns the PRAGMAs SQLite databases are opened with.
+func (c *Config) sqliteTuning() sqlite.Tuning {
+	return sqlite.Tuning{JournalMode: c.DBJournalMode, Synchronous: c.DBSynchronous, BusyTimeout: c.DBBusyTimeout}
 }
 
 func LoadConfig() (*Config, error) {
@@ -27,6 +178,114 @@ func LoadConfig() (*Config, error) {
 		dbPath = "./bifrost.db"
 	}
 
+	maxOpenShards := 64
+	if shardsStr := os.Getenv("BIFROST_DB_MAX_OPEN_SHARDS"); shardsStr != "" {
+		n, err := strconv.Atoi(shardsStr)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BIFROST_DB_MAX_OPEN_SHARDS must be a positive integer")
+		}
+		maxOpenShards = n
+	}
+
+	dbDSN := os.Getenv("BIFROST_DB_DSN")
+	if dbDriver == "mysql" && dbDSN == "" {
+		return nil, fmt.Errorf("BIFROST_DB_DSN is required when BIFROST_DB_DRIVER is mysql")
+	}
+
+	redisURL := os.Getenv("BIFROST_REDIS_URL")
+	if redisURL != "" && dbDriver != "sqlite" {
+		return nil, fmt.Errorf("BIFROST_REDIS_URL needs BIFROST_DB_DRIVER sqlite, got %s", dbDriver)
+	}
+	projectionDriver, err := storeDriver("BIFROST_PROJECTION_DRIVER", dbDriver)
+	if err != nil {
+		return nil, err
+	}
+	if redisURL != "" && projectionDriver != "" {
+		return nil, fmt.Errorf("BIFROST_REDIS_URL and BIFROST_PROJECTION_DRIVER cannot both be set")
+	}
+	checkpointDriver, err := storeDriver("BIFROST_CHECKPOINT_DRIVER", dbDriver)
+	if err != nil {
+		return nil, err
+	}
+	postgresDSN := os.Getenv("BIFROST_POSTGRES_DSN")
+	if (projectionDriver == "postgres" || checkpointDriver == "postgres") && postgresDSN == "" {
+		return nil, fmt.Errorf("BIFROST_POSTGRES_DSN is required when projections or checkpoints use postgres")
+	}
+	postgresReadDSN := os.Getenv("BIFROST_POSTGRES_READ_DSN")
+	if postgresReadDSN != "" && (projectionDriver != "postgres" || checkpointDriver != "postgres") {
+		return nil, fmt.Errorf("BIFROST_POSTGRES_READ_DSN needs BIFROST_PROJECTION_DRIVER and BIFROST_CHECKPOINT_DRIVER postgres")
+	}
+	dbReadDSN := os.Getenv("BIFROST_DB_READ_DSN")
+	if dbReadDSN != "" && (dbDriver != "mysql" || projectionDriver != "" || checkpointDriver != "") {
+		return nil, fmt.Errorf("BIFROST_DB_READ_DSN needs BIFROST_DB_DRIVER mysql with its projections and checkpoints")
+	}
+
+	maxOpenConns, err := nonNegativeInt("BIFROST_DB_MAX_OPEN_CONNS")
+	if err != nil {
+		return nil, err
+	}
+	maxIdleConns, err := nonNegativeInt("BIFROST_DB_MAX_IDLE_CONNS")
+	if err != nil {
+		return nil, err
+	}
+	var connMaxLifetime time.Duration
+	if lifetimeStr := os.Getenv("BIFROST_DB_CONN_MAX_LIFETIME"); lifetimeStr != "" {
+		d, err := time.ParseDuration(lifetimeStr)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("BIFROST_DB_CONN_MAX_LIFETIME must be a non-negative duration")
+		}
+		connMaxLifetime = d
+	}
+
+	// WAL lets the admin UI and API read while a command writes, and the
+	// busy timeout makes writers queue instead of failing with "database is
+	// locked".
+	journalMode := os.Getenv("BIFROST_DB_JOURNAL_MODE")
+	if journalMode == "" {
+		journalMode = "WAL"
+	}
+	busyTimeout := 5 * time.Second
+	if timeoutStr := os.Getenv("BIFROST_DB_BUSY_TIMEOUT"); timeoutStr != "" {
+		d, err := time.ParseDuration(timeoutStr)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("BIFROST_DB_BUSY_TIMEOUT must be a non-negative duration")
+		}
+		busyTimeout = d
+	}
+	tuning := sqlite.Tuning{
+		JournalMode: journalMode,
+		Synchronous: os.Getenv("BIFROST_DB_SYNCHRONOUS"),
+		BusyTimeout: busyTimeout,
+	}
+	if err := tuning.Validate(); err != nil {
+		return nil, fmt.Errorf("BIFROST_DB_JOURNAL_MODE or BIFROST_DB_SYNCHRONOUS: %w", err)
+	}
+
+	eventCompressionThreshold, err := nonNegativeInt("BIFROST_EVENT_COMPRESSION_THRESHOLD")
+	if err != nil {
+		return nil, err
+	}
+
+	commandQueueSize, err := nonNegativeInt("BIFROST_COMMAND_QUEUE_SIZE")
+	if err != nil {
+		return nil, err
+	}
+	commandWorkers := 1
+	if workersStr := os.Getenv("BIFROST_COMMAND_WORKERS"); workersStr != "" {
+		n, err := strconv.Atoi(workersStr)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BIFROST_COMMAND_WORKERS must be a positive integer")
+		}
+		commandWorkers = n
+	}
+
+	projectionCacheSize := 10000
+	if sizeStr := os.Getenv("BIFROST_PROJECTION_CACHE_SIZE"); sizeStr != "" {
+		if projectionCacheSize, err = nonNegativeInt("BIFROST_PROJECTION_CACHE_SIZE"); err != nil {
+			return nil, err
+		}
+	}
+
 	port := 8080
 	if portStr := os.Getenv("BIFROST_PORT"); portStr != "" {
 		p, err := strconv.Atoi(portStr)
@@ -48,12 +307,523 @@ func LoadConfig() (*Config, error) {
 		catchUpInterval = d
 	}
 
+	catchUpBatchSize := 500
+	if sizeStr := os.Getenv("BIFROST_CATCHUP_BATCH_SIZE"); sizeStr != "" {
+		n, err := strconv.Atoi(sizeStr)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BIFROST_CATCHUP_BATCH_SIZE must be a positive integer")
+		}
+		catchUpBatchSize = n
+	}
+
+	projectorErrorPolicy := core.ErrorPolicy{Action: core.SkipEvent}
+	if policyStr := os.Getenv("BIFROST_PROJECTOR_ERROR_POLICY"); policyStr != "" {
+		policy, err := core.ParseErrorPolicy(policyStr)
+		if err != nil {
+			return nil, fmt.Errorf("BIFROST_PROJECTOR_ERROR_POLICY: %w", err)
+		}
+		projectorErrorPolicy = policy
+	}
+	projectorErrorPolicies := make(map[string]core.ErrorPolicy)
+	for _, entry := range strings.Split(os.Getenv("BIFROST_PROJECTOR_ERROR_POLICIES"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, policyStr, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("BIFROST_PROJECTOR_ERROR_POLICIES entries must look like <projector>=<policy>, got %q", entry)
+		}
+		policy, err := core.ParseErrorPolicy(policyStr)
+		if err != nil {
+			return nil, fmt.Errorf("BIFROST_PROJECTOR_ERROR_POLICIES: %w", err)
+		}
+		projectorErrorPolicies[name] = policy
+	}
+
+	catchUpWorkers := 1
+	if workersStr := os.Getenv("BIFROST_CATCHUP_WORKERS"); workersStr != "" {
+		n, err := strconv.Atoi(workersStr)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BIFROST_CATCHUP_WORKERS must be a positive integer")
+		}
+		catchUpWorkers = n
+	}
+
+	var criticalProjectors []string
+	for _, name := range strings.Split(os.Getenv("BIFROST_CRITICAL_PROJECTORS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			criticalProjectors = append(criticalProjectors, name)
+		}
+	}
+
+	var encryptedRealms []string
+	for _, id := range strings.Split(os.Getenv("BIFROST_ENCRYPTED_REALMS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			encryptedRealms = append(encryptedRealms, id)
+		}
+	}
+	var eventEncryptionKey []byte
+	if len(encryptedRealms) > 0 {
+		key, err := base64.StdEncoding.DecodeString(os.Getenv("BIFROST_EVENT_ENCRYPTION_KEY"))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("BIFROST_ENCRYPTED_REALMS needs BIFROST_EVENT_ENCRYPTION_KEY, 32 bytes, base64-encoded (e.g. openssl rand -base64 32)")
+		}
+		eventEncryptionKey = key
+	}
+
+	var catchUpLeaseTTL time.Duration
+	if ttlStr := os.Getenv("BIFROST_CATCHUP_LEASE_TTL"); ttlStr != "" {
+		d, err := time.ParseDuration(ttlStr)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("BIFROST_CATCHUP_LEASE_TTL must be a non-negative duration")
+		}
+		catchUpLeaseTTL = d
+	}
+
+	debugAddr := os.Getenv("BIFROST_DEBUG_ADDR")
+	if debugAddr != "" && !isLoopbackAddr(debugAddr) {
+		return nil, fmt.Errorf("BIFROST_DEBUG_ADDR must be a loopback host:port, e.g. 127.0.0.1:6060")
+	}
+
+	readTimeout, err := positiveDuration("BIFROST_HTTP_READ_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	writeTimeout, err := positiveDuration("BIFROST_HTTP_WRITE_TIMEOUT", 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := positiveDuration("BIFROST_HTTP_IDLE_TIMEOUT", 120*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBodyBytes := int64(1 << 20)
+	if sizeStr := os.Getenv("BIFROST_MAX_BODY_BYTES"); sizeStr != "" {
+		n, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("BIFROST_MAX_BODY_BYTES must be a positive integer")
+		}
+		maxBodyBytes = n
+	}
+
+	routeLimits, err := loadRouteLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	realmHosts, err := loadRealmHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := loadBackupConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	search, err := loadSearchConfig(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := loadOutboxConfig(dbDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	restore, err := loadRestoreConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout := 30 * time.Second
+	if timeoutStr := os.Getenv("BIFROST_SHUTDOWN_TIMEOUT"); timeoutStr != "" {
+		d, err := time.ParseDuration(timeoutStr)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("BIFROST_SHUTDOWN_TIMEOUT must be a positive duration")
+		}
+		shutdownTimeout = d
+	}
+
+	nodeID := os.Getenv("BIFROST_NODE_ID")
+	if nodeID == "" {
+		hostname, _ := os.Hostname()
+		nodeID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	staleClaimAge := 7 * 24 * time.Hour
+	if daysStr := os.Getenv("BIFROST_STALE_CLAIM_DAYS"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("BIFROST_STALE_CLAIM_DAYS must be a positive integer")
+		}
+		staleClaimAge = time.Duration(days) * 24 * time.Hour
+	}
+
+	slaCheckInterval, err := positiveDuration("BIFROST_SLA_CHECK_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	escalationCheckInterval, err := positiveDuration("BIFROST_ESCALATION_CHECK_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulerInterval, err := positiveDuration("BIFROST_SCHEDULER_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	retentionInterval, err := positiveDuration("BIFROST_RETENTION_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	var archiveAfter time.Duration
+	if os.Getenv("BIFROST_ARCHIVE_AFTER") != "" {
+		if archiveAfter, err = positiveDuration("BIFROST_ARCHIVE_AFTER", 0); err != nil {
+			return nil, err
+		}
+	}
+
+	archiveInterval, err := positiveDuration("BIFROST_ARCHIVE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		DBDriver:          dbDriver,
-		DBPath:            dbPath,
-		Port:              port,
-		CatchUpInterval:   catchUpInterval,
-		AdminUIStaticPath: os.Getenv("BIFROST_ADMIN_UI_STATIC_PATH"),
-		ViteDevServerURL:  os.Getenv("BIFROST_VITE_DEV_SERVER_URL"),
+		DBDriver:                  dbDriver,
+		DBPath:                    dbPath,
+		DBDSN:                     dbDSN,
+		DBReadDSN:                 dbReadDSN,
+		RedisURL:                  redisURL,
+		ProjectionDriver:          projectionDriver,
+		CheckpointDriver:          checkpointDriver,
+		PostgresDSN:               postgresDSN,
+		PostgresReadDSN:           postgresReadDSN,
+		DBMaxOpenShards:           maxOpenShards,
+		DBMaxOpenConns:            maxOpenConns,
+		DBMaxIdleConns:            maxIdleConns,
+		DBConnMaxLifetime:         connMaxLifetime,
+		DBJournalMode:             tuning.JournalMode,
+		DBSynchronous:             tuning.Synchronous,
+		DBBusyTimeout:             tuning.BusyTimeout,
+		Port:                      port,
+		CatchUpInterval:           catchUpInterval,
+		CatchUpBatchSize:          catchUpBatchSize,
+		CatchUpWorkers:            catchUpWorkers,
+		CriticalProjectors:        criticalProjectors,
+		ProjectorErrorPolicy:      projectorErrorPolicy,
+		ProjectorErrorPolicies:    projectorErrorPolicies,
+		EventEncryptionKey:        eventEncryptionKey,
+		EncryptedRealms:           encryptedRealms,
+		AdminUIStaticPath:         os.Getenv("BIFROST_ADMIN_UI_STATIC_PATH"),
+		ViteDevServerURL:          os.Getenv("BIFROST_VITE_DEV_SERVER_URL"),
+		MetricsToken:              os.Getenv("BIFROST_METRICS_TOKEN"),
+		StaleClaimAge:             staleClaimAge,
+		SLACheckInterval:          slaCheckInterval,
+		EscalationCheckInterval:   escalationCheckInterval,
+		SchedulerInterval:         schedulerInterval,
+		RetentionInterval:         retentionInterval,
+		ArchiveAfter:              archiveAfter,
+		ArchiveInterval:           archiveInterval,
+		ProvisionFile:             os.Getenv("BIFROST_PROVISION_FILE"),
+		DirectoryFile:             os.Getenv("BIFROST_DIRECTORY_FILE"),
+		ProjectionCacheSize:       projectionCacheSize,
+		NodeID:                    nodeID,
+		CatchUpLeaseTTL:           catchUpLeaseTTL,
+		DebugAddr:                 debugAddr,
+		EventCompressionThreshold: eventCompressionThreshold,
+		CommandQueueSize:          commandQueueSize,
+		CommandWorkers:            commandWorkers,
+		ShutdownTimeout:           shutdownTimeout,
+		HTTPReadTimeout:           readTimeout,
+		HTTPWriteTimeout:          writeTimeout,
+		HTTPIdleTimeout:           idleTimeout,
+		MaxBodyBytes:              maxBodyBytes,
+		RouteLimits:               routeLimits,
+		SentryDSN:                 os.Getenv("BIFROST_SENTRY_DSN"),
+		RealmDomain:               strings.ToLower(os.Getenv("BIFROST_REALM_DOMAIN")),
+		RealmHosts:                realmHosts,
+		Backup:                    backup,
+		Search:                    search,
+		Outbox:                    outbox,
+		Restore:                   restore,
 	}, nil
 }
+
+func nonNegativeInt(key string) (int, error) {
+	str := os.Getenv(key)
+	if str == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(str)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+func positiveDuration(key string, def time.Duration) (time.Duration, error) {
+	str := os.Getenv(key)
+	if str == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration", key)
+	}
+	return d, nil
+}
+
+// loadRouteLimits applies BIFROST_ROUTE_BODY_LIMITS and BIFROST_ROUTE_TIMEOUTS,
+// comma-separated "<path prefix>=<value>" lists, on top of DefaultRouteLimits.
+func loadRouteLimits() (map[string]RouteLimit, error) {
+	limits := DefaultRouteLimits()
+	if err := parseRouteList("BIFROST_ROUTE_BODY_LIMITS", func(prefix, value string) bool {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 1 {
+			return false
+		}
+		limit := limits[prefix]
+		limit.MaxBodyBytes = n
+		limits[prefix] = limit
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if err := parseRouteList("BIFROST_ROUTE_TIMEOUTS", func(prefix, value string) bool {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return false
+		}
+		limit := limits[prefix]
+		limit.Timeout = d
+		limits[prefix] = limit
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func parseRouteList(key string, apply func(prefix, value string) bool) error {
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		prefix, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") || !apply(prefix, value) {
+			return fmt.Errorf("%s entries must look like /path=<value>, got %q", key, entry)
+		}
+	}
+	return nil
+}
+
+// loadRealmHosts parses BIFROST_REALM_HOSTS, a comma-separated list of
+// "<host>=<realm ID>" entries.
+func loadRealmHosts() (map[string]string, error) {
+	hosts := make(map[string]string)
+	for _, entry := range strings.Split(os.Getenv("BIFROST_REALM_HOSTS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		host, realmID, ok := strings.Cut(entry, "=")
+		if !ok || host == "" || realmID == "" || strings.ContainsAny(host, "/:") {
+			return nil, fmt.Errorf("BIFROST_REALM_HOSTS entries must look like host=<realm ID>, got %q", entry)
+		}
+		hosts[strings.ToLower(host)] = realmID
+	}
+	return hosts, nil
+}
+
+// loadBackupConfig reads the BIFROST_BACKUP_* settings. Backups need a key
+// and a directory or bucket once an interval is set.
+func loadBackupConfig() (BackupConfig, error) {
+	cfg := BackupConfig{
+		Dir:               os.Getenv("BIFROST_BACKUP_DIR"),
+		Keep:              7,
+		S3Bucket:          os.Getenv("BIFROST_BACKUP_S3_BUCKET"),
+		S3Region:          os.Getenv("BIFROST_BACKUP_S3_REGION"),
+		S3Endpoint:        os.Getenv("BIFROST_BACKUP_S3_ENDPOINT"),
+		S3Prefix:          os.Getenv("BIFROST_BACKUP_S3_PREFIX"),
+		S3PathStyle:       os.Getenv("BIFROST_BACKUP_S3_PATH_STYLE") == "true",
+		S3AccessKeyID:     os.Getenv("BIFROST_BACKUP_S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("BIFROST_BACKUP_S3_SECRET_ACCESS_KEY"),
+		Projections:       os.Getenv("BIFROST_BACKUP_PROJECTIONS") == "true",
+	}
+	if intervalStr := os.Getenv("BIFROST_BACKUP_INTERVAL"); intervalStr != "" {
+		d, err := time.ParseDuration(intervalStr)
+		if err != nil || d < 0 {
+			return cfg, fmt.Errorf("BIFROST_BACKUP_INTERVAL must be a non-negative duration")
+		}
+		cfg.Interval = d
+	}
+	if keepStr := os.Getenv("BIFROST_BACKUP_KEEP"); keepStr != "" {
+		n, err := strconv.Atoi(keepStr)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("BIFROST_BACKUP_KEEP must be a positive integer")
+		}
+		cfg.Keep = n
+	}
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("BIFROST_BACKUP_KEY"))
+	if err != nil || len(key) != 32 {
+		return cfg, fmt.Errorf("BIFROST_BACKUP_KEY must be 32 bytes, base64-encoded (e.g. openssl rand -base64 32)")
+	}
+	cfg.Key = key
+	if cfg.Dir == "" && cfg.S3Bucket == "" {
+		return cfg, fmt.Errorf("BIFROST_BACKUP_INTERVAL needs BIFROST_BACKUP_DIR or BIFROST_BACKUP_S3_BUCKET")
+	}
+	return cfg, nil
+}
+
+// loadRestoreConfig reads the BIFROST_RESTORE_* settings. The archive is
+// opened with the backup key.
+func loadRestoreConfig() (RestoreConfig, error) {
+	cfg := RestoreConfig{From: os.Getenv("BIFROST_RESTORE_FROM")}
+	if posStr := os.Getenv("BIFROST_RESTORE_TO_POSITION"); posStr != "" {
+		pos, err := strconv.ParseInt(posStr, 10, 64)
+		if err != nil || pos < 1 {
+			return cfg, fmt.Errorf("BIFROST_RESTORE_TO_POSITION must be a positive integer")
+		}
+		cfg.ToPosition = pos
+	}
+	if timeStr := os.Getenv("BIFROST_RESTORE_TO_TIME"); timeStr != "" {
+		t, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return cfg, fmt.Errorf("BIFROST_RESTORE_TO_TIME must be an RFC 3339 time, e.g. 2026-10-15T12:00:00Z")
+		}
+		cfg.ToTime = t
+	}
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("BIFROST_BACKUP_KEY"))
+	if err != nil || len(key) != 32 {
+		return cfg, fmt.Errorf("BIFROST_RESTORE_FROM needs BIFROST_BACKUP_KEY, 32 bytes, base64-encoded")
+	}
+	cfg.Key = key
+	return cfg, nil
+}
+
+// loadOutboxConfig reads the BIFROST_OUTBOX_* settings. The outbox is
+// written by the SQLite event store, so other drivers cannot publish.
+// storeDriver reads the driver of a store that can be kept apart from the
+// events. It returns "" when the event driver's own store is meant, which
+// is the only choice for the memory and sqlite-sharded drivers.
+func storeDriver(key, dbDriver string) (string, error) {
+	driver := os.Getenv(key)
+	switch driver {
+	case "", dbDriver:
+		return "", nil
+	case "sqlite", "postgres":
+		if dbDriver != "sqlite" && dbDriver != "mysql" {
+			return "", fmt.Errorf("%s needs BIFROST_DB_DRIVER sqlite or mysql, got %s", key, dbDriver)
+		}
+		return driver, nil
+	}
+	return "", fmt.Errorf("%s must be sqlite or postgres, got %s", key, driver)
+}
+
+// projectionsApart reports whether projections or checkpoints are kept
+// outside the events' database, so no transaction covers all three.
+// sizePool applies the pool settings to db.
+func (c *Config) sizePool(db *sql.DB) {
+	if c.DBMaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.DBMaxOpenConns)
+	}
+	if c.DBMaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.DBMaxIdleConns)
+	}
+	if c.DBConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.DBConnMaxLifetime)
+	}
+}
+
+func (c *Config) projectionsApart() bool {
+	return c.RedisURL != "" ||
+		(c.ProjectionDriver != "" && c.ProjectionDriver != c.DBDriver) ||
+		(c.CheckpointDriver != "" && c.CheckpointDriver != c.DBDriver)
+}
+
+func loadOutboxConfig(dbDriver string) (OutboxConfig, error) {
+	cfg := OutboxConfig{
+		WebhookURL:    os.Getenv("BIFROST_OUTBOX_WEBHOOK_URL"),
+		WebhookSecret: os.Getenv("BIFROST_OUTBOX_WEBHOOK_SECRET"),
+		NATSURL:       os.Getenv("BIFROST_OUTBOX_NATS_URL"),
+		NATSSubject:   os.Getenv("BIFROST_OUTBOX_NATS_SUBJECT"),
+		NATSCAFile:    os.Getenv("BIFROST_OUTBOX_NATS_CA_FILE"),
+	}
+	if cfg.NATSSubject == "" {
+		cfg.NATSSubject = "bifrost.events"
+	}
+	interval, err := positiveDuration("BIFROST_OUTBOX_INTERVAL", time.Second)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Interval = interval
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+	if dbDriver != "sqlite" {
+		return cfg, fmt.Errorf("the outbox needs BIFROST_DB_DRIVER sqlite, got %s", dbDriver)
+	}
+	if cfg.WebhookURL != "" && cfg.WebhookSecret == "" {
+		return cfg, fmt.Errorf("BIFROST_OUTBOX_WEBHOOK_URL needs BIFROST_OUTBOX_WEBHOOK_SECRET")
+	}
+	if cfg.NATSURL != "" && !strings.HasPrefix(cfg.NATSURL, "nats://") {
+		return cfg, fmt.Errorf("BIFROST_OUTBOX_NATS_URL must look like nats://host:4222")
+	}
+	return cfg, nil
+}
+
+// loadSearchConfig reads BIFROST_SEARCH_PROVIDER, which defaults to a Bleve
+// index in a bifrost-search directory beside the database. "none" disables
+// search.
+func loadSearchConfig(dbPath string) (SearchConfig, error) {
+	cfg := SearchConfig{
+		Provider: os.Getenv("BIFROST_SEARCH_PROVIDER"),
+		Dir:      os.Getenv("BIFROST_SEARCH_DIR"),
+		URL:      os.Getenv("BIFROST_SEARCH_URL"),
+		Index:    os.Getenv("BIFROST_SEARCH_INDEX"),
+		APIKey:   os.Getenv("BIFROST_SEARCH_API_KEY"),
+		Username: os.Getenv("BIFROST_SEARCH_USERNAME"),
+		Password: os.Getenv("BIFROST_SEARCH_PASSWORD"),
+	}
+	switch cfg.Provider {
+	case "", "bleve":
+		cfg.Provider = "bleve"
+		if cfg.Dir == "" {
+			cfg.Dir = filepath.Join(filepath.Dir(dbPath), "bifrost-search")
+		}
+	case "elasticsearch", "opensearch":
+		cfg.Provider = "elasticsearch"
+		if cfg.URL == "" {
+			return cfg, fmt.Errorf("BIFROST_SEARCH_PROVIDER=elasticsearch needs BIFROST_SEARCH_URL")
+		}
+	case "none":
+		cfg.Provider = ""
+	default:
+		return cfg, fmt.Errorf("BIFROST_SEARCH_PROVIDER must be bleve, elasticsearch, opensearch or none")
+	}
+	return cfg, nil
+}
+
+// isLoopbackAddr reports whether addr is a host:port that only accepts
+// connections from the local machine.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
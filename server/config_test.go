@@ -1,9 +1,11 @@
 package server
 
 import (
+	"encoding/base64"
 	"testing"
 	"time"
 
+	"github.com/devzeebo/bifrost/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,72 +46,1294 @@ func TestLoadConfig(t *testing.T) {
 		tc.db_path_is("./bifrost.db")
 		tc.port_is(8080)
 		tc.catchup_interval_is(1 * time.Second)
+		tc.stale_claim_age_is(7 * 24 * time.Hour)
+	})
+
+	t.Run("reads the MySQL DSN for the mysql driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "mysql")
+		tc.env_var("BIFROST_DB_DSN", "bifrost:secret@tcp(db:3306)/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		tc.db_driver_is("mysql")
+		assert.Equal(t, "bifrost:secret@tcp(db:3306)/bifrost", tc.cfg.DBDSN)
+	})
+
+	t.Run("reads the DSN of a MySQL read replica", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "mysql")
+		tc.env_var("BIFROST_DB_DSN", "bifrost:secret@tcp(db:3306)/bifrost")
+		tc.env_var("BIFROST_DB_READ_DSN", "bifrost:secret@tcp(replica:3306)/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "bifrost:secret@tcp(replica:3306)/bifrost", tc.cfg.DBReadDSN)
+	})
+
+	t.Run("returns error when a read replica is set with another driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_READ_DSN", "bifrost:secret@tcp(replica:3306)/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_DB_READ_DSN")
+	})
+
+	t.Run("reads the Redis URL holding the projections", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_REDIS_URL", "redis://cache:6379/1")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "redis://cache:6379/1", tc.cfg.RedisURL)
+	})
+
+	t.Run("returns error when the Redis URL is set with another driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "memory")
+		tc.env_var("BIFROST_REDIS_URL", "redis://cache:6379")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_REDIS_URL")
+	})
+
+	t.Run("reads the drivers of projections and checkpoints kept apart from the events", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "postgres")
+		tc.env_var("BIFROST_CHECKPOINT_DRIVER", "postgres")
+		tc.env_var("BIFROST_POSTGRES_DSN", "postgres://bifrost@reads/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "postgres", tc.cfg.ProjectionDriver)
+		assert.Equal(t, "postgres", tc.cfg.CheckpointDriver)
+		assert.Equal(t, "postgres://bifrost@reads/bifrost", tc.cfg.PostgresDSN)
+		assert.True(t, tc.cfg.projectionsApart())
+	})
+
+	t.Run("keeps projections with the events when their driver is the event driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "mysql")
+		tc.env_var("BIFROST_DB_DSN", "bifrost:secret@tcp(db:3306)/bifrost")
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "mysql")
+		tc.env_var("BIFROST_CHECKPOINT_DRIVER", "sqlite")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Empty(t, tc.cfg.ProjectionDriver)
+		assert.Equal(t, "sqlite", tc.cfg.CheckpointDriver)
+		assert.True(t, tc.cfg.projectionsApart())
+	})
+
+	t.Run("returns error when postgres stores have no DSN", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CHECKPOINT_DRIVER", "postgres")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_POSTGRES_DSN")
+	})
+
+	t.Run("reads the DSN of a PostgreSQL read replica", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "postgres")
+		tc.env_var("BIFROST_CHECKPOINT_DRIVER", "postgres")
+		tc.env_var("BIFROST_POSTGRES_DSN", "postgres://bifrost@reads/bifrost")
+		tc.env_var("BIFROST_POSTGRES_READ_DSN", "postgres://bifrost@standby/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "postgres://bifrost@standby/bifrost", tc.cfg.PostgresReadDSN)
+	})
+
+	t.Run("returns error when a PostgreSQL read replica serves only the projections", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "postgres")
+		tc.env_var("BIFROST_POSTGRES_DSN", "postgres://bifrost@reads/bifrost")
+		tc.env_var("BIFROST_POSTGRES_READ_DSN", "postgres://bifrost@standby/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_POSTGRES_READ_DSN")
+	})
+
+	t.Run("returns error for an unknown projection driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "oracle")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PROJECTION_DRIVER")
+	})
+
+	t.Run("returns error when stores are kept apart from sharded events", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "sqlite-sharded")
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "postgres")
+		tc.env_var("BIFROST_POSTGRES_DSN", "postgres://bifrost@reads/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PROJECTION_DRIVER")
+	})
+
+	t.Run("returns error when Redis and a projection driver are both chosen", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_REDIS_URL", "redis://cache:6379")
+		tc.env_var("BIFROST_PROJECTION_DRIVER", "postgres")
+		tc.env_var("BIFROST_POSTGRES_DSN", "postgres://bifrost@reads/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_REDIS_URL")
+	})
+
+	t.Run("keeps 64 realm databases open by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 64, tc.cfg.DBMaxOpenShards)
+	})
+
+	t.Run("returns error when BIFROST_DB_MAX_OPEN_SHARDS is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_MAX_OPEN_SHARDS", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_DB_MAX_OPEN_SHARDS")
+	})
+
+	t.Run("returns error when the mysql driver has no DSN", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "mysql")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_DB_DSN")
 	})
 
 	t.Run("returns error when BIFROST_PORT is not a number", func(t *testing.T) {
 		tc := newConfigTestContext(t)
 
 		// Given
-		tc.env_var("BIFROST_PORT", "abc")
+		tc.env_var("BIFROST_PORT", "abc")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PORT")
+	})
+
+	t.Run("returns error when BIFROST_PORT is zero", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PORT", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PORT")
+	})
+
+	t.Run("returns error when BIFROST_PORT exceeds 65535", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PORT", "65536")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PORT")
+	})
+
+	t.Run("returns error when BIFROST_CATCHUP_INTERVAL is invalid", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_INTERVAL", "bad")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_CATCHUP_INTERVAL")
+	})
+
+	t.Run("parses BIFROST_CATCHUP_INTERVAL as duration", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_INTERVAL", "2s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		tc.catchup_interval_is(2 * time.Second)
+	})
+
+	t.Run("parses BIFROST_CATCHUP_BATCH_SIZE", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_BATCH_SIZE", "1000")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 1000, tc.cfg.CatchUpBatchSize)
+	})
+
+	t.Run("returns error when BIFROST_CATCHUP_BATCH_SIZE is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_BATCH_SIZE", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_CATCHUP_BATCH_SIZE")
+	})
+
+	t.Run("parses BIFROST_CATCHUP_WORKERS", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_WORKERS", "8")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 8, tc.cfg.CatchUpWorkers)
+	})
+
+	t.Run("returns error when BIFROST_CATCHUP_WORKERS is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_WORKERS", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_CATCHUP_WORKERS")
+	})
+
+	t.Run("skips events projectors fail on by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, core.ErrorPolicy{Action: core.SkipEvent}, tc.cfg.ProjectorErrorPolicy)
+		assert.Empty(t, tc.cfg.ProjectorErrorPolicies)
+	})
+
+	t.Run("parses the projector error policies", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTOR_ERROR_POLICY", "retry:3")
+		tc.env_var("BIFROST_PROJECTOR_ERROR_POLICIES", "dashboard_stats=halt, search=retry:5:halt")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, core.ErrorPolicy{Retries: 3, Action: core.SkipEvent}, tc.cfg.ProjectorErrorPolicy)
+		assert.Equal(t, map[string]core.ErrorPolicy{
+			"dashboard_stats": {Action: core.HaltProjector},
+			"search":          {Retries: 5, Action: core.HaltProjector},
+		}, tc.cfg.ProjectorErrorPolicies)
+	})
+
+	t.Run("returns error for an unknown projector error policy", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTOR_ERROR_POLICIES", "search=ignore")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_PROJECTOR_ERROR_POLICIES")
+	})
+
+	t.Run("parses BIFROST_CRITICAL_PROJECTORS", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CRITICAL_PROJECTORS", "rune_list, rune_detail,")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, []string{"rune_list", "rune_detail"}, tc.cfg.CriticalProjectors)
+	})
+
+	t.Run("parses BIFROST_ENCRYPTED_REALMS with BIFROST_EVENT_ENCRYPTION_KEY", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ENCRYPTED_REALMS", "bf-r1, bf-r2")
+		tc.env_var("BIFROST_EVENT_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(make([]byte, 32)))
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, []string{"bf-r1", "bf-r2"}, tc.cfg.EncryptedRealms)
+		assert.Len(t, tc.cfg.EventEncryptionKey, 32)
+	})
+
+	t.Run("returns error for encrypted realms without a valid key", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ENCRYPTED_REALMS", "*")
+		tc.env_var("BIFROST_EVENT_ENCRYPTION_KEY", "c2hvcnQ=")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_EVENT_ENCRYPTION_KEY")
+	})
+
+	t.Run("parses BIFROST_CATCHUP_LEASE_TTL and BIFROST_NODE_ID", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_LEASE_TTL", "30s")
+		tc.env_var("BIFROST_NODE_ID", "node-a")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 30*time.Second, tc.cfg.CatchUpLeaseTTL)
+		assert.Equal(t, "node-a", tc.cfg.NodeID)
+	})
+
+	t.Run("defaults to single-node catch-up with a generated node ID", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_LEASE_TTL", "")
+		tc.env_var("BIFROST_NODE_ID", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Zero(t, tc.cfg.CatchUpLeaseTTL)
+		assert.NotEmpty(t, tc.cfg.NodeID)
+	})
+
+	t.Run("returns error when BIFROST_CATCHUP_LEASE_TTL is invalid", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_CATCHUP_LEASE_TTL", "soon")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_CATCHUP_LEASE_TTL")
+	})
+
+	t.Run("parses BIFROST_SHUTDOWN_TIMEOUT", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SHUTDOWN_TIMEOUT", "2m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 2*time.Minute, tc.cfg.ShutdownTimeout)
+	})
+
+	t.Run("defaults BIFROST_SHUTDOWN_TIMEOUT to 30s", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SHUTDOWN_TIMEOUT", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 30*time.Second, tc.cfg.ShutdownTimeout)
+	})
+
+	t.Run("returns error when BIFROST_SHUTDOWN_TIMEOUT is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SHUTDOWN_TIMEOUT", "0s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_SHUTDOWN_TIMEOUT")
+	})
+
+	t.Run("defaults HTTP timeouts, body size and route limits", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_HTTP_READ_TIMEOUT", "")
+		tc.env_var("BIFROST_HTTP_WRITE_TIMEOUT", "")
+		tc.env_var("BIFROST_HTTP_IDLE_TIMEOUT", "")
+		tc.env_var("BIFROST_MAX_BODY_BYTES", "")
+		tc.env_var("BIFROST_ROUTE_BODY_LIMITS", "")
+		tc.env_var("BIFROST_ROUTE_TIMEOUTS", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 30*time.Second, tc.cfg.HTTPReadTimeout)
+		assert.Equal(t, 60*time.Second, tc.cfg.HTTPWriteTimeout)
+		assert.Equal(t, 120*time.Second, tc.cfg.HTTPIdleTimeout)
+		assert.Equal(t, int64(1<<20), tc.cfg.MaxBodyBytes)
+		assert.Equal(t, DefaultRouteLimits(), tc.cfg.RouteLimits)
+	})
+
+	t.Run("parses HTTP timeouts, body size and route overrides", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_HTTP_READ_TIMEOUT", "5s")
+		tc.env_var("BIFROST_HTTP_WRITE_TIMEOUT", "10s")
+		tc.env_var("BIFROST_HTTP_IDLE_TIMEOUT", "1m")
+		tc.env_var("BIFROST_MAX_BODY_BYTES", "4096")
+		tc.env_var("BIFROST_ROUTE_BODY_LIMITS", "/api/link-commits=65536, /api/import-github=8192")
+		tc.env_var("BIFROST_ROUTE_TIMEOUTS", "/api/import-github=10m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 5*time.Second, tc.cfg.HTTPReadTimeout)
+		assert.Equal(t, 10*time.Second, tc.cfg.HTTPWriteTimeout)
+		assert.Equal(t, time.Minute, tc.cfg.HTTPIdleTimeout)
+		assert.Equal(t, int64(4096), tc.cfg.MaxBodyBytes)
+		assert.Equal(t, RouteLimit{MaxBodyBytes: 65536}, tc.cfg.RouteLimits["/api/link-commits"])
+		assert.Equal(t, RouteLimit{MaxBodyBytes: 8192, Timeout: 10 * time.Minute}, tc.cfg.RouteLimits["/api/import-github"])
+		assert.Equal(t, RouteLimit{MaxBodyBytes: 25 << 20}, tc.cfg.RouteLimits["/integrations/github/"])
+	})
+
+	t.Run("returns error when a route override is malformed", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ROUTE_TIMEOUTS", "/api/import-github")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_ROUTE_TIMEOUTS")
+	})
+
+	t.Run("parses realm hosts and the realm domain", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_REALM_HOSTS", "Tracker.Team-A.com=realm-a, bugs.example.org=realm-b")
+		tc.env_var("BIFROST_REALM_DOMAIN", "Bifrost.Example.com")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, map[string]string{"tracker.team-a.com": "realm-a", "bugs.example.org": "realm-b"}, tc.cfg.RealmHosts)
+		assert.Equal(t, "bifrost.example.com", tc.cfg.RealmDomain)
+	})
+
+	t.Run("returns error when a realm host is malformed", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_REALM_HOSTS", "team-a.example.com")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_REALM_HOSTS")
+	})
+
+	t.Run("parses the BIFROST_BACKUP settings", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_BACKUP_INTERVAL", "24h")
+		tc.env_var("BIFROST_BACKUP_DIR", "/var/backups/bifrost")
+		tc.env_var("BIFROST_BACKUP_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+		tc.env_var("BIFROST_BACKUP_KEEP", "14")
+		tc.env_var("BIFROST_BACKUP_PROJECTIONS", "true")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.True(t, tc.cfg.Backup.Enabled())
+		assert.Equal(t, 24*time.Hour, tc.cfg.Backup.Interval)
+		assert.Equal(t, "/var/backups/bifrost", tc.cfg.Backup.Dir)
+		assert.Len(t, tc.cfg.Backup.Key, 32)
+		assert.Equal(t, 14, tc.cfg.Backup.Keep)
+		assert.True(t, tc.cfg.Backup.Projections)
+	})
+
+	t.Run("leaves backups disabled by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.False(t, tc.cfg.Backup.Enabled())
+		assert.Equal(t, 7, tc.cfg.Backup.Keep)
+	})
+
+	t.Run("returns error when backups are scheduled without a key", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_BACKUP_INTERVAL", "24h")
+		tc.env_var("BIFROST_BACKUP_DIR", "/var/backups/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_BACKUP_KEY")
+	})
+
+	t.Run("returns error when backups are scheduled without a destination", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_BACKUP_INTERVAL", "24h")
+		tc.env_var("BIFROST_BACKUP_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_BACKUP_DIR")
+	})
+
+	t.Run("parses the BIFROST_RESTORE settings", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_RESTORE_FROM", "/var/backups/bifrost/bifrost-20261015T000000Z.bak")
+		tc.env_var("BIFROST_RESTORE_TO_POSITION", "1200")
+		tc.env_var("BIFROST_RESTORE_TO_TIME", "2026-10-14T18:30:00Z")
+		tc.env_var("BIFROST_BACKUP_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.True(t, tc.cfg.Restore.Enabled())
+		assert.Equal(t, "/var/backups/bifrost/bifrost-20261015T000000Z.bak", tc.cfg.Restore.From)
+		assert.Equal(t, int64(1200), tc.cfg.Restore.ToPosition)
+		assert.Equal(t, time.Date(2026, 10, 14, 18, 30, 0, 0, time.UTC), tc.cfg.Restore.ToTime)
+		assert.Len(t, tc.cfg.Restore.Key, 32)
+	})
+
+	t.Run("returns error when restoring without the backup key", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_RESTORE_FROM", "/var/backups/bifrost/bifrost-20261015T000000Z.bak")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_BACKUP_KEY")
+	})
+
+	t.Run("parses the BIFROST_OUTBOX settings", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_OUTBOX_INTERVAL", "5s")
+		tc.env_var("BIFROST_OUTBOX_WEBHOOK_URL", "https://hooks.example.com/bifrost")
+		tc.env_var("BIFROST_OUTBOX_WEBHOOK_SECRET", "s3cret")
+		tc.env_var("BIFROST_OUTBOX_NATS_URL", "nats://nats.example.com:4222")
+		tc.env_var("BIFROST_OUTBOX_NATS_SUBJECT", "tracker.events")
+		tc.env_var("BIFROST_OUTBOX_NATS_CA_FILE", "/etc/bifrost/nats-ca.pem")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.True(t, tc.cfg.Outbox.Enabled())
+		assert.Equal(t, OutboxConfig{
+			Interval:      5 * time.Second,
+			WebhookURL:    "https://hooks.example.com/bifrost",
+			WebhookSecret: "s3cret",
+			NATSURL:       "nats://nats.example.com:4222",
+			NATSSubject:   "tracker.events",
+			NATSCAFile:    "/etc/bifrost/nats-ca.pem",
+		}, tc.cfg.Outbox)
+	})
+
+	t.Run("leaves the outbox disabled by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.False(t, tc.cfg.Outbox.Enabled())
+		assert.Equal(t, time.Second, tc.cfg.Outbox.Interval)
+		assert.Equal(t, "bifrost.events", tc.cfg.Outbox.NATSSubject)
+	})
+
+	t.Run("returns error when the outbox is enabled for another driver", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_DRIVER", "memory")
+		tc.env_var("BIFROST_OUTBOX_NATS_URL", "nats://localhost:4222")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("sqlite")
+	})
+
+	t.Run("returns error when the outbox webhook has no secret", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_OUTBOX_WEBHOOK_URL", "https://hooks.example.com/bifrost")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_OUTBOX_WEBHOOK_SECRET")
+	})
+
+	t.Run("defaults search to a Bleve index beside the database", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_PATH", "/data/bifrost.db")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.True(t, tc.cfg.Search.Enabled())
+		assert.Equal(t, "bleve", tc.cfg.Search.Provider)
+		assert.Equal(t, "/data/bifrost-search", tc.cfg.Search.Dir)
+	})
+
+	t.Run("parses the BIFROST_SEARCH settings for OpenSearch", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SEARCH_PROVIDER", "opensearch")
+		tc.env_var("BIFROST_SEARCH_URL", "https://search.example.com:9200")
+		tc.env_var("BIFROST_SEARCH_INDEX", "runes")
+		tc.env_var("BIFROST_SEARCH_API_KEY", "k3y")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "elasticsearch", tc.cfg.Search.Provider)
+		assert.Equal(t, "https://search.example.com:9200", tc.cfg.Search.URL)
+		assert.Equal(t, "runes", tc.cfg.Search.Index)
+		assert.Equal(t, "k3y", tc.cfg.Search.APIKey)
+	})
+
+	t.Run("disables search when BIFROST_SEARCH_PROVIDER is none", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SEARCH_PROVIDER", "none")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.False(t, tc.cfg.Search.Enabled())
+	})
+
+	t.Run("returns error when Elasticsearch has no URL", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SEARCH_PROVIDER", "elasticsearch")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_SEARCH_URL")
+	})
+
+	t.Run("returns error for an unknown search provider", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SEARCH_PROVIDER", "solr")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_SEARCH_PROVIDER")
+	})
+
+	t.Run("returns error when BIFROST_MAX_BODY_BYTES is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_MAX_BODY_BYTES", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_MAX_BODY_BYTES")
+	})
+
+	t.Run("parses BIFROST_EVENT_COMPRESSION_THRESHOLD", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_EVENT_COMPRESSION_THRESHOLD", "4096")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 4096, tc.cfg.EventCompressionThreshold)
+	})
+
+	t.Run("returns error when BIFROST_EVENT_COMPRESSION_THRESHOLD is negative", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_EVENT_COMPRESSION_THRESHOLD", "-1")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_EVENT_COMPRESSION_THRESHOLD")
+	})
+
+	t.Run("parses command queue settings", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_COMMAND_QUEUE_SIZE", "500")
+		tc.env_var("BIFROST_COMMAND_WORKERS", "4")
 
 		// When
 		tc.load_config()
 
 		// Then
-		tc.config_has_error_containing("BIFROST_PORT")
+		tc.config_has_no_error()
+		assert.Equal(t, 500, tc.cfg.CommandQueueSize)
+		assert.Equal(t, 4, tc.cfg.CommandWorkers)
 	})
 
-	t.Run("returns error when BIFROST_PORT is zero", func(t *testing.T) {
+	t.Run("returns error when BIFROST_COMMAND_WORKERS is zero", func(t *testing.T) {
 		tc := newConfigTestContext(t)
 
 		// Given
-		tc.env_var("BIFROST_PORT", "0")
+		tc.env_var("BIFROST_COMMAND_WORKERS", "0")
 
 		// When
 		tc.load_config()
 
 		// Then
-		tc.config_has_error_containing("BIFROST_PORT")
+		tc.config_has_error_containing("BIFROST_COMMAND_WORKERS")
 	})
 
-	t.Run("returns error when BIFROST_PORT exceeds 65535", func(t *testing.T) {
+	t.Run("parses a loopback BIFROST_DEBUG_ADDR", func(t *testing.T) {
 		tc := newConfigTestContext(t)
 
 		// Given
-		tc.env_var("BIFROST_PORT", "65536")
+		tc.env_var("BIFROST_DEBUG_ADDR", "127.0.0.1:6060")
 
 		// When
 		tc.load_config()
 
 		// Then
-		tc.config_has_error_containing("BIFROST_PORT")
+		tc.config_has_no_error()
+		assert.Equal(t, "127.0.0.1:6060", tc.cfg.DebugAddr)
 	})
 
-	t.Run("returns error when BIFROST_CATCHUP_INTERVAL is invalid", func(t *testing.T) {
+	t.Run("returns error when BIFROST_DEBUG_ADDR is not loopback", func(t *testing.T) {
 		tc := newConfigTestContext(t)
 
 		// Given
-		tc.env_var("BIFROST_CATCHUP_INTERVAL", "bad")
+		tc.env_var("BIFROST_DEBUG_ADDR", ":6060")
 
 		// When
 		tc.load_config()
 
 		// Then
-		tc.config_has_error_containing("BIFROST_CATCHUP_INTERVAL")
+		tc.config_has_error_containing("BIFROST_DEBUG_ADDR")
 	})
 
-	t.Run("parses BIFROST_CATCHUP_INTERVAL as duration", func(t *testing.T) {
+	t.Run("parses BIFROST_STALE_CLAIM_DAYS as days", func(t *testing.T) {
 		tc := newConfigTestContext(t)
 
 		// Given
-		tc.env_var("BIFROST_CATCHUP_INTERVAL", "2s")
+		tc.env_var("BIFROST_STALE_CLAIM_DAYS", "3")
+		tc.env_var("BIFROST_METRICS_TOKEN", "scrape-me")
 
 		// When
 		tc.load_config()
 
 		// Then
 		tc.config_has_no_error()
-		tc.catchup_interval_is(2 * time.Second)
+		tc.stale_claim_age_is(3 * 24 * time.Hour)
+		assert.Equal(t, "scrape-me", tc.cfg.MetricsToken)
+	})
+
+	t.Run("reads BIFROST_PROVISION_FILE and BIFROST_DIRECTORY_FILE", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROVISION_FILE", "/etc/bifrost/provision.yaml")
+		tc.env_var("BIFROST_DIRECTORY_FILE", "/etc/bifrost/directory.yaml")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "/etc/bifrost/provision.yaml", tc.cfg.ProvisionFile)
+		assert.Equal(t, "/etc/bifrost/directory.yaml", tc.cfg.DirectoryFile)
+	})
+
+	t.Run("parses database pool sizing", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_MAX_OPEN_CONNS", "20")
+		tc.env_var("BIFROST_DB_MAX_IDLE_CONNS", "5")
+		tc.env_var("BIFROST_DB_CONN_MAX_LIFETIME", "30m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 20, tc.cfg.DBMaxOpenConns)
+		assert.Equal(t, 5, tc.cfg.DBMaxIdleConns)
+		assert.Equal(t, 30*time.Minute, tc.cfg.DBConnMaxLifetime)
+	})
+
+	t.Run("opens SQLite in WAL mode with a busy timeout by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "WAL", tc.cfg.DBJournalMode)
+		assert.Equal(t, "", tc.cfg.DBSynchronous)
+		assert.Equal(t, 5*time.Second, tc.cfg.DBBusyTimeout)
+	})
+
+	t.Run("parses the SQLite tuning", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_JOURNAL_MODE", "delete")
+		tc.env_var("BIFROST_DB_SYNCHRONOUS", "NORMAL")
+		tc.env_var("BIFROST_DB_BUSY_TIMEOUT", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, "delete", tc.cfg.DBJournalMode)
+		assert.Equal(t, "NORMAL", tc.cfg.DBSynchronous)
+		assert.Equal(t, time.Duration(0), tc.cfg.DBBusyTimeout)
+	})
+
+	t.Run("returns error for an unknown SQLite journal mode", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_JOURNAL_MODE", "FAST")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_DB_JOURNAL_MODE")
+	})
+
+	t.Run("caches 10000 projection entries by default", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 10000, tc.cfg.ProjectionCacheSize)
+	})
+
+	t.Run("disables the projection cache when BIFROST_PROJECTION_CACHE_SIZE is zero", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_PROJECTION_CACHE_SIZE", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 0, tc.cfg.ProjectionCacheSize)
+	})
+
+	t.Run("returns error when BIFROST_DB_MAX_OPEN_CONNS is negative", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_DB_MAX_OPEN_CONNS", "-1")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_DB_MAX_OPEN_CONNS")
+	})
+
+	t.Run("parses BIFROST_SLA_CHECK_INTERVAL and defaults it to a minute", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SLA_CHECK_INTERVAL", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, time.Minute, tc.cfg.SLACheckInterval)
+
+		// Given
+		tc.env_var("BIFROST_SLA_CHECK_INTERVAL", "15m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 15*time.Minute, tc.cfg.SLACheckInterval)
+	})
+
+	t.Run("returns error when BIFROST_SLA_CHECK_INTERVAL is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SLA_CHECK_INTERVAL", "0s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_SLA_CHECK_INTERVAL")
+	})
+
+	t.Run("parses BIFROST_ESCALATION_CHECK_INTERVAL and defaults it to a minute", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ESCALATION_CHECK_INTERVAL", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, time.Minute, tc.cfg.EscalationCheckInterval)
+
+		// Given
+		tc.env_var("BIFROST_ESCALATION_CHECK_INTERVAL", "5m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 5*time.Minute, tc.cfg.EscalationCheckInterval)
+	})
+
+	t.Run("returns error when BIFROST_ESCALATION_CHECK_INTERVAL is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ESCALATION_CHECK_INTERVAL", "-1m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_ESCALATION_CHECK_INTERVAL")
+	})
+
+	t.Run("parses BIFROST_SCHEDULER_INTERVAL and defaults it to 30 seconds", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SCHEDULER_INTERVAL", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 30*time.Second, tc.cfg.SchedulerInterval)
+
+		// Given
+		tc.env_var("BIFROST_SCHEDULER_INTERVAL", "10s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 10*time.Second, tc.cfg.SchedulerInterval)
+	})
+
+	t.Run("returns error when BIFROST_SCHEDULER_INTERVAL is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_SCHEDULER_INTERVAL", "0s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_SCHEDULER_INTERVAL")
+	})
+
+	t.Run("parses BIFROST_RETENTION_INTERVAL and defaults it to an hour", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_RETENTION_INTERVAL", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, time.Hour, tc.cfg.RetentionInterval)
+
+		// Given
+		tc.env_var("BIFROST_RETENTION_INTERVAL", "15m")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 15*time.Minute, tc.cfg.RetentionInterval)
+	})
+
+	t.Run("parses BIFROST_ARCHIVE_AFTER and leaves archiving off without it", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ARCHIVE_AFTER", "")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Zero(t, tc.cfg.ArchiveAfter)
+		assert.Equal(t, time.Hour, tc.cfg.ArchiveInterval)
+
+		// Given
+		tc.env_var("BIFROST_ARCHIVE_AFTER", "2160h")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_no_error()
+		assert.Equal(t, 2160*time.Hour, tc.cfg.ArchiveAfter)
+	})
+
+	t.Run("returns error for a BIFROST_ARCHIVE_AFTER that is not a positive duration", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_ARCHIVE_AFTER", "90d")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_ARCHIVE_AFTER")
+	})
+
+	t.Run("returns error when BIFROST_RETENTION_INTERVAL is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_RETENTION_INTERVAL", "0s")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_RETENTION_INTERVAL")
+	})
+
+	t.Run("returns error when BIFROST_STALE_CLAIM_DAYS is not positive", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// Given
+		tc.env_var("BIFROST_STALE_CLAIM_DAYS", "0")
+
+		// When
+		tc.load_config()
+
+		// Then
+		tc.config_has_error_containing("BIFROST_STALE_CLAIM_DAYS")
 	})
 }
 
@@ -179,3 +1403,8 @@ func (tc *configTestContext) catchup_interval_is(expected time.Duration) {
 	tc.t.Helper()
 	assert.Equal(tc.t, expected, tc.cfg.CatchUpInterval)
 }
+
+func (tc *configTestContext) stale_claim_age_is(expected time.Duration) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.cfg.StaleClaimAge)
+}
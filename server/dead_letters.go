@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// WithDeadLetterStore enables the dead-letters endpoint, listing the events
+// projectors failed to handle from s.
+func WithDeadLetterStore(s core.DeadLetterStore) HandlersOption {
+	return func(h *Handlers) {
+		h.deadLetters = s
+	}
+}
+
+type deadLettersResponse struct {
+	DeadLetters []core.DeadLetter `json:"dead_letters"`
+}
+
+// ListDeadLetters returns the events of the realm_id realm that projectors
+// failed to handle, only those of projector when it is given.
+func (h *Handlers) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if h.deadLetters == nil {
+		writeError(w, http.StatusNotFound, "dead letters are not recorded")
+		return
+	}
+	realmID := r.URL.Query().Get("realm_id")
+	if realmID == "" {
+		writeError(w, http.StatusBadRequest, "realm_id parameter required")
+		return
+	}
+	letters, err := h.deadLetters.ListDeadLetters(r.Context(), realmID, r.URL.Query().Get("projector"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, deadLettersResponse{DeadLetters: letters})
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// --- Tests ---
+
+func TestListDeadLettersHandler(t *testing.T) {
+	t.Run("returns the realm's dead letters", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.dead_letters_recorded(
+			core.DeadLetter{
+				RealmID: "realm-1", Projector: "dashboard_stats", GlobalPosition: 12, StreamID: "bf-a1", Version: 3,
+				EventType: "RuneFulfilled", Error: "panic: nil map", Attempts: 4, Halted: true,
+				FailedAt: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC),
+			},
+			core.DeadLetter{RealmID: "realm-2", Projector: "rune_detail", GlobalPosition: 3},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/dead-letters?realm_id=realm-1")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"dead_letters":[{"realm_id":"realm-1","projector":"dashboard_stats","global_position":12,` +
+			`"stream_id":"bf-a1","version":3,"event_type":"RuneFulfilled","error":"panic: nil map","attempts":4,` +
+			`"halted":true,"failed_at":"2026-10-15T09:00:00Z"}]}`)
+	})
+
+	t.Run("filters by projector", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.dead_letters_recorded(
+			core.DeadLetter{RealmID: "realm-1", Projector: "dashboard_stats", GlobalPosition: 12},
+			core.DeadLetter{RealmID: "realm-1", Projector: "rune_detail", GlobalPosition: 13},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/dead-letters?realm_id=realm-1&projector=rune_detail")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"projector":"rune_detail"`)
+		tc.response_body_does_not_contain(`"projector":"dashboard_stats"`)
+	})
+
+	t.Run("returns 400 without a realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.dead_letters_recorded()
+		tc.handlers_configured()
+
+		// When
+		tc.get("/dead-letters")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 when dead letters are not recorded", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.get("/dead-letters?realm_id=realm-1")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+// --- Mock Dead Letter Store ---
+
+type mockDeadLetterStore struct {
+	letters []core.DeadLetter
+}
+
+func (m *mockDeadLetterStore) RecordDeadLetter(_ context.Context, letter core.DeadLetter) error {
+	m.letters = append(m.letters, letter)
+	return nil
+}
+
+func (m *mockDeadLetterStore) ListDeadLetters(_ context.Context, realmID, projector string) ([]core.DeadLetter, error) {
+	letters := []core.DeadLetter{}
+	for _, l := range m.letters {
+		if l.RealmID == realmID && (projector == "" || l.Projector == projector) {
+			letters = append(letters, l)
+		}
+	}
+	return letters, nil
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) dead_letters_recorded(letters ...core.DeadLetter) {
+	tc.t.Helper()
+	tc.deadLetters = &mockDeadLetterStore{letters: letters}
+}
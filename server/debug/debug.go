@@ -0,0 +1,31 @@
+// Package debug serves runtime diagnostics for performance work in
+// production: net/http/pprof profiles and expvar variables, including
+// goroutine counts, memory and GC statistics and event store latency
+// histograms. It is meant for a listener reachable only from the host.
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+)
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+}
+
+// Handler serves /debug/pprof/ and /debug/vars. The expvar "memstats"
+// variable carries the runtime memory and GC statistics.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return mux
+}
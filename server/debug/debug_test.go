@@ -0,0 +1,129 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ core.EventStoreHooks = latencyHooks{}
+
+// --- Tests ---
+
+func TestHistogram(t *testing.T) {
+	t.Run("counts durations into cumulative buckets", func(t *testing.T) {
+		// Given
+		h := NewHistogram()
+
+		// When
+		h.Observe(500 * time.Microsecond)
+		h.Observe(20 * time.Millisecond)
+		h.Observe(time.Minute)
+
+		// Then
+		var rendered struct {
+			Count   int64            `json:"count"`
+			Buckets map[string]int64 `json:"buckets"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(h.String()), &rendered))
+		assert.Equal(t, int64(3), rendered.Count)
+		assert.Equal(t, int64(1), rendered.Buckets["1ms"])
+		assert.Equal(t, int64(1), rendered.Buckets["10ms"])
+		assert.Equal(t, int64(2), rendered.Buckets["25ms"])
+		assert.Equal(t, int64(2), rendered.Buckets["5s"])
+		assert.Equal(t, int64(3), rendered.Buckets["+Inf"])
+	})
+}
+
+func TestInstrumentEventStore(t *testing.T) {
+	t.Run("records the latency of each call", func(t *testing.T) {
+		// Given
+		store := InstrumentEventStore(&stubEventStore{})
+		before := observations(t, "list_realm_ids")
+
+		// When
+		_, err := store.ListRealmIDs(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, before+1, observations(t, "list_realm_ids"))
+	})
+
+	t.Run("trims full reads for stores without batching", func(t *testing.T) {
+		// Given
+		store := InstrumentEventStore(&stubEventStore{events: []core.Event{
+			{GlobalPosition: 1}, {GlobalPosition: 2}, {GlobalPosition: 3},
+		}})
+
+		// When
+		events, err := store.(core.BatchEventReader).ReadAllBatch(context.Background(), "realm-1", 0, 2)
+
+		// Then
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("serves expvar variables", func(t *testing.T) {
+		// When
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var vars map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+		assert.Contains(t, vars, "goroutines")
+		assert.Contains(t, vars, "memstats")
+		assert.Contains(t, vars, "event_store_latency")
+	})
+
+	t.Run("serves the pprof index", func(t *testing.T) {
+		// When
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "goroutine")
+	})
+}
+
+// --- Helpers ---
+
+func observations(t *testing.T, op string) int64 {
+	t.Helper()
+	var rendered struct {
+		Count int64 `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(eventStoreLatency.Get(op).String()), &rendered))
+	return rendered.Count
+}
+
+type stubEventStore struct {
+	events []core.Event
+}
+
+func (s *stubEventStore) Append(_ context.Context, _ string, _ string, _ int, _ []core.EventData) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (s *stubEventStore) ReadStream(_ context.Context, _ string, _ string, _ int) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (s *stubEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]core.Event, error) {
+	return s.events, nil
+}
+
+func (s *stubEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return nil, nil
+}
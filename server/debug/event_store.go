@@ -0,0 +1,38 @@
+package debug
+
+import (
+	"context"
+	"expvar"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// eventStoreLatency holds a Histogram per event store operation.
+var eventStoreLatency = expvar.NewMap("event_store_latency")
+
+func init() {
+	for _, op := range []string{core.OpAppend, core.OpReadStream, core.OpReadAll, core.OpListRealmIDs} {
+		eventStoreLatency.Set(op, NewHistogram())
+	}
+}
+
+// latencyHooks records each call's latency in its operation's histogram.
+type latencyHooks struct{}
+
+func (latencyHooks) OnAppend(_ context.Context, call core.StoreCall) {
+	observe(call)
+}
+
+func (latencyHooks) OnRead(_ context.Context, call core.StoreCall) {
+	observe(call)
+}
+
+// InstrumentEventStore wraps store so its call latencies are published in
+// the event_store_latency expvar. Batched reads count as read_all.
+func InstrumentEventStore(store core.EventStore) core.EventStore {
+	return core.InstrumentEventStore(store, latencyHooks{})
+}
+
+func observe(call core.StoreCall) {
+	eventStoreLatency.Get(call.Op).(*Histogram).Observe(call.Duration)
+}
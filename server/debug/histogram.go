@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the histogram buckets.
+var latencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// Histogram is an expvar.Var counting durations into cumulative buckets,
+// in the style of a Prometheus histogram.
+type Histogram struct {
+	mu     sync.Mutex
+	counts []int64 // per bucket, the last one unbounded
+	count  int64
+	sum    time.Duration
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make([]int64, len(latencyBounds)+1)}
+}
+
+// Observe records one duration.
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.count++
+	h.sum += d
+}
+
+// String renders the histogram as JSON with cumulative bucket counts keyed
+// by upper bound.
+func (h *Histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.counts))
+	var cumulative int64
+	for i, n := range h.counts {
+		cumulative += n
+		le := "+Inf"
+		if i < len(latencyBounds) {
+			le = latencyBounds[i].String()
+		}
+		buckets[le] = cumulative
+	}
+	data, _ := json.Marshal(struct {
+		Count   int64            `json:"count"`
+		SumMS   float64          `json:"sum_ms"`
+		Buckets map[string]int64 `json:"buckets"`
+	}{h.count, float64(h.sum) / float64(time.Millisecond), buckets})
+	return string(data)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// decodeJSONBody decodes the request body into dst, rejecting fields dst does
+// not declare so a misspelt field fails instead of being ignored. On failure
+// it writes the response and returns false: 413 when the body is over the
+// limit set by LimitRequests, and 400 otherwise, listing any unknown fields
+// in details.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		if unknown := unknownFields(body, dst, err); len(unknown) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{
+				"error":   "unknown fields: " + strings.Join(unknown, ", "),
+				"details": map[string]any{"unknown_fields": unknown},
+			})
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+// unknownFields lists the body's top-level fields that dst does not declare.
+// The decoder stops at the first unknown field, so the body is read again to
+// report all of them; an unknown field inside a nested object is reported by
+// the name in err.
+func unknownFields(body []byte, dst any, err error) []string {
+	msg, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) == nil {
+		known := jsonFieldNames(reflect.TypeOf(dst))
+		var unknown []string
+		for name := range fields {
+			if !known[strings.ToLower(name)] {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return unknown
+		}
+	}
+	return []string{strings.Trim(msg, `"`)}
+}
+
+// jsonFieldNames returns the lower-cased JSON names of a struct type's
+// fields, including promoted ones, matching encoding/json's case-insensitive
+// lookup.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			for embedded := range jsonFieldNames(f.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
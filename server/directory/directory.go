@@ -0,0 +1,124 @@
+// Package directory keeps accounts in step with an external user directory.
+//
+// A Source lists the directory's users and the groups they belong to. The
+// Syncer provisions accounts for users that are granted access by a rule,
+// keeps their roles in the mapped realms matching their groups, and suspends
+// accounts whose users are disabled or gone. Only accounts the sync created
+// (source "directory") are changed, so hand-made accounts are never touched.
+package directory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// AccountSource marks accounts created by the directory sync.
+const AccountSource = "directory"
+
+// User is a directory entry.
+type User struct {
+	Username string
+	Active   bool
+	// Groups holds group names or DNs, matched case-insensitively by rules.
+	Groups []string
+}
+
+// Source lists every user in a directory.
+type Source interface {
+	Name() string
+	Users(ctx context.Context) ([]User, error)
+}
+
+// Rule grants Role in Realm to members of Group. When several rules match
+// a user in the same realm, the highest role wins.
+type Rule struct {
+	Group string `yaml:"group"`
+	// Realm is a realm ID or a unique realm name.
+	Realm string `yaml:"realm"`
+	Role  string `yaml:"role"`
+}
+
+// Config is the directory sync file.
+//
+//	interval: 15m
+//	ldap:
+//	  url: ldaps://ldap.example.com
+//	  bind_dn: cn=bifrost,ou=services,dc=example,dc=com
+//	  password_env: LDAP_PASSWORD
+//	  base_dn: ou=people,dc=example,dc=com
+//	rules:
+//	  - group: cn=engineering,ou=groups,dc=example,dc=com
+//	    realm: platform
+//	    role: member
+type Config struct {
+	Interval time.Duration `yaml:"interval"`
+	LDAP     *LDAPConfig   `yaml:"ldap"`
+	SCIM     *SCIMConfig   `yaml:"scim"`
+	Rules    []Rule        `yaml:"rules"`
+}
+
+// LoadConfig reads and validates a directory sync file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig decodes and validates a directory sync document, applying
+// defaults. Unknown fields are rejected.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse directory file: %w", err)
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+	if cfg.Interval < time.Minute {
+		return nil, fmt.Errorf("directory sync interval must be at least 1m")
+	}
+	if (cfg.LDAP == nil) == (cfg.SCIM == nil) {
+		return nil, fmt.Errorf("directory file must configure exactly one of ldap or scim")
+	}
+	if cfg.LDAP != nil {
+		if err := cfg.LDAP.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SCIM != nil && cfg.SCIM.URL == "" {
+		return nil, fmt.Errorf("scim url is required")
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("directory file must declare at least one rule")
+	}
+	for _, r := range cfg.Rules {
+		if r.Group == "" || r.Realm == "" {
+			return nil, fmt.Errorf("directory rule needs a group and a realm")
+		}
+		if !domain.IsValidRole(r.Role) {
+			return nil, fmt.Errorf("directory rule for group %q: invalid role %q", r.Group, r.Role)
+		}
+	}
+	return &cfg, nil
+}
+
+// Source builds the configured directory source. Secrets are read from the
+// environment variables the file names.
+func (c *Config) Source(getenv func(string) string) Source {
+	if c.LDAP != nil {
+		return NewLDAPSource(*c.LDAP, getenv(c.LDAP.PasswordEnv))
+	}
+	return NewSCIMSource(*c.SCIM, getenv(c.SCIM.TokenEnv), nil)
+}
@@ -0,0 +1,110 @@
+package directory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestParseConfig(t *testing.T) {
+	t.Run("applies LDAP defaults", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.parse(`
+ldap:
+  url: ldaps://ldap.example.com
+  base_dn: ou=people,dc=example,dc=com
+rules:
+  - {group: "cn=eng,ou=groups,dc=example,dc=com", realm: platform, role: member}
+`)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 15*time.Minute, tc.cfg.Interval)
+		assert.Equal(t, "(objectClass=person)", tc.cfg.LDAP.Filter)
+		assert.Equal(t, "uid", tc.cfg.LDAP.UsernameAttribute)
+		assert.Equal(t, "memberOf", tc.cfg.LDAP.GroupAttribute)
+		assert.IsType(t, &LDAPSource{}, tc.cfg.Source(func(string) string { return "" }))
+	})
+
+	t.Run("reads a SCIM source and interval", func(t *testing.T) {
+		tc := newConfigTestContext(t)
+
+		// When
+		tc.parse(`
+interval: 5m
+scim: {url: https://idp.example.com/scim/v2, token_env: SCIM_TOKEN}
+rules: [{group: Engineering, realm: platform, role: admin}]
+`)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 5*time.Minute, tc.cfg.Interval)
+		assert.IsType(t, &SCIMSource{}, tc.cfg.Source(func(string) string { return "" }))
+	})
+
+	cases := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{"no source", "rules: [{group: g, realm: r, role: member}]", "exactly one of ldap or scim"},
+		{"two sources", "ldap: {url: ldap://h, base_dn: dc=x}\nscim: {url: https://x}\nrules: [{group: g, realm: r, role: member}]", "exactly one of ldap or scim"},
+		{"bad ldap url", "ldap: {url: http://h, base_dn: dc=x}\nrules: [{group: g, realm: r, role: member}]", "ldap url must be"},
+		{"bad ldap filter", "ldap: {url: ldap://h, base_dn: dc=x, filter: uid=x}\nrules: [{group: g, realm: r, role: member}]", "does not start with an '('"},
+		{"no rules", "scim: {url: https://x}", "at least one rule"},
+		{"invalid role", "scim: {url: https://x}\nrules: [{group: g, realm: r, role: boss}]", `invalid role "boss"`},
+		{"short interval", "interval: 10s\nscim: {url: https://x}\nrules: [{group: g, realm: r, role: member}]", "at least 1m"},
+		{"unknown field", "scim: {url: https://x, tokn: y}\nrules: [{group: g, realm: r, role: member}]", "field tokn not found"},
+	}
+	for _, c := range cases {
+		t.Run("rejects "+c.name, func(t *testing.T) {
+			tc := newConfigTestContext(t)
+
+			// When
+			tc.parse(c.doc)
+
+			// Then
+			tc.error_contains(c.expected)
+		})
+	}
+}
+
+// --- Test Context ---
+
+type configTestContext struct {
+	t *testing.T
+
+	cfg *Config
+	err error
+}
+
+func newConfigTestContext(t *testing.T) *configTestContext {
+	t.Helper()
+	return &configTestContext{t: t}
+}
+
+// --- When ---
+
+func (tc *configTestContext) parse(doc string) {
+	tc.t.Helper()
+	tc.cfg, tc.err = ParseConfig([]byte(doc))
+}
+
+// --- Then ---
+
+func (tc *configTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *configTestContext) error_contains(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substr)
+}
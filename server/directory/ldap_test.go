@@ -0,0 +1,273 @@
+package directory
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestLDAPConfig(t *testing.T) {
+	for _, bad := range []string{"uid=alice", "(uid=alice", `(cn=\zz)`, "(uid=a)(uid=b)"} {
+		t.Run("rejects the filter "+bad, func(t *testing.T) {
+			cfg := LDAPConfig{URL: "ldaps://ldap.example.com", BaseDN: "dc=example", Filter: bad}
+
+			assert.Error(t, cfg.validate())
+		})
+	}
+}
+
+func TestLDAPSource(t *testing.T) {
+	t.Run("binds over StartTLS and pages through users", func(t *testing.T) {
+		tc := newLDAPTestContext(t)
+
+		// Given
+		tc.an_ldap_server(false, ldap.LDAPResultSuccess, [][]ldapTestEntry{
+			{
+				{"uid": {"alice"}, "memberOf": {"cn=eng,dc=example"}},
+				{"uid": {"bob"}, "userAccountControl": {"514"}},
+			},
+			{
+				{"uid": {"carol"}, "nsAccountLock": {"TRUE"}},
+				{"cn": {"no-username"}},
+			},
+		})
+
+		// When
+		tc.users_are_listed("ldap")
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, []User{
+			{Username: "alice", Active: true, Groups: []string{"cn=eng,dc=example"}},
+			{Username: "bob", Active: false},
+			{Username: "carol", Active: false},
+		}, tc.users)
+		tc.bound_over_tls("cn=bifrost,dc=example", "s3cret")
+		assert.Equal(t, 2, tc.searches)
+	})
+
+	t.Run("binds over LDAPS", func(t *testing.T) {
+		tc := newLDAPTestContext(t)
+
+		// Given
+		tc.an_ldaps_server([][]ldapTestEntry{{{"uid": {"alice"}}}})
+
+		// When
+		tc.users_are_listed("ldaps")
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, []User{{Username: "alice", Active: true}}, tc.users)
+		tc.bound_over_tls("cn=bifrost,dc=example", "s3cret")
+	})
+
+	t.Run("does not bind when the server refuses StartTLS", func(t *testing.T) {
+		tc := newLDAPTestContext(t)
+
+		// Given
+		tc.an_ldap_server(true, ldap.LDAPResultSuccess, nil)
+
+		// When
+		tc.users_are_listed("ldap")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "starttls")
+		assert.Empty(t, tc.boundPassword)
+	})
+
+	t.Run("returns error when the bind is rejected", func(t *testing.T) {
+		tc := newLDAPTestContext(t)
+
+		// Given
+		tc.an_ldap_server(false, ldap.LDAPResultInvalidCredentials, nil)
+
+		// When
+		tc.users_are_listed("ldap")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.True(t, ldap.IsErrorWithCode(tc.err, ldap.LDAPResultInvalidCredentials))
+	})
+}
+
+// --- Test Context ---
+
+type ldapTestEntry map[string][]string
+
+type ldapTestContext struct {
+	t *testing.T
+
+	addr          string
+	boundDN       string
+	boundPassword string
+	boundOverTLS  bool
+	searches      int
+	users         []User
+	err           error
+}
+
+func newLDAPTestContext(t *testing.T) *ldapTestContext {
+	t.Helper()
+	return &ldapTestContext{t: t}
+}
+
+// --- Fake Server ---
+
+// serveLDAP answers one connection: StartTLS unless refuseTLS, the bind
+// with bindResult, and each search with the next page of entries.
+func (tc *ldapTestContext) serveLDAP(listener net.Listener, config *tls.Config, refuseTLS bool, bindResult uint16, pages [][]ldapTestEntry) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { conn.Close() }()
+	_, encrypted := conn.(*tls.Conn)
+	reply := func(id int64, op *ber.Packet, controls ...ldap.Control) {
+		msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+		msg.AppendChild(op)
+		if len(controls) > 0 {
+			encoded := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "")
+			for _, control := range controls {
+				encoded.AppendChild(control.Encode())
+			}
+			msg.AppendChild(encoded)
+		}
+		_, _ = conn.Write(msg.Bytes())
+	}
+	result := func(tag ber.Tag, code uint16) *ber.Packet {
+		op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+		op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+		op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+		op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+		return op
+	}
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, _ := msg.Children[0].Value.(int64)
+		op := msg.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationExtendedRequest:
+			if refuseTLS {
+				reply(id, result(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError))
+				continue
+			}
+			reply(id, result(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess))
+			tlsConn := tls.Server(conn, config)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, encrypted = tlsConn, true
+		case ldap.ApplicationBindRequest:
+			tc.boundDN = string(op.Children[1].Data.Bytes())
+			tc.boundPassword = string(op.Children[2].Data.Bytes())
+			tc.boundOverTLS = encrypted
+			reply(id, result(ldap.ApplicationBindResponse, bindResult))
+		case ldap.ApplicationSearchRequest:
+			for _, entry := range pages[tc.searches] {
+				reply(id, searchEntry(entry))
+			}
+			tc.searches++
+			paging := ldap.NewControlPaging(0)
+			if tc.searches < len(pages) {
+				paging.SetCookie([]byte("next"))
+			}
+			reply(id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess), paging)
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func searchEntry(entry ldapTestEntry) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "uid=x,dc=example", ""))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	for name, values := range entry {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, v := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, ""))
+		}
+		attr.AppendChild(vals)
+		attrs.AppendChild(attr)
+	}
+	op.AppendChild(attrs)
+	return op
+}
+
+// selfSignedTLS returns a server config with a throwaway certificate for
+// 127.0.0.1.
+func selfSignedTLS(t *testing.T) *tls.Config {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}, MinVersion: tls.VersionTLS12}
+}
+
+// --- Given ---
+
+func (tc *ldapTestContext) an_ldap_server(refuseTLS bool, bindResult uint16, pages [][]ldapTestEntry) {
+	tc.t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(tc.t, err)
+	tc.t.Cleanup(func() { listener.Close() })
+	tc.addr = listener.Addr().String()
+	go tc.serveLDAP(listener, selfSignedTLS(tc.t), refuseTLS, bindResult, pages)
+}
+
+func (tc *ldapTestContext) an_ldaps_server(pages [][]ldapTestEntry) {
+	tc.t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", selfSignedTLS(tc.t))
+	require.NoError(tc.t, err)
+	tc.t.Cleanup(func() { listener.Close() })
+	tc.addr = listener.Addr().String()
+	go tc.serveLDAP(listener, nil, false, ldap.LDAPResultSuccess, pages)
+}
+
+// --- When ---
+
+func (tc *ldapTestContext) users_are_listed(scheme string) {
+	tc.t.Helper()
+	cfg := LDAPConfig{URL: scheme + "://" + tc.addr, BindDN: "cn=bifrost,dc=example", BaseDN: "dc=example", InsecureSkipVerify: true}
+	require.NoError(tc.t, cfg.validate())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tc.users, tc.err = NewLDAPSource(cfg, "s3cret").Users(ctx)
+}
+
+// --- Then ---
+
+func (tc *ldapTestContext) bound_over_tls(dn, password string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, dn, tc.boundDN)
+	assert.Equal(tc.t, password, tc.boundPassword)
+	assert.True(tc.t, tc.boundOverTLS)
+}
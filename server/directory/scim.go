@@ -0,0 +1,113 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const scimPageSize = 100
+
+// SCIMConfig configures a SCIM 2.0 service provider to read users from.
+type SCIMConfig struct {
+	// URL is the SCIM base URL, e.g. https://idp.example.com/scim/v2.
+	URL      string `yaml:"url"`
+	TokenEnv string `yaml:"token_env"`
+}
+
+// SCIMSource pages through /Users. A user's groups are the display names
+// and IDs of its groups attribute, so rules may use either.
+type SCIMSource struct {
+	cfg    SCIMConfig
+	token  string
+	client *http.Client
+}
+
+func NewSCIMSource(cfg SCIMConfig, token string, client *http.Client) *SCIMSource {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &SCIMSource{cfg: cfg, token: token, client: client}
+}
+
+func (s *SCIMSource) Name() string {
+	return "scim"
+}
+
+type scimListResponse struct {
+	TotalResults int        `json:"totalResults"`
+	Resources    []scimUser `json:"Resources"`
+}
+
+type scimUser struct {
+	UserName string `json:"userName"`
+	// Active defaults to true when the provider omits it.
+	Active *bool `json:"active"`
+	Groups []struct {
+		Value   string `json:"value"`
+		Display string `json:"display"`
+	} `json:"groups"`
+}
+
+func (s *SCIMSource) Users(ctx context.Context) ([]User, error) {
+	var users []User
+	for start := 1; ; {
+		page, err := s.fetchPage(ctx, start)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.Resources {
+			if u.UserName == "" {
+				continue
+			}
+			user := User{Username: u.UserName, Active: u.Active == nil || *u.Active}
+			for _, g := range u.Groups {
+				if g.Display != "" {
+					user.Groups = append(user.Groups, g.Display)
+				}
+				if g.Value != "" {
+					user.Groups = append(user.Groups, g.Value)
+				}
+			}
+			users = append(users, user)
+		}
+		start += len(page.Resources)
+		if len(page.Resources) == 0 || start > page.TotalResults {
+			return users, nil
+		}
+	}
+}
+
+func (s *SCIMSource) fetchPage(ctx context.Context, start int) (scimListResponse, error) {
+	query := url.Values{}
+	query.Set("startIndex", strconv.Itoa(start))
+	query.Set("count", strconv.Itoa(scimPageSize))
+	endpoint := strings.TrimSuffix(s.cfg.URL, "/") + "/Users?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return scimListResponse{}, err
+	}
+	req.Header.Set("Accept", "application/scim+json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return scimListResponse{}, fmt.Errorf("scim: list users: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return scimListResponse{}, fmt.Errorf("scim: list users: unexpected status %d", resp.StatusCode)
+	}
+	var page scimListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return scimListResponse{}, fmt.Errorf("scim: decode users: %w", err)
+	}
+	return page, nil
+}
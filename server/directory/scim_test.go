@@ -0,0 +1,94 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSCIMSource(t *testing.T) {
+	t.Run("pages through users with a bearer token", func(t *testing.T) {
+		tc := newSCIMTestContext(t)
+
+		// Given
+		tc.a_scim_server(http.StatusOK, map[string]string{
+			"1": `{"totalResults":3,"Resources":[
+				{"userName":"alice","groups":[{"value":"g-1","display":"Engineering"}]},
+				{"userName":"bob","active":false}]}`,
+			"3": `{"totalResults":3,"Resources":[{"userName":"carol","active":true}]}`,
+		})
+
+		// When
+		tc.users_are_listed()
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, []User{
+			{Username: "alice", Active: true, Groups: []string{"Engineering", "g-1"}},
+			{Username: "bob", Active: false},
+			{Username: "carol", Active: true},
+		}, tc.users)
+		assert.Equal(t, []string{"Bearer scim-token", "Bearer scim-token"}, tc.authHeaders)
+	})
+
+	t.Run("returns error on a non-200 response", func(t *testing.T) {
+		tc := newSCIMTestContext(t)
+
+		// Given
+		tc.a_scim_server(http.StatusUnauthorized, nil)
+
+		// When
+		tc.users_are_listed()
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "unexpected status 401")
+	})
+}
+
+// --- Test Context ---
+
+type scimTestContext struct {
+	t *testing.T
+
+	server      *httptest.Server
+	authHeaders []string
+	users       []User
+	err         error
+}
+
+func newSCIMTestContext(t *testing.T) *scimTestContext {
+	t.Helper()
+	return &scimTestContext{t: t}
+}
+
+// --- Given ---
+
+// a_scim_server answers /scim/v2/Users with the page for each startIndex.
+func (tc *scimTestContext) a_scim_server(status int, pages map[string]string) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.authHeaders = append(tc.authHeaders, r.Header.Get("Authorization"))
+		if r.URL.Path != "/scim/v2/Users" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("startIndex")]))
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+// --- When ---
+
+func (tc *scimTestContext) users_are_listed() {
+	tc.t.Helper()
+	source := NewSCIMSource(SCIMConfig{URL: tc.server.URL + "/scim/v2/"}, "scim-token", nil)
+	tc.users, tc.err = source.Users(context.Background())
+}
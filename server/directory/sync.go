@@ -0,0 +1,252 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Report lists what a sync changed and what it had to leave alone.
+type Report struct {
+	Changes []string
+	Skipped []string
+}
+
+type Syncer struct {
+	source          Source
+	rules           []Rule
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+}
+
+func NewSyncer(source Source, rules []Rule, eventStore core.EventStore, projectionStore core.ProjectionStore) *Syncer {
+	return &Syncer{
+		source:          source,
+		rules:           rules,
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+	}
+}
+
+// Run syncs immediately and then every interval until ctx is done.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := s.Sync(ctx)
+		for _, change := range report.Changes {
+			log.Printf("directory sync (%s): %s", s.source.Name(), change)
+		}
+		for _, skipped := range report.Skipped {
+			log.Printf("directory sync (%s): skipped: %s", s.source.Name(), skipped)
+		}
+		if err != nil {
+			log.Printf("directory sync (%s): %v", s.source.Name(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type grant struct {
+	realmID string
+	role    string
+}
+
+// Sync applies the directory to the accounts it manages once. It reads the
+// account and realm projections, so they should be caught up.
+func (s *Syncer) Sync(ctx context.Context) (Report, error) {
+	var report Report
+
+	users, err := s.source.Users(ctx)
+	if err != nil {
+		return report, err
+	}
+	// An empty result is far more likely a bad filter or endpoint than an
+	// empty directory, and would suspend every managed account.
+	if len(users) == 0 {
+		return report, fmt.Errorf("directory returned no users; nothing was changed")
+	}
+
+	grantsByGroup, mappedRealms, err := s.resolveRules(ctx, &report)
+	if err != nil {
+		return report, err
+	}
+	accounts, err := s.listAccounts(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	seen := map[string]bool{}
+	for _, user := range users {
+		seen[user.Username] = true
+		desired := map[string]string{}
+		for _, group := range user.Groups {
+			for _, g := range grantsByGroup[strings.ToLower(group)] {
+				if domain.RoleLevel(g.role) > domain.RoleLevel(desired[g.realmID]) {
+					desired[g.realmID] = g.role
+				}
+			}
+		}
+
+		entry, exists := accounts[user.Username]
+		if exists && entry.Source != AccountSource {
+			if len(desired) > 0 {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("account %q was not created by the directory", user.Username))
+			}
+			continue
+		}
+
+		if !user.Active {
+			if exists && entry.Status == "active" {
+				if err := s.suspend(ctx, entry, "disabled in directory"); err != nil {
+					return report, err
+				}
+				report.Changes = append(report.Changes, fmt.Sprintf("suspended %q (disabled in directory)", user.Username))
+			}
+			continue
+		}
+
+		if !exists {
+			if len(desired) == 0 {
+				continue
+			}
+			result, err := domain.HandleCreateAccount(ctx, domain.CreateAccount{Username: user.Username, Source: AccountSource}, s.eventStore, s.projectionStore)
+			if err != nil {
+				return report, fmt.Errorf("create account %q: %w", user.Username, err)
+			}
+			entry = projectors.AccountListEntry{AccountID: result.AccountID, Username: user.Username, Status: "active"}
+			report.Changes = append(report.Changes, fmt.Sprintf("created account %q", user.Username))
+		}
+		if entry.Status != "active" {
+			if len(desired) > 0 {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("account %q is %s", user.Username, entry.Status))
+			}
+			continue
+		}
+
+		changes, err := s.applyRoles(ctx, entry, desired, mappedRealms)
+		report.Changes = append(report.Changes, changes...)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	usernames := make([]string, 0, len(accounts))
+	for username := range accounts {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	for _, username := range usernames {
+		entry := accounts[username]
+		if seen[username] || entry.Source != AccountSource || entry.Status != "active" {
+			continue
+		}
+		if err := s.suspend(ctx, entry, "removed from directory"); err != nil {
+			return report, err
+		}
+		report.Changes = append(report.Changes, fmt.Sprintf("suspended %q (removed from directory)", username))
+	}
+	return report, nil
+}
+
+// resolveRules indexes rules by lower-cased group, resolving realm names to
+// IDs. Rules whose realm cannot be resolved are reported and ignored.
+func (s *Syncer) resolveRules(ctx context.Context, report *Report) (map[string][]grant, []string, error) {
+	raws, err := s.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := map[string]bool{domain.AdminRealmID: true}
+	byName := map[string][]string{}
+	for _, raw := range raws {
+		var entry projectors.RealmListEntry
+		if json.Unmarshal(raw, &entry) == nil && entry.RealmID != "" {
+			byID[entry.RealmID] = true
+			byName[entry.Name] = append(byName[entry.Name], entry.RealmID)
+		}
+	}
+
+	grants := map[string][]grant{}
+	mapped := map[string]bool{}
+	for _, rule := range s.rules {
+		realmID := rule.Realm
+		if !byID[realmID] {
+			if ids := byName[rule.Realm]; len(ids) == 1 {
+				realmID = ids[0]
+			} else {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("rule for group %q: realm %q matches %d realms", rule.Group, rule.Realm, len(ids)))
+				continue
+			}
+		}
+		group := strings.ToLower(rule.Group)
+		grants[group] = append(grants[group], grant{realmID: realmID, role: rule.Role})
+		mapped[realmID] = true
+	}
+
+	realms := make([]string, 0, len(mapped))
+	for realmID := range mapped {
+		realms = append(realms, realmID)
+	}
+	sort.Strings(realms)
+	return grants, realms, nil
+}
+
+func (s *Syncer) listAccounts(ctx context.Context) (map[string]projectors.AccountListEntry, error) {
+	raws, err := s.projectionStore.List(ctx, domain.AdminRealmID, "account_list")
+	if err != nil {
+		return nil, err
+	}
+	accounts := map[string]projectors.AccountListEntry{}
+	for _, raw := range raws {
+		var entry projectors.AccountListEntry
+		if json.Unmarshal(raw, &entry) == nil && entry.AccountID != "" {
+			accounts[entry.Username] = entry
+		}
+	}
+	return accounts, nil
+}
+
+// applyRoles sets the account's role in every mapped realm to the desired
+// one, revoking roles no rule grants. Realms no rule maps are left alone.
+func (s *Syncer) applyRoles(ctx context.Context, entry projectors.AccountListEntry, desired map[string]string, mappedRealms []string) ([]string, error) {
+	var changes []string
+	for _, realmID := range mappedRealms {
+		want, have := desired[realmID], entry.Roles[realmID]
+		switch {
+		case want == have:
+		case want == "":
+			if err := domain.HandleRevokeRole(ctx, domain.RevokeRole{AccountID: entry.AccountID, RealmID: realmID}, s.eventStore); err != nil {
+				return changes, fmt.Errorf("revoke role of %q in realm %s: %w", entry.Username, realmID, err)
+			}
+			changes = append(changes, fmt.Sprintf("revoked %s from %q in realm %s", have, entry.Username, realmID))
+		default:
+			if err := domain.HandleAssignRole(ctx, domain.AssignRole{AccountID: entry.AccountID, RealmID: realmID, Role: want}, s.eventStore); err != nil {
+				return changes, fmt.Errorf("assign %s to %q in realm %s: %w", want, entry.Username, realmID, err)
+			}
+			changes = append(changes, fmt.Sprintf("assigned %s to %q in realm %s", want, entry.Username, realmID))
+		}
+	}
+	return changes, nil
+}
+
+func (s *Syncer) suspend(ctx context.Context, entry projectors.AccountListEntry, reason string) error {
+	err := domain.HandleSuspendAccount(ctx, domain.SuspendAccount{AccountID: entry.AccountID, Reason: reason}, s.eventStore)
+	if err != nil {
+		return fmt.Errorf("suspend %q: %w", entry.Username, err)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSyncer(t *testing.T) {
+	t.Run("creates accounts for users granted access with the highest matching role", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "platform")
+		tc.rules(Rule{Group: "Engineering", Realm: "platform", Role: "member"}, Rule{Group: "Leads", Realm: "realm-1", Role: "admin"})
+		tc.directory_users(
+			User{Username: "alice", Active: true, Groups: []string{"engineering", "leads"}},
+			User{Username: "bob", Active: true, Groups: []string{"Sales"}},
+		)
+
+		// When
+		tc.sync()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`created account "alice"`, `assigned admin to "alice" in realm realm-1`}, tc.report.Changes)
+		tc.appended_types_are(domain.EventAccountCreated, domain.EventPATCreated, domain.EventRoleAssigned)
+		assert.Equal(t, AccountSource, tc.appended_data(domain.EventAccountCreated)["source"])
+	})
+
+	t.Run("follows group changes in mapped realms only", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "platform")
+		tc.a_realm("realm-2", "docs")
+		tc.a_managed_account("acct-1", "alice", "active", map[string]string{"realm-1": "member", "realm-2": "viewer", "realm-3": "owner"})
+		tc.rules(Rule{Group: "eng", Realm: "platform", Role: "member"}, Rule{Group: "leads", Realm: "platform", Role: "admin"}, Rule{Group: "writers", Realm: "docs", Role: "member"})
+		tc.directory_users(User{Username: "alice", Active: true, Groups: []string{"eng", "leads"}})
+
+		// When
+		tc.sync()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{
+			`assigned admin to "alice" in realm realm-1`,
+			`revoked viewer from "alice" in realm realm-2`,
+		}, tc.report.Changes)
+		tc.appended_types_are(domain.EventRoleAssigned, domain.EventRoleRevoked)
+	})
+
+	t.Run("suspends managed accounts that are disabled or removed", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "platform")
+		tc.a_managed_account("acct-1", "alice", "active", nil)
+		tc.a_managed_account("acct-2", "bob", "active", nil)
+		tc.a_managed_account("acct-3", "carol", "active", nil)
+		tc.rules(Rule{Group: "eng", Realm: "platform", Role: "member"})
+		tc.directory_users(User{Username: "alice", Active: false}, User{Username: "carol", Active: true})
+
+		// When
+		tc.sync()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`suspended "alice" (disabled in directory)`, `suspended "bob" (removed from directory)`}, tc.report.Changes)
+		tc.appended_types_are(domain.EventAccountSuspended, domain.EventAccountSuspended)
+	})
+
+	t.Run("leaves accounts it did not create alone", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "platform")
+		tc.an_account("acct-1", "alice", "", "active", nil)
+		tc.an_account("acct-2", "bob", "", "active", nil)
+		tc.rules(Rule{Group: "eng", Realm: "platform", Role: "member"})
+		tc.directory_users(User{Username: "alice", Active: false, Groups: []string{"eng"}})
+
+		// When
+		tc.sync()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.report.Changes)
+		assert.Equal(t, []string{`account "alice" was not created by the directory`}, tc.report.Skipped)
+		tc.appended_types_are()
+	})
+
+	t.Run("skips rules whose realm cannot be resolved", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_realm("realm-1", "platform")
+		tc.a_realm("realm-2", "platform")
+		tc.rules(Rule{Group: "eng", Realm: "platform", Role: "member"})
+		tc.directory_users(User{Username: "alice", Active: true, Groups: []string{"eng"}})
+
+		// When
+		tc.sync()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`rule for group "eng": realm "platform" matches 2 realms`}, tc.report.Skipped)
+		tc.appended_types_are()
+	})
+
+	t.Run("refuses to apply an empty directory", func(t *testing.T) {
+		tc := newSyncTestContext(t)
+
+		// Given
+		tc.a_managed_account("acct-1", "alice", "active", nil)
+		tc.rules(Rule{Group: "eng", Realm: "platform", Role: "member"})
+		tc.directory_users()
+
+		// When
+		tc.sync()
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "directory returned no users")
+		tc.appended_types_are()
+	})
+}
+
+// --- Test Context ---
+
+type syncTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	source          *fakeSource
+	ruleList        []Rule
+	report          Report
+	err             error
+}
+
+func newSyncTestContext(t *testing.T) *syncTestContext {
+	t.Helper()
+	return &syncTestContext{
+		t:               t,
+		eventStore:      &mockEventStore{streams: make(map[string][]core.Event)},
+		projectionStore: &mockProjectionStore{data: make(map[string]map[string]json.RawMessage)},
+		source:          &fakeSource{},
+	}
+}
+
+// --- Given ---
+
+func (tc *syncTestContext) a_realm(realmID, name string) {
+	tc.t.Helper()
+	tc.projectionStore.put(domain.AdminRealmID, "realm_list", realmID, projectors.RealmListEntry{RealmID: realmID, Name: name, Status: "active"})
+}
+
+func (tc *syncTestContext) a_managed_account(accountID, username, status string, roles map[string]string) {
+	tc.t.Helper()
+	tc.an_account(accountID, username, AccountSource, status, roles)
+}
+
+func (tc *syncTestContext) an_account(accountID, username, source, status string, roles map[string]string) {
+	tc.t.Helper()
+	tc.projectionStore.put(domain.AdminRealmID, "account_list", accountID, projectors.AccountListEntry{
+		AccountID: accountID, Username: username, Source: source, Status: status, Roles: roles,
+	})
+	tc.eventStore.seed(domain.AdminRealmID, "account-"+accountID, domain.EventAccountCreated, domain.AccountCreated{AccountID: accountID, Username: username, Source: source})
+	for realmID, role := range roles {
+		tc.eventStore.seed(domain.AdminRealmID, "account-"+accountID, domain.EventRoleAssigned, domain.RoleAssigned{AccountID: accountID, RealmID: realmID, Role: role})
+	}
+}
+
+func (tc *syncTestContext) rules(rules ...Rule) {
+	tc.t.Helper()
+	tc.ruleList = rules
+}
+
+func (tc *syncTestContext) directory_users(users ...User) {
+	tc.t.Helper()
+	tc.source.users = users
+}
+
+// --- When ---
+
+func (tc *syncTestContext) sync() {
+	tc.t.Helper()
+	syncer := NewSyncer(tc.source, tc.ruleList, tc.eventStore, tc.projectionStore)
+	tc.report, tc.err = syncer.Sync(context.Background())
+}
+
+// --- Then ---
+
+func (tc *syncTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *syncTestContext) appended_types_are(expected ...string) {
+	tc.t.Helper()
+	var types []string
+	for _, e := range tc.eventStore.appended {
+		types = append(types, e.EventType)
+	}
+	assert.Equal(tc.t, expected, types)
+}
+
+func (tc *syncTestContext) appended_data(eventType string) map[string]any {
+	tc.t.Helper()
+	for _, e := range tc.eventStore.appended {
+		if e.EventType == eventType {
+			var data map[string]any
+			require.NoError(tc.t, json.Unmarshal(e.Data, &data))
+			return data
+		}
+	}
+	tc.t.Fatalf("no %s event appended", eventType)
+	return nil
+}
+
+// --- Fake Source ---
+
+type fakeSource struct {
+	users []User
+}
+
+func (f *fakeSource) Name() string {
+	return "fake"
+}
+
+func (f *fakeSource) Users(_ context.Context) ([]User, error) {
+	return f.users, nil
+}
+
+// --- Mock Event Store ---
+
+type mockEventStore struct {
+	streams  map[string][]core.Event
+	appended []core.Event
+}
+
+func (m *mockEventStore) seed(realmID, streamID, eventType string, data any) {
+	raw, _ := json.Marshal(data)
+	key := realmID + ":" + streamID
+	m.streams[key] = append(m.streams[key], core.Event{
+		RealmID: realmID, StreamID: streamID, Version: len(m.streams[key]), EventType: eventType, Data: raw,
+	})
+}
+
+func (m *mockEventStore) Append(_ context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	key := realmID + ":" + streamID
+	if len(m.streams[key]) != expectedVersion {
+		return nil, &core.ConcurrencyError{StreamID: streamID, ExpectedVersion: expectedVersion, ActualVersion: len(m.streams[key])}
+	}
+	var result []core.Event
+	for _, e := range events {
+		raw, err := json.Marshal(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		event := core.Event{RealmID: realmID, StreamID: streamID, Version: len(m.streams[key]), EventType: e.EventType, Data: raw}
+		m.streams[key] = append(m.streams[key], event)
+		m.appended = append(m.appended, event)
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (m *mockEventStore) ReadStream(_ context.Context, realmID string, streamID string, _ int) ([]core.Event, error) {
+	return m.streams[realmID+":"+streamID], nil
+}
+
+func (m *mockEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (m *mockEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]map[string]json.RawMessage
+}
+
+func (m *mockProjectionStore) put(realmID, projectionName, key string, value any) {
+	raw, _ := json.Marshal(value)
+	name := realmID + ":" + projectionName
+	if m.data[name] == nil {
+		m.data[name] = make(map[string]json.RawMessage)
+	}
+	m.data[name][key] = raw
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	raw, ok := m.data[realmID+":"+projectionName][key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	return json.Unmarshal(raw, dest)
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	entries := m.data[realmID+":"+projectionName]
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var result []json.RawMessage
+	for _, key := range keys {
+		result = append(result, entries[key])
+	}
+	return result, nil
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.put(realmID, projectionName, key, value)
+	return nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data[realmID+":"+projectionName], key)
+	return nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+const (
+	// duplicateSearchLimit is how many search hits are compared with a new
+	// rune, and maxDuplicates how many of them are reported.
+	duplicateSearchLimit = 20
+	maxDuplicates        = 5
+	// minDuplicateSimilarity is the share of words a candidate must have in
+	// common with the new rune. The search matches any word, so its hits
+	// alone are too loose to report.
+	minDuplicateSimilarity = 0.5
+)
+
+// duplicateStatuses are the statuses of runes a new rune may duplicate.
+var duplicateStatuses = []string{"draft", "open", "claimed"}
+
+// DuplicateCandidate is an unfinished rune that looks like a new one.
+type DuplicateCandidate struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	Similarity float64 `json:"similarity"`
+}
+
+// CreateRuneResponse is the created rune, with the unfinished runes it may
+// duplicate.
+type CreateRuneResponse struct {
+	domain.RuneCreated
+	Duplicates []DuplicateCandidate `json:"duplicates,omitempty"`
+}
+
+// findDuplicates searches the realm for unfinished runes whose title, or
+// title and description, share most of their words with the created rune.
+// Duplicates are advice, so a failing search index yields none.
+func (h *Handlers) findDuplicates(ctx context.Context, realmID string, created domain.RuneCreated) []DuplicateCandidate {
+	if h.search == nil {
+		return nil
+	}
+	hits, err := h.search.Search(ctx, core.SearchQuery{
+		RealmID:  realmID,
+		Text:     strings.TrimSpace(created.Title + " " + created.Description),
+		Statuses: duplicateStatuses,
+		Limit:    duplicateSearchLimit,
+	})
+	if err != nil {
+		return nil
+	}
+
+	title := wordSet(created.Title)
+	full := wordSet(created.Title + " " + created.Description)
+	candidates := []DuplicateCandidate{}
+	for _, hit := range hits {
+		if hit.ID == created.ID {
+			continue
+		}
+		var detail projectors.RuneDetail
+		if err := h.projectionStore.Get(ctx, realmID, "rune_detail", hit.ID, &detail); err != nil {
+			continue
+		}
+		similarity := diceSimilarity(title, wordSet(detail.Title))
+		if created.Description != "" && detail.Description != "" {
+			similarity = max(similarity, diceSimilarity(full, wordSet(detail.Title+" "+detail.Description)))
+		}
+		if similarity < minDuplicateSimilarity {
+			continue
+		}
+		candidates = append(candidates, DuplicateCandidate{ID: detail.ID, Title: detail.Title, Status: detail.Status, Similarity: similarity})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	if len(candidates) > maxDuplicates {
+		candidates = candidates[:maxDuplicates]
+	}
+	return candidates
+}
+
+// wordSet returns the lowercased words of s, leaving out words shorter than
+// three letters.
+func wordSet(s string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// diceSimilarity is twice the number of shared words over the total number
+// of words, from 0 for nothing shared to 1 for the same words.
+func diceSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
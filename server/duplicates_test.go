@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCreateRuneDuplicates(t *testing.T) {
+	t.Run("returns unfinished runes with similar titles", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+		index := tc.a_search_index(
+			core.SearchHit{ID: "bf-a1", Score: 3},
+			core.SearchHit{ID: "bf-a2", Score: 2},
+		)
+		tc.rune_detail_is("realm-1", "bf-a1", "Login page crashes on submit", "", "open")
+		tc.rune_detail_is("realm-1", "bf-a2", "Submit button colour", "", "claimed")
+		tc.handlers_configured()
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Login page crashes", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		resp := tc.create_rune_response()
+		require.Len(t, resp.Duplicates, 1)
+		assert.Equal(t, "bf-a1", resp.Duplicates[0].ID)
+		assert.Equal(t, "open", resp.Duplicates[0].Status)
+		assert.InDelta(t, 6.0/7, resp.Duplicates[0].Similarity, 0.001)
+		assert.Equal(t, []string{"draft", "open", "claimed"}, index.query.Statuses)
+		assert.Equal(t, "Login page crashes", index.query.Text)
+	})
+
+	t.Run("compares descriptions when both runes have one", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+		tc.a_search_index(core.SearchHit{ID: "bf-a1", Score: 1})
+		tc.rune_detail_is("realm-1", "bf-a1", "Broken signin", "The login form crashes when the password field is submitted empty", "open")
+		tc.handlers_configured()
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{
+			Title:       "Crash in login form",
+			Description: "Submitting the login form with an empty password field crashes",
+			Priority:    1,
+			Branch:      strPtr("main"),
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		resp := tc.create_rune_response()
+		require.Len(t, resp.Duplicates, 1)
+		assert.Equal(t, "bf-a1", resp.Duplicates[0].ID)
+	})
+
+	t.Run("creates the rune without duplicates when the search index fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+		index := tc.a_search_index()
+		index.err = assert.AnError
+		tc.handlers_configured()
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Login page crashes", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		assert.Empty(t, tc.create_rune_response().Duplicates)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) rune_detail_is(realmID, runeID, title, description, status string) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_detail", runeID, projectors.RuneDetail{
+		ID: runeID, Title: title, Description: description, Status: status,
+	})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) create_rune_response() CreateRuneResponse {
+	tc.t.Helper()
+	var resp CreateRuneResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
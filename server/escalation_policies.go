@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+func (h *Handlers) AddEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.AddEscalationPolicy
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	result, err := domain.HandleAddEscalationPolicy(r.Context(), cmd, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (h *Handlers) RemoveEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.RemoveEscalationPolicy
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	if err := domain.HandleRemoveEscalationPolicy(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListEscalationPolicies returns the realm's policies ordered by name.
+func (h *Handlers) ListEscalationPolicies(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "escalation_policies")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list escalation policies")
+		return
+	}
+	policies := []projectors.EscalationPolicyEntry{}
+	for _, raw := range raws {
+		var policy projectors.EscalationPolicyEntry
+		if json.Unmarshal(raw, &policy) == nil && policy.ID != "" {
+			policies = append(policies, policy)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	writeJSON(w, http.StatusOK, policies)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAddEscalationPolicyHandler(t *testing.T) {
+	t.Run("adds policy to the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/add-escalation-policy", domain.AddEscalationPolicy{
+			RealmID: "realm-other", Name: "Stale claims", Trigger: domain.EscalationTriggerStaleClaim,
+			After: "5d", Action: domain.EscalationActionUnclaim,
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.response_body_has_field("policy_id")
+		added := tc.only_added_escalation_policy()
+		assert.Equal(t, "realm-1", added.RealmID)
+		assert.Equal(t, "5d", added.After)
+	})
+
+	t.Run("returns 400 for an invalid policy", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/add-escalation-policy", domain.AddEscalationPolicy{
+			Name: "Overdue", Trigger: domain.EscalationTriggerOverdue, Action: domain.EscalationActionUnclaim,
+		})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("needs the stale_claim trigger")
+	})
+}
+
+func TestRemoveEscalationPolicyHandler(t *testing.T) {
+	t.Run("removes policy from the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.escalation_policy_exists_in_event_store("ep-1", "realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-escalation-policy", map[string]string{"policy_id": "ep-1"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("returns 404 for a policy in another realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.escalation_policy_exists_in_event_store("ep-1", "realm-2")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-escalation-policy", map[string]string{"policy_id": "ep-1"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestListEscalationPoliciesHandler(t *testing.T) {
+	t.Run("lists the realm's policies by name", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.projection_has_escalation_policy("realm-1", "ep-2", "Stale claims")
+		tc.projection_has_escalation_policy("realm-1", "ep-1", "Overdue")
+		tc.projection_has_escalation_policy("realm-2", "ep-3", "Other realm")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/escalation-policies")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var policies []projectors.EscalationPolicyEntry
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &policies))
+		require.Len(t, policies, 2)
+		assert.Equal(t, "Overdue", policies[0].Name)
+		assert.Equal(t, "Stale claims", policies[1].Name)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) escalation_policy_exists_in_event_store(policyID, realmID string) {
+	tc.t.Helper()
+	added := domain.EscalationPolicyAdded{
+		PolicyID: policyID, RealmID: realmID, Name: "Overdue", Trigger: domain.EscalationTriggerOverdue,
+		Action: domain.EscalationActionNotifyAdmins,
+	}
+	tc.eventStore.appendToStream(domain.AdminRealmID, "escalation-policy-"+policyID, domain.EventEscalationPolicyAdded, added)
+}
+
+func (tc *handlerTestContext) projection_has_escalation_policy(realmID, policyID, name string) {
+	tc.t.Helper()
+	entry := projectors.EscalationPolicyEntry{ID: policyID, RealmID: realmID, Name: name, Trigger: domain.EscalationTriggerOverdue}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "escalation_policies", policyID, entry)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) only_added_escalation_policy() domain.EscalationPolicyAdded {
+	tc.t.Helper()
+	var found []domain.EscalationPolicyAdded
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType != domain.EventEscalationPolicyAdded {
+				continue
+			}
+			var added domain.EscalationPolicyAdded
+			require.NoError(tc.t, json.Unmarshal(evt.Data, &added))
+			found = append(found, added)
+		}
+	}
+	require.Len(tc.t, found, 1)
+	return found[0]
+}
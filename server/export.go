@@ -0,0 +1,43 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+type exportRealmRequest struct {
+	RealmID string `json:"realm_id"`
+}
+
+// ExportRealm streams every event of a realm as newline-delimited JSON, one
+// core.ExportedEvent per line, for backing the realm up or moving it out.
+// Once the stream has started a failure can only cut it short, so it is
+// logged instead.
+func (h *Handlers) ExportRealm(w http.ResponseWriter, r *http.Request) {
+	var req exportRealmRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.RealmID == "" {
+		writeError(w, http.StatusBadRequest, "realm_id is required")
+		return
+	}
+	realmIDs, err := h.eventStore.ListRealmIDs(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !slices.Contains(realmIDs, req.RealmID) {
+		writeError(w, http.StatusNotFound, "realm not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+req.RealmID+`.ndjson"`)
+	if _, err := core.ExportRealm(r.Context(), h.eventStore, req.RealmID, w); err != nil {
+		log.Printf("export realm %s: %v", req.RealmID, err)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestExportRealmHandler(t *testing.T) {
+	t.Run("streams the realm's events as newline-delimited JSON", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.history_has_realm("bf-1234", "Team A")
+		tc.history_has_event("bf-1234", "rune-bf-1", `{"title":"Bridge"}`)
+		tc.history_has_event("bf-5678", "rune-bf-2", `{"title":"Other"}`)
+		tc.export_configured()
+
+		// When
+		tc.post("/export-realm", map[string]any{"realm_id": "bf-1234"})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, "application/x-ndjson", tc.recorder.Header().Get("Content-Type"))
+		events := tc.exported_events()
+		require.Len(t, events, 1)
+		assert.Equal(t, "rune-bf-1", events[0].StreamID)
+		assert.Equal(t, int64(2), events[0].GlobalPosition)
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(events[0].Data))
+	})
+
+	t.Run("returns 404 for a realm without events", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.export_configured()
+
+		// When
+		tc.post("/export-realm", map[string]any{"realm_id": "bf-404"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 400 without a realm ID", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.export_configured()
+
+		// When
+		tc.post("/export-realm", map[string]any{})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) history_has_event(realmID, streamID, data string) {
+	tc.t.Helper()
+	tc.history = append(tc.history, core.Event{
+		RealmID:        realmID,
+		StreamID:       streamID,
+		Version:        1,
+		GlobalPosition: int64(len(tc.history) + 1),
+		EventType:      "RuneCreated",
+		Data:           []byte(data),
+	})
+}
+
+func (tc *handlerTestContext) export_configured() {
+	tc.t.Helper()
+	history := &historyEventStore{mockEventStore: tc.eventStore, events: tc.history}
+	tc.handlers = NewHandlers(history, tc.projectionStore, tc.engine)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) exported_events() []core.ExportedEvent {
+	tc.t.Helper()
+	var events []core.ExportedEvent
+	scanner := bufio.NewScanner(bytes.NewReader(tc.recorder.Body.Bytes()))
+	for scanner.Scan() {
+		var evt core.ExportedEvent
+		require.NoError(tc.t, json.Unmarshal(scanner.Bytes(), &evt))
+		events = append(events, evt)
+	}
+	require.NoError(tc.t, scanner.Err())
+	return events
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Features lists every feature flag with its value in the request realm, so
+// clients can show or hide experimental features. Admins override a flag by
+// setting "feature.<name>" to true or false through /set-realm-setting.
+func (h *Handlers) Features(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	settings, err := projectors.GetRealmSettings(r.Context(), h.projectionStore, realmID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get realm settings")
+		return
+	}
+	writeJSON(w, http.StatusOK, domain.FeatureStates(settings))
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestFeaturesHandler(t *testing.T) {
+	t.Run("returns defaults when the realm overrides nothing", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/features")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		state := tc.feature_state(domain.FeatureStrictBlocking)
+		assert.False(t, state.Enabled)
+		assert.False(t, state.Overridden)
+	})
+
+	t.Run("applies the realm's overrides", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_realm_settings("realm-1", map[string]string{"feature.strict_blocking": "true"})
+
+		// When
+		tc.get("/features")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		state := tc.feature_state(domain.FeatureStrictBlocking)
+		assert.True(t, state.Enabled)
+		assert.True(t, state.Overridden)
+	})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) feature_state(name string) domain.FeatureState {
+	tc.t.Helper()
+	var states []domain.FeatureState
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &states))
+	for _, state := range states {
+		if state.Name == name {
+			return state
+		}
+	}
+	require.Failf(tc.t, "feature not listed", "no %q in %s", name, tc.recorder.Body.String())
+	return domain.FeatureState{}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// Form drafts let the admin UI autosave a create or edit form while it is
+// being filled in, so a long description is not lost to a closed tab. They
+// are kept per account and realm, and only the owning account sees them.
+
+// maxFormKeyLength bounds form keys, which name a form and at most the rune
+// it edits.
+const maxFormKeyLength = 200
+
+// WithDraftStore enables the form draft endpoints.
+func WithDraftStore(s core.DraftStore) HandlersOption {
+	return func(h *Handlers) {
+		h.drafts = s
+	}
+}
+
+type saveDraftRequest struct {
+	Form   string          `json:"form"`
+	Fields json.RawMessage `json:"fields"`
+}
+
+type discardDraftRequest struct {
+	Form string `json:"form"`
+}
+
+// draftCaller returns the realm and account a draft request acts for,
+// writing an error response when either is missing or drafts are disabled.
+func (h *Handlers) draftCaller(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	if h.drafts == nil {
+		writeError(w, http.StatusNotFound, "form drafts are not enabled")
+		return "", "", false
+	}
+	return savedSearchCaller(w, r)
+}
+
+func validFormKey(w http.ResponseWriter, form string) bool {
+	if form == "" {
+		writeError(w, http.StatusBadRequest, "form is required")
+		return false
+	}
+	if len(form) > maxFormKeyLength {
+		writeError(w, http.StatusBadRequest, "form must be at most 200 characters")
+		return false
+	}
+	return true
+}
+
+// SaveDraft replaces the caller's draft of a form.
+func (h *Handlers) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := h.draftCaller(w, r)
+	if !ok {
+		return
+	}
+	var req saveDraftRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if !validFormKey(w, req.Form) {
+		return
+	}
+	if fields := bytes.TrimSpace(req.Fields); len(fields) == 0 || fields[0] != '{' {
+		writeError(w, http.StatusBadRequest, "fields must be a JSON object")
+		return
+	}
+	err := h.drafts.SaveDraft(r.Context(), core.FormDraft{
+		AccountID: accountID,
+		RealmID:   realmID,
+		FormKey:   req.Form,
+		Fields:    req.Fields,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DiscardDraft deletes the caller's draft of a form, typically once the form
+// is submitted or abandoned.
+func (h *Handlers) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := h.draftCaller(w, r)
+	if !ok {
+		return
+	}
+	var req discardDraftRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if !validFormKey(w, req.Form) {
+		return
+	}
+	if err := h.drafts.DeleteDraft(r.Context(), accountID, realmID, req.Form); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDrafts returns the caller's draft of the form named by ?form=, or all
+// of the caller's drafts in the realm without it.
+func (h *Handlers) ListDrafts(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := h.draftCaller(w, r)
+	if !ok {
+		return
+	}
+	if form := r.URL.Query().Get("form"); form != "" {
+		draft, err := h.drafts.GetDraft(r.Context(), accountID, realmID, form)
+		if err != nil {
+			handleDomainError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, draft)
+		return
+	}
+	drafts, err := h.drafts.ListDrafts(r.Context(), accountID, realmID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, drafts)
+}
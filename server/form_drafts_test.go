@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSaveDraftHandler(t *testing.T) {
+	t.Run("saves the draft for the calling account in the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/save-draft", map[string]any{"form": "create-rune", "fields": map[string]string{"title": "Bridge"}})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		draft := tc.saved_draft("acct-1", "realm-1", "create-rune")
+		assert.JSONEq(t, `{"title":"Bridge"}`, string(draft.Fields))
+	})
+
+	t.Run("returns 400 when fields is not an object", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/save-draft", map[string]any{"form": "create-rune", "fields": "Bridge"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("fields must be a JSON object")
+	})
+
+	t.Run("returns 400 without a form", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/save-draft", map[string]any{"fields": map[string]string{}})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("form is required")
+	})
+
+	t.Run("returns 403 without an account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/save-draft", map[string]any{"form": "create-rune", "fields": map[string]string{}})
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+	})
+
+	t.Run("returns 404 when drafts are disabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/save-draft", map[string]any{"form": "create-rune", "fields": map[string]string{}})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestDiscardDraftHandler(t *testing.T) {
+	t.Run("deletes only the caller's draft", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.existing_draft("acct-1", "realm-1", "create-rune", `{"title":"Mine"}`)
+		tc.existing_draft("acct-2", "realm-1", "create-rune", `{"title":"Theirs"}`)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/discard-draft", map[string]string{"form": "create-rune"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		require.Len(t, tc.drafts.drafts, 1)
+		assert.Equal(t, "acct-2", tc.drafts.drafts[0].AccountID)
+	})
+}
+
+func TestListDraftsHandler(t *testing.T) {
+	t.Run("returns the caller's drafts in the realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.existing_draft("acct-1", "realm-1", "create-rune", `{"title":"Mine"}`)
+		tc.existing_draft("acct-1", "realm-2", "create-rune", `{"title":"Elsewhere"}`)
+		tc.existing_draft("acct-2", "realm-1", "create-rune", `{"title":"Theirs"}`)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.get("/drafts")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var drafts []core.FormDraft
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &drafts))
+		require.Len(t, drafts, 1)
+		assert.JSONEq(t, `{"title":"Mine"}`, string(drafts[0].Fields))
+	})
+
+	t.Run("returns a single form's draft", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.existing_draft("acct-1", "realm-1", "update-rune:bf-1", `{"title":"Edited"}`)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.get("/drafts?form=update-rune:bf-1")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"form":"update-rune:bf-1"`)
+		tc.response_body_contains(`"title":"Edited"`)
+	})
+
+	t.Run("returns 404 when the form has no draft", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_draft_store()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.get("/drafts?form=create-rune")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_draft_store() {
+	tc.t.Helper()
+	tc.drafts = &mockDraftStore{}
+}
+
+func (tc *handlerTestContext) existing_draft(accountID, realmID, form, fields string) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.drafts.SaveDraft(context.Background(), core.FormDraft{
+		AccountID: accountID, RealmID: realmID, FormKey: form, Fields: json.RawMessage(fields),
+	}))
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) saved_draft(accountID, realmID, form string) core.FormDraft {
+	tc.t.Helper()
+	draft, err := tc.drafts.GetDraft(context.Background(), accountID, realmID, form)
+	require.NoError(tc.t, err)
+	return draft
+}
+
+// --- Mock Draft Store ---
+
+type mockDraftStore struct {
+	drafts []core.FormDraft
+}
+
+func (m *mockDraftStore) find(accountID, realmID, form string) int {
+	for i, d := range m.drafts {
+		if d.AccountID == accountID && d.RealmID == realmID && d.FormKey == form {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *mockDraftStore) SaveDraft(_ context.Context, draft core.FormDraft) error {
+	if i := m.find(draft.AccountID, draft.RealmID, draft.FormKey); i >= 0 {
+		m.drafts[i] = draft
+		return nil
+	}
+	m.drafts = append(m.drafts, draft)
+	return nil
+}
+
+func (m *mockDraftStore) GetDraft(_ context.Context, accountID, realmID, form string) (core.FormDraft, error) {
+	if i := m.find(accountID, realmID, form); i >= 0 {
+		return m.drafts[i], nil
+	}
+	return core.FormDraft{}, &core.NotFoundError{Entity: "draft", ID: form}
+}
+
+func (m *mockDraftStore) ListDrafts(_ context.Context, accountID, realmID string) ([]core.FormDraft, error) {
+	var drafts []core.FormDraft
+	for _, d := range m.drafts {
+		if d.AccountID == accountID && d.RealmID == realmID {
+			drafts = append(drafts, d)
+		}
+	}
+	return drafts, nil
+}
+
+func (m *mockDraftStore) DeleteDraft(_ context.Context, accountID, realmID, form string) error {
+	if i := m.find(accountID, realmID, form); i >= 0 {
+		m.drafts = append(m.drafts[:i], m.drafts[i+1:]...)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// The stats endpoints implement the Grafana JSON datasource protocol: a GET
+// on the base URL tests the connection, /metrics lists the queryable
+// targets and /query returns one timeseries per target with a datapoint per
+// UTC day. Every series is computed from the daily_stats projection.
+
+// Grafana query targets.
+const (
+	statsRunesByStatus = "runes_by_status"
+	statsCreated       = "created"
+	statsFulfilled     = "fulfilled"
+	statsCycleTime     = "cycle_time"
+	statsLeadTime      = "lead_time"
+)
+
+// maxStatsDays bounds the range of a single query.
+const maxStatsDays = 3660
+
+var statsMetrics = []grafanaMetric{
+	{Label: "Runes by status", Value: statsRunesByStatus},
+	{Label: "Runes created per day", Value: statsCreated},
+	{Label: "Runes fulfilled per day", Value: statsFulfilled},
+	{Label: "Cycle time (hours, claim to fulfil)", Value: statsCycleTime},
+	{Label: "Lead time (hours, creation to fulfil)", Value: statsLeadTime},
+}
+
+// statsStatuses orders the series returned for runes_by_status.
+var statsStatuses = []string{"draft", "open", "claimed", "fulfilled", "sealed"}
+
+type grafanaMetric struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+	} `json:"targets"`
+}
+
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// StatsHealth answers the datasource connection test.
+func (h *Handlers) StatsHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// StatsMetrics lists the targets that can be queried.
+func (h *Handlers) StatsMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, statsMetrics)
+}
+
+// StatsQuery returns a daily timeseries for each requested target over the
+// requested range.
+func (h *Handlers) StatsQuery(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var query grafanaQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	from := statsDay(query.Range.From)
+	to := statsDay(query.Range.To)
+	if query.Range.From.IsZero() || query.Range.To.IsZero() || to.Before(from) {
+		writeError(w, http.StatusBadRequest, "range.from and range.to are required and must be in order")
+		return
+	}
+	if to.Sub(from) > maxStatsDays*24*time.Hour {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("range may not exceed %d days", maxStatsDays))
+		return
+	}
+
+	days, err := h.dailyStats(r, realmID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load stats")
+		return
+	}
+
+	series := []grafanaSeries{}
+	for _, target := range query.Targets {
+		switch target.Target {
+		case statsRunesByStatus:
+			series = append(series, statusSeries(days, from, to)...)
+		case statsCreated:
+			series = append(series, dailySeries(target.Target, days, from, to, func(d projectors.DailyStats) (float64, bool) {
+				return float64(d.Created), true
+			}))
+		case statsFulfilled:
+			series = append(series, dailySeries(target.Target, days, from, to, func(d projectors.DailyStats) (float64, bool) {
+				return float64(d.Fulfilled), true
+			}))
+		case statsCycleTime:
+			series = append(series, dailySeries(target.Target, days, from, to, func(d projectors.DailyStats) (float64, bool) {
+				return averageHours(d.CycleTimeSeconds, d.CycleTimeCount)
+			}))
+		case statsLeadTime:
+			series = append(series, dailySeries(target.Target, days, from, to, func(d projectors.DailyStats) (float64, bool) {
+				return averageHours(d.LeadTimeSeconds, d.LeadTimeCount)
+			}))
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown target %q", target.Target))
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, series)
+}
+
+func (h *Handlers) dailyStats(r *http.Request, realmID string) (map[string]projectors.DailyStats, error) {
+	raws, err := h.projectionStore.List(r.Context(), realmID, "daily_stats")
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[string]projectors.DailyStats, len(raws))
+	for _, raw := range raws {
+		var day projectors.DailyStats
+		if json.Unmarshal(raw, &day) != nil || day.Date == "" {
+			continue
+		}
+		days[day.Date] = day
+	}
+	return days, nil
+}
+
+// statusSeries returns the number of runes in each status at the end of
+// every day, summing the deltas recorded before the range as the baseline.
+func statusSeries(days map[string]projectors.DailyStats, from, to time.Time) []grafanaSeries {
+	first := from.Format(projectors.DailyStatsDateFormat)
+	counts := map[string]int{}
+	for date, day := range days {
+		if date < first {
+			for status, delta := range day.StatusDelta {
+				counts[status] += delta
+			}
+		}
+	}
+
+	series := make([]grafanaSeries, len(statsStatuses))
+	for i, status := range statsStatuses {
+		series[i] = grafanaSeries{Target: status, Datapoints: [][2]float64{}}
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		for status, delta := range days[d.Format(projectors.DailyStatsDateFormat)].StatusDelta {
+			counts[status] += delta
+		}
+		for i, status := range statsStatuses {
+			series[i].Datapoints = append(series[i].Datapoints, [2]float64{float64(counts[status]), statsMillis(d)})
+		}
+	}
+	return series
+}
+
+// dailySeries returns one datapoint per day for which value reports one.
+func dailySeries(target string, days map[string]projectors.DailyStats, from, to time.Time, value func(projectors.DailyStats) (float64, bool)) grafanaSeries {
+	series := grafanaSeries{Target: target, Datapoints: [][2]float64{}}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if v, ok := value(days[d.Format(projectors.DailyStatsDateFormat)]); ok {
+			series.Datapoints = append(series.Datapoints, [2]float64{v, statsMillis(d)})
+		}
+	}
+	return series
+}
+
+func averageHours(seconds float64, count int) (float64, bool) {
+	if count == 0 {
+		return 0, false
+	}
+	return seconds / float64(count) / 3600, true
+}
+
+func statsDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func statsMillis(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
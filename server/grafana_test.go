@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestStatsHandlers(t *testing.T) {
+	t.Run("answers the connection test", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.get("/stats/")
+
+		// Then
+		tc.status_is(http.StatusOK)
+	})
+
+	t.Run("lists the queryable metrics", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/stats/metrics", map[string]any{})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var metrics []grafanaMetric
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &metrics))
+		values := make([]string, len(metrics))
+		for i, m := range metrics {
+			values[i] = m.Value
+		}
+		assert.Equal(t, []string{statsRunesByStatus, statsCreated, statsFulfilled, statsCycleTime, statsLeadTime}, values)
+	})
+
+	t.Run("returns cumulative status counts per day", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_daily_stats("realm-1", projectors.DailyStats{Date: "2026-02-27", StatusDelta: map[string]int{"open": 2}})
+		tc.projection_has_daily_stats("realm-1", projectors.DailyStats{Date: "2026-03-02", StatusDelta: map[string]int{"open": -1, "claimed": 1}})
+
+		// When
+		tc.post("/stats/query", stats_query("2026-03-01T00:00:00Z", "2026-03-02T23:59:59Z", statsRunesByStatus))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		series := tc.stats_series()
+		require.Len(t, series, len(statsStatuses))
+		assert.Equal(t, "open", series[1].Target)
+		assert.Equal(t, [][2]float64{{2, 1772323200000}, {1, 1772409600000}}, series[1].Datapoints)
+		assert.Equal(t, "claimed", series[2].Target)
+		assert.Equal(t, [][2]float64{{0, 1772323200000}, {1, 1772409600000}}, series[2].Datapoints)
+	})
+
+	t.Run("returns average cycle time in hours for days with fulfilled runes", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_daily_stats("realm-1", projectors.DailyStats{Date: "2026-03-02", CycleTimeSeconds: 3 * 3600, CycleTimeCount: 2})
+
+		// When
+		tc.post("/stats/query", stats_query("2026-03-01T00:00:00Z", "2026-03-02T23:59:59Z", statsCycleTime))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		series := tc.stats_series()
+		require.Len(t, series, 1)
+		assert.Equal(t, statsCycleTime, series[0].Target)
+		assert.Equal(t, [][2]float64{{1.5, 1772409600000}}, series[0].Datapoints)
+	})
+
+	t.Run("returns 400 for an unknown target", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/stats/query", stats_query("2026-03-01T00:00:00Z", "2026-03-02T00:00:00Z", "nope"))
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("returns 400 for a reversed range", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/stats/query", stats_query("2026-03-05T00:00:00Z", "2026-03-01T00:00:00Z", statsCreated))
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) projection_has_daily_stats(realmID string, day projectors.DailyStats) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), realmID, "daily_stats", day.Date, day)
+}
+
+func stats_query(from, to string, targets ...string) map[string]any {
+	list := make([]map[string]string, len(targets))
+	for i, target := range targets {
+		list[i] = map[string]string{"target": target, "refId": string(rune('A' + i))}
+	}
+	return map[string]any{
+		"range":   map[string]string{"from": from, "to": to},
+		"targets": list,
+	}
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) stats_series() []grafanaSeries {
+	tc.t.Helper()
+	var series []grafanaSeries
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &series))
+	return series
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/devzeebo/bifrost/core"
 	"github.com/devzeebo/bifrost/domain"
 	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/server/integrations"
+	"github.com/devzeebo/bifrost/server/notify"
 )
 
 // ProjectionEngine is the interface for running sync projections.
@@ -20,43 +23,173 @@ type ProjectionEngine interface {
 	RunCatchUpOnce(ctx context.Context)
 }
 
+// CommandExecutor is implemented by engines that project a command's events
+// in the same transaction that appends them.
+type CommandExecutor interface {
+	Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow core.UnitOfWork) error) error
+}
+
+// Notifier delivers test notifications through a realm's configured channels.
+type Notifier interface {
+	SendTest(ctx context.Context, realmID, channel string) error
+}
+
+// GitHubImporter imports GitHub issues into a realm.
+type GitHubImporter interface {
+	ImportGitHub(ctx context.Context, realmID string, req integrations.GitHubImportRequest) (integrations.GitHubImportResult, error)
+}
+
 // Handlers holds dependencies for HTTP route handlers.
 type Handlers struct {
-	eventStore      core.EventStore
-	projectionStore core.ProjectionStore
-	engine          ProjectionEngine
-	mux             *http.ServeMux
+	eventStore        core.EventStore
+	projectionStore   core.ProjectionStore
+	commandStore      core.ProjectionStore // Commands' store, the primary when queries read a replica
+	engine            ProjectionEngine
+	notifier          Notifier
+	githubImporter    GitHubImporter
+	checkpoints       core.CheckpointStore
+	readCheckpoints   core.CheckpointStore
+	commandQueue      core.CommandQueue
+	maxQueuedCommands int
+	drafts            core.DraftStore
+	webhookSender     WebhookSender
+	commandMetrics    *commandMetrics
+	commandMiddleware []CommandMiddleware
+	backups           BackupStatus
+	projections       ProjectionStatus
+	deadLetters       core.DeadLetterStore
+	rebuilder         core.ProjectionRebuilder
+	replay            *projectionSandbox
+	search            core.SearchIndex
+	syncReceipts      core.SyncReceiptStore
+	mux               *http.ServeMux
+}
+
+// HandlersOption configures optional Handlers dependencies.
+type HandlersOption func(*Handlers)
+
+// WithNotifier enables the test-notification endpoint.
+func WithNotifier(n Notifier) HandlersOption {
+	return func(h *Handlers) {
+		h.notifier = n
+	}
+}
+
+// WithGitHubImporter enables the import-github endpoint.
+func WithGitHubImporter(i GitHubImporter) HandlersOption {
+	return func(h *Handlers) {
+		h.githubImporter = i
+	}
+}
+
+// WithCheckpointStore enables ETags on projection reads, derived from the
+// checkpoints of the projectors behind each response.
+func WithCheckpointStore(c core.CheckpointStore) HandlersOption {
+	return func(h *Handlers) {
+		h.checkpoints = c
+	}
+}
+
+// WithReadReplica serves the query endpoints' projection reads from a read
+// replica, along with the checkpoints their ETags derive from, so both lag
+// alike. Commands keep reading the primary store, which they write.
+func WithReadReplica(projections core.ProjectionStore, checkpoints core.CheckpointStore) HandlersOption {
+	return func(h *Handlers) {
+		h.projectionStore = projections
+		h.readCheckpoints = checkpoints
+	}
 }
 
 // NewHandlers creates a new Handlers instance with the given dependencies.
-func NewHandlers(eventStore core.EventStore, projectionStore core.ProjectionStore, engine ProjectionEngine) *Handlers {
+func NewHandlers(eventStore core.EventStore, projectionStore core.ProjectionStore, engine ProjectionEngine, opts ...HandlersOption) *Handlers {
 	h := &Handlers{
 		eventStore:      eventStore,
 		projectionStore: projectionStore,
+		commandStore:    projectionStore,
 		engine:          engine,
+		commandMetrics:  newCommandMetrics(),
 		mux:             http.NewServeMux(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.checkpoints != nil && h.readCheckpoints != nil {
+		h.checkpoints = h.readCheckpoints
+	}
 	h.mux.HandleFunc("GET /health", h.Health)
-	h.mux.HandleFunc("POST /create-rune", h.CreateRune)
-	h.mux.HandleFunc("POST /update-rune", h.UpdateRune)
-	h.mux.HandleFunc("POST /claim-rune", h.ClaimRune)
-	h.mux.HandleFunc("POST /unclaim-rune", h.UnclaimRune)
-	h.mux.HandleFunc("POST /fulfill-rune", h.FulfillRune)
-	h.mux.HandleFunc("POST /seal-rune", h.SealRune)
-	h.mux.HandleFunc("POST /forge-rune", h.ForgeRune)
-	h.mux.HandleFunc("POST /add-dependency", h.AddDependency)
-	h.mux.HandleFunc("POST /remove-dependency", h.RemoveDependency)
-	h.mux.HandleFunc("POST /add-note", h.AddNote)
-	h.mux.HandleFunc("POST /shatter-rune", h.ShatterRune)
-	h.mux.HandleFunc("POST /sweep-runes", h.SweepRunes)
+	h.mux.HandleFunc("POST /create-rune", h.queueable(h.measured(h.CreateRune)))
+	h.mux.HandleFunc("POST /update-rune", h.queueable(h.measured(h.UpdateRune)))
+	h.mux.HandleFunc("POST /claim-rune", h.queueable(h.measured(h.ClaimRune)))
+	h.mux.HandleFunc("POST /unclaim-rune", h.queueable(h.measured(h.UnclaimRune)))
+	h.mux.HandleFunc("POST /fulfill-rune", h.queueable(h.measured(h.FulfillRune)))
+	h.mux.HandleFunc("POST /seal-rune", h.queueable(h.measured(h.SealRune)))
+	h.mux.HandleFunc("POST /forge-rune", h.queueable(h.measured(h.ForgeRune)))
+	h.mux.HandleFunc("POST /add-dependency", h.queueable(h.measured(h.AddDependency)))
+	h.mux.HandleFunc("POST /remove-dependency", h.queueable(h.measured(h.RemoveDependency)))
+	h.mux.HandleFunc("POST /add-note", h.queueable(h.measured(h.AddNote)))
+	h.mux.HandleFunc("POST /link-commits", h.queueable(h.measured(h.LinkCommits)))
+	h.mux.HandleFunc("POST /shatter-rune", h.queueable(h.measured(h.ShatterRune)))
+	h.mux.HandleFunc("POST /sweep-runes", h.queueable(h.measured(h.SweepRunes)))
 	h.mux.HandleFunc("GET /runes", h.ListRunes)
 	h.mux.HandleFunc("GET /rune", h.GetRune)
-	h.mux.HandleFunc("POST /create-realm", h.CreateRealm)
-	h.mux.HandleFunc("POST /suspend-realm", h.SuspendRealm)
+	h.mux.HandleFunc("GET /rune/{id}/impact", h.RuneImpact)
+	h.mux.HandleFunc("GET /rune/{id}/children", h.RuneChildren)
+	h.mux.HandleFunc("GET /search", h.Search)
+	h.mux.HandleFunc("GET /sync", h.SyncPull)
+	h.mux.HandleFunc("POST /sync", h.SyncPush)
+	h.mux.HandleFunc("GET /dashboard", h.Dashboard)
+	h.mux.HandleFunc("GET /accounts/{id}/workload", h.AccountWorkload)
+	h.mux.HandleFunc("GET /command", h.GetCommand)
+	h.mux.HandleFunc("POST /create-realm", h.measured(h.CreateRealm))
+	h.mux.HandleFunc("POST /suspend-realm", h.measured(h.SuspendRealm))
 	h.mux.HandleFunc("GET /realms", h.ListRealms)
 	h.mux.HandleFunc("GET /realm", h.GetRealm)
-	h.mux.HandleFunc("POST /assign-role", h.AssignRole)
-	h.mux.HandleFunc("POST /revoke-role", h.RevokeRole)
+	h.mux.HandleFunc("GET /backup-status", h.GetBackupStatus)
+	h.mux.HandleFunc("GET /projection-status", h.GetProjectionStatus)
+	h.mux.HandleFunc("GET /dead-letters", h.ListDeadLetters)
+	h.mux.HandleFunc("POST /rebuild-projection", h.RebuildProjection)
+	h.mux.HandleFunc("GET /replay-projectors", h.ListReplayProjectors)
+	h.mux.HandleFunc("POST /replay-projections", h.ReplayProjections)
+	h.mux.HandleFunc("POST /export-realm", h.ExportRealm)
+	h.mux.HandleFunc("POST /import-realm", h.ImportRealm)
+	h.mux.HandleFunc("POST /assign-role", h.measured(h.AssignRole))
+	h.mux.HandleFunc("POST /purge-rune", h.measured(h.PurgeRune))
+	h.mux.HandleFunc("POST /revoke-role", h.measured(h.RevokeRole))
+	h.mux.HandleFunc("POST /set-realm-setting", h.measured(h.SetRealmSetting))
+	h.mux.HandleFunc("POST /delete-realm-setting", h.measured(h.DeleteRealmSetting))
+	h.mux.HandleFunc("GET /realm-settings", h.GetRealmSettings)
+	h.mux.HandleFunc("GET /features", h.Features)
+	h.mux.HandleFunc("POST /test-notification", h.TestNotification)
+	h.mux.HandleFunc("POST /import-github", h.ImportGitHub)
+	h.mux.HandleFunc("POST /add-automation-rule", h.measured(h.AddAutomationRule))
+	h.mux.HandleFunc("POST /remove-automation-rule", h.measured(h.RemoveAutomationRule))
+	h.mux.HandleFunc("GET /automation-rules", h.ListAutomationRules)
+	h.mux.HandleFunc("POST /add-escalation-policy", h.measured(h.AddEscalationPolicy))
+	h.mux.HandleFunc("POST /remove-escalation-policy", h.measured(h.RemoveEscalationPolicy))
+	h.mux.HandleFunc("GET /escalation-policies", h.ListEscalationPolicies)
+	h.mux.HandleFunc("POST /create-schedule", h.measured(h.CreateSchedule))
+	h.mux.HandleFunc("POST /delete-schedule", h.measured(h.DeleteSchedule))
+	h.mux.HandleFunc("GET /schedules", h.ListSchedules)
+	h.mux.HandleFunc("POST /register-webhook", h.measured(h.RegisterWebhook))
+	h.mux.HandleFunc("POST /remove-webhook", h.measured(h.RemoveWebhook))
+	h.mux.HandleFunc("POST /test-webhook", h.TestWebhook)
+	h.mux.HandleFunc("GET /webhooks", h.ListWebhooks)
+	h.mux.HandleFunc("GET /webhook-deliveries", h.WebhookDeliveries)
+	h.mux.HandleFunc("POST /create-saved-search", h.measured(h.CreateSavedSearch))
+	h.mux.HandleFunc("POST /update-saved-search", h.measured(h.UpdateSavedSearch))
+	h.mux.HandleFunc("POST /delete-saved-search", h.measured(h.DeleteSavedSearch))
+	h.mux.HandleFunc("GET /saved-searches", h.ListSavedSearches)
+	h.mux.HandleFunc("POST /save-draft", h.SaveDraft)
+	h.mux.HandleFunc("POST /discard-draft", h.DiscardDraft)
+	h.mux.HandleFunc("GET /drafts", h.ListDrafts)
+	h.mux.HandleFunc("POST /mcp", h.MCP)
+	h.mux.HandleFunc("POST /calendar-token", h.measured(h.IssueCalendarToken))
+	h.mux.HandleFunc("GET /calendar/{token}", h.Calendar)
+	h.mux.HandleFunc("GET /stats/{$}", h.StatsHealth)
+	h.mux.HandleFunc("POST /stats/metrics", h.StatsMetrics)
+	h.mux.HandleFunc("POST /stats/query", h.StatsQuery)
+	h.mux.HandleFunc("GET /reports/velocity", h.VelocityReport)
+	h.mux.HandleFunc("GET /reports/sla", h.SLAReport)
 	return h
 }
 
@@ -86,33 +219,110 @@ func (h *Handlers) RegisterRoutes(mux *http.ServeMux, realmMiddleware, adminMidd
 	// Health check — no auth
 	mux.HandleFunc("GET /health", h.Health)
 
-	// Rune commands (member role minimum)
-	mux.Handle("POST /api/create-rune", memberAuth(http.HandlerFunc(h.CreateRune)))
-	mux.Handle("POST /api/update-rune", memberAuth(http.HandlerFunc(h.UpdateRune)))
-	mux.Handle("POST /api/claim-rune", memberAuth(http.HandlerFunc(h.ClaimRune)))
-	mux.Handle("POST /api/unclaim-rune", memberAuth(http.HandlerFunc(h.UnclaimRune)))
-	mux.Handle("POST /api/fulfill-rune", memberAuth(http.HandlerFunc(h.FulfillRune)))
-	mux.Handle("POST /api/seal-rune", memberAuth(http.HandlerFunc(h.SealRune)))
-	mux.Handle("POST /api/forge-rune", memberAuth(http.HandlerFunc(h.ForgeRune)))
-	mux.Handle("POST /api/add-dependency", memberAuth(http.HandlerFunc(h.AddDependency)))
-	mux.Handle("POST /api/remove-dependency", memberAuth(http.HandlerFunc(h.RemoveDependency)))
-	mux.Handle("POST /api/add-note", memberAuth(http.HandlerFunc(h.AddNote)))
-	mux.Handle("POST /api/shatter-rune", memberAuth(http.HandlerFunc(h.ShatterRune)))
-	mux.Handle("POST /api/sweep-runes", memberAuth(http.HandlerFunc(h.SweepRunes)))
+	// Calendar feed — authenticated by the token in the URL
+	mux.HandleFunc("GET /calendar/{token}", h.Calendar)
+
+	// Rune commands (member role minimum; queued when the client prefers respond-async)
+	mux.Handle("POST /api/create-rune", memberAuth(h.queueable(h.measured(h.CreateRune))))
+	mux.Handle("POST /api/update-rune", memberAuth(h.queueable(h.measured(h.UpdateRune))))
+	mux.Handle("POST /api/claim-rune", memberAuth(h.queueable(h.measured(h.ClaimRune))))
+	mux.Handle("POST /api/unclaim-rune", memberAuth(h.queueable(h.measured(h.UnclaimRune))))
+	mux.Handle("POST /api/fulfill-rune", memberAuth(h.queueable(h.measured(h.FulfillRune))))
+	mux.Handle("POST /api/seal-rune", memberAuth(h.queueable(h.measured(h.SealRune))))
+	mux.Handle("POST /api/forge-rune", memberAuth(h.queueable(h.measured(h.ForgeRune))))
+	mux.Handle("POST /api/add-dependency", memberAuth(h.queueable(h.measured(h.AddDependency))))
+	mux.Handle("POST /api/remove-dependency", memberAuth(h.queueable(h.measured(h.RemoveDependency))))
+	mux.Handle("POST /api/add-note", memberAuth(h.queueable(h.measured(h.AddNote))))
+	mux.Handle("POST /api/link-commits", memberAuth(h.queueable(h.measured(h.LinkCommits))))
+	mux.Handle("POST /api/shatter-rune", memberAuth(h.queueable(h.measured(h.ShatterRune))))
+	mux.Handle("POST /api/sweep-runes", memberAuth(h.queueable(h.measured(h.SweepRunes))))
 
 	// Rune queries (viewer role minimum)
 	mux.Handle("GET /api/runes", viewerAuth(http.HandlerFunc(h.ListRunes)))
 	mux.Handle("GET /api/rune", viewerAuth(http.HandlerFunc(h.GetRune)))
+	mux.Handle("GET /api/rune/{id}/impact", viewerAuth(http.HandlerFunc(h.RuneImpact)))
+	mux.Handle("GET /api/rune/{id}/children", viewerAuth(http.HandlerFunc(h.RuneChildren)))
+	mux.Handle("GET /api/search", viewerAuth(http.HandlerFunc(h.Search)))
+	mux.Handle("GET /api/dashboard", viewerAuth(http.HandlerFunc(h.Dashboard)))
+	mux.Handle("GET /api/features", viewerAuth(http.HandlerFunc(h.Features)))
+	mux.Handle("GET /api/command", viewerAuth(http.HandlerFunc(h.GetCommand)))
+
+	// Offline client sync: pulling needs viewer, pushing commands needs member
+	mux.Handle("GET /api/sync", viewerAuth(http.HandlerFunc(h.SyncPull)))
+	mux.Handle("POST /api/sync", memberAuth(http.HandlerFunc(h.SyncPush)))
+
+	// Claimed runes of an account across the realms the caller can see (viewer role minimum)
+	mux.Handle("GET /api/accounts/{id}/workload", viewerAuth(http.HandlerFunc(h.AccountWorkload)))
+
+	// MCP endpoint for coding agents (viewer role minimum; command tools check member)
+	mux.Handle("POST /api/mcp", viewerAuth(http.HandlerFunc(h.MCP)))
+
+	// Saved searches of the authenticated account (viewer role minimum)
+	mux.Handle("POST /api/create-saved-search", viewerAuth(h.measured(h.CreateSavedSearch)))
+	mux.Handle("POST /api/update-saved-search", viewerAuth(h.measured(h.UpdateSavedSearch)))
+	mux.Handle("POST /api/delete-saved-search", viewerAuth(h.measured(h.DeleteSavedSearch)))
+	mux.Handle("GET /api/saved-searches", viewerAuth(http.HandlerFunc(h.ListSavedSearches)))
+
+	// Form drafts of the authenticated account (viewer role minimum)
+	mux.Handle("POST /api/save-draft", viewerAuth(http.HandlerFunc(h.SaveDraft)))
+	mux.Handle("POST /api/discard-draft", viewerAuth(http.HandlerFunc(h.DiscardDraft)))
+	mux.Handle("GET /api/drafts", viewerAuth(http.HandlerFunc(h.ListDrafts)))
+
+	// Calendar feed token for the authenticated account (viewer role minimum)
+	mux.Handle("POST /api/calendar-token", viewerAuth(h.measured(h.IssueCalendarToken)))
+
+	// Grafana JSON datasource for rune stats (viewer role minimum)
+	mux.Handle("GET /api/stats/{$}", viewerAuth(http.HandlerFunc(h.StatsHealth)))
+	mux.Handle("POST /api/stats/metrics", viewerAuth(http.HandlerFunc(h.StatsMetrics)))
+	mux.Handle("POST /api/stats/query", viewerAuth(http.HandlerFunc(h.StatsQuery)))
+
+	// Velocity and SLA reports (viewer role minimum)
+	mux.Handle("GET /api/reports/velocity", viewerAuth(http.HandlerFunc(h.VelocityReport)))
+	mux.Handle("GET /api/reports/sla", viewerAuth(http.HandlerFunc(h.SLAReport)))
 
 	// Role management (admin role minimum, realm auth)
-	mux.Handle("POST /api/assign-role", adminRealmAuth(http.HandlerFunc(h.AssignRole)))
-	mux.Handle("POST /api/revoke-role", adminRealmAuth(http.HandlerFunc(h.RevokeRole)))
+	mux.Handle("POST /api/assign-role", adminRealmAuth(h.measured(h.AssignRole)))
+	mux.Handle("POST /api/revoke-role", adminRealmAuth(h.measured(h.RevokeRole)))
+
+	// Erasure of a shattered rune's contents (admin role minimum, realm auth)
+	mux.Handle("POST /api/purge-rune", adminRealmAuth(h.measured(h.PurgeRune)))
+
+	// Realm settings (admin role minimum, realm auth)
+	mux.Handle("POST /api/set-realm-setting", adminRealmAuth(h.measured(h.SetRealmSetting)))
+	mux.Handle("POST /api/delete-realm-setting", adminRealmAuth(h.measured(h.DeleteRealmSetting)))
+	mux.Handle("GET /api/realm-settings", adminRealmAuth(http.HandlerFunc(h.GetRealmSettings)))
+	mux.Handle("POST /api/test-notification", adminRealmAuth(http.HandlerFunc(h.TestNotification)))
+	mux.Handle("POST /api/import-github", adminRealmAuth(http.HandlerFunc(h.ImportGitHub)))
+
+	// Automation rules (admin role minimum, realm auth)
+	mux.Handle("POST /api/add-automation-rule", adminRealmAuth(h.measured(h.AddAutomationRule)))
+	mux.Handle("POST /api/remove-automation-rule", adminRealmAuth(h.measured(h.RemoveAutomationRule)))
+	mux.Handle("GET /api/automation-rules", adminRealmAuth(http.HandlerFunc(h.ListAutomationRules)))
+	mux.Handle("POST /api/add-escalation-policy", adminRealmAuth(h.measured(h.AddEscalationPolicy)))
+	mux.Handle("POST /api/remove-escalation-policy", adminRealmAuth(h.measured(h.RemoveEscalationPolicy)))
+	mux.Handle("GET /api/escalation-policies", adminRealmAuth(http.HandlerFunc(h.ListEscalationPolicies)))
+	mux.Handle("POST /api/create-schedule", adminRealmAuth(h.measured(h.CreateSchedule)))
+	mux.Handle("POST /api/delete-schedule", adminRealmAuth(h.measured(h.DeleteSchedule)))
+	mux.Handle("GET /api/schedules", adminRealmAuth(http.HandlerFunc(h.ListSchedules)))
+	mux.Handle("POST /api/register-webhook", adminRealmAuth(h.measured(h.RegisterWebhook)))
+	mux.Handle("POST /api/remove-webhook", adminRealmAuth(h.measured(h.RemoveWebhook)))
+	mux.Handle("POST /api/test-webhook", adminRealmAuth(http.HandlerFunc(h.TestWebhook)))
+	mux.Handle("GET /api/webhooks", adminRealmAuth(http.HandlerFunc(h.ListWebhooks)))
+	mux.Handle("GET /api/webhook-deliveries", adminRealmAuth(http.HandlerFunc(h.WebhookDeliveries)))
 
 	// Admin commands (admin auth — allows _admin realm with role check)
-	mux.Handle("POST /api/create-realm", adminAuth(http.HandlerFunc(h.CreateRealm)))
-	mux.Handle("POST /api/suspend-realm", adminMiddleware(http.HandlerFunc(h.SuspendRealm)))
+	mux.Handle("POST /api/create-realm", adminAuth(h.measured(h.CreateRealm)))
+	mux.Handle("POST /api/suspend-realm", adminMiddleware(h.measured(h.SuspendRealm)))
 	mux.Handle("GET /api/realms", adminAuth(http.HandlerFunc(h.ListRealms)))
 	mux.Handle("GET /api/realm", viewerAuth(http.HandlerFunc(h.GetRealm)))
+	mux.Handle("GET /api/backup-status", adminAuth(http.HandlerFunc(h.GetBackupStatus)))
+	mux.Handle("GET /api/projection-status", adminAuth(http.HandlerFunc(h.GetProjectionStatus)))
+	mux.Handle("GET /api/dead-letters", adminAuth(http.HandlerFunc(h.ListDeadLetters)))
+	mux.Handle("POST /api/rebuild-projection", adminAuth(http.HandlerFunc(h.RebuildProjection)))
+	mux.Handle("GET /api/replay-projectors", adminAuth(http.HandlerFunc(h.ListReplayProjectors)))
+	mux.Handle("POST /api/replay-projections", adminAuth(http.HandlerFunc(h.ReplayProjections)))
+	mux.Handle("POST /api/export-realm", adminAuth(http.HandlerFunc(h.ExportRealm)))
+	mux.Handle("POST /api/import-realm", adminAuth(http.HandlerFunc(h.ImportRealm)))
 }
 
 // --- Command Handlers ---
@@ -128,17 +338,23 @@ func (h *Handlers) CreateRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.CreateRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	result, err := domain.HandleCreateRune(r.Context(), realmID, cmd, h.eventStore, h.projectionStore)
+	var result domain.RuneCreated
+	err := h.execute(w, r, realmID, "CreateRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		var err error
+		result, err = domain.HandleCreateRune(ctx, realmID, cmd, events, projections)
+		return err
+	})
 	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
-	writeJSON(w, http.StatusCreated, result)
+	writeJSON(w, http.StatusCreated, CreateRuneResponse{
+		RuneCreated: result,
+		Duplicates:  h.findDuplicates(r.Context(), realmID, result),
+	})
 }
 
 func (h *Handlers) UpdateRune(w http.ResponseWriter, r *http.Request) {
@@ -148,15 +364,16 @@ func (h *Handlers) UpdateRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.UpdateRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleUpdateRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "UpdateRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleUpdateRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -167,15 +384,16 @@ func (h *Handlers) ClaimRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.ClaimRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleClaimRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "ClaimRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleClaimRune(ctx, realmID, cmd, events, projections)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -186,15 +404,16 @@ func (h *Handlers) UnclaimRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.UnclaimRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleUnclaimRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "UnclaimRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleUnclaimRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -205,15 +424,16 @@ func (h *Handlers) FulfillRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.FulfillRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleFulfillRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "FulfillRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleFulfillRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -224,15 +444,16 @@ func (h *Handlers) SealRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.SealRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleSealRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "SealRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleSealRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -243,15 +464,16 @@ func (h *Handlers) ForgeRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.ForgeRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleForgeRune(r.Context(), realmID, cmd, h.eventStore, h.projectionStore); err != nil {
+	err := h.execute(w, r, realmID, "ForgeRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleForgeRune(ctx, realmID, cmd, events, projections)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -262,15 +484,16 @@ func (h *Handlers) AddDependency(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.AddDependency
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleAddDependency(r.Context(), realmID, cmd, h.eventStore, h.projectionStore); err != nil {
+	err := h.execute(w, r, realmID, "AddDependency", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleAddDependency(ctx, realmID, cmd, events, projections)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -281,15 +504,16 @@ func (h *Handlers) RemoveDependency(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.RemoveDependency
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleRemoveDependency(r.Context(), realmID, cmd, h.eventStore, h.projectionStore); err != nil {
+	err := h.execute(w, r, realmID, "RemoveDependency", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleRemoveDependency(ctx, realmID, cmd, events, projections)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -300,8 +524,7 @@ func (h *Handlers) AssignRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.AssignRole
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
 
@@ -354,8 +577,7 @@ func (h *Handlers) RevokeRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.RevokeRole
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
 
@@ -428,15 +650,38 @@ func (h *Handlers) ShatterRune(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.ShatterRune
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleShatterRune(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "ShatterRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleShatterRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeRune redacts a shattered rune's events, leaving a RunePurged
+// tombstone on its stream.
+func (h *Handlers) PurgeRune(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.PurgeRune
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	err := h.execute(w, r, realmID, "PurgeRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandlePurgeRune(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -446,12 +691,16 @@ func (h *Handlers) SweepRunes(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusForbidden, "realm ID required")
 		return
 	}
-	shattered, err := domain.HandleSweepRunes(r.Context(), realmID, h.eventStore, h.projectionStore)
+	var shattered []string
+	err := h.execute(w, r, realmID, "SweepRunes", nil, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		var err error
+		shattered, err = domain.HandleSweepRunes(ctx, realmID, events, projections)
+		return err
+	})
 	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	writeJSON(w, http.StatusOK, map[string][]string{"shattered": shattered})
 }
 
@@ -462,22 +711,45 @@ func (h *Handlers) AddNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var cmd domain.AddNote
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
-	if err := domain.HandleAddNote(r.Context(), realmID, cmd, h.eventStore); err != nil {
+	err := h.execute(w, r, realmID, "AddNote", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		return domain.HandleAddNote(ctx, realmID, cmd, events)
+	})
+	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
-	h.runSyncQuietly(r)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *Handlers) LinkCommits(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.LinkCommits
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	var result domain.LinkCommitsResult
+	err := h.execute(w, r, realmID, "LinkCommits", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+		var err error
+		result, err = domain.HandleLinkCommits(ctx, realmID, cmd, events)
+		return err
+	})
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (h *Handlers) CreateRealm(w http.ResponseWriter, r *http.Request) {
 	var cmd domain.CreateRealm
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
 	result, err := domain.HandleCreateRealm(r.Context(), cmd, h.eventStore)
@@ -506,8 +778,7 @@ func (h *Handlers) CreateRealm(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) SuspendRealm(w http.ResponseWriter, r *http.Request) {
 	var cmd domain.SuspendRealm
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+	if !decodeJSONBody(w, r, &cmd) {
 		return
 	}
 	if cmd.RealmID == "" {
@@ -523,62 +794,134 @@ func (h *Handlers) SuspendRealm(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// --- Query Handlers ---
+func (h *Handlers) SetRealmSetting(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.SetRealmSetting
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	if err := domain.HandleSetRealmSetting(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
 
-func (h *Handlers) ListRunes(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) DeleteRealmSetting(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.DeleteRealmSetting
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	if err := domain.HandleDeleteRealmSetting(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) TestNotification(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	if h.notifier == nil {
+		writeError(w, http.StatusNotImplemented, "notifications are not enabled")
+		return
+	}
+	var body struct {
+		Channel string `json:"channel"`
+	}
+	if !decodeJSONBody(w, r, &body) {
+		return
+	}
+	if err := h.notifier.SendTest(r.Context(), realmID, body.Channel); err != nil {
+		var deliveryErr *notify.DeliveryError
+		if errors.As(err, &deliveryErr) {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		handleDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ImportGitHub(w http.ResponseWriter, r *http.Request) {
 	realmID, ok := RealmIDFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusForbidden, "realm ID required")
 		return
 	}
-	runes, err := h.projectionStore.List(r.Context(), realmID, "rune_list")
+	if h.githubImporter == nil {
+		writeError(w, http.StatusNotImplemented, "github import is not enabled")
+		return
+	}
+	var req integrations.GitHubImportRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	result, err := h.githubImporter.ImportGitHub(r.Context(), realmID, req)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list runes")
+		var apiErr *integrations.GitHubAPIError
+		if errors.As(err, &apiErr) {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		handleDomainError(w, err)
 		return
 	}
-	allRunes := append([]json.RawMessage(nil), runes...)
+	writeJSON(w, http.StatusOK, result)
+}
 
-	statusFilter := r.URL.Query().Get("status")
-	priorityFilter := r.URL.Query().Get("priority")
-	assigneeFilter := r.URL.Query().Get("assignee")
-	branchFilter := r.URL.Query().Get("branch")
-	sagaFilter := r.URL.Query().Get("saga")
+// --- Query Handlers ---
 
-	if statusFilter != "" || priorityFilter != "" || assigneeFilter != "" || branchFilter != "" || sagaFilter != "" {
-		var filtered []json.RawMessage
-		for _, raw := range runes {
-			var item map[string]any
-			if json.Unmarshal(raw, &item) != nil {
-				continue
-			}
-			if statusFilter != "" {
-				if fmt.Sprintf("%v", item["status"]) != statusFilter {
-					continue
-				}
-			}
-			if priorityFilter != "" {
-				if fmt.Sprintf("%v", item["priority"]) != priorityFilter {
-					continue
-				}
-			}
-			if assigneeFilter != "" {
-				if fmt.Sprintf("%v", item["assignee"]) != assigneeFilter {
-					continue
-				}
-			}
-			if branchFilter != "" {
-				if fmt.Sprintf("%v", item["branch"]) != branchFilter {
-					continue
-				}
-			}
-			if sagaFilter != "" {
-				if fmt.Sprintf("%v", item["parent_id"]) != sagaFilter {
-					continue
-				}
-			}
-			filtered = append(filtered, raw)
+// runeListFilterFields maps ListRunes query parameters to rune_list fields.
+var runeListFilterFields = map[string]string{
+	"status":   "status",
+	"priority": "priority",
+	"assignee": "assignee",
+	"claimant": "claimant",
+	"branch":   "branch",
+	"saga":     "parent_id",
+}
+
+func (h *Handlers) ListRunes(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	if h.notModified(w, r,
+		projectionSource{realmID, "rune_list"},
+		projectionSource{realmID, "dependency_graph"},
+		projectionSource{domain.AdminRealmID, "account_lookup"},
+	) {
+		return
+	}
+	filter := core.ProjectionFilter{}
+	for param, field := range runeListFilterFields {
+		if value := r.URL.Query().Get(param); value != "" {
+			filter[field] = value
 		}
-		runes = filtered
+	}
+	runes, err := core.ListProjectionWhere(r.Context(), h.projectionStore, realmID, "rune_list", filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list runes")
+		return
 	}
 
 	blockedFilter := r.URL.Query().Get("blocked")
@@ -589,49 +932,32 @@ func (h *Handlers) ListRunes(w http.ResponseWriter, r *http.Request) {
 			if json.Unmarshal(raw, &item) != nil {
 				continue
 			}
-			runeID := fmt.Sprintf("%v", item["id"])
-			var detail projectors.RuneDetail
-			err := h.projectionStore.Get(r.Context(), realmID, "rune_detail", runeID, &detail)
-			if err != nil {
-				if isNotFound(err) {
-					unblocked = append(unblocked, raw)
-					continue
-				}
-				continue
-			}
-			isBlocked := false
-			for _, dep := range detail.Dependencies {
-				if dep.Relationship == domain.RelBlockedBy {
-					var summary projectors.RuneSummary
-					err := h.projectionStore.Get(r.Context(), realmID, "rune_list", dep.TargetID, &summary)
-					if err != nil {
-						isBlocked = true
-						break
-					}
-					if summary.Status != "fulfilled" {
-						isBlocked = true
-						break
-					}
-				}
-			}
-			if !isBlocked {
+			if !h.isRuneBlocked(r.Context(), realmID, fmt.Sprintf("%v", item["id"])) {
 				unblocked = append(unblocked, raw)
 			}
 		}
 		runes = unblocked
 	}
 
-	allStatuses := make(map[string]string)
-	for _, raw := range allRunes {
+	// Dependency counts only include active runes, so look up the status of
+	// each rune they mention, starting from the ones already listed.
+	statuses := make(map[string]string, len(runes))
+	for _, raw := range runes {
 		var item map[string]any
 		if json.Unmarshal(raw, &item) != nil {
 			continue
 		}
-		runeID := fmt.Sprintf("%v", item["id"])
-		status := fmt.Sprintf("%v", item["status"])
-		if runeID != "" {
-			allStatuses[runeID] = status
+		statuses[fmt.Sprintf("%v", item["id"])] = fmt.Sprintf("%v", item["status"])
+	}
+	statusOf := func(runeID string) string {
+		if status, ok := statuses[runeID]; ok {
+			return status
+		}
+		var summary projectors.RuneSummary
+		if err := h.projectionStore.Get(r.Context(), realmID, "rune_list", runeID, &summary); err == nil {
+			statuses[runeID] = summary.Status
 		}
+		return statuses[runeID]
 	}
 
 	isActiveStatus := func(status string) bool {
@@ -656,12 +982,12 @@ func (h *Handlers) ListRunes(w http.ResponseWriter, r *http.Request) {
 		dependentCount := 0
 		if err := h.projectionStore.Get(r.Context(), realmID, "dependency_graph", runeID, &graph); err == nil {
 			for _, dep := range graph.Dependencies {
-				if isActiveStatus(allStatuses[dep.TargetID]) {
+				if isActiveStatus(statusOf(dep.TargetID)) {
 					depCount++
 				}
 			}
 			for _, dependent := range graph.Dependents {
-				if isActiveStatus(allStatuses[dependent.SourceID]) {
+				if isActiveStatus(statusOf(dependent.SourceID)) {
 					dependentCount++
 				}
 			}
@@ -726,6 +1052,9 @@ func (h *Handlers) GetRune(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "id query parameter is required")
 		return
 	}
+	if h.notModified(w, r, projectionSource{realmID, "rune_detail"}) {
+		return
+	}
 	var detail any
 	err := h.projectionStore.Get(r.Context(), realmID, "rune_detail", runeID, &detail)
 	if err != nil {
@@ -871,8 +1200,56 @@ func (h *Handlers) GetRealm(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, response)
 }
 
+// GetRealmSettings returns the realm's settings. Values of keys that look
+// like credentials are masked.
+func (h *Handlers) GetRealmSettings(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	stored, err := projectors.GetRealmSettings(r.Context(), h.projectionStore, realmID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get realm settings")
+		return
+	}
+	settings := make(map[string]string, len(stored))
+	for key, value := range stored {
+		if isSecretSettingKey(key) {
+			value = "********"
+		}
+		settings[key] = value
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// Dashboard returns the realm's precomputed status counts and recently
+// changed runes.
+func (h *Handlers) Dashboard(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	stats := projectors.DashboardStats{StatusCounts: map[string]int{}, Recent: []projectors.DashboardRune{}}
+	if err := h.projectionStore.Get(r.Context(), realmID, "dashboard_stats", "summary", &stats); err != nil && !isNotFound(err) {
+		writeError(w, http.StatusInternalServerError, "failed to get dashboard")
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
 // --- Helpers ---
 
+// isSecretSettingKey reports whether a setting's value is a credential.
+// Incoming webhook URLs, such as Slack's and Discord's, carry their token in
+// the URL.
+func isSecretSettingKey(key string) bool {
+	lower := strings.ToLower(key)
+	return strings.Contains(lower, "secret") || strings.Contains(lower, "token") ||
+		strings.HasSuffix(lower, ".webhook_url")
+}
+
 func writeJSON(w http.ResponseWriter, statusCode int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -898,35 +1275,99 @@ func handleDomainError(w http.ResponseWriter, err error) {
 		return
 	}
 
-	msg := err.Error()
-	if isValidationError(msg) {
-		writeError(w, http.StatusBadRequest, msg)
+	var ruleErr *domain.RuleError
+	if errors.As(err, &ruleErr) {
+		if ruleErr.Details != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error(), "details": ruleErr.Details})
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	writeError(w, http.StatusInternalServerError, msg)
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func isNotFound(err error) bool {
+	var nfe *core.NotFoundError
+	return errors.As(err, &nfe)
 }
 
-func isValidationError(msg string) bool {
-	prefixes := []string{
-		"cannot ",
-		"rune ",
-		"realm ",
-		"unknown ",
+// isRuneBlocked reports whether any blocked_by dependency of the rune is not
+// yet fulfilled. Runes without a detail projection are treated as unblocked.
+func (h *Handlers) isRuneBlocked(ctx context.Context, realmID, runeID string) bool {
+	var detail projectors.RuneDetail
+	if err := h.projectionStore.Get(ctx, realmID, "rune_detail", runeID, &detail); err != nil {
+		return !isNotFound(err)
 	}
-	for _, p := range prefixes {
-		if strings.HasPrefix(msg, p) {
+	for _, dep := range detail.Dependencies {
+		if dep.Relationship != domain.RelBlockedBy {
+			continue
+		}
+		var summary projectors.RuneSummary
+		if err := h.projectionStore.Get(ctx, realmID, "rune_list", dep.TargetID, &summary); err != nil {
+			return true
+		}
+		if summary.Status != "fulfilled" {
 			return true
 		}
 	}
 	return false
 }
 
-func isNotFound(err error) bool {
-	var nfe *core.NotFoundError
-	return errors.As(err, &nfe)
-}
-
 func (h *Handlers) runSyncQuietly(r *http.Request) {
 	h.engine.RunCatchUpOnce(r.Context())
 }
+
+// execute runs a rune command against the event and projection stores,
+// once the realm is known to exist and not be suspended. When the engine is
+// a CommandExecutor the command's events are appended and projected in one
+// transaction; otherwise they are projected afterwards. The command passes
+// through the command middleware under name, with payload pointing at its
+// arguments. Once it succeeds, the X-Bifrost-Position header of w carries
+// the global position of the last event it appended.
+func (h *Handlers) execute(w http.ResponseWriter, r *http.Request, realmID, name string, payload any, command func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error) error {
+	cmd, err := h.runCommand(r, realmID, name, payload, command)
+	if err != nil {
+		return err
+	}
+	if position := cmd.Position(); position > 0 {
+		w.Header().Set("X-Bifrost-Position", strconv.FormatInt(position, 10))
+	}
+	return nil
+}
+
+// runCommand runs a command as execute does and returns it with the events
+// it appended.
+func (h *Handlers) runCommand(r *http.Request, realmID, name string, payload any, command func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error) (*Command, error) {
+	run := func(ctx context.Context, cmd *Command) error {
+		var recorder *recordingEventStore
+		guarded := func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			if err := domain.RequireActiveRealm(ctx, realmID, events); err != nil {
+				return err
+			}
+			recorder = &recordingEventStore{EventStore: events, idempotencyKey: r.Header.Get("Idempotency-Key")}
+			return command(ctx, recorder, projections)
+		}
+		if executor, ok := h.engine.(CommandExecutor); ok {
+			if err := executor.Execute(ctx, realmID, func(ctx context.Context, uow core.UnitOfWork) error {
+				return guarded(ctx, uow.EventStore, uow.ProjectionStore)
+			}); err != nil {
+				return err
+			}
+		} else {
+			if err := guarded(ctx, h.eventStore, h.commandStore); err != nil {
+				return err
+			}
+			h.runSyncQuietly(r)
+		}
+		cmd.Events = recorder.appended
+		return nil
+	}
+	actor, _ := AccountIDFromContext(r.Context())
+	cmd := &Command{Name: name, RealmID: realmID, Actor: actor, Payload: payload}
+	if err := h.chainCommand(run)(r.Context(), cmd); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
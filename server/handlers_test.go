@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 
 	"github.com/devzeebo/bifrost/core"
 	"github.com/devzeebo/bifrost/domain"
 	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/server/integrations"
+	"github.com/devzeebo/bifrost/server/notify"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -93,6 +96,35 @@ func TestHandleDomainError(t *testing.T) {
 		tc.response_body_has_error_field()
 	})
 
+	t.Run("maps wrapped rule error to 400", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.domain_error_is(fmt.Errorf("create rune: %w", domain.Rejectf(domain.ErrBranchRequired, "branch is required for top-level runes")))
+
+		// When
+		tc.handle_domain_error()
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("branch is required for top-level runes")
+	})
+
+	t.Run("includes the details of a rule error", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.domain_error_is(domain.BranchPolicy{Prefixes: []string{"feature/"}}.Check("main"))
+
+		// When
+		tc.handle_domain_error()
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("does not follow the realm's branch policy")
+		tc.response_body_contains(`"details":{"prefixes":["feature/"]}`)
+	})
+
 	t.Run("maps generic error to 500", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
@@ -108,11 +140,11 @@ func TestHandleDomainError(t *testing.T) {
 		tc.response_body_has_error_field()
 	})
 
-	t.Run("maps validation-style error to 400", func(t *testing.T) {
+	t.Run("maps rule error to 400", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
 		// Given
-		tc.domain_error_is(fmt.Errorf("cannot update sealed rune %q", "bf-1234"))
+		tc.domain_error_is(domain.Rejectf(domain.ErrRuneSealed, "cannot update sealed rune %q", "bf-1234"))
 
 		// When
 		tc.handle_domain_error()
@@ -167,6 +199,60 @@ func TestCreateRuneHandler(t *testing.T) {
 		tc.response_body_has_field("id")
 	})
 
+	t.Run("returns 404 when the realm does not exist", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-missing")
+		tc.realm_does_not_exist("realm-missing")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+		tc.no_rune_was_written("realm-missing")
+	})
+
+	t.Run("returns 400 when the realm is suspended", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.realm_exists("realm-1", "suspended")
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{Title: "Fix bug", Priority: 1, Branch: strPtr("main")})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains(`realm \"realm-1\" is suspended`)
+		tc.no_rune_was_written("realm-1")
+	})
+
+	t.Run("executes the command through a transactional engine", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_command_executor()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.event_store_appends_successfully()
+
+		// When
+		tc.post("/create-rune", domain.CreateRune{
+			Title:    "Fix bug",
+			Priority: 1,
+			Branch:   strPtr("main"),
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.command_was_executed_in("realm-1")
+	})
+
 	t.Run("returns 400 for invalid JSON body", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
@@ -181,6 +267,22 @@ func TestCreateRuneHandler(t *testing.T) {
 		tc.status_is(http.StatusBadRequest)
 		tc.response_body_has_error_field()
 	})
+
+	t.Run("returns 400 listing unknown fields", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post_raw("/create-rune", []byte(`{"title":"Fix bug","priorty":1,"colour":"red"}`))
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_equals(`{"details":{"unknown_fields":["colour","priorty"]},"error":"unknown fields: colour, priorty"}`)
+		tc.no_rune_was_written("realm-1")
+	})
 }
 
 // --- Tests: UpdateRune ---
@@ -416,6 +518,293 @@ func TestAddNoteHandler(t *testing.T) {
 	})
 }
 
+// --- Tests: LinkCommits ---
+
+func TestLinkCommitsHandler(t *testing.T) {
+	t.Run("notes commits on referenced runes and returns 200", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+
+		// When
+		tc.post("/link-commits", domain.LinkCommits{
+			Commits: []domain.CommitRef{{SHA: "abcdef0123", Message: "Fix bf-0001"}},
+		})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"bf-0001":["abcdef0123"]`)
+	})
+
+	t.Run("returns 400 when commit has no sha", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/link-commits", domain.LinkCommits{
+			Commits: []domain.CommitRef{{Message: "Fix bf-0001"}},
+		})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- Tests: Realm Settings ---
+
+func TestSetRealmSettingHandler(t *testing.T) {
+	t.Run("sets setting on the request realm and returns 204", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.realm_exists_in_event_store("realm-1")
+
+		// When
+		tc.post("/set-realm-setting", domain.SetRealmSetting{
+			Key:   "github.token",
+			Value: "abc",
+		})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("returns 404 when realm does not exist", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-missing")
+		tc.realm_does_not_exist("realm-missing")
+
+		// When
+		tc.post("/set-realm-setting", domain.SetRealmSetting{
+			Key:   "github.token",
+			Value: "abc",
+		})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestDeleteRealmSettingHandler(t *testing.T) {
+	t.Run("returns 404 when setting does not exist", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.realm_exists_in_event_store("realm-1")
+
+		// When
+		tc.post("/delete-realm-setting", domain.DeleteRealmSetting{Key: "github.token"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestGetRealmSettingsHandler(t *testing.T) {
+	t.Run("returns settings with secrets masked", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_realm_settings("realm-1", map[string]string{
+			"github.token":        "abc",
+			"github.auto_fulfill": "true",
+		})
+
+		// When
+		tc.get("/realm-settings")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"github.auto_fulfill":"true"`)
+		tc.response_body_contains(`"github.token":"********"`)
+	})
+
+	t.Run("masks incoming webhook URLs", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_realm_settings("realm-1", map[string]string{
+			notify.SlackWebhookURLSetting:   "https://hooks.slack.com/services/T0/B0/secret",
+			notify.DiscordWebhookURLSetting: "https://discord.com/api/webhooks/1/abc",
+			notify.SlackAPIURLSetting:       "https://slack.com/api",
+		})
+
+		// When
+		tc.get("/realm-settings")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"slack.webhook_url":"********"`)
+		tc.response_body_contains(`"discord.webhook_url":"********"`)
+		tc.response_body_contains(`"slack.api_url":"https://slack.com/api"`)
+	})
+
+	t.Run("returns empty object when no settings exist", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/realm-settings")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`{}`)
+	})
+}
+
+// --- Tests: TestNotification ---
+
+func TestTestNotificationHandler(t *testing.T) {
+	t.Run("sends test notification and returns 204", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_notifier(nil)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-notification", map[string]string{"channel": "slack"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		tc.notifier_was_called_with("realm-1", "slack")
+	})
+
+	t.Run("returns 502 when delivery fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_notifier(&notify.DeliveryError{Channel: "slack", Err: fmt.Errorf("timeout")})
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-notification", map[string]string{"channel": "slack"})
+
+		// Then
+		tc.status_is(http.StatusBadGateway)
+	})
+
+	t.Run("returns 400 for unknown channel", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_notifier(domain.Rejectf(domain.ErrInvalidCommand, "unknown notification channel %q", "fax"))
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-notification", map[string]string{"channel": "fax"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("returns 501 when notifications are not enabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-notification", map[string]string{"channel": "slack"})
+
+		// Then
+		tc.status_is(http.StatusNotImplemented)
+	})
+}
+
+// --- Tests: ImportGitHub ---
+
+func TestImportGitHubHandler(t *testing.T) {
+	t.Run("imports repository and returns 200 with result", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_github_importer(integrations.GitHubImportResult{
+			Issues:     map[int]string{1: "bf-a1b2"},
+			Milestones: map[int]string{},
+		}, nil)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/import-github", integrations.GitHubImportRequest{Repository: "acme/app"})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.content_type_is_json()
+		tc.response_body_has_field("issues")
+		tc.github_importer_was_called_with("realm-1", "acme/app")
+	})
+
+	t.Run("returns 502 when the GitHub API fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_github_importer(integrations.GitHubImportResult{}, &integrations.GitHubAPIError{StatusCode: 404, Message: "Not Found"})
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/import-github", integrations.GitHubImportRequest{Repository: "acme/missing"})
+
+		// Then
+		tc.status_is(http.StatusBadGateway)
+	})
+
+	t.Run("returns 400 for invalid repository", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_github_importer(integrations.GitHubImportResult{}, domain.Rejectf(domain.ErrInvalidCommand, "cannot import: repository must be in owner/name form"))
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/import-github", integrations.GitHubImportRequest{Repository: "acme"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("returns 501 when import is not enabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/import-github", integrations.GitHubImportRequest{Repository: "acme/app"})
+
+		// Then
+		tc.status_is(http.StatusNotImplemented)
+	})
+}
+
 // --- Tests: CreateRealm ---
 
 func TestCreateRealmHandler(t *testing.T) {
@@ -593,6 +982,23 @@ func TestListRunesHandler(t *testing.T) {
 		tc.response_array_all_have_field_value("assignee", "alice")
 	})
 
+	t.Run("filters runes by claimant query parameter", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_runes_with_claimants("realm-1")
+
+		// When
+		tc.get("/runes?claimant=alice")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_array_has_length(1)
+		tc.response_array_all_have_field_value("id", "bf-0002")
+	})
+
 	t.Run("returns empty array when no runes match filter", func(t *testing.T) {
 		tc := newHandlerTestContext(t)
 
@@ -896,6 +1302,86 @@ func TestGetRuneHandler(t *testing.T) {
 	})
 }
 
+func TestReadReplica(t *testing.T) {
+	t.Run("serves queries from the replica", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_read_replica()
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.replica.put("realm-1", "rune_detail", "bf-0001", map[string]any{"id": "bf-0001"})
+
+		// When
+		tc.get("/rune?id=bf-0001")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_has_field("id")
+	})
+
+	t.Run("runs commands against the primary", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_read_replica()
+		tc.replica.forceError = true
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.rune_is_sealed_in_event_store("realm-1", "bf-0001")
+		tc.projection_has_rune_summary("realm-1", "bf-0001", "sealed")
+
+		// When
+		tc.post("/sweep-runes", nil)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_shattered_contains("bf-0001")
+	})
+}
+
+// --- Tests: Dashboard ---
+
+func TestDashboardHandler(t *testing.T) {
+	t.Run("returns the precomputed dashboard stats", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		_ = tc.projectionStore.Put(context.Background(), "realm-1", "dashboard_stats", "summary", projectors.DashboardStats{
+			Total:        1,
+			StatusCounts: map[string]int{"open": 1},
+			Recent:       []projectors.DashboardRune{{ID: "bf-0001", Title: "Bridge", Status: "open"}},
+		})
+
+		// When
+		tc.get("/dashboard")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.content_type_is_json()
+		tc.response_body_contains(`"total":1`)
+		tc.response_body_contains(`"status_counts":{"open":1}`)
+		tc.response_body_contains(`"id":"bf-0001"`)
+	})
+
+	t.Run("returns empty stats for a realm without runes", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/dashboard")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"total":0,"status_counts":{},"recent":[]}`)
+	})
+}
+
 // --- Tests: AssignRole ---
 
 func TestAssignRoleHandler(t *testing.T) {
@@ -1173,16 +1659,64 @@ func TestRegisterRoutes(t *testing.T) {
 		tc.route_exists("POST", "/api/forge-rune")
 		tc.route_exists("POST", "/api/seal-rune")
 		tc.route_exists("POST", "/api/shatter-rune")
+		tc.route_exists("POST", "/api/purge-rune")
 		tc.route_exists("POST", "/api/sweep-runes")
 		tc.route_exists("POST", "/api/add-dependency")
 		tc.route_exists("POST", "/api/remove-dependency")
 		tc.route_exists("POST", "/api/add-note")
+		tc.route_exists("POST", "/api/link-commits")
 		tc.route_exists("GET", "/api/runes")
 		tc.route_exists("GET", "/api/rune")
+		tc.route_exists("GET", "/api/rune/{id}/impact")
+		tc.route_exists("GET", "/api/rune/{id}/children")
+		tc.route_exists("GET", "/api/features")
+		tc.route_exists("GET", "/api/dashboard")
+		tc.route_exists("GET", "/api/command")
 		tc.route_exists("POST", "/api/create-realm")
 		tc.route_exists("GET", "/api/realms")
+		tc.route_exists("GET", "/api/backup-status")
+		tc.route_exists("GET", "/api/projection-status")
+		tc.route_exists("GET", "/api/dead-letters")
+		tc.route_exists("POST", "/api/rebuild-projection")
+		tc.route_exists("GET", "/api/replay-projectors")
+		tc.route_exists("POST", "/api/replay-projections")
+		tc.route_exists("POST", "/api/export-realm")
+		tc.route_exists("POST", "/api/import-realm")
 		tc.route_exists("POST", "/api/assign-role")
 		tc.route_exists("POST", "/api/revoke-role")
+		tc.route_exists("POST", "/api/set-realm-setting")
+		tc.route_exists("POST", "/api/delete-realm-setting")
+		tc.route_exists("GET", "/api/realm-settings")
+		tc.route_exists("POST", "/api/test-notification")
+		tc.route_exists("POST", "/api/import-github")
+		tc.route_exists("POST", "/api/mcp")
+		tc.route_exists("POST", "/api/calendar-token")
+		tc.route_exists("GET", "/api/stats/")
+		tc.route_exists("POST", "/api/stats/metrics")
+		tc.route_exists("POST", "/api/stats/query")
+		tc.route_exists("GET", "/api/reports/velocity")
+		tc.route_exists("GET", "/api/reports/sla")
+		tc.route_exists("POST", "/api/add-automation-rule")
+		tc.route_exists("POST", "/api/remove-automation-rule")
+		tc.route_exists("GET", "/api/automation-rules")
+		tc.route_exists("POST", "/api/add-escalation-policy")
+		tc.route_exists("POST", "/api/remove-escalation-policy")
+		tc.route_exists("GET", "/api/escalation-policies")
+		tc.route_exists("POST", "/api/create-schedule")
+		tc.route_exists("POST", "/api/delete-schedule")
+		tc.route_exists("GET", "/api/schedules")
+		tc.route_exists("POST", "/api/register-webhook")
+		tc.route_exists("POST", "/api/remove-webhook")
+		tc.route_exists("POST", "/api/test-webhook")
+		tc.route_exists("GET", "/api/webhooks")
+		tc.route_exists("GET", "/api/webhook-deliveries")
+		tc.route_exists("POST", "/api/create-saved-search")
+		tc.route_exists("POST", "/api/update-saved-search")
+		tc.route_exists("POST", "/api/delete-saved-search")
+		tc.route_exists("GET", "/api/saved-searches")
+		tc.route_exists("POST", "/api/save-draft")
+		tc.route_exists("POST", "/api/discard-draft")
+		tc.route_exists("GET", "/api/drafts")
 	})
 }
 
@@ -1334,6 +1868,22 @@ type handlerTestContext struct {
 	eventStore      *mockEventStore
 	projectionStore *mockProjectionStore
 	engine          *mockProjectionEngine
+	notifier        *mockNotifier
+	githubImporter  *mockGitHubImporter
+	checkpoints     *mockCheckpointStore
+	commandQueue    *mockCommandQueue
+	drafts          *mockDraftStore
+	webhookSender   *mockWebhookSender
+	executor        *mockCommandExecutor
+	middleware      []CommandMiddleware
+	backups         BackupStatus
+	projections     ProjectionStatus
+	deadLetters     core.DeadLetterStore
+	rebuilder       *mockProjectionRebuilder
+	search          core.SearchIndex
+	syncReceipts    *mockSyncReceiptStore
+	replica         *mockProjectionStore
+	history         []core.Event
 	handlers        *Handlers
 
 	// HTTP
@@ -1364,12 +1914,98 @@ func newHandlerTestContext(t *testing.T) *handlerTestContext {
 
 func (tc *handlerTestContext) handlers_configured() {
 	tc.t.Helper()
-	tc.handlers = NewHandlers(tc.eventStore, tc.projectionStore, tc.engine)
+	var opts []HandlersOption
+	if tc.notifier != nil {
+		opts = append(opts, WithNotifier(tc.notifier))
+	}
+	if tc.githubImporter != nil {
+		opts = append(opts, WithGitHubImporter(tc.githubImporter))
+	}
+	if tc.checkpoints != nil {
+		opts = append(opts, WithCheckpointStore(tc.checkpoints))
+	}
+	if tc.commandQueue != nil {
+		opts = append(opts, WithCommandQueue(tc.commandQueue, tc.commandQueue.limit))
+	}
+	if tc.drafts != nil {
+		opts = append(opts, WithDraftStore(tc.drafts))
+	}
+	if tc.webhookSender != nil {
+		opts = append(opts, WithWebhookSender(tc.webhookSender))
+	}
+	if len(tc.middleware) > 0 {
+		opts = append(opts, WithCommandMiddleware(tc.middleware...))
+	}
+	if tc.backups != nil {
+		opts = append(opts, WithBackupStatus(tc.backups))
+	}
+	if tc.projections != nil {
+		opts = append(opts, WithProjectionStatus(tc.projections))
+	}
+	if tc.deadLetters != nil {
+		opts = append(opts, WithDeadLetterStore(tc.deadLetters))
+	}
+	if tc.rebuilder != nil {
+		opts = append(opts, WithProjectionRebuilder(tc.rebuilder))
+	}
+	if tc.search != nil {
+		opts = append(opts, WithSearchIndex(tc.search))
+	}
+	if tc.syncReceipts != nil {
+		opts = append(opts, WithSyncReceiptStore(tc.syncReceipts))
+	}
+	if tc.replica != nil {
+		opts = append(opts, WithReadReplica(tc.replica, &mockCheckpointStore{}))
+	}
+	var engine ProjectionEngine = tc.engine
+	if tc.executor != nil {
+		engine = tc.executor
+	}
+	tc.handlers = NewHandlers(tc.eventStore, tc.projectionStore, engine, opts...)
+}
+
+func (tc *handlerTestContext) a_command_executor() {
+	tc.t.Helper()
+	tc.executor = &mockCommandExecutor{eventStore: tc.eventStore, projectionStore: tc.projectionStore}
+}
+
+func (tc *handlerTestContext) a_read_replica() {
+	tc.t.Helper()
+	tc.replica = newMockProjectionStore()
+}
+
+func (tc *handlerTestContext) a_notifier(sendErr error) {
+	tc.t.Helper()
+	tc.notifier = &mockNotifier{err: sendErr}
+}
+
+func (tc *handlerTestContext) a_github_importer(result integrations.GitHubImportResult, importErr error) {
+	tc.t.Helper()
+	tc.githubImporter = &mockGitHubImporter{result: result, err: importErr}
 }
 
 func (tc *handlerTestContext) request_has_realm_id(realmID string) {
 	tc.t.Helper()
 	tc.realmID = realmID
+	tc.realm_exists(realmID, "active")
+}
+
+func (tc *handlerTestContext) realm_does_not_exist(realmID string) {
+	tc.t.Helper()
+	delete(tc.eventStore.streams, tc.eventStore.streamKey(domain.AdminRealmID, "realm-"+realmID))
+}
+
+// realm_exists records the realm's stream, which commands check before
+// writing to the realm.
+func (tc *handlerTestContext) realm_exists(realmID, status string) {
+	tc.t.Helper()
+	data, _ := json.Marshal(domain.RealmCreated{RealmID: realmID, Name: realmID})
+	events := []core.Event{{EventType: domain.EventRealmCreated, Data: data}}
+	if status == "suspended" {
+		data, _ := json.Marshal(domain.RealmSuspended{RealmID: realmID})
+		events = append(events, core.Event{EventType: domain.EventRealmSuspended, Data: data})
+	}
+	tc.eventStore.streams[tc.eventStore.streamKey(domain.AdminRealmID, "realm-"+realmID)] = events
 }
 
 func (tc *handlerTestContext) request_has_account_id(accountID string) {
@@ -1460,6 +2096,18 @@ func (tc *handlerTestContext) account_has_role_in_event_store(accountID, realmID
 	tc.eventStore.appendToStream("_admin", "account-"+accountID, domain.EventRoleAssigned, assigned)
 }
 
+func (tc *handlerTestContext) realm_exists_in_event_store(realmID string) {
+	tc.t.Helper()
+	created := domain.RealmCreated{RealmID: realmID, Name: "Test Realm"}
+	tc.eventStore.appendToStream("_admin", "realm-"+realmID, domain.EventRealmCreated, created)
+}
+
+func (tc *handlerTestContext) projection_has_realm_settings(realmID string, settings map[string]string) {
+	tc.t.Helper()
+	entry := projectors.RealmSettingsEntry{RealmID: realmID, Settings: settings}
+	_ = tc.projectionStore.Put(context.Background(), "_admin", "realm_settings", realmID, entry)
+}
+
 func (tc *handlerTestContext) projection_has_rune_summary(realmID, runeID, status string) {
 	tc.t.Helper()
 	summary := projectors.RuneSummary{ID: runeID, Status: status}
@@ -1504,6 +2152,19 @@ func (tc *handlerTestContext) projection_has_mixed_runes(realmID string) {
 	})
 }
 
+func (tc *handlerTestContext) projection_has_runes_with_claimants(realmID string) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", "bf-0001", map[string]any{
+		"id": "bf-0001", "title": "Open Rune", "status": "open", "priority": float64(0),
+	})
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", "bf-0002", map[string]any{
+		"id": "bf-0002", "title": "Alice's Rune", "status": "claimed", "priority": float64(0), "claimant": "alice",
+	})
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", "bf-0003", map[string]any{
+		"id": "bf-0003", "title": "Bob's Rune", "status": "claimed", "priority": float64(0), "claimant": "bob",
+	})
+}
+
 func (tc *handlerTestContext) projection_has_runes_with_branches(realmID string) {
 	tc.t.Helper()
 	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", "bf-0001", map[string]any{
@@ -1608,6 +2269,12 @@ func (tc *handlerTestContext) routes_are_registered() {
 
 // --- Then ---
 
+func (tc *handlerTestContext) command_was_executed_in(realmID string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, []string{realmID}, tc.executor.realmIDs)
+	assert.NotEmpty(tc.t, tc.eventStore.streams)
+}
+
 func (tc *handlerTestContext) status_is(code int) {
 	tc.t.Helper()
 	assert.Equal(tc.t, code, tc.recorder.Code)
@@ -1623,6 +2290,11 @@ func (tc *handlerTestContext) response_body_contains(substr string) {
 	assert.Contains(tc.t, tc.recorder.Body.String(), substr)
 }
 
+func (tc *handlerTestContext) response_body_does_not_contain(substr string) {
+	tc.t.Helper()
+	assert.NotContains(tc.t, tc.recorder.Body.String(), substr)
+}
+
 func (tc *handlerTestContext) response_body_equals(expected string) {
 	tc.t.Helper()
 	actual := tc.recorder.Body.String()
@@ -1706,6 +2378,18 @@ func (tc *handlerTestContext) response_array_all_have_field_value(field, expecte
 	}
 }
 
+func (tc *handlerTestContext) notifier_was_called_with(realmID, channel string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, realmID, tc.notifier.realmID)
+	assert.Equal(tc.t, channel, tc.notifier.channel)
+}
+
+func (tc *handlerTestContext) github_importer_was_called_with(realmID, repository string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, realmID, tc.githubImporter.realmID)
+	assert.Equal(tc.t, repository, tc.githubImporter.req.Repository)
+}
+
 func (tc *handlerTestContext) route_exists(method, path string) {
 	tc.t.Helper()
 	req := httptest.NewRequest(method, path, nil)
@@ -1726,7 +2410,10 @@ func (tc *handlerTestContext) response_shattered_contains(runeID string) {
 // --- Mock Event Store ---
 
 type mockEventStore struct {
-	streams map[string][]core.Event
+	streams  map[string][]core.Event
+	position int64
+	// Idempotency keys of the appended batches, in order
+	idempotencyKeys []string
 }
 
 func newMockEventStore() *mockEventStore {
@@ -1742,12 +2429,14 @@ func (m *mockEventStore) streamKey(realmID, streamID string) string {
 func (m *mockEventStore) appendToStream(realmID, streamID, eventType string, data any) {
 	key := m.streamKey(realmID, streamID)
 	dataBytes, _ := json.Marshal(data)
+	m.position++
 	evt := core.Event{
-		RealmID:   realmID,
-		StreamID:  streamID,
-		Version:   len(m.streams[key]),
-		EventType: eventType,
-		Data:      dataBytes,
+		RealmID:        realmID,
+		StreamID:       streamID,
+		Version:        len(m.streams[key]) + 1,
+		GlobalPosition: m.position,
+		EventType:      eventType,
+		Data:           dataBytes,
 	}
 	m.streams[key] = append(m.streams[key], evt)
 }
@@ -1765,12 +2454,17 @@ func (m *mockEventStore) Append(_ context.Context, realmID string, streamID stri
 	var appended []core.Event
 	for _, ed := range events {
 		dataBytes, _ := json.Marshal(ed.Data)
+		m.position++
 		evt := core.Event{
-			RealmID:   realmID,
-			StreamID:  streamID,
-			Version:   len(m.streams[key]),
-			EventType: ed.EventType,
-			Data:      dataBytes,
+			RealmID:        realmID,
+			StreamID:       streamID,
+			Version:        len(m.streams[key]) + 1,
+			GlobalPosition: m.position,
+			EventType:      ed.EventType,
+			Data:           dataBytes,
+		}
+		if ed.IdempotencyKey != "" {
+			m.idempotencyKeys = append(m.idempotencyKeys, ed.IdempotencyKey)
 		}
 		m.streams[key] = append(m.streams[key], evt)
 		appended = append(appended, evt)
@@ -1781,14 +2475,25 @@ func (m *mockEventStore) Append(_ context.Context, realmID string, streamID stri
 func (m *mockEventStore) ReadStream(_ context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
 	key := m.streamKey(realmID, streamID)
 	events := m.streams[key]
-	if fromVersion >= len(events) {
+	// Versions start at 1, and fromVersion is included.
+	start := max(fromVersion-1, 0)
+	if start >= len(events) {
 		return nil, nil
 	}
-	return events[fromVersion:], nil
+	return events[start:], nil
 }
 
 func (m *mockEventStore) ReadAll(_ context.Context, realmID string, fromGlobalPosition int64) ([]core.Event, error) {
-	return nil, nil
+	var events []core.Event
+	for _, stream := range m.streams {
+		for _, evt := range stream {
+			if evt.RealmID == realmID && evt.GlobalPosition > fromGlobalPosition {
+				events = append(events, evt)
+			}
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].GlobalPosition < events[j].GlobalPosition })
+	return events, nil
 }
 
 func (m *mockEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
@@ -1808,4 +2513,45 @@ func (m *mockProjectionEngine) RunSync(ctx context.Context, events []core.Event)
 
 func (m *mockProjectionEngine) RunCatchUpOnce(ctx context.Context) {}
 
+// --- Mock Command Executor ---
+
+type mockCommandExecutor struct {
+	mockProjectionEngine
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	realmIDs        []string
+}
+
+func (m *mockCommandExecutor) Execute(ctx context.Context, realmID string, command func(ctx context.Context, uow core.UnitOfWork) error) error {
+	m.realmIDs = append(m.realmIDs, realmID)
+	return command(ctx, core.UnitOfWork{EventStore: m.eventStore, ProjectionStore: m.projectionStore})
+}
+
+// --- Mock Notifier ---
+
+type mockNotifier struct {
+	realmID string
+	channel string
+	err     error
+}
+
+func (m *mockNotifier) SendTest(_ context.Context, realmID, channel string) error {
+	m.realmID = realmID
+	m.channel = channel
+	return m.err
+}
+
+type mockGitHubImporter struct {
+	realmID string
+	req     integrations.GitHubImportRequest
+	result  integrations.GitHubImportResult
+	err     error
+}
+
+func (m *mockGitHubImporter) ImportGitHub(_ context.Context, realmID string, req integrations.GitHubImportRequest) (integrations.GitHubImportResult, error) {
+	m.realmID = realmID
+	m.req = req
+	return m.result, m.err
+}
+
 func strPtr(s string) *string { return &s }
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+)
+
+// projectionSource names a projector whose output a response is built from.
+type projectionSource struct {
+	realmID   string
+	projector string
+}
+
+// notModified sets a weak ETag built from the checkpoints of sources, and
+// answers 304 when the request already holds that version. A checkpoint only
+// moves when its projector has written, so while none of them moves the
+// response is unchanged. Without a checkpoint store, or if one cannot be
+// read, the response is served uncached.
+func (h *Handlers) notModified(w http.ResponseWriter, r *http.Request, sources ...projectionSource) bool {
+	if h.checkpoints == nil {
+		return false
+	}
+
+	hash := fnv.New64a()
+	for _, src := range sources {
+		pos, err := h.checkpoints.GetCheckpoint(r.Context(), src.realmID, src.projector)
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00%d\x00", src.realmID, src.projector, pos)
+	}
+	etag := fmt.Sprintf(`W/"%x"`, hash.Sum64())
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Add("Vary", "X-Bifrost-Realm")
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison of If-None-Match.
+func etagMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjectionETags(t *testing.T) {
+	t.Run("answers 304 when the rune projection has not moved", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.checkpoints_at("realm-1", "rune_detail", 7)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_rune_detail("realm-1", "bf-0001")
+		etag := tc.etag_of("/rune?id=bf-0001")
+
+		// When
+		tc.get_if_none_match("/rune?id=bf-0001", etag)
+
+		// Then
+		tc.status_is(http.StatusNotModified)
+		assert.Empty(t, tc.recorder.Body.String())
+	})
+
+	t.Run("serves the rune again once its projection moves", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.checkpoints_at("realm-1", "rune_detail", 7)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_rune_detail("realm-1", "bf-0001")
+		etag := tc.etag_of("/rune?id=bf-0001")
+		tc.checkpoints_at("realm-1", "rune_detail", 8)
+
+		// When
+		tc.get_if_none_match("/rune?id=bf-0001", etag)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.NotEqual(t, etag, tc.recorder.Header().Get("ETag"))
+	})
+
+	t.Run("keys the rune list on every projection it reads", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.checkpoints_at("realm-1", "rune_list", 3)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_mixed_runes("realm-1")
+		etag := tc.etag_of("/runes")
+		tc.checkpoints_at("_admin", "account_lookup", 4)
+
+		// When
+		tc.get_if_none_match("/runes", etag)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_array_has_length(3)
+	})
+
+	t.Run("marks responses for revalidation", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.checkpoints_at("realm-1", "rune_list", 3)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/runes")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, "private, no-cache", tc.recorder.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, tc.recorder.Header().Get("ETag"))
+	})
+
+	t.Run("sends no ETag without a checkpoint store", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/runes")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Empty(t, tc.recorder.Header().Get("ETag"))
+	})
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"x", W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`*`, `W/"abc"`))
+	assert.False(t, etagMatches(``, `W/"abc"`))
+	assert.False(t, etagMatches(`W/"abd"`, `W/"abc"`))
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) checkpoints_at(realmID, projector string, pos int64) {
+	tc.t.Helper()
+	if tc.checkpoints == nil {
+		tc.checkpoints = &mockCheckpointStore{positions: map[string]int64{}}
+	}
+	tc.checkpoints.positions[realmID+"/"+projector] = pos
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) etag_of(path string) string {
+	tc.t.Helper()
+	tc.get(path)
+	require.Equal(tc.t, http.StatusOK, tc.recorder.Code)
+	etag := tc.recorder.Header().Get("ETag")
+	require.NotEmpty(tc.t, etag)
+	tc.recorder = httptest.NewRecorder()
+	return etag
+}
+
+func (tc *handlerTestContext) get_if_none_match(path, etag string) {
+	tc.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("If-None-Match", etag)
+	req = req.WithContext(tc.build_context(req.Context()))
+	tc.handlers.ServeHTTP(tc.recorder, req)
+}
+
+// --- Mock Checkpoint Store ---
+
+type mockCheckpointStore struct {
+	positions map[string]int64
+}
+
+func (m *mockCheckpointStore) GetCheckpoint(_ context.Context, realmID string, projectorName string) (int64, error) {
+	return m.positions[realmID+"/"+projectorName], nil
+}
+
+func (m *mockCheckpointStore) SetCheckpoint(_ context.Context, realmID string, projectorName string, globalPosition int64) error {
+	m.positions[realmID+"/"+projectorName] = globalPosition
+	return nil
+}
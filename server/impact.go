@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// ImpactRune is a rune named in an impact report.
+type ImpactRune struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+}
+
+// RuneImpact reports what fulfilling a rune would change. Unblocked lists the
+// open runes it blocks whose other blockers are all fulfilled. CriticalPath
+// is the longest chain of open runes it transitively blocks, starting with
+// the rune itself.
+type RuneImpact struct {
+	RuneID       string       `json:"rune_id"`
+	Unblocked    []ImpactRune `json:"unblocked"`
+	CriticalPath []ImpactRune `json:"critical_path"`
+}
+
+// RuneImpact serves GET /rune/{id}/impact from the dependency graph and rune
+// list projections.
+func (h *Handlers) RuneImpact(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	if h.notModified(w, r,
+		projectionSource{realmID, "rune_list"},
+		projectionSource{realmID, "dependency_graph"},
+	) {
+		return
+	}
+
+	a := &impactAnalysis{ctx: r.Context(), store: h.projectionStore, realmID: realmID,
+		runes: make(map[string]ImpactRune), graph: make(map[string]projectors.GraphEntry)}
+	runeID := r.PathValue("id")
+	root, err := a.rune(runeID)
+	if err != nil {
+		if isNotFound(err) {
+			writeError(w, http.StatusNotFound, "rune not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to analyse rune impact")
+		return
+	}
+
+	impact := RuneImpact{RuneID: runeID, Unblocked: []ImpactRune{}}
+	if isOpenStatus(root.Status) {
+		impact.Unblocked, err = a.unblockedBy(runeID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to analyse rune impact")
+			return
+		}
+	}
+	path, err := a.longestChain(runeID, make(map[string][]string), make(map[string]bool))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to analyse rune impact")
+		return
+	}
+	for _, id := range path {
+		impact.CriticalPath = append(impact.CriticalPath, a.runes[id])
+	}
+	writeJSON(w, http.StatusOK, impact)
+}
+
+// isOpenStatus reports whether a rune is still waiting to be done, and so
+// can be blocked.
+func isOpenStatus(status string) bool {
+	return status != "" && status != "fulfilled" && status != "sealed" && status != "shattered"
+}
+
+// impactAnalysis caches the projections an impact report reads, since the
+// same runes are reached along many paths.
+type impactAnalysis struct {
+	ctx     context.Context
+	store   core.ProjectionStore
+	realmID string
+	runes   map[string]ImpactRune
+	graph   map[string]projectors.GraphEntry
+}
+
+func (a *impactAnalysis) rune(runeID string) (ImpactRune, error) {
+	if ir, ok := a.runes[runeID]; ok {
+		return ir, nil
+	}
+	var summary projectors.RuneSummary
+	if err := a.store.Get(a.ctx, a.realmID, "rune_list", runeID, &summary); err != nil {
+		return ImpactRune{}, err
+	}
+	ir := ImpactRune{ID: runeID, Title: summary.Title, Status: summary.Status, Priority: summary.Priority}
+	a.runes[runeID] = ir
+	return ir, nil
+}
+
+// status returns the rune's status, or "" for a rune missing from the list.
+func (a *impactAnalysis) status(runeID string) (string, error) {
+	ir, err := a.rune(runeID)
+	if isNotFound(err) {
+		return "", nil
+	}
+	return ir.Status, err
+}
+
+func (a *impactAnalysis) entry(runeID string) (projectors.GraphEntry, error) {
+	if entry, ok := a.graph[runeID]; ok {
+		return entry, nil
+	}
+	var entry projectors.GraphEntry
+	if err := a.store.Get(a.ctx, a.realmID, "dependency_graph", runeID, &entry); err != nil && !isNotFound(err) {
+		return projectors.GraphEntry{}, err
+	}
+	a.graph[runeID] = entry
+	return entry, nil
+}
+
+// blocks returns the open runes that runeID blocks, sorted by ID.
+func (a *impactAnalysis) blocks(runeID string) ([]string, error) {
+	entry, err := a.entry(runeID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, dep := range entry.Dependencies {
+		if dep.Relationship != domain.RelBlocks {
+			continue
+		}
+		status, err := a.status(dep.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if isOpenStatus(status) {
+			ids = append(ids, dep.TargetID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// unblockedBy returns the open runes blocked by runeID whose other blockers
+// are all fulfilled, matching how /runes?blocked=false decides.
+func (a *impactAnalysis) unblockedBy(runeID string) ([]ImpactRune, error) {
+	targets, err := a.blocks(runeID)
+	if err != nil {
+		return nil, err
+	}
+	unblocked := []ImpactRune{}
+	for _, target := range targets {
+		entry, err := a.entry(target)
+		if err != nil {
+			return nil, err
+		}
+		free := true
+		for _, blocker := range entry.Dependents {
+			if blocker.Relationship != domain.RelBlocks || blocker.SourceID == runeID {
+				continue
+			}
+			status, err := a.status(blocker.SourceID)
+			if err != nil {
+				return nil, err
+			}
+			if status != "fulfilled" {
+				free = false
+				break
+			}
+		}
+		if free {
+			unblocked = append(unblocked, a.runes[target])
+		}
+	}
+	return unblocked, nil
+}
+
+// longestChain returns the longest path of blocks edges from runeID through
+// open runes. Commands reject blocking cycles, but a rune already on the
+// path is skipped so a damaged graph cannot recurse forever.
+func (a *impactAnalysis) longestChain(runeID string, memo map[string][]string, onPath map[string]bool) ([]string, error) {
+	if chain, ok := memo[runeID]; ok {
+		return chain, nil
+	}
+	targets, err := a.blocks(runeID)
+	if err != nil {
+		return nil, err
+	}
+	onPath[runeID] = true
+	var longest []string
+	for _, target := range targets {
+		if onPath[target] {
+			continue
+		}
+		chain, err := a.longestChain(target, memo, onPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(chain) > len(longest) {
+			longest = chain
+		}
+	}
+	delete(onPath, runeID)
+	chain := append([]string{runeID}, longest...)
+	memo[runeID] = chain
+	return chain, nil
+}
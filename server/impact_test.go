@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRuneImpactHandler(t *testing.T) {
+	t.Run("lists open runes whose only open blocker is the rune", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.impact_rune("realm-1", "bf-a", "open")
+		tc.impact_rune("realm-1", "bf-b", "open")
+		tc.impact_rune("realm-1", "bf-c", "open")
+		tc.impact_rune("realm-1", "bf-d", "open")
+		tc.impact_rune("realm-1", "bf-e", "fulfilled")
+		tc.impact_rune("realm-1", "bf-f", "fulfilled")
+		tc.impact_blocks("realm-1", "bf-a", "bf-b")
+		tc.impact_blocks("realm-1", "bf-a", "bf-c")
+		tc.impact_blocks("realm-1", "bf-d", "bf-c")
+		tc.impact_blocks("realm-1", "bf-e", "bf-b")
+		tc.impact_blocks("realm-1", "bf-a", "bf-f")
+
+		// When
+		tc.get("/rune/bf-a/impact")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		impact := tc.rune_impact()
+		assert.Equal(t, "bf-a", impact.RuneID)
+		assert.Equal(t, []string{"bf-b"}, impactIDs(impact.Unblocked))
+	})
+
+	t.Run("returns the longest chain of open runes the rune blocks", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		for _, id := range []string{"bf-a", "bf-b", "bf-c", "bf-d", "bf-e"} {
+			tc.impact_rune("realm-1", id, "open")
+		}
+		tc.impact_rune("realm-1", "bf-x", "sealed")
+		tc.impact_blocks("realm-1", "bf-a", "bf-b")
+		tc.impact_blocks("realm-1", "bf-a", "bf-c")
+		tc.impact_blocks("realm-1", "bf-c", "bf-d")
+		tc.impact_blocks("realm-1", "bf-d", "bf-e")
+		tc.impact_blocks("realm-1", "bf-b", "bf-x")
+
+		// When
+		tc.get("/rune/bf-a/impact")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, []string{"bf-a", "bf-c", "bf-d", "bf-e"}, impactIDs(tc.rune_impact().CriticalPath))
+	})
+
+	t.Run("reports nothing unblocked for a rune that is already fulfilled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.impact_rune("realm-1", "bf-a", "fulfilled")
+		tc.impact_rune("realm-1", "bf-b", "open")
+		tc.impact_blocks("realm-1", "bf-a", "bf-b")
+
+		// When
+		tc.get("/rune/bf-a/impact")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		impact := tc.rune_impact()
+		assert.Empty(t, impact.Unblocked)
+		assert.Equal(t, []string{"bf-a", "bf-b"}, impactIDs(impact.CriticalPath))
+	})
+
+	t.Run("returns 404 for an unknown rune", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/rune/bf-missing/impact")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) impact_rune(realmID, runeID, status string) {
+	tc.t.Helper()
+	summary := projectors.RuneSummary{ID: runeID, Title: "Rune " + runeID, Status: status}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", runeID, summary)
+}
+
+func (tc *handlerTestContext) impact_blocks(realmID, sourceID, targetID string) {
+	tc.t.Helper()
+	source := tc.impact_graph_entry(realmID, sourceID)
+	source.Dependencies = append(source.Dependencies, projectors.GraphDependency{TargetID: targetID, Relationship: domain.RelBlocks})
+	_ = tc.projectionStore.Put(context.Background(), realmID, "dependency_graph", sourceID, source)
+
+	target := tc.impact_graph_entry(realmID, targetID)
+	target.Dependents = append(target.Dependents, projectors.GraphDependent{SourceID: sourceID, Relationship: domain.RelBlocks})
+	_ = tc.projectionStore.Put(context.Background(), realmID, "dependency_graph", targetID, target)
+}
+
+func (tc *handlerTestContext) impact_graph_entry(realmID, runeID string) projectors.GraphEntry {
+	tc.t.Helper()
+	entry := projectors.GraphEntry{RuneID: runeID}
+	_ = tc.projectionStore.Get(context.Background(), realmID, "dependency_graph", runeID, &entry)
+	return entry
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) rune_impact() RuneImpact {
+	tc.t.Helper()
+	var impact RuneImpact
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &impact))
+	return impact
+}
+
+func impactIDs(runes []ImpactRune) []string {
+	ids := []string{}
+	for _, r := range runes {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+type importRealmResponse struct {
+	RealmID  string `json:"realm_id"`
+	Imported int    `json:"imported"`
+}
+
+// ImportRealm appends the events of a realm export, sent as the request
+// body, to the realm named by the realm_id query parameter. The realm must
+// be active and have no events yet, so dumps are imported into a realm
+// just made with /create-realm.
+func (h *Handlers) ImportRealm(w http.ResponseWriter, r *http.Request) {
+	realmID := r.URL.Query().Get("realm_id")
+	if realmID == "" {
+		writeError(w, http.StatusBadRequest, "realm_id is required")
+		return
+	}
+	if err := domain.RequireActiveRealm(r.Context(), realmID, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	imported, err := core.ImportRealm(r.Context(), h.eventStore, realmID, r.Body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	case errors.Is(err, core.ErrInvalidImport):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, core.ErrRealmNotEmpty):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusOK, importRealmResponse{RealmID: realmID, Imported: imported})
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestImportRealmHandler(t *testing.T) {
+	dump := `{"realm_id":"bf-old","stream_id":"rune-bf-1","version":1,"event_type":"RuneCreated","data":{"id":"bf-1","title":"Bridge"}}` + "\n"
+
+	t.Run("appends the dump to the realm and projects it", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.realm_exists("bf-new", "active")
+		tc.handlers_configured()
+
+		// When
+		tc.dump_is_imported("/import-realm?realm_id=bf-new", dump)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"realm_id":"bf-new","imported":1}`)
+		events, err := tc.eventStore.ReadStream(context.Background(), "bf-new", "rune-bf-1", 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.JSONEq(t, `{"id":"bf-1","title":"Bridge"}`, string(events[0].Data))
+	})
+
+	t.Run("returns 409 for a realm that already has events", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.realm_exists("bf-new", "active")
+		tc.eventStore.appendToStream("bf-new", "rune-bf-9", "RuneCreated", map[string]string{"id": "bf-9"})
+		tc.handlers_configured()
+
+		// When
+		tc.dump_is_imported("/import-realm?realm_id=bf-new", dump)
+
+		// Then
+		tc.status_is(http.StatusConflict)
+	})
+
+	t.Run("returns 400 for a dump that is not events", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.realm_exists("bf-new", "active")
+		tc.handlers_configured()
+
+		// When
+		tc.dump_is_imported("/import-realm?realm_id=bf-new", "not json\n")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("line 1")
+	})
+
+	t.Run("returns 404 for an unknown realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.dump_is_imported("/import-realm?realm_id=bf-404", dump)
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 400 without a realm ID", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.dump_is_imported("/import-realm", dump)
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) dump_is_imported(path, dump string) {
+	tc.t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(dump))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req = req.WithContext(tc.build_context(req.Context()))
+	tc.handlers.ServeHTTP(tc.recorder, req)
+}
@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/devzeebo/bifrost/core"
 	"github.com/devzeebo/bifrost/domain"
@@ -229,6 +231,152 @@ func TestGetRune_E2E(t *testing.T) {
 	})
 }
 
+func TestIdempotencyKey_E2E(t *testing.T) {
+	t.Run("a retried command with the same Idempotency-Key appends nothing", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Retry Realm")
+		tc.a_rune_exists("Flaky network", 1)
+		tc.idempotency_key("note-1")
+		tc.post("/api/add-note", `{"rune_id":"`+tc.lastRuneID+`","text":"sent twice"}`, tc.realmPATToken)
+		tc.status_is(http.StatusNoContent)
+
+		// When
+		tc.post("/api/add-note", `{"rune_id":"`+tc.lastRuneID+`","text":"sent twice"}`, tc.realmPATToken)
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		tc.rune_stream_has_events(tc.lastRuneID, 2)
+	})
+}
+
+func TestCommandPosition_E2E(t *testing.T) {
+	t.Run("a command's response carries the global position of its last event", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Position Realm")
+		tc.a_rune_exists("Track me", 1)
+
+		// When
+		tc.post("/api/add-note", `{"rune_id":"`+tc.lastRuneID+`","text":"noted"}`, tc.realmPATToken)
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		tc.position_is_the_realms_last()
+	})
+
+	t.Run("a created rune's response carries the position of its creation", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Position Realm")
+
+		// When
+		tc.post("/api/create-rune", `{"title":"Fresh","priority":1,"branch":"main"}`, tc.realmPATToken)
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.position_is_the_realms_last()
+	})
+}
+
+func TestEventEncryption_E2E(t *testing.T) {
+	t.Run("serves runes of a realm whose events are stored encrypted", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.events_are_encrypted()
+		tc.server_is_running()
+		tc.a_realm_exists("Secret Realm")
+		tc.a_rune_exists("Launch codes", 1)
+
+		// When
+		tc.get("/api/rune?id="+tc.lastRuneID, tc.realmPATToken)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_json_has("title", "Launch codes")
+		tc.stored_events_do_not_contain("Launch codes")
+	})
+}
+
+func TestPurgeRune_E2E(t *testing.T) {
+	t.Run("scrubs a shattered rune's title from the stored events", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Purge Realm")
+		tc.a_rune_exists("Call Jane Doe", 1)
+		tc.post("/api/seal-rune", `{"id":"`+tc.lastRuneID+`"}`, tc.realmPATToken)
+		tc.status_is(http.StatusNoContent)
+		tc.post("/api/shatter-rune", `{"id":"`+tc.lastRuneID+`"}`, tc.realmPATToken)
+		tc.status_is(http.StatusNoContent)
+
+		// When
+		tc.request_with_realm("POST", "/api/purge-rune", `{"id":"`+tc.lastRuneID+`"}`, tc.adminKey, tc.realmID)
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		tc.stored_events_do_not_contain("Jane Doe")
+		tc.rune_stream_has_events(tc.lastRuneID, 4)
+	})
+}
+
+func TestTruncatedRune_E2E(t *testing.T) {
+	t.Run("rejects a claim of a truncated shattered rune from its snapshot", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Retention Realm")
+		tc.a_rune_exists("Long gone", 1)
+		tc.post("/api/seal-rune", `{"id":"`+tc.lastRuneID+`"}`, tc.realmPATToken)
+		tc.status_is(http.StatusNoContent)
+		tc.post("/api/shatter-rune", `{"id":"`+tc.lastRuneID+`"}`, tc.realmPATToken)
+		tc.status_is(http.StatusNoContent)
+		tc.shattered_runes_are_truncated()
+
+		// When
+		tc.post("/api/claim-rune", `{"id":"`+tc.lastRuneID+`","claimant":"alice"}`, tc.realmPATToken)
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		assert.Contains(t, string(tc.respBody), "shattered")
+	})
+}
+
+func TestRealmExportImport_E2E(t *testing.T) {
+	t.Run("moves a realm's runes into a fresh realm", func(t *testing.T) {
+		tc := newE2EContext(t)
+
+		// Given
+		tc.server_is_running()
+		tc.a_realm_exists("Source Realm")
+		sourceRealmID := tc.realmID
+		tc.a_rune_exists("Carried over", 2)
+		runeID := tc.lastRuneID
+		tc.post("/api/export-realm", `{"realm_id":"`+sourceRealmID+`"}`, tc.adminKey)
+		tc.status_is(http.StatusOK)
+		dump := string(tc.respBody)
+		tc.a_realm_exists("Target Realm")
+
+		// When
+		tc.post("/api/import-realm?realm_id="+tc.realmID, dump, tc.adminKey)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.get("/api/rune?id="+runeID, tc.realmPATToken)
+		tc.status_is(http.StatusOK)
+		tc.response_json_has("title", "Carried over")
+	})
+}
+
 func TestRealmIsolation_E2E(t *testing.T) {
 	t.Run("rune created in one realm is not visible in another via GetRune", func(t *testing.T) {
 		tc := newE2EContext(t)
@@ -283,6 +431,12 @@ type e2eTestContext struct {
 	// Current rune state
 	lastRuneID string
 
+	// Sent as the Idempotency-Key header when set
+	idempotencyKey string
+
+	// Wraps the event store when set
+	encryption *core.RealmEncryption
+
 	// HTTP response
 	resp     *http.Response
 	respBody []byte
@@ -306,8 +460,12 @@ func (tc *e2eTestContext) server_is_running() {
 	db.SetMaxOpenConns(1)
 	tc.db = db
 
-	es, err := sqlite.NewEventStore(db)
+	sqlES, err := sqlite.NewEventStore(db)
 	require.NoError(tc.t, err)
+	var es core.EventStore = sqlES
+	if tc.encryption != nil {
+		es = tc.encryption.EventStore(sqlES)
+	}
 	tc.eventStore = es
 
 	ps, err := sqlite.NewProjectionStore(db)
@@ -381,6 +539,27 @@ func (tc *e2eTestContext) a_rune_exists(title string, priority int) {
 	tc.lastRuneID = tc.respJSON["id"].(string)
 }
 
+func (tc *e2eTestContext) events_are_encrypted() {
+	tc.t.Helper()
+	wrapper, err := core.NewAESKeyWrapper(bytes.Repeat([]byte{42}, 32))
+	require.NoError(tc.t, err)
+	tc.encryption = core.NewRealmEncryption(wrapper, "*")
+}
+
+func (tc *e2eTestContext) idempotency_key(key string) {
+	tc.t.Helper()
+	tc.idempotencyKey = key
+}
+
+func (tc *e2eTestContext) shattered_runes_are_truncated() {
+	tc.t.Helper()
+	n, err := domain.HandleTruncateShatteredRunes(context.Background(), tc.realmID,
+		domain.TruncateShatteredRunes{Before: time.Now().Add(time.Hour)}, tc.eventStore, tc.eventStore.(core.EventTruncator))
+	require.NoError(tc.t, err)
+	require.Equal(tc.t, 1, n)
+	tc.rune_stream_has_events(tc.lastRuneID, 1)
+}
+
 // --- When ---
 
 func (tc *e2eTestContext) get(path string, authToken string) {
@@ -420,6 +599,9 @@ func (tc *e2eTestContext) request_with_realm(method string, path string, body st
 	if realmID != "" {
 		req.Header.Set("X-Bifrost-Realm", realmID)
 	}
+	if tc.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", tc.idempotencyKey)
+	}
 
 	tc.resp, err = http.DefaultClient.Do(req)
 	require.NoError(tc.t, err)
@@ -456,6 +638,30 @@ func (tc *e2eTestContext) response_json_has_key(key string) {
 	assert.True(tc.t, ok, "expected key %q in response JSON: %s", key, string(tc.respBody))
 }
 
+func (tc *e2eTestContext) rune_stream_has_events(runeID string, expected int) {
+	tc.t.Helper()
+	events, err := tc.eventStore.ReadStream(context.Background(), tc.realmID, "rune-"+runeID, 0)
+	require.NoError(tc.t, err)
+	assert.Len(tc.t, events, expected)
+}
+
+func (tc *e2eTestContext) position_is_the_realms_last() {
+	tc.t.Helper()
+	events, err := tc.eventStore.ReadAll(context.Background(), tc.realmID, 0)
+	require.NoError(tc.t, err)
+	require.NotEmpty(tc.t, events)
+	last := events[len(events)-1].GlobalPosition
+	assert.Equal(tc.t, strconv.FormatInt(last, 10), tc.resp.Header.Get("X-Bifrost-Position"))
+}
+
+func (tc *e2eTestContext) stored_events_do_not_contain(text string) {
+	tc.t.Helper()
+	var count int
+	err := tc.db.QueryRow(`SELECT COUNT(*) FROM events WHERE realm_id = ? AND CAST(data AS TEXT) LIKE ?`, tc.realmID, "%"+text+"%").Scan(&count)
+	require.NoError(tc.t, err)
+	assert.Zero(tc.t, count)
+}
+
 // --- Helpers ---
 
 // syncProjectionEngine processes all events from the store synchronously
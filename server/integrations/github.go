@@ -0,0 +1,189 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Realm settings read by the GitHub integration.
+const (
+	GitHubWebhookSecretSetting = "github.webhook_secret"
+	GitHubTokenSetting         = "github.token"
+	GitHubAPIURLSetting        = "github.api_url"
+	GitHubAutoFulfillSetting   = "github.auto_fulfill"
+)
+
+const (
+	defaultGitHubAPIURL = "https://api.github.com"
+	githubStatusContext = "bifrost"
+)
+
+type githubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	PullRequest githubPullRequest `json:"pull_request"`
+	Repository  struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type githubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func handleGitHubWebhook(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		realmID := r.PathValue("realm_id")
+
+		settings, err := projectors.GetRealmSettings(ctx, cfg.ProjectionStore, realmID)
+		if err != nil {
+			http.Error(w, "failed to load realm settings", http.StatusInternalServerError)
+			return
+		}
+		secret := settings[GitHubWebhookSecretSetting]
+		if secret == "" {
+			http.Error(w, "github integration not configured", http.StatusNotFound)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if !validGitHubSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Header.Get("X-GitHub-Event") {
+		case "ping":
+			writeJSON(w, http.StatusOK, map[string]string{"status": "pong"})
+			return
+		case "pull_request":
+		default:
+			writeJSON(w, http.StatusAccepted, map[string]string{"status": "ignored"})
+			return
+		}
+
+		var event githubPullRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid pull_request payload", http.StatusBadRequest)
+			return
+		}
+
+		resp, err := processGitHubPullRequest(ctx, cfg, realmID, settings, event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		cfg.Engine.RunCatchUpOnce(ctx)
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func processGitHubPullRequest(ctx context.Context, cfg *RouteConfig, realmID string, settings map[string]string, event githubPullRequestEvent) (WebhookResponse, error) {
+	pr := event.PullRequest
+	merged := event.Action == "closed" && pr.Merged
+
+	var note string
+	switch {
+	case event.Action == "opened" || event.Action == "reopened":
+		note = fmt.Sprintf("Linked pull request %s#%d: %s (%s)", event.Repository.FullName, pr.Number, pr.Title, pr.HTMLURL)
+	case merged:
+		note = fmt.Sprintf("Pull request %s#%d merged into %s", event.Repository.FullName, pr.Number, pr.Base.Ref)
+	}
+
+	resp, err := applyReviewEvent(ctx, cfg, realmID, "github", reviewEvent{
+		source:       strings.Join([]string{pr.Title, pr.Body, pr.Head.Ref}, "\n"),
+		note:         note,
+		merged:       merged,
+		targetBranch: pr.Base.Ref,
+		autoFulfill:  settings[GitHubAutoFulfillSetting] == "true",
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	if len(resp.Linked) > 0 && event.Action != "closed" {
+		postGitHubStatus(ctx, cfg.HTTPClient, settings, event, resp.Linked)
+	}
+
+	return resp, nil
+}
+
+// postGitHubStatus reports the linked runes as a commit status on the pull
+// request head. Failures are logged and otherwise ignored.
+func postGitHubStatus(ctx context.Context, client *http.Client, settings map[string]string, event githubPullRequestEvent, runeIDs []string) {
+	token := settings[GitHubTokenSetting]
+	sha := event.PullRequest.Head.SHA
+	if token == "" || sha == "" || event.Repository.FullName == "" {
+		return
+	}
+
+	apiURL := settings[GitHubAPIURLSetting]
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", strings.TrimSuffix(apiURL, "/"), event.Repository.FullName, sha)
+
+	payload, _ := json.Marshal(map[string]string{
+		"state":       "success",
+		"context":     githubStatusContext,
+		"description": "Linked to " + strings.Join(runeIDs, ", "),
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("github: build status request: %v", err)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		log.Printf("github: post status: %v", err)
+		return
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		log.Printf("github: post status: unexpected status %d", res.StatusCode)
+	}
+}
+
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,400 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+const githubPageSize = 100
+
+// GitHubImportRequest selects the repository and issues to import.
+type GitHubImportRequest struct {
+	// Repository is the "owner/name" of the repository to import from.
+	Repository string `json:"repository"`
+	// State filters issues by state: "open", "closed" or "all" (default).
+	State string `json:"state,omitempty"`
+	// Branch is assigned to top-level runes (default "main").
+	Branch string `json:"branch,omitempty"`
+	// Token overrides the realm's github.token setting.
+	Token string `json:"token,omitempty"`
+}
+
+// GitHubImportResult maps imported GitHub numbers to the runes created for
+// them.
+type GitHubImportResult struct {
+	Issues       map[int]string `json:"issues"`
+	Milestones   map[int]string `json:"milestones"`
+	Dependencies int            `json:"dependencies"`
+	Notes        int            `json:"notes"`
+}
+
+// GitHubAPIError reports a failed call to the GitHub API during an import.
+type GitHubAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GitHubAPIError) Error() string {
+	if e.StatusCode == 0 {
+		return "github api request failed: " + e.Message
+	}
+	return fmt.Sprintf("github api returned %d: %s", e.StatusCode, e.Message)
+}
+
+type githubIssue struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	State       string `json:"state"`
+	StateReason string `json:"state_reason"`
+	HTMLURL     string `json:"html_url"`
+	Comments    int    `json:"comments"`
+	User        struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone   *githubMilestone `json:"milestone"`
+	PullRequest json.RawMessage  `json:"pull_request"`
+}
+
+type githubMilestone struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+type githubComment struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// issueRefPattern matches "#12", "owner/repo#12" and issue URLs.
+var issueRefPattern = regexp.MustCompile(`(?:^|[^\w&/])(?:([\w.-]+/[\w.-]+))?#(\d+)\b|github\.com/([\w.-]+/[\w.-]+)/issues/(\d+)\b`)
+
+var priorityLabelPattern = regexp.MustCompile(`(?i)^(?:p|priority[\s:/-]*)([0-9])$`)
+
+// GitHubImporter imports GitHub issues, milestones and comments into a realm.
+type GitHubImporter struct {
+	cfg *RouteConfig
+}
+
+func NewGitHubImporter(cfg *RouteConfig) *GitHubImporter {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &GitHubImporter{cfg: cfg}
+}
+
+// ImportGitHub creates a rune for every issue in the repository. Milestones
+// become saga runes parenting their issues, labels are recorded in the
+// description (and "P1"-style labels set the priority), comments become notes
+// and references between imported issues become relates_to dependencies.
+// Closed issues and milestones are sealed. Pull requests are skipped.
+func (i *GitHubImporter) ImportGitHub(ctx context.Context, realmID string, req GitHubImportRequest) (GitHubImportResult, error) {
+	if owner, name, ok := strings.Cut(req.Repository, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return GitHubImportResult{}, domain.Rejectf(domain.ErrInvalidCommand, "cannot import: repository must be in owner/name form")
+	}
+	state := req.State
+	if state == "" {
+		state = "all"
+	}
+	if state != "open" && state != "closed" && state != "all" {
+		return GitHubImportResult{}, domain.Rejectf(domain.ErrInvalidCommand, "cannot import: unknown issue state %q", state)
+	}
+	branch := req.Branch
+	if branch == "" {
+		branch = "main"
+	}
+
+	settings, err := projectors.GetRealmSettings(ctx, i.cfg.ProjectionStore, realmID)
+	if err != nil {
+		return GitHubImportResult{}, err
+	}
+	api := &githubAPI{
+		client:  i.cfg.HTTPClient,
+		baseURL: strings.TrimSuffix(settings[GitHubAPIURLSetting], "/"),
+		token:   req.Token,
+	}
+	if api.baseURL == "" {
+		api.baseURL = defaultGitHubAPIURL
+	}
+	if api.token == "" {
+		api.token = settings[GitHubTokenSetting]
+	}
+
+	issues, err := api.listIssues(ctx, req.Repository, state)
+	if err != nil {
+		return GitHubImportResult{}, err
+	}
+
+	result := GitHubImportResult{
+		Issues:     make(map[int]string),
+		Milestones: make(map[int]string),
+	}
+	var closedMilestones []string
+	var closedIssues []githubIssue
+	texts := make(map[int][]string)
+
+	for _, issue := range issues {
+		parentID := ""
+		if m := issue.Milestone; m != nil {
+			if _, ok := result.Milestones[m.Number]; !ok {
+				created, err := domain.HandleCreateRune(ctx, realmID, domain.CreateRune{
+					Title:       m.Title,
+					Description: m.Description,
+					Type:        "saga",
+					Branch:      &branch,
+				}, i.cfg.EventStore, i.cfg.ProjectionStore)
+				if err != nil {
+					return result, fmt.Errorf("import milestone %q: %w", m.Title, err)
+				}
+				result.Milestones[m.Number] = created.ID
+				if m.State == "closed" {
+					closedMilestones = append(closedMilestones, created.ID)
+				}
+			}
+			parentID = result.Milestones[m.Number]
+		}
+
+		cmd := domain.CreateRune{
+			Title:       issue.Title,
+			Description: githubIssueDescription(issue),
+			Priority:    githubIssuePriority(issue),
+			ParentID:    parentID,
+		}
+		if parentID == "" {
+			cmd.Branch = &branch
+		}
+		created, err := domain.HandleCreateRune(ctx, realmID, cmd, i.cfg.EventStore, i.cfg.ProjectionStore)
+		if err != nil {
+			return result, fmt.Errorf("import issue #%d: %w", issue.Number, err)
+		}
+		result.Issues[issue.Number] = created.ID
+		texts[issue.Number] = append(texts[issue.Number], issue.Body)
+		if issue.State == "closed" {
+			closedIssues = append(closedIssues, issue)
+		}
+		if parentID != "" {
+			// Child IDs are numbered from the RuneChildCount projection.
+			i.cfg.Engine.RunCatchUpOnce(ctx)
+		}
+
+		if issue.Comments == 0 {
+			continue
+		}
+		comments, err := api.listComments(ctx, req.Repository, issue.Number)
+		if err != nil {
+			return result, err
+		}
+		for _, c := range comments {
+			note := fmt.Sprintf("@%s commented on GitHub (%s):\n\n%s", c.User.Login, c.CreatedAt.Format("2006-01-02"), c.Body)
+			if err := domain.HandleAddNote(ctx, realmID, domain.AddNote{RuneID: created.ID, Text: note}, i.cfg.EventStore); err != nil {
+				return result, fmt.Errorf("import comments on #%d: %w", issue.Number, err)
+			}
+			result.Notes++
+			texts[issue.Number] = append(texts[issue.Number], c.Body)
+		}
+	}
+
+	linked := make(map[[2]string]bool)
+	for _, issue := range issues {
+		source := result.Issues[issue.Number]
+		for _, n := range githubIssueRefs(texts[issue.Number], req.Repository) {
+			target, ok := result.Issues[n]
+			if !ok || target == source {
+				continue
+			}
+			pair := [2]string{min(source, target), max(source, target)}
+			if linked[pair] {
+				continue
+			}
+			linked[pair] = true
+			err := domain.HandleAddDependency(ctx, realmID, domain.AddDependency{
+				RuneID:       source,
+				TargetID:     target,
+				Relationship: domain.RelRelatesTo,
+			}, i.cfg.EventStore, i.cfg.ProjectionStore)
+			if err != nil {
+				return result, fmt.Errorf("link #%d to #%d: %w", issue.Number, n, err)
+			}
+			result.Dependencies++
+		}
+	}
+
+	i.cfg.Engine.RunCatchUpOnce(ctx)
+	for _, id := range result.Milestones {
+		if err := domain.HandleForgeRune(ctx, realmID, domain.ForgeRune{ID: id}, i.cfg.EventStore, i.cfg.ProjectionStore); err != nil {
+			return result, err
+		}
+	}
+	for _, issue := range issues {
+		if issue.Milestone != nil {
+			continue
+		}
+		if err := domain.HandleForgeRune(ctx, realmID, domain.ForgeRune{ID: result.Issues[issue.Number]}, i.cfg.EventStore, i.cfg.ProjectionStore); err != nil {
+			return result, err
+		}
+	}
+
+	for _, issue := range closedIssues {
+		reason := "closed on GitHub"
+		if issue.StateReason == "not_planned" {
+			reason = "closed on GitHub as not planned"
+		}
+		if err := domain.HandleSealRune(ctx, realmID, domain.SealRune{ID: result.Issues[issue.Number], Reason: reason}, i.cfg.EventStore); err != nil {
+			return result, err
+		}
+	}
+	for _, id := range closedMilestones {
+		if err := domain.HandleSealRune(ctx, realmID, domain.SealRune{ID: id, Reason: "milestone closed on GitHub"}, i.cfg.EventStore); err != nil {
+			return result, err
+		}
+	}
+
+	i.cfg.Engine.RunCatchUpOnce(ctx)
+	return result, nil
+}
+
+// githubIssueDescription appends the issue's origin and labels to its body.
+func githubIssueDescription(issue githubIssue) string {
+	var b strings.Builder
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n\n---\n")
+	}
+	fmt.Fprintf(&b, "Imported from GitHub issue #%d", issue.Number)
+	if issue.HTMLURL != "" {
+		fmt.Fprintf(&b, " (%s)", issue.HTMLURL)
+	}
+	if issue.User.Login != "" {
+		fmt.Fprintf(&b, ", opened by @%s", issue.User.Login)
+	}
+	if len(issue.Labels) > 0 {
+		names := make([]string, len(issue.Labels))
+		for i, l := range issue.Labels {
+			names[i] = l.Name
+		}
+		fmt.Fprintf(&b, "\nLabels: %s", strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// githubIssuePriority reads the priority from a "P1" or "priority: 1" style
+// label, defaulting to 0.
+func githubIssuePriority(issue githubIssue) int {
+	for _, l := range issue.Labels {
+		if m := priorityLabelPattern.FindStringSubmatch(strings.TrimSpace(l.Name)); m != nil {
+			p, _ := strconv.Atoi(m[1])
+			return p
+		}
+	}
+	return 0
+}
+
+// githubIssueRefs returns the numbers of issues in repository referenced by
+// texts, in order of first appearance.
+func githubIssueRefs(texts []string, repository string) []int {
+	seen := make(map[int]bool)
+	var refs []int
+	for _, text := range texts {
+		for _, m := range issueRefPattern.FindAllStringSubmatch(text, -1) {
+			repo, num := m[1], m[2]
+			if m[4] != "" {
+				repo, num = m[3], m[4]
+			}
+			if repo != "" && !strings.EqualFold(repo, repository) {
+				continue
+			}
+			n, err := strconv.Atoi(num)
+			if err != nil || seen[n] {
+				continue
+			}
+			seen[n] = true
+			refs = append(refs, n)
+		}
+	}
+	return refs
+}
+
+type githubAPI struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// listIssues returns the repository's issues, oldest first, excluding pull
+// requests.
+func (a *githubAPI) listIssues(ctx context.Context, repository, state string) ([]githubIssue, error) {
+	var issues []githubIssue
+	for page := 1; ; page++ {
+		var batch []githubIssue
+		url := fmt.Sprintf("%s/repos/%s/issues?state=%s&sort=created&direction=asc&per_page=%d&page=%d",
+			a.baseURL, repository, state, githubPageSize, page)
+		if err := a.get(ctx, url, &batch); err != nil {
+			return nil, err
+		}
+		for _, issue := range batch {
+			if len(issue.PullRequest) == 0 {
+				issues = append(issues, issue)
+			}
+		}
+		if len(batch) < githubPageSize {
+			return issues, nil
+		}
+	}
+}
+
+func (a *githubAPI) listComments(ctx context.Context, repository string, number int) ([]githubComment, error) {
+	var comments []githubComment
+	for page := 1; ; page++ {
+		var batch []githubComment
+		url := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=%d&page=%d",
+			a.baseURL, repository, number, githubPageSize, page)
+		if err := a.get(ctx, url, &batch); err != nil {
+			return nil, err
+		}
+		comments = append(comments, batch...)
+		if len(batch) < githubPageSize {
+			return comments, nil
+		}
+	}
+}
+
+func (a *githubAPI) get(ctx context.Context, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return &GitHubAPIError{Message: err.Error()}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<12))
+		return &GitHubAPIError{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return json.NewDecoder(res.Body).Decode(dest)
+}
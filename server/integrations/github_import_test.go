@@ -0,0 +1,345 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestGitHubImporter(t *testing.T) {
+	t.Run("creates a forged rune per issue with labels in the description", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[
+			{"number": 1, "title": "Crash on start", "body": "Boom", "state": "open",
+			 "html_url": "https://github.com/acme/app/issues/1", "user": {"login": "alice"},
+			 "labels": [{"name": "bug"}, {"name": "P1"}]}
+		]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		state := tc.rune_state_for_issue(1)
+		assert.Equal(t, "Crash on start", state.Title)
+		assert.Equal(t, "open", state.Status)
+		assert.Equal(t, 1, state.Priority)
+		assert.Equal(t, "main", state.Branch)
+		assert.Contains(t, state.Description, "Boom")
+		assert.Contains(t, state.Description, "Imported from GitHub issue #1")
+		assert.Contains(t, state.Description, "Labels: bug, P1")
+	})
+
+	t.Run("skips pull requests", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[
+			{"number": 1, "title": "Issue", "state": "open"},
+			{"number": 2, "title": "PR", "state": "open", "pull_request": {"url": "x"}}
+		]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.result.Issues, 1)
+		assert.Contains(t, tc.result.Issues, 1)
+	})
+
+	t.Run("creates milestones as sagas parenting their issues", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[
+			{"number": 1, "title": "First", "state": "open", "milestone": {"number": 7, "title": "v1", "state": "open"}},
+			{"number": 2, "title": "Second", "state": "open", "milestone": {"number": 7, "title": "v1", "state": "open"}}
+		]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		sagaID := tc.result.Milestones[7]
+		require.NotEmpty(t, sagaID)
+		assert.Equal(t, "saga", tc.rune_state(sagaID).Type)
+		assert.Equal(t, sagaID+".1", tc.result.Issues[1])
+		assert.Equal(t, sagaID+".2", tc.result.Issues[2])
+		assert.Equal(t, "open", tc.rune_state_for_issue(2).Status)
+	})
+
+	t.Run("imports comments as notes", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[{"number": 1, "title": "Issue", "state": "open", "comments": 1}]`)
+		tc.issue_has_comments(1, `[{"body": "Repro attached", "created_at": "2024-05-01T10:00:00Z", "user": {"login": "bob"}}]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 1, tc.result.Notes)
+		tc.rune_has_note_containing(tc.result.Issues[1], "@bob commented on GitHub (2024-05-01)")
+	})
+
+	t.Run("links cross-referenced issues as relates_to", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[
+			{"number": 1, "title": "One", "state": "open", "body": "See #2 and other/repo#3", "comments": 1},
+			{"number": 2, "title": "Two", "state": "open"},
+			{"number": 3, "title": "Three", "state": "open"}
+		]`)
+		tc.issue_has_comments(1, `[{"body": "Also https://github.com/acme/app/issues/2", "created_at": "2024-05-01T10:00:00Z", "user": {"login": "bob"}}]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, 1, tc.result.Dependencies)
+		tc.rune_relates_to(tc.result.Issues[1], tc.result.Issues[2])
+	})
+
+	t.Run("seals closed issues", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[{"number": 1, "title": "Old", "state": "closed", "state_reason": "not_planned"}]`)
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "sealed", tc.rune_state_for_issue(1).Status)
+	})
+
+	t.Run("sends the realm token to GitHub", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.repository_has_issues(`[]`)
+		tc.realm_has_token("ghp_realm")
+
+		// When
+		tc.import_repository("acme/app")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "Bearer ghp_realm", tc.receivedAuth)
+	})
+
+	t.Run("returns GitHubAPIError when the repository is missing", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// Given
+		tc.a_github_api()
+
+		// When
+		tc.import_repository("acme/missing")
+
+		// Then
+		var apiErr *GitHubAPIError
+		require.ErrorAs(t, tc.err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+
+	t.Run("rejects malformed repository", func(t *testing.T) {
+		tc := newGitHubImportTestContext(t)
+
+		// When
+		tc.import_repository("acme")
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "owner/name")
+	})
+}
+
+func TestGitHubIssueRefs(t *testing.T) {
+	t.Run("finds same-repository references in order", func(t *testing.T) {
+		refs := githubIssueRefs([]string{
+			"Fixes #4, see acme/app#2 and ACME/APP#4",
+			"Not other/repo#9 or a&#39; entity, but https://github.com/acme/app/issues/11",
+		}, "acme/app")
+
+		assert.Equal(t, []int{4, 2, 11}, refs)
+	})
+}
+
+// --- Test Context ---
+
+type githubImportTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	engine          *mockProjectionEngine
+	importer        *GitHubImporter
+	api             *httptest.Server
+	routes          map[string]string
+	settings        map[string]string
+	receivedAuth    string
+	result          GitHubImportResult
+	err             error
+}
+
+func newGitHubImportTestContext(t *testing.T) *githubImportTestContext {
+	t.Helper()
+	tc := &githubImportTestContext{
+		t:               t,
+		eventStore:      newMockEventStore(),
+		projectionStore: newMockProjectionStore(),
+		engine:          &mockProjectionEngine{},
+		routes:          make(map[string]string),
+		settings:        make(map[string]string),
+	}
+	tc.engine.onCatchUp = tc.project_child_counts
+	tc.importer = NewGitHubImporter(&RouteConfig{
+		EventStore:      tc.eventStore,
+		ProjectionStore: tc.projectionStore,
+		Engine:          tc.engine,
+	})
+	return tc
+}
+
+// project_child_counts stands in for the RuneChildCount projector.
+func (tc *githubImportTestContext) project_child_counts() {
+	counts := make(map[string]int)
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType != domain.EventRuneCreated {
+				continue
+			}
+			var created domain.RuneCreated
+			_ = json.Unmarshal(evt.Data, &created)
+			if created.ParentID != "" {
+				counts[created.ParentID]++
+			}
+		}
+	}
+	for parentID, count := range counts {
+		_ = tc.projectionStore.Put(context.Background(), "realm-1", "RuneChildCount", parentID, count)
+	}
+}
+
+// --- Given ---
+
+func (tc *githubImportTestContext) a_github_api() {
+	tc.t.Helper()
+	if tc.api != nil {
+		return
+	}
+	tc.api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedAuth = r.Header.Get("Authorization")
+		body, ok := tc.routes[r.URL.Path]
+		if !ok {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	tc.t.Cleanup(tc.api.Close)
+	tc.settings[GitHubAPIURLSetting] = tc.api.URL
+	tc.write_settings()
+}
+
+func (tc *githubImportTestContext) repository_has_issues(issues string) {
+	tc.t.Helper()
+	tc.a_github_api()
+	tc.routes["/repos/acme/app/issues"] = issues
+}
+
+func (tc *githubImportTestContext) issue_has_comments(number int, comments string) {
+	tc.t.Helper()
+	tc.a_github_api()
+	tc.routes["/repos/acme/app/issues/"+strconv.Itoa(number)+"/comments"] = comments
+}
+
+func (tc *githubImportTestContext) realm_has_token(token string) {
+	tc.t.Helper()
+	tc.settings[GitHubTokenSetting] = token
+	tc.write_settings()
+}
+
+func (tc *githubImportTestContext) write_settings() {
+	tc.projectionStore.data["_admin:realm_settings:realm-1"] = map[string]any{
+		"realm_id": "realm-1", "settings": tc.settings,
+	}
+}
+
+// --- When ---
+
+func (tc *githubImportTestContext) import_repository(repository string) {
+	tc.t.Helper()
+	tc.result, tc.err = tc.importer.ImportGitHub(context.Background(), "realm-1", GitHubImportRequest{Repository: repository})
+}
+
+// --- Then ---
+
+func (tc *githubImportTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *githubImportTestContext) rune_state(runeID string) domain.RuneState {
+	tc.t.Helper()
+	events := tc.eventStore.streams["realm-1:rune-"+runeID]
+	require.NotEmpty(tc.t, events, "rune %s should exist", runeID)
+	return domain.RebuildRuneState(events)
+}
+
+func (tc *githubImportTestContext) rune_state_for_issue(number int) domain.RuneState {
+	tc.t.Helper()
+	runeID, ok := tc.result.Issues[number]
+	require.True(tc.t, ok, "issue #%d should be imported", number)
+	return tc.rune_state(runeID)
+}
+
+func (tc *githubImportTestContext) rune_has_note_containing(runeID, text string) {
+	tc.t.Helper()
+	for _, evt := range tc.eventStore.streams["realm-1:rune-"+runeID] {
+		if evt.EventType != domain.EventRuneNoted {
+			continue
+		}
+		var noted domain.RuneNoted
+		require.NoError(tc.t, json.Unmarshal(evt.Data, &noted))
+		if strings.Contains(noted.Text, text) {
+			return
+		}
+	}
+	tc.t.Errorf("rune %s has no note containing %q", runeID, text)
+}
+
+func (tc *githubImportTestContext) rune_relates_to(runeID, targetID string) {
+	tc.t.Helper()
+	for _, evt := range tc.eventStore.streams["realm-1:rune-"+runeID] {
+		if evt.EventType != domain.EventDependencyAdded {
+			continue
+		}
+		var dep domain.DependencyAdded
+		require.NoError(tc.t, json.Unmarshal(evt.Data, &dep))
+		if dep.TargetID == targetID && dep.Relationship == domain.RelRelatesTo {
+			return
+		}
+	}
+	tc.t.Errorf("rune %s does not relate to %s", runeID, targetID)
+}
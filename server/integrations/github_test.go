@@ -0,0 +1,309 @@
+package integrations
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestGitHubWebhook(t *testing.T) {
+	t.Run("returns 404 when integration is not configured", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("opened", false, "main"))
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 401 when signature is invalid", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitHubWebhookSecretSetting: "s3cret"})
+		tc.routes_are_registered()
+		tc.signing_secret_is("wrong")
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("opened", false, "main"))
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+	})
+
+	t.Run("responds to ping", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitHubWebhookSecretSetting: "s3cret"})
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("ping", []byte(`{"zen":"hi"}`))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains("pong")
+	})
+
+	t.Run("links opened pull request to referenced runes and posts status", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.a_github_api()
+		tc.realm_has_settings(map[string]string{
+			GitHubWebhookSecretSetting: "s3cret",
+			GitHubTokenSetting:         "ghp_token",
+		})
+		tc.rune_exists("bf-a1b2", "claimed", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("opened", false, "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are("bf-a1b2")
+		tc.fulfilled_runes_are()
+		tc.note_was_added_to("bf-a1b2")
+		tc.status_was_posted_for("abc123")
+	})
+
+	t.Run("fulfills rune when merged into rune branch with auto-fulfill enabled", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{
+			GitHubWebhookSecretSetting: "s3cret",
+			GitHubAutoFulfillSetting:   "true",
+		})
+		tc.rune_exists("bf-a1b2", "claimed", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("closed", true, "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.fulfilled_runes_are("bf-a1b2")
+		tc.rune_fulfilled_event_was_appended("bf-a1b2")
+	})
+
+	t.Run("does not fulfill when merged into a different branch", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{
+			GitHubWebhookSecretSetting: "s3cret",
+			GitHubAutoFulfillSetting:   "true",
+		})
+		tc.rune_exists("bf-a1b2", "claimed", "release")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("closed", true, "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are("bf-a1b2")
+		tc.fulfilled_runes_are()
+	})
+
+	t.Run("does not fulfill when auto-fulfill is disabled", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitHubWebhookSecretSetting: "s3cret"})
+		tc.rune_exists("bf-a1b2", "claimed", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("closed", true, "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.fulfilled_runes_are()
+	})
+
+	t.Run("ignores references to unknown runes", func(t *testing.T) {
+		tc := newGitHubTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitHubWebhookSecretSetting: "s3cret"})
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("pull_request", tc.pull_request_payload("opened", false, "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are()
+	})
+}
+
+// --- Test Context ---
+
+type githubTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	engine          *mockProjectionEngine
+	mux             *http.ServeMux
+	api             *httptest.Server
+	statusPaths     []string
+	signingSecret   string
+	recorder        *httptest.ResponseRecorder
+}
+
+func newGitHubTestContext(t *testing.T) *githubTestContext {
+	t.Helper()
+	return &githubTestContext{
+		t:               t,
+		eventStore:      newMockEventStore(),
+		projectionStore: newMockProjectionStore(),
+		engine:          &mockProjectionEngine{},
+		signingSecret:   "s3cret",
+		recorder:        httptest.NewRecorder(),
+	}
+}
+
+// --- Given ---
+
+func (tc *githubTestContext) a_github_api() {
+	tc.t.Helper()
+	tc.api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.statusPaths = append(tc.statusPaths, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	tc.t.Cleanup(tc.api.Close)
+}
+
+func (tc *githubTestContext) realm_has_settings(settings map[string]string) {
+	tc.t.Helper()
+	if tc.api != nil {
+		settings[GitHubAPIURLSetting] = tc.api.URL
+	}
+	tc.projectionStore.data["_admin:realm_settings:realm-1"] = projectors.RealmSettingsEntry{
+		RealmID: "realm-1", Settings: settings,
+	}
+}
+
+func (tc *githubTestContext) rune_exists(runeID, status, branch string) {
+	tc.t.Helper()
+	tc.projectionStore.data["realm-1:rune_detail:"+runeID] = projectors.RuneDetail{
+		ID: runeID, Status: status, Branch: branch,
+	}
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Branch: branch})
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID})
+	if status == "claimed" {
+		tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneClaimed, domain.RuneClaimed{ID: runeID, Claimant: "alice"})
+	}
+}
+
+func (tc *githubTestContext) routes_are_registered() {
+	tc.t.Helper()
+	tc.mux = http.NewServeMux()
+	RegisterRoutes(tc.mux, &RouteConfig{
+		EventStore:      tc.eventStore,
+		ProjectionStore: tc.projectionStore,
+		Engine:          tc.engine,
+	})
+}
+
+func (tc *githubTestContext) signing_secret_is(secret string) {
+	tc.t.Helper()
+	tc.signingSecret = secret
+}
+
+func (tc *githubTestContext) pull_request_payload(action string, merged bool, baseRef string) []byte {
+	tc.t.Helper()
+	payload := map[string]any{
+		"action": action,
+		"pull_request": map[string]any{
+			"number":   42,
+			"title":    "Add login page",
+			"body":     "Implements bf-a1b2",
+			"html_url": "https://github.com/acme/app/pull/42",
+			"merged":   merged,
+			"head":     map[string]string{"ref": "feature/login", "sha": "abc123"},
+			"base":     map[string]string{"ref": baseRef},
+		},
+		"repository": map[string]string{"full_name": "acme/app"},
+	}
+	data, err := json.Marshal(payload)
+	require.NoError(tc.t, err)
+	return data
+}
+
+// --- When ---
+
+func (tc *githubTestContext) webhook_is_delivered(eventType string, body []byte) {
+	tc.t.Helper()
+	mac := hmac.New(sha256.New, []byte(tc.signingSecret))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/integrations/github/realm-1", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", eventType)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	tc.mux.ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *githubTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code, "body: %s", tc.recorder.Body.String())
+}
+
+func (tc *githubTestContext) response_body_contains(substr string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.recorder.Body.String(), substr)
+}
+
+func (tc *githubTestContext) response() WebhookResponse {
+	tc.t.Helper()
+	var resp WebhookResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
+
+func (tc *githubTestContext) linked_runes_are(expected ...string) {
+	tc.t.Helper()
+	assert.ElementsMatch(tc.t, expected, tc.response().Linked)
+}
+
+func (tc *githubTestContext) fulfilled_runes_are(expected ...string) {
+	tc.t.Helper()
+	assert.ElementsMatch(tc.t, expected, tc.response().Fulfilled)
+}
+
+func (tc *githubTestContext) note_was_added_to(runeID string) {
+	tc.t.Helper()
+	assert.True(tc.t, tc.eventStore.hasEvent("realm-1", "rune-"+runeID, domain.EventRuneNoted), "expected note on %s", runeID)
+}
+
+func (tc *githubTestContext) rune_fulfilled_event_was_appended(runeID string) {
+	tc.t.Helper()
+	assert.True(tc.t, tc.eventStore.hasEvent("realm-1", "rune-"+runeID, domain.EventRuneFulfilled), "expected %s to be fulfilled", runeID)
+}
+
+func (tc *githubTestContext) status_was_posted_for(sha string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, []string{"/repos/acme/app/statuses/" + sha}, tc.statusPaths)
+}
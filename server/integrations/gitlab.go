@@ -0,0 +1,157 @@
+package integrations
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Realm settings read by the GitLab integration.
+const (
+	GitLabWebhookSecretSetting = "gitlab.webhook_secret"
+	GitLabAutoFulfillSetting   = "gitlab.auto_fulfill"
+)
+
+type gitlabMergeRequestEvent struct {
+	ObjectAttributes struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		URL          string `json:"url"`
+		Action       string `json:"action"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+	} `json:"object_attributes"`
+	Project gitlabProject `json:"project"`
+}
+
+type gitlabPipelineEvent struct {
+	ObjectAttributes struct {
+		ID     int    `json:"id"`
+		Ref    string `json:"ref"`
+		Status string `json:"status"`
+	} `json:"object_attributes"`
+	MergeRequest *struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		SourceBranch string `json:"source_branch"`
+	} `json:"merge_request"`
+	Project gitlabProject `json:"project"`
+}
+
+type gitlabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+func handleGitLabWebhook(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		realmID := r.PathValue("realm_id")
+
+		settings, err := projectors.GetRealmSettings(ctx, cfg.ProjectionStore, realmID)
+		if err != nil {
+			http.Error(w, "failed to load realm settings", http.StatusInternalServerError)
+			return
+		}
+		secret := settings[GitLabWebhookSecretSetting]
+		if secret == "" {
+			http.Error(w, "gitlab integration not configured", http.StatusNotFound)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(r.Header.Get("X-Gitlab-Token"))) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		var resp WebhookResponse
+		switch r.Header.Get("X-Gitlab-Event") {
+		case "Merge Request Hook":
+			var event gitlabMergeRequestEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				http.Error(w, "invalid merge request payload", http.StatusBadRequest)
+				return
+			}
+			resp, err = processGitLabMergeRequest(ctx, cfg, realmID, settings, event)
+		case "Pipeline Hook":
+			var event gitlabPipelineEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				http.Error(w, "invalid pipeline payload", http.StatusBadRequest)
+				return
+			}
+			resp, err = processGitLabPipeline(ctx, cfg, realmID, event)
+		default:
+			writeJSON(w, http.StatusAccepted, map[string]string{"status": "ignored"})
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		cfg.Engine.RunCatchUpOnce(ctx)
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func processGitLabMergeRequest(ctx context.Context, cfg *RouteConfig, realmID string, settings map[string]string, event gitlabMergeRequestEvent) (WebhookResponse, error) {
+	mr := event.ObjectAttributes
+	project := event.Project.PathWithNamespace
+
+	var note string
+	switch mr.Action {
+	case "open", "reopen":
+		note = fmt.Sprintf("Linked merge request %s!%d: %s (%s)", project, mr.IID, mr.Title, mr.URL)
+	case "merge":
+		note = fmt.Sprintf("Merge request %s!%d merged into %s", project, mr.IID, mr.TargetBranch)
+	}
+
+	return applyReviewEvent(ctx, cfg, realmID, "gitlab", reviewEvent{
+		source:       strings.Join([]string{mr.Title, mr.Description, mr.SourceBranch}, "\n"),
+		note:         note,
+		merged:       mr.Action == "merge",
+		targetBranch: mr.TargetBranch,
+		autoFulfill:  settings[GitLabAutoFulfillSetting] == "true",
+	})
+}
+
+// processGitLabPipeline notes the outcome of finished pipelines on the runes
+// referenced by the pipeline's ref or merge request.
+func processGitLabPipeline(ctx context.Context, cfg *RouteConfig, realmID string, event gitlabPipelineEvent) (WebhookResponse, error) {
+	pipeline := event.ObjectAttributes
+
+	var outcome string
+	switch pipeline.Status {
+	case "success":
+		outcome = "succeeded"
+	case "failed":
+		outcome = "failed"
+	case "canceled":
+		outcome = "was canceled"
+	default:
+		return WebhookResponse{Linked: []string{}, Fulfilled: []string{}}, nil
+	}
+
+	sources := []string{pipeline.Ref}
+	subject := pipeline.Ref
+	if event.MergeRequest != nil {
+		sources = append(sources, event.MergeRequest.Title, event.MergeRequest.SourceBranch)
+		subject = fmt.Sprintf("%s!%d", event.Project.PathWithNamespace, event.MergeRequest.IID)
+	}
+
+	return applyReviewEvent(ctx, cfg, realmID, "gitlab", reviewEvent{
+		source: strings.Join(sources, "\n"),
+		note:   fmt.Sprintf("Pipeline #%d for %s %s", pipeline.ID, subject, outcome),
+	})
+}
@@ -0,0 +1,258 @@
+package integrations
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestGitLabWebhook(t *testing.T) {
+	t.Run("returns 404 when integration is not configured", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Merge Request Hook", tc.merge_request_payload("open", "main"))
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 401 when token does not match", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitLabWebhookSecretSetting: "s3cret"})
+		tc.routes_are_registered()
+		tc.token_is("wrong")
+
+		// When
+		tc.webhook_is_delivered("Merge Request Hook", tc.merge_request_payload("open", "main"))
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+	})
+
+	t.Run("links opened merge request to referenced runes", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitLabWebhookSecretSetting: "s3cret"})
+		tc.rune_exists("bf-a1b2", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Merge Request Hook", tc.merge_request_payload("open", "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are("bf-a1b2")
+		tc.rune_has_event("bf-a1b2", domain.EventRuneNoted)
+	})
+
+	t.Run("fulfills rune when merged into rune branch with auto-fulfill enabled", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{
+			GitLabWebhookSecretSetting: "s3cret",
+			GitLabAutoFulfillSetting:   "true",
+		})
+		tc.rune_exists("bf-a1b2", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Merge Request Hook", tc.merge_request_payload("merge", "main"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.fulfilled_runes_are("bf-a1b2")
+		tc.rune_has_event("bf-a1b2", domain.EventRuneFulfilled)
+	})
+
+	t.Run("notes finished pipeline on referenced runes", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitLabWebhookSecretSetting: "s3cret"})
+		tc.rune_exists("bf-a1b2", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Pipeline Hook", tc.pipeline_payload("failed"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are("bf-a1b2")
+		tc.rune_has_event("bf-a1b2", domain.EventRuneNoted)
+	})
+
+	t.Run("ignores running pipelines", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitLabWebhookSecretSetting: "s3cret"})
+		tc.rune_exists("bf-a1b2", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Pipeline Hook", tc.pipeline_payload("running"))
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.linked_runes_are()
+	})
+
+	t.Run("ignores unsupported event types", func(t *testing.T) {
+		tc := newGitLabTestContext(t)
+
+		// Given
+		tc.realm_has_settings(map[string]string{GitLabWebhookSecretSetting: "s3cret"})
+		tc.routes_are_registered()
+
+		// When
+		tc.webhook_is_delivered("Push Hook", []byte(`{}`))
+
+		// Then
+		tc.status_is(http.StatusAccepted)
+	})
+}
+
+// --- Test Context ---
+
+type gitlabTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	mux             *http.ServeMux
+	token           string
+	recorder        *httptest.ResponseRecorder
+}
+
+func newGitLabTestContext(t *testing.T) *gitlabTestContext {
+	t.Helper()
+	return &gitlabTestContext{
+		t:               t,
+		eventStore:      newMockEventStore(),
+		projectionStore: newMockProjectionStore(),
+		token:           "s3cret",
+		recorder:        httptest.NewRecorder(),
+	}
+}
+
+// --- Given ---
+
+func (tc *gitlabTestContext) realm_has_settings(settings map[string]string) {
+	tc.t.Helper()
+	tc.projectionStore.data["_admin:realm_settings:realm-1"] = projectors.RealmSettingsEntry{
+		RealmID: "realm-1", Settings: settings,
+	}
+}
+
+func (tc *gitlabTestContext) rune_exists(runeID, branch string) {
+	tc.t.Helper()
+	tc.projectionStore.data["realm-1:rune_detail:"+runeID] = projectors.RuneDetail{
+		ID: runeID, Status: "claimed", Branch: branch,
+	}
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Branch: branch})
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID})
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneClaimed, domain.RuneClaimed{ID: runeID, Claimant: "alice"})
+}
+
+func (tc *gitlabTestContext) routes_are_registered() {
+	tc.t.Helper()
+	tc.mux = http.NewServeMux()
+	RegisterRoutes(tc.mux, &RouteConfig{
+		EventStore:      tc.eventStore,
+		ProjectionStore: tc.projectionStore,
+		Engine:          &mockProjectionEngine{},
+	})
+}
+
+func (tc *gitlabTestContext) token_is(token string) {
+	tc.t.Helper()
+	tc.token = token
+}
+
+func (tc *gitlabTestContext) merge_request_payload(action, targetBranch string) []byte {
+	tc.t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"object_attributes": map[string]any{
+			"iid":           7,
+			"title":         "bf-a1b2: add login page",
+			"description":   "",
+			"url":           "https://gitlab.com/acme/app/-/merge_requests/7",
+			"action":        action,
+			"source_branch": "feature/login",
+			"target_branch": targetBranch,
+		},
+		"project": map[string]string{"path_with_namespace": "acme/app"},
+	})
+	require.NoError(tc.t, err)
+	return data
+}
+
+func (tc *gitlabTestContext) pipeline_payload(status string) []byte {
+	tc.t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"object_attributes": map[string]any{
+			"id":     99,
+			"ref":    "feature/bf-a1b2-login",
+			"status": status,
+		},
+		"project": map[string]string{"path_with_namespace": "acme/app"},
+	})
+	require.NoError(tc.t, err)
+	return data
+}
+
+// --- When ---
+
+func (tc *gitlabTestContext) webhook_is_delivered(eventType string, body []byte) {
+	tc.t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/integrations/gitlab/realm-1", bytes.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", eventType)
+	req.Header.Set("X-Gitlab-Token", tc.token)
+	tc.mux.ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *gitlabTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code, "body: %s", tc.recorder.Body.String())
+}
+
+func (tc *gitlabTestContext) response() WebhookResponse {
+	tc.t.Helper()
+	var resp WebhookResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
+
+func (tc *gitlabTestContext) linked_runes_are(expected ...string) {
+	tc.t.Helper()
+	assert.ElementsMatch(tc.t, expected, tc.response().Linked)
+}
+
+func (tc *gitlabTestContext) fulfilled_runes_are(expected ...string) {
+	tc.t.Helper()
+	assert.ElementsMatch(tc.t, expected, tc.response().Fulfilled)
+}
+
+func (tc *gitlabTestContext) rune_has_event(runeID, eventType string) {
+	tc.t.Helper()
+	assert.True(tc.t, tc.eventStore.hasEvent("realm-1", "rune-"+runeID, eventType), "expected %s on %s", eventType, runeID)
+}
@@ -0,0 +1,138 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// --- Mock Event Store ---
+
+type mockEventStore struct {
+	streams map[string][]core.Event
+}
+
+func newMockEventStore() *mockEventStore {
+	return &mockEventStore{streams: make(map[string][]core.Event)}
+}
+
+func (m *mockEventStore) appendToStream(realmID, streamID, eventType string, data any) {
+	key := realmID + ":" + streamID
+	dataBytes, _ := json.Marshal(data)
+	m.streams[key] = append(m.streams[key], core.Event{
+		RealmID:   realmID,
+		StreamID:  streamID,
+		Version:   len(m.streams[key]) + 1,
+		EventType: eventType,
+		Data:      dataBytes,
+	})
+}
+
+func (m *mockEventStore) hasEvent(realmID, streamID, eventType string) bool {
+	for _, evt := range m.streams[realmID+":"+streamID] {
+		if evt.EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockEventStore) Append(_ context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	key := realmID + ":" + streamID
+	if expectedVersion != len(m.streams[key]) {
+		return nil, &core.ConcurrencyError{StreamID: streamID, ExpectedVersion: expectedVersion, ActualVersion: len(m.streams[key])}
+	}
+	var appended []core.Event
+	for _, ed := range events {
+		m.appendToStream(realmID, streamID, ed.EventType, ed.Data)
+		appended = append(appended, m.streams[key][len(m.streams[key])-1])
+	}
+	return appended, nil
+}
+
+func (m *mockEventStore) ReadStream(_ context.Context, realmID string, streamID string, fromVersion int) ([]core.Event, error) {
+	events := m.streams[realmID+":"+streamID]
+	// Versions start at 1, and fromVersion is included.
+	start := max(fromVersion-1, 0)
+	if start >= len(events) {
+		return nil, nil
+	}
+	return events[start:], nil
+}
+
+func (m *mockEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (m *mockEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]any
+}
+
+func newMockProjectionStore() *mockProjectionStore {
+	return &mockProjectionStore{data: make(map[string]any)}
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	val, ok := m.data[realmID+":"+projectionName+":"+key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	dataBytes, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dataBytes, dest)
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.data[realmID+":"+projectionName+":"+key] = value
+	return nil
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var keys []string
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var result []json.RawMessage
+	for _, key := range keys {
+		dataBytes, err := json.Marshal(m.data[key])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dataBytes)
+	}
+	return result, nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data, realmID+":"+projectionName+":"+key)
+	return nil
+}
+
+// --- Mock Projection Engine ---
+
+type mockProjectionEngine struct {
+	catchUpCalls int
+	onCatchUp    func()
+}
+
+func (m *mockProjectionEngine) RunCatchUpOnce(_ context.Context) {
+	m.catchUpCalls++
+	if m.onCatchUp != nil {
+		m.onCatchUp()
+	}
+}
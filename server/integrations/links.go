@@ -0,0 +1,62 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// WebhookResponse reports which runes a code review event touched.
+type WebhookResponse struct {
+	Linked    []string `json:"linked"`
+	Fulfilled []string `json:"fulfilled"`
+}
+
+// reviewEvent is a provider-neutral view of a pull/merge request event.
+type reviewEvent struct {
+	// source is scanned for rune IDs (title, description, branch name).
+	source string
+	// note is added to every linked rune; empty means no note.
+	note string
+	// merged reports that the change landed on targetBranch.
+	merged       bool
+	targetBranch string
+	autoFulfill  bool
+}
+
+// applyReviewEvent links the runes referenced by a review event, adds the
+// event note to each, and fulfills runes whose branch received the merge
+// when auto-fulfill is enabled. Command failures on individual runes are
+// logged and skipped so one bad reference does not fail the delivery.
+func applyReviewEvent(ctx context.Context, cfg *RouteConfig, realmID, provider string, event reviewEvent) (WebhookResponse, error) {
+	resp := WebhookResponse{Linked: []string{}, Fulfilled: []string{}}
+
+	for _, runeID := range domain.ExtractRuneIDs(event.source) {
+		detail, found, err := lookupRune(ctx, cfg.ProjectionStore, realmID, runeID)
+		if err != nil {
+			return resp, fmt.Errorf("look up rune %s: %w", runeID, err)
+		}
+		if !found {
+			continue
+		}
+		resp.Linked = append(resp.Linked, runeID)
+
+		if event.note != "" {
+			if err := domain.HandleAddNote(ctx, realmID, domain.AddNote{RuneID: runeID, Text: event.note}, cfg.EventStore); err != nil {
+				log.Printf("%s: add note to %s: %v", provider, runeID, err)
+			}
+		}
+
+		if event.merged && event.autoFulfill && detail.Branch != "" && detail.Branch == event.targetBranch {
+			if err := domain.HandleFulfillRune(ctx, realmID, domain.FulfillRune{ID: runeID}, cfg.EventStore); err != nil {
+				log.Printf("%s: fulfill %s: %v", provider, runeID, err)
+				continue
+			}
+			resp.Fulfilled = append(resp.Fulfilled, runeID)
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+// Package integrations receives webhooks from third-party services and
+// translates them into Bifrost commands.
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// maxWebhookBodyBytes bounds the size of an incoming webhook payload.
+const maxWebhookBodyBytes = 1 << 20
+
+// ProjectionEngine is the subset of the projection engine used to bring
+// projections up to date after a webhook issues commands.
+type ProjectionEngine interface {
+	RunCatchUpOnce(ctx context.Context)
+}
+
+// RouteConfig holds the configuration for registering integration routes.
+type RouteConfig struct {
+	EventStore      core.EventStore
+	ProjectionStore core.ProjectionStore
+	Engine          ProjectionEngine
+	// HTTPClient is used for outbound calls back to the integrated service.
+	// Defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// RegisterRoutes registers the webhook receivers for all integrations.
+func RegisterRoutes(mux *http.ServeMux, cfg *RouteConfig) {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	mux.HandleFunc("POST /integrations/github/{realm_id}", handleGitHubWebhook(cfg))
+	mux.HandleFunc("POST /integrations/gitlab/{realm_id}", handleGitLabWebhook(cfg))
+	mux.HandleFunc("POST /integrations/slack/commands/{realm_id}", handleSlackCommand(cfg))
+}
+
+// lookupRune returns the rune detail for a referenced rune, or false when the
+// rune does not exist in the realm.
+func lookupRune(ctx context.Context, store core.ProjectionStore, realmID, runeID string) (projectors.RuneDetail, bool, error) {
+	var detail projectors.RuneDetail
+	err := store.Get(ctx, realmID, "rune_detail", runeID, &detail)
+	if err != nil {
+		var nfe *core.NotFoundError
+		if errors.As(err, &nfe) {
+			return projectors.RuneDetail{}, false, nil
+		}
+		return projectors.RuneDetail{}, false, err
+	}
+	return detail, true, nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,230 @@
+package integrations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Realm settings read by the Slack slash command. Slack users are linked to
+// accounts explicitly, one setting per user, because Slack display names
+// can be changed by their owner.
+const (
+	SlackSigningSecretSetting = "slack.signing_secret"
+	// SlackUserSettingPrefix is followed by a Slack user ID, e.g.
+	// slack.user.U024BE7LH, and holds the linked Bifrost username.
+	SlackUserSettingPrefix = "slack.user."
+)
+
+const (
+	// slackMaxClockSkew is how old a signed request may be before it is
+	// treated as a replay.
+	slackMaxClockSkew = 5 * time.Minute
+	slackListLimit    = 20
+)
+
+type slackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func slackReply(w http.ResponseWriter, text string) {
+	writeJSON(w, http.StatusOK, slackCommandResponse{ResponseType: "ephemeral", Text: text})
+}
+
+func slackAnnounce(w http.ResponseWriter, text string) {
+	writeJSON(w, http.StatusOK, slackCommandResponse{ResponseType: "in_channel", Text: text})
+}
+
+const slackUsage = "Usage:\n" +
+	"• `/bifrost list [open|claimed|fulfilled|sealed]` — list runes (default open)\n" +
+	"• `/bifrost claim <rune-id>` — claim a rune as your linked account"
+
+func handleSlackCommand(cfg *RouteConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		realmID := r.PathValue("realm_id")
+
+		settings, err := projectors.GetRealmSettings(ctx, cfg.ProjectionStore, realmID)
+		if err != nil {
+			http.Error(w, "failed to load realm settings", http.StatusInternalServerError)
+			return
+		}
+		secret := settings[SlackSigningSecretSetting]
+		if secret == "" {
+			http.Error(w, "slack integration not configured", http.StatusNotFound)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		if !validSlackSignature(secret, body, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), time.Now()) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "invalid slash command payload", http.StatusBadRequest)
+			return
+		}
+
+		args := strings.Fields(form.Get("text"))
+		if len(args) == 0 || args[0] == "help" {
+			slackReply(w, slackUsage)
+			return
+		}
+
+		username, role, problem, err := slackAccount(ctx, cfg.ProjectionStore, realmID, settings, form.Get("user_id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if problem != "" {
+			slackReply(w, problem)
+			return
+		}
+
+		switch args[0] {
+		case "list":
+			if domain.RoleLevel(role) < domain.RoleLevel(domain.RoleViewer) {
+				slackReply(w, "You need the viewer role in this realm to list runes.")
+				return
+			}
+			status := "open"
+			if len(args) > 1 {
+				status = args[1]
+			}
+			text, err := slackListRunes(ctx, cfg.ProjectionStore, realmID, status)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			slackReply(w, text)
+		case "claim":
+			if len(args) != 2 {
+				slackReply(w, "Usage: `/bifrost claim <rune-id>`")
+				return
+			}
+			if domain.RoleLevel(role) < domain.RoleLevel(domain.RoleMember) {
+				slackReply(w, "You need the member role in this realm to claim runes.")
+				return
+			}
+			runeID := args[1]
+			if err := domain.HandleClaimRune(ctx, realmID, domain.ClaimRune{ID: runeID, Claimant: username}, cfg.EventStore, cfg.ProjectionStore); err != nil {
+				slackReply(w, fmt.Sprintf("Could not claim %s: %v", runeID, err))
+				return
+			}
+			cfg.Engine.RunCatchUpOnce(ctx)
+			title := ""
+			if detail, found, _ := lookupRune(ctx, cfg.ProjectionStore, realmID, runeID); found {
+				title = ": " + detail.Title
+			}
+			slackAnnounce(w, fmt.Sprintf("%s claimed *%s*%s", username, runeID, title))
+		default:
+			slackReply(w, fmt.Sprintf("Unknown command %q.\n%s", args[0], slackUsage))
+		}
+	}
+}
+
+// slackAccount resolves the Slack user to the linked account's username and
+// its role in the realm. problem explains to the user why they cannot act.
+func slackAccount(ctx context.Context, store core.ProjectionStore, realmID string, settings map[string]string, slackUserID string) (username, role, problem string, err error) {
+	username = settings[SlackUserSettingPrefix+slackUserID]
+	if slackUserID == "" || username == "" {
+		return "", "", fmt.Sprintf("Your Slack user is not linked to a Bifrost account. Ask a realm admin to set `%s%s` to your username.", SlackUserSettingPrefix, slackUserID), nil
+	}
+
+	var accountID string
+	var info projectors.AccountLookupEntry
+	err = store.Get(ctx, domain.AdminRealmID, "account_lookup", "username:"+username, &accountID)
+	if err == nil {
+		err = store.Get(ctx, domain.AdminRealmID, "account_lookup", "accountinfo:"+accountID, &info)
+	}
+	var nfe *core.NotFoundError
+	if errors.As(err, &nfe) || (err == nil && info.Status != "active") {
+		return "", "", fmt.Sprintf("The Bifrost account %q linked to your Slack user is missing or suspended.", username), nil
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	return username, info.Roles[realmID], "", nil
+}
+
+func slackListRunes(ctx context.Context, store core.ProjectionStore, realmID, status string) (string, error) {
+	raws, err := store.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return "", err
+	}
+	var runes []projectors.RuneSummary
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) == nil && summary.ID != "" && summary.Status == status {
+			runes = append(runes, summary)
+		}
+	}
+	if len(runes) == 0 {
+		return fmt.Sprintf("No %s runes.", status), nil
+	}
+	sort.Slice(runes, func(i, j int) bool {
+		if runes[i].Priority != runes[j].Priority {
+			return runes[i].Priority < runes[j].Priority
+		}
+		return runes[i].ID < runes[j].ID
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s rune(s):", len(runes), status)
+	for i, summary := range runes {
+		if i == slackListLimit {
+			fmt.Fprintf(&b, "\n…and %d more", len(runes)-slackListLimit)
+			break
+		}
+		fmt.Fprintf(&b, "\n• `%s` P%d %s", summary.ID, summary.Priority, summary.Title)
+		if summary.Claimant != "" {
+			fmt.Fprintf(&b, " (%s)", summary.Claimant)
+		}
+	}
+	return b.String(), nil
+}
+
+// validSlackSignature checks Slack's v0 request signature and rejects
+// requests signed more than slackMaxClockSkew from now.
+func validSlackSignature(secret string, body []byte, timestamp, signature string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > slackMaxClockSkew || skew < -slackMaxClockSkew {
+		return false
+	}
+	sig, ok := strings.CutPrefix(signature, "v0=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,301 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSlackCommand(t *testing.T) {
+	t.Run("returns 404 when integration is not configured", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "list")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("returns 401 when signature is invalid", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.routes_are_registered()
+		tc.signingSecret = "wrong"
+
+		// When
+		tc.command_is_sent("U1", "list")
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+	})
+
+	t.Run("returns 401 when the request is stale", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.routes_are_registered()
+		tc.signedAt = time.Now().Add(-10 * time.Minute)
+
+		// When
+		tc.command_is_sent("U1", "list")
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+	})
+
+	t.Run("replies with usage for help", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.reply_is_ephemeral()
+		assert.Contains(t, tc.reply().Text, "/bifrost claim <rune-id>")
+	})
+
+	t.Run("asks unlinked users to be linked", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U999", "list open")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Contains(t, tc.reply().Text, "slack.user.U999")
+	})
+
+	t.Run("lists open runes by priority", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.linked_account("U1", "alice", domain.RoleViewer)
+		tc.rune_summary("bf-b", "Second", "open", 2)
+		tc.rune_summary("bf-a", "First", "open", 1)
+		tc.rune_summary("bf-c", "Done", "fulfilled", 0)
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "list open")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.reply_is_ephemeral()
+		assert.Equal(t, "2 open rune(s):\n• `bf-a` P1 First\n• `bf-b` P2 Second", tc.reply().Text)
+	})
+
+	t.Run("claims a rune as the linked account", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.linked_account("U1", "alice", domain.RoleMember)
+		tc.rune_exists("bf-a1b2", "open", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "claim bf-a1b2")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, "in_channel", tc.reply().ResponseType)
+		assert.Contains(t, tc.reply().Text, "alice claimed *bf-a1b2*")
+		tc.rune_was_claimed_by("bf-a1b2", "alice")
+		assert.Equal(t, 1, tc.engine.catchUpCalls)
+	})
+
+	t.Run("refuses to claim for viewers", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.linked_account("U1", "alice", domain.RoleViewer)
+		tc.rune_exists("bf-a1b2", "open", "main")
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "claim bf-a1b2")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Contains(t, tc.reply().Text, "member role")
+		assert.False(t, tc.eventStore.hasEvent("realm-1", "rune-bf-a1b2", domain.EventRuneClaimed))
+	})
+
+	t.Run("reports domain errors to the user", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.slack_is_configured()
+		tc.linked_account("U1", "alice", domain.RoleMember)
+		tc.routes_are_registered()
+
+		// When
+		tc.command_is_sent("U1", "claim bf-nope")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.reply_is_ephemeral()
+		assert.Contains(t, tc.reply().Text, "Could not claim bf-nope")
+	})
+}
+
+// --- Test Context ---
+
+type slackTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	engine          *mockProjectionEngine
+	settings        map[string]string
+	signingSecret   string
+	signedAt        time.Time
+	mux             *http.ServeMux
+	recorder        *httptest.ResponseRecorder
+}
+
+func newSlackTestContext(t *testing.T) *slackTestContext {
+	t.Helper()
+	return &slackTestContext{
+		t:               t,
+		eventStore:      newMockEventStore(),
+		projectionStore: newMockProjectionStore(),
+		engine:          &mockProjectionEngine{},
+		settings:        map[string]string{},
+		signingSecret:   "s3cret",
+		signedAt:        time.Now(),
+		recorder:        httptest.NewRecorder(),
+	}
+}
+
+// --- Given ---
+
+func (tc *slackTestContext) slack_is_configured() {
+	tc.t.Helper()
+	tc.settings[SlackSigningSecretSetting] = "s3cret"
+	tc.projectionStore.data["_admin:realm_settings:realm-1"] = projectors.RealmSettingsEntry{
+		RealmID: "realm-1", Settings: tc.settings,
+	}
+}
+
+func (tc *slackTestContext) linked_account(slackUserID, username, role string) {
+	tc.t.Helper()
+	tc.settings[SlackUserSettingPrefix+slackUserID] = username
+	tc.projectionStore.data["_admin:account_lookup:username:"+username] = "acct-" + username
+	tc.projectionStore.data["_admin:account_lookup:accountinfo:acct-"+username] = projectors.AccountLookupEntry{
+		AccountID: "acct-" + username, Username: username, Status: "active", Roles: map[string]string{"realm-1": role},
+	}
+}
+
+func (tc *slackTestContext) rune_summary(runeID, title, status string, priority int) {
+	tc.t.Helper()
+	tc.projectionStore.data["realm-1:rune_list:"+runeID] = projectors.RuneSummary{
+		ID: runeID, Title: title, Status: status, Priority: priority,
+	}
+}
+
+func (tc *slackTestContext) rune_exists(runeID, status, branch string) {
+	tc.t.Helper()
+	tc.projectionStore.data["realm-1:rune_detail:"+runeID] = projectors.RuneDetail{
+		ID: runeID, Title: "Fix the bridge", Status: status, Branch: branch,
+	}
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneCreated, domain.RuneCreated{ID: runeID, Branch: branch})
+	tc.eventStore.appendToStream("realm-1", "rune-"+runeID, domain.EventRuneForged, domain.RuneForged{ID: runeID})
+}
+
+func (tc *slackTestContext) routes_are_registered() {
+	tc.t.Helper()
+	tc.mux = http.NewServeMux()
+	RegisterRoutes(tc.mux, &RouteConfig{
+		EventStore:      tc.eventStore,
+		ProjectionStore: tc.projectionStore,
+		Engine:          tc.engine,
+	})
+}
+
+// --- When ---
+
+func (tc *slackTestContext) command_is_sent(slackUserID, text string) {
+	tc.t.Helper()
+	body := url.Values{
+		"command":   {"/bifrost"},
+		"text":      {text},
+		"user_id":   {slackUserID},
+		"user_name": {"someone"},
+		"team_id":   {"T1"},
+	}.Encode()
+	timestamp := strconv.FormatInt(tc.signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(tc.signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/slack/commands/realm-1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	tc.mux.ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *slackTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code, "body: %s", tc.recorder.Body.String())
+}
+
+func (tc *slackTestContext) reply() slackCommandResponse {
+	tc.t.Helper()
+	var resp slackCommandResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
+
+func (tc *slackTestContext) reply_is_ephemeral() {
+	tc.t.Helper()
+	assert.Equal(tc.t, "ephemeral", tc.reply().ResponseType)
+}
+
+func (tc *slackTestContext) rune_was_claimed_by(runeID, claimant string) {
+	tc.t.Helper()
+	for _, evt := range tc.eventStore.streams["realm-1:rune-"+runeID] {
+		if evt.EventType == domain.EventRuneClaimed {
+			var claimed domain.RuneClaimed
+			require.NoError(tc.t, json.Unmarshal(evt.Data, &claimed))
+			assert.Equal(tc.t, claimant, claimed.Claimant)
+			return
+		}
+	}
+	tc.t.Fatalf("expected %s to be claimed", runeID)
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RouteLimit overrides the server-wide request limits for the paths that
+// start with its prefix.
+type RouteLimit struct {
+	MaxBodyBytes int64         // Largest request body accepted (server-wide limit when zero)
+	Timeout      time.Duration // Read and write deadline from the start of the handler (server timeouts when zero)
+}
+
+// DefaultRouteLimits allows GitHub and GitLab webhook payloads up to the 25 MB
+// GitHub sends, gives GitHub imports, which page through the GitHub API,
+// five minutes, and gives projection replays, which read the whole history,
+// thirty. Realm exports and imports, which move a whole realm, get thirty
+// minutes too, and imports may send up to 1 GiB.
+func DefaultRouteLimits() map[string]RouteLimit {
+	return map[string]RouteLimit{
+		"/integrations/github/":   {MaxBodyBytes: 25 << 20},
+		"/integrations/gitlab/":   {MaxBodyBytes: 25 << 20},
+		"/api/import-github":      {Timeout: 5 * time.Minute},
+		"/api/replay-projections": {Timeout: 30 * time.Minute},
+		"/api/export-realm":       {Timeout: 30 * time.Minute},
+		"/api/import-realm":       {MaxBodyBytes: 1 << 30, Timeout: 30 * time.Minute},
+	}
+}
+
+// LimitRequests returns HTTP middleware that caps request bodies at
+// maxBodyBytes, or at the limit of the longest route prefix matching the
+// path. Reading past the cap fails with *http.MaxBytesError. A matching
+// route's timeout replaces the server's read and write timeouts.
+func LimitRequests(maxBodyBytes int64, routes map[string]RouteLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := routeLimit(routes, r.URL.Path)
+			if limit.MaxBodyBytes == 0 {
+				limit.MaxBodyBytes = maxBodyBytes
+			}
+			if limit.MaxBodyBytes > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit.MaxBodyBytes)
+			}
+			if limit.Timeout > 0 {
+				// Writers that cannot set deadlines, such as test recorders,
+				// keep the server's.
+				deadline := time.Now().Add(limit.Timeout)
+				rc := http.NewResponseController(w)
+				_ = rc.SetReadDeadline(deadline)
+				_ = rc.SetWriteDeadline(deadline)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeLimit returns the limit of the longest prefix of path in routes.
+func routeLimit(routes map[string]RouteLimit, path string) RouteLimit {
+	var best string
+	var limit RouteLimit
+	for prefix, l := range routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(best) {
+			best, limit = prefix, l
+		}
+	}
+	return limit
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestLimitRequests(t *testing.T) {
+	t.Run("rejects a body over the server-wide limit with 413", func(t *testing.T) {
+		tc := newLimitsTestContext(t)
+
+		// Given
+		tc.server_wide_body_limit(16)
+
+		// When
+		tc.json_body_is_posted_to("/api/create-rune", 32)
+
+		// Then
+		tc.status_is(http.StatusRequestEntityTooLarge)
+		tc.response_body_contains("request body exceeds 16 bytes")
+	})
+
+	t.Run("accepts a body within the limit", func(t *testing.T) {
+		tc := newLimitsTestContext(t)
+
+		// Given
+		tc.server_wide_body_limit(64)
+
+		// When
+		tc.json_body_is_posted_to("/api/create-rune", 32)
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("applies the limit of the longest matching route prefix", func(t *testing.T) {
+		tc := newLimitsTestContext(t)
+
+		// Given
+		tc.server_wide_body_limit(16)
+		tc.route_limit("/integrations/", RouteLimit{MaxBodyBytes: 24})
+		tc.route_limit("/integrations/github/", RouteLimit{MaxBodyBytes: 64})
+
+		// When
+		tc.json_body_is_posted_to("/integrations/github/realm-1", 32)
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("keeps the server-wide limit for a route that only sets a timeout", func(t *testing.T) {
+		tc := newLimitsTestContext(t)
+
+		// Given
+		tc.server_wide_body_limit(16)
+		tc.route_limit("/api/import-github", RouteLimit{Timeout: time.Minute})
+
+		// When
+		tc.json_body_is_posted_to("/api/import-github", 32)
+
+		// Then
+		tc.status_is(http.StatusRequestEntityTooLarge)
+	})
+}
+
+// --- Test Context ---
+
+type limitsTestContext struct {
+	t *testing.T
+
+	maxBodyBytes int64
+	routes       map[string]RouteLimit
+	recorder     *httptest.ResponseRecorder
+}
+
+func newLimitsTestContext(t *testing.T) *limitsTestContext {
+	t.Helper()
+	return &limitsTestContext{t: t, routes: make(map[string]RouteLimit)}
+}
+
+// --- Given ---
+
+func (tc *limitsTestContext) server_wide_body_limit(n int64) {
+	tc.t.Helper()
+	tc.maxBodyBytes = n
+}
+
+func (tc *limitsTestContext) route_limit(prefix string, limit RouteLimit) {
+	tc.t.Helper()
+	tc.routes[prefix] = limit
+}
+
+// --- When ---
+
+// json_body_is_posted_to posts a JSON object of exactly size bytes to a
+// handler that decodes it as a command body.
+func (tc *limitsTestContext) json_body_is_posted_to(path string, size int) {
+	tc.t.Helper()
+	body := `{"title":"` + strings.Repeat("a", size-len(`{"title":""}`)) + `"}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cmd struct {
+			Title string `json:"title"`
+		}
+		if decodeJSONBody(w, r, &cmd) {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	tc.recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	LimitRequests(tc.maxBodyBytes, tc.routes)(handler).ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *limitsTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code)
+}
+
+func (tc *limitsTestContext) response_body_contains(substr string) {
+	tc.t.Helper()
+	assert.Contains(tc.t, tc.recorder.Body.String(), substr)
+}
@@ -2,165 +2,72 @@ package server
 
 import (
 	"context"
-	"database/sql"
-	"encoding/base64"
-	"fmt"
 	"log"
-	"net"
-	"net/http"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
-	"time"
-
-	_ "modernc.org/sqlite"
 
 	"github.com/devzeebo/bifrost/core"
-	"github.com/devzeebo/bifrost/domain/projectors"
-	"github.com/devzeebo/bifrost/providers/sqlite"
-	"github.com/devzeebo/bifrost/server/admin"
+	"github.com/devzeebo/bifrost/server/provision"
 )
 
+// Run starts a server configured by cfg and blocks until ctx is cancelled or
+// the process receives SIGINT or SIGTERM, then shuts it down gracefully.
+// With cfg.Restore enabled it restores the database instead and returns.
 func Run(ctx context.Context, cfg *Config) error {
-	// 1. Open DB
-	var db *sql.DB
-	var err error
-	switch cfg.DBDriver {
-	case "sqlite":
-		db, err = sql.Open("sqlite", cfg.DBPath)
-		if err != nil {
-			return fmt.Errorf("open database: %w", err)
-		}
-	default:
-		return fmt.Errorf("unsupported DB driver: %q", cfg.DBDriver)
-	}
-	defer db.Close()
-
-	// 2. Create stores
-	eventStore, err := sqlite.NewEventStore(db)
-	if err != nil {
-		return fmt.Errorf("create event store: %w", err)
-	}
-
-	projectionStore, err := sqlite.NewProjectionStore(db)
-	if err != nil {
-		return fmt.Errorf("create projection store: %w", err)
-	}
-
-	checkpointStore, err := sqlite.NewCheckpointStore(db)
-	if err != nil {
-		return fmt.Errorf("create checkpoint store: %w", err)
+	if cfg.Restore.Enabled() {
+		return runRestore(ctx, cfg)
 	}
-
-	// 3. Create projection engine and register projectors
-	engine := core.NewProjectionEngine(
-		eventStore,
-		projectionStore,
-		checkpointStore,
-		core.WithPollInterval(cfg.CatchUpInterval),
-	)
-
-	engine.Register(projectors.NewRealmListProjector())
-	engine.Register(projectors.NewRuneListProjector())
-	engine.Register(projectors.NewRuneDetailProjector())
-	engine.Register(projectors.NewDependencyGraphProjector())
-	engine.Register(projectors.NewAccountLookupProjector())
-	engine.Register(projectors.NewAccountListProjector())
-	engine.Register(projectors.NewRuneChildCountProjector())
-
-	// 4. Start catch-up in background
-	if err := engine.StartCatchUp(ctx); err != nil {
-		return fmt.Errorf("start catch-up: %w", err)
-	}
-
-	// 5. Set up admin auth config (used by both API and UI routes)
-	adminAuthConfig := admin.DefaultAuthConfig()
-	if keyStr := os.Getenv("ADMIN_JWT_SIGNING_KEY"); keyStr != "" {
-		key, err := base64.RawURLEncoding.DecodeString(keyStr)
-		if err != nil {
-			return fmt.Errorf("decode ADMIN_JWT_SIGNING_KEY: %w", err)
-		}
-		adminAuthConfig.SigningKey = key
-	} else {
-		// Generate a temporary key for development (will change on restart)
-		log.Println("Warning: ADMIN_JWT_SIGNING_KEY not set, generating temporary key (sessions will invalidate on restart)")
-		key, err := admin.GenerateSigningKey()
-		if err != nil {
-			return fmt.Errorf("generate signing key: %w", err)
-		}
-		adminAuthConfig.SigningKey = key
-	}
-
-	// Disable secure cookies for local development
-	adminAuthConfig.CookieSecure = false
-
-	// 6. Set up HTTP routes with auth middleware
-	mux := http.NewServeMux()
-	auth := AuthMiddleware(projectionStore, &AuthConfig{AdminAuthConfig: adminAuthConfig})
-	realmAuth := func(h http.Handler) http.Handler { return auth(RequireRealm(h)) }
-	adminAuth := func(h http.Handler) http.Handler { return auth(RequireAdmin(h)) }
-
-	handlers := NewHandlers(eventStore, projectionStore, engine)
-	handlers.RegisterRoutes(mux, realmAuth, adminAuth)
-
-	// Register admin UI routes
-	result, err := admin.RegisterRoutes(mux, &admin.RouteConfig{
-		AuthConfig:       adminAuthConfig,
-		ProjectionStore:  projectionStore,
-		EventStore:       eventStore,
-		StaticPath:       cfg.AdminUIStaticPath,
-		ViteDevServerURL: cfg.ViteDevServerURL,
-	})
+	srv, err := New(WithConfig(cfg))
 	if err != nil {
-		return fmt.Errorf("register admin routes: %w", err)
-	}
-
-	// Use the wrapped handler (may include Vike proxy)
-	handler := result.Handler
-
-	// 6. Create and start HTTP server
-	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%d", cfg.Port),
-		Handler: handler,
-		BaseContext: func(l net.Listener) context.Context {
-			return ctx
-		},
+		return err
 	}
 
-	// 7. Listen for shutdown signals
 	notifyCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	// Start server in goroutine
-	errCh := make(chan error, 1)
-	go func() {
-		log.Printf("bifrost server listening on :%d", cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- err
-		}
-		close(errCh)
-	}()
+	if err := srv.Start(ctx); err != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer shutdownCancel()
+		_ = srv.Stop(shutdownCtx)
+		return err
+	}
 
 	// Wait for context cancellation or signal
 	<-notifyCtx.Done()
 	log.Println("shutting down...")
 
-	// 8. Graceful shutdown
-	if err := engine.Stop(); err != nil {
-		log.Printf("projection engine stop error: %v", err)
-	}
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer shutdownCancel()
+	return srv.Stop(shutdownCtx)
+}
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		return fmt.Errorf("server shutdown: %w", err)
-	}
-
-	// Wait for ListenAndServe to return
-	if err := <-errCh; err != nil {
+func runProvisioning(ctx context.Context, path string, eventStore core.EventStore, projectionStore core.ProjectionStore) error {
+	spec, err := provision.Load(path)
+	if err != nil {
 		return err
 	}
+	report, err := provision.Reconcile(ctx, spec, eventStore, projectionStore)
+	for _, created := range report.Created {
+		log.Printf("provision: created %s", created)
+	}
+	for _, drift := range report.Drift {
+		log.Printf("provision: drift: %s", drift)
+	}
+	return err
+}
 
-	return nil
+// waitGroupWithin waits for wg, giving up with ctx's error once ctx ends.
+func waitGroupWithin(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
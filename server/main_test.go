@@ -53,6 +53,47 @@ func TestRun(t *testing.T) {
 		// Then
 		tc.run_returned_error_containing("unsupported")
 	})
+
+	t.Run("returns error when the MySQL database cannot be reached", func(t *testing.T) {
+		tc := newRunTestContext(t)
+
+		// Given
+		tc.config_with_db_driver("mysql")
+		tc.cfg.DBDSN = fmt.Sprintf("bifrost@tcp(127.0.0.1:%d)/bifrost", tc.freePort())
+
+		// When
+		tc.run_server_sync()
+
+		// Then
+		tc.run_returned_error_containing("create event store")
+	})
+
+	t.Run("returns error for an unknown critical projector", func(t *testing.T) {
+		tc := newRunTestContext(t)
+
+		// Given
+		tc.valid_config()
+		tc.cfg.CriticalProjectors = []string{"rune_list", "no_such_projector"}
+
+		// When
+		tc.run_server_sync()
+
+		// Then
+		tc.run_returned_error_containing(`unknown projector "no_such_projector"`)
+	})
+
+	t.Run("starts with the in-memory driver", func(t *testing.T) {
+		tc := newRunTestContext(t)
+
+		// Given
+		tc.config_with_db_driver("memory")
+
+		// When
+		tc.run_server()
+
+		// Then
+		tc.server_is_listening()
+	})
 }
 
 // --- Test Context ---
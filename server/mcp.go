@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// MCP (Model Context Protocol) support lets coding agents work with runes
+// through tools. The endpoint speaks JSON-RPC 2.0 over the streamable HTTP
+// transport, answering every request with a single JSON response, and is
+// authenticated with the same PAT and realm header as the rest of the API.
+
+const mcpProtocolVersion = "2025-03-26"
+
+// JSON-RPC error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	// minRole is the role required to call the tool.
+	minRole string
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+func mcpSchema(required []string, props map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "list_ready_runes",
+		Description: "List open runes that are unclaimed, not blocked and not sagas, ordered by priority.",
+		InputSchema: mcpSchema(nil, map[string]any{
+			"branch": map[string]any{"type": "string", "description": "Only runes on this branch"},
+		}),
+		minRole: domain.RoleViewer,
+	},
+	{
+		Name:        "show_rune",
+		Description: "Show a rune's details, dependencies and notes.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id": map[string]any{"type": "string", "description": "Rune ID, e.g. bf-a1b2"},
+		}),
+		minRole: domain.RoleViewer,
+	},
+	{
+		Name:        "claim_rune",
+		Description: "Claim a rune before starting work on it.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id":       map[string]any{"type": "string", "description": "Rune ID"},
+			"claimant": map[string]any{"type": "string", "description": "Claimant (default: the authenticated account)"},
+		}),
+		minRole: domain.RoleMember,
+	},
+	{
+		Name:        "add_note",
+		Description: "Record progress on a rune.",
+		InputSchema: mcpSchema([]string{"id", "text"}, map[string]any{
+			"id":   map[string]any{"type": "string", "description": "Rune ID"},
+			"text": map[string]any{"type": "string", "description": "Note text"},
+		}),
+		minRole: domain.RoleMember,
+	},
+	{
+		Name:        "fulfill_rune",
+		Description: "Mark a claimed rune as fulfilled once the work is done.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id": map[string]any{"type": "string", "description": "Rune ID"},
+		}),
+		minRole: domain.RoleMember,
+	},
+}
+
+func (h *Handlers) MCP(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: "parse error"}})
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: "2.0", ID: rpcID(req.ID), Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}})
+		return
+	}
+	// Notifications carry no ID and get no response body.
+	if len(req.ID) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	result, rpcErr := h.dispatchMCP(r, req)
+	writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+}
+
+func rpcID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func (h *Handlers) dispatchMCP(r *http.Request, req rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = mcpProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "bifrost", "version": "1.0.0"},
+			"instructions":    "Use list_ready_runes to find work, claim_rune before starting, add_note to record progress, and fulfill_rune when done.",
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "tool name is required"}
+		}
+		return h.callMCPTool(r, params.Name, params.Arguments)
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// callMCPTool runs a tool. Domain failures are reported as tool results with
+// isError set so the agent can read and react to them.
+func (h *Handlers) callMCPTool(r *http.Request, name string, rawArgs json.RawMessage) (any, *rpcError) {
+	var tool *mcpTool
+	for i := range mcpTools {
+		if mcpTools[i].Name == name {
+			tool = &mcpTools[i]
+		}
+	}
+	if tool == nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %q", name)}
+	}
+
+	ctx := r.Context()
+	realmID, ok := RealmIDFromContext(ctx)
+	if !ok {
+		return mcpErrorResult("realm ID required"), nil
+	}
+	role, _ := RoleFromContext(ctx)
+	if domain.RoleLevel(role) < domain.RoleLevel(tool.minRole) {
+		return mcpErrorResult(fmt.Sprintf("%s requires the %s role", name, tool.minRole)), nil
+	}
+
+	var args struct {
+		ID       string `json:"id"`
+		Text     string `json:"text"`
+		Claimant string `json:"claimant"`
+		Branch   string `json:"branch"`
+	}
+	if len(rawArgs) > 0 {
+		if err := json.Unmarshal(rawArgs, &args); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "invalid tool arguments"}
+		}
+	}
+	if args.ID == "" && name != "list_ready_runes" {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "id is required"}
+	}
+
+	var ran *Command
+	var err error
+	switch name {
+	case "list_ready_runes":
+		ready, listErr := h.readyRunes(ctx, realmID, args.Branch)
+		if listErr != nil {
+			return mcpErrorResult(listErr.Error()), nil
+		}
+		return mcpJSONResult(ready), nil
+	case "show_rune":
+		var detail projectors.RuneDetail
+		if getErr := h.projectionStore.Get(ctx, realmID, "rune_detail", args.ID, &detail); getErr != nil {
+			return mcpErrorResult(getErr.Error()), nil
+		}
+		return mcpJSONResult(detail), nil
+	case "claim_rune":
+		claimant := args.Claimant
+		if claimant == "" {
+			claimant, _ = AccountIDFromContext(ctx)
+		}
+		cmd := domain.ClaimRune{ID: args.ID, Claimant: claimant}
+		ran, err = h.runCommand(r, realmID, "ClaimRune", &cmd, func(ctx context.Context, events core.EventStore, projections core.ProjectionStore) error {
+			return domain.HandleClaimRune(ctx, realmID, cmd, events, projections)
+		})
+	case "add_note":
+		cmd := domain.AddNote{RuneID: args.ID, Text: args.Text}
+		ran, err = h.runCommand(r, realmID, "AddNote", &cmd, func(ctx context.Context, events core.EventStore, _ core.ProjectionStore) error {
+			return domain.HandleAddNote(ctx, realmID, cmd, events)
+		})
+	case "fulfill_rune":
+		cmd := domain.FulfillRune{ID: args.ID}
+		ran, err = h.runCommand(r, realmID, "FulfillRune", &cmd, func(ctx context.Context, events core.EventStore, _ core.ProjectionStore) error {
+			return domain.HandleFulfillRune(ctx, realmID, cmd, events)
+		})
+	}
+	if err != nil {
+		return mcpErrorResult(err.Error()), nil
+	}
+	// The position lets an agent tell whether a later read reflects the
+	// command, as the X-Bifrost-Position header does for the API.
+	text := fmt.Sprintf("%s: ok", name)
+	if position := ran.Position(); position > 0 {
+		text = fmt.Sprintf("%s: ok at position %d", name, position)
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text}}}, nil
+}
+
+// readyRunes returns open, unclaimed, unblocked leaf runes ordered by
+// priority and then ID.
+func (h *Handlers) readyRunes(ctx context.Context, realmID, branch string) ([]projectors.RuneSummary, error) {
+	raws, err := h.projectionStore.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return nil, err
+	}
+	ready := []projectors.RuneSummary{}
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.ID == "" {
+			continue
+		}
+		if summary.Status != "open" || summary.Claimant != "" {
+			continue
+		}
+		if branch != "" && summary.Branch != branch {
+			continue
+		}
+		var childCount int
+		if err := h.projectionStore.Get(ctx, realmID, "RuneChildCount", summary.ID, &childCount); err == nil && childCount > 0 {
+			continue
+		}
+		if h.isRuneBlocked(ctx, realmID, summary.ID) {
+			continue
+		}
+		ready = append(ready, summary)
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].Priority != ready[j].Priority {
+			return ready[i].Priority < ready[j].Priority
+		}
+		return ready[i].ID < ready[j].ID
+	})
+	return ready, nil
+}
+
+func mcpJSONResult(v any) mcpToolResult {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcpErrorResult(err.Error())
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(data)}}}
+}
+
+func mcpErrorResult(msg string) mcpToolResult {
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: msg}}, IsError: true}
+}
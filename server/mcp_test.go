@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestMCPHandler(t *testing.T) {
+	t.Run("initialize reports tools capability", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleViewer)
+
+		// When
+		tc.mcp_call("initialize", map[string]any{"protocolVersion": "2025-03-26"})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		result := tc.mcp_result()
+		assert.Equal(t, "2025-03-26", result["protocolVersion"])
+		assert.Contains(t, result["capabilities"], "tools")
+	})
+
+	t.Run("acknowledges notifications with 202", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/mcp", map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+
+		// Then
+		tc.status_is(http.StatusAccepted)
+	})
+
+	t.Run("lists tools", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleViewer)
+
+		// When
+		tc.mcp_call("tools/list", nil)
+
+		// Then
+		tools, _ := tc.mcp_result()["tools"].([]any)
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.(map[string]any)["name"].(string))
+		}
+		assert.Equal(t, []string{"list_ready_runes", "show_rune", "claim_rune", "add_note", "fulfill_rune"}, names)
+	})
+
+	t.Run("returns method not found for unknown methods", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.mcp_call("resources/list", nil)
+
+		// Then
+		tc.mcp_error_code_is(rpcMethodNotFound)
+	})
+
+	t.Run("list_ready_runes returns open unblocked leaf runes by priority", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleViewer)
+		tc.projection_has_ready_rune("realm-1", "bf-0002", 2)
+		tc.projection_has_ready_rune("realm-1", "bf-0001", 1)
+		tc.projection_has_ready_rune("realm-1", "bf-saga", 0)
+		tc.projection_has_child_count("realm-1", "bf-saga", 1)
+		tc.projection_has_ready_rune("realm-1", "bf-0003", 0)
+		tc.projection_has_rune_detail_with_dependencies("realm-1", "bf-0003", []projectors.DependencyRef{
+			{TargetID: "bf-0002", Relationship: domain.RelBlockedBy},
+		})
+		tc.projection_has_rune_summary("realm-1", "bf-0004", "claimed")
+
+		// When
+		tc.mcp_tool_call("list_ready_runes", nil)
+
+		// Then
+		var ready []projectors.RuneSummary
+		require.NoError(t, json.Unmarshal([]byte(tc.mcp_tool_text(false)), &ready))
+		require.Len(t, ready, 2)
+		assert.Equal(t, "bf-0001", ready[0].ID)
+		assert.Equal(t, "bf-0002", ready[1].ID)
+	})
+
+	t.Run("show_rune returns rune detail", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleViewer)
+		tc.projection_has_rune_detail("realm-1", "bf-0001")
+
+		// When
+		tc.mcp_tool_call("show_rune", map[string]any{"id": "bf-0001"})
+
+		// Then
+		assert.Contains(t, tc.mcp_tool_text(false), `"title": "Test Rune"`)
+	})
+
+	t.Run("claim_rune claims as the authenticated account by default", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-agent")
+		tc.request_has_role(domain.RoleMember)
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+
+		// When
+		tc.mcp_tool_call("claim_rune", map[string]any{"id": "bf-0001"})
+
+		// Then
+		assert.Equal(t, fmt.Sprintf("claim_rune: ok at position %d", tc.eventStore.position), tc.mcp_tool_text(false))
+		stream := tc.eventStore.streams["realm-1:rune-bf-0001"]
+		require.Len(t, stream, 3)
+		var claimed domain.RuneClaimed
+		require.NoError(t, json.Unmarshal(stream[2].Data, &claimed))
+		assert.Equal(t, "acct-agent", claimed.Claimant)
+	})
+
+	t.Run("add_note and fulfill_rune append events", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleMember)
+		tc.rune_is_claimed_in_event_store("realm-1", "bf-0001", "acct-agent")
+
+		// When
+		tc.mcp_tool_call("add_note", map[string]any{"id": "bf-0001", "text": "halfway"})
+		tc.mcp_tool_text(false)
+		tc.mcp_tool_call("fulfill_rune", map[string]any{"id": "bf-0001"})
+
+		// Then
+		tc.mcp_tool_text(false)
+		stream := tc.eventStore.streams["realm-1:rune-bf-0001"]
+		require.Len(t, stream, 5)
+		assert.Equal(t, domain.EventRuneNoted, stream[3].EventType)
+		assert.Equal(t, domain.EventRuneFulfilled, stream[4].EventType)
+	})
+
+	t.Run("reports domain errors as tool errors", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleMember)
+
+		// When
+		tc.mcp_tool_call("fulfill_rune", map[string]any{"id": "bf-missing"})
+
+		// Then
+		assert.Contains(t, tc.mcp_tool_text(true), "not found")
+	})
+
+	t.Run("viewers cannot call command tools", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleViewer)
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+
+		// When
+		tc.mcp_tool_call("claim_rune", map[string]any{"id": "bf-0001"})
+
+		// Then
+		assert.Contains(t, tc.mcp_tool_text(true), "requires the member role")
+		assert.Len(t, tc.eventStore.streams["realm-1:rune-bf-0001"], 2)
+	})
+
+	t.Run("returns invalid params when id is missing", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_role(domain.RoleMember)
+
+		// When
+		tc.mcp_tool_call("claim_rune", map[string]any{})
+
+		// Then
+		tc.mcp_error_code_is(rpcInvalidParams)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) projection_has_ready_rune(realmID, runeID string, priority int) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", runeID, projectors.RuneSummary{
+		ID: runeID, Status: "open", Priority: priority,
+	})
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) mcp_call(method string, params any) {
+	tc.t.Helper()
+	tc.recorder = httptest.NewRecorder()
+	tc.post("/mcp", map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+}
+
+func (tc *handlerTestContext) mcp_tool_call(name string, args map[string]any) {
+	tc.t.Helper()
+	tc.mcp_call("tools/call", map[string]any{"name": name, "arguments": args})
+}
+
+// --- Then ---
+
+type mcpTestResponse struct {
+	Result map[string]any `json:"result"`
+	Error  *rpcError      `json:"error"`
+}
+
+func (tc *handlerTestContext) mcp_response() mcpTestResponse {
+	tc.t.Helper()
+	var resp mcpTestResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
+
+func (tc *handlerTestContext) mcp_result() map[string]any {
+	tc.t.Helper()
+	resp := tc.mcp_response()
+	require.Nil(tc.t, resp.Error, "unexpected rpc error")
+	return resp.Result
+}
+
+func (tc *handlerTestContext) mcp_error_code_is(code int) {
+	tc.t.Helper()
+	resp := tc.mcp_response()
+	require.NotNil(tc.t, resp.Error)
+	assert.Equal(tc.t, code, resp.Error.Code)
+}
+
+func (tc *handlerTestContext) mcp_tool_text(isError bool) string {
+	tc.t.Helper()
+	result := tc.mcp_result()
+	gotError, _ := result["isError"].(bool)
+	content, _ := result["content"].([]any)
+	require.Len(tc.t, content, 1)
+	text, _ := content[0].(map[string]any)["text"].(string)
+	require.Equal(tc.t, isError, gotError, "tool result: %s", text)
+	return text
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// DomainCollector reports workflow health per realm from the rune
+// projections: runes by status, open runes by priority, blocked runes and
+// claims held too long.
+type DomainCollector struct {
+	store         core.ProjectionStore
+	staleClaimAge time.Duration
+	now           func() time.Time
+}
+
+type DomainCollectorOption func(*DomainCollector)
+
+// WithStaleClaimAge sets how long a rune may stay claimed before it counts
+// as a stale claim.
+func WithStaleClaimAge(age time.Duration) DomainCollectorOption {
+	return func(c *DomainCollector) {
+		c.staleClaimAge = age
+	}
+}
+
+func NewDomainCollector(store core.ProjectionStore, opts ...DomainCollectorOption) *DomainCollector {
+	c := &DomainCollector{
+		store:         store,
+		staleClaimAge: 7 * 24 * time.Hour,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *DomainCollector) Collect(ctx context.Context) ([]Family, error) {
+	realms, err := c.realmIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus := Family{Name: "bifrost_runes", Help: "Runes by status.", Type: TypeGauge}
+	open := Family{Name: "bifrost_open_runes", Help: "Open runes by priority.", Type: TypeGauge}
+	blocked := Family{Name: "bifrost_blocked_runes", Help: "Open or claimed runes blocked by an unfulfilled rune.", Type: TypeGauge}
+	stale := Family{
+		Name: "bifrost_stale_claims",
+		Help: fmt.Sprintf("Runes claimed for longer than %s.", c.staleClaimAge),
+		Type: TypeGauge,
+	}
+
+	for _, realmID := range realms {
+		summaries, err := c.runeSummaries(ctx, realmID)
+		if err != nil {
+			return nil, err
+		}
+		statuses := make(map[string]string, len(summaries))
+		for _, s := range summaries {
+			statuses[s.ID] = s.Status
+		}
+
+		statusCounts := map[string]int{}
+		priorityCounts := map[int]int{}
+		var blockedCount, staleCount int
+		for _, s := range summaries {
+			statusCounts[s.Status]++
+			if s.Status == "open" {
+				priorityCounts[s.Priority]++
+			}
+			if s.Status != "open" && s.Status != "claimed" {
+				continue
+			}
+			isBlocked, err := c.isBlocked(ctx, realmID, s.ID, statuses)
+			if err != nil {
+				return nil, err
+			}
+			if isBlocked {
+				blockedCount++
+			}
+			if s.Status == "claimed" && !s.ClaimedAt.IsZero() && c.now().Sub(s.ClaimedAt) > c.staleClaimAge {
+				staleCount++
+			}
+		}
+
+		realmLabel := Label{Name: "realm", Value: realmID}
+		for _, status := range sortedKeys(statusCounts) {
+			byStatus.Samples = append(byStatus.Samples, Sample{
+				Labels: []Label{realmLabel, {Name: "status", Value: status}},
+				Value:  float64(statusCounts[status]),
+			})
+		}
+		priorities := make([]int, 0, len(priorityCounts))
+		for p := range priorityCounts {
+			priorities = append(priorities, p)
+		}
+		sort.Ints(priorities)
+		for _, p := range priorities {
+			open.Samples = append(open.Samples, Sample{
+				Labels: []Label{realmLabel, {Name: "priority", Value: strconv.Itoa(p)}},
+				Value:  float64(priorityCounts[p]),
+			})
+		}
+		blocked.Samples = append(blocked.Samples, Sample{Labels: []Label{realmLabel}, Value: float64(blockedCount)})
+		stale.Samples = append(stale.Samples, Sample{Labels: []Label{realmLabel}, Value: float64(staleCount)})
+	}
+
+	return []Family{byStatus, open, blocked, stale}, nil
+}
+
+func (c *DomainCollector) realmIDs(ctx context.Context) ([]string, error) {
+	raws, err := c.store.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, raw := range raws {
+		var entry projectors.RealmListEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.RealmID == "" || entry.RealmID == domain.AdminRealmID {
+			continue
+		}
+		ids = append(ids, entry.RealmID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (c *DomainCollector) runeSummaries(ctx context.Context, realmID string) ([]projectors.RuneSummary, error) {
+	raws, err := c.store.List(ctx, realmID, "rune_list")
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]projectors.RuneSummary, 0, len(raws))
+	for _, raw := range raws {
+		var s projectors.RuneSummary
+		if json.Unmarshal(raw, &s) == nil && s.ID != "" {
+			summaries = append(summaries, s)
+		}
+	}
+	return summaries, nil
+}
+
+// isBlocked reports whether the rune has a blocked_by dependency on a rune
+// that is not fulfilled. Unknown blockers count as blocking.
+func (c *DomainCollector) isBlocked(ctx context.Context, realmID, runeID string, statuses map[string]string) (bool, error) {
+	var detail projectors.RuneDetail
+	if err := c.store.Get(ctx, realmID, "rune_detail", runeID, &detail); err != nil {
+		var nfe *core.NotFoundError
+		if errors.As(err, &nfe) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, dep := range detail.Dependencies {
+		if dep.Relationship == domain.RelBlockedBy && statuses[dep.TargetID] != "fulfilled" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestDomainCollector(t *testing.T) {
+	t.Run("counts runes by status and open runes by priority", func(t *testing.T) {
+		tc := newDomainCollectorTestContext(t)
+
+		// Given
+		tc.a_realm("bf-r1")
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-1", Status: "open", Priority: 0})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-2", Status: "open", Priority: 2})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-3", Status: "open", Priority: 2})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-4", Status: "fulfilled", Priority: 2})
+
+		// When
+		tc.collect()
+
+		// Then
+		tc.no_error()
+		tc.sample_is("bifrost_runes", "bf-r1", "status", "open", 3)
+		tc.sample_is("bifrost_runes", "bf-r1", "status", "fulfilled", 1)
+		tc.sample_is("bifrost_open_runes", "bf-r1", "priority", "0", 1)
+		tc.sample_is("bifrost_open_runes", "bf-r1", "priority", "2", 2)
+	})
+
+	t.Run("counts runes blocked by unfulfilled runes", func(t *testing.T) {
+		tc := newDomainCollectorTestContext(t)
+
+		// Given
+		tc.a_realm("bf-r1")
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-1", Status: "open"})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-2", Status: "open"})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-3", Status: "claimed"})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-done", Status: "fulfilled"})
+		tc.blocked_by("bf-r1", "bf-1", "bf-3")
+		tc.blocked_by("bf-r1", "bf-2", "bf-done")
+
+		// When
+		tc.collect()
+
+		// Then
+		tc.no_error()
+		tc.sample_is("bifrost_blocked_runes", "bf-r1", "", "", 1)
+	})
+
+	t.Run("counts claims older than the stale claim age", func(t *testing.T) {
+		tc := newDomainCollectorTestContext(t)
+
+		// Given
+		tc.a_realm("bf-r1")
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-1", Status: "claimed", ClaimedAt: tc.now.Add(-4 * 24 * time.Hour)})
+		tc.a_rune("bf-r1", projectors.RuneSummary{ID: "bf-2", Status: "claimed", ClaimedAt: tc.now.Add(-time.Hour)})
+
+		// When
+		tc.collector = NewDomainCollector(tc.store, WithStaleClaimAge(3*24*time.Hour))
+		tc.collector.now = func() time.Time { return tc.now }
+		tc.collect()
+
+		// Then
+		tc.no_error()
+		tc.sample_is("bifrost_stale_claims", "bf-r1", "", "", 1)
+	})
+
+	t.Run("reports zero for realms without blocked runes or stale claims", func(t *testing.T) {
+		tc := newDomainCollectorTestContext(t)
+
+		// Given
+		tc.a_realm("bf-r1")
+
+		// When
+		tc.collect()
+
+		// Then
+		tc.no_error()
+		tc.sample_is("bifrost_blocked_runes", "bf-r1", "", "", 0)
+		tc.sample_is("bifrost_stale_claims", "bf-r1", "", "", 0)
+	})
+
+	t.Run("skips the admin realm", func(t *testing.T) {
+		tc := newDomainCollectorTestContext(t)
+
+		// Given
+		tc.a_realm(domain.AdminRealmID)
+
+		// When
+		tc.collect()
+
+		// Then
+		tc.no_error()
+		for _, f := range tc.families {
+			assert.Empty(t, f.Samples, f.Name)
+		}
+	})
+}
+
+// --- Test Context ---
+
+type domainCollectorTestContext struct {
+	t *testing.T
+
+	store     *mockProjectionStore
+	collector *DomainCollector
+	now       time.Time
+	families  []Family
+	err       error
+}
+
+func newDomainCollectorTestContext(t *testing.T) *domainCollectorTestContext {
+	t.Helper()
+	store := &mockProjectionStore{data: make(map[string]any)}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	collector := NewDomainCollector(store)
+	collector.now = func() time.Time { return now }
+	return &domainCollectorTestContext{
+		t:         t,
+		store:     store,
+		collector: collector,
+		now:       now,
+	}
+}
+
+// --- Given ---
+
+func (tc *domainCollectorTestContext) a_realm(realmID string) {
+	tc.t.Helper()
+	tc.store.put(domain.AdminRealmID, "realm_list", realmID, projectors.RealmListEntry{RealmID: realmID, Status: "active"})
+}
+
+func (tc *domainCollectorTestContext) a_rune(realmID string, summary projectors.RuneSummary) {
+	tc.t.Helper()
+	tc.store.put(realmID, "rune_list", summary.ID, summary)
+}
+
+func (tc *domainCollectorTestContext) blocked_by(realmID, runeID, blockerID string) {
+	tc.t.Helper()
+	tc.store.put(realmID, "rune_detail", runeID, projectors.RuneDetail{
+		ID:           runeID,
+		Dependencies: []projectors.DependencyRef{{TargetID: blockerID, Relationship: domain.RelBlockedBy}},
+	})
+}
+
+// --- When ---
+
+func (tc *domainCollectorTestContext) collect() {
+	tc.t.Helper()
+	tc.families, tc.err = tc.collector.Collect(context.Background())
+}
+
+// --- Then ---
+
+func (tc *domainCollectorTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+// sample_is asserts the value of the sample labeled with realm and, when
+// labelName is set, labelName=labelValue.
+func (tc *domainCollectorTestContext) sample_is(name, realm, labelName, labelValue string, expected float64) {
+	tc.t.Helper()
+	for _, f := range tc.families {
+		if f.Name != name {
+			continue
+		}
+		for _, s := range f.Samples {
+			want := []Label{{Name: "realm", Value: realm}}
+			if labelName != "" {
+				want = append(want, Label{Name: labelName, Value: labelValue})
+			}
+			if assert.ObjectsAreEqual(want, s.Labels) {
+				assert.Equal(tc.t, expected, s.Value)
+				return
+			}
+		}
+	}
+	tc.t.Errorf("no %s sample for realm %s %s=%s", name, realm, labelName, labelValue)
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]any
+}
+
+func (m *mockProjectionStore) put(realmID, projectionName, key string, value any) {
+	m.data[realmID+":"+projectionName+":"+key] = value
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	val, ok := m.data[realmID+":"+projectionName+":"+key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var keys []string
+	for key := range m.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var results []json.RawMessage
+	for _, key := range keys {
+		data, err := json.Marshal(m.data[key])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, data)
+	}
+	return results, nil
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.put(realmID, projectionName, key, value)
+	return nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data, realmID+":"+projectionName+":"+key)
+	return nil
+}
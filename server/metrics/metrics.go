@@ -0,0 +1,280 @@
+// Package metrics serves metrics in the Prometheus text exposition format.
+//
+// Metrics come from Collectors, which are asked for their current values on
+// every scrape. Gauges derived from projections are computed in Collect;
+// counters kept in memory use CounterVec and latencies HistogramVec.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	TypeGauge     = "gauge"
+	TypeCounter   = "counter"
+	TypeHistogram = "histogram"
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+type Sample struct {
+	// Suffix is appended to the family name, e.g. "_bucket" for the samples
+	// of a histogram.
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family is a named metric and its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+type Collector interface {
+	Collect(ctx context.Context) ([]Family, error)
+}
+
+// Write renders families in the Prometheus text format.
+func Write(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			bw.WriteString(f.Name + s.Suffix)
+			if len(s.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, l := range s.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.FormatFloat(s.Value, 'g', -1, 64))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the collectors' metrics. When token is set, requests must
+// carry it as a bearer token.
+func Handler(token string, collectors ...Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var families []Family
+		for _, c := range collectors {
+			collected, err := c.Collect(r.Context())
+			if err != nil {
+				log.Printf("metrics: collect: %v", err)
+				http.Error(w, "failed to collect metrics", http.StatusInternalServerError)
+				return
+			}
+			families = append(families, collected...)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = Write(w, families)
+	})
+}
+
+// CounterVec is an in-memory counter partitioned by label values.
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+	}
+}
+
+// Inc adds one to the counter for the label values, which must match the
+// label names in number and order.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if len(labelValues) != len(c.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labelNames), len(labelValues)))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[strings.Join(labelValues, "\xff")] += delta
+}
+
+// Value returns the counter for the label values.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[strings.Join(labelValues, "\xff")]
+}
+
+func (c *CounterVec) Collect(_ context.Context) ([]Family, error) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	family := Family{Name: c.name, Help: c.help, Type: TypeCounter}
+	for _, key := range keys {
+		family.Samples = append(family.Samples, Sample{Labels: splitLabels(c.labelNames, key), Value: c.values[key]})
+	}
+	c.mu.Unlock()
+	return []Family{family}, nil
+}
+
+// DefaultBuckets are upper bounds, in seconds, suited to request latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HistogramVec is an in-memory histogram partitioned by label values.
+type HistogramVec struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]*histogram
+}
+
+type histogram struct {
+	// counts holds the cumulative count of each bucket.
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram with the given ascending bucket upper
+// bounds. An implicit +Inf bucket counts every observation.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	return &HistogramVec{
+		name:       name,
+		help:       help,
+		buckets:    buckets,
+		labelNames: labelNames,
+		values:     make(map[string]*histogram),
+	}
+}
+
+// Observe records value for the label values, which must match the label
+// names in number and order.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", h.name, len(h.labelNames), len(labelValues)))
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := strings.Join(labelValues, "\xff")
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// Count returns how many values were observed for the label values.
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if hist, ok := h.values[strings.Join(labelValues, "\xff")]; ok {
+		return hist.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) Collect(_ context.Context) ([]Family, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	family := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	for _, key := range keys {
+		labels := splitLabels(h.labelNames, key)
+		hist := h.values[key]
+		for i, bound := range h.buckets {
+			family.Samples = append(family.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: withLabel(labels, "le", strconv.FormatFloat(bound, 'g', -1, 64)),
+				Value:  float64(hist.counts[i]),
+			})
+		}
+		family.Samples = append(family.Samples,
+			Sample{Suffix: "_bucket", Labels: withLabel(labels, "le", "+Inf"), Value: float64(hist.count)},
+			Sample{Suffix: "_sum", Labels: labels, Value: hist.sum},
+			Sample{Suffix: "_count", Labels: labels, Value: float64(hist.count)},
+		)
+	}
+	return []Family{family}, nil
+}
+
+// splitLabels pairs label names with the values joined in key.
+func splitLabels(names []string, key string) []Label {
+	if len(names) == 0 {
+		return nil
+	}
+	var labels []Label
+	for i, value := range strings.Split(key, "\xff") {
+		labels = append(labels, Label{Name: names[i], Value: value})
+	}
+	return labels
+}
+
+func withLabel(labels []Label, name, value string) []Label {
+	return append(append(make([]Label, 0, len(labels)+1), labels...), Label{Name: name, Value: value})
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestWrite(t *testing.T) {
+	t.Run("renders help, type and labeled samples", func(t *testing.T) {
+		// Given
+		families := []Family{{
+			Name: "bifrost_open_runes",
+			Help: "Open runes by priority.",
+			Type: TypeGauge,
+			Samples: []Sample{
+				{Labels: []Label{{Name: "realm", Value: "bf-r1"}, {Name: "priority", Value: "0"}}, Value: 3},
+				{Value: 1.5},
+			},
+		}}
+
+		// When
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, families))
+
+		// Then
+		assert.Equal(t, "# HELP bifrost_open_runes Open runes by priority.\n"+
+			"# TYPE bifrost_open_runes gauge\n"+
+			"bifrost_open_runes{realm=\"bf-r1\",priority=\"0\"} 3\n"+
+			"bifrost_open_runes 1.5\n", buf.String())
+	})
+
+	t.Run("escapes label values", func(t *testing.T) {
+		// Given
+		families := []Family{{Name: "m", Type: TypeGauge, Samples: []Sample{
+			{Labels: []Label{{Name: "l", Value: "a\"b\\c\nd"}}, Value: 1},
+		}}}
+
+		// When
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, families))
+
+		// Then
+		assert.Contains(t, buf.String(), `m{l="a\"b\\c\nd"} 1`)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("serves collected metrics", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handler = Handler("", tc.counter)
+		tc.counter.Inc("bf-r1")
+
+		// When
+		tc.get("")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Contains(t, tc.recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+		assert.Contains(t, tc.recorder.Body.String(), `test_total{realm="bf-r1"} 1`)
+	})
+
+	t.Run("requires the bearer token when configured", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handler = Handler("s3cret", tc.counter)
+
+		// When
+		tc.get("wrong")
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+	})
+
+	t.Run("accepts the configured bearer token", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handler = Handler("s3cret", tc.counter)
+
+		// When
+		tc.get("s3cret")
+
+		// Then
+		tc.status_is(http.StatusOK)
+	})
+
+	t.Run("returns 500 when a collector fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handler = Handler("", failingCollector{})
+
+		// When
+		tc.get("")
+
+		// Then
+		tc.status_is(http.StatusInternalServerError)
+	})
+}
+
+func TestCounterVec(t *testing.T) {
+	t.Run("collects counters sorted by label values", func(t *testing.T) {
+		// Given
+		c := NewCounterVec("test_total", "Test counter.", "realm")
+		c.Inc("bf-r2")
+		c.Inc("bf-r1")
+		c.Add(2, "bf-r1")
+
+		// When
+		families, err := c.Collect(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		assert.Equal(t, TypeCounter, families[0].Type)
+		assert.Equal(t, []Sample{
+			{Labels: []Label{{Name: "realm", Value: "bf-r1"}}, Value: 3},
+			{Labels: []Label{{Name: "realm", Value: "bf-r2"}}, Value: 1},
+		}, families[0].Samples)
+	})
+
+	t.Run("panics on label count mismatch", func(t *testing.T) {
+		c := NewCounterVec("test_total", "Test counter.", "realm")
+
+		assert.Panics(t, func() { c.Inc() })
+	})
+}
+
+func TestHistogramVec(t *testing.T) {
+	t.Run("collects cumulative buckets, sum and count", func(t *testing.T) {
+		// Given
+		h := NewHistogramVec("test_seconds", "Test histogram.", []float64{0.1, 1}, "realm")
+		h.Observe(0.05, "bf-r1")
+		h.Observe(0.5, "bf-r1")
+		h.Observe(3, "bf-r1")
+
+		// When
+		families, err := h.Collect(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		assert.Equal(t, TypeHistogram, families[0].Type)
+		realm := Label{Name: "realm", Value: "bf-r1"}
+		assert.Equal(t, []Sample{
+			{Suffix: "_bucket", Labels: []Label{realm, {Name: "le", Value: "0.1"}}, Value: 1},
+			{Suffix: "_bucket", Labels: []Label{realm, {Name: "le", Value: "1"}}, Value: 2},
+			{Suffix: "_bucket", Labels: []Label{realm, {Name: "le", Value: "+Inf"}}, Value: 3},
+			{Suffix: "_sum", Labels: []Label{realm}, Value: 3.55},
+			{Suffix: "_count", Labels: []Label{realm}, Value: 3},
+		}, families[0].Samples)
+		assert.Equal(t, uint64(3), h.Count("bf-r1"))
+	})
+
+	t.Run("renders samples under the suffixed name", func(t *testing.T) {
+		// Given
+		h := NewHistogramVec("test_seconds", "Test histogram.", []float64{1})
+		h.Observe(0.5)
+		families, err := h.Collect(context.Background())
+		require.NoError(t, err)
+		var buf bytes.Buffer
+
+		// When
+		require.NoError(t, Write(&buf, families))
+
+		// Then
+		assert.Equal(t, "# HELP test_seconds Test histogram.\n"+
+			"# TYPE test_seconds histogram\n"+
+			"test_seconds_bucket{le=\"1\"} 1\n"+
+			"test_seconds_bucket{le=\"+Inf\"} 1\n"+
+			"test_seconds_sum 0.5\n"+
+			"test_seconds_count 1\n", buf.String())
+	})
+
+	t.Run("panics on label count mismatch", func(t *testing.T) {
+		h := NewHistogramVec("test_seconds", "Test histogram.", DefaultBuckets, "realm")
+
+		assert.Panics(t, func() { h.Observe(1) })
+	})
+}
+
+// --- Test Context ---
+
+type handlerTestContext struct {
+	t *testing.T
+
+	handler  http.Handler
+	counter  *CounterVec
+	recorder *httptest.ResponseRecorder
+}
+
+func newHandlerTestContext(t *testing.T) *handlerTestContext {
+	t.Helper()
+	return &handlerTestContext{
+		t:       t,
+		counter: NewCounterVec("test_total", "Test counter.", "realm"),
+	}
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) get(token string) {
+	tc.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	tc.recorder = httptest.NewRecorder()
+	tc.handler.ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code)
+}
+
+// --- Mocks ---
+
+type failingCollector struct{}
+
+func (failingCollector) Collect(context.Context) ([]Family, error) {
+	return nil, errors.New("boom")
+}
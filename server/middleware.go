@@ -91,7 +91,8 @@ type AuthConfig struct {
 
 // AuthMiddleware returns HTTP middleware that authenticates via:
 // 1. JWT cookie (for UI sessions), OR
-// 2. Bearer token + X-Bifrost-Realm header (for API clients)
+// 2. Bearer token + X-Bifrost-Realm header (for API clients), OR
+// 3. Admin token, for the _admin realm only (for automation)
 func AuthMiddleware(projectionStore core.ProjectionStore, authConfig *AuthConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -124,7 +125,24 @@ func AuthMiddleware(projectionStore core.ProjectionStore, authConfig *AuthConfig
 				return
 			}
 
-			realmID := r.Header.Get("X-Bifrost-Realm")
+			realmID, realmErr := headerRealm(r)
+			if realmErr != nil {
+				http.Error(w, realmErr.Message, realmErr.Status)
+				return
+			}
+			if admin.IsAdminToken(token) {
+				ctx, err := authenticateViaAdminToken(r.Context(), token, realmID, projectionStore)
+				if err != nil {
+					if authErr, ok := err.(*AuthError); ok {
+						http.Error(w, authErr.Message, authErr.Status)
+					} else {
+						http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					}
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
 			if realmID == "" {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
@@ -159,8 +177,11 @@ func authenticateViaJWT(ctx context.Context, token string, cfg *admin.AuthConfig
 		return nil, ErrUnauthorized("Unauthorized")
 	}
 
-	// Get realm from header or cookie, fallback to first available
-	realmID := r.Header.Get("X-Bifrost-Realm")
+	// Get realm from header, host or cookie, fallback to first available
+	realmID, authErr := headerRealm(r)
+	if authErr != nil {
+		return nil, authErr
+	}
 	if realmID == "" {
 		realmID = getSelectedRealm(r, entry.Roles, entry.Realms)
 	}
@@ -191,6 +212,24 @@ func authenticateViaJWT(ctx context.Context, token string, cfg *admin.AuthConfig
 	return ctx, nil
 }
 
+// headerRealm returns the realm named by the X-Bifrost-Realm header or, when
+// there is none, by the request's host. A host mapped to a realm only admits
+// that realm and _admin.
+func headerRealm(r *http.Request) (string, *AuthError) {
+	realmID := r.Header.Get("X-Bifrost-Realm")
+	hostRealm, scoped := HostRealmFromContext(r.Context())
+	if !scoped {
+		return realmID, nil
+	}
+	if realmID == "" {
+		return hostRealm, nil
+	}
+	if realmID != hostRealm && realmID != "_admin" {
+		return "", ErrForbidden("Realm does not match host")
+	}
+	return realmID, nil
+}
+
 // getSelectedRealm returns the realm ID from cookie if valid, otherwise the first available realm.
 func getSelectedRealm(r *http.Request, roles map[string]string, realms []string) string {
 	// Check cookie first
@@ -272,6 +311,29 @@ func authenticateViaBearerToken(ctx context.Context, token string, realmID strin
 	return ctx, nil
 }
 
+// authenticateViaAdminToken validates an admin token, which acts as an
+// admin of the _admin realm and of no other realm.
+func authenticateViaAdminToken(ctx context.Context, token string, realmID string, projectionStore core.ProjectionStore) (context.Context, error) {
+	if realmID == "" {
+		realmID = domain.AdminRealmID
+	}
+	if realmID != domain.AdminRealmID {
+		return nil, ErrForbidden("Admin tokens only access the _admin realm")
+	}
+	entry, err := admin.AuthenticateAdminToken(ctx, projectionStore, token)
+	if errors.Is(err, admin.ErrInvalidToken) {
+		return nil, ErrUnauthorized("Unauthorized")
+	}
+	if err != nil {
+		return nil, ErrInternal("Internal server error")
+	}
+
+	ctx = context.WithValue(ctx, accountIDKey, entry.ID)
+	ctx = context.WithValue(ctx, realmIDKey, realmID)
+	ctx = context.WithValue(ctx, roleKey, domain.RoleAdmin)
+	return ctx, nil
+}
+
 // AuthError represents an authentication/authorization error with HTTP status
 type AuthError struct {
 	Status  int
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/projectors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -159,6 +160,39 @@ func TestAuthMiddleware(t *testing.T) {
 		tc.context_has_role("admin")
 	})
 
+	t.Run("uses the host's realm when X-Bifrost-Realm header is missing", func(t *testing.T) {
+		tc := newTestContext(t)
+
+		// Given
+		tc.request_with_bearer_token(tc.rawKey)
+		tc.request_is_for_host_realm("realm-1")
+		tc.projection_store_has_account_with_roles("acct-1", "alice", "active", map[string]string{"realm-1": "member"})
+
+		// When
+		tc.middleware_is_invoked()
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.context_has_realm_id("realm-1")
+	})
+
+	t.Run("returns 403 when X-Bifrost-Realm header names another realm than the host", func(t *testing.T) {
+		tc := newTestContext(t)
+
+		// Given
+		tc.request_with_bearer_token(tc.rawKey)
+		tc.request_has_realm_header("realm-2")
+		tc.request_is_for_host_realm("realm-1")
+		tc.projection_store_has_account_with_roles("acct-1", "alice", "active", map[string]string{"realm-1": "member", "realm-2": "member"})
+
+		// When
+		tc.middleware_is_invoked()
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+		tc.next_handler_was_not_called()
+	})
+
 	t.Run("falls back to Realms slice with member role for legacy data", func(t *testing.T) {
 		tc := newTestContext(t)
 
@@ -194,6 +228,54 @@ func TestAuthMiddleware(t *testing.T) {
 		tc.next_handler_was_not_called()
 	})
 
+	t.Run("admits admin token as admin of the _admin realm", func(t *testing.T) {
+		tc := newTestContext(t)
+
+		// Given
+		tc.request_with_bearer_token("bfa_" + tc.rawKey)
+		tc.projection_store_has_admin_token("adm-a1b2c3d4")
+
+		// When
+		tc.middleware_is_invoked()
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.context_has_realm_id("_admin")
+		tc.context_has_account_id("adm-a1b2c3d4")
+		tc.context_has_role("admin")
+	})
+
+	t.Run("returns 403 when admin token asks for another realm", func(t *testing.T) {
+		tc := newTestContext(t)
+
+		// Given
+		tc.request_with_bearer_token("bfa_" + tc.rawKey)
+		tc.request_has_realm_header("realm-1")
+		tc.projection_store_has_admin_token("adm-a1b2c3d4")
+
+		// When
+		tc.middleware_is_invoked()
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+		tc.next_handler_was_not_called()
+	})
+
+	t.Run("returns 401 when admin token is unknown or revoked", func(t *testing.T) {
+		tc := newTestContext(t)
+
+		// Given
+		tc.request_with_bearer_token("bfa_" + tc.rawKey)
+		tc.projection_store_has_no_entries()
+
+		// When
+		tc.middleware_is_invoked()
+
+		// Then
+		tc.status_is(http.StatusUnauthorized)
+		tc.next_handler_was_not_called()
+	})
+
 	t.Run("returns 500 when projection store returns unexpected error", func(t *testing.T) {
 		tc := newTestContext(t)
 
@@ -596,6 +678,14 @@ func (tc *testContext) request_has_realm_header(realmID string) {
 	tc.request.Header.Set("X-Bifrost-Realm", realmID)
 }
 
+func (tc *testContext) request_is_for_host_realm(realmID string) {
+	tc.t.Helper()
+	if tc.request == nil {
+		tc.request = httptest.NewRequest(http.MethodGet, "/test", nil)
+	}
+	tc.request = tc.request.WithContext(context.WithValue(tc.request.Context(), hostRealmKey, realmID))
+}
+
 func (tc *testContext) request_has_no_realm_header() {
 	tc.t.Helper()
 	// no realm header set — this is the default
@@ -633,6 +723,12 @@ func (tc *testContext) projection_store_has_account_with_roles(accountID, userna
 	tc.store.put("_admin", "account_lookup", tc.keyHash, entry)
 }
 
+func (tc *testContext) projection_store_has_admin_token(tokenID string) {
+	tc.t.Helper()
+	tc.store.put("_admin", "admin_tokens", tokenID, projectors.AdminTokenEntry{ID: tokenID, Name: "provisioner"})
+	tc.store.put("_admin", "admin_token_hashes", tc.keyHash, tokenID)
+}
+
 func (tc *testContext) projection_store_returns_error() {
 	tc.t.Helper()
 	tc.store.forceError = true
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+)
+
+// Realm settings read by the Discord channel.
+const (
+	DiscordWebhookURLSetting = "discord.webhook_url"
+	DiscordUsernameSetting   = "discord.username"
+)
+
+// Embed colors per notification kind, as Discord's decimal RGB values.
+var discordColors = map[string]int{
+	KindCreated:   0x5865F2,
+	KindClaimed:   0xFEE75C,
+	KindFulfilled: 0x57F287,
+	KindMention:   0xEB459E,
+	KindTest:      0x99AAB5,
+}
+
+var discordTitles = map[string]string{
+	KindCreated:   "Rune created",
+	KindClaimed:   "Rune claimed",
+	KindFulfilled: "Rune fulfilled",
+	KindMention:   "Mentioned on rune",
+	KindTest:      "Test notification",
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+}
+
+type discordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type DiscordChannel struct {
+	client *http.Client
+}
+
+func NewDiscordChannel(client *http.Client) *DiscordChannel {
+	return &DiscordChannel{client: client}
+}
+
+func (c *DiscordChannel) Name() string {
+	return "discord"
+}
+
+func (c *DiscordChannel) Configured(settings map[string]string) bool {
+	return settings[DiscordWebhookURLSetting] != ""
+}
+
+// Send posts the rendered message as the description of a single embed,
+// colored by kind, with the rune and actor as inline fields.
+func (c *DiscordChannel) Send(ctx context.Context, settings map[string]string, n Notification, message string) error {
+	embed := discordEmbed{
+		Title:       discordTitles[n.Kind],
+		Description: message,
+		Color:       discordColors[n.Kind],
+	}
+	if n.RuneID != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "Rune", Value: n.RuneID, Inline: true})
+	}
+	if n.Actor != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "By", Value: n.Actor, Inline: true})
+	}
+
+	payload := discordPayload{
+		Username: settings[DiscordUsernameSetting],
+		Embeds:   []discordEmbed{embed},
+	}
+	return postJSON(ctx, c.client, settings[DiscordWebhookURLSetting], "", payload, nil)
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestDiscordChannel(t *testing.T) {
+	t.Run("is configured with a webhook URL", func(t *testing.T) {
+		tc := newDiscordTestContext(t)
+
+		// Then
+		assert.True(t, tc.channel.Configured(map[string]string{DiscordWebhookURLSetting: "https://discord.test/api/webhooks/1/x"}))
+		assert.False(t, tc.channel.Configured(map[string]string{}))
+	})
+
+	t.Run("posts an embed colored by kind", func(t *testing.T) {
+		tc := newDiscordTestContext(t)
+
+		// Given
+		tc.a_discord_server(http.StatusNoContent)
+		tc.settings = map[string]string{DiscordWebhookURLSetting: tc.server.URL + "/hook"}
+
+		// When
+		tc.send(Notification{Kind: KindFulfilled, RuneID: "bf-a1b2", Actor: "alice"}, "bf-a1b2 fulfilled")
+
+		// Then
+		tc.no_error()
+		tc.request_path_was("/hook")
+		embed := tc.only_embed()
+		assert.Equal(t, "Rune fulfilled", embed.Title)
+		assert.Equal(t, "bf-a1b2 fulfilled", embed.Description)
+		assert.Equal(t, discordColors[KindFulfilled], embed.Color)
+		assert.Equal(t, []discordEmbedField{
+			{Name: "Rune", Value: "bf-a1b2", Inline: true},
+			{Name: "By", Value: "alice", Inline: true},
+		}, embed.Fields)
+	})
+
+	t.Run("overrides username when configured", func(t *testing.T) {
+		tc := newDiscordTestContext(t)
+
+		// Given
+		tc.a_discord_server(http.StatusNoContent)
+		tc.settings = map[string]string{
+			DiscordWebhookURLSetting: tc.server.URL,
+			DiscordUsernameSetting:   "Bifrost",
+		}
+
+		// When
+		tc.send(Notification{Kind: KindTest, RealmID: "realm-1"}, "hello")
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, "Bifrost", tc.receivedBody.Username)
+		assert.Empty(t, tc.only_embed().Fields)
+	})
+
+	t.Run("returns error when Discord rejects the webhook", func(t *testing.T) {
+		tc := newDiscordTestContext(t)
+
+		// Given
+		tc.a_discord_server(http.StatusNotFound)
+		tc.settings = map[string]string{DiscordWebhookURLSetting: tc.server.URL}
+
+		// When
+		tc.send(Notification{Kind: KindCreated}, "hello")
+
+		// Then
+		tc.error_contains("unexpected status 404")
+	})
+}
+
+// --- Test Context ---
+
+type discordTestContext struct {
+	t *testing.T
+
+	channel      *DiscordChannel
+	server       *httptest.Server
+	settings     map[string]string
+	receivedPath string
+	receivedBody discordPayload
+	err          error
+}
+
+func newDiscordTestContext(t *testing.T) *discordTestContext {
+	t.Helper()
+	return &discordTestContext{
+		t:       t,
+		channel: NewDiscordChannel(http.DefaultClient),
+	}
+}
+
+// --- Given ---
+
+func (tc *discordTestContext) a_discord_server(status int) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &tc.receivedBody)
+		w.WriteHeader(status)
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+// --- When ---
+
+func (tc *discordTestContext) send(n Notification, message string) {
+	tc.t.Helper()
+	tc.err = tc.channel.Send(context.Background(), tc.settings, n, message)
+}
+
+// --- Then ---
+
+func (tc *discordTestContext) no_error() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *discordTestContext) error_contains(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substr)
+}
+
+func (tc *discordTestContext) request_path_was(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedPath)
+}
+
+func (tc *discordTestContext) only_embed() discordEmbed {
+	tc.t.Helper()
+	require.Len(tc.t, tc.receivedBody.Embeds, 1)
+	return tc.receivedBody.Embeds[0]
+}
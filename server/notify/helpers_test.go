@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// --- Helpers ---
+
+func makeEvent(position int64, eventType string, data any) core.Event {
+	dataBytes, _ := json.Marshal(data)
+	return core.Event{
+		RealmID:        "realm-1",
+		GlobalPosition: position,
+		EventType:      eventType,
+		Data:           dataBytes,
+		Timestamp:      time.Now(),
+	}
+}
+
+// --- Mock Channel ---
+
+type sentMessage struct {
+	notification Notification
+	message      string
+}
+
+type mockChannel struct {
+	name    string
+	sent    []sentMessage
+	sendErr error
+}
+
+func (c *mockChannel) Name() string {
+	return c.name
+}
+
+func (c *mockChannel) Configured(settings map[string]string) bool {
+	return settings[c.name+".enabled"] == "true"
+}
+
+func (c *mockChannel) Send(_ context.Context, _ map[string]string, n Notification, message string) error {
+	c.sent = append(c.sent, sentMessage{notification: n, message: message})
+	return c.sendErr
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]any
+}
+
+func newMockProjectionStore() *mockProjectionStore {
+	return &mockProjectionStore{data: make(map[string]any)}
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	val, ok := m.data[realmID+":"+projectionName+":"+key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	dataBytes, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dataBytes, dest)
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.data[realmID+":"+projectionName+":"+key] = value
+	return nil
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var result []json.RawMessage
+	for key, val := range m.data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		dataBytes, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dataBytes)
+	}
+	return result, nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data, realmID+":"+projectionName+":"+key)
+	return nil
+}
@@ -0,0 +1,397 @@
+// Package notify routes domain events to chat channels such as Slack and
+// Discord.
+//
+// The Router is registered with the projection engine like a projector, so it
+// sees every event once per checkpoint. Because notifications are side
+// effects rather than projections, it drops events older than a configurable
+// age (so a fresh checkpoint does not replay history into chat) and events it
+// has already dispatched in this process.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Notification kinds. Each channel subscribes to kinds through its
+// "<channel>.events" realm setting.
+const (
+	KindCreated   = "created"
+	KindClaimed   = "claimed"
+	KindFulfilled = "fulfilled"
+	KindMention   = "mention"
+	KindEscalated = "escalated"
+	KindTest      = "test"
+)
+
+// AllKinds lists the kinds delivered when a channel does not restrict them.
+var AllKinds = []string{KindCreated, KindClaimed, KindFulfilled, KindMention, KindEscalated}
+
+// Notification describes a rune event worth telling people about.
+type Notification struct {
+	Kind     string
+	RealmID  string
+	RuneID   string
+	Title    string
+	Actor    string
+	Text     string
+	Mentions []string
+}
+
+// Channel delivers rendered notifications to an external service. Its Name
+// is also the prefix of the realm settings that configure it.
+type Channel interface {
+	Name() string
+	Configured(settings map[string]string) bool
+	Send(ctx context.Context, settings map[string]string, n Notification, message string) error
+}
+
+// DeliveryError reports that a channel was configured but the external
+// service rejected or failed to receive a notification.
+type DeliveryError struct {
+	Channel string
+	Err     error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("deliver %s notification: %v", e.Channel, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+var defaultTemplates = map[string]string{
+	KindCreated:   `New rune {{.RuneID}}: {{.Title}}`,
+	KindClaimed:   `{{.Actor}} claimed {{.RuneID}}{{if .Title}}: {{.Title}}{{end}}`,
+	KindFulfilled: `{{.RuneID}} fulfilled{{if .Title}}: {{.Title}}{{end}}`,
+	KindMention:   `{{join .Mentions ", "}} mentioned on {{.RuneID}}: {{.Text}}`,
+	KindEscalated: `Escalation on {{.RuneID}}{{if .Title}}: {{.Title}}{{end}} ({{.Text}}){{if .Mentions}} cc {{join .Mentions ", "}}{{end}}`,
+	KindTest:      `Test notification from Bifrost realm {{.RealmID}}`,
+}
+
+// templateFuncs returns the functions templates may call: join, and
+// feature, which reports whether a feature flag is on in the realm.
+func templateFuncs(settings map[string]string) template.FuncMap {
+	return template.FuncMap{
+		"join":    strings.Join,
+		"feature": func(name string) bool { return domain.FeatureEnabled(settings, name) },
+	}
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9_.-]+)`)
+
+type Router struct {
+	store    core.ProjectionStore
+	channels []Channel
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	dispatched map[string]int64
+}
+
+type RouterOption func(*Router)
+
+// WithMaxEventAge sets how old an event may be and still be delivered.
+func WithMaxEventAge(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.maxAge = d
+	}
+}
+
+func NewRouter(store core.ProjectionStore, channels []Channel, opts ...RouterOption) *Router {
+	r := &Router{
+		store:      store,
+		channels:   channels,
+		maxAge:     10 * time.Minute,
+		now:        time.Now,
+		dispatched: make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Router) Name() string {
+	return "notifications"
+}
+
+// HasSideEffects keeps the router out of command transactions, so nothing
+// is sent for events that are rolled back.
+func (r *Router) HasSideEffects() bool {
+	return true
+}
+
+// Handle dispatches a notification for the event to every configured channel
+// that subscribes to its kind. Delivery failures are logged rather than
+// returned so they never hold back the checkpoint.
+func (r *Router) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	if !r.claim(event) {
+		return nil
+	}
+	if !event.Timestamp.IsZero() && r.now().Sub(event.Timestamp) > r.maxAge {
+		return nil
+	}
+
+	n, ok, err := notificationFor(ctx, event, store)
+	if err != nil || !ok {
+		return err
+	}
+
+	settings, err := projectors.GetRealmSettings(ctx, store, event.RealmID)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := recipientPreferences(ctx, store, n)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range r.channels {
+		if !ch.Configured(settings) || !subscribed(settings, ch.Name(), n.Kind) {
+			continue
+		}
+		personal, ok := withoutMuted(n, ch.Name(), prefs)
+		if !ok {
+			continue
+		}
+		if err := r.send(ctx, ch, settings, personal); err != nil {
+			log.Printf("notify: %v", err)
+		}
+	}
+	return nil
+}
+
+// SendTest delivers a test notification through the named channel using the
+// realm's current settings.
+func (r *Router) SendTest(ctx context.Context, realmID, channel string) error {
+	settings, err := projectors.GetRealmSettings(ctx, r.store, realmID)
+	if err != nil {
+		return err
+	}
+	for _, ch := range r.channels {
+		if ch.Name() != channel {
+			continue
+		}
+		if !ch.Configured(settings) {
+			return domain.Rejectf(domain.ErrInvalidCommand, "cannot send test notification: %s is not configured for this realm", channel)
+		}
+		return r.send(ctx, ch, settings, Notification{Kind: KindTest, RealmID: realmID})
+	}
+	return domain.Rejectf(domain.ErrInvalidCommand, "unknown notification channel %q", channel)
+}
+
+func (r *Router) send(ctx context.Context, ch Channel, settings map[string]string, n Notification) error {
+	message, err := render(settings, ch.Name(), n)
+	if err != nil {
+		return &DeliveryError{Channel: ch.Name(), Err: err}
+	}
+	if err := ch.Send(ctx, settings, n, message); err != nil {
+		return &DeliveryError{Channel: ch.Name(), Err: err}
+	}
+	return nil
+}
+
+// claim records the event as dispatched, returning false when it already
+// was. Catch-up cycles can overlap, so the same event may arrive twice.
+func (r *Router) claim(event core.Event) bool {
+	if event.GlobalPosition == 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.GlobalPosition <= r.dispatched[event.RealmID] {
+		return false
+	}
+	r.dispatched[event.RealmID] = event.GlobalPosition
+	return true
+}
+
+func notificationFor(ctx context.Context, event core.Event, store core.ProjectionStore) (Notification, bool, error) {
+	n := Notification{RealmID: event.RealmID}
+
+	switch event.EventType {
+	case domain.EventRuneCreated:
+		var data domain.RuneCreated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return n, false, err
+		}
+		n.Kind, n.RuneID, n.Title = KindCreated, data.ID, data.Title
+		return n, true, nil
+	case domain.EventRuneClaimed:
+		var data domain.RuneClaimed
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return n, false, err
+		}
+		n.Kind, n.RuneID, n.Actor = KindClaimed, data.ID, data.Claimant
+	case domain.EventRuneFulfilled:
+		var data domain.RuneFulfilled
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return n, false, err
+		}
+		n.Kind, n.RuneID = KindFulfilled, data.ID
+	case domain.EventRuneNoted:
+		var data domain.RuneNoted
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return n, false, err
+		}
+		for _, m := range mentionPattern.FindAllStringSubmatch(data.Text, -1) {
+			n.Mentions = append(n.Mentions, "@"+m[1])
+		}
+		if len(n.Mentions) == 0 {
+			return n, false, nil
+		}
+		n.Kind, n.RuneID, n.Text = KindMention, data.RuneID, data.Text
+	case domain.EventRuneEscalated:
+		var data domain.RuneEscalated
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return n, false, err
+		}
+		if data.Action != domain.EscalationActionNotifyAdmins {
+			return n, false, nil
+		}
+		admins, err := realmAdmins(ctx, store, event.RealmID)
+		if err != nil {
+			return n, false, err
+		}
+		n.Kind, n.RuneID, n.Text, n.Mentions = KindEscalated, data.ID, data.PolicyName, admins
+	default:
+		return n, false, nil
+	}
+
+	var detail projectors.RuneDetail
+	if err := store.Get(ctx, event.RealmID, "rune_detail", n.RuneID, &detail); err == nil {
+		n.Title = detail.Title
+	}
+	return n, true, nil
+}
+
+// realmAdmins returns the active owners and admins of the realm as
+// @mentions, sorted by username.
+func realmAdmins(ctx context.Context, store core.ProjectionStore, realmID string) ([]string, error) {
+	raws, err := store.List(ctx, domain.AdminRealmID, "account_list")
+	if err != nil {
+		return nil, err
+	}
+	var admins []string
+	for _, raw := range raws {
+		var entry projectors.AccountListEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.Status != "active" {
+			continue
+		}
+		if domain.RoleLevel(entry.Roles[realmID]) >= domain.RoleLevel(domain.RoleAdmin) {
+			admins = append(admins, "@"+entry.Username)
+		}
+	}
+	sort.Strings(admins)
+	return admins, nil
+}
+
+// subscribed reports whether the channel's "<name>.events" setting includes
+// kind. An unset value subscribes to every kind.
+func subscribed(settings map[string]string, channel, kind string) bool {
+	if kind == KindTest {
+		return true
+	}
+	value, ok := settings[channel+".events"]
+	if !ok || strings.TrimSpace(value) == "" {
+		return true
+	}
+	for _, k := range strings.Split(value, ",") {
+		if strings.TrimSpace(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// recipientPreferences loads the notification preferences of the accounts n
+// is addressed to: the people mentioned, or the claimant of a claim. Names
+// that are not account usernames have no preferences.
+func recipientPreferences(ctx context.Context, store core.ProjectionStore, n Notification) (map[string]projectors.NotificationPreferencesEntry, error) {
+	var usernames []string
+	switch n.Kind {
+	case KindMention, KindEscalated:
+		for _, m := range n.Mentions {
+			usernames = append(usernames, strings.TrimPrefix(m, "@"))
+		}
+	case KindClaimed:
+		usernames = append(usernames, n.Actor)
+	}
+
+	prefs := make(map[string]projectors.NotificationPreferencesEntry)
+	for _, username := range usernames {
+		var accountID string
+		err := store.Get(ctx, domain.AdminRealmID, "account_lookup", "username:"+username, &accountID)
+		var nfe *core.NotFoundError
+		if errors.As(err, &nfe) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry, err := projectors.GetNotificationPreferences(ctx, store, accountID)
+		if err != nil {
+			return nil, err
+		}
+		prefs[username] = entry
+	}
+	return prefs, nil
+}
+
+// withoutMuted drops the recipients who muted n's kind on channel, reporting
+// false when none are left. Notifications addressed to nobody in particular,
+// such as new runes, are unaffected.
+func withoutMuted(n Notification, channel string, prefs map[string]projectors.NotificationPreferencesEntry) (Notification, bool) {
+	switch n.Kind {
+	case KindMention, KindEscalated:
+		var mentions []string
+		for _, m := range n.Mentions {
+			if !prefs[strings.TrimPrefix(m, "@")].Mutes(channel, n.Kind) {
+				mentions = append(mentions, m)
+			}
+		}
+		n.Mentions = mentions
+		// An escalation is still posted when every admin muted it; only
+		// the mentions are dropped.
+		return n, len(mentions) > 0 || n.Kind == KindEscalated
+	case KindClaimed:
+		return n, !prefs[n.Actor].Mutes(channel, n.Kind)
+	}
+	return n, true
+}
+
+// render formats n using the "<channel>.template.<kind>" setting, falling
+// back to the default template for the kind.
+func render(settings map[string]string, channel string, n Notification) (string, error) {
+	text, ok := settings[channel+".template."+n.Kind]
+	if !ok {
+		text = defaultTemplates[n.Kind]
+	}
+	tmpl, err := template.New(n.Kind).Funcs(templateFuncs(settings)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse %s template: %w", n.Kind, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return "", fmt.Errorf("render %s template: %w", n.Kind, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,494 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRouter(t *testing.T) {
+	t.Run("Name returns notifications", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.a_router()
+
+		// Then
+		assert.Equal(t, "notifications", tc.router.Name())
+	})
+
+	t.Run("sends created notification to configured channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.messages_sent_are("New rune bf-a1b2: Fix the bridge")
+	})
+
+	t.Run("skips channels that are not configured", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.messages_sent_are()
+	})
+
+	t.Run("includes claimant and title from rune detail for claims", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.rune_detail("bf-a1b2", "Fix the bridge")
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1b2", Claimant: "odin"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("odin claimed bf-a1b2: Fix the bridge")
+	})
+
+	t.Run("sends mention notification for notes with @mentions", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1b2", Text: "ping @thor and @freya"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("@thor, @freya mentioned on bf-a1b2: ping @thor and @freya")
+	})
+
+	t.Run("leaves out mentioned accounts that muted mentions on the channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.an_account_muting("thor", "acct-thor", "chat", KindMention)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1b2", Text: "ping @thor and @freya"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("@freya mentioned on bf-a1b2: ping @thor and @freya")
+	})
+
+	t.Run("skips mentions when every mentioned account muted them", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.an_account_muting("thor", "acct-thor", "chat", KindMention)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1b2", Text: "ping @thor"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("keeps mentions muted only on another channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.an_account_muting("thor", "acct-thor", "slack", KindMention)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1b2", Text: "ping @thor"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("@thor mentioned on bf-a1b2: ping @thor")
+	})
+
+	t.Run("skips claims by a claimant that muted them", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.an_account_muting("odin", "acct-odin", "chat", KindClaimed)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1b2", Claimant: "odin"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("sends escalations to the realm's admins", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.rune_detail("bf-a1b2", "Fix the bridge")
+		tc.an_account("acct-thor", "thor", "active", domain.RoleAdmin)
+		tc.an_account("acct-odin", "odin", "active", domain.RoleOwner)
+		tc.an_account("acct-loki", "loki", "suspended", domain.RoleAdmin)
+		tc.an_account("acct-freya", "freya", "active", domain.RoleMember)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneEscalated, domain.RuneEscalated{
+			ID: "bf-a1b2", PolicyID: "ep-1", PolicyName: "Overdue", Action: domain.EscalationActionNotifyAdmins,
+		}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		tc.messages_sent_are("Escalation on bf-a1b2: Fix the bridge (Overdue) cc @odin, @thor")
+	})
+
+	t.Run("still posts escalations when every admin muted them", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.an_account("acct-thor", "thor", "active", domain.RoleAdmin)
+		tc.an_account_muting("thor", "acct-thor", "chat", KindEscalated)
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneEscalated, domain.RuneEscalated{
+			ID: "bf-a1b2", PolicyName: "Overdue", Action: domain.EscalationActionNotifyAdmins,
+		}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("Escalation on bf-a1b2 (Overdue)")
+	})
+
+	t.Run("ignores escalations that do not notify admins", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneEscalated, domain.RuneEscalated{
+			ID: "bf-a1b2", PolicyName: "Overdue", Action: domain.EscalationActionBumpPriority,
+		}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("ignores notes without mentions", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1b2", Text: "email me at a@b.c"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("honours the channel events filter", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true", "chat.events": "fulfilled, claimed"})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("uses template override from realm settings", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{
+			"chat.enabled":          "true",
+			"chat.template.created": ":sparkles: {{.Title}} ({{.RuneID}})",
+		})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are(":sparkles: Fix the bridge (bf-a1b2)")
+	})
+
+	t.Run("lets templates check the realm's feature flags", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{
+			"chat.enabled":            "true",
+			"chat.template.created":   `{{.RuneID}}{{if feature "strict_blocking"}} (strict){{end}}`,
+			"feature.strict_blocking": "true",
+		})
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix the bridge"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("bf-a1b2 (strict)")
+	})
+
+	t.Run("does not deliver the same event twice", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		tc.an_event(makeEvent(5, domain.EventRuneFulfilled, domain.RuneFulfilled{ID: "bf-a1b2"}))
+
+		// When
+		tc.handle_is_called()
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are("bf-a1b2 fulfilled")
+	})
+
+	t.Run("drops events older than the max age", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+		evt := makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Old"})
+		evt.Timestamp = time.Now().Add(-time.Hour)
+		tc.an_event(evt)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.messages_sent_are()
+	})
+
+	t.Run("logs delivery failures without returning an error", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.channel.sendErr = errors.New("boom")
+		tc.a_router()
+		tc.an_event(makeEvent(1, domain.EventRuneCreated, domain.RuneCreated{ID: "bf-a1b2", Title: "Fix"}))
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+	})
+}
+
+func TestRouterSendTest(t *testing.T) {
+	t.Run("sends test message through the named channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.a_router()
+
+		// When
+		tc.send_test("chat")
+
+		// Then
+		tc.no_error()
+		tc.messages_sent_are("Test notification from Bifrost realm realm-1")
+	})
+
+	t.Run("returns error for unconfigured channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.a_router()
+
+		// When
+		tc.send_test("chat")
+
+		// Then
+		tc.error_contains("not configured")
+	})
+
+	t.Run("returns error for unknown channel", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.a_router()
+
+		// When
+		tc.send_test("carrier-pigeon")
+
+		// Then
+		tc.error_contains("unknown notification channel")
+	})
+
+	t.Run("wraps channel failures in DeliveryError", func(t *testing.T) {
+		tc := newRouterTestContext(t)
+
+		// Given
+		tc.realm_settings(map[string]string{"chat.enabled": "true"})
+		tc.channel.sendErr = errors.New("boom")
+		tc.a_router()
+
+		// When
+		tc.send_test("chat")
+
+		// Then
+		tc.error_is_delivery_error()
+	})
+}
+
+// --- Test Context ---
+
+type routerTestContext struct {
+	t *testing.T
+
+	store   *mockProjectionStore
+	channel *mockChannel
+	router  *Router
+	event   core.Event
+	err     error
+}
+
+func newRouterTestContext(t *testing.T) *routerTestContext {
+	t.Helper()
+	return &routerTestContext{
+		t:       t,
+		store:   newMockProjectionStore(),
+		channel: &mockChannel{name: "chat"},
+	}
+}
+
+// --- Given ---
+
+func (tc *routerTestContext) realm_settings(settings map[string]string) {
+	tc.t.Helper()
+	tc.store.data["_admin:realm_settings:realm-1"] = projectors.RealmSettingsEntry{RealmID: "realm-1", Settings: settings}
+}
+
+func (tc *routerTestContext) rune_detail(runeID, title string) {
+	tc.t.Helper()
+	tc.store.data["realm-1:rune_detail:"+runeID] = projectors.RuneDetail{ID: runeID, Title: title}
+}
+
+func (tc *routerTestContext) an_account_muting(username, accountID, channel, kind string) {
+	tc.t.Helper()
+	tc.store.data["_admin:account_lookup:username:"+username] = accountID
+	tc.store.data["_admin:notification_preferences:"+accountID] = projectors.NotificationPreferencesEntry{
+		AccountID: accountID,
+		Muted:     map[string][]string{channel: {kind}},
+	}
+}
+
+func (tc *routerTestContext) an_account(accountID, username, status, role string) {
+	tc.t.Helper()
+	tc.store.data["_admin:account_list:"+accountID] = projectors.AccountListEntry{
+		AccountID: accountID,
+		Username:  username,
+		Status:    status,
+		Realms:    []string{"realm-1"},
+		Roles:     map[string]string{"realm-1": role},
+	}
+}
+
+func (tc *routerTestContext) a_router() {
+	tc.t.Helper()
+	tc.router = NewRouter(tc.store, []Channel{tc.channel})
+}
+
+func (tc *routerTestContext) an_event(event core.Event) {
+	tc.t.Helper()
+	tc.event = event
+}
+
+// --- When ---
+
+func (tc *routerTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.router.Handle(context.Background(), tc.event, tc.store)
+}
+
+func (tc *routerTestContext) send_test(channel string) {
+	tc.t.Helper()
+	tc.err = tc.router.SendTest(context.Background(), "realm-1", channel)
+}
+
+// --- Then ---
+
+func (tc *routerTestContext) no_error() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *routerTestContext) error_contains(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substr)
+}
+
+func (tc *routerTestContext) error_is_delivery_error() {
+	tc.t.Helper()
+	var de *DeliveryError
+	assert.True(tc.t, errors.As(tc.err, &de), "expected DeliveryError, got %T: %v", tc.err, tc.err)
+}
+
+func (tc *routerTestContext) messages_sent_are(expected ...string) {
+	tc.t.Helper()
+	var messages []string
+	for _, s := range tc.channel.sent {
+		messages = append(messages, s.message)
+	}
+	if len(expected) == 0 {
+		assert.Empty(tc.t, messages)
+		return
+	}
+	assert.Equal(tc.t, expected, messages)
+}
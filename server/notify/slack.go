@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Realm settings read by the Slack channel. Either an incoming webhook URL
+// or an app token plus channel must be set.
+const (
+	SlackWebhookURLSetting = "slack.webhook_url"
+	SlackTokenSetting      = "slack.token"
+	SlackChannelSetting    = "slack.channel"
+	SlackAPIURLSetting     = "slack.api_url"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+type SlackChannel struct {
+	client *http.Client
+}
+
+func NewSlackChannel(client *http.Client) *SlackChannel {
+	return &SlackChannel{client: client}
+}
+
+func (c *SlackChannel) Name() string {
+	return "slack"
+}
+
+func (c *SlackChannel) Configured(settings map[string]string) bool {
+	return settings[SlackWebhookURLSetting] != "" ||
+		(settings[SlackTokenSetting] != "" && settings[SlackChannelSetting] != "")
+}
+
+func (c *SlackChannel) Send(ctx context.Context, settings map[string]string, _ Notification, message string) error {
+	if url := settings[SlackWebhookURLSetting]; url != "" {
+		return postJSON(ctx, c.client, url, "", map[string]string{"text": message}, nil)
+	}
+
+	apiURL := settings[SlackAPIURLSetting]
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	err := postJSON(ctx, c.client, strings.TrimSuffix(apiURL, "/")+"/chat.postMessage", settings[SlackTokenSetting], map[string]string{
+		"channel": settings[SlackChannelSetting],
+		"text":    message,
+	}, &result)
+	if err != nil {
+		return err
+	}
+	if !result.OK {
+		return fmt.Errorf("slack api error: %s", result.Error)
+	}
+	return nil
+}
+
+// postJSON posts payload to url, optionally with a bearer token, and decodes
+// the response into dest when it is non-nil.
+func postJSON(ctx context.Context, client *http.Client, url, token string, payload, dest any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if dest != nil {
+		return json.Unmarshal(respBody, dest)
+	}
+	return nil
+}
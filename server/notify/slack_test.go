@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSlackChannel(t *testing.T) {
+	t.Run("is configured with a webhook URL", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Then
+		assert.True(t, tc.channel.Configured(map[string]string{SlackWebhookURLSetting: "https://hooks.slack.test/x"}))
+		assert.True(t, tc.channel.Configured(map[string]string{SlackTokenSetting: "xoxb", SlackChannelSetting: "#dev"}))
+		assert.False(t, tc.channel.Configured(map[string]string{SlackTokenSetting: "xoxb"}))
+	})
+
+	t.Run("posts text to incoming webhook", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.a_slack_server(`ok`)
+		tc.settings = map[string]string{SlackWebhookURLSetting: tc.server.URL + "/hook"}
+
+		// When
+		tc.send("hello")
+
+		// Then
+		tc.no_error()
+		tc.request_path_was("/hook")
+		tc.request_body_has("text", "hello")
+	})
+
+	t.Run("posts to chat.postMessage with app token", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.a_slack_server(`{"ok":true}`)
+		tc.settings = map[string]string{
+			SlackTokenSetting:   "xoxb-1",
+			SlackChannelSetting: "#dev",
+			SlackAPIURLSetting:  tc.server.URL,
+		}
+
+		// When
+		tc.send("hello")
+
+		// Then
+		tc.no_error()
+		tc.request_path_was("/chat.postMessage")
+		tc.authorization_was("Bearer xoxb-1")
+		tc.request_body_has("channel", "#dev")
+	})
+
+	t.Run("returns error when Slack API reports failure", func(t *testing.T) {
+		tc := newSlackTestContext(t)
+
+		// Given
+		tc.a_slack_server(`{"ok":false,"error":"channel_not_found"}`)
+		tc.settings = map[string]string{
+			SlackTokenSetting:   "xoxb-1",
+			SlackChannelSetting: "#nope",
+			SlackAPIURLSetting:  tc.server.URL,
+		}
+
+		// When
+		tc.send("hello")
+
+		// Then
+		tc.error_contains("channel_not_found")
+	})
+}
+
+// --- Test Context ---
+
+type slackTestContext struct {
+	t *testing.T
+
+	channel      *SlackChannel
+	server       *httptest.Server
+	settings     map[string]string
+	receivedPath string
+	receivedAuth string
+	receivedBody map[string]string
+	err          error
+}
+
+func newSlackTestContext(t *testing.T) *slackTestContext {
+	t.Helper()
+	return &slackTestContext{
+		t:       t,
+		channel: NewSlackChannel(http.DefaultClient),
+	}
+}
+
+// --- Given ---
+
+func (tc *slackTestContext) a_slack_server(response string) {
+	tc.t.Helper()
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.receivedPath = r.URL.Path
+		tc.receivedAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &tc.receivedBody)
+		_, _ = w.Write([]byte(response))
+	}))
+	tc.t.Cleanup(tc.server.Close)
+}
+
+// --- When ---
+
+func (tc *slackTestContext) send(message string) {
+	tc.t.Helper()
+	tc.err = tc.channel.Send(context.Background(), tc.settings, Notification{Kind: KindCreated}, message)
+}
+
+// --- Then ---
+
+func (tc *slackTestContext) no_error() {
+	tc.t.Helper()
+	assert.NoError(tc.t, tc.err)
+}
+
+func (tc *slackTestContext) error_contains(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substr)
+}
+
+func (tc *slackTestContext) request_path_was(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedPath)
+}
+
+func (tc *slackTestContext) authorization_was(expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedAuth)
+}
+
+func (tc *slackTestContext) request_body_has(key, expected string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.receivedBody[key])
+}
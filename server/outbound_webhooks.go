@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/server/webhooks"
+)
+
+// WebhookSender sends test events to outbound webhooks and remembers their
+// recent deliveries.
+type WebhookSender interface {
+	SendTest(ctx context.Context, hook projectors.WebhookListEntry) error
+	Deliveries(webhookID string) []webhooks.Delivery
+}
+
+// WithWebhookSender enables the test-webhook and webhook-deliveries
+// endpoints.
+func WithWebhookSender(s WebhookSender) HandlersOption {
+	return func(h *Handlers) {
+		h.webhookSender = s
+	}
+}
+
+// WebhookSummary describes a realm's webhook without its secret, which is
+// only shown when the webhook is registered.
+type WebhookSummary struct {
+	ID           string             `json:"id"`
+	URL          string             `json:"url"`
+	Events       []string           `json:"events,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	LastDelivery *webhooks.Delivery `json:"last_delivery,omitempty"`
+}
+
+type webhookRequest struct {
+	WebhookID string `json:"webhook_id"`
+}
+
+func (h *Handlers) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.RegisterWebhook
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	result, err := domain.HandleRegisterWebhook(r.Context(), cmd, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (h *Handlers) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := h.realmWebhook(w, r)
+	if !ok {
+		return
+	}
+	if err := domain.HandleRemoveWebhook(r.Context(), domain.RemoveWebhook{WebhookID: hook.ID}, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhooks returns the realm's webhooks, oldest first, each with the
+// outcome of its latest delivery when one is known.
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "webhook_list")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list webhooks")
+		return
+	}
+	hooks := []WebhookSummary{}
+	for _, raw := range raws {
+		var entry projectors.WebhookListEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.ID == "" {
+			continue
+		}
+		summary := WebhookSummary{ID: entry.ID, URL: entry.URL, Events: entry.Events, CreatedAt: entry.CreatedAt}
+		if h.webhookSender != nil {
+			if deliveries := h.webhookSender.Deliveries(entry.ID); len(deliveries) > 0 {
+				summary.LastDelivery = &deliveries[0]
+			}
+		}
+		hooks = append(hooks, summary)
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		if !hooks[i].CreatedAt.Equal(hooks[j].CreatedAt) {
+			return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+		}
+		return hooks[i].ID < hooks[j].ID
+	})
+	writeJSON(w, http.StatusOK, hooks)
+}
+
+// WebhookDeliveries returns a webhook's recent deliveries, newest first.
+func (h *Handlers) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if h.webhookSender == nil {
+		writeError(w, http.StatusNotImplemented, "webhook deliveries are not enabled")
+		return
+	}
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	webhookID := r.URL.Query().Get("webhook_id")
+	if webhookID == "" {
+		writeError(w, http.StatusBadRequest, "webhook_id is required")
+		return
+	}
+	if _, err := projectors.GetWebhook(r.Context(), h.projectionStore, realmID, webhookID); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	deliveries := h.webhookSender.Deliveries(webhookID)
+	if deliveries == nil {
+		deliveries = []webhooks.Delivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// TestWebhook delivers a WebhookTest event to one of the realm's webhooks
+// and answers with the recorded delivery. A receiver that fails or rejects
+// the event is reported as a bad gateway.
+func (h *Handlers) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.webhookSender == nil {
+		writeError(w, http.StatusNotImplemented, "webhook deliveries are not enabled")
+		return
+	}
+	hook, ok := h.realmWebhook(w, r)
+	if !ok {
+		return
+	}
+	err := h.webhookSender.SendTest(r.Context(), hook)
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusBadGateway
+	}
+	deliveries := h.webhookSender.Deliveries(hook.ID)
+	if len(deliveries) == 0 {
+		writeError(w, http.StatusInternalServerError, "test delivery was not recorded")
+		return
+	}
+	writeJSON(w, status, deliveries[0])
+}
+
+// realmWebhook decodes the webhook named in the request body and looks it up
+// in the request realm, so one realm cannot act on another's webhooks.
+func (h *Handlers) realmWebhook(w http.ResponseWriter, r *http.Request) (projectors.WebhookListEntry, bool) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return projectors.WebhookListEntry{}, false
+	}
+	var req webhookRequest
+	if !decodeJSONBody(w, r, &req) {
+		return projectors.WebhookListEntry{}, false
+	}
+	if req.WebhookID == "" {
+		writeError(w, http.StatusBadRequest, "webhook_id is required")
+		return projectors.WebhookListEntry{}, false
+	}
+	hook, err := projectors.GetWebhook(r.Context(), h.projectionStore, realmID, req.WebhookID)
+	if err != nil {
+		handleDomainError(w, err)
+		return projectors.WebhookListEntry{}, false
+	}
+	return hook, true
+}
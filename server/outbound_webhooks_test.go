@@ -0,0 +1,285 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/server/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRegisterWebhookHandler(t *testing.T) {
+	t.Run("registers the webhook for the request realm and returns its secret", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/register-webhook", map[string]any{
+			"url":    "https://example.com/hook",
+			"events": []string{domain.EventRuneCreated},
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		var result domain.RegisterWebhookResult
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &result))
+		assert.NotEmpty(t, result.WebhookID)
+		assert.NotEmpty(t, result.Secret)
+		tc.webhook_event_was_appended(domain.EventWebhookRegistered)
+	})
+
+	t.Run("returns 400 for a URL that is not http or https", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/register-webhook", map[string]any{"url": "ftp://example.com/hook"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("url must be an absolute http or https URL")
+	})
+}
+
+func TestRemoveWebhookHandler(t *testing.T) {
+	t.Run("removes the realm's webhook", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-webhook", map[string]string{"webhook_id": "wh-1"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+		tc.webhook_event_was_appended(domain.EventWebhookRemoved)
+	})
+
+	t.Run("returns 404 for another realm's webhook", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.registered_webhook("realm-2", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/remove-webhook", map[string]string{"webhook_id": "wh-1"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestListWebhooksHandler(t *testing.T) {
+	t.Run("lists the realm's webhooks without secrets", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.registered_webhook("realm-2", "wh-2")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/webhooks")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var hooks []WebhookSummary
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &hooks))
+		require.Len(t, hooks, 1)
+		assert.Equal(t, "wh-1", hooks[0].ID)
+		assert.NotContains(t, tc.recorder.Body.String(), "whsec_")
+	})
+
+	t.Run("includes each webhook's latest delivery", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_webhook_sender(nil)
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.webhook_delivery("wh-1", "realm-1-7", http.StatusNoContent)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/webhooks")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"last_delivery":{"id":"realm-1-7"`)
+	})
+}
+
+func TestWebhookDeliveriesHandler(t *testing.T) {
+	t.Run("returns the webhook's recent deliveries", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_webhook_sender(nil)
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.webhook_delivery("wh-1", "realm-1-7", http.StatusNoContent)
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/webhook-deliveries?webhook_id=wh-1")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var deliveries []webhooks.Delivery
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &deliveries))
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, http.StatusNoContent, deliveries[0].StatusCode)
+	})
+
+	t.Run("returns 404 for another realm's webhook", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_webhook_sender(nil)
+		tc.registered_webhook("realm-2", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/webhook-deliveries?webhook_id=wh-1")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestTestWebhookHandler(t *testing.T) {
+	t.Run("sends a test event and returns the delivery", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_webhook_sender(nil)
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-webhook", map[string]string{"webhook_id": "wh-1"})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"event_type":"WebhookTest"`)
+		assert.Equal(t, []string{"wh-1"}, tc.webhookSender.tested)
+	})
+
+	t.Run("returns 502 when the receiver rejects the event", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_webhook_sender(fmt.Errorf("unexpected status 401: bad signature"))
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-webhook", map[string]string{"webhook_id": "wh-1"})
+
+		// Then
+		tc.status_is(http.StatusBadGateway)
+		tc.response_body_contains("bad signature")
+	})
+
+	t.Run("returns 501 when deliveries are not enabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.registered_webhook("realm-1", "wh-1")
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/test-webhook", map[string]string{"webhook_id": "wh-1"})
+
+		// Then
+		tc.status_is(http.StatusNotImplemented)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_webhook_sender(sendErr error) {
+	tc.t.Helper()
+	tc.webhookSender = &mockWebhookSender{err: sendErr, deliveries: make(map[string][]webhooks.Delivery)}
+}
+
+// registered_webhook records the webhook's registration event and its entry
+// in the webhook list, as the projector would.
+func (tc *handlerTestContext) registered_webhook(realmID, webhookID string) {
+	tc.t.Helper()
+	tc.eventStore.appendToStream(domain.AdminRealmID, "webhook-"+webhookID, domain.EventWebhookRegistered, domain.WebhookRegistered{
+		WebhookID: webhookID, RealmID: realmID, URL: "https://example.com/hook", Secret: "whsec_abc",
+	})
+	entry := projectors.WebhookListEntry{
+		ID: webhookID, RealmID: realmID, URL: "https://example.com/hook", Secret: "whsec_abc", CreatedAt: time.Now(),
+	}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "webhook_list", webhookID, entry)
+}
+
+func (tc *handlerTestContext) webhook_delivery(webhookID, deliveryID string, status int) {
+	tc.t.Helper()
+	tc.webhookSender.deliveries[webhookID] = append(tc.webhookSender.deliveries[webhookID], webhooks.Delivery{
+		ID: deliveryID, WebhookID: webhookID, EventType: domain.EventRuneCreated, StatusCode: status,
+	})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) webhook_event_was_appended(eventType string) {
+	tc.t.Helper()
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType == eventType {
+				return
+			}
+		}
+	}
+	assert.Failf(tc.t, "event not appended", "no %s event was appended", eventType)
+}
+
+// --- Mock Webhook Sender ---
+
+type mockWebhookSender struct {
+	err        error
+	tested     []string
+	deliveries map[string][]webhooks.Delivery
+}
+
+func (m *mockWebhookSender) SendTest(_ context.Context, hook projectors.WebhookListEntry) error {
+	m.tested = append(m.tested, hook.ID)
+	delivery := webhooks.Delivery{ID: hook.ID + "-test", WebhookID: hook.ID, EventType: webhooks.TestEventType}
+	if m.err != nil {
+		delivery.Error = m.err.Error()
+	}
+	m.deliveries[hook.ID] = append([]webhooks.Delivery{delivery}, m.deliveries[hook.ID]...)
+	return m.err
+}
+
+func (m *mockWebhookSender) Deliveries(webhookID string) []webhooks.Delivery {
+	return m.deliveries[webhookID]
+}
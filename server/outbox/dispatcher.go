@@ -0,0 +1,165 @@
+// Package outbox publishes appended events to external systems, such as a
+// webhook endpoint or a NATS subject, from the event store's transactional
+// outbox.
+//
+// Every event is recorded in the outbox in the transaction that appends it,
+// so an event that was committed is published even if the process stops
+// right after. The Dispatcher drains the outbox in the background, retrying
+// failed publishes with a growing delay until they succeed. Publishing is at
+// least once: an event may reach a sink twice, for instance when a second
+// sink failed, and receivers should ignore repeats by realm and global
+// position. Events of the admin realm are not published.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// Sink publishes events outside Bifrost.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event core.Event) error
+}
+
+// Dispatcher publishes the events recorded in an outbox to its sinks.
+type Dispatcher struct {
+	outbox     core.Outbox
+	events     core.EventStore
+	sinks      []Sink
+	batchSize  int
+	maxBackoff time.Duration
+	now        func() time.Time
+}
+
+type DispatcherOption func(*Dispatcher)
+
+// WithBatchSize sets how many entries one Dispatch publishes at most.
+func WithBatchSize(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.batchSize = n
+	}
+}
+
+// WithMaxBackoff caps the delay before retrying a failed publish.
+func WithMaxBackoff(max time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxBackoff = max
+	}
+}
+
+// NewDispatcher publishes the entries of outbox to sinks, reading their
+// events from events.
+func NewDispatcher(outbox core.Outbox, events core.EventStore, sinks []Sink, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		outbox:     outbox,
+		events:     events,
+		sinks:      sinks,
+		batchSize:  100,
+		maxBackoff: 10 * time.Minute,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run publishes due entries every interval until ctx is cancelled. A full
+// batch is followed by the next one straight away.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := d.Dispatch(ctx)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+		}
+		if err == nil && n == d.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch publishes a batch of due entries and returns how many it
+// handled. An entry that fails to publish is put back with its attempt
+// recorded rather than returned as an error.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	entries, err := d.outbox.Due(ctx, d.now(), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if err := d.publish(ctx, entry); err != nil {
+			next := d.now().Add(d.backoff(entry.Attempts))
+			log.Printf("outbox: publish %s event %d (attempt %d): %v", entry.RealmID, entry.GlobalPosition, entry.Attempts+1, err)
+			if err := d.outbox.Retry(ctx, entry.ID, next, err.Error()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := d.outbox.Delivered(ctx, entry.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// publish hands the entry's event to every sink, trying them all even when
+// one fails.
+func (d *Dispatcher) publish(ctx context.Context, entry core.OutboxEntry) error {
+	if entry.RealmID == domain.AdminRealmID {
+		return nil
+	}
+	page, err := core.ReadPage(ctx, d.events, entry.RealmID, entry.GlobalPosition-1, 1)
+	if err != nil {
+		return err
+	}
+	if len(page.Events) == 0 || page.Events[0].GlobalPosition != entry.GlobalPosition {
+		// Archived or purged before it was published; there is nothing
+		// left to send.
+		log.Printf("outbox: %s event %d is no longer in the feed, skipping it", entry.RealmID, entry.GlobalPosition)
+		return nil
+	}
+	event := page.Events[0]
+	var failed []string
+	var errs []error
+	for _, sink := range d.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			failed = append(failed, sink.Name())
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("sinks %s failed: %w", strings.Join(failed, ", "), errors.Join(errs...))
+	}
+	return nil
+}
+
+// backoff is the delay after a publish that failed for the attempts+1st
+// time: a second, doubling with each failure up to maxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := time.Second
+	for range attempts {
+		delay *= 2
+		if delay >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return min(delay, d.maxBackoff)
+}
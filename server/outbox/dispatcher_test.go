@@ -0,0 +1,316 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/providers/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Compile-time interface satisfaction checks
+var (
+	_ Sink        = (*WebhookSink)(nil)
+	_ Sink        = (*NATSSink)(nil)
+	_ Sink        = (*mockSink)(nil)
+	_ core.Outbox = (*mockOutbox)(nil)
+)
+
+// --- Tests ---
+
+func TestDispatcher(t *testing.T) {
+	t.Run("publishes due events to every sink and removes them", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.an_appended_event("realm-1", domain.EventRuneCreated)
+		tc.an_appended_event("realm-1", domain.EventRuneClaimed)
+
+		// When
+		tc.dispatch()
+
+		// Then
+		tc.handled(2)
+		tc.sink_received(tc.webhook, "realm-1/1", "realm-1/2")
+		tc.sink_received(tc.nats, "realm-1/1", "realm-1/2")
+		tc.pending_is(0)
+	})
+
+	t.Run("retries a failed publish with a growing delay", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.an_appended_event("realm-1", domain.EventRuneCreated)
+		tc.nats.fails_with(errors.New("connection refused"))
+
+		// When
+		tc.dispatch()
+
+		// Then
+		tc.pending_is(1)
+		entry := tc.outbox.entries[0]
+		assert.Equal(t, 1, entry.Attempts)
+		assert.Contains(t, entry.LastError, "nats: connection refused")
+		assert.Equal(t, tc.now.Add(time.Second), tc.outbox.next[entry.ID])
+
+		// When the retry falls due and fails again
+		tc.time_passes(time.Second)
+		tc.dispatch()
+
+		// Then
+		assert.Equal(t, tc.now.Add(2*time.Second), tc.outbox.next[entry.ID])
+	})
+
+	t.Run("delivers a retried event once its sinks recover", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.an_appended_event("realm-1", domain.EventRuneCreated)
+		tc.webhook.fails_with(errors.New("503"))
+		tc.dispatch()
+
+		// When
+		tc.webhook.fails_with(nil)
+		tc.time_passes(time.Second)
+		tc.dispatch()
+
+		// Then
+		tc.pending_is(0)
+		tc.sink_received(tc.webhook, "realm-1/1")
+	})
+
+	t.Run("does not retry before the entry is due", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.an_appended_event("realm-1", domain.EventRuneCreated)
+		tc.webhook.fails_with(errors.New("503"))
+		tc.dispatch()
+
+		// When
+		tc.dispatch()
+
+		// Then
+		tc.handled(0)
+		assert.Equal(t, 1, tc.outbox.entries[0].Attempts)
+	})
+
+	t.Run("caps the delay at the maximum backoff", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Then
+		assert.Equal(t, time.Second, tc.dispatcher.backoff(0))
+		assert.Equal(t, 8*time.Second, tc.dispatcher.backoff(3))
+		assert.Equal(t, 10*time.Minute, tc.dispatcher.backoff(20))
+		assert.Equal(t, 10*time.Minute, tc.dispatcher.backoff(1000))
+	})
+
+	t.Run("does not publish admin realm events", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.an_appended_event(domain.AdminRealmID, domain.EventRealmCreated)
+
+		// When
+		tc.dispatch()
+
+		// Then
+		tc.handled(1)
+		tc.sink_received(tc.webhook)
+		tc.pending_is(0)
+	})
+
+	t.Run("stops when the outbox cannot be read", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.outbox.dueErr = errors.New("database is locked")
+
+		// When
+		tc.dispatch()
+
+		// Then
+		assert.EqualError(t, tc.err, "database is locked")
+	})
+}
+
+// --- Test Context ---
+
+type dispatcherTestContext struct {
+	t *testing.T
+
+	events     *memory.EventStore
+	outbox     *mockOutbox
+	webhook    *mockSink
+	nats       *mockSink
+	dispatcher *Dispatcher
+	now        time.Time
+	versions   map[string]int
+
+	n   int
+	err error
+}
+
+func newDispatcherTestContext(t *testing.T) *dispatcherTestContext {
+	t.Helper()
+	tc := &dispatcherTestContext{
+		t:        t,
+		events:   memory.NewEventStore(),
+		outbox:   newMockOutbox(),
+		webhook:  &mockSink{name: "webhook"},
+		nats:     &mockSink{name: "nats"},
+		now:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		versions: make(map[string]int),
+	}
+	tc.dispatcher = NewDispatcher(tc.outbox, tc.events, []Sink{tc.webhook, tc.nats})
+	tc.dispatcher.now = func() time.Time { return tc.now }
+	return tc
+}
+
+// --- Given ---
+
+// an_appended_event appends an event and records it in the outbox, as a
+// store made with an outbox does.
+func (tc *dispatcherTestContext) an_appended_event(realmID, eventType string) {
+	tc.t.Helper()
+	version := tc.versions[realmID]
+	events, err := tc.events.Append(context.Background(), realmID, "stream-1", version, []core.EventData{
+		{EventType: eventType, Data: map[string]int{"n": version}},
+	})
+	require.NoError(tc.t, err)
+	tc.versions[realmID]++
+	tc.outbox.record(events[0], tc.now)
+}
+
+func (tc *dispatcherTestContext) time_passes(d time.Duration) {
+	tc.now = tc.now.Add(d)
+}
+
+func (s *mockSink) fails_with(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// --- When ---
+
+func (tc *dispatcherTestContext) dispatch() {
+	tc.n, tc.err = tc.dispatcher.Dispatch(context.Background())
+}
+
+// --- Then ---
+
+func (tc *dispatcherTestContext) handled(expected int) {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+	assert.Equal(tc.t, expected, tc.n)
+}
+
+func (tc *dispatcherTestContext) sink_received(sink *mockSink, expected ...string) {
+	tc.t.Helper()
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Equal(tc.t, expected, sink.received)
+}
+
+func (tc *dispatcherTestContext) pending_is(expected int) {
+	tc.t.Helper()
+	n, err := tc.outbox.Pending(context.Background())
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, n)
+}
+
+// --- Mock Outbox ---
+
+type mockOutbox struct {
+	entries []core.OutboxEntry
+	next    map[int64]time.Time
+	lastID  int64
+	dueErr  error
+}
+
+func newMockOutbox() *mockOutbox {
+	return &mockOutbox{next: make(map[int64]time.Time)}
+}
+
+func (o *mockOutbox) record(event core.Event, now time.Time) {
+	o.lastID++
+	o.entries = append(o.entries, core.OutboxEntry{
+		ID:             o.lastID,
+		RealmID:        event.RealmID,
+		GlobalPosition: event.GlobalPosition,
+		CreatedAt:      now,
+	})
+	o.next[o.lastID] = now
+}
+
+func (o *mockOutbox) Due(_ context.Context, now time.Time, limit int) ([]core.OutboxEntry, error) {
+	if o.dueErr != nil {
+		return nil, o.dueErr
+	}
+	var due []core.OutboxEntry
+	for _, entry := range o.entries {
+		if !o.next[entry.ID].After(now) && len(due) < limit {
+			due = append(due, entry)
+		}
+	}
+	return due, nil
+}
+
+func (o *mockOutbox) Delivered(_ context.Context, id int64) error {
+	for i, entry := range o.entries {
+		if entry.ID == id {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			delete(o.next, id)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (o *mockOutbox) Retry(_ context.Context, id int64, next time.Time, lastErr string) error {
+	for i := range o.entries {
+		if o.entries[i].ID == id {
+			o.entries[i].Attempts++
+			o.entries[i].LastError = lastErr
+			o.next[id] = next
+			return nil
+		}
+	}
+	return &core.NotFoundError{Entity: "outbox entry"}
+}
+
+func (o *mockOutbox) Pending(context.Context) (int, error) {
+	return len(o.entries), nil
+}
+
+// --- Mock Sink ---
+
+type mockSink struct {
+	name string
+
+	mu       sync.Mutex
+	err      error
+	received []string
+}
+
+func (s *mockSink) Name() string {
+	return s.name
+}
+
+func (s *mockSink) Publish(_ context.Context, event core.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.received = append(s.received, event.RealmID+"/"+strconv.FormatInt(event.GlobalPosition, 10))
+	return nil
+}
@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/nats-io/nats.go"
+)
+
+// natsTimeout bounds connecting, and a publish when ctx has no earlier
+// deadline.
+const natsTimeout = 10 * time.Second
+
+// NATSSink publishes each event to the subject
+// "<prefix>.<realm>.<event type>" of a NATS server. The connection always
+// uses TLS, so credentials in the URL never cross the network in the clear,
+// and a server that does not offer TLS is refused. Every publish is
+// confirmed with a flush, so an event counts as published once the server
+// has it. The connection reconnects on its own after it drops; publishes
+// fail while it is down instead of being buffered, so the outbox retries
+// them.
+type NATSSink struct {
+	url       string
+	prefix    string
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// NewNATSSink publishes to the server at rawURL, nats://[user:pass@]host[:port]
+// or tls://, under the subject prefix. The server's certificate is checked
+// against the system roots, or against the PEM certificates in caFile when
+// it is set.
+func NewNATSSink(rawURL, prefix, caFile string) (*NATSSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Hostname() == "" {
+		return nil, fmt.Errorf("NATS URL must look like nats://host:4222 or tls://host:4222, got %q", rawURL)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read NATS CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("NATS CA file %s holds no PEM certificates", caFile)
+		}
+	}
+	return &NATSSink{url: rawURL, prefix: prefix, tlsConfig: tlsConfig}, nil
+}
+
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+func (s *NATSSink) Publish(ctx context.Context, event core.Event) error {
+	body, err := payload(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, err := s.connection()
+	if err != nil {
+		return err
+	}
+
+	// The server reports a refused publish, such as a permissions
+	// violation, before it answers the flush.
+	before := conn.LastError()
+	if err := conn.Publish(s.subject(event), body); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, natsTimeout)
+		defer cancel()
+	}
+	err = conn.FlushWithContext(ctx)
+	if last := conn.LastError(); last != nil && last != before {
+		return last
+	}
+	return err
+}
+
+// Close closes the connection, if one is open.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return nil
+}
+
+// connection returns the open connection, connecting when there is none or
+// the server closed the last one.
+func (s *NATSSink) connection() (*nats.Conn, error) {
+	if s.conn != nil && !s.conn.IsClosed() {
+		return s.conn, nil
+	}
+	conn, err := nats.Connect(s.url,
+		nats.Name("bifrost"),
+		nats.Secure(s.tlsConfig),
+		nats.Timeout(natsTimeout),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1),
+	)
+	if err != nil {
+		if errors.Is(err, nats.ErrSecureConnWanted) {
+			return nil, errors.New("NATS server does not offer TLS, which is required")
+		}
+		return nil, err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// subject builds the event's subject, replacing characters NATS gives a
+// meaning to in the realm and event type.
+func (s *NATSSink) subject(event core.Event) string {
+	clean := strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_", "\t", "_")
+	return s.prefix + "." + clean.Replace(event.RealmID) + "." + clean.Replace(event.EventType)
+}
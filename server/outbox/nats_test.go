@@ -0,0 +1,356 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestNATSSink(t *testing.T) {
+	t.Run("publishes the event to its realm and type subject over TLS", func(t *testing.T) {
+		tc := newNATSSinkTestContext(t)
+
+		// Given
+		tc.a_nats_server(true)
+
+		// When
+		tc.event_is_published()
+
+		// Then
+		require.NoError(t, tc.err)
+		tc.server_received("bifrost.events.realm-1."+domain.EventRuneCreated, `{"id":"bf-a1b2"}`)
+		assert.Equal(t, "alice", tc.server.connect["user"])
+		assert.Equal(t, "pw", tc.server.connect["pass"])
+	})
+
+	t.Run("reuses its connection", func(t *testing.T) {
+		tc := newNATSSinkTestContext(t)
+
+		// Given
+		tc.a_nats_server(true)
+
+		// When
+		tc.event_is_published()
+		tc.event_is_published()
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, 1, tc.server.connections())
+		assert.Len(t, tc.server.messages(), 2)
+	})
+
+	t.Run("refuses a server that does not offer TLS", func(t *testing.T) {
+		tc := newNATSSinkTestContext(t)
+
+		// Given
+		tc.a_nats_server(false)
+
+		// When
+		tc.event_is_published()
+
+		// Then
+		assert.ErrorContains(t, tc.err, "does not offer TLS")
+		assert.Nil(t, tc.server.connect, "credentials were sent")
+	})
+
+	t.Run("returns error and reconnects when the server closes the connection", func(t *testing.T) {
+		tc := newNATSSinkTestContext(t)
+
+		// Given
+		tc.a_nats_server(true)
+		tc.event_is_published()
+		require.NoError(t, tc.err)
+		tc.server.reject("Maximum Payload Violation")
+
+		// When
+		tc.event_is_published()
+
+		// Then
+		assert.ErrorContains(t, tc.err, "Maximum Payload Violation")
+
+		// When the server accepts again
+		tc.server.reject("")
+		tc.event_is_published()
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, 2, tc.server.connections())
+	})
+
+	t.Run("returns error when the server refuses the publish", func(t *testing.T) {
+		tc := newNATSSinkTestContext(t)
+
+		// Given
+		tc.a_nats_server(true)
+		tc.server.deny(true)
+
+		// When
+		tc.event_is_published()
+
+		// Then
+		assert.ErrorContains(t, tc.err, "Permissions Violation")
+
+		// When the server allows it again
+		tc.server.deny(false)
+		tc.event_is_published()
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, 1, tc.server.connections())
+	})
+
+	t.Run("replaces subject tokens in realm IDs", func(t *testing.T) {
+		sink, err := NewNATSSink("nats://localhost", "bifrost", "")
+		require.NoError(t, err)
+
+		// Given
+		event := sinkEvent()
+		event.RealmID = "team.a>*"
+
+		// Then
+		assert.Equal(t, "bifrost.team_a__."+domain.EventRuneCreated, sink.subject(event))
+	})
+
+	t.Run("rejects URLs that are not nats:// or tls://", func(t *testing.T) {
+		_, err := NewNATSSink("http://localhost:4222", "bifrost", "")
+
+		assert.ErrorContains(t, err, "nats://")
+	})
+
+	t.Run("rejects a CA file without certificates", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+		_, err := NewNATSSink("tls://localhost:4222", "bifrost", caFile)
+
+		assert.ErrorContains(t, err, "no PEM certificates")
+	})
+}
+
+// --- Test Context ---
+
+type natsSinkTestContext struct {
+	t *testing.T
+
+	server *fakeNATSServer
+	sink   *NATSSink
+	err    error
+}
+
+func newNATSSinkTestContext(t *testing.T) *natsSinkTestContext {
+	t.Helper()
+	return &natsSinkTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *natsSinkTestContext) a_nats_server(offerTLS bool) {
+	tc.t.Helper()
+	cert, caFile := natsTestCertificate(tc.t)
+	var tlsConfig *tls.Config
+	if offerTLS {
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+	tc.server = newFakeNATSServer(tc.t, tlsConfig)
+	sink, err := NewNATSSink("nats://alice:pw@"+tc.server.addr(), "bifrost.events", caFile)
+	require.NoError(tc.t, err)
+	tc.t.Cleanup(func() { sink.Close() })
+	tc.sink = sink
+}
+
+// natsTestCertificate returns a self-signed certificate for 127.0.0.1 and
+// the path of a CA file trusting it.
+func natsTestCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+// --- When ---
+
+func (tc *natsSinkTestContext) event_is_published() {
+	tc.err = tc.sink.Publish(context.Background(), sinkEvent())
+}
+
+// --- Then ---
+
+func (tc *natsSinkTestContext) server_received(subject, data string) {
+	tc.t.Helper()
+	msgs := tc.server.messages()
+	require.Len(tc.t, msgs, 1)
+	assert.Equal(tc.t, subject, msgs[0].subject)
+	var payload map[string]any
+	require.NoError(tc.t, json.Unmarshal([]byte(msgs[0].payload), &payload))
+	assert.Equal(tc.t, "realm-1-7", payload["id"])
+	got, _ := json.Marshal(payload["data"])
+	assert.JSONEq(tc.t, data, string(got))
+}
+
+// --- Fake NATS Server ---
+
+type natsMessage struct {
+	subject string
+	payload string
+}
+
+// fakeNATSServer speaks enough of the NATS protocol to accept publishes:
+// it sends INFO, upgrades the connection to TLS when it has a certificate,
+// reads CONNECT, PUB and PING, and answers PING with PONG. While rejecting
+// it answers PING with -ERR and hangs up; while denying it answers each PUB
+// with a permissions violation.
+type fakeNATSServer struct {
+	ln        net.Listener
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	connect map[string]any
+	msgs    []natsMessage
+	conns   int
+	errMsg  string
+	denied  bool
+}
+
+func newFakeNATSServer(t *testing.T, tlsConfig *tls.Config) *fakeNATSServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNATSServer{ln: ln, tlsConfig: tlsConfig}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeNATSServer) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *fakeNATSServer) reject(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errMsg = msg
+}
+
+func (s *fakeNATSServer) deny(denied bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.denied = denied
+}
+
+func (s *fakeNATSServer) messages() []natsMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]natsMessage(nil), s.msgs...)
+}
+
+func (s *fakeNATSServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns
+}
+
+func (s *fakeNATSServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeNATSServer) handle(conn net.Conn) {
+	defer conn.Close()
+	if s.tlsConfig == nil {
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+	} else {
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576,\"tls_required\":true}\r\n")
+		secure := tls.Server(conn, s.tlsConfig)
+		if err := secure.Handshake(); err != nil {
+			return
+		}
+		conn = secure
+	}
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			var connect map[string]any
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &connect)
+			s.mu.Lock()
+			s.connect = connect
+			s.mu.Unlock()
+		case strings.HasPrefix(line, "PUB "):
+			var subject string
+			var size int
+			if _, err := fmt.Sscanf(line, "PUB %s %d", &subject, &size); err != nil {
+				return
+			}
+			body := make([]byte, size+2)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return
+			}
+			s.mu.Lock()
+			denied := s.denied
+			if s.errMsg == "" && !denied {
+				s.msgs = append(s.msgs, natsMessage{subject: subject, payload: string(body[:size])})
+			}
+			s.mu.Unlock()
+			if denied {
+				fmt.Fprintf(conn, "-ERR 'Permissions Violation for Publish to \"%s\"'\r\n", subject)
+			}
+		case line == "PING":
+			s.mu.Lock()
+			errMsg := s.errMsg
+			s.mu.Unlock()
+			if errMsg != "" {
+				fmt.Fprintf(conn, "-ERR '%s'\r\n", errMsg)
+				return
+			}
+			fmt.Fprint(conn, "PONG\r\n")
+		}
+	}
+}
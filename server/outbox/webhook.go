@@ -0,0 +1,80 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/server/webhooks"
+)
+
+// WebhookSink posts each event to a URL in the format and with the
+// signature headers of realm webhooks (see package webhooks). The delivery
+// ID is "<realm>-<global position>", the same for every attempt.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookSink posts to url, signing with secret.
+func NewWebhookSink(client *http.Client, url, secret string) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret, client: client, now: time.Now}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event core.Event) error {
+	body, err := payload(event)
+	if err != nil {
+		return err
+	}
+	timestamp := s.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.EventHeader, event.EventType)
+	req.Header.Set(webhooks.DeliveryHeader, deliveryID(event))
+	req.Header.Set(webhooks.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(s.secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+func deliveryID(event core.Event) string {
+	return fmt.Sprintf("%s-%d", event.RealmID, event.GlobalPosition)
+}
+
+// payload is the JSON every sink publishes for event.
+func payload(event core.Event) ([]byte, error) {
+	return json.Marshal(webhooks.Payload{
+		ID:        deliveryID(event),
+		Type:      event.EventType,
+		RealmID:   event.RealmID,
+		StreamID:  event.StreamID,
+		Version:   event.Version,
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	})
+}
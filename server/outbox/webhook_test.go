@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/server/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("posts the event signed with the secret", func(t *testing.T) {
+		tc := newWebhookSinkTestContext(t)
+
+		// When
+		tc.event_is_published(sinkEvent())
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, domain.EventRuneCreated, tc.header.Get(webhooks.EventHeader))
+		assert.Equal(t, "realm-1-7", tc.header.Get(webhooks.DeliveryHeader))
+		assert.NoError(t, webhooks.Verify("s3cret", tc.header.Get(webhooks.SignatureHeader), tc.header.Get(webhooks.TimestampHeader), tc.body, webhooks.DefaultTolerance, time.Now()))
+
+		var payload webhooks.Payload
+		require.NoError(t, json.Unmarshal(tc.body, &payload))
+		assert.Equal(t, "realm-1-7", payload.ID)
+		assert.Equal(t, "rune-bf-a1b2", payload.StreamID)
+		assert.JSONEq(t, `{"id":"bf-a1b2"}`, string(payload.Data))
+	})
+
+	t.Run("returns error when the endpoint rejects the event", func(t *testing.T) {
+		tc := newWebhookSinkTestContext(t)
+
+		// Given
+		tc.status = http.StatusServiceUnavailable
+
+		// When
+		tc.event_is_published(sinkEvent())
+
+		// Then
+		assert.ErrorContains(t, tc.err, "503")
+	})
+}
+
+// --- Test Context ---
+
+type webhookSinkTestContext struct {
+	t *testing.T
+
+	sink   *WebhookSink
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+func newWebhookSinkTestContext(t *testing.T) *webhookSinkTestContext {
+	t.Helper()
+	tc := &webhookSinkTestContext{t: t, status: http.StatusOK}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.header = r.Header.Clone()
+		tc.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(tc.status)
+	}))
+	t.Cleanup(srv.Close)
+	tc.sink = NewWebhookSink(srv.Client(), srv.URL, "s3cret")
+	return tc
+}
+
+// sinkEvent is the event the sink tests publish.
+func sinkEvent() core.Event {
+	return core.Event{
+		RealmID:        "realm-1",
+		StreamID:       "rune-bf-a1b2",
+		Version:        1,
+		GlobalPosition: 7,
+		EventType:      domain.EventRuneCreated,
+		Data:           []byte(`{"id":"bf-a1b2"}`),
+		Timestamp:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// --- When ---
+
+func (tc *webhookSinkTestContext) event_is_published(event core.Event) {
+	tc.err = tc.sink.Publish(context.Background(), event)
+}
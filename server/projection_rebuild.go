@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// WithProjectionRebuilder enables the rebuild-projection endpoint, rebuilding
+// a projection of one realm through r while catch-up carries on elsewhere.
+func WithProjectionRebuilder(r core.ProjectionRebuilder) HandlersOption {
+	return func(h *Handlers) {
+		h.rebuilder = r
+	}
+}
+
+type rebuildProjectionRequest struct {
+	RealmID    string `json:"realm_id"`
+	Projection string `json:"projection"`
+}
+
+// RebuildProjection clears the realm's entries of a projection and projects
+// the realm's events into it again, for recovering a tenant whose read
+// models went wrong. It answers once the rebuild is done.
+func (h *Handlers) RebuildProjection(w http.ResponseWriter, r *http.Request) {
+	if h.rebuilder == nil {
+		writeError(w, http.StatusNotFound, "projection rebuilds are not enabled")
+		return
+	}
+	var req rebuildProjectionRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.RealmID == "" || req.Projection == "" {
+		writeError(w, http.StatusBadRequest, "realm_id and projection are required")
+		return
+	}
+	if err := h.rebuilder.Rebuild(r.Context(), req.Projection, req.RealmID); err != nil {
+		if errors.Is(err, core.ErrNotRebuildable) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestRebuildProjectionHandler(t *testing.T) {
+	t.Run("rebuilds the projection in the realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_projection_rebuilder(nil)
+		tc.handlers_configured()
+
+		// When
+		tc.post("/rebuild-projection", map[string]string{"realm_id": "realm-1", "projection": "rune_list"})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"realm_id":"realm-1","projection":"rune_list"}`)
+		tc.projections_rebuilt("realm-1/rune_list")
+	})
+
+	t.Run("returns 400 without a realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_projection_rebuilder(nil)
+		tc.handlers_configured()
+
+		// When
+		tc.post("/rebuild-projection", map[string]string{"projection": "rune_list"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.projections_rebuilt()
+	})
+
+	t.Run("returns 400 for a projection that cannot be rebuilt", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.a_projection_rebuilder(fmt.Errorf("%w: \"schedules\" writes into other realms", core.ErrNotRebuildable))
+		tc.handlers_configured()
+
+		// When
+		tc.post("/rebuild-projection", map[string]string{"realm_id": "realm-1", "projection": "schedules"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("writes into other realms")
+	})
+
+	t.Run("returns 404 when rebuilds are not enabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/rebuild-projection", map[string]string{"realm_id": "realm-1", "projection": "rune_list"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+// --- Mock Projection Rebuilder ---
+
+type mockProjectionRebuilder struct {
+	err      error
+	rebuilds []string
+}
+
+func (m *mockProjectionRebuilder) Rebuild(_ context.Context, projectionName string, realmID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.rebuilds = append(m.rebuilds, realmID+"/"+projectionName)
+	return nil
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_projection_rebuilder(err error) {
+	tc.t.Helper()
+	tc.rebuilder = &mockProjectionRebuilder{err: err}
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) projections_rebuilt(expected ...string) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.rebuilder.rebuilds)
+}
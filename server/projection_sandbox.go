@@ -0,0 +1,129 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/sqlite"
+)
+
+// The projection sandbox replays the event history through the server's
+// projectors into a throwaway SQLite database and compares the result with
+// the live read models, without touching them. It is the HTTP counterpart
+// of `bf admin replay-projections`.
+
+// WithReplayProjectors enables the replay-projections endpoint, replaying
+// through projectors.
+func WithReplayProjectors(projectors []core.Projector) HandlersOption {
+	return func(h *Handlers) {
+		h.replay = &projectionSandbox{projectors: projectors}
+	}
+}
+
+type projectionSandbox struct {
+	projectors []core.Projector
+	// running allows one replay at a time, since each reads the whole
+	// history.
+	running sync.Mutex
+}
+
+type replayProjectionsRequest struct {
+	RealmIDs   []string `json:"realm_ids"`
+	Projectors []string `json:"projectors"`
+}
+
+type replayProjectorInfo struct {
+	Name    string `json:"name"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// ListReplayProjectors returns the projectors a replay can run.
+func (h *Handlers) ListReplayProjectors(w http.ResponseWriter, r *http.Request) {
+	if h.replay == nil {
+		writeError(w, http.StatusNotFound, "projection replay is not enabled")
+		return
+	}
+	infos := make([]replayProjectorInfo, 0, len(h.replay.projectors))
+	for _, p := range h.replay.projectors {
+		s, ok := p.(core.SideEffectProjector)
+		infos = append(infos, replayProjectorInfo{Name: p.Name(), Skipped: ok && s.HasSideEffects()})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// ReplayProjections replays the history of the requested realms (all when
+// none are given) through the requested projectors (all by default) into a
+// sandbox, returning a core.ReplayReport compared with the live store.
+func (h *Handlers) ReplayProjections(w http.ResponseWriter, r *http.Request) {
+	if h.replay == nil {
+		writeError(w, http.StatusNotFound, "projection replay is not enabled")
+		return
+	}
+	var req replayProjectionsRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	projectors := h.replay.projectors
+	if len(req.Projectors) > 0 {
+		projectors = nil
+		for _, name := range req.Projectors {
+			i := slices.IndexFunc(h.replay.projectors, func(p core.Projector) bool { return p.Name() == name })
+			if i < 0 {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown projector %q", name))
+				return
+			}
+			projectors = append(projectors, h.replay.projectors[i])
+		}
+	}
+
+	if !h.replay.running.TryLock() {
+		writeError(w, http.StatusConflict, "a replay is already running")
+		return
+	}
+	defer h.replay.running.Unlock()
+
+	sandbox, cleanup, err := openSandboxStore()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer cleanup()
+
+	report, err := core.Replay(r.Context(), h.eventStore, sandbox, projectors,
+		core.ReplayRealms(req.RealmIDs...), core.ReplayCompareWith(h.projectionStore))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// openSandboxStore creates a projection store in a temporary SQLite
+// database, removed by cleanup.
+func openSandboxStore() (core.ProjectionStore, func(), error) {
+	f, err := os.CreateTemp("", "bifrost-sandbox-*.db")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create sandbox: %w", err)
+	}
+	f.Close()
+	db, err := sql.Open("sqlite", f.Name())
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, nil, fmt.Errorf("open sandbox: %w", err)
+	}
+	cleanup := func() {
+		db.Close()
+		os.Remove(f.Name())
+	}
+	store, err := sqlite.NewProjectionStore(db)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("create sandbox: %w", err)
+	}
+	return store, cleanup, nil
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestReplayProjectionsHandler(t *testing.T) {
+	t.Run("returns 404 when replays are not enabled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.post("/replay-projections", map[string]any{})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("replays history into a sandbox and compares it with the live store", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.history_has_realm("bf-1234", "Team A")
+		tc.replay_configured(projectors.NewRealmListProjector())
+
+		// When
+		tc.post("/replay-projections", map[string]any{})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		report := tc.replay_report()
+		assert.Equal(t, 1, report.Events)
+		require.Len(t, report.Diffs, 1)
+		assert.Equal(t, "realm_list", report.Diffs[0].ProjectionName)
+		assert.Equal(t, 1, report.Diffs[0].Added)
+		assert.Empty(t, tc.projectionStore.data, "live projections must not be written")
+	})
+
+	t.Run("returns 400 for an unknown projector", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.replay_configured(projectors.NewRealmListProjector())
+
+		// When
+		tc.post("/replay-projections", map[string]any{"projectors": []string{"nope"}})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains(`unknown projector \"nope\"`)
+	})
+
+	t.Run("returns 409 while another replay is running", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.replay_configured(projectors.NewRealmListProjector())
+		tc.handlers.replay.running.Lock()
+
+		// When
+		tc.post("/replay-projections", map[string]any{})
+
+		// Then
+		tc.status_is(http.StatusConflict)
+	})
+}
+
+func TestListReplayProjectorsHandler(t *testing.T) {
+	t.Run("lists the projectors and which are skipped", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.replay_configured(projectors.NewRealmListProjector(), &sideEffectsProjector{})
+
+		// When
+		tc.get("/replay-projectors")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`[{"name":"realm_list"},{"name":"notifier","skipped":true}]`)
+	})
+}
+
+// --- Test Doubles ---
+
+// historyEventStore serves the mock event store's events as realm history.
+type historyEventStore struct {
+	*mockEventStore
+	events []core.Event
+}
+
+func (s *historyEventStore) ReadAll(_ context.Context, realmID string, from int64) ([]core.Event, error) {
+	var events []core.Event
+	for _, evt := range s.events {
+		if evt.RealmID == realmID && evt.GlobalPosition > from {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (s *historyEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	var ids []string
+	for _, evt := range s.events {
+		if !slices.Contains(ids, evt.RealmID) {
+			ids = append(ids, evt.RealmID)
+		}
+	}
+	return ids, nil
+}
+
+type sideEffectsProjector struct{}
+
+func (p *sideEffectsProjector) Name() string { return "notifier" }
+
+func (p *sideEffectsProjector) Handle(_ context.Context, _ core.Event, _ core.ProjectionStore) error {
+	return nil
+}
+
+func (p *sideEffectsProjector) HasSideEffects() bool { return true }
+
+// --- Given ---
+
+func (tc *handlerTestContext) history_has_realm(realmID, name string) {
+	tc.t.Helper()
+	data, _ := json.Marshal(domain.RealmCreated{RealmID: realmID, Name: name})
+	tc.history = append(tc.history, core.Event{
+		RealmID:        "_admin",
+		StreamID:       "realm-" + realmID,
+		GlobalPosition: int64(len(tc.history) + 1),
+		EventType:      domain.EventRealmCreated,
+		Data:           data,
+	})
+}
+
+func (tc *handlerTestContext) replay_configured(p ...core.Projector) {
+	tc.t.Helper()
+	history := &historyEventStore{mockEventStore: tc.eventStore, events: tc.history}
+	tc.handlers = NewHandlers(history, tc.projectionStore, tc.engine, WithReplayProjectors(p))
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) replay_report() core.ReplayReport {
+	tc.t.Helper()
+	var report core.ReplayReport
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &report))
+	return report
+}
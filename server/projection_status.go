@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/server/metrics"
+)
+
+// ProjectionStatus reports how far each projector has caught up.
+type ProjectionStatus interface {
+	Status(ctx context.Context) ([]core.ProjectorStatus, error)
+}
+
+// WithProjectionStatus reports the projectors of p on the
+// projection-status endpoint and in the metrics.
+func WithProjectionStatus(p ProjectionStatus) HandlersOption {
+	return func(h *Handlers) {
+		h.projections = p
+	}
+}
+
+type projectionStatusResponse struct {
+	Projectors []core.ProjectorStatus `json:"projectors"`
+}
+
+// GetProjectionStatus returns every projector's position, lag, throughput
+// and errors, so operators can tell whether reads are stale.
+func (h *Handlers) GetProjectionStatus(w http.ResponseWriter, r *http.Request) {
+	if h.projections == nil {
+		writeJSON(w, http.StatusOK, projectionStatusResponse{Projectors: []core.ProjectorStatus{}})
+		return
+	}
+	statuses, err := h.projections.Status(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, projectionStatusResponse{Projectors: statuses})
+}
+
+// collectProjectionStatus reports each projector's lag, position and
+// errors as gauges labelled with its name.
+func (h *Handlers) collectProjectionStatus(ctx context.Context) ([]metrics.Family, error) {
+	if h.projections == nil {
+		return nil, nil
+	}
+	statuses, err := h.projections.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lag := metrics.Family{Name: "bifrost_projection_lag", Type: metrics.TypeGauge,
+		Help: "Global positions between each projector's checkpoints and the newest events, summed over realms."}
+	position := metrics.Family{Name: "bifrost_projection_position", Type: metrics.TypeGauge,
+		Help: "Highest global position each projector has processed."}
+	rate := metrics.Family{Name: "bifrost_projection_events_per_second", Type: metrics.TypeGauge,
+		Help: "Events each projector projected per second over the last minute."}
+	errs := metrics.Family{Name: "bifrost_projection_errors_total", Type: metrics.TypeCounter,
+		Help: "Events each projector failed to handle and batches it failed to store since the process started."}
+	for _, status := range statuses {
+		labels := []metrics.Label{{Name: "projector", Value: status.Name}}
+		lag.Samples = append(lag.Samples, metrics.Sample{Labels: labels, Value: float64(status.Lag)})
+		position.Samples = append(position.Samples, metrics.Sample{Labels: labels, Value: float64(status.Position)})
+		rate.Samples = append(rate.Samples, metrics.Sample{Labels: labels, Value: status.EventsPerSecond})
+		errs.Samples = append(errs.Samples, metrics.Sample{Labels: labels, Value: float64(status.Errors)})
+	}
+	return []metrics.Family{lag, position, rate, errs}, nil
+}
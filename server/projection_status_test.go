@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// --- Tests ---
+
+func TestGetProjectionStatusHandler(t *testing.T) {
+	t.Run("returns each projector's progress", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.projections_report(
+			core.ProjectorStatus{Name: "rune_detail", Position: 42, Head: 42},
+			core.ProjectorStatus{Name: "analytics", Position: 30, Head: 42, Lag: 12, EventsPerSecond: 2.5, Errors: 1, LastError: "event 31: boom"},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/projection-status")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`{"name":"rune_detail","position":42,"head":42,"lag":0,"events_per_second":0,"errors":0}`)
+		tc.response_body_contains(`{"name":"analytics","position":30,"head":42,"lag":12,"events_per_second":2.5,"errors":1,"last_error":"event 31: boom"}`)
+	})
+
+	t.Run("returns no projectors without an engine to ask", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+
+		// When
+		tc.get("/projection-status")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_equals(`{"projectors":[]}`)
+	})
+
+	t.Run("returns 500 when the status cannot be read", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.projections = failingProjectionStatus{}
+		tc.handlers_configured()
+
+		// When
+		tc.get("/projection-status")
+
+		// Then
+		tc.status_is(http.StatusInternalServerError)
+	})
+
+	t.Run("exports each projector's lag as a metric", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.projections_report(core.ProjectorStatus{Name: "analytics", Position: 30, Head: 42, Lag: 12, Errors: 1})
+		tc.handlers_configured()
+
+		// When
+		out := tc.collected_command_metrics()
+
+		// Then
+		assert.Contains(t, out, `bifrost_projection_lag{projector="analytics"} 12`)
+		assert.Contains(t, out, `bifrost_projection_position{projector="analytics"} 30`)
+		assert.Contains(t, out, `bifrost_projection_errors_total{projector="analytics"} 1`)
+	})
+}
+
+// --- Test Context ---
+
+type staticProjectionStatus []core.ProjectorStatus
+
+func (s staticProjectionStatus) Status(context.Context) ([]core.ProjectorStatus, error) {
+	return s, nil
+}
+
+type failingProjectionStatus struct{}
+
+func (failingProjectionStatus) Status(context.Context) ([]core.ProjectorStatus, error) {
+	return nil, errors.New("listing realms: database is locked")
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) projections_report(statuses ...core.ProjectorStatus) {
+	tc.t.Helper()
+	tc.projections = staticProjectionStatus(statuses)
+}
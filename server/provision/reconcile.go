@@ -0,0 +1,239 @@
+package provision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Report lists what a reconciliation created and the drift it found.
+type Report struct {
+	Created []string
+	Drift   []string
+}
+
+type reconciler struct {
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	getenv          func(string) string
+
+	report Report
+	// realmIDs maps declared realm names to IDs. Realms whose name is
+	// ambiguous are left out.
+	realmIDs map[string]string
+}
+
+// Reconcile creates the realms, accounts, roles and webhooks in spec that do
+// not exist yet and reports drift between spec and the instance. It reads
+// the projections, so they should be caught up first.
+func Reconcile(ctx context.Context, spec *Spec, eventStore core.EventStore, projectionStore core.ProjectionStore) (Report, error) {
+	r := &reconciler{
+		eventStore:      eventStore,
+		projectionStore: projectionStore,
+		getenv:          os.Getenv,
+		realmIDs:        map[string]string{domain.AdminRealmID: domain.AdminRealmID},
+	}
+	err := r.run(ctx, spec)
+	return r.report, err
+}
+
+func (r *reconciler) run(ctx context.Context, spec *Spec) error {
+	if err := r.reconcileRealms(ctx, spec.Realms); err != nil {
+		return err
+	}
+	if err := r.reconcileAccounts(ctx, spec.Accounts); err != nil {
+		return err
+	}
+	return r.reconcileWebhooks(ctx, spec.Webhooks)
+}
+
+func (r *reconciler) created(format string, args ...any) {
+	r.report.Created = append(r.report.Created, fmt.Sprintf(format, args...))
+}
+
+func (r *reconciler) drift(format string, args ...any) {
+	r.report.Drift = append(r.report.Drift, fmt.Sprintf(format, args...))
+}
+
+func (r *reconciler) reconcileRealms(ctx context.Context, realms []RealmSpec) error {
+	raws, err := r.projectionStore.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return err
+	}
+	existing := map[string][]projectors.RealmListEntry{}
+	for _, raw := range raws {
+		var entry projectors.RealmListEntry
+		if json.Unmarshal(raw, &entry) == nil && entry.RealmID != "" && entry.RealmID != domain.AdminRealmID {
+			existing[entry.Name] = append(existing[entry.Name], entry)
+		}
+	}
+
+	for _, realm := range realms {
+		matches := existing[realm.Name]
+		switch len(matches) {
+		case 0:
+			result, err := domain.HandleCreateRealm(ctx, domain.CreateRealm{Name: realm.Name}, r.eventStore)
+			if err != nil {
+				return fmt.Errorf("create realm %q: %w", realm.Name, err)
+			}
+			r.realmIDs[realm.Name] = result.RealmID
+			r.created("realm %q (%s)", realm.Name, result.RealmID)
+		case 1:
+			r.realmIDs[realm.Name] = matches[0].RealmID
+			if matches[0].Status != "active" {
+				r.drift("realm %q is %s", realm.Name, matches[0].Status)
+			}
+		default:
+			r.drift("realm %q matches %d realms; skipping its roles and webhooks", realm.Name, len(matches))
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) reconcileAccounts(ctx context.Context, accounts []AccountSpec) error {
+	for _, account := range accounts {
+		var accountID string
+		var current map[string]string
+		err := r.projectionStore.Get(ctx, domain.AdminRealmID, "account_lookup", "username:"+account.Username, &accountID)
+		switch {
+		case isNotFound(err):
+			result, err := domain.HandleCreateAccount(ctx, domain.CreateAccount{Username: account.Username}, r.eventStore, r.projectionStore)
+			if err != nil {
+				return fmt.Errorf("create account %q: %w", account.Username, err)
+			}
+			accountID = result.AccountID
+			r.created("account %q (%s)", account.Username, accountID)
+		case err != nil:
+			return err
+		default:
+			var info projectors.AccountLookupEntry
+			if err := r.projectionStore.Get(ctx, domain.AdminRealmID, "account_lookup", "accountinfo:"+accountID, &info); err != nil {
+				return fmt.Errorf("load account %q: %w", account.Username, err)
+			}
+			if info.Status != "active" {
+				r.drift("account %q is %s", account.Username, info.Status)
+				continue
+			}
+			current = info.Roles
+		}
+
+		for _, realmName := range sortedKeys(account.Roles) {
+			role := account.Roles[realmName]
+			realmID, ok := r.realmIDs[realmName]
+			if !ok {
+				continue
+			}
+			switch have := current[realmID]; have {
+			case role:
+			case "":
+				if err := domain.HandleAssignRole(ctx, domain.AssignRole{AccountID: accountID, RealmID: realmID, Role: role}, r.eventStore); err != nil {
+					return fmt.Errorf("assign %s to %q in realm %q: %w", role, account.Username, realmName, err)
+				}
+				r.created("role %s for %q in realm %q", role, account.Username, realmName)
+			default:
+				r.drift("account %q has role %s in realm %q, declared %s", account.Username, have, realmName, role)
+			}
+		}
+
+		declared := map[string]bool{}
+		for realmName := range account.Roles {
+			declared[r.realmIDs[realmName]] = true
+		}
+		for _, realmID := range sortedKeys(current) {
+			if !declared[realmID] {
+				r.drift("account %q has undeclared role %s in realm %s", account.Username, current[realmID], realmID)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *reconciler) reconcileWebhooks(ctx context.Context, webhooks []WebhookSpec) error {
+	byRealm := map[string][]WebhookSpec{}
+	var realmNames []string
+	for _, hook := range webhooks {
+		if _, ok := byRealm[hook.Realm]; !ok {
+			realmNames = append(realmNames, hook.Realm)
+		}
+		byRealm[hook.Realm] = append(byRealm[hook.Realm], hook)
+	}
+
+	for _, realmName := range realmNames {
+		realmID, ok := r.realmIDs[realmName]
+		if !ok {
+			continue
+		}
+		raws, err := r.projectionStore.List(ctx, realmID, "webhook_list")
+		if err != nil {
+			return err
+		}
+		existing := map[string]projectors.WebhookListEntry{}
+		for _, raw := range raws {
+			var entry projectors.WebhookListEntry
+			if json.Unmarshal(raw, &entry) == nil && entry.ID != "" {
+				existing[entry.URL] = entry
+			}
+		}
+
+		for _, hook := range byRealm[realmName] {
+			entry, ok := existing[hook.URL]
+			delete(existing, hook.URL)
+			if ok {
+				if !sameEvents(entry.Events, hook.Events) {
+					r.drift("webhook %s in realm %q delivers %v, declared %v", hook.URL, realmName, entry.Events, hook.Events)
+				}
+				continue
+			}
+			var secret string
+			if hook.SecretEnv != "" {
+				if secret = r.getenv(hook.SecretEnv); secret == "" {
+					return fmt.Errorf("webhook %s: environment variable %s is not set", hook.URL, hook.SecretEnv)
+				}
+			}
+			result, err := domain.HandleRegisterWebhook(ctx, domain.RegisterWebhook{
+				RealmID: realmID,
+				URL:     hook.URL,
+				Secret:  secret,
+				Events:  hook.Events,
+			}, r.eventStore)
+			if err != nil {
+				return fmt.Errorf("register webhook %s in realm %q: %w", hook.URL, realmName, err)
+			}
+			r.created("webhook %s in realm %q (%s)", hook.URL, realmName, result.WebhookID)
+		}
+
+		for _, url := range sortedKeys(existing) {
+			r.drift("webhook %s in realm %q is not declared", url, realmName)
+		}
+	}
+	return nil
+}
+
+func sameEvents(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isNotFound(err error) bool {
+	var nfe *core.NotFoundError
+	return errors.As(err, &nfe)
+}
@@ -0,0 +1,375 @@
+package provision
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestReconcile(t *testing.T) {
+	t.Run("creates missing realms, accounts, roles and webhooks", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.env["CI_SECRET"] = "s3cret"
+		tc.a_spec(`
+realms: [{name: platform}]
+accounts: [{username: alice, roles: {platform: admin}}]
+webhooks: [{realm: platform, url: https://ci.example.com/hook, events: [RuneFulfilled], secret_env: CI_SECRET}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.report.Created, 4)
+		assert.Empty(t, tc.report.Drift)
+		tc.appended_types_are(domain.EventRealmCreated, domain.EventAccountCreated, domain.EventPATCreated, domain.EventRoleAssigned, domain.EventWebhookRegistered)
+		registered := tc.appended_data(domain.EventWebhookRegistered)
+		assert.Equal(t, "s3cret", registered["secret"])
+		assert.Equal(t, "https://ci.example.com/hook", registered["url"])
+	})
+
+	t.Run("creates nothing when the instance matches", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.existing_account("acct-1", "alice", "active", map[string]string{"realm-1": "admin"})
+		tc.existing_webhook("realm-1", "wh-1", "https://ci.example.com/hook", "RuneFulfilled", "RuneCreated")
+		tc.a_spec(`
+realms: [{name: platform}]
+accounts: [{username: alice, roles: {platform: admin}}]
+webhooks: [{realm: platform, url: https://ci.example.com/hook, events: [RuneCreated, RuneFulfilled]}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.report.Created)
+		assert.Empty(t, tc.report.Drift)
+		tc.appended_types_are()
+	})
+
+	t.Run("assigns missing roles to existing accounts", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.existing_account("acct-1", "alice", "active", nil)
+		tc.a_spec(`
+realms: [{name: platform}]
+accounts: [{username: alice, roles: {platform: member}}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`role member for "alice" in realm "platform"`}, tc.report.Created)
+		tc.appended_types_are(domain.EventRoleAssigned)
+	})
+
+	t.Run("reports role drift without changing roles", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.existing_realm("realm-2", "legacy", "active")
+		tc.existing_account("acct-1", "alice", "active", map[string]string{"realm-1": "owner", "realm-2": "viewer"})
+		tc.a_spec(`
+realms: [{name: platform}]
+accounts: [{username: alice, roles: {platform: admin}}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{
+			`account "alice" has role owner in realm "platform", declared admin`,
+			`account "alice" has undeclared role viewer in realm realm-2`,
+		}, tc.report.Drift)
+		tc.appended_types_are()
+	})
+
+	t.Run("reports suspended accounts and realms as drift", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "suspended")
+		tc.existing_account("acct-1", "alice", "suspended", nil)
+		tc.a_spec(`
+realms: [{name: platform}]
+accounts: [{username: alice, roles: {platform: admin}}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`realm "platform" is suspended`, `account "alice" is suspended`}, tc.report.Drift)
+		tc.appended_types_are()
+	})
+
+	t.Run("skips realms whose name is ambiguous", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.existing_realm("realm-2", "platform", "active")
+		tc.a_spec(`
+realms: [{name: platform}]
+webhooks: [{realm: platform, url: https://ci.example.com/hook}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{`realm "platform" matches 2 realms; skipping its roles and webhooks`}, tc.report.Drift)
+		tc.appended_types_are()
+	})
+
+	t.Run("reports webhook drift", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.existing_webhook("realm-1", "wh-1", "https://ci.example.com/hook", "RuneCreated")
+		tc.existing_webhook("realm-1", "wh-2", "https://old.example.com/hook")
+		tc.a_spec(`
+realms: [{name: platform}]
+webhooks: [{realm: platform, url: https://ci.example.com/hook, events: [RuneFulfilled]}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{
+			`webhook https://ci.example.com/hook in realm "platform" delivers [RuneCreated], declared [RuneFulfilled]`,
+			`webhook https://old.example.com/hook in realm "platform" is not declared`,
+		}, tc.report.Drift)
+		tc.appended_types_are()
+	})
+
+	t.Run("returns error when a webhook secret variable is unset", func(t *testing.T) {
+		tc := newReconcileTestContext(t)
+
+		// Given
+		tc.existing_realm("realm-1", "platform", "active")
+		tc.a_spec(`
+realms: [{name: platform}]
+webhooks: [{realm: platform, url: https://ci.example.com/hook, secret_env: MISSING}]
+`)
+
+		// When
+		tc.reconcile()
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "MISSING is not set")
+	})
+}
+
+// --- Test Context ---
+
+type reconcileTestContext struct {
+	t *testing.T
+
+	eventStore      *mockEventStore
+	projectionStore *mockProjectionStore
+	env             map[string]string
+	spec            *Spec
+	report          Report
+	err             error
+}
+
+func newReconcileTestContext(t *testing.T) *reconcileTestContext {
+	t.Helper()
+	return &reconcileTestContext{
+		t:               t,
+		eventStore:      &mockEventStore{streams: make(map[string][]core.Event)},
+		projectionStore: &mockProjectionStore{data: make(map[string]map[string]json.RawMessage)},
+		env:             make(map[string]string),
+	}
+}
+
+// --- Given ---
+
+func (tc *reconcileTestContext) a_spec(doc string) {
+	tc.t.Helper()
+	spec, err := Parse([]byte(doc))
+	require.NoError(tc.t, err)
+	tc.spec = spec
+}
+
+func (tc *reconcileTestContext) existing_realm(realmID, name, status string) {
+	tc.t.Helper()
+	tc.projectionStore.put(domain.AdminRealmID, "realm_list", realmID, projectors.RealmListEntry{RealmID: realmID, Name: name, Status: status})
+}
+
+func (tc *reconcileTestContext) existing_account(accountID, username, status string, roles map[string]string) {
+	tc.t.Helper()
+	tc.projectionStore.put(domain.AdminRealmID, "account_lookup", "username:"+username, accountID)
+	tc.projectionStore.put(domain.AdminRealmID, "account_lookup", "accountinfo:"+accountID, projectors.AccountLookupEntry{
+		AccountID: accountID, Username: username, Status: status, Roles: roles,
+	})
+	tc.eventStore.seed(domain.AdminRealmID, "account-"+accountID, domain.EventAccountCreated, domain.AccountCreated{AccountID: accountID, Username: username})
+	for realmID, role := range roles {
+		tc.eventStore.seed(domain.AdminRealmID, "account-"+accountID, domain.EventRoleAssigned, domain.RoleAssigned{AccountID: accountID, RealmID: realmID, Role: role})
+	}
+}
+
+func (tc *reconcileTestContext) existing_webhook(realmID, webhookID, url string, events ...string) {
+	tc.t.Helper()
+	tc.projectionStore.put(realmID, "webhook_list", webhookID, projectors.WebhookListEntry{ID: webhookID, RealmID: realmID, URL: url, Events: events})
+}
+
+// --- When ---
+
+func (tc *reconcileTestContext) reconcile() {
+	tc.t.Helper()
+	r := &reconciler{
+		eventStore:      tc.eventStore,
+		projectionStore: tc.projectionStore,
+		getenv:          func(key string) string { return tc.env[key] },
+		realmIDs:        map[string]string{domain.AdminRealmID: domain.AdminRealmID},
+	}
+	tc.err = r.run(context.Background(), tc.spec)
+	tc.report = r.report
+}
+
+// --- Then ---
+
+func (tc *reconcileTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *reconcileTestContext) appended_types_are(expected ...string) {
+	tc.t.Helper()
+	var types []string
+	for _, e := range tc.eventStore.appended {
+		types = append(types, e.EventType)
+	}
+	assert.Equal(tc.t, expected, types)
+}
+
+func (tc *reconcileTestContext) appended_data(eventType string) map[string]any {
+	tc.t.Helper()
+	for _, e := range tc.eventStore.appended {
+		if e.EventType == eventType {
+			var data map[string]any
+			require.NoError(tc.t, json.Unmarshal(e.Data, &data))
+			return data
+		}
+	}
+	tc.t.Fatalf("no %s event appended", eventType)
+	return nil
+}
+
+// --- Mock Event Store ---
+
+type mockEventStore struct {
+	streams  map[string][]core.Event
+	appended []core.Event
+}
+
+func (m *mockEventStore) seed(realmID, streamID, eventType string, data any) {
+	raw, _ := json.Marshal(data)
+	key := realmID + ":" + streamID
+	m.streams[key] = append(m.streams[key], core.Event{
+		RealmID: realmID, StreamID: streamID, Version: len(m.streams[key]), EventType: eventType, Data: raw,
+	})
+}
+
+func (m *mockEventStore) Append(_ context.Context, realmID string, streamID string, expectedVersion int, events []core.EventData) ([]core.Event, error) {
+	key := realmID + ":" + streamID
+	if len(m.streams[key]) != expectedVersion {
+		return nil, &core.ConcurrencyError{StreamID: streamID, ExpectedVersion: expectedVersion, ActualVersion: len(m.streams[key])}
+	}
+	var result []core.Event
+	for _, e := range events {
+		raw, err := json.Marshal(e.Data)
+		if err != nil {
+			return nil, err
+		}
+		event := core.Event{RealmID: realmID, StreamID: streamID, Version: len(m.streams[key]), EventType: e.EventType, Data: raw}
+		m.streams[key] = append(m.streams[key], event)
+		m.appended = append(m.appended, event)
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (m *mockEventStore) ReadStream(_ context.Context, realmID string, streamID string, _ int) ([]core.Event, error) {
+	return m.streams[realmID+":"+streamID], nil
+}
+
+func (m *mockEventStore) ReadAll(_ context.Context, _ string, _ int64) ([]core.Event, error) {
+	return nil, nil
+}
+
+func (m *mockEventStore) ListRealmIDs(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]map[string]json.RawMessage
+}
+
+func (m *mockProjectionStore) put(realmID, projectionName, key string, value any) {
+	raw, _ := json.Marshal(value)
+	name := realmID + ":" + projectionName
+	if m.data[name] == nil {
+		m.data[name] = make(map[string]json.RawMessage)
+	}
+	m.data[name][key] = raw
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	raw, ok := m.data[realmID+":"+projectionName][key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	return json.Unmarshal(raw, dest)
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	entries := m.data[realmID+":"+projectionName]
+	var result []json.RawMessage
+	for _, key := range sortedKeys(entries) {
+		result = append(result, entries[key])
+	}
+	return result, nil
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.put(realmID, projectionName, key, value)
+	return nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data[realmID+":"+projectionName], key)
+	return nil
+}
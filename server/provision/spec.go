@@ -0,0 +1,132 @@
+// Package provision reconciles a declarative description of realms,
+// accounts, roles and webhooks against the event store.
+//
+// Reconciliation only creates what is missing. Differences it will not
+// resolve on its own, such as a role that changed or a webhook that is not
+// declared, are reported as drift so the file or the instance can be fixed
+// by hand.
+package provision
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/devzeebo/bifrost/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the provisioning file. Realms are referred to by name; the _admin
+// realm may be used in roles to grant instance administration.
+//
+//	realms:
+//	  - name: Platform
+//	accounts:
+//	  - username: alice
+//	    roles:
+//	      Platform: admin
+//	webhooks:
+//	  - realm: Platform
+//	    url: https://ci.example.com/bifrost
+//	    events: [RuneFulfilled]
+//	    secret_env: PLATFORM_WEBHOOK_SECRET
+type Spec struct {
+	Realms   []RealmSpec   `yaml:"realms"`
+	Accounts []AccountSpec `yaml:"accounts"`
+	Webhooks []WebhookSpec `yaml:"webhooks"`
+}
+
+type RealmSpec struct {
+	Name string `yaml:"name"`
+}
+
+type AccountSpec struct {
+	Username string `yaml:"username"`
+	// Roles maps realm names to roles.
+	Roles map[string]string `yaml:"roles"`
+}
+
+type WebhookSpec struct {
+	Realm  string   `yaml:"realm"`
+	URL    string   `yaml:"url"`
+	Events []string `yaml:"events"`
+	// SecretEnv names the environment variable holding the signing secret,
+	// keeping secrets out of the file. A secret is generated when unset.
+	SecretEnv string `yaml:"secret_env"`
+}
+
+// Load reads and validates a provisioning file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a provisioning document. Unknown fields are
+// rejected so typos do not silently drop configuration.
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse provisioning file: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks that names are unique, roles are valid and every realm
+// referenced is declared.
+func (s *Spec) Validate() error {
+	realms := map[string]bool{domain.AdminRealmID: true}
+	for _, r := range s.Realms {
+		if r.Name == "" {
+			return fmt.Errorf("realm name is required")
+		}
+		if realms[r.Name] {
+			return fmt.Errorf("realm %q is declared more than once", r.Name)
+		}
+		realms[r.Name] = true
+	}
+
+	usernames := map[string]bool{}
+	for _, a := range s.Accounts {
+		if a.Username == "" {
+			return fmt.Errorf("account username is required")
+		}
+		if usernames[a.Username] {
+			return fmt.Errorf("account %q is declared more than once", a.Username)
+		}
+		usernames[a.Username] = true
+		for realm, role := range a.Roles {
+			if !realms[realm] {
+				return fmt.Errorf("account %q: realm %q is not declared", a.Username, realm)
+			}
+			if !domain.IsValidRole(role) {
+				return fmt.Errorf("account %q: invalid role %q for realm %q", a.Username, role, realm)
+			}
+		}
+	}
+
+	hooks := map[string]bool{}
+	for _, w := range s.Webhooks {
+		if w.Realm == domain.AdminRealmID || !realms[w.Realm] {
+			return fmt.Errorf("webhook %s: realm %q is not declared", w.URL, w.Realm)
+		}
+		if w.URL == "" {
+			return fmt.Errorf("webhook url is required for realm %q", w.Realm)
+		}
+		key := w.Realm + " " + w.URL
+		if hooks[key] {
+			return fmt.Errorf("webhook %s is declared more than once for realm %q", w.URL, w.Realm)
+		}
+		hooks[key] = true
+	}
+	return nil
+}
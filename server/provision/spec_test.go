@@ -0,0 +1,118 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestParse(t *testing.T) {
+	t.Run("decodes realms, accounts and webhooks", func(t *testing.T) {
+		tc := newSpecTestContext(t)
+
+		// When
+		tc.parse(`
+realms:
+  - name: platform
+accounts:
+  - username: alice
+    roles:
+      _admin: owner
+      platform: admin
+webhooks:
+  - realm: platform
+    url: https://ci.example.com/hook
+    events: [RuneFulfilled]
+    secret_env: CI_SECRET
+`)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []RealmSpec{{Name: "platform"}}, tc.spec.Realms)
+		assert.Equal(t, []AccountSpec{{Username: "alice", Roles: map[string]string{"_admin": "owner", "platform": "admin"}}}, tc.spec.Accounts)
+		assert.Equal(t, []WebhookSpec{{Realm: "platform", URL: "https://ci.example.com/hook", Events: []string{"RuneFulfilled"}, SecretEnv: "CI_SECRET"}}, tc.spec.Webhooks)
+	})
+
+	t.Run("accepts an empty document", func(t *testing.T) {
+		tc := newSpecTestContext(t)
+
+		// When
+		tc.parse("")
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.spec.Realms)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		tc := newSpecTestContext(t)
+
+		// When
+		tc.parse("realms:\n  - nmae: platform\n")
+
+		// Then
+		tc.error_contains("field nmae not found")
+	})
+
+	cases := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{"duplicate realm", "realms: [{name: a}, {name: a}]", `realm "a" is declared more than once`},
+		{"duplicate account", "accounts: [{username: bob}, {username: bob}]", `account "bob" is declared more than once`},
+		{"undeclared role realm", "accounts: [{username: bob, roles: {other: member}}]", `realm "other" is not declared`},
+		{"invalid role", "realms: [{name: a}]\naccounts: [{username: bob, roles: {a: boss}}]", `invalid role "boss"`},
+		{"webhook in admin realm", "webhooks: [{realm: _admin, url: https://x}]", `realm "_admin" is not declared`},
+		{"webhook without url", "realms: [{name: a}]\nwebhooks: [{realm: a}]", "webhook url is required"},
+		{"duplicate webhook", "realms: [{name: a}]\nwebhooks: [{realm: a, url: https://x}, {realm: a, url: https://x}]", "declared more than once"},
+	}
+	for _, c := range cases {
+		t.Run("rejects "+c.name, func(t *testing.T) {
+			tc := newSpecTestContext(t)
+
+			// When
+			tc.parse(c.doc)
+
+			// Then
+			tc.error_contains(c.expected)
+		})
+	}
+}
+
+// --- Test Context ---
+
+type specTestContext struct {
+	t *testing.T
+
+	spec *Spec
+	err  error
+}
+
+func newSpecTestContext(t *testing.T) *specTestContext {
+	t.Helper()
+	return &specTestContext{t: t}
+}
+
+// --- When ---
+
+func (tc *specTestContext) parse(doc string) {
+	tc.t.Helper()
+	tc.spec, tc.err = Parse([]byte(doc))
+}
+
+// --- Then ---
+
+func (tc *specTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+func (tc *specTestContext) error_contains(substr string) {
+	tc.t.Helper()
+	require.Error(tc.t, tc.err)
+	assert.Contains(tc.t, tc.err.Error(), substr)
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+const hostRealmKey contextKey = "host_realm"
+
+// HostRealmFromContext returns the realm the request's host is mapped to.
+func HostRealmFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(hostRealmKey).(string)
+	return id, ok
+}
+
+// RealmHosts maps request hosts to realms: explicitly, host by host, or by
+// subdomain of a shared domain, where the subdomain is the realm's ID or
+// its name in lower case with spaces replaced by dashes.
+type RealmHosts struct {
+	Hosts  map[string]string // host -> realm ID
+	Domain string            // e.g. "bifrost.example.com" for team-a.bifrost.example.com
+}
+
+// Enabled reports whether any host is mapped.
+func (m *RealmHosts) Enabled() bool {
+	return m != nil && (len(m.Hosts) > 0 || m.Domain != "")
+}
+
+// Resolve returns the realm host is mapped to. mapped is true when host
+// should name a realm, even if no realm matches it.
+func (m *RealmHosts) Resolve(ctx context.Context, store core.ProjectionStore, host string) (realmID string, mapped bool, err error) {
+	if h, _, splitErr := net.SplitHostPort(host); splitErr == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if id, ok := m.Hosts[host]; ok {
+		return id, true, nil
+	}
+	if m.Domain == "" {
+		return "", false, nil
+	}
+	label, ok := strings.CutSuffix(host, "."+strings.ToLower(m.Domain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return "", false, nil
+	}
+
+	raws, err := store.List(ctx, "_admin", "realm_list")
+	if err != nil {
+		return "", true, err
+	}
+	for _, raw := range raws {
+		var entry projectors.RealmListEntry
+		if json.Unmarshal(raw, &entry) != nil || entry.RealmID == "" {
+			continue
+		}
+		if entry.RealmID == label || realmSlug(entry.Name) == label {
+			return entry.RealmID, true, nil
+		}
+	}
+	return "", true, nil
+}
+
+// realmSlug turns a realm name into a hostname label.
+func realmSlug(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// HostRealm returns HTTP middleware that records the realm the request's
+// host is mapped to, for AuthMiddleware to scope the request to. A host that
+// should name a realm but matches none gets 404.
+func HostRealm(hosts *RealmHosts, projectionStore core.ProjectionStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !hosts.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			realmID, mapped, err := hosts.Resolve(r.Context(), projectionStore, r.Host)
+			switch {
+			case err != nil:
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			case !mapped:
+				next.ServeHTTP(w, r)
+				return
+			case realmID == "":
+				http.Error(w, "No realm for host", http.StatusNotFound)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), hostRealmKey, realmID)))
+		})
+	}
+}
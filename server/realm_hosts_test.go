@@ -0,0 +1,166 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestHostRealm(t *testing.T) {
+	t.Run("scopes a mapped host to its realm", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_hosts(&RealmHosts{Hosts: map[string]string{"tracker.team-a.com": "realm-a"}})
+
+		// When
+		tc.request_for_host("Tracker.Team-A.com:8443")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.host_realm_is("realm-a")
+	})
+
+	t.Run("scopes a subdomain to the realm with that name", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_exists("realm-a", "Team A")
+		tc.realm_hosts(&RealmHosts{Domain: "bifrost.example.com"})
+
+		// When
+		tc.request_for_host("team-a.bifrost.example.com")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.host_realm_is("realm-a")
+	})
+
+	t.Run("scopes a subdomain to the realm with that ID", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_exists("realm-a", "Team A")
+		tc.realm_hosts(&RealmHosts{Domain: "bifrost.example.com"})
+
+		// When
+		tc.request_for_host("realm-a.bifrost.example.com")
+
+		// Then
+		tc.host_realm_is("realm-a")
+	})
+
+	t.Run("returns 404 for a subdomain that names no realm", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_exists("realm-a", "Team A")
+		tc.realm_hosts(&RealmHosts{Domain: "bifrost.example.com"})
+
+		// When
+		tc.request_for_host("team-b.bifrost.example.com")
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+		assert.False(t, tc.nextCalled)
+	})
+
+	t.Run("leaves the shared domain itself unscoped", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_hosts(&RealmHosts{Domain: "bifrost.example.com"})
+
+		// When
+		tc.request_for_host("bifrost.example.com")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.host_is_unscoped()
+	})
+
+	t.Run("does nothing when no hosts are mapped", func(t *testing.T) {
+		tc := newHostRealmTestContext(t)
+
+		// Given
+		tc.realm_hosts(&RealmHosts{})
+
+		// When
+		tc.request_for_host("team-a.bifrost.example.com")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.host_is_unscoped()
+	})
+}
+
+// --- Test Context ---
+
+type hostRealmTestContext struct {
+	t     *testing.T
+	store *mockProjectionStore
+	hosts *RealmHosts
+
+	recorder   *httptest.ResponseRecorder
+	nextCalled bool
+	hostRealm  string
+	scoped     bool
+}
+
+func newHostRealmTestContext(t *testing.T) *hostRealmTestContext {
+	t.Helper()
+	return &hostRealmTestContext{
+		t:        t,
+		store:    newMockProjectionStore(),
+		recorder: httptest.NewRecorder(),
+	}
+}
+
+// --- Given ---
+
+func (tc *hostRealmTestContext) realm_exists(realmID, name string) {
+	tc.t.Helper()
+	tc.store.put("_admin", "realm_list", realmID, projectors.RealmListEntry{RealmID: realmID, Name: name, Status: "active"})
+}
+
+func (tc *hostRealmTestContext) realm_hosts(hosts *RealmHosts) {
+	tc.t.Helper()
+	tc.hosts = hosts
+}
+
+// --- When ---
+
+func (tc *hostRealmTestContext) request_for_host(host string) {
+	tc.t.Helper()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.nextCalled = true
+		tc.hostRealm, tc.scoped = HostRealmFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/runes", nil)
+	req.Host = host
+	HostRealm(tc.hosts, tc.store)(next).ServeHTTP(tc.recorder, req)
+}
+
+// --- Then ---
+
+func (tc *hostRealmTestContext) status_is(code int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, code, tc.recorder.Code)
+}
+
+func (tc *hostRealmTestContext) host_realm_is(expected string) {
+	tc.t.Helper()
+	assert.True(tc.t, tc.scoped, "expected the request to be scoped to a realm")
+	assert.Equal(tc.t, expected, tc.hostRealm)
+}
+
+func (tc *hostRealmTestContext) host_is_unscoped() {
+	tc.t.Helper()
+	assert.True(tc.t, tc.nextCalled)
+	assert.False(tc.t, tc.scoped)
+}
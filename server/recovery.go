@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// CorrelationIDHeader carries the ID of a request that panicked, so a user
+// can quote it and an operator can find the matching log line and report.
+const CorrelationIDHeader = "X-Bifrost-Correlation-Id"
+
+// PanicReport describes a panic recovered from an HTTP handler.
+type PanicReport struct {
+	CorrelationID string
+	Time          time.Time
+	Method        string
+	URL           string
+	Value         any
+	Stack         []byte
+}
+
+// PanicReporter receives every recovered panic after it is logged, e.g. to
+// forward it to an error tracker. It runs on the request's goroutine and
+// should not block.
+type PanicReporter func(ctx context.Context, report PanicReport)
+
+// Recover returns HTTP middleware that turns a panic in next into a 500
+// response carrying a correlation ID. The panic and its stack trace are
+// logged under that ID and passed to report when it is not nil.
+// http.ErrAbortHandler is re-raised, since it asks the server to drop the
+// connection.
+func Recover(report PanicReporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				p := PanicReport{
+					CorrelationID: newCorrelationID(),
+					Time:          time.Now().UTC(),
+					Method:        r.Method,
+					URL:           r.URL.String(),
+					Value:         v,
+					Stack:         debug.Stack(),
+				}
+				log.Printf("panic [%s] %s %s: %v\n%s", p.CorrelationID, p.Method, p.URL, p.Value, p.Stack)
+				if report != nil {
+					report(r.Context(), p)
+				}
+
+				w.Header().Set(CorrelationIDHeader, p.CorrelationID)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"error":          "internal server error",
+					"correlation_id": p.CorrelationID,
+				})
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newCorrelationID returns 32 hex digits, the form Sentry expects of an
+// event ID.
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
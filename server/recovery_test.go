@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestRecover(t *testing.T) {
+	t.Run("turns a panic into a 500 with a correlation ID", func(t *testing.T) {
+		tc := newRecoveryTestContext(t)
+
+		// Given
+		tc.a_handler_that_panics_with("boom")
+
+		// When
+		tc.request_is_served()
+
+		// Then
+		tc.status_is(http.StatusInternalServerError)
+		tc.response_has_correlation_id()
+	})
+
+	t.Run("reports the panic under the same correlation ID", func(t *testing.T) {
+		tc := newRecoveryTestContext(t)
+
+		// Given
+		tc.a_panic_reporter()
+		tc.a_handler_that_panics_with("boom")
+
+		// When
+		tc.request_is_served()
+
+		// Then
+		tc.panic_was_reported("boom")
+	})
+
+	t.Run("passes requests through when nothing panics", func(t *testing.T) {
+		tc := newRecoveryTestContext(t)
+
+		// Given
+		tc.a_panic_reporter()
+		tc.a_handler_that_succeeds()
+
+		// When
+		tc.request_is_served()
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.no_panic_was_reported()
+	})
+
+	t.Run("re-raises http.ErrAbortHandler", func(t *testing.T) {
+		tc := newRecoveryTestContext(t)
+
+		// Given
+		tc.a_handler_that_panics_with(http.ErrAbortHandler)
+
+		// When / Then
+		assert.PanicsWithValue(t, http.ErrAbortHandler, tc.request_is_served)
+	})
+}
+
+func TestNewSentryReporter(t *testing.T) {
+	t.Run("posts the panic to the DSN's store endpoint", func(t *testing.T) {
+		tc := newRecoveryTestContext(t)
+
+		// Given
+		tc.a_sentry_server()
+		tc.a_sentry_reporter_for_project("42")
+
+		// When
+		tc.panic_is_reported(PanicReport{CorrelationID: "0123456789abcdef0123456789abcdef", Method: "POST", URL: "/api/create-rune", Value: "boom"})
+
+		// Then
+		tc.sentry_received("/api/42/store/", "0123456789abcdef0123456789abcdef", "panic: boom")
+	})
+
+	t.Run("rejects a DSN without a key or project", func(t *testing.T) {
+		// When
+		_, err := NewSentryReporter("https://sentry.example.com/", http.DefaultClient)
+
+		// Then
+		assert.ErrorContains(t, err, "sentry DSN")
+	})
+}
+
+// --- Test Context ---
+
+type recoveryTestContext struct {
+	t *testing.T
+
+	handler  http.Handler
+	reporter PanicReporter
+	reports  []PanicReport
+	recorder *httptest.ResponseRecorder
+
+	sentry         *httptest.Server
+	sentryRequests chan sentryRequestRecord
+}
+
+type sentryRequestRecord struct {
+	path  string
+	auth  string
+	event sentryEvent
+}
+
+func newRecoveryTestContext(t *testing.T) *recoveryTestContext {
+	t.Helper()
+	return &recoveryTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *recoveryTestContext) a_panic_reporter() {
+	tc.t.Helper()
+	tc.reporter = func(_ context.Context, report PanicReport) {
+		tc.reports = append(tc.reports, report)
+	}
+}
+
+func (tc *recoveryTestContext) a_handler_that_panics_with(value any) {
+	tc.t.Helper()
+	tc.handler = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(value)
+	})
+}
+
+func (tc *recoveryTestContext) a_handler_that_succeeds() {
+	tc.t.Helper()
+	tc.handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (tc *recoveryTestContext) a_sentry_server() {
+	tc.t.Helper()
+	tc.sentryRequests = make(chan sentryRequestRecord, 1)
+	var once sync.Once
+	tc.sentry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event sentryEvent
+		_ = json.Unmarshal(body, &event)
+		once.Do(func() {
+			tc.sentryRequests <- sentryRequestRecord{path: r.URL.Path, auth: r.Header.Get("X-Sentry-Auth"), event: event}
+		})
+		w.WriteHeader(http.StatusOK)
+	}))
+	tc.t.Cleanup(tc.sentry.Close)
+}
+
+func (tc *recoveryTestContext) a_sentry_reporter_for_project(project string) {
+	tc.t.Helper()
+	dsn := "http://public-key@" + tc.sentry.Listener.Addr().String() + "/" + project
+	reporter, err := NewSentryReporter(dsn, tc.sentry.Client())
+	require.NoError(tc.t, err)
+	tc.reporter = reporter
+}
+
+// --- When ---
+
+func (tc *recoveryTestContext) request_is_served() {
+	tc.t.Helper()
+	tc.recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/create-rune", nil)
+	Recover(tc.reporter)(tc.handler).ServeHTTP(tc.recorder, req)
+}
+
+func (tc *recoveryTestContext) panic_is_reported(report PanicReport) {
+	tc.t.Helper()
+	report.Time = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tc.reporter(context.Background(), report)
+}
+
+// --- Then ---
+
+func (tc *recoveryTestContext) status_is(expected int) {
+	tc.t.Helper()
+	assert.Equal(tc.t, expected, tc.recorder.Code)
+}
+
+func (tc *recoveryTestContext) response_has_correlation_id() {
+	tc.t.Helper()
+	id := tc.recorder.Header().Get(CorrelationIDHeader)
+	assert.Regexp(tc.t, regexp.MustCompile(`^[0-9a-f]{32}$`), id)
+	var body map[string]string
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &body))
+	assert.Equal(tc.t, id, body["correlation_id"])
+	assert.Equal(tc.t, "internal server error", body["error"])
+}
+
+func (tc *recoveryTestContext) panic_was_reported(value any) {
+	tc.t.Helper()
+	require.Len(tc.t, tc.reports, 1)
+	report := tc.reports[0]
+	assert.Equal(tc.t, value, report.Value)
+	assert.Equal(tc.t, tc.recorder.Header().Get(CorrelationIDHeader), report.CorrelationID)
+	assert.Equal(tc.t, "/api/create-rune", report.URL)
+	assert.Contains(tc.t, string(report.Stack), "recovery_test.go")
+}
+
+func (tc *recoveryTestContext) no_panic_was_reported() {
+	tc.t.Helper()
+	assert.Empty(tc.t, tc.reports)
+}
+
+func (tc *recoveryTestContext) sentry_received(path, eventID, message string) {
+	tc.t.Helper()
+	select {
+	case got := <-tc.sentryRequests:
+		assert.Equal(tc.t, path, got.path)
+		assert.Contains(tc.t, got.auth, "sentry_key=public-key")
+		assert.Equal(tc.t, eventID, got.event.EventID)
+		assert.Equal(tc.t, message, got.event.Message)
+		assert.Equal(tc.t, "2026-01-02T03:04:05Z", got.event.Timestamp)
+	case <-time.After(5 * time.Second):
+		tc.t.Fatal("sentry received no report")
+	}
+}
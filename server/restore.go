@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/server/backup"
+)
+
+// Restore rebuilds the server's empty database from a backup archive
+// encrypted with key, up to target, then replays the projections over the
+// restored events. It is a maintenance operation: call it on a server built
+// by New that has not been started, so nothing else appends meanwhile.
+//
+// Notifications, webhooks and automation rules already acted on the
+// restored history when it first happened, so their checkpoints are moved
+// past it instead of replaying it.
+func (s *Server) Restore(ctx context.Context, archive io.Reader, key []byte, target backup.Target) (backup.RestoreResult, error) {
+	if s.stopBackground != nil {
+		return backup.RestoreResult{}, errors.New("restore needs a server that has not been started")
+	}
+	result, err := backup.Restore(ctx, s.eventStore, archive, key, target)
+	if err != nil {
+		return result, err
+	}
+	for _, realmID := range result.Realms {
+		last, err := lastPosition(ctx, s.eventStore, realmID)
+		if err != nil {
+			return result, fmt.Errorf("read realm %s: %w", realmID, err)
+		}
+		for _, p := range s.outward {
+			if err := s.checkpoints.SetCheckpoint(ctx, realmID, p.Name(), last); err != nil {
+				return result, fmt.Errorf("skip %s past the restored events: %w", p.Name(), err)
+			}
+		}
+	}
+	s.engine.RunCatchUpOnce(ctx)
+	return result, nil
+}
+
+// lastPosition returns the global position of the realm's last event.
+func lastPosition(ctx context.Context, events core.EventStore, realmID string) (int64, error) {
+	limit := core.ExportBatchSize
+	if _, ok := events.(core.BatchEventReader); !ok {
+		limit = 0
+	}
+	var from int64
+	for {
+		page, err := core.ReadPage(ctx, events, realmID, from, limit)
+		if err != nil {
+			return 0, err
+		}
+		from = page.Next
+		if !page.More {
+			return from, nil
+		}
+	}
+}
+
+// runRestore restores the database named by cfg from cfg.Restore.From and
+// shuts down without serving. Events are not published while restoring,
+// since subscribers saw them the first time.
+func runRestore(ctx context.Context, cfg *Config) error {
+	restoreCfg := *cfg
+	restoreCfg.Outbox = OutboxConfig{}
+	restoreCfg.Backup = BackupConfig{}
+	srv, err := New(WithConfig(&restoreCfg))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		_ = srv.Stop(shutdownCtx)
+	}()
+
+	f, err := os.Open(cfg.Restore.From)
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	defer f.Close()
+	result, err := srv.Restore(ctx, f, cfg.Restore.Key, backup.Target{Position: cfg.Restore.ToPosition, Time: cfg.Restore.ToTime})
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	log.Printf("restore: restored %d events of %d realms from %s, leaving out %d", result.Events, len(result.Realms), cfg.Restore.From, result.Skipped)
+	return nil
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// Saved searches are named rune list filters kept per account and realm, so
+// the CLI, TUI and admin UI offer the same saved views. Each account sees and
+// changes only its own.
+
+// savedSearchCaller returns the realm and account a saved search request acts
+// for, writing a 403 when either is missing.
+func savedSearchCaller(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return "", "", false
+	}
+	accountID, ok := AccountIDFromContext(r.Context())
+	if !ok || accountID == "" {
+		writeError(w, http.StatusForbidden, "account ID required")
+		return "", "", false
+	}
+	return realmID, accountID, true
+}
+
+func (h *Handlers) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := savedSearchCaller(w, r)
+	if !ok {
+		return
+	}
+	var cmd domain.CreateSavedSearch
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID, cmd.AccountID = realmID, accountID
+	result, err := domain.HandleCreateSavedSearch(r.Context(), cmd, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (h *Handlers) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := savedSearchCaller(w, r)
+	if !ok {
+		return
+	}
+	var cmd domain.UpdateSavedSearch
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID, cmd.AccountID = realmID, accountID
+	if err := domain.HandleUpdateSavedSearch(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := savedSearchCaller(w, r)
+	if !ok {
+		return
+	}
+	var cmd domain.DeleteSavedSearch
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID, cmd.AccountID = realmID, accountID
+	if err := domain.HandleDeleteSavedSearch(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSavedSearches returns the calling account's searches in the realm,
+// ordered by name.
+func (h *Handlers) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	realmID, accountID, ok := savedSearchCaller(w, r)
+	if !ok {
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "saved_searches")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list saved searches")
+		return
+	}
+	searches := []projectors.SavedSearchEntry{}
+	for _, raw := range raws {
+		var search projectors.SavedSearchEntry
+		if json.Unmarshal(raw, &search) == nil && search.AccountID == accountID {
+			searches = append(searches, search)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	writeJSON(w, http.StatusOK, searches)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCreateSavedSearchHandler(t *testing.T) {
+	t.Run("saves the search for the calling account in the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/create-saved-search", map[string]string{"name": "Open", "filter": "status=open"})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.response_body_has_field("search_id")
+		created := tc.only_created_saved_search()
+		assert.Equal(t, "realm-1", created.RealmID)
+		assert.Equal(t, "acct-1", created.AccountID)
+	})
+
+	t.Run("returns 400 for an unknown filter", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/create-saved-search", map[string]string{"name": "Red", "filter": "color=red"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("must be one of")
+	})
+
+	t.Run("returns 403 without an account", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-saved-search", map[string]string{"name": "Open"})
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+	})
+}
+
+func TestDeleteSavedSearchHandler(t *testing.T) {
+	t.Run("returns 404 for another account's search", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.saved_search_exists_in_event_store("ss-1", "realm-1", "acct-2")
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/delete-saved-search", map[string]string{"search_id": "ss-1"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+
+	t.Run("deletes the account's search", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.saved_search_exists_in_event_store("ss-1", "realm-1", "acct-1")
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.post("/delete-saved-search", map[string]string{"search_id": "ss-1"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+}
+
+func TestListSavedSearchesHandler(t *testing.T) {
+	t.Run("lists only the calling account's searches by name", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.projection_has_saved_search("realm-1", "ss-2", "acct-1", "Zeta")
+		tc.projection_has_saved_search("realm-1", "ss-1", "acct-1", "Alpha")
+		tc.projection_has_saved_search("realm-1", "ss-3", "acct-2", "Someone else's")
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+
+		// When
+		tc.get("/saved-searches")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var searches []projectors.SavedSearchEntry
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &searches))
+		require.Len(t, searches, 2)
+		assert.Equal(t, "Alpha", searches[0].Name)
+		assert.Equal(t, "Zeta", searches[1].Name)
+	})
+}
+
+func TestSavedSearchFilterKeys(t *testing.T) {
+	t.Run("match the rune list query parameters", func(t *testing.T) {
+		// Given
+		params := []string{"blocked"}
+		for param := range runeListFilterFields {
+			params = append(params, param)
+		}
+
+		// Then
+		assert.ElementsMatch(t, params, domain.SavedSearchFilterKeys)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) saved_search_exists_in_event_store(searchID, realmID, accountID string) {
+	tc.t.Helper()
+	created := domain.SavedSearchCreated{SearchID: searchID, RealmID: realmID, AccountID: accountID, Name: "Open", Filter: "status=open"}
+	tc.eventStore.appendToStream(domain.AdminRealmID, "saved-search-"+searchID, domain.EventSavedSearchCreated, created)
+}
+
+func (tc *handlerTestContext) projection_has_saved_search(realmID, searchID, accountID, name string) {
+	tc.t.Helper()
+	entry := projectors.SavedSearchEntry{ID: searchID, RealmID: realmID, AccountID: accountID, Name: name}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "saved_searches", searchID, entry)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) only_created_saved_search() domain.SavedSearchCreated {
+	tc.t.Helper()
+	var found []domain.SavedSearchCreated
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType != domain.EventSavedSearchCreated {
+				continue
+			}
+			var created domain.SavedSearchCreated
+			require.NoError(tc.t, json.Unmarshal(evt.Data, &created))
+			found = append(found, created)
+		}
+	}
+	require.Len(tc.t, found, 1)
+	return found[0]
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+func (h *Handlers) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.CreateSchedule
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	result, err := domain.HandleCreateSchedule(r.Context(), cmd, h.eventStore)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (h *Handlers) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var cmd domain.DeleteSchedule
+	if !decodeJSONBody(w, r, &cmd) {
+		return
+	}
+	cmd.RealmID = realmID
+	if err := domain.HandleDeleteSchedule(r.Context(), cmd, h.eventStore); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	h.runSyncQuietly(r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSchedules returns the realm's schedules ordered by name.
+func (h *Handlers) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "schedules")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list schedules")
+		return
+	}
+	schedules := []projectors.ScheduleEntry{}
+	for _, raw := range raws {
+		var schedule projectors.ScheduleEntry
+		if json.Unmarshal(raw, &schedule) == nil && schedule.ID != "" {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	writeJSON(w, http.StatusOK, schedules)
+}
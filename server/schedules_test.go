@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestCreateScheduleHandler(t *testing.T) {
+	t.Run("creates schedule in the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-schedule", domain.CreateSchedule{
+			RealmID: "realm-other", Name: "Seal old drafts", Cron: "@weekly",
+			Command: domain.ScheduleCommandSealStaleDrafts, Args: map[string]string{"days": "30"},
+		})
+
+		// Then
+		tc.status_is(http.StatusCreated)
+		tc.response_body_has_field("schedule_id")
+		created := tc.only_created_schedule()
+		assert.Equal(t, "realm-1", created.RealmID)
+		assert.Equal(t, map[string]string{"days": "30"}, created.Args)
+	})
+
+	t.Run("returns 400 for a command that is not allowed", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.realm_exists_in_event_store("realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/create-schedule", domain.CreateSchedule{Name: "Nope", Cron: "@daily", Command: "suspend_realm"})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("command must be one of")
+	})
+}
+
+func TestDeleteScheduleHandler(t *testing.T) {
+	t.Run("deletes schedule from the request realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.schedule_exists_in_event_store("sc-1", "realm-1")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/delete-schedule", map[string]string{"schedule_id": "sc-1"})
+
+		// Then
+		tc.status_is(http.StatusNoContent)
+	})
+
+	t.Run("returns 404 for a schedule in another realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.schedule_exists_in_event_store("sc-1", "realm-2")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.post("/delete-schedule", map[string]string{"schedule_id": "sc-1"})
+
+		// Then
+		tc.status_is(http.StatusNotFound)
+	})
+}
+
+func TestListSchedulesHandler(t *testing.T) {
+	t.Run("lists the realm's schedules by name", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.projection_has_schedule("realm-1", "sc-2", "Weekly review")
+		tc.projection_has_schedule("realm-1", "sc-1", "Nightly sweep")
+		tc.projection_has_schedule("realm-2", "sc-3", "Other realm")
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/schedules")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		var schedules []projectors.ScheduleEntry
+		require.NoError(t, json.Unmarshal(tc.recorder.Body.Bytes(), &schedules))
+		require.Len(t, schedules, 2)
+		assert.Equal(t, "Nightly sweep", schedules[0].Name)
+		assert.Equal(t, "Weekly review", schedules[1].Name)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) schedule_exists_in_event_store(scheduleID, realmID string) {
+	tc.t.Helper()
+	created := domain.ScheduleCreated{
+		ScheduleID: scheduleID, RealmID: realmID, Name: "Nightly sweep", Cron: "@daily",
+		Command: domain.ScheduleCommandSweepRunes,
+	}
+	tc.eventStore.appendToStream(domain.AdminRealmID, "schedule-"+scheduleID, domain.EventScheduleCreated, created)
+}
+
+func (tc *handlerTestContext) projection_has_schedule(realmID, scheduleID, name string) {
+	tc.t.Helper()
+	entry := projectors.ScheduleEntry{ID: scheduleID, RealmID: realmID, Name: name, Cron: "@daily", Command: domain.ScheduleCommandSweepRunes}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "schedules", scheduleID, entry)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) only_created_schedule() domain.ScheduleCreated {
+	tc.t.Helper()
+	var found []domain.ScheduleCreated
+	for _, events := range tc.eventStore.streams {
+		for _, evt := range events {
+			if evt.EventType != domain.EventScheduleCreated {
+				continue
+			}
+			var created domain.ScheduleCreated
+			require.NoError(tc.t, json.Unmarshal(evt.Data, &created))
+			found = append(found, created)
+		}
+	}
+	require.Len(tc.t, found, 1)
+	return found[0]
+}
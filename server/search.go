@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// WithSearchIndex answers the search endpoint from index.
+func WithSearchIndex(index core.SearchIndex) HandlersOption {
+	return func(h *Handlers) {
+		h.search = index
+	}
+}
+
+// SearchResult is a rune matching a search, with its relevance score.
+type SearchResult struct {
+	projectors.RuneSummary
+	Score float64 `json:"score"`
+}
+
+// Search serves GET /search: the realm's runes whose title, description or
+// notes match the q parameter, best matches first. status narrows the hits
+// to a comma-separated list of statuses and limit caps their number.
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	if h.search == nil {
+		writeError(w, http.StatusNotImplemented, "search is not enabled")
+		return
+	}
+
+	query := core.SearchQuery{RealmID: realmID, Text: strings.TrimSpace(r.URL.Query().Get("q")), Limit: defaultSearchLimit}
+	if query.Text == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit))
+			return
+		}
+		query.Limit = n
+	}
+	if v := r.URL.Query().Get("status"); v != "" {
+		for _, status := range strings.Split(v, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				query.Statuses = append(query.Statuses, status)
+			}
+		}
+	}
+
+	hits, err := h.search.Search(r.Context(), query)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "search index unavailable")
+		return
+	}
+
+	// The index trails the projections, so hits for runes shattered since
+	// they were indexed are dropped.
+	results := make([]SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		var summary projectors.RuneSummary
+		if err := h.projectionStore.Get(r.Context(), realmID, "rune_list", hit.ID, &summary); err != nil {
+			var nfe *core.NotFoundError
+			if errors.As(err, &nfe) {
+				continue
+			}
+			writeError(w, http.StatusInternalServerError, "failed to load rune")
+			return
+		}
+		results = append(results, SearchResult{RuneSummary: summary, Score: hit.Score})
+	}
+	writeJSON(w, http.StatusOK, results)
+}
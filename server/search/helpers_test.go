@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/devzeebo/bifrost/core"
+)
+
+// --- Helpers ---
+
+func makeEvent(eventType string, data any) core.Event {
+	dataBytes, _ := json.Marshal(data)
+	return core.Event{
+		RealmID:   "realm-1",
+		EventType: eventType,
+		Data:      dataBytes,
+	}
+}
+
+// --- Mock Search Index ---
+
+type mockSearchIndex struct {
+	docs map[string]core.SearchDocument
+}
+
+func newMockSearchIndex() *mockSearchIndex {
+	return &mockSearchIndex{docs: make(map[string]core.SearchDocument)}
+}
+
+func (m *mockSearchIndex) Index(_ context.Context, doc core.SearchDocument) error {
+	m.docs[doc.RealmID+"/"+doc.ID] = doc
+	return nil
+}
+
+func (m *mockSearchIndex) Delete(_ context.Context, realmID, runeID string) error {
+	delete(m.docs, realmID+"/"+runeID)
+	return nil
+}
+
+func (m *mockSearchIndex) Search(context.Context, core.SearchQuery) ([]core.SearchHit, error) {
+	return nil, nil
+}
+
+func (m *mockSearchIndex) Count(context.Context) (int, error) {
+	return len(m.docs), nil
+}
+
+func (m *mockSearchIndex) Close() error {
+	return nil
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	data map[string]any
+}
+
+func newMockProjectionStore() *mockProjectionStore {
+	return &mockProjectionStore{data: make(map[string]any)}
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, realmID string, projectionName string, key string, dest any) error {
+	val, ok := m.data[realmID+":"+projectionName+":"+key]
+	if !ok {
+		return &core.NotFoundError{Entity: projectionName, ID: key}
+	}
+	dataBytes, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dataBytes, dest)
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, realmID string, projectionName string, key string, value any) error {
+	m.data[realmID+":"+projectionName+":"+key] = value
+	return nil
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	prefix := realmID + ":" + projectionName + ":"
+	var result []json.RawMessage
+	for key, val := range m.data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		dataBytes, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dataBytes)
+	}
+	return result, nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, realmID string, projectionName string, key string) error {
+	delete(m.data, realmID+":"+projectionName+":"+key)
+	return nil
+}
+
+// --- Mock Checkpoint Store ---
+
+type mockCheckpointStore struct {
+	checkpoints map[string]int64
+}
+
+func newMockCheckpointStore() *mockCheckpointStore {
+	return &mockCheckpointStore{checkpoints: make(map[string]int64)}
+}
+
+func (m *mockCheckpointStore) GetCheckpoint(_ context.Context, realmID, projectorName string) (int64, error) {
+	return m.checkpoints[realmID+":"+projectorName], nil
+}
+
+func (m *mockCheckpointStore) SetCheckpoint(_ context.Context, realmID, projectorName string, globalPosition int64) error {
+	m.checkpoints[realmID+":"+projectorName] = globalPosition
+	return nil
+}
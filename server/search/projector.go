@@ -0,0 +1,129 @@
+// Package search keeps a core.SearchIndex in step with the runes of every
+// realm and answers full-text queries from it.
+//
+// The Projector is registered with the projection engine after the read
+// model projectors and indexes each rune from its rune_detail projection,
+// so the index always holds a rune's current text however many events
+// changed it.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// ProjectorName is the checkpoint name of the search projector.
+const ProjectorName = "search_index"
+
+// Projector feeds rune changes into a search index.
+type Projector struct {
+	index core.SearchIndex
+}
+
+func NewProjector(index core.SearchIndex) *Projector {
+	return &Projector{index: index}
+}
+
+func (p *Projector) Name() string {
+	return ProjectorName
+}
+
+// HasSideEffects keeps the projector out of command transactions, since the
+// index is outside the database and cannot be rolled back. It also keeps
+// projection replays from writing to the live index.
+func (p *Projector) HasSideEffects() bool {
+	return true
+}
+
+// Handle reindexes the rune an event changed, or removes a shattered rune.
+func (p *Projector) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	runeID, ok := runeIDOf(event)
+	if !ok {
+		return nil
+	}
+	if event.EventType == domain.EventRuneShattered {
+		return p.index.Delete(ctx, event.RealmID, runeID)
+	}
+
+	var detail projectors.RuneDetail
+	if err := store.Get(ctx, event.RealmID, "rune_detail", runeID, &detail); err != nil {
+		var nfe *core.NotFoundError
+		if errors.As(err, &nfe) {
+			// Shattered since; its RuneShattered event removes it
+			return nil
+		}
+		return err
+	}
+	return p.index.Index(ctx, documentFor(event.RealmID, detail))
+}
+
+func documentFor(realmID string, detail projectors.RuneDetail) core.SearchDocument {
+	notes := make([]string, 0, len(detail.Notes))
+	for _, note := range detail.Notes {
+		notes = append(notes, note.Text)
+	}
+	return core.SearchDocument{
+		RealmID:     realmID,
+		ID:          detail.ID,
+		Title:       detail.Title,
+		Description: detail.Description,
+		Notes:       notes,
+		Status:      detail.Status,
+	}
+}
+
+// runeIDOf returns the rune whose searchable text or status an event may
+// change. Notes name their rune as rune_id, every other rune event as id.
+func runeIDOf(event core.Event) (string, bool) {
+	switch event.EventType {
+	case domain.EventRuneCreated, domain.EventRuneUpdated, domain.EventRuneNoted,
+		domain.EventRuneClaimed, domain.EventRuneUnclaimed, domain.EventRuneFulfilled,
+		domain.EventRuneForged, domain.EventRuneSealed, domain.EventRuneShattered:
+	default:
+		return "", false
+	}
+	var data struct {
+		ID     string `json:"id"`
+		RuneID string `json:"rune_id"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return "", false
+	}
+	if data.RuneID != "" {
+		return data.RuneID, true
+	}
+	return data.ID, data.ID != ""
+}
+
+// ResetIfEmpty rewinds the projector in every realm when the index holds no
+// runes, such as after its directory was deleted, so catch-up rebuilds it
+// from the event history.
+func ResetIfEmpty(ctx context.Context, index core.SearchIndex, store core.ProjectionStore, checkpoints core.CheckpointStore) error {
+	n, err := index.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("count search index: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	raws, err := store.List(ctx, domain.AdminRealmID, "realm_list")
+	if err != nil {
+		return err
+	}
+	for _, raw := range raws {
+		var realm projectors.RealmListEntry
+		if err := json.Unmarshal(raw, &realm); err != nil || realm.RealmID == "" {
+			continue
+		}
+		if err := checkpoints.SetCheckpoint(ctx, realm.RealmID, ProjectorName, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
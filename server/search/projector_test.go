@@ -0,0 +1,181 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestProjector(t *testing.T) {
+	t.Run("indexes the rune's current detail", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.rune_detail_exists(projectors.RuneDetail{
+			ID: "bf-a1", Title: "Fix login", Description: "Redirect loop", Status: "claimed",
+			Notes: []projectors.NoteEntry{{Text: "seen on Safari"}},
+		})
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneClaimed, domain.RuneClaimed{ID: "bf-a1", Claimant: "alice"}))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, core.SearchDocument{
+			RealmID: "realm-1", ID: "bf-a1", Title: "Fix login", Description: "Redirect loop",
+			Notes: []string{"seen on Safari"}, Status: "claimed",
+		}, tc.index.docs["realm-1/bf-a1"])
+	})
+
+	t.Run("reindexes the noted rune", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.rune_detail_exists(projectors.RuneDetail{ID: "bf-a1", Title: "Fix login", Notes: []projectors.NoteEntry{{Text: "repro steps"}}})
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneNoted, domain.RuneNoted{RuneID: "bf-a1", Text: "repro steps"}))
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, []string{"repro steps"}, tc.index.docs["realm-1/bf-a1"].Notes)
+	})
+
+	t.Run("removes shattered runes", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.index.docs["realm-1/bf-a1"] = core.SearchDocument{RealmID: "realm-1", ID: "bf-a1"}
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneShattered, domain.RuneShattered{ID: "bf-a1"}))
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.index.docs)
+	})
+
+	t.Run("skips runes no longer projected", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// When
+		tc.handle(makeEvent(domain.EventRuneCreated, domain.RuneCreated{ID: "bf-gone", Title: "Gone"}))
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.index.docs)
+	})
+
+	t.Run("ignores events that do not change rune text or status", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.rune_detail_exists(projectors.RuneDetail{ID: "bf-a1", Title: "Fix login"})
+
+		// When
+		tc.handle(makeEvent(domain.EventDependencyAdded, domain.DependencyAdded{RuneID: "bf-a1", TargetID: "bf-a2"}))
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.index.docs)
+	})
+
+	t.Run("has side effects", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Then
+		assert.True(t, tc.projector.HasSideEffects())
+		assert.Equal(t, "search_index", tc.projector.Name())
+	})
+}
+
+func TestResetIfEmpty(t *testing.T) {
+	t.Run("rewinds every realm when the index is empty", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.realm_exists("bf-r1")
+		tc.checkpoints.checkpoints["bf-r1:search_index"] = 42
+
+		// When
+		tc.err = ResetIfEmpty(context.Background(), tc.index, tc.store, tc.checkpoints)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, int64(0), tc.checkpoints.checkpoints["bf-r1:search_index"])
+	})
+
+	t.Run("keeps checkpoints when the index has runes", func(t *testing.T) {
+		tc := newProjectorTestContext(t)
+
+		// Given
+		tc.realm_exists("bf-r1")
+		tc.checkpoints.checkpoints["bf-r1:search_index"] = 42
+		tc.index.docs["bf-r1/bf-a1"] = core.SearchDocument{RealmID: "bf-r1", ID: "bf-a1"}
+
+		// When
+		tc.err = ResetIfEmpty(context.Background(), tc.index, tc.store, tc.checkpoints)
+
+		// Then
+		tc.no_error()
+		assert.Equal(t, int64(42), tc.checkpoints.checkpoints["bf-r1:search_index"])
+	})
+}
+
+// --- Test Context ---
+
+type projectorTestContext struct {
+	t *testing.T
+
+	index       *mockSearchIndex
+	store       *mockProjectionStore
+	checkpoints *mockCheckpointStore
+	projector   *Projector
+
+	err error
+}
+
+func newProjectorTestContext(t *testing.T) *projectorTestContext {
+	t.Helper()
+	index := newMockSearchIndex()
+	return &projectorTestContext{
+		t:           t,
+		index:       index,
+		store:       newMockProjectionStore(),
+		checkpoints: newMockCheckpointStore(),
+		projector:   NewProjector(index),
+	}
+}
+
+// --- Given ---
+
+func (tc *projectorTestContext) rune_detail_exists(detail projectors.RuneDetail) {
+	tc.t.Helper()
+	_ = tc.store.Put(context.Background(), "realm-1", "rune_detail", detail.ID, detail)
+}
+
+func (tc *projectorTestContext) realm_exists(realmID string) {
+	tc.t.Helper()
+	_ = tc.store.Put(context.Background(), domain.AdminRealmID, "realm_list", realmID, projectors.RealmListEntry{RealmID: realmID})
+}
+
+// --- When ---
+
+func (tc *projectorTestContext) handle(event core.Event) {
+	tc.t.Helper()
+	tc.err = tc.projector.Handle(context.Background(), event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *projectorTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestSearchHandler(t *testing.T) {
+	t.Run("returns matching runes with their scores, best first", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		index := tc.a_search_index(core.SearchHit{ID: "bf-a2", Score: 2}, core.SearchHit{ID: "bf-a1", Score: 1})
+		tc.projection_has_rune_summary("bf-r1", "bf-a1", "open")
+		tc.projection_has_rune_summary("bf-r1", "bf-a2", "claimed")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=login+bug&status=open,claimed&limit=10")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`[{"id":"bf-a2","title":"","status":"claimed"`)
+		tc.response_body_contains(`"score":2}`)
+		assert.Equal(t, core.SearchQuery{RealmID: "bf-r1", Text: "login bug", Statuses: []string{"open", "claimed"}, Limit: 10}, index.query)
+	})
+
+	t.Run("drops hits for runes no longer projected", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		tc.a_search_index(core.SearchHit{ID: "bf-gone", Score: 1})
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=anything")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_is_empty_json_array()
+	})
+
+	t.Run("requires a query", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		tc.a_search_index()
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=+")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("rejects a limit over the maximum", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		tc.a_search_index()
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=login&limit=1000")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("limit must be between 1 and 200")
+	})
+
+	t.Run("reports search as not enabled without an index", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=login")
+
+		// Then
+		tc.status_is(http.StatusNotImplemented)
+	})
+
+	t.Run("returns 502 when the index fails", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("bf-r1")
+		tc.a_search_index().err = errors.New("connection refused")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/search?q=login")
+
+		// Then
+		tc.status_is(http.StatusBadGateway)
+	})
+}
+
+// --- Test Context ---
+
+type stubSearchIndex struct {
+	hits  []core.SearchHit
+	err   error
+	query core.SearchQuery
+}
+
+func (s *stubSearchIndex) Index(context.Context, core.SearchDocument) error { return nil }
+func (s *stubSearchIndex) Delete(context.Context, string, string) error     { return nil }
+func (s *stubSearchIndex) Count(context.Context) (int, error)               { return len(s.hits), nil }
+func (s *stubSearchIndex) Close() error                                     { return nil }
+
+func (s *stubSearchIndex) Search(_ context.Context, q core.SearchQuery) ([]core.SearchHit, error) {
+	s.query = q
+	return s.hits, s.err
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_search_index(hits ...core.SearchHit) *stubSearchIndex {
+	tc.t.Helper()
+	index := &stubSearchIndex{hits: hits}
+	tc.search = index
+	return index
+}
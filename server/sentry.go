@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// sentryEvent is the subset of Sentry's event payload that a panic fills in.
+type sentryEvent struct {
+	EventID   string            `json:"event_id"`
+	Timestamp string            `json:"timestamp"`
+	Level     string            `json:"level"`
+	Platform  string            `json:"platform"`
+	Logger    string            `json:"logger"`
+	Message   string            `json:"message"`
+	Request   sentryRequest     `json:"request"`
+	Tags      map[string]string `json:"tags"`
+	Extra     map[string]string `json:"extra"`
+}
+
+type sentryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// NewSentryReporter returns a PanicReporter that sends each panic to the
+// store endpoint of a Sentry-compatible service (Sentry, GlitchTip, ...).
+// dsn has the form "https://<key>@<host>/<project>". Reports are sent in the
+// background; failures are logged.
+func NewSentryReporter(dsn string, client *http.Client) (PanicReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse sentry DSN: %w", err)
+	}
+	key := u.User.Username()
+	prefix, project := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if u.Scheme == "" || u.Host == "" || key == "" || project == "" {
+		return nil, fmt.Errorf("sentry DSN must look like https://<key>@<host>/<project>")
+	}
+	storeURL := fmt.Sprintf("%s://%s%sapi/%s/store/", u.Scheme, u.Host, prefix, project)
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=bifrost/1.0, sentry_key=%s", key)
+
+	return func(ctx context.Context, report PanicReport) {
+		body, err := json.Marshal(sentryEvent{
+			EventID:   report.CorrelationID,
+			Timestamp: report.Time.Format("2006-01-02T15:04:05Z"),
+			Level:     "fatal",
+			Platform:  "go",
+			Logger:    "bifrost",
+			Message:   fmt.Sprintf("panic: %v", report.Value),
+			Request:   sentryRequest{Method: report.Method, URL: report.URL},
+			Tags:      map[string]string{"correlation_id": report.CorrelationID},
+			Extra:     map[string]string{"stack": string(report.Stack)},
+		})
+		if err != nil {
+			log.Printf("sentry: encode report %s: %v", report.CorrelationID, err)
+			return
+		}
+		go func() {
+			req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, storeURL, bytes.NewReader(body))
+			if err != nil {
+				log.Printf("sentry: report %s: %v", report.CorrelationID, err)
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Sentry-Auth", auth)
+			res, err := client.Do(req)
+			if err != nil {
+				log.Printf("sentry: report %s: %v", report.CorrelationID, err)
+				return
+			}
+			defer res.Body.Close()
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+			if res.StatusCode >= 300 {
+				log.Printf("sentry: report %s: unexpected status %d", report.CorrelationID, res.StatusCode)
+			}
+		}()
+	}, nil
+}
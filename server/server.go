@@ -0,0 +1,1059 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/providers/blobstore"
+	"github.com/devzeebo/bifrost/providers/memory"
+	"github.com/devzeebo/bifrost/providers/mysql"
+	"github.com/devzeebo/bifrost/providers/postgres"
+	"github.com/devzeebo/bifrost/providers/redis"
+	"github.com/devzeebo/bifrost/providers/searchindex"
+	"github.com/devzeebo/bifrost/providers/sqlite"
+	"github.com/devzeebo/bifrost/server/admin"
+	"github.com/devzeebo/bifrost/server/automation"
+	"github.com/devzeebo/bifrost/server/backup"
+	"github.com/devzeebo/bifrost/server/debug"
+	"github.com/devzeebo/bifrost/server/directory"
+	"github.com/devzeebo/bifrost/server/integrations"
+	"github.com/devzeebo/bifrost/server/metrics"
+	"github.com/devzeebo/bifrost/server/notify"
+	"github.com/devzeebo/bifrost/server/outbox"
+	"github.com/devzeebo/bifrost/server/search"
+	"github.com/devzeebo/bifrost/server/webhooks"
+)
+
+// Server is a Bifrost instance that can be embedded in another binary: its
+// stores, projection engine, background workers and HTTP routes. Build one
+// with New, call Start to begin processing and Stop to shut it down.
+type Server struct {
+	cfg *Config
+
+	db              *sql.DB
+	ownsDB          bool
+	storeDB         *sql.DB // MySQL database of the event, projection and checkpoint stores
+	replicaDB       *sql.DB // Read replica of the MySQL or PostgreSQL stores serving queries
+	shards          *sqlite.Shards
+	redis           *redis.Client // Holds the projections when BIFROST_REDIS_URL is set
+	postgresDB      *sql.DB       // PostgreSQL database of the projection or checkpoint store
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	bus             *core.EventBus
+	engine          serverEngine
+	handlers        *Handlers
+	handler         http.Handler
+	listen          bool
+	directoryCfg    *directory.Config
+	backups         *backup.Scheduler
+	publisher       *outbox.Dispatcher
+	search          core.SearchIndex
+	archiver        core.EventArchiver
+	truncator       core.EventTruncator
+	checkpoints     core.CheckpointStore
+	// Projectors that act outside Bifrost, which a restore skips past
+	outward []core.Projector
+
+	stopBackground context.CancelFunc
+	workers        sync.WaitGroup
+	httpServer     *http.Server
+	debugServer    *http.Server
+	serveErr       chan error
+}
+
+// serverEngine is the projection engine as the server drives it.
+type serverEngine interface {
+	core.ProjectionEngine
+	EnsureIndexes(ctx context.Context) error
+	RebuildOutdated(ctx context.Context) ([]string, error)
+	Shutdown(ctx context.Context) error
+}
+
+// Option configures a Server built by New.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	cfg             *Config
+	db              *sql.DB
+	eventStore      core.EventStore
+	projectionStore core.ProjectionStore
+	checkpointStore core.CheckpointStore
+	projectors      []core.Projector
+	eventStoreHooks []core.EventStoreHooks
+	mux             *http.ServeMux
+	handlerOpts     []HandlersOption
+}
+
+// WithConfig sets the server's configuration. Without it New reads the
+// configuration from the environment with LoadConfig.
+func WithConfig(cfg *Config) Option {
+	return func(o *serverOptions) {
+		o.cfg = cfg
+	}
+}
+
+// WithDB uses an open database instead of the one named by the config. The
+// caller keeps ownership: Stop does not close it.
+func WithDB(db *sql.DB) Option {
+	return func(o *serverOptions) {
+		o.db = db
+	}
+}
+
+// WithStores replaces the event, projection and checkpoint stores the server
+// would otherwise create in its database. The database still holds form
+// drafts, queued commands and leases, and rune commands are no longer
+// appended and projected in one transaction.
+func WithStores(events core.EventStore, projections core.ProjectionStore, checkpoints core.CheckpointStore) Option {
+	return func(o *serverOptions) {
+		o.eventStore = events
+		o.projectionStore = projections
+		o.checkpointStore = checkpoints
+	}
+}
+
+// WithProjectors registers projectors alongside the built-in ones. They run
+// after the built-in projectors and before notifications and webhooks.
+func WithProjectors(p ...core.Projector) Option {
+	return func(o *serverOptions) {
+		o.projectors = append(o.projectors, p...)
+	}
+}
+
+// WithEventStoreHooks reports every call to the server's event store to
+// hooks, such as Prometheus metrics or logging, including the calls rune
+// commands make inside their transactions.
+func WithEventStoreHooks(hooks ...core.EventStoreHooks) Option {
+	return func(o *serverOptions) {
+		o.eventStoreHooks = append(o.eventStoreHooks, hooks...)
+	}
+}
+
+// WithMux registers the server's routes on mux, next to the embedder's own,
+// instead of on a mux of its own. The server then does not listen on the
+// configured port: serve Handler, which wraps mux with panic recovery and
+// request limits.
+func WithMux(mux *http.ServeMux) Option {
+	return func(o *serverOptions) {
+		o.mux = mux
+	}
+}
+
+// WithHandlerOptions passes options to the server's HTTP handlers, e.g.
+// WithCommandMiddleware.
+func WithHandlerOptions(opts ...HandlersOption) Option {
+	return func(o *serverOptions) {
+		o.handlerOpts = append(o.handlerOpts, opts...)
+	}
+}
+
+// New builds a server. Nothing runs until Start is called.
+func New(opts ...Option) (*Server, error) {
+	o := &serverOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	cfg := o.cfg
+	if cfg == nil {
+		var err error
+		if cfg, err = LoadConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Server{cfg: cfg, db: o.db, listen: o.mux == nil, bus: core.NewEventBus()}
+	if err := s.build(o); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) build(o *serverOptions) error {
+	cfg := s.cfg
+
+	// 1. Open DB
+	if s.db == nil {
+		switch cfg.DBDriver {
+		case "sqlite", "mysql":
+			db, err := sqlite.Open(cfg.DBPath, cfg.sqliteTuning())
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			s.db, s.ownsDB = db, true
+		case "sqlite-sharded":
+			// BIFROST_DB_PATH is a directory holding a database per realm
+			// under realms/, and bifrost.db for everything else.
+			if err := os.MkdirAll(cfg.DBPath, 0o755); err != nil {
+				return fmt.Errorf("create database directory: %w", err)
+			}
+			db, err := sqlite.Open(filepath.Join(cfg.DBPath, "bifrost.db"), cfg.sqliteTuning())
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			s.db, s.ownsDB = db, true
+		case "memory":
+			// Events and projections are kept in memory. Drafts, sync
+			// receipts, the command queue and leases still use SQL, so they
+			// get an in-memory SQLite database. It lives as long as its one
+			// connection, since each connection to ":memory:" opens a
+			// database of its own, so the pool settings do not apply.
+			db, err := sql.Open("sqlite", ":memory:")
+			if err != nil {
+				return fmt.Errorf("open database: %w", err)
+			}
+			db.SetMaxOpenConns(1)
+			db.SetMaxIdleConns(1)
+			db.SetConnMaxLifetime(0)
+			s.db, s.ownsDB = db, true
+		default:
+			return fmt.Errorf("unsupported DB driver: %q", cfg.DBDriver)
+		}
+	}
+	// With MySQL, events, projections and checkpoints live in MySQL and the
+	// SQLite database keeps drafts, sync receipts, the command queue and
+	// leases. The pool settings apply to the database holding the events.
+	pool := s.db
+	if !s.ownsDB || cfg.DBDriver == "memory" {
+		pool = nil
+	}
+	if cfg.DBDriver == "mysql" && o.eventStore == nil {
+		db, err := mysql.Open(cfg.DBDSN)
+		if err != nil {
+			return fmt.Errorf("open mysql database: %w", err)
+		}
+		s.storeDB, pool = db, db
+	}
+	if cfg.DBDriver == "sqlite-sharded" && o.eventStore == nil {
+		shards, err := sqlite.NewShards(filepath.Join(cfg.DBPath, "realms"), cfg.DBMaxOpenShards, cfg.sqliteTuning(),
+			sqlite.WithCompressionThreshold(cfg.EventCompressionThreshold))
+		if err != nil {
+			return fmt.Errorf("open realm databases: %w", err)
+		}
+		s.shards = shards
+	}
+	if pool != nil {
+		cfg.sizePool(pool)
+	}
+
+	// 2. Create stores
+	engineOpts := []core.EngineOption{
+		core.WithPollInterval(cfg.CatchUpInterval),
+		core.WithBatchSize(cfg.CatchUpBatchSize),
+		core.WithWorkers(cfg.CatchUpWorkers),
+		core.WithEventBus(s.bus),
+	}
+	checkpointStore := o.checkpointStore
+	var sqlOutbox *sqlite.Outbox
+	// Stores of a read replica, serving the query endpoints when set.
+	var readProjections core.ProjectionStore
+	var readCheckpoints core.CheckpointStore
+	// The projection store before any cache, for backups to dump.
+	var rawProjectionStore core.ProjectionStore
+	// Encryption sits directly on the provider's stores, under the debug
+	// instrumentation, so everything above it sees plain events.
+	var encryption *core.RealmEncryption
+	if len(cfg.EncryptedRealms) > 0 {
+		wrapper, err := core.NewAESKeyWrapper(cfg.EventEncryptionKey)
+		if err != nil {
+			return fmt.Errorf("create event encryption: %w", err)
+		}
+		encryption = core.NewRealmEncryption(wrapper, cfg.EncryptedRealms...)
+	}
+	if o.eventStore != nil {
+		if o.projectionStore == nil || o.checkpointStore == nil {
+			return fmt.Errorf("WithStores requires event, projection and checkpoint stores")
+		}
+		s.eventStore, s.projectionStore = o.eventStore, o.projectionStore
+		rawProjectionStore = o.projectionStore
+	} else if cfg.DBDriver == "memory" {
+		s.eventStore = memory.NewEventStore()
+		if encryption != nil {
+			s.eventStore = encryption.EventStore(s.eventStore)
+		}
+		if cfg.DebugAddr != "" {
+			s.eventStore = debug.InstrumentEventStore(s.eventStore)
+		}
+		s.projectionStore = memory.NewProjectionStore()
+		rawProjectionStore = s.projectionStore
+		checkpointStore = memory.NewCheckpointStore()
+	} else if s.shards != nil {
+		// A unit of work cannot span realm databases, so projections catch
+		// up after commands rather than in their transaction.
+		shardedEventStore := s.shards.EventStore()
+		s.eventStore = shardedEventStore
+		s.archiver = shardedEventStore
+		s.truncator = shardedEventStore
+		if encryption != nil {
+			s.eventStore = encryption.EventStore(s.eventStore)
+		}
+		if cfg.DebugAddr != "" {
+			s.eventStore = debug.InstrumentEventStore(s.eventStore)
+		}
+
+		shardedProjectionStore := s.shards.ProjectionStore()
+		s.projectionStore = shardedProjectionStore
+		rawProjectionStore = shardedProjectionStore
+		if cfg.ProjectionCacheSize > 0 && cfg.CatchUpLeaseTTL == 0 {
+			s.projectionStore = core.NewCachedProjectionStore(shardedProjectionStore, cfg.ProjectionCacheSize)
+		}
+		checkpointStore = s.shards.CheckpointStore()
+		// Projector versions are not per realm, so they are kept in
+		// bifrost.db rather than in a realm's database.
+		versions, err := sqlite.NewCheckpointStore(s.db)
+		if err != nil {
+			return fmt.Errorf("create projector version store: %w", err)
+		}
+		engineOpts = append(engineOpts, core.WithProjectorVersions(versions))
+	} else if cfg.DBDriver == "mysql" {
+		// There is no MySQL transactor, so projections catch up after
+		// commands rather than in their transaction.
+		mysqlEventStore, err := mysql.NewEventStore(s.storeDB)
+		if err != nil {
+			return fmt.Errorf("create event store: %w", err)
+		}
+		s.eventStore = mysqlEventStore
+		if encryption != nil {
+			s.eventStore = encryption.EventStore(s.eventStore)
+		}
+		if cfg.DebugAddr != "" {
+			s.eventStore = debug.InstrumentEventStore(s.eventStore)
+		}
+
+		mysqlProjectionStore, err := mysql.NewProjectionStore(s.storeDB)
+		if err != nil {
+			return fmt.Errorf("create projection store: %w", err)
+		}
+		s.projectionStore = mysqlProjectionStore
+		rawProjectionStore = mysqlProjectionStore
+		if cfg.ProjectionCacheSize > 0 && cfg.CatchUpLeaseTTL == 0 {
+			s.projectionStore = core.NewCachedProjectionStore(mysqlProjectionStore, cfg.ProjectionCacheSize)
+		}
+
+		if checkpointStore, err = mysql.NewCheckpointStore(s.storeDB); err != nil {
+			return fmt.Errorf("create checkpoint store: %w", err)
+		}
+
+		if cfg.DBReadDSN != "" {
+			db, err := mysql.Open(cfg.DBReadDSN)
+			if err != nil {
+				return fmt.Errorf("open mysql read replica: %w", err)
+			}
+			cfg.sizePool(db)
+			s.replicaDB = db
+			readProjections, readCheckpoints = mysql.NewReplicaStores(db)
+		}
+	} else {
+		// With an outbox, every append records its events for publishing in
+		// the same transaction.
+		storeOpts := []sqlite.EventStoreOption{sqlite.WithCompressionThreshold(cfg.EventCompressionThreshold)}
+		if cfg.Outbox.Enabled() {
+			storeOpts = append(storeOpts, sqlite.WithOutbox())
+			var err error
+			if sqlOutbox, err = sqlite.NewOutbox(s.db); err != nil {
+				return fmt.Errorf("create outbox: %w", err)
+			}
+		}
+		sqlEventStore, err := sqlite.NewEventStore(s.db, storeOpts...)
+		if err != nil {
+			return fmt.Errorf("create event store: %w", err)
+		}
+		s.eventStore = sqlEventStore
+		s.archiver = sqlEventStore
+		s.truncator = sqlEventStore
+		if encryption != nil {
+			s.eventStore = encryption.EventStore(s.eventStore)
+		}
+		if cfg.DebugAddr != "" {
+			s.eventStore = debug.InstrumentEventStore(s.eventStore)
+		}
+
+		sqlProjectionStore, err := sqlite.NewProjectionStore(s.db)
+		if err != nil {
+			return fmt.Errorf("create projection store: %w", err)
+		}
+		// Every projection write in this process goes through the same
+		// store, so the cache is invalidated as projectors update it. With
+		// catch-up leases another instance may be the writer, so nothing is
+		// cached.
+		s.projectionStore = sqlProjectionStore
+		rawProjectionStore = sqlProjectionStore
+		if cfg.ProjectionCacheSize > 0 && cfg.CatchUpLeaseTTL == 0 {
+			s.projectionStore = core.NewCachedProjectionStore(sqlProjectionStore, cfg.ProjectionCacheSize)
+		}
+
+		if checkpointStore, err = sqlite.NewCheckpointStore(s.db); err != nil {
+			return fmt.Errorf("create checkpoint store: %w", err)
+		}
+
+		// Rune commands append and project their events in one transaction,
+		// so reads never see events whose projections are missing. With
+		// projections or checkpoints kept elsewhere no transaction spans
+		// them, so they catch up after commands instead.
+		if !cfg.projectionsApart() {
+			sqlTransactor, err := sqlite.NewTransactor(s.db, storeOpts...)
+			if err != nil {
+				return fmt.Errorf("create transactor: %w", err)
+			}
+			var transactor core.Transactor = sqlTransactor
+			if encryption != nil {
+				transactor = encryption.Transactor(sqlTransactor)
+			}
+			if len(o.eventStoreHooks) > 0 {
+				transactor = core.InstrumentTransactor(transactor, o.eventStoreHooks...)
+			}
+			transactor = s.bus.Transactor(transactor)
+			engineOpts = append(engineOpts, core.WithTransactor(transactor))
+			if len(cfg.CriticalProjectors) > 0 {
+				engineOpts = append(engineOpts, core.WithCriticalProjectors(cfg.CriticalProjectors...))
+			}
+		}
+	}
+	if o.eventStore == nil && cfg.projectionsApart() {
+		projections, checkpoints, err := s.openApartStores()
+		if err != nil {
+			return err
+		}
+		if projections != nil {
+			// Redis serves reads from memory already, so only SQL stores
+			// are cached.
+			s.projectionStore, rawProjectionStore = projections, projections
+			if cfg.RedisURL == "" && cfg.ProjectionCacheSize > 0 && cfg.CatchUpLeaseTTL == 0 {
+				s.projectionStore = core.NewCachedProjectionStore(projections, cfg.ProjectionCacheSize)
+			}
+		}
+		if checkpoints != nil {
+			checkpointStore = checkpoints
+		}
+		if cfg.PostgresReadDSN != "" {
+			db, err := postgres.Open(cfg.PostgresReadDSN)
+			if err != nil {
+				return fmt.Errorf("open postgres read replica: %w", err)
+			}
+			cfg.sizePool(db)
+			s.replicaDB = db
+			readProjections, readCheckpoints = postgres.NewReplicaStores(db)
+		}
+	}
+	if len(o.eventStoreHooks) > 0 {
+		s.eventStore = core.InstrumentEventStore(s.eventStore, o.eventStoreHooks...)
+	}
+	// The bus sits above every other wrapper, so subscribers see plain
+	// events and only those that were stored.
+	s.eventStore = s.bus.EventStore(s.eventStore)
+	eventStore, projectionStore := s.eventStore, s.projectionStore
+
+	// 3. Create projection engine and register projectors
+	if cfg.CatchUpLeaseTTL > 0 {
+		leaseStore, err := sqlite.NewLeaseStore(s.db)
+		if err != nil {
+			return fmt.Errorf("create lease store: %w", err)
+		}
+		engineOpts = append(engineOpts, core.WithLease(leaseStore, cfg.NodeID, cfg.CatchUpLeaseTTL))
+	}
+	deadLetters, err := sqlite.NewDeadLetterStore(s.db)
+	if err != nil {
+		return fmt.Errorf("create dead letter store: %w", err)
+	}
+	engineOpts = append(engineOpts, core.WithErrorPolicy(cfg.ProjectorErrorPolicy), core.WithDeadLetters(deadLetters))
+	for name, policy := range cfg.ProjectorErrorPolicies {
+		engineOpts = append(engineOpts, core.WithProjectorErrorPolicy(name, policy))
+	}
+	engine := core.NewProjectionEngine(eventStore, projectionStore, checkpointStore, engineOpts...)
+	s.engine = engine
+	s.checkpoints = checkpointStore
+
+	// The read model projectors, which a projection replay also runs
+	readModels := []core.Projector{
+		projectors.NewRealmListProjector(),
+		projectors.NewRuneListProjector(),
+		projectors.NewRuneDetailProjector(),
+		projectors.NewDependencyGraphProjector(),
+		projectors.NewAccountLookupProjector(),
+		projectors.NewAccountListProjector(),
+		projectors.NewAdminTokensProjector(),
+		projectors.NewClaimsByAccountProjector(),
+		projectors.NewRuneChildCountProjector(),
+		projectors.NewRuneChildrenProjector(),
+		projectors.NewRealmSettingsProjector(),
+		projectors.NewNotificationPreferencesProjector(),
+		projectors.NewWebhookListProjector(),
+		projectors.NewAutomationRulesProjector(),
+		projectors.NewEscalationPoliciesProjector(),
+		projectors.NewSchedulesProjector(),
+		projectors.NewSavedSearchesProjector(),
+		projectors.NewDailyStatsProjector(),
+		projectors.NewRuneTransitionsProjector(),
+		projectors.NewDashboardStatsProjector(),
+	}
+	readModels = append(readModels, o.projectors...)
+	for _, p := range readModels {
+		engine.Register(p)
+	}
+	for _, name := range cfg.CriticalProjectors {
+		if !slices.ContainsFunc(readModels, func(p core.Projector) bool { return p.Name() == name }) {
+			return fmt.Errorf("BIFROST_CRITICAL_PROJECTORS: unknown projector %q", name)
+		}
+	}
+
+	// Notifications run after the projectors so rune details are current
+	notifyClient := &http.Client{Timeout: 10 * time.Second}
+	notifier := notify.NewRouter(projectionStore, []notify.Channel{
+		notify.NewSlackChannel(notifyClient),
+		notify.NewDiscordChannel(notifyClient),
+	})
+	webhookDispatcher := webhooks.NewDispatcher(notifyClient)
+	s.outward = []core.Projector{notifier, webhookDispatcher, automation.NewReactor(eventStore)}
+	for _, p := range s.outward {
+		engine.Register(p)
+	}
+	registered := slices.Concat(readModels, s.outward)
+
+	// The search index is fed from rune details like notifications are
+	if cfg.Search.Enabled() {
+		index, err := newSearchIndex(cfg.Search)
+		if err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+		s.search = index
+		if err := search.ResetIfEmpty(context.Background(), s.search, projectionStore, checkpointStore); err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+		searchProjector := search.NewProjector(s.search)
+		engine.Register(searchProjector)
+		registered = append(registered, searchProjector)
+	}
+	for name := range cfg.ProjectorErrorPolicies {
+		if !slices.ContainsFunc(registered, func(p core.Projector) bool { return p.Name() == name }) {
+			return fmt.Errorf("BIFROST_PROJECTOR_ERROR_POLICIES: unknown projector %q", name)
+		}
+	}
+
+	if cfg.DirectoryFile != "" {
+		directoryCfg, err := directory.LoadConfig(cfg.DirectoryFile)
+		if err != nil {
+			return fmt.Errorf("directory sync: %w", err)
+		}
+		s.directoryCfg = directoryCfg
+	}
+
+	// 4. Set up admin auth config (used by both API and UI routes)
+	adminAuthConfig := admin.DefaultAuthConfig()
+	if keyStr := os.Getenv("ADMIN_JWT_SIGNING_KEY"); keyStr != "" {
+		key, err := base64.RawURLEncoding.DecodeString(keyStr)
+		if err != nil {
+			return fmt.Errorf("decode ADMIN_JWT_SIGNING_KEY: %w", err)
+		}
+		adminAuthConfig.SigningKey = key
+	} else {
+		// Generate a temporary key for development (will change on restart)
+		log.Println("Warning: ADMIN_JWT_SIGNING_KEY not set, generating temporary key (sessions will invalidate on restart)")
+		key, err := admin.GenerateSigningKey()
+		if err != nil {
+			return fmt.Errorf("generate signing key: %w", err)
+		}
+		adminAuthConfig.SigningKey = key
+	}
+
+	// Disable secure cookies for local development
+	adminAuthConfig.CookieSecure = false
+
+	// 5. Set up HTTP routes with auth middleware
+	mux := o.mux
+	if mux == nil {
+		mux = http.NewServeMux()
+	}
+	auth := AuthMiddleware(projectionStore, &AuthConfig{AdminAuthConfig: adminAuthConfig})
+	realmAuth := func(h http.Handler) http.Handler { return auth(RequireRealm(h)) }
+	adminAuth := func(h http.Handler) http.Handler { return auth(RequireAdmin(h)) }
+
+	githubImporter := integrations.NewGitHubImporter(&integrations.RouteConfig{
+		EventStore:      eventStore,
+		ProjectionStore: projectionStore,
+		Engine:          engine,
+	})
+
+	handlerOpts := []HandlersOption{
+		WithNotifier(notifier),
+		WithGitHubImporter(githubImporter),
+		WithCheckpointStore(checkpointStore),
+		WithWebhookSender(webhookDispatcher),
+		WithReplayProjectors(readModels),
+		WithProjectionStatus(engine),
+		WithDeadLetterStore(deadLetters),
+		WithProjectionRebuilder(engine),
+	}
+	draftStore, err := sqlite.NewDraftStore(s.db)
+	if err != nil {
+		return fmt.Errorf("create draft store: %w", err)
+	}
+	handlerOpts = append(handlerOpts, WithDraftStore(draftStore))
+	syncReceipts, err := sqlite.NewSyncReceiptStore(s.db)
+	if err != nil {
+		return fmt.Errorf("create sync receipt store: %w", err)
+	}
+	handlerOpts = append(handlerOpts, WithSyncReceiptStore(syncReceipts))
+	if readProjections != nil {
+		handlerOpts = append(handlerOpts, WithReadReplica(readProjections, readCheckpoints))
+	}
+	if cfg.CommandQueueSize > 0 {
+		commandQueue, err := sqlite.NewCommandQueue(s.db)
+		if err != nil {
+			return fmt.Errorf("create command queue: %w", err)
+		}
+		handlerOpts = append(handlerOpts, WithCommandQueue(commandQueue, cfg.CommandQueueSize))
+	}
+	if cfg.Backup.Enabled() {
+		if s.backups, err = newBackupScheduler(cfg.Backup, eventStore, rawProjectionStore, checkpointStore); err != nil {
+			return fmt.Errorf("backups: %w", err)
+		}
+		handlerOpts = append(handlerOpts, WithBackupStatus(s.backups))
+	}
+	if sqlOutbox != nil {
+		if s.publisher, err = newOutboxDispatcher(cfg.Outbox, sqlOutbox, eventStore); err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+	}
+	if s.search != nil {
+		handlerOpts = append(handlerOpts, WithSearchIndex(s.search))
+	}
+	handlerOpts = append(handlerOpts, o.handlerOpts...)
+	s.handlers = NewHandlers(eventStore, projectionStore, engine, handlerOpts...)
+	s.handlers.RegisterRoutes(mux, realmAuth, adminAuth)
+
+	// Workflow health metrics for Prometheus (optionally token-protected)
+	collectors := []metrics.Collector{
+		metrics.NewDomainCollector(projectionStore, metrics.WithStaleClaimAge(cfg.StaleClaimAge)),
+		webhookDispatcher,
+		s.handlers,
+	}
+	if s.backups != nil {
+		collectors = append(collectors, s.backups)
+	}
+	mux.Handle("GET /metrics", metrics.Handler(cfg.MetricsToken, collectors...))
+
+	// Register third-party webhook receivers (authenticated by signature)
+	integrations.RegisterRoutes(mux, &integrations.RouteConfig{
+		EventStore:      eventStore,
+		ProjectionStore: projectionStore,
+		Engine:          engine,
+	})
+
+	// Register admin UI routes
+	result, err := admin.RegisterRoutes(mux, &admin.RouteConfig{
+		AuthConfig:       adminAuthConfig,
+		ProjectionStore:  projectionStore,
+		EventStore:       eventStore,
+		Engine:           engine,
+		StaticPath:       cfg.AdminUIStaticPath,
+		ViteDevServerURL: cfg.ViteDevServerURL,
+		HostRealm:        HostRealmFromContext,
+	})
+	if err != nil {
+		return fmt.Errorf("register admin routes: %w", err)
+	}
+
+	// Use the wrapped handler (may include Vike proxy), recovering panics
+	// from every route
+	panicReporter := cfg.PanicReporter
+	if panicReporter == nil && cfg.SentryDSN != "" {
+		if panicReporter, err = NewSentryReporter(cfg.SentryDSN, &http.Client{Timeout: 10 * time.Second}); err != nil {
+			return fmt.Errorf("BIFROST_SENTRY_DSN: %w", err)
+		}
+	}
+	hostRealm := HostRealm(&RealmHosts{Hosts: cfg.RealmHosts, Domain: cfg.RealmDomain}, projectionStore)
+	s.handler = Recover(panicReporter)(LimitRequests(cfg.MaxBodyBytes, cfg.RouteLimits)(hostRealm(result.Handler)))
+	return nil
+}
+
+// newSearchIndex opens the search index the config selects.
+func newSearchIndex(cfg SearchConfig) (core.SearchIndex, error) {
+	if cfg.Provider == "elasticsearch" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return searchindex.NewElasticsearch(ctx, searchindex.ElasticsearchConfig{
+			URL:      cfg.URL,
+			Index:    cfg.Index,
+			APIKey:   cfg.APIKey,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}, &http.Client{Timeout: 10 * time.Second})
+	}
+	return searchindex.OpenBleve(cfg.Dir)
+}
+
+// newBackupScheduler creates the scheduler for the backup destination in
+// cfg: an S3-compatible bucket when one is set, otherwise a directory.
+// Projections are backed up too when cfg asks and the stores can list
+// their contents.
+func newBackupScheduler(cfg BackupConfig, events core.EventStore, projections core.ProjectionStore, checkpoints core.CheckpointStore) (*backup.Scheduler, error) {
+	var opts []backup.SchedulerOption
+	if cfg.Projections {
+		dumper, canDump := projections.(core.ProjectionDumper)
+		lister, canList := checkpoints.(core.CheckpointLister)
+		if !canDump || !canList {
+			return nil, fmt.Errorf("the projection and checkpoint stores cannot be backed up; BIFROST_BACKUP_PROJECTIONS needs the sqlite or memory driver")
+		}
+		opts = append(opts, backup.WithProjections(dumper, lister))
+	}
+	var dest backup.Destination
+	if cfg.S3Bucket != "" {
+		store, err := blobstore.NewS3(blobstore.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		}, &http.Client{Timeout: 10 * time.Minute})
+		if err != nil {
+			return nil, err
+		}
+		dest = backup.NewBlobDestination(store, cfg.S3Prefix, "s3://"+cfg.S3Bucket+"/"+cfg.S3Prefix)
+	} else {
+		dir, err := backup.NewDirDestination(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		dest = dir
+	}
+	return backup.NewScheduler(events, dest, cfg.Key, cfg.Keep, opts...)
+}
+
+// newOutboxDispatcher creates the dispatcher publishing the outbox to the
+// webhook and NATS server in cfg.
+func newOutboxDispatcher(cfg OutboxConfig, ob core.Outbox, events core.EventStore) (*outbox.Dispatcher, error) {
+	var sinks []outbox.Sink
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, outbox.NewWebhookSink(&http.Client{Timeout: 10 * time.Second}, cfg.WebhookURL, cfg.WebhookSecret))
+	}
+	if cfg.NATSURL != "" {
+		nats, err := outbox.NewNATSSink(cfg.NATSURL, cfg.NATSSubject, cfg.NATSCAFile)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, nats)
+	}
+	return outbox.NewDispatcher(ob, events, sinks), nil
+}
+
+// Handler serves every Bifrost route, with panic recovery and request
+// limits applied.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// EventStore returns the store the server appends events to.
+func (s *Server) EventStore() core.EventStore {
+	return s.eventStore
+}
+
+// EventBus returns the bus the server publishes appended events on.
+// Subscribe before Start to see every event appended after it.
+func (s *Server) EventBus() *core.EventBus {
+	return s.bus
+}
+
+// ProjectionStore returns the store the server's projectors write to.
+func (s *Server) ProjectionStore() core.ProjectionStore {
+	return s.projectionStore
+}
+
+// Start provisions the realms in the config's provision file, starts
+// projection catch-up, the monitors and command workers, and, unless the
+// routes were registered on an embedder's mux, listens on the configured
+// port. Background work stops when ctx is cancelled or Stop is called.
+func (s *Server) Start(ctx context.Context) error {
+	cfg := s.cfg
+	eventStore, projectionStore := s.eventStore, s.projectionStore
+
+	if err := s.engine.EnsureIndexes(ctx); err != nil {
+		return fmt.Errorf("index projections: %w", err)
+	}
+
+	// Projectors whose version changed start over before anything reads
+	// their projections
+	rebuilt, err := s.engine.RebuildOutdated(ctx)
+	if err != nil {
+		return fmt.Errorf("rebuild projections: %w", err)
+	}
+	if len(rebuilt) > 0 {
+		s.engine.RunCatchUpOnce(ctx)
+	}
+
+	// Provisioning and directory sync read projections, so each starts from
+	// a caught-up state
+	if cfg.ProvisionFile != "" {
+		s.engine.RunCatchUpOnce(ctx)
+		if err := runProvisioning(ctx, cfg.ProvisionFile, eventStore, projectionStore); err != nil {
+			return fmt.Errorf("provision: %w", err)
+		}
+	}
+	if s.directoryCfg != nil {
+		s.engine.RunCatchUpOnce(ctx)
+	}
+
+	// Catch-up is stopped by Stop rather than by ctx, so its last batch can
+	// finish
+	if err := s.engine.StartCatchUp(context.WithoutCancel(ctx)); err != nil {
+		return fmt.Errorf("start catch-up: %w", err)
+	}
+
+	background, stop := context.WithCancel(ctx)
+	s.stopBackground = stop
+	if cfg.SLACheckInterval > 0 {
+		go automation.NewSLAMonitor(eventStore, projectionStore).Run(background, cfg.SLACheckInterval)
+	}
+	if cfg.EscalationCheckInterval > 0 {
+		go automation.NewEscalator(eventStore, projectionStore).Run(background, cfg.EscalationCheckInterval)
+	}
+	if cfg.SchedulerInterval > 0 {
+		go automation.NewScheduler(eventStore, projectionStore).Run(background, cfg.SchedulerInterval)
+	}
+	if cfg.RetentionInterval > 0 {
+		var opts []automation.RetentionOption
+		if s.truncator != nil {
+			opts = append(opts, automation.WithTruncator(s.truncator))
+		}
+		go automation.NewRetentionEnforcer(eventStore, projectionStore, opts...).Run(background, cfg.RetentionInterval)
+	}
+	if cfg.ArchiveAfter > 0 && s.archiver != nil {
+		go automation.NewArchiver(s.archiver, cfg.ArchiveAfter).Run(background, cfg.ArchiveInterval)
+	}
+	if s.directoryCfg != nil {
+		syncer := directory.NewSyncer(s.directoryCfg.Source(os.Getenv), s.directoryCfg.Rules, eventStore, projectionStore)
+		go syncer.Run(background, s.directoryCfg.Interval)
+	}
+
+	// Backups are waited for by Stop, so the database is not closed under
+	// an archive being written
+	if s.backups != nil {
+		s.workers.Go(func() { s.backups.Run(background, cfg.Backup.Interval) })
+	}
+
+	// The outbox is drained until Stop, which waits for the publish in
+	// flight to be recorded
+	if s.publisher != nil {
+		s.workers.Go(func() { s.publisher.Run(background, cfg.Outbox.Interval) })
+	}
+
+	// Queued commands are worked off in the background
+	if cfg.CommandQueueSize > 0 {
+		for i := 0; i < cfg.CommandWorkers; i++ {
+			s.workers.Go(func() { s.handlers.RunCommandWorker(background, cfg.CatchUpInterval) })
+		}
+	}
+
+	if s.listen {
+		srv := &http.Server{
+			Addr:              fmt.Sprintf(":%d", cfg.Port),
+			Handler:           s.handler,
+			ReadHeaderTimeout: min(cfg.HTTPReadTimeout, 10*time.Second),
+			ReadTimeout:       cfg.HTTPReadTimeout,
+			WriteTimeout:      cfg.HTTPWriteTimeout,
+			IdleTimeout:       cfg.HTTPIdleTimeout,
+			BaseContext: func(l net.Listener) context.Context {
+				return ctx
+			},
+		}
+		ln, err := net.Listen("tcp", srv.Addr)
+		if err != nil {
+			return fmt.Errorf("listen: %w", err)
+		}
+		s.httpServer = srv
+		s.serveErr = make(chan error, 1)
+		go func() {
+			log.Printf("bifrost server listening on :%d", cfg.Port)
+			if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				s.serveErr <- err
+			}
+			close(s.serveErr)
+		}()
+	}
+
+	// Profiles and runtime variables, reachable only from this host
+	if cfg.DebugAddr != "" {
+		s.debugServer = &http.Server{Addr: cfg.DebugAddr, Handler: debug.Handler()}
+		go func() {
+			log.Printf("debug endpoints listening on %s", cfg.DebugAddr)
+			if err := s.debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("debug server error: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop shuts the server down gracefully: it stops accepting requests and
+// queued commands, lets those in flight finish, then drains the projection
+// engine so its current batch is checkpointed and its deliveries complete.
+// Work still running when ctx ends is abandoned. A database the server
+// opened is closed.
+func (s *Server) Stop(ctx context.Context) error {
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
+	var serverErr error
+	if s.httpServer != nil {
+		serverErr = s.httpServer.Shutdown(ctx)
+	}
+	if err := waitGroupWithin(ctx, &s.workers); err != nil {
+		log.Printf("command workers did not finish: %v", err)
+	}
+	if err := s.engine.Shutdown(ctx); err != nil {
+		log.Printf("projection engine shutdown error: %v", err)
+	}
+	if s.debugServer != nil {
+		_ = s.debugServer.Shutdown(ctx)
+	}
+	s.closeDB()
+	if serverErr != nil {
+		return fmt.Errorf("server shutdown: %w", serverErr)
+	}
+
+	// Report why Serve returned, if it failed
+	if s.serveErr != nil {
+		if err := <-s.serveErr; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) closeDB() {
+	if s.search != nil {
+		if err := s.search.Close(); err != nil {
+			log.Printf("close search index: %v", err)
+		}
+	}
+	if s.ownsDB && s.db != nil {
+		if err := s.db.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+			log.Printf("close database: %v", err)
+		}
+	}
+	if s.storeDB != nil {
+		if err := s.storeDB.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+			log.Printf("close mysql database: %v", err)
+		}
+	}
+	if s.replicaDB != nil {
+		if err := s.replicaDB.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+			log.Printf("close read replica: %v", err)
+		}
+	}
+	if s.shards != nil {
+		if err := s.shards.Close(); err != nil {
+			log.Printf("close realm databases: %v", err)
+		}
+	}
+	if s.redis != nil {
+		if err := s.redis.Close(); err != nil {
+			log.Printf("close redis: %v", err)
+		}
+	}
+	if s.postgresDB != nil {
+		if err := s.postgresDB.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+			log.Printf("close postgres database: %v", err)
+		}
+	}
+}
+
+// openApartStores opens the projection and checkpoint stores the config
+// keeps apart from the events, returning nil for either that is not.
+func (s *Server) openApartStores() (core.ProjectionStore, core.CheckpointStore, error) {
+	cfg := s.cfg
+	var projections core.ProjectionStore
+	switch {
+	case cfg.RedisURL != "":
+		store, err := s.openRedis(cfg.RedisURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		projections = store
+	case cfg.ProjectionDriver == "sqlite":
+		store, err := sqlite.NewProjectionStore(s.db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create projection store: %w", err)
+		}
+		projections = store
+	case cfg.ProjectionDriver == "postgres":
+		db, err := s.openPostgres()
+		if err != nil {
+			return nil, nil, err
+		}
+		store, err := postgres.NewProjectionStore(db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create projection store: %w", err)
+		}
+		projections = store
+	}
+
+	var checkpoints core.CheckpointStore
+	switch cfg.CheckpointDriver {
+	case "sqlite":
+		store, err := sqlite.NewCheckpointStore(s.db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create checkpoint store: %w", err)
+		}
+		checkpoints = store
+	case "postgres":
+		db, err := s.openPostgres()
+		if err != nil {
+			return nil, nil, err
+		}
+		store, err := postgres.NewCheckpointStore(db)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create checkpoint store: %w", err)
+		}
+		checkpoints = store
+	}
+	return projections, checkpoints, nil
+}
+
+// openPostgres opens the PostgreSQL database once for both stores.
+func (s *Server) openPostgres() (*sql.DB, error) {
+	if s.postgresDB == nil {
+		db, err := postgres.Open(s.cfg.PostgresDSN)
+		if err != nil {
+			return nil, fmt.Errorf("open postgres database: %w", err)
+		}
+		s.postgresDB = db
+	}
+	return s.postgresDB, nil
+}
+
+// openRedis connects to the Redis server at rawURL and returns a projection
+// store on it. The client is closed with the databases.
+func (s *Server) openRedis(rawURL string) (*redis.ProjectionStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, err := redis.NewClient(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("connect to redis: %w", err)
+	}
+	s.redis = client
+	store, err := redis.NewProjectionStore(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("create projection store: %w", err)
+	}
+	return store, nil
+}
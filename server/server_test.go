@@ -0,0 +1,463 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/providers/memory"
+	"github.com/devzeebo/bifrost/server/backup"
+	"github.com/devzeebo/bifrost/server/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestServer(t *testing.T) {
+	t.Run("registers its routes on the embedder's mux without listening", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.embedder_mux_with_route("GET /embedder", "embedder")
+
+		// When
+		tc.server_built(WithMux(tc.mux))
+		tc.server_started()
+
+		// Then
+		tc.handler_responds("/health", http.StatusOK)
+		tc.handler_responds("/embedder", http.StatusOK)
+		tc.port_is_free()
+	})
+
+	t.Run("runs embedder projectors alongside the built-in ones", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.recording_projector()
+		tc.server_built(WithMux(http.NewServeMux()), WithProjectors(tc.projector))
+		tc.server_started()
+
+		// When
+		tc.event_appended("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		tc.projector_receives("SomethingHappened")
+	})
+
+	t.Run("publishes appended events on its event bus", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.server_built(WithMux(http.NewServeMux()))
+		tc.event_bus_subscriber()
+		tc.server_started()
+
+		// When
+		tc.event_appended("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		assert.Equal(t, []string{"SomethingHappened"}, tc.published)
+	})
+
+	t.Run("publishes appended events to the outbox webhook", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.outbox_webhook()
+		tc.server_built(WithMux(http.NewServeMux()))
+		tc.server_started()
+
+		// When
+		tc.event_appended("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		tc.webhook_receives("SomethingHappened")
+	})
+
+	t.Run("reports event store calls to the embedder's hooks", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		hooks := &countingHooks{}
+		tc.server_built(WithMux(http.NewServeMux()), WithEventStoreHooks(hooks))
+
+		// When
+		tc.event_appended("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		assert.Equal(t, 1, hooks.appends)
+	})
+
+	t.Run("reports appends inside a command's transaction to the embedder's hooks", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		hooks := &countingHooks{}
+		tc.server_built(WithMux(http.NewServeMux()), WithEventStoreHooks(hooks))
+
+		// When
+		tc.event_appended_in_transaction("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		assert.Equal(t, 1, hooks.appends)
+	})
+
+	t.Run("keeps each realm in a database of its own with the sqlite-sharded driver", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.cfg.DBDriver = "sqlite-sharded"
+		tc.cfg.DBPath = t.TempDir()
+		tc.cfg.DBMaxOpenShards = 1
+		tc.recording_projector()
+		tc.server_built(WithMux(http.NewServeMux()), WithProjectors(tc.projector))
+		tc.server_started()
+
+		// When
+		tc.event_appended("realm-1", "stream-1", "SomethingHappened")
+
+		// Then
+		tc.projector_receives("SomethingHappened")
+		assert.FileExists(t, filepath.Join(tc.cfg.DBPath, "realms", "realm-1.db"))
+		assert.FileExists(t, filepath.Join(tc.cfg.DBPath, "bifrost.db"))
+	})
+
+	t.Run("leaves an embedder's database open when stopped", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.embedder_db()
+		tc.server_built(WithMux(http.NewServeMux()), WithDB(tc.db))
+		tc.server_started()
+
+		// When
+		tc.server_stopped()
+
+		// Then
+		require.NoError(t, tc.db.Ping())
+	})
+
+	t.Run("rejects stores that are only partly replaced", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.embedder_db()
+		tc.server_built(WithDB(tc.db), WithMux(http.NewServeMux()))
+
+		// When
+		_, tc.err = New(WithConfig(tc.cfg), WithStores(tc.server.EventStore(), nil, nil))
+
+		// Then
+		require.Error(t, tc.err)
+		assert.Contains(t, tc.err.Error(), "WithStores")
+	})
+
+	t.Run("restores a backup archive up to a position and replays its projections", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.backup_archive_of("realm-1", "SomethingHappened", "SomethingElseHappened", "LaterThingHappened")
+		tc.recording_projector()
+		tc.server_built(WithMux(http.NewServeMux()), WithProjectors(tc.projector))
+
+		// When
+		tc.archive_restored(backup.Target{Position: 2})
+
+		// Then
+		require.NoError(t, tc.err)
+		assert.Equal(t, 2, tc.restored.Events)
+		assert.Equal(t, 1, tc.restored.Skipped)
+		assert.True(t, tc.projector.saw("SomethingElseHappened"))
+		assert.False(t, tc.projector.saw("LaterThingHappened"))
+		tc.checkpoint_is("realm-1", "notifications", 2)
+		tc.checkpoint_is("realm-1", "automation", 2)
+	})
+
+	t.Run("refuses to restore into a started server", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+		tc.backup_archive_of("realm-1", "SomethingHappened")
+		tc.server_built(WithMux(http.NewServeMux()))
+		tc.server_started()
+
+		// When
+		tc.archive_restored(backup.Target{})
+
+		// Then
+		assert.ErrorContains(t, tc.err, "not been started")
+	})
+
+	t.Run("listens on the configured port without an embedder mux", func(t *testing.T) {
+		tc := newServerTestContext(t)
+
+		// Given
+		tc.file_config()
+
+		// When
+		tc.server_built()
+		tc.server_started()
+
+		// Then
+		tc.server_answers_on_port("/health", http.StatusOK)
+	})
+}
+
+// --- Test Context ---
+
+type serverTestContext struct {
+	t         *testing.T
+	cfg       *Config
+	db        *sql.DB
+	mux       *http.ServeMux
+	projector *recordingProjector
+	published []string
+	webhook   chan string
+	archive   []byte
+	server    *Server
+	restored  backup.RestoreResult
+	err       error
+}
+
+func newServerTestContext(t *testing.T) *serverTestContext {
+	t.Helper()
+	return &serverTestContext{t: t}
+}
+
+// --- Given ---
+
+func (tc *serverTestContext) file_config() {
+	tc.t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(tc.t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	tc.cfg = &Config{
+		DBDriver:        "sqlite",
+		DBPath:          filepath.Join(tc.t.TempDir(), "bifrost.db"),
+		Port:            port,
+		CatchUpInterval: 10 * time.Millisecond,
+		ShutdownTimeout: 5 * time.Second,
+	}
+}
+
+func (tc *serverTestContext) embedder_mux_with_route(pattern, body string) {
+	tc.t.Helper()
+	tc.mux = http.NewServeMux()
+	tc.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+}
+
+func (tc *serverTestContext) embedder_db() {
+	tc.t.Helper()
+	db, err := sql.Open("sqlite", tc.cfg.DBPath)
+	require.NoError(tc.t, err)
+	tc.t.Cleanup(func() { db.Close() })
+	tc.db = db
+}
+
+func (tc *serverTestContext) outbox_webhook() {
+	tc.t.Helper()
+	tc.webhook = make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc.webhook <- r.Header.Get(webhooks.EventHeader)
+	}))
+	tc.t.Cleanup(srv.Close)
+	tc.cfg.Outbox = OutboxConfig{Interval: 10 * time.Millisecond, WebhookURL: srv.URL, WebhookSecret: "s3cret"}
+}
+
+// backup_archive_of writes an archive of a realm holding one stream with
+// an event of each type, encrypted with a zero key.
+func (tc *serverTestContext) backup_archive_of(realmID string, eventTypes ...string) {
+	tc.t.Helper()
+	source := memory.NewEventStore()
+	for i, eventType := range eventTypes {
+		_, err := source.Append(context.Background(), realmID, "stream-1", i, []core.EventData{
+			{EventType: eventType, Data: map[string]string{}},
+		})
+		require.NoError(tc.t, err)
+	}
+	var buf bytes.Buffer
+	enc, err := backup.NewEncryptWriter(&buf, make([]byte, backup.KeySize))
+	require.NoError(tc.t, err)
+	_, err = backup.WriteArchive(context.Background(), source, enc)
+	require.NoError(tc.t, err)
+	require.NoError(tc.t, enc.Close())
+	tc.archive = buf.Bytes()
+}
+
+func (tc *serverTestContext) recording_projector() {
+	tc.t.Helper()
+	tc.projector = &recordingProjector{}
+}
+
+// --- When ---
+
+func (tc *serverTestContext) event_bus_subscriber() {
+	tc.server.EventBus().Subscribe(func(_ context.Context, events []core.Event) {
+		for _, evt := range events {
+			tc.published = append(tc.published, evt.EventType)
+		}
+	})
+}
+
+func (tc *serverTestContext) server_built(opts ...Option) {
+	tc.t.Helper()
+	srv, err := New(append([]Option{WithConfig(tc.cfg)}, opts...)...)
+	require.NoError(tc.t, err)
+	tc.server = srv
+}
+
+func (tc *serverTestContext) server_started() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.server.Start(context.Background()))
+	tc.t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = tc.server.Stop(ctx)
+	})
+}
+
+func (tc *serverTestContext) server_stopped() {
+	tc.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(tc.t, tc.server.Stop(ctx))
+}
+
+func (tc *serverTestContext) event_appended(realmID, streamID, eventType string) {
+	tc.t.Helper()
+	_, err := tc.server.EventStore().Append(context.Background(), realmID, streamID, 0, []core.EventData{
+		{EventType: eventType, Data: map[string]string{}},
+	})
+	require.NoError(tc.t, err)
+}
+
+func (tc *serverTestContext) event_appended_in_transaction(realmID, streamID, eventType string) {
+	tc.t.Helper()
+	executor, ok := tc.server.engine.(CommandExecutor)
+	require.True(tc.t, ok, "engine runs no transactions")
+	err := executor.Execute(context.Background(), realmID, func(ctx context.Context, uow core.UnitOfWork) error {
+		_, err := uow.EventStore.Append(ctx, realmID, streamID, 0, []core.EventData{
+			{EventType: eventType, Data: map[string]string{}},
+		})
+		return err
+	})
+	require.NoError(tc.t, err)
+}
+
+func (tc *serverTestContext) archive_restored(target backup.Target) {
+	tc.restored, tc.err = tc.server.Restore(context.Background(), bytes.NewReader(tc.archive), make([]byte, backup.KeySize), target)
+}
+
+// --- Then ---
+
+func (tc *serverTestContext) checkpoint_is(realmID, projectorName string, expected int64) {
+	tc.t.Helper()
+	pos, err := tc.server.checkpoints.GetCheckpoint(context.Background(), realmID, projectorName)
+	require.NoError(tc.t, err)
+	assert.Equal(tc.t, expected, pos)
+}
+
+func (tc *serverTestContext) handler_responds(path string, status int) {
+	tc.t.Helper()
+	rec := httptest.NewRecorder()
+	tc.server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(tc.t, status, rec.Code, path)
+}
+
+func (tc *serverTestContext) port_is_free() {
+	tc.t.Helper()
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", tc.cfg.Port))
+	require.NoError(tc.t, err, "server should not listen when given a mux")
+	l.Close()
+}
+
+func (tc *serverTestContext) server_answers_on_port(path string, status int) {
+	tc.t.Helper()
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", tc.cfg.Port, path))
+	require.NoError(tc.t, err)
+	resp.Body.Close()
+	assert.Equal(tc.t, status, resp.StatusCode)
+}
+
+func (tc *serverTestContext) webhook_receives(eventType string) {
+	tc.t.Helper()
+	select {
+	case got := <-tc.webhook:
+		assert.Equal(tc.t, eventType, got)
+	case <-time.After(3 * time.Second):
+		tc.t.Fatal("outbox webhook was not called")
+	}
+}
+
+func (tc *serverTestContext) projector_receives(eventType string) {
+	tc.t.Helper()
+	assert.Eventually(tc.t, func() bool {
+		return tc.projector.saw(eventType)
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+// --- Counting Hooks ---
+
+type countingHooks struct {
+	appends int
+}
+
+func (h *countingHooks) OnAppend(_ context.Context, call core.StoreCall) {
+	if call.Err == nil {
+		h.appends++
+	}
+}
+
+func (h *countingHooks) OnRead(context.Context, core.StoreCall) {}
+
+// --- Recording Projector ---
+
+type recordingProjector struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *recordingProjector) Name() string { return "recording" }
+
+func (p *recordingProjector) Handle(_ context.Context, event core.Event, _ core.ProjectionStore) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event.EventType)
+	return nil
+}
+
+func (p *recordingProjector) saw(eventType string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// SLAReport lists the realm's SLA thresholds and the runes currently in
+// breach of them.
+type SLAReport struct {
+	Thresholds map[string]string `json:"thresholds"`
+	Counts     map[string]int    `json:"counts"`
+	Breaches   []SLABreach       `json:"breaches"`
+}
+
+// SLABreach is a rune that has overstayed its current status.
+type SLABreach struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	Claimant   string    `json:"claimant,omitempty"`
+	BreachedAt time.Time `json:"breached_at"`
+}
+
+// SLAReport serves GET /reports/sla. Breaches are listed oldest first, and
+// a rune drops out of the report as soon as it changes status.
+func (h *Handlers) SLAReport(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+
+	settings, err := projectors.GetRealmSettings(r.Context(), h.projectionStore, realmID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load realm settings")
+		return
+	}
+	report := SLAReport{Thresholds: map[string]string{}, Counts: map[string]int{}, Breaches: []SLABreach{}}
+	for status := range domain.SLAThresholdsFromSettings(settings) {
+		report.Thresholds[status] = settings[domain.SLASettingPrefix+status]
+	}
+
+	raws, err := h.projectionStore.List(r.Context(), realmID, "rune_list")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list runes")
+		return
+	}
+	for _, raw := range raws {
+		var summary projectors.RuneSummary
+		if json.Unmarshal(raw, &summary) != nil || summary.SLABreachedAt.IsZero() {
+			continue
+		}
+		report.Counts[summary.Status]++
+		report.Breaches = append(report.Breaches, SLABreach{
+			ID:         summary.ID,
+			Title:      summary.Title,
+			Status:     summary.Status,
+			Claimant:   summary.Claimant,
+			BreachedAt: summary.SLABreachedAt,
+		})
+	}
+	sort.Slice(report.Breaches, func(i, j int) bool {
+		a, b := report.Breaches[i], report.Breaches[j]
+		if !a.BreachedAt.Equal(b.BreachedAt) {
+			return a.BreachedAt.Before(b.BreachedAt)
+		}
+		return a.ID < b.ID
+	})
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSLAReportHandler(t *testing.T) {
+	t.Run("lists breached runes oldest first with counts per status", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.projection_has_realm_settings("realm-1", map[string]string{"sla.claimed": "7d", "sla.open": "2d"})
+		tc.rune_breached_sla("realm-1", "bf-b", "claimed", "2026-03-10T09:00:00Z")
+		tc.rune_breached_sla("realm-1", "bf-a", "open", "2026-03-12T09:00:00Z")
+		tc.rune_breached_sla("realm-1", "bf-c", "claimed", "2026-03-05T09:00:00Z")
+		tc.rune_breached_sla("realm-1", "bf-ok", "claimed", "")
+
+		// When
+		tc.get("/reports/sla")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		report := tc.sla_report()
+		assert.Equal(t, map[string]string{"claimed": "7d", "open": "2d"}, report.Thresholds)
+		assert.Equal(t, map[string]int{"claimed": 2, "open": 1}, report.Counts)
+		ids := []string{}
+		for _, b := range report.Breaches {
+			ids = append(ids, b.ID)
+		}
+		assert.Equal(t, []string{"bf-c", "bf-b", "bf-a"}, ids)
+	})
+
+	t.Run("returns an empty report for a realm without SLAs", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/reports/sla")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		report := tc.sla_report()
+		assert.Empty(t, report.Thresholds)
+		assert.Empty(t, report.Breaches)
+	})
+}
+
+// --- Given ---
+
+// rune_breached_sla stores a rune summary, flagged as breached at the given
+// time unless it is empty.
+func (tc *handlerTestContext) rune_breached_sla(realmID, runeID, status, breachedAt string) {
+	tc.t.Helper()
+	summary := projectors.RuneSummary{ID: runeID, Title: "Rune " + runeID, Status: status}
+	if breachedAt != "" {
+		at, err := time.Parse(time.RFC3339, breachedAt)
+		require.NoError(tc.t, err)
+		summary.SLABreachedAt = at
+	}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_list", runeID, summary)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) sla_report() SLAReport {
+	tc.t.Helper()
+	var report SLAReport
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &report))
+	return report
+}
@@ -0,0 +1,311 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+)
+
+// The sync endpoints let a client that works offline catch up on a realm's
+// rune events and push the commands it queued meanwhile. Each pushed command
+// carries an idempotency key, so a push retried after a lost response is not
+// applied twice, and the sync position the client had reached when it queued
+// the command, so that changes made on the server since are reported as
+// conflicts instead of being overwritten.
+
+const (
+	defaultSyncPullLimit = 500
+	maxSyncPullLimit     = 5000
+	maxSyncPushCommands  = 100
+)
+
+// Outcomes of a pushed command.
+const (
+	SyncApplied   = "applied"
+	SyncDuplicate = "duplicate"
+	SyncConflict  = "conflict"
+	SyncRejected  = "rejected"
+	SyncFailed    = "failed"
+)
+
+// syncCommands are the rune commands an offline client can push.
+var syncCommands = map[string]bool{
+	"create-rune":       true,
+	"update-rune":       true,
+	"claim-rune":        true,
+	"unclaim-rune":      true,
+	"fulfill-rune":      true,
+	"seal-rune":         true,
+	"forge-rune":        true,
+	"add-dependency":    true,
+	"remove-dependency": true,
+	"add-note":          true,
+	"shatter-rune":      true,
+}
+
+// WithSyncReceiptStore enables pushing commands to the sync endpoint,
+// recording their outcomes in s.
+func WithSyncReceiptStore(s core.SyncReceiptStore) HandlersOption {
+	return func(h *Handlers) {
+		h.syncReceipts = s
+	}
+}
+
+// SyncEvent is a rune event returned by a sync pull.
+type SyncEvent struct {
+	GlobalPosition int64           `json:"global_position"`
+	StreamID       string          `json:"stream_id"`
+	Version        int             `json:"version"`
+	EventType      string          `json:"event_type"`
+	Data           json.RawMessage `json:"data"`
+	Timestamp      time.Time       `json:"timestamp"`
+}
+
+// SyncPullResponse is a page of a realm's rune events. Position is where the
+// next pull starts; it can be past the last event returned, since events
+// outside rune streams are skipped.
+type SyncPullResponse struct {
+	Events   []SyncEvent `json:"events"`
+	Position int64       `json:"position"`
+	More     bool        `json:"more"`
+}
+
+// SyncCommand is a command queued by an offline client.
+type SyncCommand struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	Command        string          `json:"command"`
+	Payload        json.RawMessage `json:"payload"`
+	// BasePosition is the sync position the client had pulled to when it
+	// queued the command. Zero skips the conflict check.
+	BasePosition int64 `json:"base_position,omitempty"`
+}
+
+type syncPushRequest struct {
+	Commands []SyncCommand `json:"commands"`
+}
+
+// SyncResult is the outcome of a pushed command. StatusCode and Result are
+// the response the command got, or gets again for a duplicate.
+type SyncResult struct {
+	IdempotencyKey string          `json:"idempotency_key"`
+	Command        string          `json:"command"`
+	Status         string          `json:"status"`
+	StatusCode     int             `json:"status_code,omitempty"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// SyncPull serves GET /sync: the realm's rune events after the since
+// position, oldest first, at most limit of them.
+func (h *Handlers) SyncPull(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "since must be a non-negative position")
+			return
+		}
+		since = n
+	}
+	limit := defaultSyncPullLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSyncPullLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxSyncPullLimit))
+			return
+		}
+		limit = n
+	}
+
+	page, err := core.ReadPage(r.Context(), h.eventStore, realmID, since, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := SyncPullResponse{Events: []SyncEvent{}, Position: page.Next, More: page.More}
+	for _, evt := range page.Events {
+		if !domain.IsRuneStream(evt.StreamID) {
+			continue
+		}
+		resp.Events = append(resp.Events, SyncEvent{
+			GlobalPosition: evt.GlobalPosition,
+			StreamID:       evt.StreamID,
+			Version:        evt.Version,
+			EventType:      evt.EventType,
+			Data:           json.RawMessage(evt.Data),
+			Timestamp:      evt.Timestamp,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SyncPush serves POST /sync: it runs the pushed commands in order and
+// reports the outcome of each. Conflicts are checked against the realm as it
+// was when the push arrived, so a command is not reported as conflicting
+// with an earlier command of the same push.
+func (h *Handlers) SyncPush(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	accountID, ok := AccountIDFromContext(r.Context())
+	if !ok || accountID == "" {
+		writeError(w, http.StatusForbidden, "account ID required")
+		return
+	}
+	if h.syncReceipts == nil {
+		writeError(w, http.StatusNotImplemented, "sync is not enabled")
+		return
+	}
+	var req syncPushRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if len(req.Commands) > maxSyncPushCommands {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d commands can be pushed at once", maxSyncPushCommands))
+		return
+	}
+	for i, cmd := range req.Commands {
+		switch {
+		case cmd.IdempotencyKey == "":
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("commands[%d]: idempotency_key is required", i))
+			return
+		case !syncCommands[cmd.Command]:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("commands[%d]: %q cannot be synced", i, cmd.Command))
+			return
+		case !json.Valid(cmd.Payload):
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("commands[%d]: payload must be JSON", i))
+			return
+		}
+	}
+
+	results := make([]SyncResult, len(req.Commands))
+	receipts := make([]*core.SyncReceipt, len(req.Commands))
+	for i, cmd := range req.Commands {
+		results[i] = SyncResult{IdempotencyKey: cmd.IdempotencyKey, Command: cmd.Command}
+		receipt, err := h.syncReceipts.GetReceipt(r.Context(), realmID, accountID, cmd.IdempotencyKey)
+		if err == nil {
+			receipts[i] = &receipt
+			continue
+		}
+		if !isNotFound(err) {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		conflict, err := h.syncConflict(r, realmID, cmd)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if conflict != "" {
+			results[i].Status = SyncConflict
+			results[i].Error = conflict
+		}
+	}
+
+	for i, cmd := range req.Commands {
+		if receipts[i] != nil {
+			results[i].Status = SyncDuplicate
+			results[i].StatusCode = receipts[i].StatusCode
+			results[i].Result = receipts[i].Result
+			continue
+		}
+		if results[i].Status == SyncConflict {
+			continue
+		}
+
+		statusCode, result := h.runSyncCommand(r, cmd)
+		results[i].StatusCode, results[i].Result = statusCode, result
+		switch {
+		case statusCode >= 500:
+			// Left without a receipt, so that the client can retry it.
+			results[i].Status = SyncFailed
+			results[i].Error = errorMessage(result)
+			continue
+		case statusCode >= 400:
+			results[i].Status = SyncRejected
+			results[i].Error = errorMessage(result)
+		default:
+			results[i].Status = SyncApplied
+		}
+		if err := h.syncReceipts.SaveReceipt(r.Context(), core.SyncReceipt{
+			RealmID:        realmID,
+			AccountID:      accountID,
+			IdempotencyKey: cmd.IdempotencyKey,
+			Command:        cmd.Command,
+			StatusCode:     statusCode,
+			Result:         result,
+		}); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+// syncConflict describes why cmd conflicts with the realm, or returns "" when
+// no rune it targets has changed since the client's base position.
+func (h *Handlers) syncConflict(r *http.Request, realmID string, cmd SyncCommand) (string, error) {
+	if cmd.BasePosition == 0 {
+		return "", nil
+	}
+	var refs struct {
+		ID     string `json:"id"`
+		RuneID string `json:"rune_id"`
+	}
+	_ = json.Unmarshal(cmd.Payload, &refs)
+	for _, runeID := range []string{refs.ID, refs.RuneID} {
+		if runeID == "" {
+			continue
+		}
+		events, err := h.eventStore.ReadStream(r.Context(), realmID, domain.RuneStreamID(runeID), 0)
+		if err != nil {
+			return "", err
+		}
+		if n := len(events); n > 0 && events[n-1].GlobalPosition > cmd.BasePosition {
+			return fmt.Sprintf("rune %s changed at position %d, after position %d", runeID, events[n-1].GlobalPosition, cmd.BasePosition), nil
+		}
+	}
+	return "", nil
+}
+
+// runSyncCommand runs cmd through the command routes as the pushing account
+// and returns the response it got.
+func (h *Handlers) runSyncCommand(r *http.Request, cmd SyncCommand) (int, json.RawMessage) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "/"+cmd.Command, bytes.NewReader(cmd.Payload))
+	if err != nil {
+		return http.StatusInternalServerError, nil
+	}
+	rec := &commandRecorder{header: http.Header{}, status: http.StatusOK}
+	h.mux.ServeHTTP(rec, req)
+
+	var result json.RawMessage
+	if body := bytes.TrimSpace(rec.body.Bytes()); json.Valid(body) {
+		result = body
+	}
+	return rec.status, result
+}
+
+// errorMessage returns the error field of a JSON error response.
+func errorMessage(result json.RawMessage) string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(result, &body); err != nil || body.Error == "" {
+		return "command failed"
+	}
+	return body.Error
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestSyncPullHandler(t *testing.T) {
+	t.Run("returns the realm's rune events after the since position", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.eventStore.appendToStream("realm-1", "saved-search-s1", "SavedSearchCreated", map[string]string{"id": "s1"})
+		tc.rune_exists_in_event_store("realm-2", "bf-0002")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/sync?since=1")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		pull := tc.sync_pull_response()
+		require.Len(t, pull.Events, 1)
+		assert.Equal(t, domain.EventRuneForged, pull.Events[0].EventType)
+		assert.JSONEq(t, `{"id":"bf-0001"}`, string(pull.Events[0].Data))
+		assert.Equal(t, int64(3), pull.Position, "skipped events still advance the position")
+		assert.False(t, pull.More)
+	})
+
+	t.Run("reports more events past the limit", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/sync?limit=1")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		pull := tc.sync_pull_response()
+		require.Len(t, pull.Events, 1)
+		assert.Equal(t, int64(1), pull.Position)
+		assert.True(t, pull.More)
+	})
+
+	t.Run("rejects a negative since", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/sync?since=-1")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+func TestSyncPushHandler(t *testing.T) {
+	t.Run("applies queued commands and records their receipts", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{IdempotencyKey: "k1", Command: "add-note", Payload: json.RawMessage(`{"rune_id":"bf-0001","text":"offline note"}`)})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		results := tc.sync_results()
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncApplied, results[0].Status)
+		assert.Equal(t, http.StatusNoContent, results[0].StatusCode)
+		tc.rune_stream_has_events("realm-1", "bf-0001", 3)
+		tc.receipt_exists("realm-1", "acct-1", "k1")
+	})
+
+	t.Run("answers a retried command from its receipt without running it again", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.handlers_configured()
+		cmd := SyncCommand{IdempotencyKey: "k1", Command: "add-note", Payload: json.RawMessage(`{"rune_id":"bf-0001","text":"offline note"}`)}
+		tc.push(cmd)
+		tc.recorder = httptest.NewRecorder()
+
+		// When
+		tc.push(cmd)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		results := tc.sync_results()
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncDuplicate, results[0].Status)
+		assert.Equal(t, http.StatusNoContent, results[0].StatusCode)
+		tc.rune_stream_has_events("realm-1", "bf-0001", 3)
+	})
+
+	t.Run("reports a conflict when the rune changed after the base position", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{IdempotencyKey: "k1", Command: "claim-rune", Payload: json.RawMessage(`{"id":"bf-0001","claimant":"alice"}`), BasePosition: 1})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		results := tc.sync_results()
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncConflict, results[0].Status)
+		assert.Contains(t, results[0].Error, "rune bf-0001 changed at position 2")
+		tc.rune_stream_has_events("realm-1", "bf-0001", 2)
+		tc.receipt_does_not_exist("realm-1", "acct-1", "k1")
+	})
+
+	t.Run("does not report conflicts between commands of the same push", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.rune_exists_in_event_store("realm-1", "bf-0001")
+		tc.handlers_configured()
+
+		// When
+		tc.push(
+			SyncCommand{IdempotencyKey: "k1", Command: "claim-rune", Payload: json.RawMessage(`{"id":"bf-0001","claimant":"alice"}`), BasePosition: 2},
+			SyncCommand{IdempotencyKey: "k2", Command: "add-note", Payload: json.RawMessage(`{"rune_id":"bf-0001","text":"started"}`), BasePosition: 2},
+		)
+
+		// Then
+		tc.status_is(http.StatusOK)
+		results := tc.sync_results()
+		require.Len(t, results, 2)
+		assert.Equal(t, SyncApplied, results[0].Status)
+		assert.Equal(t, SyncApplied, results[1].Status)
+	})
+
+	t.Run("records commands the domain rejects", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{IdempotencyKey: "k1", Command: "add-note", Payload: json.RawMessage(`{"rune_id":"bf-9999","text":"lost"}`)})
+
+		// Then
+		tc.status_is(http.StatusOK)
+		results := tc.sync_results()
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncRejected, results[0].Status)
+		assert.Equal(t, http.StatusNotFound, results[0].StatusCode)
+		assert.Contains(t, results[0].Error, "bf-9999")
+		tc.receipt_exists("realm-1", "acct-1", "k1")
+	})
+
+	t.Run("rejects commands that cannot be synced", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{IdempotencyKey: "k1", Command: "sweep-runes", Payload: json.RawMessage(`{}`)})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains(`\"sweep-runes\" cannot be synced`)
+	})
+
+	t.Run("requires an idempotency key", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.a_sync_receipt_store()
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{Command: "add-note", Payload: json.RawMessage(`{}`)})
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+		tc.response_body_contains("idempotency_key is required")
+	})
+
+	t.Run("returns 501 without a receipt store", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.handlers_configured()
+
+		// When
+		tc.push(SyncCommand{IdempotencyKey: "k1", Command: "add-note", Payload: json.RawMessage(`{}`)})
+
+		// Then
+		tc.status_is(http.StatusNotImplemented)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) a_sync_receipt_store() {
+	tc.t.Helper()
+	tc.syncReceipts = &mockSyncReceiptStore{receipts: make(map[string]core.SyncReceipt)}
+}
+
+// --- When ---
+
+func (tc *handlerTestContext) push(cmds ...SyncCommand) {
+	tc.t.Helper()
+	tc.post("/sync", map[string]any{"commands": cmds})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) sync_pull_response() SyncPullResponse {
+	tc.t.Helper()
+	var resp SyncPullResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
+
+func (tc *handlerTestContext) sync_results() []SyncResult {
+	tc.t.Helper()
+	var resp struct {
+		Results []SyncResult `json:"results"`
+	}
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp.Results
+}
+
+func (tc *handlerTestContext) rune_stream_has_events(realmID, runeID string, expected int) {
+	tc.t.Helper()
+	assert.Len(tc.t, tc.eventStore.streams[tc.eventStore.streamKey(realmID, "rune-"+runeID)], expected)
+}
+
+func (tc *handlerTestContext) receipt_exists(realmID, accountID, key string) {
+	tc.t.Helper()
+	_, err := tc.syncReceipts.GetReceipt(context.Background(), realmID, accountID, key)
+	assert.NoError(tc.t, err)
+}
+
+func (tc *handlerTestContext) receipt_does_not_exist(realmID, accountID, key string) {
+	tc.t.Helper()
+	_, err := tc.syncReceipts.GetReceipt(context.Background(), realmID, accountID, key)
+	assert.True(tc.t, isNotFound(err))
+}
+
+// --- Mock Sync Receipt Store ---
+
+type mockSyncReceiptStore struct {
+	receipts map[string]core.SyncReceipt
+}
+
+func (m *mockSyncReceiptStore) SaveReceipt(_ context.Context, receipt core.SyncReceipt) error {
+	key := receipt.RealmID + ":" + receipt.AccountID + ":" + receipt.IdempotencyKey
+	if _, ok := m.receipts[key]; !ok {
+		m.receipts[key] = receipt
+	}
+	return nil
+}
+
+func (m *mockSyncReceiptStore) GetReceipt(_ context.Context, realmID, accountID, key string) (core.SyncReceipt, error) {
+	receipt, ok := m.receipts[realmID+":"+accountID+":"+key]
+	if !ok {
+		return core.SyncReceipt{}, &core.NotFoundError{Entity: "sync receipt", ID: key}
+	}
+	return receipt, nil
+}
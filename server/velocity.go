@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// defaultVelocityWeeks is how far back /reports/velocity looks when the
+// request gives no from date.
+const defaultVelocityWeeks = 12
+
+// VelocityReport summarises how quickly runes were fulfilled between two UTC
+// dates, inclusive.
+type VelocityReport struct {
+	From             string             `json:"from"`
+	To               string             `json:"to"`
+	Fulfilled        int                `json:"fulfilled"`
+	LeadTime         DurationStats      `json:"lead_time"`
+	CycleTime        DurationStats      `json:"cycle_time"`
+	WeeklyThroughput []WeeklyThroughput `json:"weekly_throughput"`
+}
+
+// DurationStats aggregates a set of durations in hours. Count is the number
+// of runes measured; the other fields are zero when it is zero.
+type DurationStats struct {
+	Count        int     `json:"count"`
+	AverageHours float64 `json:"average_hours"`
+	MedianHours  float64 `json:"median_hours"`
+	P85Hours     float64 `json:"p85_hours"`
+}
+
+// WeeklyThroughput counts the runes fulfilled in the week starting on the
+// Monday WeekStart.
+type WeeklyThroughput struct {
+	WeekStart string `json:"week_start"`
+	Fulfilled int    `json:"fulfilled"`
+}
+
+// VelocityReport serves GET /reports/velocity. Lead time runs from creation
+// to fulfilment and cycle time from the last claim to fulfilment; runes
+// fulfilled without a claim only count towards lead time.
+func (h *Handlers) VelocityReport(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+
+	to := statsDay(time.Now())
+	if v := r.URL.Query().Get("to"); v != "" {
+		parsed, err := time.Parse(projectors.DailyStatsDateFormat, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -7*defaultVelocityWeeks+1)
+	if v := r.URL.Query().Get("from"); v != "" {
+		parsed, err := time.Parse(projectors.DailyStatsDateFormat, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+	if to.Sub(from) > maxStatsDays*24*time.Hour {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("range may not exceed %d days", maxStatsDays))
+		return
+	}
+
+	if h.notModified(w, r, projectionSource{realmID, "rune_transitions"}) {
+		return
+	}
+	raws, err := h.projectionStore.List(r.Context(), realmID, "rune_transitions")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load rune transitions")
+		return
+	}
+
+	report := VelocityReport{
+		From:             from.Format(projectors.DailyStatsDateFormat),
+		To:               to.Format(projectors.DailyStatsDateFormat),
+		WeeklyThroughput: []WeeklyThroughput{},
+	}
+	firstWeek := weekStart(from)
+	for week := firstWeek; !week.After(to); week = week.AddDate(0, 0, 7) {
+		report.WeeklyThroughput = append(report.WeeklyThroughput, WeeklyThroughput{WeekStart: week.Format(projectors.DailyStatsDateFormat)})
+	}
+
+	var leadHours, cycleHours []float64
+	end := to.AddDate(0, 0, 1)
+	for _, raw := range raws {
+		var entry projectors.RuneTransitions
+		if json.Unmarshal(raw, &entry) != nil {
+			continue
+		}
+		fulfilled, claimed := entry.FulfilledAt()
+		if fulfilled.IsZero() || fulfilled.Before(from) || !fulfilled.Before(end) {
+			continue
+		}
+		report.Fulfilled++
+		report.WeeklyThroughput[int(weekStart(fulfilled).Sub(firstWeek).Hours()/(24*7))].Fulfilled++
+		if created := entry.CreatedAt(); !created.IsZero() {
+			leadHours = append(leadHours, fulfilled.Sub(created).Hours())
+		}
+		if !claimed.IsZero() {
+			cycleHours = append(cycleHours, fulfilled.Sub(claimed).Hours())
+		}
+	}
+	report.LeadTime = durationStats(leadHours)
+	report.CycleTime = durationStats(cycleHours)
+	writeJSON(w, http.StatusOK, report)
+}
+
+// weekStart returns the Monday that begins t's UTC week.
+func weekStart(t time.Time) time.Time {
+	day := statsDay(t)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func durationStats(hours []float64) DurationStats {
+	if len(hours) == 0 {
+		return DurationStats{}
+	}
+	sort.Float64s(hours)
+	var total float64
+	for _, h := range hours {
+		total += h
+	}
+	n := len(hours)
+	median := hours[n/2]
+	if n%2 == 0 {
+		median = (hours[n/2-1] + hours[n/2]) / 2
+	}
+	return DurationStats{
+		Count:        n,
+		AverageHours: total / float64(n),
+		MedianHours:  median,
+		P85Hours:     hours[int(math.Ceil(0.85*float64(n)))-1],
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestVelocityReportHandler(t *testing.T) {
+	t.Run("aggregates lead and cycle time of runes fulfilled in the range", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.rune_fulfilled_at("realm-1", "bf-a", "2026-03-02T09:00:00Z", "2026-03-03T09:00:00Z", "2026-03-03T11:00:00Z")
+		tc.rune_fulfilled_at("realm-1", "bf-b", "2026-03-02T09:00:00Z", "2026-03-05T09:00:00Z", "2026-03-05T13:00:00Z")
+		tc.rune_fulfilled_at("realm-1", "bf-c", "2026-03-09T09:00:00Z", "", "2026-03-09T15:00:00Z")
+
+		// When
+		tc.get("/reports/velocity?from=2026-03-02&to=2026-03-15")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		report := tc.velocity_report()
+		assert.Equal(t, 3, report.Fulfilled)
+		assert.Equal(t, DurationStats{Count: 3, AverageHours: 36, MedianHours: 26, P85Hours: 76}, report.LeadTime)
+		assert.Equal(t, DurationStats{Count: 2, AverageHours: 3, MedianHours: 3, P85Hours: 4}, report.CycleTime)
+	})
+
+	t.Run("counts throughput per Monday-based week including empty weeks", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.rune_fulfilled_at("realm-1", "bf-a", "2026-03-01T09:00:00Z", "", "2026-03-04T09:00:00Z")
+		tc.rune_fulfilled_at("realm-1", "bf-b", "2026-03-01T09:00:00Z", "", "2026-03-08T23:00:00Z")
+		tc.rune_fulfilled_at("realm-1", "bf-c", "2026-03-01T09:00:00Z", "", "2026-03-20T09:00:00Z")
+		tc.rune_fulfilled_at("realm-1", "bf-old", "2026-02-01T09:00:00Z", "", "2026-02-02T09:00:00Z")
+
+		// When
+		tc.get("/reports/velocity?from=2026-03-04&to=2026-03-20")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, []WeeklyThroughput{
+			{WeekStart: "2026-03-02", Fulfilled: 2},
+			{WeekStart: "2026-03-09", Fulfilled: 0},
+			{WeekStart: "2026-03-16", Fulfilled: 1},
+		}, tc.velocity_report().WeeklyThroughput)
+	})
+
+	t.Run("ignores runes that are not fulfilled", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+		tc.rune_fulfilled_at("realm-1", "bf-a", "2026-03-02T09:00:00Z", "2026-03-03T09:00:00Z", "")
+
+		// When
+		tc.get("/reports/velocity?from=2026-03-02&to=2026-03-08")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		report := tc.velocity_report()
+		assert.Equal(t, 0, report.Fulfilled)
+		assert.Equal(t, DurationStats{}, report.LeadTime)
+	})
+
+	t.Run("returns 400 for a malformed date", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/reports/velocity?from=March")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+
+	t.Run("returns 400 when from is after to", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.handlers_configured()
+		tc.request_has_realm_id("realm-1")
+
+		// When
+		tc.get("/reports/velocity?from=2026-03-10&to=2026-03-02")
+
+		// Then
+		tc.status_is(http.StatusBadRequest)
+	})
+}
+
+// --- Given ---
+
+// rune_fulfilled_at records a rune's transitions; empty claimed or fulfilled
+// times leave that transition out.
+func (tc *handlerTestContext) rune_fulfilled_at(realmID, runeID, created, claimed, fulfilled string) {
+	tc.t.Helper()
+	entry := projectors.RuneTransitions{RuneID: runeID}
+	for _, step := range []struct{ status, at string }{{"draft", created}, {"claimed", claimed}, {"fulfilled", fulfilled}} {
+		if step.at == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, step.at)
+		require.NoError(tc.t, err)
+		entry.Transitions = append(entry.Transitions, projectors.StatusTransition{Status: step.status, At: at})
+	}
+	_ = tc.projectionStore.Put(context.Background(), realmID, "rune_transitions", runeID, entry)
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) velocity_report() VelocityReport {
+	tc.t.Helper()
+	var report VelocityReport
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &report))
+	return report
+}
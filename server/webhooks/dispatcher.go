@@ -0,0 +1,272 @@
+// Package webhooks delivers domain events to HTTP endpoints registered per
+// realm.
+//
+// Each delivery is a JSON POST signed with the webhook's secret. The
+// X-Bifrost-Signature header carries "v1=<hex>", the HMAC-SHA256 of
+// "<X-Bifrost-Timestamp>.<body>", so receivers can authenticate the sender
+// and reject stale timestamps to prevent replays (see Verify).
+//
+// Like notify.Router, the Dispatcher is registered with the projection engine
+// and drops events that are too old or already dispatched in this process.
+// It remembers each webhook's recent deliveries in memory, so they are lost
+// on restart.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/devzeebo/bifrost/server/metrics"
+)
+
+// Payload is the JSON body of a delivery.
+type Payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	RealmID   string          `json:"realm_id"`
+	StreamID  string          `json:"stream_id"`
+	Version   int             `json:"version"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TestEventType is the type of the event SendTest delivers.
+const TestEventType = "WebhookTest"
+
+// recentDeliveries is how many deliveries are kept per webhook.
+const recentDeliveries = 20
+
+// Delivery is the outcome of posting one event to a webhook.
+type Delivery struct {
+	ID          string    `json:"id"`
+	WebhookID   string    `json:"webhook_id"`
+	EventType   string    `json:"event_type"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// Succeeded reports whether the receiver accepted the delivery.
+func (d Delivery) Succeeded() bool {
+	return d.Error == ""
+}
+
+type Dispatcher struct {
+	client *http.Client
+	maxAge time.Duration
+	now    func() time.Time
+
+	failures *metrics.CounterVec
+
+	mu         sync.Mutex
+	dispatched map[string]int64
+	deliveries map[string][]Delivery
+}
+
+type DispatcherOption func(*Dispatcher)
+
+// WithMaxEventAge sets how old an event may be and still be delivered.
+func WithMaxEventAge(age time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxAge = age
+	}
+}
+
+func NewDispatcher(client *http.Client, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		client:     client,
+		maxAge:     10 * time.Minute,
+		now:        time.Now,
+		dispatched: make(map[string]int64),
+		deliveries: make(map[string][]Delivery),
+		failures: metrics.NewCounterVec("bifrost_webhook_delivery_failures_total",
+			"Webhook deliveries that failed, by realm and webhook.", "realm", "webhook"),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *Dispatcher) Name() string {
+	return "webhooks"
+}
+
+// HasSideEffects reports true: deliveries cannot be recalled if a command
+// transaction rolls back.
+func (d *Dispatcher) HasSideEffects() bool {
+	return true
+}
+
+// Handle delivers the event to every webhook registered for its realm that
+// subscribes to its type. Delivery failures are logged rather than returned
+// so they never hold back the checkpoint.
+func (d *Dispatcher) Handle(ctx context.Context, event core.Event, store core.ProjectionStore) error {
+	if event.RealmID == domain.AdminRealmID || !d.claim(event) {
+		return nil
+	}
+	if !event.Timestamp.IsZero() && d.now().Sub(event.Timestamp) > d.maxAge {
+		return nil
+	}
+
+	raws, err := store.List(ctx, event.RealmID, "webhook_list")
+	if err != nil {
+		return err
+	}
+	for _, raw := range raws {
+		var hook projectors.WebhookListEntry
+		if json.Unmarshal(raw, &hook) != nil || hook.URL == "" || !subscribed(hook, event.EventType) {
+			continue
+		}
+		if err := d.Deliver(ctx, hook, event); err != nil {
+			d.failures.Inc(event.RealmID, hook.ID)
+			log.Printf("webhooks: deliver %s to %s: %v", event.EventType, hook.ID, err)
+		}
+	}
+	return nil
+}
+
+// Deliver posts a signed payload for event to the webhook and records the
+// outcome among the webhook's recent deliveries.
+func (d *Dispatcher) Deliver(ctx context.Context, hook projectors.WebhookListEntry, event core.Event) error {
+	return d.deliver(ctx, hook, event, fmt.Sprintf("%s-%d", event.RealmID, event.GlobalPosition))
+}
+
+// SendTest delivers a WebhookTest event to the webhook, so its receiver can
+// be checked without waiting for a real event.
+func (d *Dispatcher) SendTest(ctx context.Context, hook projectors.WebhookListEntry) error {
+	now := d.now().UTC()
+	data, err := json.Marshal(map[string]string{"webhook_id": hook.ID})
+	if err != nil {
+		return err
+	}
+	event := core.Event{
+		RealmID:   hook.RealmID,
+		StreamID:  "webhook-" + hook.ID,
+		EventType: TestEventType,
+		Data:      data,
+		Timestamp: now,
+	}
+	return d.deliver(ctx, hook, event, fmt.Sprintf("%s-test-%d", hook.ID, now.UnixNano()))
+}
+
+// Deliveries returns the webhook's recent deliveries, newest first.
+func (d *Dispatcher) Deliveries(webhookID string) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Delivery{}, d.deliveries[webhookID]...)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, hook projectors.WebhookListEntry, event core.Event, deliveryID string) error {
+	started := d.now()
+	status, err := d.post(ctx, hook, event, deliveryID)
+	delivery := Delivery{
+		ID:          deliveryID,
+		WebhookID:   hook.ID,
+		EventType:   event.EventType,
+		StatusCode:  status,
+		DurationMS:  d.now().Sub(started).Milliseconds(),
+		DeliveredAt: started.UTC(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	d.record(delivery)
+	return err
+}
+
+// post sends the signed payload, returning the response status when the
+// receiver answered at all.
+func (d *Dispatcher) post(ctx context.Context, hook projectors.WebhookListEntry, event core.Event, deliveryID string) (int, error) {
+	body, err := json.Marshal(Payload{
+		ID:        deliveryID,
+		Type:      event.EventType,
+		RealmID:   event.RealmID,
+		StreamID:  event.StreamID,
+		Version:   event.Version,
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := d.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.EventType)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return res.StatusCode, nil
+}
+
+func (d *Dispatcher) record(delivery Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	recent := append([]Delivery{delivery}, d.deliveries[delivery.WebhookID]...)
+	if len(recent) > recentDeliveries {
+		recent = recent[:recentDeliveries]
+	}
+	d.deliveries[delivery.WebhookID] = recent
+}
+
+// Collect reports delivery failures since the process started.
+func (d *Dispatcher) Collect(ctx context.Context) ([]metrics.Family, error) {
+	return d.failures.Collect(ctx)
+}
+
+// claim records the event as dispatched, returning false when it already
+// was. Catch-up cycles can overlap, so the same event may arrive twice.
+func (d *Dispatcher) claim(event core.Event) bool {
+	if event.GlobalPosition == 0 {
+		return true
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if event.GlobalPosition <= d.dispatched[event.RealmID] {
+		return false
+	}
+	d.dispatched[event.RealmID] = event.GlobalPosition
+	return true
+}
+
+// subscribed reports whether the webhook wants eventType. A webhook without
+// an event filter receives every event.
+func subscribed(hook projectors.WebhookListEntry, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/core"
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestDispatcher(t *testing.T) {
+	t.Run("Name returns webhooks", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Then
+		assert.Equal(t, "webhooks", tc.dispatcher.Name())
+	})
+
+	t.Run("delivers signed payload to registered webhook", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret")
+		tc.an_event(7, domain.EventRuneCreated)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.received, 1)
+		req := tc.received[0]
+		assert.Equal(t, domain.EventRuneCreated, req.header.Get(EventHeader))
+		assert.Equal(t, "realm-1-7", req.header.Get(DeliveryHeader))
+		assert.NoError(t, Verify("s3cret", req.header.Get(SignatureHeader), req.header.Get(TimestampHeader), req.body, DefaultTolerance, time.Now()))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, "realm-1-7", payload.ID)
+		assert.Equal(t, domain.EventRuneCreated, payload.Type)
+		assert.Equal(t, "realm-1", payload.RealmID)
+		assert.JSONEq(t, `{"id":"bf-a1b2"}`, string(payload.Data))
+	})
+
+	t.Run("skips webhooks filtering out the event type", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret", domain.EventRuneFulfilled)
+		tc.an_event(1, domain.EventRuneCreated)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Empty(t, tc.received)
+	})
+
+	t.Run("does not deliver the same event twice", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret")
+		tc.an_event(1, domain.EventRuneCreated)
+
+		// When
+		tc.handle_is_called()
+		tc.handle_is_called()
+
+		// Then
+		assert.Len(t, tc.received, 1)
+	})
+
+	t.Run("skips events older than max age", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret")
+		tc.an_event(1, domain.EventRuneCreated)
+		tc.event.Timestamp = time.Now().Add(-time.Hour)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		assert.Empty(t, tc.received)
+	})
+
+	t.Run("does not fail the checkpoint when delivery fails", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.status = http.StatusInternalServerError
+		tc.a_webhook("wh-1", "s3cret")
+		tc.an_event(1, domain.EventRuneCreated)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		tc.no_error()
+		assert.Len(t, tc.received, 1)
+		assert.Equal(t, 1.0, tc.dispatcher.failures.Value("realm-1", "wh-1"))
+	})
+
+	t.Run("records recent deliveries newest first", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret")
+		tc.an_event(1, domain.EventRuneCreated)
+		tc.handle_is_called()
+		tc.status = http.StatusInternalServerError
+		tc.an_event(2, domain.EventRuneFulfilled)
+
+		// When
+		tc.handle_is_called()
+
+		// Then
+		deliveries := tc.dispatcher.Deliveries("wh-1")
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, "realm-1-2", deliveries[0].ID)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].StatusCode)
+		assert.False(t, deliveries[0].Succeeded())
+		assert.Equal(t, domain.EventRuneCreated, deliveries[1].EventType)
+		assert.True(t, deliveries[1].Succeeded())
+	})
+
+	t.Run("keeps only the most recent deliveries", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.a_webhook("wh-1", "s3cret")
+
+		// When
+		for i := 1; i <= recentDeliveries+5; i++ {
+			tc.an_event(int64(i), domain.EventRuneCreated)
+			tc.handle_is_called()
+		}
+
+		// Then
+		deliveries := tc.dispatcher.Deliveries("wh-1")
+		assert.Len(t, deliveries, recentDeliveries)
+		assert.Equal(t, fmt.Sprintf("realm-1-%d", recentDeliveries+5), deliveries[0].ID)
+	})
+}
+
+func TestDispatcherSendTest(t *testing.T) {
+	t.Run("delivers a signed test event and records it", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// When
+		tc.err = tc.dispatcher.SendTest(context.Background(), projectors.WebhookListEntry{
+			ID: "wh-1", RealmID: "realm-1", URL: tc.server.URL, Secret: "s3cret",
+		})
+
+		// Then
+		tc.no_error()
+		require.Len(t, tc.received, 1)
+		req := tc.received[0]
+		assert.Equal(t, TestEventType, req.header.Get(EventHeader))
+		assert.NoError(t, Verify("s3cret", req.header.Get(SignatureHeader), req.header.Get(TimestampHeader), req.body, DefaultTolerance, time.Now()))
+		deliveries := tc.dispatcher.Deliveries("wh-1")
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, TestEventType, deliveries[0].EventType)
+	})
+
+	t.Run("returns the receiver's rejection", func(t *testing.T) {
+		tc := newDispatcherTestContext(t)
+
+		// Given
+		tc.status = http.StatusUnauthorized
+
+		// When
+		tc.err = tc.dispatcher.SendTest(context.Background(), projectors.WebhookListEntry{
+			ID: "wh-1", RealmID: "realm-1", URL: tc.server.URL, Secret: "s3cret",
+		})
+
+		// Then
+		assert.ErrorContains(t, tc.err, "unexpected status 401")
+	})
+}
+
+// --- Test Context ---
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type dispatcherTestContext struct {
+	t *testing.T
+
+	dispatcher *Dispatcher
+	server     *httptest.Server
+	store      *mockProjectionStore
+	status     int
+	event      core.Event
+	received   []receivedRequest
+	err        error
+}
+
+func newDispatcherTestContext(t *testing.T) *dispatcherTestContext {
+	t.Helper()
+	tc := &dispatcherTestContext{
+		t:          t,
+		dispatcher: NewDispatcher(http.DefaultClient),
+		store:      &mockProjectionStore{lists: make(map[string][]json.RawMessage)},
+		status:     http.StatusNoContent,
+	}
+	tc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		tc.received = append(tc.received, receivedRequest{header: r.Header.Clone(), body: body})
+		w.WriteHeader(tc.status)
+	}))
+	t.Cleanup(tc.server.Close)
+	return tc
+}
+
+// --- Given ---
+
+func (tc *dispatcherTestContext) a_webhook(id, secret string, events ...string) {
+	tc.t.Helper()
+	raw, err := json.Marshal(projectors.WebhookListEntry{
+		ID: id, RealmID: "realm-1", URL: tc.server.URL, Secret: secret, Events: events,
+	})
+	require.NoError(tc.t, err)
+	tc.store.lists["realm-1:webhook_list"] = append(tc.store.lists["realm-1:webhook_list"], raw)
+}
+
+func (tc *dispatcherTestContext) an_event(position int64, eventType string) {
+	tc.t.Helper()
+	tc.event = core.Event{
+		RealmID:        "realm-1",
+		StreamID:       "rune-bf-a1b2",
+		Version:        1,
+		GlobalPosition: position,
+		EventType:      eventType,
+		Data:           []byte(`{"id":"bf-a1b2"}`),
+		Timestamp:      time.Now(),
+	}
+}
+
+// --- When ---
+
+func (tc *dispatcherTestContext) handle_is_called() {
+	tc.t.Helper()
+	tc.err = tc.dispatcher.Handle(context.Background(), tc.event, tc.store)
+}
+
+// --- Then ---
+
+func (tc *dispatcherTestContext) no_error() {
+	tc.t.Helper()
+	require.NoError(tc.t, tc.err)
+}
+
+// --- Mock Projection Store ---
+
+type mockProjectionStore struct {
+	lists map[string][]json.RawMessage
+}
+
+func (m *mockProjectionStore) Get(_ context.Context, _ string, projectionName string, key string, _ any) error {
+	return &core.NotFoundError{Entity: projectionName, ID: key}
+}
+
+func (m *mockProjectionStore) Put(_ context.Context, _ string, _ string, _ string, _ any) error {
+	return nil
+}
+
+func (m *mockProjectionStore) List(_ context.Context, realmID string, projectionName string) ([]json.RawMessage, error) {
+	return m.lists[realmID+":"+projectionName], nil
+}
+
+func (m *mockProjectionStore) Delete(_ context.Context, _ string, _ string, _ string) error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	EventHeader     = "X-Bifrost-Event"
+	DeliveryHeader  = "X-Bifrost-Delivery"
+	TimestampHeader = "X-Bifrost-Timestamp"
+	SignatureHeader = "X-Bifrost-Signature"
+)
+
+// DefaultTolerance is how far a delivery's timestamp may drift from the
+// receiver's clock before Verify treats it as a replay.
+const DefaultTolerance = 5 * time.Minute
+
+const signatureVersion = "v1"
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidTimestamp = errors.New("invalid webhook timestamp")
+	ErrExpiredTimestamp = errors.New("webhook timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("webhook signature mismatch")
+)
+
+// Sign returns the signature header value for body sent at timestamp: the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed by secret, prefixed with the
+// scheme version.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return signatureVersion + "=" + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+// Verify checks a delivery's signature and timestamp headers against body.
+// Deliveries whose timestamp differs from now by more than tolerance are
+// rejected so a captured request cannot be replayed later.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if drift := now.Sub(time.Unix(ts, 0)); drift > tolerance || drift < -tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	expected := mac(secret, ts, body)
+	for _, part := range strings.Split(signature, ",") {
+		version, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		got, err := hex.DecodeString(value)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Tests ---
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"RuneCreated"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	t.Run("accepts a signature produced by Sign", func(t *testing.T) {
+		signature := Sign("s3cret", now.Unix(), body)
+
+		assert.NoError(t, Verify("s3cret", signature, timestamp, body, DefaultTolerance, now))
+	})
+
+	t.Run("accepts a matching signature among several versions", func(t *testing.T) {
+		signature := "v0=deadbeef, " + Sign("s3cret", now.Unix(), body)
+
+		assert.NoError(t, Verify("s3cret", signature, timestamp, body, DefaultTolerance, now))
+	})
+
+	t.Run("rejects a different secret", func(t *testing.T) {
+		signature := Sign("other", now.Unix(), body)
+
+		assert.ErrorIs(t, Verify("s3cret", signature, timestamp, body, DefaultTolerance, now), ErrInvalidSignature)
+	})
+
+	t.Run("rejects a tampered body", func(t *testing.T) {
+		signature := Sign("s3cret", now.Unix(), body)
+
+		assert.ErrorIs(t, Verify("s3cret", signature, timestamp, []byte(`{}`), DefaultTolerance, now), ErrInvalidSignature)
+	})
+
+	t.Run("rejects a timestamp that does not match the signature", func(t *testing.T) {
+		signature := Sign("s3cret", now.Unix(), body)
+		shifted := strconv.FormatInt(now.Unix()+1, 10)
+
+		assert.ErrorIs(t, Verify("s3cret", signature, shifted, body, DefaultTolerance, now), ErrInvalidSignature)
+	})
+
+	t.Run("rejects replays outside the tolerance", func(t *testing.T) {
+		signature := Sign("s3cret", now.Unix(), body)
+
+		err := Verify("s3cret", signature, timestamp, body, DefaultTolerance, now.Add(DefaultTolerance+time.Second))
+
+		assert.ErrorIs(t, err, ErrExpiredTimestamp)
+	})
+
+	t.Run("rejects missing headers", func(t *testing.T) {
+		assert.ErrorIs(t, Verify("s3cret", "", timestamp, body, DefaultTolerance, now), ErrMissingSignature)
+		assert.ErrorIs(t, Verify("s3cret", "v1=00", "", body, DefaultTolerance, now), ErrMissingSignature)
+	})
+
+	t.Run("rejects a malformed timestamp", func(t *testing.T) {
+		assert.ErrorIs(t, Verify("s3cret", "v1=00", "yesterday", body, DefaultTolerance, now), ErrInvalidTimestamp)
+	})
+}
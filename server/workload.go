@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+)
+
+// WorkloadResponse is an account's claimed runes across realms.
+type WorkloadResponse struct {
+	AccountID string         `json:"account_id"`
+	Count     int            `json:"count"`
+	Runes     []WorkloadItem `json:"runes"`
+}
+
+// WorkloadItem is a claimed rune with how long it has been claimed.
+type WorkloadItem struct {
+	projectors.WorkloadRune
+	AgeSeconds int64 `json:"age_seconds"`
+}
+
+// AccountWorkload serves GET /accounts/{id}/workload: the runes the account
+// has claimed, most urgent first and then longest claimed, limited to the
+// realms the caller can see. The id "me" is the caller's own account.
+func (h *Handlers) AccountWorkload(w http.ResponseWriter, r *http.Request) {
+	realmID, ok := RealmIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "realm ID required")
+		return
+	}
+	callerID, _ := AccountIDFromContext(r.Context())
+	accountID := r.PathValue("id")
+	if accountID == "me" {
+		if callerID == "" {
+			writeError(w, http.StatusForbidden, "account ID required")
+			return
+		}
+		accountID = callerID
+	}
+
+	workload, err := projectors.GetAccountWorkload(r.Context(), h.projectionStore, accountID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get workload")
+		return
+	}
+	visible, err := h.visibleRealms(r, realmID, callerID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get workload")
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := WorkloadResponse{AccountID: accountID, Runes: []WorkloadItem{}}
+	for _, claimed := range workload.Runes {
+		if visible != nil && !visible[claimed.RealmID] {
+			continue
+		}
+		resp.Runes = append(resp.Runes, WorkloadItem{
+			WorkloadRune: claimed,
+			AgeSeconds:   int64(now.Sub(claimed.ClaimedAt) / time.Second),
+		})
+	}
+	sort.SliceStable(resp.Runes, func(i, j int) bool {
+		return resp.Runes[i].Priority < resp.Runes[j].Priority
+	})
+	resp.Count = len(resp.Runes)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// visibleRealms returns the realms the caller holds a role in, or nil when
+// the caller is a system admin and can see every realm. A caller without an
+// account sees only the realm of the request.
+func (h *Handlers) visibleRealms(r *http.Request, realmID, callerID string) (map[string]bool, error) {
+	if callerID == "" {
+		return map[string]bool{realmID: true}, nil
+	}
+	var accountEntry struct {
+		Roles map[string]string `json:"roles"`
+	}
+	if err := h.projectionStore.Get(r.Context(), domain.AdminRealmID, "account_list", callerID, &accountEntry); err != nil {
+		if isNotFound(err) {
+			return map[string]bool{realmID: true}, nil
+		}
+		return nil, err
+	}
+	if role := accountEntry.Roles[domain.AdminRealmID]; role == "admin" || role == "owner" {
+		return nil, nil
+	}
+	visible := map[string]bool{realmID: true}
+	for id := range accountEntry.Roles {
+		visible[id] = true
+	}
+	return visible, nil
+}
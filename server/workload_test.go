@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devzeebo/bifrost/domain"
+	"github.com/devzeebo/bifrost/domain/projectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Tests ---
+
+func TestAccountWorkloadHandler(t *testing.T) {
+	t.Run("returns the caller's claims, most urgent first", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.account_has_roles("acct-1", map[string]string{"realm-1": "member", "realm-2": "member"})
+		tc.account_has_workload("acct-1",
+			projectors.WorkloadRune{RealmID: "realm-2", RuneID: "bf-b1", Priority: 3, ClaimedAt: time.Now().Add(-2 * time.Hour)},
+			projectors.WorkloadRune{RealmID: "realm-1", RuneID: "bf-a1", Priority: 1, ClaimedAt: time.Now().Add(-time.Hour)},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/accounts/me/workload")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		resp := tc.workload_response()
+		assert.Equal(t, "acct-1", resp.AccountID)
+		assert.Equal(t, 2, resp.Count)
+		require.Len(t, resp.Runes, 2)
+		assert.Equal(t, "bf-a1", resp.Runes[0].RuneID)
+		assert.Equal(t, "bf-b1", resp.Runes[1].RuneID)
+		assert.InDelta(t, 7200, resp.Runes[1].AgeSeconds, 5)
+	})
+
+	t.Run("leaves out claims in realms the caller has no role in", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-2")
+		tc.account_has_roles("acct-2", map[string]string{"realm-1": "admin"})
+		tc.account_has_workload("acct-1",
+			projectors.WorkloadRune{RealmID: "realm-1", RuneID: "bf-a1", ClaimedAt: time.Now()},
+			projectors.WorkloadRune{RealmID: "realm-2", RuneID: "bf-b1", ClaimedAt: time.Now()},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/accounts/acct-1/workload")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		resp := tc.workload_response()
+		require.Len(t, resp.Runes, 1)
+		assert.Equal(t, "bf-a1", resp.Runes[0].RuneID)
+	})
+
+	t.Run("shows system admins claims in every realm", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-admin")
+		tc.account_has_roles("acct-admin", map[string]string{domain.AdminRealmID: "admin"})
+		tc.account_has_workload("acct-1",
+			projectors.WorkloadRune{RealmID: "realm-1", RuneID: "bf-a1", ClaimedAt: time.Now()},
+			projectors.WorkloadRune{RealmID: "realm-2", RuneID: "bf-b1", ClaimedAt: time.Now()},
+		)
+		tc.handlers_configured()
+
+		// When
+		tc.get("/accounts/acct-1/workload")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		assert.Equal(t, 2, tc.workload_response().Count)
+	})
+
+	t.Run("returns an empty workload for an account without claims", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.request_has_account_id("acct-1")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/accounts/acct-9/workload")
+
+		// Then
+		tc.status_is(http.StatusOK)
+		tc.response_body_contains(`"runes":[]`)
+	})
+
+	t.Run("requires an account for me", func(t *testing.T) {
+		tc := newHandlerTestContext(t)
+
+		// Given
+		tc.request_has_realm_id("realm-1")
+		tc.handlers_configured()
+
+		// When
+		tc.get("/accounts/me/workload")
+
+		// Then
+		tc.status_is(http.StatusForbidden)
+	})
+}
+
+// --- Given ---
+
+func (tc *handlerTestContext) account_has_roles(accountID string, roles map[string]string) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), domain.AdminRealmID, "account_list", accountID, map[string]any{
+		"account_id": accountID,
+		"roles":      roles,
+	})
+}
+
+func (tc *handlerTestContext) account_has_workload(accountID string, runes ...projectors.WorkloadRune) {
+	tc.t.Helper()
+	_ = tc.projectionStore.Put(context.Background(), domain.AdminRealmID, "claims_by_account", accountID, projectors.AccountWorkload{
+		AccountID: accountID,
+		Runes:     runes,
+	})
+}
+
+// --- Then ---
+
+func (tc *handlerTestContext) workload_response() WorkloadResponse {
+	tc.t.Helper()
+	var resp WorkloadResponse
+	require.NoError(tc.t, json.Unmarshal(tc.recorder.Body.Bytes(), &resp))
+	return resp
+}
@@ -10,6 +10,7 @@ type AuthContextValue = {
   roles: Record<string, string>;
   realms: string[];
   realmNames: Record<string, string>;
+  hostRealm: string | null;
   isSysadmin: boolean;
   login: (pat: string) => Promise<void>;
   logout: () => Promise<void>;
@@ -48,6 +49,7 @@ const createMockAuthValue = (
   roles: {},
   realms: [],
   realmNames: {},
+  hostRealm: null,
   isSysadmin: false,
   login: vi.fn().mockResolvedValue(undefined),
   logout: vi.fn().mockResolvedValue(undefined),
@@ -174,6 +174,31 @@ describe("ApiClient", () => {
     });
   });
 
+  describe("getDashboard", () => {
+    test("sends GET request to /api/dashboard with realm header", async () => {
+      const stats = { total: 1, status_counts: { open: 1 }, recent: [] };
+
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => stats,
+      });
+
+      const result = await apiClient.getDashboard("test-realm");
+
+      expect(mockFetch).toHaveBeenCalledWith(
+        "/api/dashboard",
+        expect.objectContaining({
+          method: "GET",
+          headers: expect.objectContaining({
+            "X-Bifrost-Realm": "test-realm",
+          }),
+          credentials: "include",
+        })
+      );
+      expect(result).toEqual(stats);
+    });
+  });
+
   describe("getRunes", () => {
     test("sends GET request to /api/runes with realm header", async () => {
       const runes = [
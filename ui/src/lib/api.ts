@@ -9,7 +9,9 @@ import type {
   RuneListItem,
   RuneDetail,
   CreateRuneRequest,
+  CreateRuneResponse,
   RuneRelationship,
+  DashboardStats,
 } from "../types/rune";
 import type {
   RealmListEntry,
@@ -17,7 +19,32 @@ import type {
   CreateRealmRequest,
   CreateRealmResponse,
 } from "../types/realm";
-import type { AccountListEntry, AdminAccountEntry, PatEntry } from "../types/account";
+import type {
+  AccountListEntry,
+  AdminAccountEntry,
+  NotificationPreferences,
+  PatEntry,
+} from "../types/account";
+import type { GitHubImportRequest, GitHubImportResult } from "../types/import";
+import type {
+  AutomationRule,
+  AddAutomationRuleRequest,
+  AddAutomationRuleResponse,
+} from "../types/automation";
+import type {
+  Schedule,
+  CreateScheduleRequest,
+  CreateScheduleResponse,
+} from "../types/schedule";
+import type { FormDraft } from "../types/draft";
+import type {
+  Webhook,
+  WebhookDelivery,
+  RegisterWebhookRequest,
+  RegisterWebhookResponse,
+} from "../types/webhook";
+import type { BackupStatus } from "../types/backup";
+import type { ReplayProjector, ReplayReport, ReplayRequest } from "../types/replay";
 
 const API_PREFIX = "/api";
 
@@ -198,6 +225,13 @@ export class ApiClient {
     }
   }
 
+  async getDashboard(realmId: string): Promise<DashboardStats> {
+    return this.request<DashboardStats>("/dashboard", {
+      method: "GET",
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
   async getRune(realmId: string, runeId: string): Promise<RuneDetail> {
     try {
       const detail = await this.request<Partial<RuneDetail> & { id: string }>(
@@ -220,8 +254,8 @@ export class ApiClient {
     }
   }
 
-  async createRune(request: CreateRuneRequest, realmId?: string): Promise<RuneDetail> {
-    return this.request<RuneDetail>("/create-rune", {
+  async createRune(request: CreateRuneRequest, realmId?: string): Promise<CreateRuneResponse> {
+    return this.request<CreateRuneResponse>("/create-rune", {
       method: "POST",
       body: JSON.stringify(request),
       headers: this.withRealmHeader(realmId),
@@ -380,6 +414,28 @@ export class ApiClient {
     });
   }
 
+  async getBackupStatus(): Promise<BackupStatus> {
+    return this.request<BackupStatus>("/backup-status", {
+      method: "GET",
+      headers: this.withRealmHeader("_admin"),
+    });
+  }
+
+  async getReplayProjectors(): Promise<ReplayProjector[]> {
+    return this.request<ReplayProjector[]>("/replay-projectors", {
+      method: "GET",
+      headers: this.withRealmHeader("_admin"),
+    });
+  }
+
+  async replayProjections(request: ReplayRequest): Promise<ReplayReport> {
+    return this.request<ReplayReport>("/replay-projections", {
+      method: "POST",
+      body: JSON.stringify(request),
+      headers: this.withRealmHeader("_admin"),
+    });
+  }
+
   async assignRole(
     request: { account_id: string; realm_id: string; role: string },
     realmId?: string
@@ -402,6 +458,138 @@ export class ApiClient {
     });
   }
 
+  // Import
+  async importGitHub(request: GitHubImportRequest, realmId: string): Promise<GitHubImportResult> {
+    return this.request<GitHubImportResult>("/import-github", {
+      method: "POST",
+      body: JSON.stringify(request),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  // Automation rules
+  async getAutomationRules(realmId: string): Promise<AutomationRule[]> {
+    return this.request<AutomationRule[]>("/automation-rules", {
+      method: "GET",
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async addAutomationRule(
+    request: AddAutomationRuleRequest,
+    realmId: string
+  ): Promise<AddAutomationRuleResponse> {
+    return this.request<AddAutomationRuleResponse>("/add-automation-rule", {
+      method: "POST",
+      body: JSON.stringify(request),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async removeAutomationRule(ruleId: string, realmId: string): Promise<void> {
+    return this.request("/remove-automation-rule", {
+      method: "POST",
+      body: JSON.stringify({ rule_id: ruleId }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  // Schedules
+  async getSchedules(realmId: string): Promise<Schedule[]> {
+    return this.request<Schedule[]>("/schedules", {
+      method: "GET",
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async createSchedule(
+    request: CreateScheduleRequest,
+    realmId: string
+  ): Promise<CreateScheduleResponse> {
+    return this.request<CreateScheduleResponse>("/create-schedule", {
+      method: "POST",
+      body: JSON.stringify(request),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async deleteSchedule(scheduleId: string, realmId: string): Promise<void> {
+    return this.request("/delete-schedule", {
+      method: "POST",
+      body: JSON.stringify({ schedule_id: scheduleId }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  // Outbound webhooks
+  async getWebhooks(realmId: string): Promise<Webhook[]> {
+    return this.request<Webhook[]>("/webhooks", {
+      method: "GET",
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async registerWebhook(
+    request: RegisterWebhookRequest,
+    realmId: string
+  ): Promise<RegisterWebhookResponse> {
+    return this.request<RegisterWebhookResponse>("/register-webhook", {
+      method: "POST",
+      body: JSON.stringify(request),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async removeWebhook(webhookId: string, realmId: string): Promise<void> {
+    return this.request("/remove-webhook", {
+      method: "POST",
+      body: JSON.stringify({ webhook_id: webhookId }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async getWebhookDeliveries(webhookId: string, realmId: string): Promise<WebhookDelivery[]> {
+    return this.request<WebhookDelivery[]>(
+      `/webhook-deliveries?webhook_id=${encodeURIComponent(webhookId)}`,
+      {
+        method: "GET",
+        headers: this.withRealmHeader(realmId),
+      }
+    );
+  }
+
+  async testWebhook(webhookId: string, realmId: string): Promise<WebhookDelivery> {
+    return this.request<WebhookDelivery>("/test-webhook", {
+      method: "POST",
+      body: JSON.stringify({ webhook_id: webhookId }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  // Form drafts
+  async getDraft<T>(form: string, realmId: string): Promise<FormDraft<T>> {
+    return this.request<FormDraft<T>>(`/drafts?form=${encodeURIComponent(form)}`, {
+      method: "GET",
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async saveDraft(form: string, fields: unknown, realmId: string): Promise<void> {
+    return this.request("/save-draft", {
+      method: "POST",
+      body: JSON.stringify({ form, fields }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
+  async discardDraft(form: string, realmId: string): Promise<void> {
+    return this.request("/discard-draft", {
+      method: "POST",
+      body: JSON.stringify({ form }),
+      headers: this.withRealmHeader(realmId),
+    });
+  }
+
   // Accounts
   async getAccounts(realmId: string): Promise<AccountListEntry[]> {
     return this.request<AccountListEntry[]>(`/realms/${realmId}/accounts`, {
@@ -481,6 +669,22 @@ export class ApiClient {
     });
   }
 
+  // Notification preferences (the signed-in account's own)
+  async getNotificationPreferences(): Promise<NotificationPreferences> {
+    return this.request<NotificationPreferences>("/notification-preferences", {
+      method: "GET",
+    });
+  }
+
+  async setNotificationPreferences(
+    preferences: Pick<NotificationPreferences, "muted" | "digest">
+  ): Promise<NotificationPreferences> {
+    return this.request<NotificationPreferences>("/notification-preferences", {
+      method: "POST",
+      body: JSON.stringify(preferences),
+    });
+  }
+
   async suspendAccount(accountId: string, suspend = true): Promise<void> {
     return this.request("/suspend-account", {
       method: "POST",
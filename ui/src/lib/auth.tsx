@@ -16,6 +16,7 @@ interface AuthContextValue {
   roles: Record<string, string>;
   realms: string[];
   realmNames: Record<string, string>;
+  hostRealm: string | null;
   isSysadmin: boolean;
   login: (pat: string, rememberMe?: boolean) => Promise<void>;
   logout: () => Promise<void>;
@@ -65,6 +66,7 @@ export function AuthProvider({ children }: AuthProviderProps) {
     roles: session?.roles ?? {},
     realms: session?.realms ?? [],
     realmNames: session?.realm_names ?? {},
+    hostRealm: session?.host_realm ?? null,
     isSysadmin: session?.is_sysadmin ?? false,
     login,
     logout,
@@ -0,0 +1,115 @@
+"use client";
+
+import { useCallback, useEffect, useRef, useState } from "react";
+import { ApiError, api } from "./api";
+
+// How long a form must sit unchanged before it is saved as a draft.
+const AUTOSAVE_DELAY_MS = 2000;
+
+type FormDraftState = {
+  // When the draft restored into the form was saved, if one was restored.
+  restoredAt: string | null;
+  // Deletes the server-side draft. Autosave stops, for a submitted form,
+  // unless the values the form is reset to are given.
+  discard: (resetTo?: T) => Promise<void>;
+};
+
+/**
+ * Autosaves a form's values to the server as the account's draft of the form,
+ * and restores a saved draft when the form opens. Nothing is saved until the
+ * existing draft has been looked up, so an empty form never overwrites it.
+ */
+export function useFormDraft<T>(
+  form: string,
+  realmId: string | null,
+  values: T,
+  restore: (fields: T) => void,
+  enabled = true
+): FormDraftState {
+  const [loadedKey, setLoadedKey] = useState<string | null>(null);
+  const [restoredAt, setRestoredAt] = useState<string | null>(null);
+  const lastSaved = useRef<string | null>(null);
+  const restoreRef = useRef(restore);
+  restoreRef.current = restore;
+  const serialized = JSON.stringify(values);
+  const serializedRef = useRef(serialized);
+  serializedRef.current = serialized;
+
+  const key = enabled && realmId ? `${realmId}:${form}` : null;
+
+  useEffect(() => {
+    if (!key || !realmId) {
+      return;
+    }
+    let cancelled = false;
+    setLoadedKey(null);
+    setRestoredAt(null);
+    lastSaved.current = null;
+
+    api
+      .getDraft<T>(form, realmId)
+      .then((draft) => {
+        if (cancelled) {
+          return;
+        }
+        lastSaved.current = JSON.stringify(draft.fields);
+        restoreRef.current(draft.fields);
+        setRestoredAt(draft.updated_at);
+      })
+      .catch((error) => {
+        if (cancelled) {
+          return;
+        }
+        // The form as opened is the baseline; only changes to it are saved.
+        lastSaved.current = serializedRef.current;
+        if (!(error instanceof ApiError && error.status === 404)) {
+          console.warn("Failed to load form draft", error);
+        }
+      })
+      .finally(() => {
+        if (!cancelled) {
+          setLoadedKey(key);
+        }
+      });
+
+    return () => {
+      cancelled = true;
+    };
+  }, [form, key, realmId]);
+
+  useEffect(() => {
+    if (!key || !realmId || loadedKey !== key || serialized === lastSaved.current) {
+      return;
+    }
+    const timer = setTimeout(() => {
+      api
+        .saveDraft(form, JSON.parse(serialized), realmId)
+        .then(() => {
+          lastSaved.current = serialized;
+        })
+        .catch((error) => console.warn("Failed to autosave form draft", error));
+    }, AUTOSAVE_DELAY_MS);
+
+    return () => clearTimeout(timer);
+  }, [form, key, loadedKey, realmId, serialized]);
+
+  const discard = useCallback(async (resetTo?: T) => {
+    if (!realmId) {
+      return;
+    }
+    setRestoredAt(null);
+    if (resetTo === undefined) {
+      // Stop the pending autosave from recreating the draft.
+      setLoadedKey(null);
+    } else {
+      lastSaved.current = JSON.stringify(resetTo);
+    }
+    try {
+      await api.discardDraft(form, realmId);
+    } catch (error) {
+      console.warn("Failed to discard form draft", error);
+    }
+  }, [form, realmId]);
+
+  return { restoredAt, discard };
+}
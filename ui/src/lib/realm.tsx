@@ -97,6 +97,7 @@ export function RealmProvider({ children }: { children: ReactNode }) {
   const {
     realms: sessionRealms,
     realmNames,
+    hostRealm,
     isAuthenticated,
     loading: authLoading,
   } = useAuth();
@@ -104,7 +105,10 @@ export function RealmProvider({ children }: { children: ReactNode }) {
   // Load available realms and restore persisted realm
   useEffect(() => {
     const applyRealmSelection = (rawRealms: Array<RealmOption | null | undefined>) => {
-      const options = normalizeRealmOptions(rawRealms);
+      // A realm's own hostname scopes the UI to that realm
+      const options = normalizeRealmOptions(rawRealms).filter(
+        (option) => !hostRealm || option.id === hostRealm
+      );
       const realms = options.map((option) => option.id);
       setRealmOptions(options);
       setAvailableRealms(realms);
@@ -179,7 +183,7 @@ export function RealmProvider({ children }: { children: ReactNode }) {
     };
 
     init();
-  }, [authLoading, isAuthenticated, realmNames, sessionRealms]);
+  }, [authLoading, hostRealm, isAuthenticated, realmNames, sessionRealms]);
 
   const setCurrentRealm = (realm: string | null) => {
     const nextRealm = realm && availableRealms.includes(realm) ? realm : null;
@@ -7,7 +7,11 @@ import { useAuth } from "../../lib/auth";
 import { useToast } from "../../lib/toast";
 import { api } from "../../lib/api";
 import { Dialog } from "../../components/Dialog/Dialog";
-import type { PatEntry } from "../../types/account";
+import type {
+  DigestFrequency,
+  NotificationPreferences,
+  PatEntry,
+} from "../../types/account";
 
 export { Page };
 
@@ -18,6 +22,8 @@ function Page() {
   const [isCreatingPAT, setIsCreatingPAT] = useState(false);
   const [revokingPATId, setRevokingPATId] = useState<string | null>(null);
   const [patToRevoke, setPatToRevoke] = useState<string | null>(null);
+  const [preferences, setPreferences] = useState<NotificationPreferences | null>(null);
+  const [isSavingPreferences, setIsSavingPreferences] = useState(false);
 
   const {
     isAuthenticated,
@@ -40,8 +46,50 @@ function Page() {
     }
 
     fetchPATs();
+    fetchPreferences();
   }, [authLoading, isAuthenticated]);
 
+  const fetchPreferences = async () => {
+    try {
+      setPreferences(await api.getNotificationPreferences());
+    } catch (error) {
+      showToast("Error", "Failed to load notification preferences", "error");
+    }
+  };
+
+  const isMuted = (channel: string, kind: string) =>
+    preferences?.muted[channel]?.includes(kind) ?? false;
+
+  const toggleMuted = (channel: string, kind: string) => {
+    if (!preferences) return;
+    const current = preferences.muted[channel] ?? [];
+    const next = current.includes(kind)
+      ? current.filter((k) => k !== kind)
+      : [...current, kind];
+    setPreferences({
+      ...preferences,
+      muted: { ...preferences.muted, [channel]: next },
+    });
+  };
+
+  const handleSavePreferences = async () => {
+    if (!preferences) return;
+
+    setIsSavingPreferences(true);
+    try {
+      const saved = await api.setNotificationPreferences({
+        muted: preferences.muted,
+        digest: preferences.digest,
+      });
+      setPreferences(saved);
+      showToast("Success", "Notification preferences saved", "success");
+    } catch (error) {
+      showToast("Error", "Failed to save notification preferences", "error");
+    } finally {
+      setIsSavingPreferences(false);
+    }
+  };
+
   const fetchPATs = async () => {
     if (!accountId) return;
 
@@ -296,6 +344,105 @@ function Page() {
         </div>
       </div>
 
+      {/* Notification Preferences Section */}
+      <div
+        className="p-6 mb-6"
+        style={{
+          backgroundColor: "var(--color-bg)",
+          border: "2px solid var(--color-border)",
+          boxShadow: "var(--shadow-soft)",
+        }}
+      >
+        <div className="flex items-center justify-between mb-6">
+          <h2 className="text-xl font-bold uppercase tracking-wide">
+            Notifications
+          </h2>
+          <Button
+            onClick={handleSavePreferences}
+            disabled={isSavingPreferences || !preferences}
+            className="px-4 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150 disabled:opacity-50 disabled:cursor-not-allowed"
+            style={{
+              backgroundColor: "var(--color-purple)",
+              border: "2px solid var(--color-border)",
+              color: "white",
+              boxShadow: "var(--shadow-soft)",
+            }}
+          >
+            {isSavingPreferences ? "Saving..." : "Save"}
+          </Button>
+        </div>
+
+        {!preferences ? (
+          <div className="text-center py-8">
+            <span style={{ color: "var(--color-border)" }}>Loading preferences...</span>
+          </div>
+        ) : (
+          <>
+            <p className="text-sm mb-4" style={{ color: "var(--color-border)" }}>
+              Untick the events you do not want to be notified about when they
+              mention you or your claims.
+            </p>
+            <table className="w-full mb-6 text-sm">
+              <thead>
+                <tr>
+                  <th className="text-left text-xs uppercase tracking-wider p-2">Event</th>
+                  {(preferences.channels ?? []).map((channel) => (
+                    <th
+                      key={channel}
+                      className="text-center text-xs uppercase tracking-wider p-2"
+                    >
+                      {channel}
+                    </th>
+                  ))}
+                </tr>
+              </thead>
+              <tbody>
+                {(preferences.kinds ?? []).map((kind) => (
+                  <tr key={kind} style={{ borderTop: "1px solid var(--color-border)" }}>
+                    <td className="p-2 font-mono">{kind}</td>
+                    {(preferences.channels ?? []).map((channel) => (
+                      <td key={channel} className="p-2 text-center">
+                        <input
+                          type="checkbox"
+                          aria-label={`${kind} on ${channel}`}
+                          checked={!isMuted(channel, kind)}
+                          onChange={() => toggleMuted(channel, kind)}
+                        />
+                      </td>
+                    ))}
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+
+            <label
+              className="block text-xs uppercase tracking-wider font-semibold mb-2"
+              style={{ color: "var(--color-border)" }}
+            >
+              Digest
+            </label>
+            <select
+              value={preferences.digest}
+              onChange={(e) =>
+                setPreferences({
+                  ...preferences,
+                  digest: e.target.value as DigestFrequency,
+                })
+              }
+              className="p-2 font-mono"
+              style={{
+                backgroundColor: "var(--color-surface)",
+                border: "2px solid var(--color-border)",
+              }}
+            >
+              <option value="off">Off</option>
+              <option value="daily">Daily</option>
+              <option value="weekly">Weekly</option>
+            </select>
+          </>
+        )}
+      </div>
+
       {/* PAT Section */}
       <div
         className="p-6"
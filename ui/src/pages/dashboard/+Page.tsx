@@ -7,10 +7,13 @@ import { useAuth } from "../../lib/auth";
 import { useRealm } from "../../lib/realm";
 import { useToast } from "../../lib/toast";
 import { ApiError, api } from "../../lib/api";
-import type { RuneListItem, RuneStatus } from "../../types/rune";
+import type { DashboardStats, RuneStatus } from "../../types/rune";
+import type { BackupStatus } from "../../types/backup";
 
 export { Page };
 
+const emptyDashboard: DashboardStats = { total: 0, status_counts: {}, recent: [] };
+
 interface StatCard {
   label: string;
   value: number;
@@ -18,9 +21,9 @@ interface StatCard {
 }
 
 function Page() {
-  const [runes, setRunes] = useState<RuneListItem[]>([]);
+  const [dashboard, setDashboard] = useState<DashboardStats>(emptyDashboard);
   const [isLoading, setIsLoading] = useState(true);
-  const { realms, isAuthenticated, loading: authLoading } = useAuth();
+  const { realms, isAuthenticated, isSysadmin, loading: authLoading } = useAuth();
   const { currentRealm, availableRealms, isLoading: realmLoading } = useRealm();
   const { showToast } = useToast();
   const fallbackRealms = realms.filter((realmId) => realmId !== "_admin");
@@ -38,7 +41,7 @@ function Page() {
       return;
     }
 
-    const fetchRunes = async () => {
+    const fetchDashboard = async () => {
       if (realmLoading) {
         return;
       }
@@ -49,53 +52,51 @@ function Page() {
       }
 
       try {
-        const data = await api.getRunes(effectiveRealm);
-        setRunes(data);
+        const data = await api.getDashboard(effectiveRealm);
+        setDashboard(data);
       } catch (error) {
         if (error instanceof ApiError && error.status === 404) {
-          setRunes([]);
+          setDashboard(emptyDashboard);
         } else {
-          showToast("Error", "Failed to load runes", "error");
+          showToast("Error", "Failed to load dashboard", "error");
         }
       } finally {
         setIsLoading(false);
       }
     };
 
-    fetchRunes();
+    fetchDashboard();
   }, [authLoading, effectiveRealm, isAuthenticated, realmLoading, showToast]);
 
   const stats: StatCard[] = [
     {
       label: "Total Runes",
-      value: runes.length,
+      value: dashboard.total,
       color: "var(--color-red)",
     },
     {
       label: "Open",
-      value: runes.filter((r) => r.status === "open").length,
+      value: dashboard.status_counts.open ?? 0,
       color: "var(--color-blue)",
     },
     {
       label: "In Progress",
-      value: runes.filter((r) => r.status === "in_progress").length,
+      value: dashboard.status_counts.claimed ?? 0,
       color: "var(--color-amber)",
     },
     {
       label: "Fulfilled",
-      value: runes.filter((r) => r.status === "fulfilled").length,
+      value: dashboard.status_counts.fulfilled ?? 0,
       color: "var(--color-green)",
     },
     {
       label: "Sealed",
-      value: runes.filter((r) => r.status === "sealed").length,
+      value: dashboard.status_counts.sealed ?? 0,
       color: "var(--color-text-muted)",
     },
   ];
 
-  const recentRunes = [...runes]
-    .sort((a, b) => new Date(b.updated_at).getTime() - new Date(a.updated_at).getTime())
-    .slice(0, 10);
+  const recentRunes = dashboard.recent;
 
   const formatDate = (dateStr: string) => {
     const date = new Date(dateStr);
@@ -284,6 +285,109 @@ function Page() {
           </div>
         )}
       </div>
+
+      {isSysadmin && <BackupPanel formatDate={formatDate} />}
+
+      {isSysadmin && (
+        <div className="p-6 mt-8 flex items-center justify-between"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          <div>
+            <h2 className="text-xl font-bold uppercase tracking-wide">Projection Sandbox</h2>
+            <p className="text-sm mt-1" style={{ color: "var(--color-text-muted)" }}>
+              Try projector changes against the full history without touching live read models.
+            </p>
+          </div>
+          <Button
+            onClick={() => navigate("/projections")}
+            className="px-4 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
+            style={{
+              backgroundColor: "var(--color-blue)",
+              border: "2px solid var(--color-border)",
+              color: "white",
+              boxShadow: "var(--shadow-soft)",
+            }}
+          >
+            Open
+          </Button>
+        </div>
+      )}
+    </div>
+  );
+}
+
+function BackupPanel({ formatDate }: { formatDate: (dateStr: string) => string }) {
+  const [status, setStatus] = useState<BackupStatus | null>(null);
+
+  useEffect(() => {
+    api
+      .getBackupStatus()
+      .then(setStatus)
+      .catch(() => setStatus(null));
+  }, []);
+
+  if (!status) {
+    return null;
+  }
+
+  const formatSize = (bytes: number) => {
+    if (bytes < 1024 * 1024) {
+      return `${(bytes / 1024).toFixed(1)} KiB`;
+    }
+    return `${(bytes / (1024 * 1024)).toFixed(1)} MiB`;
+  };
+
+  const rows: [string, string][] = status.enabled
+    ? [
+        ["Destination", status.destination ?? ""],
+        ["Schedule", `Every ${status.interval ?? "?"}, keeping ${status.keep ?? 0}`],
+        ["Last success", status.last_success_at ? formatDate(status.last_success_at) : "Never"],
+        [
+          "Last archive",
+          status.last_archive
+            ? `${status.last_archive} (${formatSize(status.last_size_bytes ?? 0)}, ${status.last_event_count ?? 0} events)`
+            : "None",
+        ],
+        ["Archives kept", String(status.archives?.length ?? 0)],
+      ]
+    : [];
+
+  return (
+    <div
+      className="p-6 mt-8"
+      style={{
+        backgroundColor: "var(--color-bg)",
+        border: "2px solid var(--color-border)",
+        boxShadow: "var(--shadow-soft)",
+      }}
+    >
+      <h2 className="text-xl font-bold uppercase tracking-wide mb-4">Backups</h2>
+      {!status.enabled ? (
+        <p className="text-sm" style={{ color: "var(--color-text-muted)" }}>
+          Scheduled backups are not configured. Set BIFROST_BACKUP_INTERVAL to enable them.
+        </p>
+      ) : (
+        <div className="space-y-2 text-sm">
+          {status.last_error && (
+            <p style={{ color: "var(--color-red)" }}>Last backup failed: {status.last_error}</p>
+          )}
+          {rows.map(([label, value]) => (
+            <div key={label} className="flex gap-4">
+              <span
+                className="w-32 text-xs uppercase tracking-wider font-semibold"
+                style={{ color: "var(--color-text-muted)" }}
+              >
+                {label}
+              </span>
+              <span className="font-medium">{value}</span>
+            </div>
+          ))}
+        </div>
+      )}
     </div>
   );
 }
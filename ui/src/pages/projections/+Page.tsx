@@ -0,0 +1,264 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { Button } from "@base-ui/react/button";
+import { Input } from "@base-ui/react/input";
+import { navigate } from "@/lib/router";
+import { useAuth } from "../../lib/auth";
+import { ApiError, api } from "../../lib/api";
+import { useToast } from "../../lib/toast";
+import type { ReplayProjector, ReplayReport } from "../../types/replay";
+
+export { Page };
+
+const inputStyle = {
+  backgroundColor: "var(--color-surface)",
+  border: "2px solid var(--color-border)",
+  color: "var(--color-text)",
+};
+
+const cardStyle = {
+  backgroundColor: "var(--color-bg)",
+  border: "2px solid var(--color-border)",
+  boxShadow: "var(--shadow-soft)",
+};
+
+const labelClassName = "text-xs uppercase tracking-wider block mb-2 font-bold";
+
+function errorMessage(error: unknown, fallback: string): string {
+  return error instanceof ApiError &&
+    typeof error.data === "object" &&
+    error.data !== null &&
+    "error" in error.data
+    ? String((error.data as { error: unknown }).error)
+    : fallback;
+}
+
+function Page() {
+  const [projectors, setProjectors] = useState<ReplayProjector[]>([]);
+  const [selected, setSelected] = useState<string[]>([]);
+  const [realmIds, setRealmIds] = useState("");
+  const [report, setReport] = useState<ReplayReport | null>(null);
+  const [isLoading, setIsLoading] = useState(true);
+  const [isRunning, setIsRunning] = useState(false);
+  const { isAuthenticated, isSysadmin, loading: authLoading } = useAuth();
+  const { showToast } = useToast();
+
+  useEffect(() => {
+    if (authLoading) return;
+
+    if (!isAuthenticated) {
+      navigate("/login");
+      return;
+    }
+
+    if (!isSysadmin) {
+      navigate("/dashboard");
+      return;
+    }
+
+    api
+      .getReplayProjectors()
+      .then(setProjectors)
+      .catch((error) => {
+        showToast("Error", errorMessage(error, "Failed to load projectors"), "error");
+      })
+      .finally(() => setIsLoading(false));
+  }, [authLoading, isAuthenticated, isSysadmin, showToast]);
+
+  const toggleProjector = (name: string) => {
+    setSelected((prev) =>
+      prev.includes(name) ? prev.filter((n) => n !== name) : [...prev, name],
+    );
+  };
+
+  const handleRun = async () => {
+    setIsRunning(true);
+    setReport(null);
+    try {
+      const realm_ids = realmIds
+        .split(",")
+        .map((id) => id.trim())
+        .filter((id) => id !== "");
+      setReport(await api.replayProjections({ realm_ids, projectors: selected }));
+    } catch (error) {
+      showToast("Error", errorMessage(error, "Failed to replay projections"), "error");
+    } finally {
+      setIsRunning(false);
+    }
+  };
+
+  if (authLoading || isLoading) {
+    return (
+      <div className="min-h-[calc(100vh-56px)] flex items-center justify-center">
+        <div
+          className="px-8 py-4 text-lg font-bold uppercase tracking-wider"
+          style={cardStyle}
+        >
+          Loading...
+        </div>
+      </div>
+    );
+  }
+
+  const differing = report?.diffs?.filter(
+    (d) => d.changed > 0 || d.added > 0 || d.removed > 0 || d.live_entries !== d.sandbox_entries,
+  );
+
+  return (
+    <div className="min-h-[calc(100vh-56px)] p-6 space-y-6">
+      <div>
+        <h1
+          className="text-4xl font-bold tracking-tight uppercase"
+          style={{ color: "var(--color-blue)" }}
+        >
+          Projection Sandbox
+        </h1>
+        <p
+          className="text-sm uppercase tracking-widest mt-1"
+          style={{ color: "var(--color-text-muted)" }}
+        >
+          Replay history into a scratch database and compare it with the live read models
+        </p>
+      </div>
+
+      <div className="p-6 space-y-6" style={cardStyle}>
+        <div>
+          <span className={labelClassName}>Projectors (none selected runs all)</span>
+          <div className="flex flex-wrap gap-2">
+            {projectors.map((p) => (
+              <label
+                key={p.name}
+                className="flex items-center gap-2 px-3 py-1 text-xs font-semibold"
+                style={{ ...inputStyle, opacity: p.skipped ? 0.5 : 1 }}
+                title={p.skipped ? "Has side effects, so it is never replayed" : undefined}
+              >
+                <input
+                  type="checkbox"
+                  checked={selected.includes(p.name)}
+                  disabled={p.skipped}
+                  onChange={() => toggleProjector(p.name)}
+                />
+                {p.name}
+              </label>
+            ))}
+          </div>
+        </div>
+
+        <div>
+          <label className={labelClassName} htmlFor="replay-realms">
+            Realm IDs (comma separated, blank for all)
+          </label>
+          <Input
+            id="replay-realms"
+            value={realmIds}
+            onChange={(e) => setRealmIds(e.target.value)}
+            className="w-full px-3 py-2 text-sm"
+            style={inputStyle}
+          />
+        </div>
+
+        <Button
+          onClick={handleRun}
+          disabled={isRunning}
+          className="px-4 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
+          style={{
+            backgroundColor: "var(--color-red)",
+            border: "2px solid var(--color-border)",
+            color: "white",
+            boxShadow: "var(--shadow-soft)",
+            opacity: isRunning ? 0.6 : 1,
+          }}
+        >
+          {isRunning ? "Replaying..." : "Run Replay"}
+        </Button>
+      </div>
+
+      {report && (
+        <div className="p-6 space-y-6" style={cardStyle}>
+          <p className="text-sm font-semibold">
+            Replayed {report.events} events from {report.realms.length} realms
+          </p>
+
+          <table className="w-full text-sm">
+            <thead>
+              <tr className="text-left text-xs uppercase tracking-wider">
+                <th className="py-2">Projector</th>
+                <th className="py-2">Handled</th>
+                <th className="py-2">Errors</th>
+              </tr>
+            </thead>
+            <tbody>
+              {report.projectors.map((p) => (
+                <tr key={p.name} style={{ borderTop: "1px solid var(--color-border)" }}>
+                  <td className="py-2 font-mono">{p.name}</td>
+                  <td className="py-2">{p.skipped ? "Skipped (side effects)" : p.handled}</td>
+                  <td
+                    className="py-2"
+                    style={{ color: p.failed > 0 ? "var(--color-red)" : undefined }}
+                  >
+                    {p.skipped ? "" : p.failed}
+                  </td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+
+          {report.projectors.some((p) => p.errors?.length) && (
+            <div className="space-y-1 text-xs font-mono" style={{ color: "var(--color-red)" }}>
+              {report.projectors.flatMap((p) =>
+                (p.errors ?? []).map((e) => (
+                  <div key={`${p.name}-${e.realm_id}-${e.global_position}`}>
+                    {p.name}: {e.realm_id} event {e.global_position} ({e.event_type}): {e.error}
+                  </div>
+                )),
+              )}
+            </div>
+          )}
+
+          {report.diffs && differing && (
+            <div className="space-y-3">
+              <p className="text-sm font-semibold">
+                {report.diffs.length - differing.length} of {report.diffs.length} projections
+                match the live read models
+              </p>
+              {differing.length > 0 && (
+                <table className="w-full text-sm">
+                  <thead>
+                    <tr className="text-left text-xs uppercase tracking-wider">
+                      <th className="py-2">Realm</th>
+                      <th className="py-2">Projection</th>
+                      <th className="py-2">Changed</th>
+                      <th className="py-2">Added</th>
+                      <th className="py-2">Removed</th>
+                      <th className="py-2">Live / Sandbox</th>
+                      <th className="py-2">Examples</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+                    {differing.map((d) => (
+                      <tr
+                        key={`${d.realm_id}-${d.projection}`}
+                        style={{ borderTop: "1px solid var(--color-border)" }}
+                      >
+                        <td className="py-2 font-mono">{d.realm_id}</td>
+                        <td className="py-2 font-mono">{d.projection}</td>
+                        <td className="py-2">{d.changed}</td>
+                        <td className="py-2">{d.added}</td>
+                        <td className="py-2">{d.removed}</td>
+                        <td className="py-2">
+                          {d.live_entries} / {d.sandbox_entries}
+                        </td>
+                        <td className="py-2 font-mono text-xs">{(d.examples ?? []).join(", ")}</td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              )}
+            </div>
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
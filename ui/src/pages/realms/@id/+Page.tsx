@@ -439,22 +439,36 @@ function Page() {
             </span>
           </div>
 
-          <Button
-            onClick={() => navigate(`/realms/${realm.id}/edit`)}
-            className="inline-flex h-9 w-9 items-center justify-center text-base font-bold"
-            style={{
-              backgroundColor: "var(--color-green)",
-              border: "2px solid var(--color-border)",
-              color: "white",
-              boxShadow: "var(--shadow-soft)",
-            }}
-            title="Edit Realm"
-            aria-label="Edit realm"
-          >
-            <svg viewBox="0 0 24 24" width="14" height="14" fill="currentColor" aria-hidden="true">
-              <path d="M3 17.25V21h3.75L17.8 9.94l-3.75-3.75L3 17.25zm17.71-10.04a1.003 1.003 0 0 0 0-1.42l-2.5-2.5a1.003 1.003 0 0 0-1.42 0L14.83 5.25l3.75 3.75 2.13-2.12z" />
-            </svg>
-          </Button>
+          <div className="flex items-center gap-2">
+            <Button
+              onClick={() => navigate(`/realms/${realm.id}/webhooks`)}
+              className="inline-flex h-9 items-center px-3 text-xs font-bold uppercase tracking-wider"
+              style={{
+                backgroundColor: "var(--color-bg)",
+                border: "2px solid var(--color-border)",
+                color: "var(--color-text)",
+                boxShadow: "var(--shadow-soft)",
+              }}
+            >
+              Webhooks
+            </Button>
+            <Button
+              onClick={() => navigate(`/realms/${realm.id}/edit`)}
+              className="inline-flex h-9 w-9 items-center justify-center text-base font-bold"
+              style={{
+                backgroundColor: "var(--color-green)",
+                border: "2px solid var(--color-border)",
+                color: "white",
+                boxShadow: "var(--shadow-soft)",
+              }}
+              title="Edit Realm"
+              aria-label="Edit realm"
+            >
+              <svg viewBox="0 0 24 24" width="14" height="14" fill="currentColor" aria-hidden="true">
+                <path d="M3 17.25V21h3.75L17.8 9.94l-3.75-3.75L3 17.25zm17.71-10.04a1.003 1.003 0 0 0 0-1.42l-2.5-2.5a1.003 1.003 0 0 0-1.42 0L14.83 5.25l3.75 3.75 2.13-2.12z" />
+              </svg>
+            </Button>
+          </div>
         </div>
       </div>
 
@@ -0,0 +1,424 @@
+"use client";
+
+import { useCallback, useEffect, useState } from "react";
+import { Button } from "@base-ui/react/button";
+import { Input } from "@base-ui/react/input";
+import { navigate } from "@/lib/router";
+import { usePageContext } from "vike-react/usePageContext";
+import { useAuth } from "../../../../lib/auth";
+import { ApiError, api } from "../../../../lib/api";
+import { useToast } from "../../../../lib/toast";
+import type { Webhook, WebhookDelivery } from "../../../../types/webhook";
+
+export { Page };
+
+type FormData = {
+  url: string;
+  secret: string;
+  events: string[];
+};
+
+const INITIAL_FORM: FormData = {
+  url: "",
+  secret: "",
+  events: [],
+};
+
+// Event types a webhook can subscribe to. Selecting none delivers them all.
+const EVENT_TYPES = [
+  "RuneCreated",
+  "RuneUpdated",
+  "RuneForged",
+  "RuneClaimed",
+  "RuneUnclaimed",
+  "RuneFulfilled",
+  "RuneSealed",
+  "RuneShattered",
+  "RuneNoted",
+  "DependencyAdded",
+  "DependencyRemoved",
+  "SLABreached",
+  "RuneEscalated",
+];
+
+const inputStyle = {
+  backgroundColor: "var(--color-surface)",
+  border: "2px solid var(--color-border)",
+  color: "var(--color-text)",
+};
+
+const cardStyle = {
+  backgroundColor: "var(--color-bg)",
+  border: "2px solid var(--color-border)",
+  boxShadow: "var(--shadow-soft)",
+};
+
+const labelClassName = "text-xs uppercase tracking-wider block mb-2 font-bold";
+
+function errorMessage(error: unknown, fallback: string): string {
+  return error instanceof ApiError &&
+    typeof error.data === "object" &&
+    error.data !== null &&
+    "error" in error.data
+    ? String((error.data as { error: unknown }).error)
+    : fallback;
+}
+
+function describeDelivery(delivery: WebhookDelivery): string {
+  const outcome = delivery.error ?? `HTTP ${delivery.status_code}`;
+  return `${delivery.event_type} · ${outcome} · ${delivery.duration_ms} ms`;
+}
+
+function DeliveryStatus({ delivery }: { delivery: WebhookDelivery }) {
+  const ok = !delivery.error;
+  return (
+    <span
+      className="text-xs uppercase tracking-wider px-2 py-0.5 font-bold"
+      style={{
+        backgroundColor: ok ? "var(--color-green)" : "var(--color-red)",
+        border: "2px solid var(--color-border)",
+        color: "white",
+      }}
+    >
+      {ok ? "Delivered" : "Failed"}
+    </span>
+  );
+}
+
+function Page() {
+  const pageContext = usePageContext();
+  const routeParams = pageContext.routeParams as Record<string, string | undefined>;
+  const realmId = routeParams?.id ?? routeParams?.["@id"] ?? routeParams?.["-id"] ?? "";
+  const { isAuthenticated, loading: authLoading } = useAuth();
+  const { showToast } = useToast();
+
+  const [webhooks, setWebhooks] = useState<Webhook[]>([]);
+  const [form, setForm] = useState<FormData>(INITIAL_FORM);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+  const [createdSecret, setCreatedSecret] = useState<{ id: string; secret: string } | null>(null);
+  const [openWebhook, setOpenWebhook] = useState<string | null>(null);
+  const [deliveries, setDeliveries] = useState<WebhookDelivery[]>([]);
+
+  const loadWebhooks = useCallback(async () => {
+    if (!realmId) {
+      return;
+    }
+    try {
+      setWebhooks(await api.getWebhooks(realmId));
+    } catch (error) {
+      showToast("Error", errorMessage(error, "Failed to load webhooks"), "error");
+    }
+  }, [realmId, showToast]);
+
+  const loadDeliveries = useCallback(
+    async (webhookId: string) => {
+      try {
+        setDeliveries(await api.getWebhookDeliveries(webhookId, realmId));
+      } catch (error) {
+        setDeliveries([]);
+        showToast("Error", errorMessage(error, "Failed to load deliveries"), "error");
+      }
+    },
+    [realmId, showToast]
+  );
+
+  useEffect(() => {
+    if (isAuthenticated) {
+      void loadWebhooks();
+    }
+  }, [isAuthenticated, loadWebhooks]);
+
+  if (authLoading) {
+    return (
+      <div className="min-h-[calc(100vh-56px)] flex items-center justify-center">
+        <div
+          className="px-8 py-4 text-lg font-bold uppercase tracking-wider"
+          style={cardStyle}
+        >
+          Loading...
+        </div>
+      </div>
+    );
+  }
+
+  if (!isAuthenticated) {
+    navigate("/login");
+    return null;
+  }
+
+  const toggleEvent = (eventType: string) => {
+    setForm((prev) => ({
+      ...prev,
+      events: prev.events.includes(eventType)
+        ? prev.events.filter((e) => e !== eventType)
+        : [...prev.events, eventType],
+    }));
+  };
+
+  const canSubmit = form.url.trim() !== "" && !!realmId && !isSubmitting;
+
+  const handleSubmit = async () => {
+    setIsSubmitting(true);
+    try {
+      const result = await api.registerWebhook(
+        {
+          url: form.url.trim(),
+          secret: form.secret.trim() || undefined,
+          events: form.events.length > 0 ? form.events : undefined,
+        },
+        realmId
+      );
+      setCreatedSecret({ id: result.webhook_id, secret: result.secret });
+      setForm(INITIAL_FORM);
+      await loadWebhooks();
+    } catch (error) {
+      showToast("Create Failed", errorMessage(error, "Failed to create webhook"), "error");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  const handleRemove = async (webhook: Webhook) => {
+    try {
+      await api.removeWebhook(webhook.id, realmId);
+      showToast("Webhook Removed", webhook.url, "success");
+      if (openWebhook === webhook.id) {
+        setOpenWebhook(null);
+      }
+      await loadWebhooks();
+    } catch (error) {
+      showToast("Remove Failed", errorMessage(error, "Failed to remove webhook"), "error");
+    }
+  };
+
+  const handleTest = async (webhook: Webhook) => {
+    try {
+      const delivery = await api.testWebhook(webhook.id, realmId);
+      showToast("Test Delivered", describeDelivery(delivery), "success");
+    } catch (error) {
+      showToast("Test Failed", errorMessage(error, "Failed to send test event"), "error");
+    }
+    await loadWebhooks();
+    if (openWebhook === webhook.id) {
+      await loadDeliveries(webhook.id);
+    }
+  };
+
+  const toggleDeliveries = async (webhook: Webhook) => {
+    if (openWebhook === webhook.id) {
+      setOpenWebhook(null);
+      return;
+    }
+    setOpenWebhook(webhook.id);
+    setDeliveries([]);
+    await loadDeliveries(webhook.id);
+  };
+
+  const smallButtonClassName = "px-3 py-2 text-xs font-bold uppercase tracking-wider";
+
+  return (
+    <div className="min-h-[calc(100vh-56px)] p-6">
+      {/* Header */}
+      <div className="mb-8">
+        <Button
+          onClick={() => navigate(`/realms/${realmId}`)}
+          className="inline-flex items-center gap-2 text-sm font-bold uppercase tracking-wider mb-4 transition-all duration-150 hover:translate-x-[-2px]"
+          style={{ color: "var(--color-text-muted)" }}
+        >
+          <span>&larr;</span>
+          <span>Back to Realm</span>
+        </Button>
+        <h1
+          className="text-4xl font-bold tracking-tight uppercase"
+          style={{ color: "var(--color-green)" }}
+        >
+          Webhooks
+        </h1>
+        <p
+          className="text-sm uppercase tracking-widest mt-1"
+          style={{ color: "var(--color-text-muted)" }}
+        >
+          Post signed events from {realmId} to your own services
+        </p>
+      </div>
+
+      <div className="max-w-3xl mx-auto space-y-6">
+        {createdSecret && (
+          <div className="p-6 space-y-2" style={cardStyle}>
+            <div className="font-bold">Webhook {createdSecret.id} created</div>
+            <div className="text-sm" style={{ color: "var(--color-text-muted)" }}>
+              Copy the signing secret now. It is not shown again.
+            </div>
+            <code className="block p-3 text-sm break-all" style={inputStyle}>
+              {createdSecret.secret}
+            </code>
+            <Button
+              onClick={() => setCreatedSecret(null)}
+              className={smallButtonClassName}
+              style={{ ...inputStyle, backgroundColor: "var(--color-bg)" }}
+            >
+              Done
+            </Button>
+          </div>
+        )}
+
+        {/* Existing webhooks */}
+        <div style={cardStyle}>
+          {webhooks.length === 0 ? (
+            <div className="p-6 text-sm" style={{ color: "var(--color-text-muted)" }}>
+              No webhooks in this realm
+            </div>
+          ) : (
+            webhooks.map((webhook) => (
+              <div
+                key={webhook.id}
+                className="p-4 space-y-3"
+                style={{ borderBottom: "1px solid var(--color-border)" }}
+              >
+                <div className="flex items-center justify-between gap-4">
+                  <div className="min-w-0">
+                    <div className="font-bold break-all">{webhook.url}</div>
+                    <div className="text-sm" style={{ color: "var(--color-text-muted)" }}>
+                      {webhook.id} ·{" "}
+                      {webhook.events && webhook.events.length > 0
+                        ? webhook.events.join(", ")
+                        : "All events"}
+                    </div>
+                    {webhook.last_delivery && (
+                      <div className="mt-1 flex items-center gap-2 text-xs">
+                        <DeliveryStatus delivery={webhook.last_delivery} />
+                        <span style={{ color: "var(--color-text-muted)" }}>
+                          {new Date(webhook.last_delivery.delivered_at).toLocaleString()}
+                        </span>
+                      </div>
+                    )}
+                  </div>
+                  <div className="flex gap-2 shrink-0">
+                    <Button
+                      onClick={() => toggleDeliveries(webhook)}
+                      className={smallButtonClassName}
+                      style={{ ...inputStyle, backgroundColor: "var(--color-bg)" }}
+                    >
+                      {openWebhook === webhook.id ? "Hide" : "Deliveries"}
+                    </Button>
+                    <Button
+                      onClick={() => handleTest(webhook)}
+                      className={smallButtonClassName}
+                      style={{ ...inputStyle, backgroundColor: "var(--color-bg)" }}
+                    >
+                      Send Test
+                    </Button>
+                    <Button
+                      onClick={() => handleRemove(webhook)}
+                      className={smallButtonClassName}
+                      style={{
+                        backgroundColor: "var(--color-bg)",
+                        border: "2px solid var(--color-border)",
+                        color: "var(--color-red)",
+                      }}
+                    >
+                      Remove
+                    </Button>
+                  </div>
+                </div>
+
+                {openWebhook === webhook.id && (
+                  <div className="text-sm" style={inputStyle}>
+                    {deliveries.length === 0 ? (
+                      <div className="p-3" style={{ color: "var(--color-text-muted)" }}>
+                        No deliveries since the server started
+                      </div>
+                    ) : (
+                      deliveries.map((delivery) => (
+                        <div
+                          key={delivery.id + delivery.delivered_at}
+                          className="p-3 flex items-center gap-3"
+                          style={{ borderBottom: "1px solid var(--color-border)" }}
+                        >
+                          <DeliveryStatus delivery={delivery} />
+                          <span className="min-w-0 break-all">{describeDelivery(delivery)}</span>
+                          <span className="ml-auto shrink-0" style={{ color: "var(--color-text-muted)" }}>
+                            {new Date(delivery.delivered_at).toLocaleString()}
+                          </span>
+                        </div>
+                      ))
+                    )}
+                  </div>
+                )}
+              </div>
+            ))
+          )}
+        </div>
+
+        {/* New webhook */}
+        <div className="p-8 space-y-6" style={cardStyle}>
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              URL
+            </label>
+            <Input
+              type="url"
+              value={form.url}
+              onChange={(e) => setForm((prev) => ({ ...prev, url: e.target.value }))}
+              placeholder="https://example.com/bifrost"
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Secret (optional, generated when empty)
+            </label>
+            <Input
+              type="text"
+              value={form.secret}
+              onChange={(e) => setForm((prev) => ({ ...prev, secret: e.target.value }))}
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Events (none selected delivers all)
+            </label>
+            <div className="flex flex-wrap gap-2">
+              {EVENT_TYPES.map((eventType) => {
+                const selected = form.events.includes(eventType);
+                return (
+                  <Button
+                    key={eventType}
+                    onClick={() => toggleEvent(eventType)}
+                    className="px-3 py-2 text-xs font-bold tracking-wider"
+                    style={{
+                      backgroundColor: selected ? "var(--color-green)" : "var(--color-bg)",
+                      border: "2px solid var(--color-border)",
+                      color: selected ? "white" : "var(--color-text)",
+                    }}
+                  >
+                    {eventType}
+                  </Button>
+                );
+              })}
+            </div>
+          </div>
+
+          <Button
+            onClick={handleSubmit}
+            disabled={!canSubmit}
+            className="w-full px-6 py-4 text-sm font-bold uppercase tracking-wider transition-all duration-150 disabled:opacity-50 disabled:cursor-not-allowed"
+            style={{
+              backgroundColor: "var(--color-green)",
+              border: "2px solid var(--color-border)",
+              color: "white",
+              boxShadow: canSubmit ? "4px 4px 0px var(--color-border)" : "none",
+            }}
+          >
+            {isSubmitting ? "Creating..." : "Create Webhook"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+}
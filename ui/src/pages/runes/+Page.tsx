@@ -187,6 +187,72 @@ function Page() {
         </ToggleGroup>
         <div className="flex items-center gap-3">
           <RealmSelector />
+          <Button
+            onClick={() => navigate("/runes/import")}
+            className="px-3 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
+            style={{
+              backgroundColor: "var(--color-bg)",
+              border: "2px solid var(--color-border)",
+              color: "var(--color-text)",
+              boxShadow: "var(--shadow-soft)",
+            }}
+            onMouseEnter={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-amber)";
+              e.currentTarget.style.color = "white";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft-hover)";
+            }}
+            onMouseLeave={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-bg)";
+              e.currentTarget.style.color = "var(--color-text)";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft)";
+            }}
+          >
+            Import
+          </Button>
+          <Button
+            onClick={() => navigate("/runes/rules")}
+            className="px-3 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
+            style={{
+              backgroundColor: "var(--color-bg)",
+              border: "2px solid var(--color-border)",
+              color: "var(--color-text)",
+              boxShadow: "var(--shadow-soft)",
+            }}
+            onMouseEnter={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-amber)";
+              e.currentTarget.style.color = "white";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft-hover)";
+            }}
+            onMouseLeave={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-bg)";
+              e.currentTarget.style.color = "var(--color-text)";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft)";
+            }}
+          >
+            Rules
+          </Button>
+          <Button
+            onClick={() => navigate("/runes/schedules")}
+            className="px-3 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
+            style={{
+              backgroundColor: "var(--color-bg)",
+              border: "2px solid var(--color-border)",
+              color: "var(--color-text)",
+              boxShadow: "var(--shadow-soft)",
+            }}
+            onMouseEnter={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-amber)";
+              e.currentTarget.style.color = "white";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft-hover)";
+            }}
+            onMouseLeave={(e) => {
+              e.currentTarget.style.backgroundColor = "var(--color-bg)";
+              e.currentTarget.style.color = "var(--color-text)";
+              e.currentTarget.style.boxShadow = "var(--shadow-soft)";
+            }}
+          >
+            Schedules
+          </Button>
           <Button
             onClick={() => navigate("/runes/new")}
             className="px-3 py-2 text-xs font-bold uppercase tracking-wider transition-all duration-150"
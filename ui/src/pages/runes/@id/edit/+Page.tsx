@@ -9,6 +9,7 @@ import { useAuth } from "../../../../lib/auth";
 import { ApiError, api } from "../../../../lib/api";
 import { useRealm } from "../../../../lib/realm";
 import { useToast } from "../../../../lib/toast";
+import { useFormDraft } from "../../../../lib/drafts";
 
 export { Page };
 
@@ -40,6 +41,16 @@ function Page() {
     priority: 2,
     branch: "",
   });
+  const [original, setOriginal] = useState<FormState | null>(null);
+
+  // The draft is restored over the rune once it has loaded.
+  const draft = useFormDraft<FormState>(
+    `update-rune:${runeId}`,
+    effectiveRealm,
+    form,
+    (fields) => setForm((prev) => ({ ...prev, ...fields })),
+    original !== null
+  );
 
   useEffect(() => {
     if (authLoading || realmLoading) {
@@ -59,12 +70,14 @@ function Page() {
     const loadRune = async () => {
       try {
         const rune = await api.getRune(effectiveRealm, runeId);
-        setForm({
+        const loaded = {
           title: rune.title,
           description: rune.description || "",
           priority: rune.priority,
           branch: rune.branch || "",
-        });
+        };
+        setForm(loaded);
+        setOriginal(loaded);
       } catch {
         showToast("Error", "Failed to load rune", "error");
       } finally {
@@ -90,6 +103,7 @@ function Page() {
         priority: form.priority,
         branch: form.branch.trim(),
       });
+      await draft.discard();
       showToast("Rune Updated", "Your changes were saved", "success");
       navigate(`/runes/${runeId}`);
     } catch (error) {
@@ -144,6 +158,29 @@ function Page() {
           Edit Rune
         </h1>
 
+        {draft.restoredAt && original && (
+          <div
+            className="flex items-center justify-between gap-3 p-3 text-sm"
+            style={{
+              backgroundColor: "var(--color-surface)",
+              border: "2px solid var(--color-border)",
+            }}
+          >
+            <span>Restored your unsaved changes from {new Date(draft.restoredAt).toLocaleString()}.</span>
+            <Button
+              type="button"
+              onClick={async () => {
+                await draft.discard(original);
+                setForm(original);
+              }}
+              className="text-xs font-bold uppercase tracking-wider"
+              style={{ color: "var(--color-red)" }}
+            >
+              Discard Changes
+            </Button>
+          </div>
+        )}
+
         <div>
           <label htmlFor="rune-edit-title" className="text-xs uppercase tracking-wider block mb-2 font-bold">
             Title
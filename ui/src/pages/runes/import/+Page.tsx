@@ -0,0 +1,279 @@
+"use client";
+
+import { useState } from "react";
+import { Button } from "@base-ui/react/button";
+import { Input } from "@base-ui/react/input";
+import { navigate } from "@/lib/router";
+import { useAuth } from "../../../lib/auth";
+import { useRealm } from "../../../lib/realm";
+import { ApiError, api } from "../../../lib/api";
+import { useToast } from "../../../lib/toast";
+import { RealmSelector } from "../../../components/RealmSelector/RealmSelector";
+import type { GitHubImportResult, GitHubIssueState } from "../../../types/import";
+
+export { Page };
+
+type FormData = {
+  repository: string;
+  state: GitHubIssueState;
+  branch: string;
+  token: string;
+};
+
+const INITIAL_FORM: FormData = {
+  repository: "",
+  state: "all",
+  branch: "",
+  token: "",
+};
+
+const STATES: { value: GitHubIssueState; label: string }[] = [
+  { value: "all", label: "All" },
+  { value: "open", label: "Open" },
+  { value: "closed", label: "Closed" },
+];
+
+const REPOSITORY_PATTERN = /^[\w.-]+\/[\w.-]+$/;
+
+const inputStyle = {
+  backgroundColor: "var(--color-surface)",
+  border: "2px solid var(--color-border)",
+  color: "var(--color-text)",
+};
+
+function Page() {
+  const { realms, isAuthenticated, loading: authLoading } = useAuth();
+  const { currentRealm, availableRealms } = useRealm();
+  const { showToast } = useToast();
+  const visibleRealms =
+    availableRealms.length > 0 ? availableRealms : realms.filter((realmId) => realmId !== "_admin");
+  const selectedRealm =
+    currentRealm && visibleRealms.includes(currentRealm) ? currentRealm : (visibleRealms[0] ?? null);
+
+  const [form, setForm] = useState<FormData>(INITIAL_FORM);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+  const [result, setResult] = useState<GitHubImportResult | null>(null);
+
+  if (authLoading) {
+    return (
+      <div className="min-h-[calc(100vh-56px)] flex items-center justify-center">
+        <div
+          className="px-8 py-4 text-lg font-bold uppercase tracking-wider"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          Loading...
+        </div>
+      </div>
+    );
+  }
+
+  if (!isAuthenticated) {
+    navigate("/login");
+    return null;
+  }
+
+  const updateForm = <K extends keyof FormData>(field: K, value: FormData[K]) => {
+    setForm((prev) => ({ ...prev, [field]: value }));
+  };
+
+  const canSubmit = REPOSITORY_PATTERN.test(form.repository.trim()) && !!selectedRealm && !isSubmitting;
+
+  const handleSubmit = async () => {
+    if (!selectedRealm) {
+      return;
+    }
+    setIsSubmitting(true);
+    setResult(null);
+
+    try {
+      const imported = await api.importGitHub(
+        {
+          repository: form.repository.trim(),
+          state: form.state,
+          branch: form.branch.trim() || undefined,
+          token: form.token.trim() || undefined,
+        },
+        selectedRealm
+      );
+      setResult(imported);
+      showToast(
+        "Import Complete",
+        `Imported ${Object.keys(imported.issues).length} issue(s) from ${form.repository.trim()}`,
+        "success"
+      );
+    } catch (error) {
+      const message =
+        error instanceof ApiError &&
+        typeof error.data === "object" &&
+        error.data !== null &&
+        "error" in error.data
+          ? String((error.data as { error: unknown }).error)
+          : "Failed to import issues";
+      showToast("Import Failed", message, "error");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="min-h-[calc(100vh-56px)] p-6">
+      {/* Header */}
+      <div className="mb-8 flex items-start justify-between">
+        <div>
+          <Button
+            onClick={() => navigate("/runes")}
+            className="inline-flex items-center gap-2 text-sm font-bold uppercase tracking-wider mb-4 transition-all duration-150 hover:translate-x-[-2px]"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            <span>&larr;</span>
+            <span>Back to Runes</span>
+          </Button>
+          <h1
+            className="text-4xl font-bold tracking-tight uppercase"
+            style={{ color: "var(--color-amber)" }}
+          >
+            Import from GitHub
+          </h1>
+          <p
+            className="text-sm uppercase tracking-widest mt-1"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Issues, milestones and comments become runes, sagas and notes
+          </p>
+        </div>
+        <RealmSelector />
+      </div>
+
+      <div
+        className="max-w-2xl mx-auto p-8 space-y-6"
+        style={{
+          backgroundColor: "var(--color-bg)",
+          border: "2px solid var(--color-border)",
+          boxShadow: "var(--shadow-soft)",
+        }}
+      >
+        <div>
+          <label
+            className="text-xs uppercase tracking-wider block mb-2 font-bold"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Repository
+          </label>
+          <Input
+            type="text"
+            value={form.repository}
+            onChange={(e) => updateForm("repository", e.target.value)}
+            placeholder="owner/name"
+            className="w-full px-4 py-3 text-lg outline-none"
+            style={inputStyle}
+            autoFocus
+          />
+        </div>
+
+        <div>
+          <label
+            className="text-xs uppercase tracking-wider block mb-2 font-bold"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Issues
+          </label>
+          <div className="flex gap-2">
+            {STATES.map((s) => (
+              <Button
+                key={s.value}
+                onClick={() => updateForm("state", s.value)}
+                className="flex-1 px-4 py-2 text-xs font-bold uppercase tracking-wider"
+                style={{
+                  backgroundColor: form.state === s.value ? "var(--color-amber)" : "var(--color-bg)",
+                  border: "2px solid var(--color-border)",
+                  color: form.state === s.value ? "white" : "var(--color-text)",
+                }}
+              >
+                {s.label}
+              </Button>
+            ))}
+          </div>
+        </div>
+
+        <div>
+          <label
+            className="text-xs uppercase tracking-wider block mb-2 font-bold"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Branch (optional)
+          </label>
+          <Input
+            type="text"
+            value={form.branch}
+            onChange={(e) => updateForm("branch", e.target.value)}
+            placeholder="main"
+            className="w-full px-4 py-3 outline-none"
+            style={inputStyle}
+          />
+        </div>
+
+        <div>
+          <label
+            className="text-xs uppercase tracking-wider block mb-2 font-bold"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Token (optional)
+          </label>
+          <Input
+            type="password"
+            value={form.token}
+            onChange={(e) => updateForm("token", e.target.value)}
+            placeholder="Defaults to the realm's github.token setting"
+            className="w-full px-4 py-3 outline-none"
+            style={inputStyle}
+          />
+        </div>
+
+        {result && (
+          <div
+            className="p-4 space-y-2 text-sm"
+            style={{
+              backgroundColor: "var(--color-surface)",
+              border: "1px solid var(--color-border)",
+            }}
+          >
+            <div className="flex justify-between">
+              <span style={{ color: "var(--color-text-muted)" }}>Issues:</span>
+              <span className="font-medium">{Object.keys(result.issues).length}</span>
+            </div>
+            <div className="flex justify-between">
+              <span style={{ color: "var(--color-text-muted)" }}>Milestones:</span>
+              <span className="font-medium">{Object.keys(result.milestones).length}</span>
+            </div>
+            <div className="flex justify-between">
+              <span style={{ color: "var(--color-text-muted)" }}>Notes:</span>
+              <span className="font-medium">{result.notes}</span>
+            </div>
+            <div className="flex justify-between">
+              <span style={{ color: "var(--color-text-muted)" }}>Links:</span>
+              <span className="font-medium">{result.dependencies}</span>
+            </div>
+          </div>
+        )}
+
+        <Button
+          onClick={handleSubmit}
+          disabled={!canSubmit}
+          className="w-full px-6 py-4 text-sm font-bold uppercase tracking-wider transition-all duration-150 disabled:opacity-50 disabled:cursor-not-allowed"
+          style={{
+            backgroundColor: "var(--color-amber)",
+            border: "2px solid var(--color-border)",
+            color: "white",
+            boxShadow: canSubmit ? "4px 4px 0px var(--color-border)" : "none",
+          }}
+        >
+          {isSubmitting ? "Importing..." : "Import"}
+        </Button>
+      </div>
+    </div>
+  );
+}
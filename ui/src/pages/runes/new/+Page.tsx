@@ -12,8 +12,9 @@ import { useAuth } from "../../../lib/auth";
 import { useRealm } from "../../../lib/realm";
 import { ApiError, api } from "../../../lib/api";
 import { useToast } from "../../../lib/toast";
+import { useFormDraft } from "../../../lib/drafts";
 import { RealmSelector } from "../../../components/RealmSelector/RealmSelector";
-import type { CreateRuneRequest, RuneListItem } from "../../../types/rune";
+import type { CreateRuneRequest, CreateRuneResponse, RuneListItem } from "../../../types/rune";
 
 export { Page };
 
@@ -25,6 +26,10 @@ type FormData = {
   branch: string;
 };
 
+type DraftFields = FormData & {
+  relationships: SelectedRelationship[];
+};
+
 type RelationshipDirection = "depends_on" | "depended_on_by";
 
 type SelectedRelationship = {
@@ -58,6 +63,19 @@ function Page() {
   const [relationshipFilter, setRelationshipFilter] = useState("");
   const [relationshipTargetId, setRelationshipTargetId] = useState("");
   const [queryRealmApplied, setQueryRealmApplied] = useState(false);
+  const [createdRune, setCreatedRune] = useState<CreateRuneResponse | null>(null);
+  const [markingDuplicate, setMarkingDuplicate] = useState<string | null>(null);
+
+  const draft = useFormDraft<DraftFields>(
+    "create-rune",
+    selectedRealm,
+    { ...form, relationships: selectedRelationships },
+    ({ relationships, ...fields }) => {
+      setForm({ ...initialForm, ...fields });
+      setSelectedRelationships(relationships ?? []);
+    },
+    isAuthenticated && queryRealmApplied
+  );
 
   useEffect(() => {
     if (queryRealmApplied || realmLoading) {
@@ -193,6 +211,7 @@ function Page() {
       };
 
       const rune = await api.createRune(request, selectedRealm);
+      await draft.discard();
 
       const relationshipRequests = selectedRelationships.map((relationship) =>
         api.addDependency({
@@ -214,6 +233,11 @@ function Page() {
         );
       }
 
+      if (rune.duplicates && rune.duplicates.length > 0) {
+        setCreatedRune(rune);
+        setIsSubmitting(false);
+        return;
+      }
       navigate(`/runes/${rune.id}`);
     } catch (error) {
       if (error instanceof ApiError) {
@@ -232,6 +256,25 @@ function Page() {
     }
   };
 
+  const markAsDuplicate = async (targetId: string) => {
+    if (!createdRune || !selectedRealm) {
+      return;
+    }
+
+    setMarkingDuplicate(targetId);
+    try {
+      await api.addDependency(
+        { rune_id: createdRune.id, target_id: targetId, relationship: "duplicates" },
+        selectedRealm
+      );
+      showToast("Marked as Duplicate", `${createdRune.id} duplicates ${targetId}`, "success");
+      navigate(`/runes/${createdRune.id}`);
+    } catch {
+      showToast("Error", "Failed to mark the rune as a duplicate", "error");
+      setMarkingDuplicate(null);
+    }
+  };
+
   return (
     <div className="min-h-[calc(100vh-56px)] p-6">
       <div className="mb-6">
@@ -253,6 +296,86 @@ function Page() {
           boxShadow: "var(--shadow-soft)",
         }}
       >
+        {createdRune?.duplicates && createdRune.duplicates.length > 0 && (
+          <div
+            className="mb-6 p-4"
+            role="alert"
+            style={{
+              backgroundColor: "var(--color-surface)",
+              border: "2px solid var(--color-amber)",
+            }}
+          >
+            <div className="flex items-center justify-between gap-3 mb-3">
+              <h3 className="text-xs uppercase tracking-wider font-bold">Possible Duplicates</h3>
+              <Button
+                type="button"
+                onClick={() => navigate(`/runes/${createdRune.id}`)}
+                className="text-xs font-bold uppercase tracking-wider"
+                style={{ color: "var(--color-text-muted)" }}
+              >
+                Keep as New Rune
+              </Button>
+            </div>
+            <p className="text-sm mb-3" style={{ color: "var(--color-text-muted)" }}>
+              "{createdRune.title}" ({createdRune.id}) was created, but these open runes look alike.
+            </p>
+            <div className="space-y-2">
+              {createdRune.duplicates.map((candidate) => (
+                <div
+                  key={candidate.id}
+                  className="flex items-center justify-between gap-3 p-2 text-sm"
+                  style={{
+                    backgroundColor: "var(--color-bg)",
+                    border: "1px solid var(--color-border)",
+                  }}
+                >
+                  <span>
+                    {candidate.title} ({candidate.id}, {candidate.status},{" "}
+                    {Math.round(candidate.similarity * 100)}% alike)
+                  </span>
+                  <Button
+                    type="button"
+                    onClick={() => void markAsDuplicate(candidate.id)}
+                    disabled={markingDuplicate !== null}
+                    className="px-3 py-1 text-xs font-bold uppercase tracking-wider disabled:opacity-50 disabled:cursor-not-allowed"
+                    style={{
+                      backgroundColor: "var(--color-amber)",
+                      border: "2px solid var(--color-border)",
+                      color: "white",
+                    }}
+                  >
+                    {markingDuplicate === candidate.id ? "Marking..." : "Mark as Duplicate"}
+                  </Button>
+                </div>
+              ))}
+            </div>
+          </div>
+        )}
+
+        {draft.restoredAt && (
+          <div
+            className="mb-6 flex items-center justify-between gap-3 p-3 text-sm"
+            style={{
+              backgroundColor: "var(--color-surface)",
+              border: "2px solid var(--color-border)",
+            }}
+          >
+            <span>Restored your unsaved draft from {new Date(draft.restoredAt).toLocaleString()}.</span>
+            <Button
+              type="button"
+              onClick={async () => {
+                await draft.discard({ ...initialForm, relationships: [] });
+                setForm(initialForm);
+                setSelectedRelationships([]);
+              }}
+              className="text-xs font-bold uppercase tracking-wider"
+              style={{ color: "var(--color-red)" }}
+            >
+              Discard Draft
+            </Button>
+          </div>
+        )}
+
         <div className="grid grid-cols-1 lg:grid-cols-2 gap-6">
           <div className="space-y-6">
             <div>
@@ -557,7 +680,7 @@ function Page() {
           <Button
             type="button"
             onClick={handleSubmit}
-            disabled={!canSubmit || isSubmitting}
+            disabled={!canSubmit || isSubmitting || createdRune !== null}
             className="px-6 py-3 text-sm font-bold uppercase tracking-wider disabled:opacity-50 disabled:cursor-not-allowed"
             style={{
               backgroundColor: "var(--color-amber)",
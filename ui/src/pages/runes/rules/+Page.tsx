@@ -0,0 +1,355 @@
+"use client";
+
+import { useCallback, useEffect, useState } from "react";
+import { Button } from "@base-ui/react/button";
+import { Input } from "@base-ui/react/input";
+import { navigate } from "@/lib/router";
+import { useAuth } from "../../../lib/auth";
+import { useRealm } from "../../../lib/realm";
+import { ApiError, api } from "../../../lib/api";
+import { useToast } from "../../../lib/toast";
+import { RealmSelector } from "../../../components/RealmSelector/RealmSelector";
+import type {
+  AutomationAction,
+  AutomationRule,
+  AutomationTrigger,
+} from "../../../types/automation";
+
+export { Page };
+
+type FormData = {
+  name: string;
+  trigger: AutomationTrigger;
+  match: string;
+  action: AutomationAction;
+  value: string;
+};
+
+const INITIAL_FORM: FormData = {
+  name: "",
+  trigger: "created",
+  match: "",
+  action: "set_priority",
+  value: "",
+};
+
+const TRIGGERS: { value: AutomationTrigger; label: string }[] = [
+  { value: "created", label: "Created" },
+  { value: "claimed", label: "Claimed" },
+  { value: "fulfilled", label: "Fulfilled" },
+  { value: "sealed", label: "Sealed" },
+];
+
+const ACTIONS: { value: AutomationAction; label: string }[] = [
+  { value: "set_priority", label: "Set Priority" },
+  { value: "add_note", label: "Add Note" },
+];
+
+const inputStyle = {
+  backgroundColor: "var(--color-surface)",
+  border: "2px solid var(--color-border)",
+  color: "var(--color-text)",
+};
+
+const labelClassName = "text-xs uppercase tracking-wider block mb-2 font-bold";
+
+function errorMessage(error: unknown, fallback: string): string {
+  return error instanceof ApiError &&
+    typeof error.data === "object" &&
+    error.data !== null &&
+    "error" in error.data
+    ? String((error.data as { error: unknown }).error)
+    : fallback;
+}
+
+function describeRule(rule: AutomationRule): string {
+  const when = rule.match ? `When a rune matching "${rule.match}" is ${rule.trigger}` : `When a rune is ${rule.trigger}`;
+  const then = rule.action === "set_priority" ? `set priority ${rule.value}` : `add note "${rule.value}"`;
+  return `${when}, ${then}`;
+}
+
+function Page() {
+  const { realms, isAuthenticated, loading: authLoading } = useAuth();
+  const { currentRealm, availableRealms } = useRealm();
+  const { showToast } = useToast();
+  const visibleRealms =
+    availableRealms.length > 0 ? availableRealms : realms.filter((realmId) => realmId !== "_admin");
+  const selectedRealm =
+    currentRealm && visibleRealms.includes(currentRealm) ? currentRealm : (visibleRealms[0] ?? null);
+
+  const [rules, setRules] = useState<AutomationRule[]>([]);
+  const [form, setForm] = useState<FormData>(INITIAL_FORM);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  const loadRules = useCallback(async () => {
+    if (!selectedRealm) {
+      setRules([]);
+      return;
+    }
+    try {
+      setRules(await api.getAutomationRules(selectedRealm));
+    } catch (error) {
+      showToast("Error", errorMessage(error, "Failed to load automation rules"), "error");
+    }
+  }, [selectedRealm, showToast]);
+
+  useEffect(() => {
+    if (isAuthenticated) {
+      void loadRules();
+    }
+  }, [isAuthenticated, loadRules]);
+
+  if (authLoading) {
+    return (
+      <div className="min-h-[calc(100vh-56px)] flex items-center justify-center">
+        <div
+          className="px-8 py-4 text-lg font-bold uppercase tracking-wider"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          Loading...
+        </div>
+      </div>
+    );
+  }
+
+  if (!isAuthenticated) {
+    navigate("/login");
+    return null;
+  }
+
+  const updateForm = <K extends keyof FormData>(field: K, value: FormData[K]) => {
+    setForm((prev) => ({ ...prev, [field]: value }));
+  };
+
+  const canSubmit =
+    form.name.trim() !== "" && form.value.trim() !== "" && !!selectedRealm && !isSubmitting;
+
+  const handleSubmit = async () => {
+    if (!selectedRealm) {
+      return;
+    }
+    setIsSubmitting(true);
+    try {
+      await api.addAutomationRule(
+        {
+          name: form.name.trim(),
+          trigger: form.trigger,
+          match: form.match.trim() || undefined,
+          action: form.action,
+          value: form.value.trim(),
+        },
+        selectedRealm
+      );
+      showToast("Rule Added", form.name.trim(), "success");
+      setForm(INITIAL_FORM);
+      await loadRules();
+    } catch (error) {
+      showToast("Add Failed", errorMessage(error, "Failed to add rule"), "error");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  const handleRemove = async (rule: AutomationRule) => {
+    if (!selectedRealm) {
+      return;
+    }
+    try {
+      await api.removeAutomationRule(rule.id, selectedRealm);
+      showToast("Rule Removed", rule.name, "success");
+      await loadRules();
+    } catch (error) {
+      showToast("Remove Failed", errorMessage(error, "Failed to remove rule"), "error");
+    }
+  };
+
+  return (
+    <div className="min-h-[calc(100vh-56px)] p-6">
+      {/* Header */}
+      <div className="mb-8 flex items-start justify-between">
+        <div>
+          <Button
+            onClick={() => navigate("/runes")}
+            className="inline-flex items-center gap-2 text-sm font-bold uppercase tracking-wider mb-4 transition-all duration-150 hover:translate-x-[-2px]"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            <span>&larr;</span>
+            <span>Back to Runes</span>
+          </Button>
+          <h1
+            className="text-4xl font-bold tracking-tight uppercase"
+            style={{ color: "var(--color-amber)" }}
+          >
+            Automation Rules
+          </h1>
+          <p
+            className="text-sm uppercase tracking-widest mt-1"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Set priorities and add notes as runes move through their lifecycle
+          </p>
+        </div>
+        <RealmSelector />
+      </div>
+
+      <div className="max-w-2xl mx-auto space-y-6">
+        {/* Existing rules */}
+        <div
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          {rules.length === 0 ? (
+            <div className="p-6 text-sm" style={{ color: "var(--color-text-muted)" }}>
+              No automation rules in this realm
+            </div>
+          ) : (
+            rules.map((rule) => (
+              <div
+                key={rule.id}
+                className="p-4 flex items-center justify-between gap-4"
+                style={{ borderBottom: "1px solid var(--color-border)" }}
+              >
+                <div>
+                  <div className="font-bold">{rule.name}</div>
+                  <div className="text-sm" style={{ color: "var(--color-text-muted)" }}>
+                    {describeRule(rule)}
+                  </div>
+                </div>
+                <Button
+                  onClick={() => handleRemove(rule)}
+                  className="px-3 py-2 text-xs font-bold uppercase tracking-wider"
+                  style={{
+                    backgroundColor: "var(--color-bg)",
+                    border: "2px solid var(--color-border)",
+                    color: "var(--color-red)",
+                  }}
+                >
+                  Remove
+                </Button>
+              </div>
+            ))
+          )}
+        </div>
+
+        {/* New rule */}
+        <div
+          className="p-8 space-y-6"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Name
+            </label>
+            <Input
+              type="text"
+              value={form.name}
+              onChange={(e) => updateForm("name", e.target.value)}
+              placeholder="Urgent first"
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              When a rune is
+            </label>
+            <div className="flex gap-2">
+              {TRIGGERS.map((t) => (
+                <Button
+                  key={t.value}
+                  onClick={() => updateForm("trigger", t.value)}
+                  className="flex-1 px-4 py-2 text-xs font-bold uppercase tracking-wider"
+                  style={{
+                    backgroundColor: form.trigger === t.value ? "var(--color-amber)" : "var(--color-bg)",
+                    border: "2px solid var(--color-border)",
+                    color: form.trigger === t.value ? "white" : "var(--color-text)",
+                  }}
+                >
+                  {t.label}
+                </Button>
+              ))}
+            </div>
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Title contains (optional)
+            </label>
+            <Input
+              type="text"
+              value={form.match}
+              onChange={(e) => updateForm("match", e.target.value)}
+              placeholder="[urgent]"
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Then
+            </label>
+            <div className="flex gap-2">
+              {ACTIONS.map((a) => (
+                <Button
+                  key={a.value}
+                  onClick={() => updateForm("action", a.value)}
+                  className="flex-1 px-4 py-2 text-xs font-bold uppercase tracking-wider"
+                  style={{
+                    backgroundColor: form.action === a.value ? "var(--color-amber)" : "var(--color-bg)",
+                    border: "2px solid var(--color-border)",
+                    color: form.action === a.value ? "white" : "var(--color-text)",
+                  }}
+                >
+                  {a.label}
+                </Button>
+              ))}
+            </div>
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              {form.action === "set_priority" ? "Priority" : "Note"}
+            </label>
+            <Input
+              type={form.action === "set_priority" ? "number" : "text"}
+              value={form.value}
+              onChange={(e) => updateForm("value", e.target.value)}
+              placeholder={
+                form.action === "set_priority" ? "0" : "Fulfilled by {claimant}: {title}"
+              }
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <Button
+            onClick={handleSubmit}
+            disabled={!canSubmit}
+            className="w-full px-6 py-4 text-sm font-bold uppercase tracking-wider transition-all duration-150 disabled:opacity-50 disabled:cursor-not-allowed"
+            style={{
+              backgroundColor: "var(--color-amber)",
+              border: "2px solid var(--color-border)",
+              color: "white",
+              boxShadow: canSubmit ? "4px 4px 0px var(--color-border)" : "none",
+            }}
+          >
+            {isSubmitting ? "Adding..." : "Add Rule"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+}
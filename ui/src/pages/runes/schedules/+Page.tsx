@@ -0,0 +1,413 @@
+"use client";
+
+import { useCallback, useEffect, useState } from "react";
+import { Button } from "@base-ui/react/button";
+import { Input } from "@base-ui/react/input";
+import { navigate } from "@/lib/router";
+import { useAuth } from "../../../lib/auth";
+import { useRealm } from "../../../lib/realm";
+import { ApiError, api } from "../../../lib/api";
+import { useToast } from "../../../lib/toast";
+import { RealmSelector } from "../../../components/RealmSelector/RealmSelector";
+import type { Schedule, ScheduleCommand } from "../../../types/schedule";
+
+export { Page };
+
+type FormData = {
+  name: string;
+  cron: string;
+  command: ScheduleCommand;
+  days: string;
+  title: string;
+  branch: string;
+  priority: string;
+};
+
+const INITIAL_FORM: FormData = {
+  name: "",
+  cron: "0 0 * * *",
+  command: "sweep_runes",
+  days: "30",
+  title: "",
+  branch: "main",
+  priority: "2",
+};
+
+const COMMANDS: { value: ScheduleCommand; label: string }[] = [
+  { value: "sweep_runes", label: "Sweep" },
+  { value: "seal_stale_drafts", label: "Seal Old Drafts" },
+  { value: "create_rune", label: "Create Rune" },
+];
+
+const inputStyle = {
+  backgroundColor: "var(--color-surface)",
+  border: "2px solid var(--color-border)",
+  color: "var(--color-text)",
+};
+
+const labelClassName = "text-xs uppercase tracking-wider block mb-2 font-bold";
+
+function errorMessage(error: unknown, fallback: string): string {
+  return error instanceof ApiError &&
+    typeof error.data === "object" &&
+    error.data !== null &&
+    "error" in error.data
+    ? String((error.data as { error: unknown }).error)
+    : fallback;
+}
+
+function describeSchedule(schedule: Schedule): string {
+  const args = schedule.args ?? {};
+  switch (schedule.command) {
+    case "sweep_runes":
+      return `${schedule.cron}: shatter unreferenced sealed and fulfilled runes`;
+    case "seal_stale_drafts":
+      return `${schedule.cron}: seal drafts older than ${args.days} days`;
+    case "create_rune":
+      return `${schedule.cron}: create "${args.title}"`;
+  }
+}
+
+function argsFor(form: FormData): Record<string, string> | undefined {
+  switch (form.command) {
+    case "sweep_runes":
+      return undefined;
+    case "seal_stale_drafts":
+      return { days: form.days.trim() };
+    case "create_rune":
+      return { title: form.title.trim(), branch: form.branch.trim(), priority: form.priority.trim() };
+  }
+}
+
+function formatTime(value?: string): string {
+  return value ? new Date(value).toLocaleString() : "never";
+}
+
+function Page() {
+  const { realms, isAuthenticated, loading: authLoading } = useAuth();
+  const { currentRealm, availableRealms } = useRealm();
+  const { showToast } = useToast();
+  const visibleRealms =
+    availableRealms.length > 0 ? availableRealms : realms.filter((realmId) => realmId !== "_admin");
+  const selectedRealm =
+    currentRealm && visibleRealms.includes(currentRealm) ? currentRealm : (visibleRealms[0] ?? null);
+
+  const [schedules, setSchedules] = useState<Schedule[]>([]);
+  const [form, setForm] = useState<FormData>(INITIAL_FORM);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  const loadSchedules = useCallback(async () => {
+    if (!selectedRealm) {
+      setSchedules([]);
+      return;
+    }
+    try {
+      setSchedules(await api.getSchedules(selectedRealm));
+    } catch (error) {
+      showToast("Error", errorMessage(error, "Failed to load schedules"), "error");
+    }
+  }, [selectedRealm, showToast]);
+
+  useEffect(() => {
+    if (isAuthenticated) {
+      void loadSchedules();
+    }
+  }, [isAuthenticated, loadSchedules]);
+
+  if (authLoading) {
+    return (
+      <div className="min-h-[calc(100vh-56px)] flex items-center justify-center">
+        <div
+          className="px-8 py-4 text-lg font-bold uppercase tracking-wider"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          Loading...
+        </div>
+      </div>
+    );
+  }
+
+  if (!isAuthenticated) {
+    navigate("/login");
+    return null;
+  }
+
+  const updateForm = <K extends keyof FormData>(field: K, value: FormData[K]) => {
+    setForm((prev) => ({ ...prev, [field]: value }));
+  };
+
+
+  const argsComplete =
+    form.command === "sweep_runes" ||
+    (form.command === "seal_stale_drafts" && form.days.trim() !== "") ||
+    (form.command === "create_rune" && form.title.trim() !== "");
+
+  const canSubmit =
+    form.name.trim() !== "" &&
+    form.cron.trim() !== "" &&
+    argsComplete &&
+    !!selectedRealm &&
+    !isSubmitting;
+
+  const handleSubmit = async () => {
+    if (!selectedRealm) {
+      return;
+    }
+    setIsSubmitting(true);
+    try {
+      await api.createSchedule(
+        {
+          name: form.name.trim(),
+          cron: form.cron.trim(),
+          command: form.command,
+          args: argsFor(form),
+        },
+        selectedRealm
+      );
+      showToast("Schedule Created", form.name.trim(), "success");
+      setForm(INITIAL_FORM);
+      await loadSchedules();
+    } catch (error) {
+      showToast("Create Failed", errorMessage(error, "Failed to create schedule"), "error");
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  const handleDelete = async (schedule: Schedule) => {
+    if (!selectedRealm) {
+      return;
+    }
+    try {
+      await api.deleteSchedule(schedule.id, selectedRealm);
+      showToast("Schedule Deleted", schedule.name, "success");
+      await loadSchedules();
+    } catch (error) {
+      showToast("Delete Failed", errorMessage(error, "Failed to delete schedule"), "error");
+    }
+  };
+
+  return (
+    <div className="min-h-[calc(100vh-56px)] p-6">
+      {/* Header */}
+      <div className="mb-8 flex items-start justify-between">
+        <div>
+          <Button
+            onClick={() => navigate("/runes")}
+            className="inline-flex items-center gap-2 text-sm font-bold uppercase tracking-wider mb-4 transition-all duration-150 hover:translate-x-[-2px]"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            <span>&larr;</span>
+            <span>Back to Runes</span>
+          </Button>
+          <h1
+            className="text-4xl font-bold tracking-tight uppercase"
+            style={{ color: "var(--color-amber)" }}
+          >
+            Schedules
+          </h1>
+          <p
+            className="text-sm uppercase tracking-widest mt-1"
+            style={{ color: "var(--color-text-muted)" }}
+          >
+            Run sweeps, seal old drafts and create recurring runes on a cron schedule (UTC)
+          </p>
+        </div>
+        <RealmSelector />
+      </div>
+
+      <div className="max-w-2xl mx-auto space-y-6">
+        {/* Existing schedules */}
+        <div
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          {schedules.length === 0 ? (
+            <div className="p-6 text-sm" style={{ color: "var(--color-text-muted)" }}>
+              No schedules in this realm
+            </div>
+          ) : (
+            schedules.map((schedule) => (
+              <div
+                key={schedule.id}
+                className="p-4 flex items-center justify-between gap-4"
+                style={{ borderBottom: "1px solid var(--color-border)" }}
+              >
+                <div>
+                  <div className="font-bold">{schedule.name}</div>
+                  <div className="text-sm" style={{ color: "var(--color-text-muted)" }}>
+                    {describeSchedule(schedule)}
+                  </div>
+                  <div className="text-xs mt-1" style={{ color: "var(--color-text-muted)" }}>
+                    Next run {formatTime(schedule.next_run_at)} &middot; Last run{" "}
+                    {formatTime(schedule.last_run_at)}
+                    {schedule.last_result ? ` (${schedule.last_result})` : ""}
+                  </div>
+                  {schedule.last_error && (
+                    <div className="text-xs mt-1" style={{ color: "var(--color-red)" }}>
+                      {schedule.last_error}
+                    </div>
+                  )}
+                </div>
+                <Button
+                  onClick={() => handleDelete(schedule)}
+                  className="px-3 py-2 text-xs font-bold uppercase tracking-wider"
+                  style={{
+                    backgroundColor: "var(--color-bg)",
+                    border: "2px solid var(--color-border)",
+                    color: "var(--color-red)",
+                  }}
+                >
+                  Delete
+                </Button>
+              </div>
+            ))
+          )}
+        </div>
+
+        {/* New schedule */}
+        <div
+          className="p-8 space-y-6"
+          style={{
+            backgroundColor: "var(--color-bg)",
+            border: "2px solid var(--color-border)",
+            boxShadow: "var(--shadow-soft)",
+          }}
+        >
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Name
+            </label>
+            <Input
+              type="text"
+              value={form.name}
+              onChange={(e) => updateForm("name", e.target.value)}
+              placeholder="Nightly sweep"
+              className="w-full px-4 py-3 outline-none"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Cron (UTC)
+            </label>
+            <Input
+              type="text"
+              value={form.cron}
+              onChange={(e) => updateForm("cron", e.target.value)}
+              placeholder="0 9 * * 1-5 or @daily"
+              className="w-full px-4 py-3 outline-none font-mono"
+              style={inputStyle}
+            />
+          </div>
+
+          <div>
+            <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+              Command
+            </label>
+            <div className="flex gap-2">
+              {COMMANDS.map((c) => (
+                <Button
+                  key={c.value}
+                  onClick={() => updateForm("command", c.value)}
+                  className="flex-1 px-4 py-2 text-xs font-bold uppercase tracking-wider"
+                  style={{
+                    backgroundColor: form.command === c.value ? "var(--color-amber)" : "var(--color-bg)",
+                    border: "2px solid var(--color-border)",
+                    color: form.command === c.value ? "white" : "var(--color-text)",
+                  }}
+                >
+                  {c.label}
+                </Button>
+              ))}
+            </div>
+          </div>
+
+          {form.command === "seal_stale_drafts" && (
+            <div>
+              <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+                Older than (days)
+              </label>
+              <Input
+                type="number"
+                value={form.days}
+                onChange={(e) => updateForm("days", e.target.value)}
+                placeholder="30"
+                className="w-full px-4 py-3 outline-none"
+                style={inputStyle}
+              />
+            </div>
+          )}
+
+          {form.command === "create_rune" && (
+            <>
+              <div>
+                <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+                  Title
+                </label>
+                <Input
+                  type="text"
+                  value={form.title}
+                  onChange={(e) => updateForm("title", e.target.value)}
+                  placeholder="Weekly dependency review"
+                  className="w-full px-4 py-3 outline-none"
+                  style={inputStyle}
+                />
+              </div>
+              <div className="flex gap-4">
+                <div className="flex-1">
+                  <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+                    Branch
+                  </label>
+                  <Input
+                    type="text"
+                    value={form.branch}
+                    onChange={(e) => updateForm("branch", e.target.value)}
+                    placeholder="main"
+                    className="w-full px-4 py-3 outline-none"
+                    style={inputStyle}
+                  />
+                </div>
+                <div className="flex-1">
+                  <label className={labelClassName} style={{ color: "var(--color-text-muted)" }}>
+                    Priority
+                  </label>
+                  <Input
+                    type="number"
+                    value={form.priority}
+                    onChange={(e) => updateForm("priority", e.target.value)}
+                    placeholder="2"
+                    className="w-full px-4 py-3 outline-none"
+                    style={inputStyle}
+                  />
+                </div>
+              </div>
+            </>
+          )}
+
+          <Button
+            onClick={handleSubmit}
+            disabled={!canSubmit}
+            className="w-full px-6 py-4 text-sm font-bold uppercase tracking-wider transition-all duration-150 disabled:opacity-50 disabled:cursor-not-allowed"
+            style={{
+              backgroundColor: "var(--color-amber)",
+              border: "2px solid var(--color-border)",
+              color: "white",
+              boxShadow: canSubmit ? "4px 4px 0px var(--color-border)" : "none",
+            }}
+          >
+            {isSubmitting ? "Creating..." : "Create Schedule"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+}
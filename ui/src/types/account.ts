@@ -19,9 +19,20 @@ export interface PatEntry {
 export interface AdminAccountEntry {
   account_id: string;
   username: string;
+  source?: string;
   status: AccountStatus;
   realms: string[];
   roles: Record<string, string>;
   pat_count: number;
   created_at: string;
 }
+
+export type DigestFrequency = "off" | "daily" | "weekly";
+
+export interface NotificationPreferences {
+  account_id: string;
+  muted: Record<string, string[]>;
+  digest: DigestFrequency;
+  channels?: string[];
+  kinds?: string[];
+}
@@ -0,0 +1,26 @@
+export type AutomationTrigger = "created" | "claimed" | "fulfilled" | "sealed";
+
+export type AutomationAction = "set_priority" | "add_note";
+
+export interface AutomationRule {
+  id: string;
+  realm_id: string;
+  name: string;
+  trigger: AutomationTrigger;
+  match?: string;
+  action: AutomationAction;
+  value: string;
+  created_at: string;
+}
+
+export interface AddAutomationRuleRequest {
+  name: string;
+  trigger: AutomationTrigger;
+  match?: string;
+  action: AutomationAction;
+  value: string;
+}
+
+export interface AddAutomationRuleResponse {
+  rule_id: string;
+}
@@ -0,0 +1,13 @@
+export interface BackupStatus {
+  enabled: boolean;
+  destination?: string;
+  interval?: string;
+  keep?: number;
+  last_attempt_at?: string;
+  last_success_at?: string;
+  last_archive?: string;
+  last_size_bytes?: number;
+  last_event_count?: number;
+  last_error?: string;
+  archives?: string[];
+}
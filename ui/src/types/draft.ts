@@ -0,0 +1,7 @@
+export interface FormDraft<T = Record<string, unknown>> {
+  account_id: string;
+  realm_id: string;
+  form: string;
+  fields: T;
+  updated_at: string;
+}
@@ -0,0 +1,15 @@
+export type GitHubIssueState = "open" | "closed" | "all";
+
+export interface GitHubImportRequest {
+  repository: string;
+  state?: GitHubIssueState;
+  branch?: string;
+  token?: string;
+}
+
+export interface GitHubImportResult {
+  issues: Record<string, string>;
+  milestones: Record<string, string>;
+  dependencies: number;
+  notes: number;
+}
@@ -2,3 +2,10 @@ export * from "./rune";
 export * from "./realm";
 export * from "./account";
 export * from "./session";
+export * from "./import";
+export * from "./automation";
+export * from "./schedule";
+export * from "./draft";
+export * from "./webhook";
+export * from "./backup";
+export * from "./replay";
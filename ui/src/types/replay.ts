@@ -0,0 +1,43 @@
+export interface ReplayProjector {
+  name: string;
+  skipped?: boolean;
+}
+
+export interface ReplayError {
+  realm_id: string;
+  global_position: number;
+  event_type: string;
+  error: string;
+}
+
+export interface ProjectorReport {
+  name: string;
+  skipped?: boolean;
+  handled: number;
+  failed: number;
+  errors?: ReplayError[];
+}
+
+export interface ProjectionDiff {
+  realm_id: string;
+  projection: string;
+  same: number;
+  changed: number;
+  added: number;
+  removed: number;
+  live_entries: number;
+  sandbox_entries: number;
+  examples?: string[];
+}
+
+export interface ReplayReport {
+  realms: string[];
+  events: number;
+  projectors: ProjectorReport[];
+  diffs?: ProjectionDiff[];
+}
+
+export interface ReplayRequest {
+  realm_ids?: string[];
+  projectors?: string[];
+}
@@ -40,6 +40,17 @@ export interface RuneDetail extends RuneListItem {
   tags: string[];
 }
 
+export interface DuplicateCandidate {
+  id: string;
+  title: string;
+  status: RuneStatus;
+  similarity: number;
+}
+
+export interface CreateRuneResponse extends RuneDetail {
+  duplicates?: DuplicateCandidate[];
+}
+
 export interface CreateRuneRequest {
   title: string;
   description?: string;
@@ -49,3 +60,16 @@ export interface CreateRuneRequest {
   saga_id?: string;
   tags?: string[];
 }
+
+export interface DashboardRune {
+  id: string;
+  title: string;
+  status: RuneStatus;
+  updated_at: string;
+}
+
+export interface DashboardStats {
+  total: number;
+  status_counts: Record<string, number>;
+  recent: DashboardRune[];
+}
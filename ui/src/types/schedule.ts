@@ -0,0 +1,27 @@
+export type ScheduleCommand = "sweep_runes" | "seal_stale_drafts" | "create_rune";
+
+export interface Schedule {
+  id: string;
+  realm_id: string;
+  name: string;
+  cron: string;
+  command: ScheduleCommand;
+  args?: Record<string, string>;
+  created_at: string;
+  next_run_at?: string;
+  last_due_at?: string;
+  last_run_at?: string;
+  last_result?: string;
+  last_error?: string;
+}
+
+export interface CreateScheduleRequest {
+  name: string;
+  cron: string;
+  command: ScheduleCommand;
+  args?: Record<string, string>;
+}
+
+export interface CreateScheduleResponse {
+  schedule_id: string;
+}
@@ -5,6 +5,7 @@ export interface SessionInfo {
   roles: Record<string, string>;
   is_sysadmin: boolean;
   realm_names?: Record<string, string>;
+  host_realm?: string;
 }
 
 
@@ -0,0 +1,29 @@
+export interface WebhookDelivery {
+  id: string;
+  webhook_id: string;
+  event_type: string;
+  status_code?: number;
+  error?: string;
+  duration_ms: number;
+  delivered_at: string;
+}
+
+export interface Webhook {
+  id: string;
+  url: string;
+  // Empty or missing means every event type is delivered.
+  events?: string[];
+  created_at: string;
+  last_delivery?: WebhookDelivery;
+}
+
+export interface RegisterWebhookRequest {
+  url: string;
+  secret?: string;
+  events?: string[];
+}
+
+export interface RegisterWebhookResponse {
+  webhook_id: string;
+  secret: string;
+}